@@ -2,16 +2,67 @@ package main
 
 import (
 	"context"
+	"flag"
 	"fmt"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
 	"github.com/jaydeep/go-n8n/configs"
+	"github.com/jaydeep/go-n8n/internal/application/alerting"
+	"github.com/jaydeep/go-n8n/internal/application/analytics"
+	"github.com/jaydeep/go-n8n/internal/application/announcement"
+	"github.com/jaydeep/go-n8n/internal/application/audit"
+	"github.com/jaydeep/go-n8n/internal/application/billing"
+	"github.com/jaydeep/go-n8n/internal/application/chat"
+	"github.com/jaydeep/go-n8n/internal/application/cleanup"
+	"github.com/jaydeep/go-n8n/internal/application/credential"
+	"github.com/jaydeep/go-n8n/internal/application/dashboard"
+	"github.com/jaydeep/go-n8n/internal/application/endpoint"
+	"github.com/jaydeep/go-n8n/internal/application/engine"
+	"github.com/jaydeep/go-n8n/internal/application/events"
+	"github.com/jaydeep/go-n8n/internal/application/execution"
+	"github.com/jaydeep/go-n8n/internal/application/feature"
+	"github.com/jaydeep/go-n8n/internal/application/instancehook"
+	"github.com/jaydeep/go-n8n/internal/application/maintenance"
+	"github.com/jaydeep/go-n8n/internal/application/migration"
+	"github.com/jaydeep/go-n8n/internal/application/schedule"
+	"github.com/jaydeep/go-n8n/internal/application/settings"
+	"github.com/jaydeep/go-n8n/internal/application/sla"
+	"github.com/jaydeep/go-n8n/internal/application/teamnode"
+	"github.com/jaydeep/go-n8n/internal/application/user"
+	"github.com/jaydeep/go-n8n/internal/application/worker"
+	"github.com/jaydeep/go-n8n/internal/application/workflow"
+	"github.com/jaydeep/go-n8n/internal/application/workflowimport"
+	alertdomain "github.com/jaydeep/go-n8n/internal/domain/alert"
+	announcementdomain "github.com/jaydeep/go-n8n/internal/domain/announcement"
+	executiondomain "github.com/jaydeep/go-n8n/internal/domain/execution"
+	alertchannels "github.com/jaydeep/go-n8n/internal/infrastructure/alerting"
+	analyticssinks "github.com/jaydeep/go-n8n/internal/infrastructure/analytics"
+	"github.com/jaydeep/go-n8n/internal/infrastructure/email"
+	migrationclient "github.com/jaydeep/go-n8n/internal/infrastructure/migration"
+	"github.com/jaydeep/go-n8n/internal/infrastructure/persistence/postgres/repositories"
+	"github.com/jaydeep/go-n8n/internal/infrastructure/persistence/sqlite"
+	"github.com/jaydeep/go-n8n/internal/nodes"
+	"github.com/jaydeep/go-n8n/internal/interfaces/graphql"
 	"github.com/jaydeep/go-n8n/internal/interfaces/http/rest/v1"
+	"github.com/jaydeep/go-n8n/internal/interfaces/websocket"
+	"github.com/jaydeep/go-n8n/pkg/binarydata"
+	"github.com/jaydeep/go-n8n/pkg/cache"
 	"github.com/jaydeep/go-n8n/pkg/database"
+	"github.com/jaydeep/go-n8n/pkg/encryption"
+	"github.com/jaydeep/go-n8n/pkg/https"
+	"github.com/jaydeep/go-n8n/pkg/i18n"
+	"github.com/jaydeep/go-n8n/pkg/leader"
 	"github.com/jaydeep/go-n8n/pkg/logger"
+	"github.com/jaydeep/go-n8n/pkg/nodesdk"
+	"github.com/jaydeep/go-n8n/pkg/pool"
+	"github.com/jaydeep/go-n8n/pkg/queue"
+	"github.com/jaydeep/go-n8n/pkg/shutdown"
+	"github.com/redis/go-redis/v9"
 )
 
 var (
@@ -19,7 +70,24 @@ var (
 	BuildTime = "unknown"
 )
 
+const usage = `Usage: go-n8n [serve] [flags]
+
+Flags:
+  --lite   run on SQLite with an in-process queue, without PostgreSQL or Redis
+`
+
 func main() {
+	// The command is optional so that "go-n8n" and "go-n8n serve" both start
+	// the server
+	args := os.Args[1:]
+	if len(args) > 0 && args[0] == "serve" {
+		args = args[1:]
+	}
+	flags := flag.NewFlagSet("serve", flag.ExitOnError)
+	lite := flags.Bool("lite", false, "run on SQLite with an in-process queue")
+	flags.Usage = func() { fmt.Fprint(os.Stderr, usage) }
+	flags.Parse(args)
+
 	// Initialize logger
 	log := logger.New()
 	log.Info("Starting n8n Clone API Server",
@@ -32,6 +100,12 @@ func main() {
 	if err != nil {
 		log.Fatal("Failed to load configuration", "error", err)
 	}
+	if *lite {
+		cfg.ApplyLite()
+	}
+	if cfg.Callbacks.Secret == "" || cfg.Callbacks.Secret == configs.ExampleCallbackSecret {
+		log.Fatal("Set callbacks.secret, or CALLBACK_SECRET, to a secret of your own")
+	}
 
 	// Connect to database
 	db, err := database.Connect(cfg.Database)
@@ -40,25 +114,589 @@ func main() {
 	}
 	defer db.Close()
 
-	// Initialize Redis
-	// TODO: Add Redis initialization
+	// Lite mode keeps everything in process: the schema is created on start
+	// and without Redis the services fall back to in-process state
+	var redisClient *redis.Client
+	if cfg.Lite.Enabled {
+		if err := sqlite.ApplySchema(db); err != nil {
+			log.Fatal("Failed to create database schema", "error", err)
+		}
+		log.Info("Running in lite mode", "data_dir", cfg.Lite.DataDir)
+	} else if cfg.Redis.Addr != "" {
+		cacheClient, err := cache.Connect(cfg.Redis)
+		if err != nil {
+			log.Fatal("Failed to connect to redis", "error", err)
+		}
+		defer cacheClient.Close()
+
+		redisClient = cacheClient.Client
+	} else {
+		log.Warn("Redis is not configured; settings, chat sessions and endpoint rate limits are kept per instance")
+	}
+
+	// Initialize repositories
+	executionRepo := repositories.NewExecutionRepository(db)
+	executionHoldRepo := repositories.NewExecutionHoldRepository(db)
+	callbackRepo := repositories.NewCallbackRepository(db)
+	waitRepo := repositories.NewWaitRepository(db)
+	workerRepo := repositories.NewWorkerRepository(db)
+	workflowRepo := repositories.NewWorkflowRepository(db)
+	workflowVersionRepo := repositories.NewWorkflowVersionRepository(db)
+	changeRequestRepo := repositories.NewChangeRequestRepository(db)
+	auditRepo := repositories.NewAuditRepository(db)
+	userRepo := repositories.NewUserRepository(db)
+	settingsRepo := repositories.NewSettingsRepository(db)
+	holidayCalendarRepo := repositories.NewHolidayCalendarRepository(db)
+	scheduleRepo := repositories.NewScheduleRepository(db)
+	endpointRepo := repositories.NewEndpointRepository(db)
+	credentialRepo := repositories.NewCredentialRepository(db)
+	dashboardRepo := repositories.NewDashboardRepository(db)
+	billingRepo := repositories.NewBillingRepository(db)
+
+	var jobQueue queue.Queue
+	switch {
+	case cfg.Lite.Enabled:
+		jobQueue = queue.NewMemoryQueue()
+	case cfg.Worker.QueueDriver == "postgres":
+		jobQueue = queue.NewPostgresQueue(db, cfg.Worker.QueuePollInterval)
+	case redisClient != nil:
+		jobQueue = queue.NewRedisQueue(redisClient)
+	default:
+		log.Fatal("The redis queue driver requires redis.addr; set worker.queue_driver to postgres to run without Redis")
+	}
+	// Workers take the jobs of the teams and workflows sharing a queue in
+	// turn, weighted by team, rather than first in first out
+	teamQuotas := execution.NewTeamQuotas(userRepo, executionRepo, redisClient, log)
+	jobQueue = queue.NewFair(jobQueue, teamQuotas)
+	// Job types routed to queues of their own are taken by dedicated workers,
+	// jobs of a region by workers in the region
+	jobRouter, err := queue.NewRouter(jobQueue, cfg.Worker.QueueName, cfg.Worker.Routes, cfg.Worker.Queues, cfg.Worker.Regions)
+	if err != nil {
+		log.Fatal("Invalid worker routes", "error", err)
+	}
+	if cfg.Storage.Type == "local" {
+		for region := range cfg.Worker.Regions {
+			if _, ok := cfg.Storage.ForRegion(region); !ok {
+				log.Fatal("Region has no binary data storage; set storage.regions", "region", region)
+			}
+		}
+	}
+	// Workers take the jobs of teams over their execution quotas after those
+	// of other teams
+	jobQueue = queue.NewThrottle(jobRouter, teamQuotas)
+
+	// Background services are stopped on shutdown in dependency order: intake
+	// first, then the work in flight, then the buffered writers
+	lifecycle := shutdown.New(log)
+
+	// Execution events stream to the analytics sinks alongside Postgres
+	var eventSinks []executiondomain.EventSink
+	if cfg.Analytics.Enabled {
+		eventSinks, err = analyticssinks.NewSinks(cfg.Analytics.Sinks)
+		if err != nil {
+			log.Fatal("Failed to create analytics sinks", "error", err)
+		}
+	}
+	pipeline := analytics.NewPipeline(eventSinks, cfg.Analytics, log)
+	lifecycle.Go(shutdown.PhaseServices, "analytics", pipeline.Start)
+
+	// Services publish on the event bus; analytics, audit and WebSocket push
+	// subscribe to it. The bus is flushed after the executions in flight and
+	// before the analytics sinks.
+	bus := events.NewBus(cfg.Events, log)
+	bus.Subscribe("analytics", events.Analytics(pipeline), events.ExecutionTypes...)
+	executionEvents := bus.Executions()
+
+	// Node execution records are written in batches as the engine queues
+	// them
+	nodeRunRepo := repositories.NewNodeExecutionRepository(db)
+	nodeWriter := engine.NewNodeWriter(nodeRunRepo, cfg.Engine, log)
+	lifecycle.Go(shutdown.PhaseFlush, "node_writer", nodeWriter.Start)
+
+	// Executions running in this process are waited for before the writers
+	// are flushed
+	inFlight := engine.NewInFlight()
+	lifecycle.OnShutdown(shutdown.PhaseDrain, "executions", inFlight.Drain)
+
+	// Long-lived node clients shared per credential; the engine hands the
+	// pool to nodes through their context
+	connPool := pool.New(cfg.Node.Pool)
+	lifecycle.Go(shutdown.PhaseServices, "pool", connPool.Start)
+
+	// Singleton services run on the one instance holding their lock, so
+	// replicas neither double-fire schedules nor prune concurrently
+	singleton := func(name string, run func(context.Context)) func(context.Context) {
+		lock, err := leader.NewLock(cfg.Leader, name, redisClient, db)
+		if err != nil {
+			log.Fatal("Failed to create leader lock", "service", name, "error", err)
+		}
+		return leader.NewElector(lock, name, run, cfg.Leader, log).Start
+	}
+
+	watchdog := engine.NewWatchdog(executionRepo, nodeRunRepo, workflowRepo, workflowVersionRepo, executionEvents, jobQueue, cfg.Worker.QueueName, cfg.Engine, log)
+	lifecycle.Go(shutdown.PhaseServices, "watchdog", watchdog.Start)
+
+	workerRegistry := worker.NewRegistry(workerRepo, jobQueue, cfg.Worker, log)
+	lifecycle.Go(shutdown.PhaseServices, "worker_reaper", workerRegistry.StartReaper)
+	autoscaler := worker.NewAutoscaler(workerRegistry, jobRouter, cfg.Worker)
+
+	// Operational alerts are evaluated by one instance at a time and sent to
+	// the configured channels when they fire and resolve
+	var alertChannels []alertdomain.Channel
+	if cfg.Alerting.Enabled {
+		alertChannels, err = alertchannels.NewChannels(cfg.Alerting.Channels, cfg.Email.SMTP)
+		if err != nil {
+			log.Fatal("Failed to create alert channels", "error", err)
+		}
+	}
+	alertMonitor := alerting.NewMonitor(repositories.NewAlertRepository(db), alertChannels, workerRegistry, jobQueue, cfg.Worker.QueueName, executionRepo, workflowRepo, cfg.Alerting, log)
+	if cfg.Alerting.Enabled {
+		lifecycle.Go(shutdown.PhaseServices, "alerting", singleton("alerting", alertMonitor.Start))
+	}
+
+	auditAnchorRepo := repositories.NewAuditAnchorRepository(db)
+	auditService := audit.NewService(auditRepo, auditAnchorRepo, log)
+	// The head of the audit chain is signed periodically by one instance
+	if cfg.Audit.SigningKey != "" {
+		anchorer, err := audit.NewAnchorer(auditRepo, auditAnchorRepo, cfg.Audit, log)
+		if err != nil {
+			log.Fatal("Invalid audit configuration", "error", err)
+		}
+		log.Infow("Audit anchoring enabled", "public_key", anchorer.PublicKey())
+		lifecycle.Go(shutdown.PhaseServices, "audit_anchors", singleton("audit_anchors", anchorer.Start))
+	}
+	featureService := feature.NewService(repositories.NewFeatureRepository(db), userRepo, cfg.Features, auditService, log)
+	settingsService := settings.NewService(settingsRepo, redisClient, cfg.Settings.CacheTTL, auditService, log)
+	bus.Subscribe("audit", events.Audit(auditService), events.AuditTypes...)
+
+	// Platform events are delivered to the outgoing webhooks admins register,
+	// by one instance at a time
+	instanceHookRepo := repositories.NewInstanceWebhookRepository(db)
+	instanceHookDeliveryRepo := repositories.NewInstanceWebhookDeliveryRepository(db)
+	instanceHookService := instancehook.NewService(instanceHookRepo, instanceHookDeliveryRepo, auditService, log)
+	if cfg.InstanceHook.Enabled {
+		bus.Subscribe("instance_webhooks", instanceHookService.Handle, instancehook.Types...)
+		dispatcher := instancehook.NewDispatcher(instanceHookRepo, instanceHookDeliveryRepo, cfg.InstanceHook, log)
+		lifecycle.Go(shutdown.PhaseFlush, "instance_webhooks", singleton("instance_webhooks", dispatcher.Start))
+	}
+
+	hub := websocket.NewHub(log)
+	lifecycle.OnShutdown(shutdown.PhaseDrain, "websocket", hub.Shutdown)
+
+	// Maintenance mode pauses the scheduler and webhooks and makes the API
+	// read-only; clients are told as it changes
+	maintenanceService := maintenance.NewService(settingsService, func(event string, state maintenance.State) {
+		hub.Broadcast(websocket.Event{Type: event, Data: state})
+	}, log)
+
+	// Translations for API messages; node types add their own on registration
+	bundle := i18n.NewBundle(i18n.DefaultLanguage)
+	if err := bundle.LoadFS(v1.Locales, "locales", ""); err != nil {
+		log.Fatal("Failed to load translations", "error", err)
+	}
+
+	// Workflow graphs may only loop back to the node types that loop
+	nodeRegistry, err := nodes.NewRegistry(cfg.Node, bundle, log)
+	if err != nil {
+		log.Fatal("Failed to load nodes", "error", err)
+	}
+	graphCache := workflow.NewGraphCache(cfg.Engine.GraphCacheSize, nodeRegistry.Loops)
+	workflowService := workflow.NewService(workflowRepo, workflowVersionRepo, changeRequestRepo, auditService, bus, cfg.Approval, jobRouter.Queues(), cfg.Worker.Regions, graphCache, log)
+
+	// Workflow and credential secrets need the encryption key; without one
+	// they can only be listed and deleted
+	secretCipher, err := encryption.New(cfg.Security.EncryptionKey)
+	if err != nil {
+		log.Warnw("Workflow secrets, credential secrets and credential transfer are disabled", "error", err)
+	}
+	secretRepo := repositories.NewWorkflowSecretRepository(db)
+	secretService := workflow.NewSecretService(secretRepo, secretCipher, auditService)
+	pinnedDataService := workflow.NewPinnedDataService(repositories.NewPinnedDataRepository(db), auditService)
+	credentialService := credential.NewService(credentialRepo, secretCipher, auditService, bus)
+	credentialTransfer := credential.NewTransfer(credentialRepo, userRepo, secretCipher, auditService, bus)
+	// Credentials are tested and checked for expiry by one instance at a
+	// time; owners are told before the workflows using them fail
+	credentialHealth := credential.NewHealthMonitor(credentialRepo, workflowRepo, secretCipher, bus, cfg.CredentialHealth, log)
+	if cfg.CredentialHealth.Enabled && secretCipher != nil {
+		lifecycle.Go(shutdown.PhaseServices, "credential_health", singleton("credential_health", credentialHealth.Start))
+	}
+	autocomplete := workflow.NewAutocomplete(workflowService, executionRepo, nodeRunRepo, secretRepo)
+
+	// Workflows are pushed to the configured instances as one of their admins
+	migrationTargets, err := migrationclient.NewTargets(cfg.Migration)
+	if err != nil {
+		log.Fatal("Failed to create migration targets", "error", err)
+	}
+	migrationService := migration.NewService(workflowRepo, workflowService, credentialRepo, userRepo, secretCipher, migrationTargets, auditService, bus)
+
+	// SLA policies are evaluated by one instance at a time; breaches are
+	// published on the bus
+	slaService := sla.NewService(repositories.NewSLARepository(db), workflowRepo, executionRepo, bus, cfg.SLA, log)
+	if cfg.SLA.Enabled {
+		lifecycle.Go(shutdown.PhaseServices, "sla", singleton("sla", slaService.Start))
+	}
+
+	// Editors see the executions, activation and SLA breaches of their own
+	// workflows, the output of each node of their manual and test
+	// executions as it finishes, and the health of their own credentials
+	bus.Subscribe("websocket", func(ctx context.Context, event events.Event) {
+		switch e := event.(type) {
+		case events.Execution:
+			w, err := workflowService.Get(ctx, e.WorkflowID)
+			if err != nil {
+				return
+			}
+			hub.SendToUser(w.UserID.String(), websocket.Event{Type: string(e.Type), Data: e.Event})
+		case events.Output:
+			w, err := workflowService.Get(ctx, e.WorkflowID)
+			if err != nil {
+				return
+			}
+			hub.SendToUser(w.UserID.String(), websocket.Event{Type: string(events.NodeOutput), Data: e})
+		case events.Workflow:
+			hub.SendToUser(e.OwnerID.String(), websocket.Event{Type: string(e.Type), Data: e})
+		case events.SLA:
+			hub.SendToUser(e.OwnerID.String(), websocket.Event{Type: string(e.Type), Data: e})
+		case events.CredentialHealth:
+			hub.SendToUser(e.OwnerID.String(), websocket.Event{Type: string(e.Type), Data: e})
+		}
+	}, events.ExecutionStarted, events.ExecutionFinished, events.NodeOutput, events.WorkflowActivated, events.WorkflowDeactivated,
+		events.SLABreached, events.SLARecovered, events.CredentialUnhealthy, events.CredentialRecovered)
+	// Owners of unhealthy credentials are also emailed when configured
+	if cfg.CredentialHealth.NotifyEmail && cfg.Email.SMTP.Host != "" {
+		bus.Subscribe("credential_health_email", func(ctx context.Context, event events.Event) {
+			e, ok := event.(events.CredentialHealth)
+			if !ok {
+				return
+			}
+			owner, err := userRepo.FindByID(ctx, e.OwnerID)
+			if err != nil {
+				log.Errorw("Failed to load credential owner", "credential_id", e.CredentialID, "error", err)
+				return
+			}
+			subject, body := credential.HealthNotice(e)
+			if err := email.Send(ctx, cfg.Email.SMTP, email.Message{To: []string{owner.Email}, Subject: subject, Body: body}); err != nil {
+				log.Errorw("Failed to email credential health", "credential_id", e.CredentialID, "error", err)
+			}
+		}, events.CredentialUnhealthy, events.CredentialRecovered)
+	}
+
+	executionService := execution.NewService(workflowService, executionRepo, callbackRepo, waitRepo, executionEvents, jobQueue, cfg.Worker.QueueName, redisClient, log)
+	// Users are notified of finished executions matching their subscribed
+	// execution views
+	executionViews := execution.NewViewService(repositories.NewExecutionViewRepository(db), executionRepo, workflowRepo, userRepo)
+	bus.Subscribe("execution_views", func(ctx context.Context, event events.Event) {
+		e, ok := event.(events.Execution)
+		if !ok {
+			return
+		}
+		matches, err := executionViews.Match(ctx, e.Event)
+		if err != nil {
+			log.Errorw("Failed to match execution views", "execution_id", e.ExecutionID, "error", err)
+			return
+		}
+		for _, m := range matches {
+			hub.SendToUser(m.UserID.String(), websocket.Event{Type: "execution_view.matched", Data: m})
+		}
+	}, events.ExecutionFinished)
+	// Failed executions start the error workflow set on their workflow
+	bus.Subscribe("error_workflows", func(ctx context.Context, event events.Event) {
+		e, ok := event.(events.Execution)
+		if !ok || !e.Status.IsFailed() || e.DryRun || e.Mode == executiondomain.ExecutionModeError {
+			return
+		}
+		failed, err := executionService.Get(ctx, e.ExecutionID)
+		if err != nil {
+			log.Errorw("Failed to load failed execution", "execution_id", e.ExecutionID, "error", err)
+			return
+		}
+		if _, err := executionService.StartErrorWorkflow(ctx, failed); err != nil {
+			log.Errorw("Failed to start error workflow", "execution_id", failed.ID, "workflow_id", failed.WorkflowID,
+				"correlation_id", failed.CorrelationID, "error", err)
+		}
+	}, events.ExecutionFinished)
+	lifecycle.Go(shutdown.PhaseFlush, "events", bus.Start)
+	callbackDispatcher := execution.NewCallbackDispatcher(callbackRepo, executionRepo, cfg.Callbacks, log)
+	lifecycle.Go(shutdown.PhaseFlush, "callbacks", callbackDispatcher.Start)
+
+	endpointService := endpoint.NewService(endpointRepo, repositories.NewCustomDomainRepository(db), workflowService, executionService, userRepo, redisClient, log)
+
+	scheduleService := schedule.NewService(scheduleRepo, holidayCalendarRepo, workflowRepo, userRepo, cfg.Scheduler, log)
+	if cfg.Scheduler.Enabled {
+		scheduler := schedule.NewScheduler(scheduleService, scheduleRepo, workflowService, executionRepo, waitRepo, executionService, executionEvents, jobQueue, cfg.Worker.QueueName, maintenanceService, cfg.Scheduler, log)
+		lifecycle.Go(shutdown.PhaseIntake, "scheduler", singleton("scheduler", scheduler.Start))
+	}
+
+	if cfg.Retention.Enabled {
+		// Partitions are PostgreSQL only; SQLite deletes expired rows alone
+		var partitionRepo executiondomain.PartitionRepository
+		if db.Driver() == database.DriverPostgres {
+			partitionRepo = repositories.NewPartitionRepository(db)
+		}
+		retention := execution.NewRetention(executionRepo, executionHoldRepo, partitionRepo, settingsService, cfg.Retention, log)
+		lifecycle.Go(shutdown.PhaseServices, "retention", singleton("retention", retention.Start))
+	}
+
+	// Last seen times are flushed after requests stop arriving
+	activity := user.NewActivityTracker(userRepo, cfg.UserActivity, log)
+	lifecycle.Go(shutdown.PhaseFlush, "user_activity", activity.Start)
+	userService := user.NewService(userRepo, settingsService, auditService, bus, log)
+	if cfg.UserActivity.DeactivationEnabled {
+		dormancy := user.NewDormancy(userRepo, settingsService, auditService, bus, cfg.UserActivity, log)
+		lifecycle.Go(shutdown.PhaseServices, "dormant_accounts", singleton("dormant_accounts", dormancy.Start))
+	}
+
+	// Execution usage is recorded by the engine
+	billingService := billing.NewService(billingRepo, workflowRepo, userRepo, log)
+
+	dashboardService := dashboard.NewService(dashboardRepo, userRepo, log)
+	if cfg.Dashboards.Enabled {
+		refresher := dashboard.NewRefresher(dashboardRepo, cfg.Dashboards, log)
+		lifecycle.Go(shutdown.PhaseServices, "dashboards", refresher.Start)
+	}
+
+	// Execution requests are shed while the queue, database or heap is
+	// overloaded
+	var loadMonitor *engine.LoadMonitor
+	if cfg.LoadShedding.Enabled {
+		loadMonitor = engine.NewLoadMonitor(jobQueue, cfg.Worker.QueueName, db, cfg.LoadShedding, log)
+		lifecycle.Go(shutdown.PhaseServices, "load_monitor", loadMonitor.Start)
+	}
+
+	// Endpoint calls arriving while executions cannot be started are
+	// buffered and replayed by one instance once they can
+	var endpointBuffer *endpoint.Buffer
+	if cfg.Webhook.Buffer.Enabled {
+		endpointBuffer = endpoint.NewBuffer(repositories.NewEndpointBufferRepository(db), endpointRepo, endpointService, loadMonitor, maintenanceService, cfg.Webhook.Buffer, log)
+		lifecycle.Go(shutdown.PhaseServices, "endpoint_buffer", singleton("endpoint_buffer", endpointBuffer.Start))
+	}
+
+	// Announcements are pushed to connected clients as they change
+	announcementService := announcement.NewService(repositories.NewAnnouncementRepository(db), auditService, func(event string, a *announcementdomain.Announcement) {
+		hub.Broadcast(websocket.Event{Type: event, Data: a})
+	}, log)
+
+	graphqlServer, err := graphql.NewServer(workflowRepo, executionRepo, userRepo, credentialRepo, log)
+	if err != nil {
+		log.Fatal("Failed to build GraphQL schema", "error", err)
+	}
+
+	languages := user.NewLanguageResolver(userRepo, 5*time.Minute, log)
+
+	// Reload custom nodes on change while developing them
+	if cfg.App.Environment == "development" && cfg.Node.CustomDir != "" {
+		reloader := nodes.NewReloader(nodeRegistry, bundle, cfg.Node.CustomDir, log, func(changed, removed []string) {
+			hub.Broadcast(websocket.Event{
+				Type: websocket.EventNodeTypesChanged,
+				Data: map[string]interface{}{"changed": changed, "removed": removed},
+			})
+		})
+		lifecycle.Go(shutdown.PhaseIntake, "node_reloader", reloader.Start)
+	}
+
+	// Nodes teams generated from OpenAPI documents are registered before
+	// the engine takes jobs, then kept in step with the other instances
+	teamNodeService := teamnode.NewService(repositories.NewTeamNodeRepository(db), userRepo, nodeRegistry, log)
+	if err := teamNodeService.Sync(context.Background()); err != nil {
+		log.Warnw("Failed to load team nodes", "error", err)
+	}
+	lifecycle.Go(shutdown.PhaseServices, "team_nodes", teamNodeService.Start)
+
+	// The engine runs the queued executions in this process, registered as
+	// a worker so that its jobs are handed back should it die. It stops
+	// taking jobs with the intake; the executions it runs are drained after.
+	// Binary data moves between nodes as streams through local storage; on
+	// S3 storage nodes keep it in the items. The binary data of workflows
+	// tagged with a region is kept in the region's directory.
+	var binaryStore binarydata.Store
+	if cfg.Storage.Type == "local" {
+		fileStore, err := binarydata.NewFileStore(cfg.Storage.Local.Path)
+		if err != nil {
+			log.Fatal("Failed to open binary data storage", "error", err)
+		}
+		binaryStore = fileStore
+		if len(cfg.Storage.Regions) > 0 {
+			regionStores := make(map[string]binarydata.Store, len(cfg.Storage.Regions))
+			for region := range cfg.Storage.Regions {
+				storage, ok := cfg.Storage.ForRegion(region)
+				if !ok {
+					log.Fatal("Region has no binary data storage; set storage.regions", "region", region)
+				}
+				if regionStores[region], err = binarydata.NewFileStore(storage.Path); err != nil {
+					log.Fatal("Failed to open binary data storage", "region", region, "error", err)
+				}
+			}
+			binaryStore = binarydata.NewRegionStore(fileStore, regionStores)
+		}
+	}
+	// Nodes call APIs through the node proxy and timeout, rate limited and
+	// circuit broken per credential and host
+	nodeHTTP, err := nodesdk.NewHTTPClientFromConfig(cfg.Node)
+	if err != nil {
+		log.Fatal("Invalid node HTTP settings", "error", err)
+	}
+	executionEngine := engine.NewExecutionEngine(executionRepo, waitRepo, workflowService, secretService, pinnedDataService, nodeRegistry, nodeWriter, inFlight,
+		engine.NewAnomalyDetector(cfg.Anomalies), billingService, executionEvents, bus.Outputs(), executionService, executionService, connPool, nodeHTTP, binaryStore, jobQueue, cfg.Engine, cfg.Node.MaxExecutionTime, log)
+	lifecycle.Go(shutdown.PhaseIntake, "engine", func(ctx context.Context) {
+		w, err := workerRegistry.Register(ctx, worker.Info{Version: Version, Concurrency: cfg.Worker.Concurrency})
+		if err != nil {
+			log.Errorw("Failed to register worker", "error", err)
+			return
+		}
+		go workerRegistry.Heartbeat(ctx, w, executionEngine.Active)
+		executionEngine.Consume(ctx, w.ID.String(), w.Queues, cfg.Worker.Concurrency)
+	})
+
+	// The engine runner is attached once workflow execution is available
+	chatSessions := chat.NewSessionStore(redisClient, cfg.Chat.SessionTTL)
+	chatService := chat.NewService(workflowService, chatSessions, nil, cfg.Chat, log)
+
+	// Orphaned resources are reported to admins; the kinds configured are
+	// deleted by one instance at a time
+	cleanupService := cleanup.NewService(repositories.NewCleanupRepository(db), workflowRepo, workflowService, credentialRepo, credentialService, binaryStore, cfg.Cleanup, log)
+	if cfg.Cleanup.Enabled {
+		lifecycle.Go(shutdown.PhaseServices, "cleanup", singleton("cleanup", cleanupService.Start))
+	}
 
 	// Initialize router
-	router := v1.NewRouter(cfg, db, log)
+	router, err := v1.NewRouter(cfg, db, log, &v1.Services{
+		Activity:         activity,
+		Alerts:           alertMonitor,
+		Announcements:    announcementService,
+		Audit:            auditService,
+		Autocomplete:     autocomplete,
+		Autoscaler:       autoscaler,
+		Billing:          billingService,
+		BinaryData:       binaryStore,
+		Chat:             chatService,
+		Cleanup:          cleanupService,
+		Comparer:         execution.NewComparer(nodeRunRepo),
+		CredentialStore:  credentialService,
+		Credentials:      credentialTransfer,
+		CredentialHealth: credentialHealth,
+		Dashboards:       dashboardService,
+		Dependencies:     workflow.NewDependencyGraph(workflowRepo, credentialRepo),
+		Diagnostics:      engine.NewDiagnostics(watchdog, jobQueue, cfg.Worker.QueueName, nodeWriter, graphCache, connPool),
+		Endpoints:        endpointService,
+		EndpointBuffer:   endpointBuffer,
+		Executions:       executionService,
+		ExecutionViews:   executionViews,
+		Features:         featureService,
+		InstanceHooks:    instanceHookService,
+		GraphQL:          graphqlServer,
+		Holds:            execution.NewHoldService(executionHoldRepo, auditService),
+		Hub:              hub,
+		I18n:             bundle,
+		Languages:        languages,
+		Load:             loadMonitor,
+		Maintenance:      maintenanceService,
+		Migrations:       migrationService,
+		Nodes:            nodeRegistry,
+		PinnedData:       pinnedDataService,
+		Pools:            connPool,
+		Schedules:        scheduleService,
+		Secrets:          secretService,
+		Settings:         settingsService,
+		SLAs:             slaService,
+		Statistics:       engine.NewStatistics(executionRepo, cfg.Anomalies),
+		TeamNodes:        teamNodeService,
+		Users:            userService,
+		Watchdog:         watchdog,
+		Workers:          workerRegistry,
+		Workflows:        workflowService,
+		WorkflowImports:  workflowimport.NewService(workflowService, log),
+	})
+	if err != nil {
+		log.Fatal("Failed to create router", "error", err)
+	}
 
-	// Create HTTP server
+	// Create HTTP server. Request contexts derive from requestsCtx, so the
+	// requests still running when shutdown times out can be cancelled.
+	requestsCtx, cancelRequests := context.WithCancel(context.Background())
 	srv := &http.Server{
 		Addr:         fmt.Sprintf(":%d", cfg.Server.Port),
 		Handler:      router,
 		ReadTimeout:  cfg.Server.ReadTimeout,
 		WriteTimeout: cfg.Server.WriteTimeout,
 		IdleTimeout:  cfg.Server.IdleTimeout,
+		BaseContext:  func(net.Listener) context.Context { return requestsCtx },
+
+		ReadHeaderTimeout: cfg.Server.ReadHeaderTimeout,
+		MaxHeaderBytes:    cfg.Server.MaxHeaderBytes,
+	}
+	srv.SetKeepAlivesEnabled(!cfg.Server.DisableKeepAlives)
+
+	lifecycle.OnShutdown(shutdown.PhaseIntake, "http", func(ctx context.Context) error {
+		err := srv.Shutdown(ctx)
+		// Stop the queries and node calls of the requests still running
+		cancelRequests()
+		return err
+	})
+
+	// Serve HTTPS directly when configured, with plain HTTP redirecting to
+	// it and answering ACME challenges
+	serveTLS := cfg.Server.TLS.Enabled
+	if serveTLS {
+		// Verified custom domains get certificates issued on first use
+		var customDomains func(ctx context.Context, host string) error
+		if cfg.Webhook.CustomDomains.Enabled {
+			customDomains = endpointService.AllowCertificate
+		}
+		tlsSetup, err := https.New(cfg.Server.TLS, cfg.Server.Port, customDomains)
+		if err != nil {
+			log.Fatal("Failed to configure TLS", "error", err)
+		}
+		srv.TLSConfig = tlsSetup.TLSConfig
+
+		if tlsSetup.HTTPHandler != nil {
+			redirectSrv := &http.Server{
+				Addr:         tlsSetup.HTTPAddr,
+				Handler:      tlsSetup.HTTPHandler,
+				ReadTimeout:  cfg.Server.ReadTimeout,
+				WriteTimeout: cfg.Server.WriteTimeout,
+				IdleTimeout:  cfg.Server.IdleTimeout,
+			}
+			lifecycle.OnShutdown(shutdown.PhaseIntake, "http_redirect", redirectSrv.Shutdown)
+			go func() {
+				log.Info("HTTP redirect server starting", "addr", redirectSrv.Addr)
+				if err := redirectSrv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+					log.Fatal("Failed to start HTTP redirect server", "error", err)
+				}
+			}()
+		}
+	}
+
+	if err := https.ConfigureHTTP2(srv, cfg.Server.HTTP2); err != nil {
+		log.Fatal("Failed to configure HTTP/2", "error", err)
+	}
+
+	// Prometheus scrapes the autoscaling metrics on a port of their own
+	if cfg.Monitoring.Metrics.Enabled {
+		metricsSrv := &http.Server{
+			Addr:              fmt.Sprintf(":%d", cfg.Monitoring.Metrics.Port),
+			Handler:           v1.NewMonitoringRouter(cfg.Monitoring.Metrics, log, autoscaler),
+			ReadHeaderTimeout: cfg.Server.ReadHeaderTimeout,
+		}
+		lifecycle.OnShutdown(shutdown.PhaseIntake, "metrics", metricsSrv.Shutdown)
+		go func() {
+			log.Infow("Metrics server starting", "port", cfg.Monitoring.Metrics.Port)
+			if err := metricsSrv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				log.Errorw("Metrics server failed", "error", err)
+			}
+		}()
 	}
 
 	// Start server in goroutine
 	go func() {
-		log.Info("API Server starting", "port", cfg.Server.Port)
-		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		log.Info("API Server starting", "port", cfg.Server.Port, "tls", serveTLS)
+		var err error
+		if serveTLS {
+			// The certificates come from srv.TLSConfig
+			err = srv.ListenAndServeTLS("", "")
+		} else {
+			err = srv.ListenAndServe()
+		}
+		if err != nil && err != http.ErrServerClosed {
 			log.Fatal("Failed to start server", "error", err)
 		}
 	}()
@@ -70,12 +708,13 @@ func main() {
 
 	log.Info("Shutting down server...")
 
-	// Graceful shutdown with timeout
+	// Graceful shutdown with timeout; the database and cache connections
+	// are closed once every phase has completed
 	ctx, cancel := context.WithTimeout(context.Background(), cfg.Server.ShutdownTimeout)
 	defer cancel()
 
-	if err := srv.Shutdown(ctx); err != nil {
-		log.Fatal("Server forced to shutdown", "error", err)
+	if err := lifecycle.Shutdown(ctx); err != nil {
+		log.Errorw("Server forced to shutdown", "error", err)
 	}
 
 	log.Info("Server exited")
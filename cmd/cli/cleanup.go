@@ -0,0 +1,178 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/jaydeep/go-n8n/internal/domain/cleanup"
+)
+
+// cleanupTimeout bounds a cleanup request; reports scan every workflow and
+// execution
+const cleanupTimeout = 30 * time.Minute
+
+// cleanupFlags are the flags of the cleanup commands
+type cleanupFlags struct {
+	fs           *flag.FlagSet
+	url          *string
+	token        *string
+	kinds        *string
+	inactiveDays *int
+}
+
+func newCleanupFlags(name, usage string) *cleanupFlags {
+	fs := flag.NewFlagSet(name, flag.ExitOnError)
+	f := &cleanupFlags{
+		fs:           fs,
+		url:          fs.String("url", envOr("N8N_URL", "http://localhost:8080"), "base URL of the instance, without /api/v1 (N8N_URL)"),
+		token:        fs.String("token", os.Getenv("N8N_TOKEN"), "access token of an admin (N8N_TOKEN)"),
+		kinds:        fs.String("kinds", "", "comma separated kinds: credentials, workflows, variables, binary_data"),
+		inactiveDays: fs.Int("inactive-days", 0, "days credentials and workflows must be unused for; 0 uses the instance setting"),
+	}
+	fs.Usage = func() {
+		fmt.Fprintln(os.Stderr, usage)
+		fs.PrintDefaults()
+	}
+	return f
+}
+
+// cleanupReport prints the orphaned resources of an instance
+func cleanupReport(args []string) error {
+	f := newCleanupFlags("cleanup report", "Usage: cli cleanup report [flags]")
+	if err := f.fs.Parse(args); err != nil {
+		return err
+	}
+	query := url.Values{}
+	if *f.kinds != "" {
+		query.Set("kinds", *f.kinds)
+	}
+	if *f.inactiveDays > 0 {
+		query.Set("inactive_days", strconv.Itoa(*f.inactiveDays))
+	}
+
+	var report cleanup.Report
+	if err := cleanupRequest(f, http.MethodGet, "/api/v1/admin/cleanup?"+query.Encode(), nil, &report); err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "Credentials unused for %d days: %d\n", report.InactiveDays, len(report.Credentials))
+	for _, c := range report.Credentials {
+		fmt.Fprintf(w, "  %s\t%s\t%s\n", c.ID, c.Type, c.Name)
+	}
+	fmt.Fprintf(w, "Inactive workflows without executions for %d days: %d\n", report.InactiveDays, len(report.Workflows))
+	for _, wf := range report.Workflows {
+		last := "never"
+		if wf.LastExecutedAt != nil {
+			last = wf.LastExecutedAt.Format(time.RFC3339)
+		}
+		fmt.Fprintf(w, "  %s\tlast run %s\t%s\n", wf.ID, last, wf.Name)
+	}
+	fmt.Fprintf(w, "Unused variables: %d\n", len(report.Variables))
+	for _, v := range report.Variables {
+		fmt.Fprintf(w, "  %s\t%s\t%s\n", v.WorkflowID, v.Name, v.WorkflowName)
+	}
+	if report.BinaryDataListed {
+		var size int64
+		for _, b := range report.BinaryData {
+			size += b.Size
+		}
+		fmt.Fprintf(w, "Dangling binary data: %d (%d bytes)\n", len(report.BinaryData), size)
+	} else {
+		fmt.Fprintln(w, "Dangling binary data: not listed by the binary data store")
+	}
+	return w.Flush()
+}
+
+// cleanupRun deletes the orphaned resources of the kinds given
+func cleanupRun(args []string) error {
+	f := newCleanupFlags("cleanup run", "Usage: cli cleanup run -kinds <kinds> [flags]")
+	if err := f.fs.Parse(args); err != nil {
+		return err
+	}
+	if *f.kinds == "" {
+		f.fs.Usage()
+		os.Exit(2)
+	}
+	input := map[string]interface{}{"inactive_days": *f.inactiveDays}
+	var kinds []string
+	for _, k := range strings.Split(*f.kinds, ",") {
+		kinds = append(kinds, strings.TrimSpace(k))
+	}
+	input["kinds"] = kinds
+
+	var result cleanup.Result
+	if err := cleanupRequest(f, http.MethodPost, "/api/v1/admin/cleanup", input, &result); err != nil {
+		return err
+	}
+	for _, k := range kinds {
+		fmt.Printf("%s: %d deleted\n", k, result.Deleted[cleanup.Kind(k)])
+	}
+	for _, failure := range result.Failed {
+		fmt.Printf("failed to delete %s %s: %s\n", failure.Kind, failure.ID, failure.Error)
+	}
+	if len(result.Failed) > 0 {
+		return fmt.Errorf("%d resources were not deleted", len(result.Failed))
+	}
+	return nil
+}
+
+// cleanupRequest sends a request to the cleanup endpoint and decodes the
+// data of the response into out
+func cleanupRequest(f *cleanupFlags, method, path string, body interface{}, out interface{}) error {
+	if *f.token == "" {
+		return fmt.Errorf("-token or N8N_TOKEN is required")
+	}
+	var reader io.Reader
+	if body != nil {
+		encoded, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(encoded)
+	}
+	req, err := http.NewRequest(method, strings.TrimRight(*f.url, "/")+path, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+*f.token)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := (&http.Client{Timeout: cleanupTimeout}).Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	var payload struct {
+		Data  json.RawMessage `json:"data"`
+		Error string          `json:"error"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
+		return fmt.Errorf("status %d: unexpected response", resp.StatusCode)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("status %d: %s", resp.StatusCode, payload.Error)
+	}
+	return json.Unmarshal(payload.Data, out)
+}
+
+// envOr returns the environment variable, or fallback when unset
+func envOr(name, fallback string) string {
+	if v := os.Getenv(name); v != "" {
+		return v
+	}
+	return fallback
+}
@@ -1,6 +1,7 @@
 package configs
 
 import (
+	"path/filepath"
 	"time"
 
 	"github.com/jaydeep/go-n8n/pkg/database"
@@ -9,26 +10,47 @@ import (
 
 // Config holds all configuration for the application
 type Config struct {
-	App        AppConfig        `mapstructure:"app"`
-	Server     ServerConfig     `mapstructure:"server"`
-	Database   database.Config  `mapstructure:"database"`
-	Redis      RedisConfig      `mapstructure:"redis"`
-	JWT        JWTConfig        `mapstructure:"jwt"`
-	Security   SecurityConfig   `mapstructure:"security"`
-	CORS       CORSConfig       `mapstructure:"cors"`
-	RateLimit  RateLimitConfig  `mapstructure:"rate_limit"`
-	Engine     EngineConfig     `mapstructure:"engine"`
-	Node       NodeConfig       `mapstructure:"node"`
-	Storage    StorageConfig    `mapstructure:"storage"`
-	Logging    LoggingConfig    `mapstructure:"logging"`
-	Monitoring MonitoringConfig `mapstructure:"monitoring"`
-	Webhook    WebhookConfig    `mapstructure:"webhook"`
-	Scheduler  SchedulerConfig  `mapstructure:"scheduler"`
-	Worker     WorkerConfig     `mapstructure:"worker"`
-	Email      EmailConfig      `mapstructure:"email"`
-	OAuth      OAuthConfig      `mapstructure:"oauth"`
-	Features   FeaturesConfig   `mapstructure:"features"`
-	Limits     LimitsConfig     `mapstructure:"limits"`
+	App              AppConfig              `mapstructure:"app"`
+	Server           ServerConfig           `mapstructure:"server"`
+	Database         database.Config        `mapstructure:"database"`
+	Redis            RedisConfig            `mapstructure:"redis"`
+	JWT              JWTConfig              `mapstructure:"jwt"`
+	Security         SecurityConfig         `mapstructure:"security"`
+	CORS             CORSConfig             `mapstructure:"cors"`
+	Network          NetworkConfig          `mapstructure:"network"`
+	RateLimit        RateLimitConfig        `mapstructure:"rate_limit"`
+	LoadShedding     LoadSheddingConfig     `mapstructure:"load_shedding"`
+	Engine           EngineConfig           `mapstructure:"engine"`
+	Node             NodeConfig             `mapstructure:"node"`
+	Storage          StorageConfig          `mapstructure:"storage"`
+	Logging          LoggingConfig          `mapstructure:"logging"`
+	Monitoring       MonitoringConfig       `mapstructure:"monitoring"`
+	Webhook          WebhookConfig          `mapstructure:"webhook"`
+	Scheduler        SchedulerConfig        `mapstructure:"scheduler"`
+	Leader           LeaderConfig           `mapstructure:"leader"`
+	Worker           WorkerConfig           `mapstructure:"worker"`
+	Email            EmailConfig            `mapstructure:"email"`
+	OAuth            OAuthConfig            `mapstructure:"oauth"`
+	Features         FeaturesConfig         `mapstructure:"features"`
+	Limits           LimitsConfig           `mapstructure:"limits"`
+	Approval         ApprovalConfig         `mapstructure:"approval"`
+	Chat             ChatConfig             `mapstructure:"chat"`
+	Settings         SettingsConfig         `mapstructure:"settings"`
+	Callbacks        CallbackConfig         `mapstructure:"callbacks"`
+	Events           EventsConfig           `mapstructure:"events"`
+	InstanceHook     InstanceHookConfig     `mapstructure:"instance_webhooks"`
+	Alerting         AlertingConfig         `mapstructure:"alerting"`
+	SLA              SLAConfig              `mapstructure:"sla"`
+	CredentialHealth CredentialHealthConfig `mapstructure:"credential_health"`
+	Anomalies        AnomalyConfig          `mapstructure:"anomaly_detection"`
+	Analytics        AnalyticsConfig        `mapstructure:"analytics"`
+	Dashboards       DashboardConfig        `mapstructure:"dashboards"`
+	Lite             LiteConfig             `mapstructure:"lite"`
+	Retention        RetentionConfig        `mapstructure:"retention"`
+	Cleanup          CleanupConfig          `mapstructure:"cleanup"`
+	UserActivity     UserActivityConfig     `mapstructure:"user_activity"`
+	Migration        MigrationConfig        `mapstructure:"migration"`
+	Audit            AuditConfig            `mapstructure:"audit"`
 }
 
 type AppConfig struct {
@@ -45,6 +67,73 @@ type ServerConfig struct {
 	WriteTimeout    time.Duration `mapstructure:"write_timeout"`
 	IdleTimeout     time.Duration `mapstructure:"idle_timeout"`
 	ShutdownTimeout time.Duration `mapstructure:"shutdown_timeout"`
+	ReadHeaderTimeout time.Duration `mapstructure:"read_header_timeout"`
+	MaxHeaderBytes    int           `mapstructure:"max_header_bytes"`
+	DisableKeepAlives bool          `mapstructure:"disable_keep_alives"`
+	HTTP2             HTTP2Config   `mapstructure:"http2"`
+	// ReadTimeout and WriteTimeout apply to the JSON API; RouteTimeouts
+	// replaces them for routes whose requests or responses run longer
+	RouteTimeouts RouteTimeoutsConfig `mapstructure:"route_timeouts"`
+	// Client IPs are read from RemoteIPHeaders only on connections from
+	// TrustedProxies, or from the TrustedPlatform header (such as
+	// CF-Connecting-IP) when set; otherwise the connection's address is used
+	TrustedProxies  []string `mapstructure:"trusted_proxies"`
+	RemoteIPHeaders []string `mapstructure:"remote_ip_headers"`
+	TrustedPlatform string   `mapstructure:"trusted_platform"`
+	TLS             TLSConfig `mapstructure:"tls"`
+	// CorrelationHeader carries correlation IDs on requests and responses
+	CorrelationHeader string `mapstructure:"correlation_header"`
+}
+
+// HTTP2Config tunes HTTP/2. It is negotiated over TLS unless Disabled;
+// H2C also accepts cleartext HTTP/2, for internal clients such as gRPC
+// behind a proxy that does not terminate HTTP/2.
+type HTTP2Config struct {
+	Disabled             bool          `mapstructure:"disabled"`
+	H2C                  bool          `mapstructure:"h2c"`
+	MaxConcurrentStreams uint32        `mapstructure:"max_concurrent_streams"`
+	MaxReadFrameSize     uint32        `mapstructure:"max_read_frame_size"`
+	IdleTimeout          time.Duration `mapstructure:"idle_timeout"`
+}
+
+// RouteTimeoutsConfig holds the deadlines of route classes other than the
+// JSON API
+type RouteTimeoutsConfig struct {
+	// Streaming covers WebSocket and event stream routes
+	Streaming RouteTimeoutConfig `mapstructure:"streaming"`
+	// Webhooks covers webhook and workflow endpoint routes
+	Webhooks RouteTimeoutConfig `mapstructure:"webhooks"`
+}
+
+// RouteTimeoutConfig bounds reading a request and writing its response.
+// Zero removes the deadline.
+type RouteTimeoutConfig struct {
+	ReadTimeout  time.Duration `mapstructure:"read_timeout"`
+	WriteTimeout time.Duration `mapstructure:"write_timeout"`
+}
+
+// TLSConfig enables HTTPS on the API server itself, for deployments without
+// a TLS-terminating proxy. Certificates come from CertFile and KeyFile, or
+// are issued by an ACME CA such as Let's Encrypt for ACME.Domains.
+type TLSConfig struct {
+	Enabled  bool   `mapstructure:"enabled"`
+	CertFile string `mapstructure:"cert_file"`
+	KeyFile  string `mapstructure:"key_file"`
+	ACME     ACMEConfig `mapstructure:"acme"`
+	// RedirectHTTP serves HTTPPort, redirecting plain HTTP requests to
+	// HTTPS and answering ACME HTTP-01 challenges
+	RedirectHTTP bool `mapstructure:"redirect_http"`
+	HTTPPort     int  `mapstructure:"http_port"`
+}
+
+// ACMEConfig configures automatic certificate issuance
+type ACMEConfig struct {
+	Domains []string `mapstructure:"domains"`
+	Email   string   `mapstructure:"email"`
+	// CacheDir keeps the account key and issued certificates across restarts
+	CacheDir string `mapstructure:"cache_dir"`
+	// DirectoryURL selects the CA; empty uses Let's Encrypt production
+	DirectoryURL string `mapstructure:"directory_url"`
 }
 
 type RedisConfig struct {
@@ -65,6 +154,35 @@ type JWTConfig struct {
 	AccessTokenExpiry time.Duration `mapstructure:"access_token_expiry"`
 	RefreshTokenExpiry time.Duration `mapstructure:"refresh_token_expiry"`
 	Issuer            string        `mapstructure:"issuer"`
+	// Mode selects the tokens accepted: "local" for HMAC tokens signed
+	// with Secret, "external" for tokens of the identity provider set up
+	// in External, and "hybrid" for both
+	Mode     string             `mapstructure:"mode"`
+	External ExternalAuthConfig `mapstructure:"external"`
+}
+
+// ExternalAuthConfig delegates authentication to an identity provider
+// whose tokens are verified with the keys published at JWKSURL. Claims
+// are dotted paths into the token, such as realm_access.roles.
+type ExternalAuthConfig struct {
+	JWKSURL         string        `mapstructure:"jwks_url"`
+	RefreshInterval time.Duration `mapstructure:"refresh_interval"`
+	Issuer          string        `mapstructure:"issuer"`
+	Audience        string        `mapstructure:"audience"`
+	// Algorithms lists the signing algorithms accepted, RS256 by default
+	Algorithms []string      `mapstructure:"algorithms"`
+	Leeway     time.Duration `mapstructure:"leeway"`
+	// UserIDClaim holds the user ID, mapped to a UUID derived from the
+	// issuer and the value.
+	UserIDClaim string `mapstructure:"user_id_claim"`
+	EmailClaim  string `mapstructure:"email_claim"`
+	// RoleClaim holds a role or a list of them, translated to local roles
+	// by RoleMapping. Without a mapping, values naming a local role are
+	// used as is. Users without a role get DefaultRole, or are refused
+	// when it is empty.
+	RoleClaim   string            `mapstructure:"role_claim"`
+	RoleMapping map[string]string `mapstructure:"role_mapping"`
+	DefaultRole string            `mapstructure:"default_role"`
 }
 
 type SecurityConfig struct {
@@ -72,6 +190,24 @@ type SecurityConfig struct {
 	EncryptionKey    string        `mapstructure:"encryption_key"`
 	APIKeyLength     int           `mapstructure:"api_key_length"`
 	SessionLifetime  time.Duration `mapstructure:"session_lifetime"`
+	Headers          SecurityHeadersConfig `mapstructure:"headers"`
+}
+
+// SecurityHeadersConfig controls the security headers set on responses.
+// ContentSecurityPolicy and FrameOptions protect the hosted UI; webhook,
+// workflow endpoint and chat trigger responses carry content from the
+// workflow and use the Webhook variants instead. Empty values omit the
+// header, as does a zero HSTSMaxAge.
+type SecurityHeadersConfig struct {
+	Enabled                      bool          `mapstructure:"enabled"`
+	HSTSMaxAge                   time.Duration `mapstructure:"hsts_max_age"`
+	HSTSIncludeSubdomains        bool          `mapstructure:"hsts_include_subdomains"`
+	HSTSPreload                  bool          `mapstructure:"hsts_preload"`
+	FrameOptions                 string        `mapstructure:"frame_options"`
+	ReferrerPolicy               string        `mapstructure:"referrer_policy"`
+	ContentSecurityPolicy        string        `mapstructure:"content_security_policy"`
+	WebhookFrameOptions          string        `mapstructure:"webhook_frame_options"`
+	WebhookContentSecurityPolicy string        `mapstructure:"webhook_content_security_policy"`
 }
 
 type CORSConfig struct {
@@ -83,6 +219,23 @@ type CORSConfig struct {
 	MaxAge           int      `mapstructure:"max_age"`
 }
 
+// NetworkConfig controls which networks may reach each route group. The
+// client IP is determined as configured in ServerConfig.
+type NetworkConfig struct {
+	Allowlists AllowlistConfig `mapstructure:"allowlists"`
+}
+
+// AllowlistConfig lists the IP addresses and CIDR ranges allowed per route
+// group. An empty list allows every address.
+type AllowlistConfig struct {
+	// Admin covers the /admin routes
+	Admin []string `mapstructure:"admin"`
+	// APIKeys covers the workflow endpoints called with an API key
+	APIKeys []string `mapstructure:"api_keys"`
+	// Webhooks covers the webhook routes
+	Webhooks []string `mapstructure:"webhooks"`
+}
+
 type RateLimitConfig struct {
 	Enabled  bool          `mapstructure:"enabled"`
 	Requests int           `mapstructure:"requests"`
@@ -90,6 +243,17 @@ type RateLimitConfig struct {
 	Burst    int           `mapstructure:"burst"`
 }
 
+// LoadSheddingConfig rejects requests that start executions while the
+// instance is overloaded. A threshold of zero is not checked.
+type LoadSheddingConfig struct {
+	Enabled       bool          `mapstructure:"enabled"`
+	CheckInterval time.Duration `mapstructure:"check_interval"`
+	MaxQueueDepth int64         `mapstructure:"max_queue_depth"`
+	MaxDBLatency  time.Duration `mapstructure:"max_db_latency"`
+	MaxHeapMB     int           `mapstructure:"max_heap_mb"`
+	RetryAfter    time.Duration `mapstructure:"retry_after"`
+}
+
 type EngineConfig struct {
 	MaxParallelExecutions int           `mapstructure:"max_parallel_executions"`
 	MaxExecutionTime      time.Duration `mapstructure:"max_execution_time"`
@@ -98,6 +262,29 @@ type EngineConfig struct {
 	MaxRetries           int           `mapstructure:"max_retries"`
 	RetryBackoff         time.Duration `mapstructure:"retry_backoff"`
 	CheckpointInterval   time.Duration `mapstructure:"checkpoint_interval"`
+	StuckCheckInterval   time.Duration `mapstructure:"stuck_check_interval"`
+	RequeueStuck         bool          `mapstructure:"requeue_stuck"`
+	SyncWaitTimeout      time.Duration `mapstructure:"sync_wait_timeout"`
+	MaxSyncWaitTimeout   time.Duration `mapstructure:"max_sync_wait_timeout"`
+	GraphCacheSize       int           `mapstructure:"graph_cache_size"`
+	ExpressionCacheSize  int           `mapstructure:"expression_cache_size"`
+	// ExpressionEnv lists the environment variables expressions may read
+	// as $env; the others stay out of reach of workflows
+	ExpressionEnv []string `mapstructure:"expression_env"`
+	NodeWriteBuffer      int           `mapstructure:"node_write_buffer"`
+	NodeWriteBatchSize   int           `mapstructure:"node_write_batch_size"`
+	NodeWriteInterval    time.Duration `mapstructure:"node_write_interval"`
+	// MaxSubWorkflowDepth bounds how deep sub-workflows nest; zero leaves
+	// it unbounded, cycles being refused anyway
+	MaxSubWorkflowDepth int `mapstructure:"max_sub_workflow_depth"`
+	// MaxLoopIterations bounds how many times a loop, such as that of a
+	// Split In Batches node, may run in an execution
+	MaxLoopIterations int `mapstructure:"max_loop_iterations"`
+	// NodeRetryBackoff multiplies the wait between the tries of a node
+	// retrying on fail after each retry; 1 keeps it constant
+	NodeRetryBackoff float64 `mapstructure:"node_retry_backoff"`
+	// MaxNodeRetryWait caps the wait between the tries of a node
+	MaxNodeRetryWait time.Duration `mapstructure:"max_node_retry_wait"`
 }
 
 type NodeConfig struct {
@@ -106,12 +293,86 @@ type NodeConfig struct {
 	SandboxExecution      bool          `mapstructure:"sandbox_execution"`
 	MaxDataSize          int64         `mapstructure:"max_data_size"`
 	Timeout              time.Duration `mapstructure:"timeout"`
+	Proxy                string        `mapstructure:"proxy"`
+	CustomDir            string        `mapstructure:"custom_dir"`
+	RateLimit            OutboundRateLimitConfig `mapstructure:"rate_limit"`
+	CircuitBreaker       CircuitBreakerConfig    `mapstructure:"circuit_breaker"`
+	Pool                 PoolConfig              `mapstructure:"pool"`
+	// CorrelationHeader carries the correlation ID of the execution on
+	// outbound HTTP requests; empty sends none
+	CorrelationHeader string            `mapstructure:"correlation_header"`
+	Document          DocumentConfig    `mapstructure:"document"`
+	Compression       CompressionConfig `mapstructure:"compression"`
+}
+
+// DocumentConfig configures the document node. HTML is rendered to PDF by
+// the Gotenberg service at RendererURL; empty disables rendering.
+type DocumentConfig struct {
+	RendererURL string `mapstructure:"renderer_url"`
+}
+
+// CompressionConfig bounds what the compression node extracts from one
+// archive: MaxExpandedSize bytes in all and MaxEntries files. Zero values
+// fall back to 1 GB and 10000 files.
+type CompressionConfig struct {
+	MaxExpandedSize int64 `mapstructure:"max_expanded_size"`
+	MaxEntries      int   `mapstructure:"max_entries"`
 }
 
+// PoolConfig limits the long-lived clients nodes share per credential; a
+// zero MaxClients leaves their number unbounded
+type PoolConfig struct {
+	MaxClients          int           `mapstructure:"max_clients"`
+	IdleTimeout         time.Duration `mapstructure:"idle_timeout"`
+	HealthCheckInterval time.Duration `mapstructure:"health_check_interval"`
+	HealthCheckTimeout  time.Duration `mapstructure:"health_check_timeout"`
+}
+
+// OutboundRateLimitConfig limits the API calls nodes make per credential and host
+type OutboundRateLimitConfig struct {
+	RequestsPerSecond float64         `mapstructure:"requests_per_second"`
+	Burst             int             `mapstructure:"burst"`
+	MaxRetries        int             `mapstructure:"max_retries"`
+	MaxRetryAfter     time.Duration   `mapstructure:"max_retry_after"`
+	Hosts             []HostRateLimit `mapstructure:"hosts"`
+}
+
+// CircuitBreakerConfig trips outbound calls per credential and host after
+// consecutive failures; a zero threshold disables it
+type CircuitBreakerConfig struct {
+	FailureThreshold int           `mapstructure:"failure_threshold"`
+	Cooldown         time.Duration `mapstructure:"cooldown"`
+}
+
+type HostRateLimit struct {
+	Host              string  `mapstructure:"host"`
+	RequestsPerSecond float64 `mapstructure:"requests_per_second"`
+	Burst             int     `mapstructure:"burst"`
+}
+
+// StorageConfig configures where binary data is stored. Only local storage
+// keeps binary data apart from the items; on S3 storage nodes keep it in
+// the items, stored with the execution data. Regions maps data residency
+// regions to the directories binary data of workflows tagged with them is
+// stored in instead of Local; every region of worker.regions needs one
+// when Type is local.
 type StorageConfig struct {
-	Type  string              `mapstructure:"type"`
-	Local LocalStorageConfig  `mapstructure:"local"`
-	S3    S3StorageConfig     `mapstructure:"s3"`
+	Type    string                        `mapstructure:"type"`
+	Local   LocalStorageConfig            `mapstructure:"local"`
+	S3      S3StorageConfig               `mapstructure:"s3"`
+	Regions map[string]LocalStorageConfig `mapstructure:"regions"`
+}
+
+// ForRegion returns the local storage binary data of workflows tagged with
+// region is stored in: Local for untagged workflows, and the region's
+// directory for tagged ones. ok is false for regions without a directory,
+// whose data must not be stored elsewhere.
+func (c StorageConfig) ForRegion(region string) (storage LocalStorageConfig, ok bool) {
+	if region == "" {
+		return c.Local, true
+	}
+	storage, ok = c.Regions[region]
+	return storage, ok && storage.Path != ""
 }
 
 type LocalStorageConfig struct {
@@ -172,6 +433,32 @@ type WebhookConfig struct {
 	MaxPayloadSize  int64         `mapstructure:"max_payload_size"`
 	RetryAttempts   int           `mapstructure:"retry_attempts"`
 	RetryDelay      time.Duration `mapstructure:"retry_delay"`
+	Buffer          WebhookBufferConfig `mapstructure:"buffer"`
+	CustomDomains   CustomDomainsConfig `mapstructure:"custom_domains"`
+	// CORS is the default policy for browsers calling webhooks, workflow
+	// endpoints and chats, in place of the policy of the API. Webhook
+	// triggers can set their own origins and preflight caching.
+	CORS CORSConfig `mapstructure:"cors"`
+}
+
+// WebhookBufferConfig controls buffering of workflow endpoint calls while
+// executions cannot be started, because the instance is overloaded, in
+// maintenance or cannot reach the queue. Buffered calls are acknowledged
+// with 202, stored in the database and replayed oldest first once
+// executions can be started again; calls older than MaxAge are dropped.
+type WebhookBufferConfig struct {
+	Enabled       bool          `mapstructure:"enabled"`
+	DrainInterval time.Duration `mapstructure:"drain_interval"`
+	BatchSize     int           `mapstructure:"batch_size"`
+	MaxAge        time.Duration `mapstructure:"max_age"`
+}
+
+// CustomDomainsConfig lets teams serve their workflow endpoints on their
+// own hostnames. Requests for a verified custom domain only reach the
+// endpoints of the team's workflows. With ACME TLS, certificates are also
+// issued for verified custom domains.
+type CustomDomainsConfig struct {
+	Enabled bool `mapstructure:"enabled"`
 }
 
 type SchedulerConfig struct {
@@ -179,14 +466,61 @@ type SchedulerConfig struct {
 	CheckInterval     time.Duration `mapstructure:"check_interval"`
 	Location          string        `mapstructure:"location"`
 	MaxConcurrentJobs int           `mapstructure:"max_concurrent_jobs"`
+	SpreadWindow      time.Duration `mapstructure:"spread_window"`
+	MaxJitter         time.Duration `mapstructure:"max_jitter"`
+}
+
+// LeaderConfig controls the election of the instance running singleton
+// services such as the scheduler. Backend is redis, postgres or local; when
+// empty, redis is used if configured, then postgres, and local in lite
+// mode. A crashed leader is replaced within LeaseTTL.
+type LeaderConfig struct {
+	Backend       string        `mapstructure:"backend"`
+	LeaseTTL      time.Duration `mapstructure:"lease_ttl"`
+	RenewInterval time.Duration `mapstructure:"renew_interval"`
+	RetryInterval time.Duration `mapstructure:"retry_interval"`
 }
 
+// WorkerConfig configures the workers and their queue. QueueDriver is
+// redis or postgres; the postgres driver polls its table every
+// QueuePollInterval while waiting for jobs. Routes maps job types, the
+// modes of the executions jobs run, to queues of their own, so dedicated
+// workers consume them; other jobs go to QueueName.
+//
+// Queues names further queues, such as gpu or eu-region, that workflows
+// can be pinned to for heterogeneous worker pools and data residency. Jobs
+// of pinned workflows go to their queue whatever their type. Subscriptions
+// are the queues worker processes started with the configuration consume,
+// QueueName when empty.
+//
+// Regions maps data residency regions, such as eu, to their queues. Jobs of
+// workflows tagged with a region go to its queue, to be consumed by workers
+// in the region subscribed to it.
 type WorkerConfig struct {
-	Concurrency     int           `mapstructure:"concurrency"`
-	QueueName       string        `mapstructure:"queue_name"`
-	RetryMax        int           `mapstructure:"retry_max"`
-	RetryDelay      time.Duration `mapstructure:"retry_delay"`
-	ShutdownTimeout time.Duration `mapstructure:"shutdown_timeout"`
+	Concurrency       int               `mapstructure:"concurrency"`
+	QueueName         string            `mapstructure:"queue_name"`
+	QueueDriver       string            `mapstructure:"queue_driver"`
+	QueuePollInterval time.Duration     `mapstructure:"queue_poll_interval"`
+	RetryMax          int               `mapstructure:"retry_max"`
+	RetryDelay        time.Duration     `mapstructure:"retry_delay"`
+	ShutdownTimeout   time.Duration     `mapstructure:"shutdown_timeout"`
+	HeartbeatInterval time.Duration     `mapstructure:"heartbeat_interval"`
+	HeartbeatTimeout  time.Duration     `mapstructure:"heartbeat_timeout"`
+	Routes            map[string]string `mapstructure:"routes"`
+	Queues            []string          `mapstructure:"queues"`
+	Subscriptions     []string          `mapstructure:"subscriptions"`
+	Regions           map[string]string `mapstructure:"regions"`
+	Autoscaling       AutoscalingConfig `mapstructure:"autoscaling"`
+}
+
+// AutoscalingConfig shapes the replica counts suggested for each queue's
+// workers. A replica is meant to take JobsPerReplica waiting and running
+// jobs, worker.concurrency when zero; the suggestion stays within
+// MinReplicas and MaxReplicas, the latter unbounded when zero.
+type AutoscalingConfig struct {
+	JobsPerReplica int `mapstructure:"jobs_per_replica"`
+	MinReplicas    int `mapstructure:"min_replicas"`
+	MaxReplicas    int `mapstructure:"max_replicas"`
 }
 
 type EmailConfig struct {
@@ -216,6 +550,8 @@ type OAuthProviderConfig struct {
 	Scopes       []string `mapstructure:"scopes"`
 }
 
+// FeaturesConfig holds the defaults of the built-in feature flags, used
+// until an admin stores a flag through the API
 type FeaturesConfig struct {
 	Teams         bool `mapstructure:"teams"`
 	Marketplace   bool `mapstructure:"marketplace"`
@@ -224,6 +560,11 @@ type FeaturesConfig struct {
 	APIAccess     bool `mapstructure:"api_access"`
 	OAuthLogin    bool `mapstructure:"oauth_login"`
 	TwoFactorAuth bool `mapstructure:"two_factor_auth"`
+	// Diagnostics exposes pprof and engine internals to admins
+	Diagnostics bool `mapstructure:"diagnostics"`
+	// CacheTTL is how long flags are cached per instance; toggles made on
+	// another instance apply here within it
+	CacheTTL time.Duration `mapstructure:"cache_ttl"`
 }
 
 type LimitsConfig struct {
@@ -234,6 +575,268 @@ type LimitsConfig struct {
 	MaxAPIRequestsPerMinute  int           `mapstructure:"max_api_requests_per_minute"`
 }
 
+type ApprovalConfig struct {
+	Enabled      bool     `mapstructure:"enabled"`
+	RequiredTags []string `mapstructure:"required_tags"`
+}
+
+type ChatConfig struct {
+	SessionTTL  time.Duration `mapstructure:"session_ttl"`
+	MaxMessages int           `mapstructure:"max_messages"`
+}
+
+type SettingsConfig struct {
+	CacheTTL time.Duration `mapstructure:"cache_ttl"`
+}
+
+// ExampleCallbackSecret is the callback secret of the example
+// configuration, which the server refuses to start with
+const ExampleCallbackSecret = "your-callback-secret"
+
+// CallbackConfig configures execution callbacks. Those of each workflow are
+// signed with a secret derived from Secret.
+type CallbackConfig struct {
+	Secret         string        `mapstructure:"secret"`
+	Timeout        time.Duration `mapstructure:"timeout"`
+	MaxAttempts    int           `mapstructure:"max_attempts"`
+	InitialBackoff time.Duration `mapstructure:"initial_backoff"`
+	MaxBackoff     time.Duration `mapstructure:"max_backoff"`
+	PollInterval   time.Duration `mapstructure:"poll_interval"`
+}
+
+// EventsConfig controls the in-process event bus. Each subscriber has its
+// own buffer; events arriving while it is full are dropped.
+type EventsConfig struct {
+	BufferSize     int           `mapstructure:"buffer_size"`
+	HandlerTimeout time.Duration `mapstructure:"handler_timeout"`
+}
+
+// InstanceHookConfig controls the delivery of platform events to the
+// outgoing webhooks admins register
+type InstanceHookConfig struct {
+	Enabled        bool          `mapstructure:"enabled"`
+	Timeout        time.Duration `mapstructure:"timeout"`
+	MaxAttempts    int           `mapstructure:"max_attempts"`
+	InitialBackoff time.Duration `mapstructure:"initial_backoff"`
+	MaxBackoff     time.Duration `mapstructure:"max_backoff"`
+	PollInterval   time.Duration `mapstructure:"poll_interval"`
+	// DeliveryRetention is how long delivered and failed deliveries are
+	// kept in the delivery log
+	DeliveryRetention time.Duration `mapstructure:"delivery_retention"`
+}
+
+// AlertingConfig controls the operational alerts raised when workers go
+// down, the execution queue backs up or a workflow starts failing, and the
+// channels they are sent to. Alerts are notified once when they fire and
+// once when they resolve.
+type AlertingConfig struct {
+	Enabled       bool          `mapstructure:"enabled"`
+	CheckInterval time.Duration `mapstructure:"check_interval"`
+	// RepeatInterval notifies alerts still firing again after it; zero
+	// notifies them once
+	RepeatInterval time.Duration `mapstructure:"repeat_interval"`
+	// Retention is how long resolved alerts are kept
+	Retention    time.Duration           `mapstructure:"retention"`
+	WorkerDown   WorkerDownAlertConfig   `mapstructure:"worker_down"`
+	QueueBacklog QueueBacklogAlertConfig `mapstructure:"queue_backlog"`
+	ErrorRate    ErrorRateAlertConfig    `mapstructure:"error_rate"`
+	Channels     []AlertChannelConfig    `mapstructure:"channels"`
+}
+
+// WorkerDownAlertConfig alerts on workers marked dead after missing their
+// heartbeats. The alert resolves when a worker on the same host is running
+// again or once Window has passed.
+type WorkerDownAlertConfig struct {
+	Enabled bool          `mapstructure:"enabled"`
+	Window  time.Duration `mapstructure:"window"`
+}
+
+// QueueBacklogAlertConfig alerts when more than MaxWaiting jobs wait on
+// the execution queue or the oldest has waited longer than MaxLag. Zero
+// disables either limit.
+type QueueBacklogAlertConfig struct {
+	Enabled    bool          `mapstructure:"enabled"`
+	MaxWaiting int64         `mapstructure:"max_waiting"`
+	MaxLag     time.Duration `mapstructure:"max_lag"`
+}
+
+// ErrorRateAlertConfig alerts when at least Threshold of the executions of
+// a workflow started within Window failed, once it ran MinExecutions times
+type ErrorRateAlertConfig struct {
+	Enabled       bool          `mapstructure:"enabled"`
+	Window        time.Duration `mapstructure:"window"`
+	Threshold     float64       `mapstructure:"threshold"`
+	MinExecutions int64         `mapstructure:"min_executions"`
+}
+
+// AlertChannelConfig configures one alert channel. Type is slack, teams or
+// email; the remaining fields apply to the types noted.
+type AlertChannelConfig struct {
+	Name    string        `mapstructure:"name"`
+	Type    string        `mapstructure:"type"`
+	Timeout time.Duration `mapstructure:"timeout"`
+	// slack and teams: the incoming webhook URL
+	URL string `mapstructure:"url"`
+	// email, sent with the SMTP settings of email
+	To []string `mapstructure:"to"`
+}
+
+// SLAConfig controls the evaluation of the SLA policies set on workflows.
+// MetricsWindow is the period the workflow metrics cover.
+type SLAConfig struct {
+	Enabled       bool          `mapstructure:"enabled"`
+	CheckInterval time.Duration `mapstructure:"check_interval"`
+	MetricsWindow time.Duration `mapstructure:"metrics_window"`
+}
+
+// CredentialHealthConfig controls the job testing credentials and checking
+// when their tokens and certificates expire. Owners are warned
+// ExpiryWarning before a credential expires and reminded every
+// RepeatInterval while it stays unhealthy; with NotifyEmail they are also
+// emailed.
+type CredentialHealthConfig struct {
+	Enabled        bool          `mapstructure:"enabled"`
+	CheckInterval  time.Duration `mapstructure:"check_interval"`
+	ExpiryWarning  time.Duration `mapstructure:"expiry_warning"`
+	RepeatInterval time.Duration `mapstructure:"repeat_interval"`
+	TestTimeout    time.Duration `mapstructure:"test_timeout"`
+	NotifyEmail    bool          `mapstructure:"notify_email"`
+}
+
+// AnomalyConfig controls the detection of unusually slow runs. A run is
+// anomalous when it took at least Threshold scaled median absolute
+// deviations and MinDelta longer than the median of the Window previous
+// successful runs, once there are MinSamples of them.
+type AnomalyConfig struct {
+	Enabled    bool          `mapstructure:"enabled"`
+	Window     int           `mapstructure:"window"`
+	MinSamples int           `mapstructure:"min_samples"`
+	Threshold  float64       `mapstructure:"threshold"`
+	MinDelta   time.Duration `mapstructure:"min_delta"`
+}
+
+// AnalyticsConfig controls streaming of execution events to external
+// analytics stores
+type AnalyticsConfig struct {
+	Enabled       bool              `mapstructure:"enabled"`
+	BufferSize    int               `mapstructure:"buffer_size"`
+	BatchSize     int               `mapstructure:"batch_size"`
+	FlushInterval time.Duration     `mapstructure:"flush_interval"`
+	MaxAttempts   int               `mapstructure:"max_attempts"`
+	RetryBackoff  time.Duration     `mapstructure:"retry_backoff"`
+	Sinks         []EventSinkConfig `mapstructure:"sinks"`
+}
+
+// EventSinkConfig configures one analytics sink. Type is clickhouse,
+// kafka or webhook; the remaining fields apply to the types noted.
+type EventSinkConfig struct {
+	Name    string        `mapstructure:"name"`
+	Type    string        `mapstructure:"type"`
+	Timeout time.Duration `mapstructure:"timeout"`
+	// clickhouse and webhook
+	URL     string            `mapstructure:"url"`
+	Headers map[string]string `mapstructure:"headers"`
+	// clickhouse
+	Table    string `mapstructure:"table"`
+	Username string `mapstructure:"username"`
+	Password string `mapstructure:"password"`
+	// kafka
+	Brokers []string `mapstructure:"brokers"`
+	Topic   string   `mapstructure:"topic"`
+	// webhook
+	Secret string `mapstructure:"secret"`
+}
+
+// DashboardConfig controls the rollup job behind the team dashboards.
+// Each refresh recomputes the hours within Lookback, which must exceed the
+// longest execution so late finishes are counted. Backfill bounds how far
+// back the first refresh reaches.
+type DashboardConfig struct {
+	Enabled         bool          `mapstructure:"enabled"`
+	RefreshInterval time.Duration `mapstructure:"refresh_interval"`
+	Lookback        time.Duration `mapstructure:"lookback"`
+	Backfill        time.Duration `mapstructure:"backfill"`
+}
+
+// RetentionConfig controls the job deleting executions older than the
+// executions.retention_days setting. Where executions are partitioned it
+// also creates the partitions of the next PartitionsAhead months and drops
+// whole partitions once they expire.
+type RetentionConfig struct {
+	Enabled         bool          `mapstructure:"enabled"`
+	Interval        time.Duration `mapstructure:"interval"`
+	PartitionsAhead int           `mapstructure:"partitions_ahead"`
+	BatchSize       int           `mapstructure:"batch_size"`
+}
+
+// CleanupConfig controls the job deleting orphaned resources every
+// Interval. It deletes those of Kinds only: credentials, workflows,
+// variables or binary_data. Credentials and workflows are orphaned once
+// unused for InactiveDays; binary data nothing refers to once older than
+// BinaryGracePeriod, which must outlast the longest execution.
+type CleanupConfig struct {
+	Enabled           bool          `mapstructure:"enabled"`
+	Interval          time.Duration `mapstructure:"interval"`
+	Kinds             []string      `mapstructure:"kinds"`
+	InactiveDays      int           `mapstructure:"inactive_days"`
+	BinaryGracePeriod time.Duration `mapstructure:"binary_grace_period"`
+}
+
+// UserActivityConfig controls last seen tracking and the job deactivating
+// accounts inactive beyond the users.deactivate_after_days setting. Last
+// seen times are written every FlushInterval, so they lag by up to that
+// long.
+type UserActivityConfig struct {
+	FlushInterval        time.Duration `mapstructure:"flush_interval"`
+	DeactivationEnabled  bool          `mapstructure:"deactivation_enabled"`
+	DeactivationInterval time.Duration `mapstructure:"deactivation_interval"`
+}
+
+// MigrationConfig lists the instances admins may push workflows to, such
+// as production from staging. Tokens are those of an admin on the target.
+type MigrationConfig struct {
+	Timeout time.Duration           `mapstructure:"timeout"`
+	Targets []MigrationTargetConfig `mapstructure:"targets"`
+}
+
+// MigrationTargetConfig configures one migration target. URL is the base
+// URL of the instance, without /api/v1.
+type MigrationTargetConfig struct {
+	Name  string `mapstructure:"name"`
+	URL   string `mapstructure:"url"`
+	Token string `mapstructure:"token"`
+}
+
+// AuditConfig controls the anchors signing the head of the hash chained
+// audit log every AnchorInterval. SigningKey is a base64 Ed25519 seed of 32
+// bytes; without one no anchors are written and exported bundles prove the
+// order of entries but not who vouched for them.
+type AuditConfig struct {
+	SigningKey     string        `mapstructure:"signing_key"`
+	AnchorInterval time.Duration `mapstructure:"anchor_interval"`
+}
+
+// LiteConfig selects the single binary mode for development and small
+// installs: SQLite in DataDir instead of PostgreSQL, an in-process queue
+// instead of Redis and local file storage under DataDir.
+type LiteConfig struct {
+	Enabled bool   `mapstructure:"enabled"`
+	DataDir string `mapstructure:"data_dir"`
+}
+
+// ApplyLite switches the configuration to lite mode
+func (c *Config) ApplyLite() {
+	c.Lite.Enabled = true
+	if c.Lite.DataDir == "" {
+		c.Lite.DataDir = "./data"
+	}
+	c.Database.Driver = database.DriverSQLite
+	c.Database.Name = filepath.Join(c.Lite.DataDir, "n8n.db")
+	c.Storage.Type = "local"
+	c.Storage.Local.Path = filepath.Join(c.Lite.DataDir, "storage")
+	c.Leader.Backend = "local"
+}
+
 // Load loads configuration from file and environment
 func Load() (*Config, error) {
 	viper.SetConfigFile("configs/config.yaml")
@@ -255,6 +858,9 @@ func Load() (*Config, error) {
 	
 	// Override with environment variables
 	loadEnvOverrides(&config)
+	if config.Lite.Enabled {
+		config.ApplyLite()
+	}
 	
 	return &config, nil
 }
@@ -277,4 +883,13 @@ func loadEnvOverrides(cfg *Config) {
 	if viper.IsSet("ENCRYPTION_KEY") {
 		cfg.Security.EncryptionKey = viper.GetString("ENCRYPTION_KEY")
 	}
+	if viper.IsSet("AUDIT_SIGNING_KEY") {
+		cfg.Audit.SigningKey = viper.GetString("AUDIT_SIGNING_KEY")
+	}
+	if viper.IsSet("CALLBACK_SECRET") {
+		cfg.Callbacks.Secret = viper.GetString("CALLBACK_SECRET")
+	}
+	if viper.IsSet("LITE") {
+		cfg.Lite.Enabled = viper.GetBool("LITE")
+	}
 }
@@ -0,0 +1,174 @@
+package alerting
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jaydeep/go-n8n/internal/application/worker"
+	domain "github.com/jaydeep/go-n8n/internal/domain/alert"
+	"github.com/jaydeep/go-n8n/internal/domain/execution"
+	"github.com/jaydeep/go-n8n/internal/domain/workflow"
+	"github.com/jaydeep/go-n8n/pkg/queue"
+)
+
+// Condition is a problem found by a check. Its alert fires while a check
+// keeps reporting it and resolves once it no longer does.
+type Condition struct {
+	Key     string
+	Kind    domain.Kind
+	Summary string
+	Details map[string]interface{}
+}
+
+// check reports the conditions of one kind that currently hold
+type check struct {
+	kind domain.Kind
+	run  func(ctx context.Context) ([]Condition, error)
+}
+
+// workerDown reports the hosts whose worker was marked dead within window
+// and that have no running worker since
+func workerDown(registry *worker.Registry, window time.Duration) check {
+	return check{kind: domain.KindWorkerDown, run: func(ctx context.Context) ([]Condition, error) {
+		dead, err := registry.DeadSince(ctx, time.Now().Add(-window))
+		if err != nil {
+			return nil, err
+		}
+		if len(dead) == 0 {
+			return nil, nil
+		}
+		live, err := registry.Live(ctx)
+		if err != nil {
+			return nil, err
+		}
+		running := make(map[string]bool, len(live))
+		for _, w := range live {
+			running[w.Hostname] = true
+		}
+
+		// Dead workers are in order, so the latest one of a host wins
+		byHost := make(map[string]Condition)
+		var hosts []string
+		for _, w := range dead {
+			if running[w.Hostname] {
+				continue
+			}
+			if _, ok := byHost[w.Hostname]; !ok {
+				hosts = append(hosts, w.Hostname)
+			}
+			byHost[w.Hostname] = Condition{
+				Key:     "worker_down:" + w.Hostname,
+				Kind:    domain.KindWorkerDown,
+				Summary: fmt.Sprintf("Worker on %s is down", w.Hostname),
+				Details: map[string]interface{}{
+					"hostname":          w.Hostname,
+					"worker_id":         w.ID,
+					"last_heartbeat_at": w.LastHeartbeatAt.UTC().Format(time.RFC3339),
+					"live_workers":      len(live),
+				},
+			}
+		}
+
+		conditions := make([]Condition, 0, len(hosts))
+		for _, host := range hosts {
+			conditions = append(conditions, byHost[host])
+		}
+		return conditions, nil
+	}}
+}
+
+// queueBacklog reports the execution queue when more than maxWaiting jobs
+// wait on it or the oldest waited longer than maxLag
+func queueBacklog(jobs queue.Queue, queueName string, maxWaiting int64, maxLag time.Duration) check {
+	return check{kind: domain.KindQueueBacklog, run: func(ctx context.Context) ([]Condition, error) {
+		waiting, err := jobs.Len(ctx, queueName)
+		if err != nil {
+			return nil, err
+		}
+		lag, err := jobs.Lag(ctx, queueName)
+		if err != nil {
+			return nil, err
+		}
+
+		backlogged := maxWaiting > 0 && waiting > maxWaiting
+		lagging := maxLag > 0 && lag > maxLag
+		if !backlogged && !lagging {
+			return nil, nil
+		}
+		return []Condition{{
+			Key:  "queue_backlog:" + queueName,
+			Kind: domain.KindQueueBacklog,
+			Summary: fmt.Sprintf("Execution queue %s has %d jobs waiting, the oldest for %s",
+				queueName, waiting, lag.Round(time.Second)),
+			Details: map[string]interface{}{
+				"queue":       queueName,
+				"waiting":     waiting,
+				"lag_seconds": int64(lag.Seconds()),
+				"max_waiting": maxWaiting,
+				"max_lag":     maxLag.String(),
+			},
+		}}, nil
+	}}
+}
+
+// errorRate reports the workflows of which at least threshold of the
+// executions started within window failed, once they ran minExecutions
+// times
+func errorRate(executions execution.Repository, workflows workflow.Repository, window time.Duration, threshold float64, minExecutions int64) check {
+	return check{kind: domain.KindErrorRate, run: func(ctx context.Context) ([]Condition, error) {
+		outcomes, err := executions.CountOutcomesSince(ctx, time.Now().Add(-window))
+		if err != nil {
+			return nil, err
+		}
+
+		var failing []*execution.WorkflowOutcomes
+		var ids []uuid.UUID
+		for _, o := range outcomes {
+			if o.Finished < minExecutions || float64(o.Failed)/float64(o.Finished) < threshold {
+				continue
+			}
+			failing = append(failing, o)
+			ids = append(ids, o.WorkflowID)
+		}
+		if len(failing) == 0 {
+			return nil, nil
+		}
+
+		names := make(map[uuid.UUID]string, len(ids))
+		found, err := workflows.FindByIDs(ctx, ids)
+		if err != nil {
+			return nil, err
+		}
+		for _, w := range found {
+			names[w.ID] = w.Name
+		}
+
+		conditions := make([]Condition, 0, len(failing))
+		for _, o := range failing {
+			name, ok := names[o.WorkflowID]
+			if !ok {
+				// Deleted since
+				continue
+			}
+			rate := float64(o.Failed) / float64(o.Finished)
+			conditions = append(conditions, Condition{
+				Key:  "error_rate:" + o.WorkflowID.String(),
+				Kind: domain.KindErrorRate,
+				Summary: fmt.Sprintf("Workflow %q failed %d of %d executions in the last %s",
+					name, o.Failed, o.Finished, window),
+				Details: map[string]interface{}{
+					"workflow_id":   o.WorkflowID,
+					"workflow_name": name,
+					"finished":      o.Finished,
+					"failed":        o.Failed,
+					"error_rate":    math.Round(rate*1000) / 1000,
+					"window":        window.String(),
+				},
+			})
+		}
+		return conditions, nil
+	}}
+}
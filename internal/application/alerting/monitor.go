@@ -0,0 +1,228 @@
+// Package alerting raises operational alerts, such as a worker going down
+// or the execution queue backing up, and notifies the configured channels
+// when they fire and when they resolve.
+package alerting
+
+import (
+	"context"
+	"time"
+
+	"github.com/jaydeep/go-n8n/configs"
+	"github.com/jaydeep/go-n8n/internal/application/worker"
+	domain "github.com/jaydeep/go-n8n/internal/domain/alert"
+	"github.com/jaydeep/go-n8n/internal/domain/execution"
+	"github.com/jaydeep/go-n8n/internal/domain/workflow"
+	"github.com/jaydeep/go-n8n/pkg/logger"
+	"github.com/jaydeep/go-n8n/pkg/queue"
+)
+
+const (
+	// maxListLimit bounds a page of the alert history
+	maxListLimit = 200
+	// pruneInterval bounds how often resolved alerts past the retention
+	// are removed
+	pruneInterval = time.Hour
+)
+
+// ChannelResult is the outcome of a test notification on one channel
+type ChannelResult struct {
+	Channel string `json:"channel"`
+	Success bool   `json:"success"`
+	Error   string `json:"error,omitempty"`
+}
+
+// Monitor runs the checks periodically and keeps one alert per condition:
+// it fires when a check first reports the condition and resolves once no
+// check does. Alerts are stored, so a restart or another instance taking
+// over does not notify them again.
+type Monitor struct {
+	repo     domain.Repository
+	channels []domain.Channel
+	checks   []check
+	cfg      configs.AlertingConfig
+	log      *logger.Logger
+}
+
+// NewMonitor creates a new alert monitor with the checks enabled in cfg
+func NewMonitor(repo domain.Repository, channels []domain.Channel, registry *worker.Registry, jobs queue.Queue, queueName string, executions execution.Repository, workflows workflow.Repository, cfg configs.AlertingConfig, log *logger.Logger) *Monitor {
+	if cfg.CheckInterval <= 0 {
+		cfg.CheckInterval = time.Minute
+	}
+	if cfg.WorkerDown.Window <= 0 {
+		cfg.WorkerDown.Window = time.Hour
+	}
+	if cfg.ErrorRate.Window <= 0 {
+		cfg.ErrorRate.Window = 15 * time.Minute
+	}
+	if cfg.ErrorRate.Threshold <= 0 {
+		cfg.ErrorRate.Threshold = 0.5
+	}
+	if cfg.ErrorRate.MinExecutions <= 0 {
+		cfg.ErrorRate.MinExecutions = 10
+	}
+
+	var checks []check
+	if cfg.WorkerDown.Enabled {
+		checks = append(checks, workerDown(registry, cfg.WorkerDown.Window))
+	}
+	if cfg.QueueBacklog.Enabled {
+		checks = append(checks, queueBacklog(jobs, queueName, cfg.QueueBacklog.MaxWaiting, cfg.QueueBacklog.MaxLag))
+	}
+	if cfg.ErrorRate.Enabled {
+		checks = append(checks, errorRate(executions, workflows, cfg.ErrorRate.Window, cfg.ErrorRate.Threshold, cfg.ErrorRate.MinExecutions))
+	}
+
+	return &Monitor{
+		repo:     repo,
+		channels: channels,
+		checks:   checks,
+		cfg:      cfg,
+		log:      log,
+	}
+}
+
+// Start evaluates the checks until the context is cancelled
+func (m *Monitor) Start(ctx context.Context) {
+	ticker := time.NewTicker(m.cfg.CheckInterval)
+	defer ticker.Stop()
+
+	var prunedAt time.Time
+	for {
+		if err := m.Evaluate(ctx); err != nil && ctx.Err() == nil {
+			m.log.Errorw("Alert evaluation failed", "error", err)
+		}
+		if m.cfg.Retention > 0 && time.Since(prunedAt) > pruneInterval {
+			m.prune(ctx)
+			prunedAt = time.Now()
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Evaluate runs the checks once, firing alerts for new conditions and
+// resolving those of conditions that cleared. Alerts of a check that
+// failed are left as they are.
+func (m *Monitor) Evaluate(ctx context.Context) error {
+	firing, err := m.repo.ListFiring(ctx)
+	if err != nil {
+		return err
+	}
+	byKey := make(map[string]*domain.Alert, len(firing))
+	for _, a := range firing {
+		byKey[a.Key] = a
+	}
+
+	seen := make(map[string]bool)
+	failed := make(map[domain.Kind]bool)
+	for _, c := range m.checks {
+		conditions, err := c.run(ctx)
+		if err != nil {
+			m.log.Errorw("Alert check failed", "kind", c.kind, "error", err)
+			failed[c.kind] = true
+			continue
+		}
+		for _, cond := range conditions {
+			seen[cond.Key] = true
+			m.fire(ctx, byKey[cond.Key], cond)
+		}
+	}
+
+	for _, a := range firing {
+		if seen[a.Key] || failed[a.Kind] {
+			continue
+		}
+		a.Resolve()
+		m.notify(ctx, a)
+		if err := m.repo.Update(ctx, a); err != nil {
+			m.log.Errorw("Failed to resolve alert", "alert_id", a.ID, "key", a.Key, "error", err)
+			continue
+		}
+		m.log.Infow("Alert resolved", "alert_id", a.ID, "key", a.Key)
+	}
+	return nil
+}
+
+// fire raises the alert of a condition, or refreshes the firing one,
+// notifying it again when it was never notified or RepeatInterval passed
+func (m *Monitor) fire(ctx context.Context, a *domain.Alert, cond Condition) {
+	if a == nil {
+		a = domain.NewAlert(cond.Key, cond.Kind, cond.Summary, cond.Details)
+		m.notify(ctx, a)
+		if err := m.repo.Create(ctx, a); err != nil {
+			m.log.Errorw("Failed to store alert", "key", a.Key, "error", err)
+			return
+		}
+		m.log.Warnw("Alert fired", "alert_id", a.ID, "key", a.Key, "summary", a.Summary)
+		return
+	}
+
+	a.Summary = cond.Summary
+	a.Details = cond.Details
+	if a.NotifiedAt == nil || (m.cfg.RepeatInterval > 0 && time.Since(*a.NotifiedAt) >= m.cfg.RepeatInterval) {
+		m.notify(ctx, a)
+	}
+	if err := m.repo.Update(ctx, a); err != nil {
+		m.log.Errorw("Failed to update alert", "alert_id", a.ID, "key", a.Key, "error", err)
+	}
+}
+
+// notify sends an alert to every channel and records it as notified when
+// at least one channel accepted it, so failed notifications of a firing
+// alert are retried on the next evaluation
+func (m *Monitor) notify(ctx context.Context, a *domain.Alert) {
+	if len(m.channels) == 0 {
+		return
+	}
+	delivered := false
+	for _, channel := range m.channels {
+		if err := channel.Notify(ctx, a); err != nil {
+			m.log.Errorw("Failed to send alert notification", "channel", channel.Name(), "key", a.Key, "error", err)
+			continue
+		}
+		delivered = true
+	}
+	if delivered {
+		a.Notified()
+	}
+}
+
+// List returns the latest alerts, optionally only those with status
+func (m *Monitor) List(ctx context.Context, status domain.Status, limit int) ([]*domain.Alert, error) {
+	if limit <= 0 || limit > maxListLimit {
+		limit = maxListLimit
+	}
+	return m.repo.List(ctx, status, limit)
+}
+
+// Test sends a test alert to every channel and reports the outcome per
+// channel
+func (m *Monitor) Test(ctx context.Context) []ChannelResult {
+	a := domain.NewAlert("test", domain.KindTest, "Test notification, alerting is configured", nil)
+	results := make([]ChannelResult, 0, len(m.channels))
+	for _, channel := range m.channels {
+		result := ChannelResult{Channel: channel.Name(), Success: true}
+		if err := channel.Notify(ctx, a); err != nil {
+			result.Success = false
+			result.Error = err.Error()
+		}
+		results = append(results, result)
+	}
+	return results
+}
+
+// prune removes resolved alerts older than the retention
+func (m *Monitor) prune(ctx context.Context) {
+	removed, err := m.repo.DeleteResolvedBefore(ctx, time.Now().Add(-m.cfg.Retention))
+	if err != nil {
+		m.log.Errorw("Failed to prune resolved alerts", "error", err)
+		return
+	}
+	if removed > 0 {
+		m.log.Infow("Pruned resolved alerts", "count", removed)
+	}
+}
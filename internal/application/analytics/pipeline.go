@@ -0,0 +1,186 @@
+// Package analytics streams execution events to external analytics sinks.
+package analytics
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/jaydeep/go-n8n/configs"
+	domain "github.com/jaydeep/go-n8n/internal/domain/execution"
+	"github.com/jaydeep/go-n8n/pkg/logger"
+)
+
+const (
+	defaultBufferSize    = 10000
+	defaultBatchSize     = 500
+	defaultFlushInterval = 5 * time.Second
+	defaultMaxAttempts   = 5
+	defaultRetryBackoff  = 2 * time.Second
+	// shutdownFlushTimeout bounds the final flush of buffered events
+	shutdownFlushTimeout = 10 * time.Second
+)
+
+// Pipeline fans execution events out to the analytics sinks. Each sink has
+// its own buffer and goroutine, so a slow or unavailable sink delays
+// neither executions nor the other sinks. Events arriving while a buffer
+// is full are dropped and counted rather than blocking the publisher.
+type Pipeline struct {
+	workers []*sinkWorker
+	cfg     configs.AnalyticsConfig
+	log     *logger.Logger
+}
+
+// sinkWorker batches the events of one sink
+type sinkWorker struct {
+	sink    domain.EventSink
+	events  chan *domain.Event
+	dropped atomic.Int64
+}
+
+// NewPipeline creates a pipeline delivering to sinks. With no sinks
+// Publish discards events.
+func NewPipeline(sinks []domain.EventSink, cfg configs.AnalyticsConfig, log *logger.Logger) *Pipeline {
+	if cfg.BufferSize <= 0 {
+		cfg.BufferSize = defaultBufferSize
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = defaultBatchSize
+	}
+	if cfg.FlushInterval <= 0 {
+		cfg.FlushInterval = defaultFlushInterval
+	}
+	if cfg.MaxAttempts <= 0 {
+		cfg.MaxAttempts = defaultMaxAttempts
+	}
+	if cfg.RetryBackoff <= 0 {
+		cfg.RetryBackoff = defaultRetryBackoff
+	}
+
+	p := &Pipeline{cfg: cfg, log: log}
+	for _, sink := range sinks {
+		p.workers = append(p.workers, &sinkWorker{
+			sink:   sink,
+			events: make(chan *domain.Event, cfg.BufferSize),
+		})
+	}
+	return p
+}
+
+// Publish queues an event for every sink without blocking
+func (p *Pipeline) Publish(event *domain.Event) {
+	for _, w := range p.workers {
+		select {
+		case w.events <- event:
+		default:
+			w.dropped.Add(1)
+		}
+	}
+}
+
+// Start delivers events until the context is cancelled, then flushes the
+// buffered events and closes the sinks before returning
+func (p *Pipeline) Start(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, w := range p.workers {
+		wg.Add(1)
+		go func(w *sinkWorker) {
+			defer wg.Done()
+			p.run(ctx, w)
+		}(w)
+	}
+	wg.Wait()
+}
+
+func (p *Pipeline) run(ctx context.Context, w *sinkWorker) {
+	ticker := time.NewTicker(p.cfg.FlushInterval)
+	defer ticker.Stop()
+
+	// A batch interrupted by shutdown is kept for the final drain
+	batch := make([]*domain.Event, 0, p.cfg.BatchSize)
+	for ctx.Err() == nil {
+		select {
+		case <-ctx.Done():
+		case event := <-w.events:
+			batch = append(batch, event)
+			if len(batch) >= p.cfg.BatchSize && p.flush(ctx, w, batch) {
+				batch = batch[:0]
+			}
+		case <-ticker.C:
+			if len(batch) > 0 && p.flush(ctx, w, batch) {
+				batch = batch[:0]
+			}
+			if dropped := w.dropped.Swap(0); dropped > 0 {
+				p.log.Warnw("Analytics buffer full, events dropped", "sink", w.sink.Name(), "dropped", dropped)
+			}
+		}
+	}
+	p.drain(w, batch)
+}
+
+// drain delivers the buffered events on shutdown and closes the sink
+func (p *Pipeline) drain(w *sinkWorker, batch []*domain.Event) {
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownFlushTimeout)
+	defer cancel()
+
+	for {
+		batch = fill(w.events, batch, p.cfg.BatchSize)
+		if len(batch) == 0 {
+			break
+		}
+		if !p.flush(ctx, w, batch) {
+			p.log.Warnw("Analytics events lost on shutdown", "sink", w.sink.Name(), "events", len(batch)+len(w.events))
+			break
+		}
+		batch = batch[:0]
+	}
+
+	if err := w.sink.Close(); err != nil {
+		p.log.Warnw("Failed to close analytics sink", "sink", w.sink.Name(), "error", err)
+	}
+}
+
+// fill moves buffered events into batch until it holds size events or the
+// buffer is empty
+func fill(events <-chan *domain.Event, batch []*domain.Event, size int) []*domain.Event {
+	for len(batch) < size {
+		select {
+		case event := <-events:
+			batch = append(batch, event)
+		default:
+			return batch
+		}
+	}
+	return batch
+}
+
+// flush writes a batch, retrying with exponential backoff. It returns
+// false when ctx ends before the batch is written. A batch still failing
+// after MaxAttempts is dropped so one broken sink cannot stall its buffer
+// forever.
+func (p *Pipeline) flush(ctx context.Context, w *sinkWorker, batch []*domain.Event) bool {
+	backoff := p.cfg.RetryBackoff
+	var err error
+	for attempt := 1; attempt <= p.cfg.MaxAttempts; attempt++ {
+		if err = w.sink.Write(ctx, batch); err == nil {
+			return true
+		}
+		if ctx.Err() != nil {
+			return false
+		}
+		if attempt == p.cfg.MaxAttempts {
+			break
+		}
+
+		p.log.Debugw("Analytics sink write failed, retrying", "sink", w.sink.Name(), "attempt", attempt, "error", err)
+		select {
+		case <-ctx.Done():
+			return false
+		case <-time.After(backoff):
+			backoff *= 2
+		}
+	}
+	p.log.Errorw("Dropping analytics batch", "sink", w.sink.Name(), "events", len(batch), "error", err)
+	return true
+}
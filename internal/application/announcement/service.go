@@ -0,0 +1,173 @@
+package announcement
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jaydeep/go-n8n/internal/application/audit"
+	domain "github.com/jaydeep/go-n8n/internal/domain/announcement"
+	auditdomain "github.com/jaydeep/go-n8n/internal/domain/audit"
+	"github.com/jaydeep/go-n8n/pkg/logger"
+)
+
+// Events passed to the notify function
+const (
+	EventPublished = "announcement.published"
+	EventUpdated   = "announcement.updated"
+	EventWithdrawn = "announcement.withdrawn"
+)
+
+// Input holds the fields of an announcement
+type Input struct {
+	Title     string          `json:"title"`
+	Message   string          `json:"message"`
+	Severity  domain.Severity `json:"severity"`
+	ExpiresAt *time.Time      `json:"expires_at"`
+}
+
+// UpdateInput holds the editable fields of an announcement. Nil fields are
+// left unchanged; ClearExpiry keeps the announcement until withdrawn.
+type UpdateInput struct {
+	Title       *string          `json:"title"`
+	Message     *string          `json:"message"`
+	Severity    *domain.Severity `json:"severity"`
+	ExpiresAt   *time.Time       `json:"expires_at"`
+	ClearExpiry bool             `json:"clear_expiry"`
+}
+
+// Service publishes announcements and pushes them to connected clients
+// through notify
+type Service struct {
+	repo   domain.Repository
+	audit  *audit.Service
+	notify func(event string, a *domain.Announcement)
+	log    *logger.Logger
+}
+
+// NewService creates a new announcement service. notify is called after
+// every change; it may be nil.
+func NewService(repo domain.Repository, auditService *audit.Service, notify func(event string, a *domain.Announcement), log *logger.Logger) *Service {
+	if notify == nil {
+		notify = func(string, *domain.Announcement) {}
+	}
+	return &Service{
+		repo:   repo,
+		audit:  auditService,
+		notify: notify,
+		log:    log,
+	}
+}
+
+// Active returns the announcements currently shown
+func (s *Service) Active(ctx context.Context) ([]*domain.Announcement, error) {
+	return s.repo.ListActive(ctx, time.Now())
+}
+
+// List returns every announcement including expired ones
+func (s *Service) List(ctx context.Context) ([]*domain.Announcement, error) {
+	return s.repo.List(ctx)
+}
+
+// Get returns an announcement
+func (s *Service) Get(ctx context.Context, id uuid.UUID) (*domain.Announcement, error) {
+	return s.repo.FindByID(ctx, id)
+}
+
+// Publish stores an announcement and pushes it to connected clients
+func (s *Service) Publish(ctx context.Context, actor audit.Actor, input Input) (*domain.Announcement, error) {
+	a := &domain.Announcement{
+		Title:     input.Title,
+		Message:   input.Message,
+		Severity:  input.Severity,
+		ExpiresAt: input.ExpiresAt,
+	}
+	if a.Severity == "" {
+		a.Severity = domain.SeverityInfo
+	}
+	if err := validate(a); err != nil {
+		return nil, err
+	}
+	if actor.UserID != uuid.Nil {
+		userID := actor.UserID
+		a.CreatedBy = &userID
+	}
+
+	if err := s.repo.Create(ctx, a); err != nil {
+		return nil, err
+	}
+	s.record(ctx, actor, auditdomain.ActionAnnouncementPublished, a)
+	s.notify(EventPublished, a)
+	return a, nil
+}
+
+// Update changes an announcement and pushes the change to connected clients
+func (s *Service) Update(ctx context.Context, actor audit.Actor, id uuid.UUID, input UpdateInput) (*domain.Announcement, error) {
+	a, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if input.Title != nil {
+		a.Title = *input.Title
+	}
+	if input.Message != nil {
+		a.Message = *input.Message
+	}
+	if input.Severity != nil {
+		a.Severity = *input.Severity
+	}
+	if input.ClearExpiry {
+		a.ExpiresAt = nil
+	} else if input.ExpiresAt != nil {
+		a.ExpiresAt = input.ExpiresAt
+	}
+	if err := validate(a); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Update(ctx, a); err != nil {
+		return nil, err
+	}
+	s.record(ctx, actor, auditdomain.ActionAnnouncementUpdated, a)
+	s.notify(EventUpdated, a)
+	return a, nil
+}
+
+// Withdraw deletes an announcement and tells connected clients to hide it
+func (s *Service) Withdraw(ctx context.Context, actor audit.Actor, id uuid.UUID) error {
+	a, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.Delete(ctx, id); err != nil {
+		return err
+	}
+	s.record(ctx, actor, auditdomain.ActionAnnouncementWithdrawn, a)
+	s.notify(EventWithdrawn, a)
+	return nil
+}
+
+func (s *Service) record(ctx context.Context, actor audit.Actor, action string, a *domain.Announcement) {
+	_ = s.audit.Record(ctx, actor, &auditdomain.AuditLog{
+		Action:       action,
+		ResourceType: auditdomain.ResourceAnnouncement,
+		ResourceID:   a.ID.String(),
+		NewValue: map[string]interface{}{
+			"title":      a.Title,
+			"severity":   a.Severity,
+			"expires_at": a.ExpiresAt,
+		},
+	})
+}
+
+// validate checks the fields and that the announcement is not already
+// expired, which would publish a banner nobody sees
+func validate(a *domain.Announcement) error {
+	if err := a.Validate(); err != nil {
+		return err
+	}
+	if !a.Active(time.Now()) {
+		return domain.ErrExpiryInPast
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+package audit
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jaydeep/go-n8n/configs"
+	domain "github.com/jaydeep/go-n8n/internal/domain/audit"
+	"github.com/jaydeep/go-n8n/pkg/logger"
+)
+
+const defaultAnchorInterval = time.Hour
+
+// Anchorer signs the head of the audit chain periodically, so that entries
+// up to the anchor cannot be rewritten without the signing key
+type Anchorer struct {
+	repo    domain.Repository
+	anchors domain.AnchorRepository
+	key     ed25519.PrivateKey
+	cfg     configs.AuditConfig
+	log     *logger.Logger
+}
+
+// NewAnchorer creates a new anchoring job. It fails when the signing key is
+// not a base64 Ed25519 seed.
+func NewAnchorer(repo domain.Repository, anchors domain.AnchorRepository, cfg configs.AuditConfig, log *logger.Logger) (*Anchorer, error) {
+	seed, err := base64.StdEncoding.DecodeString(cfg.SigningKey)
+	if err != nil || len(seed) != ed25519.SeedSize {
+		return nil, fmt.Errorf("audit signing key must be a base64 Ed25519 seed of %d bytes", ed25519.SeedSize)
+	}
+	if cfg.AnchorInterval <= 0 {
+		cfg.AnchorInterval = defaultAnchorInterval
+	}
+	return &Anchorer{
+		repo:    repo,
+		anchors: anchors,
+		key:     ed25519.NewKeyFromSeed(seed),
+		cfg:     cfg,
+		log:     log,
+	}, nil
+}
+
+// PublicKey returns the base64 key verifying the anchors
+func (a *Anchorer) PublicKey() string {
+	return base64.StdEncoding.EncodeToString(a.key.Public().(ed25519.PublicKey))
+}
+
+// Start runs the anchoring job until the context is cancelled
+func (a *Anchorer) Start(ctx context.Context) {
+	ticker := time.NewTicker(a.cfg.AnchorInterval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := a.Run(ctx); err != nil && ctx.Err() == nil {
+			a.log.Errorw("Audit anchoring failed", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Run signs the head of the chain unless it is anchored already. It returns
+// the new anchor, or nil when there was nothing to anchor.
+func (a *Anchorer) Run(ctx context.Context) (*domain.Anchor, error) {
+	head, err := a.repo.Head(ctx)
+	if errors.Is(err, domain.ErrAuditLogNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	latest, err := a.anchors.Latest(ctx)
+	if err == nil && latest.Sequence >= head.Sequence {
+		return nil, nil
+	}
+	if err != nil && !errors.Is(err, domain.ErrAnchorNotFound) {
+		return nil, err
+	}
+
+	anchor := &domain.Anchor{
+		Sequence:  head.Sequence,
+		Hash:      head.Hash,
+		CreatedAt: time.Now().UTC().Truncate(time.Microsecond),
+	}
+	anchor.Sign(a.key)
+	if err := a.anchors.Create(ctx, anchor); err != nil {
+		return nil, err
+	}
+	a.log.Infow("Audit chain anchored", "sequence", anchor.Sequence)
+	return anchor, nil
+}
@@ -0,0 +1,165 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	domain "github.com/jaydeep/go-n8n/internal/domain/audit"
+	"github.com/jaydeep/go-n8n/pkg/logger"
+	"github.com/jaydeep/go-n8n/pkg/pagination"
+)
+
+const (
+	// exportBatchSize is how many entries Export reads per query
+	exportBatchSize = 1000
+	// maxBundleEntries bounds the entries of one exported bundle
+	maxBundleEntries = 10000
+)
+
+// Actor identifies who performed an audited action
+type Actor struct {
+	UserID    uuid.UUID
+	IPAddress string
+	UserAgent string
+}
+
+// Service records and lists audit log entries
+type Service struct {
+	repo    domain.Repository
+	anchors domain.AnchorRepository
+	log     *logger.Logger
+}
+
+// NewService creates a new audit service
+func NewService(repo domain.Repository, anchors domain.AnchorRepository, log *logger.Logger) *Service {
+	return &Service{repo: repo, anchors: anchors, log: log}
+}
+
+// Record stores an audit entry on behalf of the actor. Failures are logged
+// and returned so callers can decide whether auditing is mandatory.
+func (s *Service) Record(ctx context.Context, actor Actor, entry *domain.AuditLog) error {
+	if actor.UserID != uuid.Nil {
+		userID := actor.UserID
+		entry.UserID = &userID
+	}
+	entry.IPAddress = actor.IPAddress
+	entry.UserAgent = actor.UserAgent
+	if entry.CreatedAt.IsZero() {
+		entry.CreatedAt = time.Now()
+	}
+
+	if err := s.repo.Create(ctx, entry); err != nil {
+		s.log.Errorw("Failed to record audit log", "action", entry.Action, "error", err)
+		return err
+	}
+	return nil
+}
+
+// Get returns an audit log entry
+func (s *Service) Get(ctx context.Context, id uuid.UUID) (*domain.AuditLog, error) {
+	return s.repo.FindByID(ctx, id)
+}
+
+// List returns a page of the entries matching filter, newest first, and the
+// cursor of the next page, which is nil on the last page
+func (s *Service) List(ctx context.Context, filter domain.ListFilter) ([]*domain.AuditLog, *pagination.Cursor, error) {
+	limit := pagination.Limit(filter.Limit)
+	filter.Limit = limit + 1
+	logs, err := s.repo.List(ctx, filter)
+	if err != nil {
+		return nil, nil, err
+	}
+	logs, next := pagination.Page(logs, limit, logCursor)
+	return logs, next, nil
+}
+
+// Export calls fn with every entry matching filter, newest first, reading
+// them in batches by cursor. It stops at the first error returned by fn.
+func (s *Service) Export(ctx context.Context, filter domain.ListFilter, fn func(*domain.AuditLog) error) error {
+	filter.Limit = exportBatchSize
+	for {
+		logs, err := s.repo.List(ctx, filter)
+		if err != nil {
+			return err
+		}
+		for _, entry := range logs {
+			if err := fn(entry); err != nil {
+				return err
+			}
+		}
+		if len(logs) < exportBatchSize {
+			return nil
+		}
+		filter.After = logCursor(logs[len(logs)-1])
+	}
+}
+
+// ExportBundle returns a verifiable bundle of up to limit entries of the
+// chain from the sequence on, with the anchors signed within them. The
+// export is itself recorded, as the newest entry of the chain.
+func (s *Service) ExportBundle(ctx context.Context, actor Actor, fromSequence int64, limit int) (*domain.Bundle, error) {
+	if fromSequence < 1 {
+		fromSequence = 1
+	}
+	if limit <= 0 || limit > maxBundleEntries {
+		limit = maxBundleEntries
+	}
+
+	bundle := &domain.Bundle{
+		Version:    domain.BundleVersion,
+		ExportedAt: time.Now().UTC(),
+		Anchors:    []*domain.Anchor{},
+	}
+	if fromSequence > 1 {
+		prev, err := s.repo.FindBySequence(ctx, fromSequence-1)
+		if err != nil {
+			return nil, err
+		}
+		bundle.PrevHash = prev.Hash
+	}
+	entries, err := s.repo.ListChain(ctx, fromSequence, limit+1)
+	if err != nil {
+		return nil, err
+	}
+	if len(entries) > limit {
+		next := entries[limit].Sequence
+		bundle.NextSequence = &next
+		entries = entries[:limit]
+	}
+	bundle.Entries = entries
+	if len(entries) > 0 {
+		bundle.Anchors, err = s.anchors.ListRange(ctx, entries[0].Sequence, entries[len(entries)-1].Sequence)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	s.Record(ctx, actor, &domain.AuditLog{
+		Action:       domain.ActionAuditExported,
+		ResourceType: domain.ResourceAuditLog,
+		NewValue: map[string]interface{}{
+			"from_sequence": fromSequence,
+			"entries":       len(entries),
+		},
+	})
+	return bundle, nil
+}
+
+func logCursor(entry *domain.AuditLog) *pagination.Cursor {
+	return pagination.After(entry.CreatedAt, entry.ID)
+}
+
+// ToMap converts a value to the generic map form stored in audit logs
+func ToMap(v interface{}) map[string]interface{} {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil
+	}
+	var m map[string]interface{}
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil
+	}
+	return m
+}
@@ -0,0 +1,135 @@
+package billing
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	domain "github.com/jaydeep/go-n8n/internal/domain/billing"
+	"github.com/jaydeep/go-n8n/internal/domain/execution"
+	"github.com/jaydeep/go-n8n/internal/domain/node"
+	"github.com/jaydeep/go-n8n/internal/domain/user"
+	"github.com/jaydeep/go-n8n/internal/domain/workflow"
+	"github.com/jaydeep/go-n8n/pkg/logger"
+)
+
+// Caller identifies who is using the service. Admins see all usage; other
+// users the usage of their own workflows and of the teams they belong to.
+type Caller struct {
+	UserID uuid.UUID
+	Admin  bool
+}
+
+// Query selects a usage report. The range defaults to the current
+// calendar month in UTC and the grouping to workflows.
+type Query struct {
+	From       time.Time
+	To         time.Time
+	GroupBy    domain.GroupBy
+	TeamID     *uuid.UUID
+	WorkflowID *uuid.UUID
+}
+
+// Report is the usage over a range, in total and per group
+type Report struct {
+	From    time.Time         `json:"from"`
+	To      time.Time         `json:"to"`
+	GroupBy domain.GroupBy    `json:"group_by"`
+	Total   domain.Summary    `json:"total"`
+	Groups  []*domain.Summary `json:"groups"`
+}
+
+// Service records the resources executions consume and reports them for
+// chargeback
+type Service struct {
+	repo      domain.Repository
+	workflows workflow.Repository
+	users     user.Repository
+	log       *logger.Logger
+}
+
+// NewService creates a new billing service
+func NewService(repo domain.Repository, workflows workflow.Repository, users user.Repository, log *logger.Logger) *Service {
+	return &Service{repo: repo, workflows: workflows, users: users, log: log}
+}
+
+// Record stores the usage of a finished execution from its node runs. It
+// is called by the engine once the execution reaches a terminal state.
+func (s *Service) Record(ctx context.Context, exec *execution.Execution, runs []node.NodeExecutionData) error {
+	w, err := s.workflows.FindByID(ctx, exec.WorkflowID)
+	if err != nil {
+		return err
+	}
+
+	usage := domain.NewExecutionUsage(exec, w.TeamID, w.UserID, runs)
+	if err := s.repo.Record(ctx, usage); err != nil {
+		s.log.Errorw("Failed to record execution usage", "execution_id", exec.ID, "error", err)
+		return err
+	}
+	return nil
+}
+
+// Usage reports the usage matching the query the caller may see
+func (s *Service) Usage(ctx context.Context, caller Caller, q Query) (*Report, error) {
+	now := time.Now().UTC()
+	if q.To.IsZero() {
+		q.To = now
+	}
+	if q.From.IsZero() {
+		q.From = time.Date(q.To.Year(), q.To.Month(), 1, 0, 0, 0, 0, time.UTC)
+	}
+	if !q.From.Before(q.To) {
+		return nil, domain.ErrInvalidRange
+	}
+	if q.GroupBy == "" {
+		q.GroupBy = domain.GroupByWorkflow
+	}
+	if !q.GroupBy.Valid() {
+		return nil, domain.ErrInvalidGroupBy
+	}
+
+	filter := domain.Filter{
+		TeamID:     q.TeamID,
+		WorkflowID: q.WorkflowID,
+		From:       q.From.UTC(),
+		To:         q.To.UTC(),
+	}
+	if err := s.authorize(ctx, caller, &filter); err != nil {
+		return nil, err
+	}
+
+	groups, err := s.repo.Summarize(ctx, filter, q.GroupBy)
+	if err != nil {
+		return nil, err
+	}
+	if groups == nil {
+		groups = []*domain.Summary{}
+	}
+
+	report := &Report{From: filter.From, To: filter.To, GroupBy: q.GroupBy, Groups: groups}
+	for _, g := range groups {
+		report.Total.Executions += g.Executions
+		report.Total.Usage.Add(g.Usage)
+	}
+	return report, nil
+}
+
+// authorize narrows the filter to the usage the caller may see. Users
+// other than admins see a team they belong to, or else their own
+// workflows.
+func (s *Service) authorize(ctx context.Context, caller Caller, filter *domain.Filter) error {
+	if caller.Admin {
+		return nil
+	}
+	if filter.TeamID == nil {
+		filter.UserID = &caller.UserID
+		return nil
+	}
+
+	_, err := s.users.FindTeamMember(ctx, *filter.TeamID, caller.UserID)
+	if errors.Is(err, user.ErrTeamMemberNotFound) {
+		return domain.ErrUsageAccessDenied
+	}
+	return err
+}
@@ -0,0 +1,198 @@
+package chat
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jaydeep/go-n8n/configs"
+	"github.com/jaydeep/go-n8n/internal/application/workflow"
+	"github.com/jaydeep/go-n8n/internal/domain/node"
+	domain "github.com/jaydeep/go-n8n/internal/domain/workflow"
+	"github.com/jaydeep/go-n8n/internal/nodes/core/trigger"
+	"github.com/jaydeep/go-n8n/pkg/logger"
+)
+
+var (
+	ErrSessionNotFound     = errors.New("chat session not found")
+	ErrMessageEmpty        = errors.New("chat message is required")
+	ErrChatTriggerNotFound = errors.New("workflow has no chat trigger")
+	ErrWorkflowInactive    = errors.New("workflow is not active")
+	ErrChatUnavailable     = errors.New("workflow execution is not available")
+)
+
+// Runner executes a workflow starting at the given trigger node and returns
+// the items produced by its last node
+type Runner interface {
+	RunChat(ctx context.Context, w *domain.Workflow, triggerNodeID string, item node.Item) ([]node.Item, error)
+}
+
+// Reply is the workflow's answer to a chat message
+type Reply struct {
+	SessionID string `json:"session_id"`
+	Message   string `json:"message"`
+}
+
+// Service routes chat messages to workflows with a chat trigger and keeps
+// the conversation of each session
+type Service struct {
+	workflows *workflow.Service
+	sessions  *SessionStore
+	runner    Runner
+	cfg       configs.ChatConfig
+	log       *logger.Logger
+}
+
+// NewService creates a new chat service. Without a runner, messages are
+// rejected with ErrChatUnavailable.
+func NewService(workflows *workflow.Service, sessions *SessionStore, runner Runner, cfg configs.ChatConfig, log *logger.Logger) *Service {
+	return &Service{
+		workflows: workflows,
+		sessions:  sessions,
+		runner:    runner,
+		cfg:       cfg,
+		log:       log,
+	}
+}
+
+// Open returns the session, starting a new one (with the trigger's initial
+// message) when sessionID is empty or unknown
+func (s *Service) Open(ctx context.Context, workflowID uuid.UUID, sessionID string) (*Session, error) {
+	_, chatNode, err := s.chatWorkflow(ctx, workflowID)
+	if err != nil {
+		return nil, err
+	}
+
+	session, err := s.loadSession(ctx, workflowID, sessionID, trigger.ParseChatSettings(chatNode.Parameters))
+	if err != nil {
+		return nil, err
+	}
+	if err := s.sessions.Save(ctx, session); err != nil {
+		return nil, err
+	}
+	return session, nil
+}
+
+// Send runs the workflow for a message and records both the message and the
+// reply in the session
+func (s *Service) Send(ctx context.Context, workflowID uuid.UUID, sessionID, text string) (*Reply, error) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return nil, ErrMessageEmpty
+	}
+
+	w, chatNode, err := s.chatWorkflow(ctx, workflowID)
+	if err != nil {
+		return nil, err
+	}
+	if s.runner == nil {
+		return nil, ErrChatUnavailable
+	}
+
+	settings := trigger.ParseChatSettings(chatNode.Parameters)
+	session, err := s.loadSession(ctx, workflowID, sessionID, settings)
+	if err != nil {
+		return nil, err
+	}
+
+	history := make([]interface{}, 0, settings.HistoryLength)
+	for _, m := range session.History(settings.HistoryLength) {
+		history = append(history, map[string]interface{}{
+			"role":    m.Role,
+			"content": m.Content,
+		})
+	}
+
+	item := node.Item{
+		JSON: map[string]interface{}{
+			"session_id":  session.ID,
+			"workflow_id": workflowID.String(),
+			"message":     text,
+			"history":     history,
+		},
+		Binary: make(map[string]node.Binary),
+	}
+
+	items, err := s.runner.RunChat(ctx, w, chatNode.ID, item)
+	if err != nil {
+		s.log.Warnw("Chat workflow failed", "workflow_id", workflowID, "session_id", session.ID, "error", err)
+		return nil, err
+	}
+
+	reply := replyText(items, settings.ResponseField)
+	session.Append(RoleUser, text, s.cfg.MaxMessages)
+	session.Append(RoleAssistant, reply, s.cfg.MaxMessages)
+	if err := s.sessions.Save(ctx, session); err != nil {
+		return nil, err
+	}
+
+	return &Reply{SessionID: session.ID, Message: reply}, nil
+}
+
+// chatWorkflow loads the active, published workflow and its chat trigger
+func (s *Service) chatWorkflow(ctx context.Context, workflowID uuid.UUID) (*domain.Workflow, *domain.Node, error) {
+	w, err := s.workflows.Published(ctx, workflowID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !w.IsActive {
+		return nil, nil, ErrWorkflowInactive
+	}
+
+	for i := range w.Nodes {
+		if w.Nodes[i].Type == trigger.ChatTriggerType && !w.Nodes[i].Disabled {
+			return w, &w.Nodes[i], nil
+		}
+	}
+	return nil, nil, ErrChatTriggerNotFound
+}
+
+// loadSession returns the stored session or a new one. Clients may choose
+// their own session IDs; a session never moves between workflows.
+func (s *Service) loadSession(ctx context.Context, workflowID uuid.UUID, sessionID string, settings trigger.ChatSettings) (*Session, error) {
+	if sessionID != "" {
+		session, err := s.sessions.Get(ctx, sessionID)
+		if err == nil {
+			if session.WorkflowID != workflowID {
+				return nil, ErrSessionNotFound
+			}
+			return session, nil
+		}
+		if !errors.Is(err, ErrSessionNotFound) {
+			return nil, err
+		}
+	} else {
+		sessionID = uuid.New().String()
+	}
+
+	now := time.Now()
+	session := &Session{
+		ID:         sessionID,
+		WorkflowID: workflowID,
+		Messages:   []Message{},
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+	if settings.InitialMessage != "" {
+		session.Append(RoleAssistant, settings.InitialMessage, s.cfg.MaxMessages)
+	}
+	return session, nil
+}
+
+// replyText extracts the reply from the first item of the workflow output
+func replyText(items []node.Item, field string) string {
+	if len(items) == 0 {
+		return ""
+	}
+	value, ok := items[0].JSON[field]
+	if !ok || value == nil {
+		return ""
+	}
+	if str, ok := value.(string); ok {
+		return str
+	}
+	return fmt.Sprint(value)
+}
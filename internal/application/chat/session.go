@@ -0,0 +1,148 @@
+package chat
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+const sessionKeyPrefix = "n8n:chat:session:"
+
+// Message roles follow the convention of chat completion APIs
+const (
+	RoleUser      = "user"
+	RoleAssistant = "assistant"
+)
+
+// Message is a single chat message
+type Message struct {
+	Role      string    `json:"role"`
+	Content   string    `json:"content"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Session is a conversation with a workflow, kept across messages
+type Session struct {
+	ID         string    `json:"id"`
+	WorkflowID uuid.UUID `json:"workflow_id"`
+	Messages   []Message `json:"messages"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// History returns up to n of the most recent messages
+func (s *Session) History(n int) []Message {
+	if n <= 0 || len(s.Messages) == 0 {
+		return []Message{}
+	}
+	if len(s.Messages) <= n {
+		return s.Messages
+	}
+	return s.Messages[len(s.Messages)-n:]
+}
+
+// Append adds a message, keeping at most max messages when max is positive
+func (s *Session) Append(role, content string, max int) {
+	now := time.Now()
+	s.Messages = append(s.Messages, Message{Role: role, Content: content, CreatedAt: now})
+	if max > 0 && len(s.Messages) > max {
+		s.Messages = s.Messages[len(s.Messages)-max:]
+	}
+	s.UpdatedAt = now
+}
+
+// SessionStore persists chat sessions in Redis with a sliding expiry. With
+// a nil client sessions are kept in process, for single binary deployments.
+type SessionStore struct {
+	client *redis.Client
+	ttl    time.Duration
+
+	mu    sync.Mutex
+	local map[string]localSession
+}
+
+// localSession is an encoded session held in process
+type localSession struct {
+	data      []byte
+	expiresAt time.Time
+}
+
+// NewSessionStore creates a new session store
+func NewSessionStore(client *redis.Client, ttl time.Duration) *SessionStore {
+	return &SessionStore{client: client, ttl: ttl, local: make(map[string]localSession)}
+}
+
+// Get loads a session
+func (s *SessionStore) Get(ctx context.Context, id string) (*Session, error) {
+	var data []byte
+	if s.client == nil {
+		s.mu.Lock()
+		entry, ok := s.local[id]
+		s.mu.Unlock()
+		if !ok || s.expired(entry) {
+			return nil, ErrSessionNotFound
+		}
+		data = entry.data
+	} else {
+		var err error
+		data, err = s.client.Get(ctx, sessionKeyPrefix+id).Bytes()
+		if errors.Is(err, redis.Nil) {
+			return nil, ErrSessionNotFound
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var session Session
+	if err := json.Unmarshal(data, &session); err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+// Save stores a session and refreshes its expiry
+func (s *SessionStore) Save(ctx context.Context, session *Session) error {
+	data, err := json.Marshal(session)
+	if err != nil {
+		return err
+	}
+	if s.client != nil {
+		return s.client.Set(ctx, sessionKeyPrefix+session.ID, data, s.ttl).Err()
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entry := localSession{data: data}
+	if s.ttl > 0 {
+		entry.expiresAt = time.Now().Add(s.ttl)
+	}
+	s.local[session.ID] = entry
+	// Expired sessions are dropped as new ones are saved
+	for id, other := range s.local {
+		if s.expired(other) {
+			delete(s.local, id)
+		}
+	}
+	return nil
+}
+
+// Delete removes a session
+func (s *SessionStore) Delete(ctx context.Context, id string) error {
+	if s.client == nil {
+		s.mu.Lock()
+		delete(s.local, id)
+		s.mu.Unlock()
+		return nil
+	}
+	return s.client.Del(ctx, sessionKeyPrefix+id).Err()
+}
+
+func (s *SessionStore) expired(entry localSession) bool {
+	return !entry.expiresAt.IsZero() && time.Now().After(entry.expiresAt)
+}
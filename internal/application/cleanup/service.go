@@ -0,0 +1,448 @@
+// Package cleanup finds the resources of the instance nothing uses any
+// more and deletes them on request or on a schedule.
+package cleanup
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jaydeep/go-n8n/configs"
+	"github.com/jaydeep/go-n8n/internal/application/audit"
+	"github.com/jaydeep/go-n8n/internal/application/credential"
+	"github.com/jaydeep/go-n8n/internal/application/workflow"
+	domain "github.com/jaydeep/go-n8n/internal/domain/cleanup"
+	credentialdomain "github.com/jaydeep/go-n8n/internal/domain/credential"
+	workflowdomain "github.com/jaydeep/go-n8n/internal/domain/workflow"
+	"github.com/jaydeep/go-n8n/pkg/binarydata"
+	"github.com/jaydeep/go-n8n/pkg/logger"
+)
+
+const (
+	defaultCleanupInterval   = 24 * time.Hour
+	defaultInactiveDays      = 90
+	defaultBinaryGracePeriod = 24 * time.Hour
+	// workflowBatch bounds a page of the workflows scanned
+	workflowBatch = 500
+)
+
+// uuidPattern matches a UUID in JSON data, such as the ID of stored binary
+// data in an item
+var uuidPattern = regexp.MustCompile(`[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}`)
+
+// CleanInput selects the kinds of orphaned resources to delete.
+// InactiveDays overrides the configured number of days.
+type CleanInput struct {
+	Kinds        []domain.Kind `json:"kinds" binding:"required"`
+	InactiveDays int           `json:"inactive_days"`
+}
+
+// Service reports and deletes orphaned resources: credentials no workflow
+// uses, inactive workflows nothing ran or edited for a while, variables
+// no expression reads, and stored binary data no execution or pinned data
+// refers to. Deletions go through the services owning the resources, so
+// they are audit logged as if made by hand.
+type Service struct {
+	repo        domain.Repository
+	workflows   workflowdomain.Repository
+	workflowSvc *workflow.Service
+	credentials credentialdomain.Repository
+	credSvc     *credential.Service
+	binary      binarydata.Store
+	cfg         configs.CleanupConfig
+	log         *logger.Logger
+}
+
+// NewService creates a new cleanup service. binary may be nil when binary
+// data is kept in the items.
+func NewService(repo domain.Repository, workflows workflowdomain.Repository, workflowSvc *workflow.Service, credentials credentialdomain.Repository, credSvc *credential.Service, binary binarydata.Store, cfg configs.CleanupConfig, log *logger.Logger) *Service {
+	if cfg.Interval <= 0 {
+		cfg.Interval = defaultCleanupInterval
+	}
+	if cfg.InactiveDays <= 0 {
+		cfg.InactiveDays = defaultInactiveDays
+	}
+	if cfg.BinaryGracePeriod <= 0 {
+		cfg.BinaryGracePeriod = defaultBinaryGracePeriod
+	}
+	return &Service{
+		repo:        repo,
+		workflows:   workflows,
+		workflowSvc: workflowSvc,
+		credentials: credentials,
+		credSvc:     credSvc,
+		binary:      binary,
+		cfg:         cfg,
+		log:         log,
+	}
+}
+
+// Start deletes the orphaned resources of the configured kinds every
+// interval until the context is cancelled
+func (s *Service) Start(ctx context.Context) {
+	var kinds []domain.Kind
+	for _, k := range s.cfg.Kinds {
+		kind := domain.Kind(k)
+		if !kind.Valid() {
+			s.log.Warnw("Unknown cleanup kind ignored", "kind", k)
+			continue
+		}
+		kinds = append(kinds, kind)
+	}
+	if len(kinds) == 0 {
+		return
+	}
+
+	ticker := time.NewTicker(s.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := s.Clean(ctx, audit.Actor{}, CleanInput{Kinds: kinds}); err != nil && ctx.Err() == nil {
+			s.log.Errorw("Cleanup failed", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Report lists the orphaned resources of kinds, or of every kind when none
+// is given. Credentials and workflows are orphaned once unused for
+// inactiveDays, or the configured number of days when zero.
+func (s *Service) Report(ctx context.Context, inactiveDays int, kinds ...domain.Kind) (*domain.Report, error) {
+	if inactiveDays < 0 {
+		return nil, domain.ErrInvalidInactiveDays
+	}
+	if inactiveDays == 0 {
+		inactiveDays = s.cfg.InactiveDays
+	}
+	selected := make(map[domain.Kind]bool, len(domain.Kinds))
+	for _, k := range kinds {
+		if !k.Valid() {
+			return nil, domain.ErrUnknownKind
+		}
+		selected[k] = true
+	}
+	if len(selected) == 0 {
+		for _, k := range domain.Kinds {
+			selected[k] = true
+		}
+	}
+
+	now := time.Now()
+	report := &domain.Report{
+		GeneratedAt:  now.UTC(),
+		InactiveDays: inactiveDays,
+		Credentials:  []domain.Credential{},
+		Workflows:    []domain.Workflow{},
+		Variables:    []domain.Variable{},
+		BinaryData:   []domain.BinaryData{},
+	}
+	cutoff := now.AddDate(0, 0, -inactiveDays)
+
+	if selected[domain.KindCredentials] || selected[domain.KindWorkflows] || selected[domain.KindVariables] {
+		usage, err := s.scanWorkflows(ctx, cutoff)
+		if err != nil {
+			return nil, err
+		}
+		if selected[domain.KindVariables] {
+			report.Variables = usage.variables
+		}
+		if selected[domain.KindWorkflows] {
+			if report.Workflows, err = s.staleWorkflows(ctx, usage.candidates, cutoff); err != nil {
+				return nil, err
+			}
+		}
+		if selected[domain.KindCredentials] {
+			if report.Credentials, err = s.unusedCredentials(ctx, usage.credentials, cutoff); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if selected[domain.KindBinaryData] {
+		lister, ok := s.binary.(binarydata.Lister)
+		report.BinaryDataListed = ok
+		if ok {
+			dangling, err := s.danglingBinaryData(ctx, lister, now.Add(-s.cfg.BinaryGracePeriod))
+			if err != nil {
+				return nil, err
+			}
+			report.BinaryData = dangling
+		}
+	}
+	return report, nil
+}
+
+// workflowUsage is what the workflows of the instance use
+type workflowUsage struct {
+	// credentials are used by a node of a draft or published version
+	credentials map[uuid.UUID]bool
+	// candidates are the inactive workflows not edited since the cutoff
+	// that no other workflow runs
+	candidates []*workflowdomain.Workflow
+	// variables are defined but never read
+	variables []domain.Variable
+}
+
+// scanWorkflows reads every workflow for the credentials, workflows and
+// variables their drafts and published versions use
+func (s *Service) scanWorkflows(ctx context.Context, cutoff time.Time) (*workflowUsage, error) {
+	usage := &workflowUsage{credentials: make(map[uuid.UUID]bool), variables: []domain.Variable{}}
+	run := make(map[uuid.UUID]bool)
+	var inactive []*workflowdomain.Workflow
+
+	for offset := 0; ; offset += workflowBatch {
+		page, err := s.workflows.List(ctx, nil, workflowBatch, offset)
+		if err != nil {
+			return nil, err
+		}
+		for _, w := range page {
+			refs := workflow.ReferencesOf(w.Nodes)
+			if w.PublishedVersion != nil && w.HasUnpublishedChanges() {
+				published, err := s.workflowSvc.Published(ctx, w.ID)
+				if err != nil {
+					return nil, err
+				}
+				publishedRefs := workflow.ReferencesOf(published.Nodes)
+				refs.Credentials = append(refs.Credentials, publishedRefs.Credentials...)
+				refs.Workflows = append(refs.Workflows, publishedRefs.Workflows...)
+				refs.Variables = append(refs.Variables, publishedRefs.Variables...)
+				refs.AllVariables = refs.AllVariables || publishedRefs.AllVariables
+			}
+
+			for _, id := range refs.Credentials {
+				usage.credentials[id] = true
+			}
+			for _, id := range refs.Workflows {
+				run[id] = true
+			}
+			if id := w.Settings.ErrorWorkflow; id != nil {
+				run[*id] = true
+			}
+			if !refs.AllVariables {
+				usage.variables = append(usage.variables, unreadVariables(w, refs.Variables)...)
+			}
+			if !w.IsActive && w.UpdatedAt.Before(cutoff) {
+				inactive = append(inactive, w)
+			}
+		}
+		if len(page) < workflowBatch {
+			break
+		}
+	}
+
+	for _, w := range inactive {
+		if !run[w.ID] {
+			usage.candidates = append(usage.candidates, w)
+		}
+	}
+	return usage, nil
+}
+
+// unreadVariables returns the variables of w not among those read, by name
+func unreadVariables(w *workflowdomain.Workflow, read []string) []domain.Variable {
+	readNames := make(map[string]bool, len(read))
+	for _, name := range read {
+		readNames[name] = true
+	}
+	var unread []domain.Variable
+	for name := range w.Variables {
+		if !readNames[name] {
+			unread = append(unread, domain.Variable{WorkflowID: w.ID, WorkflowName: w.Name, Name: name})
+		}
+	}
+	sort.Slice(unread, func(i, j int) bool { return unread[i].Name < unread[j].Name })
+	return unread
+}
+
+// staleWorkflows returns the candidates whose latest execution started
+// before the cutoff, or that have none
+func (s *Service) staleWorkflows(ctx context.Context, candidates []*workflowdomain.Workflow, cutoff time.Time) ([]domain.Workflow, error) {
+	stale := []domain.Workflow{}
+	for start := 0; start < len(candidates); start += workflowBatch {
+		batch := candidates[start:min(start+workflowBatch, len(candidates))]
+		ids := make([]uuid.UUID, len(batch))
+		for i, w := range batch {
+			ids[i] = w.ID
+		}
+		last, err := s.repo.LastExecutedAt(ctx, ids)
+		if err != nil {
+			return nil, err
+		}
+		for _, w := range batch {
+			entry := domain.Workflow{ID: w.ID, Name: w.Name, OwnerID: w.UserID, UpdatedAt: w.UpdatedAt}
+			if at, ok := last[w.ID]; ok {
+				if !at.Before(cutoff) {
+					continue
+				}
+				entry.LastExecutedAt = &at
+			}
+			stale = append(stale, entry)
+		}
+	}
+	return stale, nil
+}
+
+// unusedCredentials returns the credentials created before the cutoff that
+// are not used
+func (s *Service) unusedCredentials(ctx context.Context, used map[uuid.UUID]bool, cutoff time.Time) ([]domain.Credential, error) {
+	credentials, err := s.credentials.List(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	unused := []domain.Credential{}
+	for _, c := range credentials {
+		if used[c.ID] || !c.CreatedAt.Before(cutoff) {
+			continue
+		}
+		unused = append(unused, domain.Credential{ID: c.ID, Name: c.Name, Type: c.Type, OwnerID: c.UserID, CreatedAt: c.CreatedAt})
+	}
+	return unused, nil
+}
+
+// danglingBinaryData returns the stored binary data written before the
+// given time that no execution, node run, waiting execution or pinned data
+// refers to
+func (s *Service) danglingBinaryData(ctx context.Context, lister binarydata.Lister, before time.Time) ([]domain.BinaryData, error) {
+	candidates := make(map[string]domain.BinaryData)
+	err := lister.List(ctx, func(o binarydata.Object) error {
+		if o.ModifiedAt.Before(before) {
+			candidates[o.ID] = domain.BinaryData{ID: o.ID, Size: o.Size, ModifiedAt: o.ModifiedAt.UTC()}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(candidates) > 0 {
+		err = s.repo.ScanData(ctx, func(data string) {
+			for _, id := range uuidPattern.FindAllString(data, -1) {
+				delete(candidates, id)
+			}
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	dangling := make([]domain.BinaryData, 0, len(candidates))
+	for _, b := range candidates {
+		dangling = append(dangling, b)
+	}
+	sort.Slice(dangling, func(i, j int) bool { return dangling[i].ModifiedAt.Before(dangling[j].ModifiedAt) })
+	return dangling, nil
+}
+
+// Clean deletes the orphaned resources of the selected kinds as reported
+// at the time. Failures to delete single resources are returned in the
+// result rather than stopping the cleanup.
+func (s *Service) Clean(ctx context.Context, actor audit.Actor, in CleanInput) (*domain.Result, error) {
+	if len(in.Kinds) == 0 {
+		return nil, domain.ErrKindsRequired
+	}
+	report, err := s.Report(ctx, in.InactiveDays, in.Kinds...)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &domain.Result{Deleted: make(map[domain.Kind]int, len(in.Kinds)), Failed: []domain.Failure{}}
+	for _, k := range in.Kinds {
+		result.Deleted[k] = 0
+	}
+	fail := func(kind domain.Kind, id string, err error) {
+		result.Failed = append(result.Failed, domain.Failure{Kind: kind, ID: id, Error: err.Error()})
+	}
+
+	cutoff := report.GeneratedAt.AddDate(0, 0, -report.InactiveDays)
+	for _, w := range report.Workflows {
+		deleted, err := s.deleteWorkflow(ctx, actor, w.ID, cutoff)
+		if err != nil {
+			fail(domain.KindWorkflows, w.ID.String(), err)
+			continue
+		}
+		if deleted {
+			result.Deleted[domain.KindWorkflows]++
+		}
+	}
+
+	// Variables are removed after the workflows, as removing them counts
+	// as an edit that would keep their workflows from being deleted
+	byWorkflow := make(map[uuid.UUID][]string)
+	for _, v := range report.Variables {
+		byWorkflow[v.WorkflowID] = append(byWorkflow[v.WorkflowID], v.Name)
+	}
+	for id, names := range byWorkflow {
+		err := s.removeVariables(ctx, id, names)
+		if errors.Is(err, workflowdomain.ErrWorkflowNotFound) {
+			continue
+		}
+		if err != nil {
+			fail(domain.KindVariables, id.String(), err)
+			continue
+		}
+		result.Deleted[domain.KindVariables] += len(names)
+	}
+
+	for _, c := range report.Credentials {
+		if err := s.credSvc.Delete(ctx, actor, c.ID); err != nil && !errors.Is(err, credentialdomain.ErrCredentialNotFound) {
+			fail(domain.KindCredentials, c.ID.String(), err)
+			continue
+		}
+		result.Deleted[domain.KindCredentials]++
+	}
+
+	for _, b := range report.BinaryData {
+		if err := s.binary.Delete(ctx, b.ID); err != nil && !errors.Is(err, binarydata.ErrNotFound) {
+			fail(domain.KindBinaryData, b.ID, err)
+			continue
+		}
+		result.Deleted[domain.KindBinaryData]++
+	}
+
+	s.log.Infow("Orphaned resources cleaned", "user_id", actor.UserID, "deleted", result.Deleted, "failed", len(result.Failed))
+	return result, nil
+}
+
+// removeVariables removes variables from the draft of a workflow; the
+// published version reads the variables of the draft too
+func (s *Service) removeVariables(ctx context.Context, id uuid.UUID, names []string) error {
+	w, err := s.workflowSvc.Get(ctx, id)
+	if err != nil {
+		return err
+	}
+	variables := make(map[string]interface{}, len(w.Variables))
+	for name, value := range w.Variables {
+		variables[name] = value
+	}
+	for _, name := range names {
+		delete(variables, name)
+	}
+	_, err = s.workflowSvc.UpdateDraft(ctx, id, workflow.DraftInput{Variables: variables})
+	return err
+}
+
+// deleteWorkflow deletes a workflow unless it was activated or edited
+// since the report, reporting whether it did
+func (s *Service) deleteWorkflow(ctx context.Context, actor audit.Actor, id uuid.UUID, cutoff time.Time) (bool, error) {
+	w, err := s.workflowSvc.Get(ctx, id)
+	if errors.Is(err, workflowdomain.ErrWorkflowNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if w.IsActive || !w.UpdatedAt.Before(cutoff) {
+		return false, nil
+	}
+	if err := s.workflowSvc.Delete(ctx, id, actor); err != nil {
+		return false, err
+	}
+	return true, nil
+}
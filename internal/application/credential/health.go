@@ -0,0 +1,417 @@
+package credential
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jaydeep/go-n8n/configs"
+	"github.com/jaydeep/go-n8n/internal/application/events"
+	domain "github.com/jaydeep/go-n8n/internal/domain/credential"
+	"github.com/jaydeep/go-n8n/internal/domain/workflow"
+	"github.com/jaydeep/go-n8n/pkg/encryption"
+	"github.com/jaydeep/go-n8n/pkg/logger"
+	"github.com/jaydeep/go-n8n/pkg/nodesdk"
+)
+
+const (
+	defaultHealthInterval = 6 * time.Hour
+	defaultExpiryWarning  = 7 * 24 * time.Hour
+	defaultTestTimeout    = 10 * time.Second
+	// workflowBatch bounds a page of the workflows scanned for those using
+	// a credential
+	workflowBatch = 500
+	// maxTestBody bounds how much of a test response is read
+	maxTestBody = 64 << 10
+)
+
+var (
+	// expiryFields are the fields of credential data holding when a token
+	// expires, as RFC 3339 or seconds since the epoch
+	expiryFields = map[string]bool{
+		"expires_at":               true,
+		"expiry":                   true,
+		"expiry_date":              true,
+		"refresh_token_expires_at": true,
+	}
+	// tokenFields are the fields of credential data holding tokens that may
+	// be JWTs with an exp claim
+	tokenFields = map[string]bool{
+		"access_token": true,
+		"token":        true,
+		"id_token":     true,
+	}
+)
+
+// HealthMonitor periodically tests the credentials that have a test_url
+// and checks when their tokens and certificates expire. The owner of a
+// credential is told when it becomes unhealthy, reminded while it stays
+// so, and told when it recovers, before the workflows using it fail.
+type HealthMonitor struct {
+	repo      domain.Repository
+	workflows workflow.Repository
+	cipher    *encryption.Cipher
+	client    *http.Client
+	events    events.Publisher
+	cfg       configs.CredentialHealthConfig
+	log       *logger.Logger
+}
+
+// NewHealthMonitor creates a new credential health monitor. Without a
+// cipher no credential can be checked.
+func NewHealthMonitor(repo domain.Repository, workflows workflow.Repository, cipher *encryption.Cipher, publisher events.Publisher, cfg configs.CredentialHealthConfig, log *logger.Logger) *HealthMonitor {
+	if cfg.CheckInterval <= 0 {
+		cfg.CheckInterval = defaultHealthInterval
+	}
+	if cfg.ExpiryWarning <= 0 {
+		cfg.ExpiryWarning = defaultExpiryWarning
+	}
+	if cfg.TestTimeout <= 0 {
+		cfg.TestTimeout = defaultTestTimeout
+	}
+	return &HealthMonitor{
+		repo:      repo,
+		workflows: workflows,
+		cipher:    cipher,
+		client:    &http.Client{Timeout: cfg.TestTimeout},
+		events:    publisher,
+		cfg:       cfg,
+		log:       log,
+	}
+}
+
+// Start checks the credentials until the context is cancelled
+func (m *HealthMonitor) Start(ctx context.Context) {
+	ticker := time.NewTicker(m.cfg.CheckInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := m.Run(ctx); err != nil && ctx.Err() == nil {
+			m.log.Errorw("Credential health check failed", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Run checks every credential once
+func (m *HealthMonitor) Run(ctx context.Context) error {
+	if m.cipher == nil {
+		return domain.ErrSecretsUnavailable
+	}
+	credentials, err := m.repo.ListWithSecrets(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	var unhealthy int
+	for _, c := range credentials {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		health, err := m.Check(ctx, c)
+		if err != nil {
+			m.log.Errorw("Failed to check credential health", "credential_id", c.ID, "error", err)
+			continue
+		}
+		if !health.Healthy() {
+			unhealthy++
+		}
+	}
+	if unhealthy > 0 {
+		m.log.Infow("Checked credential health", "credentials", len(credentials), "unhealthy", unhealthy)
+	}
+	return nil
+}
+
+// Check tests a credential, loaded with its secrets, and checks when it
+// expires. The outcome is stored, and published as CredentialUnhealthy
+// when the credential becomes unhealthy or RepeatInterval passed since the
+// owner was last told, and as CredentialRecovered when it recovers.
+func (m *HealthMonitor) Check(ctx context.Context, c *domain.Credential) (*domain.Health, error) {
+	if m.cipher == nil {
+		return nil, domain.ErrSecretsUnavailable
+	}
+	plain, err := m.cipher.Decrypt(c.Data, c.IV)
+	if err != nil {
+		return nil, err
+	}
+	var data map[string]interface{}
+	if err := json.Unmarshal(plain, &data); err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	health := &domain.Health{Status: domain.HealthOK, CheckedAt: now, Since: now}
+	if expires, ok := expiry(data); ok {
+		health.ExpiresAt = &expires
+		switch left := expires.Sub(now); {
+		case left <= 0:
+			health.Status = domain.HealthExpired
+			health.Message = fmt.Sprintf("expired on %s", expires.Format(time.RFC3339))
+		case left <= m.cfg.ExpiryWarning:
+			health.Status = domain.HealthExpiring
+			health.Message = fmt.Sprintf("expires on %s, in %s", expires.Format(time.RFC3339), left.Round(time.Minute))
+		}
+	}
+	if target := nodesdk.GetString(data, "test_url", ""); target != "" {
+		health.Tested = true
+		// A failing test outweighs an expiry
+		if err := m.test(ctx, target, data); err != nil {
+			health.Status = domain.HealthFailing
+			health.Message = err.Error()
+		}
+	}
+
+	previous := c.Health
+	if previous != nil && previous.Status == health.Status {
+		health.Since = previous.Since
+		health.NotifiedAt = previous.NotifiedAt
+	}
+	var notice events.Type
+	switch {
+	case !health.Healthy() && (health.NotifiedAt == nil ||
+		(m.cfg.RepeatInterval > 0 && now.Sub(*health.NotifiedAt) >= m.cfg.RepeatInterval)):
+		notice = events.CredentialUnhealthy
+		health.NotifiedAt = &now
+	case health.Healthy() && !previous.Healthy():
+		notice = events.CredentialRecovered
+	}
+
+	if err := m.repo.UpdateHealth(ctx, c.ID, health); err != nil {
+		return nil, err
+	}
+	c.Health = health
+	if notice != "" {
+		workflows, err := m.activeWorkflows(ctx, c.ID)
+		if err != nil {
+			m.log.Errorw("Failed to list the workflows using a credential", "credential_id", c.ID, "error", err)
+		}
+		m.log.Infow("Credential health changed", "credential_id", c.ID, "status", health.Status, "message", health.Message)
+		m.events.Publish(events.NewCredentialHealthEvent(notice, c, workflows))
+	}
+	return health, nil
+}
+
+// Unhealthy returns the credentials owned by userID, or all credentials
+// when userID is nil, whose latest check found them unhealthy
+func (m *HealthMonitor) Unhealthy(ctx context.Context, userID *uuid.UUID) ([]*domain.Credential, error) {
+	credentials, err := m.repo.List(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	unhealthy := []*domain.Credential{}
+	for _, c := range credentials {
+		if !c.Health.Healthy() {
+			unhealthy = append(unhealthy, c)
+		}
+	}
+	return unhealthy, nil
+}
+
+// test sends a GET request to the test URL of a credential, authenticated
+// with it, and fails unless it succeeds
+func (m *HealthMonitor) test(ctx context.Context, target string, data map[string]interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return errors.New("invalid test URL")
+	}
+	if err := nodesdk.ApplyAuth(req, nodesdk.Params(data)); err != nil {
+		return err
+	}
+	resp, err := m.client.Do(req)
+	if err != nil {
+		// The URL may carry the secret as a query parameter
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return fmt.Errorf("test request failed: %v", err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, maxTestBody))
+
+	switch {
+	case resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden:
+		return fmt.Errorf("test request was rejected with status %d", resp.StatusCode)
+	case resp.StatusCode >= http.StatusBadRequest:
+		return fmt.Errorf("test request failed with status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// activeWorkflows returns the active workflows with nodes using a
+// credential
+func (m *HealthMonitor) activeWorkflows(ctx context.Context, id uuid.UUID) ([]events.WorkflowRef, error) {
+	refs := []events.WorkflowRef{}
+	for offset := 0; ; offset += workflowBatch {
+		page, err := m.workflows.List(ctx, nil, workflowBatch, offset)
+		if err != nil {
+			return refs, err
+		}
+		for _, w := range page {
+			if w.IsActive && usesCredential(w, id) {
+				refs = append(refs, events.WorkflowRef{ID: w.ID, Name: w.Name})
+			}
+		}
+		if len(page) < workflowBatch {
+			return refs, nil
+		}
+	}
+}
+
+// usesCredential reports whether an enabled node of w uses a credential
+func usesCredential(w *workflow.Workflow, id uuid.UUID) bool {
+	for _, n := range w.Nodes {
+		if !n.Disabled && n.CredentialID != nil && *n.CredentialID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// HealthNotice returns the subject and body of the email telling the owner
+// of a credential of its health
+func HealthNotice(e events.CredentialHealth) (string, string) {
+	var subject string
+	var body strings.Builder
+	if e.Type == events.CredentialRecovered {
+		subject = fmt.Sprintf("Credential %q is healthy again", e.CredentialName)
+		fmt.Fprintf(&body, "The credential %q (%s) passed its health check.\n", e.CredentialName, e.CredentialType)
+		return subject, body.String()
+	}
+
+	subject = fmt.Sprintf("Credential %q is %s", e.CredentialName, e.Health.Status)
+	fmt.Fprintf(&body, "The credential %q (%s) is %s: %s.\n", e.CredentialName, e.CredentialType, e.Health.Status, e.Health.Message)
+	if len(e.Workflows) > 0 {
+		body.WriteString("\nActive workflows using it:\n")
+		for _, w := range e.Workflows {
+			fmt.Fprintf(&body, "- %s (%s)\n", w.Name, w.ID)
+		}
+	}
+	body.WriteString("\nUpdate the credential to keep these workflows running.\n")
+	return subject, body.String()
+}
+
+// expiry returns when the first of the tokens and certificates in
+// credential data expires: the expiry fields, the exp claim of JWT tokens
+// and the validity of PEM certificates, at any depth
+func expiry(data map[string]interface{}) (time.Time, bool) {
+	var first time.Time
+	found := false
+	see := func(t time.Time) {
+		if !found || t.Before(first) {
+			first, found = t, true
+		}
+	}
+
+	var walk func(key string, v interface{})
+	walk = func(key string, v interface{}) {
+		switch v := v.(type) {
+		case map[string]interface{}:
+			for k, item := range v {
+				walk(k, item)
+			}
+		case []interface{}:
+			for _, item := range v {
+				walk(key, item)
+			}
+		case float64:
+			if expiryFields[key] {
+				see(epoch(v))
+			}
+		case string:
+			switch {
+			case expiryFields[key]:
+				if t, ok := parseExpiry(v); ok {
+					see(t)
+				}
+			case tokenFields[key]:
+				if t, ok := tokenExpiry(v); ok {
+					see(t)
+				}
+			case strings.Contains(v, "-----BEGIN CERTIFICATE-----"):
+				for _, t := range certificateExpiries(v) {
+					see(t)
+				}
+			}
+		}
+	}
+	walk("", data)
+	return first, found
+}
+
+// parseExpiry parses an expiry as RFC 3339 or seconds since the epoch
+func parseExpiry(s string) (time.Time, bool) {
+	s = strings.TrimSpace(s)
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t.UTC(), true
+	}
+	if n, err := strconv.ParseFloat(s, 64); err == nil {
+		return epoch(n), true
+	}
+	return time.Time{}, false
+}
+
+// epoch converts seconds since the epoch, or milliseconds for values too
+// large to be seconds, to a time
+func epoch(n float64) time.Time {
+	if math.Abs(n) >= 1e12 {
+		return time.UnixMilli(int64(n)).UTC()
+	}
+	return time.Unix(int64(n), 0).UTC()
+}
+
+// tokenExpiry returns the exp claim of a JWT, without verifying it
+func tokenExpiry(token string) (time.Time, bool) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return time.Time{}, false
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+	if err != nil {
+		return time.Time{}, false
+	}
+	var claims struct {
+		Exp *float64 `json:"exp"`
+	}
+	if json.Unmarshal(payload, &claims) != nil || claims.Exp == nil {
+		return time.Time{}, false
+	}
+	return epoch(*claims.Exp), true
+}
+
+// certificateExpiries returns when the PEM certificates in s expire
+func certificateExpiries(s string) []time.Time {
+	var out []time.Time
+	rest := []byte(s)
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			return out
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		if cert, err := x509.ParseCertificate(block.Bytes); err == nil {
+			out = append(out, cert.NotAfter.UTC())
+		}
+	}
+}
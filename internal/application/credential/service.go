@@ -0,0 +1,229 @@
+package credential
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jaydeep/go-n8n/internal/application/audit"
+	"github.com/jaydeep/go-n8n/internal/application/events"
+	auditlog "github.com/jaydeep/go-n8n/internal/domain/audit"
+	domain "github.com/jaydeep/go-n8n/internal/domain/credential"
+	"github.com/jaydeep/go-n8n/pkg/encryption"
+)
+
+// Input holds the fields of a credential a client manages
+type Input struct {
+	Name string `json:"name"`
+	// Slug sets the credential's slug; an empty slug removes it and nil
+	// keeps it
+	Slug      *string  `json:"slug"`
+	Type      string   `json:"type"`
+	NodeTypes []string `json:"node_types"`
+	// Data holds the secrets; nil keeps the stored ones
+	Data map[string]interface{} `json:"data"`
+}
+
+// Service manages credentials for clients that create them under their
+// own IDs and replace them whole, such as infrastructure as code tools
+type Service struct {
+	repo   domain.Repository
+	cipher *encryption.Cipher
+	audit  *audit.Service
+	events events.Publisher
+}
+
+// NewService creates a new credential service. Without a cipher secrets
+// can be neither stored nor compared.
+func NewService(repo domain.Repository, cipher *encryption.Cipher, auditService *audit.Service, publisher events.Publisher) *Service {
+	return &Service{
+		repo:   repo,
+		cipher: cipher,
+		audit:  auditService,
+		events: publisher,
+	}
+}
+
+// Get returns a credential
+func (s *Service) Get(ctx context.Context, id uuid.UUID) (*domain.Credential, error) {
+	return s.repo.FindByID(ctx, id)
+}
+
+// FindBySlug returns the credential with the slug
+func (s *Service) FindBySlug(ctx context.Context, slug string) (*domain.Credential, error) {
+	return s.repo.FindBySlug(ctx, slug)
+}
+
+// Create stores a new credential under an ID chosen by the client, so that
+// creating it can be retried without duplicates
+func (s *Service) Create(ctx context.Context, actor audit.Actor, id, ownerID uuid.UUID, input Input) (*domain.Credential, error) {
+	if input.Data == nil {
+		return nil, domain.ErrCredentialDataRequired
+	}
+	c := &domain.Credential{ID: id, UserID: ownerID}
+	if _, err := s.apply(c, input); err != nil {
+		return nil, err
+	}
+	if err := s.checkUnique(ctx, c); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Create(ctx, c); err != nil {
+		return nil, err
+	}
+	s.events.Publish(events.NewCredentialEvent(events.CredentialCreated, c, actor))
+	return c, nil
+}
+
+// Update replaces the fields of a credential. Updates leaving it as it was,
+// secrets included, are not saved, so its content hash stays the same and
+// repeating an update changes nothing.
+func (s *Service) Update(ctx context.Context, actor audit.Actor, id uuid.UUID, input Input) (*domain.Credential, error) {
+	c, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	changed, err := s.apply(c, input)
+	if err != nil || !changed {
+		return c, err
+	}
+	if err := s.checkUnique(ctx, c); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Update(ctx, c); err != nil {
+		return nil, err
+	}
+	s.record(ctx, actor, auditlog.ActionCredentialUpdated, c)
+	return c, nil
+}
+
+// Delete removes a credential
+func (s *Service) Delete(ctx context.Context, actor audit.Actor, id uuid.UUID) error {
+	c, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.Delete(ctx, id); err != nil {
+		return err
+	}
+	s.record(ctx, actor, auditlog.ActionCredentialDeleted, c)
+	return nil
+}
+
+// apply sets the input on c, encrypting its secrets unless they equal the
+// stored ones, and reports whether anything changed
+func (s *Service) apply(c *domain.Credential, input Input) (bool, error) {
+	input.Name = strings.TrimSpace(input.Name)
+	if input.Name == "" {
+		return false, domain.ErrCredentialNameRequired
+	}
+	if input.Type == "" {
+		return false, domain.ErrCredentialTypeRequired
+	}
+	if input.NodeTypes == nil {
+		input.NodeTypes = []string{}
+	}
+	slug := c.Slug
+	if input.Slug != nil {
+		slug = nil
+		if *input.Slug != "" {
+			if err := domain.ValidateSlug(*input.Slug); err != nil {
+				return false, err
+			}
+			value := *input.Slug
+			slug = &value
+		}
+	}
+
+	changed := c.Name != input.Name || c.Type != input.Type ||
+		!reflect.DeepEqual(c.Slug, slug) || !equalStrings(c.NodeTypes, input.NodeTypes)
+	c.Name = input.Name
+	c.Slug = slug
+	c.Type = input.Type
+	c.NodeTypes = input.NodeTypes
+	if input.Data == nil {
+		return changed, nil
+	}
+
+	if s.cipher == nil {
+		return false, domain.ErrSecretsUnavailable
+	}
+	secret, err := json.Marshal(input.Data)
+	if err != nil {
+		return false, err
+	}
+	if c.Data != nil {
+		stored, err := s.cipher.Decrypt(c.Data, c.IV)
+		if err != nil {
+			return false, err
+		}
+		if equalJSON(stored, secret) {
+			return changed, nil
+		}
+	}
+	c.Data, c.IV, err = s.cipher.Encrypt(secret)
+	if err != nil {
+		return false, err
+	}
+	// The health of the old secrets says nothing of the new ones
+	c.Health = nil
+	return true, nil
+}
+
+// checkUnique fails when another credential has the slug of c, or the
+// owner of c has another credential with its name
+func (s *Service) checkUnique(ctx context.Context, c *domain.Credential) error {
+	existing, err := s.repo.FindByName(ctx, c.UserID, c.Name)
+	if err == nil && existing.ID != c.ID {
+		return domain.ErrCredentialNameTaken
+	}
+	if err != nil && !errors.Is(err, domain.ErrCredentialNotFound) {
+		return err
+	}
+	if c.Slug == nil {
+		return nil
+	}
+	existing, err = s.repo.FindBySlug(ctx, *c.Slug)
+	if err == nil && existing.ID != c.ID {
+		return domain.ErrCredentialSlugTaken
+	}
+	if err != nil && !errors.Is(err, domain.ErrCredentialNotFound) {
+		return err
+	}
+	return nil
+}
+
+// record logs a change of a credential, never its secrets
+func (s *Service) record(ctx context.Context, actor audit.Actor, action string, c *domain.Credential) {
+	s.audit.Record(ctx, actor, &auditlog.AuditLog{
+		Action:       action,
+		ResourceType: auditlog.ResourceCredential,
+		ResourceID:   c.ID.String(),
+		NewValue:     map[string]interface{}{"name": c.Name, "type": c.Type},
+	})
+}
+
+// equalJSON reports whether two JSON documents hold the same values
+func equalJSON(a, b []byte) bool {
+	var va, vb interface{}
+	if json.Unmarshal(a, &va) != nil || json.Unmarshal(b, &vb) != nil {
+		return false
+	}
+	return reflect.DeepEqual(va, vb)
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,300 @@
+// Package credential manages credentials and moves them between instances,
+// re-encrypting their secrets on the way out and in.
+package credential
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jaydeep/go-n8n/internal/application/audit"
+	"github.com/jaydeep/go-n8n/internal/application/events"
+	auditlog "github.com/jaydeep/go-n8n/internal/domain/audit"
+	domain "github.com/jaydeep/go-n8n/internal/domain/credential"
+	"github.com/jaydeep/go-n8n/internal/domain/user"
+	"github.com/jaydeep/go-n8n/pkg/encryption"
+)
+
+// minPassphraseLength is the shortest passphrase a bundle is encrypted with
+const minPassphraseLength = 12
+
+// Policies for imported credentials that already exist, by ID or by name
+// for the same owner
+const (
+	ConflictSkip      = "skip"
+	ConflictOverwrite = "overwrite"
+)
+
+// ExportInput selects the credentials to export
+type ExportInput struct {
+	Passphrase string `json:"passphrase"`
+	// CredentialIDs limits the export; every credential is exported when
+	// empty
+	CredentialIDs []uuid.UUID `json:"credential_ids"`
+}
+
+// ImportInput holds a bundle to import
+type ImportInput struct {
+	Passphrase string         `json:"passphrase"`
+	Bundle     *domain.Bundle `json:"bundle"`
+	// OwnerID owns the credentials whose owner email matches no user of
+	// the instance; it defaults to the importing user
+	OwnerID *uuid.UUID `json:"owner_id"`
+	// OnConflict is skip, the default, or overwrite
+	OnConflict string `json:"on_conflict"`
+}
+
+// ImportResult describes what an import did with each credential
+type ImportResult struct {
+	Created []uuid.UUID         `json:"created"`
+	Updated []uuid.UUID         `json:"updated"`
+	Skipped []SkippedCredential `json:"skipped"`
+}
+
+// SkippedCredential is a credential of a bundle that was not imported
+type SkippedCredential struct {
+	ID     uuid.UUID `json:"id"`
+	Name   string    `json:"name"`
+	Reason string    `json:"reason"`
+}
+
+// Transfer exports credentials into passphrase encrypted bundles and
+// imports them, re-encrypting their secrets with the instance key
+type Transfer struct {
+	repo   domain.Repository
+	users  user.Repository
+	cipher *encryption.Cipher
+	audit  *audit.Service
+	events events.Publisher
+}
+
+// NewTransfer creates a new credential transfer service. Without a cipher
+// credentials can be neither exported nor imported.
+func NewTransfer(repo domain.Repository, users user.Repository, cipher *encryption.Cipher, auditService *audit.Service, publisher events.Publisher) *Transfer {
+	return &Transfer{
+		repo:   repo,
+		users:  users,
+		cipher: cipher,
+		audit:  auditService,
+		events: publisher,
+	}
+}
+
+// Export returns a bundle of the selected credentials with their secrets
+// encrypted under the passphrase
+func (t *Transfer) Export(ctx context.Context, actor audit.Actor, input ExportInput) (*domain.Bundle, error) {
+	if t.cipher == nil {
+		return nil, domain.ErrEncryptionKeyRequired
+	}
+	if len(input.Passphrase) < minPassphraseLength {
+		return nil, domain.ErrPassphraseTooShort
+	}
+	params, err := encryption.NewKDFParams()
+	if err != nil {
+		return nil, err
+	}
+	bundleCipher, err := encryption.FromPassphrase(input.Passphrase, params)
+	if err != nil {
+		return nil, err
+	}
+
+	credentials, err := t.repo.ListWithSecrets(ctx, input.CredentialIDs)
+	if err != nil {
+		return nil, err
+	}
+	owners, err := t.ownerEmails(ctx, credentials)
+	if err != nil {
+		return nil, err
+	}
+
+	bundle := &domain.Bundle{
+		Version:     domain.BundleVersion,
+		ExportedAt:  time.Now().UTC(),
+		KDF:         params,
+		Credentials: make([]domain.BundleCredential, 0, len(credentials)),
+	}
+	ids := make([]string, 0, len(credentials))
+	for _, c := range credentials {
+		secret, err := t.cipher.Decrypt(c.Data, c.IV)
+		if err != nil {
+			return nil, fmt.Errorf("credential %s: %w", c.ID, err)
+		}
+		data, iv, err := bundleCipher.Encrypt(secret)
+		if err != nil {
+			return nil, err
+		}
+		bundle.Credentials = append(bundle.Credentials, domain.BundleCredential{
+			ID:         c.ID,
+			Name:       c.Name,
+			Type:       c.Type,
+			OwnerEmail: owners[c.UserID],
+			NodeTypes:  c.NodeTypes,
+			Data:       data,
+			IV:         iv,
+		})
+		ids = append(ids, c.ID.String())
+	}
+
+	t.record(ctx, actor, auditlog.ActionCredentialsExported, map[string]interface{}{"credential_ids": ids})
+	return bundle, nil
+}
+
+// Import stores the credentials of a bundle. Credentials keep their ID and
+// are owned by the user with their owner's email, or else by the input
+// owner. Nothing is stored unless every secret decrypts.
+func (t *Transfer) Import(ctx context.Context, actor audit.Actor, input ImportInput) (*ImportResult, error) {
+	if t.cipher == nil {
+		return nil, domain.ErrEncryptionKeyRequired
+	}
+	if len(input.Passphrase) < minPassphraseLength {
+		return nil, domain.ErrPassphraseTooShort
+	}
+	if input.OnConflict == "" {
+		input.OnConflict = ConflictSkip
+	}
+	if input.OnConflict != ConflictSkip && input.OnConflict != ConflictOverwrite {
+		return nil, domain.ErrInvalidConflictPolicy
+	}
+	if input.Bundle == nil || input.Bundle.Version != domain.BundleVersion {
+		return nil, domain.ErrUnsupportedBundle
+	}
+	bundleCipher, err := encryption.FromPassphrase(input.Passphrase, input.Bundle.KDF)
+	if err != nil {
+		return nil, err
+	}
+
+	secrets := make([][]byte, len(input.Bundle.Credentials))
+	for i, bc := range input.Bundle.Credentials {
+		secrets[i], err = bundleCipher.Decrypt(bc.Data, bc.IV)
+		if err != nil {
+			return nil, domain.ErrBundleDecrypt
+		}
+	}
+
+	defaultOwner := actor.UserID
+	if input.OwnerID != nil {
+		defaultOwner = *input.OwnerID
+	}
+	if _, err := t.users.FindByID(ctx, defaultOwner); err != nil {
+		return nil, err
+	}
+	owners, err := t.ownerIDs(ctx, input.Bundle.Credentials)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ImportResult{Created: []uuid.UUID{}, Updated: []uuid.UUID{}, Skipped: []SkippedCredential{}}
+	for i, bc := range input.Bundle.Credentials {
+		if bc.Name == "" || bc.Type == "" {
+			result.Skipped = append(result.Skipped, SkippedCredential{ID: bc.ID, Name: bc.Name, Reason: "name and type are required"})
+			continue
+		}
+		if bc.ID == uuid.Nil {
+			bc.ID = uuid.New()
+		}
+		ownerID, ok := owners[strings.ToLower(bc.OwnerEmail)]
+		if !ok {
+			ownerID = defaultOwner
+		}
+
+		existing, err := t.repo.FindByID(ctx, bc.ID)
+		if errors.Is(err, domain.ErrCredentialNotFound) {
+			existing, err = t.repo.FindByName(ctx, ownerID, bc.Name)
+		}
+		created := errors.Is(err, domain.ErrCredentialNotFound)
+		if err != nil && !created {
+			return nil, err
+		}
+		if !created && input.OnConflict == ConflictSkip {
+			result.Skipped = append(result.Skipped, SkippedCredential{ID: bc.ID, Name: bc.Name, Reason: "credential exists"})
+			continue
+		}
+
+		c := existing
+		if created {
+			c = &domain.Credential{ID: bc.ID, UserID: ownerID}
+		}
+		c.Name = bc.Name
+		c.Type = bc.Type
+		c.NodeTypes = bc.NodeTypes
+		c.Data, c.IV, err = t.cipher.Encrypt(secrets[i])
+		if err != nil {
+			return nil, err
+		}
+
+		if created {
+			if err := t.repo.Create(ctx, c); err != nil {
+				return nil, err
+			}
+			result.Created = append(result.Created, c.ID)
+			t.events.Publish(events.NewCredentialEvent(events.CredentialCreated, c, actor))
+		} else {
+			if err := t.repo.Update(ctx, c); err != nil {
+				return nil, err
+			}
+			result.Updated = append(result.Updated, c.ID)
+		}
+	}
+
+	t.record(ctx, actor, auditlog.ActionCredentialsImported, map[string]interface{}{
+		"created": result.Created,
+		"updated": result.Updated,
+		"skipped": len(result.Skipped),
+	})
+	return result, nil
+}
+
+// ownerEmails returns the emails of the owners of the credentials by ID
+func (t *Transfer) ownerEmails(ctx context.Context, credentials []*domain.Credential) (map[uuid.UUID]string, error) {
+	emails := make(map[uuid.UUID]string)
+	if len(credentials) == 0 {
+		return emails, nil
+	}
+	ids := make([]uuid.UUID, 0, len(credentials))
+	for _, c := range credentials {
+		ids = append(ids, c.UserID)
+	}
+	users, err := t.users.FindByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	for _, u := range users {
+		emails[u.ID] = u.Email
+	}
+	return emails, nil
+}
+
+// ownerIDs returns the IDs of the users of the instance owning the
+// credentials of a bundle, by lowercase email
+func (t *Transfer) ownerIDs(ctx context.Context, credentials []domain.BundleCredential) (map[string]uuid.UUID, error) {
+	ids := make(map[string]uuid.UUID)
+	var emails []string
+	for _, bc := range credentials {
+		if bc.OwnerEmail != "" {
+			emails = append(emails, bc.OwnerEmail)
+		}
+	}
+	if len(emails) == 0 {
+		return ids, nil
+	}
+	users, err := t.users.FindByEmails(ctx, emails)
+	if err != nil {
+		return nil, err
+	}
+	for _, u := range users {
+		ids[strings.ToLower(u.Email)] = u.ID
+	}
+	return ids, nil
+}
+
+// record logs an export or import, never the secrets
+func (t *Transfer) record(ctx context.Context, actor audit.Actor, action string, details map[string]interface{}) {
+	t.audit.Record(ctx, actor, &auditlog.AuditLog{
+		Action:       action,
+		ResourceType: auditlog.ResourceCredential,
+		NewValue:     details,
+	})
+}
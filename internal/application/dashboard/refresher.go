@@ -0,0 +1,98 @@
+package dashboard
+
+import (
+	"context"
+	"time"
+
+	"github.com/jaydeep/go-n8n/configs"
+	domain "github.com/jaydeep/go-n8n/internal/domain/dashboard"
+	"github.com/jaydeep/go-n8n/pkg/logger"
+)
+
+const (
+	defaultRefreshInterval = 5 * time.Minute
+	defaultLookback        = 6 * time.Hour
+	defaultBackfill        = 90 * 24 * time.Hour
+	// refreshChunk bounds the hours recomputed per transaction
+	refreshChunk = 24 * time.Hour
+)
+
+// Refresher keeps the execution rollups current. Every run recomputes the
+// hours within the lookback window, catching executions that finished
+// after their hour was first rolled up. The first run resumes from the
+// newest rollup, or backfills when there is none.
+type Refresher struct {
+	repo domain.Repository
+	cfg  configs.DashboardConfig
+	log  *logger.Logger
+
+	// refreshedTo is the end of the last refreshed range
+	refreshedTo time.Time
+}
+
+// NewRefresher creates a new rollup refresher
+func NewRefresher(repo domain.Repository, cfg configs.DashboardConfig, log *logger.Logger) *Refresher {
+	if cfg.RefreshInterval <= 0 {
+		cfg.RefreshInterval = defaultRefreshInterval
+	}
+	if cfg.Lookback <= 0 {
+		cfg.Lookback = defaultLookback
+	}
+	if cfg.Backfill <= 0 {
+		cfg.Backfill = defaultBackfill
+	}
+	return &Refresher{repo: repo, cfg: cfg, log: log}
+}
+
+// Start refreshes the rollups until the context is cancelled
+func (r *Refresher) Start(ctx context.Context) {
+	ticker := time.NewTicker(r.cfg.RefreshInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := r.Refresh(ctx); err != nil && ctx.Err() == nil {
+			r.log.Errorw("Execution rollup refresh failed", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Refresh recomputes the rollups that may have changed since the last run
+func (r *Refresher) Refresh(ctx context.Context) error {
+	to := time.Now().UTC().Truncate(time.Hour).Add(time.Hour)
+
+	start := r.refreshedTo
+	if start.IsZero() {
+		latest, err := r.repo.LatestBucket(ctx)
+		if err != nil {
+			return err
+		}
+		if latest != nil {
+			start = latest.UTC()
+		} else {
+			start = to.Add(-r.cfg.Backfill)
+		}
+	}
+	from := start.Add(-r.cfg.Lookback).Truncate(time.Hour)
+
+	began := time.Now()
+	for chunk := from; chunk.Before(to); chunk = chunk.Add(refreshChunk) {
+		end := chunk.Add(refreshChunk)
+		if end.After(to) {
+			end = to
+		}
+		if err := r.repo.Refresh(ctx, chunk, end); err != nil {
+			return err
+		}
+		// Progress survives a failure in a later chunk
+		r.refreshedTo = end
+	}
+
+	r.log.Debugw("Execution rollups refreshed", "from", from, "to", to, "duration_ms", time.Since(began).Milliseconds())
+	return nil
+}
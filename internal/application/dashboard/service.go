@@ -0,0 +1,158 @@
+package dashboard
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	domain "github.com/jaydeep/go-n8n/internal/domain/dashboard"
+	"github.com/jaydeep/go-n8n/internal/domain/user"
+	"github.com/jaydeep/go-n8n/pkg/logger"
+)
+
+const (
+	defaultRange = 7 * 24 * time.Hour
+	// hourlyRange is the longest default range shown in hourly buckets
+	hourlyRange     = 48 * time.Hour
+	defaultTopLimit = 10
+	maxTopLimit     = 100
+)
+
+// Caller identifies who is using the service. Admins may see every team;
+// other users only the teams they belong to.
+type Caller struct {
+	UserID uuid.UUID
+	Admin  bool
+}
+
+// Range selects the executions a dashboard covers. Zero fields take
+// defaults: the last 7 days, in hourly buckets up to 48 hours and daily
+// buckets beyond.
+type Range struct {
+	From     time.Time
+	To       time.Time
+	Interval domain.Interval
+}
+
+// Metrics is the time series of a team's executions
+type Metrics struct {
+	TeamID   uuid.UUID       `json:"team_id"`
+	From     time.Time       `json:"from"`
+	To       time.Time       `json:"to"`
+	Interval domain.Interval `json:"interval"`
+	Summary  domain.Summary  `json:"summary"`
+	Series   []domain.Point  `json:"series"`
+}
+
+// Service answers team dashboard queries from the execution rollups
+type Service struct {
+	repo  domain.Repository
+	users user.Repository
+	log   *logger.Logger
+}
+
+// NewService creates a new dashboard service
+func NewService(repo domain.Repository, users user.Repository, log *logger.Logger) *Service {
+	return &Service{repo: repo, users: users, log: log}
+}
+
+// Metrics returns the execution counts, error rates and average durations
+// of a team's workflows bucketed over the range
+func (s *Service) Metrics(ctx context.Context, caller Caller, teamID uuid.UUID, r Range) (*Metrics, error) {
+	if err := s.authorize(ctx, caller, teamID); err != nil {
+		return nil, err
+	}
+	r, err := normalize(r)
+	if err != nil {
+		return nil, err
+	}
+
+	totals, err := s.repo.Totals(ctx, teamID, r.From, r.To, r.Interval)
+	if err != nil {
+		return nil, err
+	}
+
+	var sum domain.Totals
+	for _, t := range totals {
+		sum.Add(t)
+	}
+	return &Metrics{
+		TeamID:   teamID,
+		From:     r.From,
+		To:       r.To,
+		Interval: r.Interval,
+		Summary:  domain.Summarize(sum),
+		Series:   domain.Series(totals, r.From, r.To, r.Interval),
+	}, nil
+}
+
+// TopFailing returns the workflows of a team with the most failed
+// executions over the range
+func (s *Service) TopFailing(ctx context.Context, caller Caller, teamID uuid.UUID, r Range, limit int) ([]*domain.FailingWorkflow, error) {
+	if err := s.authorize(ctx, caller, teamID); err != nil {
+		return nil, err
+	}
+	r, err := normalize(r)
+	if err != nil {
+		return nil, err
+	}
+	if limit <= 0 {
+		limit = defaultTopLimit
+	}
+	if limit > maxTopLimit {
+		limit = maxTopLimit
+	}
+
+	failing, err := s.repo.TopFailing(ctx, teamID, r.From, r.To, limit)
+	if err != nil {
+		return nil, err
+	}
+	if failing == nil {
+		failing = []*domain.FailingWorkflow{}
+	}
+	return failing, nil
+}
+
+// normalize applies the defaults of a range and aligns it to hours, the
+// resolution of the rollups
+func normalize(r Range) (Range, error) {
+	if r.To.IsZero() {
+		r.To = time.Now()
+	}
+	if r.From.IsZero() {
+		r.From = r.To.Add(-defaultRange)
+	}
+	if !r.From.Before(r.To) {
+		return r, domain.ErrInvalidRange
+	}
+	// Rollups are hourly; To is rounded up to a whole hour
+	r.From = r.From.UTC().Truncate(time.Hour)
+	r.To = r.To.UTC().Add(-time.Nanosecond).Truncate(time.Hour).Add(time.Hour)
+
+	if r.Interval == "" {
+		r.Interval = domain.IntervalDay
+		if r.To.Sub(r.From) <= hourlyRange {
+			r.Interval = domain.IntervalHour
+		}
+	}
+	if !r.Interval.Valid() {
+		return r, domain.ErrInvalidInterval
+	}
+	if domain.BucketCount(r.From, r.To, r.Interval) > domain.MaxBuckets {
+		return r, domain.ErrRangeTooLarge
+	}
+	return r, nil
+}
+
+// authorize checks that the caller belongs to the team
+func (s *Service) authorize(ctx context.Context, caller Caller, teamID uuid.UUID) error {
+	if caller.Admin {
+		return nil
+	}
+	_, err := s.users.FindTeamMember(ctx, teamID, caller.UserID)
+	if errors.Is(err, user.ErrTeamMemberNotFound) {
+		return domain.ErrTeamAccessDenied
+	}
+	return err
+}
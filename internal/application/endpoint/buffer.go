@@ -0,0 +1,190 @@
+package endpoint
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jaydeep/go-n8n/configs"
+	domain "github.com/jaydeep/go-n8n/internal/domain/endpoint"
+	"github.com/jaydeep/go-n8n/pkg/logger"
+)
+
+// LoadReporter reports whether the instance is too loaded to start
+// executions
+type LoadReporter interface {
+	Overloaded() (retryAfter time.Duration, overloaded bool)
+}
+
+// Pauser reports whether intake is paused, such as while the instance is
+// in maintenance mode
+type Pauser interface {
+	Paused(ctx context.Context) bool
+}
+
+// Buffer accepts endpoint calls while executions cannot be started and
+// replays them, oldest first, once they can. Calls are stored in the
+// database, so they survive queue outages and restarts. A call is replayed
+// at least once: a crash between starting its execution and removing it
+// from the buffer replays it again.
+type Buffer struct {
+	repo      domain.BufferRepository
+	endpoints domain.Repository
+	service   *Service
+	load      LoadReporter
+	pauser    Pauser
+	cfg       configs.WebhookBufferConfig
+	log       *logger.Logger
+}
+
+// NewBuffer creates a new endpoint call buffer. load and pauser may be nil.
+func NewBuffer(repo domain.BufferRepository, endpoints domain.Repository, service *Service, load LoadReporter, pauser Pauser, cfg configs.WebhookBufferConfig, log *logger.Logger) *Buffer {
+	if cfg.DrainInterval <= 0 {
+		cfg.DrainInterval = 10 * time.Second
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = 100
+	}
+	return &Buffer{
+		repo:      repo,
+		endpoints: endpoints,
+		service:   service,
+		load:      load,
+		pauser:    pauser,
+		cfg:       cfg,
+		log:       log,
+	}
+}
+
+// Unavailable reports why executions cannot be started now, if they cannot
+func (b *Buffer) Unavailable(ctx context.Context) (string, bool) {
+	if b.pauser != nil && b.pauser.Paused(ctx) {
+		return domain.BufferReasonMaintenance, true
+	}
+	if b.load != nil {
+		if _, overloaded := b.load.Overloaded(); overloaded {
+			return domain.BufferReasonOverloaded, true
+		}
+	}
+	return "", false
+}
+
+// Accept validates a call and stores it for replay
+func (b *Buffer) Accept(ctx context.Context, e *domain.Endpoint, req Request, reason string) (*domain.BufferedCall, error) {
+	if err := validateCall(e, req); err != nil {
+		return nil, err
+	}
+
+	call := &domain.BufferedCall{
+		EndpointID: e.ID,
+		Method:     req.Method,
+		Body:       req.Body,
+		Query:      req.Query,
+		Headers:    req.Headers,
+		Binary:     req.Binary,
+		Reason:     reason,
+		ReceivedAt: time.Now(),
+	}
+	if err := b.repo.Create(ctx, call); err != nil {
+		return nil, err
+	}
+	b.log.Infow("Endpoint call buffered", "call_id", call.ID, "endpoint_id", e.ID, "reason", reason)
+	return call, nil
+}
+
+// Start replays buffered calls until the context is cancelled
+func (b *Buffer) Start(ctx context.Context) {
+	ticker := time.NewTicker(b.cfg.DrainInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			b.Drain(ctx)
+		}
+	}
+}
+
+// Drain replays buffered calls while executions can be started. It stops
+// at the first call that cannot be started, leaving it and every later
+// call for the next run so the order is kept.
+func (b *Buffer) Drain(ctx context.Context) {
+	for ctx.Err() == nil {
+		if _, unavailable := b.Unavailable(ctx); unavailable {
+			return
+		}
+
+		calls, err := b.repo.FindOldest(ctx, b.cfg.BatchSize)
+		if err != nil {
+			b.log.Errorw("Failed to load buffered endpoint calls", "error", err)
+			return
+		}
+		for _, call := range calls {
+			if ctx.Err() != nil || !b.replay(ctx, call) {
+				return
+			}
+		}
+		if len(calls) < b.cfg.BatchSize {
+			return
+		}
+	}
+}
+
+// replay starts the execution of a buffered call and removes it from the
+// buffer. It returns false when draining should stop, because executions
+// still cannot be started or the call could not be removed.
+func (b *Buffer) replay(ctx context.Context, call *domain.BufferedCall) bool {
+	if call.Expired(b.cfg.MaxAge, time.Now()) {
+		b.log.Warnw("Dropping expired buffered endpoint call", "call_id", call.ID, "endpoint_id", call.EndpointID, "received_at", call.ReceivedAt)
+		return b.remove(ctx, call)
+	}
+
+	e, err := b.endpoints.FindByID(ctx, call.EndpointID)
+	if errors.Is(err, domain.ErrEndpointNotFound) || (err == nil && !e.IsActive) {
+		b.log.Warnw("Dropping buffered call of a removed endpoint", "call_id", call.ID, "endpoint_id", call.EndpointID)
+		return b.remove(ctx, call)
+	}
+	if err != nil {
+		b.retryLater(ctx, call, err)
+		return false
+	}
+
+	exec, err := b.service.start(ctx, e, Request{
+		Method:  call.Method,
+		Body:    call.Body,
+		Query:   call.Query,
+		Headers: call.Headers,
+		Binary:  call.Binary,
+	})
+	if errors.Is(err, domain.ErrEngineUnavailable) {
+		b.retryLater(ctx, call, err)
+		return false
+	}
+	if err != nil {
+		b.log.Warnw("Dropping buffered endpoint call", "call_id", call.ID, "endpoint_id", call.EndpointID, "error", err)
+		return b.remove(ctx, call)
+	}
+
+	b.log.Infow("Buffered endpoint call replayed", "call_id", call.ID, "endpoint_id", call.EndpointID,
+		"execution_id", exec.ID, "waited", time.Since(call.ReceivedAt))
+	return b.remove(ctx, call)
+}
+
+func (b *Buffer) retryLater(ctx context.Context, call *domain.BufferedCall, cause error) {
+	call.Attempts++
+	call.LastError = cause.Error()
+	b.log.Warnw("Failed to replay buffered endpoint call", "call_id", call.ID, "attempts", call.Attempts, "error", cause)
+	if err := b.repo.Update(ctx, call); err != nil {
+		b.log.Errorw("Failed to update buffered endpoint call", "call_id", call.ID, "error", err)
+	}
+}
+
+func (b *Buffer) remove(ctx context.Context, call *domain.BufferedCall) bool {
+	if err := b.repo.Delete(ctx, call.ID); err != nil {
+		b.log.Errorw("Failed to remove buffered endpoint call", "call_id", call.ID, "error", err)
+		return false
+	}
+	return true
+}
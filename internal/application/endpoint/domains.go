@@ -0,0 +1,200 @@
+package endpoint
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	domain "github.com/jaydeep/go-n8n/internal/domain/endpoint"
+	"github.com/jaydeep/go-n8n/internal/domain/user"
+)
+
+// hostCacheTTL bounds how long a change to the verified custom domains
+// takes to reach other instances
+const hostCacheTTL = time.Minute
+
+// hostCache maps the hostnames of verified custom domains to their teams.
+// Requests for unknown hosts are answered from memory, so arbitrary Host
+// headers do not cost a query.
+type hostCache struct {
+	mu       sync.Mutex
+	teams    map[string]uuid.UUID
+	loadedAt time.Time
+}
+
+// Domains returns the custom domains of a team
+func (s *Service) Domains(ctx context.Context, caller Caller, teamID uuid.UUID) ([]*domain.CustomDomain, error) {
+	if err := s.authorizeTeam(ctx, caller, teamID, false); err != nil {
+		return nil, err
+	}
+	return s.domains.FindByTeam(ctx, teamID)
+}
+
+// AddDomain registers a custom domain for a team. It is not used until
+// VerifyDomain finds its verification record.
+func (s *Service) AddDomain(ctx context.Context, caller Caller, teamID uuid.UUID, hostname string) (*domain.CustomDomain, error) {
+	if err := s.authorizeTeam(ctx, caller, teamID, true); err != nil {
+		return nil, err
+	}
+	d, err := domain.NewCustomDomain(teamID, caller.UserID, hostname)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.domains.FindByHostname(ctx, d.Hostname); err == nil {
+		return nil, domain.ErrHostnameTaken
+	} else if !errors.Is(err, domain.ErrDomainNotFound) {
+		return nil, err
+	}
+
+	if err := s.domains.Create(ctx, d); err != nil {
+		return nil, err
+	}
+	s.log.Infow("Custom domain added", "domain_id", d.ID, "team_id", teamID, "hostname", d.Hostname)
+	return d, nil
+}
+
+// VerifyDomain looks up the verification record of a custom domain and
+// starts serving the domain once the record holds its token
+func (s *Service) VerifyDomain(ctx context.Context, caller Caller, teamID, id uuid.UUID) (*domain.CustomDomain, error) {
+	d, err := s.teamDomain(ctx, caller, teamID, id)
+	if err != nil {
+		return nil, err
+	}
+	if d.Verified() {
+		return d, nil
+	}
+
+	records, err := s.lookupTXT(ctx, d.VerificationRecord())
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) && dnsErr.IsNotFound {
+		return nil, domain.ErrDomainNotVerified
+	}
+	if err != nil {
+		return nil, err
+	}
+	found := false
+	for _, record := range records {
+		if strings.TrimSpace(record) == d.VerificationToken {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return nil, domain.ErrDomainNotVerified
+	}
+
+	now := time.Now()
+	d.VerifiedAt = &now
+	if err := s.domains.Update(ctx, d); err != nil {
+		return nil, err
+	}
+	s.invalidateHosts()
+	s.log.Infow("Custom domain verified", "domain_id", d.ID, "team_id", teamID, "hostname", d.Hostname)
+	return d, nil
+}
+
+// RemoveDomain stops serving a custom domain and deletes it
+func (s *Service) RemoveDomain(ctx context.Context, caller Caller, teamID, id uuid.UUID) error {
+	d, err := s.teamDomain(ctx, caller, teamID, id)
+	if err != nil {
+		return err
+	}
+	if err := s.domains.Delete(ctx, d.ID); err != nil {
+		return err
+	}
+	s.invalidateHosts()
+	s.log.Infow("Custom domain removed", "domain_id", d.ID, "team_id", teamID, "hostname", d.Hostname)
+	return nil
+}
+
+// DomainTeam returns the team owning host when it is a verified custom
+// domain. host may carry a port.
+func (s *Service) DomainTeam(ctx context.Context, host string) (uuid.UUID, bool) {
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	host = strings.TrimSuffix(strings.ToLower(host), ".")
+
+	s.hosts.mu.Lock()
+	defer s.hosts.mu.Unlock()
+	if s.hosts.teams == nil || time.Since(s.hosts.loadedAt) > hostCacheTTL {
+		s.loadHosts(ctx)
+	}
+	teamID, ok := s.hosts.teams[host]
+	return teamID, ok
+}
+
+// AllowCertificate reports whether certificates may be issued for host,
+// which must be a verified custom domain. It is the ACME host policy for
+// custom domains.
+func (s *Service) AllowCertificate(ctx context.Context, host string) error {
+	if _, ok := s.DomainTeam(ctx, host); !ok {
+		return domain.ErrDomainNotFound
+	}
+	return nil
+}
+
+// loadHosts reloads the verified custom domains. The caller holds the
+// cache lock. On failure the previous hosts are kept until the next
+// reload, rather than querying again on every request.
+func (s *Service) loadHosts(ctx context.Context) {
+	s.hosts.loadedAt = time.Now()
+	domains, err := s.domains.FindVerified(ctx)
+	if err != nil {
+		s.log.Warnw("Failed to load custom domains", "error", err)
+		if s.hosts.teams == nil {
+			s.hosts.teams = map[string]uuid.UUID{}
+		}
+		return
+	}
+	teams := make(map[string]uuid.UUID, len(domains))
+	for _, d := range domains {
+		teams[d.Hostname] = d.TeamID
+	}
+	s.hosts.teams = teams
+}
+
+// invalidateHosts makes the next lookup reload the verified custom domains
+func (s *Service) invalidateHosts() {
+	s.hosts.mu.Lock()
+	s.hosts.teams = nil
+	s.hosts.mu.Unlock()
+}
+
+// teamDomain returns a custom domain of a team the caller may manage
+func (s *Service) teamDomain(ctx context.Context, caller Caller, teamID, id uuid.UUID) (*domain.CustomDomain, error) {
+	if err := s.authorizeTeam(ctx, caller, teamID, true); err != nil {
+		return nil, err
+	}
+	d, err := s.domains.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if d.TeamID != teamID {
+		return nil, domain.ErrDomainNotFound
+	}
+	return d, nil
+}
+
+// authorizeTeam checks that the caller belongs to a team and, to manage
+// its domains, is one of its admins or its owner
+func (s *Service) authorizeTeam(ctx context.Context, caller Caller, teamID uuid.UUID, manage bool) error {
+	if caller.Admin {
+		return nil
+	}
+	member, err := s.users.FindTeamMember(ctx, teamID, caller.UserID)
+	if errors.Is(err, user.ErrTeamMemberNotFound) {
+		return domain.ErrTeamAccessDenied
+	}
+	if err != nil {
+		return err
+	}
+	if manage && member.Role != user.TeamRoleAdmin && member.Role != user.TeamRoleOwner {
+		return domain.ErrTeamAccessDenied
+	}
+	return nil
+}
@@ -0,0 +1,53 @@
+package endpoint
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	domain "github.com/jaydeep/go-n8n/internal/domain/endpoint"
+)
+
+const (
+	rateLimitKeyPrefix = "n8n:endpoint:ratelimit:"
+	rateLimitWindow    = time.Minute
+)
+
+// RateLimit is the state of an endpoint's rate limit after a call
+type RateLimit struct {
+	Limit     int
+	Remaining int
+	Reset     time.Time
+}
+
+// Allow counts a call against the per-minute rate limit of the endpoint.
+// It returns ErrRateLimited once the limit is exhausted, and a nil
+// RateLimit when the endpoint is unlimited. Calls are allowed when the
+// counter is unavailable.
+func (s *Service) Allow(ctx context.Context, e *domain.Endpoint) (*RateLimit, error) {
+	if e.RateLimit <= 0 || s.redis == nil {
+		return nil, nil
+	}
+
+	window := time.Now().Truncate(rateLimitWindow)
+	key := fmt.Sprintf("%s%s:%d", rateLimitKeyPrefix, e.ID, window.Unix())
+
+	pipe := s.redis.TxPipeline()
+	count := pipe.Incr(ctx, key)
+	pipe.Expire(ctx, key, 2*rateLimitWindow)
+	if _, err := pipe.Exec(ctx); err != nil {
+		s.log.Warnw("Failed to count endpoint call", "endpoint_id", e.ID, "error", err)
+		return nil, nil
+	}
+
+	limit := &RateLimit{
+		Limit:     e.RateLimit,
+		Remaining: e.RateLimit - int(count.Val()),
+		Reset:     window.Add(rateLimitWindow),
+	}
+	if limit.Remaining < 0 {
+		limit.Remaining = 0
+		return limit, domain.ErrRateLimited
+	}
+	return limit, nil
+}
@@ -0,0 +1,432 @@
+package endpoint
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jaydeep/go-n8n/internal/application/execution"
+	"github.com/jaydeep/go-n8n/internal/application/workflow"
+	domain "github.com/jaydeep/go-n8n/internal/domain/endpoint"
+	executiondomain "github.com/jaydeep/go-n8n/internal/domain/execution"
+	"github.com/jaydeep/go-n8n/internal/domain/node"
+	"github.com/jaydeep/go-n8n/internal/domain/user"
+	workflowdomain "github.com/jaydeep/go-n8n/internal/domain/workflow"
+	"github.com/jaydeep/go-n8n/internal/nodes/core/flow"
+	"github.com/jaydeep/go-n8n/internal/nodes/core/trigger"
+	"github.com/jaydeep/go-n8n/pkg/logger"
+	"github.com/redis/go-redis/v9"
+)
+
+// Caller identifies who is managing endpoints. Admins may manage every
+// endpoint; other users only their own.
+type Caller struct {
+	UserID uuid.UUID
+	Admin  bool
+}
+
+// Input holds the fields of a new endpoint
+type Input struct {
+	Slug       string
+	WorkflowID uuid.UUID
+	// WorkflowVersion pins the version to run; 0 pins the current
+	// published version
+	WorkflowVersion int
+	Description     string
+	RateLimit       int
+	AllowedIPs      []string
+}
+
+// UpdateInput holds the editable fields of an endpoint. Nil fields are
+// left unchanged. The slug cannot change so the URL stays stable.
+type UpdateInput struct {
+	WorkflowVersion *int
+	Description     *string
+	RateLimit       *int
+	AllowedIPs      *[]string
+	IsActive        *bool
+}
+
+// Request is a call of an endpoint
+type Request struct {
+	Method  string
+	Body    interface{}
+	Query   map[string]string
+	Headers map[string]string
+	// Binary holds the files uploaded with the call and, when kept, its
+	// raw body
+	Binary map[string]node.Binary
+}
+
+// Response is the HTTP response to an endpoint call. Finished is false
+// when the execution did not finish in time.
+type Response struct {
+	StatusCode int
+	Body       interface{}
+	Execution  *executiondomain.Execution
+	Finished   bool
+}
+
+// Service manages workflow endpoints and serves their calls
+type Service struct {
+	repo       domain.Repository
+	domains    domain.CustomDomainRepository
+	workflows  *workflow.Service
+	executions *execution.Service
+	users      user.Repository
+	redis      *redis.Client
+	log        *logger.Logger
+
+	// lookupTXT resolves the records verifying custom domains
+	lookupTXT func(ctx context.Context, name string) ([]string, error)
+	hosts     hostCache
+}
+
+// NewService creates a new endpoint service. Rate limits are counted in
+// redisClient; with a nil client endpoints are not rate limited.
+func NewService(repo domain.Repository, domains domain.CustomDomainRepository, workflows *workflow.Service, executions *execution.Service, users user.Repository, redisClient *redis.Client, log *logger.Logger) *Service {
+	return &Service{
+		repo:       repo,
+		domains:    domains,
+		workflows:  workflows,
+		executions: executions,
+		users:      users,
+		redis:      redisClient,
+		log:        log,
+		lookupTXT:  net.DefaultResolver.LookupTXT,
+	}
+}
+
+// List returns the endpoints the caller may manage
+func (s *Service) List(ctx context.Context, caller Caller) ([]*domain.Endpoint, error) {
+	if caller.Admin {
+		return s.repo.List(ctx, nil)
+	}
+	return s.repo.List(ctx, &caller.UserID)
+}
+
+// Get returns an endpoint the caller may manage
+func (s *Service) Get(ctx context.Context, caller Caller, id uuid.UUID) (*domain.Endpoint, error) {
+	e, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !caller.Admin && e.UserID != caller.UserID {
+		return nil, domain.ErrEndpointAccessDenied
+	}
+	return e, nil
+}
+
+// Create exposes a published workflow version as an endpoint. It returns
+// the endpoint API key, which is only available now.
+func (s *Service) Create(ctx context.Context, caller Caller, input Input) (*domain.Endpoint, string, error) {
+	if err := domain.ValidateSlug(input.Slug); err != nil {
+		return nil, "", err
+	}
+	if input.RateLimit < 0 {
+		return nil, "", domain.ErrInvalidRateLimit
+	}
+	if err := domain.ValidateAllowedIPs(input.AllowedIPs); err != nil {
+		return nil, "", err
+	}
+
+	w, err := s.workflows.Get(ctx, input.WorkflowID)
+	if err != nil {
+		return nil, "", err
+	}
+	if !caller.Admin && w.UserID != caller.UserID {
+		return nil, "", domain.ErrEndpointAccessDenied
+	}
+
+	if _, err := s.repo.FindBySlug(ctx, input.Slug); err == nil {
+		return nil, "", domain.ErrSlugTaken
+	} else if !errors.Is(err, domain.ErrEndpointNotFound) {
+		return nil, "", err
+	}
+
+	e := &domain.Endpoint{
+		Slug:        input.Slug,
+		WorkflowID:  w.ID,
+		UserID:      w.UserID,
+		Description: input.Description,
+		RateLimit:   input.RateLimit,
+		AllowedIPs:  input.AllowedIPs,
+		IsActive:    true,
+	}
+	if err := s.pin(ctx, e, input.WorkflowVersion); err != nil {
+		return nil, "", err
+	}
+	key, err := e.RotateAPIKey()
+	if err != nil {
+		return nil, "", err
+	}
+
+	if err := s.repo.Create(ctx, e); err != nil {
+		return nil, "", err
+	}
+	s.log.Infow("Endpoint created", "endpoint_id", e.ID, "slug", e.Slug, "workflow_id", e.WorkflowID, "version", e.WorkflowVersion)
+	return e, key, nil
+}
+
+// Update changes an endpoint. Pinning another version derives the schemas
+// again from that version.
+func (s *Service) Update(ctx context.Context, caller Caller, id uuid.UUID, input UpdateInput) (*domain.Endpoint, error) {
+	e, err := s.Get(ctx, caller, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if input.WorkflowVersion != nil {
+		if err := s.pin(ctx, e, *input.WorkflowVersion); err != nil {
+			return nil, err
+		}
+	}
+	if input.Description != nil {
+		e.Description = *input.Description
+	}
+	if input.RateLimit != nil {
+		if *input.RateLimit < 0 {
+			return nil, domain.ErrInvalidRateLimit
+		}
+		e.RateLimit = *input.RateLimit
+	}
+	if input.AllowedIPs != nil {
+		if err := domain.ValidateAllowedIPs(*input.AllowedIPs); err != nil {
+			return nil, err
+		}
+		e.AllowedIPs = *input.AllowedIPs
+	}
+	if input.IsActive != nil {
+		e.IsActive = *input.IsActive
+	}
+
+	if err := s.repo.Update(ctx, e); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// RotateKey replaces the API key of an endpoint and returns the new key.
+// The old key stops working immediately.
+func (s *Service) RotateKey(ctx context.Context, caller Caller, id uuid.UUID) (*domain.Endpoint, string, error) {
+	e, err := s.Get(ctx, caller, id)
+	if err != nil {
+		return nil, "", err
+	}
+	key, err := e.RotateAPIKey()
+	if err != nil {
+		return nil, "", err
+	}
+	if err := s.repo.Update(ctx, e); err != nil {
+		return nil, "", err
+	}
+	s.log.Infow("Endpoint API key rotated", "endpoint_id", e.ID, "slug", e.Slug)
+	return e, key, nil
+}
+
+// Delete removes an endpoint
+func (s *Service) Delete(ctx context.Context, caller Caller, id uuid.UUID) error {
+	if _, err := s.Get(ctx, caller, id); err != nil {
+		return err
+	}
+	return s.repo.Delete(ctx, id)
+}
+
+// Usage returns the call statistics of an endpoint between from and to
+func (s *Service) Usage(ctx context.Context, caller Caller, id uuid.UUID, from, to time.Time) (*domain.UsageSummary, error) {
+	if _, err := s.Get(ctx, caller, id); err != nil {
+		return nil, err
+	}
+	days, err := s.repo.Usage(ctx, id, from, to)
+	if err != nil {
+		return nil, err
+	}
+	return domain.Summarize(from, to, days), nil
+}
+
+// pin points the endpoint at a published workflow version and derives its
+// method and schemas from the webhook trigger and respond node
+func (s *Service) pin(ctx context.Context, e *domain.Endpoint, version int) error {
+	w, err := s.workflows.AtVersion(ctx, e.WorkflowID, version)
+	if err != nil {
+		return err
+	}
+
+	hook := findNode(w, trigger.WebhookTriggerType)
+	if hook == nil {
+		return domain.ErrWebhookTriggerRequired
+	}
+	webhook := trigger.ParseWebhookSettings(hook.Parameters)
+
+	response := flow.ResponseSettings{StatusCode: http.StatusOK}
+	if respond := findNode(w, flow.RespondToWebhookType); respond != nil {
+		response = flow.ParseResponseSettings(respond.Parameters)
+	}
+
+	e.WorkflowVersion = w.Version
+	e.Method = webhook.Method
+	e.RequestSchema = webhook.RequestSchema
+	e.ResponseSchema = response.ResponseSchema
+	e.ResponseStatus = response.StatusCode
+	e.ResponseField = response.ResponseField
+	e.BodyFormat = webhook.BodyFormat
+	e.RawBody = webhook.RawBody
+	e.WebhookAllowedIPs = webhook.AllowedIPs
+	e.Region = w.Settings.Region
+	e.CORS = nil
+	if len(webhook.AllowedOrigins) > 0 || webhook.CORSMaxAge > 0 {
+		e.CORS = &domain.CORSPolicy{
+			AllowedOrigins: webhook.AllowedOrigins,
+			AllowedMethods: []string{webhook.Method},
+			MaxAge:         webhook.CORSMaxAge,
+		}
+	}
+	return nil
+}
+
+// findNode returns the first enabled node of a type
+func findNode(w *workflowdomain.Workflow, nodeType string) *workflowdomain.Node {
+	for i := range w.Nodes {
+		if w.Nodes[i].Type == nodeType && !w.Nodes[i].Disabled {
+			return &w.Nodes[i]
+		}
+	}
+	return nil
+}
+
+// CORSPolicy returns the CORS policy of the active endpoint for slug, or
+// nil when it has none or there is no such endpoint. It answers preflight
+// requests, which carry no API key.
+func (s *Service) CORSPolicy(ctx context.Context, slug string) *domain.CORSPolicy {
+	e, err := s.repo.FindBySlug(ctx, slug)
+	if err != nil || !e.IsActive {
+		return nil
+	}
+	return e.CORS
+}
+
+// Authenticate returns the active endpoint for slug if key is its API key
+// and the endpoint allows calls from clientIP. Calls on a custom domain
+// pass the team owning it, and only reach the endpoints of the team's
+// workflows. The network is checked first, so keys cannot be probed from
+// elsewhere.
+func (s *Service) Authenticate(ctx context.Context, slug, key, clientIP string, team *uuid.UUID) (*domain.Endpoint, error) {
+	e, err := s.repo.FindBySlug(ctx, slug)
+	if err != nil {
+		return nil, err
+	}
+	if !e.IsActive {
+		return nil, domain.ErrEndpointNotFound
+	}
+	if team != nil {
+		w, err := s.workflows.Get(ctx, e.WorkflowID)
+		if err != nil {
+			return nil, err
+		}
+		if w.TeamID == nil || *w.TeamID != *team {
+			return nil, domain.ErrEndpointNotFound
+		}
+	}
+	if !e.AllowsIP(clientIP) {
+		return nil, domain.ErrNetworkNotAllowed
+	}
+	if !e.VerifyAPIKey(key) {
+		return nil, domain.ErrInvalidAPIKey
+	}
+	return e, nil
+}
+
+// Invoke runs the pinned workflow version for a call and waits up to
+// timeout for its result
+func (s *Service) Invoke(ctx context.Context, e *domain.Endpoint, req Request, timeout time.Duration) (*Response, error) {
+	if err := validateCall(e, req); err != nil {
+		return nil, err
+	}
+
+	exec, err := s.start(ctx, e, req)
+	if err != nil {
+		return nil, err
+	}
+
+	finished, err := s.executions.Wait(ctx, exec.ID, timeout)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case !finished.Status.IsTerminal():
+		return &Response{
+			StatusCode: http.StatusAccepted,
+			Body:       map[string]interface{}{"execution_id": finished.ID, "status": finished.Status},
+			Execution:  finished,
+		}, nil
+	case finished.Status != executiondomain.ExecutionStatusSuccess:
+		return &Response{
+			StatusCode: http.StatusInternalServerError,
+			Body:       map[string]interface{}{"error": finished.ErrorMessage, "execution_id": finished.ID},
+			Execution:  finished,
+			Finished:   true,
+		}, nil
+	}
+
+	var body interface{} = finished.OutputData
+	if e.ResponseField != "" {
+		body = finished.OutputData[e.ResponseField]
+	}
+	return &Response{
+		StatusCode: e.ResponseStatus,
+		Body:       body,
+		Execution:  finished,
+		Finished:   true,
+	}, nil
+}
+
+// validateCall checks a call against the method and request schema of the
+// endpoint
+func validateCall(e *domain.Endpoint, req Request) error {
+	if req.Method != e.Method {
+		return fmt.Errorf("%w: use %s", domain.ErrMethodNotAllowed, e.Method)
+	}
+	return domain.ValidateRequest(e.RequestSchema, req.Body)
+}
+
+// start queues an execution of the pinned workflow version for a call.
+// The call is the item the webhook trigger emits, with the files of the
+// call as its binary data. Failures other than a missing workflow are
+// reported as ErrEngineUnavailable, as the call itself was valid.
+func (s *Service) start(ctx context.Context, e *domain.Endpoint, req Request) (*executiondomain.Execution, error) {
+	input := execution.StartInput{
+		Mode:    executiondomain.ExecutionModeWebhook,
+		Version: e.WorkflowVersion,
+		Data: map[string]interface{}{
+			"endpoint": e.Slug,
+			"method":   req.Method,
+			"body":     req.Body,
+			"query":    req.Query,
+			"headers":  req.Headers,
+		},
+	}
+	if len(req.Binary) > 0 {
+		input.Items = []node.Item{{JSON: input.Data, Binary: req.Binary}}
+	}
+	exec, err := s.executions.Start(ctx, e.WorkflowID, input)
+	if err != nil {
+		if errors.Is(err, workflowdomain.ErrWorkflowNotFound) || errors.Is(err, workflowdomain.ErrVersionNotFound) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("%w: %v", domain.ErrEngineUnavailable, err)
+	}
+	return exec, nil
+}
+
+// RecordCall adds a call to the usage analytics of an endpoint. Failures
+// are logged; they never fail the call.
+func (s *Service) RecordCall(ctx context.Context, e *domain.Endpoint, call domain.Call) {
+	if err := s.repo.RecordCall(ctx, e.ID, call); err != nil {
+		s.log.Warnw("Failed to record endpoint usage", "endpoint_id", e.ID, "error", err)
+	}
+}
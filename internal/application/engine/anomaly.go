@@ -0,0 +1,110 @@
+package engine
+
+import (
+	"math"
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/jaydeep/go-n8n/configs"
+	"github.com/jaydeep/go-n8n/internal/domain/execution"
+	"github.com/jaydeep/go-n8n/internal/domain/node"
+)
+
+const (
+	defaultAnomalyWindow     = 50
+	defaultAnomalyMinSamples = 10
+	defaultAnomalyThreshold  = 3.5
+)
+
+// withAnomalyDefaults fills in the unset anomaly detection settings
+func withAnomalyDefaults(cfg configs.AnomalyConfig) configs.AnomalyConfig {
+	if cfg.Window <= 0 {
+		cfg.Window = defaultAnomalyWindow
+	}
+	if cfg.MinSamples <= 0 {
+		cfg.MinSamples = defaultAnomalyMinSamples
+	}
+	if cfg.MinSamples > cfg.Window {
+		cfg.MinSamples = cfg.Window
+	}
+	if cfg.Threshold <= 0 {
+		cfg.Threshold = defaultAnomalyThreshold
+	}
+	return cfg
+}
+
+// AnomalyDetector flags node runs that took much longer than the recent
+// runs of the same node in the same workflow. The durations are kept in
+// memory per node, so the baseline builds up again after a restart.
+type AnomalyDetector struct {
+	cfg configs.AnomalyConfig
+
+	mu     sync.Mutex
+	series map[seriesKey]*durationWindow
+}
+
+type seriesKey struct {
+	workflowID uuid.UUID
+	nodeID     string
+}
+
+// durationWindow is a ring of the latest durations of a node
+type durationWindow struct {
+	samples []float64
+	next    int
+}
+
+// NewAnomalyDetector creates a new node run anomaly detector
+func NewAnomalyDetector(cfg configs.AnomalyConfig) *AnomalyDetector {
+	return &AnomalyDetector{
+		cfg:    withAnomalyDefaults(cfg),
+		series: make(map[seriesKey]*durationWindow),
+	}
+}
+
+// Observe compares a finished run to the baseline of its node and, when it
+// is slow, records the Anomaly under node.MetadataAnomaly. Successful runs
+// then join the baseline, slow ones included, so a lasting slowdown becomes
+// the new normal once it makes up half of the window. Failed runs are left
+// out as they often end early.
+func (d *AnomalyDetector) Observe(workflowID uuid.UUID, run *node.NodeExecutionData) *node.Anomaly {
+	if !d.cfg.Enabled || run.Status != "success" {
+		return nil
+	}
+	key := seriesKey{workflowID: workflowID, nodeID: run.NodeID}
+	duration := float64(run.ExecutionTimeMs)
+
+	d.mu.Lock()
+	w, ok := d.series[key]
+	if !ok {
+		w = &durationWindow{samples: make([]float64, 0, d.cfg.Window)}
+		d.series[key] = w
+	}
+	var baseline execution.Baseline
+	if len(w.samples) >= d.cfg.MinSamples {
+		baseline = execution.NewBaseline(w.samples)
+	}
+	if len(w.samples) < d.cfg.Window {
+		w.samples = append(w.samples, duration)
+	} else {
+		w.samples[w.next] = duration
+		w.next = (w.next + 1) % d.cfg.Window
+	}
+	d.mu.Unlock()
+
+	if !baseline.IsSlow(duration, d.cfg.Threshold, d.cfg.MinDelta) {
+		return nil
+	}
+	anomaly := &node.Anomaly{
+		DurationMs: run.ExecutionTimeMs,
+		MedianMs:   baseline.MedianMs,
+		MADMs:      baseline.MADMs,
+		Score:      math.Round(baseline.Score(duration)*10) / 10,
+		Samples:    baseline.Samples,
+	}
+	if run.Metadata == nil {
+		run.Metadata = make(map[string]interface{})
+	}
+	run.Metadata[node.MetadataAnomaly] = anomaly
+	return anomaly
+}
@@ -0,0 +1,126 @@
+package engine
+
+import (
+	"context"
+	"runtime"
+	"time"
+
+	"github.com/jaydeep/go-n8n/pkg/pool"
+	"github.com/jaydeep/go-n8n/pkg/queue"
+)
+
+// startedAt is when the process started, for the uptime in snapshots
+var startedAt = time.Now()
+
+// cacheSizer is implemented by the caches reported in snapshots
+type cacheSizer interface {
+	Len() int
+}
+
+// Snapshot is a point-in-time view of the engine internals, for diagnosing
+// performance issues in production
+type Snapshot struct {
+	RunningExecutions int64              `json:"running_executions"`
+	Queue             QueueSnapshot      `json:"queue"`
+	NodeWriter        NodeWriterSnapshot `json:"node_writer"`
+	GraphCacheSize    int                `json:"graph_cache_size"`
+	Pool              pool.Stats         `json:"pool"`
+	Runtime           RuntimeSnapshot    `json:"runtime"`
+}
+
+// QueueSnapshot describes the execution queue
+type QueueSnapshot struct {
+	Name    string `json:"name"`
+	Waiting int64  `json:"waiting"`
+	LagMs   int64  `json:"lag_ms"`
+}
+
+// NodeWriterSnapshot describes the node record buffer
+type NodeWriterSnapshot struct {
+	Buffered int `json:"buffered"`
+	Capacity int `json:"capacity"`
+}
+
+// RuntimeSnapshot describes the Go runtime
+type RuntimeSnapshot struct {
+	GoVersion     string `json:"go_version"`
+	UptimeSec     int64  `json:"uptime_sec"`
+	Goroutines    int    `json:"goroutines"`
+	GOMAXPROCS    int    `json:"gomaxprocs"`
+	NumCPU        int    `json:"num_cpu"`
+	HeapAlloc     uint64 `json:"heap_alloc_bytes"`
+	HeapInuse     uint64 `json:"heap_inuse_bytes"`
+	Sys           uint64 `json:"sys_bytes"`
+	NumGC         uint32 `json:"num_gc"`
+	LastGCPauseNs uint64 `json:"last_gc_pause_ns"`
+}
+
+// Diagnostics collects snapshots of the engine internals
+type Diagnostics struct {
+	watchdog   *Watchdog
+	jobs       queue.Queue
+	queueName  string
+	nodeWriter *NodeWriter
+	graphs     cacheSizer
+	clients    *pool.Pool
+}
+
+// NewDiagnostics creates a new diagnostics collector
+func NewDiagnostics(watchdog *Watchdog, jobs queue.Queue, queueName string, nodeWriter *NodeWriter, graphs cacheSizer, clients *pool.Pool) *Diagnostics {
+	return &Diagnostics{
+		watchdog:   watchdog,
+		jobs:       jobs,
+		queueName:  queueName,
+		nodeWriter: nodeWriter,
+		graphs:     graphs,
+		clients:    clients,
+	}
+}
+
+// Snapshot returns the current state of the engine internals
+func (d *Diagnostics) Snapshot(ctx context.Context) (*Snapshot, error) {
+	running, err := d.watchdog.Running(ctx)
+	if err != nil {
+		return nil, err
+	}
+	waiting, err := d.jobs.Len(ctx, d.queueName)
+	if err != nil {
+		return nil, err
+	}
+	lag, err := d.jobs.Lag(ctx, d.queueName)
+	if err != nil {
+		return nil, err
+	}
+
+	buffered, capacity := d.nodeWriter.Buffered()
+	return &Snapshot{
+		RunningExecutions: running,
+		Queue: QueueSnapshot{
+			Name:    d.queueName,
+			Waiting: waiting,
+			LagMs:   lag.Milliseconds(),
+		},
+		NodeWriter:     NodeWriterSnapshot{Buffered: buffered, Capacity: capacity},
+		GraphCacheSize: d.graphs.Len(),
+		Pool:           d.clients.Stats(),
+		Runtime:        runtimeSnapshot(),
+	}, nil
+}
+
+func runtimeSnapshot() RuntimeSnapshot {
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+
+	return RuntimeSnapshot{
+		GoVersion:     runtime.Version(),
+		UptimeSec:     int64(time.Since(startedAt).Seconds()),
+		Goroutines:    runtime.NumGoroutine(),
+		GOMAXPROCS:    runtime.GOMAXPROCS(0),
+		NumCPU:        runtime.NumCPU(),
+		HeapAlloc:     mem.HeapAlloc,
+		HeapInuse:     mem.HeapInuse,
+		Sys:           mem.Sys,
+		NumGC:         mem.NumGC,
+		LastGCPauseNs: mem.PauseNs[(mem.NumGC+255)%256],
+	}
+}
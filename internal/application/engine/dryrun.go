@@ -0,0 +1,159 @@
+package engine
+
+import (
+	"context"
+	"strings"
+
+	"github.com/jaydeep/go-n8n/internal/domain/execution"
+	"github.com/jaydeep/go-n8n/internal/domain/node"
+	"github.com/jaydeep/go-n8n/internal/domain/workflow"
+)
+
+// DryRunAction controls what happens to a side-effecting node in a dry run
+type DryRunAction string
+
+const (
+	// DryRunSkip passes the input items through unchanged
+	DryRunSkip DryRunAction = "skip"
+	// DryRunMock returns the configured mock output instead of executing
+	DryRunMock DryRunAction = "mock"
+)
+
+// sideEffectTypes lists node types that always talk to the outside world
+var sideEffectTypes = []string{
+	"email", "smtp", "send_email", "slack", "discord", "telegram", "twilio", "sms",
+}
+
+// writeOperations lists operation parameter values that mutate remote state
+var writeOperations = map[string]bool{
+	"create": true, "insert": true, "update": true, "upsert": true,
+	"delete": true, "remove": true, "send": true, "post": true,
+	"execute": true, "executequery": true, "upload": true, "publish": true,
+}
+
+// safeHTTPMethods are the HTTP methods that don't change remote state
+var safeHTTPMethods = map[string]bool{
+	"GET": true, "HEAD": true, "OPTIONS": true,
+}
+
+// HasSideEffects reports whether running the node would cause externally
+// visible changes. Nodes implementing node.SideEffectDeclarer decide for
+// themselves; otherwise the decision is based on type and parameters.
+func HasSideEffects(wn workflow.Node, impl node.NodeInterface) bool {
+	if declarer, ok := impl.(node.SideEffectDeclarer); ok {
+		return declarer.HasSideEffects(wn.Parameters)
+	}
+
+	if impl != nil && impl.GetCategory() == node.CategoryTrigger {
+		return false
+	}
+
+	nodeType := strings.ToLower(wn.Type)
+	for _, t := range sideEffectTypes {
+		if strings.Contains(nodeType, t) {
+			return true
+		}
+	}
+
+	if strings.Contains(nodeType, "http") {
+		method, _ := wn.Parameters["method"].(string)
+		if method == "" {
+			return false
+		}
+		return !safeHTTPMethods[strings.ToUpper(method)]
+	}
+
+	if op, ok := wn.Parameters["operation"].(string); ok {
+		return writeOperations[strings.ToLower(op)]
+	}
+
+	return false
+}
+
+// dryRunAction returns the action for a node of a dry run with the options,
+// which may be nil, or an empty string when the node should execute
+// normally
+func dryRunAction(opts *execution.DryRunOptions, wn workflow.Node, impl node.NodeInterface) DryRunAction {
+	if opts == nil {
+		opts = &execution.DryRunOptions{}
+	}
+	if matchesNode(opts.Allow, wn) {
+		return ""
+	}
+	if !matchesNode(opts.Force, wn) && !HasSideEffects(wn, impl) {
+		return ""
+	}
+	if _, ok := mockFor(opts, wn); ok {
+		return DryRunMock
+	}
+	return DryRunSkip
+}
+
+// wrapDryRun returns a node implementation honoring the dry run of the
+// execution, if it is one. Nodes that should run normally are returned
+// unchanged.
+func wrapDryRun(exec *execution.Execution, wn workflow.Node, impl node.NodeInterface) node.NodeInterface {
+	if !exec.DryRun {
+		return impl
+	}
+	switch dryRunAction(exec.DryRunOptions, wn, impl) {
+	case DryRunMock:
+		items, _ := mockFor(exec.DryRunOptions, wn)
+		return &mockNode{NodeInterface: impl, items: items}
+	case DryRunSkip:
+		return &mockNode{NodeInterface: impl, passthrough: true}
+	default:
+		return impl
+	}
+}
+
+// mockFor looks up mock output by node ID first, then by name
+func mockFor(opts *execution.DryRunOptions, wn workflow.Node) ([]node.Item, bool) {
+	if opts == nil {
+		return nil, false
+	}
+	if items, ok := opts.Mocks[wn.ID]; ok {
+		return items, true
+	}
+	items, ok := opts.Mocks[wn.Name]
+	return items, ok
+}
+
+// matchesNode reports whether a node ID or name is in the list
+func matchesNode(list []string, wn workflow.Node) bool {
+	for _, v := range list {
+		if v == wn.ID || v == wn.Name {
+			return true
+		}
+	}
+	return false
+}
+
+// mockNode replaces the Execute method of a node during dry runs
+type mockNode struct {
+	node.NodeInterface
+	items       []node.Item
+	passthrough bool
+}
+
+// Execute returns the mock items (or the input items when skipping)
+// without calling the wrapped node
+func (m *mockNode) Execute(ctx context.Context, input *node.NodeInput) (*node.NodeOutput, error) {
+	data := m.items
+	action := DryRunMock
+	if m.passthrough {
+		data = input.Data
+		action = DryRunSkip
+	}
+
+	items := make([]node.Item, len(data))
+	copy(items, data)
+
+	return &node.NodeOutput{
+		Data: items,
+		Metadata: map[string]interface{}{
+			"dry_run":        true,
+			"dry_run_action": string(action),
+		},
+	}, nil
+}
@@ -0,0 +1,70 @@
+package engine
+
+import (
+	"context"
+
+	"github.com/jaydeep/go-n8n/internal/domain/node"
+	"github.com/jaydeep/go-n8n/internal/domain/workflow"
+)
+
+// WrapEmptyHandling returns a node implementation handling runs without
+// items as the node's options say. A node other than a trigger receiving
+// no items is skipped and outputs none, unless it executes on empty input,
+// in which case it runs with one empty item. A node always outputting data
+// outputs one empty item when a run of it outputs none; skipped nodes do
+// not run and output nothing. It wraps the node as
+// returned by WrapExecutionMode, so that a node run per item is run once
+// for its empty item.
+func WrapEmptyHandling(wn workflow.Node, impl node.NodeInterface) node.NodeInterface {
+	return &emptyHandlingNode{
+		NodeInterface:    impl,
+		trigger:          impl.GetCategory() == node.CategoryTrigger,
+		executeOnEmpty:   wn.ExecuteOnEmptyInput,
+		alwaysOutputData: wn.AlwaysOutputData,
+	}
+}
+
+// emptyHandlingNode skips or feeds a node without input items and fills
+// in an empty output
+type emptyHandlingNode struct {
+	node.NodeInterface
+	trigger          bool
+	executeOnEmpty   bool
+	alwaysOutputData bool
+}
+
+// Execute runs the wrapped node unless it has no items to run for
+func (e *emptyHandlingNode) Execute(ctx context.Context, input *node.NodeInput) (*node.NodeOutput, error) {
+	if len(input.Data) == 0 && !e.trigger {
+		if !e.executeOnEmpty {
+			return &node.NodeOutput{Data: []node.Item{}}, nil
+		}
+		empty := *input
+		empty.Data = []node.Item{emptyItem()}
+		input = &empty
+	}
+
+	output, err := e.NodeInterface.Execute(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+	if output == nil {
+		output = &node.NodeOutput{Data: []node.Item{}}
+	}
+	return e.fill(output), nil
+}
+
+// fill gives an output without items or an error one empty item when the
+// node always outputs data
+func (e *emptyHandlingNode) fill(output *node.NodeOutput) *node.NodeOutput {
+	if !e.alwaysOutputData || len(output.Data) > 0 || output.Error != nil {
+		return output
+	}
+	filled := *output
+	filled.Data = []node.Item{emptyItem()}
+	return &filled
+}
+
+func emptyItem() node.Item {
+	return node.Item{JSON: map[string]interface{}{}}
+}
@@ -0,0 +1,882 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jaydeep/go-n8n/configs"
+	"github.com/jaydeep/go-n8n/internal/application/workflow"
+	"github.com/jaydeep/go-n8n/internal/domain/execution"
+	"github.com/jaydeep/go-n8n/internal/domain/node"
+	workflowdomain "github.com/jaydeep/go-n8n/internal/domain/workflow"
+	"github.com/jaydeep/go-n8n/internal/expression"
+	"github.com/jaydeep/go-n8n/pkg/binarydata"
+	"github.com/jaydeep/go-n8n/pkg/correlation"
+	"github.com/jaydeep/go-n8n/pkg/logger"
+	"github.com/jaydeep/go-n8n/pkg/nodesdk"
+	"github.com/jaydeep/go-n8n/pkg/pool"
+	"github.com/jaydeep/go-n8n/pkg/queue"
+)
+
+const (
+	// dequeueTimeout is how long Consume waits on each queue in turn
+	dequeueTimeout = time.Second
+	// finishTimeout bounds recording the end of an execution whose context
+	// has already expired
+	finishTimeout = 30 * time.Second
+)
+
+// errWaiting stops the walk of an execution suspended at a node
+var errWaiting = errors.New("execution waits")
+
+// FinishNotifier is told when the engine moves an execution to a terminal
+// state, to wake up the requests waiting for it
+type FinishNotifier interface {
+	NotifyFinished(ctx context.Context, exec *execution.Execution)
+}
+
+// SubWorkflowStarter creates the executions of the sub-workflows nodes
+// start, queued or for the engine to run inline
+type SubWorkflowStarter interface {
+	StartSubWorkflow(ctx context.Context, parent *execution.Execution, workflowID uuid.UUID, items []node.Item, inline bool) (*execution.Execution, error)
+}
+
+// UsageRecorder records the resources a finished execution consumed
+type UsageRecorder interface {
+	Record(ctx context.Context, exec *execution.Execution, runs []node.NodeExecutionData) error
+}
+
+// ExecutionEngine runs executions. It walks the compiled graph of the
+// workflow version an execution runs in topological order, runs each node
+// with the items the nodes connected before it output, and records the
+// execution and its node runs as it goes. A node may suspend the
+// execution; its state is kept until it is resumed and run again from
+// there.
+type ExecutionEngine struct {
+	executions execution.Repository
+	waits      execution.WaitRepository
+	workflows  *workflow.Service
+	secrets    *workflow.SecretService
+	pinned     *workflow.PinnedDataService
+	nodes      *node.NodeRegistry
+	writer     *NodeWriter
+	inFlight   *InFlight
+	anomalies  *AnomalyDetector
+	usage      UsageRecorder
+	events     execution.EventPublisher
+	outputs    execution.OutputPublisher
+	finished   FinishNotifier
+	subflows   SubWorkflowStarter
+	clients    *pool.Pool
+	http       *nodesdk.HTTPClient
+	binary     binarydata.Store
+	jobs       queue.Queue
+	cfg        configs.EngineConfig
+	// nodeMaxTime bounds the runs of nodes without a timeout of their own
+	nodeMaxTime time.Duration
+	// env holds the environment variables expressions read as $env
+	env map[string]string
+	log *logger.Logger
+}
+
+// NewExecutionEngine creates a new execution engine. The anomaly detector,
+// usage recorder, output publisher, HTTP client and binary data store may
+// be nil; without a client nodes call APIs without rate limits or circuit
+// breaking, and without a store they keep binary data in the items.
+// nodeMaxTime bounds the runs of nodes without a timeout of their own;
+// zero leaves them unbounded.
+func NewExecutionEngine(executions execution.Repository, waits execution.WaitRepository, workflows *workflow.Service, secrets *workflow.SecretService, pinned *workflow.PinnedDataService, nodes *node.NodeRegistry, writer *NodeWriter, inFlight *InFlight, anomalies *AnomalyDetector, usage UsageRecorder, events execution.EventPublisher, outputs execution.OutputPublisher, finished FinishNotifier, subflows SubWorkflowStarter, clients *pool.Pool, httpClient *nodesdk.HTTPClient, binary binarydata.Store, jobs queue.Queue, cfg configs.EngineConfig, nodeMaxTime time.Duration, log *logger.Logger) *ExecutionEngine {
+	return &ExecutionEngine{
+		executions:  executions,
+		waits:       waits,
+		workflows:   workflows,
+		secrets:     secrets,
+		pinned:      pinned,
+		nodes:       nodes,
+		writer:      writer,
+		inFlight:    inFlight,
+		anomalies:   anomalies,
+		usage:       usage,
+		events:      events,
+		outputs:     outputs,
+		finished:    finished,
+		subflows:    subflows,
+		clients:     clients,
+		http:        httpClient,
+		binary:      binary,
+		jobs:        jobs,
+		cfg:         cfg,
+		nodeMaxTime: nodeMaxTime,
+		env:         expressionEnv(cfg.ExpressionEnv),
+		log:         log,
+	}
+}
+
+// Active returns the number of executions running in this process
+func (e *ExecutionEngine) Active() int {
+	return e.inFlight.Len()
+}
+
+// Consume runs the jobs of the queues as consumer, up to concurrency at a
+// time, until ctx is cancelled. Executions already running go on after
+// that; shutdown waits for them through InFlight.
+func (e *ExecutionEngine) Consume(ctx context.Context, consumer string, queues []string, concurrency int) {
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+	slots := make(chan struct{}, concurrency)
+	for {
+		select {
+		case slots <- struct{}{}:
+		case <-ctx.Done():
+			return
+		}
+
+		job, err := e.next(ctx, consumer, queues)
+		if err != nil {
+			<-slots
+			if ctx.Err() != nil {
+				return
+			}
+			if !errors.Is(err, queue.ErrEmpty) {
+				e.log.Errorw("Failed to take job", "consumer", consumer, "error", err)
+				time.Sleep(dequeueTimeout)
+			}
+			continue
+		}
+
+		go func() {
+			defer func() { <-slots }()
+			e.Process(consumer, job)
+		}()
+	}
+}
+
+// next takes the next job of the first queue having one
+func (e *ExecutionEngine) next(ctx context.Context, consumer string, queues []string) (*queue.Job, error) {
+	for _, name := range queues {
+		job, err := e.jobs.Dequeue(ctx, name, consumer, dequeueTimeout)
+		if errors.Is(err, queue.ErrEmpty) {
+			continue
+		}
+		return job, err
+	}
+	return nil, queue.ErrEmpty
+}
+
+// Process runs the execution of a job until the job's deadline and
+// acknowledges it. Jobs refused during shutdown are left in flight, for
+// the registry to hand back to the queue.
+func (e *ExecutionEngine) Process(consumer string, job *queue.Job) {
+	ctx, cancel := job.Context(correlation.WithID(context.Background(), job.CorrelationID))
+	defer cancel()
+
+	id, err := uuid.Parse(job.ExecutionID)
+	if err == nil {
+		err = e.Run(ctx, id)
+	}
+	if errors.Is(err, ErrShuttingDown) {
+		return
+	}
+	if err != nil {
+		e.log.Errorw("Failed to run execution", "execution_id", job.ExecutionID, "correlation_id", job.CorrelationID, "error", err)
+	}
+
+	ackCtx, cancelAck := context.WithTimeout(context.Background(), finishTimeout)
+	defer cancelAck()
+	if err := e.jobs.Ack(ackCtx, consumer, job); err != nil {
+		e.log.Errorw("Failed to ack job", "execution_id", job.ExecutionID, "error", err)
+	}
+}
+
+// Run runs a waiting execution to its end, or until a node suspends it.
+// A resumed execution carries on after the node it waited at. Failures of
+// the workflow, such as a node error or a cycle in its graph, fail the
+// execution and are not returned; the error is about the execution that
+// could not be run or recorded. An execution whose context expires times
+// out, and one whose context is cancelled is cancelled.
+func (e *ExecutionEngine) Run(ctx context.Context, executionID uuid.UUID) error {
+	if err := e.inFlight.Add(executionID.String()); err != nil {
+		return err
+	}
+	defer e.inFlight.Done(executionID.String())
+
+	exec, err := e.executions.FindByID(ctx, executionID)
+	if err != nil {
+		return err
+	}
+	_, err = e.runExecution(ctx, exec)
+	return err
+}
+
+// runExecution runs an execution as Run does and returns its run
+func (e *ExecutionEngine) runExecution(ctx context.Context, exec *execution.Execution) (*executionRun, error) {
+	if exec.Status.IsTerminal() {
+		return nil, execution.ErrExecutionAlreadyEnded
+	}
+
+	run := &executionRun{
+		exec:         exec,
+		outputs:      make(map[string][][]node.Item),
+		errorOutputs: make(map[string][]node.Item),
+		runCounts:    make(map[string]int),
+		states:       make(map[string]map[string]interface{}),
+		iterations:   make(map[string]int),
+		lineage:      NewLineage(),
+		expressions:  expression.NewCache(e.cfg.ExpressionCacheSize),
+	}
+	wait, err := e.waits.FindByExecution(ctx, exec.ID)
+	switch {
+	case errors.Is(err, execution.ErrExecutionNotWaiting):
+		exec.Start()
+	case err != nil:
+		return nil, err
+	case !wait.Claimed():
+		return nil, execution.ErrExecutionWaiting
+	default:
+		exec.Resume()
+		run.restore(wait)
+	}
+	if err := e.executions.Update(ctx, exec); err != nil {
+		return nil, err
+	}
+	e.events.Publish(execution.NewEvent(execution.EventExecutionStarted, exec))
+	e.log.Infow("Execution started", "execution_id", exec.ID, "workflow_id", exec.WorkflowID, "correlation_id", exec.CorrelationID,
+		"resumed", run.resumed != nil)
+
+	if err := e.prepare(ctx, run); err != nil {
+		exec.Fail(err, "")
+	} else {
+		walkCtx, cancel := e.withExecutionDeadline(ctx, run.workflow)
+		e.walk(walkCtx, run)
+		cancel()
+	}
+	return run, e.finish(ctx, run)
+}
+
+// executionRun is the state of an execution while the engine runs it
+type executionRun struct {
+	exec     *execution.Execution
+	workflow *workflowdomain.Workflow
+	graph    *workflowdomain.Graph
+	secrets  map[string]string
+	lineage  *Lineage
+	// pinned holds the items pinned to nodes by node ID, which the nodes
+	// of test executions output in place of running
+	pinned map[string][]node.Item
+	// expressions caches the results of the expressions in the parameters
+	// of the nodes
+	expressions *expression.Cache
+	// loops holds the loops of the workflow by the ID of their node
+	loops map[string]*loop
+
+	// mu guards the outputs and runs of nodes running in parallel
+	mu sync.RWMutex
+	// outputs holds the items of each node run, by node ID and output
+	outputs map[string][][]node.Item
+	// errorOutputs holds the items nodes continuing on fail routed to
+	// their error output, by node ID
+	errorOutputs map[string][]node.Item
+	runs         []node.NodeExecutionData
+	// runCounts counts the runs of each node, by node ID
+	runCounts map[string]int
+	// states holds the state nodes keep between their runs, by node ID
+	states map[string]map[string]interface{}
+	// iterations counts the runs of the nodes of the running loops in
+	// their loop, by node ID
+	iterations map[string]int
+	// resumed is the wait the execution was resumed from, and waiting the
+	// one it is suspended at
+	resumed *execution.WaitingExecution
+	waiting *execution.WaitingExecution
+}
+
+// restore carries on a resumed execution: the nodes that ran before it
+// was suspended keep their outputs, and the node it waited at outputs the
+// items it was resumed with
+func (r *executionRun) restore(wait *execution.WaitingExecution) {
+	for id, outputs := range wait.State.Outputs {
+		r.outputs[id] = outputs
+	}
+	for id, items := range wait.State.ErrorOutputs {
+		r.errorOutputs[id] = items
+	}
+	r.outputs[wait.NodeID] = [][]node.Item{wait.Items()}
+	r.runs = wait.State.Runs
+	r.resumed = wait
+}
+
+// done reports whether a node has run, in this run of the execution or
+// before it was suspended
+func (r *executionRun) done(id string) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	_, ok := r.outputs[id]
+	return ok
+}
+
+// suspend records the wait of a node, unless a node running in parallel
+// suspended the execution first; that node then runs again on resume
+func (r *executionRun) suspend(wn *workflowdomain.Node, items []node.Item, wait *node.WaitError) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.waiting != nil {
+		return
+	}
+	r.waiting = &execution.WaitingExecution{
+		ExecutionID: r.exec.ID,
+		NodeID:      wn.ID,
+		Webhook:     wait.Webhook,
+		State:       execution.WaitState{Input: items},
+		CreatedAt:   time.Now(),
+	}
+	if !wait.Until.IsZero() {
+		until := wait.Until
+		r.waiting.ResumeAt = &until
+	}
+}
+
+// prepare loads the workflow version the execution runs, its compiled
+// graph with its loops and its secrets
+func (e *ExecutionEngine) prepare(ctx context.Context, run *executionRun) error {
+	w, err := e.workflow(ctx, run.exec)
+	if err != nil {
+		return err
+	}
+	g, err := e.workflows.Graph(w)
+	if err != nil {
+		return err
+	}
+	secrets, err := e.secrets.Values(ctx, w.ID)
+	if err != nil {
+		return err
+	}
+
+	loops, err := e.loops(g)
+	if err != nil {
+		return err
+	}
+
+	run.workflow, run.graph, run.secrets, run.loops = w, g, secrets, loops
+	if run.exec.Mode == execution.ExecutionModeTest && e.pinned != nil {
+		if run.pinned, err = e.pinned.Items(ctx, w.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// workflow returns the workflow version an execution runs. Versions are
+// only missing for executions of drafts, which run the draft.
+func (e *ExecutionEngine) workflow(ctx context.Context, exec *execution.Execution) (*workflowdomain.Workflow, error) {
+	w, err := e.workflows.AtVersion(ctx, exec.WorkflowID, exec.WorkflowVersion)
+	if !errors.Is(err, workflowdomain.ErrVersionNotFound) {
+		return w, err
+	}
+	draft, draftErr := e.workflows.Get(ctx, exec.WorkflowID)
+	if draftErr != nil || draft.Version != exec.WorkflowVersion {
+		return nil, err
+	}
+	return draft, nil
+}
+
+// nodeContext returns the context the nodes of an execution run with: the
+// shared clients, the rate limited and circuit broken HTTP client, the
+// binary data store and the runner of sub-workflows
+func (e *ExecutionEngine) nodeContext(ctx context.Context, run *executionRun) context.Context {
+	ctx = pool.NewContext(ctx, e.clients)
+	if e.http != nil {
+		ctx = nodesdk.WithHTTPClient(ctx, e.http)
+	}
+	if e.binary != nil {
+		// The binary data of a workflow tagged with a region stays in the
+		// region's storage
+		ctx = binarydata.NewContext(binarydata.WithRegion(ctx, run.workflow.Settings.Region), e.binary)
+	}
+	return node.WithWorkflowRunner(ctx, &subWorkflowRunner{engine: e, run: run})
+}
+
+// walk runs the nodes of the execution, one at a time or the independent
+// branches in parallel as the workflow's execution order says, and ends
+// the execution
+func (e *ExecutionEngine) walk(ctx context.Context, run *executionRun) {
+	ctx = e.nodeContext(ctx, run)
+
+	if run.resumed != nil {
+		run.relink()
+		e.recordResumed(ctx, run)
+	}
+
+	var failed *workflowdomain.Node
+	var err error
+	// Loops run one node at a time, whatever the execution order
+	if run.workflow.Settings.ExecutionOrder == workflowdomain.ExecutionOrderParallel && !run.graph.HasLoops() {
+		failed, err = e.walkParallel(ctx, run)
+	} else {
+		failed, err = e.walkSequential(ctx, run)
+	}
+
+	switch {
+	case failed != nil:
+		run.exec.Fail(fmt.Errorf("node %s: %w", failed.Name, err), failed.ID)
+	case errors.Is(err, errWaiting):
+		run.exec.Suspend()
+	case errors.Is(err, context.DeadlineExceeded):
+		e.timeOut(ctx, run)
+	case err != nil:
+		run.exec.Cancel()
+	default:
+		run.exec.Complete(map[string]interface{}{"data": run.result()})
+	}
+}
+
+// walkSequential runs the nodes in topological order until one fails,
+// returning it, one suspends the execution or the context ends. Nodes that
+// ran before the execution was suspended are skipped.
+func (e *ExecutionEngine) walkSequential(ctx context.Context, run *executionRun) (*workflowdomain.Node, error) {
+	return e.runSequence(ctx, run, run.graph.Order)
+}
+
+// runSequence runs nodes one at a time in the order given, as
+// walkSequential does. The node of a loop runs its loop, whose body nodes
+// are then done.
+func (e *ExecutionEngine) runSequence(ctx context.Context, run *executionRun, ids []string) (*workflowdomain.Node, error) {
+	for _, id := range ids {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if run.done(id) {
+			continue
+		}
+		wn, _ := run.graph.Node(id)
+		failed, err := wn, error(nil)
+		if l, ok := run.loops[id]; ok {
+			failed, err = e.runLoop(ctx, run, wn, l)
+		} else {
+			err = e.runNode(ctx, run, wn)
+		}
+		if err != nil {
+			if errors.Is(err, errWaiting) {
+				return nil, err
+			}
+			// A node stopped as the execution ends does not fail it
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			return failed, err
+		}
+	}
+	return nil, nil
+}
+
+// runNode runs a node with the items of the connections entering it and
+// records its output. Disabled nodes pass their items on without running.
+// A failing node is retried as its retry settings say, then fails the
+// execution, unless it continues on fail, in which case it passes its
+// items on: on its error output when connections leave it, with the error
+// in each item, else on its main output. A node asking to wait suspends
+// the execution, returning errWaiting.
+func (e *ExecutionEngine) runNode(ctx context.Context, run *executionRun, wn *workflowdomain.Node) error {
+	items, origins := run.input(wn.ID)
+	run.lineage.Begin(wn.Name, origins)
+	if wn.Disabled {
+		passed := passThrough(items)
+		run.lineage.Record(wn.Name, passed)
+		run.setOutput(wn.ID, 0, passed)
+		return nil
+	}
+
+	record := &execution.NodeExecution{
+		ID:          uuid.New(),
+		ExecutionID: run.exec.ID,
+		NodeID:      wn.ID,
+		NodeType:    wn.Type,
+		NodeName:    wn.Name,
+		Status:      execution.ExecutionStatusRunning,
+		InputData:   map[string]interface{}{"data": items},
+		StartedAt:   time.Now(),
+	}
+	e.events.Publish(execution.NewNodeEvent(execution.EventNodeStarted, run.exec, record))
+
+	input := &node.NodeInput{
+		Data:       items,
+		Parameters: wn.Parameters,
+		Context:    run.context(wn, run.nextRun(wn.ID)),
+	}
+	output, err := e.executeWithRetries(ctx, run, wn, input, record)
+	finished := time.Now()
+
+	var wait *node.WaitError
+	if errors.As(err, &wait) {
+		record.Status = execution.ExecutionStatusWaiting
+		if writeErr := e.writer.Write(ctx, record); writeErr != nil {
+			e.log.Errorw("Failed to queue node execution record", "execution_id", run.exec.ID, "node_id", wn.ID, "error", writeErr)
+		}
+		run.suspend(wn, items, wait)
+		return errWaiting
+	}
+
+	nodeRun := NodeRun(wn.ID, wn.Type, record.StartedAt, finished, input, output, err)
+	nodeRun.TimeoutMs = e.nodeTimeout(wn).Milliseconds()
+	nodeRun.TimedOut = errors.Is(err, ErrNodeTimedOut) || errors.Is(ctx.Err(), context.DeadlineExceeded)
+	if e.anomalies != nil {
+		e.anomalies.Observe(run.workflow.ID, &nodeRun)
+	}
+	run.mu.Lock()
+	run.runs = append(run.runs, nodeRun)
+	run.mu.Unlock()
+
+	record.FinishedAt = &finished
+	record.ExecutionTimeMs = int(nodeRun.ExecutionTimeMs)
+	// a failed node continuing on fail passes its items on its first output
+	index := 0
+	if nodeRun.Status == "error" {
+		record.Status = execution.ExecutionStatusError
+		record.ErrorMessage = nodeRun.Error
+	} else {
+		record.Status = execution.ExecutionStatusSuccess
+		items = node.PairItems(input.Data, output.Data)
+		record.OutputData = map[string]interface{}{"data": items, "metadata": nodeRun.Metadata}
+		if index = output.Output; index > 0 {
+			record.OutputData["output"] = index
+		}
+		if nodeRun.RunIndex > 0 {
+			record.OutputData["run_index"] = nodeRun.RunIndex
+		}
+	}
+	// the record of a node cut off by the execution deadline is still kept
+	if writeErr := e.writer.Write(context.WithoutCancel(ctx), record); writeErr != nil {
+		e.log.Errorw("Failed to queue node execution record", "execution_id", run.exec.ID, "node_id", wn.ID, "error", writeErr)
+	}
+	e.events.Publish(execution.NewNodeEvent(execution.EventNodeFinished, run.exec, record))
+	e.streamOutput(run, record)
+
+	if record.Status == execution.ExecutionStatusError && !wn.ContinueOnFail {
+		return errors.New(record.ErrorMessage)
+	}
+	if record.Status == execution.ExecutionStatusError && run.graph.HasErrorOutput(wn.ID) {
+		failed := errorItems(items, record.ErrorMessage)
+		run.lineage.Record(wn.Name, failed)
+		run.setErrorOutput(wn.ID, failed)
+		return nil
+	}
+	run.lineage.Record(wn.Name, items)
+	run.setOutput(wn.ID, index, items)
+	return nil
+}
+
+// recordResumed records the run of the node a resumed execution waited at
+// as finished, with the items it outputs
+func (e *ExecutionEngine) recordResumed(ctx context.Context, run *executionRun) {
+	wait := run.resumed
+	wn, ok := run.graph.Node(wait.NodeID)
+	if !ok {
+		return
+	}
+	finished := time.Now()
+	record := &execution.NodeExecution{
+		ID:              uuid.New(),
+		ExecutionID:     run.exec.ID,
+		NodeID:          wn.ID,
+		NodeType:        wn.Type,
+		NodeName:        wn.Name,
+		Status:          execution.ExecutionStatusSuccess,
+		InputData:       map[string]interface{}{"data": wait.State.Input},
+		OutputData:      map[string]interface{}{"data": wait.Items()},
+		ExecutionTimeMs: int(finished.Sub(wait.CreatedAt).Milliseconds()),
+		StartedAt:       wait.CreatedAt,
+		FinishedAt:      &finished,
+	}
+	if err := e.writer.Write(ctx, record); err != nil {
+		e.log.Errorw("Failed to queue node execution record", "execution_id", run.exec.ID, "node_id", wn.ID, "error", err)
+	}
+	e.events.Publish(execution.NewNodeEvent(execution.EventNodeFinished, run.exec, record))
+	e.streamOutput(run, record)
+}
+
+// streamOutput publishes the output of a finished node of an interactive
+// execution, for the editor to show before the execution ends
+func (e *ExecutionEngine) streamOutput(run *executionRun, record *execution.NodeExecution) {
+	if e.outputs != nil && run.exec.Mode.IsInteractive() {
+		e.outputs.PublishOutput(run.exec, record)
+	}
+}
+
+// execute instantiates a node and runs it as its options say. A node with
+// pinned data outputs it instead of running.
+func (e *ExecutionEngine) execute(ctx context.Context, run *executionRun, wn *workflowdomain.Node, input *node.NodeInput) (*node.NodeOutput, error) {
+	if items, ok := run.pinned[wn.ID]; ok {
+		return pinnedOutput(items), nil
+	}
+	constructor, err := e.nodes.Get(wn.Type)
+	if err != nil {
+		return nil, err
+	}
+	// The node types of a team are unknown to the workflows of others
+	if team := e.nodes.Team(wn.Type); team != nil && (run.workflow.TeamID == nil || *run.workflow.TeamID != *team) {
+		return nil, errors.New("node type not found: " + wn.Type)
+	}
+	if input, err = e.resolve(run, wn, input); err != nil {
+		return nil, err
+	}
+	impl := wrapDryRun(run.exec, *wn, constructor())
+	// Looping nodes run with all items, even none, for their loop to go on
+	if _, ok := impl.(node.Looper); !ok {
+		impl = WrapEmptyHandling(*wn, WrapExecutionMode(*wn, impl))
+	}
+
+	nodeCtx := ctx
+	timeout := e.nodeTimeout(wn)
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		nodeCtx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	output, err := impl.Execute(nodeCtx, input)
+	// Cut off at its own deadline rather than that of the execution, the
+	// node fails like any other failing node
+	if timeout > 0 && ctx.Err() == nil && errors.Is(nodeCtx.Err(), context.DeadlineExceeded) {
+		return nil, fmt.Errorf("%w after %s", ErrNodeTimedOut, timeout)
+	}
+	if err == nil && output == nil {
+		output = &node.NodeOutput{Data: []node.Item{}}
+	}
+	return output, err
+}
+
+// pinnedOutput returns the output of a node with pinned items, copies of
+// the items so that the nodes after it cannot change those pinned
+func pinnedOutput(pinned []node.Item) *node.NodeOutput {
+	items := make([]node.Item, len(pinned))
+	copy(items, pinned)
+	return &node.NodeOutput{
+		Data:     items,
+		Metadata: map[string]interface{}{"pinned": true},
+	}
+}
+
+// input returns the items a node runs with, those of the connections
+// entering it in the order of the connections whatever order the nodes
+// before it finished in, and the output item of a node before it each one
+// is. The nodes without incoming connections run with the input items of
+// the execution. The node of a running loop runs again with the items of
+// the connections looping back to it.
+func (r *executionRun) input(id string) ([]node.Item, []itemOrigin) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	incoming := r.graph.Incoming(id)
+	if r.iterations[id] > 0 {
+		incoming = r.graph.Loops(id)
+	}
+	if len(incoming) == 0 {
+		return r.exec.InputItems(), nil
+	}
+
+	items := []node.Item{}
+	var origins []itemOrigin
+	for _, c := range incoming {
+		var from []node.Item
+		if c.Source.Type == workflowdomain.ConnectionTypeError {
+			from = r.errorOutputs[c.Source.NodeID]
+		} else if outputs := r.outputs[c.Source.NodeID]; c.Source.Index < len(outputs) {
+			from = outputs[c.Source.Index]
+		}
+		var name string
+		if source, ok := r.graph.Node(c.Source.NodeID); ok {
+			name = source.Name
+		}
+		for k := range from {
+			origins = append(origins, itemOrigin{node: name, item: k})
+		}
+		items = append(items, from...)
+	}
+	return items, origins
+}
+
+// relink rebuilds the lineage of a resumed execution from the outputs of
+// the nodes that ran before it was suspended
+func (r *executionRun) relink() {
+	for _, id := range r.graph.Order {
+		if !r.done(id) {
+			continue
+		}
+		wn, ok := r.graph.Node(id)
+		if !ok {
+			continue
+		}
+		items, origins := r.input(id)
+		r.mu.RLock()
+		output := r.errorOutputs[id]
+		if len(output) == 0 && len(r.outputs[id]) > 0 {
+			output = r.outputs[id][0]
+		}
+		r.mu.RUnlock()
+		r.lineage.Begin(wn.Name, origins)
+		r.lineage.Record(wn.Name, node.PairItems(items, output))
+	}
+}
+
+// setOutput records the items a node output on one of its outputs; its
+// other outputs are empty
+func (r *executionRun) setOutput(id string, index int, items []node.Item) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	outputs := make([][]node.Item, index+1)
+	for i := range outputs {
+		outputs[i] = []node.Item{}
+	}
+	outputs[index] = items
+	r.outputs[id] = outputs
+}
+
+// nextRun counts a run of a node, returning the number of its runs before
+func (r *executionRun) nextRun(id string) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	index := r.runCounts[id]
+	r.runCounts[id]++
+	return index
+}
+
+// setErrorOutput records the items a failed node routes to its error
+// output; its main output is empty
+func (r *executionRun) setErrorOutput(id string, items []node.Item) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.outputs[id] = [][]node.Item{{}}
+	r.errorOutputs[id] = items
+}
+
+// passThrough returns the items a disabled node passes on, each paired
+// with the input item it is
+func passThrough(input []node.Item) []node.Item {
+	items := make([]node.Item, len(input))
+	for i, in := range input {
+		in.PairedItem = []node.PairedItem{{Item: i}}
+		items[i] = in
+	}
+	return items
+}
+
+// errorItems returns the items a failed node outputs on its error output:
+// its input items with the error message under "error", each paired with
+// the input item it copies
+func errorItems(input []node.Item, message string) []node.Item {
+	items := make([]node.Item, len(input))
+	for i, in := range input {
+		data := make(map[string]interface{}, len(in.JSON)+1)
+		for k, v := range in.JSON {
+			data[k] = v
+		}
+		data["error"] = message
+		items[i] = node.Item{JSON: data, Binary: in.Binary, PairedItem: []node.PairedItem{{Item: i}}}
+	}
+	return items
+}
+
+// result returns the items of the execution: the output of the last node
+// in topological order that ran and output items
+func (r *executionRun) result() []node.Item {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for i := len(r.graph.Order) - 1; i >= 0; i-- {
+		id := r.graph.Order[i]
+		if wn, _ := r.graph.Node(id); wn.Disabled {
+			continue
+		}
+		if outputs := r.outputs[id]; len(outputs) > 0 && len(outputs[0]) > 0 {
+			return outputs[0]
+		}
+	}
+	return nil
+}
+
+// context returns the execution context of a node run, with the state
+// the node keeps between its runs
+func (r *executionRun) context(wn *workflowdomain.Node, runIndex int) *node.ExecutionContext {
+	r.mu.Lock()
+	state, ok := r.states[wn.ID]
+	if !ok {
+		state = make(map[string]interface{})
+		r.states[wn.ID] = state
+	}
+	r.mu.Unlock()
+
+	return &node.ExecutionContext{
+		WorkflowID:    r.workflow.ID.String(),
+		ExecutionID:   r.exec.ID.String(),
+		NodeID:        wn.ID,
+		RunIndex:      runIndex,
+		ActiveNode:    wn.Name,
+		Variables:     r.workflow.Variables,
+		Mode:          string(r.exec.Mode),
+		Timezone:      r.workflow.Settings.Timezone,
+		MaxRetries:    wn.MaxRetries,
+		DryRun:        r.exec.DryRun,
+		CorrelationID: r.exec.CorrelationID,
+		Secrets:       r.secrets,
+		State:         state,
+	}
+}
+
+// finish stores the node records and the ended execution, records its
+// usage and notifies those waiting for it. The context of the execution
+// may have expired, so recording the end gets a context of its own.
+func (e *ExecutionEngine) finish(ctx context.Context, run *executionRun) error {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), finishTimeout)
+	defer cancel()
+
+	exec := run.exec
+	if err := e.writer.Flush(ctx); err != nil {
+		e.log.Errorw("Failed to flush node execution records", "execution_id", exec.ID, "error", err)
+	}
+	if exec.Status == execution.ExecutionStatusWaiting {
+		suspended, err := e.suspend(ctx, run)
+		if suspended || err != nil {
+			return err
+		}
+	}
+	if err := e.executions.Update(ctx, exec); err != nil {
+		return err
+	}
+	if run.resumed != nil {
+		if err := e.waits.Delete(ctx, exec.ID); err != nil {
+			e.log.Warnw("Failed to delete execution wait", "execution_id", exec.ID, "error", err)
+		}
+	}
+	if e.usage != nil && len(run.runs) > 0 {
+		// Failures are logged by the recorder
+		_ = e.usage.Record(ctx, exec, run.runs)
+	}
+	e.finished.NotifyFinished(ctx, exec)
+
+	e.log.Infow("Execution finished", "execution_id", exec.ID, "workflow_id", exec.WorkflowID, "status", exec.Status,
+		"duration_ms", exec.ExecutionTimeMs, "error_node", exec.ErrorNode, "correlation_id", exec.CorrelationID)
+	return nil
+}
+
+// suspend keeps the state of an execution suspended at a node until it is
+// resumed. An execution whose state cannot be kept fails instead, which
+// suspend reports by returning false.
+func (e *ExecutionEngine) suspend(ctx context.Context, run *executionRun) (bool, error) {
+	exec, wait := run.exec, run.waiting
+	run.mu.RLock()
+	wait.State.Outputs, wait.State.ErrorOutputs, wait.State.Runs = run.outputs, run.errorOutputs, run.runs
+	run.mu.RUnlock()
+
+	if err := e.waits.Save(ctx, wait); err != nil {
+		exec.Fail(fmt.Errorf("keep execution state: %w", err), wait.NodeID)
+		return false, nil
+	}
+	if err := e.executions.Update(ctx, exec); err != nil {
+		return true, err
+	}
+	e.events.Publish(execution.NewEvent(execution.EventExecutionWaiting, exec))
+	e.log.Infow("Execution waiting", "execution_id", exec.ID, "workflow_id", exec.WorkflowID, "node_id", wait.NodeID,
+		"resume_at", wait.ResumeAt, "webhook", wait.Webhook, "correlation_id", exec.CorrelationID)
+	return true, nil
+}
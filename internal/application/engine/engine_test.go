@@ -0,0 +1,55 @@
+package engine
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jaydeep/go-n8n/configs"
+	"github.com/jaydeep/go-n8n/internal/domain/node"
+	workflowdomain "github.com/jaydeep/go-n8n/internal/domain/workflow"
+	"github.com/jaydeep/go-n8n/internal/nodes/core/action"
+	"github.com/jaydeep/go-n8n/pkg/nodesdk"
+)
+
+func TestNodeRequestsThrottled(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"ok":true}`))
+	}))
+	defer server.Close()
+
+	// One request at once, then one every 100ms
+	client := nodesdk.NewHTTPClient(nil, configs.OutboundRateLimitConfig{RequestsPerSecond: 10, Burst: 1}, configs.CircuitBreakerConfig{})
+	e := &ExecutionEngine{http: client}
+	run := &executionRun{workflow: &workflowdomain.Workflow{}}
+	ctx := e.nodeContext(context.Background(), run)
+
+	input := &node.NodeInput{
+		Data:       []node.Item{{JSON: map[string]interface{}{}}, {JSON: map[string]interface{}{}}, {JSON: map[string]interface{}{}}},
+		Parameters: map[string]interface{}{"method": "GET", "url": server.URL},
+	}
+	started := time.Now()
+	if _, err := action.NewHTTPRequestNode().Execute(ctx, input); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if elapsed := time.Since(started); elapsed < 180*time.Millisecond {
+		t.Errorf("3 requests took %s, want about 200ms at 10 per second with a burst of 1", elapsed)
+	}
+	if got := calls.Load(); got != 3 {
+		t.Errorf("server got %d requests, want 3", got)
+	}
+}
+
+func TestNodeContextWithoutHTTPClient(t *testing.T) {
+	e := &ExecutionEngine{}
+	ctx := e.nodeContext(context.Background(), &executionRun{workflow: &workflowdomain.Workflow{}})
+	if nodesdk.HTTPClientFrom(ctx) == nil {
+		t.Fatal("HTTPClientFrom() = nil, want the default client")
+	}
+}
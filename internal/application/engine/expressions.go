@@ -0,0 +1,211 @@
+package engine
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/jaydeep/go-n8n/internal/domain/node"
+	workflowdomain "github.com/jaydeep/go-n8n/internal/domain/workflow"
+	"github.com/jaydeep/go-n8n/internal/expression"
+)
+
+// expressionEnv reads the environment variables expressions may read as
+// $env. Only those listed in the configuration are exposed, so that the
+// secrets of the instance stay out of reach of workflows.
+func expressionEnv(names []string) map[string]string {
+	env := make(map[string]string, len(names))
+	for _, name := range names {
+		if v, ok := os.LookupEnv(name); ok {
+			env[name] = v
+		}
+	}
+	return env
+}
+
+// resolve returns the input of a node run with the {{ }} expressions in
+// its parameters evaluated: Parameters for the first input item, and
+// ItemParameters for each item. The input is returned unchanged when no
+// parameter has expressions. The evaluated parameters may hold secrets, so
+// they are given to the node only and never recorded.
+func (e *ExecutionEngine) resolve(run *executionRun, wn *workflowdomain.Node, input *node.NodeInput) (*node.NodeInput, error) {
+	templates := run.graph.Expressions(wn.ID)
+	if len(templates) == 0 {
+		return input, nil
+	}
+
+	base := run.expressionData(wn.Name, input, e.env)
+	evaluate := func(index int) (map[string]interface{}, error) {
+		data := *base
+		data.ItemIndex = index
+		value, err := evaluateParameters(wn.Parameters, "", templates, func(t *expression.Template) (interface{}, error) {
+			return run.expressions.Evaluate(t, wn.ID, data.RunIndex, index, &data)
+		})
+		if err != nil {
+			return nil, err
+		}
+		params, _ := value.(map[string]interface{})
+		return params, nil
+	}
+
+	params, err := evaluate(0)
+	if err != nil {
+		return nil, err
+	}
+	resolved := *input
+	resolved.Parameters = params
+	resolved.ItemParameters = func(index int) (map[string]interface{}, error) {
+		if index == 0 {
+			return params, nil
+		}
+		return evaluate(index)
+	}
+	return &resolved, nil
+}
+
+// expressionData returns what the expressions of a run of the named node
+// read, for its first input item
+func (r *executionRun) expressionData(name string, input *node.NodeInput, env map[string]string) *expression.Data {
+	data := &expression.Data{
+		Input:   expressionItems(input.Data),
+		Node:    r.nodeItems(),
+		Paired:  r.pairedItem(name),
+		Vars:    r.workflow.Variables,
+		Secrets: r.secrets,
+		Env:     env,
+		Execution: expression.ExecutionInfo{
+			ID:            r.exec.ID.String(),
+			Mode:          string(r.exec.Mode),
+			CorrelationID: r.exec.CorrelationID,
+		},
+		Workflow: expression.WorkflowInfo{
+			ID:     r.workflow.ID.String(),
+			Name:   r.workflow.Name,
+			Active: r.workflow.IsActive,
+		},
+	}
+	if input.Context != nil {
+		data.RunIndex = input.Context.RunIndex
+	}
+	if tz := r.workflow.Settings.Timezone; tz != "" {
+		if loc, err := time.LoadLocation(tz); err == nil {
+			data.Location = loc
+		}
+	}
+	return data
+}
+
+// nodeItems returns a function returning the main output items of the
+// node of the workflow with a name, converted once per node run
+func (r *executionRun) nodeItems() func(name string) ([]expression.Item, bool) {
+	var mu sync.Mutex
+	converted := make(map[string][]expression.Item)
+	return func(name string) ([]expression.Item, bool) {
+		mu.Lock()
+		defer mu.Unlock()
+		if items, ok := converted[name]; ok {
+			return items, true
+		}
+		for _, id := range r.graph.Order {
+			wn, _ := r.graph.Node(id)
+			if wn.Name != name {
+				continue
+			}
+			r.mu.RLock()
+			outputs, ok := r.outputs[id]
+			r.mu.RUnlock()
+			if !ok {
+				return nil, false
+			}
+			var items []expression.Item
+			if len(outputs) > 0 {
+				items = expressionItems(outputs[0])
+			}
+			converted[name] = items
+			return items, true
+		}
+		return nil, false
+	}
+}
+
+// pairedItem returns a function returning the item of the node with a
+// name that an input item of the named node was made from, traced through
+// the lineage of the execution
+func (r *executionRun) pairedItem(nodeName string) func(name string, index int) (expression.Item, error) {
+	return func(name string, index int) (expression.Item, error) {
+		item, err := r.lineage.InputItem(name, nodeName, index)
+		if err != nil {
+			return expression.Item{}, err
+		}
+		return expressionItems([]node.Item{item})[0], nil
+	}
+}
+
+// expressionItems converts items into the items expressions read
+func expressionItems(items []node.Item) []expression.Item {
+	out := make([]expression.Item, len(items))
+	for i, item := range items {
+		out[i].JSON = item.JSON
+		if len(item.Binary) > 0 {
+			out[i].Binary = make(map[string]interface{}, len(item.Binary))
+			for k, b := range item.Binary {
+				out[i].Binary[k] = map[string]interface{}{
+					"mime_type": b.MimeType,
+					"file_name": b.FileName,
+					"file_size": b.FileSize,
+					"id":        b.ID,
+				}
+			}
+		}
+	}
+	return out
+}
+
+// evaluateParameters returns a copy of a parameter value with the
+// templates found at its paths, as the graph collected them, replaced by
+// their values
+func evaluateParameters(value interface{}, path string, templates map[string]*expression.Template, eval func(*expression.Template) (interface{}, error)) (interface{}, error) {
+	switch v := value.(type) {
+	case string:
+		t, ok := templates[path]
+		if !ok {
+			return v, nil
+		}
+		out, err := eval(t)
+		if err != nil {
+			return nil, fmt.Errorf("parameter %s: %w", path, err)
+		}
+		return out, nil
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(v))
+		for k, item := range v {
+			resolved, err := evaluateParameters(item, joinPath(path, k), templates, eval)
+			if err != nil {
+				return nil, err
+			}
+			out[k] = resolved
+		}
+		return out, nil
+	case []interface{}:
+		out := make([]interface{}, len(v))
+		for i, item := range v {
+			resolved, err := evaluateParameters(item, joinPath(path, strconv.Itoa(i)), templates, eval)
+			if err != nil {
+				return nil, err
+			}
+			out[i] = resolved
+		}
+		return out, nil
+	}
+	return value, nil
+}
+
+// joinPath joins parameter paths as the graph does
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
@@ -0,0 +1,31 @@
+package engine
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestExpressionEnv(t *testing.T) {
+	t.Setenv("EXPRESSION_ENV_REGION", "eu")
+	t.Setenv("EXPRESSION_ENV_EMPTY", "")
+	t.Setenv("EXPRESSION_ENV_SECRET", "hidden")
+
+	tests := []struct {
+		name  string
+		names []string
+		want  map[string]string
+	}{
+		{name: "nothing listed", want: map[string]string{}},
+		{name: "listed variables only", names: []string{"EXPRESSION_ENV_REGION"}, want: map[string]string{"EXPRESSION_ENV_REGION": "eu"}},
+		{name: "empty variable kept", names: []string{"EXPRESSION_ENV_EMPTY"}, want: map[string]string{"EXPRESSION_ENV_EMPTY": ""}},
+		{name: "unset variable left out", names: []string{"EXPRESSION_ENV_UNSET"}, want: map[string]string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := expressionEnv(tt.names); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("expressionEnv(%v) = %v, want %v", tt.names, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// ErrShuttingDown is returned for executions started during shutdown
+var ErrShuttingDown = errors.New("engine is shutting down")
+
+// InFlight tracks the executions running in this process, so shutdown can
+// wait for them to finish before the writers they report to are flushed
+type InFlight struct {
+	mu       sync.Mutex
+	running  map[string]struct{}
+	draining bool
+	idle     chan struct{}
+}
+
+// NewInFlight creates a new in-flight execution tracker
+func NewInFlight() *InFlight {
+	return &InFlight{running: make(map[string]struct{})}
+}
+
+// Add records an execution as running. It fails once draining started, in
+// which case the execution must be left for another process.
+func (f *InFlight) Add(executionID string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.draining {
+		return ErrShuttingDown
+	}
+	f.running[executionID] = struct{}{}
+	return nil
+}
+
+// Done records an execution as finished
+func (f *InFlight) Done(executionID string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.running, executionID)
+	if len(f.running) == 0 && f.idle != nil {
+		close(f.idle)
+		f.idle = nil
+	}
+}
+
+// Len returns the number of running executions
+func (f *InFlight) Len() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.running)
+}
+
+// Drain rejects new executions and waits until the running ones finish or
+// ctx expires
+func (f *InFlight) Drain(ctx context.Context) error {
+	f.mu.Lock()
+	f.draining = true
+	if len(f.running) == 0 {
+		f.mu.Unlock()
+		return nil
+	}
+	if f.idle == nil {
+		f.idle = make(chan struct{})
+	}
+	idle := f.idle
+	f.mu.Unlock()
+
+	select {
+	case <-idle:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
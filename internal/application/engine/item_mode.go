@@ -0,0 +1,121 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jaydeep/go-n8n/internal/domain/node"
+	"github.com/jaydeep/go-n8n/internal/domain/workflow"
+)
+
+// WrapExecutionMode returns a node implementation running the node as its
+// execute once flag and execution mode say: with only the first input item
+// when it executes once, once per input item in per-item mode. Their output
+// items are paired with the input items they were made from. Nodes
+// running once with all items are returned unchanged, as are nodes without
+// input items, such as triggers, which run once whatever their mode.
+func WrapExecutionMode(wn workflow.Node, impl node.NodeInterface) node.NodeInterface {
+	switch {
+	case wn.ExecuteOnce:
+		return &onceNode{NodeInterface: impl}
+	case wn.ExecutionMode == workflow.NodeExecutionModePerItem:
+		return &perItemNode{NodeInterface: impl}
+	default:
+		return impl
+	}
+}
+
+// onceNode runs a node with its first input item only
+type onceNode struct {
+	node.NodeInterface
+}
+
+// Execute runs the wrapped node once, with the first input item
+func (o *onceNode) Execute(ctx context.Context, input *node.NodeInput) (*node.NodeOutput, error) {
+	if len(input.Data) <= 1 {
+		return o.NodeInterface.Execute(ctx, input)
+	}
+	first := *input
+	first.Data = input.Data[:1]
+	output, err := o.NodeInterface.Execute(ctx, &first)
+	if err != nil || output == nil {
+		return output, err
+	}
+	paired := *output
+	paired.Data = node.PairItems(first.Data, output.Data)
+	return &paired, nil
+}
+
+// perItemNode runs a node once for each input item
+type perItemNode struct {
+	node.NodeInterface
+}
+
+// Execute runs the wrapped node for each input item in turn, with the
+// item's index in the execution context and the parameters evaluated for
+// the item, and returns the items of all runs
+// in order, paired with the items they were made from. The first failing
+// item stops the node; its error names the item.
+func (p *perItemNode) Execute(ctx context.Context, input *node.NodeInput) (*node.NodeOutput, error) {
+	if len(input.Data) == 0 {
+		return p.NodeInterface.Execute(ctx, input)
+	}
+
+	combined := &node.NodeOutput{Data: []node.Item{}}
+	for i := range input.Data {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		single := *input
+		single.Data = input.Data[i : i+1]
+		if input.ItemParameters != nil {
+			params, err := input.ItemParameters(i)
+			if err != nil {
+				return nil, fmt.Errorf("item %d: %w", i, err)
+			}
+			offset := i
+			single.Parameters = params
+			single.ItemParameters = func(index int) (map[string]interface{}, error) {
+				return input.ItemParameters(offset + index)
+			}
+		}
+		if input.Context != nil {
+			itemContext := *input.Context
+			itemContext.ItemIndex = i
+			single.Context = &itemContext
+		}
+
+		output, err := p.NodeInterface.Execute(ctx, &single)
+		if err != nil {
+			return nil, fmt.Errorf("item %d: %w", i, err)
+		}
+		if output == nil {
+			continue
+		}
+		if output.Error != nil {
+			combined.Error = fmt.Errorf("item %d: %w", i, output.Error)
+			return combined, nil
+		}
+		for _, item := range node.PairItems(single.Data, output.Data) {
+			combined.Data = append(combined.Data, offsetPairing(item, i))
+		}
+		for k, v := range output.Metadata {
+			if combined.Metadata == nil {
+				combined.Metadata = make(map[string]interface{}, len(output.Metadata))
+			}
+			combined.Metadata[k] = v
+		}
+	}
+	return combined, nil
+}
+
+// offsetPairing returns the item paired with the input item at index
+// rather than with the only item of its run
+func offsetPairing(item node.Item, index int) node.Item {
+	pairing := make([]node.PairedItem, len(item.PairedItem))
+	for k, p := range item.PairedItem {
+		pairing[k] = node.PairedItem{Item: p.Item + index, Input: p.Input}
+	}
+	item.PairedItem = pairing
+	return item
+}
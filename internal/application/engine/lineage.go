@@ -0,0 +1,129 @@
+package engine
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/jaydeep/go-n8n/internal/domain/node"
+)
+
+var (
+	// ErrPairedItemNotFound is returned when no item of a node leads to
+	// the current item
+	ErrPairedItemNotFound = errors.New("no paired item found")
+	// ErrPairedItemAmbiguous is returned when several items of a node lead
+	// to the current item
+	ErrPairedItemAmbiguous = errors.New("several paired items found")
+)
+
+// itemOrigin is an output item of a node: the node's name and the index
+// of the item among those it output. A node receives the items of all its
+// inputs as one list, so the origins of its input items, in order, map the
+// paired items of its output to the nodes before it.
+type itemOrigin struct {
+	node string
+	item int
+}
+
+// lineageRun is the latest run of a node: where its input items came from
+// and its output items
+type lineageRun struct {
+	inputs []itemOrigin
+	items  []node.Item
+}
+
+// Lineage records the output items of the node runs of an execution, so
+// that an item can be traced back through its paired items to the item of
+// an upstream node it was made from, as $("Node").item does. A node run
+// again replaces the items of its earlier run.
+type Lineage struct {
+	mu     sync.RWMutex
+	runs   map[string]lineageRun
+	inputs map[string][]itemOrigin
+}
+
+// NewLineage creates an empty lineage for one execution
+func NewLineage() *Lineage {
+	return &Lineage{
+		runs:   make(map[string]lineageRun),
+		inputs: make(map[string][]itemOrigin),
+	}
+}
+
+// Begin records where the input items of a node run come from, before the
+// node runs, so that the expressions of its parameters can trace them
+func (l *Lineage) Begin(nodeName string, inputs []itemOrigin) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.inputs[nodeName] = inputs
+}
+
+// Record stores the output items of the node run begun last, paired with
+// its input items by node.PairItems
+func (l *Lineage) Record(nodeName string, items []node.Item) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.runs[nodeName] = lineageRun{inputs: l.inputs[nodeName], items: items}
+}
+
+// Item returns the item of the target node that item, an output item of
+// the named node, was made from. It fails when the pairing of the items in
+// between does not lead to exactly one item of the target.
+func (l *Lineage) Item(target, nodeName string, item node.Item) (node.Item, error) {
+	if nodeName == target {
+		return item, nil
+	}
+
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.resolve(target, l.runs[nodeName].inputs, item.PairedItem)
+}
+
+// InputItem returns the item of the target node that the input item at
+// index of the running node was made from, as Item does
+func (l *Lineage) InputItem(target, nodeName string, index int) (node.Item, error) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.resolve(target, l.inputs[nodeName], []node.PairedItem{{Item: index}})
+}
+
+// resolve returns the only item of the target reached from pairing, the
+// paired items of a node run with inputs
+func (l *Lineage) resolve(target string, inputs []itemOrigin, pairing []node.PairedItem) (node.Item, error) {
+	found := make(map[int]bool)
+	l.trace(target, inputs, pairing, make(map[itemOrigin]bool), found)
+	if len(found) > 1 {
+		return node.Item{}, fmt.Errorf("%w: node %s", ErrPairedItemAmbiguous, target)
+	}
+	for index := range found {
+		return l.runs[target].items[index], nil
+	}
+	return node.Item{}, fmt.Errorf("%w: node %s", ErrPairedItemNotFound, target)
+}
+
+// trace follows the pairing of an item of a node run with inputs up to the
+// target, adding the indexes of the target items reached to found. Items
+// already visited are not followed again, which bounds the work when many
+// items share their upstream items and ends loops.
+func (l *Lineage) trace(target string, inputs []itemOrigin, pairing []node.PairedItem, visited map[itemOrigin]bool, found map[int]bool) {
+	for _, paired := range pairing {
+		if paired.Item < 0 || paired.Item >= len(inputs) {
+			continue
+		}
+		origin := inputs[paired.Item]
+		source, ok := l.runs[origin.node]
+		if !ok || origin.item < 0 || origin.item >= len(source.items) {
+			continue
+		}
+		if origin.node == target {
+			found[origin.item] = true
+			continue
+		}
+		if visited[origin] {
+			continue
+		}
+		visited[origin] = true
+		l.trace(target, source.inputs, source.items[origin.item].PairedItem, visited, found)
+	}
+}
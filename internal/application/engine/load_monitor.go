@@ -0,0 +1,155 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/jaydeep/go-n8n/configs"
+	"github.com/jaydeep/go-n8n/pkg/database"
+	"github.com/jaydeep/go-n8n/pkg/logger"
+	"github.com/jaydeep/go-n8n/pkg/queue"
+)
+
+// Load is a sample of the signals load shedding is based on
+type Load struct {
+	QueueDepth  int64     `json:"queue_depth"`
+	DBLatencyMs int64     `json:"db_latency_ms"`
+	HeapMB      int       `json:"heap_mb"`
+	Overloaded  bool      `json:"overloaded"`
+	Reason      string    `json:"reason,omitempty"`
+	SampledAt   time.Time `json:"sampled_at"`
+}
+
+// LoadMonitor samples the queue depth, database latency and heap size in
+// the background, so requests can be shed without measuring anything
+// themselves
+type LoadMonitor struct {
+	jobs      queue.Queue
+	queueName string
+	db        *database.DB
+	cfg       configs.LoadSheddingConfig
+	log       *logger.Logger
+
+	mu   sync.RWMutex
+	load Load
+}
+
+// NewLoadMonitor creates a new load monitor
+func NewLoadMonitor(jobs queue.Queue, queueName string, db *database.DB, cfg configs.LoadSheddingConfig, log *logger.Logger) *LoadMonitor {
+	if cfg.CheckInterval <= 0 {
+		cfg.CheckInterval = 5 * time.Second
+	}
+	if cfg.RetryAfter <= 0 {
+		cfg.RetryAfter = 30 * time.Second
+	}
+	return &LoadMonitor{
+		jobs:      jobs,
+		queueName: queueName,
+		db:        db,
+		cfg:       cfg,
+		log:       log,
+	}
+}
+
+// Start samples the load until the context is cancelled
+func (m *LoadMonitor) Start(ctx context.Context) {
+	ticker := time.NewTicker(m.cfg.CheckInterval)
+	defer ticker.Stop()
+
+	m.sample(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			m.sample(ctx)
+		}
+	}
+}
+
+// Load returns the latest sample. A nil monitor reports no load.
+func (m *LoadMonitor) Load() Load {
+	if m == nil {
+		return Load{}
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.load
+}
+
+// Overloaded reports whether new executions should be refused, and how
+// long clients should wait before retrying. A nil monitor is never
+// overloaded.
+func (m *LoadMonitor) Overloaded() (time.Duration, bool) {
+	if m == nil {
+		return 0, false
+	}
+	return m.cfg.RetryAfter, m.Load().Overloaded
+}
+
+func (m *LoadMonitor) sample(ctx context.Context) {
+	load := Load{SampledAt: time.Now()}
+
+	// A failed probe counts as overloaded: the instance cannot take work it
+	// would fail to queue or record
+	depth, err := m.jobs.Len(ctx, m.queueName)
+	if err != nil {
+		load.Reason = "queue unavailable"
+	}
+	load.QueueDepth = depth
+
+	latency, err := m.pingDB(ctx)
+	if err != nil && load.Reason == "" {
+		load.Reason = "database unavailable"
+	}
+	load.DBLatencyMs = latency.Milliseconds()
+
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+	load.HeapMB = int(mem.HeapAlloc >> 20)
+
+	if load.Reason == "" {
+		load.Reason = m.exceeded(load, latency)
+	}
+	load.Overloaded = load.Reason != ""
+
+	m.mu.Lock()
+	was := m.load.Overloaded
+	m.load = load
+	m.mu.Unlock()
+
+	if load.Overloaded && !was {
+		m.log.Warnw("Shedding execution requests", "reason", load.Reason, "queue_depth", load.QueueDepth, "db_latency_ms", load.DBLatencyMs, "heap_mb", load.HeapMB)
+	} else if !load.Overloaded && was {
+		m.log.Infow("Accepting execution requests again", "queue_depth", load.QueueDepth, "db_latency_ms", load.DBLatencyMs, "heap_mb", load.HeapMB)
+	}
+}
+
+// exceeded names the first threshold the sample exceeds
+func (m *LoadMonitor) exceeded(load Load, latency time.Duration) string {
+	switch {
+	case m.cfg.MaxQueueDepth > 0 && load.QueueDepth > m.cfg.MaxQueueDepth:
+		return fmt.Sprintf("queue depth %d exceeds %d", load.QueueDepth, m.cfg.MaxQueueDepth)
+	case m.cfg.MaxDBLatency > 0 && latency > m.cfg.MaxDBLatency:
+		return fmt.Sprintf("database latency %s exceeds %s", latency.Round(time.Millisecond), m.cfg.MaxDBLatency)
+	case m.cfg.MaxHeapMB > 0 && load.HeapMB > m.cfg.MaxHeapMB:
+		return fmt.Sprintf("heap %dMB exceeds %dMB", load.HeapMB, m.cfg.MaxHeapMB)
+	}
+	return ""
+}
+
+func (m *LoadMonitor) pingDB(ctx context.Context) (time.Duration, error) {
+	sqlDB, err := m.db.DB.DB()
+	if err != nil {
+		return 0, err
+	}
+	ctx, cancel := context.WithTimeout(ctx, m.cfg.CheckInterval)
+	defer cancel()
+
+	started := time.Now()
+	err = sqlDB.PingContext(ctx)
+	return time.Since(started), err
+}
@@ -0,0 +1,177 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jaydeep/go-n8n/internal/domain/execution"
+	"github.com/jaydeep/go-n8n/internal/domain/node"
+	workflowdomain "github.com/jaydeep/go-n8n/internal/domain/workflow"
+)
+
+const defaultMaxLoopIterations = 1000
+
+// loop is a loop of a workflow: a looping node connections loop back to,
+// and the nodes it runs again on each iteration
+type loop struct {
+	// output is the output of the node leading into the loop
+	output int
+	// limit bounds the iterations of the loop
+	limit int
+	// body lists the IDs of the nodes after the loop output, except those
+	// also after the other outputs, in execution order
+	body []string
+}
+
+// loops returns the loops of a graph, by the ID of their node. Graphs are
+// compiled with loops back to looping nodes only; a node type no longer
+// looping since is refused.
+func (e *ExecutionEngine) loops(g *workflowdomain.Graph) (map[string]*loop, error) {
+	loops := make(map[string]*loop)
+	for _, id := range g.Order {
+		if len(g.Loops(id)) == 0 {
+			continue
+		}
+		wn, _ := g.Node(id)
+		var looper node.Looper
+		if constructor, err := e.nodes.Get(wn.Type); err == nil {
+			looper, _ = constructor().(node.Looper)
+		}
+		if looper == nil {
+			return nil, fmt.Errorf("%w: through node %s, which does not loop", workflowdomain.ErrWorkflowCycleDetected, wn.Name)
+		}
+
+		limit := e.cfg.MaxLoopIterations
+		if limit <= 0 {
+			limit = defaultMaxLoopIterations
+		}
+		if max := looper.MaxIterations(wn.Parameters); max > 0 && max < limit {
+			limit = max
+		}
+		loops[id] = &loop{output: looper.LoopOutput(), limit: limit, body: loopBody(g, id, looper.LoopOutput())}
+	}
+	return loops, nil
+}
+
+// loopBody returns the IDs of the nodes after the loop output of a node,
+// in execution order. Nodes also after its other outputs run once the loop
+// is done, and are left out.
+func loopBody(g *workflowdomain.Graph, id string, output int) []string {
+	inLoop := func(c workflowdomain.Connection) bool {
+		return c.Source.Type != workflowdomain.ConnectionTypeError && c.Source.Index == output
+	}
+	looped := reachable(g, id, inLoop)
+	after := reachable(g, id, func(c workflowdomain.Connection) bool { return !inLoop(c) })
+
+	var body []string
+	for _, n := range g.Order {
+		if looped[n] && !after[n] {
+			body = append(body, n)
+		}
+	}
+	return body
+}
+
+// reachable returns the IDs of the nodes reached from a node through the
+// connections leaving it that follow accepts
+func reachable(g *workflowdomain.Graph, id string, follow func(workflowdomain.Connection) bool) map[string]bool {
+	seen := make(map[string]bool)
+	var pending []string
+	for _, c := range g.Outgoing(id) {
+		if follow(c) && !seen[c.Target.NodeID] {
+			seen[c.Target.NodeID] = true
+			pending = append(pending, c.Target.NodeID)
+		}
+	}
+	for len(pending) > 0 {
+		current := pending[len(pending)-1]
+		pending = pending[:len(pending)-1]
+		for _, c := range g.Outgoing(current) {
+			if !seen[c.Target.NodeID] {
+				seen[c.Target.NodeID] = true
+				pending = append(pending, c.Target.NodeID)
+			}
+		}
+	}
+	return seen
+}
+
+// runLoop runs a loop: its node, then the nodes of its body for as long as
+// the node outputs items on its loop output, the node running again after
+// each iteration with the items the connections looping back bring. The
+// body nodes run afresh on each iteration. A loop going on past its limit
+// fails at its node, as does one with a node asking to wait, the state of
+// a loop not being kept while the execution waits. It returns the node
+// that failed, if any.
+func (e *ExecutionEngine) runLoop(ctx context.Context, run *executionRun, wn *workflowdomain.Node, l *loop) (*workflowdomain.Node, error) {
+	run.beginLoop(wn.ID)
+	defer run.endLoop(wn.ID)
+
+	for iteration := 0; ; iteration++ {
+		if err := e.runNode(ctx, run, wn); err != nil {
+			return wn, err
+		}
+		run.iterate(wn.ID)
+		if len(run.output(wn.ID, l.output)) == 0 {
+			return nil, nil
+		}
+		if iteration == l.limit {
+			return wn, fmt.Errorf("%w: %d", execution.ErrLoopLimitExceeded, l.limit)
+		}
+
+		run.clear(l.body)
+		failed, err := e.runSequence(ctx, run, l.body)
+		if errors.Is(err, errWaiting) {
+			failed, _ = run.graph.Node(run.waiting.NodeID)
+			return failed, execution.ErrWaitInLoop
+		}
+		if err != nil {
+			return failed, err
+		}
+	}
+}
+
+// beginLoop starts the loop of a node, with a fresh state; the node first
+// runs with the items of the connections entering it
+func (r *executionRun) beginLoop(id string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.states, id)
+	r.iterations[id] = 0
+}
+
+// iterate counts a run of the node of a loop; its next run is with the
+// items of the connections looping back to it
+func (r *executionRun) iterate(id string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.iterations[id]++
+}
+
+// endLoop ends the loop of a node
+func (r *executionRun) endLoop(id string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.iterations, id)
+}
+
+// output returns the items a node output on one of its outputs
+func (r *executionRun) output(id string, index int) []node.Item {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if outputs := r.outputs[id]; index < len(outputs) {
+		return outputs[index]
+	}
+	return nil
+}
+
+// clear forgets the outputs of nodes, for them to run again
+func (r *executionRun) clear(ids []string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, id := range ids {
+		delete(r.outputs, id)
+		delete(r.errorOutputs, id)
+	}
+}
@@ -0,0 +1,51 @@
+package engine
+
+import (
+	"time"
+
+	"github.com/jaydeep/go-n8n/internal/domain/node"
+)
+
+// NodeRun records a finished node run. The usage reported by the node in
+// its output metadata is completed with the CPU time, taken as the time
+// the node spent executing, and the items processed: its input items, or
+// its output items for nodes without input such as triggers.
+func NodeRun(nodeID, nodeType string, start, end time.Time, input *node.NodeInput, output *node.NodeOutput, err error) node.NodeExecutionData {
+	run := node.NodeExecutionData{
+		NodeID:          nodeID,
+		NodeType:        nodeType,
+		StartTime:       start,
+		EndTime:         end,
+		ExecutionTimeMs: end.Sub(start).Milliseconds(),
+		Status:          "success",
+		Metadata:        map[string]interface{}{},
+	}
+	if input != nil {
+		run.InputItems = len(input.Data)
+		if input.Context != nil {
+			run.RunIndex = input.Context.RunIndex
+		}
+	}
+	if output != nil {
+		run.OutputItems = len(output.Data)
+		for k, v := range output.Metadata {
+			run.Metadata[k] = v
+		}
+		if err == nil {
+			err = output.Error
+		}
+	}
+	if err != nil {
+		run.Status = "error"
+		run.Error = err.Error()
+	}
+
+	usage := node.UsageFromMetadata(run.Metadata)
+	usage.CPUTimeMs = run.ExecutionTimeMs
+	usage.ItemsProcessed = int64(run.InputItems)
+	if run.InputItems == 0 {
+		usage.ItemsProcessed = int64(run.OutputItems)
+	}
+	run.Metadata[node.MetadataUsage] = usage
+	return run
+}
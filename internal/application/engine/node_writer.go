@@ -0,0 +1,181 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/jaydeep/go-n8n/configs"
+	"github.com/jaydeep/go-n8n/internal/domain/execution"
+	"github.com/jaydeep/go-n8n/pkg/logger"
+)
+
+const (
+	defaultNodeWriteBuffer    = 10000
+	defaultNodeWriteBatchSize = 500
+	defaultNodeWriteInterval  = time.Second
+	// nodeWriteTimeout bounds a single batch insert
+	nodeWriteTimeout = 30 * time.Second
+)
+
+// ErrNodeWriterStopped is returned by Write and Flush after the writer has
+// shut down
+var ErrNodeWriterStopped = errors.New("node writer stopped")
+
+// NodeWriter stores node execution records off the execution path. Records
+// are buffered and inserted in batches when a batch fills up, when the
+// write interval passes, on Flush and on shutdown. A full buffer blocks
+// Write rather than losing records.
+type NodeWriter struct {
+	repo execution.NodeExecutionRepository
+	cfg  configs.EngineConfig
+	log  *logger.Logger
+
+	writes  chan nodeWrite
+	stopped chan struct{}
+	// mu lets drain wait for queueing writers; closed is set once drained
+	mu     sync.RWMutex
+	closed bool
+}
+
+// nodeWrite is a record to store or, with flushed set, a flush request
+type nodeWrite struct {
+	node    *execution.NodeExecution
+	flushed chan error
+}
+
+// NewNodeWriter creates a new node execution writer
+func NewNodeWriter(repo execution.NodeExecutionRepository, cfg configs.EngineConfig, log *logger.Logger) *NodeWriter {
+	if cfg.NodeWriteBuffer <= 0 {
+		cfg.NodeWriteBuffer = defaultNodeWriteBuffer
+	}
+	if cfg.NodeWriteBatchSize <= 0 {
+		cfg.NodeWriteBatchSize = defaultNodeWriteBatchSize
+	}
+	if cfg.NodeWriteInterval <= 0 {
+		cfg.NodeWriteInterval = defaultNodeWriteInterval
+	}
+	return &NodeWriter{
+		repo:    repo,
+		cfg:     cfg,
+		log:     log,
+		writes:  make(chan nodeWrite, cfg.NodeWriteBuffer),
+		stopped: make(chan struct{}),
+	}
+}
+
+// Write queues a record, waiting while the buffer is full
+func (w *NodeWriter) Write(ctx context.Context, node *execution.NodeExecution) error {
+	return w.queue(ctx, nodeWrite{node: node})
+}
+
+// Flush waits until every record queued before it is stored. The engine
+// flushes before marking an execution finished, so a finished execution
+// always has its node records.
+func (w *NodeWriter) Flush(ctx context.Context) error {
+	flushed := make(chan error, 1)
+	if err := w.queue(ctx, nodeWrite{flushed: flushed}); err != nil {
+		return err
+	}
+
+	select {
+	case err := <-flushed:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (w *NodeWriter) queue(ctx context.Context, write nodeWrite) error {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	if w.closed {
+		return ErrNodeWriterStopped
+	}
+
+	select {
+	case w.writes <- write:
+		return nil
+	case <-w.stopped:
+		return ErrNodeWriterStopped
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Buffered returns the number of records and flush requests waiting to be
+// written, and the buffer's capacity
+func (w *NodeWriter) Buffered() (int, int) {
+	return len(w.writes), cap(w.writes)
+}
+
+// Start writes batches until the context is cancelled, then stores the
+// records still buffered and returns
+func (w *NodeWriter) Start(ctx context.Context) {
+	ticker := time.NewTicker(w.cfg.NodeWriteInterval)
+	defer ticker.Stop()
+
+	batch := make([]*execution.NodeExecution, 0, w.cfg.NodeWriteBatchSize)
+	for {
+		select {
+		case write := <-w.writes:
+			batch = w.handle(batch, write)
+		case <-ticker.C:
+			batch = w.store(batch)
+		case <-ctx.Done():
+			w.drain(batch)
+			return
+		}
+	}
+}
+
+// handle adds a record to the batch or answers a flush request
+func (w *NodeWriter) handle(batch []*execution.NodeExecution, write nodeWrite) []*execution.NodeExecution {
+	if write.flushed != nil {
+		write.flushed <- w.insert(batch)
+		return batch[:0]
+	}
+	batch = append(batch, write.node)
+	if len(batch) >= w.cfg.NodeWriteBatchSize {
+		return w.store(batch)
+	}
+	return batch
+}
+
+// store inserts a batch, logging failures, and returns the emptied batch
+func (w *NodeWriter) store(batch []*execution.NodeExecution) []*execution.NodeExecution {
+	if err := w.insert(batch); err != nil {
+		w.log.Errorw("Failed to store node execution records", "count", len(batch), "error", err)
+	}
+	return batch[:0]
+}
+
+func (w *NodeWriter) insert(batch []*execution.NodeExecution) error {
+	if len(batch) == 0 {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), nodeWriteTimeout)
+	defer cancel()
+	return w.repo.CreateBatch(ctx, batch)
+}
+
+// drain stores the buffered records on shutdown. Writers blocked on a full
+// buffer are released by the stopped channel; records queued before that
+// are still stored.
+func (w *NodeWriter) drain(batch []*execution.NodeExecution) {
+	close(w.stopped)
+	w.mu.Lock()
+	w.closed = true
+	w.mu.Unlock()
+
+	for {
+		select {
+		case write := <-w.writes:
+			batch = w.handle(batch, write)
+		default:
+			w.store(batch)
+			return
+		}
+	}
+}
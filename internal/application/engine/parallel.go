@@ -0,0 +1,101 @@
+package engine
+
+import (
+	"context"
+	"errors"
+
+	workflowdomain "github.com/jaydeep/go-n8n/internal/domain/workflow"
+)
+
+const defaultMaxParallelNodes = 10
+
+// nodeDone reports a finished node of a parallel walk
+type nodeDone struct {
+	node *workflowdomain.Node
+	err  error
+}
+
+// walkParallel runs the nodes of independent branches concurrently, up to
+// MaxParallelExecutions at a time. A node starts once every node connected
+// before it has finished, so branches join where they meet, and its input
+// items keep the order of its connections. The first node to fail stops
+// the walk: no further node starts and the running ones are cancelled. A
+// node suspending the execution stops the walk too, once the running nodes
+// have finished. Nodes that ran before the execution was suspended count
+// as finished.
+func (e *ExecutionEngine) walkParallel(ctx context.Context, run *executionRun) (*workflowdomain.Node, error) {
+	limit := e.cfg.MaxParallelExecutions
+	if limit <= 0 {
+		limit = defaultMaxParallelNodes
+	}
+	nodeCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	pending := make(map[string]int, len(run.graph.Order))
+	var ready []string
+	for _, id := range run.graph.Order {
+		if run.done(id) {
+			continue
+		}
+		for _, c := range run.graph.Incoming(id) {
+			if !run.done(c.Source.NodeID) {
+				pending[id]++
+			}
+		}
+		if pending[id] == 0 {
+			ready = append(ready, id)
+		}
+	}
+	done := make(chan nodeDone)
+	running := 0
+
+	var failed *workflowdomain.Node
+	var failure error
+	waiting := false
+	for {
+		for failed == nil && !waiting && len(ready) > 0 && running < limit && ctx.Err() == nil {
+			wn, _ := run.graph.Node(ready[0])
+			ready = ready[1:]
+			running++
+			go func() {
+				done <- nodeDone{node: wn, err: e.runNode(nodeCtx, run, wn)}
+			}()
+		}
+		if running == 0 {
+			break
+		}
+
+		result := <-done
+		running--
+		if errors.Is(result.err, errWaiting) {
+			waiting = true
+			continue
+		}
+		if result.err != nil {
+			// Nodes cancelled after the first failure or as the execution
+			// ends fail too
+			if failed == nil && ctx.Err() == nil {
+				failed, failure = result.node, result.err
+				cancel()
+			}
+			continue
+		}
+		for _, c := range run.graph.Outgoing(result.node.ID) {
+			pending[c.Target.NodeID]--
+			if pending[c.Target.NodeID] == 0 {
+				ready = append(ready, c.Target.NodeID)
+			}
+		}
+	}
+
+	if failed != nil {
+		return failed, failure
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if waiting {
+		return nil, errWaiting
+	}
+	return nil, nil
+}
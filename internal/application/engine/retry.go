@@ -0,0 +1,106 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jaydeep/go-n8n/internal/domain/execution"
+	"github.com/jaydeep/go-n8n/internal/domain/node"
+	workflowdomain "github.com/jaydeep/go-n8n/internal/domain/workflow"
+	"github.com/jaydeep/go-n8n/pkg/nodesdk"
+)
+
+// defaultWaitBetweenTries is the wait before the first retry of a node
+// retrying on fail without wait_between_tries
+const defaultWaitBetweenTries = time.Second
+
+// nodeRetryPolicy returns how a node is retried when it fails. Nodes not
+// retrying on fail get no retries; nodes retrying on fail without
+// max_retries are retried as often as engine.max_retries says.
+func (e *ExecutionEngine) nodeRetryPolicy(wn *workflowdomain.Node) execution.RetryPolicy {
+	if !wn.RetryOnFail {
+		return execution.RetryPolicy{}
+	}
+	policy := execution.RetryPolicy{
+		MaxRetries:       wn.MaxRetries,
+		RetryInterval:    time.Duration(wn.WaitBetweenTries) * time.Millisecond,
+		BackoffFactor:    e.cfg.NodeRetryBackoff,
+		MaxRetryInterval: e.cfg.MaxNodeRetryWait,
+	}
+	if policy.MaxRetries <= 0 {
+		policy.MaxRetries = e.cfg.MaxRetries
+	}
+	if policy.MaxRetries > workflowdomain.MaxNodeRetries {
+		policy.MaxRetries = workflowdomain.MaxNodeRetries
+	}
+	if policy.RetryInterval <= 0 {
+		policy.RetryInterval = defaultWaitBetweenTries
+	}
+	return policy
+}
+
+// executeWithRetries runs a node and, while it fails and its retry policy
+// allows, waits and runs it again with the same input. Each retry is
+// counted in record.RetryCount and published as a node.retrying event
+// carrying the error of the try before it. A node failing because the
+// remote service asks to retry later, its circuit breaker open or its rate
+// limit exhausted, is retried after the delay the service gave, capped at
+// engine.max_node_retry_wait; those retries are counted apart, up to the
+// retries of the policy as well. A node suspending the execution or
+// stopped as the execution ends is not retried.
+func (e *ExecutionEngine) executeWithRetries(ctx context.Context, run *executionRun, wn *workflowdomain.Node, input *node.NodeInput, record *execution.NodeExecution) (*node.NodeOutput, error) {
+	policy := e.nodeRetryPolicy(wn)
+	retriesLater := 0
+	for {
+		output, err := e.execute(ctx, run, wn, input)
+		failure := err
+		if failure == nil && output != nil {
+			failure = output.Error
+		}
+		var wait *node.WaitError
+		if failure == nil || errors.As(err, &wait) || ctx.Err() != nil {
+			return output, err
+		}
+
+		var delay time.Duration
+		if nodesdk.IsRetryLater(failure) && retriesLater < policy.MaxRetries {
+			retriesLater++
+			delay = e.retryLaterDelay(failure, policy)
+		} else if record.RetryCount < policy.MaxRetries {
+			record.RetryCount++
+			delay = policy.Delay(record.RetryCount)
+		} else {
+			return output, err
+		}
+		record.ErrorMessage = failure.Error()
+		e.events.Publish(execution.NewNodeEvent(execution.EventNodeRetrying, run.exec, record))
+		record.ErrorMessage = ""
+		e.log.Infow("Retrying node", "execution_id", run.exec.ID, "node_id", wn.ID, "retry", record.RetryCount,
+			"retry_later", retriesLater, "max_retries", policy.MaxRetries, "delay", delay, "error", failure)
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return output, err
+		}
+		input.Context.RetryCount = record.RetryCount
+	}
+}
+
+// retryLaterDelay returns the wait before retrying a node the remote
+// service asked to retry later: the delay it gave, capped at
+// engine.max_node_retry_wait, or the first delay of the policy when it
+// gave none
+func (e *ExecutionEngine) retryLaterDelay(failure error, policy execution.RetryPolicy) time.Duration {
+	delay, ok := nodesdk.RetryAfter(failure)
+	if !ok || delay <= 0 {
+		return policy.Delay(1)
+	}
+	if e.cfg.MaxNodeRetryWait > 0 && delay > e.cfg.MaxNodeRetryWait {
+		delay = e.cfg.MaxNodeRetryWait
+	}
+	return delay
+}
@@ -0,0 +1,129 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/jaydeep/go-n8n/configs"
+	"github.com/jaydeep/go-n8n/internal/domain/execution"
+	"github.com/jaydeep/go-n8n/internal/domain/node"
+	workflowdomain "github.com/jaydeep/go-n8n/internal/domain/workflow"
+	"github.com/jaydeep/go-n8n/pkg/logger"
+	"github.com/jaydeep/go-n8n/pkg/nodesdk"
+)
+
+// flakyNode fails with its errors in turn, then succeeds
+type flakyNode struct {
+	nodesdk.BaseNode
+	errs  []error
+	tries *int
+}
+
+func (n *flakyNode) Execute(ctx context.Context, input *node.NodeInput) (*node.NodeOutput, error) {
+	*n.tries++
+	if *n.tries <= len(n.errs) {
+		return nil, n.errs[*n.tries-1]
+	}
+	return &node.NodeOutput{Data: input.Data}, nil
+}
+
+func (n *flakyNode) Validate(map[string]interface{}) error { return nil }
+
+func (n *flakyNode) GetSchema() *node.NodeSchema { return &node.NodeSchema{Type: n.Type} }
+
+type discardEvents struct{}
+
+func (discardEvents) Publish(*execution.Event) {}
+
+func TestExecuteWithRetriesRetryLater(t *testing.T) {
+	open := &nodesdk.CircuitOpenError{Key: "api.example.com", RetryAfter: 10 * time.Millisecond}
+	tests := []struct {
+		name string
+		errs []error
+		// maxRetries and waitBetweenTries (ms) are those of the node
+		maxRetries, waitBetweenTries int
+		wantErr                      error
+		wantTries, wantRetryCount    int
+	}{
+		{
+			name:       "breaker open retried after its delay, not counted as a retry",
+			errs:       []error{open, open},
+			maxRetries: 2, waitBetweenTries: 60000,
+			wantTries: 3,
+		},
+		{
+			name:       "long delay capped at max_node_retry_wait",
+			errs:       []error{&nodesdk.CircuitOpenError{Key: "api.example.com", RetryAfter: time.Hour}},
+			maxRetries: 1, waitBetweenTries: 60000,
+			wantTries: 2,
+		},
+		{
+			name:       "exhausted 429s retried after their delay",
+			errs:       []error{&nodesdk.RateLimitError{Host: "api.example.com", RetryAfter: 10 * time.Millisecond}},
+			maxRetries: 1, waitBetweenTries: 60000,
+			wantTries: 2,
+		},
+		{
+			name:       "other failures use up the retries",
+			errs:       []error{open, errors.New("boom"), errors.New("boom")},
+			maxRetries: 1, waitBetweenTries: 10,
+			wantErr:   errors.New("boom"),
+			wantTries: 3, wantRetryCount: 1,
+		},
+		{
+			name:       "nodes not retrying on fail not retried later",
+			errs:       []error{open},
+			maxRetries: 0,
+			wantErr:    nodesdk.ErrCircuitOpen,
+			wantTries:  1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tries := 0
+			nodes := node.NewNodeRegistry()
+			if err := nodes.Register("flaky", node.CategoryAction, func() node.NodeInterface {
+				return &flakyNode{BaseNode: nodesdk.BaseNode{Type: "flaky"}, errs: tt.errs, tries: &tries}
+			}); err != nil {
+				t.Fatal(err)
+			}
+			e := &ExecutionEngine{
+				nodes:  nodes,
+				events: discardEvents{},
+				cfg:    configs.EngineConfig{MaxNodeRetryWait: 20 * time.Millisecond},
+				log:    &logger.Logger{SugaredLogger: zap.NewNop().Sugar()},
+			}
+
+			wn := workflowdomain.Node{ID: "flaky", Name: "Flaky", Type: "flaky", RetryOnFail: tt.maxRetries > 0, MaxRetries: tt.maxRetries, WaitBetweenTries: tt.waitBetweenTries}
+			w := &workflowdomain.Workflow{Name: "retries", Nodes: []workflowdomain.Node{wn}}
+			g, err := workflowdomain.Compile(w, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			run := &executionRun{exec: &execution.Execution{}, workflow: w, graph: g}
+			input := &node.NodeInput{Data: []node.Item{{JSON: map[string]interface{}{}}}, Context: &node.ExecutionContext{}}
+			record := &execution.NodeExecution{}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			_, err = e.executeWithRetries(ctx, run, &wn, input, record)
+			switch {
+			case tt.wantErr == nil && err != nil:
+				t.Fatalf("executeWithRetries() error = %v", err)
+			case tt.wantErr != nil && (err == nil || (!errors.Is(err, tt.wantErr) && err.Error() != tt.wantErr.Error())):
+				t.Fatalf("executeWithRetries() error = %v, want %v", err, tt.wantErr)
+			}
+			if tries != tt.wantTries {
+				t.Errorf("tries = %d, want %d", tries, tt.wantTries)
+			}
+			if record.RetryCount != tt.wantRetryCount {
+				t.Errorf("RetryCount = %d, want %d", record.RetryCount, tt.wantRetryCount)
+			}
+		})
+	}
+}
@@ -0,0 +1,89 @@
+package engine
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jaydeep/go-n8n/configs"
+	"github.com/jaydeep/go-n8n/internal/domain/execution"
+)
+
+// DurationAnomaly is a successful execution that took much longer than the
+// executions of the workflow before it
+type DurationAnomaly struct {
+	ExecutionID uuid.UUID `json:"execution_id"`
+	StartedAt   time.Time `json:"started_at"`
+	DurationMs  int       `json:"duration_ms"`
+	MedianMs    float64   `json:"median_ms"`
+	Score       float64   `json:"score"`
+}
+
+// WorkflowStatistics describes the durations of the recent successful
+// executions of a workflow
+type WorkflowStatistics struct {
+	WorkflowID uuid.UUID `json:"workflow_id"`
+	// Baseline covers the latest executions, up to the anomaly window
+	Baseline execution.Baseline `json:"baseline"`
+	// Anomalies are the slow executions among the latest, newest first,
+	// each compared to the window of executions before it
+	Anomalies []DurationAnomaly `json:"anomalies"`
+}
+
+// Statistics computes execution duration statistics from the stored
+// executions, so they hold across restarts and instances
+type Statistics struct {
+	repo execution.Repository
+	cfg  configs.AnomalyConfig
+}
+
+// NewStatistics creates a new execution statistics service
+func NewStatistics(repo execution.Repository, cfg configs.AnomalyConfig) *Statistics {
+	return &Statistics{repo: repo, cfg: withAnomalyDefaults(cfg)}
+}
+
+// Workflow returns the duration baseline of a workflow and the anomalies
+// among its latest executions. Each of the latest window executions is
+// compared to the rolling window of executions that preceded it.
+func (s *Statistics) Workflow(ctx context.Context, workflowID uuid.UUID) (*WorkflowStatistics, error) {
+	window := s.cfg.Window
+	executions, err := s.repo.ListDurations(ctx, workflowID, 2*window)
+	if err != nil {
+		return nil, err
+	}
+	durations := make([]float64, len(executions))
+	for i, e := range executions {
+		durations[i] = float64(e.ExecutionTimeMs)
+	}
+
+	stats := &WorkflowStatistics{
+		WorkflowID: workflowID,
+		Baseline:   execution.NewBaseline(durations[:min(window, len(durations))]),
+		Anomalies:  []DurationAnomaly{},
+	}
+	if !s.cfg.Enabled {
+		return stats, nil
+	}
+
+	// Executions are newest first, so those preceding one follow it
+	for i := 0; i < window && i < len(executions); i++ {
+		previous := durations[i+1 : min(i+1+window, len(durations))]
+		if len(previous) < s.cfg.MinSamples {
+			break
+		}
+		baseline := execution.NewBaseline(previous)
+		if !baseline.IsSlow(durations[i], s.cfg.Threshold, s.cfg.MinDelta) {
+			continue
+		}
+		e := executions[i]
+		stats.Anomalies = append(stats.Anomalies, DurationAnomaly{
+			ExecutionID: e.ID,
+			StartedAt:   e.StartedAt,
+			DurationMs:  e.ExecutionTimeMs,
+			MedianMs:    baseline.MedianMs,
+			Score:       math.Round(baseline.Score(durations[i])*10) / 10,
+		})
+	}
+	return stats, nil
+}
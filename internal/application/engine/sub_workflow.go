@@ -0,0 +1,95 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jaydeep/go-n8n/internal/domain/execution"
+	"github.com/jaydeep/go-n8n/internal/domain/node"
+)
+
+// subWorkflowRunner runs the sub-workflows the nodes of an execution start.
+// Sub-workflows waited for run inline, within the deadline of the
+// execution, so they never wait for a free worker held by their parent.
+type subWorkflowRunner struct {
+	engine *ExecutionEngine
+	run    *executionRun
+}
+
+// RunWorkflow starts a sub-workflow of the execution. Workflows already
+// running in the chain of the execution are refused, as are sub-workflows
+// nested deeper than the engine allows.
+func (r *subWorkflowRunner) RunWorkflow(ctx context.Context, workflowID string, items []node.Item, wait bool) (string, []node.Item, error) {
+	id, err := uuid.Parse(workflowID)
+	if err != nil {
+		return "", nil, fmt.Errorf("invalid workflow ID %q", workflowID)
+	}
+	if err := r.engine.checkChain(ctx, r.run.exec, id); err != nil {
+		return "", nil, err
+	}
+
+	child, err := r.engine.subflows.StartSubWorkflow(ctx, r.run.exec, id, items, wait)
+	if err != nil {
+		return "", nil, err
+	}
+	if !wait {
+		return child.ID.String(), nil, nil
+	}
+
+	childRun, err := r.engine.runExecution(ctx, child)
+	if err != nil {
+		return child.ID.String(), nil, err
+	}
+	switch child.Status {
+	case execution.ExecutionStatusSuccess:
+		return child.ID.String(), unpaired(childRun.result()), nil
+	case execution.ExecutionStatusWaiting:
+		return child.ID.String(), nil, fmt.Errorf("sub-workflow execution %s is waiting to be resumed; run it without waiting for it", child.ID)
+	case execution.ExecutionStatusError:
+		return child.ID.String(), nil, fmt.Errorf("sub-workflow execution %s failed: %s", child.ID, child.ErrorMessage)
+	default:
+		return child.ID.String(), nil, fmt.Errorf("sub-workflow execution %s ended with status %s", child.ID, child.Status)
+	}
+}
+
+// checkChain refuses to start workflowID from exec when it runs in the
+// chain of sub-workflows exec is part of, or when the sub-workflow would
+// nest deeper than the engine allows. Ancestors deleted meanwhile end the
+// chain.
+func (e *ExecutionEngine) checkChain(ctx context.Context, exec *execution.Execution, workflowID uuid.UUID) error {
+	depth := 1
+	for current := exec; ; depth++ {
+		if current.WorkflowID == workflowID {
+			return execution.ErrSubWorkflowCycle
+		}
+		if current.Relation != execution.RelationSubWorkflow || current.ParentExecutionID == nil {
+			break
+		}
+		parent, err := e.executions.FindByID(ctx, *current.ParentExecutionID)
+		if errors.Is(err, execution.ErrExecutionNotFound) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		current = parent
+	}
+	if max := e.cfg.MaxSubWorkflowDepth; max > 0 && depth > max {
+		return execution.ErrSubWorkflowTooDeep
+	}
+	return nil
+}
+
+// unpaired returns the items a sub-workflow output without the pairing
+// they had with the items of its nodes, for the node that ran it to pair
+// them with its own input
+func unpaired(items []node.Item) []node.Item {
+	out := make([]node.Item, len(items))
+	for i, item := range items {
+		item.PairedItem = nil
+		out[i] = item
+	}
+	return out
+}
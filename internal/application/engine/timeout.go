@@ -0,0 +1,77 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jaydeep/go-n8n/internal/domain/execution"
+	workflowdomain "github.com/jaydeep/go-n8n/internal/domain/workflow"
+)
+
+// ErrNodeTimedOut is the error of a node run cut off at its deadline
+var ErrNodeTimedOut = errors.New("node timed out")
+
+// withExecutionDeadline bounds a run of an execution by
+// engine.max_execution_time and the workflow's max_execution_time,
+// whichever is shorter. Each run, from the start or the resume of the
+// execution, gets the full time, so time spent waiting does not count. A
+// deadline ctx already has, such as that of its queue job, still applies.
+func (e *ExecutionEngine) withExecutionDeadline(ctx context.Context, w *workflowdomain.Workflow) (context.Context, context.CancelFunc) {
+	limit := e.executionLimit(w)
+	if limit <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, limit)
+}
+
+// executionLimit returns how long a run of an execution of a workflow may
+// take, zero for no limit
+func (e *ExecutionEngine) executionLimit(w *workflowdomain.Workflow) time.Duration {
+	limit := e.cfg.MaxExecutionTime
+	if set := time.Duration(w.Settings.MaxExecutionTime) * time.Second; set > 0 && (limit <= 0 || set < limit) {
+		limit = set
+	}
+	return limit
+}
+
+// timeOut times out an execution that ran past its deadline as the
+// watchdog does: the outputs of the nodes that finished are kept as its
+// partial output and the enabled nodes that did not are recorded as
+// skipped
+func (e *ExecutionEngine) timeOut(ctx context.Context, run *executionRun) {
+	run.mu.RLock()
+	var runs []*execution.NodeExecution
+	var planned []execution.PlannedNode
+	for _, id := range run.graph.Order {
+		wn, _ := run.graph.Node(id)
+		if wn.Disabled {
+			continue
+		}
+		planned = append(planned, execution.PlannedNode{ID: wn.ID, Type: wn.Type, Name: wn.Name})
+		if outputs := run.outputs[id]; len(outputs) > 0 {
+			runs = append(runs, &execution.NodeExecution{
+				NodeID:     id,
+				Status:     execution.ExecutionStatusSuccess,
+				OutputData: map[string]interface{}{"data": outputs[0]},
+			})
+		}
+	}
+	run.mu.RUnlock()
+
+	skipped := run.exec.TimeOutPartially(e.executionLimit(run.workflow), runs, planned)
+	for _, record := range skipped {
+		if err := e.writer.Write(context.WithoutCancel(ctx), record); err != nil {
+			e.log.Errorw("Failed to queue node execution record", "execution_id", run.exec.ID, "node_id", record.NodeID, "error", err)
+		}
+	}
+}
+
+// nodeTimeout returns how long a run of a node may take: its timeout, or
+// else node.max_execution_time; zero for no limit
+func (e *ExecutionEngine) nodeTimeout(wn *workflowdomain.Node) time.Duration {
+	if wn.Timeout > 0 {
+		return time.Duration(wn.Timeout) * time.Second
+	}
+	return e.nodeMaxTime
+}
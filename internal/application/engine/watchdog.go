@@ -0,0 +1,231 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jaydeep/go-n8n/configs"
+	"github.com/jaydeep/go-n8n/internal/domain/execution"
+	"github.com/jaydeep/go-n8n/internal/domain/workflow"
+	"github.com/jaydeep/go-n8n/pkg/logger"
+	"github.com/jaydeep/go-n8n/pkg/queue"
+)
+
+// StuckExecution describes a running execution that exceeded
+// MaxExecutionTime since it started or last resumed
+type StuckExecution struct {
+	ExecutionID  uuid.UUID `json:"execution_id"`
+	WorkflowID   uuid.UUID `json:"workflow_id"`
+	Mode         string    `json:"mode"`
+	StartedAt    time.Time `json:"started_at"`
+	RunningForMs int64     `json:"running_for_ms"`
+}
+
+// Watchdog detects executions left running past MaxExecutionTime, because
+// they hang or the worker executing them crashed, and times them out
+type Watchdog struct {
+	repo      execution.Repository
+	nodeRuns  execution.NodeExecutionRepository
+	workflows workflow.Repository
+	versions  workflow.VersionRepository
+	events    execution.EventPublisher
+	queue     queue.Queue
+	queueName string
+	cfg       configs.EngineConfig
+	log       *logger.Logger
+}
+
+// NewWatchdog creates a new stuck-execution watchdog. The queue may be nil,
+// in which case crashed executions are never re-enqueued. Retries go to the
+// queue and region of the workflow version they run, or of the draft.
+func NewWatchdog(repo execution.Repository, nodeRuns execution.NodeExecutionRepository, workflows workflow.Repository, versions workflow.VersionRepository, events execution.EventPublisher, q queue.Queue, queueName string, cfg configs.EngineConfig, log *logger.Logger) *Watchdog {
+	return &Watchdog{
+		repo:      repo,
+		nodeRuns:  nodeRuns,
+		workflows: workflows,
+		versions:  versions,
+		events:    events,
+		queue:     q,
+		queueName: queueName,
+		cfg:       cfg,
+		log:       log,
+	}
+}
+
+// Start runs the watchdog until the context is cancelled
+func (w *Watchdog) Start(ctx context.Context) {
+	interval := w.cfg.StuckCheckInterval
+	if interval <= 0 {
+		interval = time.Minute
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := w.Sweep(ctx); err != nil {
+				w.log.Errorw("Stuck execution sweep failed", "error", err)
+			}
+		}
+	}
+}
+
+// Stuck lists running executions that exceeded MaxExecutionTime
+func (w *Watchdog) Stuck(ctx context.Context) ([]StuckExecution, error) {
+	executions, err := w.findStuck(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	stuck := make([]StuckExecution, 0, len(executions))
+	for _, e := range executions {
+		stuck = append(stuck, StuckExecution{
+			ExecutionID:  e.ID,
+			WorkflowID:   e.WorkflowID,
+			Mode:         string(e.Mode),
+			StartedAt:    e.StartedAt,
+			RunningForMs: now.Sub(e.RunningSince()).Milliseconds(),
+		})
+	}
+	return stuck, nil
+}
+
+// Running returns the number of executions currently marked running
+func (w *Watchdog) Running(ctx context.Context) (int64, error) {
+	return w.repo.CountByStatus(ctx, execution.ExecutionStatusRunning)
+}
+
+// MaxExecutionTime returns the threshold after which executions are stuck
+func (w *Watchdog) MaxExecutionTime() time.Duration {
+	return w.cfg.MaxExecutionTime
+}
+
+// Sweep times out stuck executions, keeping the outputs of the nodes that
+// completed and marking the others skipped, and when enabled enqueues a
+// retry for each. It returns the number of executions transitioned.
+func (w *Watchdog) Sweep(ctx context.Context) (int, error) {
+	executions, err := w.findStuck(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	timedOut := 0
+	for _, e := range executions {
+		// Executions of deleted drafts time out without their nodes
+		def, err := w.definition(ctx, e)
+		if err != nil && !errors.Is(err, workflow.ErrVersionNotFound) {
+			w.log.Errorw("Failed to load workflow of stuck execution", "execution_id", e.ID, "error", err)
+			continue
+		}
+		if err := w.timeOut(ctx, e, def); err != nil {
+			w.log.Errorw("Failed to time out execution", "execution_id", e.ID, "error", err)
+			continue
+		}
+		timedOut++
+		w.events.Publish(execution.NewEvent(execution.EventExecutionFinished, e))
+
+		w.log.Warnw("Timed out stuck execution", "execution_id", e.ID, "workflow_id", e.WorkflowID, "correlation_id", e.CorrelationID)
+
+		if w.cfg.RequeueStuck && w.queue != nil && def != nil {
+			if err := w.requeue(ctx, e, def); err != nil {
+				w.log.Errorw("Failed to requeue timed out execution", "execution_id", e.ID, "error", err)
+			}
+		}
+	}
+
+	return timedOut, nil
+}
+
+// definition is what the watchdog needs of the workflow an execution runs
+type definition struct {
+	nodes    []workflow.Node
+	settings workflow.WorkflowSettings
+	team     string
+}
+
+// definition returns the nodes and settings of the workflow version an
+// execution runs. Versions are only missing for executions of drafts,
+// which are taken from the draft as it is now. The draft also names the
+// team, and is missing once the workflow is deleted.
+func (w *Watchdog) definition(ctx context.Context, e *execution.Execution) (*definition, error) {
+	def := &definition{}
+	draft, err := w.workflows.FindByID(ctx, e.WorkflowID)
+	switch {
+	case err == nil:
+		def.nodes, def.settings, def.team = draft.Nodes, draft.Settings, draft.QueueTeam()
+	case !errors.Is(err, workflow.ErrWorkflowNotFound):
+		return nil, err
+	}
+	version, err := w.versions.FindByVersion(ctx, e.WorkflowID, e.WorkflowVersion)
+	switch {
+	case err == nil:
+		def.nodes, def.settings = version.Nodes, version.Settings
+	case errors.Is(err, workflow.ErrVersionNotFound) && draft != nil:
+	default:
+		return nil, err
+	}
+	return def, nil
+}
+
+// timeOut marks an execution timed out, recording the enabled nodes of def,
+// which may be nil, without a finished run as skipped
+func (w *Watchdog) timeOut(ctx context.Context, e *execution.Execution, def *definition) error {
+	runs, err := w.nodeRuns.ListByExecution(ctx, e.ID)
+	if err != nil {
+		return err
+	}
+	var planned []execution.PlannedNode
+	if def != nil {
+		for _, n := range def.nodes {
+			if !n.Disabled {
+				planned = append(planned, execution.PlannedNode{ID: n.ID, Type: n.Type, Name: n.Name})
+			}
+		}
+	}
+
+	skipped := e.TimeOutPartially(w.cfg.MaxExecutionTime, runs, planned)
+	if err := w.repo.Update(ctx, e); err != nil {
+		return err
+	}
+	if len(skipped) > 0 {
+		return w.nodeRuns.CreateBatch(ctx, skipped)
+	}
+	return nil
+}
+
+// requeue creates a retry execution and places it on the worker queue
+func (w *Watchdog) requeue(ctx context.Context, e *execution.Execution, def *definition) error {
+	retry := e.CreateRetry()
+	if err := w.repo.Create(ctx, retry); err != nil {
+		return err
+	}
+
+	err := w.queue.Enqueue(ctx, &queue.Job{
+		Queue:         def.settings.QueueOr(w.queueName),
+		Type:          string(retry.Mode),
+		Region:        def.settings.Region,
+		Team:          def.team,
+		ExecutionID:   retry.ID.String(),
+		WorkflowID:    retry.WorkflowID.String(),
+		CorrelationID: retry.CorrelationID,
+	})
+	if err != nil {
+		return err
+	}
+	w.events.Publish(execution.NewEvent(execution.EventExecutionQueued, retry))
+	return nil
+}
+
+func (w *Watchdog) findStuck(ctx context.Context) ([]*execution.Execution, error) {
+	if w.cfg.MaxExecutionTime <= 0 {
+		return nil, nil
+	}
+	return w.repo.FindRunningStartedBefore(ctx, time.Now().Add(-w.cfg.MaxExecutionTime))
+}
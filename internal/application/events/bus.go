@@ -0,0 +1,197 @@
+// Package events is the in-process event bus. Services publish typed events
+// as things happen, such as executions finishing or workflows being
+// activated, and features like analytics, audit and WebSocket push
+// subscribe to them instead of being called by each service.
+package events
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/jaydeep/go-n8n/configs"
+	"github.com/jaydeep/go-n8n/internal/domain/execution"
+	"github.com/jaydeep/go-n8n/pkg/logger"
+)
+
+const (
+	defaultBufferSize     = 1000
+	defaultHandlerTimeout = 10 * time.Second
+	// dropReportInterval bounds how often dropped events are logged
+	dropReportInterval = 10 * time.Second
+)
+
+// Handler consumes the events of one subscriber, one at a time and in
+// publish order. ctx ends after the handler timeout.
+type Handler func(ctx context.Context, event Event)
+
+// Publisher publishes events on the bus
+type Publisher interface {
+	Publish(event Event)
+}
+
+// Bus delivers events to the subscribers of their type. Each subscriber
+// has its own buffer and goroutine, so a slow subscriber delays neither
+// the publisher nor the other subscribers. Events arriving while a buffer
+// is full are dropped and counted rather than blocking the publisher.
+type Bus struct {
+	cfg configs.EventsConfig
+	log *logger.Logger
+
+	mu          sync.RWMutex
+	subscribers []*subscriber
+}
+
+// subscriber is a registered handler and its buffer
+type subscriber struct {
+	name    string
+	handler Handler
+	// types the subscriber receives; nil receives every type
+	types   map[Type]bool
+	events  chan Event
+	dropped atomic.Int64
+}
+
+// NewBus creates a new event bus
+func NewBus(cfg configs.EventsConfig, log *logger.Logger) *Bus {
+	if cfg.BufferSize <= 0 {
+		cfg.BufferSize = defaultBufferSize
+	}
+	if cfg.HandlerTimeout <= 0 {
+		cfg.HandlerTimeout = defaultHandlerTimeout
+	}
+	return &Bus{cfg: cfg, log: log}
+}
+
+// Subscribe registers handler for the events of types, or for every event
+// when no type is given. Subscribers must be registered before Start.
+func (b *Bus) Subscribe(name string, handler Handler, types ...Type) {
+	s := &subscriber{
+		name:    name,
+		handler: handler,
+		events:  make(chan Event, b.cfg.BufferSize),
+	}
+	if len(types) > 0 {
+		s.types = make(map[Type]bool, len(types))
+		for _, t := range types {
+			s.types[t] = true
+		}
+	}
+
+	b.mu.Lock()
+	b.subscribers = append(b.subscribers, s)
+	b.mu.Unlock()
+}
+
+// Publish queues an event for its subscribers without blocking
+func (b *Bus) Publish(event Event) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	eventType := event.EventType()
+	for _, s := range b.subscribers {
+		if s.types != nil && !s.types[eventType] {
+			continue
+		}
+		select {
+		case s.events <- event:
+		default:
+			s.dropped.Add(1)
+		}
+	}
+}
+
+// Executions returns a publisher of execution events for the services
+// recording execution transitions
+func (b *Bus) Executions() execution.EventPublisher {
+	return executionPublisher{bus: b}
+}
+
+// Outputs returns a publisher of the node outputs of interactive
+// executions for the engine
+func (b *Bus) Outputs() execution.OutputPublisher {
+	return outputPublisher{bus: b}
+}
+
+// Start delivers events until the context is cancelled, then delivers the
+// buffered events before returning
+func (b *Bus) Start(ctx context.Context) {
+	b.mu.RLock()
+	subscribers := b.subscribers
+	b.mu.RUnlock()
+
+	var wg sync.WaitGroup
+	for _, s := range subscribers {
+		wg.Add(1)
+		go func(s *subscriber) {
+			defer wg.Done()
+			b.run(ctx, s)
+		}(s)
+	}
+	wg.Wait()
+}
+
+func (b *Bus) run(ctx context.Context, s *subscriber) {
+	ticker := time.NewTicker(dropReportInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			b.drain(s)
+			return
+		case event := <-s.events:
+			b.deliver(s, event)
+		case <-ticker.C:
+			if dropped := s.dropped.Swap(0); dropped > 0 {
+				b.log.Warnw("Event subscriber buffer full, events dropped", "subscriber", s.name, "dropped", dropped)
+			}
+		}
+	}
+}
+
+// drain delivers the events still buffered on shutdown
+func (b *Bus) drain(s *subscriber) {
+	for {
+		select {
+		case event := <-s.events:
+			b.deliver(s, event)
+		default:
+			return
+		}
+	}
+}
+
+// deliver hands one event to a subscriber. A panicking handler loses the
+// event but keeps its subscription.
+func (b *Bus) deliver(s *subscriber, event Event) {
+	ctx, cancel := context.WithTimeout(context.Background(), b.cfg.HandlerTimeout)
+	defer cancel()
+	defer func() {
+		if r := recover(); r != nil {
+			b.log.Errorw("Event subscriber panicked", "subscriber", s.name, "type", event.EventType(), "error", fmt.Sprint(r))
+		}
+	}()
+
+	s.handler(ctx, event)
+}
+
+// executionPublisher publishes execution events on the bus
+type executionPublisher struct {
+	bus *Bus
+}
+
+func (p executionPublisher) Publish(event *execution.Event) {
+	p.bus.Publish(Execution{Event: event})
+}
+
+// outputPublisher publishes node outputs on the bus
+type outputPublisher struct {
+	bus *Bus
+}
+
+func (p outputPublisher) PublishOutput(e *execution.Execution, node *execution.NodeExecution) {
+	p.bus.Publish(NewOutputEvent(e, node))
+}
@@ -0,0 +1,279 @@
+package events
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jaydeep/go-n8n/internal/application/audit"
+	"github.com/jaydeep/go-n8n/internal/domain/credential"
+	"github.com/jaydeep/go-n8n/internal/domain/execution"
+	"github.com/jaydeep/go-n8n/internal/domain/node"
+	"github.com/jaydeep/go-n8n/internal/domain/sla"
+	"github.com/jaydeep/go-n8n/internal/domain/user"
+	"github.com/jaydeep/go-n8n/internal/domain/workflow"
+)
+
+// Type identifies the kind of an event; subscribers register for types
+type Type string
+
+const (
+	ExecutionQueued     Type = Type(execution.EventExecutionQueued)
+	ExecutionStarted    Type = Type(execution.EventExecutionStarted)
+	ExecutionWaiting    Type = Type(execution.EventExecutionWaiting)
+	ExecutionResumed    Type = Type(execution.EventExecutionResumed)
+	ExecutionFinished   Type = Type(execution.EventExecutionFinished)
+	NodeStarted         Type = Type(execution.EventNodeStarted)
+	NodeRetrying        Type = Type(execution.EventNodeRetrying)
+	NodeFinished        Type = Type(execution.EventNodeFinished)
+	NodeOutput          Type = "node.output"
+	WorkflowActivated   Type = "workflow.activated"
+	WorkflowDeactivated Type = "workflow.deactivated"
+	SLABreached         Type = "workflow.sla_breached"
+	SLARecovered        Type = "workflow.sla_recovered"
+	CredentialCreated   Type = "credential.created"
+	CredentialUnhealthy Type = "credential.unhealthy"
+	CredentialRecovered Type = "credential.recovered"
+	UserCreated         Type = "user.created"
+	UserActivated       Type = "user.activated"
+	UserDeactivated     Type = "user.deactivated"
+)
+
+// ExecutionTypes are the types of execution and node transitions
+var ExecutionTypes = []Type{ExecutionQueued, ExecutionStarted, ExecutionWaiting, ExecutionResumed, ExecutionFinished, NodeStarted, NodeRetrying, NodeFinished}
+
+// Event is published on the bus. Subscribers switch on the concrete type.
+type Event interface {
+	EventType() Type
+}
+
+// Execution is a transition of an execution or one of its nodes
+type Execution struct {
+	*execution.Event
+}
+
+// EventType returns the type of the transition
+func (e Execution) EventType() Type {
+	return Type(e.Type)
+}
+
+// MaxStreamedItems bounds the items of a node output event; the editor
+// loads the rest from the execution
+const MaxStreamedItems = 100
+
+// Output is the output of a node of an interactive execution, streamed to
+// the editor as the node finishes
+type Output struct {
+	Timestamp   time.Time                 `json:"timestamp"`
+	ExecutionID uuid.UUID                 `json:"execution_id"`
+	WorkflowID  uuid.UUID                 `json:"workflow_id"`
+	Mode        execution.ExecutionMode   `json:"mode"`
+	NodeID      string                    `json:"node_id"`
+	NodeName    string                    `json:"node_name"`
+	NodeType    string                    `json:"node_type"`
+	Status      execution.ExecutionStatus `json:"status"`
+	DurationMs  int                       `json:"duration_ms"`
+	Error       string                    `json:"error,omitempty"`
+	Items       []node.Item               `json:"items"`
+	// ItemCount is the number of items output, of which Items holds the
+	// first MaxStreamedItems
+	ItemCount int                    `json:"item_count"`
+	Truncated bool                   `json:"truncated,omitempty"`
+	Metadata  map[string]interface{} `json:"metadata,omitempty"`
+}
+
+// NewOutputEvent records the output of a finished node of e
+func NewOutputEvent(e *execution.Execution, record *execution.NodeExecution) Output {
+	event := Output{
+		Timestamp:   time.Now().UTC(),
+		ExecutionID: e.ID,
+		WorkflowID:  e.WorkflowID,
+		Mode:        e.Mode,
+		NodeID:      record.NodeID,
+		NodeName:    record.NodeName,
+		NodeType:    record.NodeType,
+		Status:      record.Status,
+		DurationMs:  record.ExecutionTimeMs,
+		Error:       record.ErrorMessage,
+		Items:       []node.Item{},
+	}
+	items, _ := record.OutputData["data"].([]node.Item)
+	event.ItemCount = len(items)
+	if len(items) > MaxStreamedItems {
+		items = items[:MaxStreamedItems]
+		event.Truncated = true
+	}
+	event.Items = append(event.Items, items...)
+	if metadata, ok := record.OutputData["metadata"].(map[string]interface{}); ok && len(metadata) > 0 {
+		event.Metadata = metadata
+	}
+	return event
+}
+
+// EventType returns NodeOutput
+func (e Output) EventType() Type {
+	return NodeOutput
+}
+
+// Workflow is the activation or deactivation of a workflow
+type Workflow struct {
+	Type       Type      `json:"type"`
+	Timestamp  time.Time `json:"timestamp"`
+	WorkflowID uuid.UUID `json:"workflow_id"`
+	// Version is the published version the triggers run
+	Version int `json:"version,omitempty"`
+	// OwnerID is the user owning the workflow
+	OwnerID uuid.UUID `json:"owner_id"`
+	// Actor made the change
+	Actor audit.Actor `json:"-"`
+}
+
+// NewWorkflowEvent records a change of w made by actor
+func NewWorkflowEvent(eventType Type, w *workflow.Workflow, actor audit.Actor) Workflow {
+	event := Workflow{
+		Type:       eventType,
+		Timestamp:  time.Now().UTC(),
+		WorkflowID: w.ID,
+		OwnerID:    w.UserID,
+		Actor:      actor,
+	}
+	if w.PublishedVersion != nil {
+		event.Version = *w.PublishedVersion
+	}
+	return event
+}
+
+// EventType returns WorkflowActivated or WorkflowDeactivated
+func (e Workflow) EventType() Type {
+	return e.Type
+}
+
+// Credential is a change to a stored credential. It never carries the
+// credential data.
+type Credential struct {
+	Type           Type      `json:"type"`
+	Timestamp      time.Time `json:"timestamp"`
+	CredentialID   uuid.UUID `json:"credential_id"`
+	CredentialType string    `json:"credential_type"`
+	OwnerID        uuid.UUID `json:"owner_id"`
+	// Actor made the change
+	Actor audit.Actor `json:"-"`
+}
+
+// NewCredentialEvent records a change of c made by actor
+func NewCredentialEvent(eventType Type, c *credential.Credential, actor audit.Actor) Credential {
+	return Credential{
+		Type:           eventType,
+		Timestamp:      time.Now().UTC(),
+		CredentialID:   c.ID,
+		CredentialType: c.Type,
+		OwnerID:        c.UserID,
+		Actor:          actor,
+	}
+}
+
+// EventType returns the type of the change
+func (e Credential) EventType() Type {
+	return e.Type
+}
+
+// CredentialHealth is a credential failing its test or nearing the
+// expiry of a token or certificate, or recovering. It never carries the
+// credential data.
+type CredentialHealth struct {
+	Type           Type      `json:"type"`
+	Timestamp      time.Time `json:"timestamp"`
+	CredentialID   uuid.UUID `json:"credential_id"`
+	CredentialName string    `json:"credential_name"`
+	CredentialType string    `json:"credential_type"`
+	OwnerID        uuid.UUID `json:"owner_id"`
+	// Health is the outcome of the check
+	Health credential.Health `json:"health"`
+	// Workflows are the active workflows with nodes using the credential
+	Workflows []WorkflowRef `json:"workflows"`
+}
+
+// WorkflowRef names a workflow
+type WorkflowRef struct {
+	ID   uuid.UUID `json:"id"`
+	Name string    `json:"name"`
+}
+
+// NewCredentialHealthEvent records the health of c, used by workflows
+func NewCredentialHealthEvent(eventType Type, c *credential.Credential, workflows []WorkflowRef) CredentialHealth {
+	event := CredentialHealth{
+		Type:           eventType,
+		Timestamp:      time.Now().UTC(),
+		CredentialID:   c.ID,
+		CredentialName: c.Name,
+		CredentialType: c.Type,
+		OwnerID:        c.UserID,
+		Workflows:      workflows,
+	}
+	if c.Health != nil {
+		event.Health = *c.Health
+	}
+	return event
+}
+
+// EventType returns CredentialUnhealthy or CredentialRecovered
+func (e CredentialHealth) EventType() Type {
+	return e.Type
+}
+
+// User is a change to a user account
+type User struct {
+	Type      Type      `json:"type"`
+	Timestamp time.Time `json:"timestamp"`
+	UserID    uuid.UUID `json:"user_id"`
+	Email     string    `json:"email"`
+	Role      user.Role `json:"role"`
+	// Reason explains changes made by the instance itself, such as
+	// deactivating a dormant account
+	Reason string `json:"reason,omitempty"`
+	// Actor made the change; it is empty for changes made by the instance
+	Actor audit.Actor `json:"-"`
+}
+
+// NewUserEvent records a change of u made by actor
+func NewUserEvent(eventType Type, u *user.User, actor audit.Actor) User {
+	return User{
+		Type:      eventType,
+		Timestamp: time.Now().UTC(),
+		UserID:    u.ID,
+		Email:     u.Email,
+		Role:      u.Role,
+		Actor:     actor,
+	}
+}
+
+// EventType returns the type of the change
+func (e User) EventType() Type {
+	return e.Type
+}
+
+// SLA is a workflow starting or ceasing to meet an objective of its SLA
+// policy
+type SLA struct {
+	Type       Type      `json:"type"`
+	Timestamp  time.Time `json:"timestamp"`
+	WorkflowID uuid.UUID `json:"workflow_id"`
+	// OwnerID is the user owning the workflow
+	OwnerID uuid.UUID  `json:"owner_id"`
+	Breach  sla.Breach `json:"breach"`
+}
+
+// NewSLAEvent records a breach of an objective of w starting or ending
+func NewSLAEvent(eventType Type, w *workflow.Workflow, breach sla.Breach) SLA {
+	return SLA{
+		Type:       eventType,
+		Timestamp:  time.Now().UTC(),
+		WorkflowID: w.ID,
+		OwnerID:    w.UserID,
+		Breach:     breach,
+	}
+}
+
+// EventType returns SLABreached or SLARecovered
+func (e SLA) EventType() Type {
+	return e.Type
+}
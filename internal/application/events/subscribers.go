@@ -0,0 +1,59 @@
+package events
+
+import (
+	"context"
+
+	"github.com/jaydeep/go-n8n/internal/application/analytics"
+	"github.com/jaydeep/go-n8n/internal/application/audit"
+	auditdomain "github.com/jaydeep/go-n8n/internal/domain/audit"
+)
+
+// AuditTypes are the types recorded by the audit subscriber
+var AuditTypes = []Type{WorkflowActivated, WorkflowDeactivated, CredentialCreated}
+
+// auditActions maps event types to the audit actions recorded for them
+var auditActions = map[Type]string{
+	WorkflowActivated:   auditdomain.ActionWorkflowActivated,
+	WorkflowDeactivated: auditdomain.ActionWorkflowDeactivated,
+	CredentialCreated:   auditdomain.ActionCredentialCreated,
+}
+
+// Audit returns a handler recording workflow and credential changes in the
+// audit log. Subscribe it to AuditTypes.
+func Audit(svc *audit.Service) Handler {
+	return func(ctx context.Context, event Event) {
+		action, ok := auditActions[event.EventType()]
+		if !ok {
+			return
+		}
+
+		switch e := event.(type) {
+		case Workflow:
+			svc.Record(ctx, e.Actor, &auditdomain.AuditLog{
+				Action:       action,
+				ResourceType: auditdomain.ResourceWorkflow,
+				ResourceID:   e.WorkflowID.String(),
+				NewValue:     map[string]interface{}{"version": e.Version},
+				CreatedAt:    e.Timestamp,
+			})
+		case Credential:
+			svc.Record(ctx, e.Actor, &auditdomain.AuditLog{
+				Action:       action,
+				ResourceType: auditdomain.ResourceCredential,
+				ResourceID:   e.CredentialID.String(),
+				NewValue:     map[string]interface{}{"type": e.CredentialType},
+				CreatedAt:    e.Timestamp,
+			})
+		}
+	}
+}
+
+// Analytics returns a handler queueing execution events for the analytics
+// sinks of pipeline. Subscribe it to ExecutionTypes.
+func Analytics(pipeline *analytics.Pipeline) Handler {
+	return func(_ context.Context, event Event) {
+		if e, ok := event.(Execution); ok {
+			pipeline.Publish(e.Event)
+		}
+	}
+}
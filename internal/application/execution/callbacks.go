@@ -0,0 +1,204 @@
+package execution
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jaydeep/go-n8n/configs"
+	domain "github.com/jaydeep/go-n8n/internal/domain/execution"
+	"github.com/jaydeep/go-n8n/pkg/correlation"
+	"github.com/jaydeep/go-n8n/pkg/logger"
+)
+
+// Callback request headers
+const (
+	HeaderSignature = "X-N8N-Signature"
+	HeaderTimestamp = "X-N8N-Timestamp"
+	HeaderEvent     = "X-N8N-Event"
+	HeaderDelivery  = "X-N8N-Delivery"
+
+	callbackEvent = "execution.finished"
+	// callbackBatchSize limits how many callbacks are delivered per poll
+	callbackBatchSize = 100
+)
+
+// CallbackPayload is the body POSTed to a callback URL
+type CallbackPayload struct {
+	Event           string                 `json:"event"`
+	ExecutionID     string                 `json:"execution_id"`
+	WorkflowID      string                 `json:"workflow_id"`
+	Status          domain.ExecutionStatus `json:"status"`
+	StartedAt       time.Time              `json:"started_at"`
+	FinishedAt      *time.Time             `json:"finished_at,omitempty"`
+	ExecutionTimeMs int                    `json:"execution_time_ms"`
+	CorrelationID   string                 `json:"correlation_id,omitempty"`
+	Output          map[string]interface{} `json:"output,omitempty"`
+	Error           *CallbackError         `json:"error,omitempty"`
+}
+
+// CallbackError describes why an execution failed
+type CallbackError struct {
+	Message string `json:"message"`
+	Node    string `json:"node,omitempty"`
+}
+
+// Sign computes the signature of a callback body. Receivers recompute it
+// over the X-N8N-Timestamp header, a dot and the raw body and compare it
+// with the X-N8N-Signature header.
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// CallbackSecret derives the secret the callbacks of a workflow are signed
+// with from the instance secret, so the receivers of one workflow's
+// callbacks cannot sign those of another
+func CallbackSecret(secret string, workflowID uuid.UUID) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte("callback:" + workflowID.String()))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// CallbackDispatcher delivers the results of finished executions to their
+// callback URLs, retrying failed deliveries with exponential backoff
+type CallbackDispatcher struct {
+	callbacks  domain.CallbackRepository
+	executions domain.Repository
+	client     *http.Client
+	cfg        configs.CallbackConfig
+	log        *logger.Logger
+}
+
+// NewCallbackDispatcher creates a new callback dispatcher
+func NewCallbackDispatcher(callbacks domain.CallbackRepository, executions domain.Repository, cfg configs.CallbackConfig, log *logger.Logger) *CallbackDispatcher {
+	return &CallbackDispatcher{
+		callbacks:  callbacks,
+		executions: executions,
+		client:     &http.Client{Timeout: cfg.Timeout},
+		cfg:        cfg,
+		log:        log,
+	}
+}
+
+// Start delivers due callbacks until the context is cancelled
+func (d *CallbackDispatcher) Start(ctx context.Context) {
+	interval := d.cfg.PollInterval
+	if interval <= 0 {
+		interval = 5 * time.Second
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			d.dispatchDue(ctx)
+		}
+	}
+}
+
+func (d *CallbackDispatcher) dispatchDue(ctx context.Context) {
+	due, err := d.callbacks.FindDue(ctx, time.Now(), callbackBatchSize)
+	if err != nil {
+		d.log.Errorw("Failed to load due callbacks", "error", err)
+		return
+	}
+
+	for _, cb := range due {
+		if ctx.Err() != nil {
+			return
+		}
+		d.deliver(ctx, cb)
+	}
+}
+
+// deliver sends one callback and records the outcome
+func (d *CallbackDispatcher) deliver(ctx context.Context, cb *domain.Callback) {
+	err := d.send(ctx, cb)
+	if err == nil {
+		cb.Delivered()
+		d.log.Infow("Execution callback delivered", "callback_id", cb.ID, "execution_id", cb.ExecutionID, "attempts", cb.Attempts)
+	} else {
+		cb.Retry(err, d.cfg.MaxAttempts, d.cfg.InitialBackoff, d.cfg.MaxBackoff)
+		d.log.Warnw("Execution callback failed", "callback_id", cb.ID, "execution_id", cb.ExecutionID,
+			"attempts", cb.Attempts, "status", cb.Status, "error", err)
+	}
+
+	if err := d.callbacks.Update(ctx, cb); err != nil {
+		d.log.Errorw("Failed to update callback", "callback_id", cb.ID, "error", err)
+	}
+}
+
+func (d *CallbackDispatcher) send(ctx context.Context, cb *domain.Callback) error {
+	exec, err := d.executions.FindByID(ctx, cb.ExecutionID)
+	if err != nil {
+		return err
+	}
+
+	body, err := json.Marshal(newCallbackPayload(exec))
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, cb.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	timestamp := time.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "go-n8n-callback/1.0")
+	req.Header.Set(HeaderEvent, callbackEvent)
+	req.Header.Set(HeaderDelivery, cb.ID.String())
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(HeaderSignature, Sign(CallbackSecret(d.cfg.Secret, exec.WorkflowID), timestamp, body))
+	if exec.CorrelationID != "" {
+		req.Header.Set(correlation.DefaultHeader, exec.CorrelationID)
+	}
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("callback URL responded with status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+func newCallbackPayload(exec *domain.Execution) CallbackPayload {
+	payload := CallbackPayload{
+		Event:           callbackEvent,
+		ExecutionID:     exec.ID.String(),
+		WorkflowID:      exec.WorkflowID.String(),
+		Status:          exec.Status,
+		StartedAt:       exec.StartedAt,
+		FinishedAt:      exec.FinishedAt,
+		ExecutionTimeMs: exec.ExecutionTimeMs,
+		CorrelationID:   exec.CorrelationID,
+	}
+	if exec.Status == domain.ExecutionStatusSuccess {
+		payload.Output = exec.OutputData
+	} else if exec.ErrorMessage != "" {
+		payload.Error = &CallbackError{Message: exec.ErrorMessage, Node: exec.ErrorNode}
+	}
+	return payload
+}
@@ -0,0 +1,35 @@
+package execution
+
+import (
+	"context"
+
+	domain "github.com/jaydeep/go-n8n/internal/domain/execution"
+)
+
+// Comparer compares two executions of a workflow node by node
+type Comparer struct {
+	nodeRuns domain.NodeExecutionRepository
+}
+
+// NewComparer creates a new execution comparer
+func NewComparer(nodeRuns domain.NodeExecutionRepository) *Comparer {
+	return &Comparer{nodeRuns: nodeRuns}
+}
+
+// Compare diffs other against base, which must be executions of the same
+// workflow. Node output is only compared when node data was saved for
+// both executions.
+func (c *Comparer) Compare(ctx context.Context, base, other *domain.Execution) (*domain.Comparison, error) {
+	if base.WorkflowID != other.WorkflowID {
+		return nil, domain.ErrExecutionsNotComparable
+	}
+	baseRuns, err := c.nodeRuns.ListByExecution(ctx, base.ID)
+	if err != nil {
+		return nil, err
+	}
+	otherRuns, err := c.nodeRuns.ListByExecution(ctx, other.ID)
+	if err != nil {
+		return nil, err
+	}
+	return domain.Compare(base, other, baseRuns, otherRuns), nil
+}
@@ -0,0 +1,96 @@
+package execution
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jaydeep/go-n8n/internal/application/audit"
+	auditlog "github.com/jaydeep/go-n8n/internal/domain/audit"
+	domain "github.com/jaydeep/go-n8n/internal/domain/execution"
+)
+
+// HoldInput holds the fields of a legal hold
+type HoldInput struct {
+	Reason    string `json:"reason"`
+	Reference string `json:"reference"`
+}
+
+// HoldService places and releases legal holds on the executions of
+// workflows, recording both in the audit log
+type HoldService struct {
+	repo  domain.HoldRepository
+	audit *audit.Service
+}
+
+// NewHoldService creates a new legal hold service
+func NewHoldService(repo domain.HoldRepository, auditService *audit.Service) *HoldService {
+	return &HoldService{repo: repo, audit: auditService}
+}
+
+// List returns the holds of a workflow, released ones included, newest
+// first
+func (s *HoldService) List(ctx context.Context, workflowID uuid.UUID) ([]*domain.Hold, error) {
+	return s.repo.ListByWorkflow(ctx, workflowID)
+}
+
+// Place puts the executions of a workflow on legal hold
+func (s *HoldService) Place(ctx context.Context, actor audit.Actor, workflowID uuid.UUID, input HoldInput) (*domain.Hold, error) {
+	hold := &domain.Hold{
+		WorkflowID: workflowID,
+		Reason:     input.Reason,
+		Reference:  input.Reference,
+		PlacedBy:   actor.UserID,
+	}
+	if err := hold.Validate(); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Create(ctx, hold); err != nil {
+		return nil, err
+	}
+
+	s.audit.Record(ctx, actor, &auditlog.AuditLog{
+		Action:       auditlog.ActionLegalHoldPlaced,
+		ResourceType: auditlog.ResourceWorkflow,
+		ResourceID:   workflowID.String(),
+		NewValue:     holdDetails(hold),
+	})
+	return hold, nil
+}
+
+// Release lifts a hold of a workflow. The workflow's executions become
+// prunable again once no other hold is in place.
+func (s *HoldService) Release(ctx context.Context, actor audit.Actor, workflowID, id uuid.UUID) (*domain.Hold, error) {
+	hold, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if hold.WorkflowID != workflowID {
+		return nil, domain.ErrHoldNotFound
+	}
+	if err := hold.Release(actor.UserID, time.Now()); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Update(ctx, hold); err != nil {
+		return nil, err
+	}
+
+	s.audit.Record(ctx, actor, &auditlog.AuditLog{
+		Action:       auditlog.ActionLegalHoldReleased,
+		ResourceType: auditlog.ResourceWorkflow,
+		ResourceID:   workflowID.String(),
+		OldValue:     holdDetails(hold),
+	})
+	return hold, nil
+}
+
+// holdDetails describes a hold in the audit log
+func holdDetails(hold *domain.Hold) map[string]interface{} {
+	return map[string]interface{}{
+		"hold_id":    hold.ID,
+		"reason":     hold.Reason,
+		"reference":  hold.Reference,
+		"placed_by":  hold.PlacedBy,
+		"created_at": hold.CreatedAt,
+	}
+}
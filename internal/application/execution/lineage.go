@@ -0,0 +1,141 @@
+package execution
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	domain "github.com/jaydeep/go-n8n/internal/domain/execution"
+)
+
+// maxLineageSize bounds the executions returned for a chain
+const maxLineageSize = 1000
+
+// LineageNode is an execution of a chain with the executions it started
+type LineageNode struct {
+	ID           uuid.UUID `json:"id"`
+	WorkflowID   uuid.UUID `json:"workflow_id"`
+	WorkflowName string    `json:"workflow_name,omitempty"`
+	// Relation is how the execution relates to its parent, empty for the
+	// top of the tree
+	Relation        domain.Relation        `json:"relation,omitempty"`
+	Status          domain.ExecutionStatus `json:"status"`
+	Mode            domain.ExecutionMode   `json:"mode"`
+	StartedAt       time.Time              `json:"started_at"`
+	FinishedAt      *time.Time             `json:"finished_at,omitempty"`
+	ExecutionTimeMs int                    `json:"execution_time_ms,omitempty"`
+	ErrorNode       string                 `json:"error_node,omitempty"`
+	// Restricted is set on executions of workflows the user may not see,
+	// which are described by their status and timing only
+	Restricted bool           `json:"restricted,omitempty"`
+	Children   []*LineageNode `json:"children"`
+}
+
+// Lineage is the tree of executions chained through sub-workflows and
+// error workflows
+type Lineage struct {
+	Root *LineageNode `json:"root"`
+	// Size counts the executions in the tree
+	Size int `json:"size"`
+	// Truncated is set when the chain has more executions than returned
+	Truncated bool `json:"truncated"`
+}
+
+// Lineage returns the tree exec is part of: the first execution of its
+// chain and every execution that led to. When that execution was deleted,
+// the tree starts at the oldest ancestor of exec still stored. canSee
+// tells whether the user may see the executions of a workflow owner.
+func (s *Service) Lineage(ctx context.Context, exec *domain.Execution, canSee func(ownerID uuid.UUID) bool) (*Lineage, error) {
+	rootID := exec.ID
+	if exec.RootExecutionID != nil {
+		rootID = *exec.RootExecutionID
+	}
+	chain, err := s.repo.ListLineage(ctx, rootID, maxLineageSize+1)
+	if err != nil {
+		return nil, err
+	}
+	lineage := &Lineage{}
+	if len(chain) > maxLineageSize {
+		chain = chain[:maxLineageSize]
+		lineage.Truncated = true
+	}
+
+	nodes := make(map[uuid.UUID]*LineageNode, len(chain)+1)
+	var workflowIDs []uuid.UUID
+	seen := make(map[uuid.UUID]bool)
+	add := func(e *domain.Execution) {
+		nodes[e.ID] = &LineageNode{
+			ID:              e.ID,
+			WorkflowID:      e.WorkflowID,
+			Relation:        e.Relation,
+			Status:          e.Status,
+			Mode:            e.Mode,
+			StartedAt:       e.StartedAt,
+			FinishedAt:      e.FinishedAt,
+			ExecutionTimeMs: e.ExecutionTimeMs,
+			ErrorNode:       e.ErrorNode,
+			Children:        []*LineageNode{},
+		}
+		if !seen[e.WorkflowID] {
+			seen[e.WorkflowID] = true
+			workflowIDs = append(workflowIDs, e.WorkflowID)
+		}
+	}
+	for _, e := range chain {
+		add(e)
+	}
+	if _, ok := nodes[exec.ID]; !ok {
+		// Left out of a truncated chain
+		chain = append(chain, exec)
+		add(exec)
+	}
+
+	workflows, err := s.workflows.FindByIDs(ctx, workflowIDs)
+	if err != nil {
+		return nil, err
+	}
+	owners := make(map[uuid.UUID]uuid.UUID, len(workflows))
+	names := make(map[uuid.UUID]string, len(workflows))
+	for _, w := range workflows {
+		owners[w.ID] = w.UserID
+		names[w.ID] = w.Name
+	}
+
+	parents := make(map[uuid.UUID]uuid.UUID, len(chain))
+	for _, e := range chain {
+		node := nodes[e.ID]
+		if owner, ok := owners[e.WorkflowID]; ok && canSee(owner) {
+			node.WorkflowName = names[e.WorkflowID]
+		} else {
+			node.Restricted = true
+			node.ErrorNode = ""
+		}
+		if e.ParentExecutionID == nil {
+			continue
+		}
+		if parent, ok := nodes[*e.ParentExecutionID]; ok {
+			parent.Children = append(parent.Children, node)
+			parents[e.ID] = parent.ID
+		}
+	}
+
+	top := exec.ID
+	for {
+		parent, ok := parents[top]
+		if !ok {
+			break
+		}
+		top = parent
+	}
+	lineage.Root = nodes[top]
+	lineage.Size = countNodes(lineage.Root)
+	return lineage, nil
+}
+
+func countNodes(node *LineageNode) int {
+	n := 1
+	for _, child := range node.Children {
+		n += countNodes(child)
+	}
+	return n
+}
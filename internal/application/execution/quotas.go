@@ -0,0 +1,152 @@
+package execution
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	domain "github.com/jaydeep/go-n8n/internal/domain/execution"
+	"github.com/jaydeep/go-n8n/internal/domain/user"
+	"github.com/jaydeep/go-n8n/pkg/logger"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	teamQuotaKeyPrefix = "n8n:team:executions:"
+	teamQuotaWindow    = time.Minute
+	// teamSettingsTTL is how long team settings are cached, so changes to
+	// the quotas take effect within it
+	teamSettingsTTL = 30 * time.Second
+)
+
+// cachedTeamSettings holds the settings of a team until they expire
+type cachedTeamSettings struct {
+	settings user.TeamSettings
+	expires  time.Time
+}
+
+// TeamQuotas admits the jobs of the workflows of teams to workers within
+// the executions per minute and concurrent executions of their settings,
+// and weighs teams sharing a queue by their scheduling weight.
+// Starts are counted in Redis across instances, or in process without it;
+// concurrent executions are those marked running. Jobs are admitted when
+// the quotas cannot be checked.
+type TeamQuotas struct {
+	teams      user.Repository
+	executions domain.Repository
+	redis      *redis.Client
+	log        *logger.Logger
+
+	mu       sync.Mutex
+	settings map[uuid.UUID]cachedTeamSettings
+	// window and starts count the starts per team in process
+	window time.Time
+	starts map[uuid.UUID]int
+}
+
+// NewTeamQuotas creates a new team quota admitter. redisClient may be nil
+// to count starts per instance.
+func NewTeamQuotas(teams user.Repository, executions domain.Repository, redisClient *redis.Client, log *logger.Logger) *TeamQuotas {
+	return &TeamQuotas{
+		teams:      teams,
+		executions: executions,
+		redis:      redisClient,
+		log:        log,
+		settings:   make(map[uuid.UUID]cachedTeamSettings),
+		starts:     make(map[uuid.UUID]int),
+	}
+}
+
+// Admit reports whether a job of the team may start, counting it against
+// the team's executions per minute when it may
+func (q *TeamQuotas) Admit(ctx context.Context, team string) bool {
+	teamID, err := uuid.Parse(team)
+	if err != nil {
+		return true
+	}
+	settings, err := q.teamSettings(ctx, teamID)
+	if err != nil {
+		q.log.Warnw("Failed to load team quotas", "team_id", teamID, "error", err)
+		return true
+	}
+
+	if settings.MaxConcurrentExecutions > 0 {
+		running, err := q.executions.CountRunningByTeam(ctx, teamID)
+		if err != nil {
+			q.log.Warnw("Failed to count running executions of team", "team_id", teamID, "error", err)
+		} else if running >= int64(settings.MaxConcurrentExecutions) {
+			return false
+		}
+	}
+	if settings.ExecutionsPerMinute > 0 {
+		return q.countStart(ctx, teamID) <= settings.ExecutionsPerMinute
+	}
+	return true
+}
+
+// Weight returns the scheduling weight of the team, 1 unless set
+func (q *TeamQuotas) Weight(ctx context.Context, team string) int {
+	teamID, err := uuid.Parse(team)
+	if err != nil {
+		return 1
+	}
+	settings, err := q.teamSettings(ctx, teamID)
+	if err != nil || settings.SchedulingWeight < 1 {
+		return 1
+	}
+	return settings.SchedulingWeight
+}
+
+// teamSettings returns the settings of a team, cached. Teams that do not
+// exist have no quotas.
+func (q *TeamQuotas) teamSettings(ctx context.Context, teamID uuid.UUID) (user.TeamSettings, error) {
+	q.mu.Lock()
+	cached, ok := q.settings[teamID]
+	q.mu.Unlock()
+	if ok && time.Now().Before(cached.expires) {
+		return cached.settings, nil
+	}
+
+	var settings user.TeamSettings
+	team, err := q.teams.FindTeam(ctx, teamID)
+	switch {
+	case err == nil:
+		settings = team.Settings
+	case !errors.Is(err, user.ErrTeamNotFound):
+		return settings, err
+	}
+
+	q.mu.Lock()
+	q.settings[teamID] = cachedTeamSettings{settings: settings, expires: time.Now().Add(teamSettingsTTL)}
+	q.mu.Unlock()
+	return settings, nil
+}
+
+// countStart counts a start of a team in the current window, returning the
+// starts so far including it
+func (q *TeamQuotas) countStart(ctx context.Context, teamID uuid.UUID) int {
+	window := time.Now().Truncate(teamQuotaWindow)
+	if q.redis != nil {
+		key := fmt.Sprintf("%s%s:%d", teamQuotaKeyPrefix, teamID, window.Unix())
+		pipe := q.redis.TxPipeline()
+		count := pipe.Incr(ctx, key)
+		pipe.Expire(ctx, key, 2*teamQuotaWindow)
+		if _, err := pipe.Exec(ctx); err != nil {
+			q.log.Warnw("Failed to count team execution start", "team_id", teamID, "error", err)
+			return 0
+		}
+		return int(count.Val())
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if !window.Equal(q.window) {
+		q.window = window
+		q.starts = make(map[uuid.UUID]int)
+	}
+	q.starts[teamID]++
+	return q.starts[teamID]
+}
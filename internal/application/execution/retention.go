@@ -0,0 +1,160 @@
+package execution
+
+import (
+	"context"
+	"time"
+
+	"github.com/jaydeep/go-n8n/configs"
+	"github.com/jaydeep/go-n8n/internal/application/settings"
+	domain "github.com/jaydeep/go-n8n/internal/domain/execution"
+	settingsdomain "github.com/jaydeep/go-n8n/internal/domain/settings"
+	"github.com/jaydeep/go-n8n/pkg/logger"
+)
+
+const (
+	defaultRetentionInterval = time.Hour
+	defaultPartitionsAhead   = 2
+	defaultRetentionBatch    = 1000
+)
+
+// Retention deletes executions older than the executions.retention_days
+// setting; a setting of zero keeps them forever. On partitioned tables it
+// also creates upcoming monthly partitions before rows arrive for them, and
+// drops partitions whose whole month has expired instead of deleting their
+// rows one by one. Executions of workflows on legal hold are kept, along
+// with the partitions they may be in.
+type Retention struct {
+	repo       domain.Repository
+	holds      domain.HoldRepository
+	partitions domain.PartitionRepository
+	settings   *settings.Service
+	cfg        configs.RetentionConfig
+	log        *logger.Logger
+}
+
+// NewRetention creates a new retention job. partitions may be nil when the
+// database does not support partitioning.
+func NewRetention(repo domain.Repository, holds domain.HoldRepository, partitions domain.PartitionRepository, settingsService *settings.Service, cfg configs.RetentionConfig, log *logger.Logger) *Retention {
+	if cfg.Interval <= 0 {
+		cfg.Interval = defaultRetentionInterval
+	}
+	if cfg.PartitionsAhead <= 0 {
+		cfg.PartitionsAhead = defaultPartitionsAhead
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = defaultRetentionBatch
+	}
+	return &Retention{
+		repo:       repo,
+		holds:      holds,
+		partitions: partitions,
+		settings:   settingsService,
+		cfg:        cfg,
+		log:        log,
+	}
+}
+
+// Start runs the retention job until the context is cancelled
+func (r *Retention) Start(ctx context.Context) {
+	ticker := time.NewTicker(r.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		if err := r.Run(ctx); err != nil && ctx.Err() == nil {
+			r.log.Errorw("Execution retention failed", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Run maintains the partitions and deletes expired executions
+func (r *Retention) Run(ctx context.Context) error {
+	now := time.Now()
+	days := r.settings.Int(ctx, settingsdomain.KeyExecutionRetentionDays)
+
+	var cutoff time.Time
+	if days > 0 {
+		cutoff = now.AddDate(0, 0, -int(days))
+	}
+
+	if r.partitions != nil {
+		for _, table := range domain.PartitionedTables {
+			if err := r.maintainPartitions(ctx, table, now, cutoff); err != nil {
+				return err
+			}
+		}
+	}
+	if cutoff.IsZero() {
+		return nil
+	}
+
+	// The rows left are those of the partly expired month, or all expired
+	// rows when the tables are not partitioned
+	var total int64
+	for {
+		deleted, err := r.repo.DeleteStartedBefore(ctx, cutoff, r.cfg.BatchSize)
+		total += deleted
+		if err != nil {
+			return err
+		}
+		if deleted < int64(r.cfg.BatchSize) || ctx.Err() != nil {
+			break
+		}
+	}
+	if total > 0 {
+		r.log.Infow("Deleted expired executions", "count", total, "before", cutoff)
+	}
+	return nil
+}
+
+// maintainPartitions creates the partitions from the current month up to
+// PartitionsAhead months ahead and drops those that ended before cutoff
+func (r *Retention) maintainPartitions(ctx context.Context, table string, now, cutoff time.Time) error {
+	partitioned, err := r.partitions.Partitioned(ctx, table)
+	if err != nil || !partitioned {
+		return err
+	}
+
+	for i := 0; i <= r.cfg.PartitionsAhead; i++ {
+		p := domain.NewPartition(table, domain.MonthOf(now).AddDate(0, i, 0))
+		if err := r.partitions.CreatePartition(ctx, p); err != nil {
+			// Rows of the month already in the default partition block it;
+			// they stay there and the remaining months are still created
+			r.log.Warnw("Failed to create execution partition", "partition", p.Name(), "error", err)
+		}
+	}
+
+	if cutoff.IsZero() {
+		return nil
+	}
+	existing, err := r.partitions.ListPartitions(ctx, table)
+	if err != nil {
+		return err
+	}
+	for _, p := range existing {
+		if p.End().After(cutoff) {
+			continue
+		}
+		// Held executions started before the end of the month may have
+		// rows in the partition, their node data included, so it is kept
+		// and its other rows are deleted one by one instead
+		held, err := r.holds.HeldStartedBefore(ctx, p.End())
+		if err != nil {
+			return err
+		}
+		if held {
+			r.log.Infow("Kept expired execution partition holding executions on legal hold", "partition", p.Name())
+			continue
+		}
+		if err := r.partitions.DropPartition(ctx, p); err != nil {
+			return err
+		}
+		r.log.Infow("Dropped expired execution partition", "partition", p.Name())
+	}
+	return nil
+}
@@ -0,0 +1,451 @@
+package execution
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jaydeep/go-n8n/internal/application/workflow"
+	domain "github.com/jaydeep/go-n8n/internal/domain/execution"
+	"github.com/jaydeep/go-n8n/internal/domain/node"
+	workflowdomain "github.com/jaydeep/go-n8n/internal/domain/workflow"
+	"github.com/jaydeep/go-n8n/pkg/correlation"
+	"github.com/jaydeep/go-n8n/pkg/logger"
+	"github.com/jaydeep/go-n8n/pkg/pagination"
+	"github.com/jaydeep/go-n8n/pkg/queue"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	finishedChannelPrefix = "n8n:execution:finished:"
+	// waitPollInterval is how often Wait re-reads the execution in case a
+	// finish notification was missed
+	waitPollInterval = time.Second
+	// exportBatchSize is how many executions Export reads per query
+	exportBatchSize = 500
+)
+
+// StartInput describes an execution requested through the API
+type StartInput struct {
+	Mode domain.ExecutionMode
+	Data map[string]interface{}
+	// CallbackURL receives the result once the execution finishes
+	CallbackURL string
+	// Version runs a specific published version instead of the current one
+	Version int
+	// Draft runs the current draft instead of a published version, as test
+	// executions do
+	Draft bool
+	// Parent is the execution starting this one, as Relation
+	Parent   *domain.Execution
+	Relation domain.Relation
+	// Items are the input items of the execution, in place of Data, such
+	// as those of a sub-workflow or a call carrying files
+	Items []node.Item
+	// Inline leaves the execution to the caller to run rather than placing
+	// it on the worker queue
+	Inline bool
+	// DryRun skips or mocks the nodes with side effects, as DryRunOptions
+	// say
+	DryRun        bool
+	DryRunOptions *domain.DryRunOptions
+}
+
+// Service starts executions of published workflows
+type Service struct {
+	workflows *workflow.Service
+	repo      domain.Repository
+	callbacks domain.CallbackRepository
+	waits     domain.WaitRepository
+	events    domain.EventPublisher
+	queue     queue.Queue
+	queueName string
+	redis     *redis.Client
+	log       *logger.Logger
+}
+
+// NewService creates a new execution service. Finish notifications are
+// published on redisClient, which may be nil to rely on polling alone.
+func NewService(workflows *workflow.Service, repo domain.Repository, callbacks domain.CallbackRepository, waits domain.WaitRepository, events domain.EventPublisher, q queue.Queue, queueName string, redisClient *redis.Client, log *logger.Logger) *Service {
+	return &Service{
+		workflows: workflows,
+		repo:      repo,
+		callbacks: callbacks,
+		waits:     waits,
+		events:    events,
+		queue:     q,
+		queueName: queueName,
+		redis:     redisClient,
+		log:       log,
+	}
+}
+
+// Get returns an execution
+func (s *Service) Get(ctx context.Context, id uuid.UUID) (*domain.Execution, error) {
+	return s.repo.FindByID(ctx, id)
+}
+
+// Delete deletes an execution with its node data, unless its workflow is
+// on legal hold
+func (s *Service) Delete(ctx context.Context, id uuid.UUID) error {
+	return s.repo.Delete(ctx, id)
+}
+
+// List returns a page of the executions matching filter, newest first, and
+// the cursor of the next page, which is nil on the last page
+func (s *Service) List(ctx context.Context, filter domain.ListFilter) ([]*domain.Execution, *pagination.Cursor, error) {
+	limit := pagination.Limit(filter.Limit)
+	filter.Limit = limit + 1
+	executions, err := s.repo.List(ctx, filter)
+	if err != nil {
+		return nil, nil, err
+	}
+	executions, next := pagination.Page(executions, limit, executionCursor)
+	return executions, next, nil
+}
+
+// Export calls fn with every execution matching filter, newest first. Rows
+// are read in batches by cursor, so memory use does not grow with the
+// number of rows. Export stops at the first error returned by fn.
+func (s *Service) Export(ctx context.Context, filter domain.ListFilter, fn func(*domain.Execution) error) error {
+	filter.Limit = exportBatchSize
+	filter.Offset = 0
+	for {
+		executions, err := s.repo.List(ctx, filter)
+		if err != nil {
+			return err
+		}
+		for _, exec := range executions {
+			if err := fn(exec); err != nil {
+				return err
+			}
+		}
+		if len(executions) < exportBatchSize {
+			return nil
+		}
+		filter.After = executionCursor(executions[len(executions)-1])
+	}
+}
+
+func executionCursor(e *domain.Execution) *pagination.Cursor {
+	return pagination.After(e.StartedAt, e.ID)
+}
+
+// Start creates a waiting execution of the published version of a
+// workflow, of input.Version or of the draft when set, and places it on the
+// worker queue unless input.Inline is set
+func (s *Service) Start(ctx context.Context, workflowID uuid.UUID, input StartInput) (*domain.Execution, error) {
+	var w *workflowdomain.Workflow
+	var err error
+	if input.Draft {
+		w, err = s.workflows.Get(ctx, workflowID)
+		if err == nil {
+			err = w.Validate()
+		}
+	} else {
+		w, err = s.workflows.AtVersion(ctx, workflowID, input.Version)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if input.Mode == "" {
+		input.Mode = domain.ExecutionModeManual
+	}
+	if input.Data == nil {
+		input.Data = map[string]interface{}{}
+	}
+
+	exec := &domain.Execution{
+		ID:              uuid.New(),
+		WorkflowID:      w.ID,
+		WorkflowVersion: w.Version,
+		Status:          domain.ExecutionStatusWaiting,
+		Mode:            input.Mode,
+		StartedAt:       time.Now(),
+		InputData:       input.Data,
+	}
+	if input.Parent != nil {
+		exec.LinkTo(input.Parent, input.Relation)
+	}
+	if input.Items != nil || exec.Relation == domain.RelationSubWorkflow {
+		exec.SetInputItems(input.Items)
+	}
+	if input.DryRun {
+		exec.DryRun = true
+		if !input.DryRunOptions.IsZero() {
+			exec.DryRunOptions = input.DryRunOptions
+		}
+	}
+	if exec.Relation == domain.RelationSubWorkflow {
+		// Sub-workflows of dry runs are dry runs too, with the default
+		// options as the nodes they name are those of the parent
+		exec.DryRun = input.Parent.DryRun
+	}
+	// Executions started directly take the correlation ID of the request
+	if exec.CorrelationID == "" {
+		exec.CorrelationID = correlation.FromContext(ctx)
+	}
+	if exec.CorrelationID == "" {
+		exec.CorrelationID = correlation.New()
+	}
+
+	var callback *domain.Callback
+	if input.CallbackURL != "" {
+		callback, err = domain.NewCallback(exec.ID, input.CallbackURL)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.repo.Create(ctx, exec); err != nil {
+		return nil, err
+	}
+	// The callback is stored before the job is queued so a fast execution
+	// cannot finish before its callback exists
+	if callback != nil {
+		if err := s.callbacks.Create(ctx, callback); err != nil {
+			return nil, err
+		}
+	}
+	if input.Inline {
+		s.log.Infow("Execution created", "execution_id", exec.ID, "workflow_id", w.ID, "mode", exec.Mode, "relation", exec.Relation,
+			"correlation_id", exec.CorrelationID)
+		return exec, nil
+	}
+
+	err = s.queue.Enqueue(ctx, &queue.Job{
+		Queue:         w.Settings.QueueOr(s.queueName),
+		Type:          string(exec.Mode),
+		Region:        w.Settings.Region,
+		Team:          w.QueueTeam(),
+		ExecutionID:   exec.ID.String(),
+		WorkflowID:    w.ID.String(),
+		Deadline:      jobDeadline(w, exec.StartedAt),
+		CorrelationID: exec.CorrelationID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.events.Publish(domain.NewEvent(domain.EventExecutionQueued, exec))
+	s.log.Infow("Execution queued", "execution_id", exec.ID, "workflow_id", w.ID, "mode", exec.Mode, "callback", callback != nil,
+		"correlation_id", exec.CorrelationID)
+	return exec, nil
+}
+
+// Resume resumes an execution waiting to be resumed by request, ahead of
+// the time it waits until if it has one. The node it waited at outputs
+// data as one item, or the items it received when data is empty.
+func (s *Service) Resume(ctx context.Context, id uuid.UUID, data map[string]interface{}) (*domain.Execution, error) {
+	exec, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if exec.Status != domain.ExecutionStatusWaiting {
+		return nil, domain.ErrExecutionNotWaiting
+	}
+	wait, err := s.waits.FindByExecution(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !wait.Webhook {
+		return nil, domain.ErrWaitNotResumable
+	}
+	if err := s.resume(ctx, exec, data); err != nil {
+		return nil, err
+	}
+	return exec, nil
+}
+
+// ResumeWait resumes an execution whose wait has come to its end
+func (s *Service) ResumeWait(ctx context.Context, wait *domain.WaitingExecution) error {
+	exec, err := s.repo.FindByID(ctx, wait.ExecutionID)
+	if err != nil {
+		return err
+	}
+	return s.resume(ctx, exec, nil)
+}
+
+// resume claims the wait of an execution and places the execution back on
+// the worker queue. Its max execution time counts from the resume.
+func (s *Service) resume(ctx context.Context, exec *domain.Execution, data map[string]interface{}) error {
+	claimed, err := s.waits.Claim(ctx, exec.ID, data, time.Now())
+	if err != nil {
+		return err
+	}
+	if !claimed {
+		return domain.ErrExecutionNotWaiting
+	}
+
+	w, err := s.workflows.Get(ctx, exec.WorkflowID)
+	if err != nil {
+		return err
+	}
+	err = s.queue.Enqueue(ctx, &queue.Job{
+		Queue:         w.Settings.QueueOr(s.queueName),
+		Type:          string(exec.Mode),
+		Region:        w.Settings.Region,
+		Team:          w.QueueTeam(),
+		ExecutionID:   exec.ID.String(),
+		WorkflowID:    w.ID.String(),
+		Deadline:      jobDeadline(w, time.Now()),
+		CorrelationID: exec.CorrelationID,
+	})
+	if err != nil {
+		return err
+	}
+
+	s.events.Publish(domain.NewEvent(domain.EventExecutionResumed, exec))
+	s.log.Infow("Execution resumed", "execution_id", exec.ID, "workflow_id", exec.WorkflowID, "correlation_id", exec.CorrelationID)
+	return nil
+}
+
+// NotifyFinished wakes up requests waiting for the execution and records
+// the finish for analytics. It is called by whoever moves the execution to
+// a terminal state.
+func (s *Service) NotifyFinished(ctx context.Context, exec *domain.Execution) {
+	s.events.Publish(domain.NewEvent(domain.EventExecutionFinished, exec))
+	if s.redis == nil {
+		return
+	}
+	if err := s.redis.Publish(ctx, finishedChannel(exec.ID), string(exec.Status)).Err(); err != nil {
+		s.log.Warnw("Failed to publish execution finish", "execution_id", exec.ID, "error", err)
+	}
+}
+
+// StartErrorWorkflow starts the error workflow set on the workflow of a
+// failed execution, passing it what failed. Executions that did not fail,
+// dry runs and error workflow runs start none, so a failing error workflow
+// cannot start another. It returns nil when no error workflow is set.
+// Timed out executions pass the outputs of the nodes that completed when
+// the workflow opts in.
+func (s *Service) StartErrorWorkflow(ctx context.Context, failed *domain.Execution) (*domain.Execution, error) {
+	if !failed.Status.IsFailed() || failed.DryRun || failed.Relation == domain.RelationErrorWorkflow {
+		return nil, nil
+	}
+	w, err := s.workflows.Get(ctx, failed.WorkflowID)
+	if err != nil {
+		return nil, err
+	}
+	if w.Settings.ErrorWorkflow == nil {
+		return nil, nil
+	}
+
+	// The failure is only passed to workflows of the same owner or team
+	target, err := s.workflows.Get(ctx, *w.Settings.ErrorWorkflow)
+	if err != nil {
+		return nil, err
+	}
+	if !sameOwner(w, target) {
+		return nil, workflowdomain.ErrWorkflowNotFound
+	}
+
+	details := map[string]interface{}{
+		"id":         failed.ID,
+		"mode":       failed.Mode,
+		"status":     failed.Status,
+		"started_at": failed.StartedAt,
+		"error": map[string]interface{}{
+			"message": failed.ErrorMessage,
+			"node":    failed.ErrorNode,
+		},
+	}
+	if partial := failed.PartialOutput(); partial != nil && w.Settings.ErrorWorkflowPartialOutput {
+		details["partial_output"] = partial
+	}
+
+	return s.Start(ctx, target.ID, StartInput{
+		Mode: domain.ExecutionModeError,
+		Data: map[string]interface{}{
+			"execution": details,
+			"workflow": map[string]interface{}{
+				"id":   w.ID,
+				"name": w.Name,
+			},
+		},
+		Parent:   failed,
+		Relation: domain.RelationErrorWorkflow,
+	})
+}
+
+// StartSubWorkflow creates an execution of the published version of a
+// workflow as a sub-workflow of parent, with items as its input, in the
+// mode of parent. Only workflows of the owner or team of parent's workflow
+// may be run. Inline executions are left to the caller to run; others are
+// placed on the worker queue.
+func (s *Service) StartSubWorkflow(ctx context.Context, parent *domain.Execution, workflowID uuid.UUID, items []node.Item, inline bool) (*domain.Execution, error) {
+	w, err := s.workflows.Get(ctx, parent.WorkflowID)
+	if err != nil {
+		return nil, err
+	}
+	target, err := s.workflows.Get(ctx, workflowID)
+	if err != nil {
+		return nil, err
+	}
+	if !sameOwner(w, target) {
+		return nil, workflowdomain.ErrWorkflowNotFound
+	}
+
+	return s.Start(ctx, target.ID, StartInput{
+		Mode:     parent.Mode,
+		Items:    items,
+		Parent:   parent,
+		Relation: domain.RelationSubWorkflow,
+		Inline:   inline,
+	})
+}
+
+// sameOwner reports whether two workflows have the same owner or team
+func sameOwner(a, b *workflowdomain.Workflow) bool {
+	sameTeam := a.TeamID != nil && b.TeamID != nil && *a.TeamID == *b.TeamID
+	return a.UserID == b.UserID || sameTeam
+}
+
+// jobDeadline returns when the worker must abandon an execution, at the
+// workflow's max execution time, or zero when it has none
+func jobDeadline(w *workflowdomain.Workflow, startedAt time.Time) time.Time {
+	if w.Settings.MaxExecutionTime <= 0 {
+		return time.Time{}
+	}
+	return startedAt.Add(time.Duration(w.Settings.MaxExecutionTime) * time.Second)
+}
+
+// Wait blocks until the execution finishes or timeout elapses and returns
+// its latest state. A non-terminal status means the wait timed out.
+func (s *Service) Wait(ctx context.Context, id uuid.UUID, timeout time.Duration) (*domain.Execution, error) {
+	waitCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	// Subscribe before the first read so a finish in between is not missed
+	var finished <-chan *redis.Message
+	if s.redis != nil {
+		sub := s.redis.Subscribe(waitCtx, finishedChannel(id))
+		defer sub.Close()
+		finished = sub.Channel()
+	}
+
+	poll := time.NewTicker(waitPollInterval)
+	defer poll.Stop()
+
+	for {
+		exec, err := s.repo.FindByID(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if exec.Status.IsTerminal() {
+			return exec, nil
+		}
+
+		select {
+		case <-waitCtx.Done():
+			return exec, nil
+		case <-finished:
+		case <-poll.C:
+		}
+	}
+}
+
+func finishedChannel(id uuid.UUID) string {
+	return finishedChannelPrefix + id.String()
+}
@@ -0,0 +1,212 @@
+package execution
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	domain "github.com/jaydeep/go-n8n/internal/domain/execution"
+	"github.com/jaydeep/go-n8n/internal/domain/user"
+	"github.com/jaydeep/go-n8n/internal/domain/workflow"
+)
+
+// ViewInput holds the fields of a saved view
+type ViewInput struct {
+	Name       string            `json:"name"`
+	Filter     domain.ViewFilter `json:"filter"`
+	Subscribed bool              `json:"subscribed"`
+}
+
+// ViewMatch is a finished execution matching a subscribed view, sent to
+// the view's user
+type ViewMatch struct {
+	ViewID    uuid.UUID     `json:"view_id"`
+	ViewName  string        `json:"view_name"`
+	UserID    uuid.UUID     `json:"-"`
+	Execution *domain.Event `json:"execution"`
+}
+
+// ViewService manages the saved execution views of users and matches
+// finished executions against the subscribed ones
+type ViewService struct {
+	repo       domain.ViewRepository
+	executions domain.Repository
+	workflows  workflow.Repository
+	users      user.Repository
+}
+
+// NewViewService creates a new execution view service
+func NewViewService(repo domain.ViewRepository, executions domain.Repository, workflows workflow.Repository, users user.Repository) *ViewService {
+	return &ViewService{
+		repo:       repo,
+		executions: executions,
+		workflows:  workflows,
+		users:      users,
+	}
+}
+
+// List returns the views of a user ordered by name
+func (s *ViewService) List(ctx context.Context, userID uuid.UUID) ([]*domain.View, error) {
+	return s.repo.ListByUser(ctx, userID)
+}
+
+// Get returns a view of a user. Views of other users are not found.
+func (s *ViewService) Get(ctx context.Context, userID, id uuid.UUID) (*domain.View, error) {
+	v, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if v.UserID != userID {
+		return nil, domain.ErrViewNotFound
+	}
+	return v, nil
+}
+
+// Create saves a new view for a user
+func (s *ViewService) Create(ctx context.Context, userID uuid.UUID, input ViewInput) (*domain.View, error) {
+	v := &domain.View{UserID: userID}
+	applyViewInput(v, input)
+	if err := v.Validate(); err != nil {
+		return nil, err
+	}
+
+	existing, err := s.repo.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if len(existing) >= domain.MaxViews {
+		return nil, domain.ErrTooManyViews
+	}
+	if nameTaken(existing, v) {
+		return nil, domain.ErrViewNameTaken
+	}
+
+	if err := s.repo.Create(ctx, v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// Update replaces the name, filter and subscription of a view
+func (s *ViewService) Update(ctx context.Context, userID, id uuid.UUID, input ViewInput) (*domain.View, error) {
+	v, err := s.Get(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	applyViewInput(v, input)
+	if err := v.Validate(); err != nil {
+		return nil, err
+	}
+
+	existing, err := s.repo.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if nameTaken(existing, v) {
+		return nil, domain.ErrViewNameTaken
+	}
+
+	if err := s.repo.Update(ctx, v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// Delete removes a view of a user
+func (s *ViewService) Delete(ctx context.Context, userID, id uuid.UUID) error {
+	if _, err := s.Get(ctx, userID, id); err != nil {
+		return err
+	}
+	return s.repo.Delete(ctx, id)
+}
+
+// Match returns the subscribed views a finished execution matches, leaving
+// out those of users who may not see the execution. Dry runs match no
+// view.
+func (s *ViewService) Match(ctx context.Context, e *domain.Event) ([]ViewMatch, error) {
+	if e.DryRun {
+		return nil, nil
+	}
+	views, err := s.repo.ListSubscribed(ctx)
+	if err != nil || len(views) == 0 {
+		return nil, err
+	}
+	exec, err := s.executions.FindByID(ctx, e.ExecutionID)
+	if err != nil {
+		return nil, err
+	}
+
+	var matched []*domain.View
+	for _, v := range views {
+		if v.Filter.Matches(exec) {
+			matched = append(matched, v)
+		}
+	}
+	if len(matched) == 0 {
+		return nil, nil
+	}
+	visible, err := s.visibleTo(ctx, exec.WorkflowID, matched)
+	if err != nil {
+		return nil, err
+	}
+
+	matches := make([]ViewMatch, 0, len(matched))
+	ids := make([]uuid.UUID, 0, len(matched))
+	for _, v := range matched {
+		if !visible[v.UserID] {
+			continue
+		}
+		matches = append(matches, ViewMatch{ViewID: v.ID, ViewName: v.Name, UserID: v.UserID, Execution: e})
+		ids = append(ids, v.ID)
+	}
+	if len(ids) > 0 {
+		if err := s.repo.MarkMatched(ctx, ids, time.Now().UTC()); err != nil {
+			return nil, err
+		}
+	}
+	return matches, nil
+}
+
+// visibleTo returns which users of the views may see the executions of a
+// workflow: its owner and active admins
+func (s *ViewService) visibleTo(ctx context.Context, workflowID uuid.UUID, views []*domain.View) (map[uuid.UUID]bool, error) {
+	w, err := s.workflows.FindByID(ctx, workflowID)
+	if err != nil {
+		return nil, err
+	}
+
+	visible := map[uuid.UUID]bool{w.UserID: true}
+	var others []uuid.UUID
+	for _, v := range views {
+		if v.UserID != w.UserID {
+			others = append(others, v.UserID)
+		}
+	}
+	if len(others) == 0 {
+		return visible, nil
+	}
+	users, err := s.users.FindByIDs(ctx, others)
+	if err != nil {
+		return nil, err
+	}
+	for _, u := range users {
+		visible[u.ID] = u.IsActive && u.CanAccessWorkflow(w.UserID)
+	}
+	return visible, nil
+}
+
+func applyViewInput(v *domain.View, input ViewInput) {
+	v.Name = input.Name
+	v.Filter = input.Filter
+	v.Subscribed = input.Subscribed
+}
+
+// nameTaken reports whether another of the views is named like v
+func nameTaken(views []*domain.View, v *domain.View) bool {
+	for _, existing := range views {
+		if existing.ID != v.ID && existing.Name == v.Name {
+			return true
+		}
+	}
+	return false
+}
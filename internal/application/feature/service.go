@@ -0,0 +1,306 @@
+package feature
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jaydeep/go-n8n/configs"
+	"github.com/jaydeep/go-n8n/internal/application/audit"
+	auditdomain "github.com/jaydeep/go-n8n/internal/domain/audit"
+	domain "github.com/jaydeep/go-n8n/internal/domain/feature"
+	userdomain "github.com/jaydeep/go-n8n/internal/domain/user"
+	"github.com/jaydeep/go-n8n/pkg/logger"
+)
+
+const defaultCacheTTL = 30 * time.Second
+
+// FlagInput changes a flag; nil fields are left unchanged
+type FlagInput struct {
+	Description *string `json:"description"`
+	Enabled     *bool   `json:"enabled"`
+	Rollout     *int    `json:"rollout"`
+}
+
+// Service evaluates and manages feature flags. Stored flags are cached per
+// instance for the configured TTL; built-in flags that were never stored
+// take their defaults from the configuration.
+type Service struct {
+	repo     domain.Repository
+	users    userdomain.Repository
+	defaults map[string]bool
+	ttl      time.Duration
+	audit    *audit.Service
+	log      *logger.Logger
+
+	mu       sync.Mutex
+	flags    map[string]*domain.Flag
+	loadedAt time.Time
+}
+
+// NewService creates a new feature flag service
+func NewService(repo domain.Repository, users userdomain.Repository, cfg configs.FeaturesConfig, auditService *audit.Service, log *logger.Logger) *Service {
+	ttl := cfg.CacheTTL
+	if ttl <= 0 {
+		ttl = defaultCacheTTL
+	}
+	return &Service{
+		repo:  repo,
+		users: users,
+		defaults: map[string]bool{
+			domain.KeyTeams:         cfg.Teams,
+			domain.KeyMarketplace:   cfg.Marketplace,
+			domain.KeyCustomNodes:   cfg.CustomNodes,
+			domain.KeyWebhookTunnel: cfg.WebhookTunnel,
+			domain.KeyAPIAccess:     cfg.APIAccess,
+			domain.KeyOAuthLogin:    cfg.OAuthLogin,
+			domain.KeyTwoFactorAuth: cfg.TwoFactorAuth,
+			domain.KeyDiagnostics:   cfg.Diagnostics,
+		},
+		ttl:   ttl,
+		audit: auditService,
+		log:   log,
+	}
+}
+
+// Enabled reports whether a flag is enabled for a user. Unknown flags are
+// disabled. When the flags cannot be loaded, built-in flags fall back to
+// their configured defaults.
+func (s *Service) Enabled(ctx context.Context, key string, userID uuid.UUID) bool {
+	flags, err := s.load(ctx)
+	if err != nil {
+		s.log.Warnw("Failed to load feature flags", "key", key, "error", err)
+		return s.defaults[key]
+	}
+	flag, ok := flags[key]
+	if !ok {
+		return false
+	}
+	return flag.Evaluate(s.subject(ctx, userID, flag.HasTeamTargets()))
+}
+
+// Evaluate returns every flag with whether it is enabled for a user
+func (s *Service) Evaluate(ctx context.Context, userID uuid.UUID) (map[string]bool, error) {
+	flags, err := s.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	needTeams := false
+	for _, flag := range flags {
+		needTeams = needTeams || flag.HasTeamTargets()
+	}
+	subject := s.subject(ctx, userID, needTeams)
+
+	result := make(map[string]bool, len(flags))
+	for key, flag := range flags {
+		result[key] = flag.Evaluate(subject)
+	}
+	return result, nil
+}
+
+// List returns every flag ordered by key
+func (s *Service) List(ctx context.Context) ([]*domain.Flag, error) {
+	flags, err := s.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	list := make([]*domain.Flag, 0, len(flags))
+	for _, flag := range flags {
+		list = append(list, flag)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Key < list[j].Key })
+	return list, nil
+}
+
+// Get returns a flag
+func (s *Service) Get(ctx context.Context, key string) (*domain.Flag, error) {
+	flags, err := s.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	flag, ok := flags[key]
+	if !ok {
+		return nil, domain.ErrFlagNotFound
+	}
+	return flag, nil
+}
+
+// Update creates or changes a flag. Changes apply on this instance at
+// once and on the others within the cache TTL.
+func (s *Service) Update(ctx context.Context, actor audit.Actor, key string, input FlagInput) (*domain.Flag, error) {
+	if err := domain.ValidateKey(key); err != nil {
+		return nil, err
+	}
+	flag, err := s.Get(ctx, key)
+	if errors.Is(err, domain.ErrFlagNotFound) {
+		flag = &domain.Flag{Key: key}
+	} else if err != nil {
+		return nil, err
+	}
+
+	updated := *flag
+	if input.Description != nil {
+		updated.Description = *input.Description
+	}
+	if input.Enabled != nil {
+		updated.Enabled = *input.Enabled
+	}
+	if input.Rollout != nil {
+		updated.Rollout = *input.Rollout
+	}
+	if err := updated.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := s.save(ctx, actor, &updated); err != nil {
+		return nil, err
+	}
+	s.record(ctx, actor, auditdomain.ActionFeatureFlagUpdated, key, map[string]interface{}{
+		"enabled": updated.Enabled,
+		"rollout": updated.Rollout,
+	})
+	return s.Get(ctx, key)
+}
+
+// Delete removes a flag and its targets. Built-in flags return to their
+// configured defaults.
+func (s *Service) Delete(ctx context.Context, actor audit.Actor, key string) error {
+	if err := s.repo.Delete(ctx, key); err != nil {
+		return err
+	}
+	s.invalidate()
+	s.record(ctx, actor, auditdomain.ActionFeatureFlagDeleted, key, nil)
+	return nil
+}
+
+// SetTarget turns a flag on or off for one user or team
+func (s *Service) SetTarget(ctx context.Context, actor audit.Actor, key string, targetType domain.TargetType, targetID uuid.UUID, enabled bool) (*domain.Flag, error) {
+	if !targetType.Valid() {
+		return nil, domain.ErrInvalidTargetType
+	}
+	flag, err := s.Get(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	// Built-in flags are stored before their first target
+	if flag.CreatedAt.IsZero() {
+		stored := *flag
+		if err := s.save(ctx, actor, &stored); err != nil {
+			return nil, err
+		}
+	}
+
+	err = s.repo.SaveTarget(ctx, &domain.Target{
+		FlagKey:   key,
+		Type:      targetType,
+		TargetID:  targetID,
+		Enabled:   enabled,
+		CreatedAt: time.Now(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.invalidate()
+	s.record(ctx, actor, auditdomain.ActionFeatureFlagUpdated, key, map[string]interface{}{
+		"target_type": targetType,
+		"target_id":   targetID.String(),
+		"enabled":     enabled,
+	})
+	return s.Get(ctx, key)
+}
+
+// RemoveTarget removes the override of a flag for one user or team
+func (s *Service) RemoveTarget(ctx context.Context, actor audit.Actor, key string, targetType domain.TargetType, targetID uuid.UUID) (*domain.Flag, error) {
+	if !targetType.Valid() {
+		return nil, domain.ErrInvalidTargetType
+	}
+	if err := s.repo.DeleteTarget(ctx, key, targetType, targetID); err != nil {
+		return nil, err
+	}
+	s.invalidate()
+	s.record(ctx, actor, auditdomain.ActionFeatureFlagUpdated, key, map[string]interface{}{
+		"target_type": targetType,
+		"target_id":   targetID.String(),
+		"removed":     true,
+	})
+	return s.Get(ctx, key)
+}
+
+func (s *Service) save(ctx context.Context, actor audit.Actor, flag *domain.Flag) error {
+	now := time.Now()
+	if flag.CreatedAt.IsZero() {
+		flag.CreatedAt = now
+	}
+	flag.UpdatedAt = now
+	if actor.UserID != uuid.Nil {
+		userID := actor.UserID
+		flag.UpdatedBy = &userID
+	}
+	if err := s.repo.Save(ctx, flag); err != nil {
+		return err
+	}
+	s.invalidate()
+	return nil
+}
+
+func (s *Service) record(ctx context.Context, actor audit.Actor, action, key string, changes map[string]interface{}) {
+	_ = s.audit.Record(ctx, actor, &auditdomain.AuditLog{
+		Action:       action,
+		ResourceType: auditdomain.ResourceFeatureFlag,
+		ResourceID:   key,
+		NewValue:     changes,
+	})
+}
+
+// subject describes a user for evaluation, with their teams when a flag
+// targets teams. Without the teams, team targets are skipped.
+func (s *Service) subject(ctx context.Context, userID uuid.UUID, withTeams bool) domain.Subject {
+	subject := domain.Subject{UserID: userID}
+	if !withTeams || userID == uuid.Nil {
+		return subject
+	}
+	teamIDs, err := s.users.TeamIDs(ctx, userID)
+	if err != nil {
+		s.log.Warnw("Failed to load teams for feature flags", "user_id", userID, "error", err)
+		return subject
+	}
+	subject.TeamIDs = teamIDs
+	return subject
+}
+
+// load returns the flags by key, reloading them once the TTL expired. The
+// returned flags are shared and must not be modified.
+func (s *Service) load(ctx context.Context) (map[string]*domain.Flag, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.flags != nil && time.Since(s.loadedAt) < s.ttl {
+		return s.flags, nil
+	}
+
+	stored, err := s.repo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	flags := make(map[string]*domain.Flag, len(stored)+len(s.defaults))
+	for key, enabled := range s.defaults {
+		description, _ := domain.Builtin(key)
+		flags[key] = &domain.Flag{Key: key, Description: description, Enabled: enabled}
+	}
+	for _, flag := range stored {
+		flags[flag.Key] = flag
+	}
+
+	s.flags = flags
+	s.loadedAt = time.Now()
+	return flags, nil
+}
+
+func (s *Service) invalidate() {
+	s.mu.Lock()
+	s.flags = nil
+	s.mu.Unlock()
+}
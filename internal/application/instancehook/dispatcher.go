@@ -0,0 +1,176 @@
+package instancehook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/jaydeep/go-n8n/configs"
+	"github.com/jaydeep/go-n8n/internal/application/execution"
+	domain "github.com/jaydeep/go-n8n/internal/domain/instancehook"
+	"github.com/jaydeep/go-n8n/pkg/logger"
+)
+
+const (
+	// deliveryBatchSize limits how many deliveries are sent per poll
+	deliveryBatchSize = 100
+	// pruneInterval bounds how often old deliveries are removed from the
+	// delivery log
+	pruneInterval = time.Hour
+)
+
+// Dispatcher sends due deliveries to their webhooks, retrying failed ones
+// with exponential backoff, and prunes the delivery log. Requests are
+// signed like execution callbacks, with the secret of the webhook.
+type Dispatcher struct {
+	webhooks   domain.Repository
+	deliveries domain.DeliveryRepository
+	client     *http.Client
+	cfg        configs.InstanceHookConfig
+	log        *logger.Logger
+}
+
+// NewDispatcher creates a new delivery dispatcher
+func NewDispatcher(webhooks domain.Repository, deliveries domain.DeliveryRepository, cfg configs.InstanceHookConfig, log *logger.Logger) *Dispatcher {
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 10 * time.Second
+	}
+	if cfg.MaxAttempts <= 0 {
+		cfg.MaxAttempts = 6
+	}
+	if cfg.InitialBackoff <= 0 {
+		cfg.InitialBackoff = 10 * time.Second
+	}
+	if cfg.MaxBackoff <= 0 {
+		cfg.MaxBackoff = 30 * time.Minute
+	}
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = 5 * time.Second
+	}
+	return &Dispatcher{
+		webhooks:   webhooks,
+		deliveries: deliveries,
+		client:     &http.Client{Timeout: cfg.Timeout},
+		cfg:        cfg,
+		log:        log,
+	}
+}
+
+// Start sends due deliveries until the context is cancelled
+func (d *Dispatcher) Start(ctx context.Context) {
+	ticker := time.NewTicker(d.cfg.PollInterval)
+	defer ticker.Stop()
+
+	var prunedAt time.Time
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			d.dispatchDue(ctx)
+			if d.cfg.DeliveryRetention > 0 && time.Since(prunedAt) > pruneInterval {
+				d.prune(ctx)
+				prunedAt = time.Now()
+			}
+		}
+	}
+}
+
+func (d *Dispatcher) dispatchDue(ctx context.Context) {
+	due, err := d.deliveries.FindDue(ctx, time.Now(), deliveryBatchSize)
+	if err != nil {
+		d.log.Errorw("Failed to load due instance webhook deliveries", "error", err)
+		return
+	}
+
+	for _, delivery := range due {
+		if ctx.Err() != nil {
+			return
+		}
+		d.deliver(ctx, delivery)
+	}
+}
+
+// deliver sends one delivery and records the outcome
+func (d *Dispatcher) deliver(ctx context.Context, delivery *domain.Delivery) {
+	w, err := d.webhooks.FindByID(ctx, delivery.WebhookID)
+	if errors.Is(err, domain.ErrWebhookNotFound) {
+		return
+	}
+	if err != nil {
+		d.log.Errorw("Failed to load instance webhook", "webhook_id", delivery.WebhookID, "error", err)
+		return
+	}
+	if !w.IsActive {
+		delivery.Cancel("instance webhook is disabled")
+		if err := d.deliveries.Update(ctx, delivery); err != nil {
+			d.log.Errorw("Failed to update instance webhook delivery", "delivery_id", delivery.ID, "error", err)
+		}
+		return
+	}
+
+	started := time.Now()
+	status, err := d.send(ctx, w, delivery)
+	if err == nil {
+		delivery.Delivered(status, time.Since(started))
+	} else {
+		delivery.Retry(status, time.Since(started), err, d.cfg.MaxAttempts, d.cfg.InitialBackoff, d.cfg.MaxBackoff)
+		d.log.Warnw("Instance webhook delivery failed", "delivery_id", delivery.ID, "webhook_id", w.ID,
+			"event", delivery.Event, "attempts", delivery.Attempts, "status", delivery.Status, "error", err)
+	}
+
+	if err := d.deliveries.Update(ctx, delivery); err != nil {
+		d.log.Errorw("Failed to update instance webhook delivery", "delivery_id", delivery.ID, "error", err)
+	}
+}
+
+// send POSTs a delivery and returns the response status, 0 when no
+// response was received
+func (d *Dispatcher) send(ctx context.Context, w *domain.Webhook, delivery *domain.Delivery) (int, error) {
+	body, err := json.Marshal(delivery.Payload)
+	if err != nil {
+		return 0, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	timestamp := time.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "go-n8n-webhook/1.0")
+	req.Header.Set(execution.HeaderEvent, delivery.Event)
+	req.Header.Set(execution.HeaderDelivery, delivery.ID.String())
+	req.Header.Set(execution.HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(execution.HeaderSignature, execution.Sign(w.Secret, timestamp, body))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("webhook URL responded with status %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+// prune removes finished deliveries older than the retention
+func (d *Dispatcher) prune(ctx context.Context) {
+	removed, err := d.deliveries.DeleteFinishedBefore(ctx, time.Now().Add(-d.cfg.DeliveryRetention))
+	if err != nil {
+		d.log.Errorw("Failed to prune instance webhook deliveries", "error", err)
+		return
+	}
+	if removed > 0 {
+		d.log.Infow("Pruned instance webhook deliveries", "count", removed)
+	}
+}
@@ -0,0 +1,375 @@
+// Package instancehook sends platform events, such as users being created
+// or executions failing, to the outgoing webhooks admins register.
+package instancehook
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jaydeep/go-n8n/internal/application/audit"
+	"github.com/jaydeep/go-n8n/internal/application/events"
+	auditdomain "github.com/jaydeep/go-n8n/internal/domain/audit"
+	"github.com/jaydeep/go-n8n/internal/domain/execution"
+	domain "github.com/jaydeep/go-n8n/internal/domain/instancehook"
+	"github.com/jaydeep/go-n8n/pkg/logger"
+)
+
+const (
+	// cacheTTL bounds how long an instance keeps sending to webhooks
+	// another instance changed
+	cacheTTL = 10 * time.Second
+	// maxDeliveryLimit bounds a page of the delivery log
+	maxDeliveryLimit = 200
+)
+
+// Types are the bus event types instance webhooks receive
+var Types = []events.Type{
+	events.ExecutionFinished,
+	events.WorkflowActivated,
+	events.WorkflowDeactivated,
+	events.SLABreached,
+	events.SLARecovered,
+	events.CredentialCreated,
+	events.CredentialUnhealthy,
+	events.CredentialRecovered,
+	events.UserCreated,
+	events.UserActivated,
+	events.UserDeactivated,
+}
+
+// failedStatuses are the execution statuses sent as execution.failed
+var failedStatuses = map[execution.ExecutionStatus]bool{
+	execution.ExecutionStatusError:   true,
+	execution.ExecutionStatusCrashed: true,
+	execution.ExecutionStatusTimeout: true,
+}
+
+// Input holds the fields of a webhook. Secret is generated when empty and
+// must otherwise be at least 16 characters.
+type Input struct {
+	Name        string   `json:"name"`
+	URL         string   `json:"url"`
+	Events      []string `json:"events"`
+	WorkflowIDs []string `json:"workflow_ids"`
+	Secret      string   `json:"secret"`
+}
+
+// UpdateInput holds the editable fields of a webhook. Nil fields are left
+// unchanged.
+type UpdateInput struct {
+	Name        *string   `json:"name"`
+	URL         *string   `json:"url"`
+	Events      *[]string `json:"events"`
+	WorkflowIDs *[]string `json:"workflow_ids"`
+	IsActive    *bool     `json:"is_active"`
+}
+
+// Payload is the body POSTed for an event
+type Payload struct {
+	// ID identifies the event; deliveries of one event to several
+	// webhooks share it
+	ID        uuid.UUID   `json:"id"`
+	Event     string      `json:"event"`
+	Timestamp time.Time   `json:"timestamp"`
+	ActorID   *uuid.UUID  `json:"actor_id,omitempty"`
+	Data      interface{} `json:"data"`
+}
+
+// Service manages instance webhooks and turns bus events into deliveries
+type Service struct {
+	repo       domain.Repository
+	deliveries domain.DeliveryRepository
+	audit      *audit.Service
+	log        *logger.Logger
+
+	mu       sync.Mutex
+	active   []*domain.Webhook
+	loadedAt time.Time
+}
+
+// NewService creates a new instance webhook service
+func NewService(repo domain.Repository, deliveries domain.DeliveryRepository, auditService *audit.Service, log *logger.Logger) *Service {
+	return &Service{
+		repo:       repo,
+		deliveries: deliveries,
+		audit:      auditService,
+		log:        log,
+	}
+}
+
+// List returns every webhook
+func (s *Service) List(ctx context.Context) ([]*domain.Webhook, error) {
+	return s.repo.List(ctx)
+}
+
+// Get returns a webhook
+func (s *Service) Get(ctx context.Context, id uuid.UUID) (*domain.Webhook, error) {
+	return s.repo.FindByID(ctx, id)
+}
+
+// Create registers a webhook. It returns the signing secret, which is only
+// available now.
+func (s *Service) Create(ctx context.Context, actor audit.Actor, input Input) (*domain.Webhook, string, error) {
+	w := &domain.Webhook{
+		Name:        input.Name,
+		URL:         input.URL,
+		Events:      input.Events,
+		WorkflowIDs: input.WorkflowIDs,
+		IsActive:    true,
+	}
+	if err := w.Validate(); err != nil {
+		return nil, "", err
+	}
+	secret := input.Secret
+	if secret == "" {
+		var err error
+		if secret, err = w.RotateSecret(); err != nil {
+			return nil, "", err
+		}
+	} else if err := w.SetSecret(secret); err != nil {
+		return nil, "", err
+	}
+	if actor.UserID != uuid.Nil {
+		userID := actor.UserID
+		w.CreatedBy = &userID
+	}
+
+	if err := s.repo.Create(ctx, w); err != nil {
+		return nil, "", err
+	}
+	s.record(ctx, actor, auditdomain.ActionInstanceWebhookCreated, w)
+	s.invalidate()
+	return w, secret, nil
+}
+
+// Update changes a webhook
+func (s *Service) Update(ctx context.Context, actor audit.Actor, id uuid.UUID, input UpdateInput) (*domain.Webhook, error) {
+	w, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if input.Name != nil {
+		w.Name = *input.Name
+	}
+	if input.URL != nil {
+		w.URL = *input.URL
+	}
+	if input.Events != nil {
+		w.Events = *input.Events
+	}
+	if input.WorkflowIDs != nil {
+		w.WorkflowIDs = *input.WorkflowIDs
+	}
+	if input.IsActive != nil {
+		w.IsActive = *input.IsActive
+	}
+	if err := w.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Update(ctx, w); err != nil {
+		return nil, err
+	}
+	s.record(ctx, actor, auditdomain.ActionInstanceWebhookUpdated, w)
+	s.invalidate()
+	return w, nil
+}
+
+// Delete removes a webhook and its delivery log
+func (s *Service) Delete(ctx context.Context, actor audit.Actor, id uuid.UUID) error {
+	w, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.Delete(ctx, id); err != nil {
+		return err
+	}
+	s.record(ctx, actor, auditdomain.ActionInstanceWebhookDeleted, w)
+	s.invalidate()
+	return nil
+}
+
+// RotateSecret replaces the signing secret of a webhook and returns it
+func (s *Service) RotateSecret(ctx context.Context, actor audit.Actor, id uuid.UUID) (*domain.Webhook, string, error) {
+	w, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, "", err
+	}
+	secret, err := w.RotateSecret()
+	if err != nil {
+		return nil, "", err
+	}
+	if err := s.repo.Update(ctx, w); err != nil {
+		return nil, "", err
+	}
+	s.record(ctx, actor, auditdomain.ActionInstanceWebhookRotated, w)
+	s.invalidate()
+	return w, secret, nil
+}
+
+// Ping queues a test delivery to a webhook, whatever its events
+func (s *Service) Ping(ctx context.Context, id uuid.UUID) (*domain.Delivery, error) {
+	w, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	payload := Payload{
+		ID:        uuid.New(),
+		Event:     domain.EventPing,
+		Timestamp: time.Now().UTC(),
+		Data:      map[string]interface{}{"webhook_id": w.ID},
+	}
+	d := domain.NewDelivery(w.ID, domain.EventPing, payload)
+	if err := s.deliveries.Create(ctx, d); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// Deliveries returns the latest deliveries of a webhook, optionally only
+// those with status
+func (s *Service) Deliveries(ctx context.Context, id uuid.UUID, status domain.DeliveryStatus, limit int) ([]*domain.Delivery, error) {
+	if _, err := s.repo.FindByID(ctx, id); err != nil {
+		return nil, err
+	}
+	if limit <= 0 || limit > maxDeliveryLimit {
+		limit = maxDeliveryLimit
+	}
+	return s.deliveries.ListByWebhook(ctx, id, status, limit)
+}
+
+// Redeliver sends a delivery of a webhook again
+func (s *Service) Redeliver(ctx context.Context, id, deliveryID uuid.UUID) (*domain.Delivery, error) {
+	d, err := s.deliveries.FindByID(ctx, deliveryID)
+	if err != nil {
+		return nil, err
+	}
+	if d.WebhookID != id {
+		return nil, domain.ErrDeliveryNotFound
+	}
+	d.Redeliver()
+	if err := s.deliveries.Update(ctx, d); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// Handle queues deliveries of a bus event to the webhooks subscribed to
+// it. Subscribe it to Types.
+func (s *Service) Handle(ctx context.Context, event events.Event) {
+	payloads := newPayloads(event)
+	if len(payloads) == 0 {
+		return
+	}
+
+	webhooks, err := s.activeWebhooks(ctx)
+	if err != nil {
+		s.log.Errorw("Failed to load instance webhooks", "type", event.EventType(), "error", err)
+		return
+	}
+	workflowID := eventWorkflowID(event)
+	for _, payload := range payloads {
+		for _, w := range webhooks {
+			if !w.Matches(payload.Event, workflowID) {
+				continue
+			}
+			if err := s.deliveries.Create(ctx, domain.NewDelivery(w.ID, payload.Event, payload)); err != nil {
+				s.log.Errorw("Failed to queue instance webhook delivery", "webhook_id", w.ID, "event", payload.Event, "error", err)
+			}
+		}
+	}
+}
+
+// activeWebhooks returns the active webhooks, cached for a few seconds as
+// every execution finishing is checked against them
+func (s *Service) activeWebhooks(ctx context.Context) ([]*domain.Webhook, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.active != nil && time.Since(s.loadedAt) < cacheTTL {
+		return s.active, nil
+	}
+	webhooks, err := s.repo.ListActive(ctx)
+	if err != nil {
+		return nil, err
+	}
+	s.active = webhooks
+	s.loadedAt = time.Now()
+	return webhooks, nil
+}
+
+func (s *Service) invalidate() {
+	s.mu.Lock()
+	s.active = nil
+	s.mu.Unlock()
+}
+
+func (s *Service) record(ctx context.Context, actor audit.Actor, action string, w *domain.Webhook) {
+	_ = s.audit.Record(ctx, actor, &auditdomain.AuditLog{
+		Action:       action,
+		ResourceType: auditdomain.ResourceInstanceWebhook,
+		ResourceID:   w.ID.String(),
+		NewValue:     audit.ToMap(w),
+	})
+}
+
+// newPayloads builds the payloads sent for a bus event. An execution
+// finishing in failure is sent both as execution.finished and
+// execution.failed.
+func newPayloads(event events.Event) []Payload {
+	payload := Payload{
+		ID:        uuid.New(),
+		Event:     string(event.EventType()),
+		Timestamp: time.Now().UTC(),
+		Data:      event,
+	}
+
+	var actor audit.Actor
+	switch e := event.(type) {
+	case events.Execution:
+		payload.Timestamp = e.Timestamp
+		payload.Data = e.Event
+		if failedStatuses[e.Status] {
+			failed := payload
+			failed.ID = uuid.New()
+			failed.Event = domain.EventExecutionFailed
+			return []Payload{payload, failed}
+		}
+		return []Payload{payload}
+	case events.Workflow:
+		payload.Timestamp = e.Timestamp
+		actor = e.Actor
+	case events.SLA:
+		payload.Timestamp = e.Timestamp
+	case events.User:
+		payload.Timestamp = e.Timestamp
+		actor = e.Actor
+	case events.Credential:
+		payload.Timestamp = e.Timestamp
+		actor = e.Actor
+	case events.CredentialHealth:
+		payload.Timestamp = e.Timestamp
+	default:
+		return nil
+	}
+	if actor.UserID != uuid.Nil {
+		actorID := actor.UserID
+		payload.ActorID = &actorID
+	}
+	return []Payload{payload}
+}
+
+// eventWorkflowID returns the workflow an event is about, or uuid.Nil
+func eventWorkflowID(event events.Event) uuid.UUID {
+	switch e := event.(type) {
+	case events.Execution:
+		return e.WorkflowID
+	case events.Workflow:
+		return e.WorkflowID
+	case events.SLA:
+		return e.WorkflowID
+	}
+	return uuid.Nil
+}
@@ -0,0 +1,138 @@
+package maintenance
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jaydeep/go-n8n/internal/application/audit"
+	"github.com/jaydeep/go-n8n/internal/application/settings"
+	settingsdomain "github.com/jaydeep/go-n8n/internal/domain/settings"
+	"github.com/jaydeep/go-n8n/pkg/logger"
+)
+
+// EventChanged is passed to the notify function when maintenance mode is
+// turned on or off or its message changes
+const EventChanged = "maintenance.changed"
+
+// cacheTTL bounds how long an instance keeps serving a stale state after
+// another instance changed it
+const cacheTTL = 5 * time.Second
+
+// State is the maintenance mode of the instance
+type State struct {
+	Enabled    bool          `json:"enabled"`
+	Message    string        `json:"message"`
+	RetryAfter time.Duration `json:"-"`
+	// RetryAfterSeconds is sent in the Retry-After header of rejected
+	// requests
+	RetryAfterSeconds int `json:"retry_after_seconds"`
+}
+
+// Input changes the maintenance mode. Nil fields are left unchanged;
+// RetryAfter is a duration such as "10m".
+type Input struct {
+	Enabled    bool    `json:"enabled"`
+	Message    *string `json:"message"`
+	RetryAfter *string `json:"retry_after"`
+}
+
+// Service tracks maintenance mode, during which the API is read-only and
+// the scheduler and webhook intake are paused. The mode is stored in the
+// instance settings so every instance follows it, and cached per instance
+// for a few seconds as it is checked on every request.
+type Service struct {
+	settings *settings.Service
+	notify   func(event string, state State)
+	log      *logger.Logger
+
+	mu       sync.Mutex
+	state    State
+	loadedAt time.Time
+}
+
+// NewService creates a new maintenance service. notify is called after
+// every change; it may be nil.
+func NewService(settingsService *settings.Service, notify func(event string, state State), log *logger.Logger) *Service {
+	if notify == nil {
+		notify = func(string, State) {}
+	}
+	return &Service{
+		settings: settingsService,
+		notify:   notify,
+		log:      log,
+	}
+}
+
+// State returns the current maintenance mode
+func (s *Service) State(ctx context.Context) State {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if time.Since(s.loadedAt) > cacheTTL {
+		s.state = s.load(ctx)
+		s.loadedAt = time.Now()
+	}
+	return s.state
+}
+
+// InMaintenance reports whether maintenance mode is on, with the message
+// and Retry-After of rejected requests
+func (s *Service) InMaintenance(ctx context.Context) (retryAfter time.Duration, message string, active bool) {
+	state := s.State(ctx)
+	return state.RetryAfter, state.Message, state.Enabled
+}
+
+// Paused reports whether background intake such as the scheduler is paused
+func (s *Service) Paused(ctx context.Context) bool {
+	return s.State(ctx).Enabled
+}
+
+// Set turns maintenance mode on or off
+func (s *Service) Set(ctx context.Context, actor audit.Actor, input Input) (State, error) {
+	values := map[string]json.RawMessage{
+		settingsdomain.KeyMaintenanceEnabled: rawJSON(input.Enabled),
+	}
+	if input.Message != nil {
+		values[settingsdomain.KeyMaintenanceMessage] = rawJSON(*input.Message)
+	}
+	if input.RetryAfter != nil {
+		values[settingsdomain.KeyMaintenanceRetryAfter] = rawJSON(*input.RetryAfter)
+	}
+	if err := s.settings.Update(ctx, actor, settingsdomain.ScopeInstance, uuid.Nil, values); err != nil {
+		return State{}, err
+	}
+
+	state := s.load(ctx)
+	s.mu.Lock()
+	s.state = state
+	s.loadedAt = time.Now()
+	s.mu.Unlock()
+
+	if state.Enabled {
+		s.log.Warnw("Maintenance mode enabled", "user_id", actor.UserID)
+	} else {
+		s.log.Infow("Maintenance mode disabled", "user_id", actor.UserID)
+	}
+	s.notify(EventChanged, state)
+	return state, nil
+}
+
+// load reads the mode from the settings. Settings that cannot be read take
+// their defaults, so a failing store leaves maintenance mode off.
+func (s *Service) load(ctx context.Context) State {
+	retryAfter := s.settings.Duration(ctx, settingsdomain.KeyMaintenanceRetryAfter)
+	return State{
+		Enabled:           s.settings.Bool(ctx, settingsdomain.KeyMaintenanceEnabled),
+		Message:           s.settings.String(ctx, settingsdomain.KeyMaintenanceMessage),
+		RetryAfter:        retryAfter,
+		RetryAfterSeconds: int(retryAfter.Round(time.Second) / time.Second),
+	}
+}
+
+func rawJSON(v interface{}) json.RawMessage {
+	raw, _ := json.Marshal(v)
+	return raw
+}
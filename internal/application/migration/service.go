@@ -0,0 +1,610 @@
+// Package migration copies workflows between instances, such as from
+// staging to production, remapping the IDs they reference on the way in.
+package migration
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jaydeep/go-n8n/internal/application/audit"
+	"github.com/jaydeep/go-n8n/internal/application/events"
+	workflowapp "github.com/jaydeep/go-n8n/internal/application/workflow"
+	auditlog "github.com/jaydeep/go-n8n/internal/domain/audit"
+	"github.com/jaydeep/go-n8n/internal/domain/credential"
+	"github.com/jaydeep/go-n8n/internal/domain/user"
+	domain "github.com/jaydeep/go-n8n/internal/domain/workflow"
+	"github.com/jaydeep/go-n8n/pkg/encryption"
+)
+
+// maxRenameAttempts bounds the numbered names tried for renamed workflows
+const maxRenameAttempts = 100
+
+// ExportInput selects the workflows to export
+type ExportInput struct {
+	WorkflowIDs []uuid.UUID `json:"workflow_ids"`
+}
+
+// PushInput selects the workflows to push to a configured target and how
+// the target imports them
+type PushInput struct {
+	Target      string      `json:"target"`
+	WorkflowIDs []uuid.UUID `json:"workflow_ids"`
+	// OwnerID is a user of the target
+	OwnerID    *uuid.UUID `json:"owner_id"`
+	OnConflict string     `json:"on_conflict"`
+	DryRun     bool       `json:"dry_run"`
+	Publish    bool       `json:"publish"`
+}
+
+// Target describes a configured migration target
+type Target struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
+
+// Service exports workflows into migration packages, imports packages
+// pushed by other instances and pushes packages to configured targets
+type Service struct {
+	workflows   domain.Repository
+	publisher   *workflowapp.Service
+	credentials credential.Repository
+	users       user.Repository
+	cipher      *encryption.Cipher
+	targets     []domain.MigrationTarget
+	audit       *audit.Service
+	events      events.Publisher
+}
+
+// NewService creates a new migration service. Without a cipher, credential
+// stubs matching no credential cannot be created and are reported missing.
+func NewService(
+	workflows domain.Repository,
+	publisher *workflowapp.Service,
+	credentials credential.Repository,
+	users user.Repository,
+	cipher *encryption.Cipher,
+	targets []domain.MigrationTarget,
+	auditService *audit.Service,
+	eventPublisher events.Publisher,
+) *Service {
+	return &Service{
+		workflows:   workflows,
+		publisher:   publisher,
+		credentials: credentials,
+		users:       users,
+		cipher:      cipher,
+		targets:     targets,
+		audit:       auditService,
+		events:      eventPublisher,
+	}
+}
+
+// Targets returns the configured targets
+func (s *Service) Targets() []Target {
+	targets := make([]Target, 0, len(s.targets))
+	for _, t := range s.targets {
+		targets = append(targets, Target{Name: t.Name(), URL: t.URL()})
+	}
+	return targets
+}
+
+// Export returns a package of the selected workflows and stubs of the
+// credentials they reference
+func (s *Service) Export(ctx context.Context, actor audit.Actor, input ExportInput) (*domain.MigrationPackage, error) {
+	pkg, err := s.buildPackage(ctx, input.WorkflowIDs)
+	if err != nil {
+		return nil, err
+	}
+	s.record(ctx, actor, auditlog.ActionWorkflowsExported, map[string]interface{}{
+		"workflow_ids": input.WorkflowIDs,
+	})
+	return pkg, nil
+}
+
+// Push exports the selected workflows and imports them into a target
+func (s *Service) Push(ctx context.Context, actor audit.Actor, input PushInput) (*domain.MigrationResult, error) {
+	var target domain.MigrationTarget
+	for _, t := range s.targets {
+		if t.Name() == input.Target {
+			target = t
+		}
+	}
+	if target == nil {
+		return nil, domain.ErrMigrationTargetNotFound
+	}
+	if _, err := conflictPolicy(input.OnConflict); err != nil {
+		return nil, err
+	}
+
+	pkg, err := s.buildPackage(ctx, input.WorkflowIDs)
+	if err != nil {
+		return nil, err
+	}
+	result, err := target.Import(ctx, &domain.MigrationRequest{
+		Package:    pkg,
+		OwnerID:    input.OwnerID,
+		OnConflict: input.OnConflict,
+		DryRun:     input.DryRun,
+		Publish:    input.Publish,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !input.DryRun {
+		s.record(ctx, actor, auditlog.ActionWorkflowsExported, map[string]interface{}{
+			"workflow_ids": input.WorkflowIDs,
+			"target":       target.Name(),
+		})
+	}
+	return result, nil
+}
+
+// buildPackage collects the workflows in the order of ids along with their
+// credential stubs and owner emails
+func (s *Service) buildPackage(ctx context.Context, ids []uuid.UUID) (*domain.MigrationPackage, error) {
+	if len(ids) == 0 {
+		return nil, domain.ErrMigrationWorkflowsMissing
+	}
+	found, err := s.workflows.FindByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[uuid.UUID]*domain.Workflow, len(found))
+	for _, w := range found {
+		byID[w.ID] = w
+	}
+
+	var credentials []*credential.Credential
+	seen := make(map[uuid.UUID]bool)
+	owners := make(map[uuid.UUID]string)
+	for _, id := range ids {
+		w, ok := byID[id]
+		if !ok {
+			return nil, domain.ErrWorkflowNotFound
+		}
+		owners[w.UserID] = ""
+		for _, n := range w.Nodes {
+			if n.CredentialID == nil || seen[*n.CredentialID] {
+				continue
+			}
+			seen[*n.CredentialID] = true
+			c, err := s.credentials.FindByID(ctx, *n.CredentialID)
+			if errors.Is(err, credential.ErrCredentialNotFound) {
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+			credentials = append(credentials, c)
+			owners[c.UserID] = ""
+		}
+	}
+	if err := s.ownerEmails(ctx, owners); err != nil {
+		return nil, err
+	}
+
+	pkg := &domain.MigrationPackage{
+		Version:     domain.MigrationPackageVersion,
+		ExportedAt:  time.Now().UTC(),
+		Workflows:   make([]domain.MigratedWorkflow, 0, len(ids)),
+		Credentials: make([]domain.MigratedCredential, 0, len(credentials)),
+	}
+	for _, id := range ids {
+		w := byID[id]
+		pkg.Workflows = append(pkg.Workflows, domain.MigratedWorkflow{
+			ID:          w.ID,
+			Name:        w.Name,
+			Description: w.Description,
+			OwnerEmail:  owners[w.UserID],
+			Nodes:       w.Nodes,
+			Connections: w.Connections,
+			Settings:    w.Settings,
+			Tags:        w.Tags,
+			Variables:   w.Variables,
+		})
+	}
+	for _, c := range credentials {
+		pkg.Credentials = append(pkg.Credentials, domain.MigratedCredential{
+			ID:         c.ID,
+			Name:       c.Name,
+			Type:       c.Type,
+			OwnerEmail: owners[c.UserID],
+			NodeTypes:  c.NodeTypes,
+		})
+	}
+	return pkg, nil
+}
+
+// ownerEmails fills in the emails of the users keyed by ID
+func (s *Service) ownerEmails(ctx context.Context, owners map[uuid.UUID]string) error {
+	ids := make([]uuid.UUID, 0, len(owners))
+	for id := range owners {
+		ids = append(ids, id)
+	}
+	users, err := s.users.FindByIDs(ctx, ids)
+	if err != nil {
+		return err
+	}
+	for _, u := range users {
+		owners[u.ID] = u.Email
+	}
+	return nil
+}
+
+// plannedWorkflow is a workflow of a package and what importing it does
+type plannedWorkflow struct {
+	workflow *domain.Workflow
+	outcome  *domain.MigrationOutcome
+}
+
+// Import stores the workflows of a package, matching its credential stubs
+// to credentials of the instance first. Workflows keep their ID unless they
+// are renamed; references to credentials and error workflows are remapped
+// to their IDs on this instance. Imported workflows are inactive drafts
+// unless published.
+func (s *Service) Import(ctx context.Context, actor audit.Actor, request *domain.MigrationRequest) (*domain.MigrationResult, error) {
+	if request.Package == nil || request.Package.Version != domain.MigrationPackageVersion {
+		return nil, domain.ErrUnsupportedMigration
+	}
+	policy, err := conflictPolicy(request.OnConflict)
+	if err != nil {
+		return nil, err
+	}
+	defaultOwner := actor.UserID
+	if request.OwnerID != nil {
+		defaultOwner = *request.OwnerID
+	}
+	if _, err := s.users.FindByID(ctx, defaultOwner); err != nil {
+		return nil, err
+	}
+	owners, err := s.ownerIDs(ctx, request.Package)
+	if err != nil {
+		return nil, err
+	}
+	ownerOf := func(email string) uuid.UUID {
+		if id, ok := owners[strings.ToLower(email)]; ok {
+			return id
+		}
+		return defaultOwner
+	}
+
+	result := &domain.MigrationResult{
+		DryRun:      request.DryRun,
+		Workflows:   []domain.MigrationOutcome{},
+		Credentials: []domain.MigrationOutcome{},
+		Warnings:    []string{},
+	}
+
+	credentialIDs := make(map[uuid.UUID]uuid.UUID)
+	var stubs []*credential.Credential
+	for _, mc := range request.Package.Credentials {
+		outcome, stub, err := s.planCredential(ctx, mc, ownerOf(mc.OwnerEmail))
+		if err != nil {
+			return nil, err
+		}
+		if outcome.ID != nil {
+			credentialIDs[mc.ID] = *outcome.ID
+		}
+		if stub != nil {
+			stubs = append(stubs, stub)
+		}
+		result.Credentials = append(result.Credentials, *outcome)
+	}
+
+	workflowIDs := make(map[uuid.UUID]uuid.UUID)
+	names := make(map[string]bool)
+	var planned []plannedWorkflow
+	for _, mw := range request.Package.Workflows {
+		p, err := s.planWorkflow(ctx, mw, ownerOf(mw.OwnerEmail), policy, names)
+		if err != nil {
+			return nil, err
+		}
+		if p.outcome.ID != nil {
+			workflowIDs[mw.ID] = *p.outcome.ID
+		}
+		planned = append(planned, p)
+	}
+
+	for _, p := range planned {
+		if p.workflow != nil {
+			if err := s.remap(ctx, p.workflow, credentialIDs, workflowIDs, result); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if request.DryRun {
+		for _, p := range planned {
+			result.Workflows = append(result.Workflows, *p.outcome)
+		}
+		return result, nil
+	}
+
+	credentialsCreated := make([]uuid.UUID, 0, len(stubs))
+	for _, c := range stubs {
+		if err := s.credentials.Create(ctx, c); err != nil {
+			return nil, err
+		}
+		credentialsCreated = append(credentialsCreated, c.ID)
+		s.events.Publish(events.NewCredentialEvent(events.CredentialCreated, c, actor))
+	}
+	stored := make([]uuid.UUID, 0, len(planned))
+	for _, p := range planned {
+		if p.workflow != nil {
+			if err := s.store(ctx, p); err != nil {
+				return nil, err
+			}
+			stored = append(stored, p.workflow.ID)
+			if request.Publish {
+				s.publish(ctx, actor, p.outcome)
+			}
+		}
+		result.Workflows = append(result.Workflows, *p.outcome)
+	}
+
+	s.record(ctx, actor, auditlog.ActionWorkflowsImported, map[string]interface{}{
+		"workflow_ids":   stored,
+		"credential_ids": credentialsCreated,
+	})
+	return result, nil
+}
+
+// planCredential matches a credential stub to a credential of the instance
+// with the same type, by ID and then by name for its owner. Stubs matching
+// none are created without secrets when the instance can encrypt them.
+func (s *Service) planCredential(ctx context.Context, mc domain.MigratedCredential, ownerID uuid.UUID) (*domain.MigrationOutcome, *credential.Credential, error) {
+	outcome := &domain.MigrationOutcome{SourceID: mc.ID, Name: mc.Name}
+	existing, err := s.credentials.FindByID(ctx, mc.ID)
+	idTaken := err == nil
+	if err == nil && existing.Type != mc.Type {
+		err = credential.ErrCredentialNotFound
+	}
+	if errors.Is(err, credential.ErrCredentialNotFound) {
+		existing, err = s.credentials.FindByName(ctx, ownerID, mc.Name)
+		if err == nil && existing.Type != mc.Type {
+			err = credential.ErrCredentialNotFound
+		}
+	}
+	if err == nil {
+		outcome.ID = &existing.ID
+		outcome.Action = domain.MigrationMatched
+		return outcome, nil, nil
+	}
+	if !errors.Is(err, credential.ErrCredentialNotFound) {
+		return nil, nil, err
+	}
+
+	if s.cipher == nil {
+		outcome.Action = domain.MigrationMissing
+		outcome.Reason = "no matching credential and no encryption key to create one"
+		return outcome, nil, nil
+	}
+	data, iv, err := s.cipher.Encrypt([]byte("{}"))
+	if err != nil {
+		return nil, nil, err
+	}
+	stub := &credential.Credential{
+		ID:        mc.ID,
+		Name:      mc.Name,
+		Type:      mc.Type,
+		UserID:    ownerID,
+		NodeTypes: mc.NodeTypes,
+		Data:      data,
+		IV:        iv,
+	}
+	if idTaken || stub.ID == uuid.Nil {
+		stub.ID = uuid.New()
+	}
+	outcome.ID = &stub.ID
+	outcome.Action = domain.MigrationCreated
+	outcome.Reason = "created without secrets; enter them before running the workflows"
+	return outcome, stub, nil
+}
+
+// planWorkflow decides how to import a workflow of a package, matching it
+// to an existing workflow by ID and then by name for its owner. names holds
+// the owner and name of the workflows planned so far.
+func (s *Service) planWorkflow(ctx context.Context, mw domain.MigratedWorkflow, ownerID uuid.UUID, policy string, names map[string]bool) (plannedWorkflow, error) {
+	outcome := &domain.MigrationOutcome{SourceID: mw.ID, Name: mw.Name}
+	w := &domain.Workflow{
+		ID:          mw.ID,
+		Name:        mw.Name,
+		Description: mw.Description,
+		UserID:      ownerID,
+		Nodes:       append([]domain.Node(nil), mw.Nodes...),
+		Connections: mw.Connections,
+		Settings:    mw.Settings,
+		Tags:        mw.Tags,
+		Variables:   mw.Variables,
+		Version:     1,
+	}
+	invalid := w.Validate()
+	if invalid == nil {
+		invalid = s.publisher.CheckQueue(w)
+	}
+	if invalid != nil {
+		outcome.Action = domain.MigrationSkipped
+		outcome.Reason = invalid.Error()
+		return plannedWorkflow{outcome: outcome}, nil
+	}
+
+	existing, err := s.workflows.FindByID(ctx, mw.ID)
+	if errors.Is(err, domain.ErrWorkflowNotFound) {
+		existing, err = s.workflows.FindByName(ctx, ownerID, mw.Name)
+	}
+	if err != nil && !errors.Is(err, domain.ErrWorkflowNotFound) {
+		return plannedWorkflow{}, err
+	}
+	// Workflows of the package sharing an owner and name are renamed
+	taken := existing != nil || names[nameKey(ownerID, mw.Name)]
+
+	switch {
+	case !taken:
+		outcome.Action = domain.MigrationCreated
+		if w.ID == uuid.Nil {
+			w.ID = uuid.New()
+		}
+	case existing != nil && policy == domain.MigrationSkip:
+		outcome.ID = &existing.ID
+		outcome.Action = domain.MigrationSkipped
+		outcome.Reason = "workflow exists"
+		return plannedWorkflow{outcome: outcome}, nil
+	case existing != nil && policy == domain.MigrationOverwrite:
+		outcome.Action = domain.MigrationUpdated
+		existing.BeginDraft()
+		existing.Name = w.Name
+		existing.Description = w.Description
+		existing.Nodes = w.Nodes
+		existing.Connections = w.Connections
+		existing.Settings = w.Settings
+		existing.Tags = w.Tags
+		existing.Variables = w.Variables
+		w = existing
+	default:
+		name, err := s.freeName(ctx, ownerID, mw.Name, names)
+		if err != nil {
+			return plannedWorkflow{}, err
+		}
+		outcome.Action = domain.MigrationRenamed
+		w.ID = uuid.New()
+		w.Name = name
+	}
+	names[nameKey(w.UserID, w.Name)] = true
+	outcome.ID = &w.ID
+	outcome.Name = w.Name
+	return plannedWorkflow{workflow: w, outcome: outcome}, nil
+}
+
+// freeName returns the first of name (2), name (3) and so on that no
+// workflow of the owner has
+func (s *Service) freeName(ctx context.Context, ownerID uuid.UUID, name string, names map[string]bool) (string, error) {
+	for i := 2; i < maxRenameAttempts; i++ {
+		candidate := fmt.Sprintf("%s (%d)", name, i)
+		if names[nameKey(ownerID, candidate)] {
+			continue
+		}
+		_, err := s.workflows.FindByName(ctx, ownerID, candidate)
+		if errors.Is(err, domain.ErrWorkflowNotFound) {
+			return candidate, nil
+		}
+		if err != nil {
+			return "", err
+		}
+	}
+	return fmt.Sprintf("%s (%s)", name, uuid.NewString()[:8]), nil
+}
+
+func nameKey(ownerID uuid.UUID, name string) string {
+	return ownerID.String() + "/" + name
+}
+
+// remap points the credential and error workflow references of w at their
+// IDs on this instance. References to neither imported nor existing
+// resources are removed with a warning.
+func (s *Service) remap(ctx context.Context, w *domain.Workflow, credentialIDs, workflowIDs map[uuid.UUID]uuid.UUID, result *domain.MigrationResult) error {
+	for i, n := range w.Nodes {
+		if n.CredentialID == nil {
+			continue
+		}
+		if id, ok := credentialIDs[*n.CredentialID]; ok {
+			w.Nodes[i].CredentialID = &id
+			continue
+		}
+		_, err := s.credentials.FindByID(ctx, *n.CredentialID)
+		if errors.Is(err, credential.ErrCredentialNotFound) {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("workflow %q: node %q: credential %s not found, reference removed", w.Name, n.Name, *n.CredentialID))
+			w.Nodes[i].CredentialID = nil
+			continue
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	if ref := w.Settings.ErrorWorkflow; ref != nil {
+		if id, ok := workflowIDs[*ref]; ok {
+			w.Settings.ErrorWorkflow = &id
+			return nil
+		}
+		_, err := s.workflows.FindByID(ctx, *ref)
+		if errors.Is(err, domain.ErrWorkflowNotFound) {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("workflow %q: error workflow %s not found, reference removed", w.Name, *ref))
+			w.Settings.ErrorWorkflow = nil
+			return nil
+		}
+		return err
+	}
+	return nil
+}
+
+// store creates or updates a planned workflow
+func (s *Service) store(ctx context.Context, p plannedWorkflow) error {
+	if p.outcome.Action == domain.MigrationUpdated {
+		return s.workflows.Update(ctx, p.workflow)
+	}
+	return s.workflows.Create(ctx, p.workflow)
+}
+
+// publish publishes an imported workflow, recording the outcome
+func (s *Service) publish(ctx context.Context, actor audit.Actor, outcome *domain.MigrationOutcome) {
+	published, err := s.publisher.Publish(ctx, *outcome.ID, actor)
+	switch {
+	case err != nil:
+		outcome.PublishError = err.Error()
+	case published.ChangeRequest != nil:
+		outcome.ChangeRequestID = &published.ChangeRequest.ID
+	default:
+		outcome.PublishedVersion = &published.Version.Version
+	}
+}
+
+// ownerIDs returns the IDs of the users of the instance owning the
+// workflows and credentials of a package, by lowercase email
+func (s *Service) ownerIDs(ctx context.Context, pkg *domain.MigrationPackage) (map[string]uuid.UUID, error) {
+	ids := make(map[string]uuid.UUID)
+	var emails []string
+	for _, w := range pkg.Workflows {
+		if w.OwnerEmail != "" {
+			emails = append(emails, w.OwnerEmail)
+		}
+	}
+	for _, c := range pkg.Credentials {
+		if c.OwnerEmail != "" {
+			emails = append(emails, c.OwnerEmail)
+		}
+	}
+	if len(emails) == 0 {
+		return ids, nil
+	}
+	users, err := s.users.FindByEmails(ctx, emails)
+	if err != nil {
+		return nil, err
+	}
+	for _, u := range users {
+		ids[strings.ToLower(u.Email)] = u.ID
+	}
+	return ids, nil
+}
+
+// conflictPolicy returns the policy, skip when empty
+func conflictPolicy(policy string) (string, error) {
+	switch policy {
+	case "":
+		return domain.MigrationSkip, nil
+	case domain.MigrationSkip, domain.MigrationOverwrite, domain.MigrationRename:
+		return policy, nil
+	}
+	return "", domain.ErrInvalidMigrationConflict
+}
+
+// record logs an export or import
+func (s *Service) record(ctx context.Context, actor audit.Actor, action string, details map[string]interface{}) {
+	s.audit.Record(ctx, actor, &auditlog.AuditLog{
+		Action:       action,
+		ResourceType: auditlog.ResourceWorkflow,
+		NewValue:     details,
+	})
+}
@@ -0,0 +1,274 @@
+package schedule
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jaydeep/go-n8n/configs"
+	"github.com/jaydeep/go-n8n/internal/application/workflow"
+	"github.com/jaydeep/go-n8n/internal/domain/execution"
+	domain "github.com/jaydeep/go-n8n/internal/domain/schedule"
+	"github.com/jaydeep/go-n8n/pkg/correlation"
+	"github.com/jaydeep/go-n8n/pkg/logger"
+	"github.com/jaydeep/go-n8n/pkg/queue"
+)
+
+// dueBatchSize limits how many schedules are loaded per poll
+const dueBatchSize = 500
+
+// system is the caller used by the scheduler itself
+var system = Caller{Admin: true}
+
+// Resumer resumes executions whose wait has come to its end
+type Resumer interface {
+	ResumeWait(ctx context.Context, wait *execution.WaitingExecution) error
+}
+
+// Pauser reports whether the scheduler is paused, such as while the
+// instance is in maintenance mode
+type Pauser interface {
+	Paused(ctx context.Context) bool
+}
+
+// Scheduler starts executions of active schedules. Each poll loads the
+// schedules due before the next poll and dispatches every one at its own
+// time, so the jitter and spread offsets are honored to the millisecond
+// instead of being rounded to the poll interval. Waiting executions are
+// resumed at their time the same way. Runs and resumes are claimed in the
+// database, so several API instances may run a scheduler safely. While
+// paused, due runs and resumes are held back until the pause ends.
+type Scheduler struct {
+	service    *Service
+	schedules  domain.Repository
+	workflows  *workflow.Service
+	executions execution.Repository
+	waits      execution.WaitRepository
+	resumer    Resumer
+	events     execution.EventPublisher
+	queue      queue.Queue
+	queueName  string
+	pauser     Pauser
+	cfg        configs.SchedulerConfig
+	log        *logger.Logger
+
+	mu      sync.Mutex
+	pending map[uuid.UUID]bool
+	armed   sync.WaitGroup
+}
+
+// NewScheduler creates a new scheduler. pauser may be nil.
+func NewScheduler(service *Service, schedules domain.Repository, workflows *workflow.Service, executions execution.Repository, waits execution.WaitRepository, resumer Resumer, events execution.EventPublisher, q queue.Queue, queueName string, pauser Pauser, cfg configs.SchedulerConfig, log *logger.Logger) *Scheduler {
+	return &Scheduler{
+		service:    service,
+		schedules:  schedules,
+		workflows:  workflows,
+		executions: executions,
+		waits:      waits,
+		resumer:    resumer,
+		events:     events,
+		queue:      q,
+		queueName:  queueName,
+		pauser:     pauser,
+		cfg:        cfg,
+		log:        log,
+		pending:    make(map[uuid.UUID]bool),
+	}
+}
+
+// Start runs the scheduler until the context is cancelled. It returns once
+// the runs being fired have been enqueued; runs not yet due are left to the
+// next start.
+func (s *Scheduler) Start(ctx context.Context) {
+	defer s.armed.Wait()
+
+	interval := s.cfg.CheckInterval
+	if interval <= 0 {
+		interval = time.Minute
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	s.poll(ctx, interval)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.poll(ctx, interval)
+		}
+	}
+}
+
+// poll arms a timer for every schedule and wait due before the next poll
+func (s *Scheduler) poll(ctx context.Context, interval time.Duration) {
+	if s.paused(ctx) {
+		return
+	}
+	s.pollWaits(ctx, interval)
+
+	due, err := s.schedules.FindDue(ctx, time.Now().Add(interval), dueBatchSize)
+	if err != nil {
+		s.log.Errorw("Failed to load due schedules", "error", err)
+		return
+	}
+
+	for _, sched := range due {
+		if !s.arm(sched.ID) {
+			continue
+		}
+		s.armed.Add(1)
+		go s.dispatchAt(ctx, sched)
+	}
+}
+
+// pollWaits arms a timer for every wait due before the next poll
+func (s *Scheduler) pollWaits(ctx context.Context, interval time.Duration) {
+	due, err := s.waits.FindDue(ctx, time.Now().Add(interval), dueBatchSize)
+	if err != nil {
+		s.log.Errorw("Failed to load due waits", "error", err)
+		return
+	}
+
+	for _, wait := range due {
+		if !s.arm(wait.ExecutionID) {
+			continue
+		}
+		s.armed.Add(1)
+		go s.resumeAt(ctx, wait)
+	}
+}
+
+// arm reports whether no timer is armed for id yet, marking it armed
+func (s *Scheduler) arm(id uuid.UUID) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.pending[id] {
+		return false
+	}
+	s.pending[id] = true
+	return true
+}
+
+// resumeAt waits for the time a wait ends and resumes its execution
+func (s *Scheduler) resumeAt(ctx context.Context, wait *execution.WaitingExecution) {
+	defer func() {
+		s.mu.Lock()
+		delete(s.pending, wait.ExecutionID)
+		s.mu.Unlock()
+		s.armed.Done()
+	}()
+
+	timer := time.NewTimer(time.Until(*wait.ResumeAt))
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return
+	case <-timer.C:
+	}
+	if s.paused(ctx) {
+		return
+	}
+
+	// Executions resumed by request meanwhile are not claimed again
+	err := s.resumer.ResumeWait(context.WithoutCancel(ctx), wait)
+	if err != nil && !errors.Is(err, execution.ErrExecutionNotWaiting) {
+		s.log.Errorw("Failed to resume execution", "execution_id", wait.ExecutionID, "error", err)
+	}
+}
+
+// dispatchAt waits for the schedule's dispatch time and fires it
+func (s *Scheduler) dispatchAt(ctx context.Context, sched *domain.Schedule) {
+	defer func() {
+		s.mu.Lock()
+		delete(s.pending, sched.ID)
+		s.mu.Unlock()
+		s.armed.Done()
+	}()
+
+	timer := time.NewTimer(time.Until(*sched.NextRunAt))
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return
+	case <-timer.C:
+	}
+
+	// Runs armed before a pause are left for the first poll after it
+	if s.paused(ctx) {
+		return
+	}
+
+	// A claimed run is completed even when shutdown starts meanwhile
+	if err := s.fire(context.WithoutCancel(ctx), sched); err != nil {
+		s.log.Errorw("Failed to run schedule", "schedule_id", sched.ID, "workflow_id", sched.WorkflowID, "error", err)
+	}
+}
+
+// paused reports whether the scheduler is paused
+func (s *Scheduler) paused(ctx context.Context) bool {
+	return s.pauser != nil && s.pauser.Paused(ctx)
+}
+
+// fire claims the run, moves the schedule to its next run and enqueues an
+// execution of the published workflow
+func (s *Scheduler) fire(ctx context.Context, sched *domain.Schedule) error {
+	expected := *sched.NextRunAt
+	now := time.Now()
+
+	evaluator, err := s.service.Evaluator(ctx, system, sched)
+	if err != nil {
+		return err
+	}
+	// Missed runs are not caught up; the schedule continues from now
+	sched.Advance(evaluator, now, s.cfg.SpreadWindow)
+
+	claimed, err := s.schedules.Claim(ctx, sched.ID, expected, now, sched.NextRunAt)
+	if err != nil || !claimed {
+		return err
+	}
+
+	w, err := s.workflows.Published(ctx, sched.WorkflowID)
+	if err != nil {
+		return err
+	}
+	if !w.IsActive {
+		s.log.Infow("Skipping schedule of inactive workflow", "schedule_id", sched.ID, "workflow_id", w.ID)
+		return nil
+	}
+
+	exec := &execution.Execution{
+		WorkflowID:      w.ID,
+		WorkflowVersion: *w.PublishedVersion,
+		Status:          execution.ExecutionStatusWaiting,
+		Mode:            execution.ExecutionModeSchedule,
+		StartedAt:       now,
+		CorrelationID:   correlation.New(),
+		InputData: map[string]interface{}{
+			"schedule_id":  sched.ID.String(),
+			"scheduled_at": expected,
+			"timezone":     sched.Timezone,
+		},
+	}
+	if err := s.executions.Create(ctx, exec); err != nil {
+		return err
+	}
+
+	err = s.queue.Enqueue(ctx, &queue.Job{
+		Queue:         w.Settings.QueueOr(s.queueName),
+		Type:          string(exec.Mode),
+		Region:        w.Settings.Region,
+		Team:          w.QueueTeam(),
+		ExecutionID:   exec.ID.String(),
+		WorkflowID:    w.ID.String(),
+		CorrelationID: exec.CorrelationID,
+	})
+	if err != nil {
+		return err
+	}
+	s.events.Publish(execution.NewEvent(execution.EventExecutionQueued, exec))
+	return nil
+}
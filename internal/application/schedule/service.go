@@ -0,0 +1,285 @@
+package schedule
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jaydeep/go-n8n/configs"
+	domain "github.com/jaydeep/go-n8n/internal/domain/schedule"
+	"github.com/jaydeep/go-n8n/internal/domain/user"
+	"github.com/jaydeep/go-n8n/internal/domain/workflow"
+	"github.com/jaydeep/go-n8n/pkg/logger"
+)
+
+// Caller identifies who is using the service. Admins may access every
+// team; other users only the teams they belong to.
+type Caller struct {
+	UserID uuid.UUID
+	Admin  bool
+}
+
+// Input holds the user-editable fields of a schedule
+type Input struct {
+	CronExpression string
+	Recurrence     *domain.Recurrence
+	Timezone       string
+	JitterSeconds  int
+}
+
+// Service manages schedules and team holiday calendars
+type Service struct {
+	schedules domain.Repository
+	calendars domain.HolidayCalendarRepository
+	workflows workflow.Repository
+	users     user.Repository
+	cfg       configs.SchedulerConfig
+	log       *logger.Logger
+}
+
+// NewService creates a new schedule service
+func NewService(schedules domain.Repository, calendars domain.HolidayCalendarRepository, workflows workflow.Repository, users user.Repository, cfg configs.SchedulerConfig, log *logger.Logger) *Service {
+	return &Service{
+		schedules: schedules,
+		calendars: calendars,
+		workflows: workflows,
+		users:     users,
+		cfg:       cfg,
+		log:       log,
+	}
+}
+
+// List returns the schedules the caller may see
+func (s *Service) List(ctx context.Context, caller Caller) ([]*domain.Schedule, error) {
+	if caller.Admin {
+		return s.schedules.List(ctx, nil)
+	}
+	return s.schedules.List(ctx, &caller.UserID)
+}
+
+// Get returns a schedule of a workflow the caller may access
+func (s *Service) Get(ctx context.Context, caller Caller, id uuid.UUID) (*domain.Schedule, error) {
+	sched, err := s.schedules.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.authorizeWorkflow(ctx, caller, sched.WorkflowID); err != nil {
+		return nil, err
+	}
+	return sched, nil
+}
+
+// Create adds an active schedule to a workflow
+func (s *Service) Create(ctx context.Context, caller Caller, workflowID uuid.UUID, input Input) (*domain.Schedule, error) {
+	if err := s.authorizeWorkflow(ctx, caller, workflowID); err != nil {
+		return nil, err
+	}
+
+	sched := &domain.Schedule{WorkflowID: workflowID, IsActive: true}
+	if err := s.apply(ctx, caller, sched, input); err != nil {
+		return nil, err
+	}
+	if err := s.schedules.Create(ctx, sched); err != nil {
+		return nil, err
+	}
+	s.log.Infow("Schedule created", "schedule_id", sched.ID, "workflow_id", workflowID, "next_run_at", sched.NextRunAt)
+	return sched, nil
+}
+
+// Update replaces the timing of a schedule
+func (s *Service) Update(ctx context.Context, caller Caller, id uuid.UUID, input Input) (*domain.Schedule, error) {
+	sched, err := s.Get(ctx, caller, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.apply(ctx, caller, sched, input); err != nil {
+		return nil, err
+	}
+	if err := s.schedules.Update(ctx, sched); err != nil {
+		return nil, err
+	}
+	return sched, nil
+}
+
+// SetActive enables or pauses a schedule
+func (s *Service) SetActive(ctx context.Context, caller Caller, id uuid.UUID, active bool) (*domain.Schedule, error) {
+	sched, err := s.Get(ctx, caller, id)
+	if err != nil {
+		return nil, err
+	}
+	sched.IsActive = active
+	input := Input{
+		CronExpression: sched.CronExpression,
+		Recurrence:     sched.Recurrence,
+		Timezone:       sched.Timezone,
+		JitterSeconds:  sched.JitterSeconds,
+	}
+	if err := s.apply(ctx, caller, sched, input); err != nil {
+		return nil, err
+	}
+	if err := s.schedules.Update(ctx, sched); err != nil {
+		return nil, err
+	}
+	return sched, nil
+}
+
+// Delete removes a schedule
+func (s *Service) Delete(ctx context.Context, caller Caller, id uuid.UUID) error {
+	if _, err := s.Get(ctx, caller, id); err != nil {
+		return err
+	}
+	return s.schedules.Delete(ctx, id)
+}
+
+// apply validates input, copies it to the schedule and computes its next run
+func (s *Service) apply(ctx context.Context, caller Caller, sched *domain.Schedule, input Input) error {
+	if input.JitterSeconds < 0 || (s.cfg.MaxJitter > 0 && time.Duration(input.JitterSeconds)*time.Second > s.cfg.MaxJitter) {
+		return fmt.Errorf("%w: jitter_seconds must be between 0 and %d", domain.ErrInvalidJitter, int(s.cfg.MaxJitter/time.Second))
+	}
+	if input.Timezone == "" {
+		input.Timezone = s.cfg.Location
+	}
+
+	sched.CronExpression = input.CronExpression
+	sched.Recurrence = input.Recurrence
+	sched.Timezone = input.Timezone
+	sched.JitterSeconds = input.JitterSeconds
+
+	evaluator, err := s.Evaluator(ctx, caller, sched)
+	if err != nil {
+		return err
+	}
+	if sched.IsActive {
+		sched.Advance(evaluator, time.Now(), s.cfg.SpreadWindow)
+	} else {
+		sched.NextRunAt = nil
+	}
+	return nil
+}
+
+// authorizeWorkflow checks that the caller owns the workflow
+func (s *Service) authorizeWorkflow(ctx context.Context, caller Caller, workflowID uuid.UUID) error {
+	w, err := s.workflows.FindByID(ctx, workflowID)
+	if err != nil {
+		return err
+	}
+	if !caller.Admin && w.UserID != caller.UserID {
+		return domain.ErrWorkflowAccessDenied
+	}
+	return nil
+}
+
+// Evaluator compiles a schedule, loading the holiday calendar its
+// recurrence references
+func (s *Service) Evaluator(ctx context.Context, caller Caller, sched *domain.Schedule) (domain.Evaluator, error) {
+	var holidays []string
+	if sched.Recurrence != nil && sched.Recurrence.HolidayCalendarID != nil {
+		calendar, err := s.Calendar(ctx, caller, *sched.Recurrence.HolidayCalendarID)
+		if err != nil {
+			return nil, err
+		}
+		holidays = calendar.Dates
+	}
+	return sched.Evaluator(holidays)
+}
+
+// Calendars lists the holiday calendars of a team
+func (s *Service) Calendars(ctx context.Context, caller Caller, teamID uuid.UUID) ([]*domain.HolidayCalendar, error) {
+	if err := s.authorize(ctx, caller, teamID, false); err != nil {
+		return nil, err
+	}
+	return s.calendars.FindByTeam(ctx, teamID)
+}
+
+// Calendar returns a holiday calendar of a team the caller belongs to
+func (s *Service) Calendar(ctx context.Context, caller Caller, id uuid.UUID) (*domain.HolidayCalendar, error) {
+	calendar, err := s.calendars.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.authorize(ctx, caller, calendar.TeamID, false); err != nil {
+		return nil, err
+	}
+	return calendar, nil
+}
+
+// CreateCalendar adds a holiday calendar to a team
+func (s *Service) CreateCalendar(ctx context.Context, caller Caller, calendar *domain.HolidayCalendar) error {
+	if err := s.authorize(ctx, caller, calendar.TeamID, true); err != nil {
+		return err
+	}
+	if err := calendar.Validate(); err != nil {
+		return err
+	}
+	calendar.CreatedBy = caller.UserID
+	if err := s.calendars.Create(ctx, calendar); err != nil {
+		return err
+	}
+	s.log.Infow("Holiday calendar created", "calendar_id", calendar.ID, "team_id", calendar.TeamID)
+	return nil
+}
+
+// UpdateCalendar replaces the name and dates of a holiday calendar
+func (s *Service) UpdateCalendar(ctx context.Context, caller Caller, teamID, id uuid.UUID, name string, dates []string) (*domain.HolidayCalendar, error) {
+	calendar, err := s.teamCalendar(ctx, caller, teamID, id)
+	if err != nil {
+		return nil, err
+	}
+	calendar.Name = name
+	calendar.Dates = dates
+	if err := calendar.Validate(); err != nil {
+		return nil, err
+	}
+	if err := s.calendars.Update(ctx, calendar); err != nil {
+		return nil, err
+	}
+	return calendar, nil
+}
+
+// DeleteCalendar removes a holiday calendar
+func (s *Service) DeleteCalendar(ctx context.Context, caller Caller, teamID, id uuid.UUID) error {
+	if _, err := s.teamCalendar(ctx, caller, teamID, id); err != nil {
+		return err
+	}
+	if err := s.calendars.Delete(ctx, id); err != nil {
+		return err
+	}
+	s.log.Infow("Holiday calendar deleted", "calendar_id", id, "team_id", teamID)
+	return nil
+}
+
+// teamCalendar loads a calendar the caller may manage
+func (s *Service) teamCalendar(ctx context.Context, caller Caller, teamID, id uuid.UUID) (*domain.HolidayCalendar, error) {
+	calendar, err := s.calendars.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if calendar.TeamID != teamID {
+		return nil, domain.ErrHolidayCalendarNotFound
+	}
+	if err := s.authorize(ctx, caller, teamID, true); err != nil {
+		return nil, err
+	}
+	return calendar, nil
+}
+
+// authorize checks that the caller belongs to the team and, to manage
+// calendars, is a team admin or owner
+func (s *Service) authorize(ctx context.Context, caller Caller, teamID uuid.UUID, manage bool) error {
+	if caller.Admin {
+		return nil
+	}
+	member, err := s.users.FindTeamMember(ctx, teamID, caller.UserID)
+	if errors.Is(err, user.ErrTeamMemberNotFound) {
+		return domain.ErrTeamAccessDenied
+	}
+	if err != nil {
+		return err
+	}
+	if manage && member.Role != user.TeamRoleAdmin && member.Role != user.TeamRoleOwner {
+		return domain.ErrTeamAccessDenied
+	}
+	return nil
+}
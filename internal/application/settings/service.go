@@ -0,0 +1,310 @@
+package settings
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jaydeep/go-n8n/internal/application/audit"
+	auditdomain "github.com/jaydeep/go-n8n/internal/domain/audit"
+	domain "github.com/jaydeep/go-n8n/internal/domain/settings"
+	"github.com/jaydeep/go-n8n/pkg/logger"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	cacheKeyPrefix = "n8n:settings:"
+	// cacheLoadedField marks a cached scope so an empty scope is cached too
+	cacheLoadedField = "_loaded"
+	// secretMask replaces secret values in API responses and audit logs
+	secretMask = "********"
+)
+
+// Entry is a setting with its effective value
+type Entry struct {
+	Key         string      `json:"key"`
+	Type        domain.Type `json:"type"`
+	Value       interface{} `json:"value"`
+	Default     interface{} `json:"default"`
+	IsDefault   bool        `json:"is_default"`
+	Secret      bool        `json:"secret,omitempty"`
+	Description string      `json:"description"`
+}
+
+// Service reads and updates instance and user settings. Stored values are
+// cached in Redis per scope and invalidated on update.
+type Service struct {
+	repo  domain.Repository
+	cache *redis.Client
+	ttl   time.Duration
+	audit *audit.Service
+	log   *logger.Logger
+}
+
+// NewService creates a new settings service
+func NewService(repo domain.Repository, cache *redis.Client, ttl time.Duration, auditService *audit.Service, log *logger.Logger) *Service {
+	return &Service{
+		repo:  repo,
+		cache: cache,
+		ttl:   ttl,
+		audit: auditService,
+		log:   log,
+	}
+}
+
+// List returns every setting of a scope with its effective value. Secret
+// values are masked.
+func (s *Service) List(ctx context.Context, scope domain.Scope, scopeID uuid.UUID) ([]Entry, error) {
+	if !scope.Valid() {
+		return nil, domain.ErrInvalidScope
+	}
+	stored, err := s.load(ctx, scope, scopeID)
+	if err != nil {
+		return nil, err
+	}
+
+	defs := domain.Definitions(scope)
+	entries := make([]Entry, 0, len(defs))
+	for _, def := range defs {
+		defaultValue := displayValue(decodeOrNil(def, def.DefaultValue()))
+		entry := Entry{
+			Key:         def.Key,
+			Type:        def.Type,
+			Value:       defaultValue,
+			Default:     defaultValue,
+			IsDefault:   true,
+			Secret:      def.Secret,
+			Description: def.Description,
+		}
+		if raw, ok := stored[def.Key]; ok {
+			if v, err := def.Decode(raw); err == nil {
+				entry.Value = displayValue(v)
+				entry.IsDefault = false
+			} else {
+				s.log.Warnw("Ignoring invalid stored setting", "key", def.Key, "error", err)
+			}
+		}
+		if entry.Secret && entry.Value != "" {
+			entry.Value = secretMask
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// Update validates and stores settings of a scope. A null value resets a
+// setting to its default. Secret settings left at the mask are unchanged.
+func (s *Service) Update(ctx context.Context, actor audit.Actor, scope domain.Scope, scopeID uuid.UUID, values map[string]json.RawMessage) error {
+	if !scope.Valid() {
+		return domain.ErrInvalidScope
+	}
+
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var updated []*domain.Setting
+	var reset []string
+	changes := make(map[string]interface{}, len(keys))
+	now := time.Now()
+	for _, key := range keys {
+		def, ok := domain.Lookup(key)
+		if !ok || def.Scope != scope {
+			return fmt.Errorf("%w: %s", domain.ErrUnknownSetting, key)
+		}
+
+		raw := values[key]
+		if string(raw) == "null" || len(raw) == 0 {
+			reset = append(reset, key)
+			changes[key] = nil
+			continue
+		}
+		if def.Secret && string(raw) == `"`+secretMask+`"` {
+			continue
+		}
+
+		value, err := def.Normalize(raw)
+		if err != nil {
+			return err
+		}
+		setting := &domain.Setting{
+			Scope:     scope,
+			ScopeID:   scopeID,
+			Key:       key,
+			Type:      def.Type,
+			Value:     value,
+			CreatedAt: now,
+			UpdatedAt: now,
+		}
+		if actor.UserID != uuid.Nil {
+			userID := actor.UserID
+			setting.UpdatedBy = &userID
+		}
+		updated = append(updated, setting)
+
+		if def.Secret {
+			changes[key] = secretMask
+		} else {
+			changes[key] = json.RawMessage(value)
+		}
+	}
+
+	if len(updated) == 0 && len(reset) == 0 {
+		return nil
+	}
+	if err := s.repo.Save(ctx, scope, scopeID, updated, reset); err != nil {
+		return err
+	}
+	s.invalidate(ctx, scope, scopeID)
+
+	if scope == domain.ScopeInstance {
+		_ = s.audit.Record(ctx, actor, &auditdomain.AuditLog{
+			Action:       auditdomain.ActionSettingsUpdated,
+			ResourceType: auditdomain.ResourceSettings,
+			ResourceID:   string(scope),
+			NewValue:     audit.ToMap(changes),
+		})
+	}
+	return nil
+}
+
+// Value returns the effective value of a setting, decoded to its Go type:
+// string, int64, float64, bool, time.Duration or a decoded JSON value
+func (s *Service) Value(ctx context.Context, scope domain.Scope, scopeID uuid.UUID, key string) (interface{}, error) {
+	def, ok := domain.Lookup(key)
+	if !ok || def.Scope != scope {
+		return nil, fmt.Errorf("%w: %s", domain.ErrUnknownSetting, key)
+	}
+	stored, err := s.load(ctx, scope, scopeID)
+	if err != nil {
+		return def.Decode(def.DefaultValue())
+	}
+	if raw, ok := stored[key]; ok {
+		if v, err := def.Decode(raw); err == nil {
+			return v, nil
+		}
+	}
+	return def.Decode(def.DefaultValue())
+}
+
+// String returns an instance setting as a string
+func (s *Service) String(ctx context.Context, key string) string {
+	v, _ := s.instanceValue(ctx, key).(string)
+	return v
+}
+
+// Int returns an instance setting as an integer
+func (s *Service) Int(ctx context.Context, key string) int64 {
+	v, _ := s.instanceValue(ctx, key).(int64)
+	return v
+}
+
+// Bool returns an instance setting as a boolean
+func (s *Service) Bool(ctx context.Context, key string) bool {
+	v, _ := s.instanceValue(ctx, key).(bool)
+	return v
+}
+
+// Duration returns an instance setting as a duration
+func (s *Service) Duration(ctx context.Context, key string) time.Duration {
+	v, _ := s.instanceValue(ctx, key).(time.Duration)
+	return v
+}
+
+// UserValue returns a user setting, falling back to its default
+func (s *Service) UserValue(ctx context.Context, userID uuid.UUID, key string) interface{} {
+	v, err := s.Value(ctx, domain.ScopeUser, userID, key)
+	if err != nil {
+		s.log.Warnw("Failed to read user setting", "key", key, "user_id", userID, "error", err)
+		return nil
+	}
+	return v
+}
+
+func (s *Service) instanceValue(ctx context.Context, key string) interface{} {
+	v, err := s.Value(ctx, domain.ScopeInstance, uuid.Nil, key)
+	if err != nil {
+		s.log.Warnw("Failed to read instance setting", "key", key, "error", err)
+		return nil
+	}
+	return v
+}
+
+// load returns the stored values of a scope, from the cache when possible
+func (s *Service) load(ctx context.Context, scope domain.Scope, scopeID uuid.UUID) (map[string]json.RawMessage, error) {
+	key := cacheKey(scope, scopeID)
+
+	if s.cache != nil {
+		cached, err := s.cache.HGetAll(ctx, key).Result()
+		if err == nil {
+			if _, ok := cached[cacheLoadedField]; ok {
+				values := make(map[string]json.RawMessage, len(cached))
+				for k, v := range cached {
+					if k != cacheLoadedField {
+						values[k] = json.RawMessage(v)
+					}
+				}
+				return values, nil
+			}
+		} else {
+			s.log.Warnw("Failed to read settings cache", "key", key, "error", err)
+		}
+	}
+
+	list, err := s.repo.List(ctx, scope, scopeID)
+	if err != nil {
+		return nil, err
+	}
+	values := make(map[string]json.RawMessage, len(list))
+	fields := make([]interface{}, 0, 2*len(list)+2)
+	fields = append(fields, cacheLoadedField, "1")
+	for _, setting := range list {
+		values[setting.Key] = setting.Value
+		fields = append(fields, setting.Key, string(setting.Value))
+	}
+
+	if s.cache != nil {
+		pipe := s.cache.TxPipeline()
+		pipe.Del(ctx, key)
+		pipe.HSet(ctx, key, fields...)
+		pipe.Expire(ctx, key, s.ttl)
+		if _, err := pipe.Exec(ctx); err != nil {
+			s.log.Warnw("Failed to write settings cache", "key", key, "error", err)
+		}
+	}
+	return values, nil
+}
+
+func (s *Service) invalidate(ctx context.Context, scope domain.Scope, scopeID uuid.UUID) {
+	if s.cache == nil {
+		return
+	}
+	if err := s.cache.Del(ctx, cacheKey(scope, scopeID)).Err(); err != nil {
+		s.log.Warnw("Failed to invalidate settings cache", "scope", scope, "error", err)
+	}
+}
+
+func cacheKey(scope domain.Scope, scopeID uuid.UUID) string {
+	return cacheKeyPrefix + string(scope) + ":" + scopeID.String()
+}
+
+// displayValue renders durations the way they are entered
+func displayValue(v interface{}) interface{} {
+	if d, ok := v.(time.Duration); ok {
+		return d.String()
+	}
+	return v
+}
+
+func decodeOrNil(def domain.Definition, raw json.RawMessage) interface{} {
+	v, err := def.Decode(raw)
+	if err != nil {
+		return nil
+	}
+	return v
+}
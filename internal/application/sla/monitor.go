@@ -0,0 +1,167 @@
+package sla
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jaydeep/go-n8n/internal/application/events"
+	"github.com/jaydeep/go-n8n/internal/domain/execution"
+	domain "github.com/jaydeep/go-n8n/internal/domain/sla"
+	"github.com/jaydeep/go-n8n/internal/domain/workflow"
+)
+
+// Start evaluates the policies until the context is cancelled
+func (s *Service) Start(ctx context.Context) {
+	ticker := time.NewTicker(s.cfg.CheckInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.Evaluate(ctx); err != nil && ctx.Err() == nil {
+			s.log.Errorw("SLA evaluation failed", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Evaluate checks every policy once and publishes SLABreached and
+// SLARecovered for the breaches that started and ended. Policies of
+// inactive workflows are paused.
+func (s *Service) Evaluate(ctx context.Context) error {
+	policies, err := s.repo.List(ctx)
+	if err != nil || len(policies) == 0 {
+		return err
+	}
+
+	ids := make([]uuid.UUID, 0, len(policies))
+	for _, p := range policies {
+		ids = append(ids, p.WorkflowID)
+	}
+	found, err := s.workflows.FindByIDs(ctx, ids)
+	if err != nil {
+		return err
+	}
+	workflows := make(map[uuid.UUID]*workflow.Workflow, len(found))
+	for _, w := range found {
+		workflows[w.ID] = w
+	}
+	latest, err := s.executions.ListLatest(ctx, ids, 1)
+	if err != nil {
+		return err
+	}
+	last := make(map[uuid.UUID]*execution.Execution, len(latest))
+	for _, e := range latest {
+		last[e.WorkflowID] = e
+	}
+
+	for _, p := range policies {
+		w, ok := workflows[p.WorkflowID]
+		if !ok {
+			// Deleted workflow
+			continue
+		}
+		if !w.IsActive {
+			if p.Status != domain.StatusInactive {
+				p.Pause()
+				s.save(ctx, p)
+			}
+			continue
+		}
+
+		breaches, err := s.check(ctx, p, last[w.ID])
+		if err != nil {
+			s.log.Errorw("SLA check failed", "workflow_id", w.ID, "error", err)
+			continue
+		}
+		started, ended := p.Apply(breaches)
+		s.save(ctx, p)
+
+		for _, b := range started {
+			s.log.Warnw("Workflow breached its SLA", "workflow_id", w.ID, "objective", b.Objective, "message", b.Message)
+			s.events.Publish(events.NewSLAEvent(events.SLABreached, w, b))
+		}
+		for _, b := range ended {
+			s.log.Infow("Workflow meets its SLA again", "workflow_id", w.ID, "objective", b.Objective)
+			s.events.Publish(events.NewSLAEvent(events.SLARecovered, w, b))
+		}
+	}
+	return nil
+}
+
+func (s *Service) save(ctx context.Context, p *domain.Policy) {
+	if err := s.repo.Update(ctx, p); err != nil {
+		s.log.Errorw("Failed to save SLA status", "workflow_id", p.WorkflowID, "error", err)
+	}
+}
+
+// check returns the objectives of p the workflow does not meet. last is
+// its newest execution, nil when it never ran.
+func (s *Service) check(ctx context.Context, p *domain.Policy, last *execution.Execution) ([]domain.Breach, error) {
+	now := time.Now()
+	var breaches []domain.Breach
+
+	if p.MaxIntervalMinutes > 0 {
+		// A workflow that never ran is measured from when the policy was set
+		since := p.CreatedAt
+		if last != nil {
+			since = last.StartedAt
+		}
+		limit := time.Duration(p.MaxIntervalMinutes) * time.Minute
+		if elapsed := now.Sub(since); elapsed > limit {
+			breaches = append(breaches, domain.Breach{
+				Objective: domain.ObjectiveFrequency,
+				Limit:     float64(p.MaxIntervalMinutes),
+				Value:     round(elapsed.Minutes()),
+				Message:   fmt.Sprintf("no execution for %s, expected every %s", elapsed.Round(time.Minute), limit),
+				Since:     now,
+			})
+		}
+	}
+
+	if p.MaxDurationSeconds > 0 && last != nil {
+		var took time.Duration
+		verb := "took"
+		switch {
+		case last.Status.IsRunning():
+			took = now.Sub(last.StartedAt)
+			verb = "is running for"
+		case last.FinishedAt != nil:
+			took = last.FinishedAt.Sub(last.StartedAt)
+		}
+		limit := time.Duration(p.MaxDurationSeconds) * time.Second
+		if took > limit {
+			breaches = append(breaches, domain.Breach{
+				Objective: domain.ObjectiveDuration,
+				Limit:     float64(p.MaxDurationSeconds),
+				Value:     round(took.Seconds()),
+				Message:   fmt.Sprintf("execution %s %s %s, limit %s", last.ID, verb, took.Round(time.Second), limit),
+				Since:     now,
+			})
+		}
+	}
+
+	if p.MaxErrorRate > 0 {
+		window := time.Duration(p.ErrorRateWindowMinutes) * time.Minute
+		outcomes, err := s.executions.CountOutcomes(ctx, p.WorkflowID, now.Add(-window))
+		if err != nil {
+			return nil, err
+		}
+		if r := rate(outcomes); r > p.MaxErrorRate {
+			breaches = append(breaches, domain.Breach{
+				Objective: domain.ObjectiveErrorRate,
+				Limit:     p.MaxErrorRate,
+				Value:     r,
+				Message: fmt.Sprintf("%d of %d executions failed in the last %s, limit %g%%",
+					outcomes.Failed, outcomes.Finished, window, p.MaxErrorRate),
+				Since: now,
+			})
+		}
+	}
+	return breaches, nil
+}
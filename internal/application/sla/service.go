@@ -0,0 +1,176 @@
+// Package sla lets users set expectations on their workflows, such as
+// running at least every hour, and evaluates them periodically, publishing
+// breaches as they start and end.
+package sla
+
+import (
+	"context"
+	"errors"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jaydeep/go-n8n/configs"
+	"github.com/jaydeep/go-n8n/internal/application/events"
+	"github.com/jaydeep/go-n8n/internal/domain/execution"
+	domain "github.com/jaydeep/go-n8n/internal/domain/sla"
+	"github.com/jaydeep/go-n8n/internal/domain/workflow"
+	"github.com/jaydeep/go-n8n/pkg/logger"
+)
+
+// Input holds the objectives of a policy. Zero limits leave an objective
+// unset; the error rate window defaults to an hour.
+type Input struct {
+	MaxIntervalMinutes     int     `json:"max_interval_minutes"`
+	MaxDurationSeconds     int     `json:"max_duration_seconds"`
+	MaxErrorRate           float64 `json:"max_error_rate"`
+	ErrorRateWindowMinutes int     `json:"error_rate_window_minutes"`
+}
+
+// ExecutionSummary describes an execution without its data
+type ExecutionSummary struct {
+	ID              uuid.UUID                 `json:"id"`
+	Status          execution.ExecutionStatus `json:"status"`
+	StartedAt       time.Time                 `json:"started_at"`
+	FinishedAt      *time.Time                `json:"finished_at,omitempty"`
+	ExecutionTimeMs int                       `json:"execution_time_ms,omitempty"`
+}
+
+// Metrics describes the recent executions of a workflow and its SLA status
+type Metrics struct {
+	WorkflowID uuid.UUID `json:"workflow_id"`
+	// Window is the period the execution counts cover
+	Window        string  `json:"window"`
+	Finished      int64   `json:"finished"`
+	Failed        int64   `json:"failed"`
+	ErrorRate     float64 `json:"error_rate"`
+	AvgDurationMs float64 `json:"avg_duration_ms"`
+	// LastExecution is the newest execution, if any
+	LastExecution *ExecutionSummary `json:"last_execution"`
+	// SLA is the policy of the workflow with its latest evaluation, if one
+	// is set
+	SLA *domain.Policy `json:"sla"`
+}
+
+// Service manages SLA policies and evaluates them
+type Service struct {
+	repo       domain.Repository
+	workflows  workflow.Repository
+	executions execution.Repository
+	events     events.Publisher
+	cfg        configs.SLAConfig
+	log        *logger.Logger
+}
+
+// NewService creates a new SLA service
+func NewService(repo domain.Repository, workflows workflow.Repository, executions execution.Repository, publisher events.Publisher, cfg configs.SLAConfig, log *logger.Logger) *Service {
+	if cfg.CheckInterval <= 0 {
+		cfg.CheckInterval = time.Minute
+	}
+	if cfg.MetricsWindow <= 0 {
+		cfg.MetricsWindow = 24 * time.Hour
+	}
+	return &Service{
+		repo:       repo,
+		workflows:  workflows,
+		executions: executions,
+		events:     publisher,
+		cfg:        cfg,
+		log:        log,
+	}
+}
+
+// Get returns the policy of a workflow
+func (s *Service) Get(ctx context.Context, workflowID uuid.UUID) (*domain.Policy, error) {
+	return s.repo.FindByWorkflow(ctx, workflowID)
+}
+
+// Set creates or replaces the policy of a workflow. Its status is pending
+// until the next evaluation.
+func (s *Service) Set(ctx context.Context, w *workflow.Workflow, userID uuid.UUID, input Input) (*domain.Policy, error) {
+	p, err := s.repo.FindByWorkflow(ctx, w.ID)
+	created := errors.Is(err, domain.ErrPolicyNotFound)
+	if err != nil && !created {
+		return nil, err
+	}
+	if created {
+		p = &domain.Policy{WorkflowID: w.ID}
+		if userID != uuid.Nil {
+			p.CreatedBy = &userID
+		}
+	}
+
+	p.MaxIntervalMinutes = input.MaxIntervalMinutes
+	p.MaxDurationSeconds = input.MaxDurationSeconds
+	p.MaxErrorRate = input.MaxErrorRate
+	p.ErrorRateWindowMinutes = input.ErrorRateWindowMinutes
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+	p.Reset()
+
+	if created {
+		err = s.repo.Create(ctx, p)
+	} else {
+		err = s.repo.Update(ctx, p)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// Delete removes the policy of a workflow
+func (s *Service) Delete(ctx context.Context, workflowID uuid.UUID) error {
+	return s.repo.Delete(ctx, workflowID)
+}
+
+// Metrics returns the recent executions of a workflow and its SLA status
+func (s *Service) Metrics(ctx context.Context, w *workflow.Workflow) (*Metrics, error) {
+	outcomes, err := s.executions.CountOutcomes(ctx, w.ID, time.Now().Add(-s.cfg.MetricsWindow))
+	if err != nil {
+		return nil, err
+	}
+	m := &Metrics{
+		WorkflowID:    w.ID,
+		Window:        s.cfg.MetricsWindow.String(),
+		Finished:      outcomes.Finished,
+		Failed:        outcomes.Failed,
+		ErrorRate:     rate(outcomes),
+		AvgDurationMs: math.Round(outcomes.AvgDurationMs),
+	}
+
+	latest, err := s.executions.ListLatest(ctx, []uuid.UUID{w.ID}, 1)
+	if err != nil {
+		return nil, err
+	}
+	if len(latest) > 0 {
+		e := latest[0]
+		m.LastExecution = &ExecutionSummary{
+			ID:              e.ID,
+			Status:          e.Status,
+			StartedAt:       e.StartedAt,
+			FinishedAt:      e.FinishedAt,
+			ExecutionTimeMs: e.ExecutionTimeMs,
+		}
+	}
+
+	p, err := s.repo.FindByWorkflow(ctx, w.ID)
+	if err != nil && !errors.Is(err, domain.ErrPolicyNotFound) {
+		return nil, err
+	}
+	m.SLA = p
+	return m, nil
+}
+
+// rate returns the percentage of failed executions, rounded to a tenth
+func rate(o *execution.WorkflowOutcomes) float64 {
+	if o.Finished == 0 {
+		return 0
+	}
+	return round(float64(o.Failed) / float64(o.Finished) * 100)
+}
+
+func round(v float64) float64 {
+	return math.Round(v*10) / 10
+}
@@ -0,0 +1,271 @@
+package teamnode
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jaydeep/go-n8n/internal/domain/node"
+	domain "github.com/jaydeep/go-n8n/internal/domain/teamnode"
+	"github.com/jaydeep/go-n8n/internal/domain/user"
+	"github.com/jaydeep/go-n8n/pkg/logger"
+	"github.com/jaydeep/go-n8n/pkg/nodesdk/declarative"
+)
+
+const (
+	// MaxSpecSize bounds the OpenAPI documents nodes are generated from
+	MaxSpecSize = 5 << 20
+	// syncInterval bounds how long a node imported or removed on another
+	// instance takes to be registered or unregistered on this one
+	syncInterval = time.Minute
+)
+
+// Caller identifies the user managing team nodes
+type Caller struct {
+	UserID uuid.UUID
+	Admin  bool
+}
+
+// Input is an OpenAPI document to generate a node from. Slug names the
+// node within the team; Name and BaseURL override the title and server of
+// the document.
+type Input struct {
+	Spec    []byte
+	Slug    string
+	Name    string
+	BaseURL string
+}
+
+// Service generates declarative nodes for teams from OpenAPI documents and
+// keeps them registered as node types of their teams
+type Service struct {
+	repo     domain.Repository
+	users    user.Repository
+	registry *node.NodeRegistry
+	log      *logger.Logger
+
+	mu sync.Mutex
+	// registered maps the team node types registered on this instance to
+	// the time the node was last updated
+	registered map[string]time.Time
+}
+
+// NewService creates a new team node service
+func NewService(repo domain.Repository, users user.Repository, registry *node.NodeRegistry, log *logger.Logger) *Service {
+	return &Service{
+		repo:       repo,
+		users:      users,
+		registry:   registry,
+		log:        log,
+		registered: make(map[string]time.Time),
+	}
+}
+
+// List returns the nodes of a team
+func (s *Service) List(ctx context.Context, caller Caller, teamID uuid.UUID) ([]*domain.TeamNode, error) {
+	if err := s.authorize(ctx, caller, teamID, false); err != nil {
+		return nil, err
+	}
+	return s.repo.FindByTeam(ctx, teamID)
+}
+
+// Import generates a node from an OpenAPI document and registers it for
+// the team
+func (s *Service) Import(ctx context.Context, caller Caller, teamID uuid.UUID, in Input) (*domain.TeamNode, error) {
+	if err := s.authorize(ctx, caller, teamID, true); err != nil {
+		return nil, err
+	}
+	d, err := generate(teamID, in)
+	if err != nil {
+		return nil, err
+	}
+	if s.registry.Has(d.Type) && s.registry.Team(d.Type) == nil {
+		return nil, domain.ErrSlugTaken
+	}
+	if _, err := s.repo.FindByType(ctx, d.Type); err == nil {
+		return nil, domain.ErrSlugTaken
+	} else if !errors.Is(err, domain.ErrTeamNodeNotFound) {
+		return nil, err
+	}
+
+	n := &domain.TeamNode{TeamID: teamID, Slug: in.Slug, Type: d.Type, CreatedBy: caller.UserID}
+	if err := setDescriptor(n, d); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Create(ctx, n); err != nil {
+		return nil, err
+	}
+	s.register(n, d)
+	s.log.Infow("Team node imported", "team_id", teamID, "type", n.Type, "operations", len(d.Operations))
+	return n, nil
+}
+
+// Update regenerates a node of a team from a new version of its OpenAPI
+// document. The slug, and so the node type, stays as it was.
+func (s *Service) Update(ctx context.Context, caller Caller, teamID, id uuid.UUID, in Input) (*domain.TeamNode, error) {
+	n, err := s.teamNode(ctx, caller, teamID, id)
+	if err != nil {
+		return nil, err
+	}
+	in.Slug = n.Slug
+	d, err := generate(teamID, in)
+	if err != nil {
+		return nil, err
+	}
+	if err := setDescriptor(n, d); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Update(ctx, n); err != nil {
+		return nil, err
+	}
+	s.register(n, d)
+	s.log.Infow("Team node updated", "team_id", teamID, "type", n.Type, "operations", len(d.Operations))
+	return n, nil
+}
+
+// Delete removes a node of a team. Workflows using it fail on the node
+// until they stop using it.
+func (s *Service) Delete(ctx context.Context, caller Caller, teamID, id uuid.UUID) error {
+	n, err := s.teamNode(ctx, caller, teamID, id)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.Delete(ctx, n.ID); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	s.registry.Unregister(n.Type)
+	delete(s.registered, n.Type)
+	s.mu.Unlock()
+	s.log.Infow("Team node removed", "team_id", teamID, "type", n.Type)
+	return nil
+}
+
+// Start registers the stored team nodes, then keeps them in step with the
+// other instances until ctx ends
+func (s *Service) Start(ctx context.Context) {
+	ticker := time.NewTicker(syncInterval)
+	defer ticker.Stop()
+	for {
+		if err := s.Sync(ctx); err != nil && ctx.Err() == nil {
+			s.log.Warnw("Failed to sync team nodes", "error", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Sync registers the stored team nodes that are new or changed since they
+// were registered and unregisters the removed ones
+func (s *Service) Sync(ctx context.Context) error {
+	nodes, err := s.repo.FindAll(ctx)
+	if err != nil {
+		return err
+	}
+
+	stored := make(map[string]bool, len(nodes))
+	for _, n := range nodes {
+		stored[n.Type] = true
+		s.mu.Lock()
+		updatedAt, ok := s.registered[n.Type]
+		s.mu.Unlock()
+		if ok && !n.UpdatedAt.After(updatedAt) {
+			continue
+		}
+		var d declarative.Descriptor
+		if err := json.Unmarshal(n.Descriptor, &d); err != nil {
+			s.log.Warnw("Invalid team node descriptor", "type", n.Type, "error", err)
+			continue
+		}
+		s.register(n, &d)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for nodeType := range s.registered {
+		if !stored[nodeType] {
+			s.registry.Unregister(nodeType)
+			delete(s.registered, nodeType)
+		}
+	}
+	return nil
+}
+
+// register adds or replaces the node type of a team node
+func (s *Service) register(n *domain.TeamNode, d *declarative.Descriptor) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.registry.ReplaceTeam(n.TeamID, d.Type, declarative.NewNode(d).GetCategory(), func() node.NodeInterface { return declarative.NewNode(d) })
+	s.registered[n.Type] = n.UpdatedAt
+}
+
+// generate builds the descriptor of a team node from an OpenAPI document
+func generate(teamID uuid.UUID, in Input) (*declarative.Descriptor, error) {
+	if len(in.Spec) > MaxSpecSize {
+		return nil, fmt.Errorf("%w: larger than %d bytes", domain.ErrInvalidSpec, MaxSpecSize)
+	}
+	if err := domain.ValidateSlug(in.Slug); err != nil {
+		return nil, err
+	}
+	d, err := declarative.FromOpenAPI(in.Spec, declarative.OpenAPIOptions{
+		Type:    domain.NodeType(teamID, in.Slug),
+		Name:    in.Name,
+		BaseURL: in.BaseURL,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", domain.ErrInvalidSpec, err)
+	}
+	return d, nil
+}
+
+// setDescriptor stores a generated descriptor in a team node
+func setDescriptor(n *domain.TeamNode, d *declarative.Descriptor) error {
+	raw, err := json.Marshal(d)
+	if err != nil {
+		return err
+	}
+	n.Name = d.Name
+	n.Descriptor = raw
+	return nil
+}
+
+// teamNode returns a node of a team the caller may manage
+func (s *Service) teamNode(ctx context.Context, caller Caller, teamID, id uuid.UUID) (*domain.TeamNode, error) {
+	if err := s.authorize(ctx, caller, teamID, true); err != nil {
+		return nil, err
+	}
+	n, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if n.TeamID != teamID {
+		return nil, domain.ErrTeamNodeNotFound
+	}
+	return n, nil
+}
+
+// authorize checks that the caller belongs to the team and, to manage its
+// nodes, is a team admin or owner
+func (s *Service) authorize(ctx context.Context, caller Caller, teamID uuid.UUID, manage bool) error {
+	if caller.Admin {
+		return nil
+	}
+	member, err := s.users.FindTeamMember(ctx, teamID, caller.UserID)
+	if errors.Is(err, user.ErrTeamMemberNotFound) {
+		return domain.ErrTeamAccessDenied
+	}
+	if err != nil {
+		return err
+	}
+	if manage && member.Role != user.TeamRoleAdmin && member.Role != user.TeamRoleOwner {
+		return domain.ErrTeamAccessDenied
+	}
+	return nil
+}
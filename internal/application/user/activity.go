@@ -0,0 +1,98 @@
+package user
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jaydeep/go-n8n/configs"
+	domain "github.com/jaydeep/go-n8n/internal/domain/user"
+	"github.com/jaydeep/go-n8n/pkg/logger"
+)
+
+const defaultActivityFlushInterval = time.Minute
+
+// ActivityTracker records when users were last seen. Requests only note
+// the time in memory; the latest time per user is written in batches, so a
+// busy user costs one write per flush instead of one per request.
+type ActivityTracker struct {
+	repo domain.Repository
+	cfg  configs.UserActivityConfig
+	log  *logger.Logger
+
+	mu      sync.Mutex
+	pending map[uuid.UUID]time.Time
+}
+
+// NewActivityTracker creates a new activity tracker
+func NewActivityTracker(repo domain.Repository, cfg configs.UserActivityConfig, log *logger.Logger) *ActivityTracker {
+	if cfg.FlushInterval <= 0 {
+		cfg.FlushInterval = defaultActivityFlushInterval
+	}
+	return &ActivityTracker{
+		repo:    repo,
+		cfg:     cfg,
+		log:     log,
+		pending: make(map[uuid.UUID]time.Time),
+	}
+}
+
+// Seen notes that the user made a request now
+func (t *ActivityTracker) Seen(userID uuid.UUID) {
+	now := time.Now()
+	t.mu.Lock()
+	t.pending[userID] = now
+	t.mu.Unlock()
+}
+
+// Start flushes the noted activity until the context is cancelled, then
+// flushes once more so the last interval is not lost
+func (t *ActivityTracker) Start(ctx context.Context) {
+	ticker := time.NewTicker(t.cfg.FlushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			// Flush with a fresh context: ctx is cancelled by now
+			flushCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), t.cfg.FlushInterval)
+			t.flush(flushCtx)
+			cancel()
+			return
+		case <-ticker.C:
+			t.flush(ctx)
+		}
+	}
+}
+
+func (t *ActivityTracker) flush(ctx context.Context) {
+	t.mu.Lock()
+	pending := t.pending
+	t.pending = make(map[uuid.UUID]time.Time, len(pending))
+	t.mu.Unlock()
+	if len(pending) == 0 {
+		return
+	}
+
+	activity := make([]*domain.Activity, 0, len(pending))
+	for userID, seen := range pending {
+		activity = append(activity, &domain.Activity{UserID: userID, LastSeenAt: seen})
+	}
+	if err := t.repo.RecordActivity(ctx, activity); err != nil {
+		t.log.Warnw("Failed to record user activity", "users", len(activity), "error", err)
+		t.requeue(pending)
+	}
+}
+
+// requeue puts back the times that failed to be written, unless the user
+// was seen again meanwhile
+func (t *ActivityTracker) requeue(pending map[uuid.UUID]time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for userID, seen := range pending {
+		if _, ok := t.pending[userID]; !ok {
+			t.pending[userID] = seen
+		}
+	}
+}
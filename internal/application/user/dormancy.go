@@ -0,0 +1,130 @@
+package user
+
+import (
+	"context"
+	"time"
+
+	"github.com/jaydeep/go-n8n/configs"
+	"github.com/jaydeep/go-n8n/internal/application/audit"
+	"github.com/jaydeep/go-n8n/internal/application/events"
+	"github.com/jaydeep/go-n8n/internal/application/settings"
+	auditdomain "github.com/jaydeep/go-n8n/internal/domain/audit"
+	settingsdomain "github.com/jaydeep/go-n8n/internal/domain/settings"
+	domain "github.com/jaydeep/go-n8n/internal/domain/user"
+	"github.com/jaydeep/go-n8n/pkg/logger"
+)
+
+const (
+	defaultDeactivationInterval = time.Hour
+	deactivationBatch           = 500
+)
+
+// deactivatableRoles are the roles deactivated when dormant. Admins and
+// owners are left alone so the instance cannot lock itself out.
+var deactivatableRoles = []domain.Role{domain.RoleUser, domain.RoleApprover}
+
+// Dormancy deactivates accounts inactive beyond the
+// users.deactivate_after_days setting; a setting of zero keeps them active
+type Dormancy struct {
+	repo     domain.Repository
+	settings *settings.Service
+	audit    *audit.Service
+	events   events.Publisher
+	cfg      configs.UserActivityConfig
+	log      *logger.Logger
+}
+
+// NewDormancy creates a new dormant account job
+func NewDormancy(repo domain.Repository, settingsService *settings.Service, auditService *audit.Service, publisher events.Publisher, cfg configs.UserActivityConfig, log *logger.Logger) *Dormancy {
+	if cfg.DeactivationInterval <= 0 {
+		cfg.DeactivationInterval = defaultDeactivationInterval
+	}
+	return &Dormancy{
+		repo:     repo,
+		settings: settingsService,
+		audit:    auditService,
+		events:   publisher,
+		cfg:      cfg,
+		log:      log,
+	}
+}
+
+// Start runs the job until the context is cancelled
+func (d *Dormancy) Start(ctx context.Context) {
+	ticker := time.NewTicker(d.cfg.DeactivationInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := d.Run(ctx); err != nil && ctx.Err() == nil {
+			d.log.Errorw("Dormant account deactivation failed", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Run deactivates the accounts inactive beyond the configured period
+func (d *Dormancy) Run(ctx context.Context) error {
+	days := d.settings.Int(ctx, settingsdomain.KeyUserDeactivateDays)
+	if days <= 0 {
+		return nil
+	}
+	before := time.Now().AddDate(0, 0, -int(days))
+
+	// Deactivated users leave the listing, so only those kept are skipped
+	// on the next page
+	active := true
+	var total, kept int
+	for {
+		users, err := d.repo.List(ctx, domain.ListFilter{
+			IsActive:         &active,
+			Roles:            deactivatableRoles,
+			LastActiveBefore: &before,
+			Limit:            deactivationBatch,
+			Offset:           kept,
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, u := range users {
+			// The check is repeated in the update, so a user seen since the
+			// listing keeps their account
+			deactivated, err := d.repo.DeactivateInactive(ctx, u.ID, before)
+			if err != nil {
+				return err
+			}
+			if !deactivated {
+				kept++
+				continue
+			}
+			total++
+			_ = d.audit.Record(ctx, audit.Actor{}, &auditdomain.AuditLog{
+				Action:       auditdomain.ActionUserDeactivated,
+				ResourceType: auditdomain.ResourceUser,
+				ResourceID:   u.ID.String(),
+				NewValue: map[string]interface{}{
+					"is_active":      false,
+					"reason":         "inactive",
+					"last_active_at": u.LastActiveAt(),
+				},
+			})
+
+			event := events.NewUserEvent(events.UserDeactivated, u, audit.Actor{})
+			event.Reason = "inactive"
+			d.events.Publish(event)
+		}
+		if len(users) < deactivationBatch || ctx.Err() != nil {
+			break
+		}
+	}
+
+	if total > 0 {
+		d.log.Infow("Deactivated dormant accounts", "count", total, "inactive_since", before)
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package user
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	domain "github.com/jaydeep/go-n8n/internal/domain/user"
+	"github.com/jaydeep/go-n8n/pkg/logger"
+)
+
+// languageEntry is a cached user language
+type languageEntry struct {
+	language  string
+	expiresAt time.Time
+}
+
+// LanguageResolver returns the language users chose in their settings.
+// Results are cached briefly so requests don't each hit the database.
+type LanguageResolver struct {
+	repo domain.Repository
+	ttl  time.Duration
+	log  *logger.Logger
+
+	mu    sync.Mutex
+	cache map[uuid.UUID]languageEntry
+}
+
+// NewLanguageResolver creates a new language resolver
+func NewLanguageResolver(repo domain.Repository, ttl time.Duration, log *logger.Logger) *LanguageResolver {
+	return &LanguageResolver{
+		repo:  repo,
+		ttl:   ttl,
+		log:   log,
+		cache: make(map[uuid.UUID]languageEntry),
+	}
+}
+
+// Language returns the user's language, or an empty string when the user
+// has none set or cannot be loaded
+func (r *LanguageResolver) Language(ctx context.Context, userID uuid.UUID) string {
+	now := time.Now()
+
+	r.mu.Lock()
+	entry, ok := r.cache[userID]
+	r.mu.Unlock()
+	if ok && now.Before(entry.expiresAt) {
+		return entry.language
+	}
+
+	u, err := r.repo.FindByID(ctx, userID)
+	if err != nil {
+		r.log.Warnw("Failed to load user language", "user_id", userID, "error", err)
+		return ""
+	}
+
+	r.mu.Lock()
+	r.cache[userID] = languageEntry{language: u.Settings.Language, expiresAt: now.Add(r.ttl)}
+	r.mu.Unlock()
+	return u.Settings.Language
+}
+
+// Forget drops the cached language, e.g. after the user changed settings
+func (r *LanguageResolver) Forget(userID uuid.UUID) {
+	r.mu.Lock()
+	delete(r.cache, userID)
+	r.mu.Unlock()
+}
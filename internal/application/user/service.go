@@ -0,0 +1,113 @@
+package user
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jaydeep/go-n8n/internal/application/audit"
+	"github.com/jaydeep/go-n8n/internal/application/events"
+	"github.com/jaydeep/go-n8n/internal/application/settings"
+	auditdomain "github.com/jaydeep/go-n8n/internal/domain/audit"
+	settingsdomain "github.com/jaydeep/go-n8n/internal/domain/settings"
+	domain "github.com/jaydeep/go-n8n/internal/domain/user"
+	"github.com/jaydeep/go-n8n/pkg/logger"
+)
+
+// ListQuery selects users for the admin listing
+type ListQuery struct {
+	Active *bool
+	Role   domain.Role
+	// Dormant selects users inactive beyond the users.dormant_after_days
+	// setting
+	Dormant bool
+	// InactiveDays selects users inactive for at least that many days
+	InactiveDays int
+	Limit        int
+	Offset       int
+}
+
+// Service lists and manages user accounts for admins
+type Service struct {
+	repo     domain.Repository
+	settings *settings.Service
+	audit    *audit.Service
+	events   events.Publisher
+	log      *logger.Logger
+}
+
+// NewService creates a new user service
+func NewService(repo domain.Repository, settingsService *settings.Service, auditService *audit.Service, publisher events.Publisher, log *logger.Logger) *Service {
+	return &Service{
+		repo:     repo,
+		settings: settingsService,
+		audit:    auditService,
+		events:   publisher,
+		log:      log,
+	}
+}
+
+// List returns the users matching the query with their last activity
+func (s *Service) List(ctx context.Context, q ListQuery) ([]*domain.User, error) {
+	filter := domain.ListFilter{
+		IsActive: q.Active,
+		Limit:    q.Limit,
+		Offset:   q.Offset,
+	}
+	if q.Role != "" {
+		filter.Roles = []domain.Role{q.Role}
+	}
+
+	days := int64(q.InactiveDays)
+	if q.Dormant {
+		if dormant := s.settings.Int(ctx, settingsdomain.KeyUserDormantDays); dormant > days {
+			days = dormant
+		}
+	}
+	if days > 0 {
+		before := time.Now().AddDate(0, 0, -int(days))
+		filter.LastActiveBefore = &before
+	}
+	return s.repo.List(ctx, filter)
+}
+
+// Get returns a user with their last activity
+func (s *Service) Get(ctx context.Context, id uuid.UUID) (*domain.User, error) {
+	return s.repo.FindByID(ctx, id)
+}
+
+// SetActive activates or deactivates a user. Activating counts as
+// activity, so a dormant account is not deactivated again right away.
+func (s *Service) SetActive(ctx context.Context, actor audit.Actor, id uuid.UUID, active bool) (*domain.User, error) {
+	if err := s.repo.SetActive(ctx, id, active); err != nil {
+		return nil, err
+	}
+	if active {
+		activity := []*domain.Activity{{UserID: id, LastSeenAt: time.Now()}}
+		if err := s.repo.RecordActivity(ctx, activity); err != nil {
+			return nil, err
+		}
+	}
+
+	action := auditdomain.ActionUserDeactivated
+	if active {
+		action = auditdomain.ActionUserActivated
+	}
+	_ = s.audit.Record(ctx, actor, &auditdomain.AuditLog{
+		Action:       action,
+		ResourceType: auditdomain.ResourceUser,
+		ResourceID:   id.String(),
+		NewValue:     map[string]interface{}{"is_active": active},
+	})
+
+	u, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	eventType := events.UserDeactivated
+	if active {
+		eventType = events.UserActivated
+	}
+	s.events.Publish(events.NewUserEvent(eventType, u, actor))
+	return u, nil
+}
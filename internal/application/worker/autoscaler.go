@@ -0,0 +1,103 @@
+package worker
+
+import (
+	"context"
+	"time"
+
+	"github.com/jaydeep/go-n8n/configs"
+	"github.com/jaydeep/go-n8n/pkg/queue"
+)
+
+// QueueSignal describes the load on a queue and the workers consuming it,
+// for scaling the worker Deployment that consumes the queue
+type QueueSignal struct {
+	Queue string `json:"queue"`
+	// JobTypes are the job types routed to the queue. The default queue
+	// also takes the jobs of every type not routed.
+	JobTypes   []string `json:"job_types"`
+	Waiting    int64    `json:"waiting"`
+	LagSeconds float64  `json:"lag_seconds"`
+	ActiveJobs int      `json:"active_jobs"`
+	Workers    int      `json:"workers"`
+	Capacity   int      `json:"capacity"`
+	// DesiredReplicas is the number of workers the waiting and active jobs
+	// call for
+	DesiredReplicas int `json:"desired_replicas"`
+}
+
+// Autoscaler derives scaling signals for the workers of each queue from
+// the queue backlog and the heartbeats of the workers
+type Autoscaler struct {
+	registry *Registry
+	router   *queue.Router
+	cfg      configs.WorkerConfig
+}
+
+// NewAutoscaler creates a new autoscaler over the queues of the router
+func NewAutoscaler(registry *Registry, router *queue.Router, cfg configs.WorkerConfig) *Autoscaler {
+	return &Autoscaler{
+		registry: registry,
+		router:   router,
+		cfg:      cfg,
+	}
+}
+
+// Signals returns the signals of the default queue followed by those of
+// the routed queues
+func (a *Autoscaler) Signals(ctx context.Context) ([]QueueSignal, error) {
+	workers, err := a.registry.Live(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	queues := a.router.Queues()
+	signals := make([]QueueSignal, 0, len(queues))
+	for _, name := range queues {
+		waiting, err := a.router.Len(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		lag, err := a.router.Lag(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+
+		signal := QueueSignal{
+			Queue:      name,
+			JobTypes:   a.router.JobTypes(name),
+			Waiting:    waiting,
+			LagSeconds: lag.Round(time.Millisecond).Seconds(),
+		}
+		for _, w := range workers {
+			if w.Consumes(name, a.cfg.QueueName) {
+				signal.Workers++
+				signal.Capacity += w.Concurrency
+				signal.ActiveJobs += w.ActiveJobs
+			}
+		}
+		signal.DesiredReplicas = a.desiredReplicas(waiting + int64(signal.ActiveJobs))
+		signals = append(signals, signal)
+	}
+	return signals, nil
+}
+
+// desiredReplicas returns the replicas needed for the jobs, within the
+// configured bounds
+func (a *Autoscaler) desiredReplicas(jobs int64) int {
+	perReplica := int64(a.cfg.Autoscaling.JobsPerReplica)
+	if perReplica <= 0 {
+		perReplica = int64(a.cfg.Concurrency)
+	}
+	if perReplica <= 0 {
+		perReplica = 1
+	}
+
+	replicas := int((jobs + perReplica - 1) / perReplica)
+	if replicas < a.cfg.Autoscaling.MinReplicas {
+		replicas = a.cfg.Autoscaling.MinReplicas
+	}
+	if max := a.cfg.Autoscaling.MaxReplicas; max > 0 && replicas > max {
+		replicas = max
+	}
+	return replicas
+}
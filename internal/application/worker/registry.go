@@ -0,0 +1,171 @@
+package worker
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"github.com/jaydeep/go-n8n/configs"
+	domain "github.com/jaydeep/go-n8n/internal/domain/worker"
+	"github.com/jaydeep/go-n8n/pkg/logger"
+	"github.com/jaydeep/go-n8n/pkg/queue"
+)
+
+// Info describes a worker process at registration time
+type Info struct {
+	Hostname    string
+	Version     string
+	Concurrency int
+	Queues      []string
+}
+
+// Registry tracks worker processes through heartbeats and hands the jobs of
+// workers that stopped heartbeating back to the queue
+type Registry struct {
+	repo  domain.Repository
+	queue queue.Queue
+	cfg   configs.WorkerConfig
+	log   *logger.Logger
+}
+
+// NewRegistry creates a new worker registry
+func NewRegistry(repo domain.Repository, q queue.Queue, cfg configs.WorkerConfig, log *logger.Logger) *Registry {
+	return &Registry{
+		repo:  repo,
+		queue: q,
+		cfg:   cfg,
+		log:   log,
+	}
+}
+
+// Register records a worker process as running. Workers registering
+// without queues consume the configured subscriptions.
+func (r *Registry) Register(ctx context.Context, info Info) (*domain.Worker, error) {
+	if info.Hostname == "" {
+		info.Hostname, _ = os.Hostname()
+	}
+	if len(info.Queues) == 0 {
+		info.Queues = r.cfg.Subscriptions
+	}
+	if len(info.Queues) == 0 {
+		info.Queues = []string{r.cfg.QueueName}
+	}
+
+	now := time.Now()
+	w := &domain.Worker{
+		Hostname:        info.Hostname,
+		Version:         info.Version,
+		Concurrency:     info.Concurrency,
+		Queues:          info.Queues,
+		Status:          domain.StatusRunning,
+		StartedAt:       now,
+		LastHeartbeatAt: now,
+	}
+	if err := r.repo.Create(ctx, w); err != nil {
+		return nil, err
+	}
+
+	r.log.Infow("Worker registered", "worker_id", w.ID, "hostname", w.Hostname)
+	return w, nil
+}
+
+// Heartbeat refreshes the worker registration until the context is
+// cancelled, then marks the worker as stopped. activeJobs reports the number
+// of jobs the worker is currently processing.
+func (r *Registry) Heartbeat(ctx context.Context, w *domain.Worker, activeJobs func() int) {
+	ticker := time.NewTicker(r.heartbeatInterval())
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			w.Stop()
+			stopCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			if err := r.repo.Update(stopCtx, w); err != nil {
+				r.log.Errorw("Failed to deregister worker", "worker_id", w.ID, "error", err)
+			}
+			cancel()
+			return
+		case <-ticker.C:
+			w.Heartbeat(activeJobs())
+			if err := r.repo.Update(ctx, w); err != nil {
+				r.log.Errorw("Failed to record worker heartbeat", "worker_id", w.ID, "error", err)
+			}
+		}
+	}
+}
+
+// Live returns the workers that heartbeated within the heartbeat timeout
+func (r *Registry) Live(ctx context.Context) ([]*domain.Worker, error) {
+	return r.repo.FindLive(ctx, time.Now().Add(-r.heartbeatTimeout()))
+}
+
+// DeadSince returns the workers marked dead after since
+func (r *Registry) DeadSince(ctx context.Context, since time.Time) ([]*domain.Worker, error) {
+	return r.repo.FindDeadSince(ctx, since)
+}
+
+// StartReaper periodically reaps stale workers until the context is cancelled
+func (r *Registry) StartReaper(ctx context.Context) {
+	ticker := time.NewTicker(r.heartbeatInterval())
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := r.ReapStale(ctx); err != nil {
+				r.log.Errorw("Stale worker sweep failed", "error", err)
+			}
+		}
+	}
+}
+
+// ReapStale marks workers that missed their heartbeats as dead and requeues
+// their in-flight jobs. It returns the number of jobs requeued.
+func (r *Registry) ReapStale(ctx context.Context) (int, error) {
+	stale, err := r.repo.FindStale(ctx, time.Now().Add(-r.heartbeatTimeout()))
+	if err != nil {
+		return 0, err
+	}
+
+	requeued := 0
+	for _, w := range stale {
+		w.MarkDead()
+		if err := r.repo.Update(ctx, w); err != nil {
+			r.log.Errorw("Failed to mark worker dead", "worker_id", w.ID, "error", err)
+			continue
+		}
+
+		// Workers consume under their registration ID
+		n, err := r.queue.Requeue(ctx, w.ID.String())
+		if err != nil {
+			r.log.Errorw("Failed to requeue jobs of dead worker", "worker_id", w.ID, "error", err)
+			continue
+		}
+		requeued += n
+
+		r.log.Warnw("Worker missed heartbeats and was marked dead",
+			"worker_id", w.ID,
+			"hostname", w.Hostname,
+			"requeued_jobs", n,
+		)
+	}
+
+	return requeued, nil
+}
+
+func (r *Registry) heartbeatInterval() time.Duration {
+	if r.cfg.HeartbeatInterval > 0 {
+		return r.cfg.HeartbeatInterval
+	}
+	return 10 * time.Second
+}
+
+func (r *Registry) heartbeatTimeout() time.Duration {
+	if r.cfg.HeartbeatTimeout > 0 {
+		return r.cfg.HeartbeatTimeout
+	}
+	return 3 * r.heartbeatInterval()
+}
@@ -0,0 +1,245 @@
+package workflow
+
+import (
+	"context"
+	"sort"
+
+	"github.com/google/uuid"
+	"github.com/jaydeep/go-n8n/internal/domain/execution"
+	domain "github.com/jaydeep/go-n8n/internal/domain/workflow"
+	"github.com/jaydeep/go-n8n/internal/expression"
+)
+
+const (
+	// sampleExecutions is how many of the latest executions are searched
+	// for output of the upstream nodes
+	sampleExecutions = 5
+	// sampleItems is how many output items of a node are sampled for keys
+	sampleItems = 20
+	// maxKeyDepth bounds how deep nested objects are described
+	maxKeyDepth = 3
+	// maxKeys bounds the keys described per node
+	maxKeys = 200
+)
+
+// Key is a field of sample data, as a dotted path such as customer.email,
+// with the JSON type of its value
+type Key struct {
+	Path string `json:"path"`
+	Type string `json:"type"`
+}
+
+// UpstreamNode is a node whose output a node's expressions can read
+type UpstreamNode struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	Type string `json:"type"`
+	// Direct nodes are connected to the node, so their items are its
+	// input, $json
+	Direct bool `json:"direct"`
+	// ExecutionID is the execution the keys were sampled from, nil when
+	// the node has no output in the latest executions
+	ExecutionID *uuid.UUID `json:"execution_id,omitempty"`
+	Keys        []Key      `json:"keys"`
+}
+
+// ExpressionContext describes what the expressions of a node can reference
+type ExpressionContext struct {
+	NodeID  string              `json:"node_id"`
+	Symbols []expression.Symbol `json:"symbols"`
+	// Nodes are the upstream nodes in execution order
+	Nodes     []UpstreamNode `json:"nodes"`
+	Variables []Key          `json:"variables"`
+	// Secrets are the names of the workflow secrets
+	Secrets []string `json:"secrets"`
+}
+
+// Autocomplete describes the expression context of nodes for the editor
+type Autocomplete struct {
+	workflows  *Service
+	executions execution.Repository
+	nodeRuns   execution.NodeExecutionRepository
+	secrets    domain.SecretRepository
+}
+
+// NewAutocomplete creates a new expression autocomplete service
+func NewAutocomplete(workflows *Service, executions execution.Repository, nodeRuns execution.NodeExecutionRepository, secrets domain.SecretRepository) *Autocomplete {
+	return &Autocomplete{
+		workflows:  workflows,
+		executions: executions,
+		nodeRuns:   nodeRuns,
+		secrets:    secrets,
+	}
+}
+
+// Context returns the expression context of a node of the workflow draft.
+// Output keys of the upstream nodes are sampled from their latest
+// successful run among the latest executions.
+func (a *Autocomplete) Context(ctx context.Context, w *domain.Workflow, nodeID string) (*ExpressionContext, error) {
+	graph, err := a.workflows.Graph(w)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := graph.Node(nodeID); !ok {
+		return nil, domain.ErrNodeNotFound
+	}
+
+	direct := make(map[string]bool)
+	for _, c := range graph.Incoming(nodeID) {
+		direct[c.Source.NodeID] = true
+	}
+	upstream := graph.Upstream(nodeID)
+	nodes := make([]UpstreamNode, len(upstream))
+	index := make(map[string]int, len(upstream))
+	for i, id := range upstream {
+		n, _ := graph.Node(id)
+		nodes[i] = UpstreamNode{ID: n.ID, Name: n.Name, Type: n.Type, Direct: direct[id], Keys: []Key{}}
+		index[id] = i
+	}
+	if err := a.sample(ctx, w.ID, nodes, index); err != nil {
+		return nil, err
+	}
+
+	secrets, err := a.secrets.ListByWorkflow(ctx, w.ID)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, len(secrets))
+	for i, s := range secrets {
+		names[i] = s.Name
+	}
+
+	return &ExpressionContext{
+		NodeID:    nodeID,
+		Symbols:   expression.Symbols(),
+		Nodes:     nodes,
+		Variables: describeKeys(w.Variables),
+		Secrets:   names,
+	}, nil
+}
+
+// sample fills in the output keys of the nodes from the latest executions
+// that ran them
+func (a *Autocomplete) sample(ctx context.Context, workflowID uuid.UUID, nodes []UpstreamNode, index map[string]int) error {
+	if len(nodes) == 0 {
+		return nil
+	}
+	latest, err := a.executions.ListLatest(ctx, []uuid.UUID{workflowID}, sampleExecutions)
+	if err != nil {
+		return err
+	}
+
+	remaining := len(nodes)
+	for _, e := range latest {
+		runs, err := a.nodeRuns.ListByExecution(ctx, e.ID)
+		if err != nil {
+			return err
+		}
+		// Runs are in start order, so the last run of a node wins
+		for i := len(runs) - 1; i >= 0; i-- {
+			run := runs[i]
+			n, ok := index[run.NodeID]
+			if !ok || nodes[n].ExecutionID != nil || run.Status != execution.ExecutionStatusSuccess {
+				continue
+			}
+			items := outputItems(run.OutputData)
+			if len(items) == 0 {
+				continue
+			}
+			executionID := e.ID
+			nodes[n].ExecutionID = &executionID
+			nodes[n].Keys = describeItems(items)
+			remaining--
+		}
+		if remaining == 0 {
+			break
+		}
+	}
+	return nil
+}
+
+// outputItems returns the JSON of the items in stored node output
+func outputItems(output map[string]interface{}) []map[string]interface{} {
+	data, _ := output["data"].([]interface{})
+	items := make([]map[string]interface{}, 0, min(len(data), sampleItems))
+	for _, item := range data {
+		if len(items) == sampleItems {
+			break
+		}
+		m, _ := item.(map[string]interface{})
+		if fields, ok := m["json"].(map[string]interface{}); ok {
+			items = append(items, fields)
+		}
+	}
+	return items
+}
+
+// describeItems returns the keys found in any of the items
+func describeItems(items []map[string]interface{}) []Key {
+	types := make(map[string]string)
+	for _, item := range items {
+		collectKeys(types, "", item, 1)
+	}
+	return sortedKeys(types)
+}
+
+// describeKeys returns the keys of a single object
+func describeKeys(values map[string]interface{}) []Key {
+	types := make(map[string]string)
+	collectKeys(types, "", values, 1)
+	return sortedKeys(types)
+}
+
+func collectKeys(types map[string]string, prefix string, values map[string]interface{}, depth int) {
+	for k, v := range values {
+		if len(types) >= maxKeys {
+			return
+		}
+		path := joinKey(prefix, k)
+		kind := typeOf(v)
+		if existing, ok := types[path]; !ok || existing == expression.KindNull {
+			types[path] = kind
+		} else if existing != kind && kind != expression.KindNull {
+			types[path] = expression.KindAny
+		}
+		if nested, ok := v.(map[string]interface{}); ok && depth < maxKeyDepth {
+			collectKeys(types, path, nested, depth+1)
+		}
+	}
+}
+
+func joinKey(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+	return prefix + "." + key
+}
+
+func sortedKeys(types map[string]string) []Key {
+	keys := make([]Key, 0, len(types))
+	for path, kind := range types {
+		keys = append(keys, Key{Path: path, Type: kind})
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].Path < keys[j].Path })
+	return keys
+}
+
+// typeOf returns the JSON type of a decoded value
+func typeOf(v interface{}) string {
+	switch v.(type) {
+	case nil:
+		return expression.KindNull
+	case string:
+		return expression.KindString
+	case bool:
+		return expression.KindBoolean
+	case float64, float32, int, int64, int32:
+		return expression.KindNumber
+	case []interface{}:
+		return expression.KindArray
+	case map[string]interface{}:
+		return expression.KindObject
+	default:
+		return expression.KindAny
+	}
+}
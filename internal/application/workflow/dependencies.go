@@ -0,0 +1,441 @@
+package workflow
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jaydeep/go-n8n/internal/domain/credential"
+	domain "github.com/jaydeep/go-n8n/internal/domain/workflow"
+	"github.com/jaydeep/go-n8n/internal/expression"
+	"github.com/jaydeep/go-n8n/internal/nodes/core/action"
+	"github.com/jaydeep/go-n8n/internal/nodes/core/flow"
+	"github.com/jaydeep/go-n8n/internal/nodes/core/trigger"
+	"github.com/jaydeep/go-n8n/pkg/nodesdk"
+)
+
+// dependencyBatch bounds a page of the workflows scanned for references
+const dependencyBatch = 500
+
+// How a workflow depends on another
+const (
+	// DependencySubWorkflow: an Execute Workflow node runs the workflow
+	DependencySubWorkflow = "sub_workflow"
+	// DependencyErrorWorkflow: the workflow runs when an execution fails
+	DependencyErrorWorkflow = "error_workflow"
+	// DependencyWebhook: an HTTP Request node calls a webhook of the
+	// workflow
+	DependencyWebhook = "webhook"
+)
+
+var (
+	// varsPattern matches a variable read in an expression, as $vars.NAME
+	// or $vars["NAME"]
+	varsPattern = regexp.MustCompile(`\$vars(?:\.([A-Za-z_$][A-Za-z0-9_$]*)|\[\s*["']([^"']+)["']\s*\])`)
+	// webhookURLPattern matches the path of a webhook of the instance in a
+	// URL
+	webhookURLPattern = regexp.MustCompile(`/api/v1/webhook/([^/?#]+)`)
+)
+
+// Dependencies is what a workflow needs to run, found in its draft
+type Dependencies struct {
+	WorkflowID  uuid.UUID              `json:"workflow_id"`
+	Credentials []CredentialDependency `json:"credentials"`
+	Variables   []VariableDependency   `json:"variables"`
+	// Workflows are the sub-workflows and the error workflow it runs
+	Workflows []WorkflowDependency `json:"workflows"`
+	// ProducedWebhooks are the webhooks its triggers listen on, and
+	// ConsumedWebhooks those of the instance its HTTP Request nodes call
+	ProducedWebhooks []ProducedWebhook `json:"produced_webhooks"`
+	ConsumedWebhooks []ConsumedWebhook `json:"consumed_webhooks"`
+}
+
+// CredentialDependency is a credential the nodes of a workflow use.
+// Missing credentials were deleted; the nodes using them fail.
+type CredentialDependency struct {
+	ID      uuid.UUID `json:"id"`
+	Name    string    `json:"name,omitempty"`
+	Type    string    `json:"type,omitempty"`
+	Missing bool      `json:"missing"`
+	Nodes   []string  `json:"nodes"`
+}
+
+// VariableDependency is a variable of a workflow or one its expressions
+// read. Variables read but not defined are undefined in the expressions.
+type VariableDependency struct {
+	Name    string   `json:"name"`
+	Defined bool     `json:"defined"`
+	Nodes   []string `json:"nodes"`
+}
+
+// WorkflowDependency is a workflow another runs, as Kind says. Name is
+// empty for the workflows of other users, and Nodes for the error
+// workflow, set in the settings.
+type WorkflowDependency struct {
+	ID      uuid.UUID `json:"id"`
+	Name    string    `json:"name,omitempty"`
+	Kind    string    `json:"kind"`
+	Missing bool      `json:"missing"`
+	Nodes   []string  `json:"nodes"`
+}
+
+// ProducedWebhook is a webhook a trigger of a workflow listens on
+type ProducedWebhook struct {
+	Node   string `json:"node"`
+	Method string `json:"method,omitempty"`
+	Path   string `json:"path"`
+}
+
+// ConsumedWebhook is a webhook of the instance an HTTP Request node calls,
+// with the workflow listening on it, if any
+type ConsumedWebhook struct {
+	Node         string     `json:"node"`
+	URL          string     `json:"url"`
+	Path         string     `json:"path"`
+	WorkflowID   *uuid.UUID `json:"workflow_id,omitempty"`
+	WorkflowName string     `json:"workflow_name,omitempty"`
+}
+
+// Dependent is a workflow that depends on another, as Kind says, and would
+// break if it were deleted
+type Dependent struct {
+	WorkflowID uuid.UUID `json:"workflow_id"`
+	Name       string    `json:"name"`
+	Active     bool      `json:"active"`
+	Kind       string    `json:"kind"`
+	Nodes      []string  `json:"nodes"`
+}
+
+// DependencyGraph finds what workflows depend on, and which workflows
+// depend on a workflow, from the nodes and settings of their drafts
+type DependencyGraph struct {
+	workflows   domain.Repository
+	credentials credential.Repository
+}
+
+// NewDependencyGraph creates a new dependency graph
+func NewDependencyGraph(workflows domain.Repository, credentials credential.Repository) *DependencyGraph {
+	return &DependencyGraph{workflows: workflows, credentials: credentials}
+}
+
+// Of returns the credentials, variables, workflows and webhooks the draft
+// of a workflow depends on. The workflows it depends on are only named
+// when owned by userID, or always when userID is nil.
+func (g *DependencyGraph) Of(ctx context.Context, w *domain.Workflow, userID *uuid.UUID) (*Dependencies, error) {
+	deps := &Dependencies{
+		WorkflowID:       w.ID,
+		Credentials:      []CredentialDependency{},
+		Variables:        []VariableDependency{},
+		Workflows:        []WorkflowDependency{},
+		ProducedWebhooks: []ProducedWebhook{},
+		ConsumedWebhooks: []ConsumedWebhook{},
+	}
+
+	credentialNodes := make(map[uuid.UUID][]string)
+	var credentialIDs []uuid.UUID
+	variableNodes := make(map[string][]string)
+	for name := range w.Variables {
+		variableNodes[name] = nil
+	}
+	for _, n := range w.Nodes {
+		if n.CredentialID != nil {
+			if _, ok := credentialNodes[*n.CredentialID]; !ok {
+				credentialIDs = append(credentialIDs, *n.CredentialID)
+			}
+			credentialNodes[*n.CredentialID] = append(credentialNodes[*n.CredentialID], n.Name)
+		}
+		for _, name := range variableReads(n.Parameters) {
+			variableNodes[name] = append(variableNodes[name], n.Name)
+		}
+		switch n.Type {
+		case trigger.WebhookTriggerType:
+			settings := trigger.ParseWebhookSettings(n.Parameters)
+			deps.ProducedWebhooks = append(deps.ProducedWebhooks, ProducedWebhook{Node: n.Name, Method: settings.Method, Path: settings.Path})
+		case action.HTTPRequestType:
+			if consumed, ok := consumedWebhook(n); ok {
+				deps.ConsumedWebhooks = append(deps.ConsumedWebhooks, consumed)
+			}
+		}
+	}
+
+	for _, id := range credentialIDs {
+		dep := CredentialDependency{ID: id, Nodes: credentialNodes[id]}
+		c, err := g.credentials.FindByID(ctx, id)
+		switch {
+		case errors.Is(err, credential.ErrCredentialNotFound):
+			dep.Missing = true
+		case err != nil:
+			return nil, err
+		default:
+			dep.Name, dep.Type = c.Name, c.Type
+		}
+		deps.Credentials = append(deps.Credentials, dep)
+	}
+
+	for name, nodes := range variableNodes {
+		_, defined := w.Variables[name]
+		deps.Variables = append(deps.Variables, VariableDependency{Name: name, Defined: defined, Nodes: unique(nodes)})
+	}
+	sort.Slice(deps.Variables, func(i, j int) bool { return deps.Variables[i].Name < deps.Variables[j].Name })
+
+	workflows, err := g.workflowsOf(ctx, w, userID)
+	if err != nil {
+		return nil, err
+	}
+	deps.Workflows = workflows
+
+	if len(deps.ConsumedWebhooks) > 0 {
+		if err := g.resolveWebhooks(ctx, deps.ConsumedWebhooks, userID); err != nil {
+			return nil, err
+		}
+	}
+	return deps, nil
+}
+
+// Dependents returns the workflows owned by userID, or all workflows when
+// userID is nil, that depend on a workflow: those running it as a
+// sub-workflow or error workflow, and those calling its webhooks
+func (g *DependencyGraph) Dependents(ctx context.Context, w *domain.Workflow, userID *uuid.UUID) ([]Dependent, error) {
+	paths := make(map[string]bool)
+	for _, n := range w.Nodes {
+		if n.Type == trigger.WebhookTriggerType {
+			if path := trigger.ParseWebhookSettings(n.Parameters).Path; path != "" {
+				paths[path] = true
+			}
+		}
+	}
+
+	dependents := []Dependent{}
+	err := g.scan(ctx, userID, func(other *domain.Workflow) {
+		if other.ID == w.ID {
+			return
+		}
+		add := func(kind string, nodes []string) {
+			dependents = append(dependents, Dependent{WorkflowID: other.ID, Name: other.Name, Active: other.IsActive, Kind: kind, Nodes: nodes})
+		}
+		if nodes := subWorkflowNodes(other)[w.ID]; len(nodes) > 0 {
+			add(DependencySubWorkflow, nodes)
+		}
+		if errorWorkflow := other.Settings.ErrorWorkflow; errorWorkflow != nil && *errorWorkflow == w.ID {
+			add(DependencyErrorWorkflow, []string{})
+		}
+		var callers []string
+		for _, n := range other.Nodes {
+			if n.Type != action.HTTPRequestType {
+				continue
+			}
+			if consumed, ok := consumedWebhook(n); ok && paths[consumed.Path] {
+				callers = append(callers, n.Name)
+			}
+		}
+		if len(callers) > 0 {
+			add(DependencyWebhook, unique(callers))
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	return dependents, nil
+}
+
+// References are the credentials, workflows and variables the nodes of a
+// workflow refer to. AllVariables is set when an expression reads $vars
+// other than by name, which may read any variable.
+type References struct {
+	Credentials  []uuid.UUID
+	Workflows    []uuid.UUID
+	Variables    []string
+	AllVariables bool
+}
+
+// ReferencesOf returns what nodes refer to. Sub-workflows run by IDs set
+// by expressions are only known at run time and are left out.
+func ReferencesOf(nodes []domain.Node) References {
+	var refs References
+	for _, n := range nodes {
+		if n.CredentialID != nil {
+			refs.Credentials = append(refs.Credentials, *n.CredentialID)
+		}
+		if n.Type == flow.ExecuteWorkflowType {
+			if id, err := uuid.Parse(nodesdk.GetString(n.Parameters, "workflow_id", "")); err == nil {
+				refs.Workflows = append(refs.Workflows, id)
+			}
+		}
+		refs.Variables = append(refs.Variables, variableReads(n.Parameters)...)
+		refs.AllVariables = refs.AllVariables || readsAllVariables(n.Parameters)
+	}
+	return refs
+}
+
+// workflowsOf returns the sub-workflows and the error workflow of a
+// workflow, sub-workflows first, named when owned by userID
+func (g *DependencyGraph) workflowsOf(ctx context.Context, w *domain.Workflow, userID *uuid.UUID) ([]WorkflowDependency, error) {
+	deps := []WorkflowDependency{}
+	nodes := subWorkflowNodes(w)
+	var ids []uuid.UUID
+	for id := range nodes {
+		ids = append(ids, id)
+		deps = append(deps, WorkflowDependency{ID: id, Kind: DependencySubWorkflow, Nodes: nodes[id]})
+	}
+	sort.Slice(deps, func(i, j int) bool { return deps[i].ID.String() < deps[j].ID.String() })
+	if id := w.Settings.ErrorWorkflow; id != nil {
+		ids = append(ids, *id)
+		deps = append(deps, WorkflowDependency{ID: *id, Kind: DependencyErrorWorkflow, Nodes: []string{}})
+	}
+	if len(ids) == 0 {
+		return deps, nil
+	}
+
+	found, err := g.workflows.FindByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	names := make(map[uuid.UUID]string, len(found))
+	for _, other := range found {
+		switch {
+		case other.DeletedAt != nil:
+		case userID == nil || other.UserID == *userID:
+			names[other.ID] = other.Name
+		default:
+			names[other.ID] = ""
+		}
+	}
+	for i := range deps {
+		name, ok := names[deps[i].ID]
+		deps[i].Name, deps[i].Missing = name, !ok
+	}
+	return deps, nil
+}
+
+// resolveWebhooks sets the workflows owned by userID listening on the
+// webhooks called
+func (g *DependencyGraph) resolveWebhooks(ctx context.Context, consumed []ConsumedWebhook, userID *uuid.UUID) error {
+	return g.scan(ctx, userID, func(other *domain.Workflow) {
+		for _, n := range other.Nodes {
+			if n.Type != trigger.WebhookTriggerType {
+				continue
+			}
+			path := trigger.ParseWebhookSettings(n.Parameters).Path
+			for i := range consumed {
+				if consumed[i].WorkflowID == nil && consumed[i].Path == path {
+					id := other.ID
+					consumed[i].WorkflowID, consumed[i].WorkflowName = &id, other.Name
+				}
+			}
+		}
+	})
+}
+
+// scan calls fn with every workflow owned by userID, or every workflow
+// when userID is nil, reading them in batches
+func (g *DependencyGraph) scan(ctx context.Context, userID *uuid.UUID, fn func(*domain.Workflow)) error {
+	for offset := 0; ; offset += dependencyBatch {
+		page, err := g.workflows.List(ctx, userID, dependencyBatch, offset)
+		if err != nil {
+			return err
+		}
+		for _, w := range page {
+			fn(w)
+		}
+		if len(page) < dependencyBatch {
+			return nil
+		}
+	}
+}
+
+// subWorkflowNodes returns the names of the Execute Workflow nodes of a
+// workflow by the workflow they run. IDs set by expressions are only known
+// at run time and are left out.
+func subWorkflowNodes(w *domain.Workflow) map[uuid.UUID][]string {
+	nodes := make(map[uuid.UUID][]string)
+	for _, n := range w.Nodes {
+		if n.Type != flow.ExecuteWorkflowType {
+			continue
+		}
+		id, err := uuid.Parse(nodesdk.GetString(n.Parameters, "workflow_id", ""))
+		if err == nil {
+			nodes[id] = append(nodes[id], n.Name)
+		}
+	}
+	return nodes
+}
+
+// consumedWebhook returns the webhook of the instance a node calls, if its
+// URL has the path of one
+func consumedWebhook(n domain.Node) (ConsumedWebhook, bool) {
+	target := nodesdk.GetString(n.Parameters, "url", "")
+	match := webhookURLPattern.FindStringSubmatch(target)
+	if match == nil || expression.HasExpression(match[1]) {
+		return ConsumedWebhook{}, false
+	}
+	return ConsumedWebhook{Node: n.Name, URL: target, Path: match[1]}, true
+}
+
+// variableReads returns the names of the variables the expressions in
+// parameter values read
+func variableReads(value interface{}) []string {
+	var names []string
+	switch v := value.(type) {
+	case string:
+		if !expression.HasExpression(v) || !strings.Contains(v, "$vars") {
+			return nil
+		}
+		for _, match := range varsPattern.FindAllStringSubmatch(v, -1) {
+			if match[1] != "" {
+				names = append(names, match[1])
+			} else {
+				names = append(names, match[2])
+			}
+		}
+	case map[string]interface{}:
+		for _, item := range v {
+			names = append(names, variableReads(item)...)
+		}
+	case []interface{}:
+		for _, item := range v {
+			names = append(names, variableReads(item)...)
+		}
+	}
+	return names
+}
+
+// readsAllVariables reports whether the expressions in parameter values
+// read $vars other than by name, such as $vars[key] or $vars itself
+func readsAllVariables(value interface{}) bool {
+	switch v := value.(type) {
+	case string:
+		if !expression.HasExpression(v) {
+			return false
+		}
+		return strings.Count(v, "$vars") > len(varsPattern.FindAllStringIndex(v, -1))
+	case map[string]interface{}:
+		for _, item := range v {
+			if readsAllVariables(item) {
+				return true
+			}
+		}
+	case []interface{}:
+		for _, item := range v {
+			if readsAllVariables(item) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// unique returns names without duplicates, in order
+func unique(names []string) []string {
+	out := []string{}
+	seen := make(map[string]bool, len(names))
+	for _, name := range names {
+		if !seen[name] {
+			seen[name] = true
+			out = append(out, name)
+		}
+	}
+	return out
+}
@@ -0,0 +1,100 @@
+package workflow
+
+import (
+	"container/list"
+	"sync"
+
+	"github.com/google/uuid"
+	domain "github.com/jaydeep/go-n8n/internal/domain/workflow"
+)
+
+const defaultGraphCacheSize = 1000
+
+// graphKey identifies a published version of a workflow
+type graphKey struct {
+	workflowID uuid.UUID
+	version    int
+}
+
+// GraphCache keeps the compiled graphs of the most recently used workflow
+// versions, so frequently triggered workflows are compiled once rather than
+// on every execution. Published versions never change, so entries don't
+// expire; the least recently used are dropped once the cache is full.
+type GraphCache struct {
+	size int
+	// loops reports whether connections may loop back to nodes of a type
+	loops func(nodeType string) bool
+
+	mu      sync.Mutex
+	entries map[graphKey]*list.Element
+	recent  *list.List
+}
+
+// NewGraphCache creates a cache holding up to size graphs, compiled with
+// loops back to the node types loops accepts
+func NewGraphCache(size int, loops func(nodeType string) bool) *GraphCache {
+	if size <= 0 {
+		size = defaultGraphCacheSize
+	}
+	return &GraphCache{
+		size:    size,
+		loops:   loops,
+		entries: make(map[graphKey]*list.Element),
+		recent:  list.New(),
+	}
+}
+
+// Get returns the graph of a published workflow version, compiling it on
+// first use. Compile errors are not cached.
+func (c *GraphCache) Get(w *domain.Workflow) (*domain.Graph, error) {
+	key := graphKey{workflowID: w.ID, version: w.Version}
+
+	c.mu.Lock()
+	if el, ok := c.entries[key]; ok {
+		c.recent.MoveToFront(el)
+		c.mu.Unlock()
+		return el.Value.(*domain.Graph), nil
+	}
+	c.mu.Unlock()
+
+	g, err := domain.Compile(w, c.loops)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if el, ok := c.entries[key]; ok {
+		// Compiled concurrently by another caller
+		c.recent.MoveToFront(el)
+		return el.Value.(*domain.Graph), nil
+	}
+	c.entries[key] = c.recent.PushFront(g)
+	if c.recent.Len() > c.size {
+		oldest := c.recent.Back()
+		c.recent.Remove(oldest)
+		old := oldest.Value.(*domain.Graph)
+		delete(c.entries, graphKey{workflowID: old.WorkflowID, version: old.Version})
+	}
+	return g, nil
+}
+
+// Invalidate drops the graphs of every version of a workflow
+func (c *GraphCache) Invalidate(workflowID uuid.UUID) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for key, el := range c.entries {
+		if key.workflowID == workflowID {
+			c.recent.Remove(el)
+			delete(c.entries, key)
+		}
+	}
+}
+
+// Len returns the number of cached graphs
+func (c *GraphCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.recent.Len()
+}
@@ -0,0 +1,98 @@
+package workflow
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jaydeep/go-n8n/internal/application/audit"
+	auditlog "github.com/jaydeep/go-n8n/internal/domain/audit"
+	"github.com/jaydeep/go-n8n/internal/domain/node"
+	domain "github.com/jaydeep/go-n8n/internal/domain/workflow"
+)
+
+// PinnedDataService manages the output pinned to the nodes of workflows,
+// which test executions use in place of running the nodes
+type PinnedDataService struct {
+	repo  domain.PinnedDataRepository
+	audit *audit.Service
+}
+
+// NewPinnedDataService creates a new pinned data service
+func NewPinnedDataService(repo domain.PinnedDataRepository, auditService *audit.Service) *PinnedDataService {
+	return &PinnedDataService{repo: repo, audit: auditService}
+}
+
+// List returns the pinned data of the nodes of a workflow
+func (s *PinnedDataService) List(ctx context.Context, workflowID uuid.UUID) ([]*domain.PinnedData, error) {
+	return s.repo.ListByWorkflow(ctx, workflowID)
+}
+
+// Pin pins items as the output of a node of the draft of a workflow,
+// replacing those pinned before
+func (s *PinnedDataService) Pin(ctx context.Context, w *domain.Workflow, actor audit.Actor, nodeID string, items []node.Item) (*domain.PinnedData, error) {
+	if !hasNode(w, nodeID) {
+		return nil, domain.ErrNodeNotFound
+	}
+	if err := domain.ValidatePinnedItems(items); err != nil {
+		return nil, err
+	}
+
+	pinned := &domain.PinnedData{WorkflowID: w.ID, NodeID: nodeID, Items: items}
+	if actor.UserID != uuid.Nil {
+		pinned.PinnedBy = &actor.UserID
+	}
+	if err := s.repo.Save(ctx, pinned); err != nil {
+		return nil, err
+	}
+	s.record(ctx, actor, auditlog.ActionNodeDataPinned, w.ID, nodeID, len(items))
+	return pinned, nil
+}
+
+// Unpin removes the pinned data of a node, which runs again in test
+// executions
+func (s *PinnedDataService) Unpin(ctx context.Context, workflowID uuid.UUID, actor audit.Actor, nodeID string) error {
+	if err := s.repo.Delete(ctx, workflowID, nodeID); err != nil {
+		return err
+	}
+	s.record(ctx, actor, auditlog.ActionNodeDataUnpinned, workflowID, nodeID, 0)
+	return nil
+}
+
+// Items returns the items pinned to the nodes of a workflow by node ID,
+// which the engine outputs in place of running the nodes of test
+// executions
+func (s *PinnedDataService) Items(ctx context.Context, workflowID uuid.UUID) (map[string][]node.Item, error) {
+	pinned, err := s.repo.ListByWorkflow(ctx, workflowID)
+	if err != nil {
+		return nil, err
+	}
+	items := make(map[string][]node.Item, len(pinned))
+	for _, p := range pinned {
+		items[p.NodeID] = p.Items
+	}
+	return items, nil
+}
+
+// record logs a change to the pinned data of a node, never the items
+func (s *PinnedDataService) record(ctx context.Context, actor audit.Actor, action string, workflowID uuid.UUID, nodeID string, items int) {
+	value := map[string]interface{}{"node_id": nodeID}
+	if items > 0 {
+		value["items"] = items
+	}
+	s.audit.Record(ctx, actor, &auditlog.AuditLog{
+		Action:       action,
+		ResourceType: auditlog.ResourceWorkflow,
+		ResourceID:   workflowID.String(),
+		NewValue:     value,
+	})
+}
+
+// hasNode reports whether the draft of a workflow has a node with an ID
+func hasNode(w *domain.Workflow, nodeID string) bool {
+	for _, n := range w.Nodes {
+		if n.ID == nodeID {
+			return true
+		}
+	}
+	return false
+}
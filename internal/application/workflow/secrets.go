@@ -0,0 +1,128 @@
+package workflow
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jaydeep/go-n8n/internal/application/audit"
+	auditlog "github.com/jaydeep/go-n8n/internal/domain/audit"
+	domain "github.com/jaydeep/go-n8n/internal/domain/workflow"
+	"github.com/jaydeep/go-n8n/pkg/encryption"
+)
+
+// SecretService manages the secrets of workflows. Values are encrypted
+// with the instance encryption key and only decrypted for executions.
+type SecretService struct {
+	repo   domain.SecretRepository
+	cipher *encryption.Cipher
+	audit  *audit.Service
+}
+
+// NewSecretService creates a new workflow secret service. Without a cipher,
+// secrets can be listed and deleted but not set or read.
+func NewSecretService(repo domain.SecretRepository, cipher *encryption.Cipher, auditService *audit.Service) *SecretService {
+	return &SecretService{repo: repo, cipher: cipher, audit: auditService}
+}
+
+// List returns the secrets of a workflow with their values masked
+func (s *SecretService) List(ctx context.Context, workflowID uuid.UUID) ([]*domain.Secret, error) {
+	secrets, err := s.repo.ListByWorkflow(ctx, workflowID)
+	if err != nil {
+		return nil, err
+	}
+	for _, secret := range secrets {
+		secret.Value = domain.SecretMask
+	}
+	return secrets, nil
+}
+
+// Set creates or replaces a secret of a workflow
+func (s *SecretService) Set(ctx context.Context, w *domain.Workflow, actor audit.Actor, name, value string) (*domain.Secret, error) {
+	if s.cipher == nil {
+		return nil, domain.ErrSecretsUnavailable
+	}
+	if err := domain.ValidateSecret(name, value); err != nil {
+		return nil, err
+	}
+
+	secret, err := s.repo.FindByName(ctx, w.ID, name)
+	created := errors.Is(err, domain.ErrSecretNotFound)
+	if err != nil && !created {
+		return nil, err
+	}
+	if created {
+		existing, err := s.repo.ListByWorkflow(ctx, w.ID)
+		if err != nil {
+			return nil, err
+		}
+		if len(existing) >= domain.MaxSecrets {
+			return nil, domain.ErrTooManySecrets
+		}
+		secret = &domain.Secret{WorkflowID: w.ID, Name: name}
+	}
+
+	secret.Data, secret.IV, err = s.cipher.Encrypt([]byte(value))
+	if err != nil {
+		return nil, err
+	}
+	if actor.UserID != uuid.Nil {
+		secret.UpdatedBy = &actor.UserID
+	}
+	if created {
+		err = s.repo.Create(ctx, secret)
+	} else {
+		err = s.repo.Update(ctx, secret)
+	}
+	if err != nil {
+		return nil, err
+	}
+	s.record(ctx, actor, auditlog.ActionWorkflowSecretSet, w.ID, name)
+	secret.Value = domain.SecretMask
+	return secret, nil
+}
+
+// Delete removes a secret of a workflow
+func (s *SecretService) Delete(ctx context.Context, workflowID uuid.UUID, actor audit.Actor, name string) error {
+	if err := s.repo.Delete(ctx, workflowID, name); err != nil {
+		return err
+	}
+	s.record(ctx, actor, auditlog.ActionWorkflowSecretDeleted, workflowID, name)
+	return nil
+}
+
+// Values returns the decrypted secrets of a workflow by name, which the
+// engine exposes to expressions as $secrets through
+// node.ExecutionContext.Secrets
+func (s *SecretService) Values(ctx context.Context, workflowID uuid.UUID) (map[string]string, error) {
+	secrets, err := s.repo.ListByWorkflow(ctx, workflowID)
+	if err != nil {
+		return nil, err
+	}
+	values := make(map[string]string, len(secrets))
+	if len(secrets) == 0 {
+		return values, nil
+	}
+	if s.cipher == nil {
+		return nil, domain.ErrSecretsUnavailable
+	}
+	for _, secret := range secrets {
+		value, err := s.cipher.Decrypt(secret.Data, secret.IV)
+		if err != nil {
+			return nil, fmt.Errorf("secret %s: %w", secret.Name, err)
+		}
+		values[secret.Name] = string(value)
+	}
+	return values, nil
+}
+
+// record logs a change to a secret by name, never its value
+func (s *SecretService) record(ctx context.Context, actor audit.Actor, action string, workflowID uuid.UUID, name string) {
+	s.audit.Record(ctx, actor, &auditlog.AuditLog{
+		Action:       action,
+		ResourceType: auditlog.ResourceWorkflow,
+		ResourceID:   workflowID.String(),
+		NewValue:     map[string]interface{}{"secret": name},
+	})
+}
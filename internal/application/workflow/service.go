@@ -0,0 +1,575 @@
+package workflow
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/jaydeep/go-n8n/configs"
+	"github.com/jaydeep/go-n8n/internal/application/audit"
+	"github.com/jaydeep/go-n8n/internal/application/events"
+	auditlog "github.com/jaydeep/go-n8n/internal/domain/audit"
+	domain "github.com/jaydeep/go-n8n/internal/domain/workflow"
+	"github.com/jaydeep/go-n8n/pkg/logger"
+)
+
+// DraftInput holds the editable fields of a workflow draft. Nil fields are
+// left unchanged; an empty slug removes the workflow's slug.
+type DraftInput struct {
+	Name        *string                  `json:"name"`
+	Slug        *string                  `json:"slug"`
+	Description *string                  `json:"description"`
+	Nodes       []domain.Node            `json:"nodes"`
+	Connections []domain.Connection      `json:"connections"`
+	Settings    *domain.WorkflowSettings `json:"settings"`
+	Tags        []string                 `json:"tags"`
+	Variables   map[string]interface{}   `json:"variables"`
+}
+
+// PublishResult is the outcome of a publish request: either the published
+// version, or a pending change request when the workflow requires approval
+type PublishResult struct {
+	Version       *domain.WorkflowVersion `json:"version,omitempty"`
+	ChangeRequest *domain.ChangeRequest   `json:"change_request,omitempty"`
+}
+
+// Service implements workflow use cases. Edits always go to the draft;
+// triggers and the engine run the explicitly published version.
+type Service struct {
+	repo           domain.Repository
+	versions       domain.VersionRepository
+	changeRequests domain.ChangeRequestRepository
+	audit          *audit.Service
+	events         events.Publisher
+	approval       configs.ApprovalConfig
+	queues         []string
+	regions        map[string]string
+	graphs         *GraphCache
+	log            *logger.Logger
+}
+
+// NewService creates a new workflow service. Workflows can be pinned to
+// the queues listed and tagged with the regions mapped to their queues.
+func NewService(
+	repo domain.Repository,
+	versions domain.VersionRepository,
+	changeRequests domain.ChangeRequestRepository,
+	auditService *audit.Service,
+	publisher events.Publisher,
+	approval configs.ApprovalConfig,
+	queues []string,
+	regions map[string]string,
+	graphs *GraphCache,
+	log *logger.Logger,
+) *Service {
+	return &Service{
+		repo:           repo,
+		versions:       versions,
+		changeRequests: changeRequests,
+		audit:          auditService,
+		events:         publisher,
+		approval:       approval,
+		queues:         queues,
+		regions:        regions,
+		graphs:         graphs,
+		log:            log,
+	}
+}
+
+// Get returns the workflow with its current draft
+func (s *Service) Get(ctx context.Context, id uuid.UUID) (*domain.Workflow, error) {
+	return s.repo.FindByID(ctx, id)
+}
+
+// FindByIDs returns the workflows with the given IDs that exist
+func (s *Service) FindByIDs(ctx context.Context, ids []uuid.UUID) ([]*domain.Workflow, error) {
+	return s.repo.FindByIDs(ctx, ids)
+}
+
+// Published returns the workflow with the graph of its published version
+func (s *Service) Published(ctx context.Context, id uuid.UUID) (*domain.Workflow, error) {
+	w, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if w.PublishedVersion == nil {
+		return nil, domain.ErrWorkflowNotPublished
+	}
+
+	v, err := s.versions.FindByVersion(ctx, id, *w.PublishedVersion)
+	if err != nil {
+		return nil, err
+	}
+	return v.Apply(w), nil
+}
+
+// AtVersion returns the workflow with the graph of one of its published
+// versions. Version 0 selects the current published version.
+func (s *Service) AtVersion(ctx context.Context, id uuid.UUID, version int) (*domain.Workflow, error) {
+	if version == 0 {
+		return s.Published(ctx, id)
+	}
+
+	w, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	v, err := s.versions.FindByVersion(ctx, id, version)
+	if err != nil {
+		return nil, err
+	}
+	return v.Apply(w), nil
+}
+
+// Graph returns the compiled graph of a workflow. Published versions are
+// compiled once and cached; drafts still being edited are compiled on
+// every call.
+func (s *Service) Graph(w *domain.Workflow) (*domain.Graph, error) {
+	if w.PublishedVersion == nil || w.Version > *w.PublishedVersion {
+		return domain.Compile(w, s.graphs.loops)
+	}
+	return s.graphs.Get(w)
+}
+
+// FindBySlug returns the workflow with the slug
+func (s *Service) FindBySlug(ctx context.Context, slug string) (*domain.Workflow, error) {
+	return s.repo.FindBySlug(ctx, slug)
+}
+
+// Create stores a new workflow under an ID chosen by the client, so that
+// creating it can be retried without duplicates
+func (s *Service) Create(ctx context.Context, id, ownerID uuid.UUID, input DraftInput) (*domain.Workflow, error) {
+	w := &domain.Workflow{ID: id, UserID: ownerID, Version: 1}
+	if err := applyDraft(w, input); err != nil {
+		return nil, err
+	}
+	if err := w.Validate(); err != nil {
+		return nil, err
+	}
+	if err := s.CheckQueue(w); err != nil {
+		return nil, err
+	}
+	if err := s.checkUnique(ctx, w); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Create(ctx, w); err != nil {
+		return nil, err
+	}
+	s.log.Infow("Workflow created", "workflow_id", w.ID, "user_id", ownerID)
+	return w, nil
+}
+
+// UpdateDraft applies changes to the draft without affecting the published
+// version. Changes that leave the content as it was are not saved and
+// start no new draft version, so repeating an update changes nothing.
+func (s *Service) UpdateDraft(ctx context.Context, id uuid.UUID, input DraftInput) (*domain.Workflow, error) {
+	w, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	hash := w.ContentHash()
+	if err := applyDraft(w, input); err != nil {
+		return nil, err
+	}
+	if w.ContentHash() == hash {
+		return w, nil
+	}
+	if err := s.CheckQueue(w); err != nil {
+		return nil, err
+	}
+	if err := s.checkUnique(ctx, w); err != nil {
+		return nil, err
+	}
+
+	w.BeginDraft()
+	if err := s.repo.Update(ctx, w); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// Delete deactivates a workflow and deletes it
+func (s *Service) Delete(ctx context.Context, id uuid.UUID, actor audit.Actor) error {
+	w, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if w.IsActive {
+		if _, err := s.Deactivate(ctx, id, actor); err != nil {
+			return err
+		}
+	}
+	if err := s.repo.Delete(ctx, id); err != nil {
+		return err
+	}
+	s.graphs.Invalidate(id)
+
+	s.audit.Record(ctx, actor, &auditlog.AuditLog{
+		Action:       auditlog.ActionWorkflowDeleted,
+		ResourceType: auditlog.ResourceWorkflow,
+		ResourceID:   id.String(),
+		OldValue:     map[string]interface{}{"name": w.Name, "content_hash": w.ContentHash()},
+	})
+	return nil
+}
+
+// applyDraft sets the non-nil fields of input on w
+func applyDraft(w *domain.Workflow, input DraftInput) error {
+	if input.Name != nil {
+		w.Name = *input.Name
+	}
+	if input.Slug != nil {
+		w.Slug = nil
+		if *input.Slug != "" {
+			if err := domain.ValidateSlug(*input.Slug); err != nil {
+				return err
+			}
+			slug := *input.Slug
+			w.Slug = &slug
+		}
+	}
+	if input.Description != nil {
+		w.Description = *input.Description
+	}
+	if input.Nodes != nil {
+		w.Nodes = input.Nodes
+	}
+	if input.Connections != nil {
+		w.Connections = input.Connections
+	}
+	if input.Settings != nil {
+		w.Settings = *input.Settings
+	}
+	if input.Tags != nil {
+		w.Tags = input.Tags
+	}
+	if input.Variables != nil {
+		w.Variables = input.Variables
+	}
+	return nil
+}
+
+// CheckQueue fails when w is pinned to a queue or tagged with a region that
+// is not configured, as no worker would take its executions, or pinned to
+// a queue other than that of its region
+func (s *Service) CheckQueue(w *domain.Workflow) error {
+	if w.Settings.Region != "" {
+		regionQueue, ok := s.regions[w.Settings.Region]
+		if !ok {
+			return domain.ErrUnknownRegion
+		}
+		if w.Settings.Queue != "" && w.Settings.Queue != regionQueue {
+			return domain.ErrQueueOutsideRegion
+		}
+		return nil
+	}
+	if w.Settings.Queue == "" {
+		return nil
+	}
+	for _, q := range s.queues {
+		if q == w.Settings.Queue {
+			return nil
+		}
+	}
+	return domain.ErrUnknownQueue
+}
+
+// checkUnique fails when another workflow has the slug of w, or the owner
+// of w has another workflow with its name
+func (s *Service) checkUnique(ctx context.Context, w *domain.Workflow) error {
+	existing, err := s.repo.FindByName(ctx, w.UserID, w.Name)
+	if err == nil && existing.ID != w.ID {
+		return domain.ErrWorkflowNameTaken
+	}
+	if err != nil && !errors.Is(err, domain.ErrWorkflowNotFound) {
+		return err
+	}
+	if w.Slug == nil {
+		return nil
+	}
+	existing, err = s.repo.FindBySlug(ctx, *w.Slug)
+	if err == nil && existing.ID != w.ID {
+		return domain.ErrWorkflowSlugTaken
+	}
+	if err != nil && !errors.Is(err, domain.ErrWorkflowNotFound) {
+		return err
+	}
+	return nil
+}
+
+// Publish pins the current draft as the version triggers execute. Workflows
+// that require approval get a pending change request instead.
+func (s *Service) Publish(ctx context.Context, id uuid.UUID, actor audit.Actor) (*PublishResult, error) {
+	w, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.RequiresApproval(w) {
+		cr, err := s.requestChange(ctx, w, actor)
+		if err != nil {
+			return nil, err
+		}
+		return &PublishResult{ChangeRequest: cr}, nil
+	}
+
+	snapshot, err := w.Publish(actor.UserID)
+	if err != nil {
+		return nil, err
+	}
+	// Reject graphs that cannot run; the compiled graph stays cached for
+	// the triggers of an active workflow
+	if _, err := s.Graph(w); err != nil {
+		return nil, err
+	}
+
+	if err := s.versions.Create(ctx, snapshot); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Update(ctx, w); err != nil {
+		return nil, err
+	}
+
+	s.audit.Record(ctx, actor, &auditlog.AuditLog{
+		Action:       auditlog.ActionWorkflowPublished,
+		ResourceType: auditlog.ResourceWorkflow,
+		ResourceID:   w.ID.String(),
+		NewValue:     map[string]interface{}{"version": snapshot.Version},
+	})
+
+	s.log.Infow("Workflow published", "workflow_id", w.ID, "version", snapshot.Version)
+	return &PublishResult{Version: snapshot}, nil
+}
+
+// RequiresApproval returns whether publishing the workflow needs an approver
+func (s *Service) RequiresApproval(w *domain.Workflow) bool {
+	return s.approval.Enabled && w.HasTag(s.approval.RequiredTags...)
+}
+
+// requestChange freezes the draft into a pending change request and moves
+// further edits to a new draft version
+func (s *Service) requestChange(ctx context.Context, w *domain.Workflow, actor audit.Actor) (*domain.ChangeRequest, error) {
+	if err := w.Validate(); err != nil {
+		return nil, err
+	}
+	if !w.HasUnpublishedChanges() {
+		return nil, domain.ErrWorkflowNoChanges
+	}
+
+	if _, err := s.changeRequests.FindPending(ctx, w.ID); err == nil {
+		return nil, domain.ErrChangeRequestPending
+	} else if !errors.Is(err, domain.ErrChangeRequestNotFound) {
+		return nil, err
+	}
+
+	var base *domain.WorkflowVersion
+	if w.PublishedVersion != nil {
+		published, err := s.versions.FindByVersion(ctx, w.ID, *w.PublishedVersion)
+		if err != nil {
+			return nil, err
+		}
+		base = published
+	}
+
+	proposed := domain.NewVersionSnapshot(w, actor.UserID)
+	cr := domain.NewChangeRequest(proposed, domain.DiffVersions(base, proposed), actor.UserID)
+	if err := s.changeRequests.Create(ctx, cr); err != nil {
+		return nil, err
+	}
+
+	w.IncrementVersion()
+	if err := s.repo.Update(ctx, w); err != nil {
+		return nil, err
+	}
+
+	s.audit.Record(ctx, actor, &auditlog.AuditLog{
+		Action:       auditlog.ActionWorkflowChangeRequested,
+		ResourceType: auditlog.ResourceWorkflowChangeRequest,
+		ResourceID:   cr.ID.String(),
+		OldValue:     map[string]interface{}{"published_version": w.PublishedVersion},
+		NewValue: map[string]interface{}{
+			"workflow_id": w.ID.String(),
+			"version":     cr.Version,
+			"diff":        audit.ToMap(cr.Diff),
+		},
+	})
+
+	return cr, nil
+}
+
+// ChangeRequests returns the change requests of a workflow, newest first
+func (s *Service) ChangeRequests(ctx context.Context, id uuid.UUID) ([]*domain.ChangeRequest, error) {
+	return s.changeRequests.ListByWorkflow(ctx, id)
+}
+
+// CommentOnChange adds a comment to a change request
+func (s *Service) CommentOnChange(ctx context.Context, workflowID, requestID uuid.UUID, actor audit.Actor, body string) (*domain.ChangeRequest, error) {
+	cr, err := s.findChangeRequest(ctx, workflowID, requestID)
+	if err != nil {
+		return nil, err
+	}
+
+	comment, err := cr.AddComment(actor.UserID, body)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.changeRequests.Update(ctx, cr); err != nil {
+		return nil, err
+	}
+
+	s.audit.Record(ctx, actor, &auditlog.AuditLog{
+		Action:       auditlog.ActionWorkflowChangeCommented,
+		ResourceType: auditlog.ResourceWorkflowChangeRequest,
+		ResourceID:   cr.ID.String(),
+		NewValue:     audit.ToMap(comment),
+	})
+
+	return cr, nil
+}
+
+// ApproveChange accepts a change request and publishes its frozen snapshot
+func (s *Service) ApproveChange(ctx context.Context, workflowID, requestID uuid.UUID, actor audit.Actor, note string) (*domain.ChangeRequest, error) {
+	cr, err := s.findChangeRequest(ctx, workflowID, requestID)
+	if err != nil {
+		return nil, err
+	}
+	w, err := s.repo.FindByID(ctx, workflowID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := cr.Approve(actor.UserID, note); err != nil {
+		return nil, err
+	}
+
+	snapshot := cr.Snapshot(actor.UserID)
+	snapshot.Name = w.Name
+	if err := s.versions.Create(ctx, snapshot); err != nil {
+		return nil, err
+	}
+	w.PinVersion(snapshot)
+	if err := s.repo.Update(ctx, w); err != nil {
+		return nil, err
+	}
+	if err := s.changeRequests.Update(ctx, cr); err != nil {
+		return nil, err
+	}
+
+	s.recordDecision(ctx, actor, cr, auditlog.ActionWorkflowChangeApproved)
+	s.log.Infow("Workflow change approved", "workflow_id", w.ID, "version", snapshot.Version)
+	return cr, nil
+}
+
+// RejectChange declines a change request
+func (s *Service) RejectChange(ctx context.Context, workflowID, requestID uuid.UUID, actor audit.Actor, note string) (*domain.ChangeRequest, error) {
+	cr, err := s.findChangeRequest(ctx, workflowID, requestID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := cr.Reject(actor.UserID, note); err != nil {
+		return nil, err
+	}
+	if err := s.changeRequests.Update(ctx, cr); err != nil {
+		return nil, err
+	}
+
+	s.recordDecision(ctx, actor, cr, auditlog.ActionWorkflowChangeRejected)
+	return cr, nil
+}
+
+func (s *Service) findChangeRequest(ctx context.Context, workflowID, requestID uuid.UUID) (*domain.ChangeRequest, error) {
+	cr, err := s.changeRequests.FindByID(ctx, requestID)
+	if err != nil {
+		return nil, err
+	}
+	if cr.WorkflowID != workflowID {
+		return nil, domain.ErrChangeRequestNotFound
+	}
+	return cr, nil
+}
+
+func (s *Service) recordDecision(ctx context.Context, actor audit.Actor, cr *domain.ChangeRequest, action string) {
+	s.audit.Record(ctx, actor, &auditlog.AuditLog{
+		Action:       action,
+		ResourceType: auditlog.ResourceWorkflowChangeRequest,
+		ResourceID:   cr.ID.String(),
+		OldValue:     map[string]interface{}{"status": domain.ChangeRequestPending},
+		NewValue: map[string]interface{}{
+			"workflow_id": cr.WorkflowID.String(),
+			"version":     cr.Version,
+			"status":      cr.Status,
+			"note":        cr.DecisionNote,
+			"diff":        audit.ToMap(cr.Diff),
+		},
+	})
+}
+
+// Activate enables triggers for the published version of a workflow. The
+// published graph is compiled up front, so a workflow that cannot run is
+// not activated and the first trigger finds its graph cached.
+func (s *Service) Activate(ctx context.Context, id uuid.UUID, actor audit.Actor) (*domain.Workflow, error) {
+	w, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := w.Activate(); err != nil {
+		return nil, err
+	}
+
+	v, err := s.versions.FindByVersion(ctx, id, *w.PublishedVersion)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.Graph(v.Apply(w)); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Update(ctx, w); err != nil {
+		return nil, err
+	}
+	s.events.Publish(events.NewWorkflowEvent(events.WorkflowActivated, w, actor))
+	return w, nil
+}
+
+// Deactivate disables triggers of a workflow
+func (s *Service) Deactivate(ctx context.Context, id uuid.UUID, actor audit.Actor) (*domain.Workflow, error) {
+	w, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	w.Deactivate()
+	if err := s.repo.Update(ctx, w); err != nil {
+		return nil, err
+	}
+	s.graphs.Invalidate(id)
+	s.events.Publish(events.NewWorkflowEvent(events.WorkflowDeactivated, w, actor))
+	return w, nil
+}
+
+// Versions returns the published versions of a workflow, newest first
+func (s *Service) Versions(ctx context.Context, id uuid.UUID) ([]*domain.WorkflowVersion, error) {
+	return s.versions.ListByWorkflow(ctx, id)
+}
+
+// RestoreVersion copies a published version into the draft. The restored
+// content still has to be published to reach production.
+func (s *Service) RestoreVersion(ctx context.Context, id uuid.UUID, version int) (*domain.Workflow, error) {
+	w, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	v, err := s.versions.FindByVersion(ctx, id, version)
+	if err != nil {
+		return nil, err
+	}
+
+	w.RestoreVersion(v)
+	if err := s.repo.Update(ctx, w); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
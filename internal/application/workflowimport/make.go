@@ -0,0 +1,295 @@
+package workflowimport
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/google/uuid"
+	domain "github.com/jaydeep/go-n8n/internal/domain/workflow"
+	"github.com/jaydeep/go-n8n/internal/nodes/core/action"
+	"github.com/jaydeep/go-n8n/internal/nodes/core/flow"
+	"github.com/jaydeep/go-n8n/internal/nodes/core/trigger"
+)
+
+// MakeInput is the blueprint of a Make scenario to import. Name overrides
+// the name of the scenario.
+type MakeInput struct {
+	Blueprint json.RawMessage `json:"blueprint" binding:"required"`
+	Name      string          `json:"name"`
+}
+
+var (
+	// makeMappingPattern matches a mapping of a Make module parameter,
+	// such as {{1.body.id}}
+	makeMappingPattern = regexp.MustCompile(`\{\{([^{}]*)\}\}`)
+	// makeFieldPattern matches a mapping of a field of an earlier module:
+	// its ID, then dotted keys, quoted in backticks when not identifiers
+	makeFieldPattern = regexp.MustCompile("^\\s*(\\d+)((?:\\.(?:[A-Za-z_$][A-Za-z0-9_$]*|[0-9]+|`[^`]*`))*)\\s*$")
+	// makeKeyPattern matches one key of a field mapping
+	makeKeyPattern = regexp.MustCompile("\\.([A-Za-z_$][A-Za-z0-9_$]*|[0-9]+|`[^`]*`)")
+)
+
+// ImportMake creates a workflow from the blueprint of a Make scenario.
+// Webhook, HTTP, sleep and webhook response modules become equivalent
+// nodes; the modules of other apps become No Operation placeholders whose
+// notes keep their settings. Routers become branches, and mappings of the
+// fields of earlier modules become expressions.
+func (s *Service) ImportMake(ctx context.Context, ownerID uuid.UUID, in MakeInput) (*Result, error) {
+	if len(in.Blueprint) > MaxDocumentSize {
+		return nil, fmt.Errorf("%w: larger than %d bytes", domain.ErrInvalidImport, MaxDocumentSize)
+	}
+	var blueprint makeBlueprint
+	if err := json.Unmarshal(unwrap(in.Blueprint, "blueprint"), &blueprint); err != nil {
+		return nil, fmt.Errorf("%w: %v", domain.ErrInvalidImport, err)
+	}
+	if len(blueprint.Flow) == 0 {
+		return nil, fmt.Errorf("%w: not a Make scenario blueprint", domain.ErrInvalidImport)
+	}
+	return s.create(ctx, ownerID, convertMake(&blueprint), in.Name, "make")
+}
+
+type makeBlueprint struct {
+	Name string       `json:"name"`
+	Flow []makeModule `json:"flow"`
+}
+
+// makeModule is a module of a scenario, such as http:ActionSendData. A
+// router has routes, each a flow of modules of its own.
+type makeModule struct {
+	ID         json.Number            `json:"id"`
+	Module     string                 `json:"module"`
+	Parameters map[string]interface{} `json:"parameters"`
+	Mapper     map[string]interface{} `json:"mapper"`
+	Filter     *struct {
+		Name string `json:"name"`
+	} `json:"filter"`
+	Metadata struct {
+		Designer struct {
+			Name string `json:"name"`
+		} `json:"designer"`
+	} `json:"metadata"`
+	Routes []struct {
+		Flow []makeModule `json:"flow"`
+	} `json:"routes"`
+}
+
+// app returns the app and the action of the module
+func (m *makeModule) app() (string, string) {
+	app, module, _ := strings.Cut(m.Module, ":")
+	return app, module
+}
+
+// name returns the name of the node of the module: its name in the
+// designer, or its app and action
+func (m *makeModule) name() string {
+	if m.Metadata.Designer.Name != "" {
+		return m.Metadata.Designer.Name
+	}
+	app, module := m.app()
+	if app == "" || module == "" {
+		return m.Module
+	}
+	return strings.ToUpper(app[:1]) + app[1:] + " " + module
+}
+
+// makeConverter converts the modules of a scenario into nodes
+type makeConverter struct {
+	*conversion
+	scenario string
+	// nodeNames are the node names of the converted modules, by module ID
+	nodeNames map[string]string
+}
+
+// convertMake converts a scenario blueprint
+func convertMake(blueprint *makeBlueprint) *conversion {
+	m := &makeConverter{conversion: newConversion(), scenario: blueprint.Name, nodeNames: make(map[string]string)}
+	if blueprint.Name != "" {
+		name := blueprint.Name
+		m.draft.Name = &name
+	}
+	m.flow(blueprint.Flow, "")
+	return m.conversion
+}
+
+// flow converts a flow of modules, each following the one before, the
+// first following the node with ID after
+func (m *makeConverter) flow(modules []makeModule, after string) {
+	for i := range modules {
+		after = m.module(&modules[i], after)
+	}
+}
+
+// module converts a module and returns the ID of its node
+func (m *makeConverter) module(module *makeModule, after string) string {
+	name := module.name()
+	if module.Filter != nil {
+		m.warn("%s: the filter %q before the module is not converted", name, module.Filter.Name)
+	}
+
+	var id string
+	app, kind := module.app()
+	switch {
+	case module.Module == "gateway:CustomWebHook":
+		id = m.addNodeAfter(domain.Node{
+			Type: trigger.WebhookTriggerType,
+			Name: name,
+			Parameters: map[string]interface{}{
+				"path":        slug(m.scenario),
+				"http_method": http.MethodPost,
+			},
+		}, after)
+	case module.Module == "gateway:WebhookRespond":
+		id = m.webhookRespond(module, name, after)
+	case app == "http" && strings.HasPrefix(kind, "Action"):
+		id = m.request(module, name, after)
+	case module.Module == "util:FunctionSleep":
+		id = m.addNodeAfter(domain.Node{
+			Type: flow.WaitType,
+			Name: name,
+			Parameters: map[string]interface{}{
+				"resume": flow.ResumeAfterInterval,
+				"amount": m.value(name, module.Mapper["duration"]),
+				"unit":   "seconds",
+			},
+		}, after)
+	case module.Module == "builtin:BasicRouter":
+		id = m.addNodeAfter(domain.Node{Type: flow.NoOpType, Name: name}, after)
+		for _, route := range module.Routes {
+			m.flow(route.Flow, id)
+		}
+	default:
+		settings := make(map[string]interface{})
+		if len(module.Parameters) > 0 {
+			settings["parameters"] = module.Parameters
+		}
+		if len(module.Mapper) > 0 {
+			settings["mapper"] = module.Mapper
+		}
+		id = m.addPlaceholder(name, after, fmt.Sprintf("Make module %s is not converted", module.Module), settings)
+	}
+	m.nodeNames[module.ID.String()] = m.nodeName(id)
+	return id
+}
+
+// nodeName returns the name of the node with an ID
+func (m *makeConverter) nodeName(id string) string {
+	for _, n := range m.draft.Nodes {
+		if n.ID == id {
+			return n.Name
+		}
+	}
+	return ""
+}
+
+// request converts a module of the HTTP app into an HTTP Request node
+func (m *makeConverter) request(module *makeModule, name, after string) string {
+	_, kind := module.app()
+	mapper := module.Mapper
+	method := strings.ToUpper(fmt.Sprint(firstOf(mapper, "method")))
+	if method == "" {
+		method = http.MethodGet
+	}
+	params := map[string]interface{}{
+		"method": method,
+		"url":    m.text(name, fmt.Sprint(firstOf(mapper, "url"))),
+	}
+
+	if query := mapParam(mapper["qs"]); len(query) > 0 {
+		pairs := make(map[string]interface{}, len(query))
+		for k, v := range query {
+			pairs[m.text(name, k)] = m.text(name, fmt.Sprint(v))
+		}
+		sep := "?"
+		if strings.Contains(params["url"].(string), "?") {
+			sep = "&"
+		}
+		params["url"] = params["url"].(string) + sep + formBody(pairs)
+	}
+
+	headers := make(map[string]interface{})
+	for k, v := range mapParam(mapper["headers"]) {
+		headers[k] = m.text(name, fmt.Sprint(v))
+	}
+	if len(headers) > 0 {
+		params["headers"] = headers
+	}
+
+	switch body := fmt.Sprint(firstOf(mapper, "bodyType")); body {
+	case "raw":
+		params["body"] = m.text(name, fmt.Sprint(firstOf(mapper, "data")))
+		if contentType := fmt.Sprint(firstOf(mapper, "contentType")); contentType != "" {
+			params["content_type"] = contentType
+		}
+	case "x_www_form_urlencoded":
+		fields := make(map[string]interface{})
+		for k, v := range mapParam(mapper["fields"]) {
+			fields[k] = m.text(name, fmt.Sprint(v))
+		}
+		params["body"] = formBody(fields)
+		params["content_type"] = "application/x-www-form-urlencoded"
+	case "", "empty":
+	default:
+		m.warn("%s: body of type %s is not converted", name, body)
+	}
+
+	if kind == "ActionGetFile" {
+		params["response_format"] = action.ResponseFormatBinary
+	}
+	if kind != "ActionSendData" && kind != "ActionGetFile" {
+		m.warn("%s: the authentication of %s is not converted; use a credential", name, module.Module)
+	}
+	return m.addNodeAfter(domain.Node{Type: action.HTTPRequestType, Name: name, Parameters: params}, after)
+}
+
+// webhookRespond converts a webhook response into a Respond to Webhook
+// node
+func (m *makeConverter) webhookRespond(module *makeModule, name, after string) string {
+	params := map[string]interface{}{"status_code": http.StatusOK}
+	if status := m.value(name, module.Mapper["status"]); status != nil && status != "" {
+		params["status_code"] = status
+	}
+	if body := firstOf(module.Mapper, "body"); body != "" {
+		m.warn("%s: the response body is not converted; the items are sent as the response", name)
+	}
+	return m.addNodeAfter(domain.Node{Type: flow.RespondToWebhookType, Name: name, Parameters: params}, after)
+}
+
+// value converts a mapped parameter value: numbers stay numbers, strings
+// have their mappings converted
+func (m *makeConverter) value(name string, v interface{}) interface{} {
+	if s, ok := v.(string); ok {
+		return m.text(name, s)
+	}
+	return v
+}
+
+// text converts the mappings in a parameter value into expressions.
+// Mappings of the fields of earlier modules are converted, as is now;
+// the functions of Make are left as they are.
+func (m *makeConverter) text(name, s string) string {
+	return makeMappingPattern.ReplaceAllStringFunc(s, func(match string) string {
+		body := makeMappingPattern.FindStringSubmatch(match)[1]
+		if strings.TrimSpace(body) == "now" {
+			return "{{ $now }}"
+		}
+		field := makeFieldPattern.FindStringSubmatch(body)
+		if field == nil {
+			m.warn("%s: the mapping %s is not converted", name, match)
+			return match
+		}
+		node, ok := m.nodeNames[field[1]]
+		if !ok {
+			m.warn("%s: the mapping %s of an unknown module is not converted", name, match)
+			return match
+		}
+		var path []string
+		for _, key := range makeKeyPattern.FindAllStringSubmatch(field[2], -1) {
+			path = append(path, strings.Trim(key[1], "`"))
+		}
+		return nodeExpression(node, path)
+	})
+}
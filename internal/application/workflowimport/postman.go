@@ -0,0 +1,568 @@
+package workflowimport
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/google/uuid"
+	domain "github.com/jaydeep/go-n8n/internal/domain/workflow"
+	"github.com/jaydeep/go-n8n/internal/nodes/core/action"
+)
+
+// PostmanInput is a Postman collection to import, with an optional
+// environment whose values become variables of the workflow. Name
+// overrides the name of the collection.
+type PostmanInput struct {
+	Collection  json.RawMessage `json:"collection" binding:"required"`
+	Environment json.RawMessage `json:"environment"`
+	Name        string          `json:"name"`
+}
+
+var (
+	// postmanVariablePattern matches a Postman variable reference such as
+	// {{base_url}}
+	postmanVariablePattern = regexp.MustCompile(`\{\{\s*([^{}]+?)\s*\}\}`)
+	// identifierPattern matches variable names expressions can use after a dot
+	identifierPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+)
+
+// ImportPostman creates a workflow of HTTP Request nodes from a Postman
+// collection of format v2.0 or v2.1. Each request becomes a node, named
+// after its folders and itself and connected in collection order. The
+// collection variables and the environment values become variables of the
+// workflow, and the {{name}} references to them become {{ $vars.name }}
+// expressions.
+func (s *Service) ImportPostman(ctx context.Context, ownerID uuid.UUID, in PostmanInput) (*Result, error) {
+	if len(in.Collection)+len(in.Environment) > MaxDocumentSize {
+		return nil, fmt.Errorf("%w: larger than %d bytes", domain.ErrInvalidImport, MaxDocumentSize)
+	}
+	collection, err := parsePostmanCollection(in.Collection)
+	if err != nil {
+		return nil, err
+	}
+	var environment postmanEnvironment
+	if len(in.Environment) > 0 {
+		if err := json.Unmarshal(unwrap(in.Environment, "environment"), &environment); err != nil {
+			return nil, fmt.Errorf("%w: environment: %v", domain.ErrInvalidImport, err)
+		}
+	}
+	return s.create(ctx, ownerID, convertPostman(collection, &environment), in.Name, "postman")
+}
+
+type postmanCollection struct {
+	Info struct {
+		Name        string             `json:"name"`
+		Description postmanDescription `json:"description"`
+		Schema      string             `json:"schema"`
+	} `json:"info"`
+	Item     []postmanItem     `json:"item"`
+	Variable []postmanVariable `json:"variable"`
+	Auth     *postmanAuth      `json:"auth"`
+}
+
+type postmanEnvironment struct {
+	Name   string            `json:"name"`
+	Values []postmanVariable `json:"values"`
+}
+
+// postmanItem is a request, or a folder of items when Request is nil
+type postmanItem struct {
+	Name        string             `json:"name"`
+	Description postmanDescription `json:"description"`
+	Item        []postmanItem      `json:"item"`
+	Request     *postmanRequest    `json:"request"`
+	Auth        *postmanAuth       `json:"auth"`
+}
+
+type postmanRequest struct {
+	Method      string             `json:"method"`
+	Header      postmanHeaders     `json:"header"`
+	URL         postmanURL         `json:"url"`
+	Body        *postmanBody       `json:"body"`
+	Auth        *postmanAuth       `json:"auth"`
+	Description postmanDescription `json:"description"`
+}
+
+// UnmarshalJSON accepts requests given as a bare URL
+func (r *postmanRequest) UnmarshalJSON(data []byte) error {
+	var raw string
+	if json.Unmarshal(data, &raw) == nil {
+		*r = postmanRequest{Method: http.MethodGet, URL: postmanURL{Raw: raw}}
+		return nil
+	}
+	type plain postmanRequest
+	return json.Unmarshal(data, (*plain)(r))
+}
+
+type postmanURL struct {
+	Raw      string            `json:"raw"`
+	Protocol string            `json:"protocol"`
+	Host     json.RawMessage   `json:"host"`
+	Port     string            `json:"port"`
+	Path     json.RawMessage   `json:"path"`
+	Query    []postmanVariable `json:"query"`
+}
+
+// UnmarshalJSON accepts URLs given as a string
+func (u *postmanURL) UnmarshalJSON(data []byte) error {
+	var raw string
+	if json.Unmarshal(data, &raw) == nil {
+		*u = postmanURL{Raw: raw}
+		return nil
+	}
+	type plain postmanURL
+	return json.Unmarshal(data, (*plain)(u))
+}
+
+// String returns the URL as written in Postman, built from its parts when
+// the collection has no raw URL
+func (u postmanURL) String() string {
+	if u.Raw != "" {
+		return u.Raw
+	}
+	s := joinParts(u.Host, ".")
+	if s == "" {
+		return ""
+	}
+	if u.Protocol != "" {
+		s = u.Protocol + "://" + s
+	}
+	if u.Port != "" {
+		s += ":" + u.Port
+	}
+	if path := strings.TrimPrefix(joinParts(u.Path, "/"), "/"); path != "" {
+		s += "/" + path
+	}
+	var query []string
+	for _, q := range u.Query {
+		if q.active() {
+			query = append(query, q.Key+"="+q.text())
+		}
+	}
+	if len(query) > 0 {
+		s += "?" + strings.Join(query, "&")
+	}
+	return s
+}
+
+// joinParts joins the host or path of a URL, given as a string or as a
+// list of segments
+func joinParts(raw json.RawMessage, sep string) string {
+	var s string
+	if json.Unmarshal(raw, &s) == nil {
+		return s
+	}
+	var segments []interface{}
+	if json.Unmarshal(raw, &segments) != nil {
+		return ""
+	}
+	parts := make([]string, 0, len(segments))
+	for _, segment := range segments {
+		switch segment := segment.(type) {
+		case string:
+			parts = append(parts, segment)
+		case map[string]interface{}:
+			parts = append(parts, fmt.Sprint(segment["value"]))
+		}
+	}
+	return strings.Join(parts, sep)
+}
+
+// postmanHeaders are the headers of a request, given as a list or as
+// "Name: value" lines
+type postmanHeaders []postmanVariable
+
+// UnmarshalJSON accepts headers given as lines
+func (h *postmanHeaders) UnmarshalJSON(data []byte) error {
+	var raw string
+	if json.Unmarshal(data, &raw) == nil {
+		*h = nil
+		for _, line := range strings.Split(raw, "\n") {
+			if name, value, ok := strings.Cut(line, ":"); ok {
+				*h = append(*h, postmanVariable{Key: strings.TrimSpace(name), Value: strings.TrimSpace(value)})
+			}
+		}
+		return nil
+	}
+	var list []postmanVariable
+	if err := json.Unmarshal(data, &list); err != nil {
+		return err
+	}
+	*h = list
+	return nil
+}
+
+type postmanBody struct {
+	Mode       string            `json:"mode"`
+	Raw        string            `json:"raw"`
+	URLEncoded []postmanVariable `json:"urlencoded"`
+	FormData   []postmanVariable `json:"formdata"`
+	GraphQL    *struct {
+		Query     string `json:"query"`
+		Variables string `json:"variables"`
+	} `json:"graphql"`
+	Options struct {
+		Raw struct {
+			Language string `json:"language"`
+		} `json:"raw"`
+	} `json:"options"`
+	Disabled bool `json:"disabled"`
+}
+
+type postmanAuth struct {
+	Type   string            `json:"type"`
+	Bearer []postmanVariable `json:"bearer"`
+	APIKey []postmanVariable `json:"apikey"`
+}
+
+// postmanVariable is a key and value pair: a variable, a header, a query
+// or form parameter or an attribute of an authentication
+type postmanVariable struct {
+	Key      string      `json:"key"`
+	Value    interface{} `json:"value"`
+	Type     string      `json:"type"`
+	Disabled bool        `json:"disabled"`
+	// Enabled is set on the values of environments instead of Disabled
+	Enabled *bool `json:"enabled"`
+}
+
+// active reports whether the pair is switched on
+func (v postmanVariable) active() bool {
+	return v.Key != "" && !v.Disabled && (v.Enabled == nil || *v.Enabled)
+}
+
+// text returns the value as a string
+func (v postmanVariable) text() string {
+	if v.Value == nil {
+		return ""
+	}
+	return fmt.Sprint(v.Value)
+}
+
+// attribute returns the value of the pair with the key
+func attribute(pairs []postmanVariable, key string) string {
+	for _, pair := range pairs {
+		if pair.Key == key {
+			return pair.text()
+		}
+	}
+	return ""
+}
+
+// postmanDescription is a description given as a string or as an object
+// with its content
+type postmanDescription string
+
+// UnmarshalJSON accepts descriptions given as objects
+func (d *postmanDescription) UnmarshalJSON(data []byte) error {
+	var raw string
+	if json.Unmarshal(data, &raw) == nil {
+		*d = postmanDescription(raw)
+		return nil
+	}
+	var object struct {
+		Content string `json:"content"`
+	}
+	if err := json.Unmarshal(data, &object); err != nil {
+		return err
+	}
+	*d = postmanDescription(object.Content)
+	return nil
+}
+
+// unwrap returns the document under key when data wraps it, as the
+// Postman API does
+func unwrap(data []byte, key string) []byte {
+	var wrapper map[string]json.RawMessage
+	if json.Unmarshal(data, &wrapper) == nil && len(wrapper) == 1 && len(wrapper[key]) > 0 {
+		return wrapper[key]
+	}
+	return data
+}
+
+// parsePostmanCollection parses a collection of format v2.0 or v2.1
+func parsePostmanCollection(data []byte) (*postmanCollection, error) {
+	var collection postmanCollection
+	if err := json.Unmarshal(unwrap(data, "collection"), &collection); err != nil {
+		return nil, fmt.Errorf("%w: %v", domain.ErrInvalidImport, err)
+	}
+	if collection.Item == nil || (collection.Info.Schema != "" && !strings.Contains(collection.Info.Schema, "/v2.")) {
+		return nil, fmt.Errorf("%w: not a Postman collection of format v2.0 or v2.1", domain.ErrInvalidImport)
+	}
+	return &collection, nil
+}
+
+// postmanConverter converts the requests of a collection into nodes
+type postmanConverter struct {
+	*conversion
+	// dynamic are the dynamic variables of Postman, such as $guid, that
+	// were warned about
+	dynamic map[string]bool
+}
+
+// convertPostman converts a collection and its environment
+func convertPostman(collection *postmanCollection, environment *postmanEnvironment) *conversion {
+	p := &postmanConverter{conversion: newConversion(), dynamic: make(map[string]bool)}
+	if name := collection.Info.Name; name != "" {
+		p.draft.Name = &name
+	}
+	if description := string(collection.Info.Description); description != "" {
+		p.draft.Description = &description
+	}
+
+	variables := make(map[string]interface{})
+	for _, v := range collection.Variable {
+		if v.active() {
+			variables[v.Key] = v.Value
+		}
+	}
+	for _, v := range environment.Values {
+		if !v.active() {
+			continue
+		}
+		variables[v.Key] = v.Value
+		if v.Type == "secret" {
+			p.warn("environment value %s is secret and was stored as a plain variable; consider a workflow secret instead", v.Key)
+		}
+	}
+	if len(variables) > 0 {
+		p.draft.Variables = variables
+	}
+
+	p.items(collection.Item, nil, collection.Auth)
+	return p.conversion
+}
+
+// items converts the requests among items, in order. Folders and requests
+// without an authentication of their own inherit the one of their folder.
+func (p *postmanConverter) items(items []postmanItem, folders []string, auth *postmanAuth) {
+	for _, item := range items {
+		itemAuth := auth
+		if item.Auth != nil {
+			itemAuth = item.Auth
+		}
+		path := append(append([]string{}, folders...), item.Name)
+		if item.Request == nil {
+			p.items(item.Item, path, itemAuth)
+			continue
+		}
+		p.request(item, strings.Join(path, " / "), itemAuth)
+	}
+}
+
+// request converts a request into an HTTP Request node
+func (p *postmanConverter) request(item postmanItem, name string, auth *postmanAuth) {
+	r := item.Request
+	method := strings.ToUpper(r.Method)
+	if method == "" {
+		method = http.MethodGet
+	}
+	params := map[string]interface{}{
+		"method": method,
+		"url":    p.text(r.URL.String()),
+	}
+
+	headers := make(map[string]interface{})
+	for _, h := range r.Header {
+		if !h.active() {
+			continue
+		}
+		if strings.EqualFold(h.Key, "Content-Type") {
+			params["content_type"] = p.text(h.text())
+			continue
+		}
+		headers[h.Key] = p.text(h.text())
+	}
+	if r.Auth != nil {
+		auth = r.Auth
+	}
+	p.auth(name, auth, params, headers)
+	p.body(name, r.Body, params)
+	if len(headers) > 0 {
+		params["headers"] = headers
+	}
+
+	notes := string(r.Description)
+	if notes == "" {
+		notes = string(item.Description)
+	}
+	p.addNode(domain.Node{
+		Type:       action.HTTPRequestType,
+		Name:       name,
+		Parameters: params,
+		Notes:      notes,
+	})
+}
+
+// auth converts bearer and API key authentication into headers or query
+// parameters. Other kinds need a credential set on the node.
+func (p *postmanConverter) auth(name string, auth *postmanAuth, params, headers map[string]interface{}) {
+	if auth == nil {
+		return
+	}
+	switch auth.Type {
+	case "", "noauth":
+	case "bearer":
+		headers["Authorization"] = "Bearer " + p.text(attribute(auth.Bearer, "token"))
+	case "apikey":
+		key, value := attribute(auth.APIKey, "key"), attribute(auth.APIKey, "value")
+		if attribute(auth.APIKey, "in") != "query" {
+			headers[key] = p.text(value)
+			return
+		}
+		target := params["url"].(string)
+		sep := "?"
+		if strings.Contains(target, "?") {
+			sep = "&"
+		}
+		params["url"] = target + sep + p.formValue(key) + "=" + p.formValue(value)
+	default:
+		p.warn("%s: %s authentication is not converted; set a credential on the node", name, auth.Type)
+	}
+}
+
+// body converts the body of a request. JSON bodies are kept as JSON, so
+// the node sends them as they were; other raw bodies are sent as text.
+func (p *postmanConverter) body(name string, body *postmanBody, params map[string]interface{}) {
+	if body == nil || body.Disabled {
+		return
+	}
+	switch body.Mode {
+	case "":
+	case "raw":
+		if body.Raw == "" {
+			return
+		}
+		language := body.Options.Raw.Language
+		var parsed interface{}
+		if json.Unmarshal([]byte(body.Raw), &parsed) == nil && isJSONDocument(parsed, language) {
+			params["body"] = p.value(parsed)
+			return
+		}
+		params["body"] = p.text(body.Raw)
+		if _, ok := params["content_type"]; !ok {
+			params["content_type"] = rawContentType(language)
+		}
+	case "urlencoded":
+		pairs := make([]string, 0, len(body.URLEncoded))
+		for _, pair := range body.URLEncoded {
+			if pair.active() {
+				pairs = append(pairs, p.formValue(pair.Key)+"="+p.formValue(pair.text()))
+			}
+		}
+		params["body"] = strings.Join(pairs, "&")
+		params["content_type"] = "application/x-www-form-urlencoded"
+	case "graphql":
+		if body.GraphQL == nil {
+			return
+		}
+		request := map[string]interface{}{"query": p.text(body.GraphQL.Query)}
+		if body.GraphQL.Variables != "" {
+			var variables interface{}
+			if err := json.Unmarshal([]byte(body.GraphQL.Variables), &variables); err != nil {
+				p.warn("%s: GraphQL variables are not valid JSON and were not converted", name)
+			} else {
+				request["variables"] = p.value(variables)
+			}
+		}
+		params["body"] = request
+	case "file":
+		p.warn("%s: file body is not converted; send a binary property of the item with send_binary", name)
+	default:
+		p.warn("%s: %s body is not converted", name, body.Mode)
+	}
+}
+
+// isJSONDocument reports whether a raw body that parsed as JSON is meant
+// as JSON rather than as text that happens to parse
+func isJSONDocument(parsed interface{}, language string) bool {
+	if language == "json" {
+		return true
+	}
+	switch parsed.(type) {
+	case map[string]interface{}, []interface{}:
+		return language == ""
+	}
+	return false
+}
+
+// rawContentType returns the content type of a raw body of a language
+func rawContentType(language string) string {
+	switch language {
+	case "json":
+		return "application/json"
+	case "xml":
+		return "application/xml"
+	case "html":
+		return "text/html"
+	case "javascript":
+		return "application/javascript"
+	}
+	return "text/plain"
+}
+
+// text rewrites the Postman variable references in s into expressions.
+// Dynamic variables of Postman have no counterpart and are left as they
+// are.
+func (p *postmanConverter) text(s string) string {
+	return postmanVariablePattern.ReplaceAllStringFunc(s, func(match string) string {
+		name := postmanVariablePattern.FindStringSubmatch(match)[1]
+		if strings.HasPrefix(name, "$") {
+			if !p.dynamic[name] {
+				p.dynamic[name] = true
+				p.warn("Postman dynamic variable {{%s}} is not converted", name)
+			}
+			return match
+		}
+		return variableExpression(name)
+	})
+}
+
+// formValue escapes s for a query string or form body, rewriting its
+// variable references into expressions
+func (p *postmanConverter) formValue(s string) string {
+	var b strings.Builder
+	last := 0
+	for _, loc := range postmanVariablePattern.FindAllStringIndex(s, -1) {
+		b.WriteString(url.QueryEscape(s[last:loc[0]]))
+		b.WriteString(p.text(s[loc[0]:loc[1]]))
+		last = loc[1]
+	}
+	b.WriteString(url.QueryEscape(s[last:]))
+	return b.String()
+}
+
+// value rewrites the variable references in the strings of a JSON value
+func (p *postmanConverter) value(v interface{}) interface{} {
+	switch v := v.(type) {
+	case string:
+		return p.text(v)
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			out[p.text(key)] = p.value(item)
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(v))
+		for i, item := range v {
+			out[i] = p.value(item)
+		}
+		return out
+	}
+	return v
+}
+
+// variableExpression returns the expression reading a workflow variable
+func variableExpression(name string) string {
+	if identifierPattern.MatchString(name) {
+		return "{{ $vars." + name + " }}"
+	}
+	quoted, _ := json.Marshal(name)
+	return "{{ $vars[" + string(quoted) + "] }}"
+}
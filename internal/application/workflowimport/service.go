@@ -0,0 +1,210 @@
+// Package workflowimport converts documents of other tools, such as
+// Postman collections, Zapier zaps and Make scenarios, into workflow
+// drafts, so that what was built there can be carried on as workflows.
+package workflowimport
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/google/uuid"
+	workflowapp "github.com/jaydeep/go-n8n/internal/application/workflow"
+	domain "github.com/jaydeep/go-n8n/internal/domain/workflow"
+	"github.com/jaydeep/go-n8n/internal/nodes/core/flow"
+	"github.com/jaydeep/go-n8n/pkg/logger"
+)
+
+const (
+	// MaxDocumentSize bounds the documents imported
+	MaxDocumentSize = 10 << 20
+	// nodeSpacing is the horizontal distance between converted nodes on
+	// the canvas
+	nodeSpacing = 250
+	// branchSpacing is the vertical distance between the branches
+	// following a node
+	branchSpacing = 200
+	// maxPlaceholderSettings bounds the settings of an unconverted step
+	// kept in the notes of its placeholder, in bytes
+	maxPlaceholderSettings = 4000
+)
+
+var (
+	// indexPattern matches list indexes in field paths
+	indexPattern = regexp.MustCompile(`^[0-9]+$`)
+	// expressionPattern matches the expressions in converted values
+	expressionPattern = regexp.MustCompile(`\{\{.*?\}\}`)
+)
+
+// Result is the workflow created from an imported document, along with
+// what the import could not carry over
+type Result struct {
+	Workflow *domain.Workflow `json:"workflow"`
+	Warnings []string         `json:"warnings"`
+}
+
+// conversion is a document converted into a workflow draft
+type conversion struct {
+	draft    workflowapp.DraftInput
+	warnings []string
+	// names are the node names taken so far
+	names map[string]int
+}
+
+func newConversion() *conversion {
+	return &conversion{warnings: []string{}, names: make(map[string]int)}
+}
+
+// warn records something the conversion could not carry over
+func (c *conversion) warn(format string, args ...interface{}) {
+	c.warnings = append(c.warnings, fmt.Sprintf(format, args...))
+}
+
+// addNode appends a node after the nodes converted so far, connected to
+// the last of them
+func (c *conversion) addNode(n domain.Node) string {
+	after := ""
+	if len(c.draft.Nodes) > 0 {
+		after = c.draft.Nodes[len(c.draft.Nodes)-1].ID
+	}
+	return c.addNodeAfter(n, after)
+}
+
+// addNodeAfter appends a node connected to the node with ID after, or
+// unconnected when after is empty, and returns its ID. Names are made
+// unique by numbering repeats. Nodes are placed right of the node they
+// follow, below the nodes already following it.
+func (c *conversion) addNodeAfter(n domain.Node, after string) string {
+	if count := c.names[n.Name]; count > 0 {
+		c.names[n.Name] = count + 1
+		n.Name = fmt.Sprintf("%s %d", n.Name, count+1)
+	}
+	c.names[n.Name]++
+	n.ID = fmt.Sprintf("node_%d", len(c.draft.Nodes)+1)
+	n.Position = domain.NodePosition{X: float64(len(c.draft.Nodes) * nodeSpacing), Y: 0}
+	for _, previous := range c.draft.Nodes {
+		if previous.ID != after {
+			continue
+		}
+		branches := 0
+		for _, conn := range c.draft.Connections {
+			if conn.Source.NodeID == after {
+				branches++
+			}
+		}
+		n.Position = domain.NodePosition{X: previous.Position.X + nodeSpacing, Y: previous.Position.Y + float64(branches*branchSpacing)}
+		c.draft.Connections = append(c.draft.Connections, domain.Connection{
+			Source: domain.ConnectionPoint{NodeID: after, Type: domain.ConnectionTypeMain},
+			Target: domain.ConnectionPoint{NodeID: n.ID, Type: domain.ConnectionTypeMain},
+		})
+	}
+	c.draft.Nodes = append(c.draft.Nodes, n)
+	return n.ID
+}
+
+// addPlaceholder adds a No Operation node standing for a step that could
+// not be converted, named "TODO: " and the step. Its notes describe the
+// step and its settings, so that it can be rebuilt by hand.
+func (c *conversion) addPlaceholder(name, after, description string, settings interface{}) string {
+	notes := "TODO: " + description
+	if settings != nil {
+		if data, err := json.MarshalIndent(settings, "", "  "); err == nil && string(data) != "null" && string(data) != "{}" {
+			if len(data) > maxPlaceholderSettings {
+				data = append(data[:maxPlaceholderSettings], "\n..."...)
+			}
+			notes += "\n\nOriginal settings:\n" + string(data)
+		}
+	}
+	c.warn("%s: %s; a placeholder node was added", name, description)
+	return c.addNodeAfter(domain.Node{Type: flow.NoOpType, Name: "TODO: " + name, Notes: notes}, after)
+}
+
+// Service creates workflows from documents of other tools. Imported
+// workflows are inactive drafts of the importing user.
+type Service struct {
+	workflows *workflowapp.Service
+	log       *logger.Logger
+}
+
+// NewService creates a new workflow import service
+func NewService(workflows *workflowapp.Service, log *logger.Logger) *Service {
+	return &Service{workflows: workflows, log: log}
+}
+
+// create stores a conversion as a new workflow of the owner, named name
+// unless the document had a name of its own
+func (s *Service) create(ctx context.Context, ownerID uuid.UUID, c *conversion, name, format string) (*Result, error) {
+	if name != "" {
+		c.draft.Name = &name
+	}
+	if c.draft.Name == nil || *c.draft.Name == "" {
+		return nil, domain.ErrWorkflowNameRequired
+	}
+	if len(c.draft.Nodes) == 0 {
+		return nil, fmt.Errorf("%w: no steps to convert", domain.ErrInvalidImport)
+	}
+
+	w, err := s.workflows.Create(ctx, uuid.New(), ownerID, c.draft)
+	if err != nil {
+		return nil, err
+	}
+	s.log.Infow("Workflow imported", "workflow_id", w.ID, "user_id", ownerID, "format", format,
+		"nodes", len(w.Nodes), "warnings", len(c.warnings))
+	return &Result{Workflow: w, Warnings: c.warnings}, nil
+}
+
+// nodeExpression returns the expression reading a field of the item of the
+// node named name paired with the current one, as {{ $("Name").item.json.a.b }}
+func nodeExpression(name string, path []string) string {
+	quoted, _ := json.Marshal(name)
+	var b strings.Builder
+	b.WriteString("{{ $(" + string(quoted) + ").item.json")
+	for _, key := range path {
+		switch {
+		case identifierPattern.MatchString(key):
+			b.WriteString("." + key)
+		case indexPattern.MatchString(key):
+			b.WriteString("[" + key + "]")
+		default:
+			quoted, _ := json.Marshal(key)
+			b.WriteString("[" + string(quoted) + "]")
+		}
+	}
+	b.WriteString(" }}")
+	return b.String()
+}
+
+// slug turns a name into a lowercase path segment, such as a webhook path
+func slug(name string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(name) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			if dash && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			b.WriteRune(r)
+			dash = false
+		} else {
+			dash = true
+		}
+	}
+	return b.String()
+}
+
+// formEscape escapes s for a query string or form body, leaving the
+// expressions in it as they are
+func formEscape(s string) string {
+	var b strings.Builder
+	last := 0
+	for _, loc := range expressionPattern.FindAllStringIndex(s, -1) {
+		b.WriteString(url.QueryEscape(s[last:loc[0]]))
+		b.WriteString(s[loc[0]:loc[1]])
+		last = loc[1]
+	}
+	b.WriteString(url.QueryEscape(s[last:]))
+	return b.String()
+}
@@ -0,0 +1,362 @@
+package workflowimport
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+	domain "github.com/jaydeep/go-n8n/internal/domain/workflow"
+	"github.com/jaydeep/go-n8n/internal/nodes/core/action"
+	"github.com/jaydeep/go-n8n/internal/nodes/core/flow"
+	"github.com/jaydeep/go-n8n/internal/nodes/core/trigger"
+)
+
+// ZapierInput is a Zapier export to import. Zap picks the zap to import by
+// ID or title when the export holds several; the first is imported
+// otherwise. Name overrides the title of the zap.
+type ZapierInput struct {
+	Export json.RawMessage `json:"export" binding:"required"`
+	Zap    string          `json:"zap"`
+	Name   string          `json:"name"`
+}
+
+// zapierFieldPattern matches a reference to a field of an earlier step,
+// such as {{123456__body__id}}
+var zapierFieldPattern = regexp.MustCompile(`\{\{\s*(\d+)__([^{}\s]+?)\s*\}\}`)
+
+// ImportZapier creates a workflow from a zap of a Zapier export. Webhook,
+// HTTP and delay steps become equivalent nodes; the steps of other apps
+// become No Operation placeholders whose notes keep their settings. Paths
+// become branches, and references to the fields of earlier steps become
+// expressions.
+func (s *Service) ImportZapier(ctx context.Context, ownerID uuid.UUID, in ZapierInput) (*Result, error) {
+	if len(in.Export) > MaxDocumentSize {
+		return nil, fmt.Errorf("%w: larger than %d bytes", domain.ErrInvalidImport, MaxDocumentSize)
+	}
+	var export zapierExport
+	if err := json.Unmarshal(in.Export, &export); err != nil {
+		return nil, fmt.Errorf("%w: %v", domain.ErrInvalidImport, err)
+	}
+	if len(export.Zaps) == 0 {
+		// A single zap may be exported on its own
+		var zap zapierZap
+		if json.Unmarshal(in.Export, &zap) == nil && len(zap.Nodes) > 0 {
+			export.Zaps = []zapierZap{zap}
+		}
+	}
+	if len(export.Zaps) == 0 {
+		return nil, fmt.Errorf("%w: not a Zapier export", domain.ErrInvalidImport)
+	}
+
+	zap, err := export.zap(in.Zap)
+	if err != nil {
+		return nil, err
+	}
+	c := convertZapier(zap)
+	if in.Zap == "" && len(export.Zaps) > 1 {
+		c.warn("the export holds %d zaps; only the first was imported", len(export.Zaps))
+	}
+	return s.create(ctx, ownerID, c, in.Name, "zapier")
+}
+
+type zapierExport struct {
+	Zaps []zapierZap `json:"zaps"`
+}
+
+// zap returns the zap with the ID or title, or the first
+func (e zapierExport) zap(key string) (*zapierZap, error) {
+	if key == "" {
+		return &e.Zaps[0], nil
+	}
+	for i, zap := range e.Zaps {
+		if zap.ID.String() == key || zap.Title == key {
+			return &e.Zaps[i], nil
+		}
+	}
+	return nil, fmt.Errorf("%w: the export has no zap %s", domain.ErrInvalidImport, key)
+}
+
+type zapierZap struct {
+	ID    json.Number            `json:"id"`
+	Title string                 `json:"title"`
+	Nodes map[string]*zapierStep `json:"nodes"`
+}
+
+// zapierStep is a step of a zap. Steps form a tree by their parent: the
+// trigger has none, and the steps following a path step have it as parent.
+type zapierStep struct {
+	ID          json.Number            `json:"id"`
+	ParentID    json.Number            `json:"parent_id"`
+	Title       string                 `json:"title"`
+	TypeOf      string                 `json:"type_of"`
+	SelectedAPI string                 `json:"selected_api"`
+	Action      string                 `json:"action"`
+	Params      map[string]interface{} `json:"params"`
+	Paused      bool                   `json:"paused"`
+}
+
+// app returns the name of the app of the step, such as "GoogleSheetsV2"
+// for GoogleSheetsV2API@1.2.0
+func (s *zapierStep) app() string {
+	app, _, _ := strings.Cut(s.SelectedAPI, "@")
+	for _, suffix := range []string{"CLIAPI", "API"} {
+		if trimmed := strings.TrimSuffix(app, suffix); trimmed != app && trimmed != "" {
+			return trimmed
+		}
+	}
+	return app
+}
+
+// name returns the name of the node of the step: its title, or its app
+// and action
+func (s *zapierStep) name() string {
+	if s.Title != "" {
+		return s.Title
+	}
+	if s.Action == "" {
+		return s.app()
+	}
+	return s.app() + " " + s.Action
+}
+
+// zapierConverter converts the steps of a zap into nodes
+type zapierConverter struct {
+	*conversion
+	zap *zapierZap
+	// children are the steps following each step, by parent ID, in ID
+	// order
+	children map[string][]*zapierStep
+	// nodeNames are the node names of the converted steps, by step ID
+	nodeNames map[string]string
+}
+
+// convertZapier converts a zap
+func convertZapier(zap *zapierZap) *conversion {
+	z := &zapierConverter{
+		conversion: newConversion(),
+		zap:        zap,
+		children:   make(map[string][]*zapierStep),
+		nodeNames:  make(map[string]string),
+	}
+	if zap.Title != "" {
+		title := zap.Title
+		z.draft.Name = &title
+	}
+	for _, step := range zap.Nodes {
+		z.children[step.ParentID.String()] = append(z.children[step.ParentID.String()], step)
+	}
+	for _, steps := range z.children {
+		sort.Slice(steps, func(i, j int) bool { return stepOrder(steps[i]) < stepOrder(steps[j]) })
+	}
+	z.steps("", "", false)
+	return z.conversion
+}
+
+// stepOrder orders the steps of a zap by their numeric ID
+func stepOrder(s *zapierStep) int64 {
+	id, _ := s.ID.Int64()
+	return id
+}
+
+// steps converts the steps following the step with ID parent, after the
+// node with ID after. The steps of a parent are a chain, each following
+// the one before, unless the parent splits into paths: each path then
+// follows the parent.
+func (z *zapierConverter) steps(parent, after string, paths bool) {
+	for _, step := range z.children[parent] {
+		id := z.step(step, after, paths)
+		z.nodeNames[step.ID.String()] = z.draft.Nodes[len(z.draft.Nodes)-1].Name
+		z.steps(step.ID.String(), id, isBranching(step))
+		if !paths {
+			after = id
+		}
+	}
+}
+
+// isBranching reports whether a step splits the zap into paths
+func isBranching(step *zapierStep) bool {
+	return step.app() == "Branching" && step.TypeOf != "filter"
+}
+
+// step converts a step and returns the ID of its node. A path is a step
+// following a step splitting into paths.
+func (z *zapierConverter) step(step *zapierStep, after string, path bool) string {
+	name := step.name()
+	if step.Paused {
+		z.warn("%s: the step is paused in Zapier", name)
+	}
+	app := step.app()
+	switch {
+	case strings.HasPrefix(app, "WebHook") && step.TypeOf == "read":
+		return z.addNodeAfter(domain.Node{
+			Type: trigger.WebhookTriggerType,
+			Name: name,
+			Parameters: map[string]interface{}{
+				"path":        slug(z.zap.Title),
+				"http_method": http.MethodPost,
+			},
+		}, after)
+	case strings.HasPrefix(app, "WebHook") && step.TypeOf == "write":
+		return z.webhookRequest(step, name, after)
+	case strings.HasPrefix(app, "Delay") && step.Action == "delay_for":
+		unit := strings.ToLower(z.param(step, "delay_for_unit"))
+		amount, _ := strconv.ParseFloat(z.param(step, "delay_for_value"), 64)
+		switch unit {
+		case "weeks":
+			unit, amount = "days", amount*7
+		case "":
+			unit = "minutes"
+		}
+		return z.addNodeAfter(domain.Node{
+			Type: flow.WaitType,
+			Name: name,
+			Parameters: map[string]interface{}{
+				"resume": flow.ResumeAfterInterval,
+				"amount": amount,
+				"unit":   unit,
+			},
+		}, after)
+	case strings.HasPrefix(app, "Delay") && step.Action == "delay_until":
+		return z.addNodeAfter(domain.Node{
+			Type: flow.WaitType,
+			Name: name,
+			Parameters: map[string]interface{}{
+				"resume":    flow.ResumeAtTime,
+				"date_time": z.text(name, z.param(step, "delay_until")),
+			},
+		}, after)
+	case path:
+		// Paths pass their items on; their conditions are left to rebuild
+		return z.addPlaceholder(name, after, "conditions of the Zapier path are not converted", step.Params)
+	case isBranching(step):
+		return z.addNodeAfter(domain.Node{Type: flow.NoOpType, Name: name}, after)
+	}
+	return z.addPlaceholder(name, after, fmt.Sprintf("Zapier step %s of app %s is not converted", step.Action, app), step.Params)
+}
+
+// webhookRequest converts a request of the Webhooks by Zapier app into an
+// HTTP Request node
+func (z *zapierConverter) webhookRequest(step *zapierStep, name, after string) string {
+	method := strings.ToUpper(step.Action)
+	if method == "CUSTOM_REQUEST" || method == "" {
+		method = strings.ToUpper(z.param(step, "method"))
+	}
+	switch method {
+	case http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete, http.MethodHead:
+	default:
+		z.warn("%s: request %s is sent as POST", name, step.Action)
+		method = http.MethodPost
+	}
+
+	params := map[string]interface{}{
+		"method": method,
+		"url":    z.text(name, z.param(step, "url")),
+	}
+	headers := make(map[string]interface{})
+	for k, v := range mapParam(step.Params["headers"]) {
+		headers[k] = z.text(name, fmt.Sprint(v))
+	}
+	if len(headers) > 0 {
+		params["headers"] = headers
+	}
+
+	// Requests carry data as fields, or as a raw body for custom requests
+	if raw := z.param(step, "data"); raw != "" && mapParam(step.Params["data"]) == nil {
+		params["body"] = z.text(name, raw)
+	} else if fields := mapParam(step.Params["data"]); len(fields) > 0 {
+		body := make(map[string]interface{}, len(fields))
+		for k, v := range fields {
+			body[k] = z.text(name, fmt.Sprint(v))
+		}
+		if z.param(step, "payload_type") == "form" {
+			params["content_type"] = "application/x-www-form-urlencoded"
+			params["body"] = formBody(body)
+		} else {
+			params["body"] = body
+		}
+	}
+	if z.param(step, "basic_auth") != "" {
+		z.warn("%s: basic authentication is not converted; use a credential", name)
+	}
+	return z.addNodeAfter(domain.Node{Type: action.HTTPRequestType, Name: name, Parameters: params}, after)
+}
+
+// param returns a parameter of a step as a string
+func (z *zapierConverter) param(step *zapierStep, key string) string {
+	switch v := step.Params[key].(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// text converts the references of a parameter value to the fields of
+// earlier steps into expressions
+func (z *zapierConverter) text(name, s string) string {
+	return zapierFieldPattern.ReplaceAllStringFunc(s, func(match string) string {
+		parts := zapierFieldPattern.FindStringSubmatch(match)
+		node, ok := z.nodeNames[parts[1]]
+		if !ok {
+			z.warn("%s: reference %s to an unknown step is not converted", name, match)
+			return match
+		}
+		return nodeExpression(node, strings.Split(parts[2], "__"))
+	})
+}
+
+// mapParam returns a parameter given as an object, or as a list of
+// key and value pairs
+func mapParam(v interface{}) map[string]interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		return v
+	case []interface{}:
+		out := make(map[string]interface{})
+		for _, pair := range v {
+			p, ok := pair.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			key := fmt.Sprint(firstOf(p, "key", "name"))
+			if key != "" && key != "<nil>" {
+				out[key] = firstOf(p, "value")
+			}
+		}
+		return out
+	}
+	return nil
+}
+
+// firstOf returns the first of the keys set in an object
+func firstOf(object map[string]interface{}, keys ...string) interface{} {
+	for _, key := range keys {
+		if v, ok := object[key]; ok && v != nil {
+			return v
+		}
+	}
+	return ""
+}
+
+// formBody encodes fields as a URL-encoded form, leaving the expressions
+// in their values as they are
+func formBody(fields map[string]interface{}) string {
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	pairs := make([]string, len(keys))
+	for i, k := range keys {
+		pairs[i] = formEscape(k) + "=" + formEscape(fmt.Sprint(fields[k]))
+	}
+	return strings.Join(pairs, "&")
+}
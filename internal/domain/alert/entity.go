@@ -0,0 +1,95 @@
+// Package alert models operational incidents, such as a worker going down
+// or a workflow failing repeatedly, and the channels operators are
+// notified on.
+package alert
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Kind identifies the check that raised an alert
+type Kind string
+
+const (
+	KindWorkerDown   Kind = "worker_down"
+	KindQueueBacklog Kind = "queue_backlog"
+	KindErrorRate    Kind = "error_rate"
+	// KindTest is used by test notifications, which are not stored
+	KindTest Kind = "test"
+)
+
+// Status represents the state of an alert
+type Status string
+
+const (
+	StatusFiring   Status = "firing"
+	StatusResolved Status = "resolved"
+)
+
+// Alert is an incident raised by a check. One alert fires per key until
+// its condition clears, so operators are notified once when it fires and
+// once when it resolves.
+type Alert struct {
+	ID uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	// Key identifies the condition, such as error_rate:<workflow id>
+	Key        string                 `json:"key" gorm:"not null"`
+	Kind       Kind                   `json:"kind" gorm:"not null"`
+	Summary    string                 `json:"summary" gorm:"not null"`
+	Details    map[string]interface{} `json:"details,omitempty" gorm:"serializer:json"`
+	Status     Status                 `json:"status" gorm:"not null"`
+	FiredAt    time.Time              `json:"fired_at"`
+	ResolvedAt *time.Time             `json:"resolved_at,omitempty"`
+	// NotifiedAt is when the channels were last notified of the alert
+	NotifiedAt *time.Time `json:"notified_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+// TableName specifies the table name for GORM
+func (Alert) TableName() string {
+	return "alerts"
+}
+
+// NewAlert creates a firing alert
+func NewAlert(key string, kind Kind, summary string, details map[string]interface{}) *Alert {
+	now := time.Now()
+	return &Alert{
+		Key:       key,
+		Kind:      kind,
+		Summary:   summary,
+		Details:   details,
+		Status:    StatusFiring,
+		FiredAt:   now,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
+
+// IsFiring returns whether the alert's condition still holds
+func (a *Alert) IsFiring() bool {
+	return a.Status == StatusFiring
+}
+
+// Resolve marks the alert as resolved
+func (a *Alert) Resolve() {
+	now := time.Now()
+	a.Status = StatusResolved
+	a.ResolvedAt = &now
+}
+
+// Notified records that the channels were notified of the alert
+func (a *Alert) Notified() {
+	now := time.Now()
+	a.NotifiedAt = &now
+}
+
+// Channel notifies operators of alerts, for example on a Slack channel
+type Channel interface {
+	// Name identifies the channel in logs and test results
+	Name() string
+	// Notify sends the alert; resolved alerts announce the recovery
+	Notify(ctx context.Context, a *Alert) error
+}
@@ -0,0 +1,22 @@
+package alert
+
+import (
+	"context"
+	"time"
+)
+
+// Repository defines persistence operations for alerts
+type Repository interface {
+	Create(ctx context.Context, a *Alert) error
+	Update(ctx context.Context, a *Alert) error
+
+	// ListFiring returns the firing alerts, oldest first
+	ListFiring(ctx context.Context) ([]*Alert, error)
+
+	// List returns up to limit alerts, newest first, optionally only those
+	// with status
+	List(ctx context.Context, status Status, limit int) ([]*Alert, error)
+
+	// DeleteResolvedBefore removes the alerts resolved before cutoff
+	DeleteResolvedBefore(ctx context.Context, cutoff time.Time) (int64, error)
+}
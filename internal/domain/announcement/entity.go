@@ -0,0 +1,63 @@
+package announcement
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// maxMessageLength bounds banner messages, which are shown on every page
+const maxMessageLength = 2000
+
+// Severity controls how prominently an announcement is shown
+type Severity string
+
+const (
+	SeverityInfo     Severity = "info"
+	SeverityWarning  Severity = "warning"
+	SeverityCritical Severity = "critical"
+)
+
+// Valid reports whether the severity is known
+func (s Severity) Valid() bool {
+	return s == SeverityInfo || s == SeverityWarning || s == SeverityCritical
+}
+
+// Announcement is a banner admins publish to every user, such as a
+// maintenance window or a policy change. It is shown until it expires or
+// is withdrawn; without an expiry it stays until withdrawn.
+type Announcement struct {
+	ID        uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	Title     string     `json:"title" gorm:"not null"`
+	Message   string     `json:"message" gorm:"not null"`
+	Severity  Severity   `json:"severity" gorm:"type:varchar(20);not null"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	CreatedBy *uuid.UUID `json:"created_by,omitempty" gorm:"type:uuid"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
+// TableName specifies the table name for GORM
+func (Announcement) TableName() string {
+	return "announcements"
+}
+
+// Validate checks the announcement's fields
+func (a *Announcement) Validate() error {
+	if strings.TrimSpace(a.Title) == "" {
+		return ErrTitleRequired
+	}
+	if strings.TrimSpace(a.Message) == "" || len(a.Message) > maxMessageLength {
+		return ErrInvalidMessage
+	}
+	if !a.Severity.Valid() {
+		return ErrInvalidSeverity
+	}
+	return nil
+}
+
+// Active reports whether the announcement is shown at now
+func (a *Announcement) Active(now time.Time) bool {
+	return a.ExpiresAt == nil || a.ExpiresAt.After(now)
+}
@@ -0,0 +1,11 @@
+package announcement
+
+import "errors"
+
+var (
+	ErrAnnouncementNotFound = errors.New("announcement not found")
+	ErrTitleRequired        = errors.New("announcement title is required")
+	ErrInvalidMessage       = errors.New("announcement message must be 1 to 2000 characters")
+	ErrInvalidSeverity      = errors.New("severity must be info, warning or critical")
+	ErrExpiryInPast         = errors.New("expires_at must be in the future")
+)
@@ -0,0 +1,23 @@
+package announcement
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Repository defines persistence operations for announcements
+type Repository interface {
+	Create(ctx context.Context, a *Announcement) error
+	Update(ctx context.Context, a *Announcement) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	FindByID(ctx context.Context, id uuid.UUID) (*Announcement, error)
+
+	// List returns every announcement, newest first
+	List(ctx context.Context) ([]*Announcement, error)
+
+	// ListActive returns the announcements shown at now, most severe and
+	// then newest first
+	ListActive(ctx context.Context, now time.Time) ([]*Announcement, error)
+}
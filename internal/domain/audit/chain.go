@@ -0,0 +1,199 @@
+package audit
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// BundleVersion is the format version of audit bundles and of the hashes
+// and anchor signatures they hold
+const BundleVersion = 1
+
+// chained holds the fields of an entry its hash covers, in a fixed order
+type chained struct {
+	Sequence     int64                  `json:"sequence"`
+	ID           uuid.UUID              `json:"id"`
+	UserID       *uuid.UUID             `json:"user_id"`
+	Action       string                 `json:"action"`
+	ResourceType string                 `json:"resource_type"`
+	ResourceID   string                 `json:"resource_id"`
+	OldValue     map[string]interface{} `json:"old_value"`
+	NewValue     map[string]interface{} `json:"new_value"`
+	IPAddress    string                 `json:"ip_address"`
+	UserAgent    string                 `json:"user_agent"`
+	CreatedAt    string                 `json:"created_at"`
+	PrevHash     string                 `json:"prev_hash"`
+}
+
+// ComputeHash returns the SHA-256 hash of the entry's fields and PrevHash.
+// Values are hashed as their JSON encoding, with map keys sorted, and the
+// creation time in UTC, so the hash survives a round trip through the
+// database and through an export.
+func (l *AuditLog) ComputeHash() string {
+	encoded, _ := json.Marshal(chained{
+		Sequence:     l.Sequence,
+		ID:           l.ID,
+		UserID:       l.UserID,
+		Action:       l.Action,
+		ResourceType: l.ResourceType,
+		ResourceID:   l.ResourceID,
+		OldValue:     l.OldValue,
+		NewValue:     l.NewValue,
+		IPAddress:    l.IPAddress,
+		UserAgent:    l.UserAgent,
+		CreatedAt:    l.CreatedAt.UTC().Format(time.RFC3339Nano),
+		PrevHash:     l.PrevHash,
+	})
+	sum := sha256.Sum256(encoded)
+	return hex.EncodeToString(sum[:])
+}
+
+// Chain appends the entry to the chain whose newest entry is prev, nil
+// when the chain is empty, and computes its hash. The entry's ID must be
+// set, as the hash covers it.
+func (l *AuditLog) Chain(prev *AuditLog) {
+	l.Sequence, l.PrevHash = 1, ""
+	if prev != nil {
+		l.Sequence = prev.Sequence + 1
+		l.PrevHash = prev.Hash
+	}
+	l.Hash = l.ComputeHash()
+}
+
+// Anchor is a signed statement that Hash was the head of the chain at
+// Sequence. Entries up to an anchor cannot be rewritten without breaking
+// its signature, even by someone able to recompute every hash, as long as
+// the signing key is kept apart from the database.
+type Anchor struct {
+	ID       uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	Sequence int64     `json:"sequence" gorm:"not null"`
+	Hash     string    `json:"hash" gorm:"not null"`
+	// PublicKey is the base64 Ed25519 key verifying Signature, the base64
+	// signature of the anchor's message
+	PublicKey string    `json:"public_key" gorm:"not null"`
+	Signature string    `json:"signature" gorm:"not null"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// TableName specifies the table name for GORM
+func (Anchor) TableName() string {
+	return "audit_anchors"
+}
+
+// Message returns the bytes the anchor's signature covers
+func (a *Anchor) Message() []byte {
+	return []byte("go-n8n audit anchor v" + strconv.Itoa(BundleVersion) + "\n" +
+		strconv.FormatInt(a.Sequence, 10) + "\n" +
+		a.Hash + "\n" +
+		a.CreatedAt.UTC().Format(time.RFC3339Nano))
+}
+
+// Sign sets the anchor's public key and signature
+func (a *Anchor) Sign(key ed25519.PrivateKey) {
+	a.PublicKey = base64.StdEncoding.EncodeToString(key.Public().(ed25519.PublicKey))
+	a.Signature = base64.StdEncoding.EncodeToString(ed25519.Sign(key, a.Message()))
+}
+
+// VerifySignature checks the anchor's signature with its public key
+func (a *Anchor) VerifySignature() error {
+	key, err := base64.StdEncoding.DecodeString(a.PublicKey)
+	if err != nil || len(key) != ed25519.PublicKeySize {
+		return fmt.Errorf("%w: anchor %d: malformed public key", ErrAnchorInvalid, a.Sequence)
+	}
+	signature, err := base64.StdEncoding.DecodeString(a.Signature)
+	if err != nil || !ed25519.Verify(key, a.Message(), signature) {
+		return fmt.Errorf("%w: anchor %d: bad signature", ErrAnchorInvalid, a.Sequence)
+	}
+	return nil
+}
+
+// Bundle is a run of consecutive entries of the chain with the anchors
+// signed within it, verifiable without access to the instance. PrevHash is
+// the hash of the entry before the first, empty when the bundle starts the
+// chain; bundles exported one after another link up through it.
+type Bundle struct {
+	Version    int         `json:"version"`
+	ExportedAt time.Time   `json:"exported_at"`
+	PrevHash   string      `json:"prev_hash"`
+	Entries    []*AuditLog `json:"entries"`
+	Anchors    []*Anchor   `json:"anchors"`
+	// NextSequence is the sequence to export the following bundle from,
+	// nil when the bundle ends at the head of the chain
+	NextSequence *int64 `json:"next_sequence,omitempty"`
+}
+
+// Verification summarizes a verified bundle. Entries up to LastAnchored
+// are covered by a signed anchor; later ones only by the chain.
+type Verification struct {
+	FirstSequence int64    `json:"first_sequence"`
+	LastSequence  int64    `json:"last_sequence"`
+	LastAnchored  int64    `json:"last_anchored"`
+	PublicKeys    []string `json:"public_keys"`
+}
+
+// Verify checks that the entries are consecutive, that each hash matches
+// its entry and links to the one before, and that every anchor is signed
+// and names the hash of its entry. Anchors prove no more than their keys
+// are trusted; with trusted keys given, anchors signed by other keys fail.
+func (b *Bundle) Verify(trusted ...ed25519.PublicKey) (*Verification, error) {
+	if b.Version != BundleVersion {
+		return nil, ErrUnsupportedBundle
+	}
+	v := &Verification{PublicKeys: []string{}}
+	hashes := make(map[int64]string, len(b.Entries))
+	prevHash := b.PrevHash
+	for i, entry := range b.Entries {
+		if i == 0 {
+			v.FirstSequence = entry.Sequence
+		} else if entry.Sequence != v.LastSequence+1 {
+			return nil, fmt.Errorf("%w: entry %d follows entry %d", ErrChainBroken, entry.Sequence, v.LastSequence)
+		}
+		if entry.PrevHash != prevHash {
+			return nil, fmt.Errorf("%w: entry %d does not link to the entry before it", ErrChainBroken, entry.Sequence)
+		}
+		if entry.Hash != entry.ComputeHash() {
+			return nil, fmt.Errorf("%w: entry %d does not match its hash", ErrChainBroken, entry.Sequence)
+		}
+		hashes[entry.Sequence] = entry.Hash
+		prevHash = entry.Hash
+		v.LastSequence = entry.Sequence
+	}
+
+	seen := make(map[string]bool)
+	for _, anchor := range b.Anchors {
+		if hash, ok := hashes[anchor.Sequence]; !ok || hash != anchor.Hash {
+			return nil, fmt.Errorf("%w: anchor %d does not match the entry it signs", ErrAnchorInvalid, anchor.Sequence)
+		}
+		if err := anchor.VerifySignature(); err != nil {
+			return nil, err
+		}
+		if len(trusted) > 0 && !trustedKey(anchor.PublicKey, trusted) {
+			return nil, fmt.Errorf("%w: anchor %d is signed by an untrusted key", ErrAnchorInvalid, anchor.Sequence)
+		}
+		if !seen[anchor.PublicKey] {
+			seen[anchor.PublicKey] = true
+			v.PublicKeys = append(v.PublicKeys, anchor.PublicKey)
+		}
+		if anchor.Sequence > v.LastAnchored {
+			v.LastAnchored = anchor.Sequence
+		}
+	}
+	return v, nil
+}
+
+func trustedKey(encoded string, trusted []ed25519.PublicKey) bool {
+	for _, key := range trusted {
+		if base64.StdEncoding.EncodeToString(key) == encoded {
+			return true
+		}
+	}
+	return false
+}
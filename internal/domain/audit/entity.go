@@ -0,0 +1,79 @@
+package audit
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AuditLog represents a recorded user or system action
+type AuditLog struct {
+	ID           uuid.UUID              `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	UserID       *uuid.UUID             `json:"user_id,omitempty" gorm:"type:uuid"`
+	Action       string                 `json:"action" gorm:"not null"`
+	ResourceType string                 `json:"resource_type" gorm:"not null"`
+	ResourceID   string                 `json:"resource_id"`
+	OldValue     map[string]interface{} `json:"old_value,omitempty" gorm:"serializer:json"`
+	NewValue     map[string]interface{} `json:"new_value,omitempty" gorm:"serializer:json"`
+	IPAddress    string                 `json:"ip_address"`
+	UserAgent    string                 `json:"user_agent"`
+	CreatedAt    time.Time              `json:"created_at"`
+
+	// Sequence numbers the entries of the hash chain from 1, and Hash
+	// covers the entry's fields and PrevHash, the Hash of the entry before
+	// it. Entries recorded before the chain was introduced have neither.
+	Sequence int64  `json:"sequence,omitempty"`
+	PrevHash string `json:"prev_hash,omitempty"`
+	Hash     string `json:"hash,omitempty"`
+}
+
+// Resource types
+const (
+	ResourceWorkflow              = "workflow"
+	ResourceWorkflowChangeRequest = "workflow_change_request"
+	ResourceSettings              = "settings"
+	ResourceFeatureFlag           = "feature_flag"
+	ResourceAnnouncement          = "announcement"
+	ResourceUser                  = "user"
+	ResourceCredential            = "credential"
+	ResourceInstanceWebhook       = "instance_webhook"
+	ResourceAuditLog              = "audit_log"
+)
+
+// Actions
+const (
+	ActionWorkflowPublished       = "workflow.published"
+	ActionWorkflowChangeRequested = "workflow.change_requested"
+	ActionWorkflowChangeCommented = "workflow.change_commented"
+	ActionWorkflowChangeApproved  = "workflow.change_approved"
+	ActionWorkflowChangeRejected  = "workflow.change_rejected"
+	ActionWorkflowActivated       = "workflow.activated"
+	ActionWorkflowDeactivated     = "workflow.deactivated"
+	ActionWorkflowDeleted         = "workflow.deleted"
+	ActionWorkflowSecretSet       = "workflow.secret_set"
+	ActionWorkflowSecretDeleted   = "workflow.secret_deleted"
+	ActionNodeDataPinned          = "workflow.node_data_pinned"
+	ActionNodeDataUnpinned        = "workflow.node_data_unpinned"
+	ActionWorkflowsExported       = "workflow.exported"
+	ActionWorkflowsImported       = "workflow.imported"
+	ActionLegalHoldPlaced         = "workflow.legal_hold_placed"
+	ActionLegalHoldReleased       = "workflow.legal_hold_released"
+	ActionCredentialCreated       = "credential.created"
+	ActionCredentialUpdated       = "credential.updated"
+	ActionCredentialDeleted       = "credential.deleted"
+	ActionCredentialsExported     = "credential.exported"
+	ActionCredentialsImported     = "credential.imported"
+	ActionInstanceWebhookCreated  = "instance_webhook.created"
+	ActionInstanceWebhookUpdated  = "instance_webhook.updated"
+	ActionInstanceWebhookDeleted  = "instance_webhook.deleted"
+	ActionInstanceWebhookRotated  = "instance_webhook.secret_rotated"
+	ActionSettingsUpdated         = "settings.updated"
+	ActionFeatureFlagUpdated      = "feature_flag.updated"
+	ActionFeatureFlagDeleted      = "feature_flag.deleted"
+	ActionAnnouncementPublished   = "announcement.published"
+	ActionAnnouncementUpdated     = "announcement.updated"
+	ActionAnnouncementWithdrawn   = "announcement.withdrawn"
+	ActionUserActivated           = "user.activated"
+	ActionUserDeactivated         = "user.deactivated"
+	ActionAuditExported           = "audit.exported"
+)
@@ -0,0 +1,11 @@
+package audit
+
+import "errors"
+
+var (
+	ErrAuditLogNotFound  = errors.New("audit log not found")
+	ErrAnchorNotFound    = errors.New("audit anchor not found")
+	ErrChainBroken       = errors.New("audit chain is broken")
+	ErrAnchorInvalid     = errors.New("audit anchor is invalid")
+	ErrUnsupportedBundle = errors.New("unsupported audit bundle version")
+)
@@ -0,0 +1,57 @@
+package audit
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jaydeep/go-n8n/pkg/pagination"
+)
+
+// ListFilter selects audit logs. Zero fields do not filter.
+type ListFilter struct {
+	UserID       *uuid.UUID
+	Action       string
+	ResourceType string
+	ResourceID   string
+	// From and To bound the creation time
+	From *time.Time
+	To   *time.Time
+	// After continues a keyset listing after the given cursor
+	After *pagination.Cursor
+	Limit int
+}
+
+// Repository defines persistence operations for audit logs
+type Repository interface {
+	// Create appends log to the hash chain, linking it to the newest entry.
+	// Appends are serialized, so the chain never forks.
+	Create(ctx context.Context, log *AuditLog) error
+	FindByID(ctx context.Context, id uuid.UUID) (*AuditLog, error)
+
+	// List returns the audit logs matching filter, newest first by creation
+	// time and ID
+	List(ctx context.Context, filter ListFilter) ([]*AuditLog, error)
+
+	// FindBySequence returns the entry of the chain with the sequence
+	FindBySequence(ctx context.Context, sequence int64) (*AuditLog, error)
+
+	// ListChain returns up to limit entries of the chain from the sequence
+	// on, in chain order
+	ListChain(ctx context.Context, fromSequence int64, limit int) ([]*AuditLog, error)
+
+	// Head returns the newest entry of the chain
+	Head(ctx context.Context) (*AuditLog, error)
+}
+
+// AnchorRepository defines persistence operations for chain anchors
+type AnchorRepository interface {
+	Create(ctx context.Context, anchor *Anchor) error
+
+	// Latest returns the anchor of the highest sequence
+	Latest(ctx context.Context) (*Anchor, error)
+
+	// ListRange returns the anchors of the sequences from from up to to,
+	// inclusive, in sequence order
+	ListRange(ctx context.Context, from, to int64) ([]*Anchor, error)
+}
@@ -0,0 +1,83 @@
+package billing
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jaydeep/go-n8n/internal/domain/execution"
+	"github.com/jaydeep/go-n8n/internal/domain/node"
+)
+
+// ExecutionUsage holds the resources one execution consumed, attributed
+// to the workflow, its team and its owner at the time it ran
+type ExecutionUsage struct {
+	ExecutionID uuid.UUID  `json:"execution_id" gorm:"type:uuid;primaryKey"`
+	WorkflowID  uuid.UUID  `json:"workflow_id" gorm:"type:uuid;not null"`
+	TeamID      *uuid.UUID `json:"team_id,omitempty" gorm:"type:uuid"`
+	UserID      uuid.UUID  `json:"user_id" gorm:"type:uuid;not null"`
+	StartedAt   time.Time  `json:"started_at" gorm:"not null"`
+	node.Usage  `gorm:"embedded"`
+	// NodeTypes breaks the usage down by node type
+	NodeTypes map[string]node.Usage `json:"node_types" gorm:"serializer:json"`
+	CreatedAt time.Time             `json:"created_at"`
+}
+
+// TableName specifies the table name for GORM
+func (ExecutionUsage) TableName() string {
+	return "execution_usage"
+}
+
+// NewExecutionUsage adds up the usage of the node runs of an execution
+func NewExecutionUsage(exec *execution.Execution, teamID *uuid.UUID, userID uuid.UUID, runs []node.NodeExecutionData) *ExecutionUsage {
+	usage := &ExecutionUsage{
+		ExecutionID: exec.ID,
+		WorkflowID:  exec.WorkflowID,
+		TeamID:      teamID,
+		UserID:      userID,
+		StartedAt:   exec.StartedAt,
+		NodeTypes:   make(map[string]node.Usage),
+	}
+	for _, run := range runs {
+		u := node.UsageFromMetadata(run.Metadata)
+		usage.Usage.Add(u)
+
+		byType := usage.NodeTypes[run.NodeType]
+		byType.Add(u)
+		usage.NodeTypes[run.NodeType] = byType
+	}
+	return usage
+}
+
+// GroupBy selects how usage is aggregated
+type GroupBy string
+
+const (
+	GroupByWorkflow GroupBy = "workflow"
+	GroupByTeam     GroupBy = "team"
+)
+
+// Valid reports whether the grouping is supported
+func (g GroupBy) Valid() bool {
+	return g == GroupByWorkflow || g == GroupByTeam
+}
+
+// Filter selects the executions usage is aggregated over. Nil fields do
+// not filter.
+type Filter struct {
+	TeamID     *uuid.UUID
+	WorkflowID *uuid.UUID
+	UserID     *uuid.UUID
+	From       time.Time
+	To         time.Time
+}
+
+// Summary is the usage of a workflow or team over a range. Executions
+// without a team are grouped under a nil TeamID.
+type Summary struct {
+	WorkflowID   *uuid.UUID `json:"workflow_id,omitempty"`
+	WorkflowName string     `json:"workflow_name,omitempty"`
+	TeamID       *uuid.UUID `json:"team_id,omitempty"`
+	TeamName     string     `json:"team_name,omitempty"`
+	Executions   int64      `json:"executions"`
+	node.Usage
+}
@@ -0,0 +1,9 @@
+package billing
+
+import "errors"
+
+var (
+	ErrInvalidGroupBy    = errors.New("group_by must be workflow or team")
+	ErrInvalidRange      = errors.New("from must be before to")
+	ErrUsageAccessDenied = errors.New("usage access denied")
+)
@@ -0,0 +1,14 @@
+package billing
+
+import "context"
+
+// Repository defines persistence operations for execution usage
+type Repository interface {
+	// Record stores the usage of an execution, replacing an earlier record
+	// of the same execution
+	Record(ctx context.Context, usage *ExecutionUsage) error
+
+	// Summarize aggregates the usage matching filter, largest CPU time
+	// first
+	Summarize(ctx context.Context, filter Filter, groupBy GroupBy) ([]*Summary, error)
+}
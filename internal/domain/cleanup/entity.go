@@ -0,0 +1,100 @@
+package cleanup
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Kind is a kind of orphaned resource
+type Kind string
+
+const (
+	// KindCredentials: credentials no workflow uses
+	KindCredentials Kind = "credentials"
+	// KindWorkflows: inactive workflows not run, edited or used by other
+	// workflows for a while
+	KindWorkflows Kind = "workflows"
+	// KindVariables: variables of workflows their expressions never read
+	KindVariables Kind = "variables"
+	// KindBinaryData: stored binary data no execution or pinned data
+	// refers to
+	KindBinaryData Kind = "binary_data"
+)
+
+// Kinds are every kind of orphaned resource
+var Kinds = []Kind{KindCredentials, KindWorkflows, KindVariables, KindBinaryData}
+
+// Valid reports whether k is a known kind
+func (k Kind) Valid() bool {
+	for _, known := range Kinds {
+		if k == known {
+			return true
+		}
+	}
+	return false
+}
+
+// Report lists the orphaned resources of the instance. Credentials and
+// workflows are only listed once older than InactiveDays.
+type Report struct {
+	GeneratedAt  time.Time    `json:"generated_at"`
+	InactiveDays int          `json:"inactive_days"`
+	Credentials  []Credential `json:"credentials"`
+	Workflows    []Workflow   `json:"workflows"`
+	Variables    []Variable   `json:"variables"`
+	BinaryData   []BinaryData `json:"binary_data"`
+	// BinaryDataListed is false when the binary data store cannot list
+	// what it holds, so dangling data is unknown
+	BinaryDataListed bool `json:"binary_data_listed"`
+}
+
+// Credential is a credential no workflow uses
+type Credential struct {
+	ID        uuid.UUID `json:"id"`
+	Name      string    `json:"name"`
+	Type      string    `json:"type"`
+	OwnerID   uuid.UUID `json:"owner_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Workflow is an inactive workflow without recent executions or edits
+// that no other workflow runs
+type Workflow struct {
+	ID      uuid.UUID `json:"id"`
+	Name    string    `json:"name"`
+	OwnerID uuid.UUID `json:"owner_id"`
+	// LastExecutedAt is when its latest execution started, nil when it
+	// never ran or its executions were deleted
+	LastExecutedAt *time.Time `json:"last_executed_at,omitempty"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+// Variable is a variable of a workflow that neither its draft nor its
+// published version reads
+type Variable struct {
+	WorkflowID   uuid.UUID `json:"workflow_id"`
+	WorkflowName string    `json:"workflow_name"`
+	Name         string    `json:"name"`
+}
+
+// BinaryData is stored binary data nothing refers to
+type BinaryData struct {
+	ID         string    `json:"id"`
+	Size       int64     `json:"size"`
+	ModifiedAt time.Time `json:"modified_at"`
+}
+
+// Result is the outcome of a cleanup: the resources deleted of each kind
+// cleaned, and those that could not be
+type Result struct {
+	Deleted map[Kind]int `json:"deleted"`
+	Failed  []Failure    `json:"failed"`
+}
+
+// Failure is a resource a cleanup failed to delete
+type Failure struct {
+	Kind  Kind   `json:"kind"`
+	ID    string `json:"id"`
+	Error string `json:"error"`
+}
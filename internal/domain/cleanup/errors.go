@@ -0,0 +1,9 @@
+package cleanup
+
+import "errors"
+
+var (
+	ErrKindsRequired       = errors.New("at least one kind is required")
+	ErrUnknownKind         = errors.New("kind must be credentials, workflows, variables or binary_data")
+	ErrInvalidInactiveDays = errors.New("inactive_days must be a positive number of days")
+)
@@ -0,0 +1,20 @@
+package cleanup
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Repository reads what the cleanup needs across the executions and the
+// data kept for workflows
+type Repository interface {
+	// LastExecutedAt returns when the latest execution of each of the
+	// workflows with executions started
+	LastExecutedAt(ctx context.Context, workflowIDs []uuid.UUID) (map[uuid.UUID]time.Time, error)
+
+	// ScanData calls fn with the JSON of the data of every execution, node
+	// run, waiting execution and pinned data, a batch of rows at a time
+	ScanData(ctx context.Context, fn func(data string)) error
+}
@@ -0,0 +1,36 @@
+package credential
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jaydeep/go-n8n/pkg/encryption"
+)
+
+// BundleVersion is the format version of credential bundles
+const BundleVersion = 1
+
+// Bundle carries credentials between instances. Their secrets are
+// encrypted with a key derived from a passphrase, not the key of either
+// instance, so the bundle can be imported by any instance given the
+// passphrase.
+type Bundle struct {
+	Version     int                  `json:"version"`
+	ExportedAt  time.Time            `json:"exported_at"`
+	KDF         encryption.KDFParams `json:"kdf"`
+	Credentials []BundleCredential   `json:"credentials"`
+}
+
+// BundleCredential is a credential in a bundle. Owners are identified by
+// email, as user IDs differ between instances; the credential ID is kept
+// so imported workflows still reference it.
+type BundleCredential struct {
+	ID         uuid.UUID `json:"id"`
+	Name       string    `json:"name"`
+	Type       string    `json:"type"`
+	OwnerEmail string    `json:"owner_email,omitempty"`
+	NodeTypes  []string  `json:"node_types"`
+	// Data is the secret data sealed with the bundle key and IV
+	Data []byte `json:"data"`
+	IV   []byte `json:"iv"`
+}
@@ -0,0 +1,42 @@
+package credential
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"regexp"
+)
+
+var slugPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{1,61}[a-z0-9]$`)
+
+// ValidateSlug checks that slug can address a credential
+func ValidateSlug(slug string) error {
+	if !slugPattern.MatchString(slug) {
+		return ErrInvalidCredentialSlug
+	}
+	return nil
+}
+
+// ContentHash returns a SHA-256 hash of the credential's name, slug, type,
+// node types and encrypted secrets. The secrets are only hashed as stored,
+// encrypted under a random IV, so the hash reveals nothing about them but
+// changes whenever they are written.
+func (c *Credential) ContentHash() string {
+	secrets := sha256.New()
+	secrets.Write(c.IV)
+	secrets.Write(c.Data)
+
+	nodeTypes := c.NodeTypes
+	if nodeTypes == nil {
+		nodeTypes = []string{}
+	}
+	encoded, _ := json.Marshal(struct {
+		Name      string   `json:"name"`
+		Slug      *string  `json:"slug"`
+		Type      string   `json:"type"`
+		NodeTypes []string `json:"node_types"`
+		Secrets   string   `json:"secrets"`
+	}{c.Name, c.Slug, c.Type, nodeTypes, hex.EncodeToString(secrets.Sum(nil))})
+	sum := sha256.Sum256(encoded)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,27 @@
+package credential
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Credential holds the encrypted secrets nodes use to authenticate against
+// external services. The secrets are never serialized. The optional slug
+// is a client chosen key, unique across credentials, by which tools
+// managing credentials as code address them. Health is the outcome of
+// the latest health check, nil until the first one.
+type Credential struct {
+	ID        uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	Name      string     `json:"name" gorm:"not null"`
+	Slug      *string    `json:"slug,omitempty"`
+	Type      string     `json:"type" gorm:"not null"`
+	UserID    uuid.UUID  `json:"user_id" gorm:"type:uuid;not null"`
+	TeamID    *uuid.UUID `json:"team_id,omitempty" gorm:"type:uuid"`
+	NodeTypes []string   `json:"node_types" gorm:"type:text[];serializer:array"`
+	Data      []byte     `json:"-" gorm:"not null"`
+	IV        []byte     `json:"-" gorm:"column:iv;not null"`
+	Health    *Health    `json:"health,omitempty" gorm:"serializer:json"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
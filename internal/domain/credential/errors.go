@@ -0,0 +1,21 @@
+package credential
+
+import "errors"
+
+var (
+	ErrCredentialNotFound     = errors.New("credential not found")
+	ErrCredentialNameRequired = errors.New("credential name is required")
+	ErrCredentialTypeRequired = errors.New("credential type is required")
+	ErrCredentialDataRequired = errors.New("credential data is required")
+	ErrCredentialNameTaken    = errors.New("a credential with this name already exists")
+	ErrInvalidCredentialSlug  = errors.New("slug must be 3 to 63 lowercase letters, digits or dashes")
+	ErrCredentialSlugTaken    = errors.New("credential slug is already in use")
+	ErrSecretsUnavailable     = errors.New("credential secrets are unavailable without an encryption key")
+
+	// Transfer errors
+	ErrPassphraseTooShort    = errors.New("passphrase must be at least 12 characters")
+	ErrUnsupportedBundle     = errors.New("unsupported credential bundle version")
+	ErrBundleDecrypt         = errors.New("cannot decrypt credential bundle: wrong passphrase or corrupted bundle")
+	ErrInvalidConflictPolicy = errors.New("on_conflict must be skip or overwrite")
+	ErrEncryptionKeyRequired = errors.New("credentials cannot be transferred without an instance encryption key")
+)
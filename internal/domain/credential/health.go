@@ -0,0 +1,42 @@
+package credential
+
+import "time"
+
+// HealthStatus is the outcome of the latest health check of a credential
+type HealthStatus string
+
+const (
+	// HealthOK: the test passed, or there is none, and nothing expires
+	// within the warning period
+	HealthOK HealthStatus = "ok"
+	// HealthExpiring: a token or certificate expires within the warning
+	// period
+	HealthExpiring HealthStatus = "expiring"
+	// HealthExpired: a token or certificate has expired
+	HealthExpired HealthStatus = "expired"
+	// HealthFailing: the test request failed or was rejected
+	HealthFailing HealthStatus = "failing"
+)
+
+// Health is the outcome of the latest health check of a credential
+type Health struct {
+	Status  HealthStatus `json:"status"`
+	Message string       `json:"message,omitempty"`
+	// ExpiresAt is when the first of its tokens and certificates expires,
+	// when known
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	// Tested reports whether a test request was sent; credentials without
+	// a test URL are only checked for expiry
+	Tested    bool      `json:"tested"`
+	CheckedAt time.Time `json:"checked_at"`
+	// Since is when the credential entered its status
+	Since time.Time `json:"since"`
+	// NotifiedAt is when the owner was last told of an unhealthy status
+	NotifiedAt *time.Time `json:"notified_at,omitempty"`
+}
+
+// Healthy reports whether workflows using the credential are expected to
+// keep working
+func (h *Health) Healthy() bool {
+	return h == nil || h.Status == HealthOK
+}
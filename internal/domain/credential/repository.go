@@ -0,0 +1,34 @@
+package credential
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// Repository defines persistence operations for credentials
+type Repository interface {
+	FindByID(ctx context.Context, id uuid.UUID) (*Credential, error)
+
+	// List returns the credentials owned by userID, or all credentials
+	// when userID is nil, without their secrets
+	List(ctx context.Context, userID *uuid.UUID) ([]*Credential, error)
+
+	// ListWithSecrets returns the credentials with the given IDs, or all
+	// credentials when ids is empty, with their secrets
+	ListWithSecrets(ctx context.Context, ids []uuid.UUID) ([]*Credential, error)
+
+	// FindByName returns the credential of a user with the given name
+	FindByName(ctx context.Context, userID uuid.UUID, name string) (*Credential, error)
+
+	// FindBySlug returns the credential with the slug
+	FindBySlug(ctx context.Context, slug string) (*Credential, error)
+
+	// UpdateHealth stores the outcome of a health check of a credential,
+	// leaving the rest of it and its update time as they are
+	UpdateHealth(ctx context.Context, id uuid.UUID, health *Health) error
+
+	Create(ctx context.Context, c *Credential) error
+	Update(ctx context.Context, c *Credential) error
+	Delete(ctx context.Context, id uuid.UUID) error
+}
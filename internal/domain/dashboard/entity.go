@@ -0,0 +1,175 @@
+package dashboard
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jaydeep/go-n8n/internal/domain/execution"
+)
+
+// MaxBuckets bounds the length of a series
+const MaxBuckets = 2000
+
+// FailedStatuses are the execution statuses counted as failures
+var FailedStatuses = []execution.ExecutionStatus{
+	execution.ExecutionStatusError,
+	execution.ExecutionStatusCrashed,
+	execution.ExecutionStatusTimeout,
+}
+
+// Rollup holds the execution counts of one workflow for one hour. Rollups
+// are recomputed from the executions table by a background job, so
+// dashboards never scan executions directly.
+type Rollup struct {
+	Bucket        time.Time  `json:"bucket" gorm:"primaryKey"`
+	WorkflowID    uuid.UUID  `json:"workflow_id" gorm:"type:uuid;primaryKey"`
+	TeamID        *uuid.UUID `json:"team_id,omitempty" gorm:"type:uuid"`
+	Executions    int64      `json:"executions"`
+	Succeeded     int64      `json:"succeeded"`
+	Failed        int64      `json:"failed"`
+	Cancelled     int64      `json:"cancelled"`
+	Finished      int64      `json:"finished"`
+	DurationSumMs int64      `json:"duration_sum_ms"`
+	RefreshedAt   time.Time  `json:"refreshed_at"`
+}
+
+// TableName specifies the table name for GORM
+func (Rollup) TableName() string {
+	return "execution_rollups"
+}
+
+// Interval is the width of the buckets of a series
+type Interval string
+
+const (
+	IntervalHour Interval = "hour"
+	IntervalDay  Interval = "day"
+	IntervalWeek Interval = "week"
+)
+
+// Valid reports whether the interval is supported
+func (i Interval) Valid() bool {
+	switch i {
+	case IntervalHour, IntervalDay, IntervalWeek:
+		return true
+	default:
+		return false
+	}
+}
+
+// Truncate returns the start of the bucket holding t, in UTC. Weeks start
+// on Monday.
+func (i Interval) Truncate(t time.Time) time.Time {
+	t = t.UTC()
+	switch i {
+	case IntervalDay:
+		return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	case IntervalWeek:
+		day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+		offset := (int(day.Weekday()) + 6) % 7
+		return day.AddDate(0, 0, -offset)
+	default:
+		return t.Truncate(time.Hour)
+	}
+}
+
+// Next returns the start of the bucket following the one starting at t
+func (i Interval) Next(t time.Time) time.Time {
+	switch i {
+	case IntervalDay:
+		return t.AddDate(0, 0, 1)
+	case IntervalWeek:
+		return t.AddDate(0, 0, 7)
+	default:
+		return t.Add(time.Hour)
+	}
+}
+
+// Totals are execution counts aggregated over a bucket or range
+type Totals struct {
+	Executions    int64 `json:"executions"`
+	Succeeded     int64 `json:"succeeded"`
+	Failed        int64 `json:"failed"`
+	Cancelled     int64 `json:"cancelled"`
+	Finished      int64 `json:"finished"`
+	DurationSumMs int64 `json:"-"`
+}
+
+// Add accumulates other into t
+func (t *Totals) Add(other Totals) {
+	t.Executions += other.Executions
+	t.Succeeded += other.Succeeded
+	t.Failed += other.Failed
+	t.Cancelled += other.Cancelled
+	t.Finished += other.Finished
+	t.DurationSumMs += other.DurationSumMs
+}
+
+// ErrorRate is the share of finished executions that failed
+func (t Totals) ErrorRate() float64 {
+	if t.Finished == 0 {
+		return 0
+	}
+	return float64(t.Failed) / float64(t.Finished)
+}
+
+// AvgDurationMs is the mean duration of finished executions
+func (t Totals) AvgDurationMs() int64 {
+	if t.Finished == 0 {
+		return 0
+	}
+	return t.DurationSumMs / t.Finished
+}
+
+// Summary adds the derived rates to totals
+type Summary struct {
+	Totals
+	ErrorRate     float64 `json:"error_rate"`
+	AvgDurationMs int64   `json:"avg_duration_ms"`
+}
+
+// Summarize derives the rates of totals
+func Summarize(totals Totals) Summary {
+	return Summary{
+		Totals:        totals,
+		ErrorRate:     totals.ErrorRate(),
+		AvgDurationMs: totals.AvgDurationMs(),
+	}
+}
+
+// Point is one bucket of a series
+type Point struct {
+	Bucket time.Time `json:"bucket"`
+	Summary
+}
+
+// FailingWorkflow ranks a workflow by its failures over a range
+type FailingWorkflow struct {
+	WorkflowID   uuid.UUID `json:"workflow_id"`
+	WorkflowName string    `json:"workflow_name"`
+	Summary
+}
+
+// Series returns one point per bucket from from to to, filling buckets
+// without executions with zeros. totals is keyed by bucket start.
+func Series(totals map[time.Time]Totals, from, to time.Time, interval Interval) []Point {
+	points := []Point{}
+	for bucket := interval.Truncate(from); bucket.Before(to); bucket = interval.Next(bucket) {
+		points = append(points, Point{Bucket: bucket, Summary: Summarize(totals[bucket])})
+	}
+	return points
+}
+
+// BucketCount bounds the number of buckets between from and to
+func BucketCount(from, to time.Time, interval Interval) int {
+	var width time.Duration
+	switch interval {
+	case IntervalDay:
+		width = 24 * time.Hour
+	case IntervalWeek:
+		width = 7 * 24 * time.Hour
+	default:
+		width = time.Hour
+	}
+	return int(to.Sub(interval.Truncate(from))/width) + 1
+}
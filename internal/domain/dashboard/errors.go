@@ -0,0 +1,10 @@
+package dashboard
+
+import "errors"
+
+var (
+	ErrTeamAccessDenied = errors.New("team access denied")
+	ErrInvalidInterval  = errors.New("interval must be hour, day or week")
+	ErrInvalidRange     = errors.New("from must be before to")
+	ErrRangeTooLarge    = errors.New("range holds too many buckets for the interval")
+)
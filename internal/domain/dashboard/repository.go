@@ -0,0 +1,27 @@
+package dashboard
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Repository defines persistence operations for execution rollups
+type Repository interface {
+	// Refresh recomputes the rollups of the hours from from up to to from
+	// the executions table
+	Refresh(ctx context.Context, from, to time.Time) error
+
+	// LatestBucket returns the newest hour with rollups, or nil when none
+	// exist
+	LatestBucket(ctx context.Context) (*time.Time, error)
+
+	// Totals returns the totals of a team between from and to keyed by the
+	// start of their interval bucket
+	Totals(ctx context.Context, teamID uuid.UUID, from, to time.Time, interval Interval) (map[time.Time]Totals, error)
+
+	// TopFailing returns up to limit workflows of a team with the most
+	// failures between from and to
+	TopFailing(ctx context.Context, teamID uuid.UUID, from, to time.Time, limit int) ([]*FailingWorkflow, error)
+}
@@ -0,0 +1,44 @@
+package endpoint
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jaydeep/go-n8n/internal/domain/node"
+)
+
+// Reasons a call was buffered
+const (
+	BufferReasonMaintenance = "maintenance"
+	BufferReasonOverloaded  = "overloaded"
+	BufferReasonUnavailable = "engine_unavailable"
+)
+
+// BufferedCall is an endpoint call accepted while executions could not be
+// started. It is kept until it is replayed, the endpoint goes away or it
+// expires.
+type BufferedCall struct {
+	ID         uuid.UUID         `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	EndpointID uuid.UUID         `json:"endpoint_id" gorm:"type:uuid;not null"`
+	Method     string            `json:"method" gorm:"not null"`
+	Body       interface{}       `json:"body,omitempty" gorm:"serializer:json"`
+	Query      map[string]string `json:"query,omitempty" gorm:"serializer:json"`
+	Headers    map[string]string `json:"headers,omitempty" gorm:"serializer:json"`
+	// Binary holds the files uploaded with the call and its raw body
+	Binary     map[string]node.Binary `json:"-" gorm:"serializer:json"`
+	Reason     string                 `json:"reason"`
+	Attempts   int                    `json:"attempts" gorm:"default:0"`
+	LastError  string                 `json:"last_error,omitempty"`
+	ReceivedAt time.Time              `json:"received_at"`
+}
+
+// TableName specifies the table name for GORM
+func (BufferedCall) TableName() string {
+	return "workflow_endpoint_buffer"
+}
+
+// Expired reports whether the call was received longer than maxAge ago. A
+// zero maxAge never expires.
+func (b *BufferedCall) Expired(maxAge time.Duration, now time.Time) bool {
+	return maxAge > 0 && now.Sub(b.ReceivedAt) > maxAge
+}
@@ -0,0 +1,103 @@
+package endpoint
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// VerificationRecordPrefix is prepended to a custom domain to name the
+	// DNS TXT record proving its ownership
+	VerificationRecordPrefix = "_go-n8n-verification."
+	// verificationTokenPrefix marks the value of the TXT record
+	verificationTokenPrefix = "go-n8n-verification="
+)
+
+// CustomDomain is a hostname a team serves its endpoints on instead of the
+// instance hostname. Requests for the hostname only reach the endpoints of
+// the team's workflows. The domain is used once its ownership is verified
+// with a DNS TXT record holding VerificationToken; certificates for it are
+// then issued over ACME when the server terminates TLS itself.
+type CustomDomain struct {
+	ID                uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	TeamID            uuid.UUID  `json:"team_id" gorm:"type:uuid;not null"`
+	Hostname          string     `json:"hostname" gorm:"uniqueIndex;not null"`
+	VerificationToken string     `json:"verification_token" gorm:"not null"`
+	VerifiedAt        *time.Time `json:"verified_at,omitempty"`
+	CreatedBy         uuid.UUID  `json:"created_by" gorm:"type:uuid;not null"`
+	CreatedAt         time.Time  `json:"created_at"`
+	UpdatedAt         time.Time  `json:"updated_at"`
+}
+
+// TableName specifies the table name for GORM
+func (CustomDomain) TableName() string {
+	return "custom_domains"
+}
+
+// NewCustomDomain returns an unverified custom domain for hostname with a
+// fresh verification token
+func NewCustomDomain(teamID, createdBy uuid.UUID, hostname string) (*CustomDomain, error) {
+	hostname, err := NormalizeHostname(hostname)
+	if err != nil {
+		return nil, err
+	}
+	raw := make([]byte, 16)
+	if _, err := rand.Read(raw); err != nil {
+		return nil, err
+	}
+	return &CustomDomain{
+		TeamID:            teamID,
+		Hostname:          hostname,
+		VerificationToken: verificationTokenPrefix + hex.EncodeToString(raw),
+		CreatedBy:         createdBy,
+	}, nil
+}
+
+// Verified reports whether the ownership of the domain was verified
+func (d *CustomDomain) Verified() bool {
+	return d.VerifiedAt != nil
+}
+
+// VerificationRecord returns the name of the DNS TXT record that must hold
+// the verification token
+func (d *CustomDomain) VerificationRecord() string {
+	return VerificationRecordPrefix + d.Hostname
+}
+
+// NormalizeHostname lowercases hostname and checks that it is a fully
+// qualified domain name. IP addresses, wildcards and ports are refused.
+func NormalizeHostname(hostname string) (string, error) {
+	hostname = strings.TrimSuffix(strings.ToLower(strings.TrimSpace(hostname)), ".")
+	if len(hostname) > 253 || net.ParseIP(hostname) != nil {
+		return "", ErrInvalidHostname
+	}
+	labels := strings.Split(hostname, ".")
+	if len(labels) < 2 {
+		return "", ErrInvalidHostname
+	}
+	for _, label := range labels {
+		if !validLabel(label) {
+			return "", ErrInvalidHostname
+		}
+	}
+	return hostname, nil
+}
+
+// validLabel reports whether label is a DNS label of letters, digits and
+// inner dashes
+func validLabel(label string) bool {
+	if label == "" || len(label) > 63 || label[0] == '-' || label[len(label)-1] == '-' {
+		return false
+	}
+	for _, r := range label {
+		if (r < 'a' || r > 'z') && (r < '0' || r > '9') && r != '-' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,194 @@
+package endpoint
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jaydeep/go-n8n/pkg/netpolicy"
+)
+
+const (
+	// apiKeyPrefix marks endpoint API keys so leaked keys are recognizable
+	apiKeyPrefix = "n8n_ep_"
+	// apiKeyHintLength is the number of key characters kept for display
+	apiKeyHintLength = len(apiKeyPrefix) + 4
+)
+
+var slugPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{1,61}[a-z0-9]$`)
+
+// Endpoint exposes a pinned version of a workflow as a stable HTTP API at
+// /api/v1/endpoints/:slug. Callers authenticate with the endpoint's own
+// API key; republishing the workflow does not change the endpoint until
+// it is pinned to the new version.
+type Endpoint struct {
+	ID              uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	Slug            string    `json:"slug" gorm:"uniqueIndex;not null"`
+	WorkflowID      uuid.UUID `json:"workflow_id" gorm:"type:uuid;not null"`
+	WorkflowVersion int       `json:"workflow_version" gorm:"not null"`
+	UserID          uuid.UUID `json:"user_id" gorm:"type:uuid;not null"`
+	Description     string    `json:"description,omitempty"`
+	Method          string    `json:"method" gorm:"not null"`
+	APIKeyHash      string    `json:"-" gorm:"not null"`
+	APIKeyHint      string    `json:"api_key_hint"`
+	// RateLimit is the number of calls allowed per minute; 0 is unlimited
+	RateLimit int `json:"rate_limit" gorm:"default:0"`
+	// AllowedIPs restricts callers to these IP addresses and CIDR ranges;
+	// empty allows every address
+	AllowedIPs []string `json:"allowed_ips,omitempty" gorm:"serializer:json"`
+	// WebhookAllowedIPs restricts callers further, as set on the webhook
+	// trigger of the pinned version
+	WebhookAllowedIPs []string               `json:"webhook_allowed_ips,omitempty" gorm:"serializer:json"`
+	RequestSchema     map[string]interface{} `json:"request_schema,omitempty" gorm:"serializer:json"`
+	ResponseSchema    map[string]interface{} `json:"response_schema,omitempty" gorm:"serializer:json"`
+	ResponseStatus    int                    `json:"response_status"`
+	ResponseField     string                 `json:"response_field,omitempty"`
+	// BodyFormat and RawBody say how call bodies are read, as set on the
+	// webhook trigger of the pinned version
+	BodyFormat string `json:"body_format" gorm:"default:json"`
+	RawBody    bool   `json:"raw_body" gorm:"default:false"`
+	// CORS is the policy for browsers calling the endpoint, as set on the
+	// webhook trigger; nil uses the instance default
+	CORS *CORSPolicy `json:"cors,omitempty" gorm:"serializer:json"`
+	// Region is the data residency region of the pinned version, whose
+	// storage keeps the files of calls
+	Region    string    `json:"region,omitempty"`
+	IsActive  bool      `json:"is_active" gorm:"default:true"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// CORSPolicy says which sites browsers may call an endpoint from
+type CORSPolicy struct {
+	// AllowedOrigins holds origins such as https://example.com, or *;
+	// empty leaves them to the instance default
+	AllowedOrigins []string `json:"allowed_origins"`
+	AllowedMethods []string `json:"allowed_methods"`
+	// MaxAge is how many seconds browsers may cache preflight responses;
+	// 0 leaves it to the instance default
+	MaxAge int `json:"max_age,omitempty"`
+}
+
+// TableName specifies the table name for GORM
+func (Endpoint) TableName() string {
+	return "workflow_endpoints"
+}
+
+// ValidateSlug checks that slug can be used in the endpoint URL
+func ValidateSlug(slug string) error {
+	if !slugPattern.MatchString(slug) {
+		return ErrInvalidSlug
+	}
+	return nil
+}
+
+// ValidateAllowedIPs checks that every entry is an IP address or CIDR range
+func ValidateAllowedIPs(entries []string) error {
+	if err := netpolicy.Validate(entries); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidAllowedIPs, err)
+	}
+	return nil
+}
+
+// AllowsIP reports whether the endpoint may be called from ip: both its
+// allowlist and that of its webhook trigger must allow it
+func (e *Endpoint) AllowsIP(ip string) bool {
+	for _, entries := range [][]string{e.AllowedIPs, e.WebhookAllowedIPs} {
+		allowlist, err := netpolicy.Parse(entries)
+		if err != nil {
+			// Stored entries are validated; refuse rather than open up
+			return false
+		}
+		if !allowlist.Allows(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+// RotateAPIKey replaces the API key of the endpoint and returns the new
+// key. Only its hash is stored, so the key cannot be shown again.
+func (e *Endpoint) RotateAPIKey() (string, error) {
+	raw := make([]byte, 24)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+	key := apiKeyPrefix + hex.EncodeToString(raw)
+	e.APIKeyHash = HashAPIKey(key)
+	e.APIKeyHint = key[:apiKeyHintLength]
+	return key, nil
+}
+
+// VerifyAPIKey reports whether key is the API key of the endpoint
+func (e *Endpoint) VerifyAPIKey(key string) bool {
+	if key == "" || e.APIKeyHash == "" {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(HashAPIKey(key)), []byte(e.APIKeyHash)) == 1
+}
+
+// HashAPIKey returns the stored form of an API key
+func HashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// Usage counts the calls of an endpoint on one day
+type Usage struct {
+	EndpointID      uuid.UUID `json:"-" gorm:"type:uuid;primary_key"`
+	Day             time.Time `json:"day" gorm:"type:date;primary_key"`
+	Calls           int64     `json:"calls"`
+	Errors          int64     `json:"errors"`
+	RateLimited     int64     `json:"rate_limited"`
+	TotalDurationMs int64     `json:"total_duration_ms"`
+}
+
+// TableName specifies the table name for GORM
+func (Usage) TableName() string {
+	return "workflow_endpoint_usage"
+}
+
+// Call describes one call of an endpoint for usage analytics
+type Call struct {
+	At          time.Time
+	StatusCode  int
+	Duration    time.Duration
+	RateLimited bool
+}
+
+// UsageSummary aggregates the daily usage of an endpoint over a period
+type UsageSummary struct {
+	From          time.Time `json:"from"`
+	To            time.Time `json:"to"`
+	Calls         int64     `json:"calls"`
+	Errors        int64     `json:"errors"`
+	RateLimited   int64     `json:"rate_limited"`
+	AvgDurationMs int64     `json:"avg_duration_ms"`
+	ErrorRate     float64   `json:"error_rate"`
+	Days          []*Usage  `json:"days"`
+}
+
+// Summarize totals daily usage rows
+func Summarize(from, to time.Time, days []*Usage) *UsageSummary {
+	summary := &UsageSummary{From: from, To: to, Days: days}
+	var duration int64
+	for _, d := range days {
+		summary.Calls += d.Calls
+		summary.Errors += d.Errors
+		summary.RateLimited += d.RateLimited
+		duration += d.TotalDurationMs
+	}
+	if summary.Calls > 0 {
+		summary.AvgDurationMs = duration / summary.Calls
+		summary.ErrorRate = float64(summary.Errors) / float64(summary.Calls)
+	}
+	if summary.Days == nil {
+		summary.Days = []*Usage{}
+	}
+	return summary
+}
@@ -0,0 +1,24 @@
+package endpoint
+
+import "errors"
+
+var (
+	ErrEndpointNotFound       = errors.New("endpoint not found")
+	ErrEndpointAccessDenied   = errors.New("endpoint access denied")
+	ErrInvalidSlug            = errors.New("slug must be 3 to 63 lowercase letters, digits or dashes")
+	ErrSlugTaken              = errors.New("slug is already in use")
+	ErrInvalidRateLimit       = errors.New("rate limit must not be negative")
+	ErrInvalidAPIKey          = errors.New("invalid API key")
+	ErrRateLimited            = errors.New("rate limit exceeded")
+	ErrMethodNotAllowed       = errors.New("method not allowed")
+	ErrWebhookTriggerRequired = errors.New("workflow has no webhook trigger")
+	ErrInvalidRequest         = errors.New("request does not match the endpoint schema")
+	ErrInvalidAllowedIPs      = errors.New("allowed_ips must be IP addresses or CIDR ranges")
+	ErrNetworkNotAllowed      = errors.New("access denied from this network")
+	ErrEngineUnavailable      = errors.New("executions cannot be started, retry later")
+	ErrTeamAccessDenied       = errors.New("team access denied")
+	ErrDomainNotFound         = errors.New("custom domain not found")
+	ErrInvalidHostname        = errors.New("hostname must be a domain name such as hooks.example.com")
+	ErrHostnameTaken          = errors.New("hostname is already in use")
+	ErrDomainNotVerified      = errors.New("domain verification record not found")
+)
@@ -0,0 +1,56 @@
+package endpoint
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Repository defines persistence operations for workflow endpoints
+type Repository interface {
+	Create(ctx context.Context, endpoint *Endpoint) error
+	Update(ctx context.Context, endpoint *Endpoint) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	FindByID(ctx context.Context, id uuid.UUID) (*Endpoint, error)
+	FindBySlug(ctx context.Context, slug string) (*Endpoint, error)
+
+	// List returns the endpoints owned by userID, or all endpoints when
+	// userID is nil
+	List(ctx context.Context, userID *uuid.UUID) ([]*Endpoint, error)
+
+	// RecordCall adds a call to the usage of its day
+	RecordCall(ctx context.Context, endpointID uuid.UUID, call Call) error
+
+	// Usage returns the daily usage between from and to, oldest first
+	Usage(ctx context.Context, endpointID uuid.UUID, from, to time.Time) ([]*Usage, error)
+}
+
+// BufferRepository persists the calls accepted while executions could not
+// be started
+type BufferRepository interface {
+	Create(ctx context.Context, call *BufferedCall) error
+	Update(ctx context.Context, call *BufferedCall) error
+	Delete(ctx context.Context, id uuid.UUID) error
+
+	// FindOldest returns up to limit buffered calls, oldest first
+	FindOldest(ctx context.Context, limit int) ([]*BufferedCall, error)
+
+	// Count returns the number of buffered calls
+	Count(ctx context.Context) (int64, error)
+}
+
+// CustomDomainRepository persists the custom domains of teams
+type CustomDomainRepository interface {
+	Create(ctx context.Context, domain *CustomDomain) error
+	Update(ctx context.Context, domain *CustomDomain) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	FindByID(ctx context.Context, id uuid.UUID) (*CustomDomain, error)
+	FindByHostname(ctx context.Context, hostname string) (*CustomDomain, error)
+
+	// FindByTeam returns the custom domains of a team by hostname
+	FindByTeam(ctx context.Context, teamID uuid.UUID) ([]*CustomDomain, error)
+
+	// FindVerified returns every verified custom domain
+	FindVerified(ctx context.Context) ([]*CustomDomain, error)
+}
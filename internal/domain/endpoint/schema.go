@@ -0,0 +1,105 @@
+package endpoint
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+)
+
+// ValidateRequest checks a decoded JSON body against the request schema of
+// an endpoint. It supports the subset of JSON schema used to describe
+// webhook payloads: type, required, properties, items and enum. An empty
+// schema accepts every body.
+func ValidateRequest(schema map[string]interface{}, body interface{}) error {
+	if len(schema) == 0 {
+		return nil
+	}
+	if msg := validate(schema, body, "body"); msg != "" {
+		return fmt.Errorf("%w: %s", ErrInvalidRequest, msg)
+	}
+	return nil
+}
+
+// validate returns a description of the first violation, or "" when value
+// matches schema
+func validate(schema map[string]interface{}, value interface{}, path string) string {
+	if t, ok := schema["type"].(string); ok && !hasType(value, t) {
+		return fmt.Sprintf("%s must be of type %s", path, t)
+	}
+
+	if enum, ok := schema["enum"].([]interface{}); ok && len(enum) > 0 {
+		found := false
+		for _, allowed := range enum {
+			if reflect.DeepEqual(allowed, value) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Sprintf("%s must be one of %v", path, enum)
+		}
+	}
+
+	if obj, ok := value.(map[string]interface{}); ok {
+		if required, ok := schema["required"].([]interface{}); ok {
+			for _, r := range required {
+				name, _ := r.(string)
+				if _, present := obj[name]; name != "" && !present {
+					return fmt.Sprintf("%s.%s is required", path, name)
+				}
+			}
+		}
+		if properties, ok := schema["properties"].(map[string]interface{}); ok {
+			for name, raw := range properties {
+				sub, ok := raw.(map[string]interface{})
+				v, present := obj[name]
+				if !ok || !present {
+					continue
+				}
+				if msg := validate(sub, v, path+"."+name); msg != "" {
+					return msg
+				}
+			}
+		}
+	}
+
+	if arr, ok := value.([]interface{}); ok {
+		if items, ok := schema["items"].(map[string]interface{}); ok {
+			for i, v := range arr {
+				if msg := validate(items, v, fmt.Sprintf("%s[%d]", path, i)); msg != "" {
+					return msg
+				}
+			}
+		}
+	}
+	return ""
+}
+
+// hasType reports whether a decoded JSON value has the JSON schema type t.
+// Unknown types are accepted.
+func hasType(value interface{}, t string) bool {
+	switch t {
+	case "object":
+		_, ok := value.(map[string]interface{})
+		return ok
+	case "array":
+		_, ok := value.([]interface{})
+		return ok
+	case "string":
+		_, ok := value.(string)
+		return ok
+	case "boolean":
+		_, ok := value.(bool)
+		return ok
+	case "number":
+		_, ok := value.(float64)
+		return ok
+	case "integer":
+		f, ok := value.(float64)
+		return ok && f == math.Trunc(f)
+	case "null":
+		return value == nil
+	default:
+		return true
+	}
+}
@@ -0,0 +1,64 @@
+package execution
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// madScale makes the median absolute deviation comparable to a standard
+// deviation for normally distributed durations
+const madScale = 1.4826
+
+// Baseline summarizes typical durations by their median and median
+// absolute deviation, which unlike the mean and standard deviation are not
+// skewed by the slow outliers being looked for
+type Baseline struct {
+	Samples  int     `json:"samples"`
+	MedianMs float64 `json:"median_ms"`
+	MADMs    float64 `json:"mad_ms"`
+}
+
+// NewBaseline computes the baseline of durations in milliseconds
+func NewBaseline(durations []float64) Baseline {
+	if len(durations) == 0 {
+		return Baseline{}
+	}
+	median := medianOf(durations)
+	deviations := make([]float64, len(durations))
+	for i, d := range durations {
+		deviations[i] = math.Abs(d - median)
+	}
+	return Baseline{
+		Samples:  len(durations),
+		MedianMs: median,
+		MADMs:    medianOf(deviations),
+	}
+}
+
+// Score returns how many scaled deviations durationMs lies above the
+// median. The deviation is at least a millisecond, so identical samples do
+// not make every slower run infinitely far off.
+func (b Baseline) Score(durationMs float64) float64 {
+	return (durationMs - b.MedianMs) / math.Max(madScale*b.MADMs, 1)
+}
+
+// IsSlow returns whether durationMs is at least threshold scaled
+// deviations and minDelta above the median
+func (b Baseline) IsSlow(durationMs, threshold float64, minDelta time.Duration) bool {
+	if b.Samples == 0 {
+		return false
+	}
+	return durationMs-b.MedianMs >= float64(minDelta.Milliseconds()) && b.Score(durationMs) >= threshold
+}
+
+// medianOf returns the median of values without reordering them
+func medianOf(values []float64) float64 {
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[mid-1] + sorted[mid]) / 2
+	}
+	return sorted[mid]
+}
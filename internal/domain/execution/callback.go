@@ -0,0 +1,80 @@
+package execution
+
+import (
+	"net/url"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CallbackStatus represents the delivery state of an execution callback
+type CallbackStatus string
+
+const (
+	CallbackStatusPending   CallbackStatus = "pending"
+	CallbackStatusDelivered CallbackStatus = "delivered"
+	CallbackStatusFailed    CallbackStatus = "failed"
+)
+
+// Callback is a request to POST the result of an execution to a URL once
+// it finishes
+type Callback struct {
+	ID            uuid.UUID      `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	ExecutionID   uuid.UUID      `json:"execution_id" gorm:"type:uuid;not null"`
+	URL           string         `json:"url" gorm:"not null"`
+	Status        CallbackStatus `json:"status" gorm:"not null"`
+	Attempts      int            `json:"attempts" gorm:"default:0"`
+	NextAttemptAt time.Time      `json:"next_attempt_at"`
+	LastError     string         `json:"last_error,omitempty"`
+	DeliveredAt   *time.Time     `json:"delivered_at,omitempty"`
+	CreatedAt     time.Time      `json:"created_at"`
+	UpdatedAt     time.Time      `json:"updated_at"`
+}
+
+// TableName specifies the table name for GORM
+func (Callback) TableName() string {
+	return "execution_callbacks"
+}
+
+// NewCallback creates a pending callback for an execution
+func NewCallback(executionID uuid.UUID, rawURL string) (*Callback, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, ErrInvalidCallbackURL
+	}
+	now := time.Now()
+	return &Callback{
+		ExecutionID:   executionID,
+		URL:           u.String(),
+		Status:        CallbackStatusPending,
+		NextAttemptAt: now,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}, nil
+}
+
+// Delivered marks the callback as delivered
+func (c *Callback) Delivered() {
+	now := time.Now()
+	c.Attempts++
+	c.Status = CallbackStatusDelivered
+	c.DeliveredAt = &now
+	c.LastError = ""
+}
+
+// Retry records a failed attempt and schedules the next one, or marks the
+// callback failed once maxAttempts is reached
+func (c *Callback) Retry(err error, maxAttempts int, backoff, maxBackoff time.Duration) {
+	c.Attempts++
+	c.LastError = err.Error()
+	if c.Attempts >= maxAttempts {
+		c.Status = CallbackStatusFailed
+		return
+	}
+
+	delay := backoff << (c.Attempts - 1)
+	if delay > maxBackoff || delay <= 0 {
+		delay = maxBackoff
+	}
+	c.NextAttemptAt = time.Now().Add(delay)
+}
@@ -0,0 +1,366 @@
+package execution
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Kinds of changes between two executions
+const (
+	ChangeAdded     = "added"
+	ChangeRemoved   = "removed"
+	ChangeChanged   = "changed"
+	ChangeUnchanged = "unchanged"
+)
+
+const (
+	// maxInlineSize is the JSON size above which compared values are
+	// summarized rather than included
+	maxInlineSize = 256
+	// previewLength is the number of characters of a summarized string
+	// included as its preview
+	previewLength = 64
+	// maxValueChanges bounds the changes reported per value compared
+	maxValueChanges = 50
+	// maxCompareDepth is the nesting depth below which values are compared
+	// as a whole
+	maxCompareDepth = 10
+)
+
+// Comparison is a structured diff of two executions, the base and another
+// run compared to it
+type Comparison struct {
+	Base  RunSummary `json:"base"`
+	Other RunSummary `json:"other"`
+	// SameVersion is set when both ran the same workflow version, so
+	// differences come from data or external systems rather than edits
+	SameVersion     bool `json:"same_version"`
+	StatusChanged   bool `json:"status_changed"`
+	DurationDeltaMs int  `json:"duration_delta_ms"`
+	// FirstDivergence is the first node, in the order the other execution
+	// ran them, whose status differs or that ran in only one execution
+	FirstDivergence string           `json:"first_divergence,omitempty"`
+	Input           ValueDiff        `json:"input"`
+	Nodes           []NodeComparison `json:"nodes"`
+}
+
+// RunSummary describes a compared execution
+type RunSummary struct {
+	ID              uuid.UUID       `json:"id"`
+	WorkflowVersion int             `json:"workflow_version"`
+	Status          ExecutionStatus `json:"status"`
+	Mode            ExecutionMode   `json:"mode"`
+	StartedAt       time.Time       `json:"started_at"`
+	ExecutionTimeMs int             `json:"execution_time_ms"`
+	ErrorMessage    string          `json:"error_message,omitempty"`
+	ErrorNode       string          `json:"error_node,omitempty"`
+	// NodeRuns counts the stored node runs, zero when node data was not
+	// saved
+	NodeRuns int `json:"node_runs"`
+}
+
+// NodeComparison compares the runs of a node in both executions. Nodes
+// that ran several times are described by their last run, with durations
+// summed over all.
+type NodeComparison struct {
+	NodeID   string `json:"node_id"`
+	NodeName string `json:"node_name,omitempty"`
+	NodeType string `json:"node_type"`
+	// Change is added for nodes that ran only in the other execution,
+	// removed for those that ran only in the base one
+	Change          string         `json:"change"`
+	Base            *NodeRunResult `json:"base,omitempty"`
+	Other           *NodeRunResult `json:"other,omitempty"`
+	StatusChanged   bool           `json:"status_changed,omitempty"`
+	DurationDeltaMs int            `json:"duration_delta_ms,omitempty"`
+	Output          ValueDiff      `json:"output"`
+}
+
+// NodeRunResult describes the runs of a node in one execution
+type NodeRunResult struct {
+	Status          ExecutionStatus `json:"status"`
+	Runs            int             `json:"runs"`
+	ExecutionTimeMs int             `json:"execution_time_ms"`
+	Error           string          `json:"error,omitempty"`
+}
+
+// ValueDiff lists the changes between two JSON values by path, such as
+// data[0].json.status
+type ValueDiff struct {
+	Changes []ValueChange `json:"changes"`
+	// Truncated is set when there were more changes than listed
+	Truncated bool `json:"truncated,omitempty"`
+}
+
+// ValueChange is a changed value. Large values are replaced by a Summary.
+type ValueChange struct {
+	Path   string      `json:"path"`
+	Change string      `json:"change"`
+	Base   interface{} `json:"base,omitempty"`
+	Other  interface{} `json:"other,omitempty"`
+}
+
+// Summary stands in for a value too large to include, with enough to tell
+// whether and how it changed
+type Summary struct {
+	Type string `json:"type"`
+	// Size is the length of the JSON encoding in bytes
+	Size int `json:"size"`
+	// Length is the number of characters, items or keys
+	Length  int    `json:"length"`
+	SHA256  string `json:"sha256"`
+	Preview string `json:"preview,omitempty"`
+}
+
+// Compare diffs two executions and their node runs, each in start order
+func Compare(base, other *Execution, baseRuns, otherRuns []*NodeExecution) *Comparison {
+	c := &Comparison{
+		Base:            summarizeRun(base, len(baseRuns)),
+		Other:           summarizeRun(other, len(otherRuns)),
+		SameVersion:     base.WorkflowVersion == other.WorkflowVersion,
+		StatusChanged:   base.Status != other.Status,
+		DurationDeltaMs: other.ExecutionTimeMs - base.ExecutionTimeMs,
+		Input:           DiffValues(base.InputData, other.InputData),
+		Nodes:           []NodeComparison{},
+	}
+
+	baseNodes, _ := groupRuns(baseRuns)
+	otherNodes, order := groupRuns(otherRuns)
+	for _, r := range baseRuns {
+		if _, ok := otherNodes[r.NodeID]; !ok && !contains(order, r.NodeID) {
+			order = append(order, r.NodeID)
+		}
+	}
+
+	for _, id := range order {
+		b, o := baseNodes[id], otherNodes[id]
+		nc := NodeComparison{NodeID: id}
+		last := o
+		if last == nil {
+			last = b
+		}
+		nc.NodeName = last.last.NodeName
+		nc.NodeType = last.last.NodeType
+
+		var baseOutput, otherOutput map[string]interface{}
+		if b != nil {
+			nc.Base = b.result()
+			baseOutput = b.last.OutputData
+		}
+		if o != nil {
+			nc.Other = o.result()
+			otherOutput = o.last.OutputData
+		}
+		switch {
+		case b == nil:
+			nc.Change = ChangeAdded
+		case o == nil:
+			nc.Change = ChangeRemoved
+		default:
+			nc.StatusChanged = nc.Base.Status != nc.Other.Status
+			nc.DurationDeltaMs = nc.Other.ExecutionTimeMs - nc.Base.ExecutionTimeMs
+		}
+		nc.Output = DiffValues(baseOutput, otherOutput)
+		if nc.Change == "" {
+			nc.Change = ChangeUnchanged
+			if nc.StatusChanged || len(nc.Output.Changes) > 0 || nc.Base.Error != nc.Other.Error {
+				nc.Change = ChangeChanged
+			}
+		}
+		if c.FirstDivergence == "" && (nc.Change == ChangeAdded || nc.Change == ChangeRemoved || nc.StatusChanged) {
+			c.FirstDivergence = id
+		}
+		c.Nodes = append(c.Nodes, nc)
+	}
+	return c
+}
+
+func summarizeRun(e *Execution, nodeRuns int) RunSummary {
+	return RunSummary{
+		ID:              e.ID,
+		WorkflowVersion: e.WorkflowVersion,
+		Status:          e.Status,
+		Mode:            e.Mode,
+		StartedAt:       e.StartedAt,
+		ExecutionTimeMs: e.ExecutionTimeMs,
+		ErrorMessage:    e.ErrorMessage,
+		ErrorNode:       e.ErrorNode,
+		NodeRuns:        nodeRuns,
+	}
+}
+
+// nodeRuns are the runs of a node in one execution
+type nodeRuns struct {
+	last       *NodeExecution
+	count      int
+	durationMs int
+}
+
+func (n *nodeRuns) result() *NodeRunResult {
+	return &NodeRunResult{
+		Status:          n.last.Status,
+		Runs:            n.count,
+		ExecutionTimeMs: n.durationMs,
+		Error:           n.last.ErrorMessage,
+	}
+}
+
+// groupRuns groups runs by node, returning the nodes in the order they
+// first ran
+func groupRuns(runs []*NodeExecution) (map[string]*nodeRuns, []string) {
+	nodes := make(map[string]*nodeRuns)
+	var order []string
+	for _, r := range runs {
+		n, ok := nodes[r.NodeID]
+		if !ok {
+			n = &nodeRuns{}
+			nodes[r.NodeID] = n
+			order = append(order, r.NodeID)
+		}
+		n.last = r
+		n.count++
+		n.durationMs += r.ExecutionTimeMs
+	}
+	return nodes, order
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// DiffValues lists the changes from base to other, two decoded JSON values
+func DiffValues(base, other interface{}) ValueDiff {
+	d := ValueDiff{Changes: []ValueChange{}}
+	d.diff("", normalize(base), normalize(other), 0)
+	return d
+}
+
+func (d *ValueDiff) diff(path string, base, other interface{}, depth int) {
+	if reflect.DeepEqual(base, other) {
+		return
+	}
+	if depth < maxCompareDepth {
+		switch b := base.(type) {
+		case map[string]interface{}:
+			if o, ok := other.(map[string]interface{}); ok {
+				d.diffObjects(path, b, o, depth)
+				return
+			}
+		case []interface{}:
+			if o, ok := other.([]interface{}); ok {
+				d.diffArrays(path, b, o, depth)
+				return
+			}
+		}
+	}
+
+	change := ChangeChanged
+	switch {
+	case base == nil:
+		change = ChangeAdded
+	case other == nil:
+		change = ChangeRemoved
+	}
+	d.add(ValueChange{Path: path, Change: change, Base: summarize(base), Other: summarize(other)})
+}
+
+func (d *ValueDiff) diffObjects(path string, base, other map[string]interface{}, depth int) {
+	keys := make([]string, 0, len(base)+len(other))
+	for k := range base {
+		keys = append(keys, k)
+	}
+	for k := range other {
+		if _, ok := base[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		if d.Truncated {
+			return
+		}
+		d.diff(joinPath(path, k), base[k], other[k], depth+1)
+	}
+}
+
+func (d *ValueDiff) diffArrays(path string, base, other []interface{}, depth int) {
+	for i := 0; i < len(base) || i < len(other); i++ {
+		if d.Truncated {
+			return
+		}
+		var b, o interface{}
+		if i < len(base) {
+			b = base[i]
+		}
+		if i < len(other) {
+			o = other[i]
+		}
+		d.diff(path+"["+strconv.Itoa(i)+"]", b, o, depth+1)
+	}
+}
+
+func (d *ValueDiff) add(change ValueChange) {
+	if len(d.Changes) >= maxValueChanges {
+		d.Truncated = true
+		return
+	}
+	d.Changes = append(d.Changes, change)
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// normalize turns an empty object into nil, so missing and empty data
+// compare equal
+func normalize(v interface{}) interface{} {
+	if m, ok := v.(map[string]interface{}); ok && len(m) == 0 {
+		return nil
+	}
+	return v
+}
+
+// summarize returns small values as they are and a Summary of large ones
+func summarize(v interface{}) interface{} {
+	if v == nil {
+		return nil
+	}
+	encoded, err := json.Marshal(v)
+	if err != nil || len(encoded) <= maxInlineSize {
+		return v
+	}
+	sum := sha256.Sum256(encoded)
+	s := Summary{Size: len(encoded), SHA256: hex.EncodeToString(sum[:])}
+	switch value := v.(type) {
+	case string:
+		runes := []rune(value)
+		s.Type = "string"
+		s.Length = len(runes)
+		s.Preview = string(runes[:min(len(runes), previewLength)])
+	case []interface{}:
+		s.Type = "array"
+		s.Length = len(value)
+	case map[string]interface{}:
+		s.Type = "object"
+		s.Length = len(value)
+	default:
+		s.Type = fmt.Sprintf("%T", v)
+	}
+	return s
+}
@@ -1,11 +1,14 @@
 package execution
 
 import (
+	"encoding/json"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/jaydeep/go-n8n/internal/domain/node"
 )
 
+
 // Execution represents a workflow execution
 type Execution struct {
 	ID              uuid.UUID              `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
@@ -14,6 +17,8 @@ type Execution struct {
 	Status          ExecutionStatus        `json:"status" gorm:"not null"`
 	Mode            ExecutionMode          `json:"mode" gorm:"not null"`
 	StartedAt       time.Time              `json:"started_at"`
+	// ResumedAt is when the execution last resumed after waiting
+	ResumedAt       *time.Time             `json:"resumed_at,omitempty"`
 	FinishedAt      *time.Time             `json:"finished_at,omitempty"`
 	ExecutionTimeMs int                    `json:"execution_time_ms,omitempty"`
 	InputData       map[string]interface{} `json:"input_data" gorm:"serializer:json"`
@@ -22,7 +27,35 @@ type Execution struct {
 	ErrorNode       string                 `json:"error_node,omitempty"`
 	RetryOf         *uuid.UUID             `json:"retry_of,omitempty" gorm:"type:uuid"`
 	RetryCount      int                    `json:"retry_count" gorm:"default:0"`
-	CreatedAt       time.Time              `json:"created_at"`
+	DryRun          bool                   `json:"dry_run" gorm:"default:false"`
+	// DryRunOptions tunes what a dry run mocks and skips
+	DryRunOptions *DryRunOptions `json:"dry_run_options,omitempty" gorm:"serializer:json"`
+	// ParentExecutionID is the execution that started this one, as a
+	// sub-workflow or error workflow, and RootExecutionID the first
+	// execution of the chain. Both are nil for executions started directly.
+	ParentExecutionID *uuid.UUID `json:"parent_execution_id,omitempty" gorm:"type:uuid"`
+	RootExecutionID   *uuid.UUID `json:"root_execution_id,omitempty" gorm:"type:uuid"`
+	Relation          Relation   `json:"relation,omitempty"`
+	// CorrelationID ties the execution to the request that started it and
+	// is shared by every execution of its chain
+	CorrelationID string    `json:"correlation_id,omitempty"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// DryRunOptions tunes a dry run, which skips the nodes with side effects by
+// default. Nodes are named by ID or name.
+type DryRunOptions struct {
+	// Mocks holds the items nodes output in place of running
+	Mocks map[string][]node.Item `json:"mocks,omitempty"`
+	// Force lists nodes mocked or skipped even without side effects
+	Force []string `json:"force,omitempty"`
+	// Allow lists nodes running for real despite their side effects
+	Allow []string `json:"allow,omitempty"`
+}
+
+// IsZero reports whether the options leave the dry run as it is by default
+func (o *DryRunOptions) IsZero() bool {
+	return o == nil || len(o.Mocks) == 0 && len(o.Force) == 0 && len(o.Allow) == 0
 }
 
 // ExecutionStatus represents the status of an execution
@@ -36,6 +69,9 @@ const (
 	ExecutionStatusCancelled ExecutionStatus = "cancelled"
 	ExecutionStatusCrashed   ExecutionStatus = "crashed"
 	ExecutionStatusTimeout   ExecutionStatus = "timeout"
+	// ExecutionStatusSkippedTimeout is the status of the nodes an execution
+	// did not run before it timed out
+	ExecutionStatusSkippedTimeout ExecutionStatus = "skipped_timeout"
 )
 
 // ExecutionMode represents how the execution was triggered
@@ -48,6 +84,22 @@ const (
 	ExecutionModeSchedule ExecutionMode = "schedule"
 	ExecutionModeRetry    ExecutionMode = "retry"
 	ExecutionModeTest     ExecutionMode = "test"
+	// ExecutionModeError is the mode of error workflow runs
+	ExecutionModeError ExecutionMode = "error"
+)
+
+// IsInteractive reports whether the execution was started from the editor,
+// which shows the output of each node as it finishes
+func (m ExecutionMode) IsInteractive() bool {
+	return m == ExecutionModeManual || m == ExecutionModeTest
+}
+
+// Relation is how an execution relates to the execution that started it
+type Relation string
+
+const (
+	RelationSubWorkflow   Relation = "sub_workflow"
+	RelationErrorWorkflow Relation = "error_workflow"
 )
 
 // NodeExecution represents the execution state of a single node
@@ -67,6 +119,11 @@ type NodeExecution struct {
 	RetryCount      int                    `json:"retry_count" gorm:"default:0"`
 }
 
+// TableName returns the table node executions are stored in
+func (NodeExecution) TableName() string {
+	return "execution_node_data"
+}
+
 // ExecutionContext holds the runtime context for an execution
 type ExecutionContext struct {
 	ExecutionID     uuid.UUID              `json:"execution_id"`
@@ -80,6 +137,7 @@ type ExecutionContext struct {
 	StartTime       time.Time              `json:"start_time"`
 	MaxExecutionTime time.Duration         `json:"max_execution_time"`
 	RetryPolicy     RetryPolicy            `json:"retry_policy"`
+	DryRun          bool                   `json:"dry_run"`
 }
 
 // RetryPolicy defines retry behavior for failed executions
@@ -90,6 +148,24 @@ type RetryPolicy struct {
 	MaxRetryInterval time.Duration `json:"max_retry_interval"`
 }
 
+// Delay returns the wait before a retry, counted from 1: RetryInterval,
+// multiplied by BackoffFactor for each retry before it and capped at
+// MaxRetryInterval when set. A BackoffFactor below 1 keeps the interval
+// constant.
+func (p RetryPolicy) Delay(retry int) time.Duration {
+	delay := p.RetryInterval
+	for i := 1; i < retry && p.BackoffFactor > 1; i++ {
+		delay = time.Duration(float64(delay) * p.BackoffFactor)
+		if p.MaxRetryInterval > 0 && delay >= p.MaxRetryInterval {
+			break
+		}
+	}
+	if p.MaxRetryInterval > 0 && delay > p.MaxRetryInterval {
+		delay = p.MaxRetryInterval
+	}
+	return delay
+}
+
 // ExecutionStatistics holds execution metrics
 type ExecutionStatistics struct {
 	TotalExecutions int                    `json:"total_executions"`
@@ -112,24 +188,98 @@ type NodeStats struct {
 // IsTerminal returns whether the execution is in a terminal state
 func (s ExecutionStatus) IsTerminal() bool {
 	switch s {
-	case ExecutionStatusSuccess, ExecutionStatusError, ExecutionStatusCancelled, ExecutionStatusCrashed, ExecutionStatusTimeout:
+	case ExecutionStatusSuccess, ExecutionStatusError, ExecutionStatusCancelled, ExecutionStatusCrashed, ExecutionStatusTimeout,
+		ExecutionStatusSkippedTimeout:
 		return true
 	default:
 		return false
 	}
 }
 
+// IsFailed returns whether the execution ended in error, crashed or timed
+// out
+func (s ExecutionStatus) IsFailed() bool {
+	return s == ExecutionStatusError || s == ExecutionStatusCrashed || s == ExecutionStatusTimeout
+}
+
 // IsRunning returns whether the execution is currently running
 func (s ExecutionStatus) IsRunning() bool {
 	return s == ExecutionStatusRunning || s == ExecutionStatusWaiting
 }
 
+// LinkTo records parent as the execution that started this one, sharing
+// the root and correlation ID of its chain
+func (e *Execution) LinkTo(parent *Execution, relation Relation) {
+	root := parent.ID
+	if parent.RootExecutionID != nil {
+		root = *parent.RootExecutionID
+	}
+	e.ParentExecutionID = &parent.ID
+	e.RootExecutionID = &root
+	e.Relation = relation
+	e.CorrelationID = parent.CorrelationID
+}
+
+// inputItemsKey holds the input items of an execution in its input data
+const inputItemsKey = "$items"
+
+// SetInputItems sets the items the execution starts with, such as those
+// passed to a sub-workflow
+func (e *Execution) SetInputItems(items []node.Item) {
+	if items == nil {
+		items = []node.Item{}
+	}
+	e.InputData = map[string]interface{}{inputItemsKey: items}
+}
+
+// InputItems returns the items the nodes without incoming connections run
+// with: those set with SetInputItems, or else the input data as one item
+// when there is any
+func (e *Execution) InputItems() []node.Item {
+	if items, ok := e.InputData[inputItemsKey].([]node.Item); ok {
+		return items
+	}
+	// Input data read back from storage holds the items decoded as JSON
+	if raw, ok := e.InputData[inputItemsKey]; ok {
+		var items []node.Item
+		if b, err := json.Marshal(raw); err == nil && json.Unmarshal(b, &items) == nil {
+			return items
+		}
+	}
+	if len(e.InputData) == 0 {
+		return []node.Item{}
+	}
+	return []node.Item{{JSON: e.InputData}}
+}
+
 // Start marks the execution as started
 func (e *Execution) Start() {
 	e.Status = ExecutionStatusRunning
 	e.StartedAt = time.Now()
 }
 
+// Suspend marks the execution as waiting to be resumed
+func (e *Execution) Suspend() {
+	e.Status = ExecutionStatusWaiting
+}
+
+// Resume marks a suspended execution as running again. It keeps its start
+// time, so its duration covers the wait, and records when it resumed.
+func (e *Execution) Resume() {
+	now := time.Now()
+	e.Status = ExecutionStatusRunning
+	e.ResumedAt = &now
+}
+
+// RunningSince returns when the current run of the execution started: when
+// it last resumed, or when it started
+func (e *Execution) RunningSince() time.Time {
+	if e.ResumedAt != nil {
+		return *e.ResumedAt
+	}
+	return e.StartedAt
+}
+
 // Complete marks the execution as completed successfully
 func (e *Execution) Complete(outputData map[string]interface{}) {
 	e.Status = ExecutionStatusSuccess
@@ -157,6 +307,13 @@ func (e *Execution) Timeout() {
 	e.finish()
 }
 
+// Crash marks the execution as crashed, e.g. after its worker died
+func (e *Execution) Crash(reason string) {
+	e.Status = ExecutionStatusCrashed
+	e.ErrorMessage = reason
+	e.finish()
+}
+
 // finish sets the finish time and calculates execution time
 func (e *Execution) finish() {
 	now := time.Now()
@@ -180,7 +337,14 @@ func (e *Execution) CreateRetry() *Execution {
 		InputData:       e.InputData,
 		RetryOf:         &e.ID,
 		RetryCount:      e.RetryCount + 1,
-		CreatedAt:       time.Now(),
+		DryRun:          e.DryRun,
+		DryRunOptions:   e.DryRunOptions,
+		// A retry takes the place of the execution in its chain
+		ParentExecutionID: e.ParentExecutionID,
+		RootExecutionID:   e.RootExecutionID,
+		Relation:          e.Relation,
+		CorrelationID:     e.CorrelationID,
+		CreatedAt:         time.Now(),
 	}
 	return retry
 }
@@ -0,0 +1,46 @@
+package execution
+
+import "errors"
+
+var (
+	// Execution errors
+	ErrExecutionNotFound       = errors.New("execution not found")
+	ErrExecutionNotRunning     = errors.New("execution is not running")
+	ErrExecutionAlreadyEnded   = errors.New("execution has already finished")
+	ErrExecutionsNotComparable = errors.New("only executions of the same workflow can be compared")
+	ErrExecutionOnHold         = errors.New("execution is under legal hold")
+
+	// Wait errors
+	ErrExecutionNotWaiting = errors.New("execution is not waiting to be resumed")
+	ErrExecutionWaiting    = errors.New("execution is waiting to be resumed")
+	ErrWaitNotResumable    = errors.New("execution waits until a set time and cannot be resumed by request")
+
+	// Sub-workflow errors
+	ErrSubWorkflowCycle   = errors.New("sub-workflow is already running in the chain of the execution starting it")
+	ErrSubWorkflowTooDeep = errors.New("sub-workflows are nested deeper than allowed")
+
+	// Loop errors
+	ErrLoopLimitExceeded = errors.New("loop ran more iterations than allowed")
+	ErrWaitInLoop        = errors.New("executions cannot wait inside a loop")
+
+	// View errors
+	ErrViewNotFound      = errors.New("execution view not found")
+	ErrViewNameRequired  = errors.New("view name is required")
+	ErrViewNameTooLong   = errors.New("view name must be at most 100 characters")
+	ErrViewNameTaken     = errors.New("a view with this name already exists")
+	ErrTooManyViews      = errors.New("user already has the maximum of 50 execution views")
+	ErrInvalidViewStatus = errors.New("status must be an execution status")
+	ErrErrorTextTooLong  = errors.New("error_text must be at most 200 characters")
+	ErrInvalidViewRange  = errors.New("from must be before to")
+	ErrInvalidViewPeriod = errors.New("period must be a positive duration such as 24h and cannot be combined with from or to")
+
+	// Legal hold errors
+	ErrHoldNotFound         = errors.New("legal hold not found")
+	ErrHoldReasonRequired   = errors.New("hold reason is required")
+	ErrHoldReasonTooLong    = errors.New("hold reason must be at most 500 characters")
+	ErrHoldReferenceTooLong = errors.New("hold reference must be at most 200 characters")
+	ErrHoldReleased         = errors.New("legal hold has already been released")
+
+	// Callback errors
+	ErrInvalidCallbackURL = errors.New("callback_url must be an absolute http or https URL")
+)
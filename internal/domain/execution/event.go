@@ -0,0 +1,96 @@
+package execution
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// EventType identifies a step in the life of an execution
+type EventType string
+
+const (
+	EventExecutionQueued   EventType = "execution.queued"
+	EventExecutionStarted  EventType = "execution.started"
+	EventExecutionWaiting  EventType = "execution.waiting"
+	EventExecutionResumed  EventType = "execution.resumed"
+	EventExecutionFinished EventType = "execution.finished"
+	EventNodeStarted       EventType = "node.started"
+	EventNodeRetrying      EventType = "node.retrying"
+	EventNodeFinished      EventType = "node.finished"
+)
+
+// Event is a flat record of an execution or node transition, shaped for
+// analytics stores. It carries metadata only, never input or output data.
+type Event struct {
+	ID              uuid.UUID       `json:"id"`
+	Type            EventType       `json:"type"`
+	Timestamp       time.Time       `json:"timestamp"`
+	ExecutionID     uuid.UUID       `json:"execution_id"`
+	WorkflowID      uuid.UUID       `json:"workflow_id"`
+	WorkflowVersion int             `json:"workflow_version"`
+	Mode            ExecutionMode   `json:"mode"`
+	Status          ExecutionStatus `json:"status"`
+	NodeName        string          `json:"node_name,omitempty"`
+	NodeType        string          `json:"node_type,omitempty"`
+	DurationMs      int             `json:"duration_ms"`
+	ErrorMessage    string          `json:"error_message,omitempty"`
+	ErrorNode       string          `json:"error_node,omitempty"`
+	RetryCount      int             `json:"retry_count"`
+	DryRun          bool            `json:"dry_run"`
+	CorrelationID   string          `json:"correlation_id,omitempty"`
+}
+
+// NewEvent records the current state of an execution
+func NewEvent(eventType EventType, e *Execution) *Event {
+	return &Event{
+		ID:              uuid.New(),
+		Type:            eventType,
+		Timestamp:       time.Now().UTC(),
+		ExecutionID:     e.ID,
+		WorkflowID:      e.WorkflowID,
+		WorkflowVersion: e.WorkflowVersion,
+		Mode:            e.Mode,
+		Status:          e.Status,
+		DurationMs:      e.ExecutionTimeMs,
+		ErrorMessage:    e.ErrorMessage,
+		ErrorNode:       e.ErrorNode,
+		RetryCount:      e.RetryCount,
+		DryRun:          e.DryRun,
+		CorrelationID:   e.CorrelationID,
+	}
+}
+
+// NewNodeEvent records the current state of a node of an execution
+func NewNodeEvent(eventType EventType, e *Execution, node *NodeExecution) *Event {
+	event := NewEvent(eventType, e)
+	event.Status = node.Status
+	event.NodeName = node.NodeName
+	event.NodeType = node.NodeType
+	event.DurationMs = node.ExecutionTimeMs
+	event.ErrorMessage = node.ErrorMessage
+	event.ErrorNode = ""
+	event.RetryCount = node.RetryCount
+	return event
+}
+
+// EventPublisher accepts events for delivery to the analytics sinks.
+// Publish must not block the execution path.
+type EventPublisher interface {
+	Publish(event *Event)
+}
+
+// OutputPublisher streams the output of each node of interactive
+// executions as it finishes. Publish must not block the execution path.
+type OutputPublisher interface {
+	PublishOutput(e *Execution, node *NodeExecution)
+}
+
+// EventSink writes batches of events to an external analytics store
+type EventSink interface {
+	// Name identifies the sink in logs
+	Name() string
+	Write(ctx context.Context, events []*Event) error
+	Close() error
+}
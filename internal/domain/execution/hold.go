@@ -0,0 +1,69 @@
+package execution
+
+import (
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// maxHoldReasonLength bounds the reason of a hold, in characters
+	maxHoldReasonLength = 500
+	// maxHoldReferenceLength bounds the reference of a hold, in characters
+	maxHoldReferenceLength = 200
+)
+
+// Hold is a legal hold on the executions of a workflow. While any hold on
+// a workflow is in place its executions, whenever they started, are
+// neither pruned by retention nor deleted by hand. Released holds are kept
+// as a record of when executions were protected and by whom.
+type Hold struct {
+	ID         uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	WorkflowID uuid.UUID `json:"workflow_id" gorm:"type:uuid;not null;index"`
+	Reason     string    `json:"reason" gorm:"not null"`
+	// Reference identifies the matter the hold is for, such as a case
+	// number
+	Reference  string     `json:"reference,omitempty"`
+	PlacedBy   uuid.UUID  `json:"placed_by" gorm:"type:uuid;not null"`
+	CreatedAt  time.Time  `json:"created_at"`
+	ReleasedAt *time.Time `json:"released_at,omitempty"`
+	ReleasedBy *uuid.UUID `json:"released_by,omitempty" gorm:"type:uuid"`
+}
+
+// TableName specifies the table name for GORM
+func (Hold) TableName() string {
+	return "execution_holds"
+}
+
+// Active reports whether the hold is still in place
+func (h *Hold) Active() bool {
+	return h.ReleasedAt == nil
+}
+
+// Validate checks the hold's reason and reference
+func (h *Hold) Validate() error {
+	h.Reason = strings.TrimSpace(h.Reason)
+	h.Reference = strings.TrimSpace(h.Reference)
+	if h.Reason == "" {
+		return ErrHoldReasonRequired
+	}
+	if utf8.RuneCountInString(h.Reason) > maxHoldReasonLength {
+		return ErrHoldReasonTooLong
+	}
+	if utf8.RuneCountInString(h.Reference) > maxHoldReferenceLength {
+		return ErrHoldReferenceTooLong
+	}
+	return nil
+}
+
+// Release lifts the hold on behalf of a user
+func (h *Hold) Release(userID uuid.UUID, at time.Time) error {
+	if !h.Active() {
+		return ErrHoldReleased
+	}
+	h.ReleasedAt = &at
+	h.ReleasedBy = &userID
+	return nil
+}
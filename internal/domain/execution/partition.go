@@ -0,0 +1,70 @@
+package execution
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// PartitionedTables are partitioned by month of started_at on PostgreSQL
+var PartitionedTables = []string{"executions", "execution_node_data"}
+
+// Partition is the monthly partition of a table holding the rows started
+// in Month
+type Partition struct {
+	Table string
+	// Month is the first instant of the month in UTC
+	Month time.Time
+}
+
+// MonthOf returns the first instant of the month of t in UTC
+func MonthOf(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
+
+// NewPartition returns the partition of table holding the rows started at t
+func NewPartition(table string, t time.Time) Partition {
+	return Partition{Table: table, Month: MonthOf(t)}
+}
+
+// Name returns the table name of the partition, <table>_pYYYY_MM
+func (p Partition) Name() string {
+	return fmt.Sprintf("%s_p%04d_%02d", p.Table, p.Month.Year(), int(p.Month.Month()))
+}
+
+// End returns the first instant after the partition's range
+func (p Partition) End() time.Time {
+	return p.Month.AddDate(0, 1, 0)
+}
+
+// ParsePartition parses the name of a monthly partition of table. Other
+// partitions, such as the default one, are not recognized.
+func ParsePartition(table, name string) (Partition, bool) {
+	suffix, ok := strings.CutPrefix(name, table+"_p")
+	if !ok {
+		return Partition{}, false
+	}
+	month, err := time.Parse("2006_01", suffix)
+	if err != nil {
+		return Partition{}, false
+	}
+	return Partition{Table: table, Month: month}, true
+}
+
+// PartitionRepository manages the monthly partitions of the partitioned
+// tables
+type PartitionRepository interface {
+	// Partitioned reports whether table is partitioned
+	Partitioned(ctx context.Context, table string) (bool, error)
+
+	// ListPartitions returns the monthly partitions of table
+	ListPartitions(ctx context.Context, table string) ([]Partition, error)
+
+	// CreatePartition creates a partition unless it exists
+	CreatePartition(ctx context.Context, partition Partition) error
+
+	// DropPartition drops a partition and its rows
+	DropPartition(ctx context.Context, partition Partition) error
+}
@@ -0,0 +1,162 @@
+package execution
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jaydeep/go-n8n/pkg/pagination"
+)
+
+// ListFilter selects executions. Zero fields do not filter.
+type ListFilter struct {
+	// UserID restricts the list to executions of workflows the user owns
+	UserID     *uuid.UUID
+	WorkflowID *uuid.UUID
+	Status     ExecutionStatus
+	// CorrelationID selects the executions started by one trigger
+	CorrelationID string
+	// ErrorText selects executions whose error message contains it,
+	// ignoring case
+	ErrorText string
+	// From and To bound the start time
+	From *time.Time
+	To   *time.Time
+	// After continues a keyset listing after the given cursor
+	After  *pagination.Cursor
+	Limit  int
+	Offset int
+}
+
+// WorkflowOutcomes counts the finished executions of a workflow
+type WorkflowOutcomes struct {
+	WorkflowID uuid.UUID
+	Finished   int64
+	// Failed counts the executions that ended in error, crashed or timed
+	// out
+	Failed        int64
+	AvgDurationMs float64
+}
+
+// Repository defines persistence operations for executions
+type Repository interface {
+	Create(ctx context.Context, execution *Execution) error
+	Update(ctx context.Context, execution *Execution) error
+	FindByID(ctx context.Context, id uuid.UUID) (*Execution, error)
+
+	// FindRunningStartedBefore returns executions still marked running that
+	// started, or last resumed, before the given time
+	FindRunningStartedBefore(ctx context.Context, before time.Time) ([]*Execution, error)
+
+	// CountByStatus returns the number of executions in the given status
+	CountByStatus(ctx context.Context, status ExecutionStatus) (int64, error)
+
+	// CountRunningByTeam returns the number of running executions of the
+	// workflows of a team
+	CountRunningByTeam(ctx context.Context, teamID uuid.UUID) (int64, error)
+
+	// List returns the executions matching filter, newest first by start
+	// time and ID
+	List(ctx context.Context, filter ListFilter) ([]*Execution, error)
+
+	// ListLatest returns up to limit of the newest executions of each of
+	// the workflows
+	ListLatest(ctx context.Context, workflowIDs []uuid.UUID, limit int) ([]*Execution, error)
+
+	// ListDurations returns up to limit of the newest successful executions
+	// of a workflow, leaving out dry runs, with only their timing fields set
+	ListDurations(ctx context.Context, workflowID uuid.UUID, limit int) ([]*Execution, error)
+
+	// ListLineage returns up to limit executions of the chain started by
+	// root, root included, oldest first, without their data
+	ListLineage(ctx context.Context, rootID uuid.UUID, limit int) ([]*Execution, error)
+
+	// CountOutcomesSince counts per workflow the executions started since
+	// the given time that finished, leaving out cancelled and dry runs
+	CountOutcomesSince(ctx context.Context, since time.Time) ([]*WorkflowOutcomes, error)
+
+	// CountOutcomes is CountOutcomesSince for one workflow
+	CountOutcomes(ctx context.Context, workflowID uuid.UUID, since time.Time) (*WorkflowOutcomes, error)
+
+	// DeleteStartedBefore deletes up to limit of the oldest executions
+	// started before the given time, with their node data, returning the
+	// number deleted. Executions of workflows on legal hold are kept.
+	DeleteStartedBefore(ctx context.Context, before time.Time, limit int) (int64, error)
+
+	// Delete deletes an execution with its node data. It fails with
+	// ErrExecutionOnHold while its workflow is on legal hold.
+	Delete(ctx context.Context, id uuid.UUID) error
+}
+
+// HoldRepository defines persistence operations for legal holds
+type HoldRepository interface {
+	Create(ctx context.Context, hold *Hold) error
+	Update(ctx context.Context, hold *Hold) error
+	FindByID(ctx context.Context, id uuid.UUID) (*Hold, error)
+
+	// ListByWorkflow returns the holds of a workflow, released ones
+	// included, newest first
+	ListByWorkflow(ctx context.Context, workflowID uuid.UUID) ([]*Hold, error)
+
+	// HeldStartedBefore reports whether any execution of a workflow on
+	// legal hold started before the given time
+	HeldStartedBefore(ctx context.Context, before time.Time) (bool, error)
+}
+
+// NodeExecutionRepository defines persistence operations for node
+// execution records
+type NodeExecutionRepository interface {
+	// CreateBatch inserts records in as few statements as possible
+	CreateBatch(ctx context.Context, nodes []*NodeExecution) error
+
+	// ListByExecution returns the records of an execution in start order
+	ListByExecution(ctx context.Context, executionID uuid.UUID) ([]*NodeExecution, error)
+}
+
+// CallbackRepository defines persistence operations for execution callbacks
+type CallbackRepository interface {
+	Create(ctx context.Context, callback *Callback) error
+	Update(ctx context.Context, callback *Callback) error
+
+	// FindDue returns pending callbacks of finished executions whose next
+	// attempt is due
+	FindDue(ctx context.Context, now time.Time, limit int) ([]*Callback, error)
+}
+
+// WaitRepository defines persistence operations for waiting executions
+type WaitRepository interface {
+	// Save stores the wait of an execution, replacing an earlier one
+	Save(ctx context.Context, wait *WaitingExecution) error
+
+	// FindByExecution returns the wait of an execution, or
+	// ErrExecutionNotWaiting when it has none
+	FindByExecution(ctx context.Context, executionID uuid.UUID) (*WaitingExecution, error)
+
+	// FindDue returns unclaimed waits resuming before the given time,
+	// soonest first
+	FindDue(ctx context.Context, before time.Time, limit int) ([]*WaitingExecution, error)
+
+	// Claim marks an unclaimed wait resumed with data, reporting whether
+	// this call claimed it
+	Claim(ctx context.Context, executionID uuid.UUID, data map[string]interface{}, at time.Time) (bool, error)
+
+	// Delete removes the wait of an execution
+	Delete(ctx context.Context, executionID uuid.UUID) error
+}
+
+// ViewRepository defines persistence operations for saved execution views
+type ViewRepository interface {
+	Create(ctx context.Context, v *View) error
+	Update(ctx context.Context, v *View) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	FindByID(ctx context.Context, id uuid.UUID) (*View, error)
+
+	// ListByUser returns the views of a user ordered by name
+	ListByUser(ctx context.Context, userID uuid.UUID) ([]*View, error)
+
+	// ListSubscribed returns every subscribed view
+	ListSubscribed(ctx context.Context) ([]*View, error)
+
+	// MarkMatched records when a finished execution last matched the views
+	MarkMatched(ctx context.Context, ids []uuid.UUID, at time.Time) error
+}
@@ -0,0 +1,66 @@
+package execution
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PlannedNode is a node of the workflow an execution runs
+type PlannedNode struct {
+	ID   string
+	Type string
+	Name string
+}
+
+// TimeOutPartially marks the execution as timed out after limit, keeping
+// the outputs of the nodes that completed in its output data under
+// "nodes", by node ID. It returns a record with the skipped_timeout status
+// for each of the planned nodes without a finished run, so that the
+// execution shows where it stopped.
+func (e *Execution) TimeOutPartially(limit time.Duration, runs []*NodeExecution, planned []PlannedNode) []*NodeExecution {
+	outputs := make(map[string]interface{})
+	finished := make(map[string]bool)
+	for _, run := range runs {
+		if !run.Status.IsTerminal() {
+			continue
+		}
+		finished[run.NodeID] = true
+		if run.Status == ExecutionStatusSuccess {
+			outputs[run.NodeID] = run.OutputData
+		}
+	}
+
+	e.Timeout()
+	e.ErrorMessage = fmt.Sprintf("execution exceeded max execution time of %s", limit)
+	e.OutputData = map[string]interface{}{"partial": true, "nodes": outputs}
+
+	var skipped []*NodeExecution
+	for _, n := range planned {
+		if finished[n.ID] {
+			continue
+		}
+		skipped = append(skipped, &NodeExecution{
+			ID:          uuid.New(),
+			ExecutionID: e.ID,
+			NodeID:      n.ID,
+			NodeType:    n.Type,
+			NodeName:    n.Name,
+			Status:      ExecutionStatusSkippedTimeout,
+			StartedAt:   *e.FinishedAt,
+			FinishedAt:  e.FinishedAt,
+		})
+	}
+	return skipped
+}
+
+// PartialOutput returns the node outputs kept by TimeOutPartially, or nil
+// when the execution did not time out
+func (e *Execution) PartialOutput() map[string]interface{} {
+	if e.Status != ExecutionStatusTimeout {
+		return nil
+	}
+	outputs, _ := e.OutputData["nodes"].(map[string]interface{})
+	return outputs
+}
@@ -0,0 +1,124 @@
+package execution
+
+import (
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// MaxViews bounds the saved views of a user
+	MaxViews = 50
+	// maxViewNameLength bounds the length of view names, in characters
+	maxViewNameLength = 100
+	// maxErrorTextLength bounds the error text a view matches
+	maxErrorTextLength = 200
+)
+
+// View is a named filter over the executions list saved by a user.
+// Subscribed views notify their user of the finished executions they
+// match.
+type View struct {
+	ID         uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	UserID     uuid.UUID  `json:"user_id" gorm:"type:uuid;not null;index"`
+	Name       string     `json:"name" gorm:"not null"`
+	Filter     ViewFilter `json:"filter" gorm:"serializer:json"`
+	Subscribed bool       `json:"subscribed"`
+	// LastMatchedAt is when a finished execution last matched the view,
+	// set for subscribed views only
+	LastMatchedAt *time.Time `json:"last_matched_at,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
+
+// TableName specifies the table name for GORM
+func (View) TableName() string {
+	return "execution_views"
+}
+
+// ViewFilter selects the executions of a view. Zero fields do not filter.
+type ViewFilter struct {
+	WorkflowID *uuid.UUID      `json:"workflow_id,omitempty"`
+	Status     ExecutionStatus `json:"status,omitempty"`
+	// ErrorText matches executions whose error message contains it,
+	// ignoring case
+	ErrorText string `json:"error_text,omitempty"`
+	// From and To bound the start time
+	From *time.Time `json:"from,omitempty"`
+	To   *time.Time `json:"to,omitempty"`
+	// Period selects the executions started within a duration before now,
+	// such as 24h, instead of a fixed range
+	Period string `json:"period,omitempty"`
+}
+
+// Validate checks the view's name and filter
+func (v *View) Validate() error {
+	v.Name = strings.TrimSpace(v.Name)
+	if v.Name == "" {
+		return ErrViewNameRequired
+	}
+	if utf8.RuneCountInString(v.Name) > maxViewNameLength {
+		return ErrViewNameTooLong
+	}
+	return v.Filter.Validate()
+}
+
+// Validate checks the filter's status, error text and time range
+func (f *ViewFilter) Validate() error {
+	if f.Status != "" && !f.Status.IsTerminal() && !f.Status.IsRunning() {
+		return ErrInvalidViewStatus
+	}
+	f.ErrorText = strings.TrimSpace(f.ErrorText)
+	if utf8.RuneCountInString(f.ErrorText) > maxErrorTextLength {
+		return ErrErrorTextTooLong
+	}
+	if f.From != nil && f.To != nil && !f.From.Before(*f.To) {
+		return ErrInvalidViewRange
+	}
+	if f.Period != "" {
+		period, err := time.ParseDuration(f.Period)
+		if err != nil || period <= 0 || f.From != nil || f.To != nil {
+			return ErrInvalidViewPeriod
+		}
+	}
+	return nil
+}
+
+// ListFilter returns the filter listing the view's executions at now
+func (f ViewFilter) ListFilter(now time.Time) ListFilter {
+	filter := ListFilter{
+		WorkflowID: f.WorkflowID,
+		Status:     f.Status,
+		ErrorText:  f.ErrorText,
+		From:       f.From,
+		To:         f.To,
+	}
+	if period, err := time.ParseDuration(f.Period); err == nil && f.Period != "" {
+		from := now.Add(-period)
+		filter.From = &from
+	}
+	return filter
+}
+
+// Matches reports whether an execution belongs to the view. Periods
+// always include executions finishing now.
+func (f ViewFilter) Matches(e *Execution) bool {
+	if f.WorkflowID != nil && *f.WorkflowID != e.WorkflowID {
+		return false
+	}
+	if f.Status != "" && f.Status != e.Status {
+		return false
+	}
+	if f.ErrorText != "" && !strings.Contains(strings.ToLower(e.ErrorMessage), strings.ToLower(f.ErrorText)) {
+		return false
+	}
+	if f.From != nil && e.StartedAt.Before(*f.From) {
+		return false
+	}
+	if f.To != nil && !e.StartedAt.Before(*f.To) {
+		return false
+	}
+	return true
+}
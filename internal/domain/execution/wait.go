@@ -0,0 +1,59 @@
+package execution
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jaydeep/go-n8n/internal/domain/node"
+)
+
+// WaitingExecution is an execution suspended at a node, with the state the
+// engine needs to carry on from there. It resumes on its own at ResumeAt,
+// when set, or through the resume endpoint when Webhook is set. Resuming
+// claims it by setting ResumedAt and the data it was resumed with.
+type WaitingExecution struct {
+	ExecutionID uuid.UUID `json:"execution_id" gorm:"type:uuid;primary_key"`
+	// NodeID is the node the execution waits at
+	NodeID     string                 `json:"node_id" gorm:"not null"`
+	ResumeAt   *time.Time             `json:"resume_at,omitempty"`
+	Webhook    bool                   `json:"webhook"`
+	State      WaitState              `json:"-" gorm:"serializer:json"`
+	ResumeData map[string]interface{} `json:"-" gorm:"serializer:json"`
+	ResumedAt  *time.Time             `json:"resumed_at,omitempty"`
+	CreatedAt  time.Time              `json:"created_at"`
+}
+
+// TableName specifies the table name for GORM
+func (WaitingExecution) TableName() string {
+	return "waiting_executions"
+}
+
+// WaitState is what an execution had done when it was suspended
+type WaitState struct {
+	// Outputs holds the items of each node that ran, by node ID and output
+	Outputs map[string][][]node.Item `json:"outputs"`
+	// ErrorOutputs holds the items of the nodes that failed and continued
+	// on their error output, by node ID
+	ErrorOutputs map[string][]node.Item `json:"error_outputs,omitempty"`
+	// Runs are the node runs so far, for the usage of the execution
+	Runs []node.NodeExecutionData `json:"runs"`
+	// Input holds the items of the node the execution waits at
+	Input []node.Item `json:"input"`
+}
+
+// Claimed reports whether the execution has been resumed
+func (w *WaitingExecution) Claimed() bool {
+	return w.ResumedAt != nil
+}
+
+// Items returns the items the node the execution waited at outputs: one
+// item of the data it was resumed with, or else the items it received
+func (w *WaitingExecution) Items() []node.Item {
+	if len(w.ResumeData) > 0 {
+		return []node.Item{{JSON: w.ResumeData}}
+	}
+	if w.State.Input == nil {
+		return []node.Item{}
+	}
+	return w.State.Input
+}
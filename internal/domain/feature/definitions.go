@@ -0,0 +1,32 @@
+package feature
+
+// Built-in flag keys
+const (
+	KeyTeams         = "teams"
+	KeyMarketplace   = "marketplace"
+	KeyCustomNodes   = "custom_nodes"
+	KeyWebhookTunnel = "webhook_tunnel"
+	KeyAPIAccess     = "api_access"
+	KeyOAuthLogin    = "oauth_login"
+	KeyTwoFactorAuth = "two_factor_auth"
+	KeyDiagnostics   = "diagnostics"
+)
+
+// descriptions describes the built-in flags. Other flags may be created
+// through the API for features rolled out without a release.
+var descriptions = map[string]string{
+	KeyTeams:         "Team workspaces and sharing",
+	KeyMarketplace:   "Community workflow marketplace",
+	KeyCustomNodes:   "Loading custom node packages",
+	KeyWebhookTunnel: "Public tunnel for webhooks during development",
+	KeyAPIAccess:     "Public API access with API keys",
+	KeyOAuthLogin:    "Sign in with OAuth providers",
+	KeyTwoFactorAuth: "Two-factor authentication",
+	KeyDiagnostics:   "Profiling and engine diagnostics for admins",
+}
+
+// Builtin reports whether key is a built-in flag, with its description
+func Builtin(key string) (string, bool) {
+	description, ok := descriptions[key]
+	return description, ok
+}
@@ -0,0 +1,134 @@
+package feature
+
+import (
+	"hash/fnv"
+	"regexp"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// keyPattern restricts flag keys to lower-case words
+var keyPattern = regexp.MustCompile(`^[a-z0-9]+([._-][a-z0-9]+)*$`)
+
+// Flag is a feature flag. Whether it applies to a user is decided by the
+// first of these that matches: a target naming the user, targets naming
+// one of the user's teams, and Enabled. A flag not enabled for everyone
+// applies to the Rollout percentage of users, chosen by a stable hash of
+// the flag key and user ID.
+type Flag struct {
+	Key         string     `json:"key" gorm:"primaryKey"`
+	Description string     `json:"description"`
+	Enabled     bool       `json:"enabled" gorm:"not null"`
+	Rollout     int        `json:"rollout" gorm:"not null;default:0"`
+	Targets     []Target   `json:"targets" gorm:"foreignKey:FlagKey;references:Key"`
+	UpdatedBy   *uuid.UUID `json:"updated_by,omitempty" gorm:"type:uuid"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// TableName specifies the table name for GORM
+func (Flag) TableName() string {
+	return "feature_flags"
+}
+
+// TargetType is the kind of subject a target names
+type TargetType string
+
+const (
+	TargetUser TargetType = "user"
+	// TargetTeam targets the members of a team, the unit of tenancy
+	TargetTeam TargetType = "team"
+)
+
+// Valid reports whether the target type is known
+func (t TargetType) Valid() bool {
+	return t == TargetUser || t == TargetTeam
+}
+
+// Target turns a flag on or off for one user or team
+type Target struct {
+	FlagKey   string     `json:"-" gorm:"primaryKey"`
+	Type      TargetType `json:"type" gorm:"primaryKey;type:varchar(20)"`
+	TargetID  uuid.UUID  `json:"target_id" gorm:"primaryKey;type:uuid"`
+	Enabled   bool       `json:"enabled" gorm:"not null"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// TableName specifies the table name for GORM
+func (Target) TableName() string {
+	return "feature_flag_targets"
+}
+
+// Subject is the user a flag is evaluated for
+type Subject struct {
+	UserID  uuid.UUID
+	TeamIDs []uuid.UUID
+}
+
+// ValidateKey checks that a flag key is well formed
+func ValidateKey(key string) error {
+	if len(key) > 100 || !keyPattern.MatchString(key) {
+		return ErrInvalidFlagKey
+	}
+	return nil
+}
+
+// Validate checks the flag's key and rollout percentage
+func (f *Flag) Validate() error {
+	if err := ValidateKey(f.Key); err != nil {
+		return err
+	}
+	if f.Rollout < 0 || f.Rollout > 100 {
+		return ErrInvalidRollout
+	}
+	return nil
+}
+
+// HasTeamTargets reports whether evaluating the flag needs the user's teams
+func (f *Flag) HasTeamTargets() bool {
+	for _, t := range f.Targets {
+		if t.Type == TargetTeam {
+			return true
+		}
+	}
+	return false
+}
+
+// Evaluate reports whether the flag is enabled for the subject. When the
+// user's teams disagree, a team enabling the flag wins.
+func (f *Flag) Evaluate(s Subject) bool {
+	teamTarget, teamEnabled := false, false
+	for _, t := range f.Targets {
+		switch t.Type {
+		case TargetUser:
+			if t.TargetID == s.UserID && s.UserID != uuid.Nil {
+				return t.Enabled
+			}
+		case TargetTeam:
+			for _, teamID := range s.TeamIDs {
+				if t.TargetID == teamID {
+					teamTarget = true
+					teamEnabled = teamEnabled || t.Enabled
+				}
+			}
+		}
+	}
+	if teamTarget {
+		return teamEnabled
+	}
+
+	if f.Enabled {
+		return true
+	}
+	return s.UserID != uuid.Nil && bucket(f.Key, s.UserID) < f.Rollout
+}
+
+// bucket places a user in one of 100 buckets per flag, so raising the
+// rollout only adds users
+func bucket(key string, userID uuid.UUID) int {
+	h := fnv.New32a()
+	h.Write([]byte(key))
+	h.Write(userID[:])
+	return int(h.Sum32() % 100)
+}
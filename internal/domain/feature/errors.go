@@ -0,0 +1,11 @@
+package feature
+
+import "errors"
+
+var (
+	ErrFlagNotFound      = errors.New("feature flag not found")
+	ErrInvalidFlagKey    = errors.New("invalid feature flag key")
+	ErrInvalidRollout    = errors.New("rollout must be between 0 and 100")
+	ErrInvalidTargetType = errors.New("invalid feature flag target type")
+	ErrTargetNotFound    = errors.New("feature flag target not found")
+)
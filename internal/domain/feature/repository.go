@@ -0,0 +1,20 @@
+package feature
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// Repository defines persistence operations for feature flags
+type Repository interface {
+	// List returns every stored flag with its targets
+	List(ctx context.Context) ([]*Flag, error)
+	// Save upserts a flag without touching its targets
+	Save(ctx context.Context, flag *Flag) error
+	// Delete removes a flag and its targets
+	Delete(ctx context.Context, key string) error
+	// SaveTarget upserts a target
+	SaveTarget(ctx context.Context, target *Target) error
+	DeleteTarget(ctx context.Context, key string, targetType TargetType, targetID uuid.UUID) error
+}
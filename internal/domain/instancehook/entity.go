@@ -0,0 +1,262 @@
+package instancehook
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Platform events instance webhooks subscribe to
+const (
+	EventUserCreated         = "user.created"
+	EventUserActivated       = "user.activated"
+	EventUserDeactivated     = "user.deactivated"
+	EventWorkflowActivated   = "workflow.activated"
+	EventWorkflowDeactivated = "workflow.deactivated"
+	EventSLABreached         = "workflow.sla_breached"
+	EventSLARecovered        = "workflow.sla_recovered"
+	EventExecutionFinished   = "execution.finished"
+	// EventExecutionFailed is sent for executions finishing in error,
+	// crashed or timeout
+	EventExecutionFailed   = "execution.failed"
+	EventCredentialCreated = "credential.created"
+	// EventCredentialUnhealthy is sent for credentials failing their test
+	// or nearing the expiry of a token or certificate
+	EventCredentialUnhealthy = "credential.unhealthy"
+	EventCredentialRecovered = "credential.recovered"
+	// EventPing is sent by test deliveries regardless of the subscription
+	EventPing = "ping"
+)
+
+const (
+	maxNameLength    = 100
+	minSecretLength  = 16
+	secretLength     = 32
+	secretHintLength = 10
+)
+
+// Events are the events a webhook may subscribe to
+var Events = []string{
+	EventUserCreated,
+	EventUserActivated,
+	EventUserDeactivated,
+	EventWorkflowActivated,
+	EventWorkflowDeactivated,
+	EventSLABreached,
+	EventSLARecovered,
+	EventExecutionFinished,
+	EventExecutionFailed,
+	EventCredentialCreated,
+	EventCredentialUnhealthy,
+	EventCredentialRecovered,
+}
+
+// Webhook POSTs platform events to an external system such as a SIEM or
+// an incident tool. Unlike workflow webhook triggers, which receive
+// calls, instance webhooks are outgoing and configured by admins.
+type Webhook struct {
+	ID     uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	Name   string    `json:"name" gorm:"not null"`
+	URL    string    `json:"url" gorm:"not null"`
+	Events []string  `json:"events" gorm:"type:text[];serializer:array"`
+	// WorkflowIDs limits workflow and execution events to these workflows;
+	// empty sends them for every workflow
+	WorkflowIDs []string `json:"workflow_ids" gorm:"type:text[];serializer:array"`
+	// Secret signs the deliveries; it is only shown when set
+	Secret     string     `json:"-" gorm:"not null"`
+	SecretHint string     `json:"secret_hint"`
+	IsActive   bool       `json:"is_active" gorm:"default:true"`
+	CreatedBy  *uuid.UUID `json:"created_by,omitempty" gorm:"type:uuid"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+// TableName specifies the table name for GORM
+func (Webhook) TableName() string {
+	return "instance_webhooks"
+}
+
+// Validate checks the webhook's fields
+func (w *Webhook) Validate() error {
+	if strings.TrimSpace(w.Name) == "" || len(w.Name) > maxNameLength {
+		return ErrInvalidName
+	}
+	u, err := url.Parse(w.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return ErrInvalidURL
+	}
+	if len(w.Events) == 0 {
+		return ErrEventsRequired
+	}
+	for _, event := range w.Events {
+		if !ValidEvent(event) {
+			return ErrUnknownEvent
+		}
+	}
+	for _, id := range w.WorkflowIDs {
+		if _, err := uuid.Parse(id); err != nil {
+			return ErrInvalidWorkflowID
+		}
+	}
+	return nil
+}
+
+// ValidEvent reports whether event can be subscribed to
+func ValidEvent(event string) bool {
+	for _, e := range Events {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+// RotateSecret replaces the signing secret and returns the new one
+func (w *Webhook) RotateSecret() (string, error) {
+	raw := make([]byte, secretLength)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+	w.setSecret("whsec_" + hex.EncodeToString(raw))
+	return w.Secret, nil
+}
+
+// SetSecret sets a signing secret chosen by the admin. The hint shows at
+// most a quarter of it.
+func (w *Webhook) SetSecret(secret string) error {
+	if len(secret) < minSecretLength {
+		return ErrSecretTooShort
+	}
+	w.setSecret(secret)
+	return nil
+}
+
+func (w *Webhook) setSecret(secret string) {
+	hint := len(secret) / 4
+	if hint > secretHintLength {
+		hint = secretHintLength
+	}
+	w.Secret = secret
+	w.SecretHint = secret[:hint]
+}
+
+// Matches reports whether the webhook receives event. workflowID is the
+// workflow the event is about, or uuid.Nil for other events.
+func (w *Webhook) Matches(event string, workflowID uuid.UUID) bool {
+	if !w.IsActive {
+		return false
+	}
+	subscribed := false
+	for _, e := range w.Events {
+		if e == event {
+			subscribed = true
+			break
+		}
+	}
+	if !subscribed {
+		return false
+	}
+	if len(w.WorkflowIDs) == 0 || workflowID == uuid.Nil {
+		return true
+	}
+	for _, id := range w.WorkflowIDs {
+		if id == workflowID.String() {
+			return true
+		}
+	}
+	return false
+}
+
+// DeliveryStatus represents the state of a delivery
+type DeliveryStatus string
+
+const (
+	DeliveryStatusPending   DeliveryStatus = "pending"
+	DeliveryStatusDelivered DeliveryStatus = "delivered"
+	DeliveryStatusFailed    DeliveryStatus = "failed"
+)
+
+// Delivery is one event sent to a webhook and the log of its attempts
+type Delivery struct {
+	ID        uuid.UUID      `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	WebhookID uuid.UUID      `json:"webhook_id" gorm:"type:uuid;not null"`
+	Event     string         `json:"event" gorm:"not null"`
+	Payload   interface{}    `json:"payload" gorm:"serializer:json"`
+	Status    DeliveryStatus `json:"status" gorm:"not null"`
+	Attempts  int            `json:"attempts" gorm:"default:0"`
+	// ResponseStatus is the HTTP status of the last attempt, 0 when no
+	// response was received
+	ResponseStatus int        `json:"response_status"`
+	DurationMs     int        `json:"duration_ms"`
+	LastError      string     `json:"last_error,omitempty"`
+	NextAttemptAt  time.Time  `json:"next_attempt_at"`
+	DeliveredAt    *time.Time `json:"delivered_at,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+// TableName specifies the table name for GORM
+func (Delivery) TableName() string {
+	return "instance_webhook_deliveries"
+}
+
+// NewDelivery creates a pending delivery of an event to a webhook
+func NewDelivery(webhookID uuid.UUID, event string, payload interface{}) *Delivery {
+	now := time.Now()
+	return &Delivery{
+		WebhookID:     webhookID,
+		Event:         event,
+		Payload:       payload,
+		Status:        DeliveryStatusPending,
+		NextAttemptAt: now,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+}
+
+// Delivered marks the delivery as delivered
+func (d *Delivery) Delivered(status int, duration time.Duration) {
+	now := time.Now()
+	d.Attempts++
+	d.Status = DeliveryStatusDelivered
+	d.ResponseStatus = status
+	d.DurationMs = int(duration.Milliseconds())
+	d.DeliveredAt = &now
+	d.LastError = ""
+}
+
+// Retry records a failed attempt and schedules the next one, or marks the
+// delivery failed once maxAttempts is reached
+func (d *Delivery) Retry(status int, duration time.Duration, err error, maxAttempts int, backoff, maxBackoff time.Duration) {
+	d.Attempts++
+	d.ResponseStatus = status
+	d.DurationMs = int(duration.Milliseconds())
+	d.LastError = err.Error()
+	if d.Attempts >= maxAttempts {
+		d.Status = DeliveryStatusFailed
+		return
+	}
+
+	delay := backoff << (d.Attempts - 1)
+	if delay > maxBackoff || delay <= 0 {
+		delay = maxBackoff
+	}
+	d.NextAttemptAt = time.Now().Add(delay)
+}
+
+// Cancel marks a pending delivery failed without sending it
+func (d *Delivery) Cancel(reason string) {
+	d.Status = DeliveryStatusFailed
+	d.LastError = reason
+}
+
+// Redeliver schedules a finished delivery to be sent again now
+func (d *Delivery) Redeliver() {
+	d.Status = DeliveryStatusPending
+	d.Attempts = 0
+	d.NextAttemptAt = time.Now()
+}
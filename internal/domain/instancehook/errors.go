@@ -0,0 +1,14 @@
+package instancehook
+
+import "errors"
+
+var (
+	ErrWebhookNotFound   = errors.New("instance webhook not found")
+	ErrDeliveryNotFound  = errors.New("instance webhook delivery not found")
+	ErrInvalidName       = errors.New("instance webhook name must be 1 to 100 characters")
+	ErrInvalidURL        = errors.New("instance webhook URL must be an absolute http or https URL")
+	ErrEventsRequired    = errors.New("at least one event is required")
+	ErrUnknownEvent      = errors.New("unknown instance webhook event")
+	ErrInvalidWorkflowID = errors.New("workflow_ids must be workflow IDs")
+	ErrSecretTooShort    = errors.New("instance webhook secret must be at least 16 characters")
+)
@@ -0,0 +1,41 @@
+package instancehook
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Repository defines persistence operations for instance webhooks
+type Repository interface {
+	Create(ctx context.Context, w *Webhook) error
+	Update(ctx context.Context, w *Webhook) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	FindByID(ctx context.Context, id uuid.UUID) (*Webhook, error)
+
+	// List returns every webhook, oldest first
+	List(ctx context.Context) ([]*Webhook, error)
+
+	// ListActive returns the active webhooks
+	ListActive(ctx context.Context) ([]*Webhook, error)
+}
+
+// DeliveryRepository defines persistence operations for webhook deliveries
+type DeliveryRepository interface {
+	Create(ctx context.Context, d *Delivery) error
+	Update(ctx context.Context, d *Delivery) error
+	FindByID(ctx context.Context, id uuid.UUID) (*Delivery, error)
+
+	// ListByWebhook returns up to limit deliveries of a webhook, newest
+	// first, optionally only those with status
+	ListByWebhook(ctx context.Context, webhookID uuid.UUID, status DeliveryStatus, limit int) ([]*Delivery, error)
+
+	// FindDue returns up to limit pending deliveries whose next attempt is
+	// due at now, oldest first
+	FindDue(ctx context.Context, now time.Time, limit int) ([]*Delivery, error)
+
+	// DeleteFinishedBefore removes the delivered and failed deliveries
+	// created before cutoff
+	DeleteFinishedBefore(ctx context.Context, cutoff time.Time) (int64, error)
+}
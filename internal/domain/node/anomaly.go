@@ -0,0 +1,17 @@
+package node
+
+// MetadataAnomaly is the metadata key holding the Anomaly of a node run
+// that took much longer than the node usually does
+const MetadataAnomaly = "anomaly"
+
+// Anomaly describes how a slow node run compares to the recent runs of the
+// same node in the same workflow
+type Anomaly struct {
+	DurationMs int64   `json:"duration_ms"`
+	MedianMs   float64 `json:"median_ms"`
+	MADMs      float64 `json:"mad_ms"`
+	// Score is the number of scaled median absolute deviations the run
+	// lies above the median
+	Score   float64 `json:"score"`
+	Samples int     `json:"samples"`
+}
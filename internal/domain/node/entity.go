@@ -4,7 +4,10 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"sync"
 	"time"
+
+	"github.com/google/uuid"
 )
 
 // NodeInterface defines the interface all nodes must implement
@@ -31,6 +34,25 @@ type NodeInterface interface {
 	GetDefaultParameters() map[string]interface{}
 }
 
+// SideEffectDeclarer is implemented by nodes that can tell whether a given
+// parameter set causes externally visible changes (writes, sends, deletes).
+// Dry-run executions use it to decide which nodes must not really run.
+type SideEffectDeclarer interface {
+	HasSideEffects(parameters map[string]interface{}) bool
+}
+
+// Looper is implemented by nodes that connections may loop back to, such
+// as Split In Batches. The engine runs the nodes after the loop output of
+// such a node again each time the node outputs items on it, then runs the
+// node again with the items the connections looping back bring it.
+type Looper interface {
+	// LoopOutput returns the index of the output leading into the loop
+	LoopOutput() int
+	// MaxIterations returns how many times the loop may run with the
+	// parameters; 0 leaves the limit to the engine
+	MaxIterations(parameters map[string]interface{}) int
+}
+
 // Category represents node category
 type Category string
 
@@ -49,6 +71,10 @@ type NodeInput struct {
 	Parameters  map[string]interface{} `json:"parameters"`
 	Credentials map[string]interface{} `json:"credentials"`
 	Context     *ExecutionContext      `json:"context"`
+	// ItemParameters returns the parameters with their expressions
+	// evaluated for the input item at index; Parameters holds them
+	// evaluated for the first item. Nil when no parameter has expressions.
+	ItemParameters func(index int) (map[string]interface{}, error) `json:"-"`
 }
 
 // NodeOutput represents output data from node execution
@@ -56,15 +82,21 @@ type NodeOutput struct {
 	Data     []Item                 `json:"data"`
 	Error    error                  `json:"error,omitempty"`
 	Metadata map[string]interface{} `json:"metadata,omitempty"`
+	// Output is the index of the output the items leave the node on, for
+	// nodes with more than one
+	Output int `json:"output,omitempty"`
 }
 
 // Item represents a single data item
 type Item struct {
 	JSON   map[string]interface{} `json:"json"`
 	Binary map[string]Binary      `json:"binary,omitempty"`
+	// PairedItem names the input items of the node the item was made from
+	PairedItem []PairedItem `json:"paired_item,omitempty"`
 }
 
-// Binary represents binary data
+// Binary represents binary data. Data streamed between nodes is kept in
+// the binary data store rather than in Data, and referred to by ID.
 type Binary struct {
 	Data      []byte `json:"data,omitempty"`
 	MimeType  string `json:"mime_type"`
@@ -74,6 +106,11 @@ type Binary struct {
 	ID        string `json:"id,omitempty"`
 }
 
+// Stored reports whether the data is kept in the binary data store
+func (b Binary) Stored() bool {
+	return b.ID != ""
+}
+
 // ExecutionContext provides context for node execution
 type ExecutionContext struct {
 	WorkflowID    string                 `json:"workflow_id"`
@@ -85,8 +122,19 @@ type ExecutionContext struct {
 	Variables     map[string]interface{} `json:"variables"`
 	Mode          string                 `json:"mode"`
 	Timezone      string                 `json:"timezone"`
+	// RetryCount counts the retries of a node retrying on fail, up to
+	// MaxRetries
 	RetryCount    int                    `json:"retry_count"`
 	MaxRetries    int                    `json:"max_retries"`
+	DryRun        bool                   `json:"dry_run"`
+	// CorrelationID ties the execution to the trigger that started it
+	CorrelationID string `json:"correlation_id,omitempty"`
+	// Secrets holds the decrypted workflow secrets by name, which
+	// expressions reference as $secrets.NAME. They are never serialized.
+	Secrets map[string]string `json:"-"`
+	// State is kept between the runs of the node in the execution, for
+	// nodes running more than once, such as in a loop
+	State map[string]interface{} `json:"-"`
 }
 
 // NodeSchema defines the structure and properties of a node
@@ -189,22 +237,38 @@ type NodeRegistration struct {
 	Type        string
 	Category    Category
 	Constructor func() NodeInterface
+	// TeamID is the team whose workflows alone may use the node type, nil
+	// for node types of every team
+	TeamID *uuid.UUID
 }
 
-// NodeRegistry manages all registered nodes
+// NodeRegistry manages all registered nodes. It is safe for concurrent use
+// so node types can be reloaded while the server runs.
 type NodeRegistry struct {
+	mu    sync.RWMutex
 	nodes map[string]NodeRegistration
+	icons map[string]Icon
+}
+
+// Icon is an image asset (SVG or PNG) of a node type
+type Icon struct {
+	Data        []byte
+	ContentType string
 }
 
 // NewNodeRegistry creates a new node registry
 func NewNodeRegistry() *NodeRegistry {
 	return &NodeRegistry{
 		nodes: make(map[string]NodeRegistration),
+		icons: make(map[string]Icon),
 	}
 }
 
 // Register registers a new node type
 func (r *NodeRegistry) Register(nodeType string, category Category, constructor func() NodeInterface) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
 	if _, exists := r.nodes[nodeType]; exists {
 		return errors.New("node type already registered: " + nodeType)
 	}
@@ -218,8 +282,92 @@ func (r *NodeRegistry) Register(nodeType string, category Category, constructor
 	return nil
 }
 
+// Replace registers a node type, overwriting an existing registration
+func (r *NodeRegistry) Replace(nodeType string, category Category, constructor func() NodeInterface) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.nodes[nodeType] = NodeRegistration{
+		Type:        nodeType,
+		Category:    category,
+		Constructor: constructor,
+	}
+}
+
+// ReplaceTeam registers a node type only the workflows of a team may use,
+// overwriting an existing registration
+func (r *NodeRegistry) ReplaceTeam(teamID uuid.UUID, nodeType string, category Category, constructor func() NodeInterface) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.nodes[nodeType] = NodeRegistration{
+		Type:        nodeType,
+		Category:    category,
+		Constructor: constructor,
+		TeamID:      &teamID,
+	}
+}
+
+// Team returns the team a node type belongs to, nil for node types of
+// every team and unknown node types
+func (r *NodeRegistry) Team(nodeType string) *uuid.UUID {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.nodes[nodeType].TeamID
+}
+
+// Unregister removes a node type and its icon
+func (r *NodeRegistry) Unregister(nodeType string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.nodes, nodeType)
+	delete(r.icons, nodeType)
+}
+
+// RegisterIcon sets the icon served for a node type
+func (r *NodeRegistry) RegisterIcon(nodeType string, icon Icon) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.icons[nodeType] = icon
+}
+
+// Icon returns the icon of a node type
+func (r *NodeRegistry) Icon(nodeType string) (Icon, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	icon, exists := r.icons[nodeType]
+	return icon, exists
+}
+
+// Has reports whether a node type is registered
+func (r *NodeRegistry) Has(nodeType string) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	_, exists := r.nodes[nodeType]
+	return exists
+}
+
+// Loops reports whether connections may loop back to nodes of a type, the
+// nodes of the type implementing Looper
+func (r *NodeRegistry) Loops(nodeType string) bool {
+	constructor, err := r.Get(nodeType)
+	if err != nil {
+		return false
+	}
+	_, ok := constructor().(Looper)
+	return ok
+}
+
 // Get retrieves a node constructor by type
 func (r *NodeRegistry) Get(nodeType string) (func() NodeInterface, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
 	registration, exists := r.nodes[nodeType]
 	if !exists {
 		return nil, errors.New("node type not found: " + nodeType)
@@ -229,6 +377,9 @@ func (r *NodeRegistry) Get(nodeType string) (func() NodeInterface, error) {
 
 // List returns all registered node types
 func (r *NodeRegistry) List() []NodeRegistration {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
 	list := make([]NodeRegistration, 0, len(r.nodes))
 	for _, reg := range r.nodes {
 		list = append(list, reg)
@@ -238,6 +389,9 @@ func (r *NodeRegistry) List() []NodeRegistration {
 
 // ListByCategory returns nodes filtered by category
 func (r *NodeRegistry) ListByCategory(category Category) []NodeRegistration {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
 	var list []NodeRegistration
 	for _, reg := range r.nodes {
 		if reg.Category == category {
@@ -310,13 +464,25 @@ func (i *Item) UnmarshalJSON(data []byte) error {
 
 // NodeExecutionData holds data about node execution
 type NodeExecutionData struct {
-	NodeID          string        `json:"node_id"`
-	NodeType        string        `json:"node_type"`
-	StartTime       time.Time     `json:"start_time"`
-	EndTime         time.Time     `json:"end_time"`
-	ExecutionTimeMs int64         `json:"execution_time_ms"`
-	Status          string        `json:"status"`
-	Error           string        `json:"error,omitempty"`
-	InputItems      int           `json:"input_items"`
-	OutputItems     int           `json:"output_items"`
+	NodeID          string                 `json:"node_id"`
+	NodeType        string                 `json:"node_type"`
+	StartTime       time.Time              `json:"start_time"`
+	EndTime         time.Time              `json:"end_time"`
+	ExecutionTimeMs int64                  `json:"execution_time_ms"`
+	Status          string                 `json:"status"`
+	Error           string                 `json:"error,omitempty"`
+	InputItems      int                    `json:"input_items"`
+	OutputItems     int                    `json:"output_items"`
+	// Metadata holds the output metadata of the node, including the
+	// resources it used under MetadataUsage and, for runs much slower than
+	// usual, an Anomaly under MetadataAnomaly
+	Metadata        map[string]interface{} `json:"metadata,omitempty"`
+	// TimeoutMs is the deadline the node ran under, zero for none, and
+	// TimedOut whether the run was cut off at it or at the deadline of
+	// the execution
+	TimeoutMs int64 `json:"timeout_ms,omitempty"`
+	TimedOut  bool  `json:"timed_out,omitempty"`
+	// RunIndex counts the runs of the node before this one in the
+	// execution, as nodes in a loop run once per iteration
+	RunIndex int `json:"run_index,omitempty"`
 }
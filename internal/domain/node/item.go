@@ -0,0 +1,110 @@
+package node
+
+// Items are passed between nodes without copying: the output items of a
+// node are the input items of the next, and several branches may share
+// them. Nodes must therefore treat their input items as read-only and
+// derive changed items through Edit, which copies an item's maps only when
+// they are first written.
+
+// Get returns a JSON field of the item
+func (i Item) Get(key string) (interface{}, bool) {
+	v, ok := i.JSON[key]
+	return v, ok
+}
+
+// Edit returns a copy-on-write editor for the item
+func (i Item) Edit() *ItemEditor {
+	return &ItemEditor{item: i}
+}
+
+// ItemEditor changes an item without touching the maps it shares with
+// other items. The top-level JSON and binary maps are copied on their first
+// write, so any number of writes costs one copy and an item left unchanged
+// costs none. Nested values are still shared and must be replaced rather
+// than modified.
+type ItemEditor struct {
+	item       Item
+	ownsJSON   bool
+	ownsBinary bool
+}
+
+// Set sets a JSON field
+func (e *ItemEditor) Set(key string, value interface{}) *ItemEditor {
+	e.ownJSON()
+	e.item.JSON[key] = value
+	return e
+}
+
+// Merge sets the given JSON fields
+func (e *ItemEditor) Merge(fields map[string]interface{}) *ItemEditor {
+	if len(fields) == 0 {
+		return e
+	}
+	e.ownJSON()
+	for k, v := range fields {
+		e.item.JSON[k] = v
+	}
+	return e
+}
+
+// Delete removes JSON fields
+func (e *ItemEditor) Delete(keys ...string) *ItemEditor {
+	for _, key := range keys {
+		if _, ok := e.item.JSON[key]; !ok {
+			continue
+		}
+		e.ownJSON()
+		delete(e.item.JSON, key)
+	}
+	return e
+}
+
+// SetBinary attaches binary data under name
+func (e *ItemEditor) SetBinary(name string, data Binary) *ItemEditor {
+	e.ownBinary()
+	e.item.Binary[name] = data
+	return e
+}
+
+// DeleteBinary removes binary data
+func (e *ItemEditor) DeleteBinary(names ...string) *ItemEditor {
+	for _, name := range names {
+		if _, ok := e.item.Binary[name]; !ok {
+			continue
+		}
+		e.ownBinary()
+		delete(e.item.Binary, name)
+	}
+	return e
+}
+
+// Item returns the edited item. Further edits copy the maps again, so the
+// returned item is never changed afterwards.
+func (e *ItemEditor) Item() Item {
+	e.ownsJSON, e.ownsBinary = false, false
+	return e.item
+}
+
+func (e *ItemEditor) ownJSON() {
+	if e.ownsJSON {
+		return
+	}
+	json := make(map[string]interface{}, len(e.item.JSON)+1)
+	for k, v := range e.item.JSON {
+		json[k] = v
+	}
+	e.item.JSON = json
+	e.ownsJSON = true
+}
+
+func (e *ItemEditor) ownBinary() {
+	if e.ownsBinary {
+		return
+	}
+	binary := make(map[string]Binary, len(e.item.Binary)+1)
+	for k, v := range e.item.Binary {
+		binary[k] = v
+	}
+	e.item.Binary = binary
+	e.ownsBinary = true
+}
@@ -0,0 +1,114 @@
+package node
+
+import "reflect"
+
+// PairedItem identifies an input item of a node: the index of the item
+// among those the node received on one of its inputs
+type PairedItem struct {
+	Item  int `json:"item"`
+	Input int `json:"input,omitempty"`
+}
+
+// PairWith names the input items the edited item was made from, replacing
+// the pairing it had
+func (e *ItemEditor) PairWith(items ...int) *ItemEditor {
+	paired := make([]PairedItem, len(items))
+	for i, item := range items {
+		paired[i] = PairedItem{Item: item}
+	}
+	e.item.PairedItem = paired
+	return e
+}
+
+// PairItems pairs the output items of a node run with the input items they
+// came from, returning the output items with their pairing. An output item
+// passed on or edited from an input item is paired with it, an item the
+// node paired itself keeps its pairing, and an item sharing its JSON with
+// an input item is paired with that item. Other items are paired by
+// position when the run output as many items as it received, with the only
+// input item when it received one, and with all input items when it output
+// one. The output items are not changed; items needing a pairing are
+// copied.
+func PairItems(input, output []Item) []Item {
+	var paired []Item
+	for i, item := range output {
+		pairing, ok := pairingOf(item, input)
+		if !ok {
+			pairing = positionalPairing(i, len(input), len(output))
+		}
+		if samePairing(item.PairedItem, pairing) {
+			continue
+		}
+		if paired == nil {
+			paired = make([]Item, len(output))
+			copy(paired, output)
+		}
+		paired[i].PairedItem = pairing
+	}
+	if paired == nil {
+		return output
+	}
+	return paired
+}
+
+// pairingOf returns the pairing of an output item evident from the item
+// itself, if any
+func pairingOf(item Item, input []Item) ([]PairedItem, bool) {
+	// An item passed on or edited from an input item carries its pairing
+	for k, in := range input {
+		if sharesPairing(item.PairedItem, in.PairedItem) {
+			return []PairedItem{{Item: k}}, true
+		}
+	}
+	if len(item.PairedItem) > 0 {
+		return item.PairedItem, true
+	}
+	for k, in := range input {
+		if sharesMap(item.JSON, in.JSON) {
+			return []PairedItem{{Item: k}}, true
+		}
+	}
+	return nil, false
+}
+
+// positionalPairing pairs output item i by the counts of items alone
+func positionalPairing(i, inputs, outputs int) []PairedItem {
+	switch {
+	case inputs == 0:
+		return nil
+	case inputs == outputs:
+		return []PairedItem{{Item: i}}
+	case inputs == 1:
+		return []PairedItem{{Item: 0}}
+	case outputs == 1:
+		all := make([]PairedItem, inputs)
+		for k := range all {
+			all[k] = PairedItem{Item: k}
+		}
+		return all
+	}
+	return nil
+}
+
+// sharesMap reports whether two JSON maps are the same map
+func sharesMap(a, b map[string]interface{}) bool {
+	return a != nil && b != nil && reflect.ValueOf(a).Pointer() == reflect.ValueOf(b).Pointer()
+}
+
+// sharesPairing reports whether two pairings are the same slice, as when
+// an item is edited from another
+func sharesPairing(a, b []PairedItem) bool {
+	return len(a) > 0 && len(b) > 0 && &a[0] == &b[0]
+}
+
+func samePairing(a, b []PairedItem) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,63 @@
+package node
+
+import "encoding/json"
+
+// MetadataUsage is the metadata key holding the Usage of a node run
+const MetadataUsage = "usage"
+
+// Usage counts the resources a node run consumed, for cost attribution.
+// Nodes report the calls they make with NodeOutput.AddUsage; the engine
+// fills in CPU time and items processed.
+type Usage struct {
+	CPUTimeMs       int64 `json:"cpu_time_ms"`
+	ItemsProcessed  int64 `json:"items_processed"`
+	HTTPCalls       int64 `json:"http_calls"`
+	LLMInputTokens  int64 `json:"llm_input_tokens"`
+	LLMOutputTokens int64 `json:"llm_output_tokens"`
+}
+
+// Add accumulates other into u
+func (u *Usage) Add(other Usage) {
+	u.CPUTimeMs += other.CPUTimeMs
+	u.ItemsProcessed += other.ItemsProcessed
+	u.HTTPCalls += other.HTTPCalls
+	u.LLMInputTokens += other.LLMInputTokens
+	u.LLMOutputTokens += other.LLMOutputTokens
+}
+
+// LLMTokens is the total of input and output tokens
+func (u Usage) LLMTokens() int64 {
+	return u.LLMInputTokens + u.LLMOutputTokens
+}
+
+// AddUsage adds to the usage reported in the output metadata. Nodes call
+// it for every HTTP request they make and every LLM completion they use.
+func (o *NodeOutput) AddUsage(usage Usage) {
+	if o.Metadata == nil {
+		o.Metadata = make(map[string]interface{})
+	}
+	current := UsageFromMetadata(o.Metadata)
+	current.Add(usage)
+	o.Metadata[MetadataUsage] = current
+}
+
+// UsageFromMetadata reads the usage stored under MetadataUsage. It accepts
+// both a Usage value and its decoded JSON form, as found in persisted
+// execution data.
+func UsageFromMetadata(metadata map[string]interface{}) Usage {
+	var usage Usage
+	switch v := metadata[MetadataUsage].(type) {
+	case Usage:
+		usage = v
+	case *Usage:
+		if v != nil {
+			usage = *v
+		}
+	case map[string]interface{}:
+		data, err := json.Marshal(v)
+		if err == nil {
+			json.Unmarshal(data, &usage)
+		}
+	}
+	return usage
+}
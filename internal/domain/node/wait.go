@@ -0,0 +1,19 @@
+package node
+
+import "time"
+
+// WaitError is returned by a node, as its error, to suspend the execution
+// at the node. The engine keeps the state of the execution and carries on
+// with the nodes after it once the execution is resumed: at Until, when
+// set, or earlier through the resume endpoint when Webhook is set.
+type WaitError struct {
+	Until   time.Time
+	Webhook bool
+}
+
+func (w *WaitError) Error() string {
+	if w.Until.IsZero() {
+		return "execution waits to be resumed"
+	}
+	return "execution waits until " + w.Until.UTC().Format(time.RFC3339)
+}
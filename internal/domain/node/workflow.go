@@ -0,0 +1,26 @@
+package node
+
+import "context"
+
+// WorkflowRunner runs other workflows as sub-workflows of the execution of
+// the node calling it
+type WorkflowRunner interface {
+	// RunWorkflow starts an execution of the workflow with items as its
+	// input and returns its ID. With wait set the execution runs to its end
+	// first, and the items it output are returned.
+	RunWorkflow(ctx context.Context, workflowID string, items []Item, wait bool) (string, []Item, error)
+}
+
+type workflowRunnerKey struct{}
+
+// WithWorkflowRunner returns a context carrying the runner of sub-workflows
+func WithWorkflowRunner(ctx context.Context, runner WorkflowRunner) context.Context {
+	return context.WithValue(ctx, workflowRunnerKey{}, runner)
+}
+
+// WorkflowRunnerFrom returns the runner of sub-workflows carried by ctx, or
+// nil outside an execution
+func WorkflowRunnerFrom(ctx context.Context) WorkflowRunner {
+	runner, _ := ctx.Value(workflowRunnerKey{}).(WorkflowRunner)
+	return runner
+}
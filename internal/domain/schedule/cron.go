@@ -0,0 +1,91 @@
+package schedule
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+// Evaluator computes the fire times of a schedule
+type Evaluator interface {
+	// Next returns the first fire time after t, or the zero time if the
+	// schedule never fires again
+	Next(t time.Time) time.Time
+}
+
+// parser accepts standard five-field expressions, an optional leading
+// seconds field and descriptors such as @hourly or @every 5m
+var parser = cron.NewParser(
+	cron.SecondOptional | cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow | cron.Descriptor,
+)
+
+// neverFiresHorizon bounds the search for the first fire time so
+// expressions like "0 0 30 2 *" are rejected instead of accepted silently
+const neverFiresHorizon = 5 * 366 * 24 * time.Hour
+
+// LoadLocation resolves a schedule timezone, defaulting to UTC
+func LoadLocation(timezone string) (*time.Location, error) {
+	if timezone == "" {
+		return time.UTC, nil
+	}
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidTimezone, timezone)
+	}
+	return loc, nil
+}
+
+// ParseCron parses a cron expression evaluated in the given timezone
+func ParseCron(expr, timezone string) (Evaluator, error) {
+	expr = strings.TrimSpace(expr)
+	if expr == "" {
+		return nil, ErrExpressionRequired
+	}
+	if strings.HasPrefix(expr, "CRON_TZ=") || strings.HasPrefix(expr, "TZ=") {
+		return nil, ErrTimezoneInline
+	}
+
+	loc, err := LoadLocation(timezone)
+	if err != nil {
+		return nil, err
+	}
+
+	sched, err := parser.Parse(expr)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidExpression, err)
+	}
+	// Parse assigns the local timezone to expressions without CRON_TZ
+	if spec, ok := sched.(*cron.SpecSchedule); ok {
+		spec.Location = loc
+	}
+
+	if err := checkFires(sched, loc); err != nil {
+		return nil, err
+	}
+	return sched, nil
+}
+
+// checkFires rejects schedules without a fire time in the next few years
+func checkFires(e Evaluator, loc *time.Location) error {
+	now := time.Now().In(loc)
+	if next := e.Next(now); next.IsZero() || next.Sub(now) > neverFiresHorizon {
+		return ErrNeverFires
+	}
+	return nil
+}
+
+// Preview returns the next n fire times after from, in from's timezone
+func Preview(e Evaluator, from time.Time, n int) []time.Time {
+	times := make([]time.Time, 0, n)
+	t := from
+	for len(times) < n {
+		t = e.Next(t)
+		if t.IsZero() {
+			break
+		}
+		times = append(times, t.In(from.Location()))
+	}
+	return times
+}
@@ -0,0 +1,41 @@
+package schedule
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Schedule triggers a workflow on a recurring basis
+type Schedule struct {
+	ID             uuid.UUID   `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	WorkflowID     uuid.UUID   `json:"workflow_id" gorm:"type:uuid;not null"`
+	CronExpression string      `json:"cron_expression,omitempty"`
+	Recurrence     *Recurrence `json:"recurrence,omitempty" gorm:"serializer:json"`
+	Timezone       string      `json:"timezone" gorm:"default:'UTC'"`
+	IsActive       bool        `json:"is_active" gorm:"default:true"`
+	// JitterSeconds delays each run by a random amount up to this window
+	JitterSeconds int        `json:"jitter_seconds" gorm:"default:0"`
+	LastRunAt     *time.Time `json:"last_run_at,omitempty"`
+	// NextRunAt is the dispatch time of the next run, including jitter
+	// and spread
+	NextRunAt *time.Time `json:"next_run_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// Evaluator compiles the schedule. holidays are the dates of the holiday
+// calendar referenced by the recurrence, if any.
+func (s *Schedule) Evaluator(holidays []string) (Evaluator, error) {
+	if s.Recurrence != nil {
+		return Compile(*s.Recurrence, s.Timezone, holidays)
+	}
+	if s.CronExpression == "" {
+		return nil, ErrScheduleRequired
+	}
+	return ParseCron(s.CronExpression, s.Timezone)
+}
+
+// TableName specifies the table name for GORM
+func (Schedule) TableName() string {
+	return "scheduled_workflows"
+}
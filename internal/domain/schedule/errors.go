@@ -0,0 +1,19 @@
+package schedule
+
+import "errors"
+
+var (
+	ErrScheduleNotFound            = errors.New("schedule not found")
+	ErrWorkflowAccessDenied        = errors.New("workflow access denied")
+	ErrInvalidJitter               = errors.New("invalid jitter")
+	ErrExpressionRequired          = errors.New("cron expression is required")
+	ErrInvalidExpression           = errors.New("invalid cron expression")
+	ErrInvalidTimezone             = errors.New("invalid timezone")
+	ErrTimezoneInline              = errors.New("set the timezone separately instead of using CRON_TZ or TZ in the expression")
+	ErrNeverFires                  = errors.New("schedule never fires")
+	ErrInvalidRecurrence           = errors.New("invalid recurrence")
+	ErrScheduleRequired            = errors.New("a cron expression or recurrence is required")
+	ErrHolidayCalendarNotFound     = errors.New("holiday calendar not found")
+	ErrTeamAccessDenied            = errors.New("team access denied")
+	ErrHolidayCalendarNameRequired = errors.New("holiday calendar name is required")
+)
@@ -0,0 +1,47 @@
+package schedule
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// HolidayCalendar is a team's list of dates on which business-day
+// schedules do not fire
+type HolidayCalendar struct {
+	ID        uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	TeamID    uuid.UUID `json:"team_id" gorm:"type:uuid;not null"`
+	Name      string    `json:"name" gorm:"not null"`
+	Dates     []string  `json:"dates" gorm:"serializer:json"`
+	CreatedBy uuid.UUID `json:"created_by" gorm:"type:uuid;not null"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TableName specifies the table name for GORM
+func (HolidayCalendar) TableName() string {
+	return "holiday_calendars"
+}
+
+// Validate checks the calendar and sorts and deduplicates its dates
+func (c *HolidayCalendar) Validate() error {
+	if c.Name == "" {
+		return ErrHolidayCalendarNameRequired
+	}
+	seen := make(map[string]bool, len(c.Dates))
+	dates := make([]string, 0, len(c.Dates))
+	for _, date := range c.Dates {
+		if _, err := time.Parse(dateLayout, date); err != nil {
+			return fmt.Errorf("%w: date %q must be YYYY-MM-DD", ErrInvalidRecurrence, date)
+		}
+		if !seen[date] {
+			seen[date] = true
+			dates = append(dates, date)
+		}
+	}
+	sort.Strings(dates)
+	c.Dates = dates
+	return nil
+}
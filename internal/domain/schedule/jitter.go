@@ -0,0 +1,44 @@
+package schedule
+
+import (
+	"hash/fnv"
+	"math/rand"
+	"time"
+)
+
+// DispatchOffset returns how long after a nominal fire time the schedule
+// is dispatched. Every schedule gets a stable offset within spread, so
+// schedules firing at the same minute are dispersed across the window,
+// plus a random jitter of up to JitterSeconds. The offset is capped at
+// half the gap to the following fire time so runs keep their order.
+func (s *Schedule) DispatchOffset(spread, gap time.Duration) time.Duration {
+	var offset time.Duration
+	if spread > 0 {
+		h := fnv.New64a()
+		h.Write(s.ID[:])
+		offset += time.Duration(h.Sum64() % uint64(spread))
+	}
+	if s.JitterSeconds > 0 {
+		offset += time.Duration(rand.Int63n(int64(s.JitterSeconds) * int64(time.Second)))
+	}
+	if gap > 0 && offset > gap/2 {
+		offset = gap / 2
+	}
+	return offset.Truncate(time.Millisecond)
+}
+
+// Advance sets NextRunAt to the first fire time after t, offset by
+// DispatchOffset. NextRunAt is cleared when the schedule never fires again.
+func (s *Schedule) Advance(e Evaluator, t time.Time, spread time.Duration) {
+	nominal := e.Next(t)
+	if nominal.IsZero() {
+		s.NextRunAt = nil
+		return
+	}
+	var gap time.Duration
+	if following := e.Next(nominal); !following.IsZero() {
+		gap = following.Sub(nominal)
+	}
+	next := nominal.Add(s.DispatchOffset(spread, gap))
+	s.NextRunAt = &next
+}
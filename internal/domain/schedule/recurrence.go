@@ -0,0 +1,245 @@
+package schedule
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// dateLayout is the format of holiday and skip dates
+const dateLayout = "2006-01-02"
+
+// Recurrence describes a calendar-style schedule. Days are selected by
+// Weekdays, MonthDays and NthWeekdays (a day matching any of them fires;
+// with none set every day does) and then filtered by BusinessDaysOnly,
+// the holiday calendar and SkipDates. When Cron is set its fire times are
+// filtered the same way instead of using Times.
+type Recurrence struct {
+	// Cron is an optional base expression
+	Cron string `json:"cron,omitempty"`
+	// Times of day in 24-hour HH:MM; required without Cron
+	Times []string `json:"times,omitempty"`
+	// Weekdays selects days of the week: mon, tue, wed, thu, fri, sat, sun
+	Weekdays []string `json:"weekdays,omitempty"`
+	// MonthDays selects days of the month; -1 is the last day
+	MonthDays []int `json:"month_days,omitempty"`
+	// NthWeekdays selects e.g. the second Tuesday or last Friday of a month
+	NthWeekdays []NthWeekday `json:"nth_weekdays,omitempty"`
+	// BusinessDaysOnly skips weekends and holidays
+	BusinessDaysOnly bool `json:"business_days_only,omitempty"`
+	// HolidayCalendarID is a team holiday calendar whose dates are skipped
+	HolidayCalendarID *uuid.UUID `json:"holiday_calendar_id,omitempty"`
+	// SkipDates are additional dates (YYYY-MM-DD) to skip
+	SkipDates []string `json:"skip_dates,omitempty"`
+}
+
+// NthWeekday is the Nth occurrence of a weekday in a month
+type NthWeekday struct {
+	// N is 1 to 5, or -1 for the last occurrence
+	N       int    `json:"n"`
+	Weekday string `json:"weekday"`
+}
+
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday,
+	"mon": time.Monday,
+	"tue": time.Tuesday,
+	"wed": time.Wednesday,
+	"thu": time.Thursday,
+	"fri": time.Friday,
+	"sat": time.Saturday,
+}
+
+func parseWeekday(s string) (time.Weekday, error) {
+	key := strings.ToLower(s)
+	if len(key) > 3 {
+		key = key[:3]
+	}
+	wd, ok := weekdays[key]
+	if !ok {
+		return 0, fmt.Errorf("%w: unknown weekday %q", ErrInvalidRecurrence, s)
+	}
+	return wd, nil
+}
+
+// clock is a time of day
+type clock struct {
+	hour, minute int
+}
+
+// daySelector decides which days a recurrence fires on
+type daySelector struct {
+	weekdays    map[time.Weekday]bool
+	monthDays   map[int]bool
+	nthWeekdays []nthWeekday
+	businessDay bool
+	skip        map[string]bool
+}
+
+type nthWeekday struct {
+	n       int
+	weekday time.Weekday
+}
+
+// matches reports whether the schedule fires on day
+func (s *daySelector) matches(day time.Time) bool {
+	if s.skip[day.Format(dateLayout)] {
+		return false
+	}
+	if s.businessDay && (day.Weekday() == time.Saturday || day.Weekday() == time.Sunday) {
+		return false
+	}
+	if len(s.weekdays) == 0 && len(s.monthDays) == 0 && len(s.nthWeekdays) == 0 {
+		return true
+	}
+	if s.weekdays[day.Weekday()] {
+		return true
+	}
+	lastDay := time.Date(day.Year(), day.Month()+1, 0, 0, 0, 0, 0, day.Location()).Day()
+	if s.monthDays[day.Day()] || (s.monthDays[-1] && day.Day() == lastDay) {
+		return true
+	}
+	for _, nth := range s.nthWeekdays {
+		if day.Weekday() != nth.weekday {
+			continue
+		}
+		if nth.n == -1 && day.Day()+7 > lastDay {
+			return true
+		}
+		if nth.n > 0 && (day.Day()-1)/7+1 == nth.n {
+			return true
+		}
+	}
+	return false
+}
+
+// calendarSchedule fires at fixed times of day on the selected days
+type calendarSchedule struct {
+	loc   *time.Location
+	times []clock
+	days  *daySelector
+}
+
+// maxSearchDays bounds the search for the next matching day
+const maxSearchDays = 5 * 366
+
+// Next returns the first fire time after t. Times that do not exist on
+// a day because of a DST change are moved forward by time.Date.
+func (s *calendarSchedule) Next(t time.Time) time.Time {
+	t = t.In(s.loc)
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, s.loc)
+	for i := 0; i < maxSearchDays; i++ {
+		d := day.AddDate(0, 0, i)
+		if !s.days.matches(d) {
+			continue
+		}
+		for _, c := range s.times {
+			candidate := time.Date(d.Year(), d.Month(), d.Day(), c.hour, c.minute, 0, 0, s.loc)
+			if candidate.After(t) {
+				return candidate
+			}
+		}
+	}
+	return time.Time{}
+}
+
+// filteredSchedule keeps the fire times of a base schedule that fall on
+// selected days
+type filteredSchedule struct {
+	base Evaluator
+	loc  *time.Location
+	days *daySelector
+}
+
+// Next returns the first fire time of the base schedule after t that
+// falls on a selected day
+func (s *filteredSchedule) Next(t time.Time) time.Time {
+	limit := t.Add(neverFiresHorizon)
+	for next := s.base.Next(t); !next.IsZero() && next.Before(limit); next = s.base.Next(next) {
+		local := next.In(s.loc)
+		if s.days.matches(time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, s.loc)) {
+			return next
+		}
+	}
+	return time.Time{}
+}
+
+// Compile turns a recurrence into an evaluator in the given timezone.
+// holidays are the dates (YYYY-MM-DD) of the referenced holiday calendar.
+func Compile(r Recurrence, timezone string, holidays []string) (Evaluator, error) {
+	loc, err := LoadLocation(timezone)
+	if err != nil {
+		return nil, err
+	}
+
+	days := &daySelector{
+		weekdays:    make(map[time.Weekday]bool),
+		monthDays:   make(map[int]bool),
+		businessDay: r.BusinessDaysOnly,
+		skip:        make(map[string]bool),
+	}
+	for _, s := range r.Weekdays {
+		wd, err := parseWeekday(s)
+		if err != nil {
+			return nil, err
+		}
+		days.weekdays[wd] = true
+	}
+	for _, d := range r.MonthDays {
+		if d != -1 && (d < 1 || d > 31) {
+			return nil, fmt.Errorf("%w: month day %d must be 1 to 31 or -1", ErrInvalidRecurrence, d)
+		}
+		days.monthDays[d] = true
+	}
+	for _, nth := range r.NthWeekdays {
+		if nth.N != -1 && (nth.N < 1 || nth.N > 5) {
+			return nil, fmt.Errorf("%w: n must be 1 to 5 or -1", ErrInvalidRecurrence)
+		}
+		wd, err := parseWeekday(nth.Weekday)
+		if err != nil {
+			return nil, err
+		}
+		days.nthWeekdays = append(days.nthWeekdays, nthWeekday{n: nth.N, weekday: wd})
+	}
+	for _, list := range [][]string{r.SkipDates, holidays} {
+		for _, date := range list {
+			if _, err := time.Parse(dateLayout, date); err != nil {
+				return nil, fmt.Errorf("%w: date %q must be YYYY-MM-DD", ErrInvalidRecurrence, date)
+			}
+			days.skip[date] = true
+		}
+	}
+
+	var evaluator Evaluator
+	if r.Cron != "" {
+		base, err := ParseCron(r.Cron, timezone)
+		if err != nil {
+			return nil, err
+		}
+		evaluator = &filteredSchedule{base: base, loc: loc, days: days}
+	} else {
+		if len(r.Times) == 0 {
+			return nil, fmt.Errorf("%w: at least one time of day is required", ErrInvalidRecurrence)
+		}
+		times := make([]clock, 0, len(r.Times))
+		for _, s := range r.Times {
+			t, err := time.Parse("15:04", s)
+			if err != nil {
+				return nil, fmt.Errorf("%w: time %q must be HH:MM", ErrInvalidRecurrence, s)
+			}
+			times = append(times, clock{hour: t.Hour(), minute: t.Minute()})
+		}
+		sort.Slice(times, func(i, j int) bool {
+			return times[i].hour*60+times[i].minute < times[j].hour*60+times[j].minute
+		})
+		evaluator = &calendarSchedule{loc: loc, times: times, days: days}
+	}
+
+	if err := checkFires(evaluator, loc); err != nil {
+		return nil, err
+	}
+	return evaluator, nil
+}
@@ -0,0 +1,37 @@
+package schedule
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Repository defines persistence operations for schedules
+type Repository interface {
+	Create(ctx context.Context, schedule *Schedule) error
+	Update(ctx context.Context, schedule *Schedule) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	FindByID(ctx context.Context, id uuid.UUID) (*Schedule, error)
+
+	// List returns schedules of workflows owned by userID, or all
+	// schedules when userID is nil
+	List(ctx context.Context, userID *uuid.UUID) ([]*Schedule, error)
+
+	// FindDue returns active schedules whose next run is before the given time
+	FindDue(ctx context.Context, before time.Time, limit int) ([]*Schedule, error)
+
+	// Claim records a run and moves the schedule to its next run, but only
+	// if its next run is still expected. It reports false when another
+	// scheduler claimed the run first.
+	Claim(ctx context.Context, id uuid.UUID, expected time.Time, ranAt time.Time, next *time.Time) (bool, error)
+}
+
+// HolidayCalendarRepository defines persistence operations for holiday calendars
+type HolidayCalendarRepository interface {
+	Create(ctx context.Context, calendar *HolidayCalendar) error
+	Update(ctx context.Context, calendar *HolidayCalendar) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	FindByID(ctx context.Context, id uuid.UUID) (*HolidayCalendar, error)
+	FindByTeam(ctx context.Context, teamID uuid.UUID) ([]*HolidayCalendar, error)
+}
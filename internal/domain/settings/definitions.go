@@ -0,0 +1,68 @@
+package settings
+
+import "time"
+
+// Known setting keys
+const (
+	KeyInstanceName           = "instance.name"
+	KeyInstanceBaseURL        = "instance.base_url"
+	KeyInstanceTimezone       = "instance.timezone"
+	KeyRegistrationEnabled    = "users.registration_enabled"
+	KeyUserDormantDays        = "users.dormant_after_days"
+	KeyUserDeactivateDays     = "users.deactivate_after_days"
+	KeyExecutionTimeout       = "executions.timeout"
+	KeyExecutionSaveOnSuccess = "executions.save_data_on_success"
+	KeyExecutionSaveOnError   = "executions.save_data_on_error"
+	KeyExecutionRetentionDays = "executions.retention_days"
+	KeyEditorAutoSave         = "editor.auto_save"
+	KeyEditorSnapToGrid       = "editor.snap_to_grid"
+	KeyWorkflowListSort       = "workflows.list_sort"
+	KeyExecutionListPageSize  = "executions.list_page_size"
+	KeyMaintenanceEnabled     = "maintenance.enabled"
+	KeyMaintenanceMessage     = "maintenance.message"
+	KeyMaintenanceRetryAfter  = "maintenance.retry_after"
+)
+
+// definitions lists every setting the instance understands. Stored values
+// for keys not listed here are ignored. Profile preferences such as
+// language and theme live in user.UserSettings instead.
+var definitions = []Definition{
+	{Key: KeyInstanceName, Scope: ScopeInstance, Type: TypeString, Default: "n8n", Description: "Name shown in the UI and in emails"},
+	{Key: KeyInstanceBaseURL, Scope: ScopeInstance, Type: TypeString, Default: "", Description: "Public URL used in links sent to users"},
+	{Key: KeyInstanceTimezone, Scope: ScopeInstance, Type: TypeString, Default: "UTC", Description: "Default timezone for schedules"},
+	{Key: KeyRegistrationEnabled, Scope: ScopeInstance, Type: TypeBool, Default: true, Description: "Whether new users may sign up"},
+	{Key: KeyUserDormantDays, Scope: ScopeInstance, Type: TypeInt, Default: 90, Description: "Days without activity after which an account counts as dormant"},
+	{Key: KeyUserDeactivateDays, Scope: ScopeInstance, Type: TypeInt, Default: 0, Description: "Days without activity after which an account is deactivated, or 0 to keep accounts active"},
+	{Key: KeyExecutionTimeout, Scope: ScopeInstance, Type: TypeDuration, Default: time.Hour, Description: "Maximum run time of an execution"},
+	{Key: KeyExecutionSaveOnSuccess, Scope: ScopeInstance, Type: TypeBool, Default: true, Description: "Keep node data of successful executions"},
+	{Key: KeyExecutionSaveOnError, Scope: ScopeInstance, Type: TypeBool, Default: true, Description: "Keep node data of failed executions"},
+	{Key: KeyExecutionRetentionDays, Scope: ScopeInstance, Type: TypeInt, Default: 30, Description: "Days to keep executions before pruning"},
+	{Key: KeyMaintenanceEnabled, Scope: ScopeInstance, Type: TypeBool, Default: false, Description: "Serve the API read-only and pause the scheduler and webhooks"},
+	{Key: KeyMaintenanceMessage, Scope: ScopeInstance, Type: TypeString, Default: "", Description: "Message returned to requests rejected during maintenance"},
+	{Key: KeyMaintenanceRetryAfter, Scope: ScopeInstance, Type: TypeDuration, Default: 5 * time.Minute, Description: "Retry-After sent to requests rejected during maintenance"},
+	{Key: KeyEditorAutoSave, Scope: ScopeUser, Type: TypeBool, Default: true, Description: "Save workflows automatically while editing"},
+	{Key: KeyEditorSnapToGrid, Scope: ScopeUser, Type: TypeBool, Default: true, Description: "Snap nodes to the canvas grid"},
+	{Key: KeyWorkflowListSort, Scope: ScopeUser, Type: TypeString, Default: "updated_at", Description: "Default sort order of the workflow list"},
+	{Key: KeyExecutionListPageSize, Scope: ScopeUser, Type: TypeInt, Default: 20, Description: "Executions shown per page"},
+}
+
+// Lookup returns the definition of a setting key
+func Lookup(key string) (Definition, bool) {
+	for _, d := range definitions {
+		if d.Key == key {
+			return d, true
+		}
+	}
+	return Definition{}, false
+}
+
+// Definitions returns the definitions of a scope
+func Definitions(scope Scope) []Definition {
+	defs := make([]Definition, 0, len(definitions))
+	for _, d := range definitions {
+		if d.Scope == scope {
+			defs = append(defs, d)
+		}
+	}
+	return defs
+}
@@ -0,0 +1,154 @@
+package settings
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Scope determines who a setting applies to
+type Scope string
+
+const (
+	// ScopeInstance settings apply to the whole instance and are managed by admins
+	ScopeInstance Scope = "instance"
+	// ScopeUser settings are chosen by each user for themselves
+	ScopeUser Scope = "user"
+)
+
+// Valid reports whether the scope is known
+func (s Scope) Valid() bool {
+	return s == ScopeInstance || s == ScopeUser
+}
+
+// Type is the value type of a setting
+type Type string
+
+const (
+	TypeString   Type = "string"
+	TypeInt      Type = "int"
+	TypeFloat    Type = "float"
+	TypeBool     Type = "bool"
+	TypeDuration Type = "duration"
+	TypeJSON     Type = "json"
+)
+
+// Setting is a stored setting value. Instance settings use uuid.Nil as
+// scope ID; user settings use the user's ID.
+type Setting struct {
+	Scope     Scope           `json:"scope" gorm:"primaryKey;type:varchar(20)"`
+	ScopeID   uuid.UUID       `json:"scope_id" gorm:"primaryKey;type:uuid"`
+	Key       string          `json:"key" gorm:"primaryKey"`
+	Type      Type            `json:"type" gorm:"not null"`
+	Value     json.RawMessage `json:"value" gorm:"type:jsonb;not null"`
+	UpdatedBy *uuid.UUID      `json:"updated_by,omitempty" gorm:"type:uuid"`
+	CreatedAt time.Time       `json:"created_at"`
+	UpdatedAt time.Time       `json:"updated_at"`
+}
+
+// TableName specifies the table name for GORM
+func (Setting) TableName() string {
+	return "instance_settings"
+}
+
+// Definition describes a known setting
+type Definition struct {
+	Key         string      `json:"key"`
+	Scope       Scope       `json:"scope"`
+	Type        Type        `json:"type"`
+	Default     interface{} `json:"default"`
+	Description string      `json:"description"`
+	// Secret values are never returned by the API
+	Secret bool `json:"secret,omitempty"`
+}
+
+// Normalize validates a JSON value against the definition's type and
+// returns its canonical encoding. Durations are stored as Go duration
+// strings.
+func (d Definition) Normalize(value json.RawMessage) (json.RawMessage, error) {
+	var v interface{}
+	if err := json.Unmarshal(value, &v); err != nil {
+		return nil, fmt.Errorf("%w: %s: %v", ErrInvalidSettingValue, d.Key, err)
+	}
+
+	switch d.Type {
+	case TypeString:
+		if _, ok := v.(string); !ok {
+			return nil, fmt.Errorf("%w: %s must be a string", ErrInvalidSettingValue, d.Key)
+		}
+	case TypeInt:
+		f, ok := v.(float64)
+		if !ok || f != math.Trunc(f) {
+			return nil, fmt.Errorf("%w: %s must be an integer", ErrInvalidSettingValue, d.Key)
+		}
+	case TypeFloat:
+		if _, ok := v.(float64); !ok {
+			return nil, fmt.Errorf("%w: %s must be a number", ErrInvalidSettingValue, d.Key)
+		}
+	case TypeBool:
+		if _, ok := v.(bool); !ok {
+			return nil, fmt.Errorf("%w: %s must be a boolean", ErrInvalidSettingValue, d.Key)
+		}
+	case TypeDuration:
+		s, ok := v.(string)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s must be a duration such as \"30s\"", ErrInvalidSettingValue, d.Key)
+		}
+		dur, err := time.ParseDuration(s)
+		if err != nil || dur < 0 {
+			return nil, fmt.Errorf("%w: %s must be a duration such as \"30s\"", ErrInvalidSettingValue, d.Key)
+		}
+		v = dur.String()
+	case TypeJSON:
+	default:
+		return nil, fmt.Errorf("%w: %s has unknown type %q", ErrInvalidSettingValue, d.Key, d.Type)
+	}
+
+	return json.Marshal(v)
+}
+
+// Decode converts a stored JSON value into the Go type of the definition:
+// string, int64, float64, bool, time.Duration or a decoded JSON value
+func (d Definition) Decode(value json.RawMessage) (interface{}, error) {
+	switch d.Type {
+	case TypeString:
+		var s string
+		err := json.Unmarshal(value, &s)
+		return s, err
+	case TypeInt:
+		var i int64
+		err := json.Unmarshal(value, &i)
+		return i, err
+	case TypeFloat:
+		var f float64
+		err := json.Unmarshal(value, &f)
+		return f, err
+	case TypeBool:
+		var b bool
+		err := json.Unmarshal(value, &b)
+		return b, err
+	case TypeDuration:
+		var s string
+		if err := json.Unmarshal(value, &s); err != nil {
+			return time.Duration(0), err
+		}
+		return time.ParseDuration(s)
+	default:
+		var v interface{}
+		err := json.Unmarshal(value, &v)
+		return v, err
+	}
+}
+
+// DefaultValue returns the encoded default value
+func (d Definition) DefaultValue() json.RawMessage {
+	if dur, ok := d.Default.(time.Duration); ok {
+		data, _ := json.Marshal(dur.String())
+		return data
+	}
+	data, _ := json.Marshal(d.Default)
+	return data
+}
@@ -0,0 +1,9 @@
+package settings
+
+import "errors"
+
+var (
+	ErrUnknownSetting      = errors.New("unknown setting")
+	ErrInvalidSettingValue = errors.New("invalid setting value")
+	ErrInvalidScope        = errors.New("invalid settings scope")
+)
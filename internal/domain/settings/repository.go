@@ -0,0 +1,14 @@
+package settings
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// Repository defines persistence operations for settings
+type Repository interface {
+	List(ctx context.Context, scope Scope, scopeID uuid.UUID) ([]*Setting, error)
+	// Save upserts settings and deletes the keys in reset in one transaction
+	Save(ctx context.Context, scope Scope, scopeID uuid.UUID, settings []*Setting, reset []string) error
+}
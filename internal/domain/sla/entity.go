@@ -0,0 +1,142 @@
+// Package sla models the service level expectations users set on their
+// workflows and whether the workflows currently meet them.
+package sla
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Objectives a policy can set
+const (
+	// ObjectiveFrequency: the workflow runs at least every
+	// MaxIntervalMinutes
+	ObjectiveFrequency = "frequency"
+	// ObjectiveDuration: executions finish within MaxDurationSeconds
+	ObjectiveDuration = "duration"
+	// ObjectiveErrorRate: at most MaxErrorRate percent of the executions
+	// within the error rate window fail
+	ObjectiveErrorRate = "error_rate"
+)
+
+const (
+	defaultErrorRateWindow = 60
+	maxErrorRateWindow     = 7 * 24 * 60
+)
+
+// Status is the outcome of the latest evaluation of a policy
+type Status string
+
+const (
+	// StatusPending: the policy was not evaluated yet
+	StatusPending  Status = "pending"
+	StatusOK       Status = "ok"
+	StatusBreached Status = "breached"
+	// StatusInactive: the workflow is inactive, so it is not expected to run
+	StatusInactive Status = "inactive"
+)
+
+// Policy holds the expectations set on a workflow and the breaches found
+// by the latest evaluation. Zero limits leave an objective unset.
+type Policy struct {
+	ID                 uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	WorkflowID         uuid.UUID `json:"workflow_id" gorm:"type:uuid;not null;uniqueIndex"`
+	MaxIntervalMinutes int       `json:"max_interval_minutes"`
+	MaxDurationSeconds int       `json:"max_duration_seconds"`
+	// MaxErrorRate is a percentage of the executions finished within
+	// ErrorRateWindowMinutes
+	MaxErrorRate           float64    `json:"max_error_rate"`
+	ErrorRateWindowMinutes int        `json:"error_rate_window_minutes"`
+	Status                 Status     `json:"status" gorm:"not null"`
+	Breaches               []Breach   `json:"breaches" gorm:"serializer:json"`
+	EvaluatedAt            *time.Time `json:"evaluated_at,omitempty"`
+	CreatedBy              *uuid.UUID `json:"created_by,omitempty" gorm:"type:uuid"`
+	CreatedAt              time.Time  `json:"created_at"`
+	UpdatedAt              time.Time  `json:"updated_at"`
+}
+
+// TableName specifies the table name for GORM
+func (Policy) TableName() string {
+	return "sla_policies"
+}
+
+// Breach is an objective a workflow does not meet
+type Breach struct {
+	Objective string `json:"objective"`
+	// Limit is the expectation and Value what was measured, in minutes
+	// for frequency, seconds for duration and percent for error_rate
+	Limit   float64   `json:"limit"`
+	Value   float64   `json:"value"`
+	Message string    `json:"message"`
+	Since   time.Time `json:"since"`
+}
+
+// Validate checks the policy's objectives and defaults the error rate
+// window
+func (p *Policy) Validate() error {
+	if p.MaxIntervalMinutes < 0 || p.MaxDurationSeconds < 0 || p.MaxErrorRate < 0 || p.MaxErrorRate > 100 {
+		return ErrInvalidObjective
+	}
+	if p.MaxIntervalMinutes == 0 && p.MaxDurationSeconds == 0 && p.MaxErrorRate == 0 {
+		return ErrNoObjectives
+	}
+	if p.ErrorRateWindowMinutes == 0 {
+		p.ErrorRateWindowMinutes = defaultErrorRateWindow
+	}
+	if p.ErrorRateWindowMinutes < 0 || p.ErrorRateWindowMinutes > maxErrorRateWindow {
+		return ErrInvalidWindow
+	}
+	return nil
+}
+
+// Reset clears the evaluation state, for example after the objectives
+// changed
+func (p *Policy) Reset() {
+	p.Status = StatusPending
+	p.Breaches = nil
+	p.EvaluatedAt = nil
+}
+
+// Apply records the breaches found by an evaluation and returns those
+// that started and those that ended since the previous one. Ongoing
+// breaches keep the time they started.
+func (p *Policy) Apply(breaches []Breach) (started, ended []Breach) {
+	previous := make(map[string]Breach, len(p.Breaches))
+	for _, b := range p.Breaches {
+		previous[b.Objective] = b
+	}
+
+	current := make(map[string]bool, len(breaches))
+	for i, b := range breaches {
+		current[b.Objective] = true
+		if prev, ok := previous[b.Objective]; ok {
+			breaches[i].Since = prev.Since
+			continue
+		}
+		started = append(started, b)
+	}
+	for _, b := range p.Breaches {
+		if !current[b.Objective] {
+			ended = append(ended, b)
+		}
+	}
+
+	now := time.Now()
+	p.Breaches = breaches
+	p.EvaluatedAt = &now
+	p.Status = StatusOK
+	if len(breaches) > 0 {
+		p.Status = StatusBreached
+	}
+	return started, ended
+}
+
+// Pause marks the policy inactive, dropping its breaches without them
+// being reported as ended
+func (p *Policy) Pause() {
+	now := time.Now()
+	p.Status = StatusInactive
+	p.Breaches = nil
+	p.EvaluatedAt = &now
+}
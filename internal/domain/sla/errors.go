@@ -0,0 +1,10 @@
+package sla
+
+import "errors"
+
+var (
+	ErrPolicyNotFound   = errors.New("SLA policy not found")
+	ErrNoObjectives     = errors.New("set at least one of max_interval_minutes, max_duration_seconds or max_error_rate")
+	ErrInvalidObjective = errors.New("SLA limits must not be negative and max_error_rate must be at most 100")
+	ErrInvalidWindow    = errors.New("error_rate_window_minutes must be 1 to 10080")
+)
@@ -0,0 +1,22 @@
+package sla
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// Repository defines persistence operations for SLA policies
+type Repository interface {
+	Create(ctx context.Context, p *Policy) error
+	Update(ctx context.Context, p *Policy) error
+
+	// Delete removes the policy of a workflow
+	Delete(ctx context.Context, workflowID uuid.UUID) error
+
+	// FindByWorkflow returns the policy of a workflow
+	FindByWorkflow(ctx context.Context, workflowID uuid.UUID) (*Policy, error)
+
+	// List returns every policy
+	List(ctx context.Context) ([]*Policy, error)
+}
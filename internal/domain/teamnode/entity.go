@@ -0,0 +1,48 @@
+package teamnode
+
+import (
+	"encoding/json"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var slugPattern = regexp.MustCompile(`^[a-z][a-z0-9_]{0,62}$`)
+
+// TeamNode is a node type generated for a team from the OpenAPI document
+// of one of its APIs. Only the team's workflows may use it.
+type TeamNode struct {
+	ID     uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	TeamID uuid.UUID `json:"team_id" gorm:"type:uuid;not null"`
+	// Slug names the node within its team; the node type is derived from it
+	Slug string `json:"slug" gorm:"not null"`
+	Type string `json:"type" gorm:"uniqueIndex;not null"`
+	Name string `json:"name" gorm:"not null"`
+	// Descriptor is the declarative node descriptor generated from the document
+	Descriptor json.RawMessage `json:"descriptor" gorm:"type:jsonb;not null"`
+	CreatedBy  uuid.UUID       `json:"created_by" gorm:"type:uuid;not null"`
+	CreatedAt  time.Time       `json:"created_at"`
+	UpdatedAt  time.Time       `json:"updated_at"`
+}
+
+// TableName specifies the table name for GORM
+func (TeamNode) TableName() string {
+	return "team_nodes"
+}
+
+// NodeType returns the type of a team's node with the given slug. It is
+// prefixed with the team so the slugs of teams do not collide.
+func NodeType(teamID uuid.UUID, slug string) string {
+	return "team_" + strings.ReplaceAll(teamID.String(), "-", "")[:12] + "_" + slug
+}
+
+// ValidateSlug checks that a slug is lower case snake case starting with
+// a letter
+func ValidateSlug(slug string) error {
+	if !slugPattern.MatchString(slug) {
+		return ErrInvalidSlug
+	}
+	return nil
+}
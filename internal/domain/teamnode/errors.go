@@ -0,0 +1,11 @@
+package teamnode
+
+import "errors"
+
+var (
+	ErrTeamNodeNotFound = errors.New("team node not found")
+	ErrTeamAccessDenied = errors.New("team access denied")
+	ErrInvalidSpec      = errors.New("invalid OpenAPI document")
+	ErrInvalidSlug      = errors.New("node slug must be lower case letters, digits and underscores, starting with a letter")
+	ErrSlugTaken        = errors.New("the team already has a node with this slug")
+)
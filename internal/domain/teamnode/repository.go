@@ -0,0 +1,19 @@
+package teamnode
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// Repository defines persistence operations for team nodes
+type Repository interface {
+	Create(ctx context.Context, n *TeamNode) error
+	Update(ctx context.Context, n *TeamNode) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	FindByID(ctx context.Context, id uuid.UUID) (*TeamNode, error)
+	FindByType(ctx context.Context, nodeType string) (*TeamNode, error)
+	FindByTeam(ctx context.Context, teamID uuid.UUID) ([]*TeamNode, error)
+	// FindAll returns the nodes of every team, to register them
+	FindAll(ctx context.Context) ([]*TeamNode, error)
+}
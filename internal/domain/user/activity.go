@@ -0,0 +1,19 @@
+package user
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Activity records when a user last made an authenticated request. It is
+// kept apart from users so frequent updates do not rewrite user rows.
+type Activity struct {
+	UserID     uuid.UUID `json:"user_id" gorm:"type:uuid;primary_key"`
+	LastSeenAt time.Time `json:"last_seen_at" gorm:"not null"`
+}
+
+// TableName specifies the table name for GORM
+func (Activity) TableName() string {
+	return "user_activity"
+}
@@ -20,6 +20,7 @@ type User struct {
 	ProfilePicture    string     `json:"profile_picture,omitempty"`
 	Settings          UserSettings `json:"settings" gorm:"serializer:json"`
 	LastLoginAt       *time.Time `json:"last_login_at,omitempty"`
+	LastSeenAt        *time.Time `json:"last_seen_at,omitempty" gorm:"->;-:migration"`
 	PasswordChangedAt *time.Time `json:"password_changed_at,omitempty"`
 	CreatedAt         time.Time  `json:"created_at"`
 	UpdatedAt         time.Time  `json:"updated_at"`
@@ -30,9 +31,10 @@ type User struct {
 type Role string
 
 const (
-	RoleUser  Role = "user"
-	RoleAdmin Role = "admin"
-	RoleOwner Role = "owner"
+	RoleUser     Role = "user"
+	RoleApprover Role = "approver"
+	RoleAdmin    Role = "admin"
+	RoleOwner    Role = "owner"
 )
 
 // UserSettings contains user-specific settings
@@ -68,7 +70,7 @@ type APIKey struct {
 	Name       string     `json:"name" gorm:"not null"`
 	KeyHash    string     `json:"-" gorm:"uniqueIndex;not null"`
 	KeyPreview string     `json:"key_preview"` // First 8 chars for identification
-	Scopes     []string   `json:"scopes" gorm:"type:text[]"`
+	Scopes     []string   `json:"scopes" gorm:"type:text[];serializer:array"`
 	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
 	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
 	CreatedAt  time.Time  `json:"created_at"`
@@ -110,6 +112,14 @@ type TeamSettings struct {
 	MaxExecutions    int  `json:"max_executions"`
 	ShareCredentials bool `json:"share_credentials"`
 	ShareVariables   bool `json:"share_variables"`
+	// ExecutionsPerMinute caps the executions of the team's workflows that
+	// workers start per minute, and MaxConcurrentExecutions those running
+	// at once. Zero is unlimited.
+	ExecutionsPerMinute     int `json:"executions_per_minute,omitempty"`
+	MaxConcurrentExecutions int `json:"max_concurrent_executions,omitempty"`
+	// SchedulingWeight is how many jobs of the team workers take in a row
+	// when it shares a queue with other teams and workflows. Zero is 1.
+	SchedulingWeight int `json:"scheduling_weight,omitempty"`
 }
 
 // SetPassword hashes and sets the user's password
@@ -145,6 +155,19 @@ func (u *User) UpdateLastLogin() {
 	u.UpdatedAt = now
 }
 
+// LastActiveAt returns when the user was last seen, falling back to the
+// last login and then the account's creation
+func (u *User) LastActiveAt() time.Time {
+	switch {
+	case u.LastSeenAt != nil:
+		return *u.LastSeenAt
+	case u.LastLoginAt != nil:
+		return *u.LastLoginAt
+	default:
+		return u.CreatedAt
+	}
+}
+
 // IsPasswordExpired checks if password needs to be changed (e.g., after 90 days)
 func (u *User) IsPasswordExpired(maxAge time.Duration) bool {
 	if u.PasswordChangedAt == nil {
@@ -166,7 +189,11 @@ func (u *User) HasPermission(permission string) bool {
 	case RoleAdmin:
 		// Admins have most permissions except system-level ones
 		return permission != "system:manage"
-	case RoleUser:
+	case RoleUser, RoleApprover:
+		// Approvers may additionally accept workflow change requests
+		if u.Role == RoleApprover && permission == "workflow:approve" {
+			return true
+		}
 		// Regular users have limited permissions
 		allowedPermissions := []string{
 			"workflow:read",
@@ -0,0 +1,9 @@
+package user
+
+import "errors"
+
+var (
+	ErrUserNotFound       = errors.New("user not found")
+	ErrTeamNotFound       = errors.New("team not found")
+	ErrTeamMemberNotFound = errors.New("team member not found")
+)
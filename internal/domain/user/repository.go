@@ -0,0 +1,42 @@
+package user
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ListFilter selects users. Zero fields do not filter.
+type ListFilter struct {
+	IsActive *bool
+	Roles    []Role
+	// LastActiveBefore selects users not seen since the given time, by
+	// their last activity, last login or else creation
+	LastActiveBefore *time.Time
+	Limit            int
+	Offset           int
+}
+
+// Repository persists users
+type Repository interface {
+	FindByID(ctx context.Context, id uuid.UUID) (*User, error)
+	FindByIDs(ctx context.Context, ids []uuid.UUID) ([]*User, error)
+	// FindByEmails returns the active users with the given emails
+	FindByEmails(ctx context.Context, emails []string) ([]*User, error)
+	// List returns the users matching filter ordered by name, with their
+	// last activity
+	List(ctx context.Context, filter ListFilter) ([]*User, error)
+	SetActive(ctx context.Context, id uuid.UUID, active bool) error
+	FindTeam(ctx context.Context, id uuid.UUID) (*Team, error)
+	FindTeamMember(ctx context.Context, teamID, userID uuid.UUID) (*TeamMember, error)
+	// TeamIDs returns the IDs of the teams a user belongs to
+	TeamIDs(ctx context.Context, userID uuid.UUID) ([]uuid.UUID, error)
+
+	// RecordActivity stores when users were last seen. Older times than
+	// those stored are ignored.
+	RecordActivity(ctx context.Context, activity []*Activity) error
+	// DeactivateInactive deactivates a user unless they were active at or
+	// after before, reporting whether they were deactivated
+	DeactivateInactive(ctx context.Context, id uuid.UUID, before time.Time) (bool, error)
+}
@@ -0,0 +1,69 @@
+package worker
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Worker represents a registered worker process
+type Worker struct {
+	ID              uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	Hostname        string     `json:"hostname" gorm:"not null"`
+	Version         string     `json:"version"`
+	Concurrency     int        `json:"concurrency"`
+	Queues          []string   `json:"queues" gorm:"serializer:json"`
+	Status          Status     `json:"status" gorm:"not null"`
+	ActiveJobs      int        `json:"active_jobs"`
+	StartedAt       time.Time  `json:"started_at"`
+	LastHeartbeatAt time.Time  `json:"last_heartbeat_at"`
+	StoppedAt       *time.Time `json:"stopped_at,omitempty"`
+}
+
+// Status represents the status of a worker
+type Status string
+
+const (
+	StatusRunning Status = "running"
+	StatusStopped Status = "stopped"
+	StatusDead    Status = "dead"
+)
+
+// IsStale returns whether the worker missed heartbeats for longer than timeout
+func (w *Worker) IsStale(timeout time.Duration) bool {
+	return w.Status == StatusRunning && time.Since(w.LastHeartbeatAt) > timeout
+}
+
+// Consumes reports whether the worker takes jobs from the named queue.
+// Workers registered without queues take jobs from defaultQueue only.
+func (w *Worker) Consumes(queue, defaultQueue string) bool {
+	if len(w.Queues) == 0 {
+		return queue == defaultQueue
+	}
+	for _, q := range w.Queues {
+		if q == queue {
+			return true
+		}
+	}
+	return false
+}
+
+// Heartbeat records a heartbeat from the worker
+func (w *Worker) Heartbeat(activeJobs int) {
+	w.ActiveJobs = activeJobs
+	w.LastHeartbeatAt = time.Now()
+}
+
+// Stop marks the worker as cleanly stopped
+func (w *Worker) Stop() {
+	now := time.Now()
+	w.Status = StatusStopped
+	w.StoppedAt = &now
+}
+
+// MarkDead marks the worker as dead after missed heartbeats
+func (w *Worker) MarkDead() {
+	now := time.Now()
+	w.Status = StatusDead
+	w.StoppedAt = &now
+}
@@ -0,0 +1,8 @@
+package worker
+
+import "errors"
+
+var (
+	// Worker errors
+	ErrWorkerNotFound = errors.New("worker not found")
+)
@@ -0,0 +1,24 @@
+package worker
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Repository defines persistence operations for the worker registry
+type Repository interface {
+	Create(ctx context.Context, worker *Worker) error
+	Update(ctx context.Context, worker *Worker) error
+	FindByID(ctx context.Context, id uuid.UUID) (*Worker, error)
+
+	// FindLive returns running workers that sent a heartbeat after since
+	FindLive(ctx context.Context, since time.Time) ([]*Worker, error)
+
+	// FindStale returns running workers whose last heartbeat is before the given time
+	FindStale(ctx context.Context, before time.Time) ([]*Worker, error)
+
+	// FindDeadSince returns workers marked dead after since
+	FindDeadSince(ctx context.Context, since time.Time) ([]*Worker, error)
+}
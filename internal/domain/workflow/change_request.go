@@ -0,0 +1,125 @@
+package workflow
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ChangeRequestStatus represents the status of a change request
+type ChangeRequestStatus string
+
+const (
+	ChangeRequestPending  ChangeRequestStatus = "pending"
+	ChangeRequestApproved ChangeRequestStatus = "approved"
+	ChangeRequestRejected ChangeRequestStatus = "rejected"
+)
+
+// ChangeRequest is a proposed publication of a workflow draft that needs an
+// approver's decision before it reaches production. It freezes the proposed
+// graph so later draft edits can't slip into the approved version.
+type ChangeRequest struct {
+	ID           uuid.UUID           `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	WorkflowID   uuid.UUID           `json:"workflow_id" gorm:"type:uuid;not null"`
+	Version      int                 `json:"version" gorm:"not null"`
+	Status       ChangeRequestStatus `json:"status" gorm:"not null"`
+	RequestedBy  uuid.UUID           `json:"requested_by" gorm:"type:uuid;not null"`
+	Nodes        []Node              `json:"nodes" gorm:"serializer:json"`
+	Connections  []Connection        `json:"connections" gorm:"serializer:json"`
+	Settings     WorkflowSettings    `json:"settings" gorm:"serializer:json"`
+	Diff         Diff                `json:"diff" gorm:"serializer:json"`
+	Comments     []ChangeComment     `json:"comments" gorm:"serializer:json"`
+	DecidedBy    *uuid.UUID          `json:"decided_by,omitempty" gorm:"type:uuid"`
+	DecidedAt    *time.Time          `json:"decided_at,omitempty"`
+	DecisionNote string              `json:"decision_note,omitempty"`
+	CreatedAt    time.Time           `json:"created_at"`
+	UpdatedAt    time.Time           `json:"updated_at"`
+}
+
+// TableName returns the table name for change requests
+func (ChangeRequest) TableName() string {
+	return "workflow_change_requests"
+}
+
+// ChangeComment is a reviewer or author comment on a change request
+type ChangeComment struct {
+	UserID    uuid.UUID `json:"user_id"`
+	Body      string    `json:"body"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// NewChangeRequest freezes the proposed snapshot into a pending change request
+func NewChangeRequest(proposed *WorkflowVersion, diff Diff, requestedBy uuid.UUID) *ChangeRequest {
+	now := time.Now()
+	return &ChangeRequest{
+		WorkflowID:  proposed.WorkflowID,
+		Version:     proposed.Version,
+		Status:      ChangeRequestPending,
+		RequestedBy: requestedBy,
+		Nodes:       proposed.Nodes,
+		Connections: proposed.Connections,
+		Settings:    proposed.Settings,
+		Diff:        diff,
+		Comments:    []ChangeComment{},
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+}
+
+// AddComment appends a comment to the change request
+func (r *ChangeRequest) AddComment(userID uuid.UUID, body string) (ChangeComment, error) {
+	if body == "" {
+		return ChangeComment{}, ErrChangeCommentEmpty
+	}
+	comment := ChangeComment{UserID: userID, Body: body, CreatedAt: time.Now()}
+	r.Comments = append(r.Comments, comment)
+	r.UpdatedAt = comment.CreatedAt
+	return comment, nil
+}
+
+// Approve records an approval. Authors can't approve their own changes.
+func (r *ChangeRequest) Approve(approverID uuid.UUID, note string) error {
+	if err := r.decide(approverID); err != nil {
+		return err
+	}
+	r.Status = ChangeRequestApproved
+	r.DecisionNote = note
+	return nil
+}
+
+// Reject records a rejection
+func (r *ChangeRequest) Reject(approverID uuid.UUID, note string) error {
+	if err := r.decide(approverID); err != nil {
+		return err
+	}
+	r.Status = ChangeRequestRejected
+	r.DecisionNote = note
+	return nil
+}
+
+// Snapshot returns the version snapshot the change request proposes
+func (r *ChangeRequest) Snapshot(approvedBy uuid.UUID) *WorkflowVersion {
+	return &WorkflowVersion{
+		WorkflowID:  r.WorkflowID,
+		Version:     r.Version,
+		Nodes:       r.Nodes,
+		Connections: r.Connections,
+		Settings:    r.Settings,
+		PublishedBy: approvedBy,
+		CreatedAt:   time.Now(),
+	}
+}
+
+func (r *ChangeRequest) decide(approverID uuid.UUID) error {
+	if r.Status != ChangeRequestPending {
+		return ErrChangeRequestNotPending
+	}
+	if approverID == r.RequestedBy {
+		return ErrChangeRequestSelfApproval
+	}
+	now := time.Now()
+	r.DecidedBy = &approverID
+	r.DecidedAt = &now
+	r.UpdatedAt = now
+	return nil
+}
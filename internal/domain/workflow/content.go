@@ -0,0 +1,66 @@
+package workflow
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"regexp"
+)
+
+var slugPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{1,61}[a-z0-9]$`)
+
+// ValidateSlug checks that slug can address a workflow
+func ValidateSlug(slug string) error {
+	if !slugPattern.MatchString(slug) {
+		return ErrInvalidWorkflowSlug
+	}
+	return nil
+}
+
+// content holds the fields of a workflow clients edit, in a fixed order
+type content struct {
+	Name        string                 `json:"name"`
+	Slug        *string                `json:"slug"`
+	Description string                 `json:"description"`
+	Nodes       []Node                 `json:"nodes"`
+	Connections []Connection           `json:"connections"`
+	Settings    WorkflowSettings       `json:"settings"`
+	Tags        []string               `json:"tags"`
+	Variables   map[string]interface{} `json:"variables"`
+}
+
+// ContentHash returns a SHA-256 hash of the draft's name, slug,
+// description, graph, settings, tags and variables. It changes with every
+// edit and with nothing else, such as activation or publishing, so clients
+// detect changes made behind their back by comparing it with the hash
+// they last saw.
+func (w *Workflow) ContentHash() string {
+	c := content{
+		Name:        w.Name,
+		Slug:        w.Slug,
+		Description: w.Description,
+		Nodes:       w.Nodes,
+		Connections: w.Connections,
+		Settings:    w.Settings,
+		Tags:        w.Tags,
+		Variables:   w.Variables,
+	}
+	// Missing and empty collections are the same content
+	if c.Nodes == nil {
+		c.Nodes = []Node{}
+	}
+	if c.Connections == nil {
+		c.Connections = []Connection{}
+	}
+	if c.Tags == nil {
+		c.Tags = []string{}
+	}
+	if c.Variables == nil {
+		c.Variables = map[string]interface{}{}
+	}
+
+	// Maps encode with sorted keys, so equal content encodes equally
+	encoded, _ := json.Marshal(c)
+	sum := sha256.Sum256(encoded)
+	return hex.EncodeToString(sum[:])
+}
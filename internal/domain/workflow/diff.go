@@ -0,0 +1,86 @@
+package workflow
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Diff describes the structural changes between two workflow graphs
+type Diff struct {
+	NodesAdded         []string     `json:"nodes_added,omitempty"`
+	NodesRemoved       []string     `json:"nodes_removed,omitempty"`
+	NodesChanged       []string     `json:"nodes_changed,omitempty"`
+	ConnectionsAdded   []Connection `json:"connections_added,omitempty"`
+	ConnectionsRemoved []Connection `json:"connections_removed,omitempty"`
+	SettingsChanged    bool         `json:"settings_changed,omitempty"`
+}
+
+// IsEmpty returns whether the diff contains no changes
+func (d Diff) IsEmpty() bool {
+	return len(d.NodesAdded) == 0 &&
+		len(d.NodesRemoved) == 0 &&
+		len(d.NodesChanged) == 0 &&
+		len(d.ConnectionsAdded) == 0 &&
+		len(d.ConnectionsRemoved) == 0 &&
+		!d.SettingsChanged
+}
+
+// DiffVersions compares a base version with a proposed one. A nil base
+// means the workflow was never published, so everything counts as added.
+func DiffVersions(base, proposed *WorkflowVersion) Diff {
+	var diff Diff
+	if base == nil {
+		base = &WorkflowVersion{}
+	}
+
+	oldNodes := make(map[string]Node, len(base.Nodes))
+	for _, n := range base.Nodes {
+		oldNodes[n.ID] = n
+	}
+	newNodes := make(map[string]bool, len(proposed.Nodes))
+	for _, n := range proposed.Nodes {
+		newNodes[n.ID] = true
+		old, exists := oldNodes[n.ID]
+		switch {
+		case !exists:
+			diff.NodesAdded = append(diff.NodesAdded, n.ID)
+		case !reflect.DeepEqual(old, n):
+			diff.NodesChanged = append(diff.NodesChanged, n.ID)
+		}
+	}
+	for _, n := range base.Nodes {
+		if !newNodes[n.ID] {
+			diff.NodesRemoved = append(diff.NodesRemoved, n.ID)
+		}
+	}
+
+	oldConns := make(map[string]bool, len(base.Connections))
+	for _, c := range base.Connections {
+		oldConns[connectionKey(c)] = true
+	}
+	newConns := make(map[string]bool, len(proposed.Connections))
+	for _, c := range proposed.Connections {
+		key := connectionKey(c)
+		newConns[key] = true
+		if !oldConns[key] {
+			diff.ConnectionsAdded = append(diff.ConnectionsAdded, c)
+		}
+	}
+	for _, c := range base.Connections {
+		if !newConns[connectionKey(c)] {
+			diff.ConnectionsRemoved = append(diff.ConnectionsRemoved, c)
+		}
+	}
+
+	diff.SettingsChanged = !reflect.DeepEqual(base.Settings, proposed.Settings)
+
+	return diff
+}
+
+// connectionKey identifies a connection by its endpoints
+func connectionKey(c Connection) string {
+	return fmt.Sprintf("%s:%s:%d->%s:%s:%d",
+		c.Source.NodeID, c.Source.Type, c.Source.Index,
+		c.Target.NodeID, c.Target.Type, c.Target.Index,
+	)
+}
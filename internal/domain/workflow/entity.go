@@ -10,6 +10,9 @@ import (
 type Workflow struct {
 	ID          uuid.UUID              `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
 	Name        string                 `json:"name" gorm:"not null"`
+	// Slug is an optional client chosen key, unique across workflows, by
+	// which tools managing workflows as code address them
+	Slug        *string                `json:"slug,omitempty"`
 	Description string                 `json:"description"`
 	UserID      uuid.UUID              `json:"user_id" gorm:"type:uuid;not null"`
 	TeamID      *uuid.UUID             `json:"team_id,omitempty" gorm:"type:uuid"`
@@ -17,12 +20,15 @@ type Workflow struct {
 	Nodes       []Node                 `json:"nodes" gorm:"serializer:json"`
 	Connections []Connection           `json:"connections" gorm:"serializer:json"`
 	Settings    WorkflowSettings       `json:"settings" gorm:"serializer:json"`
-	Tags        []string               `json:"tags" gorm:"type:text[]"`
+	Tags        []string               `json:"tags" gorm:"type:text[];serializer:array"`
 	Version     int                    `json:"version" gorm:"default:1"`
 	Variables   map[string]interface{} `json:"variables" gorm:"serializer:json"`
 	CreatedAt   time.Time              `json:"created_at"`
 	UpdatedAt   time.Time              `json:"updated_at"`
 	DeletedAt   *time.Time             `json:"deleted_at,omitempty" gorm:"index"`
+
+	// PublishedVersion is the version pinned for triggers; Version is the draft
+	PublishedVersion *int `json:"published_version,omitempty"`
 }
 
 // Node represents a node in a workflow
@@ -35,13 +41,45 @@ type Node struct {
 	CredentialID   *uuid.UUID             `json:"credential_id,omitempty"`
 	Disabled       bool                   `json:"disabled"`
 	Notes          string                 `json:"notes,omitempty"`
+	// RetryOnFail runs a failing node again up to MaxRetries times,
+	// waiting WaitBetweenTries milliseconds before the first retry and
+	// longer before each next one
 	RetryOnFail    bool                   `json:"retry_on_fail"`
 	MaxRetries     int                    `json:"max_retries"`
 	WaitBetweenTries int                  `json:"wait_between_tries"` // milliseconds
 	ContinueOnFail bool                   `json:"continue_on_fail"`
 	ExecuteOnce    bool                   `json:"execute_once"`
+	// ExecutionMode sets whether the node runs once with all its input
+	// items or once per item. ExecuteOnce overrides it.
+	ExecutionMode  NodeExecutionMode      `json:"execution_mode,omitempty"`
+	// AlwaysOutputData makes the node output one empty item rather than
+	// none, so the nodes after it still run
+	AlwaysOutputData bool `json:"always_output_data,omitempty"`
+	// ExecuteOnEmptyInput runs the node with one empty item when it
+	// receives none, rather than skipping it
+	ExecuteOnEmptyInput bool `json:"execute_on_empty_input,omitempty"`
+	// Timeout cuts a run of the node off after as many seconds, failing
+	// it; zero takes node.max_execution_time
+	Timeout int `json:"timeout,omitempty"`
 }
 
+// NodeExecutionMode sets how a node is run for its input items
+type NodeExecutionMode string
+
+const (
+	// NodeExecutionModeAllItems runs the node once with all items, the default
+	NodeExecutionModeAllItems NodeExecutionMode = "all_items"
+	// NodeExecutionModePerItem runs the node once for each item
+	NodeExecutionModePerItem NodeExecutionMode = "per_item"
+)
+
+const (
+	// MaxNodeRetries bounds how often a node retrying on fail is retried
+	MaxNodeRetries = 10
+	// MaxWaitBetweenTries bounds the wait between tries, in milliseconds
+	MaxWaitBetweenTries = 300000
+)
+
 // NodePosition represents the position of a node on the canvas
 type NodePosition struct {
 	X float64 `json:"x"`
@@ -62,6 +100,14 @@ type ConnectionPoint struct {
 	Index  int    `json:"index"`
 }
 
+const (
+	// ConnectionTypeMain is the output a node's items leave on
+	ConnectionTypeMain = "main"
+	// ConnectionTypeError is the output of a node continuing on fail that
+	// the items of its failed runs leave on
+	ConnectionTypeError = "error"
+)
+
 // ConnectionData contains additional connection metadata
 type ConnectionData struct {
 	Disabled bool   `json:"disabled,omitempty"`
@@ -80,6 +126,41 @@ type WorkflowSettings struct {
 	MaxExecutionTime  int                    `json:"max_execution_time"` // seconds
 	Timeout           int                    `json:"timeout"`             // seconds
 	CustomData        map[string]interface{} `json:"custom_data,omitempty"`
+	// Queue pins the executions of the workflow to a worker queue, such as
+	// one of workers with a GPU or in a region
+	Queue string `json:"queue,omitempty"`
+	// Region tags the workflow for data residency: its executions run on
+	// the region's queue and its binary data is stored in the region
+	Region string `json:"region,omitempty"`
+	// ErrorWorkflowPartialOutput passes the outputs of the nodes that
+	// completed before an execution timed out to the error workflow
+	ErrorWorkflowPartialOutput bool `json:"error_workflow_partial_output,omitempty"`
+}
+
+// Execution orders of a workflow
+const (
+	// ExecutionOrderSequential runs one node at a time, the default
+	ExecutionOrderSequential = "sequential"
+	// ExecutionOrderParallel runs the nodes of independent branches
+	// concurrently
+	ExecutionOrderParallel = "parallel"
+)
+
+// QueueOr returns the queue the workflow is pinned to, or defaultQueue
+func (s WorkflowSettings) QueueOr(defaultQueue string) string {
+	if s.Queue != "" {
+		return s.Queue
+	}
+	return defaultQueue
+}
+
+// QueueTeam returns the team whose quotas the jobs of the workflow count
+// against, empty when no team owns it
+func (w *Workflow) QueueTeam() string {
+	if w.TeamID == nil {
+		return ""
+	}
+	return w.TeamID.String()
 }
 
 // WorkflowStatus represents the status of a workflow
@@ -132,6 +213,24 @@ func (n *Node) Validate() error {
 		return ErrNodeNameRequired
 	}
 	
+	switch n.ExecutionMode {
+	case "", NodeExecutionModeAllItems, NodeExecutionModePerItem:
+	default:
+		return ErrNodeExecutionModeInvalid
+	}
+	
+	if n.MaxRetries < 0 || n.MaxRetries > MaxNodeRetries {
+		return ErrNodeRetriesInvalid
+	}
+	
+	if n.WaitBetweenTries < 0 || n.WaitBetweenTries > MaxWaitBetweenTries {
+		return ErrNodeRetryWaitInvalid
+	}
+	
+	if n.Timeout < 0 {
+		return ErrNodeTimeoutInvalid
+	}
+	
 	return nil
 }
 
@@ -148,14 +247,15 @@ func (c *Connection) Validate() error {
 	return nil
 }
 
-// Activate activates the workflow
+// Activate activates the workflow. Triggers run the published version, so a
+// workflow must be published before it can be activated.
 func (w *Workflow) Activate() error {
 	if w.IsActive {
 		return ErrWorkflowAlreadyActive
 	}
 	
-	if err := w.Validate(); err != nil {
-		return err
+	if w.PublishedVersion == nil {
+		return ErrWorkflowNotPublished
 	}
 	
 	w.IsActive = true
@@ -175,6 +275,65 @@ func (w *Workflow) IncrementVersion() {
 	w.UpdatedAt = time.Now()
 }
 
+// HasUnpublishedChanges returns whether the draft differs from the published version
+func (w *Workflow) HasUnpublishedChanges() bool {
+	return w.PublishedVersion == nil || *w.PublishedVersion != w.Version
+}
+
+// BeginDraft prepares the workflow for edits. Editing a workflow whose draft
+// is the published version starts a new draft version so the published
+// snapshot stays untouched.
+func (w *Workflow) BeginDraft() {
+	if !w.HasUnpublishedChanges() {
+		w.IncrementVersion()
+	}
+}
+
+// Publish validates the draft, pins it as the published version and returns
+// the immutable snapshot to persist
+func (w *Workflow) Publish(publishedBy uuid.UUID) (*WorkflowVersion, error) {
+	if err := w.Validate(); err != nil {
+		return nil, err
+	}
+	
+	if !w.HasUnpublishedChanges() {
+		return nil, ErrWorkflowNoChanges
+	}
+	
+	snapshot := NewVersionSnapshot(w, publishedBy)
+	w.PinVersion(snapshot)
+	
+	return snapshot, nil
+}
+
+// PinVersion makes a stored snapshot the published version
+func (w *Workflow) PinVersion(v *WorkflowVersion) {
+	version := v.Version
+	w.PublishedVersion = &version
+	w.UpdatedAt = time.Now()
+}
+
+// HasTag returns whether the workflow carries any of the given tags
+func (w *Workflow) HasTag(tags ...string) bool {
+	for _, t := range w.Tags {
+		for _, want := range tags {
+			if t == want {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// RestoreVersion replaces the draft content with a stored version
+func (w *Workflow) RestoreVersion(v *WorkflowVersion) {
+	w.BeginDraft()
+	w.Nodes = v.Nodes
+	w.Connections = v.Connections
+	w.Settings = v.Settings
+	w.UpdatedAt = time.Now()
+}
+
 // Clone creates a copy of the workflow
 func (w *Workflow) Clone() *Workflow {
 	clone := &Workflow{
@@ -10,14 +10,59 @@ var (
 	ErrWorkflowAlreadyActive = errors.New("workflow is already active")
 	ErrWorkflowNotActive     = errors.New("workflow is not active")
 	ErrWorkflowInvalid       = errors.New("workflow configuration is invalid")
+	ErrWorkflowNotPublished  = errors.New("workflow has no published version")
+	ErrWorkflowNoChanges     = errors.New("workflow draft has no unpublished changes")
+	ErrVersionNotFound       = errors.New("workflow version not found")
+	ErrWorkflowNameTaken     = errors.New("a workflow with this name already exists")
+	ErrInvalidWorkflowSlug   = errors.New("slug must be 3 to 63 lowercase letters, digits or dashes")
+	ErrWorkflowSlugTaken     = errors.New("workflow slug is already in use")
+	ErrWorkflowIDTaken       = errors.New("workflow ID is already in use")
+	ErrUnknownQueue          = errors.New("workflow is pinned to an unknown queue")
+	ErrUnknownRegion         = errors.New("workflow is tagged with an unknown region")
+	ErrQueueOutsideRegion    = errors.New("workflow is pinned to a queue outside its region")
+	
+	// Change request errors
+	ErrChangeRequestNotFound     = errors.New("change request not found")
+	ErrChangeRequestPending      = errors.New("workflow already has a pending change request")
+	ErrChangeRequestNotPending   = errors.New("change request is not pending")
+	ErrChangeRequestSelfApproval = errors.New("change request cannot be decided by its author")
+	ErrChangeCommentEmpty        = errors.New("comment body is required")
+	
+	// Secret errors
+	ErrSecretNotFound      = errors.New("workflow secret not found")
+	ErrSecretNameInvalid   = errors.New("secret names must start with a letter or underscore and hold up to 64 letters, digits and underscores")
+	ErrSecretValueRequired = errors.New("secret value is required")
+	ErrSecretTooLarge      = errors.New("secret value must be at most 8 KiB")
+	ErrTooManySecrets      = errors.New("workflow already has the maximum of 50 secrets")
+	ErrSecretsUnavailable  = errors.New("workflow secrets are unavailable without an encryption key")
+	
+	// Pinned data errors
+	ErrPinnedDataNotFound  = errors.New("node has no pinned data")
+	ErrPinnedItemsRequired = errors.New("items to pin are required")
+	ErrPinnedDataTooLarge  = errors.New("pinned data must be at most 1000 items and 1 MiB")
+	
+	// Migration errors
+	ErrUnsupportedMigration      = errors.New("unsupported migration package version")
+	ErrMigrationWorkflowsMissing = errors.New("workflow_ids is required")
+	ErrInvalidMigrationConflict  = errors.New("on_conflict must be skip, overwrite or rename")
+	ErrMigrationTargetNotFound   = errors.New("migration target not found")
+	ErrMigrationTargetFailed     = errors.New("import into the migration target failed")
+	
+	// Import errors
+	ErrInvalidImport = errors.New("import document is invalid")
 	
 	// Node errors
-	ErrNodeNotFound      = errors.New("node not found")
-	ErrNodeIDRequired    = errors.New("node ID is required")
-	ErrNodeTypeRequired  = errors.New("node type is required")
-	ErrNodeNameRequired  = errors.New("node name is required")
-	ErrNodeTypeInvalid   = errors.New("node type is invalid")
-	ErrNodeConfigInvalid = errors.New("node configuration is invalid")
+	ErrNodeNotFound             = errors.New("node not found")
+	ErrNodeIDRequired           = errors.New("node ID is required")
+	ErrNodeIDDuplicate          = errors.New("node ID is not unique")
+	ErrNodeTypeRequired         = errors.New("node type is required")
+	ErrNodeNameRequired         = errors.New("node name is required")
+	ErrNodeTypeInvalid          = errors.New("node type is invalid")
+	ErrNodeConfigInvalid        = errors.New("node configuration is invalid")
+	ErrNodeExecutionModeInvalid = errors.New("node execution mode must be all_items or per_item")
+	ErrNodeRetriesInvalid       = errors.New("node max_retries must be between 0 and 10")
+	ErrNodeRetryWaitInvalid     = errors.New("node wait_between_tries must be between 0 and 300000 milliseconds")
+	ErrNodeTimeoutInvalid       = errors.New("node timeout must not be negative")
 	
 	// Connection errors
 	ErrConnectionNodesRequired = errors.New("connection source and target nodes are required")
@@ -0,0 +1,304 @@
+package workflow
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/google/uuid"
+	"github.com/jaydeep/go-n8n/internal/expression"
+)
+
+// Graph is a workflow compiled for execution: its nodes indexed by ID, the
+// enabled connections as adjacency lists, a topological order and the
+// parsed parameter expressions. Connections closing a cycle are kept apart
+// as loops. A graph is shared between executions and must not be modified.
+type Graph struct {
+	WorkflowID uuid.UUID
+	Version    int
+	// Order lists the node IDs so that every node comes after its parents
+	Order []string
+
+	nodes       map[string]*Node
+	outgoing    map[string][]Connection
+	incoming    map[string][]Connection
+	loops       map[string][]Connection
+	roots       []string
+	expressions map[string]map[string]*expression.Template
+}
+
+// Compile validates the workflow and builds its graph. Disabled connections
+// are left out of the graph. Connections closing a cycle loop back to the
+// node the cycle is entered by, which loops must accept by its type, as it
+// does looping nodes such as Split In Batches; other cycles return
+// ErrWorkflowCycleDetected. A nil loops accepts no cycle.
+func Compile(w *Workflow, loops func(nodeType string) bool) (*Graph, error) {
+	if err := w.Validate(); err != nil {
+		return nil, err
+	}
+
+	g := &Graph{
+		WorkflowID:  w.ID,
+		Version:     w.Version,
+		nodes:       make(map[string]*Node, len(w.Nodes)),
+		outgoing:    make(map[string][]Connection),
+		incoming:    make(map[string][]Connection),
+		loops:       make(map[string][]Connection),
+		expressions: make(map[string]map[string]*expression.Template),
+	}
+
+	nodes := make([]Node, len(w.Nodes))
+	copy(nodes, w.Nodes)
+	for i := range nodes {
+		n := &nodes[i]
+		if _, ok := g.nodes[n.ID]; ok {
+			return nil, fmt.Errorf("%w: %s", ErrNodeIDDuplicate, n.ID)
+		}
+		g.nodes[n.ID] = n
+
+		exprs := make(map[string]*expression.Template)
+		if err := collectExpressions(exprs, "", n.Parameters); err != nil {
+			return nil, fmt.Errorf("%w: node %s: %v", ErrNodeConfigInvalid, n.Name, err)
+		}
+		if len(exprs) > 0 {
+			g.expressions[n.ID] = exprs
+		}
+	}
+
+	seen := make(map[Connection]bool, len(w.Connections))
+	for _, c := range w.Connections {
+		if c.Data.Disabled {
+			continue
+		}
+		for _, id := range []string{c.Source.NodeID, c.Target.NodeID} {
+			if _, ok := g.nodes[id]; !ok {
+				return nil, fmt.Errorf("%w: unknown node %s", ErrConnectionInvalid, id)
+			}
+		}
+		if c.Source.Type == ConnectionTypeError && !g.nodes[c.Source.NodeID].ContinueOnFail {
+			return nil, fmt.Errorf("%w: error output of node %s, which does not continue on fail", ErrConnectionInvalid, c.Source.NodeID)
+		}
+		edge := Connection{Source: c.Source, Target: c.Target}
+		if seen[edge] {
+			return nil, ErrConnectionDuplicate
+		}
+		seen[edge] = true
+
+		g.outgoing[c.Source.NodeID] = append(g.outgoing[c.Source.NodeID], c)
+		g.incoming[c.Target.NodeID] = append(g.incoming[c.Target.NodeID], c)
+	}
+
+	g.separateLoops(nodes)
+	for _, n := range nodes {
+		if len(g.loops[n.ID]) > 0 && (loops == nil || !loops(n.Type)) {
+			return nil, fmt.Errorf("%w: through node %s, which does not loop", ErrWorkflowCycleDetected, n.Name)
+		}
+	}
+	if err := g.sort(nodes); err != nil {
+		return nil, err
+	}
+	return g, nil
+}
+
+// separateLoops moves the connections closing a cycle out of the adjacency
+// lists into loops. The nodes are walked depth first from those without
+// incoming connections, so the connection closing a cycle is the one going
+// back to the node the walk entered the cycle by.
+func (g *Graph) separateLoops(nodes []Node) {
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := make(map[string]int, len(nodes))
+	back := make(map[Connection]bool)
+	var visit func(id string)
+	visit = func(id string) {
+		state[id] = visiting
+		for _, c := range g.outgoing[id] {
+			switch state[c.Target.NodeID] {
+			case visiting:
+				back[c] = true
+			case unvisited:
+				visit(c.Target.NodeID)
+			}
+		}
+		state[id] = visited
+	}
+	for _, n := range nodes {
+		if len(g.incoming[n.ID]) == 0 && state[n.ID] == unvisited {
+			visit(n.ID)
+		}
+	}
+	// Cycles no node leads into are entered by their first node
+	for _, n := range nodes {
+		if state[n.ID] == unvisited {
+			visit(n.ID)
+		}
+	}
+	if len(back) == 0 {
+		return
+	}
+
+	forward := func(connections []Connection) []Connection {
+		kept := connections[:0:0]
+		for _, c := range connections {
+			if !back[c] {
+				kept = append(kept, c)
+			}
+		}
+		return kept
+	}
+	for id, connections := range g.outgoing {
+		g.outgoing[id] = forward(connections)
+	}
+	for id, connections := range g.incoming {
+		for _, c := range connections {
+			if back[c] {
+				g.loops[id] = append(g.loops[id], c)
+			}
+		}
+		g.incoming[id] = forward(connections)
+	}
+}
+
+// sort orders the nodes topologically, keeping the workflow's node order
+// among nodes that are ready at the same time
+func (g *Graph) sort(nodes []Node) error {
+	pending := make(map[string]int, len(nodes))
+	for _, n := range nodes {
+		pending[n.ID] = len(g.incoming[n.ID])
+		if pending[n.ID] == 0 {
+			g.roots = append(g.roots, n.ID)
+		}
+	}
+
+	g.Order = make([]string, 0, len(nodes))
+	queue := append([]string(nil), g.roots...)
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		g.Order = append(g.Order, id)
+		for _, c := range g.outgoing[id] {
+			pending[c.Target.NodeID]--
+			if pending[c.Target.NodeID] == 0 {
+				queue = append(queue, c.Target.NodeID)
+			}
+		}
+	}
+
+	if len(g.Order) != len(nodes) {
+		return ErrWorkflowCycleDetected
+	}
+	return nil
+}
+
+// collectExpressions parses the expressions in a parameter value, keyed by
+// the dotted path of the parameter, with list indexes as path segments
+func collectExpressions(exprs map[string]*expression.Template, path string, value interface{}) error {
+	switch v := value.(type) {
+	case string:
+		if !expression.HasExpression(v) {
+			return nil
+		}
+		t, err := expression.Parse(v)
+		if err != nil {
+			return fmt.Errorf("parameter %s: %w", path, err)
+		}
+		exprs[path] = t
+	case map[string]interface{}:
+		for k, item := range v {
+			if err := collectExpressions(exprs, joinPath(path, k), item); err != nil {
+				return err
+			}
+		}
+	case []interface{}:
+		for i, item := range v {
+			if err := collectExpressions(exprs, joinPath(path, strconv.Itoa(i)), item); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// Node returns the node with the given ID
+func (g *Graph) Node(id string) (*Node, bool) {
+	n, ok := g.nodes[id]
+	return n, ok
+}
+
+// Roots returns the IDs of the nodes without incoming connections, the
+// triggers and other starting points
+func (g *Graph) Roots() []string {
+	return g.roots
+}
+
+// Outgoing returns the connections leaving a node
+func (g *Graph) Outgoing(id string) []Connection {
+	return g.outgoing[id]
+}
+
+// Incoming returns the connections entering a node, except those looping
+// back to it
+func (g *Graph) Incoming(id string) []Connection {
+	return g.incoming[id]
+}
+
+// Loops returns the connections looping back to a node
+func (g *Graph) Loops(id string) []Connection {
+	return g.loops[id]
+}
+
+// HasLoops reports whether connections loop back to any node
+func (g *Graph) HasLoops() bool {
+	return len(g.loops) > 0
+}
+
+// HasErrorOutput reports whether a connection leaves the error output of a
+// node
+func (g *Graph) HasErrorOutput(id string) bool {
+	for _, c := range g.outgoing[id] {
+		if c.Source.Type == ConnectionTypeError {
+			return true
+		}
+	}
+	return false
+}
+
+// Expressions returns the parsed expressions in a node's parameters, keyed
+// by parameter path
+func (g *Graph) Expressions(id string) map[string]*expression.Template {
+	return g.expressions[id]
+}
+
+// Upstream returns the IDs of the nodes a node can read the output of, its
+// direct and indirect parents, in execution order
+func (g *Graph) Upstream(id string) []string {
+	ancestors := make(map[string]bool)
+	pending := []string{id}
+	for len(pending) > 0 {
+		current := pending[len(pending)-1]
+		pending = pending[:len(pending)-1]
+		for _, c := range g.incoming[current] {
+			if !ancestors[c.Source.NodeID] {
+				ancestors[c.Source.NodeID] = true
+				pending = append(pending, c.Source.NodeID)
+			}
+		}
+	}
+
+	upstream := make([]string, 0, len(ancestors))
+	for _, n := range g.Order {
+		if ancestors[n] {
+			upstream = append(upstream, n)
+		}
+	}
+	return upstream
+}
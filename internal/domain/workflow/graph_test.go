@@ -0,0 +1,124 @@
+package workflow
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// loopsBatches accepts loops back to batch nodes only
+func loopsBatches(nodeType string) bool {
+	return nodeType == "batch"
+}
+
+func connect(source string, index int, target string) Connection {
+	return Connection{
+		Source: ConnectionPoint{NodeID: source, Type: ConnectionTypeMain, Index: index},
+		Target: ConnectionPoint{NodeID: target, Type: ConnectionTypeMain},
+	}
+}
+
+func graphWorkflow(nodes [][2]string, connections ...Connection) *Workflow {
+	w := &Workflow{Name: "graph", Connections: connections}
+	for _, n := range nodes {
+		w.Nodes = append(w.Nodes, Node{ID: n[0], Name: n[0], Type: n[1]})
+	}
+	return w
+}
+
+func TestCompileLoops(t *testing.T) {
+	tests := []struct {
+		name     string
+		workflow *Workflow
+		loops    func(string) bool
+		err      error
+		order    []string
+		// loopsTo is the node the cycle loops back to, and loopFrom the
+		// node the connection looping back leaves
+		loopsTo, loopFrom string
+	}{
+		{
+			name: "loop back to a looping node",
+			workflow: graphWorkflow([][2]string{{"start", "trigger"}, {"split", "batch"}, {"body", "set"}, {"done", "set"}},
+				connect("start", 0, "split"), connect("split", 1, "body"), connect("body", 0, "split"), connect("split", 0, "done")),
+			loops:    loopsBatches,
+			order:    []string{"start", "split", "body", "done"},
+			loopsTo:  "split",
+			loopFrom: "body",
+		},
+		{
+			name: "cycle through a node that does not loop",
+			workflow: graphWorkflow([][2]string{{"start", "trigger"}, {"a", "set"}, {"b", "set"}},
+				connect("start", 0, "a"), connect("a", 0, "b"), connect("b", 0, "a")),
+			loops: loopsBatches,
+			err:   ErrWorkflowCycleDetected,
+		},
+		{
+			name: "cycle without loops accepted",
+			workflow: graphWorkflow([][2]string{{"start", "trigger"}, {"split", "batch"}, {"body", "set"}},
+				connect("start", 0, "split"), connect("split", 1, "body"), connect("body", 0, "split")),
+			err: ErrWorkflowCycleDetected,
+		},
+		{
+			name: "cycle without entry entered by its first node",
+			workflow: graphWorkflow([][2]string{{"split", "batch"}, {"body", "set"}},
+				connect("split", 1, "body"), connect("body", 0, "split")),
+			loops:    loopsBatches,
+			order:    []string{"split", "body"},
+			loopsTo:  "split",
+			loopFrom: "body",
+		},
+		{
+			name: "cycle without entry whose first node does not loop",
+			workflow: graphWorkflow([][2]string{{"body", "set"}, {"split", "batch"}},
+				connect("split", 1, "body"), connect("body", 0, "split")),
+			loops: loopsBatches,
+			err:   ErrWorkflowCycleDetected,
+		},
+		{
+			name: "no cycle",
+			workflow: graphWorkflow([][2]string{{"start", "trigger"}, {"a", "set"}},
+				connect("start", 0, "a")),
+			order: []string{"start", "a"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g, err := Compile(tt.workflow, tt.loops)
+			if tt.err != nil {
+				if !errors.Is(err, tt.err) {
+					t.Fatalf("Compile() error = %v, want %v", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Compile() error = %v", err)
+			}
+			if !reflect.DeepEqual(g.Order, tt.order) {
+				t.Errorf("Order = %v, want %v", g.Order, tt.order)
+			}
+			if tt.loopsTo == "" {
+				if g.HasLoops() {
+					t.Errorf("HasLoops() = true, want false")
+				}
+				return
+			}
+
+			loops := g.Loops(tt.loopsTo)
+			if len(loops) != 1 || loops[0].Source.NodeID != tt.loopFrom {
+				t.Fatalf("Loops(%s) = %v, want one from %s", tt.loopsTo, loops, tt.loopFrom)
+			}
+			for _, c := range g.Incoming(tt.loopsTo) {
+				if c.Source.NodeID == tt.loopFrom {
+					t.Errorf("Incoming(%s) holds the connection looping back", tt.loopsTo)
+				}
+			}
+			for _, c := range g.Outgoing(tt.loopFrom) {
+				if c.Target.NodeID == tt.loopsTo {
+					t.Errorf("Outgoing(%s) holds the connection looping back", tt.loopFrom)
+				}
+			}
+		})
+	}
+}
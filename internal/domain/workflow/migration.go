@@ -0,0 +1,111 @@
+package workflow
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MigrationPackageVersion is the format version of migration packages
+const MigrationPackageVersion = 1
+
+// Policies for migrated workflows that already exist on the target, by ID
+// or by name for the same owner
+const (
+	MigrationSkip      = "skip"
+	MigrationOverwrite = "overwrite"
+	MigrationRename    = "rename"
+)
+
+// Outcomes of migrating a workflow or credential stub
+const (
+	MigrationCreated = "created"
+	MigrationUpdated = "updated"
+	MigrationRenamed = "renamed"
+	MigrationSkipped = "skipped"
+	MigrationMatched = "matched"
+	MigrationMissing = "missing"
+)
+
+// MigrationPackage carries workflows, with their tags and variables, from
+// one instance to another. Credentials travel as stubs without secrets:
+// the target matches them to its own credentials or creates empty ones to
+// be filled in there.
+type MigrationPackage struct {
+	Version     int                  `json:"version"`
+	ExportedAt  time.Time            `json:"exported_at"`
+	Workflows   []MigratedWorkflow   `json:"workflows"`
+	Credentials []MigratedCredential `json:"credentials"`
+}
+
+// MigratedWorkflow is the draft of a workflow in a migration package.
+// Owners are identified by email, as user IDs differ between instances.
+type MigratedWorkflow struct {
+	ID          uuid.UUID              `json:"id"`
+	Name        string                 `json:"name"`
+	Description string                 `json:"description"`
+	OwnerEmail  string                 `json:"owner_email,omitempty"`
+	Nodes       []Node                 `json:"nodes"`
+	Connections []Connection           `json:"connections"`
+	Settings    WorkflowSettings       `json:"settings"`
+	Tags        []string               `json:"tags"`
+	Variables   map[string]interface{} `json:"variables"`
+}
+
+// MigratedCredential is a stub of a credential the nodes of a migrated
+// workflow reference
+type MigratedCredential struct {
+	ID         uuid.UUID `json:"id"`
+	Name       string    `json:"name"`
+	Type       string    `json:"type"`
+	OwnerEmail string    `json:"owner_email,omitempty"`
+	NodeTypes  []string  `json:"node_types"`
+}
+
+// MigrationRequest asks an instance to import a migration package
+type MigrationRequest struct {
+	Package *MigrationPackage `json:"package"`
+	// OwnerID owns the workflows and credentials whose owner email matches
+	// no user of the instance; it defaults to the importing user
+	OwnerID *uuid.UUID `json:"owner_id,omitempty"`
+	// OnConflict is skip, the default, overwrite or rename
+	OnConflict string `json:"on_conflict,omitempty"`
+	// DryRun reports what the import would do without storing anything
+	DryRun bool `json:"dry_run,omitempty"`
+	// Publish publishes the imported workflows, or requests approval for
+	// those that require it
+	Publish bool `json:"publish,omitempty"`
+}
+
+// MigrationResult describes what an import did with each workflow and
+// credential stub, mapping their IDs on the source to those on the target
+type MigrationResult struct {
+	DryRun      bool               `json:"dry_run"`
+	Workflows   []MigrationOutcome `json:"workflows"`
+	Credentials []MigrationOutcome `json:"credentials"`
+	Warnings    []string           `json:"warnings"`
+}
+
+// MigrationOutcome is the outcome of migrating one workflow or credential
+type MigrationOutcome struct {
+	SourceID uuid.UUID `json:"source_id"`
+	// ID is the ID on the target, nil for missing credentials
+	ID     *uuid.UUID `json:"id,omitempty"`
+	Name   string     `json:"name"`
+	Action string     `json:"action"`
+	Reason string     `json:"reason,omitempty"`
+	// PublishedVersion and ChangeRequestID report the outcome of publishing
+	// an imported workflow, PublishError why it could not be published
+	PublishedVersion *int       `json:"published_version,omitempty"`
+	ChangeRequestID  *uuid.UUID `json:"change_request_id,omitempty"`
+	PublishError     string     `json:"publish_error,omitempty"`
+}
+
+// MigrationTarget is another instance migration packages are pushed to
+type MigrationTarget interface {
+	Name() string
+	// URL is the base URL of the instance
+	URL() string
+	Import(ctx context.Context, request *MigrationRequest) (*MigrationResult, error)
+}
@@ -0,0 +1,52 @@
+package workflow
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jaydeep/go-n8n/internal/domain/node"
+)
+
+const (
+	// MaxPinnedItems bounds the items pinned to a node
+	MaxPinnedItems = 1000
+	// MaxPinnedDataSize bounds the items pinned to a node, in bytes of JSON
+	MaxPinnedDataSize = 1 << 20
+)
+
+// PinnedData is the output pinned to a node of a workflow. Test executions
+// output the pinned items in place of running the node, so that workflows
+// can be iterated on without waiting for triggers or calling the services
+// behind HTTP nodes. Other executions ignore them.
+type PinnedData struct {
+	WorkflowID uuid.UUID   `json:"workflow_id" gorm:"type:uuid;primaryKey"`
+	NodeID     string      `json:"node_id" gorm:"primaryKey"`
+	Items      []node.Item `json:"items" gorm:"serializer:json;not null"`
+	PinnedBy   *uuid.UUID  `json:"pinned_by,omitempty" gorm:"type:uuid"`
+	CreatedAt  time.Time   `json:"created_at"`
+	UpdatedAt  time.Time   `json:"updated_at"`
+}
+
+// TableName returns the table name for pinned node data
+func (PinnedData) TableName() string {
+	return "pinned_data"
+}
+
+// ValidatePinnedItems checks the number and size of the items to pin
+func ValidatePinnedItems(items []node.Item) error {
+	if len(items) == 0 {
+		return ErrPinnedItemsRequired
+	}
+	if len(items) > MaxPinnedItems {
+		return ErrPinnedDataTooLarge
+	}
+	data, err := json.Marshal(items)
+	if err != nil {
+		return err
+	}
+	if len(data) > MaxPinnedDataSize {
+		return ErrPinnedDataTooLarge
+	}
+	return nil
+}
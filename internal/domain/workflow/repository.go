@@ -0,0 +1,70 @@
+package workflow
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// Repository defines persistence operations for workflows
+type Repository interface {
+	Create(ctx context.Context, workflow *Workflow) error
+	Update(ctx context.Context, workflow *Workflow) error
+	FindByID(ctx context.Context, id uuid.UUID) (*Workflow, error)
+	FindByIDs(ctx context.Context, ids []uuid.UUID) ([]*Workflow, error)
+
+	// FindByName returns the non-deleted workflow of a user with the name
+	FindByName(ctx context.Context, userID uuid.UUID, name string) (*Workflow, error)
+
+	// FindBySlug returns the non-deleted workflow with the slug
+	FindBySlug(ctx context.Context, slug string) (*Workflow, error)
+
+	// Delete soft deletes a workflow
+	Delete(ctx context.Context, id uuid.UUID) error
+
+	// List returns workflows owned by userID, or all workflows when userID
+	// is nil, most recently updated first
+	List(ctx context.Context, userID *uuid.UUID, limit, offset int) ([]*Workflow, error)
+}
+
+// VersionRepository defines persistence operations for published versions
+type VersionRepository interface {
+	Create(ctx context.Context, version *WorkflowVersion) error
+	FindByVersion(ctx context.Context, workflowID uuid.UUID, version int) (*WorkflowVersion, error)
+	ListByWorkflow(ctx context.Context, workflowID uuid.UUID) ([]*WorkflowVersion, error)
+}
+
+// ChangeRequestRepository defines persistence operations for change requests
+type ChangeRequestRepository interface {
+	Create(ctx context.Context, request *ChangeRequest) error
+	Update(ctx context.Context, request *ChangeRequest) error
+	FindByID(ctx context.Context, id uuid.UUID) (*ChangeRequest, error)
+	FindPending(ctx context.Context, workflowID uuid.UUID) (*ChangeRequest, error)
+	ListByWorkflow(ctx context.Context, workflowID uuid.UUID) ([]*ChangeRequest, error)
+}
+
+// SecretRepository defines persistence operations for workflow secrets
+type SecretRepository interface {
+	Create(ctx context.Context, secret *Secret) error
+	Update(ctx context.Context, secret *Secret) error
+	FindByName(ctx context.Context, workflowID uuid.UUID, name string) (*Secret, error)
+
+	// ListByWorkflow returns the secrets of a workflow ordered by name
+	ListByWorkflow(ctx context.Context, workflowID uuid.UUID) ([]*Secret, error)
+
+	// Delete removes a secret of a workflow
+	Delete(ctx context.Context, workflowID uuid.UUID, name string) error
+}
+
+// PinnedDataRepository defines persistence operations for pinned node data
+type PinnedDataRepository interface {
+	// Save creates or replaces the pinned data of a node
+	Save(ctx context.Context, data *PinnedData) error
+
+	// ListByWorkflow returns the pinned data of the nodes of a workflow
+	// ordered by node ID
+	ListByWorkflow(ctx context.Context, workflowID uuid.UUID) ([]*PinnedData, error)
+
+	// Delete removes the pinned data of a node
+	Delete(ctx context.Context, workflowID uuid.UUID, nodeID string) error
+}
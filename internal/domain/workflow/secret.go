@@ -0,0 +1,57 @@
+package workflow
+
+import (
+	"regexp"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// SecretMask stands in for secret values in API responses
+	SecretMask = "********"
+	// MaxSecretSize bounds a secret value in bytes
+	MaxSecretSize = 8 << 10
+	// MaxSecrets bounds the secrets of a workflow
+	MaxSecrets = 50
+)
+
+// secretNamePattern keeps names usable as $secrets.NAME in expressions
+var secretNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]{0,63}$`)
+
+// Secret is a value of a single workflow, such as a signing key, that its
+// expressions reference as $secrets.NAME without setting up a credential.
+// The value is stored encrypted and only ever returned masked. Secrets
+// stay with their workflow: duplicates and exports of the workflow do not
+// carry them.
+type Secret struct {
+	ID         uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	WorkflowID uuid.UUID `json:"workflow_id" gorm:"type:uuid;not null"`
+	Name       string    `json:"name" gorm:"not null"`
+	Data       []byte    `json:"-" gorm:"not null"`
+	IV         []byte    `json:"-" gorm:"column:iv;not null"`
+	// Value is always SecretMask
+	Value     string     `json:"value" gorm:"-"`
+	UpdatedBy *uuid.UUID `json:"updated_by,omitempty" gorm:"type:uuid"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
+// TableName returns the table name for workflow secrets
+func (Secret) TableName() string {
+	return "workflow_secrets"
+}
+
+// ValidateSecret checks the name and size of a secret
+func ValidateSecret(name, value string) error {
+	if !secretNamePattern.MatchString(name) {
+		return ErrSecretNameInvalid
+	}
+	if value == "" {
+		return ErrSecretValueRequired
+	}
+	if len(value) > MaxSecretSize {
+		return ErrSecretTooLarge
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package workflow
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// WorkflowVersion is an immutable snapshot of a published workflow
+type WorkflowVersion struct {
+	ID          uuid.UUID        `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	WorkflowID  uuid.UUID        `json:"workflow_id" gorm:"type:uuid;not null"`
+	Version     int              `json:"version" gorm:"not null"`
+	Name        string           `json:"name"`
+	Nodes       []Node           `json:"nodes" gorm:"serializer:json"`
+	Connections []Connection     `json:"connections" gorm:"serializer:json"`
+	Settings    WorkflowSettings `json:"settings" gorm:"serializer:json"`
+	PublishedBy uuid.UUID        `json:"published_by" gorm:"type:uuid"`
+	CreatedAt   time.Time        `json:"created_at"`
+}
+
+// NewVersionSnapshot captures the current draft of a workflow
+func NewVersionSnapshot(w *Workflow, publishedBy uuid.UUID) *WorkflowVersion {
+	v := &WorkflowVersion{
+		WorkflowID:  w.ID,
+		Version:     w.Version,
+		Name:        w.Name,
+		Nodes:       make([]Node, len(w.Nodes)),
+		Connections: make([]Connection, len(w.Connections)),
+		Settings:    w.Settings,
+		PublishedBy: publishedBy,
+		CreatedAt:   time.Now(),
+	}
+	copy(v.Nodes, w.Nodes)
+	copy(v.Connections, w.Connections)
+	return v
+}
+
+// Apply returns a copy of the workflow with the snapshot's graph, which is
+// what triggers and the engine execute for active workflows
+func (v *WorkflowVersion) Apply(w *Workflow) *Workflow {
+	published := *w
+	published.Version = v.Version
+	published.Nodes = v.Nodes
+	published.Connections = v.Connections
+	published.Settings = v.Settings
+	return &published
+}
@@ -0,0 +1,145 @@
+package expression
+
+import (
+	"context"
+	"strings"
+	"sync"
+)
+
+const defaultCacheResults = 100000
+
+// volatileTokens mark expressions whose value changes between evaluations,
+// which are never cached
+var volatileTokens = []string{"$now", "$today", "Date.now", "new Date", "Math.random", "$uuid", "DateTime.now"}
+
+// Volatile reports whether an expression may evaluate differently each time
+func Volatile(expr string) bool {
+	for _, token := range volatileTokens {
+		if strings.Contains(expr, token) {
+			return true
+		}
+	}
+	return false
+}
+
+// resultKey identifies an expression evaluated for one item of a node run
+type resultKey struct {
+	node string
+	run  int
+	item int
+	expr string
+}
+
+type result struct {
+	value interface{}
+	err   error
+}
+
+// Cache memoizes parsed templates and expression results within a single
+// execution. Parameters referencing other nodes' output are evaluated for
+// every item, often several times, so each expression is resolved once per
+// node run and item. Cached values are shared and must not be modified.
+type Cache struct {
+	maxResults int
+
+	mu        sync.Mutex
+	templates map[string]*Template
+	results   map[resultKey]result
+	hits      int64
+	misses    int64
+}
+
+// NewCache creates a cache for one execution holding up to maxResults
+// expression results; further results are evaluated without being cached
+func NewCache(maxResults int) *Cache {
+	if maxResults <= 0 {
+		maxResults = defaultCacheResults
+	}
+	return &Cache{
+		maxResults: maxResults,
+		templates:  make(map[string]*Template),
+		results:    make(map[resultKey]result),
+	}
+}
+
+// Template parses s once per execution. A nil cache parses every time.
+func (c *Cache) Template(s string) (*Template, error) {
+	if c == nil {
+		return Parse(s)
+	}
+
+	c.mu.Lock()
+	t, ok := c.templates[s]
+	c.mu.Unlock()
+	if ok {
+		return t, nil
+	}
+
+	t, err := Parse(s)
+	if err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	c.templates[s] = t
+	c.mu.Unlock()
+	return t, nil
+}
+
+// Resolve returns the result of expr for an item of a node run, calling
+// eval on first use. Volatile expressions, and all expressions on a nil
+// cache, are evaluated every time.
+func (c *Cache) Resolve(node string, run, item int, expr string, eval func() (interface{}, error)) (interface{}, error) {
+	if c == nil || Volatile(expr) {
+		return eval()
+	}
+
+	key := resultKey{node: node, run: run, item: item, expr: expr}
+	c.mu.Lock()
+	if r, ok := c.results[key]; ok {
+		c.hits++
+		c.mu.Unlock()
+		return r.value, r.err
+	}
+	c.misses++
+	c.mu.Unlock()
+
+	value, err := eval()
+
+	c.mu.Lock()
+	if len(c.results) < c.maxResults {
+		c.results[key] = result{value: value, err: err}
+	}
+	c.mu.Unlock()
+	return value, err
+}
+
+// Evaluate evaluates a template for an item of a node run, resolving each
+// of its expressions once per item. A nil cache evaluates every time.
+func (c *Cache) Evaluate(t *Template, node string, run, item int, data *Data) (interface{}, error) {
+	return t.evaluate(func(seg Segment) (interface{}, error) {
+		return c.Resolve(node, run, item, seg.Text, func() (interface{}, error) {
+			return seg.evaluate(data)
+		})
+	})
+}
+
+// Stats returns the number of cached and evaluated results
+func (c *Cache) Stats() (hits, misses int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.hits, c.misses
+}
+
+type cacheKey struct{}
+
+// NewContext returns a context carrying the execution's expression cache
+func NewContext(ctx context.Context, c *Cache) context.Context {
+	return context.WithValue(ctx, cacheKey{}, c)
+}
+
+// CacheFrom returns the expression cache from ctx, or nil when expressions
+// are evaluated without caching
+func CacheFrom(ctx context.Context) *Cache {
+	c, _ := ctx.Value(cacheKey{}).(*Cache)
+	return c
+}
@@ -0,0 +1,385 @@
+package expression
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+// Item is an item of an execution as expressions see it
+type Item struct {
+	JSON   map[string]interface{}
+	Binary map[string]interface{}
+}
+
+// ExecutionInfo describes the execution expressions are evaluated in
+type ExecutionInfo struct {
+	ID            string
+	Mode          string
+	CorrelationID string
+}
+
+// WorkflowInfo describes the workflow expressions are evaluated in
+type WorkflowInfo struct {
+	ID     string
+	Name   string
+	Active bool
+}
+
+// Data is what the expressions of a node read when evaluated for one of
+// its input items
+type Data struct {
+	// Input holds the input items of the node; the one at ItemIndex is
+	// $json
+	Input     []Item
+	ItemIndex int
+	RunIndex  int
+	// Node returns the output items of the node of the workflow with a
+	// name, false when the node has not run
+	Node func(name string) ([]Item, bool)
+	// Paired returns the output item of the node with a name that the
+	// input item at index was made from, following the pairing of the
+	// items in between. Without it items are paired by index.
+	Paired    func(name string, index int) (Item, error)
+	Vars      map[string]interface{}
+	Secrets   map[string]string
+	Env       map[string]string
+	Execution ExecutionInfo
+	Workflow  WorkflowInfo
+	// Now is the time $now reads, the current time when zero
+	Now time.Time
+	// Location is the timezone of dates, UTC when nil
+	Location *time.Location
+}
+
+// item returns the current input item
+func (d *Data) item() Item {
+	if d.ItemIndex >= 0 && d.ItemIndex < len(d.Input) {
+		return d.Input[d.ItemIndex]
+	}
+	return Item{}
+}
+
+// nodeIndex is the value of $node, whose properties are the items of the
+// nodes of the workflow, by name, paired with the input item at item
+type nodeIndex struct {
+	item int
+}
+
+// nodeOutput is the value of $("Name"): the output items of a node, read
+// as item, paired with the input item at index, or as all(), first() and
+// last()
+type nodeOutput struct {
+	name  string
+	items []Item
+	index int
+}
+
+// itemObject returns an item as the object expressions read, with its
+// json and binary properties
+func itemObject(item Item) map[string]interface{} {
+	data := item.JSON
+	if data == nil {
+		data = map[string]interface{}{}
+	}
+	binary := item.Binary
+	if binary == nil {
+		binary = map[string]interface{}{}
+	}
+	return map[string]interface{}{"json": data, "binary": binary}
+}
+
+// itemList returns a function returning the items of a list as objects:
+// all of them, the first or the last
+func itemList(items []Item, which string) function {
+	return func(*evaluator, []interface{}) (interface{}, error) {
+		switch which {
+		case "first":
+			if len(items) == 0 {
+				return undefined, nil
+			}
+			return itemObject(items[0]), nil
+		case "last":
+			if len(items) == 0 {
+				return undefined, nil
+			}
+			return itemObject(items[len(items)-1]), nil
+		}
+		out := make([]interface{}, len(items))
+		for i, item := range items {
+			out[i] = itemObject(item)
+		}
+		return out, nil
+	}
+}
+
+// nodeItems returns the output items of an upstream node
+func (e *evaluator) nodeItems(name string) ([]Item, error) {
+	if e.data.Node != nil {
+		if items, ok := e.data.Node(name); ok {
+			return items, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: node %q has not run before this one", ErrEvaluation, name)
+}
+
+// pairedItem returns the item of an upstream node the input item at index
+// was made from. Without Paired it is the item at the same index, or its
+// last item when it output fewer.
+func (e *evaluator) pairedItem(name string, items []Item, index int) (interface{}, error) {
+	if e.data.Paired != nil {
+		item, err := e.data.Paired(name, index)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrEvaluation, err)
+		}
+		return itemObject(item), nil
+	}
+	switch {
+	case len(items) == 0:
+		return undefined, nil
+	case index < len(items):
+		return itemObject(items[index]), nil
+	}
+	return itemObject(items[len(items)-1]), nil
+}
+
+// inputItem returns the input item at index as $item(index) does: an
+// object reading it as the current item
+func (d *Data) inputItem(index int) map[string]interface{} {
+	var item Item
+	if index >= 0 && index < len(d.Input) {
+		item = d.Input[index]
+	}
+	object := itemObject(item)
+	return map[string]interface{}{
+		"$json":      object["json"],
+		"$binary":    object["binary"],
+		"$node":      nodeIndex{item: index},
+		"$itemIndex": float64(index),
+	}
+}
+
+// method returns all(), first() or last() of the output of a node
+func (o nodeOutput) method(name string) function {
+	switch name {
+	case "all", "first", "last":
+		return itemList(o.items, name)
+	}
+	return nil
+}
+
+// root returns the value of a root symbol or global
+func (e *evaluator) root(name string) (interface{}, bool) {
+	d := e.data
+	switch name {
+	case "$json":
+		return itemObject(d.item())["json"], true
+	case "$binary":
+		return itemObject(d.item())["binary"], true
+	case "$input":
+		return map[string]interface{}{
+			"item":  itemObject(d.item()),
+			"all":   itemList(d.Input, "all"),
+			"first": itemList(d.Input, "first"),
+			"last":  itemList(d.Input, "last"),
+		}, true
+	case "$node":
+		return nodeIndex{item: d.ItemIndex}, true
+	case "$":
+		return function(func(e *evaluator, args []interface{}) (interface{}, error) {
+			name := toString(arg(args, 0))
+			items, err := e.nodeItems(name)
+			if err != nil {
+				return nil, err
+			}
+			return nodeOutput{name: name, items: items, index: e.data.ItemIndex}, nil
+		}), true
+	case "$item":
+		return function(func(e *evaluator, args []interface{}) (interface{}, error) {
+			return e.data.inputItem(integer(arg(args, 0), 0)), nil
+		}), true
+	case "$vars":
+		return mapOrEmpty(d.Vars), true
+	case "$secrets":
+		return stringMap(d.Secrets), true
+	case "$env":
+		return stringMap(d.Env), true
+	case "$execution":
+		return map[string]interface{}{
+			"id":            d.Execution.ID,
+			"mode":          d.Execution.Mode,
+			"correlationId": d.Execution.CorrelationID,
+		}, true
+	case "$workflow":
+		return map[string]interface{}{
+			"id":     d.Workflow.ID,
+			"name":   d.Workflow.Name,
+			"active": d.Workflow.Active,
+		}, true
+	case "$runIndex":
+		return float64(d.RunIndex), true
+	case "$itemIndex":
+		return float64(d.ItemIndex), true
+	case "$now":
+		return e.now(), true
+	case "$today":
+		return startOf(e.now(), "day"), true
+	case "$uuid":
+		return function(func(*evaluator, []interface{}) (interface{}, error) {
+			return uuid.NewString(), nil
+		}), true
+	case "$ifEmpty":
+		return function(func(_ *evaluator, args []interface{}) (interface{}, error) {
+			if empty(arg(args, 0)) {
+				return arg(args, 1), nil
+			}
+			return arg(args, 0), nil
+		}), true
+	}
+	v, ok := globals[name]
+	return v, ok
+}
+
+func mapOrEmpty(m map[string]interface{}) map[string]interface{} {
+	if m == nil {
+		return map[string]interface{}{}
+	}
+	return m
+}
+
+func stringMap(m map[string]string) map[string]interface{} {
+	out := make(map[string]interface{}, len(m))
+	for k, v := range m {
+		out[k] = v
+	}
+	return out
+}
+
+// empty reports whether a value is null, undefined, an empty string, array
+// or object
+func empty(v interface{}) bool {
+	switch v := v.(type) {
+	case nil, undefinedValue:
+		return true
+	case string:
+		return v == ""
+	case []interface{}:
+		return len(v) == 0
+	case map[string]interface{}:
+		return len(v) == 0
+	}
+	return false
+}
+
+// arg returns the argument at index i, undefined when missing
+func arg(args []interface{}, i int) interface{} {
+	if i < len(args) {
+		return args[i]
+	}
+	return undefined
+}
+
+// property reads a property of a value
+func (e *evaluator) property(object, key interface{}) (interface{}, error) {
+	name := toString(key)
+	switch o := object.(type) {
+	case nil, undefinedValue:
+		return nil, fmt.Errorf("%w: cannot read property %q of %s", ErrEvaluation, name, toString(o))
+	case map[string]interface{}:
+		if v, ok := o[name]; ok {
+			return v, nil
+		}
+		return undefined, nil
+	case nodeIndex:
+		items, err := e.nodeItems(name)
+		if err != nil {
+			return nil, err
+		}
+		return e.pairedItem(name, items, o.item)
+	case nodeOutput:
+		if name == "item" {
+			return e.pairedItem(o.name, o.items, o.index)
+		}
+		if fn := o.method(name); fn != nil {
+			return fn, nil
+		}
+	case []interface{}:
+		if name == "length" {
+			return float64(len(o)), nil
+		}
+		if i, ok := index(key, len(o)); ok {
+			return o[i], nil
+		}
+	case string:
+		if name == "length" {
+			return float64(utf8.RuneCountInString(o)), nil
+		}
+		runes := []rune(o)
+		if i, ok := index(key, len(runes)); ok {
+			return string(runes[i]), nil
+		}
+	case time.Time:
+		if v, ok := dateProperty(o, name); ok {
+			return v, nil
+		}
+	}
+	return undefined, nil
+}
+
+// index returns the array index a key names, if it is one within length
+func index(key interface{}, length int) (int, bool) {
+	var n float64
+	switch k := key.(type) {
+	case float64:
+		n = k
+	case string:
+		i, err := strconv.Atoi(k)
+		if err != nil {
+			return 0, false
+		}
+		n = float64(i)
+	default:
+		return 0, false
+	}
+	if n != math.Trunc(n) || n < 0 || n >= float64(length) {
+		return 0, false
+	}
+	return int(n), true
+}
+
+// method returns the function a call of a property of a value calls
+func (e *evaluator) method(object, key interface{}) (interface{}, error) {
+	name := toString(key)
+	var fn function
+	switch o := object.(type) {
+	case nil, undefinedValue:
+		return nil, fmt.Errorf("%w: cannot read property %q of %s", ErrEvaluation, name, toString(o))
+	case map[string]interface{}:
+		if v, ok := o[name]; ok {
+			return v, nil
+		}
+		fn = objectMethod(o, name)
+	case string:
+		fn = stringMethod(o, name)
+	case []interface{}:
+		fn = arrayMethod(o, name)
+	case float64:
+		fn = numberMethod(o, name)
+	case bool:
+		if name == "toString" {
+			fn = func(*evaluator, []interface{}) (interface{}, error) { return toString(o), nil }
+		}
+	case time.Time:
+		fn = dateMethod(o, name)
+	case nodeOutput:
+		fn = o.method(name)
+	}
+	if fn == nil {
+		return nil, fmt.Errorf("%w: %s is not a function", ErrEvaluation, name)
+	}
+	return fn, nil
+}
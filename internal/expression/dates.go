@@ -0,0 +1,395 @@
+package expression
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// formatISO formats a date as Luxon's toISO does
+func formatISO(t time.Time) string {
+	return t.Format("2006-01-02T15:04:05.000Z07:00")
+}
+
+// dateLayouts lists the layouts toDate parses strings with
+var dateLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05.000Z07:00",
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04",
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+	time.RFC1123Z,
+	time.RFC1123,
+}
+
+// toDate converts a value to a date: dates as they are, numbers as
+// milliseconds since the epoch, strings as ISO 8601. Strings without an
+// offset are read in loc.
+func toDate(v interface{}, loc *time.Location) (time.Time, error) {
+	switch v := v.(type) {
+	case time.Time:
+		return v.In(loc), nil
+	case float64:
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return time.Time{}, fmt.Errorf("%w: invalid date", ErrEvaluation)
+		}
+		return time.UnixMilli(int64(v)).In(loc), nil
+	case string:
+		s := strings.TrimSpace(v)
+		for _, layout := range dateLayouts {
+			if t, err := time.ParseInLocation(layout, s, loc); err == nil {
+				return t.In(loc), nil
+			}
+		}
+	}
+	return time.Time{}, fmt.Errorf("%w: invalid date %s", ErrEvaluation, toString(v))
+}
+
+// unit normalizes the name of a date unit: "days", "day" and "d" are day
+func unit(name string) string {
+	name = strings.ToLower(strings.TrimSpace(name))
+	switch name {
+	case "y", "years":
+		return "year"
+	case "q", "quarters":
+		return "quarter"
+	case "months":
+		return "month"
+	case "w", "weeks":
+		return "week"
+	case "d", "days":
+		return "day"
+	case "h", "hours":
+		return "hour"
+	case "m", "minutes":
+		return "minute"
+	case "s", "seconds":
+		return "second"
+	case "ms", "milliseconds":
+		return "millisecond"
+	}
+	return name
+}
+
+// startOf returns the start of the unit a date is in
+func startOf(t time.Time, u string) time.Time {
+	y, mo, d := t.Date()
+	loc := t.Location()
+	switch unit(u) {
+	case "year":
+		return time.Date(y, time.January, 1, 0, 0, 0, 0, loc)
+	case "quarter":
+		return time.Date(y, mo-(mo-1)%3, 1, 0, 0, 0, 0, loc)
+	case "month":
+		return time.Date(y, mo, 1, 0, 0, 0, 0, loc)
+	case "week":
+		// Weeks start on Monday, as in Luxon
+		offset := (int(t.Weekday()) + 6) % 7
+		return time.Date(y, mo, d-offset, 0, 0, 0, 0, loc)
+	case "day":
+		return time.Date(y, mo, d, 0, 0, 0, 0, loc)
+	case "hour":
+		return time.Date(y, mo, d, t.Hour(), 0, 0, 0, loc)
+	case "minute":
+		return time.Date(y, mo, d, t.Hour(), t.Minute(), 0, 0, loc)
+	case "second":
+		return time.Date(y, mo, d, t.Hour(), t.Minute(), t.Second(), 0, loc)
+	}
+	return t
+}
+
+// endOf returns the last millisecond of the unit a date is in
+func endOf(t time.Time, u string) time.Time {
+	start := startOf(t, u)
+	var next time.Time
+	switch unit(u) {
+	case "year":
+		next = start.AddDate(1, 0, 0)
+	case "quarter":
+		next = start.AddDate(0, 3, 0)
+	case "month":
+		next = start.AddDate(0, 1, 0)
+	case "week":
+		next = start.AddDate(0, 0, 7)
+	case "day":
+		next = start.AddDate(0, 0, 1)
+	case "hour":
+		next = start.Add(time.Hour)
+	case "minute":
+		next = start.Add(time.Minute)
+	case "second":
+		next = start.Add(time.Second)
+	default:
+		return t
+	}
+	return next.Add(-time.Millisecond)
+}
+
+// shift adds n of a unit to a date
+func shift(t time.Time, n float64, u string) (time.Time, error) {
+	whole := int(n)
+	switch unit(u) {
+	case "year":
+		return t.AddDate(whole, 0, 0), nil
+	case "quarter":
+		return t.AddDate(0, 3*whole, 0), nil
+	case "month":
+		return t.AddDate(0, whole, 0), nil
+	case "week":
+		return t.AddDate(0, 0, 7*whole), nil
+	case "day":
+		return t.AddDate(0, 0, whole), nil
+	case "hour":
+		return t.Add(time.Duration(n * float64(time.Hour))), nil
+	case "minute":
+		return t.Add(time.Duration(n * float64(time.Minute))), nil
+	case "second":
+		return t.Add(time.Duration(n * float64(time.Second))), nil
+	case "millisecond":
+		return t.Add(time.Duration(n * float64(time.Millisecond))), nil
+	}
+	return time.Time{}, fmt.Errorf("%w: unknown date unit %q", ErrEvaluation, u)
+}
+
+// dateProperty reads a property of a date, named as in Luxon
+func dateProperty(t time.Time, name string) (interface{}, bool) {
+	switch name {
+	case "year":
+		return float64(t.Year()), true
+	case "quarter":
+		return float64((int(t.Month())-1)/3 + 1), true
+	case "month":
+		return float64(t.Month()), true
+	case "day":
+		return float64(t.Day()), true
+	case "hour":
+		return float64(t.Hour()), true
+	case "minute":
+		return float64(t.Minute()), true
+	case "second":
+		return float64(t.Second()), true
+	case "millisecond":
+		return float64(t.Nanosecond() / int(time.Millisecond)), true
+	case "weekday":
+		// 1 is Monday and 7 Sunday
+		return float64((int(t.Weekday())+6)%7 + 1), true
+	case "ordinal":
+		return float64(t.YearDay()), true
+	case "weekNumber":
+		_, week := t.ISOWeek()
+		return float64(week), true
+	case "zoneName":
+		return t.Location().String(), true
+	case "offset":
+		_, offset := t.Zone()
+		return float64(offset / 60), true
+	case "daysInMonth":
+		return float64(time.Date(t.Year(), t.Month()+1, 0, 0, 0, 0, 0, time.UTC).Day()), true
+	case "isValid":
+		return true, true
+	}
+	return nil, false
+}
+
+// dateMethod returns a method of a date. Dates offer the methods of Luxon's
+// DateTime expressions commonly use.
+func dateMethod(t time.Time, name string) function {
+	switch name {
+	case "toISO", "toISOString", "toJSON", "toString":
+		return func(*evaluator, []interface{}) (interface{}, error) { return formatISO(t), nil }
+	case "toISODate":
+		return func(*evaluator, []interface{}) (interface{}, error) { return t.Format("2006-01-02"), nil }
+	case "toMillis", "valueOf", "getTime":
+		return func(*evaluator, []interface{}) (interface{}, error) { return float64(t.UnixMilli()), nil }
+	case "toSeconds", "toUnixInteger":
+		return func(*evaluator, []interface{}) (interface{}, error) {
+			if name == "toUnixInteger" {
+				return float64(t.Unix()), nil
+			}
+			return float64(t.UnixMilli()) / 1000, nil
+		}
+	case "toFormat":
+		return func(_ *evaluator, args []interface{}) (interface{}, error) {
+			return formatDate(t, toString(arg(args, 0))), nil
+		}
+	case "plus", "minus":
+		return func(_ *evaluator, args []interface{}) (interface{}, error) {
+			sign := 1.0
+			if name == "minus" {
+				sign = -1
+			}
+			amounts, ok := arg(args, 0).(map[string]interface{})
+			if !ok {
+				amounts = map[string]interface{}{toString(arg(args, 1)): arg(args, 0)}
+			}
+			out := t
+			for u, n := range amounts {
+				var err error
+				if out, err = shift(out, sign*toNumber(normalize(n)), u); err != nil {
+					return nil, err
+				}
+			}
+			return out, nil
+		}
+	case "startOf":
+		return func(_ *evaluator, args []interface{}) (interface{}, error) {
+			return startOf(t, toString(arg(args, 0))), nil
+		}
+	case "endOf":
+		return func(_ *evaluator, args []interface{}) (interface{}, error) {
+			return endOf(t, toString(arg(args, 0))), nil
+		}
+	case "setZone":
+		return func(_ *evaluator, args []interface{}) (interface{}, error) {
+			zone := toString(arg(args, 0))
+			if strings.EqualFold(zone, "utc") {
+				return t.UTC(), nil
+			}
+			loc, err := time.LoadLocation(zone)
+			if err != nil {
+				return nil, fmt.Errorf("%w: unknown timezone %q", ErrEvaluation, zone)
+			}
+			return t.In(loc), nil
+		}
+	case "toUTC":
+		return func(*evaluator, []interface{}) (interface{}, error) { return t.UTC(), nil }
+	case "diff":
+		return func(_ *evaluator, args []interface{}) (interface{}, error) {
+			other, err := toDate(arg(args, 0), t.Location())
+			if err != nil {
+				return nil, err
+			}
+			d := t.Sub(other)
+			switch unit(toString(arg(args, 1))) {
+			case "week":
+				return d.Hours() / (24 * 7), nil
+			case "day":
+				return d.Hours() / 24, nil
+			case "hour":
+				return d.Hours(), nil
+			case "minute":
+				return d.Minutes(), nil
+			case "second":
+				return d.Seconds(), nil
+			}
+			return float64(d.Milliseconds()), nil
+		}
+	}
+	return nil
+}
+
+// formatDate formats a date with the tokens of Luxon's toFormat. Text in
+// single quotes is copied as is.
+func formatDate(t time.Time, format string) string {
+	var out strings.Builder
+	for i := 0; i < len(format); {
+		c := format[i]
+		if c == '\'' {
+			end := strings.IndexByte(format[i+1:], '\'')
+			if end < 0 {
+				out.WriteString(format[i+1:])
+				break
+			}
+			out.WriteString(format[i+1 : i+1+end])
+			i += end + 2
+			continue
+		}
+		n := 1
+		for i+n < len(format) && format[i+n] == c {
+			n++
+		}
+		out.WriteString(dateToken(t, c, n))
+		i += n
+	}
+	return out.String()
+}
+
+// dateToken formats a run of n times the token letter c
+func dateToken(t time.Time, c byte, n int) string {
+	pad := func(v, width int) string {
+		s := strconv.Itoa(v)
+		for len(s) < width {
+			s = "0" + s
+		}
+		return s
+	}
+	hour12 := t.Hour() % 12
+	if hour12 == 0 {
+		hour12 = 12
+	}
+	switch c {
+	case 'y':
+		if n == 2 {
+			return pad(t.Year()%100, 2)
+		}
+		return pad(t.Year(), n)
+	case 'M', 'L':
+		switch n {
+		case 1, 2:
+			return pad(int(t.Month()), n)
+		case 3:
+			return t.Month().String()[:3]
+		}
+		return t.Month().String()
+	case 'd':
+		return pad(t.Day(), n)
+	case 'o':
+		return pad(t.YearDay(), n)
+	case 'E', 'c':
+		switch {
+		case n <= 2:
+			return strconv.Itoa((int(t.Weekday())+6)%7 + 1)
+		case n == 3:
+			return t.Weekday().String()[:3]
+		}
+		return t.Weekday().String()
+	case 'H':
+		return pad(t.Hour(), n)
+	case 'h':
+		return pad(hour12, n)
+	case 'm':
+		return pad(t.Minute(), n)
+	case 's':
+		return pad(t.Second(), n)
+	case 'S':
+		return pad(t.Nanosecond()/int(time.Millisecond), 3)[:int(math.Min(float64(n), 3))]
+	case 'u':
+		return pad(t.Nanosecond()/int(time.Millisecond), 3)
+	case 'a':
+		if t.Hour() < 12 {
+			return "AM"
+		}
+		return "PM"
+	case 'W':
+		_, week := t.ISOWeek()
+		return pad(week, n)
+	case 'q':
+		return pad((int(t.Month())-1)/3+1, n)
+	case 'Z':
+		switch n {
+		case 1:
+			_, offset := t.Zone()
+			s := "+"
+			if offset < 0 {
+				s, offset = "-", -offset
+			}
+			if offset%3600 == 0 {
+				return s + strconv.Itoa(offset/3600)
+			}
+			return s + strconv.Itoa(offset/3600) + ":" + pad(offset%3600/60, 2)
+		case 2:
+			return t.Format("-07:00")
+		}
+		return t.Format("-0700")
+	case 'z':
+		return t.Location().String()
+	case 'X':
+		return strconv.FormatInt(t.Unix(), 10)
+	case 'x':
+		return strconv.FormatInt(t.UnixMilli(), 10)
+	}
+	return strings.Repeat(string(c), n)
+}
@@ -0,0 +1,417 @@
+package expression
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrSyntax is returned for expressions that do not parse
+	ErrSyntax = errors.New("expression syntax error")
+	// ErrEvaluation is returned for expressions failing as they are
+	// evaluated, such as by reading a property of undefined
+	ErrEvaluation = errors.New("cannot evaluate expression")
+	// ErrLimitExceeded is returned for expressions taking too many steps
+	// or building too large values
+	ErrLimitExceeded = errors.New("expression exceeds its evaluation limits")
+)
+
+const (
+	// maxSteps bounds the steps of one evaluation, which bounds its time
+	maxSteps = 100000
+	// maxStringLength bounds the strings an evaluation builds, in bytes
+	maxStringLength = 1 << 20
+	// maxArrayLength bounds the arrays an evaluation builds
+	maxArrayLength = 100000
+)
+
+// errShortCircuit ends an optional chain reaching null or undefined
+var errShortCircuit = errors.New("short circuit")
+
+// evaluator evaluates the expressions of one template for one item. It
+// has no access to anything but the data of the execution: expressions
+// cannot reach the host, and are cut off after maxSteps steps.
+type evaluator struct {
+	data  *Data
+	steps int
+	// roots caches the values of the root symbols read so far
+	roots map[string]interface{}
+}
+
+// scope holds the parameters of the arrow functions being called
+type scope struct {
+	vars   map[string]interface{}
+	parent *scope
+}
+
+func (s *scope) lookup(name string) (interface{}, bool) {
+	for ; s != nil; s = s.parent {
+		if v, ok := s.vars[name]; ok {
+			return v, true
+		}
+	}
+	return nil, false
+}
+
+func newEvaluator(data *Data) *evaluator {
+	if data == nil {
+		data = &Data{}
+	}
+	return &evaluator{data: data, roots: make(map[string]interface{})}
+}
+
+// step counts an evaluation step
+func (e *evaluator) step() error {
+	e.steps++
+	if e.steps > maxSteps {
+		return fmt.Errorf("%w: more than %d steps", ErrLimitExceeded, maxSteps)
+	}
+	return nil
+}
+
+// checkString fails for strings longer than maxStringLength
+func checkString(s string) (interface{}, error) {
+	if len(s) > maxStringLength {
+		return nil, errStringTooLong()
+	}
+	return s, nil
+}
+
+func errStringTooLong() error {
+	return fmt.Errorf("%w: string longer than %d bytes", ErrLimitExceeded, maxStringLength)
+}
+
+// checkLength fails for arrays about to grow past maxArrayLength
+func checkLength(n int) error {
+	if n > maxArrayLength {
+		return fmt.Errorf("%w: array longer than %d items", ErrLimitExceeded, maxArrayLength)
+	}
+	return nil
+}
+
+// evalExpr evaluates an expression to a normalized value
+func (e *evaluator) evalExpr(x expr, s *scope) (interface{}, error) {
+	if err := e.step(); err != nil {
+		return nil, err
+	}
+	v, err := x.eval(e, s)
+	if err != nil {
+		return nil, err
+	}
+	return normalize(v), nil
+}
+
+func (l *literal) eval(*evaluator, *scope) (interface{}, error) {
+	return l.value, nil
+}
+
+func (i *identifier) eval(e *evaluator, s *scope) (interface{}, error) {
+	if v, ok := s.lookup(i.name); ok {
+		return v, nil
+	}
+	if v, ok := e.roots[i.name]; ok {
+		return v, nil
+	}
+	v, ok := e.root(i.name)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s is not defined", ErrEvaluation, i.name)
+	}
+	e.roots[i.name] = v
+	return v, nil
+}
+
+func (t *templateLiteral) eval(e *evaluator, s *scope) (interface{}, error) {
+	var out strings.Builder
+	out.WriteString(t.quasis[0])
+	for i, sub := range t.substitutions {
+		v, err := e.evalExpr(sub, s)
+		if err != nil {
+			return nil, err
+		}
+		part, err := stringify(v)
+		if err != nil {
+			return nil, err
+		}
+		if out.Len()+len(part)+len(t.quasis[i+1]) > maxStringLength {
+			return nil, errStringTooLong()
+		}
+		out.WriteString(part)
+		out.WriteString(t.quasis[i+1])
+	}
+	return out.String(), nil
+}
+
+func (a *arrayLiteral) eval(e *evaluator, s *scope) (interface{}, error) {
+	out := make([]interface{}, len(a.elements))
+	for i, element := range a.elements {
+		v, err := e.evalExpr(element, s)
+		if err != nil {
+			return nil, err
+		}
+		out[i] = v
+	}
+	return out, nil
+}
+
+func (o *objectLiteral) eval(e *evaluator, s *scope) (interface{}, error) {
+	out := make(map[string]interface{}, len(o.keys))
+	for i, key := range o.keys {
+		v, err := e.evalExpr(o.values[i], s)
+		if err != nil {
+			return nil, err
+		}
+		out[key] = v
+	}
+	return out, nil
+}
+
+// key returns the property a member expression reads
+func (m *member) key(e *evaluator, s *scope) (interface{}, error) {
+	if m.computed == nil {
+		return m.property, nil
+	}
+	return e.evalExpr(m.computed, s)
+}
+
+func (m *member) eval(e *evaluator, s *scope) (interface{}, error) {
+	object, err := e.evalExpr(m.object, s)
+	if err != nil {
+		return nil, err
+	}
+	if m.optional && nullish(object) {
+		return nil, errShortCircuit
+	}
+	key, err := m.key(e, s)
+	if err != nil {
+		return nil, err
+	}
+	return e.property(object, key)
+}
+
+func (c *call) eval(e *evaluator, s *scope) (interface{}, error) {
+	var fn interface{}
+	if m, ok := c.callee.(*member); ok {
+		// Methods are looked up on the value they are called on
+		object, err := e.evalExpr(m.object, s)
+		if err != nil {
+			return nil, err
+		}
+		if m.optional && nullish(object) {
+			return nil, errShortCircuit
+		}
+		key, err := m.key(e, s)
+		if err != nil {
+			return nil, err
+		}
+		if fn, err = e.method(object, key); err != nil {
+			return nil, err
+		}
+	} else {
+		var err error
+		if fn, err = e.evalExpr(c.callee, s); err != nil {
+			return nil, err
+		}
+	}
+	if c.optional && nullish(fn) {
+		return nil, errShortCircuit
+	}
+	f, ok := fn.(function)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s is not a function", ErrEvaluation, describe(c.callee))
+	}
+
+	args := make([]interface{}, len(c.args))
+	for i, arg := range c.args {
+		v, err := e.evalExpr(arg, s)
+		if err != nil {
+			return nil, err
+		}
+		args[i] = v
+	}
+	return f(e, args)
+}
+
+func (c *chain) eval(e *evaluator, s *scope) (interface{}, error) {
+	v, err := c.expr.eval(e, s)
+	if errors.Is(err, errShortCircuit) {
+		return undefined, nil
+	}
+	return v, err
+}
+
+func (u *unary) eval(e *evaluator, s *scope) (interface{}, error) {
+	v, err := e.evalExpr(u.operand, s)
+	if err != nil {
+		return nil, err
+	}
+	switch u.op {
+	case "!":
+		return !truthy(v), nil
+	case "-":
+		return -toNumber(v), nil
+	case "+":
+		return toNumber(v), nil
+	}
+	return typeOf(v), nil
+}
+
+func (b *binary) eval(e *evaluator, s *scope) (interface{}, error) {
+	left, err := e.evalExpr(b.left, s)
+	if err != nil {
+		return nil, err
+	}
+	// The logical operators evaluate their right side only when needed
+	switch b.op {
+	case "&&":
+		if !truthy(left) {
+			return left, nil
+		}
+		return e.evalExpr(b.right, s)
+	case "||":
+		if truthy(left) {
+			return left, nil
+		}
+		return e.evalExpr(b.right, s)
+	case "??":
+		if !nullish(left) {
+			return left, nil
+		}
+		return e.evalExpr(b.right, s)
+	}
+
+	right, err := e.evalExpr(b.right, s)
+	if err != nil {
+		return nil, err
+	}
+	switch b.op {
+	case "+":
+		_, leftNumeric := left.(float64)
+		_, rightNumeric := right.(float64)
+		if (leftNumeric || nullish(left) || isBool(left)) && (rightNumeric || nullish(right) || isBool(right)) {
+			return toNumber(left) + toNumber(right), nil
+		}
+		l, err := stringify(left)
+		if err != nil {
+			return nil, err
+		}
+		r, err := stringify(right)
+		if err != nil {
+			return nil, err
+		}
+		if len(l)+len(r) > maxStringLength {
+			return nil, errStringTooLong()
+		}
+		return l + r, nil
+	case "-":
+		return toNumber(left) - toNumber(right), nil
+	case "*":
+		return toNumber(left) * toNumber(right), nil
+	case "/":
+		return toNumber(left) / toNumber(right), nil
+	case "%":
+		return math.Mod(toNumber(left), toNumber(right)), nil
+	case "===":
+		return strictEquals(left, right), nil
+	case "!==":
+		return !strictEquals(left, right), nil
+	case "==":
+		return looseEquals(left, right), nil
+	case "!=":
+		return !looseEquals(left, right), nil
+	}
+
+	order, ok := compare(left, right)
+	if !ok {
+		return false, nil
+	}
+	switch b.op {
+	case "<":
+		return order < 0, nil
+	case ">":
+		return order > 0, nil
+	case "<=":
+		return order <= 0, nil
+	}
+	return order >= 0, nil
+}
+
+func isBool(v interface{}) bool {
+	_, ok := v.(bool)
+	return ok
+}
+
+func (c *conditional) eval(e *evaluator, s *scope) (interface{}, error) {
+	test, err := e.evalExpr(c.test, s)
+	if err != nil {
+		return nil, err
+	}
+	if truthy(test) {
+		return e.evalExpr(c.consequent, s)
+	}
+	return e.evalExpr(c.alternate, s)
+}
+
+func (a *arrow) eval(_ *evaluator, s *scope) (interface{}, error) {
+	return function(func(e *evaluator, args []interface{}) (interface{}, error) {
+		vars := make(map[string]interface{}, len(a.params))
+		for i, name := range a.params {
+			if i < len(args) {
+				vars[name] = args[i]
+			} else {
+				vars[name] = undefined
+			}
+		}
+		return e.evalExpr(a.body, &scope{vars: vars, parent: s})
+	}), nil
+}
+
+func (d *newDate) eval(e *evaluator, s *scope) (interface{}, error) {
+	args := make([]interface{}, len(d.args))
+	for i, arg := range d.args {
+		v, err := e.evalExpr(arg, s)
+		if err != nil {
+			return nil, err
+		}
+		args[i] = v
+	}
+	if len(args) == 0 {
+		return e.now(), nil
+	}
+	return toDate(args[0], e.location())
+}
+
+// describe names the callee of a failing call for its error
+func describe(x expr) string {
+	switch x := x.(type) {
+	case *identifier:
+		return x.name
+	case *member:
+		if x.computed == nil {
+			return describe(x.object) + "." + x.property
+		}
+		return describe(x.object) + "[...]"
+	case *call:
+		return describe(x.callee) + "(...)"
+	}
+	return "expression"
+}
+
+// now returns the time of the evaluation
+func (e *evaluator) now() time.Time {
+	now := e.data.Now
+	if now.IsZero() {
+		now = time.Now()
+	}
+	return now.In(e.location())
+}
+
+// location returns the timezone of the workflow
+func (e *evaluator) location() *time.Location {
+	if e.data.Location != nil {
+		return e.data.Location
+	}
+	return time.UTC
+}
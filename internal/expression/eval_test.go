@@ -0,0 +1,101 @@
+package expression
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestEvaluateLimits(t *testing.T) {
+	tests := []struct {
+		name string
+		expr string
+		want interface{}
+		err  error
+	}{
+		{name: "steps within limit", expr: `{{ "x".repeat(1000).split("").map(c => c + c).length }}`, want: float64(1000)},
+		{name: "too many steps", expr: `{{ "x".repeat(60000).split("").map(c => c + c).length }}`, err: ErrLimitExceeded},
+		{name: "string within limit", expr: `{{ "x".repeat(1048576).length }}`, want: float64(1048576)},
+		{name: "repeat past string limit", expr: `{{ "x".repeat(1048577) }}`, err: ErrLimitExceeded},
+		{name: "padStart past string limit", expr: `{{ "x".padStart(1048577) }}`, err: ErrLimitExceeded},
+		{name: "replace within limit", expr: `{{ "abab".replace("a", "cc") }}`, want: "ccbab"},
+		{name: "replace past string limit", expr: `{{ "x".repeat(1048576).replace("x", "yy") }}`, err: ErrLimitExceeded},
+		{name: "replace shrinking a long string", expr: `{{ "x".repeat(1048576).replace("xx", "").length }}`, want: float64(1048574)},
+		{name: "replaceAll within limit", expr: `{{ "abab".replaceAll("a", "cc") }}`, want: "ccbccb"},
+		{name: "replaceAll past string limit", expr: `{{ "x".repeat(1000).replaceAll("x", "y".repeat(2000)) }}`, err: ErrLimitExceeded},
+		{name: "replaceAll of the empty string past string limit", expr: `{{ "x".repeat(600000).replaceAll("", "y") }}`, err: ErrLimitExceeded},
+		{name: "concat within limit", expr: `{{ "a".concat("b", 1) }}`, want: "ab1"},
+		{name: "concat past string limit", expr: `{{ "x".repeat(1048576).concat("y") }}`, err: ErrLimitExceeded},
+		{name: "plus past string limit", expr: `{{ "x".repeat(1048576) + "y" }}`, err: ErrLimitExceeded},
+		{name: "array within limit", expr: `{{ "x".repeat(100000).split("").length }}`, want: float64(100000)},
+		{name: "split past array limit", expr: `{{ "x".repeat(100001).split("") }}`, err: ErrLimitExceeded},
+		{name: "concat past array limit", expr: `{{ "x".repeat(100000).split("").concat(1) }}`, err: ErrLimitExceeded},
+		{name: "array toString within limit", expr: `{{ [1, "a", [true]].toString() }}`, want: `[1,"a",[true]]`},
+		{name: "array toString past string limit", expr: `{{ "x".repeat(1000).split("").map(c => "y".repeat(2000)).toString() }}`, err: ErrLimitExceeded},
+		{name: "String of an array past string limit", expr: `{{ String("x".repeat(1000).split("").map(c => "y".repeat(2000))) }}`, err: ErrLimitExceeded},
+		{name: "join within limit", expr: `{{ ["a", null, [1]].join("-") }}`, want: "a--[1]"},
+		{name: "join past string limit", expr: `{{ "x".repeat(1000).split("").map(c => "y".repeat(2000)).join("") }}`, err: ErrLimitExceeded},
+		{name: "join separators past string limit", expr: `{{ "x".repeat(100).split("").join("y".repeat(20000)) }}`, err: ErrLimitExceeded},
+		{name: "join of arrays past string limit", expr: `{{ "x".repeat(1000).split("").map(c => ["y".repeat(2000)]).join("") }}`, err: ErrLimitExceeded},
+		{name: "plus on arrays within limit", expr: `{{ [1, 2] + "" }}`, want: "[1,2]"},
+		{name: "plus on arrays past string limit", expr: `{{ "x".repeat(1000).split("").map(c => "y".repeat(2000)) + "" }}`, err: ErrLimitExceeded},
+		{name: "template literal within limit", expr: "{{ `a${1}b${[2]}` }}", want: "a1b[2]"},
+		{name: "template literal past string limit", expr: "{{ `a${\"x\".repeat(1048576)}` }}", err: ErrLimitExceeded},
+		{name: "template literal of an array past string limit", expr: "{{ `${\"x\".repeat(1000).split(\"\").map(c => \"y\".repeat(2000))}` }}", err: ErrLimitExceeded},
+		{name: "JSON.stringify within limit", expr: `{{ JSON.stringify({b: [1, {}], a: "<"}, null, 2) }}`, want: "{\n  \"a\": \"\\u003c\",\n  \"b\": [\n    1,\n    {}\n  ]\n}"},
+		{name: "JSON.stringify past string limit", expr: `{{ JSON.stringify("x".repeat(1000).split("").map(c => "y".repeat(2000))) }}`, err: ErrLimitExceeded},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpl, err := Parse(tt.expr)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			got, err := tmpl.Evaluate(&Data{})
+			if tt.err != nil {
+				if !errors.Is(err, tt.err) {
+					t.Fatalf("Evaluate() error = %v, want %v", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Evaluate() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Evaluate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEvaluateEnv(t *testing.T) {
+	t.Setenv("EXPRESSION_TEST_SECRET", "hidden")
+	data := &Data{Env: map[string]string{"REGION": "eu"}}
+
+	tests := []struct {
+		name string
+		expr string
+		want interface{}
+	}{
+		{name: "listed variable", expr: `{{ $env.REGION }}`, want: "eu"},
+		{name: "variable of the process left out", expr: `{{ $env.EXPRESSION_TEST_SECRET ?? "unset" }}`, want: "unset"},
+		{name: "only listed variables", expr: `{{ Object.keys($env).join(",") }}`, want: "REGION"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpl, err := Parse(tt.expr)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			got, err := tmpl.Evaluate(data)
+			if err != nil {
+				t.Fatalf("Evaluate() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Evaluate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
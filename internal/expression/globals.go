@@ -0,0 +1,253 @@
+package expression
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/rand"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// globals holds the global objects and functions expressions can use
+var globals map[string]interface{}
+
+func init() {
+	globals = map[string]interface{}{
+		"Math":               mathObject(),
+		"JSON":               jsonObject(),
+		"Object":             objectObject(),
+		"Array":              map[string]interface{}{"isArray": fn1(func(v interface{}) interface{} { _, ok := v.([]interface{}); return ok })},
+		"Date":               map[string]interface{}{"now": function(func(e *evaluator, _ []interface{}) (interface{}, error) { return float64(e.now().UnixMilli()), nil })},
+		"DateTime":           dateTimeObject(),
+		"String":             function(func(_ *evaluator, args []interface{}) (interface{}, error) { return stringify(arg(args, 0)) }),
+		"Number":             fn1(func(v interface{}) interface{} { return toNumber(v) }),
+		"Boolean":            fn1(func(v interface{}) interface{} { return truthy(v) }),
+		"parseInt":           function(parseInt),
+		"parseFloat":         fn1(parseFloat),
+		"isNaN":              fn1(func(v interface{}) interface{} { return math.IsNaN(toNumber(v)) }),
+		"isFinite":           fn1(func(v interface{}) interface{} { n := toNumber(v); return !math.IsNaN(n) && !math.IsInf(n, 0) }),
+		"encodeURIComponent": fn1(func(v interface{}) interface{} { return strings.ReplaceAll(url.QueryEscape(toString(v)), "+", "%20") }),
+		"decodeURIComponent": function(decodeURIComponent),
+		"NaN":                math.NaN(),
+		"Infinity":           math.Inf(1),
+	}
+}
+
+// fn1 wraps a function of one argument that cannot fail
+func fn1(f func(v interface{}) interface{}) function {
+	return func(_ *evaluator, args []interface{}) (interface{}, error) {
+		return f(arg(args, 0)), nil
+	}
+}
+
+// fnMath wraps a math function of one number
+func fnMath(f func(float64) float64) function {
+	return fn1(func(v interface{}) interface{} { return f(toNumber(v)) })
+}
+
+func mathObject() map[string]interface{} {
+	return map[string]interface{}{
+		"abs":   fnMath(math.Abs),
+		"ceil":  fnMath(math.Ceil),
+		"floor": fnMath(math.Floor),
+		// Math.round rounds halves up, unlike math.Round
+		"round": fnMath(func(n float64) float64 { return math.Floor(n + 0.5) }),
+		"trunc": fnMath(math.Trunc),
+		"sqrt":  fnMath(math.Sqrt),
+		"log":   fnMath(math.Log),
+		"sign": fnMath(func(n float64) float64 {
+			switch {
+			case n > 0:
+				return 1
+			case n < 0:
+				return -1
+			}
+			return n
+		}),
+		"pow": function(func(_ *evaluator, args []interface{}) (interface{}, error) {
+			return math.Pow(toNumber(arg(args, 0)), toNumber(arg(args, 1))), nil
+		}),
+		"min": function(func(_ *evaluator, args []interface{}) (interface{}, error) {
+			out := math.Inf(1)
+			for _, a := range args {
+				out = math.Min(out, toNumber(a))
+			}
+			return out, nil
+		}),
+		"max": function(func(_ *evaluator, args []interface{}) (interface{}, error) {
+			out := math.Inf(-1)
+			for _, a := range args {
+				out = math.Max(out, toNumber(a))
+			}
+			return out, nil
+		}),
+		"random": function(func(*evaluator, []interface{}) (interface{}, error) {
+			return rand.Float64(), nil
+		}),
+		"PI": math.Pi,
+		"E":  math.E,
+	}
+}
+
+func jsonObject() map[string]interface{} {
+	return map[string]interface{}{
+		"stringify": function(func(_ *evaluator, args []interface{}) (interface{}, error) {
+			v := arg(args, 0)
+			if _, ok := v.(undefinedValue); ok {
+				return undefined, nil
+			}
+			var prefix string
+			if indent := arg(args, 2); !nullish(indent) {
+				prefix = toString(indent)
+				if n, ok := indent.(float64); ok {
+					prefix = strings.Repeat(" ", int(math.Max(0, math.Min(n, 10))))
+				}
+			}
+			var b strings.Builder
+			if err := writeJSON(&b, v, prefix, 0); err != nil {
+				return nil, err
+			}
+			return b.String(), nil
+		}),
+		"parse": function(func(_ *evaluator, args []interface{}) (interface{}, error) {
+			var out interface{}
+			if err := json.Unmarshal([]byte(toString(arg(args, 0))), &out); err != nil {
+				return nil, fmt.Errorf("%w: invalid JSON: %v", ErrEvaluation, err)
+			}
+			return out, nil
+		}),
+	}
+}
+
+func objectObject() map[string]interface{} {
+	object := func(v interface{}) map[string]interface{} {
+		o, _ := v.(map[string]interface{})
+		return o
+	}
+	return map[string]interface{}{
+		"keys": fn1(func(v interface{}) interface{} {
+			out := []interface{}{}
+			for _, k := range sortedKeys(object(v)) {
+				out = append(out, k)
+			}
+			return out
+		}),
+		"values": fn1(func(v interface{}) interface{} {
+			o := object(v)
+			out := []interface{}{}
+			for _, k := range sortedKeys(o) {
+				out = append(out, o[k])
+			}
+			return out
+		}),
+		"entries": fn1(func(v interface{}) interface{} {
+			o := object(v)
+			out := []interface{}{}
+			for _, k := range sortedKeys(o) {
+				out = append(out, []interface{}{k, o[k]})
+			}
+			return out
+		}),
+		// assign returns a new object rather than changing its first
+		// argument, which may be shared with the data of the execution
+		"assign": function(func(_ *evaluator, args []interface{}) (interface{}, error) {
+			out := map[string]interface{}{}
+			for _, a := range args {
+				for k, v := range object(a) {
+					out[k] = v
+				}
+			}
+			return out, nil
+		}),
+	}
+}
+
+func dateTimeObject() map[string]interface{} {
+	return map[string]interface{}{
+		"now": function(func(e *evaluator, _ []interface{}) (interface{}, error) {
+			return e.now(), nil
+		}),
+		"fromISO": function(func(e *evaluator, args []interface{}) (interface{}, error) {
+			return toDate(toString(arg(args, 0)), e.location())
+		}),
+		"fromMillis": function(func(e *evaluator, args []interface{}) (interface{}, error) {
+			return toDate(toNumber(arg(args, 0)), e.location())
+		}),
+		"fromSeconds": function(func(e *evaluator, args []interface{}) (interface{}, error) {
+			return toDate(toNumber(arg(args, 0))*1000, e.location())
+		}),
+		"fromFormat": function(func(e *evaluator, args []interface{}) (interface{}, error) {
+			return toDate(toString(arg(args, 0)), e.location())
+		}),
+	}
+}
+
+// sortedKeys returns the keys of an object in order, so that expressions
+// listing them are deterministic
+func sortedKeys(o map[string]interface{}) []string {
+	keys := make([]string, 0, len(o))
+	for k := range o {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// parseInt parses the leading integer of a string as JavaScript does
+func parseInt(_ *evaluator, args []interface{}) (interface{}, error) {
+	s := strings.TrimSpace(toString(arg(args, 0)))
+	radix := integer(arg(args, 1), 10)
+	if radix == 0 {
+		radix = 10
+	}
+	sign := ""
+	if s != "" && (s[0] == '-' || s[0] == '+') {
+		sign, s = s[:1], s[1:]
+	}
+	if (radix == 16 || nullish(arg(args, 1))) && (strings.HasPrefix(s, "0x") || strings.HasPrefix(s, "0X")) {
+		radix, s = 16, s[2:]
+	}
+	if radix < 2 || radix > 36 {
+		return math.NaN(), nil
+	}
+	end := 0
+	for end < len(s) {
+		d, err := strconv.ParseInt(string(unicode.ToLower(rune(s[end]))), 36, 64)
+		if err != nil || int(d) >= radix {
+			break
+		}
+		end++
+	}
+	if end == 0 {
+		return math.NaN(), nil
+	}
+	n, err := strconv.ParseInt(sign+s[:end], radix, 64)
+	if err != nil {
+		f, _ := strconv.ParseFloat(sign+s[:end], 64)
+		return f, nil
+	}
+	return float64(n), nil
+}
+
+// parseFloat parses the leading number of a string as JavaScript does
+func parseFloat(v interface{}) interface{} {
+	s := strings.TrimSpace(toString(v))
+	for end := len(s); end > 0; end-- {
+		if n, err := strconv.ParseFloat(s[:end], 64); err == nil {
+			return n
+		}
+	}
+	return math.NaN()
+}
+
+func decodeURIComponent(_ *evaluator, args []interface{}) (interface{}, error) {
+	s, err := url.PathUnescape(toString(arg(args, 0)))
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed URI sequence", ErrEvaluation)
+	}
+	return s, nil
+}
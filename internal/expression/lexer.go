@@ -0,0 +1,266 @@
+package expression
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// tokenKind classifies the tokens of an expression
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenNumber
+	tokenString
+	tokenTemplate
+	tokenIdent
+	tokenPunct
+)
+
+// token is a lexical token of an expression. Text holds the punctuator or
+// identifier, the decoded string, or the raw body of a template literal.
+type token struct {
+	kind   tokenKind
+	text   string
+	number float64
+	pos    int
+}
+
+// punctuators are matched longest first
+var punctuators = []string{
+	"===", "!==",
+	"?.", "??", "=>", "==", "!=", "<=", ">=", "&&", "||",
+	"(", ")", "[", "]", "{", "}", ".", ",", ":", "?", "+", "-", "*", "/", "%", "!", "<", ">",
+}
+
+// lex splits an expression into tokens
+func lex(src string) ([]token, error) {
+	var tokens []token
+	for i := 0; i < len(src); {
+		c := src[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case isDigit(c) || (c == '.' && i+1 < len(src) && isDigit(src[i+1])):
+			t, next, err := lexNumber(src, i)
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, t)
+			i = next
+		case c == '"' || c == '\'':
+			s, next, err := lexString(src, i)
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, token{kind: tokenString, text: s, pos: i})
+			i = next
+		case c == '`':
+			end, err := templateEnd(src, i+1)
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, token{kind: tokenTemplate, text: src[i+1 : end], pos: i})
+			i = end + 1
+		case isIdentStart(c):
+			start := i
+			for i < len(src) && isIdentPart(src[i]) {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokenIdent, text: src[start:i], pos: start})
+		default:
+			p := matchPunctuator(src[i:])
+			if p == "" {
+				r, _ := utf8.DecodeRuneInString(src[i:])
+				return nil, fmt.Errorf("%w: unexpected character %q at %d", ErrSyntax, r, i)
+			}
+			tokens = append(tokens, token{kind: tokenPunct, text: p, pos: i})
+			i += len(p)
+		}
+	}
+	return append(tokens, token{kind: tokenEOF, pos: len(src)}), nil
+}
+
+// matchPunctuator returns the punctuator s starts with, or "". A ?. before
+// a digit is a conditional followed by a number, as in a?.5:1.
+func matchPunctuator(s string) string {
+	for _, p := range punctuators {
+		if strings.HasPrefix(s, p) {
+			if p == "?." && len(s) > 2 && isDigit(s[2]) {
+				return "?"
+			}
+			return p
+		}
+	}
+	return ""
+}
+
+func lexNumber(src string, start int) (token, int, error) {
+	i := start
+	if strings.HasPrefix(src[i:], "0x") || strings.HasPrefix(src[i:], "0X") {
+		i += 2
+		for i < len(src) && isHexDigit(src[i]) {
+			i++
+		}
+		n, err := strconv.ParseInt(src[start+2:i], 16, 64)
+		if err != nil {
+			return token{}, 0, fmt.Errorf("%w: invalid number at %d", ErrSyntax, start)
+		}
+		return token{kind: tokenNumber, number: float64(n), pos: start}, i, nil
+	}
+	for i < len(src) && (isDigit(src[i]) || src[i] == '.') {
+		i++
+	}
+	if i < len(src) && (src[i] == 'e' || src[i] == 'E') {
+		i++
+		if i < len(src) && (src[i] == '+' || src[i] == '-') {
+			i++
+		}
+		for i < len(src) && isDigit(src[i]) {
+			i++
+		}
+	}
+	n, err := strconv.ParseFloat(src[start:i], 64)
+	if err != nil {
+		return token{}, 0, fmt.Errorf("%w: invalid number at %d", ErrSyntax, start)
+	}
+	return token{kind: tokenNumber, number: n, pos: start}, i, nil
+}
+
+// lexString decodes the quoted string starting at start and returns the
+// index after its closing quote
+func lexString(src string, start int) (string, int, error) {
+	quote := src[start]
+	var b strings.Builder
+	for i := start + 1; i < len(src); i++ {
+		c := src[i]
+		switch {
+		case c == quote:
+			return b.String(), i + 1, nil
+		case c == '\\':
+			r, next, err := unescape(src, i)
+			if err != nil {
+				return "", 0, err
+			}
+			b.WriteString(r)
+			i = next - 1
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return "", 0, fmt.Errorf("%w: unterminated string at %d", ErrSyntax, start)
+}
+
+// unescape decodes the escape sequence at src[i], a backslash, and returns
+// the index after it
+func unescape(src string, i int) (string, int, error) {
+	if i+1 >= len(src) {
+		return "", 0, fmt.Errorf("%w: unterminated escape at %d", ErrSyntax, i)
+	}
+	switch c := src[i+1]; c {
+	case 'n':
+		return "\n", i + 2, nil
+	case 't':
+		return "\t", i + 2, nil
+	case 'r':
+		return "\r", i + 2, nil
+	case 'b':
+		return "\b", i + 2, nil
+	case 'f':
+		return "\f", i + 2, nil
+	case 'v':
+		return "\v", i + 2, nil
+	case '0':
+		return "\x00", i + 2, nil
+	case 'u':
+		if i+6 > len(src) {
+			return "", 0, fmt.Errorf("%w: invalid unicode escape at %d", ErrSyntax, i)
+		}
+		n, err := strconv.ParseUint(src[i+2:i+6], 16, 32)
+		if err != nil {
+			return "", 0, fmt.Errorf("%w: invalid unicode escape at %d", ErrSyntax, i)
+		}
+		return string(rune(n)), i + 6, nil
+	case 'x':
+		if i+4 > len(src) {
+			return "", 0, fmt.Errorf("%w: invalid hex escape at %d", ErrSyntax, i)
+		}
+		n, err := strconv.ParseUint(src[i+2:i+4], 16, 8)
+		if err != nil {
+			return "", 0, fmt.Errorf("%w: invalid hex escape at %d", ErrSyntax, i)
+		}
+		return string(rune(n)), i + 4, nil
+	default:
+		return string(c), i + 2, nil
+	}
+}
+
+// templateEnd returns the index of the backtick closing the template
+// literal whose body starts at start, skipping over its ${} substitutions
+func templateEnd(src string, start int) (int, error) {
+	for i := start; i < len(src); i++ {
+		switch src[i] {
+		case '\\':
+			i++
+		case '`':
+			return i, nil
+		case '$':
+			if i+1 < len(src) && src[i+1] == '{' {
+				end, err := substitutionEnd(src, i+2)
+				if err != nil {
+					return 0, err
+				}
+				i = end
+			}
+		}
+	}
+	return 0, fmt.Errorf("%w: unterminated template literal at %d", ErrSyntax, start-1)
+}
+
+// substitutionEnd returns the index of the } closing a ${ substitution
+// whose body starts at start
+func substitutionEnd(src string, start int) (int, error) {
+	depth := 0
+	for i := start; i < len(src); i++ {
+		switch c := src[i]; c {
+		case '"', '\'':
+			_, next, err := lexString(src, i)
+			if err != nil {
+				return 0, err
+			}
+			i = next - 1
+		case '`':
+			end, err := templateEnd(src, i+1)
+			if err != nil {
+				return 0, err
+			}
+			i = end
+		case '{':
+			depth++
+		case '}':
+			if depth == 0 {
+				return i, nil
+			}
+			depth--
+		}
+	}
+	return 0, fmt.Errorf("%w: unterminated substitution at %d", ErrSyntax, start-2)
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isHexDigit(c byte) bool {
+	return isDigit(c) || (c >= 'a' && c <= 'f') || (c >= 'A' && c <= 'F')
+}
+
+func isIdentStart(c byte) bool {
+	return c == '_' || c == '$' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isIdentPart(c byte) bool {
+	return isIdentStart(c) || isDigit(c)
+}
@@ -0,0 +1,484 @@
+package expression
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// callFunction calls a function value passed as an argument, such as the
+// callback of map
+func callFunction(e *evaluator, fn interface{}, args ...interface{}) (interface{}, error) {
+	f, ok := fn.(function)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s is not a function", ErrEvaluation, toString(fn))
+	}
+	v, err := f(e, args)
+	if err != nil {
+		return nil, err
+	}
+	return normalize(v), nil
+}
+
+// integer converts an argument to an integer, def when it is undefined
+func integer(v interface{}, def int) int {
+	if _, ok := v.(undefinedValue); ok {
+		return def
+	}
+	n := toNumber(v)
+	switch {
+	case math.IsNaN(n):
+		return 0
+	case n > math.MaxInt32:
+		return math.MaxInt32
+	case n < math.MinInt32:
+		return math.MinInt32
+	}
+	return int(n)
+}
+
+// relative resolves a possibly negative index against a length, as slice
+// does, clamped to the bounds
+func relative(i, length int) int {
+	if i < 0 {
+		i += length
+	}
+	if i < 0 {
+		return 0
+	}
+	if i > length {
+		return length
+	}
+	return i
+}
+
+// stringMethod returns a method of a string
+func stringMethod(s string, name string) function {
+	runes := func() []rune { return []rune(s) }
+	switch name {
+	case "toUpperCase":
+		return func(*evaluator, []interface{}) (interface{}, error) { return strings.ToUpper(s), nil }
+	case "toLowerCase":
+		return func(*evaluator, []interface{}) (interface{}, error) { return strings.ToLower(s), nil }
+	case "trim":
+		return func(*evaluator, []interface{}) (interface{}, error) { return strings.TrimSpace(s), nil }
+	case "trimStart":
+		return func(*evaluator, []interface{}) (interface{}, error) {
+			return strings.TrimLeft(s, " \t\n\r\v\f"), nil
+		}
+	case "trimEnd":
+		return func(*evaluator, []interface{}) (interface{}, error) {
+			return strings.TrimRight(s, " \t\n\r\v\f"), nil
+		}
+	case "toString", "valueOf":
+		return func(*evaluator, []interface{}) (interface{}, error) { return s, nil }
+	case "includes":
+		return func(_ *evaluator, args []interface{}) (interface{}, error) {
+			return strings.Contains(s, toString(arg(args, 0))), nil
+		}
+	case "startsWith":
+		return func(_ *evaluator, args []interface{}) (interface{}, error) {
+			return strings.HasPrefix(s, toString(arg(args, 0))), nil
+		}
+	case "endsWith":
+		return func(_ *evaluator, args []interface{}) (interface{}, error) {
+			return strings.HasSuffix(s, toString(arg(args, 0))), nil
+		}
+	case "indexOf":
+		return func(_ *evaluator, args []interface{}) (interface{}, error) {
+			i := strings.Index(s, toString(arg(args, 0)))
+			if i < 0 {
+				return float64(-1), nil
+			}
+			return float64(utf8.RuneCountInString(s[:i])), nil
+		}
+	case "lastIndexOf":
+		return func(_ *evaluator, args []interface{}) (interface{}, error) {
+			i := strings.LastIndex(s, toString(arg(args, 0)))
+			if i < 0 {
+				return float64(-1), nil
+			}
+			return float64(utf8.RuneCountInString(s[:i])), nil
+		}
+	case "slice":
+		return func(_ *evaluator, args []interface{}) (interface{}, error) {
+			r := runes()
+			start, end := relative(integer(arg(args, 0), 0), len(r)), relative(integer(arg(args, 1), len(r)), len(r))
+			if start >= end {
+				return "", nil
+			}
+			return string(r[start:end]), nil
+		}
+	case "substring":
+		return func(_ *evaluator, args []interface{}) (interface{}, error) {
+			r := runes()
+			clamp := func(i int) int { return int(math.Min(math.Max(float64(i), 0), float64(len(r)))) }
+			start, end := clamp(integer(arg(args, 0), 0)), clamp(integer(arg(args, 1), len(r)))
+			if start > end {
+				start, end = end, start
+			}
+			return string(r[start:end]), nil
+		}
+	case "charAt", "at":
+		return func(_ *evaluator, args []interface{}) (interface{}, error) {
+			r := runes()
+			i := integer(arg(args, 0), 0)
+			if name == "at" && i < 0 {
+				i += len(r)
+			}
+			if i < 0 || i >= len(r) {
+				if name == "at" {
+					return undefined, nil
+				}
+				return "", nil
+			}
+			return string(r[i]), nil
+		}
+	case "split":
+		return func(_ *evaluator, args []interface{}) (interface{}, error) {
+			var parts []string
+			if sep := arg(args, 0); nullish(sep) {
+				parts = []string{s}
+			} else {
+				parts = strings.Split(s, toString(sep))
+			}
+			if limit := arg(args, 1); !nullish(limit) && integer(limit, 0) < len(parts) {
+				parts = parts[:int(math.Max(0, float64(integer(limit, 0))))]
+			}
+			if err := checkLength(len(parts)); err != nil {
+				return nil, err
+			}
+			out := make([]interface{}, len(parts))
+			for i, part := range parts {
+				out[i] = part
+			}
+			return out, nil
+		}
+	case "replace", "replaceAll":
+		return func(_ *evaluator, args []interface{}) (interface{}, error) {
+			old, replacement := toString(arg(args, 0)), toString(arg(args, 1))
+			n, count := 1, strings.Count(s, old)
+			if name == "replaceAll" {
+				n = -1
+			} else if count > 1 {
+				count = 1
+			}
+			if grow := len(replacement) - len(old); grow > 0 && count > 0 && count > (maxStringLength-len(s))/grow {
+				return nil, errStringTooLong()
+			}
+			return checkString(strings.Replace(s, old, replacement, n))
+		}
+	case "repeat":
+		return func(_ *evaluator, args []interface{}) (interface{}, error) {
+			count := integer(arg(args, 0), 0)
+			if count < 0 {
+				return nil, fmt.Errorf("%w: invalid count %d", ErrEvaluation, count)
+			}
+			if count > 0 && len(s) > maxStringLength/count {
+				return nil, errStringTooLong()
+			}
+			return strings.Repeat(s, count), nil
+		}
+	case "padStart", "padEnd":
+		return func(_ *evaluator, args []interface{}) (interface{}, error) {
+			length := integer(arg(args, 0), 0)
+			pad := " "
+			if p := arg(args, 1); !nullish(p) {
+				pad = toString(p)
+			}
+			missing := length - utf8.RuneCountInString(s)
+			if missing <= 0 || pad == "" {
+				return s, nil
+			}
+			// Runes take a byte at least; fills of wider runes are checked
+			// once built
+			if missing > maxStringLength-len(s) {
+				return nil, errStringTooLong()
+			}
+			fill := []rune(strings.Repeat(pad, missing/utf8.RuneCountInString(pad)+1))[:missing]
+			if name == "padStart" {
+				return checkString(string(fill) + s)
+			}
+			return checkString(s + string(fill))
+		}
+	case "concat":
+		return func(_ *evaluator, args []interface{}) (interface{}, error) {
+			parts := make([]string, len(args)+1)
+			parts[0] = s
+			length := len(s)
+			for i, a := range args {
+				part, err := stringify(a)
+				if err != nil {
+					return nil, err
+				}
+				parts[i+1] = part
+				if length += len(part); length > maxStringLength {
+					return nil, errStringTooLong()
+				}
+			}
+			return checkString(strings.Join(parts, ""))
+		}
+	}
+	return nil
+}
+
+// arrayMethod returns a method of an array. Methods never modify the
+// array, which may be shared with the data of the execution: reverse and
+// sort return sorted copies.
+func arrayMethod(a []interface{}, name string) function {
+	switch name {
+	case "join":
+		return func(_ *evaluator, args []interface{}) (interface{}, error) {
+			sep := ","
+			if s := arg(args, 0); !nullish(s) {
+				sep = toString(s)
+			}
+			parts := make([]string, len(a))
+			length := 0
+			for i, item := range a {
+				if i > 0 {
+					length += len(sep)
+				}
+				if v := normalize(item); !nullish(v) {
+					part, err := stringify(v)
+					if err != nil {
+						return nil, err
+					}
+					parts[i] = part
+					length += len(part)
+				}
+				if length > maxStringLength {
+					return nil, errStringTooLong()
+				}
+			}
+			return strings.Join(parts, sep), nil
+		}
+	case "includes":
+		return func(_ *evaluator, args []interface{}) (interface{}, error) {
+			return indexOf(a, arg(args, 0)) >= 0, nil
+		}
+	case "indexOf":
+		return func(_ *evaluator, args []interface{}) (interface{}, error) {
+			return float64(indexOf(a, arg(args, 0))), nil
+		}
+	case "slice":
+		return func(_ *evaluator, args []interface{}) (interface{}, error) {
+			start, end := relative(integer(arg(args, 0), 0), len(a)), relative(integer(arg(args, 1), len(a)), len(a))
+			if start >= end {
+				return []interface{}{}, nil
+			}
+			return append([]interface{}{}, a[start:end]...), nil
+		}
+	case "at":
+		return func(_ *evaluator, args []interface{}) (interface{}, error) {
+			i := integer(arg(args, 0), 0)
+			if i < 0 {
+				i += len(a)
+			}
+			if i < 0 || i >= len(a) {
+				return undefined, nil
+			}
+			return a[i], nil
+		}
+	case "concat":
+		return func(_ *evaluator, args []interface{}) (interface{}, error) {
+			out := append([]interface{}{}, a...)
+			for _, v := range args {
+				if list, ok := v.([]interface{}); ok {
+					out = append(out, list...)
+				} else {
+					out = append(out, v)
+				}
+			}
+			return out, checkLength(len(out))
+		}
+	case "reverse":
+		return func(*evaluator, []interface{}) (interface{}, error) {
+			out := make([]interface{}, len(a))
+			for i, v := range a {
+				out[len(a)-1-i] = v
+			}
+			return out, nil
+		}
+	case "flat":
+		return func(*evaluator, []interface{}) (interface{}, error) {
+			var out []interface{}
+			for _, v := range a {
+				if list, ok := normalize(v).([]interface{}); ok {
+					out = append(out, list...)
+				} else {
+					out = append(out, v)
+				}
+			}
+			if out == nil {
+				out = []interface{}{}
+			}
+			return out, checkLength(len(out))
+		}
+	case "sort":
+		return func(e *evaluator, args []interface{}) (interface{}, error) {
+			out := append([]interface{}{}, a...)
+			less := func(x, y interface{}) (bool, error) {
+				return toString(normalize(x)) < toString(normalize(y)), nil
+			}
+			if cmp := arg(args, 0); !nullish(cmp) {
+				less = func(x, y interface{}) (bool, error) {
+					v, err := callFunction(e, cmp, normalize(x), normalize(y))
+					return err == nil && toNumber(v) < 0, err
+				}
+			}
+			var sortErr error
+			sort.SliceStable(out, func(i, j int) bool {
+				if sortErr != nil {
+					return false
+				}
+				ok, err := less(out[i], out[j])
+				sortErr = err
+				return ok
+			})
+			return out, sortErr
+		}
+	case "map", "filter", "find", "findIndex", "some", "every":
+		return func(e *evaluator, args []interface{}) (interface{}, error) {
+			return iterate(e, a, name, arg(args, 0))
+		}
+	case "reduce":
+		return func(e *evaluator, args []interface{}) (interface{}, error) {
+			items := a
+			var acc interface{}
+			if len(args) > 1 {
+				acc = args[1]
+			} else if len(items) > 0 {
+				acc, items = normalize(items[0]), items[1:]
+			} else {
+				return nil, fmt.Errorf("%w: reduce of empty array with no initial value", ErrEvaluation)
+			}
+			offset := len(a) - len(items)
+			for i, item := range items {
+				var err error
+				if acc, err = callFunction(e, arg(args, 0), acc, normalize(item), float64(i+offset)); err != nil {
+					return nil, err
+				}
+			}
+			return acc, nil
+		}
+	case "toString":
+		return func(*evaluator, []interface{}) (interface{}, error) { return stringify(a) }
+	}
+	return nil
+}
+
+// iterate runs the callback of map, filter, find, findIndex, some or every
+// over an array
+func iterate(e *evaluator, a []interface{}, name string, fn interface{}) (interface{}, error) {
+	var out []interface{}
+	for i, item := range a {
+		item = normalize(item)
+		v, err := callFunction(e, fn, item, float64(i))
+		if err != nil {
+			return nil, err
+		}
+		switch name {
+		case "map":
+			out = append(out, v)
+		case "filter":
+			if truthy(v) {
+				out = append(out, item)
+			}
+		case "find":
+			if truthy(v) {
+				return item, nil
+			}
+		case "findIndex":
+			if truthy(v) {
+				return float64(i), nil
+			}
+		case "some":
+			if truthy(v) {
+				return true, nil
+			}
+		case "every":
+			if !truthy(v) {
+				return false, nil
+			}
+		}
+	}
+	switch name {
+	case "find":
+		return undefined, nil
+	case "findIndex":
+		return float64(-1), nil
+	case "some":
+		return false, nil
+	case "every":
+		return true, nil
+	}
+	if out == nil {
+		out = []interface{}{}
+	}
+	return out, nil
+}
+
+// indexOf returns the index of the first item strictly equal to v, or -1
+func indexOf(a []interface{}, v interface{}) int {
+	for i, item := range a {
+		if strictEquals(normalize(item), v) {
+			return i
+		}
+	}
+	return -1
+}
+
+// numberMethod returns a method of a number
+func numberMethod(n float64, name string) function {
+	switch name {
+	case "toFixed":
+		return func(_ *evaluator, args []interface{}) (interface{}, error) {
+			digits := integer(arg(args, 0), 0)
+			if digits < 0 || digits > 100 {
+				return nil, fmt.Errorf("%w: toFixed digits must be between 0 and 100", ErrEvaluation)
+			}
+			return strconv.FormatFloat(n, 'f', digits, 64), nil
+		}
+	case "toString":
+		return func(_ *evaluator, args []interface{}) (interface{}, error) {
+			radix := integer(arg(args, 0), 10)
+			if radix == 10 || n != math.Trunc(n) {
+				return formatNumber(n), nil
+			}
+			if radix < 2 || radix > 36 {
+				return nil, fmt.Errorf("%w: radix must be between 2 and 36", ErrEvaluation)
+			}
+			return strconv.FormatInt(int64(n), radix), nil
+		}
+	case "toPrecision":
+		return func(_ *evaluator, args []interface{}) (interface{}, error) {
+			precision := integer(arg(args, 0), 0)
+			if precision < 1 || precision > 100 {
+				return formatNumber(n), nil
+			}
+			return strconv.FormatFloat(n, 'g', precision, 64), nil
+		}
+	case "valueOf":
+		return func(*evaluator, []interface{}) (interface{}, error) { return n, nil }
+	}
+	return nil
+}
+
+// objectMethod returns a method of an object that has no property of
+// the name
+func objectMethod(o map[string]interface{}, name string) function {
+	switch name {
+	case "hasOwnProperty":
+		return func(_ *evaluator, args []interface{}) (interface{}, error) {
+			_, ok := o[toString(arg(args, 0))]
+			return ok, nil
+		}
+	case "toString":
+		return func(*evaluator, []interface{}) (interface{}, error) { return stringify(o) }
+	}
+	return nil
+}
@@ -0,0 +1,520 @@
+package expression
+
+import (
+	"fmt"
+	"strings"
+)
+
+// maxNesting bounds how deeply expressions nest, so that evaluating them
+// cannot exhaust the stack
+const maxNesting = 64
+
+// expr is a node of the syntax tree of an expression
+type expr interface {
+	eval(e *evaluator, s *scope) (interface{}, error)
+}
+
+type (
+	literal struct {
+		value interface{}
+	}
+	identifier struct {
+		name string
+	}
+	templateLiteral struct {
+		// quasis are the literal parts around the substitutions; there is
+		// one more of them than substitutions
+		quasis        []string
+		substitutions []expr
+	}
+	arrayLiteral struct {
+		elements []expr
+	}
+	objectLiteral struct {
+		keys   []string
+		values []expr
+	}
+	member struct {
+		object expr
+		// property is the name after a dot, or the computed key in brackets
+		property string
+		computed expr
+		optional bool
+	}
+	call struct {
+		callee   expr
+		args     []expr
+		optional bool
+	}
+	// chain ends an optional chain: a?.b.c is undefined when a is null or
+	// undefined
+	chain struct {
+		expr expr
+	}
+	unary struct {
+		op      string
+		operand expr
+	}
+	binary struct {
+		op          string
+		left, right expr
+	}
+	conditional struct {
+		test, consequent, alternate expr
+	}
+	arrow struct {
+		params []string
+		body   expr
+	}
+	newDate struct {
+		args []expr
+	}
+)
+
+// parser builds the syntax tree of an expression from its tokens
+type parser struct {
+	src    string
+	tokens []token
+	pos    int
+	depth  int
+}
+
+// compile parses the source of an expression
+func compile(src string) (expr, error) {
+	return compileNested(src, 0)
+}
+
+func compileNested(src string, depth int) (expr, error) {
+	tokens, err := lex(src)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{src: src, tokens: tokens, depth: depth}
+	x, err := p.expression()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != tokenEOF {
+		return nil, p.unexpected(t)
+	}
+	return x, nil
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokenEOF {
+		p.pos++
+	}
+	return t
+}
+
+// is reports whether the next token is the punctuator s
+func (p *parser) is(s string) bool {
+	t := p.peek()
+	return t.kind == tokenPunct && t.text == s
+}
+
+// accept consumes the next token if it is the punctuator s
+func (p *parser) accept(s string) bool {
+	if p.is(s) {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *parser) expect(s string) error {
+	if !p.accept(s) {
+		return p.unexpected(p.peek())
+	}
+	return nil
+}
+
+func (p *parser) unexpected(t token) error {
+	if t.kind == tokenEOF {
+		return fmt.Errorf("%w: unexpected end of expression", ErrSyntax)
+	}
+	text := t.text
+	if t.kind == tokenNumber {
+		text = p.src[t.pos:]
+		if i := strings.IndexAny(text, " \t\n)]},"); i > 0 {
+			text = text[:i]
+		}
+	}
+	return fmt.Errorf("%w: unexpected %q at %d", ErrSyntax, text, t.pos)
+}
+
+// nest guards against expressions nesting too deeply
+func (p *parser) nest() error {
+	p.depth++
+	if p.depth > maxNesting {
+		return fmt.Errorf("%w: expression nests deeper than %d levels", ErrSyntax, maxNesting)
+	}
+	return nil
+}
+
+func (p *parser) expression() (expr, error) {
+	if err := p.nest(); err != nil {
+		return nil, err
+	}
+	defer func() { p.depth-- }()
+
+	if params, ok := p.arrowParams(); ok {
+		body, err := p.expression()
+		if err != nil {
+			return nil, err
+		}
+		return &arrow{params: params, body: body}, nil
+	}
+
+	test, err := p.binary(0)
+	if err != nil {
+		return nil, err
+	}
+	if !p.accept("?") {
+		return test, nil
+	}
+	consequent, err := p.expression()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.expect(":"); err != nil {
+		return nil, err
+	}
+	alternate, err := p.expression()
+	if err != nil {
+		return nil, err
+	}
+	return &conditional{test: test, consequent: consequent, alternate: alternate}, nil
+}
+
+// arrowParams consumes the parameters of an arrow function and its =>,
+// x => or (a, b) =>, if the next tokens start one
+func (p *parser) arrowParams() ([]string, bool) {
+	t := p.peek()
+	if t.kind == tokenIdent {
+		if next := p.tokens[p.pos+1]; next.kind == tokenPunct && next.text == "=>" {
+			p.pos += 2
+			return []string{t.text}, true
+		}
+		return nil, false
+	}
+	if !p.is("(") {
+		return nil, false
+	}
+	var params []string
+	i := p.pos + 1
+	for {
+		t := p.tokens[i]
+		if t.kind == tokenPunct && t.text == ")" {
+			break
+		}
+		if t.kind != tokenIdent {
+			return nil, false
+		}
+		params = append(params, t.text)
+		i++
+		if sep := p.tokens[i]; sep.kind == tokenPunct && sep.text == "," {
+			i++
+		}
+	}
+	if next := p.tokens[i+1]; next.kind != tokenPunct || next.text != "=>" {
+		return nil, false
+	}
+	p.pos = i + 2
+	return params, true
+}
+
+// binaryLevels lists the binary operators from the loosest binding to the
+// tightest
+var binaryLevels = [][]string{
+	{"??"},
+	{"||"},
+	{"&&"},
+	{"===", "!==", "==", "!="},
+	{"<=", ">=", "<", ">"},
+	{"+", "-"},
+	{"*", "/", "%"},
+}
+
+// binary parses the left associative operators of a precedence level and
+// those binding tighter
+func (p *parser) binary(level int) (expr, error) {
+	if level == len(binaryLevels) {
+		return p.unary()
+	}
+	left, err := p.binary(level + 1)
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op := ""
+		for _, candidate := range binaryLevels[level] {
+			if p.is(candidate) {
+				op = candidate
+				break
+			}
+		}
+		if op == "" {
+			return left, nil
+		}
+		p.pos++
+		right, err := p.binary(level + 1)
+		if err != nil {
+			return nil, err
+		}
+		left = &binary{op: op, left: left, right: right}
+	}
+}
+
+func (p *parser) unary() (expr, error) {
+	t := p.peek()
+	if (t.kind == tokenPunct && (t.text == "!" || t.text == "-" || t.text == "+")) || (t.kind == tokenIdent && t.text == "typeof") {
+		if err := p.nest(); err != nil {
+			return nil, err
+		}
+		defer func() { p.depth-- }()
+		p.pos++
+		operand, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		return &unary{op: t.text, operand: operand}, nil
+	}
+	return p.postfix()
+}
+
+// postfix parses a primary expression followed by member accesses and
+// calls
+func (p *parser) postfix() (expr, error) {
+	x, err := p.primary()
+	if err != nil {
+		return nil, err
+	}
+	optional := false
+	for {
+		switch {
+		case p.accept("."):
+			name := p.next()
+			if name.kind != tokenIdent {
+				return nil, p.unexpected(name)
+			}
+			x = &member{object: x, property: name.text}
+		case p.accept("?."):
+			optional = true
+			switch {
+			case p.accept("["):
+				key, err := p.expression()
+				if err != nil {
+					return nil, err
+				}
+				if err := p.expect("]"); err != nil {
+					return nil, err
+				}
+				x = &member{object: x, computed: key, optional: true}
+			case p.accept("("):
+				args, err := p.arguments()
+				if err != nil {
+					return nil, err
+				}
+				x = &call{callee: x, args: args, optional: true}
+			default:
+				name := p.next()
+				if name.kind != tokenIdent {
+					return nil, p.unexpected(name)
+				}
+				x = &member{object: x, property: name.text, optional: true}
+			}
+		case p.accept("["):
+			key, err := p.expression()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expect("]"); err != nil {
+				return nil, err
+			}
+			x = &member{object: x, computed: key}
+		case p.accept("("):
+			args, err := p.arguments()
+			if err != nil {
+				return nil, err
+			}
+			x = &call{callee: x, args: args}
+		default:
+			if optional {
+				return &chain{expr: x}, nil
+			}
+			return x, nil
+		}
+	}
+}
+
+// arguments parses the arguments of a call after its (
+func (p *parser) arguments() ([]expr, error) {
+	var args []expr
+	for !p.accept(")") {
+		arg, err := p.expression()
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, arg)
+		if !p.accept(",") {
+			if err := p.expect(")"); err != nil {
+				return nil, err
+			}
+			break
+		}
+	}
+	return args, nil
+}
+
+func (p *parser) primary() (expr, error) {
+	t := p.next()
+	switch t.kind {
+	case tokenNumber:
+		return &literal{value: t.number}, nil
+	case tokenString:
+		return &literal{value: t.text}, nil
+	case tokenTemplate:
+		return p.template(t)
+	case tokenIdent:
+		switch t.text {
+		case "true":
+			return &literal{value: true}, nil
+		case "false":
+			return &literal{value: false}, nil
+		case "null":
+			return &literal{value: nil}, nil
+		case "undefined":
+			return &literal{value: undefined}, nil
+		case "new":
+			return p.construct()
+		}
+		return &identifier{name: t.text}, nil
+	case tokenPunct:
+		switch t.text {
+		case "(":
+			x, err := p.expression()
+			if err != nil {
+				return nil, err
+			}
+			return x, p.expect(")")
+		case "[":
+			return p.array()
+		case "{":
+			return p.object()
+		}
+	}
+	return nil, p.unexpected(t)
+}
+
+// construct parses the class and arguments after new. Dates are the only
+// objects expressions can construct.
+func (p *parser) construct() (expr, error) {
+	class := p.next()
+	if class.kind != tokenIdent || class.text != "Date" {
+		return nil, fmt.Errorf("%w: only new Date() is supported", ErrSyntax)
+	}
+	var args []expr
+	if p.accept("(") {
+		var err error
+		if args, err = p.arguments(); err != nil {
+			return nil, err
+		}
+	}
+	return &newDate{args: args}, nil
+}
+
+func (p *parser) array() (expr, error) {
+	a := &arrayLiteral{}
+	for !p.accept("]") {
+		element, err := p.expression()
+		if err != nil {
+			return nil, err
+		}
+		a.elements = append(a.elements, element)
+		if !p.accept(",") {
+			if err := p.expect("]"); err != nil {
+				return nil, err
+			}
+			break
+		}
+	}
+	return a, nil
+}
+
+func (p *parser) object() (expr, error) {
+	o := &objectLiteral{}
+	for !p.accept("}") {
+		key := p.next()
+		var name string
+		switch key.kind {
+		case tokenIdent, tokenString:
+			name = key.text
+		case tokenNumber:
+			name = formatNumber(key.number)
+		default:
+			return nil, p.unexpected(key)
+		}
+
+		var value expr = &identifier{name: name}
+		if p.accept(":") {
+			var err error
+			if value, err = p.expression(); err != nil {
+				return nil, err
+			}
+		} else if key.kind != tokenIdent {
+			return nil, p.unexpected(p.peek())
+		}
+		o.keys = append(o.keys, name)
+		o.values = append(o.values, value)
+
+		if !p.accept(",") {
+			if err := p.expect("}"); err != nil {
+				return nil, err
+			}
+			break
+		}
+	}
+	return o, nil
+}
+
+// template parses the body of a template literal into its literal parts
+// and its ${} substitutions
+func (p *parser) template(t token) (expr, error) {
+	raw := t.text
+	tl := &templateLiteral{}
+	var quasi strings.Builder
+	for i := 0; i < len(raw); i++ {
+		switch {
+		case raw[i] == '\\':
+			s, next, err := unescape(raw, i)
+			if err != nil {
+				return nil, err
+			}
+			quasi.WriteString(s)
+			i = next - 1
+		case raw[i] == '$' && i+1 < len(raw) && raw[i+1] == '{':
+			end, err := substitutionEnd(raw, i+2)
+			if err != nil {
+				return nil, err
+			}
+			sub, err := compileNested(raw[i+2:end], p.depth+1)
+			if err != nil {
+				return nil, err
+			}
+			tl.quasis = append(tl.quasis, quasi.String())
+			tl.substitutions = append(tl.substitutions, sub)
+			quasi.Reset()
+			i = end
+		default:
+			quasi.WriteByte(raw[i])
+		}
+	}
+	tl.quasis = append(tl.quasis, quasi.String())
+	return tl, nil
+}
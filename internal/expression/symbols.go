@@ -0,0 +1,82 @@
+package expression
+
+// Symbol kinds
+const (
+	KindObject   = "object"
+	KindFunction = "function"
+	KindString   = "string"
+	KindNumber   = "number"
+	KindBoolean  = "boolean"
+	KindArray    = "array"
+	KindDate     = "date"
+	KindNull     = "null"
+	KindAny      = "any"
+)
+
+// Symbol is a name expressions can use, such as $json or $input.first()
+type Symbol struct {
+	Name        string `json:"name"`
+	Kind        string `json:"kind"`
+	Description string `json:"description"`
+	// Volatile symbols evaluate differently each time and are not cached
+	Volatile bool     `json:"volatile,omitempty"`
+	Members  []Symbol `json:"members,omitempty"`
+}
+
+// symbols is the symbol table of the expression engine. Members are named
+// as accessed from their parent, with () for functions.
+var symbols = []Symbol{
+	{Name: "$json", Kind: KindObject, Description: "JSON data of the current input item"},
+	{Name: "$binary", Kind: KindObject, Description: "Binary data of the current input item"},
+	{Name: "$input", Kind: KindObject, Description: "Input items of the node", Members: []Symbol{
+		{Name: "item", Kind: KindObject, Description: "Current input item"},
+		{Name: "all()", Kind: KindFunction, Description: "All input items"},
+		{Name: "first()", Kind: KindFunction, Description: "First input item"},
+		{Name: "last()", Kind: KindFunction, Description: "Last input item"},
+	}},
+	{Name: "$node", Kind: KindObject, Description: "Output of an upstream node by name, as $node[\"Name\"].json"},
+	{Name: "$", Kind: KindFunction, Description: "Output of an upstream node by name, as $(\"Name\").item.json", Members: []Symbol{
+		{Name: "item", Kind: KindObject, Description: "Item of the node paired with the current input item"},
+		{Name: "all()", Kind: KindFunction, Description: "All output items of the node"},
+		{Name: "first()", Kind: KindFunction, Description: "First output item of the node"},
+		{Name: "last()", Kind: KindFunction, Description: "Last output item of the node"},
+	}},
+	{Name: "$item", Kind: KindFunction, Description: "Input item at an index, as $item(0).$json or $item(0).$node[\"Name\"].json"},
+	{Name: "$vars", Kind: KindObject, Description: "Variables of the workflow"},
+	{Name: "$secrets", Kind: KindObject, Description: "Secrets of the workflow, as $secrets.NAME"},
+	{Name: "$env", Kind: KindObject, Description: "Environment variables the instance exposes to expressions"},
+	{Name: "$execution", Kind: KindObject, Description: "Current execution", Members: []Symbol{
+		{Name: "id", Kind: KindString, Description: "Execution ID"},
+		{Name: "mode", Kind: KindString, Description: "How the execution was started"},
+		{Name: "correlationId", Kind: KindString, Description: "Correlation ID shared by the executions of a chain"},
+	}},
+	{Name: "$workflow", Kind: KindObject, Description: "Current workflow", Members: []Symbol{
+		{Name: "id", Kind: KindString, Description: "Workflow ID"},
+		{Name: "name", Kind: KindString, Description: "Workflow name"},
+		{Name: "active", Kind: KindBoolean, Description: "Whether the workflow is active"},
+	}},
+	{Name: "$runIndex", Kind: KindNumber, Description: "How many times the node ran before in this execution"},
+	{Name: "$itemIndex", Kind: KindNumber, Description: "Index of the current input item"},
+	{Name: "$now", Kind: KindDate, Description: "Current date and time"},
+	{Name: "$today", Kind: KindDate, Description: "Start of the current day"},
+	{Name: "$uuid", Kind: KindFunction, Description: "Random UUID"},
+	{Name: "$ifEmpty", Kind: KindFunction, Description: "First argument, or the second when the first is null, undefined, an empty string, array or object"},
+	{Name: "DateTime", Kind: KindObject, Description: "Date functions", Members: []Symbol{
+		{Name: "now()", Kind: KindFunction, Description: "Current date and time"},
+		{Name: "fromISO()", Kind: KindFunction, Description: "Date of an ISO 8601 string"},
+		{Name: "fromMillis()", Kind: KindFunction, Description: "Date of milliseconds since the epoch"},
+		{Name: "fromSeconds()", Kind: KindFunction, Description: "Date of seconds since the epoch"},
+	}},
+}
+
+func init() {
+	for i := range symbols {
+		symbols[i].Volatile = Volatile(symbols[i].Name)
+	}
+}
+
+// Symbols returns the symbol table of the expression engine. The returned
+// symbols are shared and must not be modified.
+func Symbols() []Symbol {
+	return symbols
+}
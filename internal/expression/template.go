@@ -0,0 +1,175 @@
+// Package expression parses and evaluates the {{ }} expressions embedded in
+// node parameter values
+package expression
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	// ErrUnclosedExpression is returned for a {{ without a matching }}
+	ErrUnclosedExpression = errors.New("expression is not closed")
+	// ErrEmptyExpression is returned for {{ }} without a body
+	ErrEmptyExpression = errors.New("expression is empty")
+)
+
+const (
+	openDelim  = "{{"
+	closeDelim = "}}"
+)
+
+// Segment is a run of literal text or an embedded expression
+type Segment struct {
+	// Text is the literal text, or the trimmed source of the expression
+	Text string
+	Expr bool
+
+	// program is the compiled expression, or err why it does not compile
+	program expr
+	err     error
+}
+
+// Template is a parameter string split into literal text and expressions
+type Template struct {
+	Source   string
+	Segments []Segment
+}
+
+// HasExpression reports whether s embeds an expression
+func HasExpression(s string) bool {
+	return strings.Contains(s, openDelim)
+}
+
+// Parse splits s into literal and expression segments. A }} inside a quoted
+// string does not close an expression.
+func Parse(s string) (*Template, error) {
+	t := &Template{Source: s}
+	rest := s
+	for {
+		start := strings.Index(rest, openDelim)
+		if start < 0 {
+			break
+		}
+		if start > 0 {
+			t.Segments = append(t.Segments, Segment{Text: rest[:start]})
+		}
+		rest = rest[start+len(openDelim):]
+
+		end := closingDelim(rest)
+		if end < 0 {
+			return nil, ErrUnclosedExpression
+		}
+		body := strings.TrimSpace(rest[:end])
+		if body == "" {
+			return nil, ErrEmptyExpression
+		}
+		program, err := compile(body)
+		t.Segments = append(t.Segments, Segment{Text: body, Expr: true, program: program, err: err})
+		rest = rest[end+len(closeDelim):]
+	}
+	if rest != "" {
+		t.Segments = append(t.Segments, Segment{Text: rest})
+	}
+	return t, nil
+}
+
+// closingDelim returns the index of the }} closing an expression body,
+// skipping over quoted strings, or -1
+func closingDelim(s string) int {
+	var quote byte
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote != 0:
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'' || c == '`':
+			quote = c
+		case strings.HasPrefix(s[i:], closeDelim):
+			return i
+		}
+	}
+	return -1
+}
+
+// Single reports whether the template is exactly one expression, apart from
+// surrounding whitespace. Its value keeps its type instead of being rendered
+// into a string.
+func (t *Template) Single() bool {
+	expr := -1
+	for i, seg := range t.Segments {
+		if seg.Expr {
+			if expr >= 0 {
+				return false
+			}
+			expr = i
+		} else if strings.TrimSpace(seg.Text) != "" {
+			return false
+		}
+	}
+	return expr >= 0
+}
+
+// Expressions returns the sources of the expressions in the template
+func (t *Template) Expressions() []string {
+	var exprs []string
+	for _, seg := range t.Segments {
+		if seg.Expr {
+			exprs = append(exprs, seg.Text)
+		}
+	}
+	return exprs
+}
+
+// Evaluate evaluates the template for an item. A template that is a single
+// expression evaluates to the value of the expression, keeping its type;
+// any other renders its expressions into the text, null and undefined as
+// empty strings.
+func (t *Template) Evaluate(data *Data) (interface{}, error) {
+	return t.evaluate(func(seg Segment) (interface{}, error) {
+		return seg.evaluate(data)
+	})
+}
+
+func (t *Template) evaluate(eval func(seg Segment) (interface{}, error)) (interface{}, error) {
+	if t.Single() {
+		for _, seg := range t.Segments {
+			if seg.Expr {
+				return eval(seg)
+			}
+		}
+	}
+
+	var out strings.Builder
+	for _, seg := range t.Segments {
+		if !seg.Expr {
+			out.WriteString(seg.Text)
+			continue
+		}
+		v, err := eval(seg)
+		if err != nil {
+			return nil, err
+		}
+		if v != nil {
+			out.WriteString(toString(v))
+		}
+	}
+	return out.String(), nil
+}
+
+// evaluate evaluates the expression of a segment, exporting its value
+func (seg Segment) evaluate(data *Data) (interface{}, error) {
+	if seg.err != nil {
+		return nil, fmt.Errorf("{{ %s }}: %w", seg.Text, seg.err)
+	}
+	v, err := newEvaluator(data).evalExpr(seg.program, nil)
+	if err != nil {
+		return nil, fmt.Errorf("{{ %s }}: %w", seg.Text, err)
+	}
+	return export(v), nil
+}
@@ -0,0 +1,458 @@
+package expression
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// undefinedValue is the type of undefined, the value of missing properties
+type undefinedValue struct{}
+
+var undefined = undefinedValue{}
+
+// function is a function expressions can call: a built-in or an arrow
+// function
+type function func(e *evaluator, args []interface{}) (interface{}, error)
+
+// normalize converts a Go value read from the data of an execution into
+// one of the values expressions work with: nil, undefined, bool, float64,
+// string, time.Time, []interface{}, map[string]interface{} or a function
+func normalize(v interface{}) interface{} {
+	switch v := v.(type) {
+	case nil, undefinedValue, bool, float64, string, time.Time, []interface{}, map[string]interface{}, function, nodeIndex, nodeOutput:
+		return v
+	case int:
+		return float64(v)
+	case int8:
+		return float64(v)
+	case int16:
+		return float64(v)
+	case int32:
+		return float64(v)
+	case int64:
+		return float64(v)
+	case uint:
+		return float64(v)
+	case uint8:
+		return float64(v)
+	case uint16:
+		return float64(v)
+	case uint32:
+		return float64(v)
+	case uint64:
+		return float64(v)
+	case float32:
+		return float64(v)
+	case json.Number:
+		f, _ := v.Float64()
+		return f
+	case []string:
+		out := make([]interface{}, len(v))
+		for i, s := range v {
+			out[i] = s
+		}
+		return out
+	case map[string]string:
+		out := make(map[string]interface{}, len(v))
+		for k, s := range v {
+			out[k] = s
+		}
+		return out
+	case []map[string]interface{}:
+		out := make([]interface{}, len(v))
+		for i, m := range v {
+			out[i] = m
+		}
+		return out
+	}
+
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Array:
+		if rv.Kind() == reflect.Slice && rv.IsNil() {
+			return nil
+		}
+		out := make([]interface{}, rv.Len())
+		for i := range out {
+			out[i] = rv.Index(i).Interface()
+		}
+		return out
+	case reflect.Map:
+		if rv.Type().Key().Kind() != reflect.String {
+			break
+		}
+		if rv.IsNil() {
+			return nil
+		}
+		out := make(map[string]interface{}, rv.Len())
+		iter := rv.MapRange()
+		for iter.Next() {
+			out[iter.Key().String()] = iter.Value().Interface()
+		}
+		return out
+	case reflect.Ptr, reflect.Interface:
+		if rv.IsNil() {
+			return nil
+		}
+	}
+	// Anything else is read as its JSON form
+	data, err := json.Marshal(v)
+	if err != nil {
+		return undefined
+	}
+	var out interface{}
+	if err := json.Unmarshal(data, &out); err != nil {
+		return undefined
+	}
+	return out
+}
+
+// export converts the value of an expression into a value parameters can
+// hold: dates become ISO 8601 strings, undefined and functions nil
+func export(v interface{}) interface{} {
+	switch v := v.(type) {
+	case undefinedValue, function:
+		return nil
+	case time.Time:
+		return formatISO(v)
+	case float64:
+		// JSON has no NaN or infinities
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return nil
+		}
+		return v
+	case []interface{}:
+		out := make([]interface{}, len(v))
+		for i, item := range v {
+			out[i] = export(normalize(item))
+		}
+		return out
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(v))
+		for k, item := range v {
+			if _, ok := item.(function); ok {
+				continue
+			}
+			out[k] = export(normalize(item))
+		}
+		return out
+	}
+	return v
+}
+
+// truthy converts a value to a boolean as JavaScript does
+func truthy(v interface{}) bool {
+	switch v := v.(type) {
+	case nil, undefinedValue:
+		return false
+	case bool:
+		return v
+	case float64:
+		return v != 0 && !math.IsNaN(v)
+	case string:
+		return v != ""
+	}
+	return true
+}
+
+// toNumber converts a value to a number as JavaScript does
+func toNumber(v interface{}) float64 {
+	switch v := v.(type) {
+	case nil:
+		return 0
+	case bool:
+		if v {
+			return 1
+		}
+		return 0
+	case float64:
+		return v
+	case string:
+		s := strings.TrimSpace(v)
+		if s == "" {
+			return 0
+		}
+		if strings.HasPrefix(s, "0x") || strings.HasPrefix(s, "0X") {
+			if n, err := strconv.ParseInt(s[2:], 16, 64); err == nil {
+				return float64(n)
+			}
+			return math.NaN()
+		}
+		switch s {
+		case "Infinity", "+Infinity":
+			return math.Inf(1)
+		case "-Infinity":
+			return math.Inf(-1)
+		}
+		n, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return math.NaN()
+		}
+		return n
+	case time.Time:
+		return float64(v.UnixMilli())
+	case []interface{}:
+		switch len(v) {
+		case 0:
+			return 0
+		case 1:
+			return toNumber(normalize(v[0]))
+		}
+	}
+	return math.NaN()
+}
+
+// toString converts a value to a string. Objects and arrays are rendered
+// as JSON rather than as JavaScript's [object Object].
+func toString(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return "null"
+	case undefinedValue:
+		return "undefined"
+	case bool:
+		return strconv.FormatBool(v)
+	case float64:
+		return formatNumber(v)
+	case string:
+		return v
+	case time.Time:
+		return formatISO(v)
+	case function:
+		return "function"
+	}
+	s, err := stringify(v)
+	if err != nil {
+		return ""
+	}
+	return s
+}
+
+// stringify converts a value to a string as toString does, but fails for
+// arrays and objects whose JSON would be longer than maxStringLength. The
+// JSON is written a value at a time and given up once too long, so arrays
+// holding the same long string many times are never built in full.
+// Strings built from values use it rather than toString, which gives the
+// empty string for those.
+func stringify(v interface{}) (string, error) {
+	switch v.(type) {
+	case nil, undefinedValue, bool, float64, string, time.Time, function:
+		return toString(v), nil
+	}
+	var b strings.Builder
+	if err := writeJSON(&b, v, "", 0); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// writeJSON writes the JSON of a value as json.Marshal writes its export,
+// or as json.MarshalIndent does with a non-empty indent, failing once b
+// grows past maxStringLength
+func writeJSON(b *strings.Builder, v interface{}, indent string, depth int) error {
+	newline := func(depth int) error {
+		if indent == "" {
+			return nil
+		}
+		if b.Len()+1+len(indent)*depth > maxStringLength {
+			return errStringTooLong()
+		}
+		b.WriteByte('\n')
+		for i := 0; i < depth; i++ {
+			b.WriteString(indent)
+		}
+		return nil
+	}
+
+	switch v := normalize(v).(type) {
+	case []interface{}:
+		if len(v) == 0 {
+			b.WriteString("[]")
+			break
+		}
+		b.WriteByte('[')
+		for i, item := range v {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			if err := newline(depth + 1); err != nil {
+				return err
+			}
+			if err := writeJSON(b, item, indent, depth+1); err != nil {
+				return err
+			}
+		}
+		if err := newline(depth); err != nil {
+			return err
+		}
+		b.WriteByte(']')
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for k, item := range v {
+			if _, ok := item.(function); !ok {
+				keys = append(keys, k)
+			}
+		}
+		if len(keys) == 0 {
+			b.WriteString("{}")
+			break
+		}
+		sort.Strings(keys)
+		b.WriteByte('{')
+		for i, k := range keys {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			if err := newline(depth + 1); err != nil {
+				return err
+			}
+			if err := writeJSON(b, k, indent, depth+1); err != nil {
+				return err
+			}
+			b.WriteByte(':')
+			if indent != "" {
+				b.WriteByte(' ')
+			}
+			if err := writeJSON(b, v[k], indent, depth+1); err != nil {
+				return err
+			}
+		}
+		if err := newline(depth); err != nil {
+			return err
+		}
+		b.WriteByte('}')
+	default:
+		if s, ok := v.(string); ok && len(s) > maxStringLength-b.Len() {
+			return errStringTooLong()
+		}
+		data, err := json.Marshal(export(v))
+		if err != nil {
+			return fmt.Errorf("%w: %v", ErrEvaluation, err)
+		}
+		b.Write(data)
+	}
+	if b.Len() > maxStringLength {
+		return errStringTooLong()
+	}
+	return nil
+}
+
+// formatNumber formats a number as JavaScript does
+func formatNumber(n float64) string {
+	switch {
+	case math.IsNaN(n):
+		return "NaN"
+	case math.IsInf(n, 1):
+		return "Infinity"
+	case math.IsInf(n, -1):
+		return "-Infinity"
+	case n == 0:
+		return "0"
+	}
+	if math.Abs(n) >= 1e21 || math.Abs(n) < 1e-6 {
+		return strconv.FormatFloat(n, 'g', -1, 64)
+	}
+	return strconv.FormatFloat(n, 'f', -1, 64)
+}
+
+// typeOf returns the typeof of a value
+func typeOf(v interface{}) string {
+	switch v.(type) {
+	case undefinedValue:
+		return "undefined"
+	case bool:
+		return "boolean"
+	case float64:
+		return "number"
+	case string:
+		return "string"
+	case function:
+		return "function"
+	}
+	return "object"
+}
+
+// strictEquals compares values as === does. Arrays and objects are equal
+// only to themselves.
+func strictEquals(a, b interface{}) bool {
+	switch a := a.(type) {
+	case nil:
+		return b == nil
+	case undefinedValue:
+		_, ok := b.(undefinedValue)
+		return ok
+	case bool:
+		bb, ok := b.(bool)
+		return ok && a == bb
+	case float64:
+		bf, ok := b.(float64)
+		return ok && a == bf
+	case string:
+		bs, ok := b.(string)
+		return ok && a == bs
+	case time.Time:
+		bt, ok := b.(time.Time)
+		return ok && a.Equal(bt)
+	case []interface{}, map[string]interface{}:
+		if reflect.TypeOf(a) != reflect.TypeOf(b) {
+			return false
+		}
+		return reflect.ValueOf(a).Pointer() == reflect.ValueOf(b).Pointer()
+	}
+	return false
+}
+
+// looseEquals compares values as == does
+func looseEquals(a, b interface{}) bool {
+	if nullish(a) || nullish(b) {
+		return nullish(a) && nullish(b)
+	}
+	if typeOf(a) == typeOf(b) {
+		return strictEquals(a, b)
+	}
+	_, aObject := a.(map[string]interface{})
+	_, bObject := b.(map[string]interface{})
+	_, aArray := a.([]interface{})
+	_, bArray := b.([]interface{})
+	if aObject || bObject || aArray || bArray {
+		as, aErr := stringify(a)
+		bs, bErr := stringify(b)
+		return aErr == nil && bErr == nil && as == bs
+	}
+	return toNumber(a) == toNumber(b)
+}
+
+// nullish reports whether a value is null or undefined
+func nullish(v interface{}) bool {
+	if v == nil {
+		return true
+	}
+	_, ok := v.(undefinedValue)
+	return ok
+}
+
+// compare orders two values for <, >, <= and >=: strings by their text,
+// anything else by number. ok is false when either is NaN.
+func compare(a, b interface{}) (result int, ok bool) {
+	as, aString := a.(string)
+	bs, bString := b.(string)
+	if aString && bString {
+		return strings.Compare(as, bs), true
+	}
+	an, bn := toNumber(a), toNumber(b)
+	switch {
+	case math.IsNaN(an) || math.IsNaN(bn):
+		return 0, false
+	case an < bn:
+		return -1, true
+	case an > bn:
+		return 1, true
+	}
+	return 0, true
+}
@@ -0,0 +1,128 @@
+// Package alerting implements the channels operational alerts are sent
+// to: Slack and Microsoft Teams incoming webhooks and email.
+package alerting
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jaydeep/go-n8n/configs"
+	"github.com/jaydeep/go-n8n/internal/domain/alert"
+)
+
+// Channel types
+const (
+	ChannelSlack = "slack"
+	ChannelTeams = "teams"
+	ChannelEmail = "email"
+
+	defaultTimeout = 10 * time.Second
+)
+
+var (
+	ErrUnknownChannelType = errors.New("unknown alert channel type")
+	ErrChannelConfig      = errors.New("invalid alert channel configuration")
+)
+
+// NewChannels creates the configured channels. Email channels send with
+// smtp.
+func NewChannels(cfgs []configs.AlertChannelConfig, smtp configs.SMTPConfig) ([]alert.Channel, error) {
+	channels := make([]alert.Channel, 0, len(cfgs))
+	for _, cfg := range cfgs {
+		if cfg.Name == "" {
+			cfg.Name = cfg.Type
+		}
+		if cfg.Timeout <= 0 {
+			cfg.Timeout = defaultTimeout
+		}
+
+		var (
+			channel alert.Channel
+			err     error
+		)
+		switch cfg.Type {
+		case ChannelSlack:
+			channel, err = NewSlackChannel(cfg)
+		case ChannelTeams:
+			channel, err = NewTeamsChannel(cfg)
+		case ChannelEmail:
+			channel, err = NewEmailChannel(cfg, smtp)
+		default:
+			err = fmt.Errorf("%w: %q", ErrUnknownChannelType, cfg.Type)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("channel %s: %w", cfg.Name, err)
+		}
+		channels = append(channels, channel)
+	}
+	return channels, nil
+}
+
+// title is the one-line headline of an alert notification
+func title(a *alert.Alert) string {
+	if a.IsFiring() {
+		return "[FIRING] " + a.Summary
+	}
+	return "[RESOLVED] " + a.Summary
+}
+
+// fact is a labelled value shown in a notification
+type fact struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// facts lists when the alert fired and resolved followed by its details,
+// sorted by name
+func facts(a *alert.Alert) []fact {
+	list := []fact{
+		{Name: "kind", Value: string(a.Kind)},
+		{Name: "fired_at", Value: a.FiredAt.UTC().Format(time.RFC3339)},
+	}
+	if a.ResolvedAt != nil {
+		list = append(list, fact{Name: "resolved_at", Value: a.ResolvedAt.UTC().Format(time.RFC3339)})
+	}
+
+	names := make([]string, 0, len(a.Details))
+	for name := range a.Details {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		list = append(list, fact{Name: name, Value: fmt.Sprint(a.Details[name])})
+	}
+	return list
+}
+
+// text renders an alert as plain text lines
+func text(a *alert.Alert) string {
+	var b strings.Builder
+	for _, f := range facts(a) {
+		b.WriteString(f.Name)
+		b.WriteString(": ")
+		b.WriteString(f.Value)
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// send performs an HTTP request and fails on non-2xx responses
+func send(client *http.Client, req *http.Request) error {
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("unexpected status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	io.Copy(io.Discard, resp.Body)
+	return nil
+}
@@ -0,0 +1,126 @@
+package alerting
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/jaydeep/go-n8n/configs"
+	"github.com/jaydeep/go-n8n/internal/domain/alert"
+)
+
+// Colors of firing and resolved notifications
+const (
+	colorFiring   = "#D63232"
+	colorResolved = "#2EB67D"
+)
+
+func color(a *alert.Alert) string {
+	if a.IsFiring() {
+		return colorFiring
+	}
+	return colorResolved
+}
+
+// webhookChannel POSTs JSON messages to an incoming webhook
+type webhookChannel struct {
+	name   string
+	url    string
+	client *http.Client
+}
+
+func newWebhookChannel(cfg configs.AlertChannelConfig) (webhookChannel, error) {
+	if cfg.URL == "" {
+		return webhookChannel{}, fmt.Errorf("%w: url is required", ErrChannelConfig)
+	}
+	return webhookChannel{
+		name:   cfg.Name,
+		url:    cfg.URL,
+		client: &http.Client{Timeout: cfg.Timeout},
+	}, nil
+}
+
+// Name identifies the channel in logs and test results
+func (c webhookChannel) Name() string {
+	return c.name
+}
+
+func (c webhookChannel) post(ctx context.Context, message interface{}) error {
+	body, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	return send(c.client, req)
+}
+
+// SlackChannel posts alerts to a Slack incoming webhook as an attachment
+// colored by status
+type SlackChannel struct {
+	webhookChannel
+}
+
+// NewSlackChannel creates a Slack channel
+func NewSlackChannel(cfg configs.AlertChannelConfig) (*SlackChannel, error) {
+	c, err := newWebhookChannel(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &SlackChannel{webhookChannel: c}, nil
+}
+
+type slackField struct {
+	Title string `json:"title"`
+	Value string `json:"value"`
+	Short bool   `json:"short"`
+}
+
+// Notify posts the alert
+func (c *SlackChannel) Notify(ctx context.Context, a *alert.Alert) error {
+	fields := make([]slackField, 0, len(a.Details)+3)
+	for _, f := range facts(a) {
+		fields = append(fields, slackField{Title: f.Name, Value: f.Value, Short: true})
+	}
+	return c.post(ctx, map[string]interface{}{
+		"text": title(a),
+		"attachments": []map[string]interface{}{{
+			"color":  color(a),
+			"fields": fields,
+		}},
+	})
+}
+
+// TeamsChannel posts alerts to a Microsoft Teams incoming webhook as a
+// message card
+type TeamsChannel struct {
+	webhookChannel
+}
+
+// NewTeamsChannel creates a Teams channel
+func NewTeamsChannel(cfg configs.AlertChannelConfig) (*TeamsChannel, error) {
+	c, err := newWebhookChannel(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &TeamsChannel{webhookChannel: c}, nil
+}
+
+// Notify posts the alert
+func (c *TeamsChannel) Notify(ctx context.Context, a *alert.Alert) error {
+	return c.post(ctx, map[string]interface{}{
+		"@type":      "MessageCard",
+		"@context":   "https://schema.org/extensions",
+		"summary":    title(a),
+		"title":      title(a),
+		"themeColor": color(a)[1:],
+		"sections": []map[string]interface{}{{
+			"facts": facts(a),
+		}},
+	})
+}
@@ -0,0 +1,45 @@
+package alerting
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jaydeep/go-n8n/configs"
+	"github.com/jaydeep/go-n8n/internal/domain/alert"
+	"github.com/jaydeep/go-n8n/internal/infrastructure/email"
+)
+
+// EmailChannel mails alerts to a list of recipients
+type EmailChannel struct {
+	name string
+	cfg  configs.AlertChannelConfig
+	smtp configs.SMTPConfig
+}
+
+// NewEmailChannel creates an email channel sending with smtp
+func NewEmailChannel(cfg configs.AlertChannelConfig, smtp configs.SMTPConfig) (*EmailChannel, error) {
+	if len(cfg.To) == 0 {
+		return nil, fmt.Errorf("%w: to is required", ErrChannelConfig)
+	}
+	if smtp.Host == "" {
+		return nil, fmt.Errorf("%w: the SMTP host is not configured", ErrChannelConfig)
+	}
+	return &EmailChannel{name: cfg.Name, cfg: cfg, smtp: smtp}, nil
+}
+
+// Name identifies the channel in logs and test results
+func (c *EmailChannel) Name() string {
+	return c.name
+}
+
+// Notify mails the alert
+func (c *EmailChannel) Notify(ctx context.Context, a *alert.Alert) error {
+	ctx, cancel := context.WithTimeout(ctx, c.cfg.Timeout)
+	defer cancel()
+
+	return email.Send(ctx, c.smtp, email.Message{
+		To:      c.cfg.To,
+		Subject: title(a),
+		Body:    title(a) + "\n\n" + text(a),
+	})
+}
@@ -0,0 +1,110 @@
+package analytics
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+
+	"github.com/jaydeep/go-n8n/configs"
+	"github.com/jaydeep/go-n8n/internal/domain/execution"
+)
+
+// tableNamePattern accepts table and database.table names
+var tableNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_][A-Za-z0-9_]*)?$`)
+
+// ClickHouseSink inserts events through the ClickHouse HTTP interface as
+// JSONEachRow. Columns are matched to the JSON fields of execution.Event by
+// name, for example:
+//
+//	CREATE TABLE n8n.execution_events (
+//	    id UUID,
+//	    type LowCardinality(String),
+//	    timestamp DateTime64(3, 'UTC'),
+//	    execution_id UUID,
+//	    workflow_id UUID,
+//	    workflow_version UInt32,
+//	    mode LowCardinality(String),
+//	    status LowCardinality(String),
+//	    node_name String,
+//	    node_type LowCardinality(String),
+//	    duration_ms UInt64,
+//	    error_message String,
+//	    error_node String,
+//	    retry_count UInt32,
+//	    dry_run Bool
+//	) ENGINE = MergeTree
+//	PARTITION BY toYYYYMM(timestamp)
+//	ORDER BY (workflow_id, timestamp)
+type ClickHouseSink struct {
+	name     string
+	endpoint string
+	cfg      configs.EventSinkConfig
+	client   *http.Client
+}
+
+// NewClickHouseSink creates a ClickHouse sink
+func NewClickHouseSink(cfg configs.EventSinkConfig) (*ClickHouseSink, error) {
+	if cfg.URL == "" {
+		return nil, fmt.Errorf("%w: url is required", ErrSinkConfig)
+	}
+	if !tableNamePattern.MatchString(cfg.Table) {
+		return nil, fmt.Errorf("%w: table must be a name such as db.table", ErrSinkConfig)
+	}
+
+	u, err := url.Parse(cfg.URL)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrSinkConfig, err)
+	}
+	q := u.Query()
+	q.Set("query", "INSERT INTO "+cfg.Table+" FORMAT JSONEachRow")
+	q.Set("date_time_input_format", "best_effort")
+	q.Set("input_format_skip_unknown_fields", "1")
+	u.RawQuery = q.Encode()
+
+	return &ClickHouseSink{
+		name:     cfg.Name,
+		endpoint: u.String(),
+		cfg:      cfg,
+		client:   &http.Client{Timeout: cfg.Timeout},
+	}, nil
+}
+
+// Name identifies the sink in logs
+func (s *ClickHouseSink) Name() string {
+	return s.name
+}
+
+// Write inserts a batch of events in one request
+func (s *ClickHouseSink) Write(ctx context.Context, events []*execution.Event) error {
+	var body bytes.Buffer
+	enc := json.NewEncoder(&body)
+	for _, event := range events {
+		if err := enc.Encode(event); err != nil {
+			return err
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.endpoint, &body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-ndjson")
+	if s.cfg.Username != "" {
+		req.Header.Set("X-ClickHouse-User", s.cfg.Username)
+		req.Header.Set("X-ClickHouse-Key", s.cfg.Password)
+	}
+	for name, value := range s.cfg.Headers {
+		req.Header.Set(name, value)
+	}
+	return send(s.client, req)
+}
+
+// Close releases idle connections
+func (s *ClickHouseSink) Close() error {
+	s.client.CloseIdleConnections()
+	return nil
+}
@@ -0,0 +1,74 @@
+package analytics
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/jaydeep/go-n8n/configs"
+	"github.com/jaydeep/go-n8n/internal/domain/execution"
+	"github.com/segmentio/kafka-go"
+)
+
+// KafkaSink produces one JSON message per event. Messages are keyed by
+// execution ID so the events of an execution stay ordered within a
+// partition.
+type KafkaSink struct {
+	name   string
+	writer *kafka.Writer
+}
+
+// NewKafkaSink creates a Kafka sink
+func NewKafkaSink(cfg configs.EventSinkConfig) (*KafkaSink, error) {
+	if len(cfg.Brokers) == 0 {
+		return nil, fmt.Errorf("%w: brokers are required", ErrSinkConfig)
+	}
+	if cfg.Topic == "" {
+		return nil, fmt.Errorf("%w: topic is required", ErrSinkConfig)
+	}
+
+	return &KafkaSink{
+		name: cfg.Name,
+		writer: &kafka.Writer{
+			Addr:         kafka.TCP(cfg.Brokers...),
+			Topic:        cfg.Topic,
+			Balancer:     &kafka.Hash{},
+			RequiredAcks: kafka.RequireAll,
+			// Events arrive batched by the pipeline; send them right away
+			BatchTimeout: 10 * time.Millisecond,
+			WriteTimeout: cfg.Timeout,
+			Compression:  kafka.Lz4,
+		},
+	}, nil
+}
+
+// Name identifies the sink in logs
+func (s *KafkaSink) Name() string {
+	return s.name
+}
+
+// Write produces a batch of events
+func (s *KafkaSink) Write(ctx context.Context, events []*execution.Event) error {
+	messages := make([]kafka.Message, 0, len(events))
+	for _, event := range events {
+		value, err := json.Marshal(event)
+		if err != nil {
+			return err
+		}
+		messages = append(messages, kafka.Message{
+			Key:   []byte(event.ExecutionID.String()),
+			Value: value,
+			Time:  event.Timestamp,
+			Headers: []kafka.Header{
+				{Key: "type", Value: []byte(event.Type)},
+			},
+		})
+	}
+	return s.writer.WriteMessages(ctx, messages...)
+}
+
+// Close flushes pending messages and closes the connections
+func (s *KafkaSink) Close() error {
+	return s.writer.Close()
+}
@@ -0,0 +1,82 @@
+// Package analytics implements sinks that stream execution events to
+// external analytics stores, keeping long-term reporting off the
+// transactional database.
+package analytics
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/jaydeep/go-n8n/configs"
+	"github.com/jaydeep/go-n8n/internal/domain/execution"
+)
+
+// Sink types
+const (
+	SinkClickHouse = "clickhouse"
+	SinkKafka      = "kafka"
+	SinkWebhook    = "webhook"
+
+	defaultTimeout = 10 * time.Second
+)
+
+var (
+	ErrUnknownSinkType = errors.New("unknown analytics sink type")
+	ErrSinkConfig      = errors.New("invalid analytics sink configuration")
+)
+
+// NewSinks creates the configured sinks
+func NewSinks(cfgs []configs.EventSinkConfig) ([]execution.EventSink, error) {
+	sinks := make([]execution.EventSink, 0, len(cfgs))
+	for _, cfg := range cfgs {
+		if cfg.Name == "" {
+			cfg.Name = cfg.Type
+		}
+		if cfg.Timeout <= 0 {
+			cfg.Timeout = defaultTimeout
+		}
+
+		var (
+			sink execution.EventSink
+			err  error
+		)
+		switch cfg.Type {
+		case SinkClickHouse:
+			sink, err = NewClickHouseSink(cfg)
+		case SinkKafka:
+			sink, err = NewKafkaSink(cfg)
+		case SinkWebhook:
+			sink, err = NewWebhookSink(cfg)
+		default:
+			err = fmt.Errorf("%w: %q", ErrUnknownSinkType, cfg.Type)
+		}
+		if err != nil {
+			for _, s := range sinks {
+				s.Close()
+			}
+			return nil, fmt.Errorf("sink %s: %w", cfg.Name, err)
+		}
+		sinks = append(sinks, sink)
+	}
+	return sinks, nil
+}
+
+// send performs an HTTP request and fails on non-2xx responses
+func send(client *http.Client, req *http.Request) error {
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("unexpected status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	io.Copy(io.Discard, resp.Body)
+	return nil
+}
@@ -0,0 +1,91 @@
+package analytics
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/jaydeep/go-n8n/configs"
+	"github.com/jaydeep/go-n8n/internal/domain/execution"
+)
+
+// Webhook request headers, matching those of execution callbacks
+const (
+	HeaderSignature = "X-N8N-Signature"
+	HeaderTimestamp = "X-N8N-Timestamp"
+	HeaderEvent     = "X-N8N-Event"
+
+	webhookEvent = "execution.events"
+)
+
+// webhookPayload is the body POSTed for a batch
+type webhookPayload struct {
+	Events []*execution.Event `json:"events"`
+}
+
+// WebhookSink POSTs batches of events as JSON. When a secret is set the
+// body is signed like execution callbacks: X-N8N-Signature is the
+// HMAC-SHA256 of the X-N8N-Timestamp header, a dot and the raw body.
+type WebhookSink struct {
+	name   string
+	cfg    configs.EventSinkConfig
+	client *http.Client
+}
+
+// NewWebhookSink creates a webhook sink
+func NewWebhookSink(cfg configs.EventSinkConfig) (*WebhookSink, error) {
+	if cfg.URL == "" {
+		return nil, fmt.Errorf("%w: url is required", ErrSinkConfig)
+	}
+	return &WebhookSink{
+		name:   cfg.Name,
+		cfg:    cfg,
+		client: &http.Client{Timeout: cfg.Timeout},
+	}, nil
+}
+
+// Name identifies the sink in logs
+func (s *WebhookSink) Name() string {
+	return s.name
+}
+
+// Write POSTs a batch of events in one request
+func (s *WebhookSink) Write(ctx context.Context, events []*execution.Event) error {
+	body, err := json.Marshal(webhookPayload{Events: events})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.cfg.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderEvent, webhookEvent)
+	if s.cfg.Secret != "" {
+		timestamp := time.Now().Unix()
+		mac := hmac.New(sha256.New, []byte(s.cfg.Secret))
+		mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+		mac.Write([]byte("."))
+		mac.Write(body)
+		req.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+		req.Header.Set(HeaderSignature, "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	}
+	for name, value := range s.cfg.Headers {
+		req.Header.Set(name, value)
+	}
+	return send(s.client, req)
+}
+
+// Close releases idle connections
+func (s *WebhookSink) Close() error {
+	s.client.CloseIdleConnections()
+	return nil
+}
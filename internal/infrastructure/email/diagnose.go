@@ -0,0 +1,311 @@
+// Package email sends mail over SMTP and diagnoses SMTP configurations.
+package email
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jaydeep/go-n8n/configs"
+)
+
+// Security modes of an SMTP connection
+const (
+	SecurityNone     = "none"     // plain text
+	SecurityStartTLS = "starttls" // upgrade with STARTTLS
+	SecurityTLS      = "tls"      // implicit TLS, usually port 465
+)
+
+// Diagnostic stages in the order they run
+const (
+	StageDNS       = "dns"
+	StageConnect   = "connect"
+	StageHandshake = "handshake"
+	StageAuth      = "auth"
+	StageSend      = "send"
+)
+
+// Stage results
+const (
+	StatusOK      = "ok"
+	StatusFailed  = "failed"
+	StatusSkipped = "skipped"
+)
+
+const (
+	defaultTimeout = 15 * time.Second
+	// heloName is the name announced in EHLO
+	heloName = "localhost"
+)
+
+// TestOptions configures an SMTP diagnosis
+type TestOptions struct {
+	configs.SMTPConfig
+	// Security overrides the mode derived from UseTLS and Port
+	Security string
+	// To receives a test email when set; otherwise no mail is sent
+	To      string
+	Timeout time.Duration
+}
+
+// security returns the effective security mode
+func (o TestOptions) security() string {
+	switch strings.ToLower(o.Security) {
+	case SecurityNone, SecurityStartTLS, SecurityTLS:
+		return strings.ToLower(o.Security)
+	}
+	if o.Port == 465 {
+		return SecurityTLS
+	}
+	if o.UseTLS {
+		return SecurityStartTLS
+	}
+	return SecurityNone
+}
+
+// Stage is the outcome of one step of the diagnosis
+type Stage struct {
+	Name       string                 `json:"name"`
+	Status     string                 `json:"status"`
+	DurationMs int64                  `json:"duration_ms"`
+	Detail     map[string]interface{} `json:"detail,omitempty"`
+	Error      string                 `json:"error,omitempty"`
+	Hint       string                 `json:"hint,omitempty"`
+}
+
+// Diagnostics is the stage-by-stage result of an SMTP test
+type Diagnostics struct {
+	Success  bool    `json:"success"`
+	Host     string  `json:"host"`
+	Port     int     `json:"port"`
+	Security string  `json:"security"`
+	Stages   []Stage `json:"stages"`
+}
+
+// run executes a stage, recording its duration and outcome. Once a stage
+// fails, the remaining ones are skipped.
+func (d *Diagnostics) run(name string, fn func(detail map[string]interface{}) (string, error)) bool {
+	if !d.Success {
+		d.Stages = append(d.Stages, Stage{Name: name, Status: StatusSkipped})
+		return false
+	}
+
+	detail := make(map[string]interface{})
+	start := time.Now()
+	hint, err := fn(detail)
+	stage := Stage{
+		Name:       name,
+		Status:     StatusOK,
+		DurationMs: time.Since(start).Milliseconds(),
+		Hint:       hint,
+	}
+	if len(detail) > 0 {
+		stage.Detail = detail
+	}
+	if err != nil {
+		stage.Status = StatusFailed
+		stage.Error = err.Error()
+		d.Success = false
+	}
+	d.Stages = append(d.Stages, stage)
+	return err == nil
+}
+
+// skip records a stage that does not apply to this test
+func (d *Diagnostics) skip(name, reason string) {
+	d.Stages = append(d.Stages, Stage{Name: name, Status: StatusSkipped, Hint: reason})
+}
+
+// Diagnose connects to the SMTP server and walks through DNS resolution,
+// TCP connect, TLS negotiation, authentication and optionally delivery of
+// a test email, reporting each stage so mail problems can be pinpointed.
+func Diagnose(ctx context.Context, opts TestOptions) *Diagnostics {
+	if opts.Timeout <= 0 {
+		opts.Timeout = defaultTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, opts.Timeout)
+	defer cancel()
+
+	d := &Diagnostics{
+		Success:  true,
+		Host:     opts.Host,
+		Port:     opts.Port,
+		Security: opts.security(),
+	}
+
+	var addrs []string
+	d.run(StageDNS, func(detail map[string]interface{}) (string, error) {
+		if opts.Host == "" {
+			return "Set the SMTP host", errors.New("host is empty")
+		}
+		var err error
+		addrs, err = net.DefaultResolver.LookupHost(ctx, opts.Host)
+		if err != nil {
+			return "Check the host name and the DNS configuration of the server", err
+		}
+		detail["addresses"] = addrs
+		return "", nil
+	})
+
+	var conn net.Conn
+	d.run(StageConnect, func(detail map[string]interface{}) (string, error) {
+		if opts.Port <= 0 || opts.Port > 65535 {
+			return "Common ports are 587 (STARTTLS), 465 (TLS) and 25", fmt.Errorf("invalid port %d", opts.Port)
+		}
+		dialer := &net.Dialer{}
+		var err error
+		conn, err = dialer.DialContext(ctx, "tcp", net.JoinHostPort(opts.Host, strconv.Itoa(opts.Port)))
+		if err != nil {
+			return connectHint(err), err
+		}
+		if deadline, ok := ctx.Deadline(); ok {
+			conn.SetDeadline(deadline)
+		}
+		detail["remote_address"] = conn.RemoteAddr().String()
+		return "", nil
+	})
+	if conn != nil {
+		defer conn.Close()
+	}
+
+	var client *smtp.Client
+	d.run(StageHandshake, func(detail map[string]interface{}) (string, error) {
+		tlsConfig := &tls.Config{ServerName: opts.Host}
+		if d.Security == SecurityTLS {
+			tlsConn := tls.Client(conn, tlsConfig)
+			if err := tlsConn.HandshakeContext(ctx); err != nil {
+				return "The server did not accept implicit TLS; try STARTTLS on port 587", err
+			}
+			describeTLS(detail, tlsConn.ConnectionState())
+			conn = tlsConn
+		}
+
+		var err error
+		client, err = smtp.NewClient(conn, opts.Host)
+		if err != nil {
+			return "The server did not answer with an SMTP greeting; check host, port and security mode", err
+		}
+		if err := client.Hello(heloName); err != nil {
+			return "", err
+		}
+
+		startTLS, _ := client.Extension("STARTTLS")
+		detail["starttls_offered"] = startTLS
+		if d.Security == SecurityStartTLS {
+			if !startTLS {
+				return "The server does not offer STARTTLS; use TLS on port 465 or disable encryption", errors.New("STARTTLS not supported by server")
+			}
+			if err := client.StartTLS(tlsConfig); err != nil {
+				return "TLS negotiation failed; check the certificate of the server", err
+			}
+			if state, ok := client.TLSConnectionState(); ok {
+				describeTLS(detail, state)
+			}
+		}
+
+		if ok, mechanisms := client.Extension("AUTH"); ok {
+			detail["auth_mechanisms"] = strings.Fields(mechanisms)
+		}
+		return "", nil
+	})
+	if client != nil {
+		defer client.Close()
+	}
+
+	if opts.User == "" {
+		d.skip(StageAuth, "No user configured")
+	} else {
+		d.run(StageAuth, func(detail map[string]interface{}) (string, error) {
+			if ok, _ := client.Extension("AUTH"); !ok {
+				return "The server does not offer authentication on this connection; it may require TLS first", errors.New("AUTH not supported by server")
+			}
+			detail["user"] = opts.User
+			if err := client.Auth(smtp.PlainAuth("", opts.User, opts.Password, opts.Host)); err != nil {
+				return authHint(err), err
+			}
+			return "", nil
+		})
+	}
+
+	if opts.To == "" {
+		d.skip(StageSend, "No recipient given")
+	} else {
+		d.run(StageSend, func(detail map[string]interface{}) (string, error) {
+			detail["from"] = opts.From
+			detail["to"] = opts.To
+			if err := send(client, opts.From, opts.To); err != nil {
+				return "The server rejected the message; check that the sender address is allowed for this account", err
+			}
+			return "", nil
+		})
+	}
+
+	if client != nil && d.Success {
+		client.Quit()
+	}
+	return d
+}
+
+// send delivers a short test message over an established client
+func send(client *smtp.Client, from, to string) error {
+	if err := client.Mail(from); err != nil {
+		return err
+	}
+	if err := client.Rcpt(to); err != nil {
+		return err
+	}
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	msg := "From: " + from + "\r\n" +
+		"To: " + to + "\r\n" +
+		"Subject: SMTP test\r\n" +
+		"Date: " + time.Now().Format(time.RFC1123Z) + "\r\n" +
+		"Content-Type: text/plain; charset=utf-8\r\n" +
+		"\r\n" +
+		"This is a test email confirming that the SMTP settings work.\r\n"
+	if _, err := w.Write([]byte(msg)); err != nil {
+		return err
+	}
+	return w.Close()
+}
+
+func describeTLS(detail map[string]interface{}, state tls.ConnectionState) {
+	detail["tls_version"] = tls.VersionName(state.Version)
+	detail["cipher_suite"] = tls.CipherSuiteName(state.CipherSuite)
+	if len(state.PeerCertificates) > 0 {
+		cert := state.PeerCertificates[0]
+		detail["certificate_subject"] = cert.Subject.CommonName
+		detail["certificate_issuer"] = cert.Issuer.CommonName
+		detail["certificate_expires_at"] = cert.NotAfter
+	}
+}
+
+func connectHint(err error) string {
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return "The connection timed out; a firewall may be blocking the port"
+	}
+	if strings.Contains(err.Error(), "refused") {
+		return "The connection was refused; check the port and that the server is running"
+	}
+	return ""
+}
+
+func authHint(err error) string {
+	msg := err.Error()
+	switch {
+	case strings.Contains(msg, "unencrypted connection"):
+		return "Credentials are only sent over TLS; enable STARTTLS or TLS"
+	case strings.HasPrefix(msg, "535"), strings.HasPrefix(msg, "534"):
+		return "The server rejected the credentials; some providers require an app password"
+	}
+	return ""
+}
@@ -0,0 +1,128 @@
+// Package migration implements the client pushing migration packages to
+// other go-n8n instances over their API.
+package migration
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/jaydeep/go-n8n/configs"
+	"github.com/jaydeep/go-n8n/internal/domain/workflow"
+)
+
+const (
+	defaultTimeout = 60 * time.Second
+	importPath     = "/api/v1/admin/migrations/import"
+	// maxResponseSize bounds the import results read from a target
+	maxResponseSize = 4 << 20
+)
+
+// ErrTargetConfig is returned for invalid migration target settings
+var ErrTargetConfig = errors.New("invalid migration target configuration")
+
+// NewTargets creates a client for each configured target
+func NewTargets(cfg configs.MigrationConfig) ([]workflow.MigrationTarget, error) {
+	timeout := cfg.Timeout
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+	targets := make([]workflow.MigrationTarget, 0, len(cfg.Targets))
+	seen := make(map[string]bool)
+	for _, t := range cfg.Targets {
+		if seen[t.Name] {
+			return nil, fmt.Errorf("%w: duplicate name %q", ErrTargetConfig, t.Name)
+		}
+		seen[t.Name] = true
+		client, err := NewClient(t, timeout)
+		if err != nil {
+			return nil, err
+		}
+		targets = append(targets, client)
+	}
+	return targets, nil
+}
+
+// Client imports migration packages into another instance as one of its
+// admins
+type Client struct {
+	name   string
+	url    string
+	token  string
+	client *http.Client
+}
+
+// NewClient creates a client for a migration target
+func NewClient(cfg configs.MigrationTargetConfig, timeout time.Duration) (*Client, error) {
+	if cfg.Name == "" {
+		return nil, fmt.Errorf("%w: name is required", ErrTargetConfig)
+	}
+	u, err := url.Parse(cfg.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("%w: %s: url must be an http or https URL", ErrTargetConfig, cfg.Name)
+	}
+	if cfg.Token == "" {
+		return nil, fmt.Errorf("%w: %s: token is required", ErrTargetConfig, cfg.Name)
+	}
+	return &Client{
+		name:   cfg.Name,
+		url:    strings.TrimRight(cfg.URL, "/"),
+		token:  cfg.Token,
+		client: &http.Client{Timeout: timeout},
+	}, nil
+}
+
+// Name identifies the target in requests and the audit log
+func (c *Client) Name() string {
+	return c.name
+}
+
+// URL is the base URL of the target
+func (c *Client) URL() string {
+	return c.url
+}
+
+// Import posts the request to the import endpoint of the target. Failures
+// reported by the target wrap workflow.ErrMigrationTargetFailed.
+func (c *Client) Import(ctx context.Context, request *workflow.MigrationRequest) (*workflow.MigrationResult, error) {
+	body, err := json.Marshal(request)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url+importPath, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+c.token)
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s: %v", workflow.ErrMigrationTargetFailed, c.name, err)
+	}
+	defer resp.Body.Close()
+
+	var payload struct {
+		Data  *workflow.MigrationResult `json:"data"`
+		Error string                    `json:"error"`
+	}
+	decodeErr := json.NewDecoder(io.LimitReader(resp.Body, maxResponseSize)).Decode(&payload)
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		message := payload.Error
+		if message == "" {
+			message = http.StatusText(resp.StatusCode)
+		}
+		return nil, fmt.Errorf("%w: %s: status %d: %s", workflow.ErrMigrationTargetFailed, c.name, resp.StatusCode, message)
+	}
+	if decodeErr != nil || payload.Data == nil {
+		return nil, fmt.Errorf("%w: %s: unexpected response", workflow.ErrMigrationTargetFailed, c.name)
+	}
+	return payload.Data, nil
+}
@@ -0,0 +1,2 @@
+-- Dry-run executions skip or mock nodes with external side effects
+ALTER TABLE executions ADD COLUMN IF NOT EXISTS dry_run BOOLEAN DEFAULT false;
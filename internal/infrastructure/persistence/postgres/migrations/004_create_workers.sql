@@ -0,0 +1,15 @@
+-- Worker registry with heartbeats
+CREATE TABLE IF NOT EXISTS workers (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    hostname VARCHAR(255) NOT NULL,
+    version VARCHAR(50),
+    concurrency INT DEFAULT 0,
+    queues JSONB DEFAULT '[]',
+    status VARCHAR(50) NOT NULL, -- running, stopped, dead
+    active_jobs INT DEFAULT 0,
+    started_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    last_heartbeat_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    stopped_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_workers_heartbeat ON workers(last_heartbeat_at) WHERE status = 'running';
@@ -0,0 +1,18 @@
+-- Published version pinned for triggers; the workflow row holds the draft
+ALTER TABLE workflows ADD COLUMN IF NOT EXISTS published_version INT;
+
+-- Immutable snapshots of published workflow versions
+CREATE TABLE IF NOT EXISTS workflow_versions (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    workflow_id UUID NOT NULL REFERENCES workflows(id) ON DELETE CASCADE,
+    version INT NOT NULL,
+    name VARCHAR(255),
+    nodes JSONB DEFAULT '[]',
+    connections JSONB DEFAULT '[]',
+    settings JSONB DEFAULT '{}',
+    published_by UUID REFERENCES users(id),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(workflow_id, version)
+);
+
+CREATE INDEX IF NOT EXISTS idx_workflow_versions_workflow ON workflow_versions(workflow_id, version DESC);
@@ -0,0 +1,24 @@
+-- Change requests gating publication of workflows that require approval
+CREATE TABLE IF NOT EXISTS workflow_change_requests (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    workflow_id UUID NOT NULL REFERENCES workflows(id) ON DELETE CASCADE,
+    version INT NOT NULL,
+    status VARCHAR(50) NOT NULL, -- pending, approved, rejected
+    requested_by UUID NOT NULL REFERENCES users(id),
+    nodes JSONB DEFAULT '[]',
+    connections JSONB DEFAULT '[]',
+    settings JSONB DEFAULT '{}',
+    diff JSONB DEFAULT '{}',
+    comments JSONB DEFAULT '[]',
+    decided_by UUID REFERENCES users(id),
+    decided_at TIMESTAMP,
+    decision_note TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_change_requests_workflow ON workflow_change_requests(workflow_id, created_at DESC);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_change_requests_pending ON workflow_change_requests(workflow_id) WHERE status = 'pending';
+
+CREATE TRIGGER update_workflow_change_requests_updated_at BEFORE UPDATE ON workflow_change_requests
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
@@ -0,0 +1,16 @@
+-- Typed key/value settings for the instance and for individual users.
+-- Instance settings use the nil UUID as scope_id.
+CREATE TABLE IF NOT EXISTS instance_settings (
+    scope VARCHAR(20) NOT NULL, -- instance, user
+    scope_id UUID NOT NULL DEFAULT '00000000-0000-0000-0000-000000000000',
+    key VARCHAR(255) NOT NULL,
+    type VARCHAR(20) NOT NULL, -- string, int, float, bool, duration, json
+    value JSONB NOT NULL,
+    updated_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (scope, scope_id, key)
+);
+
+CREATE TRIGGER update_instance_settings_updated_at BEFORE UPDATE ON instance_settings
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
@@ -0,0 +1,19 @@
+-- Calendar-style recurrence for schedules as an alternative to cron
+ALTER TABLE scheduled_workflows ALTER COLUMN cron_expression DROP NOT NULL;
+ALTER TABLE scheduled_workflows ADD COLUMN IF NOT EXISTS recurrence JSONB;
+
+-- Team holiday calendars skipped by business-day schedules
+CREATE TABLE IF NOT EXISTS holiday_calendars (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    team_id UUID NOT NULL REFERENCES teams(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    dates JSONB DEFAULT '[]',
+    created_by UUID NOT NULL REFERENCES users(id),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_holiday_calendars_team ON holiday_calendars(team_id);
+
+CREATE TRIGGER update_holiday_calendars_updated_at BEFORE UPDATE ON holiday_calendars
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
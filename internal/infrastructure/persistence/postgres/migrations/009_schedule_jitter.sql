@@ -0,0 +1,4 @@
+-- Random dispatch delay per schedule to avoid queue spikes
+ALTER TABLE scheduled_workflows ADD COLUMN IF NOT EXISTS jitter_seconds INT DEFAULT 0;
+
+CREATE INDEX IF NOT EXISTS idx_scheduled_workflows_due ON scheduled_workflows(next_run_at) WHERE is_active = true;
@@ -0,0 +1,19 @@
+-- Result callbacks for API-triggered executions
+CREATE TABLE IF NOT EXISTS execution_callbacks (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    execution_id UUID NOT NULL,
+    url TEXT NOT NULL,
+    status VARCHAR(20) NOT NULL, -- pending, delivered, failed
+    attempts INT DEFAULT 0,
+    next_attempt_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_error TEXT,
+    delivered_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_execution_callbacks_pending ON execution_callbacks(next_attempt_at) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS idx_execution_callbacks_execution ON execution_callbacks(execution_id);
+
+CREATE TRIGGER update_execution_callbacks_updated_at BEFORE UPDATE ON execution_callbacks
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
@@ -0,0 +1,37 @@
+-- Workflows exposed as versioned HTTP endpoints with their own API keys
+CREATE TABLE IF NOT EXISTS workflow_endpoints (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    slug VARCHAR(63) NOT NULL UNIQUE,
+    workflow_id UUID NOT NULL REFERENCES workflows(id) ON DELETE CASCADE,
+    workflow_version INT NOT NULL,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    description TEXT,
+    method VARCHAR(10) NOT NULL,
+    api_key_hash VARCHAR(64) NOT NULL,
+    api_key_hint VARCHAR(20),
+    rate_limit INT DEFAULT 0, -- calls per minute, 0 is unlimited
+    request_schema JSONB,
+    response_schema JSONB,
+    response_status INT DEFAULT 200,
+    response_field VARCHAR(255),
+    is_active BOOLEAN DEFAULT true,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_workflow_endpoints_workflow ON workflow_endpoints(workflow_id);
+CREATE INDEX IF NOT EXISTS idx_workflow_endpoints_user ON workflow_endpoints(user_id);
+
+CREATE TRIGGER update_workflow_endpoints_updated_at BEFORE UPDATE ON workflow_endpoints
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+-- Daily call counters of workflow endpoints
+CREATE TABLE IF NOT EXISTS workflow_endpoint_usage (
+    endpoint_id UUID NOT NULL REFERENCES workflow_endpoints(id) ON DELETE CASCADE,
+    day DATE NOT NULL,
+    calls BIGINT DEFAULT 0,
+    errors BIGINT DEFAULT 0,
+    rate_limited BIGINT DEFAULT 0,
+    total_duration_ms BIGINT DEFAULT 0,
+    PRIMARY KEY (endpoint_id, day)
+);
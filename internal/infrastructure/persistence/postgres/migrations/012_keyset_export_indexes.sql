@@ -0,0 +1,3 @@
+-- Keyset cursors page newest first by timestamp and id
+CREATE INDEX IF NOT EXISTS idx_executions_started_at_id ON executions(started_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS idx_audit_logs_created_at_id ON audit_logs(created_at DESC, id DESC);
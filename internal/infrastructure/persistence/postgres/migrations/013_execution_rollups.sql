@@ -0,0 +1,17 @@
+-- Hourly execution counts per workflow, recomputed from executions by the
+-- dashboard rollup job
+CREATE TABLE IF NOT EXISTS execution_rollups (
+    bucket TIMESTAMP NOT NULL,
+    workflow_id UUID NOT NULL,
+    team_id UUID,
+    executions BIGINT NOT NULL DEFAULT 0,
+    succeeded BIGINT NOT NULL DEFAULT 0,
+    failed BIGINT NOT NULL DEFAULT 0,
+    cancelled BIGINT NOT NULL DEFAULT 0,
+    finished BIGINT NOT NULL DEFAULT 0,
+    duration_sum_ms BIGINT NOT NULL DEFAULT 0,
+    refreshed_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (bucket, workflow_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_execution_rollups_team_bucket ON execution_rollups(team_id, bucket);
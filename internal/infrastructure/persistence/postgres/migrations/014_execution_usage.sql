@@ -0,0 +1,19 @@
+-- Resources consumed per execution, for internal chargeback
+CREATE TABLE IF NOT EXISTS execution_usage (
+    execution_id UUID PRIMARY KEY,
+    workflow_id UUID NOT NULL,
+    team_id UUID,
+    user_id UUID NOT NULL,
+    started_at TIMESTAMP NOT NULL,
+    cpu_time_ms BIGINT NOT NULL DEFAULT 0,
+    items_processed BIGINT NOT NULL DEFAULT 0,
+    http_calls BIGINT NOT NULL DEFAULT 0,
+    llm_input_tokens BIGINT NOT NULL DEFAULT 0,
+    llm_output_tokens BIGINT NOT NULL DEFAULT 0,
+    node_types JSONB DEFAULT '{}',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_execution_usage_team_started ON execution_usage(team_id, started_at);
+CREATE INDEX IF NOT EXISTS idx_execution_usage_workflow_started ON execution_usage(workflow_id, started_at);
+CREATE INDEX IF NOT EXISTS idx_execution_usage_user_started ON execution_usage(user_id, started_at);
@@ -0,0 +1,14 @@
+-- Jobs of the PostgreSQL queue driver, used instead of Redis when
+-- worker.queue_driver is postgres. consumer is set while a job is claimed.
+CREATE TABLE IF NOT EXISTS queue_jobs (
+    id BIGSERIAL PRIMARY KEY,
+    queue VARCHAR(255) NOT NULL,
+    payload JSONB NOT NULL,
+    attempts INT NOT NULL DEFAULT 0,
+    consumer VARCHAR(255),
+    enqueued_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    dequeued_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_queue_jobs_waiting ON queue_jobs(queue, enqueued_at, id) WHERE consumer IS NULL;
+CREATE INDEX IF NOT EXISTS idx_queue_jobs_consumer ON queue_jobs(consumer) WHERE consumer IS NOT NULL;
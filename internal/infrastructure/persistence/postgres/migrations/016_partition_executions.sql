@@ -0,0 +1,97 @@
+-- Partition executions and their node data by month of started_at, named
+-- <table>_pYYYY_MM. The retention job creates the upcoming partitions and
+-- drops expired ones; rows outside every partition land in the default
+-- partitions. Foreign keys into executions are dropped, as PostgreSQL only
+-- allows them to reference the whole partition key.
+ALTER TABLE execution_node_data DROP CONSTRAINT IF EXISTS execution_node_data_execution_id_fkey;
+ALTER TABLE executions DROP CONSTRAINT IF EXISTS fk_retry_of;
+
+ALTER TABLE executions RENAME TO executions_unpartitioned;
+ALTER TABLE execution_node_data RENAME TO execution_node_data_unpartitioned;
+
+CREATE TABLE executions (
+    id UUID NOT NULL DEFAULT uuid_generate_v4(),
+    workflow_id UUID NOT NULL REFERENCES workflows(id),
+    workflow_version INT NOT NULL,
+    status VARCHAR(50) NOT NULL,
+    mode VARCHAR(50) NOT NULL,
+    started_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    finished_at TIMESTAMP,
+    execution_time_ms INT,
+    input_data JSONB DEFAULT '{}',
+    output_data JSONB DEFAULT '{}',
+    error_message TEXT,
+    error_node VARCHAR(255),
+    retry_of UUID,
+    retry_count INT DEFAULT 0,
+    dry_run BOOLEAN DEFAULT false,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+) PARTITION BY RANGE (started_at);
+
+CREATE TABLE execution_node_data (
+    id UUID NOT NULL DEFAULT uuid_generate_v4(),
+    execution_id UUID NOT NULL,
+    node_id VARCHAR(255) NOT NULL,
+    node_type VARCHAR(100) NOT NULL,
+    node_name VARCHAR(255),
+    status VARCHAR(50) NOT NULL,
+    input_data JSONB DEFAULT '{}',
+    output_data JSONB DEFAULT '{}',
+    error_message TEXT,
+    execution_time_ms INT,
+    started_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    finished_at TIMESTAMP,
+    retry_count INT DEFAULT 0
+) PARTITION BY RANGE (started_at);
+
+CREATE TABLE executions_default PARTITION OF executions DEFAULT;
+CREATE TABLE execution_node_data_default PARTITION OF execution_node_data DEFAULT;
+
+-- Partitions for the months of existing rows up to the next month
+DO $$
+DECLARE
+    month DATE;
+    last_month DATE := date_trunc('month', CURRENT_TIMESTAMP + INTERVAL '1 month');
+    tbl TEXT;
+BEGIN
+    SELECT date_trunc('month', LEAST(
+        (SELECT MIN(started_at) FROM executions_unpartitioned),
+        (SELECT MIN(started_at) FROM execution_node_data_unpartitioned),
+        CURRENT_TIMESTAMP
+    )) INTO month;
+
+    WHILE month <= last_month LOOP
+        FOREACH tbl IN ARRAY ARRAY['executions', 'execution_node_data'] LOOP
+            EXECUTE format(
+                'CREATE TABLE %I PARTITION OF %I FOR VALUES FROM (%L) TO (%L)',
+                tbl || '_p' || to_char(month, 'YYYY_MM'), tbl, month, month + INTERVAL '1 month'
+            );
+        END LOOP;
+        month := month + INTERVAL '1 month';
+    END LOOP;
+END $$;
+
+INSERT INTO executions
+SELECT id, workflow_id, workflow_version, status, mode,
+       COALESCE(started_at, created_at, CURRENT_TIMESTAMP), finished_at, execution_time_ms,
+       input_data, output_data, error_message, error_node, retry_of, retry_count, dry_run, created_at
+FROM executions_unpartitioned;
+
+INSERT INTO execution_node_data
+SELECT n.id, n.execution_id, n.node_id, n.node_type, n.node_name, n.status, n.input_data, n.output_data,
+       n.error_message, n.execution_time_ms, COALESCE(n.started_at, e.started_at, CURRENT_TIMESTAMP),
+       n.finished_at, n.retry_count
+FROM execution_node_data_unpartitioned n
+LEFT JOIN executions_unpartitioned e ON e.id = n.execution_id;
+
+DROP TABLE execution_node_data_unpartitioned;
+DROP TABLE executions_unpartitioned;
+
+ALTER TABLE executions ADD PRIMARY KEY (id, started_at);
+ALTER TABLE execution_node_data ADD PRIMARY KEY (id, started_at);
+
+CREATE INDEX IF NOT EXISTS idx_executions_id ON executions(id);
+CREATE INDEX IF NOT EXISTS idx_executions_workflow_status ON executions(workflow_id, status);
+CREATE INDEX IF NOT EXISTS idx_executions_created_at ON executions(created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_executions_started_at_id ON executions(started_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS idx_node_execution_data_execution ON execution_node_data(execution_id);
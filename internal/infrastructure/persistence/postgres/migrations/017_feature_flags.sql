@@ -0,0 +1,24 @@
+-- Feature flags toggled at runtime. Built-in flags without a row use the
+-- features section of the configuration as their default.
+CREATE TABLE IF NOT EXISTS feature_flags (
+    key VARCHAR(100) PRIMARY KEY,
+    description TEXT NOT NULL DEFAULT '',
+    enabled BOOLEAN NOT NULL DEFAULT false,
+    rollout INT NOT NULL DEFAULT 0 CHECK (rollout BETWEEN 0 AND 100),
+    updated_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Per user and per team overrides of a flag
+CREATE TABLE IF NOT EXISTS feature_flag_targets (
+    flag_key VARCHAR(100) NOT NULL REFERENCES feature_flags(key) ON DELETE CASCADE,
+    type VARCHAR(20) NOT NULL, -- user, team
+    target_id UUID NOT NULL,
+    enabled BOOLEAN NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (flag_key, type, target_id)
+);
+
+CREATE TRIGGER update_feature_flags_updated_at BEFORE UPDATE ON feature_flags
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
@@ -0,0 +1,8 @@
+-- When users last made an authenticated request. Kept apart from users so
+-- the frequent updates neither rewrite user rows nor bump their updated_at.
+CREATE TABLE IF NOT EXISTS user_activity (
+    user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    last_seen_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_activity_last_seen_at ON user_activity(last_seen_at);
@@ -0,0 +1,3 @@
+-- IP addresses and CIDR ranges allowed to call a workflow endpoint; NULL
+-- or empty allows every address
+ALTER TABLE workflow_endpoints ADD COLUMN IF NOT EXISTS allowed_ips JSONB;
@@ -0,0 +1,17 @@
+-- Endpoint calls accepted while executions could not be started, such as
+-- during maintenance or a queue outage. They are replayed oldest first
+-- once executions can be started again.
+CREATE TABLE IF NOT EXISTS workflow_endpoint_buffer (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    endpoint_id UUID NOT NULL REFERENCES workflow_endpoints(id) ON DELETE CASCADE,
+    method VARCHAR(10) NOT NULL,
+    body JSONB,
+    query JSONB,
+    headers JSONB,
+    reason VARCHAR(50) NOT NULL, -- maintenance, overloaded, engine_unavailable
+    attempts INT DEFAULT 0,
+    last_error TEXT,
+    received_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_workflow_endpoint_buffer_received_at ON workflow_endpoint_buffer(received_at, id);
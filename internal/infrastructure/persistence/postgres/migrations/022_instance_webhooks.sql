@@ -0,0 +1,40 @@
+-- Outgoing webhooks admins register for platform events, such as users
+-- being created or executions failing, and the log of their deliveries
+CREATE TABLE IF NOT EXISTS instance_webhooks (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    name VARCHAR(100) NOT NULL,
+    url TEXT NOT NULL,
+    events TEXT[] NOT NULL DEFAULT '{}',
+    workflow_ids TEXT[] NOT NULL DEFAULT '{}',
+    secret TEXT NOT NULL,
+    secret_hint VARCHAR(20),
+    is_active BOOLEAN DEFAULT true,
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS instance_webhook_deliveries (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    webhook_id UUID NOT NULL REFERENCES instance_webhooks(id) ON DELETE CASCADE,
+    event VARCHAR(100) NOT NULL,
+    payload JSONB,
+    status VARCHAR(20) NOT NULL, -- pending, delivered, failed
+    attempts INT DEFAULT 0,
+    response_status INT DEFAULT 0,
+    duration_ms INT DEFAULT 0,
+    last_error TEXT,
+    next_attempt_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    delivered_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_instance_webhook_deliveries_pending ON instance_webhook_deliveries(next_attempt_at) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS idx_instance_webhook_deliveries_webhook ON instance_webhook_deliveries(webhook_id, created_at);
+
+CREATE TRIGGER update_instance_webhooks_updated_at BEFORE UPDATE ON instance_webhooks
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+CREATE TRIGGER update_instance_webhook_deliveries_updated_at BEFORE UPDATE ON instance_webhook_deliveries
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
@@ -0,0 +1,21 @@
+-- Operational alerts raised on worker outages, queue backlogs and workflow
+-- error-rate spikes. One alert per key fires at a time.
+CREATE TABLE IF NOT EXISTS alerts (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    key VARCHAR(255) NOT NULL,
+    kind VARCHAR(50) NOT NULL,
+    summary TEXT NOT NULL,
+    details JSONB,
+    status VARCHAR(20) NOT NULL, -- firing, resolved
+    fired_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    resolved_at TIMESTAMP,
+    notified_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_alerts_firing_key ON alerts(key) WHERE status = 'firing';
+CREATE INDEX IF NOT EXISTS idx_alerts_fired_at ON alerts(fired_at DESC);
+
+CREATE TRIGGER update_alerts_updated_at BEFORE UPDATE ON alerts
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
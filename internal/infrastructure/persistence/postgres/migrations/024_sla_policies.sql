@@ -0,0 +1,19 @@
+-- Expectations users set on their workflows, such as running at least
+-- every hour, and the breaches found by the latest evaluation
+CREATE TABLE IF NOT EXISTS sla_policies (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    workflow_id UUID NOT NULL UNIQUE REFERENCES workflows(id) ON DELETE CASCADE,
+    max_interval_minutes INT DEFAULT 0,
+    max_duration_seconds INT DEFAULT 0,
+    max_error_rate DOUBLE PRECISION DEFAULT 0,
+    error_rate_window_minutes INT DEFAULT 60,
+    status VARCHAR(20) NOT NULL, -- pending, ok, breached, inactive
+    breaches JSONB,
+    evaluated_at TIMESTAMP,
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TRIGGER update_sla_policies_updated_at BEFORE UPDATE ON sla_policies
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
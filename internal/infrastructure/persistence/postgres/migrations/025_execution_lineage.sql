@@ -0,0 +1,8 @@
+-- Links executions started by other executions, as sub-workflows or error
+-- workflows, to the execution that started them and to the first
+-- execution of the chain
+ALTER TABLE executions ADD COLUMN IF NOT EXISTS parent_execution_id UUID;
+ALTER TABLE executions ADD COLUMN IF NOT EXISTS root_execution_id UUID;
+ALTER TABLE executions ADD COLUMN IF NOT EXISTS relation VARCHAR(20); -- sub_workflow, error_workflow
+
+CREATE INDEX IF NOT EXISTS idx_executions_root ON executions(root_execution_id) WHERE root_execution_id IS NOT NULL;
@@ -0,0 +1,5 @@
+-- Correlation ID tying an execution to the request or trigger that started
+-- it, shared by the executions of a chain
+ALTER TABLE executions ADD COLUMN IF NOT EXISTS correlation_id VARCHAR(128);
+
+CREATE INDEX IF NOT EXISTS idx_executions_correlation ON executions(correlation_id) WHERE correlation_id IS NOT NULL;
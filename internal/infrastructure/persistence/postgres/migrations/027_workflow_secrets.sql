@@ -0,0 +1,16 @@
+-- Values of a single workflow, such as signing keys, that its expressions
+-- reference as $secrets.NAME. Values are encrypted like credentials.
+CREATE TABLE IF NOT EXISTS workflow_secrets (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    workflow_id UUID NOT NULL REFERENCES workflows(id) ON DELETE CASCADE,
+    name VARCHAR(64) NOT NULL,
+    data BYTEA NOT NULL, -- Encrypted
+    iv BYTEA NOT NULL,
+    updated_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(workflow_id, name)
+);
+
+CREATE TRIGGER update_workflow_secrets_updated_at BEFORE UPDATE ON workflow_secrets
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
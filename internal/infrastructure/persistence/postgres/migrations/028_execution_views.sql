@@ -0,0 +1,18 @@
+-- Named filters over the executions list saved by users. Subscribed views
+-- notify their user of the finished executions they match.
+CREATE TABLE IF NOT EXISTS execution_views (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    filter JSONB NOT NULL DEFAULT '{}',
+    subscribed BOOLEAN NOT NULL DEFAULT false,
+    last_matched_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(user_id, name)
+);
+
+CREATE INDEX IF NOT EXISTS idx_execution_views_subscribed ON execution_views(subscribed) WHERE subscribed = true;
+
+CREATE TRIGGER update_execution_views_updated_at BEFORE UPDATE ON execution_views
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
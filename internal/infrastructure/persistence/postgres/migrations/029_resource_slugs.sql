@@ -0,0 +1,7 @@
+-- Client chosen slugs by which tools managing workflows and credentials as
+-- code address them. Slugs of deleted workflows are free again.
+ALTER TABLE workflows ADD COLUMN IF NOT EXISTS slug VARCHAR(63);
+ALTER TABLE credentials ADD COLUMN IF NOT EXISTS slug VARCHAR(63);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_workflows_slug ON workflows(slug) WHERE slug IS NOT NULL AND deleted_at IS NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_credentials_slug ON credentials(slug) WHERE slug IS NOT NULL;
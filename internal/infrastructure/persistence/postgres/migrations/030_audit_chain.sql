@@ -0,0 +1,31 @@
+-- Hash chain over the audit log: each entry's hash covers its fields and
+-- the hash of the entry before it, so rewriting or removing an entry breaks
+-- every later hash. Entries recorded before stay outside the chain.
+ALTER TABLE audit_logs ADD COLUMN IF NOT EXISTS sequence BIGINT;
+ALTER TABLE audit_logs ADD COLUMN IF NOT EXISTS prev_hash VARCHAR(64);
+ALTER TABLE audit_logs ADD COLUMN IF NOT EXISTS hash VARCHAR(64);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_audit_logs_sequence ON audit_logs(sequence) WHERE sequence IS NOT NULL;
+
+-- The audit log is append only
+CREATE OR REPLACE FUNCTION prevent_audit_log_change()
+RETURNS TRIGGER AS $$
+BEGIN
+    RAISE EXCEPTION 'audit logs are append only';
+END;
+$$ language 'plpgsql';
+
+CREATE TRIGGER audit_logs_append_only BEFORE UPDATE OR DELETE ON audit_logs
+    FOR EACH ROW EXECUTE FUNCTION prevent_audit_log_change();
+
+-- Signed statements of the head of the chain at a point in time
+CREATE TABLE IF NOT EXISTS audit_anchors (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    sequence BIGINT NOT NULL,
+    hash VARCHAR(64) NOT NULL,
+    public_key TEXT NOT NULL,
+    signature TEXT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_anchors_sequence ON audit_anchors(sequence);
@@ -0,0 +1,15 @@
+-- Legal holds keeping the executions of a workflow from being pruned or
+-- deleted until released. Released holds are kept as a record.
+CREATE TABLE IF NOT EXISTS execution_holds (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    workflow_id UUID NOT NULL REFERENCES workflows(id),
+    reason TEXT NOT NULL,
+    reference VARCHAR(200),
+    placed_by UUID NOT NULL REFERENCES users(id),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    released_at TIMESTAMP,
+    released_by UUID REFERENCES users(id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_execution_holds_workflow ON execution_holds(workflow_id);
+CREATE INDEX IF NOT EXISTS idx_execution_holds_active ON execution_holds(workflow_id) WHERE released_at IS NULL;
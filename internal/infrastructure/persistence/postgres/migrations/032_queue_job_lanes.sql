@@ -0,0 +1,2 @@
+-- Waiting jobs by queue name prefix, for listing the lanes of a queue
+CREATE INDEX IF NOT EXISTS idx_queue_jobs_waiting_prefix ON queue_jobs(queue text_pattern_ops) WHERE consumer IS NULL;
@@ -0,0 +1,14 @@
+-- Executions suspended at a node, with the state needed to carry on once
+-- resumed by their timer or through the resume endpoint
+CREATE TABLE IF NOT EXISTS waiting_executions (
+    execution_id UUID PRIMARY KEY,
+    node_id VARCHAR(255) NOT NULL,
+    resume_at TIMESTAMP,
+    webhook BOOLEAN DEFAULT false,
+    state JSONB NOT NULL,
+    resume_data JSONB,
+    resumed_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_waiting_executions_due ON waiting_executions(resume_at) WHERE resumed_at IS NULL;
@@ -0,0 +1,5 @@
+-- How workflow endpoints read call bodies, as set on the webhook trigger
+-- of the pinned version, and the files of buffered calls
+ALTER TABLE workflow_endpoints ADD COLUMN IF NOT EXISTS body_format VARCHAR(10) DEFAULT 'json';
+ALTER TABLE workflow_endpoints ADD COLUMN IF NOT EXISTS raw_body BOOLEAN DEFAULT false;
+ALTER TABLE workflow_endpoint_buffer ADD COLUMN IF NOT EXISTS binary JSONB;
@@ -0,0 +1,16 @@
+-- Custom domains teams serve their workflow endpoints on
+CREATE TABLE IF NOT EXISTS custom_domains (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    team_id UUID NOT NULL REFERENCES teams(id) ON DELETE CASCADE,
+    hostname VARCHAR(253) NOT NULL UNIQUE,
+    verification_token VARCHAR(64) NOT NULL,
+    verified_at TIMESTAMP,
+    created_by UUID NOT NULL REFERENCES users(id),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_custom_domains_team ON custom_domains(team_id);
+
+CREATE TRIGGER update_custom_domains_updated_at BEFORE UPDATE ON custom_domains
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
@@ -0,0 +1,3 @@
+-- CORS policy of workflow endpoints, as set on the webhook trigger of the
+-- pinned version
+ALTER TABLE workflow_endpoints ADD COLUMN IF NOT EXISTS cors JSONB;
@@ -0,0 +1,16 @@
+-- Node types generated for teams from the OpenAPI documents of their APIs
+CREATE TABLE IF NOT EXISTS team_nodes (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    team_id UUID NOT NULL REFERENCES teams(id) ON DELETE CASCADE,
+    slug VARCHAR(63) NOT NULL,
+    type VARCHAR(100) NOT NULL UNIQUE,
+    name VARCHAR(255) NOT NULL,
+    descriptor JSONB NOT NULL,
+    created_by UUID NOT NULL REFERENCES users(id),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (team_id, slug)
+);
+
+CREATE TRIGGER update_team_nodes_updated_at BEFORE UPDATE ON team_nodes
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
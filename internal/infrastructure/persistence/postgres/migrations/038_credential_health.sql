@@ -0,0 +1,3 @@
+-- Outcome of the latest health check of credentials: test result and
+-- token or certificate expiry
+ALTER TABLE credentials ADD COLUMN IF NOT EXISTS health JSONB;
@@ -0,0 +1,14 @@
+-- Output pinned to the nodes of workflows, which test executions output in
+-- place of running the nodes
+CREATE TABLE IF NOT EXISTS pinned_data (
+    workflow_id UUID NOT NULL REFERENCES workflows(id) ON DELETE CASCADE,
+    node_id VARCHAR(255) NOT NULL,
+    items JSONB NOT NULL,
+    pinned_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (workflow_id, node_id)
+);
+
+CREATE TRIGGER update_pinned_data_updated_at BEFORE UPDATE ON pinned_data
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
@@ -0,0 +1,2 @@
+-- The mocks, forced and allowed nodes of dry runs
+ALTER TABLE executions ADD COLUMN IF NOT EXISTS dry_run_options JSONB;
@@ -0,0 +1,3 @@
+-- When waiting executions last resumed, from which the watchdog counts
+-- their running time
+ALTER TABLE executions ADD COLUMN IF NOT EXISTS resumed_at TIMESTAMP;
@@ -0,0 +1,3 @@
+-- Data residency region of the pinned version of workflow endpoints, whose
+-- storage keeps the files of calls
+ALTER TABLE workflow_endpoints ADD COLUMN IF NOT EXISTS region VARCHAR(63);
@@ -0,0 +1,3 @@
+-- IP allowlist of workflow endpoints as set on the webhook trigger of the
+-- pinned version
+ALTER TABLE workflow_endpoints ADD COLUMN IF NOT EXISTS webhook_allowed_ips JSONB;
@@ -0,0 +1,60 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/jaydeep/go-n8n/internal/domain/alert"
+	"github.com/jaydeep/go-n8n/pkg/database"
+)
+
+// AlertRepository implements alert.Repository using PostgreSQL
+type AlertRepository struct {
+	db *database.DB
+}
+
+// NewAlertRepository creates a new alert repository
+func NewAlertRepository(db *database.DB) *AlertRepository {
+	return &AlertRepository{db: db}
+}
+
+// Create stores a new alert
+func (r *AlertRepository) Create(ctx context.Context, a *alert.Alert) error {
+	return r.db.WithContext(ctx).Create(a).Error
+}
+
+// Update saves all fields of an alert
+func (r *AlertRepository) Update(ctx context.Context, a *alert.Alert) error {
+	return r.db.WithContext(ctx).Save(a).Error
+}
+
+// ListFiring returns the firing alerts, oldest first
+func (r *AlertRepository) ListFiring(ctx context.Context) ([]*alert.Alert, error) {
+	var alerts []*alert.Alert
+	err := r.db.WithContext(ctx).
+		Where("status = ?", alert.StatusFiring).
+		Order("fired_at ASC").
+		Find(&alerts).Error
+	return alerts, err
+}
+
+// List returns up to limit alerts, newest first, optionally only those
+// with status
+func (r *AlertRepository) List(ctx context.Context, status alert.Status, limit int) ([]*alert.Alert, error) {
+	query := r.db.WithContext(ctx).Model(&alert.Alert{})
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	var alerts []*alert.Alert
+	err := query.Order("fired_at DESC").Limit(limit).Find(&alerts).Error
+	return alerts, err
+}
+
+// DeleteResolvedBefore removes the alerts resolved before cutoff
+func (r *AlertRepository) DeleteResolvedBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).
+		Where("status = ? AND resolved_at < ?", alert.StatusResolved, cutoff).
+		Delete(&alert.Alert{})
+	return result.RowsAffected, result.Error
+}
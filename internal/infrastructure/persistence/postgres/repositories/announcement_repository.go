@@ -0,0 +1,81 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jaydeep/go-n8n/internal/domain/announcement"
+	"github.com/jaydeep/go-n8n/pkg/database"
+	"gorm.io/gorm"
+)
+
+// severityOrder sorts critical announcements first
+const severityOrder = "CASE severity WHEN 'critical' THEN 0 WHEN 'warning' THEN 1 ELSE 2 END"
+
+// AnnouncementRepository implements announcement.Repository using PostgreSQL
+type AnnouncementRepository struct {
+	db *database.DB
+}
+
+// NewAnnouncementRepository creates a new announcement repository
+func NewAnnouncementRepository(db *database.DB) *AnnouncementRepository {
+	return &AnnouncementRepository{db: db}
+}
+
+// Create stores a new announcement
+func (r *AnnouncementRepository) Create(ctx context.Context, a *announcement.Announcement) error {
+	return r.db.WithContext(ctx).Create(a).Error
+}
+
+// Update saves all fields of an announcement
+func (r *AnnouncementRepository) Update(ctx context.Context, a *announcement.Announcement) error {
+	return r.db.WithContext(ctx).Save(a).Error
+}
+
+// Delete removes an announcement
+func (r *AnnouncementRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	result := r.db.WithContext(ctx).Delete(&announcement.Announcement{}, "id = ?", id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return announcement.ErrAnnouncementNotFound
+	}
+	return nil
+}
+
+// FindByID retrieves an announcement by ID
+func (r *AnnouncementRepository) FindByID(ctx context.Context, id uuid.UUID) (*announcement.Announcement, error) {
+	var a announcement.Announcement
+	err := r.db.WithContext(ctx).First(&a, "id = ?", id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, announcement.ErrAnnouncementNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+// List returns every announcement, newest first
+func (r *AnnouncementRepository) List(ctx context.Context) ([]*announcement.Announcement, error) {
+	var list []*announcement.Announcement
+	err := r.db.WithContext(ctx).
+		Order("created_at DESC").
+		Find(&list).Error
+	return list, err
+}
+
+// ListActive returns the announcements shown at now, most severe and then
+// newest first
+func (r *AnnouncementRepository) ListActive(ctx context.Context, now time.Time) ([]*announcement.Announcement, error) {
+	var list []*announcement.Announcement
+	err := r.db.WithContext(ctx).
+		Where("expires_at IS NULL OR expires_at > ?", now).
+		Order(severityOrder).
+		Order("created_at DESC").
+		Find(&list).Error
+	return list, err
+}
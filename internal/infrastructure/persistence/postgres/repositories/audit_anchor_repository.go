@@ -0,0 +1,48 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jaydeep/go-n8n/internal/domain/audit"
+	"github.com/jaydeep/go-n8n/pkg/database"
+	"gorm.io/gorm"
+)
+
+// AuditAnchorRepository implements audit.AnchorRepository using PostgreSQL
+type AuditAnchorRepository struct {
+	db *database.DB
+}
+
+// NewAuditAnchorRepository creates a new audit anchor repository
+func NewAuditAnchorRepository(db *database.DB) *AuditAnchorRepository {
+	return &AuditAnchorRepository{db: db}
+}
+
+// Create inserts a new anchor
+func (r *AuditAnchorRepository) Create(ctx context.Context, anchor *audit.Anchor) error {
+	return r.db.WithContext(ctx).Create(anchor).Error
+}
+
+// Latest retrieves the anchor of the highest sequence
+func (r *AuditAnchorRepository) Latest(ctx context.Context) (*audit.Anchor, error) {
+	var anchor audit.Anchor
+	err := r.db.WithContext(ctx).Order("sequence DESC").Take(&anchor).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, audit.ErrAnchorNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &anchor, nil
+}
+
+// ListRange returns the anchors of the sequences from from up to to
+func (r *AuditAnchorRepository) ListRange(ctx context.Context, from, to int64) ([]*audit.Anchor, error) {
+	var anchors []*audit.Anchor
+	err := r.db.WithContext(ctx).
+		Where("sequence >= ? AND sequence <= ?", from, to).
+		Order("sequence").
+		Find(&anchors).Error
+	return anchors, err
+}
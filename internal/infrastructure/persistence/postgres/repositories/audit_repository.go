@@ -0,0 +1,164 @@
+package repositories
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jaydeep/go-n8n/internal/domain/audit"
+	"github.com/jaydeep/go-n8n/pkg/database"
+	"gorm.io/gorm"
+)
+
+// chainLockKey is the advisory lock serializing appends to the audit chain
+// across instances; SQLite serializes write transactions itself
+const chainLockKey = 7240314
+
+// AuditRepository implements audit.Repository using PostgreSQL
+type AuditRepository struct {
+	db *database.DB
+}
+
+// NewAuditRepository creates a new audit log repository
+func NewAuditRepository(db *database.DB) *AuditRepository {
+	return &AuditRepository{db: db}
+}
+
+// Create appends an entry to the hash chain. Its values and creation time
+// are first brought to the form they are read back in, JSON decoded values
+// and microseconds in UTC, so its hash still matches once stored.
+func (r *AuditRepository) Create(ctx context.Context, log *audit.AuditLog) error {
+	if log.ID == uuid.Nil {
+		log.ID = uuid.New()
+	}
+	if log.CreatedAt.IsZero() {
+		log.CreatedAt = time.Now()
+	}
+	log.CreatedAt = log.CreatedAt.UTC().Truncate(time.Microsecond)
+	var err error
+	if log.OldValue, err = decodedValues(log.OldValue); err != nil {
+		return err
+	}
+	if log.NewValue, err = decodedValues(log.NewValue); err != nil {
+		return err
+	}
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if r.db.Driver() == database.DriverPostgres {
+			if err := tx.Exec("SELECT pg_advisory_xact_lock(?)", chainLockKey).Error; err != nil {
+				return err
+			}
+		}
+		var head audit.AuditLog
+		err := tx.Where("sequence IS NOT NULL").Order("sequence DESC").Take(&head).Error
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			log.Chain(nil)
+		case err != nil:
+			return err
+		default:
+			log.Chain(&head)
+		}
+		return tx.Create(log).Error
+	})
+}
+
+// decodedValues returns values as decoded from their JSON encoding, nil
+// when empty as exports omit empty values
+func decodedValues(values map[string]interface{}) (map[string]interface{}, error) {
+	if len(values) == 0 {
+		return nil, nil
+	}
+	encoded, err := json.Marshal(values)
+	if err != nil {
+		return nil, err
+	}
+	var decoded map[string]interface{}
+	err = json.Unmarshal(encoded, &decoded)
+	return decoded, err
+}
+
+// FindByID retrieves an audit log entry by ID
+func (r *AuditRepository) FindByID(ctx context.Context, id uuid.UUID) (*audit.AuditLog, error) {
+	var log audit.AuditLog
+	err := r.db.WithContext(ctx).First(&log, "id = ?", id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, audit.ErrAuditLogNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &log, nil
+}
+
+// List returns the audit log entries matching filter, newest first
+func (r *AuditRepository) List(ctx context.Context, filter audit.ListFilter) ([]*audit.AuditLog, error) {
+	query := r.db.WithContext(ctx).Model(&audit.AuditLog{}).Order("created_at DESC, id DESC")
+	if filter.UserID != nil {
+		query = query.Where("user_id = ?", *filter.UserID)
+	}
+	if filter.Action != "" {
+		query = query.Where("action = ?", filter.Action)
+	}
+	if filter.ResourceType != "" {
+		query = query.Where("resource_type = ?", filter.ResourceType)
+	}
+	if filter.ResourceID != "" {
+		query = query.Where("resource_id = ?", filter.ResourceID)
+	}
+	if filter.From != nil {
+		query = query.Where("created_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("created_at < ?", *filter.To)
+	}
+	if filter.After != nil {
+		query = query.Where("(created_at, id) < (?, ?)", filter.After.Time, filter.After.ID)
+	}
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit)
+	}
+
+	var logs []*audit.AuditLog
+	err := query.Find(&logs).Error
+	return logs, err
+}
+
+// FindBySequence retrieves the entry of the chain with the sequence
+func (r *AuditRepository) FindBySequence(ctx context.Context, sequence int64) (*audit.AuditLog, error) {
+	var log audit.AuditLog
+	err := r.db.WithContext(ctx).First(&log, "sequence = ?", sequence).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, audit.ErrAuditLogNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &log, nil
+}
+
+// ListChain returns up to limit entries of the chain from the sequence on
+func (r *AuditRepository) ListChain(ctx context.Context, fromSequence int64, limit int) ([]*audit.AuditLog, error) {
+	var logs []*audit.AuditLog
+	err := r.db.WithContext(ctx).
+		Where("sequence >= ?", fromSequence).
+		Order("sequence").
+		Limit(limit).
+		Find(&logs).Error
+	return logs, err
+}
+
+// Head retrieves the newest entry of the chain
+func (r *AuditRepository) Head(ctx context.Context) (*audit.AuditLog, error) {
+	var log audit.AuditLog
+	err := r.db.WithContext(ctx).Where("sequence IS NOT NULL").Order("sequence DESC").Take(&log).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, audit.ErrAuditLogNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &log, nil
+}
@@ -0,0 +1,65 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/jaydeep/go-n8n/internal/domain/billing"
+	"github.com/jaydeep/go-n8n/pkg/database"
+	"gorm.io/gorm/clause"
+)
+
+// usageSums are the aggregated usage columns of a summary
+const usageSums = `COUNT(*) AS executions,
+	SUM(u.cpu_time_ms) AS cpu_time_ms, SUM(u.items_processed) AS items_processed, SUM(u.http_calls) AS http_calls,
+	SUM(u.llm_input_tokens) AS llm_input_tokens, SUM(u.llm_output_tokens) AS llm_output_tokens`
+
+// BillingRepository implements billing.Repository using PostgreSQL
+type BillingRepository struct {
+	db *database.DB
+}
+
+// NewBillingRepository creates a new billing repository
+func NewBillingRepository(db *database.DB) *BillingRepository {
+	return &BillingRepository{db: db}
+}
+
+// Record upserts the usage of an execution
+func (r *BillingRepository) Record(ctx context.Context, usage *billing.ExecutionUsage) error {
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "execution_id"}},
+		UpdateAll: true,
+	}).Create(usage).Error
+}
+
+// Summarize aggregates usage per workflow or team
+func (r *BillingRepository) Summarize(ctx context.Context, filter billing.Filter, groupBy billing.GroupBy) ([]*billing.Summary, error) {
+	query := r.db.WithContext(ctx).
+		Table("execution_usage AS u").
+		Where("u.started_at >= ? AND u.started_at < ?", filter.From, filter.To)
+	if filter.TeamID != nil {
+		query = query.Where("u.team_id = ?", *filter.TeamID)
+	}
+	if filter.WorkflowID != nil {
+		query = query.Where("u.workflow_id = ?", *filter.WorkflowID)
+	}
+	if filter.UserID != nil {
+		query = query.Where("u.user_id = ?", *filter.UserID)
+	}
+
+	switch groupBy {
+	case billing.GroupByTeam:
+		query = query.
+			Select("u.team_id, t.name AS team_name, " + usageSums).
+			Joins("LEFT JOIN teams t ON t.id = u.team_id").
+			Group("u.team_id, t.name")
+	default:
+		query = query.
+			Select("u.workflow_id, w.name AS workflow_name, u.team_id, " + usageSums).
+			Joins("LEFT JOIN workflows w ON w.id = u.workflow_id").
+			Group("u.workflow_id, w.name, u.team_id")
+	}
+
+	var summaries []*billing.Summary
+	err := query.Order("cpu_time_ms DESC").Scan(&summaries).Error
+	return summaries, err
+}
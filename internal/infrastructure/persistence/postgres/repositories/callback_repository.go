@@ -0,0 +1,52 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/jaydeep/go-n8n/internal/domain/execution"
+	"github.com/jaydeep/go-n8n/pkg/database"
+)
+
+// terminalStatuses are the statuses of finished executions
+var terminalStatuses = []execution.ExecutionStatus{
+	execution.ExecutionStatusSuccess,
+	execution.ExecutionStatusError,
+	execution.ExecutionStatusCancelled,
+	execution.ExecutionStatusCrashed,
+	execution.ExecutionStatusTimeout,
+}
+
+// CallbackRepository implements execution.CallbackRepository using PostgreSQL
+type CallbackRepository struct {
+	db *database.DB
+}
+
+// NewCallbackRepository creates a new execution callback repository
+func NewCallbackRepository(db *database.DB) *CallbackRepository {
+	return &CallbackRepository{db: db}
+}
+
+// Create inserts a new callback
+func (r *CallbackRepository) Create(ctx context.Context, c *execution.Callback) error {
+	return r.db.WithContext(ctx).Create(c).Error
+}
+
+// Update saves all fields of a callback
+func (r *CallbackRepository) Update(ctx context.Context, c *execution.Callback) error {
+	return r.db.WithContext(ctx).Save(c).Error
+}
+
+// FindDue returns pending callbacks of finished executions whose next
+// attempt is due
+func (r *CallbackRepository) FindDue(ctx context.Context, now time.Time, limit int) ([]*execution.Callback, error) {
+	var callbacks []*execution.Callback
+	err := r.db.WithContext(ctx).
+		Joins("JOIN executions ON executions.id = execution_callbacks.execution_id").
+		Where("execution_callbacks.status = ? AND execution_callbacks.next_attempt_at <= ?", execution.CallbackStatusPending, now).
+		Where("executions.status IN ?", terminalStatuses).
+		Order("execution_callbacks.next_attempt_at ASC").
+		Limit(limit).
+		Find(&callbacks).Error
+	return callbacks, err
+}
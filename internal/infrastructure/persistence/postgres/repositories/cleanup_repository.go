@@ -0,0 +1,162 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jaydeep/go-n8n/pkg/database"
+)
+
+// cleanupScanBatch bounds the rows read per query while scanning data
+const cleanupScanBatch = 500
+
+// dataTable is a table whose JSON columns may refer to binary data, read
+// in the order of its key columns
+type dataTable struct {
+	name    string
+	keys    []string
+	columns []string
+}
+
+// dataTables are the tables the items of executions and pinned data are
+// kept in
+var dataTables = []dataTable{
+	{name: "executions", keys: []string{"id"}, columns: []string{"input_data", "output_data"}},
+	{name: "execution_node_data", keys: []string{"id"}, columns: []string{"input_data", "output_data"}},
+	{name: "waiting_executions", keys: []string{"execution_id"}, columns: []string{"state", "resume_data"}},
+	{name: "pinned_data", keys: []string{"workflow_id", "node_id"}, columns: []string{"items"}},
+}
+
+// CleanupRepository implements cleanup.Repository using PostgreSQL
+type CleanupRepository struct {
+	db *database.DB
+}
+
+// NewCleanupRepository creates a new cleanup repository
+func NewCleanupRepository(db *database.DB) *CleanupRepository {
+	return &CleanupRepository{db: db}
+}
+
+// LastExecutedAt returns the start of the latest execution of each
+// workflow with executions
+func (r *CleanupRepository) LastExecutedAt(ctx context.Context, workflowIDs []uuid.UUID) (map[uuid.UUID]time.Time, error) {
+	last := make(map[uuid.UUID]time.Time, len(workflowIDs))
+	if len(workflowIDs) == 0 {
+		return last, nil
+	}
+	ranked := r.db.WithContext(ctx).
+		Table("executions").
+		Select("workflow_id, started_at, ROW_NUMBER() OVER (PARTITION BY workflow_id ORDER BY started_at DESC) AS rank").
+		Where("workflow_id IN ?", workflowIDs)
+
+	var rows []struct {
+		WorkflowID uuid.UUID
+		StartedAt  time.Time
+	}
+	err := r.db.WithContext(ctx).
+		Table("(?) AS ranked", ranked).
+		Select("workflow_id, started_at").
+		Where("rank = 1").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		last[row.WorkflowID] = row.StartedAt
+	}
+	return last, nil
+}
+
+// ScanData reads the JSON columns of each data table in batches, in the
+// order of its key, so rows are read once however large the table is
+func (r *CleanupRepository) ScanData(ctx context.Context, fn func(data string)) error {
+	for _, table := range dataTables {
+		if err := r.scanTable(ctx, table, fn); err != nil {
+			return fmt.Errorf("scan %s: %w", table.name, err)
+		}
+	}
+	return nil
+}
+
+func (r *CleanupRepository) scanTable(ctx context.Context, table dataTable, fn func(data string)) error {
+	selected := make([]string, 0, len(table.keys)+len(table.columns))
+	for i, key := range table.keys {
+		selected = append(selected, fmt.Sprintf("CAST(%s AS TEXT) AS k%d", key, i))
+	}
+	for i, column := range table.columns {
+		selected = append(selected, fmt.Sprintf("CAST(%s AS TEXT) AS d%d", column, i))
+	}
+	order := strings.Join(table.keys, ", ")
+
+	var after []string
+	for {
+		query := r.db.WithContext(ctx).
+			Table(table.name).
+			Select(strings.Join(selected, ", ")).
+			Order(order).
+			Limit(cleanupScanBatch)
+		if after != nil {
+			query = query.Where(afterKeys(table.keys), keyArgs(after)...)
+		}
+		var rows []map[string]interface{}
+		if err := query.Find(&rows).Error; err != nil {
+			return err
+		}
+		for _, row := range rows {
+			for i := range table.columns {
+				if data, ok := text(row[fmt.Sprintf("d%d", i)]); ok && data != "" {
+					fn(data)
+				}
+			}
+		}
+		if len(rows) < cleanupScanBatch {
+			return nil
+		}
+		last := rows[len(rows)-1]
+		after = make([]string, len(table.keys))
+		for i := range table.keys {
+			after[i], _ = text(last[fmt.Sprintf("k%d", i)])
+		}
+	}
+}
+
+// afterKeys returns the condition selecting the rows after a key: for keys
+// a and b, a > ? OR (a = ? AND b > ?)
+func afterKeys(keys []string) string {
+	var clauses []string
+	for i := range keys {
+		var parts []string
+		for _, key := range keys[:i] {
+			parts = append(parts, key+" = ?")
+		}
+		parts = append(parts, keys[i]+" > ?")
+		clauses = append(clauses, "("+strings.Join(parts, " AND ")+")")
+	}
+	return strings.Join(clauses, " OR ")
+}
+
+// keyArgs returns the arguments of afterKeys for the values of a key
+func keyArgs(values []string) []interface{} {
+	var args []interface{}
+	for i := range values {
+		for _, v := range values[:i+1] {
+			args = append(args, v)
+		}
+	}
+	return args
+}
+
+// text returns a column read as text; drivers return text as strings or
+// byte slices
+func text(v interface{}) (string, bool) {
+	switch v := v.(type) {
+	case string:
+		return v, true
+	case []byte:
+		return string(v), true
+	}
+	return "", false
+}
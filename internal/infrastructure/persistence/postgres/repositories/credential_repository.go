@@ -0,0 +1,118 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/jaydeep/go-n8n/internal/domain/credential"
+	"github.com/jaydeep/go-n8n/pkg/database"
+	"gorm.io/gorm"
+)
+
+// credentialMetadataColumns are the credential columns without secrets
+var credentialMetadataColumns = []string{"id", "name", "slug", "type", "user_id", "team_id", "node_types", "health", "created_at", "updated_at"}
+
+// CredentialRepository implements credential.Repository using PostgreSQL
+type CredentialRepository struct {
+	db *database.DB
+}
+
+// NewCredentialRepository creates a new credential repository
+func NewCredentialRepository(db *database.DB) *CredentialRepository {
+	return &CredentialRepository{db: db}
+}
+
+// FindByID retrieves a credential by ID
+func (r *CredentialRepository) FindByID(ctx context.Context, id uuid.UUID) (*credential.Credential, error) {
+	var c credential.Credential
+	err := r.db.WithContext(ctx).First(&c, "id = ?", id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, credential.ErrCredentialNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// List returns the credentials owned by userID, or all credentials when
+// userID is nil, without their secrets
+func (r *CredentialRepository) List(ctx context.Context, userID *uuid.UUID) ([]*credential.Credential, error) {
+	query := r.db.WithContext(ctx).Select(credentialMetadataColumns).Order("name ASC")
+	if userID != nil {
+		query = query.Where("user_id = ?", *userID)
+	}
+
+	var credentials []*credential.Credential
+	err := query.Find(&credentials).Error
+	return credentials, err
+}
+
+// ListWithSecrets returns the credentials with the given IDs, or all
+// credentials when ids is empty, with their secrets, ordered by name
+func (r *CredentialRepository) ListWithSecrets(ctx context.Context, ids []uuid.UUID) ([]*credential.Credential, error) {
+	query := r.db.WithContext(ctx).Order("name ASC, id ASC")
+	if len(ids) > 0 {
+		query = query.Where("id IN ?", ids)
+	}
+
+	var credentials []*credential.Credential
+	err := query.Find(&credentials).Error
+	return credentials, err
+}
+
+// FindByName retrieves the credential of a user by name
+func (r *CredentialRepository) FindByName(ctx context.Context, userID uuid.UUID, name string) (*credential.Credential, error) {
+	var c credential.Credential
+	err := r.db.WithContext(ctx).First(&c, "user_id = ? AND name = ?", userID, name).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, credential.ErrCredentialNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// FindBySlug retrieves the credential with the slug
+func (r *CredentialRepository) FindBySlug(ctx context.Context, slug string) (*credential.Credential, error) {
+	var c credential.Credential
+	err := r.db.WithContext(ctx).First(&c, "slug = ?", slug).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, credential.ErrCredentialNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// Create stores a new credential
+func (r *CredentialRepository) Create(ctx context.Context, c *credential.Credential) error {
+	return r.db.WithContext(ctx).Create(c).Error
+}
+
+// Update saves all fields of a credential
+func (r *CredentialRepository) Update(ctx context.Context, c *credential.Credential) error {
+	return r.db.WithContext(ctx).Save(c).Error
+}
+
+// UpdateHealth stores the outcome of a health check of a credential
+func (r *CredentialRepository) UpdateHealth(ctx context.Context, id uuid.UUID, health *credential.Health) error {
+	return r.db.WithContext(ctx).Model(&credential.Credential{ID: id}).
+		Select("health").
+		UpdateColumns(&credential.Credential{Health: health}).Error
+}
+
+// Delete removes a credential
+func (r *CredentialRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	result := r.db.WithContext(ctx).Delete(&credential.Credential{}, "id = ?", id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return credential.ErrCredentialNotFound
+	}
+	return nil
+}
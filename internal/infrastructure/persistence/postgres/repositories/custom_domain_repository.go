@@ -0,0 +1,84 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/jaydeep/go-n8n/internal/domain/endpoint"
+	"github.com/jaydeep/go-n8n/pkg/database"
+	"gorm.io/gorm"
+)
+
+// CustomDomainRepository implements endpoint.CustomDomainRepository using PostgreSQL
+type CustomDomainRepository struct {
+	db *database.DB
+}
+
+// NewCustomDomainRepository creates a new custom domain repository
+func NewCustomDomainRepository(db *database.DB) *CustomDomainRepository {
+	return &CustomDomainRepository{db: db}
+}
+
+// Create inserts a new custom domain
+func (r *CustomDomainRepository) Create(ctx context.Context, d *endpoint.CustomDomain) error {
+	return r.db.WithContext(ctx).Create(d).Error
+}
+
+// Update saves all fields of a custom domain
+func (r *CustomDomainRepository) Update(ctx context.Context, d *endpoint.CustomDomain) error {
+	return r.db.WithContext(ctx).Save(d).Error
+}
+
+// Delete removes a custom domain
+func (r *CustomDomainRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	result := r.db.WithContext(ctx).Delete(&endpoint.CustomDomain{}, "id = ?", id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return endpoint.ErrDomainNotFound
+	}
+	return nil
+}
+
+// FindByID retrieves a custom domain by ID
+func (r *CustomDomainRepository) FindByID(ctx context.Context, id uuid.UUID) (*endpoint.CustomDomain, error) {
+	return r.findOne(ctx, "id = ?", id)
+}
+
+// FindByHostname retrieves a custom domain by its hostname
+func (r *CustomDomainRepository) FindByHostname(ctx context.Context, hostname string) (*endpoint.CustomDomain, error) {
+	return r.findOne(ctx, "hostname = ?", hostname)
+}
+
+func (r *CustomDomainRepository) findOne(ctx context.Context, query string, arg interface{}) (*endpoint.CustomDomain, error) {
+	var d endpoint.CustomDomain
+	err := r.db.WithContext(ctx).First(&d, query, arg).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, endpoint.ErrDomainNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
+
+// FindByTeam returns the custom domains of a team
+func (r *CustomDomainRepository) FindByTeam(ctx context.Context, teamID uuid.UUID) ([]*endpoint.CustomDomain, error) {
+	var domains []*endpoint.CustomDomain
+	err := r.db.WithContext(ctx).
+		Where("team_id = ?", teamID).
+		Order("hostname ASC").
+		Find(&domains).Error
+	return domains, err
+}
+
+// FindVerified returns every verified custom domain
+func (r *CustomDomainRepository) FindVerified(ctx context.Context) ([]*endpoint.CustomDomain, error) {
+	var domains []*endpoint.CustomDomain
+	err := r.db.WithContext(ctx).
+		Where("verified_at IS NOT NULL").
+		Find(&domains).Error
+	return domains, err
+}
@@ -0,0 +1,130 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jaydeep/go-n8n/internal/domain/dashboard"
+	"github.com/jaydeep/go-n8n/pkg/database"
+	"gorm.io/gorm"
+)
+
+// rollupLockKey is the advisory lock serializing refreshes across instances;
+// SQLite runs in a single instance and needs no lock
+const rollupLockKey = 7240313
+
+// refreshRollupsSQL aggregates the executions started in a range of hours
+const refreshRollupsSQL = `
+INSERT INTO execution_rollups (bucket, workflow_id, team_id, executions, succeeded, failed, cancelled, finished, duration_sum_ms, refreshed_at)
+SELECT date_trunc('hour', e.started_at), e.workflow_id, w.team_id,
+       COUNT(*),
+       COUNT(*) FILTER (WHERE e.status = 'success'),
+       COUNT(*) FILTER (WHERE e.status IN ?),
+       COUNT(*) FILTER (WHERE e.status = 'cancelled'),
+       COUNT(*) FILTER (WHERE e.finished_at IS NOT NULL),
+       COALESCE(SUM(e.execution_time_ms) FILTER (WHERE e.finished_at IS NOT NULL), 0),
+       CURRENT_TIMESTAMP
+FROM executions e
+JOIN workflows w ON w.id = e.workflow_id
+WHERE e.started_at >= ? AND e.started_at < ? AND NOT COALESCE(e.dry_run, false)
+GROUP BY 1, 2, 3`
+
+// DashboardRepository implements dashboard.Repository using PostgreSQL
+type DashboardRepository struct {
+	db *database.DB
+}
+
+// NewDashboardRepository creates a new dashboard repository
+func NewDashboardRepository(db *database.DB) *DashboardRepository {
+	return &DashboardRepository{db: db}
+}
+
+// Refresh replaces the rollups of the hours from from up to to in one
+// transaction, so readers never see a partially refreshed range. When
+// another instance is refreshing at the same time the call does nothing.
+func (r *DashboardRepository) Refresh(ctx context.Context, from, to time.Time) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if r.db.Driver() == database.DriverPostgres {
+			var locked bool
+			if err := tx.Raw("SELECT pg_try_advisory_xact_lock(?)", rollupLockKey).Scan(&locked).Error; err != nil {
+				return err
+			}
+			if !locked {
+				return nil
+			}
+		}
+
+		err := tx.Where("bucket >= ? AND bucket < ?", from, to).Delete(&dashboard.Rollup{}).Error
+		if err != nil {
+			return err
+		}
+		return tx.Exec(refreshRollupsSQL, dashboard.FailedStatuses, from, to).Error
+	})
+}
+
+// LatestBucket returns the newest hour with rollups
+func (r *DashboardRepository) LatestBucket(ctx context.Context) (*time.Time, error) {
+	var latest *time.Time
+	err := r.db.WithContext(ctx).
+		Model(&dashboard.Rollup{}).
+		Select("bucket").
+		Order("bucket DESC").
+		Limit(1).
+		Scan(&latest).Error
+	return latest, err
+}
+
+// Totals returns the totals of a team keyed by interval bucket. The hourly
+// rollups are folded into intervals here rather than with date_trunc so
+// the buckets come back as timestamps from both PostgreSQL and SQLite.
+func (r *DashboardRepository) Totals(ctx context.Context, teamID uuid.UUID, from, to time.Time, interval dashboard.Interval) (map[time.Time]dashboard.Totals, error) {
+	var rows []struct {
+		Bucket time.Time
+		dashboard.Totals
+	}
+	err := r.db.WithContext(ctx).
+		Model(&dashboard.Rollup{}).
+		Select(`bucket,
+			SUM(executions) AS executions, SUM(succeeded) AS succeeded, SUM(failed) AS failed,
+			SUM(cancelled) AS cancelled, SUM(finished) AS finished, SUM(duration_sum_ms) AS duration_sum_ms`).
+		Where("team_id = ? AND bucket >= ? AND bucket < ?", teamID, from, to).
+		Group("bucket").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	totals := make(map[time.Time]dashboard.Totals)
+	for _, row := range rows {
+		bucket := interval.Truncate(row.Bucket)
+		sum := totals[bucket]
+		sum.Add(row.Totals)
+		totals[bucket] = sum
+	}
+	return totals, nil
+}
+
+// TopFailing returns the workflows of a team with the most failures
+func (r *DashboardRepository) TopFailing(ctx context.Context, teamID uuid.UUID, from, to time.Time, limit int) ([]*dashboard.FailingWorkflow, error) {
+	var failing []*dashboard.FailingWorkflow
+	err := r.db.WithContext(ctx).
+		Table("execution_rollups AS r").
+		Select(`r.workflow_id, w.name AS workflow_name,
+			SUM(r.executions) AS executions, SUM(r.succeeded) AS succeeded, SUM(r.failed) AS failed,
+			SUM(r.cancelled) AS cancelled, SUM(r.finished) AS finished, SUM(r.duration_sum_ms) AS duration_sum_ms`).
+		Joins("JOIN workflows w ON w.id = r.workflow_id").
+		Where("r.team_id = ? AND r.bucket >= ? AND r.bucket < ?", teamID, from, to).
+		Group("r.workflow_id, w.name").
+		Having("SUM(r.failed) > 0").
+		Order("SUM(r.failed) DESC, r.workflow_id").
+		Limit(limit).
+		Scan(&failing).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, f := range failing {
+		f.Summary = dashboard.Summarize(f.Totals)
+	}
+	return failing, nil
+}
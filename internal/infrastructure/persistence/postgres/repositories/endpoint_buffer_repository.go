@@ -0,0 +1,55 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jaydeep/go-n8n/internal/domain/endpoint"
+	"github.com/jaydeep/go-n8n/pkg/database"
+)
+
+// EndpointBufferRepository implements endpoint.BufferRepository using
+// PostgreSQL
+type EndpointBufferRepository struct {
+	db *database.DB
+}
+
+// NewEndpointBufferRepository creates a new buffered endpoint call repository
+func NewEndpointBufferRepository(db *database.DB) *EndpointBufferRepository {
+	return &EndpointBufferRepository{db: db}
+}
+
+// Create stores a buffered call
+func (r *EndpointBufferRepository) Create(ctx context.Context, call *endpoint.BufferedCall) error {
+	return r.db.WithContext(ctx).Create(call).Error
+}
+
+// Update saves the replay attempts of a buffered call
+func (r *EndpointBufferRepository) Update(ctx context.Context, call *endpoint.BufferedCall) error {
+	return r.db.WithContext(ctx).Model(call).Updates(map[string]interface{}{
+		"attempts":   call.Attempts,
+		"last_error": call.LastError,
+	}).Error
+}
+
+// Delete removes a buffered call
+func (r *EndpointBufferRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Delete(&endpoint.BufferedCall{}, "id = ?", id).Error
+}
+
+// FindOldest returns up to limit buffered calls, oldest first
+func (r *EndpointBufferRepository) FindOldest(ctx context.Context, limit int) ([]*endpoint.BufferedCall, error) {
+	var calls []*endpoint.BufferedCall
+	err := r.db.WithContext(ctx).
+		Order("received_at ASC, id ASC").
+		Limit(limit).
+		Find(&calls).Error
+	return calls, err
+}
+
+// Count returns the number of buffered calls
+func (r *EndpointBufferRepository) Count(ctx context.Context) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&endpoint.BufferedCall{}).Count(&count).Error
+	return count, err
+}
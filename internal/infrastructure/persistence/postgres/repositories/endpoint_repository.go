@@ -0,0 +1,116 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jaydeep/go-n8n/internal/domain/endpoint"
+	"github.com/jaydeep/go-n8n/pkg/database"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// EndpointRepository implements endpoint.Repository using PostgreSQL
+type EndpointRepository struct {
+	db *database.DB
+}
+
+// NewEndpointRepository creates a new workflow endpoint repository
+func NewEndpointRepository(db *database.DB) *EndpointRepository {
+	return &EndpointRepository{db: db}
+}
+
+// Create inserts a new endpoint
+func (r *EndpointRepository) Create(ctx context.Context, e *endpoint.Endpoint) error {
+	return r.db.WithContext(ctx).Create(e).Error
+}
+
+// Update saves all fields of an endpoint
+func (r *EndpointRepository) Update(ctx context.Context, e *endpoint.Endpoint) error {
+	return r.db.WithContext(ctx).Save(e).Error
+}
+
+// Delete removes an endpoint and its usage
+func (r *EndpointRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	result := r.db.WithContext(ctx).Delete(&endpoint.Endpoint{}, "id = ?", id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return endpoint.ErrEndpointNotFound
+	}
+	return nil
+}
+
+// FindByID retrieves an endpoint by ID
+func (r *EndpointRepository) FindByID(ctx context.Context, id uuid.UUID) (*endpoint.Endpoint, error) {
+	return r.findOne(ctx, "id = ?", id)
+}
+
+// FindBySlug retrieves an endpoint by its URL slug
+func (r *EndpointRepository) FindBySlug(ctx context.Context, slug string) (*endpoint.Endpoint, error) {
+	return r.findOne(ctx, "slug = ?", slug)
+}
+
+func (r *EndpointRepository) findOne(ctx context.Context, query string, arg interface{}) (*endpoint.Endpoint, error) {
+	var e endpoint.Endpoint
+	err := r.db.WithContext(ctx).First(&e, query, arg).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, endpoint.ErrEndpointNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &e, nil
+}
+
+// List returns the endpoints owned by userID, or all endpoints when userID
+// is nil
+func (r *EndpointRepository) List(ctx context.Context, userID *uuid.UUID) ([]*endpoint.Endpoint, error) {
+	query := r.db.WithContext(ctx).Order("slug ASC")
+	if userID != nil {
+		query = query.Where("user_id = ?", *userID)
+	}
+
+	var endpoints []*endpoint.Endpoint
+	err := query.Find(&endpoints).Error
+	return endpoints, err
+}
+
+// RecordCall adds a call to the usage row of its day
+func (r *EndpointRepository) RecordCall(ctx context.Context, endpointID uuid.UUID, call endpoint.Call) error {
+	usage := &endpoint.Usage{
+		EndpointID:      endpointID,
+		Day:             call.At.UTC().Truncate(24 * time.Hour),
+		Calls:           1,
+		TotalDurationMs: call.Duration.Milliseconds(),
+	}
+	if call.RateLimited {
+		usage.RateLimited = 1
+	} else if call.StatusCode >= http.StatusBadRequest {
+		usage.Errors = 1
+	}
+
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "endpoint_id"}, {Name: "day"}},
+		DoUpdates: clause.Set{
+			{Column: clause.Column{Name: "calls"}, Value: gorm.Expr("workflow_endpoint_usage.calls + EXCLUDED.calls")},
+			{Column: clause.Column{Name: "errors"}, Value: gorm.Expr("workflow_endpoint_usage.errors + EXCLUDED.errors")},
+			{Column: clause.Column{Name: "rate_limited"}, Value: gorm.Expr("workflow_endpoint_usage.rate_limited + EXCLUDED.rate_limited")},
+			{Column: clause.Column{Name: "total_duration_ms"}, Value: gorm.Expr("workflow_endpoint_usage.total_duration_ms + EXCLUDED.total_duration_ms")},
+		},
+	}).Create(usage).Error
+}
+
+// Usage returns the daily usage between from and to, oldest first
+func (r *EndpointRepository) Usage(ctx context.Context, endpointID uuid.UUID, from, to time.Time) ([]*endpoint.Usage, error) {
+	var usage []*endpoint.Usage
+	err := r.db.WithContext(ctx).
+		Where("endpoint_id = ? AND day >= ? AND day <= ?", endpointID, from, to).
+		Order("day ASC").
+		Find(&usage).Error
+	return usage, err
+}
@@ -0,0 +1,69 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jaydeep/go-n8n/internal/domain/execution"
+	"github.com/jaydeep/go-n8n/pkg/database"
+	"gorm.io/gorm"
+)
+
+// ExecutionHoldRepository implements execution.HoldRepository using
+// PostgreSQL
+type ExecutionHoldRepository struct {
+	db *database.DB
+}
+
+// NewExecutionHoldRepository creates a new legal hold repository
+func NewExecutionHoldRepository(db *database.DB) *ExecutionHoldRepository {
+	return &ExecutionHoldRepository{db: db}
+}
+
+// Create inserts a new hold
+func (r *ExecutionHoldRepository) Create(ctx context.Context, hold *execution.Hold) error {
+	return r.db.WithContext(ctx).Create(hold).Error
+}
+
+// Update saves all fields of a hold
+func (r *ExecutionHoldRepository) Update(ctx context.Context, hold *execution.Hold) error {
+	return r.db.WithContext(ctx).Save(hold).Error
+}
+
+// FindByID retrieves a hold by ID
+func (r *ExecutionHoldRepository) FindByID(ctx context.Context, id uuid.UUID) (*execution.Hold, error) {
+	var hold execution.Hold
+	err := r.db.WithContext(ctx).First(&hold, "id = ?", id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, execution.ErrHoldNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &hold, nil
+}
+
+// ListByWorkflow returns the holds of a workflow, newest first
+func (r *ExecutionHoldRepository) ListByWorkflow(ctx context.Context, workflowID uuid.UUID) ([]*execution.Hold, error) {
+	var holds []*execution.Hold
+	err := r.db.WithContext(ctx).
+		Where("workflow_id = ?", workflowID).
+		Order("created_at DESC").
+		Find(&holds).Error
+	return holds, err
+}
+
+// HeldStartedBefore reports whether an execution of a held workflow
+// started before the given time
+func (r *ExecutionHoldRepository) HeldStartedBefore(ctx context.Context, before time.Time) (bool, error) {
+	var ids []uuid.UUID
+	err := r.db.WithContext(ctx).
+		Model(&execution.Execution{}).
+		Where("started_at < ?", before).
+		Where("workflow_id IN ("+heldWorkflowsSQL+")").
+		Limit(1).
+		Pluck("id", &ids).Error
+	return len(ids) > 0, err
+}
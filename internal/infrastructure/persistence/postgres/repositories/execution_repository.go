@@ -0,0 +1,382 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jaydeep/go-n8n/internal/domain/execution"
+	"github.com/jaydeep/go-n8n/pkg/database"
+	"gorm.io/gorm"
+)
+
+// heldWorkflowsSQL selects the workflows with a legal hold in place
+const heldWorkflowsSQL = "SELECT workflow_id FROM execution_holds WHERE released_at IS NULL"
+
+// ExecutionRepository implements execution.Repository using PostgreSQL
+type ExecutionRepository struct {
+	db *database.DB
+}
+
+// NewExecutionRepository creates a new execution repository
+func NewExecutionRepository(db *database.DB) *ExecutionRepository {
+	return &ExecutionRepository{db: db}
+}
+
+// Create inserts a new execution
+func (r *ExecutionRepository) Create(ctx context.Context, e *execution.Execution) error {
+	return r.db.WithContext(ctx).Create(e).Error
+}
+
+// Update saves all fields of an execution
+func (r *ExecutionRepository) Update(ctx context.Context, e *execution.Execution) error {
+	return r.db.WithContext(ctx).Save(e).Error
+}
+
+// FindByID retrieves an execution by ID
+func (r *ExecutionRepository) FindByID(ctx context.Context, id uuid.UUID) (*execution.Execution, error) {
+	var e execution.Execution
+	err := r.db.WithContext(ctx).First(&e, "id = ?", id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, execution.ErrExecutionNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &e, nil
+}
+
+// FindRunningStartedBefore returns running executions started, or last
+// resumed, before the given time
+func (r *ExecutionRepository) FindRunningStartedBefore(ctx context.Context, before time.Time) ([]*execution.Execution, error) {
+	var executions []*execution.Execution
+	err := r.db.WithContext(ctx).
+		Where("status = ? AND COALESCE(resumed_at, started_at) < ?", execution.ExecutionStatusRunning, before).
+		Order("started_at ASC").
+		Find(&executions).Error
+	return executions, err
+}
+
+// CountByStatus returns the number of executions in the given status
+func (r *ExecutionRepository) CountByStatus(ctx context.Context, status execution.ExecutionStatus) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&execution.Execution{}).
+		Where("status = ?", status).
+		Count(&count).Error
+	return count, err
+}
+
+// CountRunningByTeam returns the number of running executions of the
+// workflows of a team
+func (r *ExecutionRepository) CountRunningByTeam(ctx context.Context, teamID uuid.UUID) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&execution.Execution{}).
+		Joins("JOIN workflows w ON w.id = executions.workflow_id").
+		Where("executions.status = ? AND w.team_id = ?", execution.ExecutionStatusRunning, teamID).
+		Count(&count).Error
+	return count, err
+}
+
+// List returns the executions matching filter, newest first
+func (r *ExecutionRepository) List(ctx context.Context, filter execution.ListFilter) ([]*execution.Execution, error) {
+	query := r.db.WithContext(ctx).Model(&execution.Execution{}).Order("executions.started_at DESC, executions.id DESC")
+	if filter.UserID != nil {
+		query = query.
+			Joins("JOIN workflows ON workflows.id = executions.workflow_id").
+			Where("workflows.user_id = ?", *filter.UserID)
+	}
+	if filter.WorkflowID != nil {
+		query = query.Where("executions.workflow_id = ?", *filter.WorkflowID)
+	}
+	if filter.Status != "" {
+		query = query.Where("executions.status = ?", filter.Status)
+	}
+	if filter.CorrelationID != "" {
+		query = query.Where("executions.correlation_id = ?", filter.CorrelationID)
+	}
+	if filter.ErrorText != "" {
+		query = query.Where(`LOWER(executions.error_message) LIKE ? ESCAPE '\'`, "%"+escapeLike(strings.ToLower(filter.ErrorText))+"%")
+	}
+	if filter.From != nil {
+		query = query.Where("executions.started_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("executions.started_at < ?", *filter.To)
+	}
+	if filter.After != nil {
+		query = query.Where("(executions.started_at, executions.id) < (?, ?)", filter.After.Time, filter.After.ID)
+	}
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit)
+	}
+	if filter.Offset > 0 {
+		query = query.Offset(filter.Offset)
+	}
+
+	var executions []*execution.Execution
+	err := query.Find(&executions).Error
+	return executions, err
+}
+
+// ListLatest returns up to limit of the newest executions of each of the
+// workflows in a single query
+func (r *ExecutionRepository) ListLatest(ctx context.Context, workflowIDs []uuid.UUID, limit int) ([]*execution.Execution, error) {
+	ranked := r.db.WithContext(ctx).
+		Model(&execution.Execution{}).
+		Select("executions.*, ROW_NUMBER() OVER (PARTITION BY workflow_id ORDER BY started_at DESC) AS rank").
+		Where("workflow_id IN ?", workflowIDs)
+
+	var executions []*execution.Execution
+	err := r.db.WithContext(ctx).
+		Table("(?) AS ranked", ranked).
+		Where("rank <= ?", limit).
+		Order("workflow_id, started_at DESC").
+		Find(&executions).Error
+	return executions, err
+}
+
+// ListDurations returns the timing of the newest successful executions of
+// a workflow, leaving out dry runs
+func (r *ExecutionRepository) ListDurations(ctx context.Context, workflowID uuid.UUID, limit int) ([]*execution.Execution, error) {
+	var executions []*execution.Execution
+	err := r.db.WithContext(ctx).
+		Model(&execution.Execution{}).
+		Select("id, workflow_id, status, started_at, finished_at, execution_time_ms").
+		Where("workflow_id = ? AND status = ? AND dry_run = ?", workflowID, execution.ExecutionStatusSuccess, false).
+		Order("started_at DESC, id DESC").
+		Limit(limit).
+		Find(&executions).Error
+	return executions, err
+}
+
+// ListLineage returns the executions of the chain started by root, oldest
+// first, without their data
+func (r *ExecutionRepository) ListLineage(ctx context.Context, rootID uuid.UUID, limit int) ([]*execution.Execution, error) {
+	var executions []*execution.Execution
+	err := r.db.WithContext(ctx).
+		Model(&execution.Execution{}).
+		Select("id, workflow_id, workflow_version, status, mode, started_at, finished_at, execution_time_ms, "+
+			"error_node, dry_run, parent_execution_id, root_execution_id, relation, correlation_id").
+		Where("id = ? OR root_execution_id = ?", rootID, rootID).
+		Order("started_at ASC, id ASC").
+		Limit(limit).
+		Find(&executions).Error
+	return executions, err
+}
+
+// CountOutcomesSince counts per workflow the finished executions started
+// since the given time, leaving out cancelled and dry runs
+func (r *ExecutionRepository) CountOutcomesSince(ctx context.Context, since time.Time) ([]*execution.WorkflowOutcomes, error) {
+	var outcomes []*execution.WorkflowOutcomes
+	err := r.outcomes(ctx, since).Scan(&outcomes).Error
+	return outcomes, err
+}
+
+// CountOutcomes counts the finished executions of a workflow started since
+// the given time, leaving out cancelled and dry runs
+func (r *ExecutionRepository) CountOutcomes(ctx context.Context, workflowID uuid.UUID, since time.Time) (*execution.WorkflowOutcomes, error) {
+	var outcomes []*execution.WorkflowOutcomes
+	err := r.outcomes(ctx, since).Where("workflow_id = ?", workflowID).Scan(&outcomes).Error
+	if err != nil {
+		return nil, err
+	}
+	if len(outcomes) == 0 {
+		return &execution.WorkflowOutcomes{WorkflowID: workflowID}, nil
+	}
+	return outcomes[0], nil
+}
+
+// outcomes groups the finished executions started since the given time by
+// workflow
+func (r *ExecutionRepository) outcomes(ctx context.Context, since time.Time) *gorm.DB {
+	failed := []execution.ExecutionStatus{
+		execution.ExecutionStatusError,
+		execution.ExecutionStatusCrashed,
+		execution.ExecutionStatusTimeout,
+	}
+	finished := append([]execution.ExecutionStatus{execution.ExecutionStatusSuccess}, failed...)
+
+	return r.db.WithContext(ctx).
+		Model(&execution.Execution{}).
+		Select("workflow_id, COUNT(*) AS finished, SUM(CASE WHEN status IN ? THEN 1 ELSE 0 END) AS failed, "+
+			"AVG(execution_time_ms) AS avg_duration_ms", failed).
+		Where("started_at >= ? AND status IN ? AND dry_run = ?", since, finished, false).
+		Group("workflow_id")
+}
+
+// DeleteStartedBefore deletes a batch of the oldest executions started
+// before the given time together with their node data
+func (r *ExecutionRepository) DeleteStartedBefore(ctx context.Context, before time.Time, limit int) (int64, error) {
+	var deleted int64
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var ids []uuid.UUID
+		err := tx.Model(&execution.Execution{}).
+			Where("started_at < ?", before).
+			Where("workflow_id NOT IN ("+heldWorkflowsSQL+")").
+			Order("started_at ASC").
+			Limit(limit).
+			Pluck("id", &ids).Error
+		if err != nil || len(ids) == 0 {
+			return err
+		}
+		deleted, err = deleteExecutions(tx, ids)
+		return err
+	})
+	return deleted, err
+}
+
+// Delete deletes an execution with its node data unless its workflow is on
+// legal hold
+func (r *ExecutionRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var e execution.Execution
+		err := tx.Select("id", "workflow_id").First(&e, "id = ?", id).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return execution.ErrExecutionNotFound
+		}
+		if err != nil {
+			return err
+		}
+		var held int64
+		err = tx.Model(&execution.Hold{}).
+			Where("workflow_id = ? AND released_at IS NULL", e.WorkflowID).
+			Count(&held).Error
+		if err != nil {
+			return err
+		}
+		if held > 0 {
+			return execution.ErrExecutionOnHold
+		}
+		_, err = deleteExecutions(tx, []uuid.UUID{id})
+		return err
+	})
+}
+
+// deleteExecutions deletes executions with their node data and waits,
+// returning the number deleted
+func deleteExecutions(tx *gorm.DB, ids []uuid.UUID) (int64, error) {
+	// Retries outlive the executions they retried
+	if err := tx.Exec("UPDATE executions SET retry_of = NULL WHERE retry_of IN ?", ids).Error; err != nil {
+		return 0, err
+	}
+	if err := tx.Exec("DELETE FROM execution_node_data WHERE execution_id IN ?", ids).Error; err != nil {
+		return 0, err
+	}
+	if err := tx.Exec("DELETE FROM waiting_executions WHERE execution_id IN ?", ids).Error; err != nil {
+		return 0, err
+	}
+	result := tx.Exec("DELETE FROM executions WHERE id IN ?", ids)
+	return result.RowsAffected, result.Error
+}
+
+// nodeInsertBatch bounds the rows per INSERT statement, keeping the bind
+// parameters well under the PostgreSQL and SQLite limits
+const nodeInsertBatch = 200
+
+// NodeExecutionRepository implements execution.NodeExecutionRepository using PostgreSQL
+type NodeExecutionRepository struct {
+	db *database.DB
+}
+
+// NewNodeExecutionRepository creates a new node execution repository
+func NewNodeExecutionRepository(db *database.DB) *NodeExecutionRepository {
+	return &NodeExecutionRepository{db: db}
+}
+
+// CreateBatch inserts node execution records with multi-row inserts
+func (r *NodeExecutionRepository) CreateBatch(ctx context.Context, nodes []*execution.NodeExecution) error {
+	if len(nodes) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).CreateInBatches(nodes, nodeInsertBatch).Error
+}
+
+// ListByExecution returns the node execution records of an execution
+func (r *NodeExecutionRepository) ListByExecution(ctx context.Context, executionID uuid.UUID) ([]*execution.NodeExecution, error) {
+	var nodes []*execution.NodeExecution
+	err := r.db.WithContext(ctx).
+		Where("execution_id = ?", executionID).
+		Order("started_at ASC").
+		Find(&nodes).Error
+	return nodes, err
+}
+
+// likeEscaper escapes the wildcards of LIKE patterns
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+// escapeLike returns s matching itself literally in a LIKE pattern
+func escapeLike(s string) string {
+	return likeEscaper.Replace(s)
+}
+
+// ExecutionViewRepository implements execution.ViewRepository using
+// PostgreSQL
+type ExecutionViewRepository struct {
+	db *database.DB
+}
+
+// NewExecutionViewRepository creates a new execution view repository
+func NewExecutionViewRepository(db *database.DB) *ExecutionViewRepository {
+	return &ExecutionViewRepository{db: db}
+}
+
+// Create stores a new view
+func (r *ExecutionViewRepository) Create(ctx context.Context, v *execution.View) error {
+	return r.db.WithContext(ctx).Create(v).Error
+}
+
+// Update saves all fields of a view
+func (r *ExecutionViewRepository) Update(ctx context.Context, v *execution.View) error {
+	return r.db.WithContext(ctx).Save(v).Error
+}
+
+// Delete removes a view
+func (r *ExecutionViewRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	result := r.db.WithContext(ctx).Delete(&execution.View{}, "id = ?", id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return execution.ErrViewNotFound
+	}
+	return nil
+}
+
+// FindByID retrieves a view by ID
+func (r *ExecutionViewRepository) FindByID(ctx context.Context, id uuid.UUID) (*execution.View, error) {
+	var v execution.View
+	err := r.db.WithContext(ctx).First(&v, "id = ?", id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, execution.ErrViewNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &v, nil
+}
+
+// ListByUser returns the views of a user ordered by name
+func (r *ExecutionViewRepository) ListByUser(ctx context.Context, userID uuid.UUID) ([]*execution.View, error) {
+	var views []*execution.View
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).Order("name ASC").Find(&views).Error
+	return views, err
+}
+
+// ListSubscribed returns every subscribed view
+func (r *ExecutionViewRepository) ListSubscribed(ctx context.Context) ([]*execution.View, error) {
+	var views []*execution.View
+	err := r.db.WithContext(ctx).Where("subscribed = ?", true).Find(&views).Error
+	return views, err
+}
+
+// MarkMatched sets when the views last matched an execution
+func (r *ExecutionViewRepository) MarkMatched(ctx context.Context, ids []uuid.UUID, at time.Time) error {
+	return r.db.WithContext(ctx).
+		Model(&execution.View{}).
+		Where("id IN ?", ids).
+		Update("last_matched_at", at).Error
+}
@@ -0,0 +1,85 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jaydeep/go-n8n/internal/domain/feature"
+	"github.com/jaydeep/go-n8n/pkg/database"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// FeatureRepository implements feature.Repository using PostgreSQL
+type FeatureRepository struct {
+	db *database.DB
+}
+
+// NewFeatureRepository creates a new feature flag repository
+func NewFeatureRepository(db *database.DB) *FeatureRepository {
+	return &FeatureRepository{db: db}
+}
+
+// List returns every stored flag with its targets
+func (r *FeatureRepository) List(ctx context.Context) ([]*feature.Flag, error) {
+	var flags []*feature.Flag
+	err := r.db.WithContext(ctx).
+		Preload("Targets", func(db *gorm.DB) *gorm.DB {
+			return db.Order("type ASC, target_id ASC")
+		}).
+		Order("key ASC").
+		Find(&flags).Error
+	return flags, err
+}
+
+// Save upserts a flag without touching its targets
+func (r *FeatureRepository) Save(ctx context.Context, flag *feature.Flag) error {
+	return r.db.WithContext(ctx).
+		Omit("Targets").
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "key"}},
+			DoUpdates: clause.AssignmentColumns([]string{"description", "enabled", "rollout", "updated_by", "updated_at"}),
+		}).
+		Create(flag).Error
+}
+
+// Delete removes a flag and its targets
+func (r *FeatureRepository) Delete(ctx context.Context, key string) error {
+	return r.db.Transaction(ctx, func(tx *gorm.DB) error {
+		if err := tx.Where("flag_key = ?", key).Delete(&feature.Target{}).Error; err != nil {
+			return err
+		}
+		result := tx.Where("key = ?", key).Delete(&feature.Flag{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return feature.ErrFlagNotFound
+		}
+		return nil
+	})
+}
+
+// SaveTarget upserts a target
+func (r *FeatureRepository) SaveTarget(ctx context.Context, target *feature.Target) error {
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "flag_key"}, {Name: "type"}, {Name: "target_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"enabled"}),
+		}).
+		Create(target).Error
+}
+
+// DeleteTarget removes a target
+func (r *FeatureRepository) DeleteTarget(ctx context.Context, key string, targetType feature.TargetType, targetID uuid.UUID) error {
+	result := r.db.WithContext(ctx).
+		Where("flag_key = ? AND type = ? AND target_id = ?", key, targetType, targetID).
+		Delete(&feature.Target{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return feature.ErrTargetNotFound
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/jaydeep/go-n8n/internal/domain/schedule"
+	"github.com/jaydeep/go-n8n/pkg/database"
+	"gorm.io/gorm"
+)
+
+// HolidayCalendarRepository implements schedule.HolidayCalendarRepository using PostgreSQL
+type HolidayCalendarRepository struct {
+	db *database.DB
+}
+
+// NewHolidayCalendarRepository creates a new holiday calendar repository
+func NewHolidayCalendarRepository(db *database.DB) *HolidayCalendarRepository {
+	return &HolidayCalendarRepository{db: db}
+}
+
+// Create inserts a new holiday calendar
+func (r *HolidayCalendarRepository) Create(ctx context.Context, calendar *schedule.HolidayCalendar) error {
+	return r.db.WithContext(ctx).Create(calendar).Error
+}
+
+// Update saves all fields of a holiday calendar
+func (r *HolidayCalendarRepository) Update(ctx context.Context, calendar *schedule.HolidayCalendar) error {
+	return r.db.WithContext(ctx).Save(calendar).Error
+}
+
+// Delete removes a holiday calendar
+func (r *HolidayCalendarRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	result := r.db.WithContext(ctx).Delete(&schedule.HolidayCalendar{}, "id = ?", id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return schedule.ErrHolidayCalendarNotFound
+	}
+	return nil
+}
+
+// FindByID retrieves a holiday calendar by ID
+func (r *HolidayCalendarRepository) FindByID(ctx context.Context, id uuid.UUID) (*schedule.HolidayCalendar, error) {
+	var calendar schedule.HolidayCalendar
+	err := r.db.WithContext(ctx).First(&calendar, "id = ?", id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, schedule.ErrHolidayCalendarNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &calendar, nil
+}
+
+// FindByTeam returns the holiday calendars of a team
+func (r *HolidayCalendarRepository) FindByTeam(ctx context.Context, teamID uuid.UUID) ([]*schedule.HolidayCalendar, error) {
+	var calendars []*schedule.HolidayCalendar
+	err := r.db.WithContext(ctx).
+		Where("team_id = ?", teamID).
+		Order("name ASC").
+		Find(&calendars).Error
+	return calendars, err
+}
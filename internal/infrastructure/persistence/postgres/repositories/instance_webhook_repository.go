@@ -0,0 +1,140 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jaydeep/go-n8n/internal/domain/instancehook"
+	"github.com/jaydeep/go-n8n/pkg/database"
+	"gorm.io/gorm"
+)
+
+// InstanceWebhookRepository implements instancehook.Repository using
+// PostgreSQL
+type InstanceWebhookRepository struct {
+	db *database.DB
+}
+
+// NewInstanceWebhookRepository creates a new instance webhook repository
+func NewInstanceWebhookRepository(db *database.DB) *InstanceWebhookRepository {
+	return &InstanceWebhookRepository{db: db}
+}
+
+// Create stores a new webhook
+func (r *InstanceWebhookRepository) Create(ctx context.Context, w *instancehook.Webhook) error {
+	return r.db.WithContext(ctx).Create(w).Error
+}
+
+// Update saves all fields of a webhook
+func (r *InstanceWebhookRepository) Update(ctx context.Context, w *instancehook.Webhook) error {
+	return r.db.WithContext(ctx).Save(w).Error
+}
+
+// Delete removes a webhook and its deliveries
+func (r *InstanceWebhookRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	result := r.db.WithContext(ctx).Delete(&instancehook.Webhook{}, "id = ?", id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return instancehook.ErrWebhookNotFound
+	}
+	return nil
+}
+
+// FindByID retrieves a webhook by ID
+func (r *InstanceWebhookRepository) FindByID(ctx context.Context, id uuid.UUID) (*instancehook.Webhook, error) {
+	var w instancehook.Webhook
+	err := r.db.WithContext(ctx).First(&w, "id = ?", id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, instancehook.ErrWebhookNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &w, nil
+}
+
+// List returns every webhook, oldest first
+func (r *InstanceWebhookRepository) List(ctx context.Context) ([]*instancehook.Webhook, error) {
+	var webhooks []*instancehook.Webhook
+	err := r.db.WithContext(ctx).Order("created_at ASC").Find(&webhooks).Error
+	return webhooks, err
+}
+
+// ListActive returns the active webhooks
+func (r *InstanceWebhookRepository) ListActive(ctx context.Context) ([]*instancehook.Webhook, error) {
+	var webhooks []*instancehook.Webhook
+	err := r.db.WithContext(ctx).Where("is_active = ?", true).Find(&webhooks).Error
+	return webhooks, err
+}
+
+// InstanceWebhookDeliveryRepository implements
+// instancehook.DeliveryRepository using PostgreSQL
+type InstanceWebhookDeliveryRepository struct {
+	db *database.DB
+}
+
+// NewInstanceWebhookDeliveryRepository creates a new webhook delivery
+// repository
+func NewInstanceWebhookDeliveryRepository(db *database.DB) *InstanceWebhookDeliveryRepository {
+	return &InstanceWebhookDeliveryRepository{db: db}
+}
+
+// Create stores a new delivery
+func (r *InstanceWebhookDeliveryRepository) Create(ctx context.Context, d *instancehook.Delivery) error {
+	return r.db.WithContext(ctx).Create(d).Error
+}
+
+// Update saves all fields of a delivery
+func (r *InstanceWebhookDeliveryRepository) Update(ctx context.Context, d *instancehook.Delivery) error {
+	return r.db.WithContext(ctx).Save(d).Error
+}
+
+// FindByID retrieves a delivery by ID
+func (r *InstanceWebhookDeliveryRepository) FindByID(ctx context.Context, id uuid.UUID) (*instancehook.Delivery, error) {
+	var d instancehook.Delivery
+	err := r.db.WithContext(ctx).First(&d, "id = ?", id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, instancehook.ErrDeliveryNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
+
+// ListByWebhook returns up to limit deliveries of a webhook, newest first,
+// optionally only those with status
+func (r *InstanceWebhookDeliveryRepository) ListByWebhook(ctx context.Context, webhookID uuid.UUID, status instancehook.DeliveryStatus, limit int) ([]*instancehook.Delivery, error) {
+	query := r.db.WithContext(ctx).Where("webhook_id = ?", webhookID)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	var deliveries []*instancehook.Delivery
+	err := query.Order("created_at DESC").Limit(limit).Find(&deliveries).Error
+	return deliveries, err
+}
+
+// FindDue returns up to limit pending deliveries whose next attempt is due
+func (r *InstanceWebhookDeliveryRepository) FindDue(ctx context.Context, now time.Time, limit int) ([]*instancehook.Delivery, error) {
+	var deliveries []*instancehook.Delivery
+	err := r.db.WithContext(ctx).
+		Where("status = ? AND next_attempt_at <= ?", instancehook.DeliveryStatusPending, now).
+		Order("next_attempt_at ASC").
+		Limit(limit).
+		Find(&deliveries).Error
+	return deliveries, err
+}
+
+// DeleteFinishedBefore removes the delivered and failed deliveries created
+// before cutoff
+func (r *InstanceWebhookDeliveryRepository) DeleteFinishedBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).
+		Where("status <> ? AND created_at < ?", instancehook.DeliveryStatusPending, cutoff).
+		Delete(&instancehook.Delivery{})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,75 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/jaydeep/go-n8n/internal/domain/execution"
+	"github.com/jaydeep/go-n8n/pkg/database"
+)
+
+// PartitionRepository implements execution.PartitionRepository using the
+// PostgreSQL catalog
+type PartitionRepository struct {
+	db *database.DB
+}
+
+// NewPartitionRepository creates a new partition repository
+func NewPartitionRepository(db *database.DB) *PartitionRepository {
+	return &PartitionRepository{db: db}
+}
+
+// Partitioned reports whether table is partitioned
+func (r *PartitionRepository) Partitioned(ctx context.Context, table string) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Raw(`
+		SELECT COUNT(*) FROM pg_partitioned_table pt
+		JOIN pg_class c ON c.oid = pt.partrelid
+		WHERE c.relname = ? AND pg_table_is_visible(c.oid)`, table).
+		Scan(&count).Error
+	return count > 0, err
+}
+
+// ListPartitions returns the monthly partitions of table
+func (r *PartitionRepository) ListPartitions(ctx context.Context, table string) ([]execution.Partition, error) {
+	var names []string
+	err := r.db.WithContext(ctx).Raw(`
+		SELECT c.relname FROM pg_inherits i
+		JOIN pg_class c ON c.oid = i.inhrelid
+		JOIN pg_class p ON p.oid = i.inhparent
+		WHERE p.relname = ? AND pg_table_is_visible(p.oid)
+		ORDER BY c.relname`, table).
+		Scan(&names).Error
+	if err != nil {
+		return nil, err
+	}
+
+	partitions := make([]execution.Partition, 0, len(names))
+	for _, name := range names {
+		if p, ok := execution.ParsePartition(table, name); ok {
+			partitions = append(partitions, p)
+		}
+	}
+	return partitions, nil
+}
+
+// CreatePartition creates a partition unless it exists. It fails when the
+// default partition already holds rows of the partition's month.
+func (r *PartitionRepository) CreatePartition(ctx context.Context, p execution.Partition) error {
+	return r.db.WithContext(ctx).Exec(fmt.Sprintf(
+		"CREATE TABLE IF NOT EXISTS %s PARTITION OF %s FOR VALUES FROM ('%s') TO ('%s')",
+		quoteIdent(p.Name()), quoteIdent(p.Table),
+		p.Month.Format("2006-01-02"), p.End().Format("2006-01-02"),
+	)).Error
+}
+
+// DropPartition drops a partition and its rows
+func (r *PartitionRepository) DropPartition(ctx context.Context, p execution.Partition) error {
+	return r.db.WithContext(ctx).Exec("DROP TABLE IF EXISTS " + quoteIdent(p.Name())).Error
+}
+
+// quoteIdent quotes a PostgreSQL identifier
+func quoteIdent(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
@@ -0,0 +1,94 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jaydeep/go-n8n/internal/domain/schedule"
+	"github.com/jaydeep/go-n8n/pkg/database"
+	"gorm.io/gorm"
+)
+
+// ScheduleRepository implements schedule.Repository using PostgreSQL
+type ScheduleRepository struct {
+	db *database.DB
+}
+
+// NewScheduleRepository creates a new schedule repository
+func NewScheduleRepository(db *database.DB) *ScheduleRepository {
+	return &ScheduleRepository{db: db}
+}
+
+// Create inserts a new schedule
+func (r *ScheduleRepository) Create(ctx context.Context, s *schedule.Schedule) error {
+	return r.db.WithContext(ctx).Create(s).Error
+}
+
+// Update saves all fields of a schedule
+func (r *ScheduleRepository) Update(ctx context.Context, s *schedule.Schedule) error {
+	return r.db.WithContext(ctx).Save(s).Error
+}
+
+// Delete removes a schedule
+func (r *ScheduleRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	result := r.db.WithContext(ctx).Delete(&schedule.Schedule{}, "id = ?", id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return schedule.ErrScheduleNotFound
+	}
+	return nil
+}
+
+// FindByID retrieves a schedule by ID
+func (r *ScheduleRepository) FindByID(ctx context.Context, id uuid.UUID) (*schedule.Schedule, error) {
+	var s schedule.Schedule
+	err := r.db.WithContext(ctx).First(&s, "id = ?", id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, schedule.ErrScheduleNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// List returns schedules of workflows owned by userID, or all schedules
+// when userID is nil
+func (r *ScheduleRepository) List(ctx context.Context, userID *uuid.UUID) ([]*schedule.Schedule, error) {
+	var schedules []*schedule.Schedule
+	query := r.db.WithContext(ctx).
+		Joins("JOIN workflows ON workflows.id = scheduled_workflows.workflow_id AND workflows.deleted_at IS NULL")
+	if userID != nil {
+		query = query.Where("workflows.user_id = ?", *userID)
+	}
+	err := query.Order("scheduled_workflows.created_at DESC").Find(&schedules).Error
+	return schedules, err
+}
+
+// FindDue returns active schedules whose next run is before the given time
+func (r *ScheduleRepository) FindDue(ctx context.Context, before time.Time, limit int) ([]*schedule.Schedule, error) {
+	var schedules []*schedule.Schedule
+	err := r.db.WithContext(ctx).
+		Where("is_active = ? AND next_run_at IS NOT NULL AND next_run_at <= ?", true, before).
+		Order("next_run_at ASC").
+		Limit(limit).
+		Find(&schedules).Error
+	return schedules, err
+}
+
+// Claim records a run and moves the schedule to its next run if its next
+// run is still the expected one
+func (r *ScheduleRepository) Claim(ctx context.Context, id uuid.UUID, expected time.Time, ranAt time.Time, next *time.Time) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&schedule.Schedule{}).
+		Where("id = ? AND is_active = ? AND next_run_at = ?", id, true, expected).
+		Updates(map[string]interface{}{
+			"last_run_at": ranAt,
+			"next_run_at": next,
+		})
+	return result.RowsAffected == 1, result.Error
+}
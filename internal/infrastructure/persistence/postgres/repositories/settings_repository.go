@@ -0,0 +1,54 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jaydeep/go-n8n/internal/domain/settings"
+	"github.com/jaydeep/go-n8n/pkg/database"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// SettingsRepository implements settings.Repository using PostgreSQL
+type SettingsRepository struct {
+	db *database.DB
+}
+
+// NewSettingsRepository creates a new settings repository
+func NewSettingsRepository(db *database.DB) *SettingsRepository {
+	return &SettingsRepository{db: db}
+}
+
+// List returns the stored settings of a scope
+func (r *SettingsRepository) List(ctx context.Context, scope settings.Scope, scopeID uuid.UUID) ([]*settings.Setting, error) {
+	var list []*settings.Setting
+	err := r.db.WithContext(ctx).
+		Where("scope = ? AND scope_id = ?", scope, scopeID).
+		Order("key ASC").
+		Find(&list).Error
+	return list, err
+}
+
+// Save upserts settings and deletes the keys in reset in one transaction
+func (r *SettingsRepository) Save(ctx context.Context, scope settings.Scope, scopeID uuid.UUID, list []*settings.Setting, reset []string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if len(list) > 0 {
+			err := tx.Clauses(clause.OnConflict{
+				Columns:   []clause.Column{{Name: "scope"}, {Name: "scope_id"}, {Name: "key"}},
+				DoUpdates: clause.AssignmentColumns([]string{"type", "value", "updated_by", "updated_at"}),
+			}).Create(&list).Error
+			if err != nil {
+				return err
+			}
+		}
+		if len(reset) > 0 {
+			err := tx.Where("scope = ? AND scope_id = ? AND key IN ?", scope, scopeID, reset).
+				Delete(&settings.Setting{}).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
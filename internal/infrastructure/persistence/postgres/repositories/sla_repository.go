@@ -0,0 +1,63 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/jaydeep/go-n8n/internal/domain/sla"
+	"github.com/jaydeep/go-n8n/pkg/database"
+	"gorm.io/gorm"
+)
+
+// SLARepository implements sla.Repository using PostgreSQL
+type SLARepository struct {
+	db *database.DB
+}
+
+// NewSLARepository creates a new SLA policy repository
+func NewSLARepository(db *database.DB) *SLARepository {
+	return &SLARepository{db: db}
+}
+
+// Create stores a new policy
+func (r *SLARepository) Create(ctx context.Context, p *sla.Policy) error {
+	return r.db.WithContext(ctx).Create(p).Error
+}
+
+// Update saves all fields of a policy
+func (r *SLARepository) Update(ctx context.Context, p *sla.Policy) error {
+	return r.db.WithContext(ctx).Save(p).Error
+}
+
+// Delete removes the policy of a workflow
+func (r *SLARepository) Delete(ctx context.Context, workflowID uuid.UUID) error {
+	result := r.db.WithContext(ctx).Delete(&sla.Policy{}, "workflow_id = ?", workflowID)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return sla.ErrPolicyNotFound
+	}
+	return nil
+}
+
+// FindByWorkflow retrieves the policy of a workflow
+func (r *SLARepository) FindByWorkflow(ctx context.Context, workflowID uuid.UUID) (*sla.Policy, error) {
+	var p sla.Policy
+	err := r.db.WithContext(ctx).First(&p, "workflow_id = ?", workflowID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, sla.ErrPolicyNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// List returns every policy, oldest first
+func (r *SLARepository) List(ctx context.Context) ([]*sla.Policy, error) {
+	var policies []*sla.Policy
+	err := r.db.WithContext(ctx).Order("created_at ASC").Find(&policies).Error
+	return policies, err
+}
@@ -0,0 +1,82 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/jaydeep/go-n8n/internal/domain/teamnode"
+	"github.com/jaydeep/go-n8n/pkg/database"
+	"gorm.io/gorm"
+)
+
+// TeamNodeRepository implements teamnode.Repository using PostgreSQL
+type TeamNodeRepository struct {
+	db *database.DB
+}
+
+// NewTeamNodeRepository creates a new team node repository
+func NewTeamNodeRepository(db *database.DB) *TeamNodeRepository {
+	return &TeamNodeRepository{db: db}
+}
+
+// Create inserts a new team node
+func (r *TeamNodeRepository) Create(ctx context.Context, n *teamnode.TeamNode) error {
+	return r.db.WithContext(ctx).Create(n).Error
+}
+
+// Update saves all fields of a team node
+func (r *TeamNodeRepository) Update(ctx context.Context, n *teamnode.TeamNode) error {
+	return r.db.WithContext(ctx).Save(n).Error
+}
+
+// Delete removes a team node
+func (r *TeamNodeRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	result := r.db.WithContext(ctx).Delete(&teamnode.TeamNode{}, "id = ?", id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return teamnode.ErrTeamNodeNotFound
+	}
+	return nil
+}
+
+// FindByID retrieves a team node by ID
+func (r *TeamNodeRepository) FindByID(ctx context.Context, id uuid.UUID) (*teamnode.TeamNode, error) {
+	return r.findOne(ctx, "id = ?", id)
+}
+
+// FindByType retrieves a team node by its node type
+func (r *TeamNodeRepository) FindByType(ctx context.Context, nodeType string) (*teamnode.TeamNode, error) {
+	return r.findOne(ctx, "type = ?", nodeType)
+}
+
+func (r *TeamNodeRepository) findOne(ctx context.Context, query string, arg interface{}) (*teamnode.TeamNode, error) {
+	var n teamnode.TeamNode
+	err := r.db.WithContext(ctx).First(&n, query, arg).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, teamnode.ErrTeamNodeNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &n, nil
+}
+
+// FindByTeam returns the nodes of a team
+func (r *TeamNodeRepository) FindByTeam(ctx context.Context, teamID uuid.UUID) ([]*teamnode.TeamNode, error) {
+	var nodes []*teamnode.TeamNode
+	err := r.db.WithContext(ctx).
+		Where("team_id = ?", teamID).
+		Order("name ASC").
+		Find(&nodes).Error
+	return nodes, err
+}
+
+// FindAll returns the nodes of every team
+func (r *TeamNodeRepository) FindAll(ctx context.Context) ([]*teamnode.TeamNode, error) {
+	var nodes []*teamnode.TeamNode
+	err := r.db.WithContext(ctx).Find(&nodes).Error
+	return nodes, err
+}
@@ -0,0 +1,163 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jaydeep/go-n8n/internal/domain/user"
+	"github.com/jaydeep/go-n8n/pkg/database"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// lastActive is when a user was last seen, falling back to their last
+// login and then the account's creation
+const lastActive = "COALESCE(user_activity.last_seen_at, users.last_login_at, users.created_at)"
+
+// UserRepository implements user.Repository using PostgreSQL
+type UserRepository struct {
+	db *database.DB
+}
+
+// NewUserRepository creates a new user repository
+func NewUserRepository(db *database.DB) *UserRepository {
+	return &UserRepository{db: db}
+}
+
+// withActivity selects users with their last activity
+func (r *UserRepository) withActivity(ctx context.Context) *gorm.DB {
+	return r.db.WithContext(ctx).
+		Model(&user.User{}).
+		Select("users.*, user_activity.last_seen_at").
+		Joins("LEFT JOIN user_activity ON user_activity.user_id = users.id")
+}
+
+// FindByID retrieves an active user by ID
+func (r *UserRepository) FindByID(ctx context.Context, id uuid.UUID) (*user.User, error) {
+	var u user.User
+	err := r.withActivity(ctx).First(&u, "users.id = ? AND users.deleted_at IS NULL", id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, user.ErrUserNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &u, nil
+}
+
+// FindByIDs retrieves the active users with the given IDs
+func (r *UserRepository) FindByIDs(ctx context.Context, ids []uuid.UUID) ([]*user.User, error) {
+	var users []*user.User
+	err := r.withActivity(ctx).
+		Where("users.id IN ? AND users.deleted_at IS NULL", ids).
+		Find(&users).Error
+	return users, err
+}
+
+// FindByEmails retrieves the active users with the given emails
+func (r *UserRepository) FindByEmails(ctx context.Context, emails []string) ([]*user.User, error) {
+	var users []*user.User
+	err := r.withActivity(ctx).
+		Where("users.email IN ? AND users.deleted_at IS NULL", emails).
+		Find(&users).Error
+	return users, err
+}
+
+// List returns the users matching filter ordered by name
+func (r *UserRepository) List(ctx context.Context, filter user.ListFilter) ([]*user.User, error) {
+	query := r.withActivity(ctx).Where("users.deleted_at IS NULL")
+	if filter.IsActive != nil {
+		query = query.Where("users.is_active = ?", *filter.IsActive)
+	}
+	if len(filter.Roles) > 0 {
+		query = query.Where("users.role IN ?", filter.Roles)
+	}
+	if filter.LastActiveBefore != nil {
+		query = query.Where(lastActive+" < ?", *filter.LastActiveBefore)
+	}
+
+	var users []*user.User
+	err := query.
+		Order("users.name ASC").
+		Limit(filter.Limit).
+		Offset(filter.Offset).
+		Find(&users).Error
+	return users, err
+}
+
+// SetActive activates or deactivates a user
+func (r *UserRepository) SetActive(ctx context.Context, id uuid.UUID, active bool) error {
+	result := r.db.WithContext(ctx).
+		Model(&user.User{}).
+		Where("id = ? AND deleted_at IS NULL", id).
+		Update("is_active", active)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return user.ErrUserNotFound
+	}
+	return nil
+}
+
+// FindTeam retrieves a team without its members
+func (r *UserRepository) FindTeam(ctx context.Context, id uuid.UUID) (*user.Team, error) {
+	var t user.Team
+	err := r.db.WithContext(ctx).First(&t, "id = ?", id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, user.ErrTeamNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// FindTeamMember retrieves a user's membership in a team
+func (r *UserRepository) FindTeamMember(ctx context.Context, teamID, userID uuid.UUID) (*user.TeamMember, error) {
+	var m user.TeamMember
+	err := r.db.WithContext(ctx).First(&m, "team_id = ? AND user_id = ?", teamID, userID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, user.ErrTeamMemberNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+// TeamIDs returns the IDs of the teams a user belongs to
+func (r *UserRepository) TeamIDs(ctx context.Context, userID uuid.UUID) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	err := r.db.WithContext(ctx).
+		Model(&user.TeamMember{}).
+		Where("user_id = ?", userID).
+		Pluck("team_id", &ids).Error
+	return ids, err
+}
+
+// RecordActivity upserts the last seen times, keeping later stored times
+func (r *UserRepository) RecordActivity(ctx context.Context, activity []*user.Activity) error {
+	if len(activity) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"last_seen_at"}),
+		Where: clause.Where{Exprs: []clause.Expression{
+			clause.Expr{SQL: "user_activity.last_seen_at < excluded.last_seen_at"},
+		}},
+	}).Create(&activity).Error
+}
+
+// DeactivateInactive deactivates an active user last active before before
+func (r *UserRepository) DeactivateInactive(ctx context.Context, id uuid.UUID, before time.Time) (bool, error) {
+	result := r.db.WithContext(ctx).Exec(`
+		UPDATE users SET is_active = ?
+		WHERE id = ? AND is_active = ? AND deleted_at IS NULL
+		  AND COALESCE((SELECT last_seen_at FROM user_activity WHERE user_id = users.id), last_login_at, created_at) < ?`,
+		false, id, true, before)
+	return result.RowsAffected > 0, result.Error
+}
@@ -0,0 +1,67 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jaydeep/go-n8n/internal/domain/execution"
+	"github.com/jaydeep/go-n8n/pkg/database"
+	"gorm.io/gorm"
+)
+
+// WaitRepository implements execution.WaitRepository using PostgreSQL
+type WaitRepository struct {
+	db *database.DB
+}
+
+// NewWaitRepository creates a new waiting execution repository
+func NewWaitRepository(db *database.DB) *WaitRepository {
+	return &WaitRepository{db: db}
+}
+
+// Save inserts the wait of an execution or replaces its earlier one
+func (r *WaitRepository) Save(ctx context.Context, wait *execution.WaitingExecution) error {
+	return r.db.WithContext(ctx).Save(wait).Error
+}
+
+// FindByExecution retrieves the wait of an execution
+func (r *WaitRepository) FindByExecution(ctx context.Context, executionID uuid.UUID) (*execution.WaitingExecution, error) {
+	var wait execution.WaitingExecution
+	err := r.db.WithContext(ctx).First(&wait, "execution_id = ?", executionID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, execution.ErrExecutionNotWaiting
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &wait, nil
+}
+
+// FindDue returns unclaimed waits resuming before the given time
+func (r *WaitRepository) FindDue(ctx context.Context, before time.Time, limit int) ([]*execution.WaitingExecution, error) {
+	var waits []*execution.WaitingExecution
+	err := r.db.WithContext(ctx).
+		Where("resumed_at IS NULL AND resume_at <= ?", before).
+		Order("resume_at ASC").
+		Limit(limit).
+		Find(&waits).Error
+	return waits, err
+}
+
+// Claim marks an unclaimed wait resumed. The condition on resumed_at makes
+// the claim atomic, so a wait resumed by its timer and by request at once
+// is resumed once.
+func (r *WaitRepository) Claim(ctx context.Context, executionID uuid.UUID, data map[string]interface{}, at time.Time) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&execution.WaitingExecution{}).
+		Where("execution_id = ? AND resumed_at IS NULL", executionID).
+		Updates(execution.WaitingExecution{ResumeData: data, ResumedAt: &at})
+	return result.RowsAffected == 1, result.Error
+}
+
+// Delete removes the wait of an execution
+func (r *WaitRepository) Delete(ctx context.Context, executionID uuid.UUID) error {
+	return r.db.WithContext(ctx).Delete(&execution.WaitingExecution{}, "execution_id = ?", executionID).Error
+}
@@ -0,0 +1,74 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jaydeep/go-n8n/internal/domain/worker"
+	"github.com/jaydeep/go-n8n/pkg/database"
+	"gorm.io/gorm"
+)
+
+// WorkerRepository implements worker.Repository using PostgreSQL
+type WorkerRepository struct {
+	db *database.DB
+}
+
+// NewWorkerRepository creates a new worker repository
+func NewWorkerRepository(db *database.DB) *WorkerRepository {
+	return &WorkerRepository{db: db}
+}
+
+// Create inserts a new worker
+func (r *WorkerRepository) Create(ctx context.Context, w *worker.Worker) error {
+	return r.db.WithContext(ctx).Create(w).Error
+}
+
+// Update saves all fields of a worker
+func (r *WorkerRepository) Update(ctx context.Context, w *worker.Worker) error {
+	return r.db.WithContext(ctx).Save(w).Error
+}
+
+// FindByID retrieves a worker by ID
+func (r *WorkerRepository) FindByID(ctx context.Context, id uuid.UUID) (*worker.Worker, error) {
+	var w worker.Worker
+	err := r.db.WithContext(ctx).First(&w, "id = ?", id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, worker.ErrWorkerNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &w, nil
+}
+
+// FindLive returns running workers with a heartbeat after since
+func (r *WorkerRepository) FindLive(ctx context.Context, since time.Time) ([]*worker.Worker, error) {
+	var workers []*worker.Worker
+	err := r.db.WithContext(ctx).
+		Where("status = ? AND last_heartbeat_at >= ?", worker.StatusRunning, since).
+		Order("started_at ASC").
+		Find(&workers).Error
+	return workers, err
+}
+
+// FindStale returns running workers with a heartbeat before the given time
+func (r *WorkerRepository) FindStale(ctx context.Context, before time.Time) ([]*worker.Worker, error) {
+	var workers []*worker.Worker
+	err := r.db.WithContext(ctx).
+		Where("status = ? AND last_heartbeat_at < ?", worker.StatusRunning, before).
+		Find(&workers).Error
+	return workers, err
+}
+
+// FindDeadSince returns workers marked dead after since
+func (r *WorkerRepository) FindDeadSince(ctx context.Context, since time.Time) ([]*worker.Worker, error) {
+	var workers []*worker.Worker
+	err := r.db.WithContext(ctx).
+		Where("status = ? AND stopped_at >= ?", worker.StatusDead, since).
+		Order("stopped_at ASC").
+		Find(&workers).Error
+	return workers, err
+}
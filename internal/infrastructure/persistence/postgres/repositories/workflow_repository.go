@@ -0,0 +1,315 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jaydeep/go-n8n/internal/domain/workflow"
+	"github.com/jaydeep/go-n8n/pkg/database"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// WorkflowRepository implements workflow.Repository using PostgreSQL
+type WorkflowRepository struct {
+	db *database.DB
+}
+
+// NewWorkflowRepository creates a new workflow repository
+func NewWorkflowRepository(db *database.DB) *WorkflowRepository {
+	return &WorkflowRepository{db: db}
+}
+
+// Create inserts a new workflow
+func (r *WorkflowRepository) Create(ctx context.Context, w *workflow.Workflow) error {
+	// Deleted workflows keep their row, so their IDs cannot be reused
+	if w.ID != uuid.Nil {
+		var count int64
+		if err := r.db.WithContext(ctx).Model(&workflow.Workflow{}).Where("id = ?", w.ID).Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			return workflow.ErrWorkflowIDTaken
+		}
+	}
+	return r.db.WithContext(ctx).Create(w).Error
+}
+
+// Update saves all fields of a workflow
+func (r *WorkflowRepository) Update(ctx context.Context, w *workflow.Workflow) error {
+	return r.db.WithContext(ctx).Save(w).Error
+}
+
+// FindByID retrieves a non-deleted workflow by ID
+func (r *WorkflowRepository) FindByID(ctx context.Context, id uuid.UUID) (*workflow.Workflow, error) {
+	var w workflow.Workflow
+	err := r.db.WithContext(ctx).First(&w, "id = ? AND deleted_at IS NULL", id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, workflow.ErrWorkflowNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &w, nil
+}
+
+// FindByIDs retrieves the non-deleted workflows with the given IDs
+func (r *WorkflowRepository) FindByIDs(ctx context.Context, ids []uuid.UUID) ([]*workflow.Workflow, error) {
+	var workflows []*workflow.Workflow
+	err := r.db.WithContext(ctx).
+		Where("id IN ? AND deleted_at IS NULL", ids).
+		Find(&workflows).Error
+	return workflows, err
+}
+
+// FindByName retrieves the non-deleted workflow of a user by name
+func (r *WorkflowRepository) FindByName(ctx context.Context, userID uuid.UUID, name string) (*workflow.Workflow, error) {
+	var w workflow.Workflow
+	err := r.db.WithContext(ctx).First(&w, "user_id = ? AND name = ? AND deleted_at IS NULL", userID, name).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, workflow.ErrWorkflowNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &w, nil
+}
+
+// FindBySlug retrieves the non-deleted workflow with the slug
+func (r *WorkflowRepository) FindBySlug(ctx context.Context, slug string) (*workflow.Workflow, error) {
+	var w workflow.Workflow
+	err := r.db.WithContext(ctx).First(&w, "slug = ? AND deleted_at IS NULL", slug).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, workflow.ErrWorkflowNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &w, nil
+}
+
+// Delete soft deletes a workflow, freeing its name and slug
+func (r *WorkflowRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	result := r.db.WithContext(ctx).Model(&workflow.Workflow{}).
+		Where("id = ? AND deleted_at IS NULL", id).
+		Update("deleted_at", time.Now().UTC())
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return workflow.ErrWorkflowNotFound
+	}
+	return nil
+}
+
+// List returns workflows owned by userID, or all workflows when userID is
+// nil, most recently updated first
+func (r *WorkflowRepository) List(ctx context.Context, userID *uuid.UUID, limit, offset int) ([]*workflow.Workflow, error) {
+	query := r.db.WithContext(ctx).
+		Where("deleted_at IS NULL").
+		Order("updated_at DESC").
+		Limit(limit).
+		Offset(offset)
+	if userID != nil {
+		query = query.Where("user_id = ?", *userID)
+	}
+
+	var workflows []*workflow.Workflow
+	err := query.Find(&workflows).Error
+	return workflows, err
+}
+
+// WorkflowVersionRepository implements workflow.VersionRepository using PostgreSQL
+type WorkflowVersionRepository struct {
+	db *database.DB
+}
+
+// NewWorkflowVersionRepository creates a new workflow version repository
+func NewWorkflowVersionRepository(db *database.DB) *WorkflowVersionRepository {
+	return &WorkflowVersionRepository{db: db}
+}
+
+// Create inserts a new version snapshot
+func (r *WorkflowVersionRepository) Create(ctx context.Context, v *workflow.WorkflowVersion) error {
+	return r.db.WithContext(ctx).Create(v).Error
+}
+
+// FindByVersion retrieves a specific version of a workflow
+func (r *WorkflowVersionRepository) FindByVersion(ctx context.Context, workflowID uuid.UUID, version int) (*workflow.WorkflowVersion, error) {
+	var v workflow.WorkflowVersion
+	err := r.db.WithContext(ctx).
+		First(&v, "workflow_id = ? AND version = ?", workflowID, version).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, workflow.ErrVersionNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &v, nil
+}
+
+// ListByWorkflow returns all versions of a workflow, newest first
+func (r *WorkflowVersionRepository) ListByWorkflow(ctx context.Context, workflowID uuid.UUID) ([]*workflow.WorkflowVersion, error) {
+	var versions []*workflow.WorkflowVersion
+	err := r.db.WithContext(ctx).
+		Where("workflow_id = ?", workflowID).
+		Order("version DESC").
+		Find(&versions).Error
+	return versions, err
+}
+
+// ChangeRequestRepository implements workflow.ChangeRequestRepository using PostgreSQL
+type ChangeRequestRepository struct {
+	db *database.DB
+}
+
+// NewChangeRequestRepository creates a new change request repository
+func NewChangeRequestRepository(db *database.DB) *ChangeRequestRepository {
+	return &ChangeRequestRepository{db: db}
+}
+
+// Create inserts a new change request
+func (r *ChangeRequestRepository) Create(ctx context.Context, cr *workflow.ChangeRequest) error {
+	return r.db.WithContext(ctx).Create(cr).Error
+}
+
+// Update saves all fields of a change request
+func (r *ChangeRequestRepository) Update(ctx context.Context, cr *workflow.ChangeRequest) error {
+	return r.db.WithContext(ctx).Save(cr).Error
+}
+
+// FindByID retrieves a change request by ID
+func (r *ChangeRequestRepository) FindByID(ctx context.Context, id uuid.UUID) (*workflow.ChangeRequest, error) {
+	var cr workflow.ChangeRequest
+	err := r.db.WithContext(ctx).First(&cr, "id = ?", id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, workflow.ErrChangeRequestNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &cr, nil
+}
+
+// FindPending retrieves the pending change request of a workflow
+func (r *ChangeRequestRepository) FindPending(ctx context.Context, workflowID uuid.UUID) (*workflow.ChangeRequest, error) {
+	var cr workflow.ChangeRequest
+	err := r.db.WithContext(ctx).
+		First(&cr, "workflow_id = ? AND status = ?", workflowID, workflow.ChangeRequestPending).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, workflow.ErrChangeRequestNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &cr, nil
+}
+
+// ListByWorkflow returns the change requests of a workflow, newest first
+func (r *ChangeRequestRepository) ListByWorkflow(ctx context.Context, workflowID uuid.UUID) ([]*workflow.ChangeRequest, error) {
+	var requests []*workflow.ChangeRequest
+	err := r.db.WithContext(ctx).
+		Where("workflow_id = ?", workflowID).
+		Order("created_at DESC").
+		Find(&requests).Error
+	return requests, err
+}
+
+// WorkflowSecretRepository implements workflow.SecretRepository using PostgreSQL
+type WorkflowSecretRepository struct {
+	db *database.DB
+}
+
+// NewWorkflowSecretRepository creates a new workflow secret repository
+func NewWorkflowSecretRepository(db *database.DB) *WorkflowSecretRepository {
+	return &WorkflowSecretRepository{db: db}
+}
+
+// Create inserts a new secret
+func (r *WorkflowSecretRepository) Create(ctx context.Context, s *workflow.Secret) error {
+	return r.db.WithContext(ctx).Create(s).Error
+}
+
+// Update saves all fields of a secret
+func (r *WorkflowSecretRepository) Update(ctx context.Context, s *workflow.Secret) error {
+	return r.db.WithContext(ctx).Save(s).Error
+}
+
+// FindByName retrieves a secret of a workflow by name
+func (r *WorkflowSecretRepository) FindByName(ctx context.Context, workflowID uuid.UUID, name string) (*workflow.Secret, error) {
+	var s workflow.Secret
+	err := r.db.WithContext(ctx).First(&s, "workflow_id = ? AND name = ?", workflowID, name).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, workflow.ErrSecretNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// ListByWorkflow returns the secrets of a workflow ordered by name
+func (r *WorkflowSecretRepository) ListByWorkflow(ctx context.Context, workflowID uuid.UUID) ([]*workflow.Secret, error) {
+	var secrets []*workflow.Secret
+	err := r.db.WithContext(ctx).
+		Where("workflow_id = ?", workflowID).
+		Order("name ASC").
+		Find(&secrets).Error
+	return secrets, err
+}
+
+// Delete removes a secret of a workflow
+func (r *WorkflowSecretRepository) Delete(ctx context.Context, workflowID uuid.UUID, name string) error {
+	result := r.db.WithContext(ctx).Delete(&workflow.Secret{}, "workflow_id = ? AND name = ?", workflowID, name)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return workflow.ErrSecretNotFound
+	}
+	return nil
+}
+
+// PinnedDataRepository implements workflow.PinnedDataRepository using
+// PostgreSQL
+type PinnedDataRepository struct {
+	db *database.DB
+}
+
+// NewPinnedDataRepository creates a new pinned data repository
+func NewPinnedDataRepository(db *database.DB) *PinnedDataRepository {
+	return &PinnedDataRepository{db: db}
+}
+
+// Save creates or replaces the pinned data of a node
+func (r *PinnedDataRepository) Save(ctx context.Context, p *workflow.PinnedData) error {
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "workflow_id"}, {Name: "node_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"items", "pinned_by", "updated_at"}),
+	}).Create(p).Error
+}
+
+// ListByWorkflow returns the pinned data of the nodes of a workflow ordered
+// by node ID
+func (r *PinnedDataRepository) ListByWorkflow(ctx context.Context, workflowID uuid.UUID) ([]*workflow.PinnedData, error) {
+	var pinned []*workflow.PinnedData
+	err := r.db.WithContext(ctx).
+		Where("workflow_id = ?", workflowID).
+		Order("node_id ASC").
+		Find(&pinned).Error
+	return pinned, err
+}
+
+// Delete removes the pinned data of a node
+func (r *PinnedDataRepository) Delete(ctx context.Context, workflowID uuid.UUID, nodeID string) error {
+	result := r.db.WithContext(ctx).Delete(&workflow.PinnedData{}, "workflow_id = ? AND node_id = ?", workflowID, nodeID)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return workflow.ErrPinnedDataNotFound
+	}
+	return nil
+}
@@ -0,0 +1,32 @@
+// Package sqlite holds the schema of the SQLite database used in lite mode.
+// The repositories are shared with PostgreSQL.
+package sqlite
+
+import (
+	_ "embed"
+	"fmt"
+
+	"github.com/jaydeep/go-n8n/pkg/database"
+)
+
+//go:embed schema.sql
+var schemaSQL string
+
+// ApplySchema creates the tables and indexes that do not exist yet. It is
+// safe to run on every start.
+func ApplySchema(db *database.DB) error {
+	if db.Driver() != database.DriverSQLite {
+		return fmt.Errorf("schema is for sqlite, database driver is %s", db.Driver())
+	}
+
+	// The statements run on the raw connection; prepared statements would
+	// stop after the first one
+	sqlDB, err := db.DB.DB()
+	if err != nil {
+		return err
+	}
+	if _, err := sqlDB.Exec(schemaSQL); err != nil {
+		return fmt.Errorf("failed to apply sqlite schema: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,610 @@
+-- SQLite schema for lite mode, mirroring the PostgreSQL migrations up to
+-- 032_queue_job_lanes.sql. Keep it in step when adding migrations.
+-- queue_jobs has no counterpart, as lite mode queues jobs in process, and
+-- executions are not partitioned.
+--
+-- UUIDs are stored as text and JSONB and array columns as JSON and array
+-- literal text. Timestamps keep their TIMESTAMP type so the driver parses
+-- them back into times. updated_at is maintained by the repositories, so
+-- the PostgreSQL triggers have no counterpart here.
+
+CREATE TABLE IF NOT EXISTS users (
+    id TEXT PRIMARY KEY DEFAULT (uuid_generate_v4()),
+    email VARCHAR(255) UNIQUE NOT NULL,
+    password_hash VARCHAR(255) NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    role VARCHAR(50) DEFAULT 'user',
+    is_active BOOLEAN DEFAULT true,
+    email_verified BOOLEAN DEFAULT false,
+    email_verified_at TIMESTAMP,
+    profile_picture TEXT,
+    settings TEXT DEFAULT '{}',
+    last_login_at TIMESTAMP,
+    password_changed_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS teams (
+    id TEXT PRIMARY KEY DEFAULT (uuid_generate_v4()),
+    name VARCHAR(255) NOT NULL,
+    description TEXT,
+    owner_id TEXT NOT NULL REFERENCES users(id),
+    settings TEXT DEFAULT '{}',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS team_members (
+    id TEXT PRIMARY KEY DEFAULT (uuid_generate_v4()),
+    team_id TEXT NOT NULL REFERENCES teams(id) ON DELETE CASCADE,
+    user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    role VARCHAR(50) NOT NULL DEFAULT 'member',
+    joined_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(team_id, user_id)
+);
+
+CREATE TABLE IF NOT EXISTS workflows (
+    id TEXT PRIMARY KEY DEFAULT (uuid_generate_v4()),
+    name VARCHAR(255) NOT NULL,
+    slug VARCHAR(63),
+    description TEXT,
+    user_id TEXT NOT NULL REFERENCES users(id),
+    team_id TEXT REFERENCES teams(id),
+    is_active BOOLEAN DEFAULT false,
+    nodes TEXT DEFAULT '[]',
+    connections TEXT DEFAULT '[]',
+    settings TEXT DEFAULT '{}',
+    tags TEXT DEFAULT '{}',
+    version INT DEFAULT 1,
+    published_version INT,
+    variables TEXT DEFAULT '{}',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP,
+    CONSTRAINT workflow_name_user_unique UNIQUE(name, user_id, deleted_at)
+);
+
+CREATE TABLE IF NOT EXISTS executions (
+    id TEXT PRIMARY KEY DEFAULT (uuid_generate_v4()),
+    workflow_id TEXT NOT NULL REFERENCES workflows(id),
+    workflow_version INT NOT NULL,
+    status VARCHAR(50) NOT NULL,
+    mode VARCHAR(50) NOT NULL,
+    started_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    resumed_at TIMESTAMP,
+    finished_at TIMESTAMP,
+    execution_time_ms INT,
+    input_data TEXT DEFAULT '{}',
+    output_data TEXT DEFAULT '{}',
+    error_message TEXT,
+    error_node VARCHAR(255),
+    retry_of TEXT REFERENCES executions(id),
+    retry_count INT DEFAULT 0,
+    dry_run BOOLEAN DEFAULT false,
+    dry_run_options TEXT,
+    parent_execution_id TEXT,
+    root_execution_id TEXT,
+    relation VARCHAR(20),
+    correlation_id VARCHAR(128),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS execution_node_data (
+    id TEXT PRIMARY KEY DEFAULT (uuid_generate_v4()),
+    execution_id TEXT NOT NULL REFERENCES executions(id) ON DELETE CASCADE,
+    node_id VARCHAR(255) NOT NULL,
+    node_type VARCHAR(100) NOT NULL,
+    node_name VARCHAR(255),
+    status VARCHAR(50) NOT NULL,
+    input_data TEXT DEFAULT '{}',
+    output_data TEXT DEFAULT '{}',
+    error_message TEXT,
+    execution_time_ms INT,
+    started_at TIMESTAMP,
+    finished_at TIMESTAMP,
+    retry_count INT DEFAULT 0
+);
+
+CREATE TABLE IF NOT EXISTS credentials (
+    id TEXT PRIMARY KEY DEFAULT (uuid_generate_v4()),
+    name VARCHAR(255) NOT NULL,
+    slug VARCHAR(63),
+    type VARCHAR(100) NOT NULL,
+    user_id TEXT NOT NULL REFERENCES users(id),
+    team_id TEXT REFERENCES teams(id),
+    node_types TEXT DEFAULT '{}',
+    data BLOB NOT NULL,
+    iv BLOB NOT NULL,
+    health TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(name, user_id)
+);
+
+CREATE TABLE IF NOT EXISTS webhooks (
+    id TEXT PRIMARY KEY DEFAULT (uuid_generate_v4()),
+    workflow_id TEXT NOT NULL REFERENCES workflows(id) ON DELETE CASCADE,
+    node_id VARCHAR(255) NOT NULL,
+    path VARCHAR(255) UNIQUE NOT NULL,
+    method VARCHAR(10) DEFAULT 'POST',
+    is_active BOOLEAN DEFAULT true,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS scheduled_workflows (
+    id TEXT PRIMARY KEY DEFAULT (uuid_generate_v4()),
+    workflow_id TEXT NOT NULL REFERENCES workflows(id) ON DELETE CASCADE,
+    cron_expression VARCHAR(100),
+    recurrence TEXT,
+    timezone VARCHAR(50) DEFAULT 'UTC',
+    is_active BOOLEAN DEFAULT true,
+    jitter_seconds INT DEFAULT 0,
+    last_run_at TIMESTAMP,
+    next_run_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS variables (
+    id TEXT PRIMARY KEY DEFAULT (uuid_generate_v4()),
+    key VARCHAR(255) UNIQUE NOT NULL,
+    value TEXT NOT NULL,
+    type VARCHAR(50) DEFAULT 'string',
+    is_secret BOOLEAN DEFAULT false,
+    user_id TEXT REFERENCES users(id),
+    team_id TEXT REFERENCES teams(id),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS tags (
+    id TEXT PRIMARY KEY DEFAULT (uuid_generate_v4()),
+    name VARCHAR(50) UNIQUE NOT NULL,
+    color VARCHAR(7),
+    user_id TEXT REFERENCES users(id),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS workflow_tags (
+    workflow_id TEXT NOT NULL REFERENCES workflows(id) ON DELETE CASCADE,
+    tag_id TEXT NOT NULL REFERENCES tags(id) ON DELETE CASCADE,
+    PRIMARY KEY (workflow_id, tag_id)
+);
+
+CREATE TABLE IF NOT EXISTS api_keys (
+    id TEXT PRIMARY KEY DEFAULT (uuid_generate_v4()),
+    user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    key_hash VARCHAR(255) UNIQUE NOT NULL,
+    key_preview VARCHAR(8) NOT NULL,
+    scopes TEXT DEFAULT '{}',
+    expires_at TIMESTAMP,
+    last_used_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS sessions (
+    id TEXT PRIMARY KEY DEFAULT (uuid_generate_v4()),
+    user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    token VARCHAR(255) UNIQUE NOT NULL,
+    refresh_token VARCHAR(255) UNIQUE,
+    ip_address VARCHAR(45),
+    user_agent TEXT,
+    expires_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    last_used_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS audit_logs (
+    id TEXT PRIMARY KEY DEFAULT (uuid_generate_v4()),
+    user_id TEXT REFERENCES users(id),
+    action VARCHAR(100) NOT NULL,
+    resource_type VARCHAR(50) NOT NULL,
+    resource_id VARCHAR(255),
+    old_value TEXT,
+    new_value TEXT,
+    ip_address VARCHAR(45),
+    user_agent TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    sequence INTEGER,
+    prev_hash VARCHAR(64),
+    hash VARCHAR(64)
+);
+
+CREATE TABLE IF NOT EXISTS audit_anchors (
+    id TEXT PRIMARY KEY DEFAULT (uuid_generate_v4()),
+    sequence INTEGER NOT NULL,
+    hash VARCHAR(64) NOT NULL,
+    public_key TEXT NOT NULL,
+    signature TEXT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS workers (
+    id TEXT PRIMARY KEY DEFAULT (uuid_generate_v4()),
+    hostname VARCHAR(255) NOT NULL,
+    version VARCHAR(50),
+    concurrency INT DEFAULT 0,
+    queues TEXT DEFAULT '[]',
+    status VARCHAR(50) NOT NULL,
+    active_jobs INT DEFAULT 0,
+    started_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    last_heartbeat_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    stopped_at TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS workflow_versions (
+    id TEXT PRIMARY KEY DEFAULT (uuid_generate_v4()),
+    workflow_id TEXT NOT NULL REFERENCES workflows(id) ON DELETE CASCADE,
+    version INT NOT NULL,
+    name VARCHAR(255),
+    nodes TEXT DEFAULT '[]',
+    connections TEXT DEFAULT '[]',
+    settings TEXT DEFAULT '{}',
+    published_by TEXT REFERENCES users(id),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(workflow_id, version)
+);
+
+CREATE TABLE IF NOT EXISTS workflow_change_requests (
+    id TEXT PRIMARY KEY DEFAULT (uuid_generate_v4()),
+    workflow_id TEXT NOT NULL REFERENCES workflows(id) ON DELETE CASCADE,
+    version INT NOT NULL,
+    status VARCHAR(50) NOT NULL,
+    requested_by TEXT NOT NULL REFERENCES users(id),
+    nodes TEXT DEFAULT '[]',
+    connections TEXT DEFAULT '[]',
+    settings TEXT DEFAULT '{}',
+    diff TEXT DEFAULT '{}',
+    comments TEXT DEFAULT '[]',
+    decided_by TEXT REFERENCES users(id),
+    decided_at TIMESTAMP,
+    decision_note TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS instance_settings (
+    scope VARCHAR(20) NOT NULL,
+    scope_id TEXT NOT NULL DEFAULT '00000000-0000-0000-0000-000000000000',
+    key VARCHAR(255) NOT NULL,
+    type VARCHAR(20) NOT NULL,
+    value TEXT NOT NULL,
+    updated_by TEXT REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (scope, scope_id, key)
+);
+
+CREATE TABLE IF NOT EXISTS holiday_calendars (
+    id TEXT PRIMARY KEY DEFAULT (uuid_generate_v4()),
+    team_id TEXT NOT NULL REFERENCES teams(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    dates TEXT DEFAULT '[]',
+    created_by TEXT NOT NULL REFERENCES users(id),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS execution_callbacks (
+    id TEXT PRIMARY KEY DEFAULT (uuid_generate_v4()),
+    execution_id TEXT NOT NULL,
+    url TEXT NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    attempts INT DEFAULT 0,
+    next_attempt_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_error TEXT,
+    delivered_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS workflow_endpoints (
+    id TEXT PRIMARY KEY DEFAULT (uuid_generate_v4()),
+    slug VARCHAR(63) NOT NULL UNIQUE,
+    workflow_id TEXT NOT NULL REFERENCES workflows(id) ON DELETE CASCADE,
+    workflow_version INT NOT NULL,
+    user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    description TEXT,
+    method VARCHAR(10) NOT NULL,
+    api_key_hash VARCHAR(64) NOT NULL,
+    api_key_hint VARCHAR(20),
+    rate_limit INT DEFAULT 0,
+    allowed_ips TEXT,
+    webhook_allowed_ips TEXT,
+    request_schema TEXT,
+    response_schema TEXT,
+    response_status INT DEFAULT 200,
+    response_field VARCHAR(255),
+    body_format VARCHAR(10) DEFAULT 'json',
+    raw_body BOOLEAN DEFAULT false,
+    cors TEXT,
+    region VARCHAR(63),
+    is_active BOOLEAN DEFAULT true,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS workflow_endpoint_usage (
+    endpoint_id TEXT NOT NULL REFERENCES workflow_endpoints(id) ON DELETE CASCADE,
+    day DATE NOT NULL,
+    calls BIGINT DEFAULT 0,
+    errors BIGINT DEFAULT 0,
+    rate_limited BIGINT DEFAULT 0,
+    total_duration_ms BIGINT DEFAULT 0,
+    PRIMARY KEY (endpoint_id, day)
+);
+
+CREATE TABLE IF NOT EXISTS execution_rollups (
+    bucket TIMESTAMP NOT NULL,
+    workflow_id TEXT NOT NULL,
+    team_id TEXT,
+    executions BIGINT NOT NULL DEFAULT 0,
+    succeeded BIGINT NOT NULL DEFAULT 0,
+    failed BIGINT NOT NULL DEFAULT 0,
+    cancelled BIGINT NOT NULL DEFAULT 0,
+    finished BIGINT NOT NULL DEFAULT 0,
+    duration_sum_ms BIGINT NOT NULL DEFAULT 0,
+    refreshed_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (bucket, workflow_id)
+);
+
+CREATE TABLE IF NOT EXISTS execution_usage (
+    execution_id TEXT PRIMARY KEY,
+    workflow_id TEXT NOT NULL,
+    team_id TEXT,
+    user_id TEXT NOT NULL,
+    started_at TIMESTAMP NOT NULL,
+    cpu_time_ms BIGINT NOT NULL DEFAULT 0,
+    items_processed BIGINT NOT NULL DEFAULT 0,
+    http_calls BIGINT NOT NULL DEFAULT 0,
+    llm_input_tokens BIGINT NOT NULL DEFAULT 0,
+    llm_output_tokens BIGINT NOT NULL DEFAULT 0,
+    node_types TEXT DEFAULT '{}',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS feature_flags (
+    key VARCHAR(100) PRIMARY KEY,
+    description TEXT NOT NULL DEFAULT '',
+    enabled BOOLEAN NOT NULL DEFAULT false,
+    rollout INT NOT NULL DEFAULT 0 CHECK (rollout BETWEEN 0 AND 100),
+    updated_by TEXT REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS feature_flag_targets (
+    flag_key VARCHAR(100) NOT NULL REFERENCES feature_flags(key) ON DELETE CASCADE,
+    type VARCHAR(20) NOT NULL,
+    target_id TEXT NOT NULL,
+    enabled BOOLEAN NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (flag_key, type, target_id)
+);
+
+CREATE TABLE IF NOT EXISTS announcements (
+    id TEXT PRIMARY KEY DEFAULT (uuid_generate_v4()),
+    title VARCHAR(255) NOT NULL,
+    message TEXT NOT NULL,
+    severity VARCHAR(20) NOT NULL DEFAULT 'info',
+    expires_at TIMESTAMP,
+    created_by TEXT REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS user_activity (
+    user_id TEXT PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    last_seen_at TIMESTAMP NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS workflow_endpoint_buffer (
+    id TEXT PRIMARY KEY DEFAULT (uuid_generate_v4()),
+    endpoint_id TEXT NOT NULL REFERENCES workflow_endpoints(id) ON DELETE CASCADE,
+    method VARCHAR(10) NOT NULL,
+    body TEXT,
+    query TEXT,
+    headers TEXT,
+    binary TEXT,
+    reason VARCHAR(50) NOT NULL,
+    attempts INT DEFAULT 0,
+    last_error TEXT,
+    received_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS instance_webhooks (
+    id TEXT PRIMARY KEY DEFAULT (uuid_generate_v4()),
+    name VARCHAR(100) NOT NULL,
+    url TEXT NOT NULL,
+    events TEXT NOT NULL DEFAULT '{}',
+    workflow_ids TEXT NOT NULL DEFAULT '{}',
+    secret TEXT NOT NULL,
+    secret_hint VARCHAR(20),
+    is_active BOOLEAN DEFAULT true,
+    created_by TEXT REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS instance_webhook_deliveries (
+    id TEXT PRIMARY KEY DEFAULT (uuid_generate_v4()),
+    webhook_id TEXT NOT NULL REFERENCES instance_webhooks(id) ON DELETE CASCADE,
+    event VARCHAR(100) NOT NULL,
+    payload TEXT,
+    status VARCHAR(20) NOT NULL,
+    attempts INT DEFAULT 0,
+    response_status INT DEFAULT 0,
+    duration_ms INT DEFAULT 0,
+    last_error TEXT,
+    next_attempt_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    delivered_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS alerts (
+    id TEXT PRIMARY KEY DEFAULT (uuid_generate_v4()),
+    key VARCHAR(255) NOT NULL,
+    kind VARCHAR(50) NOT NULL,
+    summary TEXT NOT NULL,
+    details TEXT,
+    status VARCHAR(20) NOT NULL,
+    fired_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    resolved_at TIMESTAMP,
+    notified_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS sla_policies (
+    id TEXT PRIMARY KEY DEFAULT (uuid_generate_v4()),
+    workflow_id TEXT NOT NULL UNIQUE REFERENCES workflows(id) ON DELETE CASCADE,
+    max_interval_minutes INT DEFAULT 0,
+    max_duration_seconds INT DEFAULT 0,
+    max_error_rate REAL DEFAULT 0,
+    error_rate_window_minutes INT DEFAULT 60,
+    status VARCHAR(20) NOT NULL,
+    breaches TEXT,
+    evaluated_at TIMESTAMP,
+    created_by TEXT REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS workflow_secrets (
+    id TEXT PRIMARY KEY DEFAULT (uuid_generate_v4()),
+    workflow_id TEXT NOT NULL REFERENCES workflows(id) ON DELETE CASCADE,
+    name VARCHAR(64) NOT NULL,
+    data BLOB NOT NULL,
+    iv BLOB NOT NULL,
+    updated_by TEXT REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(workflow_id, name)
+);
+
+CREATE TABLE IF NOT EXISTS pinned_data (
+    workflow_id TEXT NOT NULL REFERENCES workflows(id) ON DELETE CASCADE,
+    node_id VARCHAR(255) NOT NULL,
+    items TEXT NOT NULL,
+    pinned_by TEXT REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (workflow_id, node_id)
+);
+
+CREATE TABLE IF NOT EXISTS execution_views (
+    id TEXT PRIMARY KEY DEFAULT (uuid_generate_v4()),
+    user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    filter TEXT NOT NULL DEFAULT '{}',
+    subscribed BOOLEAN NOT NULL DEFAULT false,
+    last_matched_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(user_id, name)
+);
+
+CREATE TABLE IF NOT EXISTS execution_holds (
+    id TEXT PRIMARY KEY DEFAULT (uuid_generate_v4()),
+    workflow_id TEXT NOT NULL REFERENCES workflows(id),
+    reason TEXT NOT NULL,
+    reference VARCHAR(200),
+    placed_by TEXT NOT NULL REFERENCES users(id),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    released_at TIMESTAMP,
+    released_by TEXT REFERENCES users(id)
+);
+
+CREATE TABLE IF NOT EXISTS waiting_executions (
+    execution_id TEXT PRIMARY KEY,
+    node_id VARCHAR(255) NOT NULL,
+    resume_at TIMESTAMP,
+    webhook BOOLEAN DEFAULT false,
+    state TEXT NOT NULL,
+    resume_data TEXT,
+    resumed_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS custom_domains (
+    id TEXT PRIMARY KEY DEFAULT (uuid_generate_v4()),
+    team_id TEXT NOT NULL REFERENCES teams(id) ON DELETE CASCADE,
+    hostname VARCHAR(253) NOT NULL UNIQUE,
+    verification_token VARCHAR(64) NOT NULL,
+    verified_at TIMESTAMP,
+    created_by TEXT NOT NULL REFERENCES users(id),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS team_nodes (
+    id TEXT PRIMARY KEY DEFAULT (uuid_generate_v4()),
+    team_id TEXT NOT NULL REFERENCES teams(id) ON DELETE CASCADE,
+    slug VARCHAR(63) NOT NULL,
+    type VARCHAR(100) NOT NULL UNIQUE,
+    name VARCHAR(255) NOT NULL,
+    descriptor TEXT NOT NULL,
+    created_by TEXT NOT NULL REFERENCES users(id),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (team_id, slug)
+);
+
+CREATE INDEX IF NOT EXISTS idx_users_active ON users(is_active) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_workflows_user_active ON workflows(user_id, is_active) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_workflows_team ON workflows(team_id) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_executions_workflow_status ON executions(workflow_id, status);
+CREATE INDEX IF NOT EXISTS idx_executions_created_at ON executions(created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_executions_started_at_id ON executions(started_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS idx_node_execution_data_execution ON execution_node_data(execution_id);
+CREATE INDEX IF NOT EXISTS idx_webhooks_path ON webhooks(path) WHERE is_active = true;
+CREATE INDEX IF NOT EXISTS idx_scheduled_workflows_due ON scheduled_workflows(next_run_at) WHERE is_active = true;
+CREATE INDEX IF NOT EXISTS idx_sessions_user ON sessions(user_id);
+CREATE INDEX IF NOT EXISTS idx_api_keys_user ON api_keys(user_id);
+CREATE INDEX IF NOT EXISTS idx_audit_logs_user ON audit_logs(user_id);
+CREATE INDEX IF NOT EXISTS idx_audit_logs_resource ON audit_logs(resource_type, resource_id);
+CREATE INDEX IF NOT EXISTS idx_audit_logs_created_at_id ON audit_logs(created_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS idx_workers_heartbeat ON workers(last_heartbeat_at) WHERE status = 'running';
+CREATE INDEX IF NOT EXISTS idx_workflow_versions_workflow ON workflow_versions(workflow_id, version DESC);
+CREATE INDEX IF NOT EXISTS idx_change_requests_workflow ON workflow_change_requests(workflow_id, created_at DESC);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_change_requests_pending ON workflow_change_requests(workflow_id) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS idx_holiday_calendars_team ON holiday_calendars(team_id);
+CREATE INDEX IF NOT EXISTS idx_execution_callbacks_pending ON execution_callbacks(next_attempt_at) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS idx_execution_callbacks_execution ON execution_callbacks(execution_id);
+CREATE INDEX IF NOT EXISTS idx_workflow_endpoints_workflow ON workflow_endpoints(workflow_id);
+CREATE INDEX IF NOT EXISTS idx_workflow_endpoints_user ON workflow_endpoints(user_id);
+CREATE INDEX IF NOT EXISTS idx_execution_rollups_team_bucket ON execution_rollups(team_id, bucket);
+CREATE INDEX IF NOT EXISTS idx_execution_usage_team_started ON execution_usage(team_id, started_at);
+CREATE INDEX IF NOT EXISTS idx_execution_usage_workflow_started ON execution_usage(workflow_id, started_at);
+CREATE INDEX IF NOT EXISTS idx_execution_usage_user_started ON execution_usage(user_id, started_at);
+CREATE INDEX IF NOT EXISTS idx_announcements_expires_at ON announcements(expires_at);
+CREATE INDEX IF NOT EXISTS idx_user_activity_last_seen_at ON user_activity(last_seen_at);
+CREATE INDEX IF NOT EXISTS idx_workflow_endpoint_buffer_received_at ON workflow_endpoint_buffer(received_at, id);
+CREATE INDEX IF NOT EXISTS idx_instance_webhook_deliveries_pending ON instance_webhook_deliveries(next_attempt_at) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS idx_instance_webhook_deliveries_webhook ON instance_webhook_deliveries(webhook_id, created_at);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_alerts_firing_key ON alerts(key) WHERE status = 'firing';
+CREATE INDEX IF NOT EXISTS idx_alerts_fired_at ON alerts(fired_at DESC);
+CREATE INDEX IF NOT EXISTS idx_executions_root ON executions(root_execution_id) WHERE root_execution_id IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_executions_correlation ON executions(correlation_id) WHERE correlation_id IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_execution_views_subscribed ON execution_views(subscribed) WHERE subscribed = true;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_workflows_slug ON workflows(slug) WHERE slug IS NOT NULL AND deleted_at IS NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_credentials_slug ON credentials(slug) WHERE slug IS NOT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_audit_logs_sequence ON audit_logs(sequence) WHERE sequence IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_audit_anchors_sequence ON audit_anchors(sequence);
+CREATE INDEX IF NOT EXISTS idx_execution_holds_workflow ON execution_holds(workflow_id);
+CREATE INDEX IF NOT EXISTS idx_execution_holds_active ON execution_holds(workflow_id) WHERE released_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_waiting_executions_due ON waiting_executions(resume_at) WHERE resumed_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_custom_domains_team ON custom_domains(team_id);
+
+CREATE TRIGGER IF NOT EXISTS audit_logs_no_update BEFORE UPDATE ON audit_logs
+BEGIN
+    SELECT RAISE(ABORT, 'audit logs are append only');
+END;
+
+CREATE TRIGGER IF NOT EXISTS audit_logs_no_delete BEFORE DELETE ON audit_logs
+BEGIN
+    SELECT RAISE(ABORT, 'audit logs are append only');
+END;
@@ -0,0 +1,92 @@
+package graphql
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+const (
+	// loaderWait is how long a loader collects keys before fetching them
+	loaderWait = 2 * time.Millisecond
+	// loaderMaxBatch fetches immediately once this many keys are waiting
+	loaderMaxBatch = 100
+)
+
+// Loader batches the lookups of one request: keys requested by resolvers
+// running concurrently are fetched with a single call, and every key is
+// fetched at most once per request.
+type Loader[K comparable, V any] struct {
+	fetch func(ctx context.Context, keys []K) (map[K]V, error)
+
+	mu      sync.Mutex
+	calls   map[K]*loaderCall[V]
+	pending []K
+	timer   *time.Timer
+}
+
+type loaderCall[V any] struct {
+	done  chan struct{}
+	value V
+	found bool
+	err   error
+}
+
+// NewLoader creates a loader. fetch returns the values of the keys it
+// found; missing keys load as not found.
+func NewLoader[K comparable, V any](fetch func(ctx context.Context, keys []K) (map[K]V, error)) *Loader[K, V] {
+	return &Loader[K, V]{
+		fetch: fetch,
+		calls: make(map[K]*loaderCall[V]),
+	}
+}
+
+// Load returns the value of key, waiting for the batch it belongs to
+func (l *Loader[K, V]) Load(ctx context.Context, key K) (V, bool, error) {
+	l.mu.Lock()
+	call, ok := l.calls[key]
+	if !ok {
+		call = &loaderCall[V]{done: make(chan struct{})}
+		l.calls[key] = call
+		l.pending = append(l.pending, key)
+
+		switch {
+		case len(l.pending) >= loaderMaxBatch:
+			l.timer.Stop()
+			go l.dispatch(ctx)
+		case len(l.pending) == 1:
+			l.timer = time.AfterFunc(loaderWait, func() { l.dispatch(ctx) })
+		}
+	}
+	l.mu.Unlock()
+
+	select {
+	case <-call.done:
+		return call.value, call.found, call.err
+	case <-ctx.Done():
+		var zero V
+		return zero, false, ctx.Err()
+	}
+}
+
+// dispatch fetches the pending keys and wakes up their callers
+func (l *Loader[K, V]) dispatch(ctx context.Context) {
+	l.mu.Lock()
+	keys := l.pending
+	l.pending = nil
+	l.mu.Unlock()
+	if len(keys) == 0 {
+		return
+	}
+
+	values, err := l.fetch(ctx, keys)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, key := range keys {
+		call := l.calls[key]
+		call.value, call.found = values[key]
+		call.err = err
+		close(call.done)
+	}
+}
@@ -0,0 +1,475 @@
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/google/uuid"
+	graphqlgo "github.com/graph-gophers/graphql-go"
+	"github.com/jaydeep/go-n8n/internal/domain/credential"
+	"github.com/jaydeep/go-n8n/internal/domain/execution"
+	"github.com/jaydeep/go-n8n/internal/domain/user"
+	"github.com/jaydeep/go-n8n/internal/domain/workflow"
+)
+
+// maxPageSize caps the first argument of list fields
+const maxPageSize = 100
+
+// JSON is a scalar carrying arbitrary JSON values such as workflow nodes
+type JSON struct {
+	Value interface{}
+}
+
+// ImplementsGraphQLType maps JSON to the schema scalar
+func (JSON) ImplementsGraphQLType(name string) bool {
+	return name == "JSON"
+}
+
+// UnmarshalGraphQL accepts any input value
+func (j *JSON) UnmarshalGraphQL(input interface{}) error {
+	j.Value = input
+	return nil
+}
+
+// MarshalJSON writes the wrapped value
+func (j JSON) MarshalJSON() ([]byte, error) {
+	return json.Marshal(j.Value)
+}
+
+type pageArgs struct {
+	First  int32
+	Offset int32
+}
+
+// limits clamps page arguments to sane values
+func (a pageArgs) limits() (int, int) {
+	first, offset := int(a.First), int(a.Offset)
+	if first <= 0 || first > maxPageSize {
+		first = maxPageSize
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	return first, offset
+}
+
+func parseID(id graphqlgo.ID) (uuid.UUID, error) {
+	parsed, err := uuid.Parse(string(id))
+	if err != nil {
+		return uuid.Nil, errors.New("invalid id")
+	}
+	return parsed, nil
+}
+
+// queryResolver resolves the root query fields
+type queryResolver struct {
+	s *Server
+}
+
+func (q *queryResolver) Me(ctx context.Context) (*userResolver, error) {
+	req, err := requestFrom(ctx)
+	if err != nil {
+		return nil, err
+	}
+	u, found, err := req.loaders.users.Load(ctx, req.viewer.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, user.ErrUserNotFound
+	}
+	return &userResolver{u}, nil
+}
+
+func (q *queryResolver) User(ctx context.Context, args struct{ ID graphqlgo.ID }) (*userResolver, error) {
+	req, err := requestFrom(ctx)
+	if err != nil {
+		return nil, err
+	}
+	id, err := parseID(args.ID)
+	if err != nil {
+		return nil, err
+	}
+	return loadUser(ctx, req, id)
+}
+
+func (q *queryResolver) Users(ctx context.Context, args pageArgs) ([]*userResolver, error) {
+	req, err := requestFrom(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := authorize(req.viewer.Admin()); err != nil {
+		return nil, err
+	}
+
+	first, offset := args.limits()
+	users, err := q.s.users.List(ctx, user.ListFilter{Limit: first, Offset: offset})
+	if err != nil {
+		return nil, err
+	}
+	resolvers := make([]*userResolver, len(users))
+	for i, u := range users {
+		resolvers[i] = &userResolver{u}
+	}
+	return resolvers, nil
+}
+
+func (q *queryResolver) Workflow(ctx context.Context, args struct{ ID graphqlgo.ID }) (*workflowResolver, error) {
+	req, err := requestFrom(ctx)
+	if err != nil {
+		return nil, err
+	}
+	id, err := parseID(args.ID)
+	if err != nil {
+		return nil, err
+	}
+	return loadWorkflow(ctx, req, id)
+}
+
+func (q *queryResolver) Workflows(ctx context.Context, args pageArgs) ([]*workflowResolver, error) {
+	req, err := requestFrom(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var owner *uuid.UUID
+	if !req.viewer.Admin() {
+		owner = &req.viewer.UserID
+	}
+	first, offset := args.limits()
+	workflows, err := q.s.workflows.List(ctx, owner, first, offset)
+	if err != nil {
+		return nil, err
+	}
+	resolvers := make([]*workflowResolver, len(workflows))
+	for i, w := range workflows {
+		resolvers[i] = &workflowResolver{w}
+	}
+	return resolvers, nil
+}
+
+func (q *queryResolver) Execution(ctx context.Context, args struct{ ID graphqlgo.ID }) (*executionResolver, error) {
+	req, err := requestFrom(ctx)
+	if err != nil {
+		return nil, err
+	}
+	id, err := parseID(args.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	e, err := q.s.executions.FindByID(ctx, id)
+	if errors.Is(err, execution.ErrExecutionNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	// Executions are visible to whoever may see their workflow
+	if w, err := loadWorkflow(ctx, req, e.WorkflowID); err != nil || w == nil {
+		return nil, err
+	}
+	return &executionResolver{e}, nil
+}
+
+func (q *queryResolver) Executions(ctx context.Context, args struct {
+	WorkflowID *graphqlgo.ID
+	Status     *string
+	First      int32
+	Offset     int32
+}) ([]*executionResolver, error) {
+	req, err := requestFrom(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := execution.ListFilter{}
+	filter.Limit, filter.Offset = pageArgs{First: args.First, Offset: args.Offset}.limits()
+	if !req.viewer.Admin() {
+		filter.UserID = &req.viewer.UserID
+	}
+	if args.WorkflowID != nil {
+		id, err := parseID(*args.WorkflowID)
+		if err != nil {
+			return nil, err
+		}
+		filter.WorkflowID = &id
+	}
+	if args.Status != nil {
+		filter.Status = execution.ExecutionStatus(*args.Status)
+	}
+
+	executions, err := q.s.executions.List(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	resolvers := make([]*executionResolver, len(executions))
+	for i, e := range executions {
+		resolvers[i] = &executionResolver{e}
+	}
+	return resolvers, nil
+}
+
+func (q *queryResolver) Credentials(ctx context.Context) ([]*credentialResolver, error) {
+	req, err := requestFrom(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := authorize(req.viewer.Can("credential:manage")); err != nil {
+		return nil, err
+	}
+
+	var owner *uuid.UUID
+	if !req.viewer.Admin() {
+		owner = &req.viewer.UserID
+	}
+	credentials, err := q.s.credentials.List(ctx, owner)
+	if err != nil {
+		return nil, err
+	}
+	resolvers := make([]*credentialResolver, len(credentials))
+	for i, c := range credentials {
+		resolvers[i] = &credentialResolver{c}
+	}
+	return resolvers, nil
+}
+
+// loadUser returns the user with id, or nil when there is none
+func loadUser(ctx context.Context, req *request, id uuid.UUID) (*userResolver, error) {
+	u, found, err := req.loaders.users.Load(ctx, id)
+	if err != nil || !found {
+		return nil, err
+	}
+	return &userResolver{u}, nil
+}
+
+// loadWorkflow returns the workflow with id, or nil when there is none or
+// the viewer may not see it
+func loadWorkflow(ctx context.Context, req *request, id uuid.UUID) (*workflowResolver, error) {
+	w, found, err := req.loaders.workflows.Load(ctx, id)
+	if err != nil || !found || !req.viewer.Owns(w.UserID) {
+		return nil, err
+	}
+	return &workflowResolver{w}, nil
+}
+
+// userResolver resolves User. Profile details are restricted to the user
+// themselves and admins.
+type userResolver struct {
+	u *user.User
+}
+
+func (r *userResolver) ID() graphqlgo.ID { return graphqlgo.ID(r.u.ID.String()) }
+func (r *userResolver) Name() string     { return r.u.Name }
+func (r *userResolver) IsActive() bool   { return r.u.IsActive }
+func (r *userResolver) CreatedAt() graphqlgo.Time {
+	return graphqlgo.Time{Time: r.u.CreatedAt}
+}
+
+func (r *userResolver) private(ctx context.Context) error {
+	req, err := requestFrom(ctx)
+	if err != nil {
+		return err
+	}
+	return authorize(req.viewer.Owns(r.u.ID))
+}
+
+func (r *userResolver) Email(ctx context.Context) (*string, error) {
+	if err := r.private(ctx); err != nil {
+		return nil, err
+	}
+	return &r.u.Email, nil
+}
+
+func (r *userResolver) Role(ctx context.Context) (*string, error) {
+	if err := r.private(ctx); err != nil {
+		return nil, err
+	}
+	role := string(r.u.Role)
+	return &role, nil
+}
+
+func (r *userResolver) LastLoginAt(ctx context.Context) (*graphqlgo.Time, error) {
+	if err := r.private(ctx); err != nil {
+		return nil, err
+	}
+	if r.u.LastLoginAt == nil {
+		return nil, nil
+	}
+	return &graphqlgo.Time{Time: *r.u.LastLoginAt}, nil
+}
+
+func (r *userResolver) LastSeenAt(ctx context.Context) (*graphqlgo.Time, error) {
+	if err := r.private(ctx); err != nil {
+		return nil, err
+	}
+	if r.u.LastSeenAt == nil {
+		return nil, nil
+	}
+	return &graphqlgo.Time{Time: *r.u.LastSeenAt}, nil
+}
+
+// workflowResolver resolves Workflow
+type workflowResolver struct {
+	w *workflow.Workflow
+}
+
+func (r *workflowResolver) ID() graphqlgo.ID    { return graphqlgo.ID(r.w.ID.String()) }
+func (r *workflowResolver) Name() string        { return r.w.Name }
+func (r *workflowResolver) Description() string { return r.w.Description }
+func (r *workflowResolver) IsActive() bool      { return r.w.IsActive }
+func (r *workflowResolver) Version() int32      { return int32(r.w.Version) }
+func (r *workflowResolver) CreatedAt() graphqlgo.Time {
+	return graphqlgo.Time{Time: r.w.CreatedAt}
+}
+func (r *workflowResolver) UpdatedAt() graphqlgo.Time {
+	return graphqlgo.Time{Time: r.w.UpdatedAt}
+}
+
+func (r *workflowResolver) Tags() []string {
+	if r.w.Tags == nil {
+		return []string{}
+	}
+	return r.w.Tags
+}
+
+func (r *workflowResolver) PublishedVersion() *int32 {
+	if r.w.PublishedVersion == nil {
+		return nil
+	}
+	v := int32(*r.w.PublishedVersion)
+	return &v
+}
+
+func (r *workflowResolver) Owner(ctx context.Context) (*userResolver, error) {
+	req, err := requestFrom(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return loadUser(ctx, req, r.w.UserID)
+}
+
+// restricted returns value when the viewer's role grants permission
+func restricted(ctx context.Context, permission string, value interface{}) (*JSON, error) {
+	req, err := requestFrom(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := authorize(req.viewer.Can(permission)); err != nil {
+		return nil, err
+	}
+	return &JSON{Value: value}, nil
+}
+
+func (r *workflowResolver) Nodes(ctx context.Context) (*JSON, error) {
+	return restricted(ctx, "workflow:read", r.w.Nodes)
+}
+
+func (r *workflowResolver) Connections(ctx context.Context) (*JSON, error) {
+	return restricted(ctx, "workflow:read", r.w.Connections)
+}
+
+func (r *workflowResolver) Variables(ctx context.Context) (*JSON, error) {
+	return restricted(ctx, "workflow:update", r.w.Variables)
+}
+
+func (r *workflowResolver) Executions(ctx context.Context, args struct{ First int32 }) ([]*executionResolver, error) {
+	req, err := requestFrom(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	limit, _ := pageArgs{First: args.First}.limits()
+	executions, _, err := req.loaders.executions.Load(ctx, executionsKey{WorkflowID: r.w.ID, Limit: limit})
+	if err != nil {
+		return nil, err
+	}
+	resolvers := make([]*executionResolver, len(executions))
+	for i, e := range executions {
+		resolvers[i] = &executionResolver{e}
+	}
+	return resolvers, nil
+}
+
+// executionResolver resolves Execution
+type executionResolver struct {
+	e *execution.Execution
+}
+
+func (r *executionResolver) ID() graphqlgo.ID       { return graphqlgo.ID(r.e.ID.String()) }
+func (r *executionResolver) WorkflowVersion() int32 { return int32(r.e.WorkflowVersion) }
+func (r *executionResolver) Status() string         { return string(r.e.Status) }
+func (r *executionResolver) Mode() string           { return string(r.e.Mode) }
+func (r *executionResolver) ExecutionTimeMs() int32 { return int32(r.e.ExecutionTimeMs) }
+func (r *executionResolver) DryRun() bool           { return r.e.DryRun }
+func (r *executionResolver) StartedAt() graphqlgo.Time {
+	return graphqlgo.Time{Time: r.e.StartedAt}
+}
+
+func (r *executionResolver) FinishedAt() *graphqlgo.Time {
+	if r.e.FinishedAt == nil {
+		return nil
+	}
+	return &graphqlgo.Time{Time: *r.e.FinishedAt}
+}
+
+func (r *executionResolver) ErrorMessage() *string {
+	if r.e.ErrorMessage == "" {
+		return nil
+	}
+	return &r.e.ErrorMessage
+}
+
+func (r *executionResolver) ErrorNode() *string {
+	if r.e.ErrorNode == "" {
+		return nil
+	}
+	return &r.e.ErrorNode
+}
+
+func (r *executionResolver) Workflow(ctx context.Context) (*workflowResolver, error) {
+	req, err := requestFrom(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return loadWorkflow(ctx, req, r.e.WorkflowID)
+}
+
+func (r *executionResolver) InputData(ctx context.Context) (*JSON, error) {
+	return restricted(ctx, "workflow:read", r.e.InputData)
+}
+
+func (r *executionResolver) OutputData(ctx context.Context) (*JSON, error) {
+	return restricted(ctx, "workflow:read", r.e.OutputData)
+}
+
+// credentialResolver resolves Credential metadata
+type credentialResolver struct {
+	c *credential.Credential
+}
+
+func (r *credentialResolver) ID() graphqlgo.ID { return graphqlgo.ID(r.c.ID.String()) }
+func (r *credentialResolver) Name() string     { return r.c.Name }
+func (r *credentialResolver) Type() string     { return r.c.Type }
+func (r *credentialResolver) CreatedAt() graphqlgo.Time {
+	return graphqlgo.Time{Time: r.c.CreatedAt}
+}
+func (r *credentialResolver) UpdatedAt() graphqlgo.Time {
+	return graphqlgo.Time{Time: r.c.UpdatedAt}
+}
+
+func (r *credentialResolver) NodeTypes() []string {
+	if r.c.NodeTypes == nil {
+		return []string{}
+	}
+	return r.c.NodeTypes
+}
+
+func (r *credentialResolver) Owner(ctx context.Context) (*userResolver, error) {
+	req, err := requestFrom(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return loadUser(ctx, req, r.c.UserID)
+}
@@ -0,0 +1,139 @@
+package graphql
+
+import (
+	"context"
+	_ "embed"
+
+	"github.com/google/uuid"
+	graphqlgo "github.com/graph-gophers/graphql-go"
+	"github.com/jaydeep/go-n8n/internal/domain/credential"
+	"github.com/jaydeep/go-n8n/internal/domain/execution"
+	"github.com/jaydeep/go-n8n/internal/domain/user"
+	"github.com/jaydeep/go-n8n/internal/domain/workflow"
+	"github.com/jaydeep/go-n8n/pkg/logger"
+)
+
+const (
+	// maxDepth bounds the nesting of queries
+	maxDepth = 8
+	// maxParallelism bounds the resolvers of one query running at once;
+	// loaders batch the lookups of resolvers running together
+	maxParallelism = 50
+)
+
+//go:embed schema.graphql
+var schemaSDL string
+
+// Request is a GraphQL request as sent over HTTP
+type Request struct {
+	Query         string                 `json:"query" form:"query"`
+	OperationName string                 `json:"operationName" form:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+// Server executes queries against the management plane schema
+type Server struct {
+	schema      *graphqlgo.Schema
+	workflows   workflow.Repository
+	executions  execution.Repository
+	users       user.Repository
+	credentials credential.Repository
+	log         *logger.Logger
+}
+
+// NewServer parses the schema and binds it to the repositories
+func NewServer(workflows workflow.Repository, executions execution.Repository, users user.Repository, credentials credential.Repository, log *logger.Logger) (*Server, error) {
+	s := &Server{
+		workflows:   workflows,
+		executions:  executions,
+		users:       users,
+		credentials: credentials,
+		log:         log,
+	}
+
+	schema, err := graphqlgo.ParseSchema(schemaSDL, &queryResolver{s},
+		graphqlgo.MaxDepth(maxDepth),
+		graphqlgo.MaxParallelism(maxParallelism),
+	)
+	if err != nil {
+		return nil, err
+	}
+	s.schema = schema
+	return s, nil
+}
+
+// Execute runs a query for viewer
+func (s *Server) Execute(ctx context.Context, viewer Viewer, req Request) *graphqlgo.Response {
+	ctx = context.WithValue(ctx, requestKey{}, &request{
+		viewer:  viewer,
+		loaders: s.newLoaders(),
+	})
+	resp := s.schema.Exec(ctx, req.Query, req.OperationName, req.Variables)
+	if len(resp.Errors) > 0 {
+		s.log.Debugw("GraphQL query returned errors", "operation", req.OperationName, "user_id", viewer.UserID, "errors", len(resp.Errors))
+	}
+	return resp
+}
+
+// executionsKey selects the newest executions of a workflow
+type executionsKey struct {
+	WorkflowID uuid.UUID
+	Limit      int
+}
+
+// loaders batch the lookups of one request
+type loaders struct {
+	users      *Loader[uuid.UUID, *user.User]
+	workflows  *Loader[uuid.UUID, *workflow.Workflow]
+	executions *Loader[executionsKey, []*execution.Execution]
+}
+
+func (s *Server) newLoaders() *loaders {
+	return &loaders{
+		users: NewLoader(func(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID]*user.User, error) {
+			users, err := s.users.FindByIDs(ctx, ids)
+			if err != nil {
+				return nil, err
+			}
+			byID := make(map[uuid.UUID]*user.User, len(users))
+			for _, u := range users {
+				byID[u.ID] = u
+			}
+			return byID, nil
+		}),
+		workflows: NewLoader(func(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID]*workflow.Workflow, error) {
+			workflows, err := s.workflows.FindByIDs(ctx, ids)
+			if err != nil {
+				return nil, err
+			}
+			byID := make(map[uuid.UUID]*workflow.Workflow, len(workflows))
+			for _, w := range workflows {
+				byID[w.ID] = w
+			}
+			return byID, nil
+		}),
+		executions: NewLoader(func(ctx context.Context, keys []executionsKey) (map[executionsKey][]*execution.Execution, error) {
+			// One query per distinct limit; queries nearly always share one
+			byLimit := make(map[int][]uuid.UUID)
+			for _, k := range keys {
+				byLimit[k.Limit] = append(byLimit[k.Limit], k.WorkflowID)
+			}
+
+			result := make(map[executionsKey][]*execution.Execution, len(keys))
+			for _, k := range keys {
+				result[k] = []*execution.Execution{}
+			}
+			for limit, ids := range byLimit {
+				executions, err := s.executions.ListLatest(ctx, ids, limit)
+				if err != nil {
+					return nil, err
+				}
+				for _, e := range executions {
+					key := executionsKey{WorkflowID: e.WorkflowID, Limit: limit}
+					result[key] = append(result[key], e)
+				}
+			}
+			return result, nil
+		}),
+	}
+}
@@ -0,0 +1,62 @@
+package graphql
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/jaydeep/go-n8n/internal/domain/user"
+)
+
+var (
+	ErrUnauthenticated = errors.New("authentication required")
+	ErrForbidden       = errors.New("forbidden")
+)
+
+// Viewer is the authenticated user a query runs for
+type Viewer struct {
+	UserID uuid.UUID
+	Role   user.Role
+}
+
+// Admin reports whether the viewer has an admin or owner role
+func (v Viewer) Admin() bool {
+	return v.Role == user.RoleAdmin || v.Role == user.RoleOwner
+}
+
+// Can reports whether the viewer's role grants permission
+func (v Viewer) Can(permission string) bool {
+	u := user.User{Role: v.Role}
+	return u.HasPermission(permission)
+}
+
+// Owns reports whether the viewer may access a resource owned by ownerID
+func (v Viewer) Owns(ownerID uuid.UUID) bool {
+	return v.Admin() || v.UserID == ownerID
+}
+
+type requestKey struct{}
+
+// request holds the per-request state of a query
+type request struct {
+	viewer  Viewer
+	loaders *loaders
+}
+
+// requestFrom returns the state attached by Server.Execute
+func requestFrom(ctx context.Context) (*request, error) {
+	req, ok := ctx.Value(requestKey{}).(*request)
+	if !ok || req.viewer.UserID == uuid.Nil {
+		return nil, ErrUnauthenticated
+	}
+	return req, nil
+}
+
+// authorize returns ErrForbidden unless allowed; it is used by restricted
+// fields
+func authorize(allowed bool) error {
+	if !allowed {
+		return ErrForbidden
+	}
+	return nil
+}
@@ -0,0 +1,17 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// TrackActivity notes that the authenticated user was seen. It must run
+// after Auth.
+func TrackActivity(seen func(userID uuid.UUID)) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if userID, err := uuid.Parse(c.GetString("UserID")); err == nil {
+			seen(userID)
+		}
+		c.Next()
+	}
+}
@@ -1,19 +1,22 @@
 package middleware
 
 import (
+	"errors"
 	"net/http"
 	"strings"
 
 	"github.com/gin-gonic/gin"
-	"github.com/golang-jwt/jwt/v5"
-	"github.com/jaydeep/go-n8n/configs"
 )
 
 // Auth returns a gin middleware for JWT authentication
-func Auth(cfg configs.JWTConfig) gin.HandlerFunc {
+func Auth(verifier *TokenVerifier) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		// Extract token from Authorization header
+		// Extract token from Authorization header. Browsers cannot set
+		// headers on WebSocket handshakes, so those may pass ?token= instead.
 		authHeader := c.GetHeader("Authorization")
+		if authHeader == "" && c.Query("token") != "" && strings.EqualFold(c.GetHeader("Upgrade"), "websocket") {
+			authHeader = "Bearer " + c.Query("token")
+		}
 		if authHeader == "" {
 			c.JSON(http.StatusUnauthorized, gin.H{"error": "authorization header required"})
 			c.Abort()
@@ -28,35 +31,27 @@ func Auth(cfg configs.JWTConfig) gin.HandlerFunc {
 			return
 		}
 
-		tokenString := parts[1]
-
-		// Parse and validate token
-		token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-			// Check signing method
-			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-				return nil, jwt.ErrSignatureInvalid
-			}
-			return []byte(cfg.Secret), nil
-		})
-
-		if err != nil || !token.Valid {
+		identity, err := verifier.Verify(c.Request.Context(), parts[1])
+		if errors.Is(err, ErrNoRole) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "no role granted to user"})
+			c.Abort()
+			return
+		}
+		if err != nil {
 			c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid or expired token"})
 			c.Abort()
 			return
 		}
 
-		// Extract claims
-		if claims, ok := token.Claims.(jwt.MapClaims); ok {
-			// Set user context
-			if userID, ok := claims["user_id"].(string); ok {
-				c.Set("UserID", userID)
-			}
-			if email, ok := claims["email"].(string); ok {
-				c.Set("Email", email)
-			}
-			if role, ok := claims["role"].(string); ok {
-				c.Set("Role", role)
-			}
+		// Set user context
+		if identity.UserID != "" {
+			c.Set("UserID", identity.UserID)
+		}
+		if identity.Email != "" {
+			c.Set("Email", identity.Email)
+		}
+		if identity.Role != "" {
+			c.Set("Role", identity.Role)
 		}
 
 		c.Next()
@@ -0,0 +1,27 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/jaydeep/go-n8n/pkg/correlation"
+)
+
+// Correlation takes the correlation ID from the header, or generates one
+// when it is missing or invalid, and adds it to the request context, so
+// executions started by the request carry it, and to the response
+func Correlation(header string) gin.HandlerFunc {
+	if header == "" {
+		header = correlation.DefaultHeader
+	}
+	return func(c *gin.Context) {
+		id := c.GetHeader(header)
+		if !correlation.Valid(id) {
+			id = correlation.New()
+		}
+
+		c.Set("CorrelationID", id)
+		c.Request = c.Request.WithContext(correlation.WithID(c.Request.Context(), id))
+		c.Writer.Header().Set(header, id)
+
+		c.Next()
+	}
+}
@@ -1,6 +1,9 @@
 package middleware
 
 import (
+	"fmt"
+	"strings"
+	"sync"
 	"time"
 	
 	"github.com/gin-contrib/cors"
@@ -8,8 +11,52 @@ import (
 	"github.com/jaydeep/go-n8n/configs"
 )
 
-// CORS returns a gin middleware for CORS
-func CORS(cfg configs.CORSConfig) gin.HandlerFunc {
+// CORS returns a gin middleware for CORS. Requests whose path starts with
+// one of publicPrefixes are left to PublicCORS.
+func CORS(cfg configs.CORSConfig, publicPrefixes ...string) gin.HandlerFunc {
+	handler := newCORS(cfg)
+	return func(c *gin.Context) {
+		for _, prefix := range publicPrefixes {
+			if strings.HasPrefix(c.Request.URL.Path, prefix) {
+				c.Next()
+				return
+			}
+		}
+		handler(c)
+	}
+}
+
+// PublicCORS applies the CORS policy policy returns for each request, for
+// routes called from browsers on other sites such as webhooks, endpoints
+// and chats. A policy without origins allows no other site. Handlers are
+// built once per distinct policy.
+func PublicCORS(policy func(c *gin.Context) configs.CORSConfig) gin.HandlerFunc {
+	var mu sync.Mutex
+	handlers := map[string]gin.HandlerFunc{}
+	return func(c *gin.Context) {
+		if c.GetHeader("Origin") == "" {
+			c.Next()
+			return
+		}
+		cfg := policy(c)
+		if len(cfg.AllowedOrigins) == 0 {
+			c.Next()
+			return
+		}
+
+		key := fmt.Sprintf("%#v", cfg)
+		mu.Lock()
+		handler, ok := handlers[key]
+		if !ok {
+			handler = newCORS(cfg)
+			handlers[key] = handler
+		}
+		mu.Unlock()
+		handler(c)
+	}
+}
+
+func newCORS(cfg configs.CORSConfig) gin.HandlerFunc {
 	return cors.New(cors.Config{
 		AllowOrigins:     cfg.AllowedOrigins,
 		AllowMethods:     cfg.AllowedMethods,
@@ -0,0 +1,53 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// DomainTeamKey is the context key holding the team owning the custom
+// domain a request came in on
+const DomainTeamKey = "DomainTeam"
+
+// DomainResolver maps the verified custom domains of teams to the teams
+type DomainResolver interface {
+	DomainTeam(ctx context.Context, host string) (uuid.UUID, bool)
+}
+
+// CustomDomains serves requests for verified custom domains on the routes
+// in publicRoutes only, answering 404 for every other route so the API is
+// not reachable under team hostnames. The team owning the domain is set
+// under DomainTeamKey. Requests for other hosts pass unchanged.
+func CustomDomains(resolver DomainResolver, publicRoutes ...string) gin.HandlerFunc {
+	public := make(map[string]bool, len(publicRoutes))
+	for _, route := range publicRoutes {
+		public[route] = true
+	}
+	return func(c *gin.Context) {
+		teamID, ok := resolver.DomainTeam(c.Request.Context(), c.Request.Host)
+		if !ok {
+			c.Next()
+			return
+		}
+		if !public[c.FullPath()] {
+			c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": "not found"})
+			return
+		}
+		c.Set(DomainTeamKey, teamID)
+		c.Next()
+	}
+}
+
+// DomainTeam returns the team owning the custom domain of the request, or
+// nil when it came in on another host
+func DomainTeam(c *gin.Context) *uuid.UUID {
+	value, ok := c.Get(DomainTeamKey)
+	if !ok {
+		return nil
+	}
+	teamID := value.(uuid.UUID)
+	return &teamID
+}
@@ -0,0 +1,31 @@
+package middleware
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jaydeep/go-n8n/configs"
+)
+
+// Deadlines returns a gin middleware replacing the server read and write
+// timeouts for a class of routes, such as WebSocket and event streams that
+// outlive any JSON API timeout. A zero timeout removes the deadline.
+func Deadlines(cfg configs.RouteTimeoutConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		rc := http.NewResponseController(c.Writer)
+		// Unsupported writers keep the server timeouts
+		_ = rc.SetReadDeadline(deadline(cfg.ReadTimeout))
+		_ = rc.SetWriteDeadline(deadline(cfg.WriteTimeout))
+
+		c.Next()
+	}
+}
+
+// deadline returns the deadline timeout from now, or no deadline for zero
+func deadline(timeout time.Duration) time.Time {
+	if timeout <= 0 {
+		return time.Time{}
+	}
+	return time.Now().Add(timeout)
+}
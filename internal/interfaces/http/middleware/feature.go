@@ -0,0 +1,29 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// FeatureChecker reports whether a feature flag is enabled for a user
+type FeatureChecker interface {
+	Enabled(ctx context.Context, key string, userID uuid.UUID) bool
+}
+
+// RequireFeature hides routes from the users a feature flag is not enabled
+// for, responding as if they did not exist. It runs after Auth so flags
+// targeting users and teams apply.
+func RequireFeature(flags FeatureChecker, key string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, _ := uuid.Parse(c.GetString("UserID"))
+		if !flags.Enabled(c.Request.Context(), key, userID) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "not found"})
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
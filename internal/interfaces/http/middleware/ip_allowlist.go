@@ -0,0 +1,20 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jaydeep/go-n8n/pkg/netpolicy"
+)
+
+// IPAllowlist rejects requests whose client IP the allowlist does not
+// allow. The client IP honors the router's trusted proxies.
+func IPAllowlist(allowlist *netpolicy.Allowlist) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !allowlist.Allows(c.ClientIP()) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "access denied from this network"})
+			return
+		}
+		c.Next()
+	}
+}
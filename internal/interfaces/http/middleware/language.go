@@ -0,0 +1,44 @@
+package middleware
+
+import (
+	"context"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jaydeep/go-n8n/pkg/i18n"
+)
+
+// Language picks the response language from the lang query parameter or
+// the Accept-Language header and stores it with the bundle in the context
+func Language(bundle *i18n.Bundle) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		lang := c.Query("lang")
+		if lang == "" {
+			lang = bundle.Match(c.GetHeader("Accept-Language"))
+		}
+		setLanguage(c, i18n.Normalize(lang))
+		c.Set("I18n", bundle)
+		c.Next()
+	}
+}
+
+// UserLanguage overrides the request language with the authenticated
+// user's settings unless the lang query parameter was given. It must run
+// after Auth.
+func UserLanguage(resolve func(ctx context.Context, userID uuid.UUID) string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Query("lang") == "" {
+			if userID, err := uuid.Parse(c.GetString("UserID")); err == nil {
+				if lang := resolve(c.Request.Context(), userID); lang != "" {
+					setLanguage(c, i18n.Normalize(lang))
+				}
+			}
+		}
+		c.Next()
+	}
+}
+
+func setLanguage(c *gin.Context, lang string) {
+	c.Set("Language", lang)
+	c.Request = c.Request.WithContext(i18n.WithLanguage(c.Request.Context(), lang))
+}
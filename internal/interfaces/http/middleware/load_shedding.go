@@ -0,0 +1,31 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// LoadReporter reports whether the instance is overloaded
+type LoadReporter interface {
+	Overloaded() (retryAfter time.Duration, overloaded bool)
+}
+
+// LoadShedding rejects requests with 503 while the instance is overloaded.
+// It guards the routes that start executions; reads are served regardless.
+func LoadShedding(load LoadReporter) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		retryAfter, overloaded := load.Overloaded()
+		if !overloaded {
+			c.Next()
+			return
+		}
+
+		c.Header("Retry-After", strconv.Itoa(int(retryAfter.Round(time.Second)/time.Second)))
+		c.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{
+			"error": "server overloaded, retry later",
+		})
+	}
+}
@@ -28,12 +28,13 @@ func Logger(log *logger.Logger) gin.HandlerFunc {
 		}
 
 		log.WithFields(map[string]interface{}{
-			"status":     statusCode,
-			"latency":    latency,
-			"client_ip":  clientIP,
-			"method":     method,
-			"path":       path,
-			"request_id": c.GetString("RequestID"),
+			"status":         statusCode,
+			"latency":        latency,
+			"client_ip":      clientIP,
+			"method":         method,
+			"path":           path,
+			"request_id":     c.GetString("RequestID"),
+			"correlation_id": c.GetString("CorrelationID"),
 		}).Info("Request processed")
 
 		// Log errors if any
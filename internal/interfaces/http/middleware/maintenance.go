@@ -0,0 +1,55 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MaintenanceReporter reports whether the instance is in maintenance mode
+type MaintenanceReporter interface {
+	InMaintenance(ctx context.Context) (retryAfter time.Duration, message string, active bool)
+}
+
+// ReadOnly rejects requests that may change data with 503 while the
+// instance is in maintenance mode; GET, HEAD and OPTIONS requests are
+// served regardless.
+func ReadOnly(maintenance MaintenanceReporter) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		switch c.Request.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+			c.Next()
+			return
+		}
+		rejectDuringMaintenance(c, maintenance)
+	}
+}
+
+// PauseIntake rejects every request with 503 while the instance is in
+// maintenance mode. It guards the routes through which outside callers
+// start executions, such as webhooks.
+func PauseIntake(maintenance MaintenanceReporter) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		rejectDuringMaintenance(c, maintenance)
+	}
+}
+
+func rejectDuringMaintenance(c *gin.Context, maintenance MaintenanceReporter) {
+	retryAfter, message, active := maintenance.InMaintenance(c.Request.Context())
+	if !active {
+		c.Next()
+		return
+	}
+
+	if retryAfter > 0 {
+		c.Header("Retry-After", strconv.Itoa(int(retryAfter.Round(time.Second)/time.Second)))
+	}
+	body := gin.H{"error": "service under maintenance, retry later"}
+	if message != "" {
+		body["message"] = message
+	}
+	c.AbortWithStatusJSON(http.StatusServiceUnavailable, body)
+}
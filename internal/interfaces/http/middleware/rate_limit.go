@@ -2,6 +2,7 @@ package middleware
 
 import (
 	"net/http"
+	"sync"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -9,24 +10,74 @@ import (
 	"golang.org/x/time/rate"
 )
 
-// RateLimit returns a gin middleware for rate limiting
+// clientLimiter is the rate limiter of one client IP
+type clientLimiter struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+// clientLimiters holds a rate limiter per client IP. Limiters idle for
+// longer than idle are dropped; idle covers a full refill, so dropping one
+// grants nothing it would not have had.
+type clientLimiters struct {
+	limit rate.Limit
+	burst int
+	idle  time.Duration
+
+	mu        sync.Mutex
+	clients   map[string]*clientLimiter
+	lastSweep time.Time
+}
+
+func (l *clientLimiters) allow(ip string) bool {
+	now := time.Now()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if now.Sub(l.lastSweep) > l.idle {
+		for key, client := range l.clients {
+			if now.Sub(client.lastSeen) > l.idle {
+				delete(l.clients, key)
+			}
+		}
+		l.lastSweep = now
+	}
+
+	client, ok := l.clients[ip]
+	if !ok {
+		client = &clientLimiter{limiter: rate.NewLimiter(l.limit, l.burst)}
+		l.clients[ip] = client
+	}
+	client.lastSeen = now
+	return client.limiter.AllowN(now, 1)
+}
+
+// RateLimit returns a gin middleware limiting each client IP to
+// cfg.Requests per cfg.Duration. The client IP honors the router's trusted
+// proxies, so clients behind a load balancer are limited separately.
 func RateLimit(cfg configs.RateLimitConfig) gin.HandlerFunc {
-	// Create a new rate limiter
-	limiter := rate.NewLimiter(
-		rate.Every(cfg.Duration/time.Duration(cfg.Requests)),
-		cfg.Burst,
-	)
+	interval := cfg.Duration / time.Duration(cfg.Requests)
+	limiters := &clientLimiters{
+		limit:   rate.Every(interval),
+		burst:   cfg.Burst,
+		idle:    interval * time.Duration(cfg.Burst),
+		clients: make(map[string]*clientLimiter),
+	}
+	if limiters.idle < time.Minute {
+		limiters.idle = time.Minute
+	}
 
 	return func(c *gin.Context) {
 		// Check if request is allowed
-		if !limiter.Allow() {
+		if !limiters.allow(c.ClientIP()) {
 			c.JSON(http.StatusTooManyRequests, gin.H{
 				"error": "too many requests",
 			})
 			c.Abort()
 			return
 		}
-		
+
 		c.Next()
 	}
 }
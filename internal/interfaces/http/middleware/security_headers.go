@@ -0,0 +1,78 @@
+package middleware
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jaydeep/go-n8n/configs"
+)
+
+// SecurityHeaders returns a gin middleware setting HSTS,
+// X-Content-Type-Options, X-Frame-Options, Referrer-Policy and the
+// Content-Security-Policy of the hosted UI on every response. HSTS is only
+// sent over HTTPS, as browsers ignore it otherwise.
+func SecurityHeaders(cfg configs.SecurityHeadersConfig) gin.HandlerFunc {
+	if !cfg.Enabled {
+		return func(c *gin.Context) { c.Next() }
+	}
+	hsts := hstsValue(cfg)
+
+	return func(c *gin.Context) {
+		header := c.Writer.Header()
+		if hsts != "" && isHTTPS(c) {
+			header.Set("Strict-Transport-Security", hsts)
+		}
+		header.Set("X-Content-Type-Options", "nosniff")
+		setHeader(c, "X-Frame-Options", cfg.FrameOptions)
+		setHeader(c, "Referrer-Policy", cfg.ReferrerPolicy)
+		setHeader(c, "Content-Security-Policy", cfg.ContentSecurityPolicy)
+
+		c.Next()
+	}
+}
+
+// WebhookSecurityHeaders returns a gin middleware overriding the framing
+// and content security policy for routes serving workflow-built responses,
+// such as webhooks and form pages. It must run after SecurityHeaders.
+func WebhookSecurityHeaders(cfg configs.SecurityHeadersConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if cfg.Enabled {
+			setHeader(c, "X-Frame-Options", cfg.WebhookFrameOptions)
+			setHeader(c, "Content-Security-Policy", cfg.WebhookContentSecurityPolicy)
+		}
+		c.Next()
+	}
+}
+
+// setHeader sets a response header, removing it when value is empty
+func setHeader(c *gin.Context, key, value string) {
+	if value == "" {
+		c.Writer.Header().Del(key)
+		return
+	}
+	c.Writer.Header().Set(key, value)
+}
+
+// hstsValue builds the Strict-Transport-Security value, empty when HSTS is
+// disabled
+func hstsValue(cfg configs.SecurityHeadersConfig) string {
+	if cfg.HSTSMaxAge <= 0 {
+		return ""
+	}
+	value := "max-age=" + strconv.FormatInt(int64(cfg.HSTSMaxAge.Seconds()), 10)
+	if cfg.HSTSIncludeSubdomains {
+		value += "; includeSubDomains"
+	}
+	if cfg.HSTSPreload {
+		value += "; preload"
+	}
+	return value
+}
+
+// isHTTPS reports whether the client reached the server over HTTPS, either
+// directly or through a proxy terminating TLS. A spoofed X-Forwarded-Proto
+// is harmless here: browsers ignore HSTS received over plain HTTP.
+func isHTTPS(c *gin.Context) bool {
+	return c.Request.TLS != nil || strings.EqualFold(c.GetHeader("X-Forwarded-Proto"), "https")
+}
@@ -0,0 +1,243 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/jaydeep/go-n8n/configs"
+	"github.com/jaydeep/go-n8n/pkg/jwks"
+)
+
+// Authentication modes
+const (
+	AuthModeLocal    = "local"
+	AuthModeExternal = "external"
+	AuthModeHybrid   = "hybrid"
+)
+
+// ErrNoRole is returned for valid tokens of users granted no local role
+var ErrNoRole = errors.New("no role granted")
+
+// roleRanks orders the local roles, so the highest granted wins when an
+// identity provider grants several
+var roleRanks = map[string]int{
+	"user":     1,
+	"approver": 2,
+	"admin":    3,
+	"owner":    4,
+}
+
+// Identity is the user a verified token was issued to
+type Identity struct {
+	UserID string
+	Email  string
+	Role   string
+}
+
+// TokenVerifier verifies bearer tokens signed with the JWT secret, issued
+// by an external identity provider, or both, depending on the mode
+type TokenVerifier struct {
+	mode   string
+	secret []byte
+
+	external configs.ExternalAuthConfig
+	keys     *jwks.KeySet
+	parser   *jwt.Parser
+	roles    map[string]string
+}
+
+// NewTokenVerifier creates a token verifier. It fails when the mode is
+// unknown or the external identity provider is not fully set up.
+func NewTokenVerifier(cfg configs.JWTConfig) (*TokenVerifier, error) {
+	v := &TokenVerifier{mode: cfg.Mode, secret: []byte(cfg.Secret)}
+	switch v.mode {
+	case "":
+		v.mode = AuthModeLocal
+	case AuthModeLocal, AuthModeExternal, AuthModeHybrid:
+	default:
+		return nil, fmt.Errorf("unknown mode %q", cfg.Mode)
+	}
+	if v.mode == AuthModeLocal {
+		return v, nil
+	}
+
+	ext := cfg.External
+	if ext.JWKSURL == "" {
+		return nil, fmt.Errorf("external.jwks_url is required in %s mode", v.mode)
+	}
+	if len(ext.Algorithms) == 0 {
+		ext.Algorithms = []string{"RS256"}
+	}
+	for _, alg := range ext.Algorithms {
+		// Shared secrets are for local tokens only, as anyone able to
+		// fetch the key set could sign tokens with a public key
+		if strings.HasPrefix(alg, "HS") || jwt.GetSigningMethod(alg) == nil || alg == "none" {
+			return nil, fmt.Errorf("external.algorithms: unsupported algorithm %q", alg)
+		}
+	}
+	if ext.UserIDClaim == "" {
+		ext.UserIDClaim = "sub"
+	}
+	if ext.EmailClaim == "" {
+		ext.EmailClaim = "email"
+	}
+	if ext.RoleClaim == "" {
+		ext.RoleClaim = "role"
+	}
+	if ext.DefaultRole != "" && roleRanks[ext.DefaultRole] == 0 {
+		return nil, fmt.Errorf("external.default_role: unknown role %q", ext.DefaultRole)
+	}
+	// Configuration keys are case insensitive, so provider roles are
+	// matched regardless of case
+	v.roles = make(map[string]string, len(ext.RoleMapping))
+	for from, to := range ext.RoleMapping {
+		if roleRanks[to] == 0 {
+			return nil, fmt.Errorf("external.role_mapping: unknown role %q for %q", to, from)
+		}
+		v.roles[strings.ToLower(from)] = to
+	}
+
+	opts := []jwt.ParserOption{
+		jwt.WithValidMethods(ext.Algorithms),
+		jwt.WithLeeway(ext.Leeway),
+		jwt.WithExpirationRequired(),
+	}
+	if ext.Issuer != "" {
+		opts = append(opts, jwt.WithIssuer(ext.Issuer))
+	}
+	if ext.Audience != "" {
+		opts = append(opts, jwt.WithAudience(ext.Audience))
+	}
+	v.external = ext
+	v.parser = jwt.NewParser(opts...)
+	v.keys = jwks.New(ext.JWKSURL, ext.RefreshInterval, nil)
+	return v, nil
+}
+
+// Verify validates a token and returns the identity it was issued to. In
+// hybrid mode, tokens signed with HMAC are verified as local tokens and the
+// others as tokens of the identity provider.
+func (v *TokenVerifier) Verify(ctx context.Context, tokenString string) (*Identity, error) {
+	switch v.mode {
+	case AuthModeLocal:
+		return v.verifyLocal(tokenString)
+	case AuthModeExternal:
+		return v.verifyExternal(ctx, tokenString)
+	}
+	token, _, err := jwt.NewParser().ParseUnverified(tokenString, jwt.MapClaims{})
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := token.Method.(*jwt.SigningMethodHMAC); ok {
+		return v.verifyLocal(tokenString)
+	}
+	return v.verifyExternal(ctx, tokenString)
+}
+
+func (v *TokenVerifier) verifyLocal(tokenString string) (*Identity, error) {
+	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
+		// Check signing method
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, jwt.ErrSignatureInvalid
+		}
+		return v.secret, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !token.Valid {
+		return nil, jwt.ErrTokenSignatureInvalid
+	}
+
+	identity := &Identity{}
+	if claims, ok := token.Claims.(jwt.MapClaims); ok {
+		identity.UserID, _ = claims["user_id"].(string)
+		identity.Email, _ = claims["email"].(string)
+		identity.Role, _ = claims["role"].(string)
+	}
+	return identity, nil
+}
+
+func (v *TokenVerifier) verifyExternal(ctx context.Context, tokenString string) (*Identity, error) {
+	claims := jwt.MapClaims{}
+	token, err := v.parser.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		return v.keys.Key(ctx, kid)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !token.Valid {
+		return nil, jwt.ErrTokenSignatureInvalid
+	}
+
+	subject, _ := claimValue(claims, v.external.UserIDClaim).(string)
+	if subject == "" {
+		return nil, fmt.Errorf("%w: missing %s", jwt.ErrTokenInvalidClaims, v.external.UserIDClaim)
+	}
+	// Users get a stable UUID per issuer and subject, under which they are
+	// provisioned. Subjects that are UUIDs themselves are mapped too, so a
+	// provider cannot claim the ID of a local user or of another issuer's.
+	issuer, _ := claims.GetIssuer()
+	userID := uuid.NewSHA1(uuid.NameSpaceURL, []byte(issuer+"#"+subject))
+
+	role := v.mapRole(claimValue(claims, v.external.RoleClaim))
+	if role == "" {
+		return nil, ErrNoRole
+	}
+	email, _ := claimValue(claims, v.external.EmailClaim).(string)
+	return &Identity{UserID: userID.String(), Email: email, Role: role}, nil
+}
+
+// mapRole returns the highest local role granted by a role claim holding
+// a role or a list of them, or the default role when none is
+func (v *TokenVerifier) mapRole(value interface{}) string {
+	var granted []string
+	switch value := value.(type) {
+	case string:
+		granted = []string{value}
+	case []interface{}:
+		for _, item := range value {
+			if s, ok := item.(string); ok {
+				granted = append(granted, s)
+			}
+		}
+	}
+
+	role := ""
+	for _, g := range granted {
+		local := g
+		if len(v.roles) > 0 {
+			local = v.roles[strings.ToLower(g)]
+		}
+		if roleRanks[local] > roleRanks[role] {
+			role = local
+		}
+	}
+	if role == "" {
+		return v.external.DefaultRole
+	}
+	return role
+}
+
+// claimValue returns the claim at a dotted path such as realm_access.roles.
+// A claim named with dots itself, as some providers use URLs as names, is
+// preferred.
+func claimValue(claims jwt.MapClaims, path string) interface{} {
+	if value, ok := claims[path]; ok {
+		return value
+	}
+	var current interface{} = map[string]interface{}(claims)
+	for _, part := range strings.Split(path, ".") {
+		m, ok := current.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		current = m[part]
+	}
+	return current
+}
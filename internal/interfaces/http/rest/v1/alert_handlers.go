@@ -0,0 +1,44 @@
+package v1
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jaydeep/go-n8n/internal/application/alerting"
+	"github.com/jaydeep/go-n8n/internal/domain/alert"
+)
+
+// listAlerts returns the latest operational alerts, newest first
+func listAlerts(monitor *alerting.Monitor) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		status := alert.Status(c.Query("status"))
+		if status != "" && status != alert.StatusFiring && status != alert.StatusResolved {
+			c.JSON(http.StatusBadRequest, gin.H{"error": translate(c, "status must be firing or resolved")})
+			return
+		}
+		var limit int
+		if raw := c.Query("limit"); raw != "" {
+			n, err := strconv.Atoi(raw)
+			if err != nil || n <= 0 {
+				c.JSON(http.StatusBadRequest, gin.H{"error": translate(c, "limit must be a positive integer")})
+				return
+			}
+			limit = n
+		}
+
+		alerts, err := monitor.List(c.Request.Context(), status, limit)
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"data": alerts, "count": len(alerts)})
+	}
+}
+
+// testAlertChannels sends a test notification to every alert channel
+func testAlertChannels(monitor *alerting.Monitor) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"data": monitor.Test(c.Request.Context())})
+	}
+}
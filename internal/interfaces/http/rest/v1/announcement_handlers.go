@@ -0,0 +1,102 @@
+package v1
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jaydeep/go-n8n/internal/application/announcement"
+)
+
+// getAnnouncements returns the announcements currently shown
+func getAnnouncements(svc *announcement.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		announcements, err := svc.Active(c.Request.Context())
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"data": announcements})
+	}
+}
+
+// listAnnouncements lists every announcement including expired ones
+func listAnnouncements(svc *announcement.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		announcements, err := svc.List(c.Request.Context())
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"data": announcements, "count": len(announcements)})
+	}
+}
+
+// publishAnnouncement publishes a banner to every user
+func publishAnnouncement(svc *announcement.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		actor, ok := actorFrom(c)
+		if !ok {
+			return
+		}
+
+		var input announcement.Input
+		if err := c.ShouldBindJSON(&input); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		a, err := svc.Publish(c.Request.Context(), actor, input)
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+		c.JSON(http.StatusCreated, gin.H{"data": a})
+	}
+}
+
+// updateAnnouncement changes a published announcement
+func updateAnnouncement(svc *announcement.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		actor, ok := actorFrom(c)
+		if !ok {
+			return
+		}
+		id, ok := paramUUID(c, "id")
+		if !ok {
+			return
+		}
+
+		var input announcement.UpdateInput
+		if err := c.ShouldBindJSON(&input); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		a, err := svc.Update(c.Request.Context(), actor, id, input)
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"data": a})
+	}
+}
+
+// withdrawAnnouncement removes an announcement from every user's view
+func withdrawAnnouncement(svc *announcement.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		actor, ok := actorFrom(c)
+		if !ok {
+			return
+		}
+		id, ok := paramUUID(c, "id")
+		if !ok {
+			return
+		}
+
+		if err := svc.Withdraw(c.Request.Context(), actor, id); err != nil {
+			respondError(c, err)
+			return
+		}
+		c.Status(http.StatusNoContent)
+	}
+}
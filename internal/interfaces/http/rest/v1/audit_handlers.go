@@ -0,0 +1,121 @@
+package v1
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jaydeep/go-n8n/internal/application/audit"
+	domain "github.com/jaydeep/go-n8n/internal/domain/audit"
+)
+
+// listAuditLogs lists audit log entries newest first, filtered by user_id,
+// action, resource_type, resource_id and a from/to time range. JSON
+// responses are pages continued with next_cursor; with Accept:
+// application/x-ndjson every matching entry is streamed instead, starting
+// after cursor if given.
+func listAuditLogs(svc *audit.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !hasPermission(c, "audit:read") {
+			c.JSON(http.StatusForbidden, gin.H{"error": translate(c, "insufficient permissions")})
+			return
+		}
+		q, ok := parseListQuery(c)
+		if !ok {
+			return
+		}
+		userID, ok := queryUUID(c, "user_id")
+		if !ok {
+			return
+		}
+
+		filter := domain.ListFilter{
+			UserID:       userID,
+			Action:       c.Query("action"),
+			ResourceType: c.Query("resource_type"),
+			ResourceID:   c.Query("resource_id"),
+			From:         q.From,
+			To:           q.To,
+			After:        q.Cursor,
+			Limit:        q.Limit,
+		}
+
+		if wantsNDJSON(c) {
+			streamNDJSON(c, func(write func(row interface{}) error) error {
+				return svc.Export(c.Request.Context(), filter, func(entry *domain.AuditLog) error {
+					return write(entry)
+				})
+			})
+			return
+		}
+
+		logs, next, err := svc.List(c.Request.Context(), filter)
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+		respondPage(c, logs, next)
+	}
+}
+
+// getAuditLog returns an audit log entry
+func getAuditLog(svc *audit.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !hasPermission(c, "audit:read") {
+			c.JSON(http.StatusForbidden, gin.H{"error": translate(c, "insufficient permissions")})
+			return
+		}
+		id, ok := paramUUID(c, "id")
+		if !ok {
+			return
+		}
+
+		entry, err := svc.Get(c.Request.Context(), id)
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"data": entry})
+	}
+}
+
+// exportAuditBundle exports a verifiable bundle of the hash chained audit
+// log from from_sequence on, at most limit entries. Bundles are continued
+// from their next_sequence.
+func exportAuditBundle(svc *audit.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !hasPermission(c, "audit:read") {
+			c.JSON(http.StatusForbidden, gin.H{"error": translate(c, "insufficient permissions")})
+			return
+		}
+		var from int64 = 1
+		if raw := c.Query("from_sequence"); raw != "" {
+			n, err := strconv.ParseInt(raw, 10, 64)
+			if err != nil || n <= 0 {
+				c.JSON(http.StatusBadRequest, gin.H{"error": translate(c, "from_sequence must be a positive integer")})
+				return
+			}
+			from = n
+		}
+		var limit int
+		if raw := c.Query("limit"); raw != "" {
+			n, err := strconv.Atoi(raw)
+			if err != nil || n <= 0 {
+				c.JSON(http.StatusBadRequest, gin.H{"error": translate(c, "limit must be a positive integer")})
+				return
+			}
+			limit = n
+		}
+		actor, ok := actorFrom(c)
+		if !ok {
+			return
+		}
+
+		bundle, err := svc.ExportBundle(c.Request.Context(), actor, from, limit)
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"data": bundle})
+	}
+}
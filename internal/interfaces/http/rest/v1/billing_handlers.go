@@ -0,0 +1,49 @@
+package v1
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jaydeep/go-n8n/internal/application/billing"
+	domain "github.com/jaydeep/go-n8n/internal/domain/billing"
+)
+
+// getBillingUsage reports the CPU time, items processed, HTTP calls and
+// LLM tokens consumed by executions, grouped by workflow or team. The
+// range (from, to as RFC 3339) defaults to the current month; team_id and
+// workflow_id narrow the report.
+func getBillingUsage(svc *billing.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, ok := currentUserID(c)
+		if !ok {
+			return
+		}
+		q := billing.Query{GroupBy: domain.GroupBy(c.Query("group_by"))}
+		for name, target := range map[string]*time.Time{"from": &q.From, "to": &q.To} {
+			raw := c.Query(name)
+			if raw == "" {
+				continue
+			}
+			t, err := time.Parse(time.RFC3339, raw)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": translate(c, "from and to must be RFC 3339 timestamps")})
+				return
+			}
+			*target = t
+		}
+		if q.TeamID, ok = queryUUID(c, "team_id"); !ok {
+			return
+		}
+		if q.WorkflowID, ok = queryUUID(c, "workflow_id"); !ok {
+			return
+		}
+
+		report, err := svc.Usage(c.Request.Context(), billing.Caller{UserID: userID, Admin: isAdmin(c)}, q)
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"data": report})
+	}
+}
@@ -0,0 +1,132 @@
+package v1
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"github.com/jaydeep/go-n8n/internal/application/chat"
+)
+
+// chatUpgrader accepts any origin: like webhooks, chat endpoints are public
+// and are meant to be embedded in third-party pages
+var chatUpgrader = websocket.Upgrader{
+	ReadBufferSize:  1024,
+	WriteBufferSize: 1024,
+	CheckOrigin:     func(r *http.Request) bool { return true },
+}
+
+// chatMessageRequest is a message sent to a chat workflow
+type chatMessageRequest struct {
+	SessionID string `json:"session_id"`
+	Message   string `json:"message"`
+}
+
+// chatEvent is a frame sent to WebSocket chat clients
+type chatEvent struct {
+	Type      string         `json:"type"`
+	SessionID string         `json:"session_id,omitempty"`
+	Message   string         `json:"message,omitempty"`
+	Messages  []chat.Message `json:"messages,omitempty"`
+	Error     string         `json:"error,omitempty"`
+}
+
+// preflight answers OPTIONS requests that are not CORS preflights, which
+// PublicCORS answers itself
+func preflight(c *gin.Context) {
+	c.Status(http.StatusNoContent)
+}
+
+// getChatSession opens a chat session, returning its history
+func getChatSession(svc *chat.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		workflowID, ok := paramUUID(c, "workflowId")
+		if !ok {
+			return
+		}
+
+		session, err := svc.Open(c.Request.Context(), workflowID, c.Query("session_id"))
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"data": session})
+	}
+}
+
+// sendChatMessage runs the chat workflow for a single message
+func sendChatMessage(svc *chat.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		workflowID, ok := paramUUID(c, "workflowId")
+		if !ok {
+			return
+		}
+
+		var input chatMessageRequest
+		if err := c.ShouldBindJSON(&input); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		reply, err := svc.Send(c.Request.Context(), workflowID, input.SessionID, input.Message)
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"data": reply})
+	}
+}
+
+// chatWebSocket keeps a chat session open over a WebSocket. The first frame
+// carries the session and its history; every message frame is answered with
+// the workflow's reply.
+func chatWebSocket(svc *chat.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		workflowID, ok := paramUUID(c, "workflowId")
+		if !ok {
+			return
+		}
+
+		ctx := c.Request.Context()
+		session, err := svc.Open(ctx, workflowID, c.Query("session_id"))
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+
+		conn, err := chatUpgrader.Upgrade(c.Writer, c.Request, nil)
+		if err != nil {
+			// Upgrade has already written the error response
+			return
+		}
+		defer conn.Close()
+
+		if err := conn.WriteJSON(chatEvent{
+			Type:      "session",
+			SessionID: session.ID,
+			Messages:  session.Messages,
+		}); err != nil {
+			return
+		}
+
+		for {
+			var input chatMessageRequest
+			if err := conn.ReadJSON(&input); err != nil {
+				return
+			}
+
+			reply, err := svc.Send(ctx, workflowID, session.ID, input.Message)
+			event := chatEvent{Type: "message", SessionID: session.ID}
+			if err != nil {
+				event.Type = "error"
+				event.Error = err.Error()
+			} else {
+				event.Message = reply.Message
+			}
+
+			if err := conn.WriteJSON(event); err != nil {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package v1
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jaydeep/go-n8n/internal/application/cleanup"
+	cleanupdomain "github.com/jaydeep/go-n8n/internal/domain/cleanup"
+)
+
+// getCleanupReport lists the orphaned resources of the kinds in the kinds
+// query parameter, or of every kind
+func getCleanupReport(svc *cleanup.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var days int
+		if raw := c.Query("inactive_days"); raw != "" {
+			n, err := strconv.Atoi(raw)
+			if err != nil || n <= 0 {
+				c.JSON(http.StatusBadRequest, gin.H{"error": translate(c, "inactive_days must be a positive integer")})
+				return
+			}
+			days = n
+		}
+		var kinds []cleanupdomain.Kind
+		if raw := c.Query("kinds"); raw != "" {
+			for _, k := range strings.Split(raw, ",") {
+				kinds = append(kinds, cleanupdomain.Kind(strings.TrimSpace(k)))
+			}
+		}
+
+		report, err := svc.Report(c.Request.Context(), days, kinds...)
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"data": report})
+	}
+}
+
+// runCleanup deletes the orphaned resources of the kinds requested
+func runCleanup(svc *cleanup.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		actor, ok := actorFrom(c)
+		if !ok {
+			return
+		}
+
+		var input cleanup.CleanInput
+		if err := c.ShouldBindJSON(&input); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		result, err := svc.Clean(c.Request.Context(), actor, input)
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"data": result})
+	}
+}
@@ -0,0 +1,248 @@
+package v1
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jaydeep/go-n8n/internal/application/credential"
+	domain "github.com/jaydeep/go-n8n/internal/domain/credential"
+)
+
+// loadCredential loads the credential referenced by the :id parameter and
+// checks that the current user may access it
+func loadCredential(c *gin.Context, svc *credential.Service) (*domain.Credential, bool) {
+	id, ok := paramUUID(c, "id")
+	if !ok {
+		return nil, false
+	}
+
+	cred, err := svc.Get(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err)
+		return nil, false
+	}
+
+	if !canAccess(c, cred.UserID) {
+		c.JSON(http.StatusNotFound, gin.H{"error": translate(c, domain.ErrCredentialNotFound.Error())})
+		return nil, false
+	}
+	return cred, true
+}
+
+// getCredential returns a credential without its secrets, with its content
+// hash, also sent as the ETag
+func getCredential(svc *credential.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		cred, ok := loadCredential(c, svc)
+		if !ok {
+			return
+		}
+		respondCredential(c, http.StatusOK, cred)
+	}
+}
+
+// getCredentialBySlug returns the credential with the :slug parameter
+func getCredentialBySlug(svc *credential.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		cred, err := svc.FindBySlug(c.Request.Context(), c.Param("slug"))
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+		if !canAccess(c, cred.UserID) {
+			c.JSON(http.StatusNotFound, gin.H{"error": translate(c, domain.ErrCredentialNotFound.Error())})
+			return
+		}
+		respondCredential(c, http.StatusOK, cred)
+	}
+}
+
+// updateCredential replaces a credential, creating it under the :id
+// parameter when it does not exist. Repeating a request changes nothing,
+// so clients managing credentials as code can retry it.
+func updateCredential(svc *credential.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, ok := paramUUID(c, "id")
+		if !ok {
+			return
+		}
+		cred, err := svc.Get(c.Request.Context(), id)
+		if err != nil && !errors.Is(err, domain.ErrCredentialNotFound) {
+			respondError(c, err)
+			return
+		}
+		if err == nil && !canAccess(c, cred.UserID) {
+			c.JSON(http.StatusNotFound, gin.H{"error": translate(c, domain.ErrCredentialNotFound.Error())})
+			return
+		}
+		saveCredential(c, svc, id, cred)
+	}
+}
+
+// putCredentialBySlug replaces the credential with the :slug parameter,
+// creating it under a new ID when no credential has the slug
+func putCredentialBySlug(svc *credential.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		slug := c.Param("slug")
+		if err := domain.ValidateSlug(slug); err != nil {
+			respondError(c, err)
+			return
+		}
+		cred, err := svc.FindBySlug(c.Request.Context(), slug)
+		if err != nil && !errors.Is(err, domain.ErrCredentialNotFound) {
+			respondError(c, err)
+			return
+		}
+		if err == nil && !canAccess(c, cred.UserID) {
+			c.JSON(http.StatusNotFound, gin.H{"error": translate(c, domain.ErrCredentialNotFound.Error())})
+			return
+		}
+		id := uuid.New()
+		if cred != nil {
+			id = cred.ID
+		}
+		saveCredential(c, svc, id, cred)
+	}
+}
+
+// saveCredential updates cred from the request body, or creates a
+// credential with the ID and the slug of the path, if any, when cred is nil
+func saveCredential(c *gin.Context, svc *credential.Service, id uuid.UUID, cred *domain.Credential) {
+	hash := ""
+	if cred != nil {
+		hash = cred.ContentHash()
+	}
+	if !checkPreconditions(c, hash) {
+		return
+	}
+	if !hasPermission(c, "credential:manage") {
+		c.JSON(http.StatusForbidden, gin.H{"error": translate(c, "insufficient permissions")})
+		return
+	}
+	actor, ok := actorFrom(c)
+	if !ok {
+		return
+	}
+
+	var input credential.Input
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if slug := c.Param("slug"); slug != "" {
+		input.Slug = &slug
+	}
+
+	if cred != nil {
+		updated, err := svc.Update(c.Request.Context(), actor, cred.ID, input)
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+		respondCredential(c, http.StatusOK, updated)
+		return
+	}
+
+	created, err := svc.Create(c.Request.Context(), actor, id, actor.UserID, input)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	respondCredential(c, http.StatusCreated, created)
+}
+
+// respondCredential responds with a credential and its content hash
+func respondCredential(c *gin.Context, status int, cred *domain.Credential) {
+	hash := cred.ContentHash()
+	c.Header("ETag", contentETag(hash))
+	c.JSON(status, gin.H{"data": cred, "content_hash": hash})
+}
+
+// deleteCredential deletes a credential
+func deleteCredential(svc *credential.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		cred, ok := loadCredential(c, svc)
+		if !ok {
+			return
+		}
+		if !checkPreconditions(c, cred.ContentHash()) {
+			return
+		}
+		if !hasPermission(c, "credential:manage") {
+			c.JSON(http.StatusForbidden, gin.H{"error": translate(c, "insufficient permissions")})
+			return
+		}
+		actor, ok := actorFrom(c)
+		if !ok {
+			return
+		}
+
+		if err := svc.Delete(c.Request.Context(), actor, cred.ID); err != nil {
+			respondError(c, err)
+			return
+		}
+		c.Status(http.StatusNoContent)
+	}
+}
+
+// getCredentialHash returns the content hash of a credential, so clients
+// detect drift, secrets included, without fetching it
+func getCredentialHash(svc *credential.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		cred, ok := loadCredential(c, svc)
+		if !ok {
+			return
+		}
+
+		hash := cred.ContentHash()
+		c.Header("ETag", contentETag(hash))
+		c.JSON(http.StatusOK, gin.H{"data": gin.H{
+			"id":           cred.ID,
+			"slug":         cred.Slug,
+			"content_hash": hash,
+			"updated_at":   cred.UpdatedAt,
+		}})
+	}
+}
+
+// testCredential runs the health check of a credential now and responds
+// with its outcome, notifying the owner of a change as the periodic check
+// does
+func testCredential(svc *credential.Service, monitor *credential.HealthMonitor) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		cred, ok := loadCredential(c, svc)
+		if !ok {
+			return
+		}
+		health, err := monitor.Check(c.Request.Context(), cred)
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"data": health})
+	}
+}
+
+// listUnhealthyCredentials returns the credentials of the current user,
+// or of everyone for admins, whose latest health check found them failing,
+// expiring or expired
+func listUnhealthyCredentials(monitor *credential.HealthMonitor) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var owner *uuid.UUID
+		if !isAdmin(c) {
+			userID, ok := currentUserID(c)
+			if !ok {
+				return
+			}
+			owner = &userID
+		}
+		credentials, err := monitor.Unhealthy(c.Request.Context(), owner)
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"data": credentials})
+	}
+}
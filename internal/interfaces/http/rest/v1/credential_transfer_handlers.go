@@ -0,0 +1,56 @@
+package v1
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jaydeep/go-n8n/internal/application/credential"
+)
+
+// exportCredentials returns a bundle of credentials with their secrets
+// encrypted under the given passphrase, for import into another instance
+func exportCredentials(svc *credential.Transfer) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		actor, ok := actorFrom(c)
+		if !ok {
+			return
+		}
+
+		var input credential.ExportInput
+		if err := c.ShouldBindJSON(&input); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		bundle, err := svc.Export(c.Request.Context(), actor, input)
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"data": bundle})
+	}
+}
+
+// importCredentials stores the credentials of a bundle exported by another
+// instance, re-encrypted with the key of this one
+func importCredentials(svc *credential.Transfer) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		actor, ok := actorFrom(c)
+		if !ok {
+			return
+		}
+
+		var input credential.ImportInput
+		if err := c.ShouldBindJSON(&input); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		result, err := svc.Import(c.Request.Context(), actor, input)
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"data": result})
+	}
+}
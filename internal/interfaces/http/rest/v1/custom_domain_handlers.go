@@ -0,0 +1,119 @@
+package v1
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jaydeep/go-n8n/internal/application/endpoint"
+)
+
+// customDomainRequest is the body for adding a custom domain to a team
+type customDomainRequest struct {
+	Hostname string `json:"hostname" binding:"required"`
+}
+
+// listCustomDomains lists the custom domains of a team
+func listCustomDomains(svc *endpoint.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		caller, ok := endpointCaller(c)
+		if !ok {
+			return
+		}
+		teamID, ok := paramUUID(c, "id")
+		if !ok {
+			return
+		}
+
+		domains, err := svc.Domains(c.Request.Context(), caller, teamID)
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"data": domains})
+	}
+}
+
+// addCustomDomain adds a custom domain to a team. The response names the
+// DNS TXT record to create before verifying the domain.
+func addCustomDomain(svc *endpoint.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		caller, ok := endpointCaller(c)
+		if !ok {
+			return
+		}
+		teamID, ok := paramUUID(c, "id")
+		if !ok {
+			return
+		}
+
+		var req customDomainRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		d, err := svc.AddDomain(c.Request.Context(), caller, teamID, req.Hostname)
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+		c.JSON(http.StatusCreated, gin.H{
+			"data": d,
+			"verification": gin.H{
+				"type":  "TXT",
+				"name":  d.VerificationRecord(),
+				"value": d.VerificationToken,
+			},
+		})
+	}
+}
+
+// verifyCustomDomain checks the verification record of a custom domain
+// and starts serving the domain once it is found
+func verifyCustomDomain(svc *endpoint.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		caller, ok := endpointCaller(c)
+		if !ok {
+			return
+		}
+		teamID, ok := paramUUID(c, "id")
+		if !ok {
+			return
+		}
+		domainID, ok := paramUUID(c, "domainId")
+		if !ok {
+			return
+		}
+
+		d, err := svc.VerifyDomain(c.Request.Context(), caller, teamID, domainID)
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"data": d})
+	}
+}
+
+// removeCustomDomain stops serving a custom domain and deletes it
+func removeCustomDomain(svc *endpoint.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		caller, ok := endpointCaller(c)
+		if !ok {
+			return
+		}
+		teamID, ok := paramUUID(c, "id")
+		if !ok {
+			return
+		}
+		domainID, ok := paramUUID(c, "domainId")
+		if !ok {
+			return
+		}
+
+		if err := svc.RemoveDomain(c.Request.Context(), caller, teamID, domainID); err != nil {
+			respondError(c, err)
+			return
+		}
+		c.Status(http.StatusNoContent)
+	}
+}
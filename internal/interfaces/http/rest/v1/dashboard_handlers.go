@@ -0,0 +1,102 @@
+package v1
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jaydeep/go-n8n/internal/application/dashboard"
+	domain "github.com/jaydeep/go-n8n/internal/domain/dashboard"
+)
+
+// dashboardCaller identifies the authenticated user to the dashboard
+// service
+func dashboardCaller(c *gin.Context) (dashboard.Caller, bool) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return dashboard.Caller{}, false
+	}
+	return dashboard.Caller{UserID: userID, Admin: isAdmin(c)}, true
+}
+
+// dashboardRange reads the from and to (RFC 3339) and interval query
+// parameters
+func dashboardRange(c *gin.Context) (dashboard.Range, bool) {
+	r := dashboard.Range{Interval: domain.Interval(c.Query("interval"))}
+	for name, target := range map[string]*time.Time{"from": &r.From, "to": &r.To} {
+		raw := c.Query(name)
+		if raw == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": translate(c, "from and to must be RFC 3339 timestamps")})
+			return r, false
+		}
+		*target = t
+	}
+	return r, true
+}
+
+// getTeamDashboard returns the time series of execution counts, error
+// rates and average durations of a team's workflows. The range defaults
+// to the last 7 days; interval is hour, day or week.
+func getTeamDashboard(svc *dashboard.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		caller, ok := dashboardCaller(c)
+		if !ok {
+			return
+		}
+		teamID, ok := paramUUID(c, "id")
+		if !ok {
+			return
+		}
+		r, ok := dashboardRange(c)
+		if !ok {
+			return
+		}
+
+		metrics, err := svc.Metrics(c.Request.Context(), caller, teamID, r)
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"data": metrics})
+	}
+}
+
+// getTeamFailingWorkflows returns the team's workflows with the most
+// failed executions over the range, up to limit
+func getTeamFailingWorkflows(svc *dashboard.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		caller, ok := dashboardCaller(c)
+		if !ok {
+			return
+		}
+		teamID, ok := paramUUID(c, "id")
+		if !ok {
+			return
+		}
+		r, ok := dashboardRange(c)
+		if !ok {
+			return
+		}
+		var limit int
+		if raw := c.Query("limit"); raw != "" {
+			n, err := strconv.Atoi(raw)
+			if err != nil || n <= 0 {
+				c.JSON(http.StatusBadRequest, gin.H{"error": translate(c, "limit must be a positive integer")})
+				return
+			}
+			limit = n
+		}
+
+		failing, err := svc.TopFailing(c.Request.Context(), caller, teamID, r, limit)
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"data": failing})
+	}
+}
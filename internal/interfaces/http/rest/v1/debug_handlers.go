@@ -0,0 +1,56 @@
+package v1
+
+import (
+	"net/http"
+	"net/http/pprof"
+	runtimepprof "runtime/pprof"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jaydeep/go-n8n/internal/application/engine"
+)
+
+// registerDebugRoutes mounts the pprof profiles, a goroutine dump and the
+// engine snapshot on the group
+func registerDebugRoutes(debug *gin.RouterGroup, diagnostics *engine.Diagnostics) {
+	// The pprof index links to the profiles relative to its own path
+	debug.GET("/pprof/", gin.WrapF(pprof.Index))
+	debug.GET("/pprof/cmdline", gin.WrapF(pprof.Cmdline))
+	debug.GET("/pprof/profile", gin.WrapF(pprof.Profile))
+	debug.GET("/pprof/symbol", gin.WrapF(pprof.Symbol))
+	debug.POST("/pprof/symbol", gin.WrapF(pprof.Symbol))
+	debug.GET("/pprof/trace", gin.WrapF(pprof.Trace))
+	debug.GET("/pprof/:profile", getPprofProfile)
+
+	debug.GET("/goroutines", getGoroutineDump)
+	debug.GET("/engine", getEngineSnapshot(diagnostics))
+}
+
+// getPprofProfile serves a named runtime profile such as heap or goroutine
+func getPprofProfile(c *gin.Context) {
+	name := c.Param("profile")
+	if runtimepprof.Lookup(name) == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "unknown profile"})
+		return
+	}
+	pprof.Handler(name).ServeHTTP(c.Writer, c.Request)
+}
+
+// getGoroutineDump writes the stacks of all goroutines as text
+func getGoroutineDump(c *gin.Context) {
+	c.Header("Content-Type", "text/plain; charset=utf-8")
+	c.Status(http.StatusOK)
+	runtimepprof.Lookup("goroutine").WriteTo(c.Writer, 2)
+}
+
+// getEngineSnapshot reports running executions, queue lag, buffers, cache
+// sizes and runtime statistics
+func getEngineSnapshot(diagnostics *engine.Diagnostics) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		snapshot, err := diagnostics.Snapshot(c.Request.Context())
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to collect engine snapshot"})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"data": snapshot})
+	}
+}
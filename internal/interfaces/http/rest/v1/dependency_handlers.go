@@ -0,0 +1,75 @@
+package v1
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jaydeep/go-n8n/internal/application/workflow"
+)
+
+// getWorkflowDependencies returns the credentials, variables, workflows and
+// webhooks a workflow depends on
+func getWorkflowDependencies(workflows *workflow.Service, graph *workflow.DependencyGraph) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !hasPermission(c, "workflow:read") {
+			c.JSON(http.StatusForbidden, gin.H{"error": translate(c, "insufficient permissions")})
+			return
+		}
+		w, ok := loadWorkflow(c, workflows)
+		if !ok {
+			return
+		}
+		owner, ok := workflowScope(c)
+		if !ok {
+			return
+		}
+
+		deps, err := graph.Of(c.Request.Context(), w, owner)
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"data": deps})
+	}
+}
+
+// getWorkflowDependents returns the workflows that would break if a
+// workflow were deleted: those of the current user, or of everyone for
+// admins, running it or calling its webhooks
+func getWorkflowDependents(workflows *workflow.Service, graph *workflow.DependencyGraph) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !hasPermission(c, "workflow:read") {
+			c.JSON(http.StatusForbidden, gin.H{"error": translate(c, "insufficient permissions")})
+			return
+		}
+		w, ok := loadWorkflow(c, workflows)
+		if !ok {
+			return
+		}
+		owner, ok := workflowScope(c)
+		if !ok {
+			return
+		}
+
+		dependents, err := graph.Dependents(c.Request.Context(), w, owner)
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"data": dependents})
+	}
+}
+
+// workflowScope returns the user whose workflows the current user sees,
+// nil for admins, who see all
+func workflowScope(c *gin.Context) (*uuid.UUID, bool) {
+	if isAdmin(c) {
+		return nil, true
+	}
+	userID, ok := currentUserID(c)
+	if !ok {
+		return nil, false
+	}
+	return &userID, true
+}
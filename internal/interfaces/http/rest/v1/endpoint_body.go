@@ -0,0 +1,192 @@
+package v1
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jaydeep/go-n8n/internal/application/endpoint"
+	domain "github.com/jaydeep/go-n8n/internal/domain/endpoint"
+	"github.com/jaydeep/go-n8n/internal/domain/node"
+	"github.com/jaydeep/go-n8n/internal/nodes/core/trigger"
+	"github.com/jaydeep/go-n8n/pkg/binarydata"
+)
+
+// maxFormFieldSize bounds the value of a form field that is not a file
+const maxFormFieldSize = 1 << 20
+
+var (
+	errBodyNotJSON = errors.New("request body must be JSON")
+	errBodyNotForm = errors.New("request body must be a URL-encoded or multipart form")
+)
+
+// readEndpointBody reads the body of an endpoint call into req as the
+// webhook trigger of the endpoint says: decoded as JSON, parsed as a form
+// whose files become binary data, kept as binary data or not at all. With
+// RawBody the bytes received are kept as well. Binary data goes to store,
+// in the storage of the region of the endpoint, or is kept in the item when
+// store is nil. Malformed bodies are reported as errBodyNotJSON or
+// errBodyNotForm.
+func readEndpointBody(c *gin.Context, e *domain.Endpoint, store binarydata.Store, maxSize int64, req *endpoint.Request) error {
+	if c.Request.ContentLength == 0 || e.BodyFormat == trigger.BodyNone {
+		return nil
+	}
+	ctx := binarydata.WithRegion(c.Request.Context(), e.Region)
+	body := io.Reader(c.Request.Body)
+	if maxSize > 0 {
+		body = http.MaxBytesReader(c.Writer, c.Request.Body, maxSize)
+	}
+	contentType := c.GetHeader("Content-Type")
+
+	if e.BodyFormat == trigger.BodyRaw {
+		data, err := putBinary(ctx, store, "body", contentType, body)
+		if err != nil {
+			return err
+		}
+		req.Binary = map[string]node.Binary{"data": data}
+		return nil
+	}
+
+	if e.RawBody {
+		raw, err := io.ReadAll(body)
+		if err != nil {
+			return err
+		}
+		data, err := putBinary(ctx, store, "body", contentType, bytes.NewReader(raw))
+		if err != nil {
+			return err
+		}
+		req.Binary = map[string]node.Binary{"raw_body": data}
+		body = bytes.NewReader(raw)
+	}
+
+	if e.BodyFormat == trigger.BodyForm {
+		return readForm(ctx, store, contentType, body, req)
+	}
+	if err := json.NewDecoder(body).Decode(&req.Body); err != nil && err != io.EOF {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			return err
+		}
+		return errBodyNotJSON
+	}
+	return nil
+}
+
+// readForm parses a URL-encoded or multipart form. Fields sent once are
+// strings and fields sent several times lists of strings. Files become
+// binary properties named by their field, followed by their index when a
+// field holds several.
+func readForm(ctx context.Context, store binarydata.Store, contentType string, body io.Reader, req *endpoint.Request) error {
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return errBodyNotForm
+	}
+	switch mediaType {
+	case "application/x-www-form-urlencoded":
+		raw, err := io.ReadAll(body)
+		if err != nil {
+			return err
+		}
+		values, err := url.ParseQuery(string(raw))
+		if err != nil {
+			return errBodyNotForm
+		}
+		req.Body = formFields(values)
+		return nil
+	case "multipart/form-data":
+	default:
+		return errBodyNotForm
+	}
+
+	values := url.Values{}
+	files := map[string][]node.Binary{}
+	mr := multipart.NewReader(body, params["boundary"])
+	for {
+		part, err := mr.NextPart()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				return err
+			}
+			return errBodyNotForm
+		}
+		name := part.FormName()
+		if part.FileName() == "" {
+			value, err := io.ReadAll(io.LimitReader(part, maxFormFieldSize))
+			if err != nil {
+				return err
+			}
+			values.Add(name, string(value))
+			continue
+		}
+		file, err := putBinary(ctx, store, part.FileName(), part.Header.Get("Content-Type"), part)
+		if err != nil {
+			return err
+		}
+		files[name] = append(files[name], file)
+	}
+
+	req.Body = formFields(values)
+	for name, list := range files {
+		if req.Binary == nil {
+			req.Binary = map[string]node.Binary{}
+		}
+		if len(list) == 1 {
+			req.Binary[name] = list[0]
+			continue
+		}
+		for i, file := range list {
+			req.Binary[fmt.Sprintf("%s%d", name, i)] = file
+		}
+	}
+	return nil
+}
+
+// formFields returns form values as strings, or lists of strings for
+// fields sent several times
+func formFields(values url.Values) map[string]interface{} {
+	fields := make(map[string]interface{}, len(values))
+	for name, list := range values {
+		if len(list) == 1 {
+			fields[name] = list[0]
+		} else {
+			fields[name] = list
+		}
+	}
+	return fields
+}
+
+// putBinary keeps data received with a call as binary data, in store when
+// there is one and in the item otherwise
+func putBinary(ctx context.Context, store binarydata.Store, fileName, mimeType string, r io.Reader) (node.Binary, error) {
+	if mimeType == "" {
+		mimeType = "application/octet-stream"
+	}
+	data := node.Binary{FileName: fileName, MimeType: mimeType}
+	if store == nil {
+		content, err := io.ReadAll(r)
+		if err != nil {
+			return node.Binary{}, err
+		}
+		data.Data, data.FileSize = content, int64(len(content))
+		return data, nil
+	}
+	id, size, err := store.Put(ctx, r)
+	if err != nil {
+		return node.Binary{}, err
+	}
+	data.ID, data.FileSize = id, size
+	return data, nil
+}
@@ -0,0 +1,395 @@
+package v1
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jaydeep/go-n8n/configs"
+	"github.com/jaydeep/go-n8n/internal/application/endpoint"
+	domain "github.com/jaydeep/go-n8n/internal/domain/endpoint"
+	"github.com/jaydeep/go-n8n/internal/interfaces/http/middleware"
+	"github.com/jaydeep/go-n8n/pkg/binarydata"
+)
+
+// defaultUsageDays is the period covered by usage analytics by default
+const defaultUsageDays = 30
+
+// endpointSecretHeaders are request headers not passed on to the workflow
+var endpointSecretHeaders = map[string]bool{
+	"Authorization": true,
+	"X-Api-Key":     true,
+	"Cookie":        true,
+}
+
+// endpointCORS returns the CORS policy for calls of the endpoint named
+// by the slug parameter: the policy of its webhook trigger on top of the
+// defaults of public routes, which apply to endpoints without one
+func endpointCORS(svc *endpoint.Service, defaults configs.CORSConfig) func(c *gin.Context) configs.CORSConfig {
+	return func(c *gin.Context) configs.CORSConfig {
+		policy := svc.CORSPolicy(c.Request.Context(), c.Param("slug"))
+		if policy == nil {
+			return defaults
+		}
+		cfg := defaults
+		if len(policy.AllowedOrigins) > 0 {
+			cfg.AllowedOrigins = policy.AllowedOrigins
+		}
+		cfg.AllowedMethods = append(append([]string{}, policy.AllowedMethods...), http.MethodOptions)
+		if policy.MaxAge > 0 {
+			cfg.MaxAge = policy.MaxAge
+		}
+		return cfg
+	}
+}
+
+// endpointCaller identifies the authenticated user to the endpoint service
+func endpointCaller(c *gin.Context) (endpoint.Caller, bool) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return endpoint.Caller{}, false
+	}
+	return endpoint.Caller{UserID: userID, Admin: isAdmin(c)}, true
+}
+
+// createEndpointRequest is the body for exposing a workflow as an endpoint
+type createEndpointRequest struct {
+	Slug            string    `json:"slug" binding:"required"`
+	WorkflowID      uuid.UUID `json:"workflow_id" binding:"required"`
+	WorkflowVersion int       `json:"workflow_version"`
+	Description     string    `json:"description"`
+	RateLimit       int       `json:"rate_limit"`
+	AllowedIPs      []string  `json:"allowed_ips"`
+}
+
+// updateEndpointRequest is the body for changing an endpoint
+type updateEndpointRequest struct {
+	WorkflowVersion *int      `json:"workflow_version"`
+	Description     *string   `json:"description"`
+	RateLimit       *int      `json:"rate_limit"`
+	AllowedIPs      *[]string `json:"allowed_ips"`
+	IsActive        *bool     `json:"is_active"`
+}
+
+// listEndpoints lists the endpoints of the user's workflows
+func listEndpoints(svc *endpoint.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		caller, ok := endpointCaller(c)
+		if !ok {
+			return
+		}
+
+		endpoints, err := svc.List(c.Request.Context(), caller)
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"data": endpoints})
+	}
+}
+
+// createEndpoint exposes a published workflow version as an endpoint. The
+// API key is only returned here and by rotateEndpointKey.
+func createEndpoint(svc *endpoint.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !hasPermission(c, "workflow:execute") {
+			c.JSON(http.StatusForbidden, gin.H{"error": translate(c, "insufficient permissions")})
+			return
+		}
+		caller, ok := endpointCaller(c)
+		if !ok {
+			return
+		}
+
+		var req createEndpointRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		e, key, err := svc.Create(c.Request.Context(), caller, endpoint.Input{
+			Slug:            req.Slug,
+			WorkflowID:      req.WorkflowID,
+			WorkflowVersion: req.WorkflowVersion,
+			Description:     req.Description,
+			RateLimit:       req.RateLimit,
+			AllowedIPs:      req.AllowedIPs,
+		})
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+		c.JSON(http.StatusCreated, gin.H{"data": e, "api_key": key})
+	}
+}
+
+// getEndpoint returns an endpoint with its request and response schema
+func getEndpoint(svc *endpoint.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		caller, ok := endpointCaller(c)
+		if !ok {
+			return
+		}
+		id, ok := paramUUID(c, "id")
+		if !ok {
+			return
+		}
+
+		e, err := svc.Get(c.Request.Context(), caller, id)
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"data": e})
+	}
+}
+
+// updateEndpoint changes the pinned version, description, rate limit or
+// active state of an endpoint
+func updateEndpoint(svc *endpoint.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		caller, ok := endpointCaller(c)
+		if !ok {
+			return
+		}
+		id, ok := paramUUID(c, "id")
+		if !ok {
+			return
+		}
+
+		var req updateEndpointRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		e, err := svc.Update(c.Request.Context(), caller, id, endpoint.UpdateInput{
+			WorkflowVersion: req.WorkflowVersion,
+			Description:     req.Description,
+			RateLimit:       req.RateLimit,
+			AllowedIPs:      req.AllowedIPs,
+			IsActive:        req.IsActive,
+		})
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"data": e})
+	}
+}
+
+// rotateEndpointKey replaces the API key of an endpoint
+func rotateEndpointKey(svc *endpoint.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		caller, ok := endpointCaller(c)
+		if !ok {
+			return
+		}
+		id, ok := paramUUID(c, "id")
+		if !ok {
+			return
+		}
+
+		e, key, err := svc.RotateKey(c.Request.Context(), caller, id)
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"data": e, "api_key": key})
+	}
+}
+
+// deleteEndpoint removes an endpoint
+func deleteEndpoint(svc *endpoint.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		caller, ok := endpointCaller(c)
+		if !ok {
+			return
+		}
+		id, ok := paramUUID(c, "id")
+		if !ok {
+			return
+		}
+
+		if err := svc.Delete(c.Request.Context(), caller, id); err != nil {
+			respondError(c, err)
+			return
+		}
+		c.Status(http.StatusNoContent)
+	}
+}
+
+// getEndpointUsage returns the daily call statistics of an endpoint. The
+// period defaults to the last 30 days and can be set with from and to
+// dates (YYYY-MM-DD).
+func getEndpointUsage(svc *endpoint.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		caller, ok := endpointCaller(c)
+		if !ok {
+			return
+		}
+		id, ok := paramUUID(c, "id")
+		if !ok {
+			return
+		}
+
+		to := time.Now().UTC().Truncate(24 * time.Hour)
+		from := to.AddDate(0, 0, -(defaultUsageDays - 1))
+		for name, target := range map[string]*time.Time{"from": &from, "to": &to} {
+			raw := c.Query(name)
+			if raw == "" {
+				continue
+			}
+			day, err := time.Parse("2006-01-02", raw)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": translate(c, "dates must use the format YYYY-MM-DD")})
+				return
+			}
+			*target = day
+		}
+
+		usage, err := svc.Usage(c.Request.Context(), caller, id, from, to)
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"data": usage})
+	}
+}
+
+// endpointAPIKey reads the API key from the X-API-Key header or a bearer
+// token
+func endpointAPIKey(c *gin.Context) string {
+	if key := c.GetHeader("X-API-Key"); key != "" {
+		return key
+	}
+	if auth := c.GetHeader("Authorization"); strings.HasPrefix(auth, "Bearer ") {
+		return strings.TrimPrefix(auth, "Bearer ")
+	}
+	return ""
+}
+
+// invokeEndpoint serves a call of a workflow endpoint. The request is
+// counted against the endpoint rate limit, read and validated as the
+// webhook trigger says and answered with the result of the respond node.
+// Files received are kept in store, or in the item when it is nil.
+// Executions that outlive the webhook timeout are answered with 202. With a
+// buffer, calls arriving while executions cannot be started are stored for
+// replay and answered with 202 as well.
+func invokeEndpoint(svc *endpoint.Service, buffer *endpoint.Buffer, store binarydata.Store, cfg configs.WebhookConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		started := time.Now()
+		ctx := c.Request.Context()
+
+		e, err := svc.Authenticate(ctx, c.Param("slug"), endpointAPIKey(c), c.ClientIP(), middleware.DomainTeam(c))
+		if errors.Is(err, domain.ErrInvalidAPIKey) {
+			c.Header("WWW-Authenticate", `Bearer realm="endpoint"`)
+			c.JSON(http.StatusUnauthorized, gin.H{"error": translate(c, err.Error())})
+			return
+		}
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+
+		record := func(rateLimited bool) {
+			svc.RecordCall(ctx, e, domain.Call{
+				At:          started,
+				StatusCode:  c.Writer.Status(),
+				Duration:    time.Since(started),
+				RateLimited: rateLimited,
+			})
+		}
+
+		limit, err := svc.Allow(ctx, e)
+		if limit != nil {
+			c.Header("X-RateLimit-Limit", strconv.Itoa(limit.Limit))
+			c.Header("X-RateLimit-Remaining", strconv.Itoa(limit.Remaining))
+			c.Header("X-RateLimit-Reset", strconv.FormatInt(limit.Reset.Unix(), 10))
+		}
+		if err != nil {
+			c.Header("Retry-After", strconv.Itoa(int(time.Until(limit.Reset).Seconds())+1))
+			c.JSON(http.StatusTooManyRequests, gin.H{"error": translate(c, err.Error())})
+			record(true)
+			return
+		}
+
+		req := endpoint.Request{
+			Method:  c.Request.Method,
+			Query:   map[string]string{},
+			Headers: map[string]string{},
+		}
+		for name := range c.Request.URL.Query() {
+			req.Query[name] = c.Query(name)
+		}
+		for name := range c.Request.Header {
+			if !endpointSecretHeaders[name] {
+				req.Headers[name] = c.GetHeader(name)
+			}
+		}
+		if err := readEndpointBody(c, e, store, cfg.MaxPayloadSize, &req); err != nil {
+			var tooLarge *http.MaxBytesError
+			switch {
+			case errors.Is(err, errBodyNotJSON), errors.Is(err, errBodyNotForm):
+				c.JSON(http.StatusBadRequest, gin.H{"error": translate(c, err.Error())})
+			case errors.As(err, &tooLarge):
+				c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": translate(c, "request body is too large")})
+			default:
+				respondError(c, err)
+			}
+			record(false)
+			return
+		}
+
+		accept := func(reason string) {
+			call, err := buffer.Accept(ctx, e, req, reason)
+			if err != nil {
+				if errors.Is(err, domain.ErrMethodNotAllowed) {
+					c.Header("Allow", e.Method)
+				}
+				respondError(c, err)
+				record(false)
+				return
+			}
+			c.JSON(http.StatusAccepted, gin.H{"status": "buffered", "buffered_call_id": call.ID})
+			record(false)
+		}
+		if buffer != nil {
+			if reason, unavailable := buffer.Unavailable(ctx); unavailable {
+				accept(reason)
+				return
+			}
+		}
+
+		// Outlive the server write timeout while waiting
+		timeout := cfg.Timeout
+		if timeout <= 0 {
+			timeout = 30 * time.Second
+		}
+		http.NewResponseController(c.Writer).SetWriteDeadline(time.Now().Add(timeout + 5*time.Second))
+
+		resp, err := svc.Invoke(ctx, e, req, timeout)
+		if buffer != nil && errors.Is(err, domain.ErrEngineUnavailable) {
+			accept(domain.BufferReasonUnavailable)
+			return
+		}
+		if err != nil {
+			if errors.Is(err, domain.ErrMethodNotAllowed) {
+				c.Header("Allow", e.Method)
+				c.JSON(http.StatusMethodNotAllowed, gin.H{"error": translate(c, domain.ErrMethodNotAllowed.Error())})
+			} else {
+				respondError(c, err)
+			}
+			record(false)
+			return
+		}
+
+		c.Header("X-Execution-ID", resp.Execution.ID.String())
+		c.JSON(resp.StatusCode, resp.Body)
+		record(false)
+	}
+}
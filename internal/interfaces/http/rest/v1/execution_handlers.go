@@ -0,0 +1,372 @@
+package v1
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jaydeep/go-n8n/configs"
+	"github.com/jaydeep/go-n8n/internal/application/execution"
+	"github.com/jaydeep/go-n8n/internal/application/workflow"
+	domain "github.com/jaydeep/go-n8n/internal/domain/execution"
+	"github.com/jaydeep/go-n8n/internal/domain/node"
+)
+
+// executeWorkflowRequest is the body for starting an execution
+type executeWorkflowRequest struct {
+	Data map[string]interface{} `json:"data"`
+	// CallbackURL receives the result once the execution finishes
+	CallbackURL string `json:"callback_url"`
+	// DryRun skips the nodes with side effects, or outputs the items of
+	// mocks in their place; force and allow name the nodes to skip or run
+	// whatever their side effects
+	DryRun bool                   `json:"dry_run"`
+	Mocks  map[string][]node.Item `json:"mocks"`
+	Force  []string               `json:"force"`
+	Allow  []string               `json:"allow"`
+}
+
+// dryRunOptions returns the dry run options of the request
+func (r *executeWorkflowRequest) dryRunOptions() *domain.DryRunOptions {
+	return &domain.DryRunOptions{Mocks: r.Mocks, Force: r.Force, Allow: r.Allow}
+}
+
+// waitTimeout parses the wait and timeout query parameters. It returns 0
+// when the caller does not want to wait.
+func waitTimeout(c *gin.Context, cfg configs.EngineConfig) (time.Duration, bool) {
+	wait, _ := strconv.ParseBool(c.Query("wait"))
+	if !wait {
+		return 0, true
+	}
+
+	timeout := cfg.SyncWaitTimeout
+	if raw := c.Query("timeout"); raw != "" {
+		d, err := time.ParseDuration(raw)
+		if err != nil || d <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": translate(c, "timeout must be a positive duration such as 30s")})
+			return 0, false
+		}
+		timeout = d
+	}
+	if cfg.MaxSyncWaitTimeout > 0 && timeout > cfg.MaxSyncWaitTimeout {
+		timeout = cfg.MaxSyncWaitTimeout
+	}
+	if timeout <= 0 {
+		timeout = 30 * time.Second
+	}
+	return timeout, true
+}
+
+// executeWorkflow queues an execution of the published workflow. When a
+// callback_url is given the result is POSTed to it once the execution
+// finishes, signed with the callback secret of the workflow. With wait=true the
+// request blocks until the execution finishes, answering 200 with the
+// result, or until the timeout, answering 202 with the execution to poll.
+func executeWorkflow(workflows *workflow.Service, executions *execution.Service, cfg configs.EngineConfig) gin.HandlerFunc {
+	return startExecution(workflows, executions, cfg, domain.ExecutionModeManual)
+}
+
+// getCallbackSecret returns the secret the callbacks of a workflow's
+// executions are signed with, for their receivers to check the signatures
+func getCallbackSecret(workflows *workflow.Service, cfg configs.CallbackConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !hasPermission(c, "workflow:execute") {
+			c.JSON(http.StatusForbidden, gin.H{"error": translate(c, "insufficient permissions")})
+			return
+		}
+		w, ok := loadWorkflow(c, workflows)
+		if !ok {
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"data": gin.H{"secret": execution.CallbackSecret(cfg.Secret, w.ID)}})
+	}
+}
+
+// testWorkflow queues a test execution of the draft of a workflow, in which
+// the nodes with pinned data output it instead of running. It takes the
+// same request as executeWorkflow.
+func testWorkflow(workflows *workflow.Service, executions *execution.Service, cfg configs.EngineConfig) gin.HandlerFunc {
+	return startExecution(workflows, executions, cfg, domain.ExecutionModeTest)
+}
+
+// startExecution queues an execution of a workflow in a mode, of its draft
+// for test executions and of its published version otherwise
+func startExecution(workflows *workflow.Service, executions *execution.Service, cfg configs.EngineConfig, mode domain.ExecutionMode) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !hasPermission(c, "workflow:execute") {
+			c.JSON(http.StatusForbidden, gin.H{"error": translate(c, "insufficient permissions")})
+			return
+		}
+		w, ok := loadWorkflow(c, workflows)
+		if !ok {
+			return
+		}
+
+		timeout, ok := waitTimeout(c, cfg)
+		if !ok {
+			return
+		}
+
+		var req executeWorkflowRequest
+		if c.Request.ContentLength != 0 {
+			if err := c.ShouldBindJSON(&req); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+		}
+		if !req.DryRun && !req.dryRunOptions().IsZero() {
+			c.JSON(http.StatusBadRequest, gin.H{"error": translate(c, "mocks, force and allow need dry_run")})
+			return
+		}
+
+		exec, err := executions.Start(c.Request.Context(), w.ID, execution.StartInput{
+			Mode:          mode,
+			Data:          req.Data,
+			CallbackURL:   req.CallbackURL,
+			Draft:         mode == domain.ExecutionModeTest,
+			DryRun:        req.DryRun,
+			DryRunOptions: req.dryRunOptions(),
+		})
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+
+		c.Header("Location", "/api/v1/executions/"+exec.ID.String())
+		if timeout == 0 {
+			c.JSON(http.StatusAccepted, gin.H{"data": exec})
+			return
+		}
+
+		// Outlive the server write timeout while waiting
+		http.NewResponseController(c.Writer).SetWriteDeadline(time.Now().Add(timeout + 5*time.Second))
+
+		finished, err := executions.Wait(c.Request.Context(), exec.ID, timeout)
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+		if !finished.Status.IsTerminal() {
+			c.JSON(http.StatusAccepted, gin.H{"data": finished, "timed_out": true})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"data": finished})
+	}
+}
+
+// listExecutions lists executions newest first, filtered by workflow_id,
+// status, correlation_id, error text and a from/to start time range.
+// Users other than admins see the executions of their own workflows only.
+// JSON responses are pages continued with next_cursor; with Accept:
+// application/x-ndjson every matching execution is streamed instead,
+// starting after cursor if given.
+func listExecutions(svc *execution.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !hasPermission(c, "workflow:read") {
+			c.JSON(http.StatusForbidden, gin.H{"error": translate(c, "insufficient permissions")})
+			return
+		}
+		userID, ok := currentUserID(c)
+		if !ok {
+			return
+		}
+		q, ok := parseListQuery(c)
+		if !ok {
+			return
+		}
+		workflowID, ok := queryUUID(c, "workflow_id")
+		if !ok {
+			return
+		}
+
+		filter := domain.ListFilter{
+			WorkflowID:    workflowID,
+			Status:        domain.ExecutionStatus(c.Query("status")),
+			CorrelationID: c.Query("correlation_id"),
+			ErrorText:     c.Query("error"),
+			From:          q.From,
+			To:            q.To,
+			After:         q.Cursor,
+			Limit:         q.Limit,
+		}
+		if !isAdmin(c) {
+			filter.UserID = &userID
+		}
+
+		if wantsNDJSON(c) {
+			streamNDJSON(c, func(write func(row interface{}) error) error {
+				return svc.Export(c.Request.Context(), filter, func(e *domain.Execution) error {
+					return write(e)
+				})
+			})
+			return
+		}
+
+		executions, next, err := svc.List(c.Request.Context(), filter)
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+		respondPage(c, executions, next)
+	}
+}
+
+// getExecutionLineage returns the tree of executions an execution is
+// chained with through sub-workflows and error workflows. Executions of
+// workflows the user may not see are included without their details.
+func getExecutionLineage(workflows *workflow.Service, executions *execution.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !hasPermission(c, "workflow:read") {
+			c.JSON(http.StatusForbidden, gin.H{"error": translate(c, "insufficient permissions")})
+			return
+		}
+		id, ok := paramUUID(c, "id")
+		if !ok {
+			return
+		}
+
+		exec, err := executions.Get(c.Request.Context(), id)
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+		w, err := workflows.Get(c.Request.Context(), exec.WorkflowID)
+		if err != nil || !canAccess(c, w.UserID) {
+			c.JSON(http.StatusNotFound, gin.H{"error": translate(c, domain.ErrExecutionNotFound.Error())})
+			return
+		}
+
+		lineage, err := executions.Lineage(c.Request.Context(), exec, func(ownerID uuid.UUID) bool {
+			return canAccess(c, ownerID)
+		})
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"data": lineage})
+	}
+}
+
+// deleteExecution deletes an execution of a workflow the user may access,
+// unless the workflow is on legal hold
+func deleteExecution(workflows *workflow.Service, executions *execution.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !hasPermission(c, "workflow:delete") {
+			c.JSON(http.StatusForbidden, gin.H{"error": translate(c, "insufficient permissions")})
+			return
+		}
+		id, ok := paramUUID(c, "id")
+		if !ok {
+			return
+		}
+
+		exec, err := executions.Get(c.Request.Context(), id)
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+		w, err := workflows.Get(c.Request.Context(), exec.WorkflowID)
+		if err != nil || !canAccess(c, w.UserID) {
+			c.JSON(http.StatusNotFound, gin.H{"error": translate(c, domain.ErrExecutionNotFound.Error())})
+			return
+		}
+
+		if err := executions.Delete(c.Request.Context(), id); err != nil {
+			respondError(c, err)
+			return
+		}
+		c.Status(http.StatusNoContent)
+	}
+}
+
+// resumeExecutionRequest is the body for resuming an execution
+type resumeExecutionRequest struct {
+	// Data is output by the node the execution waited at
+	Data map[string]interface{} `json:"data"`
+}
+
+// resumeExecution resumes an execution of a workflow the user may access
+// that waits to be resumed by request, such as the webhook callback of a
+// Wait node. The execution is queued again and carries on from the node it
+// waited at.
+func resumeExecution(workflows *workflow.Service, executions *execution.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !hasPermission(c, "workflow:execute") {
+			c.JSON(http.StatusForbidden, gin.H{"error": translate(c, "insufficient permissions")})
+			return
+		}
+		id, ok := paramUUID(c, "id")
+		if !ok {
+			return
+		}
+
+		var req resumeExecutionRequest
+		if c.Request.ContentLength != 0 {
+			if err := c.ShouldBindJSON(&req); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+		}
+
+		exec, err := executions.Get(c.Request.Context(), id)
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+		w, err := workflows.Get(c.Request.Context(), exec.WorkflowID)
+		if err != nil || !canAccess(c, w.UserID) {
+			c.JSON(http.StatusNotFound, gin.H{"error": translate(c, domain.ErrExecutionNotFound.Error())})
+			return
+		}
+
+		exec, err = executions.Resume(c.Request.Context(), id, req.Data)
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+		c.JSON(http.StatusAccepted, gin.H{"data": exec})
+	}
+}
+
+// compareExecutions diffs an execution against another run of its
+// workflow, node by node
+func compareExecutions(workflows *workflow.Service, executions *execution.Service, comparer *execution.Comparer) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !hasPermission(c, "workflow:read") {
+			c.JSON(http.StatusForbidden, gin.H{"error": translate(c, "insufficient permissions")})
+			return
+		}
+		id, ok := paramUUID(c, "id")
+		if !ok {
+			return
+		}
+		otherID, ok := paramUUID(c, "otherId")
+		if !ok {
+			return
+		}
+
+		var runs [2]*domain.Execution
+		for i, runID := range []uuid.UUID{id, otherID} {
+			exec, err := executions.Get(c.Request.Context(), runID)
+			if err != nil {
+				respondError(c, err)
+				return
+			}
+			w, err := workflows.Get(c.Request.Context(), exec.WorkflowID)
+			if err != nil || !canAccess(c, w.UserID) {
+				c.JSON(http.StatusNotFound, gin.H{"error": translate(c, domain.ErrExecutionNotFound.Error())})
+				return
+			}
+			runs[i] = exec
+		}
+
+		comparison, err := comparer.Compare(c.Request.Context(), runs[0], runs[1])
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"data": comparison})
+	}
+}
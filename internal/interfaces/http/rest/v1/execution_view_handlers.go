@@ -0,0 +1,142 @@
+package v1
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jaydeep/go-n8n/internal/application/execution"
+)
+
+// listExecutionViews lists the saved execution views of the user
+func listExecutionViews(svc *execution.ViewService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, ok := currentUserID(c)
+		if !ok {
+			return
+		}
+
+		views, err := svc.List(c.Request.Context(), userID)
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"data": views})
+	}
+}
+
+// createExecutionView saves a named filter over the executions list
+func createExecutionView(svc *execution.ViewService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !hasPermission(c, "workflow:read") {
+			c.JSON(http.StatusForbidden, gin.H{"error": translate(c, "insufficient permissions")})
+			return
+		}
+		userID, ok := currentUserID(c)
+		if !ok {
+			return
+		}
+
+		var input execution.ViewInput
+		if err := c.ShouldBindJSON(&input); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		v, err := svc.Create(c.Request.Context(), userID, input)
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+		c.JSON(http.StatusCreated, gin.H{"data": v})
+	}
+}
+
+// updateExecutionView replaces the name, filter and subscription of a view
+func updateExecutionView(svc *execution.ViewService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, ok := currentUserID(c)
+		if !ok {
+			return
+		}
+		id, ok := paramUUID(c, "id")
+		if !ok {
+			return
+		}
+
+		var input execution.ViewInput
+		if err := c.ShouldBindJSON(&input); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		v, err := svc.Update(c.Request.Context(), userID, id, input)
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"data": v})
+	}
+}
+
+// deleteExecutionView removes a view
+func deleteExecutionView(svc *execution.ViewService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, ok := currentUserID(c)
+		if !ok {
+			return
+		}
+		id, ok := paramUUID(c, "id")
+		if !ok {
+			return
+		}
+
+		if err := svc.Delete(c.Request.Context(), userID, id); err != nil {
+			respondError(c, err)
+			return
+		}
+		c.Status(http.StatusNoContent)
+	}
+}
+
+// listExecutionViewExecutions lists the executions a view selects, newest
+// first, in pages like the executions list
+func listExecutionViewExecutions(views *execution.ViewService, executions *execution.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !hasPermission(c, "workflow:read") {
+			c.JSON(http.StatusForbidden, gin.H{"error": translate(c, "insufficient permissions")})
+			return
+		}
+		userID, ok := currentUserID(c)
+		if !ok {
+			return
+		}
+		id, ok := paramUUID(c, "id")
+		if !ok {
+			return
+		}
+		q, ok := parseListQuery(c)
+		if !ok {
+			return
+		}
+
+		v, err := views.Get(c.Request.Context(), userID, id)
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+		filter := v.Filter.ListFilter(time.Now())
+		filter.After = q.Cursor
+		filter.Limit = q.Limit
+		if !isAdmin(c) {
+			filter.UserID = &userID
+		}
+
+		list, next, err := executions.List(c.Request.Context(), filter)
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+		respondPage(c, list, next)
+	}
+}
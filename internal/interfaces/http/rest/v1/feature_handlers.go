@@ -0,0 +1,142 @@
+package v1
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jaydeep/go-n8n/internal/application/feature"
+	domain "github.com/jaydeep/go-n8n/internal/domain/feature"
+)
+
+// getFeatures reports which feature flags are enabled for the caller
+func getFeatures(svc *feature.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, ok := currentUserID(c)
+		if !ok {
+			return
+		}
+
+		flags, err := svc.Evaluate(c.Request.Context(), userID)
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"data": flags})
+	}
+}
+
+// listFeatureFlags lists every flag with its targets
+func listFeatureFlags(svc *feature.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		flags, err := svc.List(c.Request.Context())
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"data": flags, "count": len(flags)})
+	}
+}
+
+// getFeatureFlag returns a flag with its targets
+func getFeatureFlag(svc *feature.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		flag, err := svc.Get(c.Request.Context(), c.Param("key"))
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"data": flag})
+	}
+}
+
+// updateFeatureFlag creates or changes a flag
+func updateFeatureFlag(svc *feature.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		actor, ok := actorFrom(c)
+		if !ok {
+			return
+		}
+
+		var input feature.FlagInput
+		if err := c.ShouldBindJSON(&input); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		flag, err := svc.Update(c.Request.Context(), actor, c.Param("key"), input)
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"data": flag})
+	}
+}
+
+// deleteFeatureFlag removes a flag; built-in flags return to their defaults
+func deleteFeatureFlag(svc *feature.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		actor, ok := actorFrom(c)
+		if !ok {
+			return
+		}
+
+		if err := svc.Delete(c.Request.Context(), actor, c.Param("key")); err != nil {
+			respondError(c, err)
+			return
+		}
+		c.Status(http.StatusNoContent)
+	}
+}
+
+// featureTargetRequest turns a flag on or off for a target
+type featureTargetRequest struct {
+	Enabled *bool `json:"enabled" binding:"required"`
+}
+
+// setFeatureFlagTarget turns a flag on or off for one user or team
+func setFeatureFlagTarget(svc *feature.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		actor, ok := actorFrom(c)
+		if !ok {
+			return
+		}
+		targetID, ok := paramUUID(c, "targetId")
+		if !ok {
+			return
+		}
+
+		var req featureTargetRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		flag, err := svc.SetTarget(c.Request.Context(), actor, c.Param("key"), domain.TargetType(c.Param("type")), targetID, *req.Enabled)
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"data": flag})
+	}
+}
+
+// removeFeatureFlagTarget removes the override of a flag for one user or team
+func removeFeatureFlagTarget(svc *feature.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		actor, ok := actorFrom(c)
+		if !ok {
+			return
+		}
+		targetID, ok := paramUUID(c, "targetId")
+		if !ok {
+			return
+		}
+
+		flag, err := svc.RemoveTarget(c.Request.Context(), actor, c.Param("key"), domain.TargetType(c.Param("type")), targetID)
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"data": flag})
+	}
+}
@@ -0,0 +1,44 @@
+package v1
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jaydeep/go-n8n/internal/domain/user"
+	"github.com/jaydeep/go-n8n/internal/interfaces/graphql"
+)
+
+// serveGraphQL executes a management plane query for the authenticated
+// user. POST takes a JSON body; GET takes query, operationName and
+// variables (JSON-encoded) as query parameters.
+func serveGraphQL(server *graphql.Server) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, ok := currentUserID(c)
+		if !ok {
+			return
+		}
+
+		var req graphql.Request
+		if c.Request.Method == http.MethodGet {
+			req.Query = c.Query("query")
+			req.OperationName = c.Query("operationName")
+			if raw := c.Query("variables"); raw != "" {
+				if err := json.Unmarshal([]byte(raw), &req.Variables); err != nil {
+					c.JSON(http.StatusBadRequest, gin.H{"error": translate(c, "variables must be a JSON object")})
+					return
+				}
+			}
+		} else if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if req.Query == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": translate(c, "query is required")})
+			return
+		}
+
+		viewer := graphql.Viewer{UserID: userID, Role: user.Role(c.GetString("Role"))}
+		c.JSON(http.StatusOK, server.Execute(c.Request.Context(), viewer, req))
+	}
+}
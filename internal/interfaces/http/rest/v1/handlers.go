@@ -22,10 +22,6 @@ func verify2FAHandler(c *gin.Context) {
 }
 
 // Workflow handlers
-func testWorkflow(c *gin.Context) {
-	c.JSON(501, gin.H{"error": "not implemented"})
-}
-
 func getWorkflowNodes(c *gin.Context) {
 	c.JSON(501, gin.H{"error": "not implemented"})
 }
@@ -42,27 +38,11 @@ func importWorkflow(c *gin.Context) {
 	c.JSON(501, gin.H{"error": "not implemented"})
 }
 
-func getWorkflowStatistics(c *gin.Context) {
-	c.JSON(501, gin.H{"error": "not implemented"})
-}
-
-func getWorkflowMetrics(c *gin.Context) {
-	c.JSON(501, gin.H{"error": "not implemented"})
-}
-
-func restoreWorkflowVersion(c *gin.Context) {
-	c.JSON(501, gin.H{"error": "not implemented"})
-}
-
 func batchWorkflowOperations(c *gin.Context) {
 	c.JSON(501, gin.H{"error": "not implemented"})
 }
 
 // Node handlers
-func getNodeSchema(c *gin.Context) {
-	c.JSON(501, gin.H{"error": "not implemented"})
-}
-
 func updateNode(c *gin.Context) {
 	c.JSON(501, gin.H{"error": "not implemented"})
 }
@@ -79,14 +59,6 @@ func getNodeExecutionData(c *gin.Context) {
 	c.JSON(501, gin.H{"error": "not implemented"})
 }
 
-func pinNodeData(c *gin.Context) {
-	c.JSON(501, gin.H{"error": "not implemented"})
-}
-
-func unpinNodeData(c *gin.Context) {
-	c.JSON(501, gin.H{"error": "not implemented"})
-}
-
 // Execution handlers
 func deleteMultipleExecutions(c *gin.Context) {
 	c.JSON(501, gin.H{"error": "not implemented"})
@@ -122,10 +94,6 @@ func updateSMTPSettings(c *gin.Context) {
 	c.JSON(501, gin.H{"error": "not implemented"})
 }
 
-func testSMTPSettings(c *gin.Context) {
-	c.JSON(501, gin.H{"error": "not implemented"})
-}
-
 // User handlers
 func updateUserSettings(c *gin.Context) {
 	c.JSON(501, gin.H{"error": "not implemented"})
@@ -214,35 +182,6 @@ func getWebhookURL(c *gin.Context) {
 	c.JSON(501, gin.H{"error": "not implemented"})
 }
 
-// Schedule handlers
-func listSchedules(c *gin.Context) {
-	c.JSON(501, gin.H{"error": "not implemented"})
-}
-
-func createSchedule(c *gin.Context) {
-	c.JSON(501, gin.H{"error": "not implemented"})
-}
-
-func getSchedule(c *gin.Context) {
-	c.JSON(501, gin.H{"error": "not implemented"})
-}
-
-func updateSchedule(c *gin.Context) {
-	c.JSON(501, gin.H{"error": "not implemented"})
-}
-
-func deleteSchedule(c *gin.Context) {
-	c.JSON(501, gin.H{"error": "not implemented"})
-}
-
-func activateSchedule(c *gin.Context) {
-	c.JSON(501, gin.H{"error": "not implemented"})
-}
-
-func deactivateSchedule(c *gin.Context) {
-	c.JSON(501, gin.H{"error": "not implemented"})
-}
-
 // Notification handlers
 func getNotifications(c *gin.Context) {
 	c.JSON(501, gin.H{"error": "not implemented"})
@@ -281,15 +220,6 @@ func searchExecutions(c *gin.Context) {
 	c.JSON(501, gin.H{"error": "not implemented"})
 }
 
-// Audit log handlers
-func listAuditLogs(c *gin.Context) {
-	c.JSON(501, gin.H{"error": "not implemented"})
-}
-
-func getAuditLog(c *gin.Context) {
-	c.JSON(501, gin.H{"error": "not implemented"})
-}
-
 // Metrics handlers
 func getMetrics(c *gin.Context) {
 	c.JSON(501, gin.H{"error": "not implemented"})
@@ -303,10 +233,6 @@ func getExecutionStatistics(c *gin.Context) {
 	c.JSON(501, gin.H{"error": "not implemented"})
 }
 
-func getWorkerStatus(c *gin.Context) {
-	c.JSON(501, gin.H{"error": "not implemented"})
-}
-
 func getPerformanceMetrics(c *gin.Context) {
 	c.JSON(501, gin.H{"error": "not implemented"})
 }
@@ -404,10 +330,6 @@ func updateTeamMemberRole(c *gin.Context) {
 }
 
 // Billing handlers
-func getUsageStatistics(c *gin.Context) {
-	c.JSON(501, gin.H{"error": "not implemented"})
-}
-
 func getBillingInfo(c *gin.Context) {
 	c.JSON(501, gin.H{"error": "not implemented"})
 }
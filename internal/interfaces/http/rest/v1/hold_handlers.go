@@ -0,0 +1,91 @@
+package v1
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jaydeep/go-n8n/internal/application/execution"
+	"github.com/jaydeep/go-n8n/internal/application/workflow"
+)
+
+// listWorkflowHolds returns the legal holds of a workflow, released ones
+// included
+func listWorkflowHolds(workflows *workflow.Service, holds *execution.HoldService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !isAdmin(c) {
+			c.JSON(http.StatusForbidden, gin.H{"error": translate(c, "insufficient permissions")})
+			return
+		}
+		w, ok := loadWorkflow(c, workflows)
+		if !ok {
+			return
+		}
+
+		list, err := holds.List(c.Request.Context(), w.ID)
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"data": list})
+	}
+}
+
+// placeWorkflowHold puts the executions of a workflow on legal hold
+func placeWorkflowHold(workflows *workflow.Service, holds *execution.HoldService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !isAdmin(c) {
+			c.JSON(http.StatusForbidden, gin.H{"error": translate(c, "insufficient permissions")})
+			return
+		}
+		actor, ok := actorFrom(c)
+		if !ok {
+			return
+		}
+		w, ok := loadWorkflow(c, workflows)
+		if !ok {
+			return
+		}
+
+		var input execution.HoldInput
+		if err := c.ShouldBindJSON(&input); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		hold, err := holds.Place(c.Request.Context(), actor, w.ID, input)
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+		c.JSON(http.StatusCreated, gin.H{"data": hold})
+	}
+}
+
+// releaseWorkflowHold lifts a legal hold of a workflow
+func releaseWorkflowHold(workflows *workflow.Service, holds *execution.HoldService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !isAdmin(c) {
+			c.JSON(http.StatusForbidden, gin.H{"error": translate(c, "insufficient permissions")})
+			return
+		}
+		actor, ok := actorFrom(c)
+		if !ok {
+			return
+		}
+		w, ok := loadWorkflow(c, workflows)
+		if !ok {
+			return
+		}
+		holdID, ok := paramUUID(c, "holdId")
+		if !ok {
+			return
+		}
+
+		hold, err := holds.Release(c.Request.Context(), actor, w.ID, holdID)
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"data": hold})
+	}
+}
@@ -0,0 +1,196 @@
+package v1
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jaydeep/go-n8n/internal/application/instancehook"
+	domain "github.com/jaydeep/go-n8n/internal/domain/instancehook"
+)
+
+// listInstanceWebhooks lists the outgoing webhooks of the instance
+func listInstanceWebhooks(svc *instancehook.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		webhooks, err := svc.List(c.Request.Context())
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"data": webhooks, "events": domain.Events})
+	}
+}
+
+// getInstanceWebhook returns one outgoing webhook
+func getInstanceWebhook(svc *instancehook.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, ok := paramUUID(c, "id")
+		if !ok {
+			return
+		}
+
+		w, err := svc.Get(c.Request.Context(), id)
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"data": w})
+	}
+}
+
+// createInstanceWebhook registers an outgoing webhook. The signing secret
+// is only returned here and when rotated.
+func createInstanceWebhook(svc *instancehook.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		actor, ok := actorFrom(c)
+		if !ok {
+			return
+		}
+
+		var input instancehook.Input
+		if err := c.ShouldBindJSON(&input); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		w, secret, err := svc.Create(c.Request.Context(), actor, input)
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+		c.JSON(http.StatusCreated, gin.H{"data": w, "secret": secret})
+	}
+}
+
+// updateInstanceWebhook changes an outgoing webhook
+func updateInstanceWebhook(svc *instancehook.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		actor, ok := actorFrom(c)
+		if !ok {
+			return
+		}
+		id, ok := paramUUID(c, "id")
+		if !ok {
+			return
+		}
+
+		var input instancehook.UpdateInput
+		if err := c.ShouldBindJSON(&input); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		w, err := svc.Update(c.Request.Context(), actor, id, input)
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"data": w})
+	}
+}
+
+// deleteInstanceWebhook removes an outgoing webhook and its delivery log
+func deleteInstanceWebhook(svc *instancehook.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		actor, ok := actorFrom(c)
+		if !ok {
+			return
+		}
+		id, ok := paramUUID(c, "id")
+		if !ok {
+			return
+		}
+
+		if err := svc.Delete(c.Request.Context(), actor, id); err != nil {
+			respondError(c, err)
+			return
+		}
+		c.Status(http.StatusNoContent)
+	}
+}
+
+// rotateInstanceWebhookSecret replaces the signing secret of a webhook
+func rotateInstanceWebhookSecret(svc *instancehook.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		actor, ok := actorFrom(c)
+		if !ok {
+			return
+		}
+		id, ok := paramUUID(c, "id")
+		if !ok {
+			return
+		}
+
+		w, secret, err := svc.RotateSecret(c.Request.Context(), actor, id)
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"data": w, "secret": secret})
+	}
+}
+
+// pingInstanceWebhook queues a test delivery
+func pingInstanceWebhook(svc *instancehook.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, ok := paramUUID(c, "id")
+		if !ok {
+			return
+		}
+
+		d, err := svc.Ping(c.Request.Context(), id)
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+		c.JSON(http.StatusAccepted, gin.H{"data": d})
+	}
+}
+
+// listInstanceWebhookDeliveries returns the delivery log of a webhook,
+// newest first
+func listInstanceWebhookDeliveries(svc *instancehook.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, ok := paramUUID(c, "id")
+		if !ok {
+			return
+		}
+		var limit int
+		if raw := c.Query("limit"); raw != "" {
+			n, err := strconv.Atoi(raw)
+			if err != nil || n <= 0 {
+				c.JSON(http.StatusBadRequest, gin.H{"error": translate(c, "limit must be a positive integer")})
+				return
+			}
+			limit = n
+		}
+
+		deliveries, err := svc.Deliveries(c.Request.Context(), id, domain.DeliveryStatus(c.Query("status")), limit)
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"data": deliveries, "count": len(deliveries)})
+	}
+}
+
+// redeliverInstanceWebhookDelivery sends a delivery again
+func redeliverInstanceWebhookDelivery(svc *instancehook.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, ok := paramUUID(c, "id")
+		if !ok {
+			return
+		}
+		deliveryID, ok := paramUUID(c, "deliveryId")
+		if !ok {
+			return
+		}
+
+		d, err := svc.Redeliver(c.Request.Context(), id, deliveryID)
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+		c.JSON(http.StatusAccepted, gin.H{"data": d})
+	}
+}
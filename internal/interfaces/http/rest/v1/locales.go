@@ -0,0 +1,8 @@
+package v1
+
+import "embed"
+
+// Locales holds the translations of API messages, keyed by English text
+//
+//go:embed locales/*.json
+var Locales embed.FS
@@ -0,0 +1,38 @@
+package v1
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jaydeep/go-n8n/internal/application/maintenance"
+)
+
+// getMaintenance returns whether the instance is in maintenance mode
+func getMaintenance(svc *maintenance.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"data": svc.State(c.Request.Context())})
+	}
+}
+
+// setMaintenance turns maintenance mode on or off
+func setMaintenance(svc *maintenance.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		actor, ok := actorFrom(c)
+		if !ok {
+			return
+		}
+
+		var input maintenance.Input
+		if err := c.ShouldBindJSON(&input); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		state, err := svc.Set(c.Request.Context(), actor, input)
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"data": state})
+	}
+}
@@ -0,0 +1,74 @@
+package v1
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jaydeep/go-n8n/internal/application/engine"
+	"github.com/jaydeep/go-n8n/internal/application/worker"
+	"github.com/jaydeep/go-n8n/pkg/pool"
+)
+
+// listStuckExecutions lists running executions that exceeded MaxExecutionTime
+func listStuckExecutions(watchdog *engine.Watchdog) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+
+		stuck, err := watchdog.Stuck(ctx)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list stuck executions"})
+			return
+		}
+
+		running, err := watchdog.Running(ctx)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to count running executions"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"data":                  stuck,
+			"count":                 len(stuck),
+			"running":               running,
+			"max_execution_time_ms": watchdog.MaxExecutionTime().Milliseconds(),
+		})
+	}
+}
+
+// getWorkerStatus lists the workers that are currently heartbeating
+func getWorkerStatus(registry *worker.Registry) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		workers, err := registry.Live(c.Request.Context())
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list workers"})
+			return
+		}
+
+		capacity, active := 0, 0
+		for _, w := range workers {
+			capacity += w.Concurrency
+			active += w.ActiveJobs
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"data":        workers,
+			"count":       len(workers),
+			"capacity":    capacity,
+			"active_jobs": active,
+		})
+	}
+}
+
+// getPoolStats reports the clients nodes share per credential
+func getPoolStats(p *pool.Pool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"data": p.Stats()})
+	}
+}
+
+// getLoad reports the latest load sample and whether executions are shed
+func getLoad(monitor *engine.LoadMonitor) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"data": monitor.Load()})
+	}
+}
@@ -0,0 +1,88 @@
+package v1
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jaydeep/go-n8n/internal/application/migration"
+	"github.com/jaydeep/go-n8n/internal/domain/workflow"
+)
+
+// listMigrationTargets returns the instances workflows can be pushed to
+func listMigrationTargets(svc *migration.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"data": svc.Targets()})
+	}
+}
+
+// exportMigration returns a migration package of the selected workflows,
+// for import into another instance
+func exportMigration(svc *migration.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		actor, ok := actorFrom(c)
+		if !ok {
+			return
+		}
+
+		var input migration.ExportInput
+		if err := c.ShouldBindJSON(&input); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		pkg, err := svc.Export(c.Request.Context(), actor, input)
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"data": pkg})
+	}
+}
+
+// importMigration stores the workflows of a migration package exported or
+// pushed by another instance
+func importMigration(svc *migration.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		actor, ok := actorFrom(c)
+		if !ok {
+			return
+		}
+
+		var request workflow.MigrationRequest
+		if err := c.ShouldBindJSON(&request); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		result, err := svc.Import(c.Request.Context(), actor, &request)
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"data": result})
+	}
+}
+
+// pushMigration exports the selected workflows and imports them into a
+// configured target, returning the target's import result
+func pushMigration(svc *migration.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		actor, ok := actorFrom(c)
+		if !ok {
+			return
+		}
+
+		var input migration.PushInput
+		if err := c.ShouldBindJSON(&input); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		result, err := svc.Push(c.Request.Context(), actor, input)
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"data": result})
+	}
+}
@@ -0,0 +1,83 @@
+package v1
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jaydeep/go-n8n/configs"
+	"github.com/jaydeep/go-n8n/internal/application/worker"
+	"github.com/jaydeep/go-n8n/internal/interfaces/http/middleware"
+	"github.com/jaydeep/go-n8n/pkg/logger"
+)
+
+// prometheusContentType is the content type of the Prometheus text format
+const prometheusContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// NewMonitoringRouter creates the router of the monitoring port, serving
+// Prometheus metrics at the configured path and the autoscaling signals
+// below it. It has no authentication, so the port must only be reachable
+// from within the cluster.
+func NewMonitoringRouter(cfg configs.MetricsConfig, log *logger.Logger, autoscaler *worker.Autoscaler) *gin.Engine {
+	path := cfg.Path
+	if path == "" {
+		path = "/metrics"
+	}
+
+	router := gin.New()
+	router.Use(gin.Recovery())
+	router.Use(middleware.Logger(log))
+	router.GET(path, getPrometheusMetrics(autoscaler))
+	router.GET(strings.TrimRight(path, "/")+"/autoscale", getAutoscaleSignals(autoscaler))
+	return router
+}
+
+// getAutoscaleSignals reports the backlog and suggested worker replicas of
+// each queue
+func getAutoscaleSignals(autoscaler *worker.Autoscaler) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		signals, err := autoscaler.Signals(c.Request.Context())
+		if err != nil {
+			c.Error(err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to collect autoscaling signals"})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"data": signals})
+	}
+}
+
+// getPrometheusMetrics exposes the autoscaling signals in the Prometheus
+// text format, for KEDA's Prometheus scaler and the HPA via an adapter
+func getPrometheusMetrics(autoscaler *worker.Autoscaler) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		signals, err := autoscaler.Signals(c.Request.Context())
+		if err != nil {
+			c.Error(err)
+			c.String(http.StatusInternalServerError, "failed to collect autoscaling signals\n")
+			return
+		}
+
+		var b strings.Builder
+		gauge := func(name, help string, value func(s worker.QueueSignal) float64) {
+			fmt.Fprintf(&b, "# HELP %s %s\n# TYPE %s gauge\n", name, help, name)
+			for _, s := range signals {
+				fmt.Fprintf(&b, "%s{queue=%q} %g\n", name, s.Queue, value(s))
+			}
+		}
+		gauge("n8n_queue_jobs_waiting", "Jobs waiting on the queue.",
+			func(s worker.QueueSignal) float64 { return float64(s.Waiting) })
+		gauge("n8n_queue_oldest_job_age_seconds", "How long the oldest job waiting on the queue has waited.",
+			func(s worker.QueueSignal) float64 { return s.LagSeconds })
+		gauge("n8n_queue_jobs_active", "Jobs of the queue being processed by live workers.",
+			func(s worker.QueueSignal) float64 { return float64(s.ActiveJobs) })
+		gauge("n8n_queue_workers", "Live workers consuming the queue.",
+			func(s worker.QueueSignal) float64 { return float64(s.Workers) })
+		gauge("n8n_queue_worker_capacity", "Jobs the live workers consuming the queue can process at once.",
+			func(s worker.QueueSignal) float64 { return float64(s.Capacity) })
+		gauge("n8n_queue_desired_replicas", "Worker replicas the waiting and active jobs of the queue call for.",
+			func(s worker.QueueSignal) float64 { return float64(s.DesiredReplicas) })
+
+		c.Data(http.StatusOK, prometheusContentType, []byte(b.String()))
+	}
+}
@@ -0,0 +1,116 @@
+package v1
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jaydeep/go-n8n/pkg/pagination"
+)
+
+const (
+	mimeNDJSON = "application/x-ndjson"
+	// ndjsonFlushEvery is how many rows are written between flushes
+	ndjsonFlushEvery = 100
+)
+
+// listQuery holds the paging and time range parameters shared by lists
+// and exports
+type listQuery struct {
+	Cursor *pagination.Cursor
+	Limit  int
+	From   *time.Time
+	To     *time.Time
+}
+
+// parseListQuery reads the cursor, limit, from and to query parameters,
+// responding with 400 when one is invalid
+func parseListQuery(c *gin.Context) (listQuery, bool) {
+	var q listQuery
+
+	cursor, err := pagination.Decode(c.Query("cursor"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": translate(c, err.Error())})
+		return q, false
+	}
+	q.Cursor = cursor
+
+	if raw := c.Query("limit"); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil || limit <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": translate(c, "limit must be a positive integer")})
+			return q, false
+		}
+		q.Limit = limit
+	}
+
+	for name, target := range map[string]**time.Time{"from": &q.From, "to": &q.To} {
+		raw := c.Query(name)
+		if raw == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": translate(c, "from and to must be RFC 3339 timestamps")})
+			return q, false
+		}
+		*target = &t
+	}
+	return q, true
+}
+
+// respondPage writes a page of a list with the cursor of the next page
+func respondPage(c *gin.Context, data interface{}, next *pagination.Cursor) {
+	resp := gin.H{"data": data}
+	if next != nil {
+		resp["next_cursor"] = next.Encode()
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// wantsNDJSON reports whether the client asked for a newline-delimited
+// JSON stream instead of a page
+func wantsNDJSON(c *gin.Context) bool {
+	return c.NegotiateFormat(gin.MIMEJSON, mimeNDJSON) == mimeNDJSON
+}
+
+// errStreamWrite marks a failed write to the client, which ends the
+// stream without an error line
+var errStreamWrite = errors.New("stream write failed")
+
+// streamNDJSON answers with one JSON object per line for every row export
+// passes to write. Rows are flushed as they are produced and the server
+// write timeout is lifted, so exports of any size run in constant memory.
+// The status is sent before the first row; an export failing midway ends
+// the stream with an {"error": ...} line so a truncated export can be told
+// apart from a complete one.
+func streamNDJSON(c *gin.Context, export func(write func(row interface{}) error) error) {
+	c.Header("Content-Type", mimeNDJSON)
+	c.Header("Cache-Control", "no-store")
+	c.Header("X-Content-Type-Options", "nosniff")
+	c.Status(http.StatusOK)
+	c.Writer.WriteHeaderNow()
+
+	http.NewResponseController(c.Writer).SetWriteDeadline(time.Time{})
+
+	enc := json.NewEncoder(c.Writer)
+	rows := 0
+	err := export(func(row interface{}) error {
+		if err := enc.Encode(row); err != nil {
+			return errStreamWrite
+		}
+		rows++
+		if rows%ndjsonFlushEvery == 0 {
+			c.Writer.Flush()
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errStreamWrite) && c.Request.Context().Err() == nil {
+		c.Error(err)
+		enc.Encode(gin.H{"error": translate(c, "export failed")})
+	}
+	c.Writer.Flush()
+}
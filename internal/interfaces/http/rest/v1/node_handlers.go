@@ -0,0 +1,132 @@
+package v1
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"sort"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jaydeep/go-n8n/internal/domain/node"
+	"github.com/jaydeep/go-n8n/pkg/nodesdk"
+)
+
+// nodeTypeSummary describes a node type in the node palette
+type nodeTypeSummary struct {
+	Type        string        `json:"type"`
+	Name        string        `json:"name"`
+	Category    node.Category `json:"category"`
+	Version     string        `json:"version"`
+	Description string        `json:"description"`
+	Icon        string        `json:"icon"`
+	IconURL     string        `json:"icon_url,omitempty"`
+}
+
+// localizedSchema returns the schema of a node type in the request language
+func localizedSchema(c *gin.Context, n node.NodeInterface) *node.NodeSchema {
+	return nodesdk.LocalizeSchema(n.GetSchema(), bundleFrom(c), c.GetString("Language"))
+}
+
+func summarizeNodeType(c *gin.Context, registry *node.NodeRegistry, n node.NodeInterface) nodeTypeSummary {
+	schema := localizedSchema(c, n)
+	summary := nodeTypeSummary{
+		Type:        n.GetType(),
+		Name:        schema.Name,
+		Category:    n.GetCategory(),
+		Version:     n.GetVersion(),
+		Description: schema.Description,
+		Icon:        n.GetIcon(),
+	}
+	if _, ok := registry.Icon(n.GetType()); ok {
+		summary.IconURL = "/api/v1/nodes/types/" + n.GetType() + "/icon"
+	}
+	return summary
+}
+
+// loadNodeType instantiates the node type named by the :type parameter
+func loadNodeType(c *gin.Context, registry *node.NodeRegistry) (node.NodeInterface, bool) {
+	constructor, err := registry.Get(c.Param("type"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": translate(c, "node type not found")})
+		return nil, false
+	}
+	return constructor(), true
+}
+
+// listNodeTypes lists the registered node types, optionally by category.
+// The node types of teams are listed with the teams.
+func listNodeTypes(registry *node.NodeRegistry) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		registrations := registry.List()
+		if category := c.Query("category"); category != "" {
+			registrations = registry.ListByCategory(node.Category(category))
+		}
+
+		types := make([]nodeTypeSummary, 0, len(registrations))
+		for _, reg := range registrations {
+			if reg.TeamID != nil {
+				continue
+			}
+			types = append(types, summarizeNodeType(c, registry, reg.Constructor()))
+		}
+		sort.Slice(types, func(i, j int) bool { return types[i].Type < types[j].Type })
+
+		c.JSON(http.StatusOK, gin.H{"data": types})
+	}
+}
+
+// getNodeType returns a node type with its schema
+func getNodeType(registry *node.NodeRegistry) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		n, ok := loadNodeType(c, registry)
+		if !ok {
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{
+			"data": gin.H{
+				"type":        summarizeNodeType(c, registry, n),
+				"schema":      localizedSchema(c, n),
+				"credentials": n.GetCredentialTypes(),
+				"defaults":    n.GetDefaultParameters(),
+			},
+		})
+	}
+}
+
+// getNodeSchema returns the schema of a node type
+func getNodeSchema(registry *node.NodeRegistry) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		n, ok := loadNodeType(c, registry)
+		if !ok {
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"data": localizedSchema(c, n)})
+	}
+}
+
+// getNodeTypeIcon serves the icon registered for a node type. Icons are
+// public so the editor can use them in <img> tags.
+func getNodeTypeIcon(registry *node.NodeRegistry) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		icon, ok := registry.Icon(c.Param("type"))
+		if !ok {
+			c.JSON(http.StatusNotFound, gin.H{"error": translate(c, "node icon not found")})
+			return
+		}
+
+		sum := sha256.Sum256(icon.Data)
+		etag := `"` + hex.EncodeToString(sum[:8]) + `"`
+		c.Header("ETag", etag)
+		// Custom node icons may change while developing, so revalidate daily
+		c.Header("Cache-Control", "public, max-age=86400")
+		c.Header("X-Content-Type-Options", "nosniff")
+		// SVGs may contain scripts; never let them run
+		c.Header("Content-Security-Policy", "default-src 'none'; style-src 'unsafe-inline'")
+
+		if c.GetHeader("If-None-Match") == etag {
+			c.Status(http.StatusNotModified)
+			return
+		}
+		c.Data(http.StatusOK, icon.ContentType, icon.Data)
+	}
+}
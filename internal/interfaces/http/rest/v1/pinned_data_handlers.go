@@ -0,0 +1,76 @@
+package v1
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jaydeep/go-n8n/internal/application/workflow"
+	"github.com/jaydeep/go-n8n/internal/domain/node"
+)
+
+// listPinnedData returns the data pinned to the nodes of a workflow
+func listPinnedData(workflows *workflow.Service, pinned *workflow.PinnedDataService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		w, ok := loadWorkflow(c, workflows)
+		if !ok {
+			return
+		}
+
+		list, err := pinned.List(c.Request.Context(), w.ID)
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"data": list})
+	}
+}
+
+// pinNodeData pins items as the output of a node of a workflow, which test
+// executions use in place of running the node
+func pinNodeData(workflows *workflow.Service, pinned *workflow.PinnedDataService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		actor, ok := actorFrom(c)
+		if !ok {
+			return
+		}
+		w, ok := loadWorkflow(c, workflows)
+		if !ok {
+			return
+		}
+
+		var input struct {
+			Items []node.Item `json:"items" binding:"required"`
+		}
+		if err := c.ShouldBindJSON(&input); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		data, err := pinned.Pin(c.Request.Context(), w, actor, c.Param("nodeId"), input.Items)
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"data": data})
+	}
+}
+
+// unpinNodeData removes the data pinned to a node of a workflow
+func unpinNodeData(workflows *workflow.Service, pinned *workflow.PinnedDataService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		actor, ok := actorFrom(c)
+		if !ok {
+			return
+		}
+		w, ok := loadWorkflow(c, workflows)
+		if !ok {
+			return
+		}
+
+		if err := pinned.Unpin(c.Request.Context(), w.ID, actor, c.Param("nodeId")); err != nil {
+			respondError(c, err)
+			return
+		}
+		c.Status(http.StatusNoContent)
+	}
+}
@@ -0,0 +1,317 @@
+package v1
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jaydeep/go-n8n/internal/application/audit"
+	"github.com/jaydeep/go-n8n/internal/application/chat"
+	"github.com/jaydeep/go-n8n/internal/domain/announcement"
+	auditlog "github.com/jaydeep/go-n8n/internal/domain/audit"
+	"github.com/jaydeep/go-n8n/internal/domain/billing"
+	"github.com/jaydeep/go-n8n/internal/domain/cleanup"
+	"github.com/jaydeep/go-n8n/internal/domain/credential"
+	"github.com/jaydeep/go-n8n/internal/domain/dashboard"
+	"github.com/jaydeep/go-n8n/internal/domain/endpoint"
+	"github.com/jaydeep/go-n8n/internal/domain/execution"
+	"github.com/jaydeep/go-n8n/internal/domain/feature"
+	"github.com/jaydeep/go-n8n/internal/domain/instancehook"
+	"github.com/jaydeep/go-n8n/internal/domain/schedule"
+	"github.com/jaydeep/go-n8n/internal/domain/settings"
+	"github.com/jaydeep/go-n8n/internal/domain/sla"
+	"github.com/jaydeep/go-n8n/internal/domain/teamnode"
+	"github.com/jaydeep/go-n8n/internal/domain/user"
+	"github.com/jaydeep/go-n8n/internal/domain/worker"
+	"github.com/jaydeep/go-n8n/internal/domain/workflow"
+	"github.com/jaydeep/go-n8n/pkg/encryption"
+	"github.com/jaydeep/go-n8n/pkg/i18n"
+)
+
+// currentUserID returns the ID of the authenticated user
+func currentUserID(c *gin.Context) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.GetString("UserID"))
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid user in token"})
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+// actorFrom describes the authenticated user for audit logging
+func actorFrom(c *gin.Context) (audit.Actor, bool) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return audit.Actor{}, false
+	}
+	return audit.Actor{
+		UserID:    userID,
+		IPAddress: c.ClientIP(),
+		UserAgent: c.Request.UserAgent(),
+	}, true
+}
+
+// hasPermission reports whether the authenticated user's role grants permission
+func hasPermission(c *gin.Context, permission string) bool {
+	u := user.User{Role: user.Role(c.GetString("Role"))}
+	return u.HasPermission(permission)
+}
+
+// isAdmin reports whether the authenticated user has an admin or owner role
+func isAdmin(c *gin.Context) bool {
+	role := c.GetString("Role")
+	return role == "admin" || role == "owner"
+}
+
+// canAccess reports whether the authenticated user may access a resource
+// owned by ownerID
+func canAccess(c *gin.Context, ownerID uuid.UUID) bool {
+	return isAdmin(c) || c.GetString("UserID") == ownerID.String()
+}
+
+// paramUUID parses a UUID path parameter, responding with 400 when invalid
+func paramUUID(c *gin.Context, name string) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param(name))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid " + name})
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+// errPreconditionFailed is the message of 412 responses
+const errPreconditionFailed = "resource has changed since it was last read"
+
+// contentETag quotes a content hash for the ETag header
+func contentETag(hash string) string {
+	return `"` + hash + `"`
+}
+
+// checkPreconditions evaluates the If-Match and If-None-Match headers
+// against the content hash of a resource, empty when it does not exist,
+// responding with 412 when they fail. Clients send the ETag they last read
+// in If-Match to only change what they have seen, and If-None-Match: * to
+// only create.
+func checkPreconditions(c *gin.Context, hash string) bool {
+	match := c.GetHeader("If-Match")
+	failed := match != "" && (hash == "" || (match != "*" && match != contentETag(hash)))
+	if c.GetHeader("If-None-Match") == "*" && hash != "" {
+		failed = true
+	}
+	if failed {
+		c.JSON(http.StatusPreconditionFailed, gin.H{"error": translate(c, errPreconditionFailed)})
+		return false
+	}
+	return true
+}
+
+// queryUUID parses an optional UUID query parameter, responding with 400
+// when invalid
+func queryUUID(c *gin.Context, name string) (*uuid.UUID, bool) {
+	raw := c.Query(name)
+	if raw == "" {
+		return nil, true
+	}
+	id, err := uuid.Parse(raw)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid " + name})
+		return nil, false
+	}
+	return &id, true
+}
+
+// bundleFrom returns the translations set by the Language middleware
+func bundleFrom(c *gin.Context) *i18n.Bundle {
+	bundle, _ := c.Get("I18n")
+	b, _ := bundle.(*i18n.Bundle)
+	return b
+}
+
+// translate returns an API message in the request language
+func translate(c *gin.Context, msg string) string {
+	return bundleFrom(c).T(c.GetString("Language"), msg, msg)
+}
+
+// respondError maps domain errors to HTTP responses
+func respondError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, workflow.ErrWorkflowNotFound),
+		errors.Is(err, workflow.ErrVersionNotFound),
+		errors.Is(err, workflow.ErrChangeRequestNotFound),
+		errors.Is(err, workflow.ErrSecretNotFound),
+		errors.Is(err, workflow.ErrPinnedDataNotFound),
+		errors.Is(err, workflow.ErrNodeNotFound),
+		errors.Is(err, workflow.ErrMigrationTargetNotFound),
+		errors.Is(err, chat.ErrSessionNotFound),
+		errors.Is(err, chat.ErrChatTriggerNotFound),
+		errors.Is(err, execution.ErrExecutionNotFound),
+		errors.Is(err, execution.ErrViewNotFound),
+		errors.Is(err, execution.ErrHoldNotFound),
+		errors.Is(err, credential.ErrCredentialNotFound),
+		errors.Is(err, worker.ErrWorkerNotFound),
+		errors.Is(err, schedule.ErrHolidayCalendarNotFound),
+		errors.Is(err, schedule.ErrScheduleNotFound),
+		errors.Is(err, endpoint.ErrEndpointNotFound),
+		errors.Is(err, endpoint.ErrDomainNotFound),
+		errors.Is(err, teamnode.ErrTeamNodeNotFound),
+		errors.Is(err, auditlog.ErrAuditLogNotFound),
+		errors.Is(err, feature.ErrFlagNotFound),
+		errors.Is(err, feature.ErrTargetNotFound),
+		errors.Is(err, announcement.ErrAnnouncementNotFound),
+		errors.Is(err, instancehook.ErrWebhookNotFound),
+		errors.Is(err, instancehook.ErrDeliveryNotFound),
+		errors.Is(err, sla.ErrPolicyNotFound),
+		errors.Is(err, user.ErrUserNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": translate(c, err.Error())})
+	case errors.Is(err, workflow.ErrWorkflowAlreadyActive),
+		errors.Is(err, workflow.ErrWorkflowNotActive),
+		errors.Is(err, workflow.ErrWorkflowNotPublished),
+		errors.Is(err, workflow.ErrWorkflowNoChanges),
+		errors.Is(err, workflow.ErrChangeRequestPending),
+		errors.Is(err, workflow.ErrChangeRequestNotPending),
+		errors.Is(err, workflow.ErrChangeRequestSelfApproval),
+		errors.Is(err, workflow.ErrTooManySecrets),
+		errors.Is(err, workflow.ErrWorkflowNameTaken),
+		errors.Is(err, workflow.ErrWorkflowSlugTaken),
+		errors.Is(err, workflow.ErrWorkflowIDTaken),
+		errors.Is(err, credential.ErrCredentialNameTaken),
+		errors.Is(err, credential.ErrCredentialSlugTaken),
+		errors.Is(err, execution.ErrViewNameTaken),
+		errors.Is(err, execution.ErrTooManyViews),
+		errors.Is(err, execution.ErrExecutionOnHold),
+		errors.Is(err, execution.ErrExecutionNotWaiting),
+		errors.Is(err, execution.ErrWaitNotResumable),
+		errors.Is(err, execution.ErrHoldReleased),
+		errors.Is(err, chat.ErrWorkflowInactive),
+		errors.Is(err, endpoint.ErrSlugTaken),
+		errors.Is(err, endpoint.ErrHostnameTaken),
+		errors.Is(err, teamnode.ErrSlugTaken):
+		c.JSON(http.StatusConflict, gin.H{"error": translate(c, err.Error())})
+	case errors.Is(err, workflow.ErrWorkflowNameRequired),
+		errors.Is(err, workflow.ErrWorkflowNodesRequired),
+		errors.Is(err, workflow.ErrWorkflowInvalid),
+		errors.Is(err, workflow.ErrNodeIDRequired),
+		errors.Is(err, workflow.ErrNodeTypeRequired),
+		errors.Is(err, workflow.ErrNodeNameRequired),
+		errors.Is(err, workflow.ErrNodeIDDuplicate),
+		errors.Is(err, workflow.ErrNodeConfigInvalid),
+		errors.Is(err, workflow.ErrNodeExecutionModeInvalid),
+		errors.Is(err, workflow.ErrNodeRetriesInvalid),
+		errors.Is(err, workflow.ErrNodeRetryWaitInvalid),
+		errors.Is(err, workflow.ErrNodeTimeoutInvalid),
+		errors.Is(err, workflow.ErrConnectionNodesRequired),
+		errors.Is(err, workflow.ErrConnectionSelfLoop),
+		errors.Is(err, workflow.ErrConnectionInvalid),
+		errors.Is(err, workflow.ErrConnectionDuplicate),
+		errors.Is(err, workflow.ErrWorkflowCycleDetected),
+		errors.Is(err, workflow.ErrChangeCommentEmpty),
+		errors.Is(err, workflow.ErrSecretNameInvalid),
+		errors.Is(err, workflow.ErrSecretValueRequired),
+		errors.Is(err, workflow.ErrSecretTooLarge),
+		errors.Is(err, workflow.ErrPinnedItemsRequired),
+		errors.Is(err, workflow.ErrPinnedDataTooLarge),
+		errors.Is(err, workflow.ErrInvalidWorkflowSlug),
+		errors.Is(err, workflow.ErrUnknownQueue),
+		errors.Is(err, workflow.ErrUnknownRegion),
+		errors.Is(err, workflow.ErrQueueOutsideRegion),
+		errors.Is(err, workflow.ErrUnsupportedMigration),
+		errors.Is(err, workflow.ErrMigrationWorkflowsMissing),
+		errors.Is(err, workflow.ErrInvalidMigrationConflict),
+		errors.Is(err, workflow.ErrInvalidImport),
+		errors.Is(err, chat.ErrMessageEmpty),
+		errors.Is(err, execution.ErrInvalidCallbackURL),
+		errors.Is(err, execution.ErrExecutionsNotComparable),
+		errors.Is(err, execution.ErrViewNameRequired),
+		errors.Is(err, execution.ErrViewNameTooLong),
+		errors.Is(err, execution.ErrInvalidViewStatus),
+		errors.Is(err, execution.ErrErrorTextTooLong),
+		errors.Is(err, execution.ErrInvalidViewRange),
+		errors.Is(err, execution.ErrInvalidViewPeriod),
+		errors.Is(err, execution.ErrHoldReasonRequired),
+		errors.Is(err, execution.ErrHoldReasonTooLong),
+		errors.Is(err, execution.ErrHoldReferenceTooLong),
+		errors.Is(err, credential.ErrCredentialNameRequired),
+		errors.Is(err, credential.ErrCredentialTypeRequired),
+		errors.Is(err, credential.ErrCredentialDataRequired),
+		errors.Is(err, credential.ErrInvalidCredentialSlug),
+		errors.Is(err, credential.ErrPassphraseTooShort),
+		errors.Is(err, credential.ErrUnsupportedBundle),
+		errors.Is(err, credential.ErrBundleDecrypt),
+		errors.Is(err, credential.ErrInvalidConflictPolicy),
+		errors.Is(err, encryption.ErrInvalidKDF),
+		errors.Is(err, settings.ErrUnknownSetting),
+		errors.Is(err, settings.ErrInvalidSettingValue),
+		errors.Is(err, schedule.ErrExpressionRequired),
+		errors.Is(err, schedule.ErrInvalidExpression),
+		errors.Is(err, schedule.ErrInvalidTimezone),
+		errors.Is(err, schedule.ErrTimezoneInline),
+		errors.Is(err, schedule.ErrNeverFires),
+		errors.Is(err, schedule.ErrInvalidRecurrence),
+		errors.Is(err, schedule.ErrScheduleRequired),
+		errors.Is(err, schedule.ErrHolidayCalendarNameRequired),
+		errors.Is(err, schedule.ErrInvalidJitter),
+		errors.Is(err, endpoint.ErrInvalidSlug),
+		errors.Is(err, endpoint.ErrInvalidRateLimit),
+		errors.Is(err, endpoint.ErrWebhookTriggerRequired),
+		errors.Is(err, endpoint.ErrInvalidRequest),
+		errors.Is(err, endpoint.ErrInvalidAllowedIPs),
+		errors.Is(err, endpoint.ErrInvalidHostname),
+		errors.Is(err, endpoint.ErrDomainNotVerified),
+		errors.Is(err, teamnode.ErrInvalidSpec),
+		errors.Is(err, teamnode.ErrInvalidSlug),
+		errors.Is(err, dashboard.ErrInvalidInterval),
+		errors.Is(err, dashboard.ErrInvalidRange),
+		errors.Is(err, dashboard.ErrRangeTooLarge),
+		errors.Is(err, billing.ErrInvalidGroupBy),
+		errors.Is(err, billing.ErrInvalidRange),
+		errors.Is(err, feature.ErrInvalidFlagKey),
+		errors.Is(err, feature.ErrInvalidRollout),
+		errors.Is(err, announcement.ErrTitleRequired),
+		errors.Is(err, announcement.ErrInvalidMessage),
+		errors.Is(err, announcement.ErrInvalidSeverity),
+		errors.Is(err, announcement.ErrExpiryInPast),
+		errors.Is(err, instancehook.ErrInvalidName),
+		errors.Is(err, instancehook.ErrInvalidURL),
+		errors.Is(err, instancehook.ErrEventsRequired),
+		errors.Is(err, instancehook.ErrUnknownEvent),
+		errors.Is(err, instancehook.ErrInvalidWorkflowID),
+		errors.Is(err, instancehook.ErrSecretTooShort),
+		errors.Is(err, sla.ErrNoObjectives),
+		errors.Is(err, sla.ErrInvalidObjective),
+		errors.Is(err, sla.ErrInvalidWindow),
+		errors.Is(err, cleanup.ErrKindsRequired),
+		errors.Is(err, cleanup.ErrUnknownKind),
+		errors.Is(err, cleanup.ErrInvalidInactiveDays):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": translate(c, err.Error())})
+	case errors.Is(err, schedule.ErrTeamAccessDenied),
+		errors.Is(err, schedule.ErrWorkflowAccessDenied),
+		errors.Is(err, endpoint.ErrEndpointAccessDenied),
+		errors.Is(err, endpoint.ErrTeamAccessDenied),
+		errors.Is(err, teamnode.ErrTeamAccessDenied),
+		errors.Is(err, endpoint.ErrNetworkNotAllowed),
+		errors.Is(err, dashboard.ErrTeamAccessDenied),
+		errors.Is(err, billing.ErrUsageAccessDenied):
+		c.JSON(http.StatusForbidden, gin.H{"error": translate(c, err.Error())})
+	case errors.Is(err, endpoint.ErrInvalidAPIKey):
+		c.JSON(http.StatusUnauthorized, gin.H{"error": translate(c, err.Error())})
+	case errors.Is(err, endpoint.ErrMethodNotAllowed):
+		c.JSON(http.StatusMethodNotAllowed, gin.H{"error": translate(c, err.Error())})
+	case errors.Is(err, endpoint.ErrRateLimited):
+		c.JSON(http.StatusTooManyRequests, gin.H{"error": translate(c, err.Error())})
+	case errors.Is(err, settings.ErrInvalidScope),
+		errors.Is(err, feature.ErrInvalidTargetType):
+		c.JSON(http.StatusBadRequest, gin.H{"error": translate(c, err.Error())})
+	case errors.Is(err, chat.ErrChatUnavailable),
+		errors.Is(err, workflow.ErrSecretsUnavailable),
+		errors.Is(err, credential.ErrEncryptionKeyRequired),
+		errors.Is(err, credential.ErrSecretsUnavailable),
+		errors.Is(err, endpoint.ErrEngineUnavailable):
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": translate(c, err.Error())})
+	case errors.Is(err, workflow.ErrMigrationTargetFailed):
+		c.JSON(http.StatusBadGateway, gin.H{"error": err.Error()})
+	default:
+		c.Error(err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": translate(c, "internal server error")})
+	}
+}
@@ -1,33 +1,182 @@
 package v1
 
 import (
+	"fmt"
+
 	"github.com/gin-gonic/gin"
 	"github.com/jaydeep/go-n8n/configs"
+	"github.com/jaydeep/go-n8n/internal/application/alerting"
+	"github.com/jaydeep/go-n8n/internal/application/announcement"
+	"github.com/jaydeep/go-n8n/internal/application/audit"
+	"github.com/jaydeep/go-n8n/internal/application/billing"
+	"github.com/jaydeep/go-n8n/internal/application/chat"
+	"github.com/jaydeep/go-n8n/internal/application/cleanup"
+	"github.com/jaydeep/go-n8n/internal/application/credential"
+	"github.com/jaydeep/go-n8n/internal/application/dashboard"
+	"github.com/jaydeep/go-n8n/internal/application/endpoint"
+	"github.com/jaydeep/go-n8n/internal/application/engine"
+	"github.com/jaydeep/go-n8n/internal/application/execution"
+	"github.com/jaydeep/go-n8n/internal/application/feature"
+	"github.com/jaydeep/go-n8n/internal/application/instancehook"
+	"github.com/jaydeep/go-n8n/internal/application/maintenance"
+	"github.com/jaydeep/go-n8n/internal/application/migration"
+	"github.com/jaydeep/go-n8n/internal/application/schedule"
+	"github.com/jaydeep/go-n8n/internal/application/settings"
+	"github.com/jaydeep/go-n8n/internal/application/sla"
+	"github.com/jaydeep/go-n8n/internal/application/teamnode"
+	"github.com/jaydeep/go-n8n/internal/application/user"
+	"github.com/jaydeep/go-n8n/internal/application/worker"
+	"github.com/jaydeep/go-n8n/internal/application/workflow"
+	"github.com/jaydeep/go-n8n/internal/application/workflowimport"
+	featuredomain "github.com/jaydeep/go-n8n/internal/domain/feature"
+	"github.com/jaydeep/go-n8n/internal/domain/node"
+	"github.com/jaydeep/go-n8n/internal/interfaces/graphql"
 	"github.com/jaydeep/go-n8n/internal/interfaces/http/middleware"
+	"github.com/jaydeep/go-n8n/internal/interfaces/websocket"
+	"github.com/jaydeep/go-n8n/pkg/binarydata"
 	"github.com/jaydeep/go-n8n/pkg/database"
+	"github.com/jaydeep/go-n8n/pkg/i18n"
 	"github.com/jaydeep/go-n8n/pkg/logger"
+	"github.com/jaydeep/go-n8n/pkg/netpolicy"
+	"github.com/jaydeep/go-n8n/pkg/pool"
 )
 
-// NewRouter creates and configures the main router
-func NewRouter(cfg *configs.Config, db *database.DB, log *logger.Logger) *gin.Engine {
+// Services holds the application services used by the HTTP handlers
+type Services struct {
+	Activity      *user.ActivityTracker
+	Alerts        *alerting.Monitor
+	Announcements *announcement.Service
+	Audit         *audit.Service
+	Autocomplete  *workflow.Autocomplete
+	Autoscaler    *worker.Autoscaler
+	Billing       *billing.Service
+	// BinaryData keeps the files received by endpoints; nil keeps them in
+	// the items
+	BinaryData binarydata.Store
+	Chat       *chat.Service
+	Cleanup    *cleanup.Service
+	Comparer   *execution.Comparer
+	// CredentialStore manages credentials, Credentials moves them between
+	// instances and CredentialHealth checks them
+	CredentialStore  *credential.Service
+	Credentials      *credential.Transfer
+	CredentialHealth *credential.HealthMonitor
+	Dashboards       *dashboard.Service
+	Dependencies     *workflow.DependencyGraph
+	Diagnostics      *engine.Diagnostics
+	Endpoints        *endpoint.Service
+	// EndpointBuffer is nil unless webhook buffering is enabled
+	EndpointBuffer *endpoint.Buffer
+	Executions     *execution.Service
+	ExecutionViews *execution.ViewService
+	Features       *feature.Service
+	InstanceHooks  *instancehook.Service
+	GraphQL        *graphql.Server
+	Holds          *execution.HoldService
+	Hub            *websocket.Hub
+	I18n           *i18n.Bundle
+	Languages      *user.LanguageResolver
+	Load           *engine.LoadMonitor
+	Maintenance    *maintenance.Service
+	Migrations     *migration.Service
+	Nodes          *node.NodeRegistry
+	PinnedData     *workflow.PinnedDataService
+	Pools          *pool.Pool
+	Schedules      *schedule.Service
+	Secrets        *workflow.SecretService
+	Settings       *settings.Service
+	SLAs           *sla.Service
+	Statistics     *engine.Statistics
+	TeamNodes      *teamnode.Service
+	Users          *user.Service
+	Watchdog       *engine.Watchdog
+	Workers        *worker.Registry
+	Workflows      *workflow.Service
+	// WorkflowImports creates workflows from documents of other tools
+	WorkflowImports *workflowimport.Service
+}
+
+// NewRouter creates and configures the main router. It fails when the
+// network configuration is invalid.
+func NewRouter(cfg *configs.Config, db *database.DB, log *logger.Logger, svc *Services) (*gin.Engine, error) {
 	// Set Gin mode based on environment
 	if cfg.App.Environment == "production" {
 		gin.SetMode(gin.ReleaseMode)
 	}
 
+	adminNetworks, err := netpolicy.Parse(cfg.Network.Allowlists.Admin)
+	if err != nil {
+		return nil, fmt.Errorf("network.allowlists.admin: %w", err)
+	}
+	apiKeyNetworks, err := netpolicy.Parse(cfg.Network.Allowlists.APIKeys)
+	if err != nil {
+		return nil, fmt.Errorf("network.allowlists.api_keys: %w", err)
+	}
+	webhookNetworks, err := netpolicy.Parse(cfg.Network.Allowlists.Webhooks)
+	if err != nil {
+		return nil, fmt.Errorf("network.allowlists.webhooks: %w", err)
+	}
+
+	verifier, err := middleware.NewTokenVerifier(cfg.JWT)
+	if err != nil {
+		return nil, fmt.Errorf("jwt: %w", err)
+	}
+	authenticate := middleware.Auth(verifier)
+
 	router := gin.New()
+	// Handlers passing the gin context on get the request's cancellation, so
+	// client disconnects stop their queries and outbound calls
+	router.ContextWithFallback = true
+
+	// Client IPs are taken from forwarding headers only when set by a
+	// trusted proxy, so clients cannot spoof the IP that rate limits,
+	// allowlists and audit logs see
+	if err := router.SetTrustedProxies(cfg.Server.TrustedProxies); err != nil {
+		return nil, fmt.Errorf("server.trusted_proxies: %w", err)
+	}
+	if len(cfg.Server.RemoteIPHeaders) > 0 {
+		router.RemoteIPHeaders = cfg.Server.RemoteIPHeaders
+	}
+	router.TrustedPlatform = cfg.Server.TrustedPlatform
 
 	// Global middleware
 	router.Use(gin.Recovery())
 	router.Use(middleware.Logger(log))
 	router.Use(middleware.RequestID())
-	router.Use(middleware.CORS(cfg.CORS))
+	router.Use(middleware.Correlation(cfg.Server.CorrelationHeader))
+	// Webhooks, endpoints and chats have CORS policies of their own
+	router.Use(middleware.CORS(cfg.CORS, "/api/v1/webhook/", "/api/v1/endpoints/", "/api/v1/chat/"))
+	router.Use(middleware.SecurityHeaders(cfg.Security.Headers))
+	router.Use(middleware.Language(svc.I18n))
+
+	// Custom domains of teams only serve the routes through which outside
+	// callers reach their workflows
+	if cfg.Webhook.CustomDomains.Enabled {
+		router.Use(middleware.CustomDomains(svc.Endpoints, "/api/v1/webhook/:path", "/api/v1/endpoints/:slug"))
+	}
 	
 	// Rate limiting
 	if cfg.RateLimit.Enabled {
 		router.Use(middleware.RateLimit(cfg.RateLimit))
 	}
 
+	// Requests starting executions are shed while the instance is
+	// overloaded; a nil monitor never sheds
+	shed := middleware.LoadShedding(svc.Load)
+
+	// Webhook, endpoint and chat responses are built by workflows and get
+	// their own framing and content security policy
+	webhookHeaders := middleware.WebhookSecurityHeaders(cfg.Security.Headers)
+	publicCORS := middleware.PublicCORS(func(*gin.Context) configs.CORSConfig { return cfg.Webhook.CORS })
+
+	// Streaming and webhook routes replace the JSON API timeouts
+	streamingDeadlines := middleware.Deadlines(cfg.Server.RouteTimeouts.Streaming)
+	webhookDeadlines := middleware.Deadlines(cfg.Server.RouteTimeouts.Webhooks)
+
+	// In maintenance mode the API is read-only and webhook intake is paused
+	readOnly := middleware.ReadOnly(svc.Maintenance)
+	pauseIntake := middleware.PauseIntake(svc.Maintenance)
+
 	// Health check endpoints
 	router.GET("/health", healthCheck)
 	router.GET("/ready", readinessCheck)
@@ -35,86 +184,171 @@ func NewRouter(cfg *configs.Config, db *database.DB, log *logger.Logger) *gin.En
 	// API v1 routes
 	v1 := router.Group("/api/v1")
 	{
-		// Public routes
+		// Public routes. Signing in stays available during maintenance.
 		auth := v1.Group("/auth")
 		{
-			auth.POST("/register", registerHandler)
+			auth.POST("/register", readOnly, registerHandler)
 			auth.POST("/login", loginHandler)
 			auth.POST("/refresh", refreshTokenHandler)
-			auth.POST("/forgot-password", forgotPasswordHandler)
-			auth.POST("/reset-password", resetPasswordHandler)
-			auth.POST("/verify-email", verifyEmailHandler)
+			auth.POST("/forgot-password", readOnly, forgotPasswordHandler)
+			auth.POST("/reset-password", readOnly, resetPasswordHandler)
+			auth.POST("/verify-email", readOnly, verifyEmailHandler)
 		}
 
 		// Webhook endpoints (public but validated)
-		v1.Any("/webhook/:path", webhookHandler)
+		v1.Any("/webhook/:path", webhookDeadlines, webhookHeaders, publicCORS, middleware.IPAllowlist(webhookNetworks), pauseIntake, shed, webhookHandler)
+
+		// Workflow endpoints (public, authenticated with the endpoint API key).
+		// With a buffer, calls are buffered instead of shed or paused.
+		endpointIntake := []gin.HandlerFunc{webhookDeadlines, webhookHeaders, middleware.PublicCORS(endpointCORS(svc.Endpoints, cfg.Webhook.CORS)), middleware.IPAllowlist(apiKeyNetworks)}
+		if svc.EndpointBuffer == nil {
+			endpointIntake = append(endpointIntake, pauseIntake, shed)
+		}
+		endpointIntake = append(endpointIntake, invokeEndpoint(svc.Endpoints, svc.EndpointBuffer, svc.BinaryData, cfg.Webhook))
+		v1.Any("/endpoints/:slug", endpointIntake...)
+
+		// Node icons (public so they can be used in <img> tags)
+		v1.GET("/nodes/types/:type/icon", getNodeTypeIcon(svc.Nodes))
+
+		// Chat trigger endpoints (public, like webhooks)
+		chatRoutes := v1.Group("/chat/:workflowId", webhookHeaders, publicCORS)
+		{
+			chatRoutes.OPTIONS("", preflight)
+			chatRoutes.GET("", getChatSession(svc.Chat))
+			chatRoutes.POST("", pauseIntake, shed, sendChatMessage(svc.Chat))
+			chatRoutes.GET("/ws", streamingDeadlines, chatWebSocket(svc.Chat))
+		}
+
+		// Maintenance mode, public so clients can show it before signing in
+		v1.GET("/maintenance", getMaintenance(svc.Maintenance))
+
+		// Toggled outside the read-only routes so it can be turned off
+		maintenanceAdmin := v1.Group("/admin/maintenance")
+		maintenanceAdmin.Use(authenticate)
+		maintenanceAdmin.Use(middleware.IPAllowlist(adminNetworks))
+		maintenanceAdmin.Use(middleware.RequireRole("admin"))
+		{
+			maintenanceAdmin.GET("", getMaintenance(svc.Maintenance))
+			maintenanceAdmin.PUT("", setMaintenance(svc.Maintenance))
+		}
 
 		// Protected routes
 		protected := v1.Group("/")
-		protected.Use(middleware.Auth(cfg.JWT))
+		protected.Use(authenticate)
+		protected.Use(middleware.UserLanguage(svc.Languages.Language))
+		protected.Use(middleware.TrackActivity(svc.Activity.Seen))
+		protected.Use(readOnly)
 		{
 			// User routes
 			protected.GET("/auth/me", getCurrentUser)
 			protected.PUT("/auth/me", updateCurrentUser)
 			protected.POST("/auth/logout", logoutHandler)
 			protected.POST("/auth/change-password", changePasswordHandler)
-			protected.POST("/auth/2fa/enable", enable2FAHandler)
-			protected.POST("/auth/2fa/disable", disable2FAHandler)
-			protected.POST("/auth/2fa/verify", verify2FAHandler)
+			twoFactor := protected.Group("/auth/2fa")
+			twoFactor.Use(middleware.RequireFeature(svc.Features, featuredomain.KeyTwoFactorAuth))
+			{
+				twoFactor.POST("/enable", enable2FAHandler)
+				twoFactor.POST("/disable", disable2FAHandler)
+				twoFactor.POST("/verify", verify2FAHandler)
+			}
+
+			// Feature flags enabled for the caller
+			protected.GET("/features", getFeatures(svc.Features))
+
+			// Banners published by admins
+			protected.GET("/announcements", getAnnouncements(svc.Announcements))
 
 			// Workflow routes
 			workflows := protected.Group("/workflows")
 			{
 				workflows.GET("", listWorkflows)
 				workflows.POST("", createWorkflow)
-				workflows.GET("/:id", getWorkflow)
-				workflows.PUT("/:id", updateWorkflow)
-				workflows.DELETE("/:id", deleteWorkflow)
-				workflows.POST("/:id/activate", activateWorkflow)
-				workflows.POST("/:id/deactivate", deactivateWorkflow)
-				workflows.POST("/:id/execute", executeWorkflow)
+				workflows.GET("/:id", getWorkflow(svc.Workflows))
+				workflows.PUT("/:id", updateWorkflow(svc.Workflows))
+				workflows.DELETE("/:id", deleteWorkflow(svc.Workflows))
+				workflows.GET("/:id/hash", getWorkflowHash(svc.Workflows))
+				workflows.GET("/:id/dependencies", getWorkflowDependencies(svc.Workflows, svc.Dependencies))
+				workflows.GET("/:id/dependents", getWorkflowDependents(svc.Workflows, svc.Dependencies))
+				workflows.GET("/by-slug/:slug", getWorkflowBySlug(svc.Workflows))
+				workflows.PUT("/by-slug/:slug", putWorkflowBySlug(svc.Workflows))
+				workflows.POST("/:id/activate", activateWorkflow(svc.Workflows))
+				workflows.POST("/:id/deactivate", deactivateWorkflow(svc.Workflows))
+				workflows.POST("/:id/publish", publishWorkflow(svc.Workflows))
+				workflows.POST("/:id/execute", shed, executeWorkflow(svc.Workflows, svc.Executions, cfg.Engine))
+				workflows.GET("/:id/callback-secret", getCallbackSecret(svc.Workflows, cfg.Callbacks))
 				workflows.POST("/:id/duplicate", duplicateWorkflow)
 				workflows.GET("/:id/executions", getWorkflowExecutions)
 				workflows.POST("/:id/share", shareWorkflow)
-				workflows.GET("/:id/versions", getWorkflowVersions)
-				workflows.POST("/:id/test", testWorkflow)
+				workflows.GET("/:id/versions", getWorkflowVersions(svc.Workflows))
+				workflows.POST("/:id/test", shed, testWorkflow(svc.Workflows, svc.Executions, cfg.Engine))
 				workflows.GET("/:id/nodes", getWorkflowNodes)
 				workflows.PUT("/:id/nodes", updateWorkflowNodes)
+				workflows.GET("/:id/nodes/:nodeId/expression-context", getExpressionContext(svc.Workflows, svc.Autocomplete))
+				workflows.GET("/:id/pinned-data", listPinnedData(svc.Workflows, svc.PinnedData))
+				workflows.PUT("/:id/nodes/:nodeId/pin", pinNodeData(svc.Workflows, svc.PinnedData))
+				workflows.DELETE("/:id/nodes/:nodeId/pin", unpinNodeData(svc.Workflows, svc.PinnedData))
 				workflows.GET("/:id/export", exportWorkflow)
 				workflows.POST("/import", importWorkflow)
-				workflows.GET("/:id/statistics", getWorkflowStatistics)
-				workflows.GET("/:id/metrics", getWorkflowMetrics)
-				workflows.POST("/:id/versions/:versionId/restore", restoreWorkflowVersion)
+				workflows.POST("/import/postman", importPostmanCollection(svc.WorkflowImports))
+				workflows.POST("/import/zapier", importZapierExport(svc.WorkflowImports))
+				workflows.POST("/import/make", importMakeScenario(svc.WorkflowImports))
+				workflows.GET("/:id/statistics", getWorkflowStatistics(svc.Workflows, svc.Statistics))
+				workflows.GET("/:id/metrics", getWorkflowMetrics(svc.Workflows, svc.SLAs))
+				workflows.GET("/:id/sla", getWorkflowSLA(svc.Workflows, svc.SLAs))
+				workflows.PUT("/:id/sla", setWorkflowSLA(svc.Workflows, svc.SLAs))
+				workflows.DELETE("/:id/sla", deleteWorkflowSLA(svc.Workflows, svc.SLAs))
+				workflows.GET("/:id/secrets", listWorkflowSecrets(svc.Workflows, svc.Secrets))
+				workflows.PUT("/:id/secrets/:name", setWorkflowSecret(svc.Workflows, svc.Secrets))
+				workflows.DELETE("/:id/secrets/:name", deleteWorkflowSecret(svc.Workflows, svc.Secrets))
+				workflows.GET("/:id/holds", listWorkflowHolds(svc.Workflows, svc.Holds))
+				workflows.POST("/:id/holds", placeWorkflowHold(svc.Workflows, svc.Holds))
+				workflows.POST("/:id/holds/:holdId/release", releaseWorkflowHold(svc.Workflows, svc.Holds))
+				workflows.POST("/:id/versions/:versionId/restore", restoreWorkflowVersion(svc.Workflows))
+				workflows.GET("/:id/change-requests", listChangeRequests(svc.Workflows))
+				workflows.POST("/:id/change-requests/:requestId/comments", commentChangeRequest(svc.Workflows))
+				workflows.POST("/:id/change-requests/:requestId/approve", approveChangeRequest(svc.Workflows))
+				workflows.POST("/:id/change-requests/:requestId/reject", rejectChangeRequest(svc.Workflows))
 				workflows.POST("/batch", batchWorkflowOperations)
 			}
 
 			// Node routes
 			nodes := protected.Group("/nodes")
 			{
-				nodes.GET("/types", listNodeTypes)
-				nodes.GET("/types/:type", getNodeType)
-				nodes.GET("/types/:type/schema", getNodeSchema)
+				nodes.GET("/types", listNodeTypes(svc.Nodes))
+				nodes.GET("/types/:type", getNodeType(svc.Nodes))
+				nodes.GET("/types/:type/schema", getNodeSchema(svc.Nodes))
 				nodes.POST("/test", testNode)
 				nodes.PUT("/:id", updateNode)
 				nodes.DELETE("/:id", deleteNode)
 				nodes.POST("/:id/test", testNodeById)
 				nodes.GET("/:id/executions/:executionId/data", getNodeExecutionData)
-				nodes.POST("/:id/pin", pinNodeData)
-				nodes.DELETE("/:id/pin", unpinNodeData)
 			}
 
 			// Execution routes
 			executions := protected.Group("/executions")
 			{
-				executions.GET("", listExecutions)
+				executions.GET("", listExecutions(svc.Executions))
 				executions.GET("/:id", getExecution)
 				executions.POST("/:id/stop", stopExecution)
-				executions.POST("/:id/retry", retryExecution)
-				executions.DELETE("/:id", deleteExecution)
+				executions.POST("/:id/retry", shed, retryExecution)
+				executions.POST("/:id/resume", shed, resumeExecution(svc.Workflows, svc.Executions))
+				executions.DELETE("/:id", deleteExecution(svc.Workflows, svc.Executions))
 				executions.GET("/:id/data", getExecutionData)
 				executions.POST("/delete", deleteMultipleExecutions)
 				executions.GET("/:id/logs", getExecutionLogs)
 				executions.GET("/:id/timeline", getExecutionTimeline)
+				executions.GET("/:id/lineage", getExecutionLineage(svc.Workflows, svc.Executions))
+				executions.GET("/:id/compare/:otherId", compareExecutions(svc.Workflows, svc.Executions, svc.Comparer))
+			}
+
+			// Saved execution views
+			executionViews := protected.Group("/execution-views")
+			{
+				executionViews.GET("", listExecutionViews(svc.ExecutionViews))
+				executionViews.POST("", createExecutionView(svc.ExecutionViews))
+				executionViews.PUT("/:id", updateExecutionView(svc.ExecutionViews))
+				executionViews.DELETE("/:id", deleteExecutionView(svc.ExecutionViews))
+				executionViews.GET("/:id/executions", listExecutionViewExecutions(svc.ExecutionViews, svc.Executions))
 			}
 
 			// Credential routes
@@ -122,10 +356,14 @@ func NewRouter(cfg *configs.Config, db *database.DB, log *logger.Logger) *gin.En
 			{
 				credentials.GET("", listCredentials)
 				credentials.POST("", createCredential)
-				credentials.GET("/:id", getCredential)
-				credentials.PUT("/:id", updateCredential)
-				credentials.DELETE("/:id", deleteCredential)
-				credentials.POST("/:id/test", testCredential)
+				credentials.GET("/health", listUnhealthyCredentials(svc.CredentialHealth))
+				credentials.GET("/:id", getCredential(svc.CredentialStore))
+				credentials.PUT("/:id", updateCredential(svc.CredentialStore))
+				credentials.DELETE("/:id", deleteCredential(svc.CredentialStore))
+				credentials.GET("/:id/hash", getCredentialHash(svc.CredentialStore))
+				credentials.GET("/by-slug/:slug", getCredentialBySlug(svc.CredentialStore))
+				credentials.PUT("/by-slug/:slug", putCredentialBySlug(svc.CredentialStore))
+				credentials.POST("/:id/test", testCredential(svc.CredentialStore, svc.CredentialHealth))
 				credentials.GET("/oauth2/:credentialType/auth", getOAuth2URL)
 				credentials.GET("/oauth2/callback", oAuth2Callback)
 				credentials.POST("/:id/share", shareCredential)
@@ -153,11 +391,11 @@ func NewRouter(cfg *configs.Config, db *database.DB, log *logger.Logger) *gin.En
 			// Settings routes
 			settings := protected.Group("/settings")
 			{
-				settings.GET("", getSettings)
-				settings.PUT("", updateSettings)
+				settings.GET("", getSettings(svc.Settings))
+				settings.PUT("", updateSettings(svc.Settings))
 				settings.GET("/smtp", getSMTPSettings)
 				settings.PUT("/smtp", updateSMTPSettings)
-				settings.POST("/smtp/test", testSMTPSettings)
+				settings.POST("/smtp/test", testSMTPSettings(cfg.Email.SMTP))
 			}
 
 			// Stats routes
@@ -211,16 +449,29 @@ func NewRouter(cfg *configs.Config, db *database.DB, log *logger.Logger) *gin.En
 				webhooks.GET("/:id/url", getWebhookURL)
 			}
 
+			// Workflow endpoint management routes
+			endpoints := protected.Group("/workflow-endpoints")
+			{
+				endpoints.GET("", listEndpoints(svc.Endpoints))
+				endpoints.POST("", createEndpoint(svc.Endpoints))
+				endpoints.GET("/:id", getEndpoint(svc.Endpoints))
+				endpoints.PUT("/:id", updateEndpoint(svc.Endpoints))
+				endpoints.DELETE("/:id", deleteEndpoint(svc.Endpoints))
+				endpoints.POST("/:id/rotate-key", rotateEndpointKey(svc.Endpoints))
+				endpoints.GET("/:id/usage", getEndpointUsage(svc.Endpoints))
+			}
+
 			// Schedules routes
 			schedules := protected.Group("/schedules")
 			{
-				schedules.GET("", listSchedules)
-				schedules.POST("", createSchedule)
-				schedules.GET("/:id", getSchedule)
-				schedules.PUT("/:id", updateSchedule)
-				schedules.DELETE("/:id", deleteSchedule)
-				schedules.POST("/:id/activate", activateSchedule)
-				schedules.POST("/:id/deactivate", deactivateSchedule)
+				schedules.GET("", listSchedules(svc.Schedules))
+				schedules.POST("", createSchedule(svc.Schedules))
+				schedules.POST("/validate", validateSchedule(svc.Schedules, cfg.Scheduler))
+				schedules.GET("/:id", getSchedule(svc.Schedules))
+				schedules.PUT("/:id", updateSchedule(svc.Schedules))
+				schedules.DELETE("/:id", deleteSchedule(svc.Schedules))
+				schedules.POST("/:id/activate", setScheduleActive(svc.Schedules, true))
+				schedules.POST("/:id/deactivate", setScheduleActive(svc.Schedules, false))
 			}
 
 			// Notifications routes
@@ -245,8 +496,9 @@ func NewRouter(cfg *configs.Config, db *database.DB, log *logger.Logger) *gin.En
 			// Audit logs routes
 			auditLogs := protected.Group("/audit-logs")
 			{
-				auditLogs.GET("", listAuditLogs)
-				auditLogs.GET("/:id", getAuditLog)
+				auditLogs.GET("", listAuditLogs(svc.Audit))
+				auditLogs.GET("/export", exportAuditBundle(svc.Audit))
+				auditLogs.GET("/:id", getAuditLog(svc.Audit))
 			}
 
 			// Metrics routes
@@ -255,8 +507,17 @@ func NewRouter(cfg *configs.Config, db *database.DB, log *logger.Logger) *gin.En
 				metrics.GET("", getMetrics)
 				metrics.GET("/queue", getQueueStatus)
 				metrics.GET("/executions", getExecutionStatistics)
-				metrics.GET("/workers", getWorkerStatus)
 				metrics.GET("/performance", getPerformanceMetrics)
+
+				// Instance wide, across all teams
+				instanceMetrics := metrics.Group("")
+				instanceMetrics.Use(middleware.IPAllowlist(adminNetworks))
+				instanceMetrics.Use(middleware.RequireRole("admin"))
+				instanceMetrics.GET("/executions/stuck", listStuckExecutions(svc.Watchdog))
+				instanceMetrics.GET("/pools", getPoolStats(svc.Pools))
+				instanceMetrics.GET("/workers", getWorkerStatus(svc.Workers))
+				instanceMetrics.GET("/load", getLoad(svc.Load))
+				instanceMetrics.GET("/autoscale", getAutoscaleSignals(svc.Autoscaler))
 			}
 
 			// Import/Export routes
@@ -267,6 +528,7 @@ func NewRouter(cfg *configs.Config, db *database.DB, log *logger.Logger) *gin.En
 
 			// Community routes
 			community := protected.Group("/community")
+			community.Use(middleware.RequireFeature(svc.Features, featuredomain.KeyMarketplace))
 			{
 				community.GET("/workflows", getCommunityWorkflows)
 				community.POST("/workflows", publishWorkflowToCommunity)
@@ -296,12 +558,26 @@ func NewRouter(cfg *configs.Config, db *database.DB, log *logger.Logger) *gin.En
 				teams.POST("/:id/members", addTeamMember)
 				teams.DELETE("/:id/members/:userId", removeTeamMember)
 				teams.PUT("/:id/members/:userId", updateTeamMemberRole)
+				teams.GET("/:id/dashboard", getTeamDashboard(svc.Dashboards))
+				teams.GET("/:id/dashboard/failing-workflows", getTeamFailingWorkflows(svc.Dashboards))
+				teams.GET("/:id/holiday-calendars", listHolidayCalendars(svc.Schedules))
+				teams.POST("/:id/holiday-calendars", createHolidayCalendar(svc.Schedules))
+				teams.PUT("/:id/holiday-calendars/:calendarId", updateHolidayCalendar(svc.Schedules))
+				teams.DELETE("/:id/holiday-calendars/:calendarId", deleteHolidayCalendar(svc.Schedules))
+				teams.GET("/:id/domains", listCustomDomains(svc.Endpoints))
+				teams.POST("/:id/domains", addCustomDomain(svc.Endpoints))
+				teams.POST("/:id/domains/:domainId/verify", verifyCustomDomain(svc.Endpoints))
+				teams.DELETE("/:id/domains/:domainId", removeCustomDomain(svc.Endpoints))
+				teams.GET("/:id/nodes", listTeamNodes(svc.TeamNodes))
+				teams.POST("/:id/nodes", importTeamNode(svc.TeamNodes))
+				teams.PUT("/:id/nodes/:nodeId", updateTeamNode(svc.TeamNodes))
+				teams.DELETE("/:id/nodes/:nodeId", deleteTeamNode(svc.TeamNodes))
 			}
 
 			// Billing routes (Enterprise)
 			billing := protected.Group("/billing")
 			{
-				billing.GET("/usage", getUsageStatistics)
+				billing.GET("/usage", getBillingUsage(svc.Billing))
 				billing.GET("/info", getBillingInfo)
 				billing.GET("/invoices", getInvoices)
 				billing.GET("/subscription", getSubscription)
@@ -310,25 +586,84 @@ func NewRouter(cfg *configs.Config, db *database.DB, log *logger.Logger) *gin.En
 
 			// Admin routes
 			admin := protected.Group("/admin")
+			admin.Use(middleware.IPAllowlist(adminNetworks))
 			admin.Use(middleware.RequireRole("admin"))
 			{
-				admin.GET("/users", listUsers)
-				admin.GET("/users/:id", getUser)
+				admin.GET("/users", listUsers(svc.Users))
+				admin.GET("/users/:id", getUserAccount(svc.Users))
 				admin.PUT("/users/:id", updateUser)
 				admin.DELETE("/users/:id", deleteUser)
-				admin.POST("/users/:id/activate", activateUser)
-				admin.POST("/users/:id/deactivate", deactivateUser)
+				admin.POST("/users/:id/activate", activateUser(svc.Users))
+				admin.POST("/users/:id/deactivate", deactivateUser(svc.Users))
+
+				// Feature flag management
+				admin.GET("/features", listFeatureFlags(svc.Features))
+				admin.GET("/features/:key", getFeatureFlag(svc.Features))
+				admin.PUT("/features/:key", updateFeatureFlag(svc.Features))
+				admin.DELETE("/features/:key", deleteFeatureFlag(svc.Features))
+				admin.PUT("/features/:key/targets/:type/:targetId", setFeatureFlagTarget(svc.Features))
+				admin.DELETE("/features/:key/targets/:type/:targetId", removeFeatureFlagTarget(svc.Features))
+
+				// Announcement management
+				admin.GET("/announcements", listAnnouncements(svc.Announcements))
+				admin.POST("/announcements", publishAnnouncement(svc.Announcements))
+				admin.PUT("/announcements/:id", updateAnnouncement(svc.Announcements))
+				admin.DELETE("/announcements/:id", withdrawAnnouncement(svc.Announcements))
+
+				// Outgoing webhooks for platform events
+				admin.GET("/instance-webhooks", listInstanceWebhooks(svc.InstanceHooks))
+				admin.POST("/instance-webhooks", createInstanceWebhook(svc.InstanceHooks))
+				admin.GET("/instance-webhooks/:id", getInstanceWebhook(svc.InstanceHooks))
+				admin.PUT("/instance-webhooks/:id", updateInstanceWebhook(svc.InstanceHooks))
+				admin.DELETE("/instance-webhooks/:id", deleteInstanceWebhook(svc.InstanceHooks))
+				admin.POST("/instance-webhooks/:id/rotate-secret", rotateInstanceWebhookSecret(svc.InstanceHooks))
+				admin.POST("/instance-webhooks/:id/ping", pingInstanceWebhook(svc.InstanceHooks))
+				admin.GET("/instance-webhooks/:id/deliveries", listInstanceWebhookDeliveries(svc.InstanceHooks))
+				admin.POST("/instance-webhooks/:id/deliveries/:deliveryId/redeliver", redeliverInstanceWebhookDelivery(svc.InstanceHooks))
+
+				// Credential migration between instances
+				admin.POST("/credentials/export", exportCredentials(svc.Credentials))
+				admin.POST("/credentials/import", importCredentials(svc.Credentials))
+
+				// Workflow migration between instances
+				admin.GET("/migrations/targets", listMigrationTargets(svc.Migrations))
+				admin.POST("/migrations/export", exportMigration(svc.Migrations))
+				admin.POST("/migrations/import", importMigration(svc.Migrations))
+				admin.POST("/migrations/push", pushMigration(svc.Migrations))
+
+				// Operational alerts
+				admin.GET("/alerts", listAlerts(svc.Alerts))
+				admin.POST("/alerts/test", testAlertChannels(svc.Alerts))
+
+				// Orphaned resources
+				admin.GET("/cleanup", getCleanupReport(svc.Cleanup))
+				admin.POST("/cleanup", runCleanup(svc.Cleanup))
+
+				// Profiling and engine internals, only when enabled
+				debug := admin.Group("/debug")
+				debug.Use(middleware.RequireFeature(svc.Features, featuredomain.KeyDiagnostics))
+				registerDebugRoutes(debug, svc.Diagnostics)
 			}
 		}
 	}
 
 	// WebSocket endpoint
-	router.GET("/ws", websocketHandler)
+	router.GET("/ws", streamingDeadlines, authenticate, serveWebSocket(svc.Hub))
+
+	// GraphQL endpoint for the management plane
+	graphqlRoutes := router.Group("/graphql")
+	graphqlRoutes.Use(authenticate)
+	graphqlRoutes.Use(middleware.UserLanguage(svc.Languages.Language))
+	graphqlRoutes.Use(middleware.TrackActivity(svc.Activity.Seen))
+	{
+		graphqlRoutes.GET("", serveGraphQL(svc.GraphQL))
+		graphqlRoutes.POST("", serveGraphQL(svc.GraphQL))
+	}
 
 	// Static files (if needed)
 	router.Static("/assets", "./assets")
 
-	return router
+	return router, nil
 }
 
 // Placeholder handlers - to be implemented
@@ -388,30 +723,6 @@ func createWorkflow(c *gin.Context) {
 	c.JSON(501, gin.H{"error": "not implemented"})
 }
 
-func getWorkflow(c *gin.Context) {
-	c.JSON(501, gin.H{"error": "not implemented"})
-}
-
-func updateWorkflow(c *gin.Context) {
-	c.JSON(501, gin.H{"error": "not implemented"})
-}
-
-func deleteWorkflow(c *gin.Context) {
-	c.JSON(501, gin.H{"error": "not implemented"})
-}
-
-func activateWorkflow(c *gin.Context) {
-	c.JSON(501, gin.H{"error": "not implemented"})
-}
-
-func deactivateWorkflow(c *gin.Context) {
-	c.JSON(501, gin.H{"error": "not implemented"})
-}
-
-func executeWorkflow(c *gin.Context) {
-	c.JSON(501, gin.H{"error": "not implemented"})
-}
-
 func duplicateWorkflow(c *gin.Context) {
 	c.JSON(501, gin.H{"error": "not implemented"})
 }
@@ -424,26 +735,10 @@ func shareWorkflow(c *gin.Context) {
 	c.JSON(501, gin.H{"error": "not implemented"})
 }
 
-func getWorkflowVersions(c *gin.Context) {
-	c.JSON(501, gin.H{"error": "not implemented"})
-}
-
-func listNodeTypes(c *gin.Context) {
-	c.JSON(501, gin.H{"error": "not implemented"})
-}
-
-func getNodeType(c *gin.Context) {
-	c.JSON(501, gin.H{"error": "not implemented"})
-}
-
 func testNode(c *gin.Context) {
 	c.JSON(501, gin.H{"error": "not implemented"})
 }
 
-func listExecutions(c *gin.Context) {
-	c.JSON(501, gin.H{"error": "not implemented"})
-}
-
 func getExecution(c *gin.Context) {
 	c.JSON(501, gin.H{"error": "not implemented"})
 }
@@ -456,10 +751,6 @@ func retryExecution(c *gin.Context) {
 	c.JSON(501, gin.H{"error": "not implemented"})
 }
 
-func deleteExecution(c *gin.Context) {
-	c.JSON(501, gin.H{"error": "not implemented"})
-}
-
 func getExecutionData(c *gin.Context) {
 	c.JSON(501, gin.H{"error": "not implemented"})
 }
@@ -472,22 +763,6 @@ func createCredential(c *gin.Context) {
 	c.JSON(501, gin.H{"error": "not implemented"})
 }
 
-func getCredential(c *gin.Context) {
-	c.JSON(501, gin.H{"error": "not implemented"})
-}
-
-func updateCredential(c *gin.Context) {
-	c.JSON(501, gin.H{"error": "not implemented"})
-}
-
-func deleteCredential(c *gin.Context) {
-	c.JSON(501, gin.H{"error": "not implemented"})
-}
-
-func testCredential(c *gin.Context) {
-	c.JSON(501, gin.H{"error": "not implemented"})
-}
-
 func listVariables(c *gin.Context) {
 	c.JSON(501, gin.H{"error": "not implemented"})
 }
@@ -524,14 +799,6 @@ func deleteTag(c *gin.Context) {
 	c.JSON(501, gin.H{"error": "not implemented"})
 }
 
-func getSettings(c *gin.Context) {
-	c.JSON(501, gin.H{"error": "not implemented"})
-}
-
-func updateSettings(c *gin.Context) {
-	c.JSON(501, gin.H{"error": "not implemented"})
-}
-
 func getWorkflowStats(c *gin.Context) {
 	c.JSON(501, gin.H{"error": "not implemented"})
 }
@@ -544,10 +811,6 @@ func getUsageStats(c *gin.Context) {
 	c.JSON(501, gin.H{"error": "not implemented"})
 }
 
-func listUsers(c *gin.Context) {
-	c.JSON(501, gin.H{"error": "not implemented"})
-}
-
 func getUser(c *gin.Context) {
 	c.JSON(501, gin.H{"error": "not implemented"})
 }
@@ -559,15 +822,3 @@ func updateUser(c *gin.Context) {
 func deleteUser(c *gin.Context) {
 	c.JSON(501, gin.H{"error": "not implemented"})
 }
-
-func activateUser(c *gin.Context) {
-	c.JSON(501, gin.H{"error": "not implemented"})
-}
-
-func deactivateUser(c *gin.Context) {
-	c.JSON(501, gin.H{"error": "not implemented"})
-}
-
-func websocketHandler(c *gin.Context) {
-	c.JSON(501, gin.H{"error": "not implemented"})
-}
@@ -0,0 +1,357 @@
+package v1
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jaydeep/go-n8n/configs"
+	"github.com/jaydeep/go-n8n/internal/application/schedule"
+	domain "github.com/jaydeep/go-n8n/internal/domain/schedule"
+)
+
+// defaultPreviewCount is the number of fire times previewed by default
+const defaultPreviewCount = 5
+
+// scheduleCaller identifies the authenticated user to the schedule service
+func scheduleCaller(c *gin.Context) (schedule.Caller, bool) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return schedule.Caller{}, false
+	}
+	return schedule.Caller{UserID: userID, Admin: isAdmin(c)}, true
+}
+
+// scheduleRequest is the body for creating or updating a schedule. Either
+// cron_expression or recurrence is required.
+type scheduleRequest struct {
+	WorkflowID     uuid.UUID          `json:"workflow_id"`
+	CronExpression string             `json:"cron_expression"`
+	Recurrence     *domain.Recurrence `json:"recurrence"`
+	Timezone       string             `json:"timezone"`
+	JitterSeconds  int                `json:"jitter_seconds"`
+}
+
+func (r scheduleRequest) input() schedule.Input {
+	return schedule.Input{
+		CronExpression: r.CronExpression,
+		Recurrence:     r.Recurrence,
+		Timezone:       r.Timezone,
+		JitterSeconds:  r.JitterSeconds,
+	}
+}
+
+// listSchedules lists the schedules of the user's workflows
+func listSchedules(svc *schedule.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		caller, ok := scheduleCaller(c)
+		if !ok {
+			return
+		}
+
+		schedules, err := svc.List(c.Request.Context(), caller)
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"data": schedules})
+	}
+}
+
+// createSchedule adds an active schedule to a workflow
+func createSchedule(svc *schedule.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		caller, ok := scheduleCaller(c)
+		if !ok {
+			return
+		}
+
+		var req scheduleRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if req.WorkflowID == uuid.Nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": translate(c, "workflow_id is required")})
+			return
+		}
+
+		sched, err := svc.Create(c.Request.Context(), caller, req.WorkflowID, req.input())
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+		c.JSON(http.StatusCreated, gin.H{"data": sched})
+	}
+}
+
+// getSchedule returns a schedule
+func getSchedule(svc *schedule.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		caller, ok := scheduleCaller(c)
+		if !ok {
+			return
+		}
+		id, ok := paramUUID(c, "id")
+		if !ok {
+			return
+		}
+
+		sched, err := svc.Get(c.Request.Context(), caller, id)
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"data": sched})
+	}
+}
+
+// updateSchedule replaces the timing of a schedule
+func updateSchedule(svc *schedule.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		caller, ok := scheduleCaller(c)
+		if !ok {
+			return
+		}
+		id, ok := paramUUID(c, "id")
+		if !ok {
+			return
+		}
+
+		var req scheduleRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		sched, err := svc.Update(c.Request.Context(), caller, id, req.input())
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"data": sched})
+	}
+}
+
+// deleteSchedule removes a schedule
+func deleteSchedule(svc *schedule.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		caller, ok := scheduleCaller(c)
+		if !ok {
+			return
+		}
+		id, ok := paramUUID(c, "id")
+		if !ok {
+			return
+		}
+
+		if err := svc.Delete(c.Request.Context(), caller, id); err != nil {
+			respondError(c, err)
+			return
+		}
+		c.Status(http.StatusNoContent)
+	}
+}
+
+// setScheduleActive enables or pauses a schedule
+func setScheduleActive(svc *schedule.Service, active bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		caller, ok := scheduleCaller(c)
+		if !ok {
+			return
+		}
+		id, ok := paramUUID(c, "id")
+		if !ok {
+			return
+		}
+
+		sched, err := svc.SetActive(c.Request.Context(), caller, id, active)
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"data": sched})
+	}
+}
+
+// validateScheduleRequest is a cron expression or recurrence to validate
+// and preview
+type validateScheduleRequest struct {
+	Expression string             `json:"expression"`
+	Recurrence *domain.Recurrence `json:"recurrence"`
+	Timezone   string             `json:"timezone"`
+	Count      int                `json:"count" binding:"omitempty,min=1,max=50"`
+	From       *time.Time         `json:"from"`
+}
+
+// validateSchedule compiles a cron expression or calendar recurrence in
+// the schedule's timezone and returns its next fire times so schedules
+// can be previewed before saving
+func validateSchedule(svc *schedule.Service, cfg configs.SchedulerConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		caller, ok := scheduleCaller(c)
+		if !ok {
+			return
+		}
+
+		var req validateScheduleRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if req.Timezone == "" {
+			req.Timezone = cfg.Location
+		}
+		if req.Count == 0 {
+			req.Count = defaultPreviewCount
+		}
+		if req.Expression == "" && req.Recurrence == nil {
+			respondError(c, domain.ErrScheduleRequired)
+			return
+		}
+
+		sched := &domain.Schedule{
+			CronExpression: req.Expression,
+			Recurrence:     req.Recurrence,
+			Timezone:       req.Timezone,
+		}
+		evaluator, err := svc.Evaluator(c.Request.Context(), caller, sched)
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+		loc, _ := domain.LoadLocation(req.Timezone)
+
+		from := time.Now()
+		if req.From != nil {
+			from = *req.From
+		}
+		next := domain.Preview(evaluator, from.In(loc), req.Count)
+
+		warnings := []string{}
+		if len(next) > 1 && cfg.CheckInterval > 0 && next[1].Sub(next[0]) < cfg.CheckInterval {
+			warnings = append(warnings, translate(c, "schedule fires more often than the scheduler checks for due schedules, so runs may be delayed"))
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"data": gin.H{
+				"valid":      true,
+				"expression": req.Expression,
+				"recurrence": req.Recurrence,
+				"timezone":   loc.String(),
+				"next":       next,
+				"warnings":   warnings,
+			},
+		})
+	}
+}
+
+// holidayCalendarRequest is the body for creating or updating a holiday calendar
+type holidayCalendarRequest struct {
+	Name  string   `json:"name" binding:"required"`
+	Dates []string `json:"dates"`
+}
+
+// listHolidayCalendars lists the holiday calendars of a team
+func listHolidayCalendars(svc *schedule.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		caller, ok := scheduleCaller(c)
+		if !ok {
+			return
+		}
+		teamID, ok := paramUUID(c, "id")
+		if !ok {
+			return
+		}
+
+		calendars, err := svc.Calendars(c.Request.Context(), caller, teamID)
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"data": calendars})
+	}
+}
+
+// createHolidayCalendar adds a holiday calendar to a team
+func createHolidayCalendar(svc *schedule.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		caller, ok := scheduleCaller(c)
+		if !ok {
+			return
+		}
+		teamID, ok := paramUUID(c, "id")
+		if !ok {
+			return
+		}
+
+		var req holidayCalendarRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		calendar := &domain.HolidayCalendar{TeamID: teamID, Name: req.Name, Dates: req.Dates}
+		if err := svc.CreateCalendar(c.Request.Context(), caller, calendar); err != nil {
+			respondError(c, err)
+			return
+		}
+		c.JSON(http.StatusCreated, gin.H{"data": calendar})
+	}
+}
+
+// updateHolidayCalendar replaces the name and dates of a holiday calendar
+func updateHolidayCalendar(svc *schedule.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		caller, ok := scheduleCaller(c)
+		if !ok {
+			return
+		}
+		teamID, ok := paramUUID(c, "id")
+		if !ok {
+			return
+		}
+		calendarID, ok := paramUUID(c, "calendarId")
+		if !ok {
+			return
+		}
+
+		var req holidayCalendarRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		calendar, err := svc.UpdateCalendar(c.Request.Context(), caller, teamID, calendarID, req.Name, req.Dates)
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"data": calendar})
+	}
+}
+
+// deleteHolidayCalendar removes a holiday calendar
+func deleteHolidayCalendar(svc *schedule.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		caller, ok := scheduleCaller(c)
+		if !ok {
+			return
+		}
+		teamID, ok := paramUUID(c, "id")
+		if !ok {
+			return
+		}
+		calendarID, ok := paramUUID(c, "calendarId")
+		if !ok {
+			return
+		}
+
+		if err := svc.DeleteCalendar(c.Request.Context(), caller, teamID, calendarID); err != nil {
+			respondError(c, err)
+			return
+		}
+		c.Status(http.StatusNoContent)
+	}
+}
@@ -0,0 +1,75 @@
+package v1
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jaydeep/go-n8n/internal/application/workflow"
+)
+
+// listWorkflowSecrets returns the secrets of a workflow with their values
+// masked
+func listWorkflowSecrets(workflows *workflow.Service, secrets *workflow.SecretService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		w, ok := loadWorkflow(c, workflows)
+		if !ok {
+			return
+		}
+
+		list, err := secrets.List(c.Request.Context(), w.ID)
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"data": list})
+	}
+}
+
+// setWorkflowSecret creates or replaces a secret of a workflow
+func setWorkflowSecret(workflows *workflow.Service, secrets *workflow.SecretService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		actor, ok := actorFrom(c)
+		if !ok {
+			return
+		}
+		w, ok := loadWorkflow(c, workflows)
+		if !ok {
+			return
+		}
+
+		var input struct {
+			Value string `json:"value" binding:"required"`
+		}
+		if err := c.ShouldBindJSON(&input); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		secret, err := secrets.Set(c.Request.Context(), w, actor, c.Param("name"), input.Value)
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"data": secret})
+	}
+}
+
+// deleteWorkflowSecret removes a secret of a workflow
+func deleteWorkflowSecret(workflows *workflow.Service, secrets *workflow.SecretService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		actor, ok := actorFrom(c)
+		if !ok {
+			return
+		}
+		w, ok := loadWorkflow(c, workflows)
+		if !ok {
+			return
+		}
+
+		if err := secrets.Delete(c.Request.Context(), w.ID, actor, c.Param("name")); err != nil {
+			respondError(c, err)
+			return
+		}
+		c.Status(http.StatusNoContent)
+	}
+}
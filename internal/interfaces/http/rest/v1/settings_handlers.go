@@ -0,0 +1,146 @@
+package v1
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jaydeep/go-n8n/configs"
+	"github.com/jaydeep/go-n8n/internal/application/settings"
+	domain "github.com/jaydeep/go-n8n/internal/domain/settings"
+	"github.com/jaydeep/go-n8n/internal/infrastructure/email"
+)
+
+// settingsScope resolves the ?scope= parameter to the scope and scope ID
+// the authenticated user may access. Instance settings require the
+// settings:manage permission; user settings always belong to the caller.
+func settingsScope(c *gin.Context) (domain.Scope, uuid.UUID, bool) {
+	scope := domain.Scope(c.DefaultQuery("scope", string(domain.ScopeUser)))
+	switch scope {
+	case domain.ScopeInstance:
+		if !hasPermission(c, "settings:manage") {
+			c.JSON(http.StatusForbidden, gin.H{"error": translate(c, "insufficient permissions")})
+			return "", uuid.Nil, false
+		}
+		return scope, uuid.Nil, true
+	case domain.ScopeUser:
+		userID, ok := currentUserID(c)
+		if !ok {
+			return "", uuid.Nil, false
+		}
+		return scope, userID, true
+	default:
+		respondError(c, domain.ErrInvalidScope)
+		return "", uuid.Nil, false
+	}
+}
+
+// getSettings lists the settings of a scope with their effective values
+func getSettings(svc *settings.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		scope, scopeID, ok := settingsScope(c)
+		if !ok {
+			return
+		}
+
+		entries, err := svc.List(c.Request.Context(), scope, scopeID)
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"data": gin.H{"scope": scope, "settings": entries}})
+	}
+}
+
+// updateSettings stores the given settings of a scope. Keys set to null
+// are reset to their defaults.
+func updateSettings(svc *settings.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		scope, scopeID, ok := settingsScope(c)
+		if !ok {
+			return
+		}
+		actor, ok := actorFrom(c)
+		if !ok {
+			return
+		}
+
+		var values map[string]json.RawMessage
+		if err := c.ShouldBindJSON(&values); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		if err := svc.Update(c.Request.Context(), actor, scope, scopeID, values); err != nil {
+			respondError(c, err)
+			return
+		}
+
+		entries, err := svc.List(c.Request.Context(), scope, scopeID)
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"data": gin.H{"scope": scope, "settings": entries}})
+	}
+}
+
+// smtpTestRequest overrides the configured SMTP settings for a test
+type smtpTestRequest struct {
+	Host     string `json:"host"`
+	Port     int    `json:"port"`
+	User     string `json:"user"`
+	Password string `json:"password"`
+	From     string `json:"from"`
+	Security string `json:"security" binding:"omitempty,oneof=none starttls tls"`
+	To       string `json:"to" binding:"omitempty,email"`
+	Timeout  string `json:"timeout"`
+}
+
+// testSMTPSettings diagnoses an SMTP configuration stage by stage and
+// optionally sends a test email. Fields left empty use the configured
+// values. The response is 200 even when a stage fails; success and the
+// failing stage are reported in the body.
+func testSMTPSettings(smtpCfg configs.SMTPConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !hasPermission(c, "settings:manage") {
+			c.JSON(http.StatusForbidden, gin.H{"error": translate(c, "insufficient permissions")})
+			return
+		}
+
+		var req smtpTestRequest
+		if c.Request.ContentLength != 0 {
+			if err := c.ShouldBindJSON(&req); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+		}
+
+		opts := email.TestOptions{SMTPConfig: smtpCfg, Security: req.Security, To: req.To}
+		if req.Host != "" {
+			opts.Host = req.Host
+		}
+		if req.Port != 0 {
+			opts.Port = req.Port
+		}
+		if req.User != "" {
+			opts.User = req.User
+			opts.Password = req.Password
+		}
+		if req.From != "" {
+			opts.From = req.From
+		}
+		if req.Timeout != "" {
+			timeout, err := time.ParseDuration(req.Timeout)
+			if err != nil || timeout <= 0 || timeout > time.Minute {
+				c.JSON(http.StatusBadRequest, gin.H{"error": translate(c, "timeout must be a duration up to 1m")})
+				return
+			}
+			opts.Timeout = timeout
+		}
+
+		c.JSON(http.StatusOK, gin.H{"data": email.Diagnose(c.Request.Context(), opts)})
+	}
+}
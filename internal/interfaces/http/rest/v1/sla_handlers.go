@@ -0,0 +1,87 @@
+package v1
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jaydeep/go-n8n/internal/application/sla"
+	"github.com/jaydeep/go-n8n/internal/application/workflow"
+)
+
+// getWorkflowSLA returns the SLA policy of a workflow with its status
+func getWorkflowSLA(workflows *workflow.Service, slas *sla.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		w, ok := loadWorkflow(c, workflows)
+		if !ok {
+			return
+		}
+
+		p, err := slas.Get(c.Request.Context(), w.ID)
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"data": p})
+	}
+}
+
+// setWorkflowSLA creates or replaces the SLA policy of a workflow
+func setWorkflowSLA(workflows *workflow.Service, slas *sla.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		actor, ok := actorFrom(c)
+		if !ok {
+			return
+		}
+		w, ok := loadWorkflow(c, workflows)
+		if !ok {
+			return
+		}
+
+		var input sla.Input
+		if err := c.ShouldBindJSON(&input); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		p, err := slas.Set(c.Request.Context(), w, actor.UserID, input)
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"data": p})
+	}
+}
+
+// deleteWorkflowSLA removes the SLA policy of a workflow
+func deleteWorkflowSLA(workflows *workflow.Service, slas *sla.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		w, ok := loadWorkflow(c, workflows)
+		if !ok {
+			return
+		}
+
+		if err := slas.Delete(c.Request.Context(), w.ID); err != nil {
+			respondError(c, err)
+			return
+		}
+		c.Status(http.StatusNoContent)
+	}
+}
+
+// getWorkflowMetrics summarizes the recent executions of a workflow and its
+// SLA status
+func getWorkflowMetrics(workflows *workflow.Service, slas *sla.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		w, ok := loadWorkflow(c, workflows)
+		if !ok {
+			return
+		}
+
+		metrics, err := slas.Metrics(c.Request.Context(), w)
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"data": metrics})
+	}
+}
@@ -0,0 +1,27 @@
+package v1
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jaydeep/go-n8n/internal/application/engine"
+	"github.com/jaydeep/go-n8n/internal/application/workflow"
+)
+
+// getWorkflowStatistics returns the duration baseline of a workflow and
+// its recent executions that were much slower than usual
+func getWorkflowStatistics(workflows *workflow.Service, statistics *engine.Statistics) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		w, ok := loadWorkflow(c, workflows)
+		if !ok {
+			return
+		}
+
+		stats, err := statistics.Workflow(c.Request.Context(), w.ID)
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"data": stats})
+	}
+}
@@ -0,0 +1,136 @@
+package v1
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jaydeep/go-n8n/internal/application/teamnode"
+)
+
+// teamNodeRequest is the body for generating a team node from an OpenAPI
+// document, given as JSON or YAML text
+type teamNodeRequest struct {
+	Spec    string `json:"spec" binding:"required"`
+	Slug    string `json:"slug"`
+	Name    string `json:"name"`
+	BaseURL string `json:"base_url"`
+}
+
+func (r teamNodeRequest) input() teamnode.Input {
+	return teamnode.Input{Spec: []byte(r.Spec), Slug: r.Slug, Name: r.Name, BaseURL: r.BaseURL}
+}
+
+// teamNodeCaller returns the caller of a team node request
+func teamNodeCaller(c *gin.Context) (teamnode.Caller, bool) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return teamnode.Caller{}, false
+	}
+	return teamnode.Caller{UserID: userID, Admin: isAdmin(c)}, true
+}
+
+// listTeamNodes lists the nodes generated for a team
+func listTeamNodes(svc *teamnode.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		caller, ok := teamNodeCaller(c)
+		if !ok {
+			return
+		}
+		teamID, ok := paramUUID(c, "id")
+		if !ok {
+			return
+		}
+
+		nodes, err := svc.List(c.Request.Context(), caller, teamID)
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"data": nodes})
+	}
+}
+
+// importTeamNode generates a node for a team from an OpenAPI document
+func importTeamNode(svc *teamnode.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		caller, ok := teamNodeCaller(c)
+		if !ok {
+			return
+		}
+		teamID, ok := paramUUID(c, "id")
+		if !ok {
+			return
+		}
+
+		c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, 2*teamnode.MaxSpecSize)
+		var req teamNodeRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		n, err := svc.Import(c.Request.Context(), caller, teamID, req.input())
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+		c.JSON(http.StatusCreated, gin.H{"data": n})
+	}
+}
+
+// updateTeamNode regenerates a node of a team from a new version of its
+// OpenAPI document
+func updateTeamNode(svc *teamnode.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		caller, ok := teamNodeCaller(c)
+		if !ok {
+			return
+		}
+		teamID, ok := paramUUID(c, "id")
+		if !ok {
+			return
+		}
+		nodeID, ok := paramUUID(c, "nodeId")
+		if !ok {
+			return
+		}
+
+		c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, 2*teamnode.MaxSpecSize)
+		var req teamNodeRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		n, err := svc.Update(c.Request.Context(), caller, teamID, nodeID, req.input())
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"data": n})
+	}
+}
+
+// deleteTeamNode removes a node of a team
+func deleteTeamNode(svc *teamnode.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		caller, ok := teamNodeCaller(c)
+		if !ok {
+			return
+		}
+		teamID, ok := paramUUID(c, "id")
+		if !ok {
+			return
+		}
+		nodeID, ok := paramUUID(c, "nodeId")
+		if !ok {
+			return
+		}
+
+		if err := svc.Delete(c.Request.Context(), caller, teamID, nodeID); err != nil {
+			respondError(c, err)
+			return
+		}
+		c.Status(http.StatusNoContent)
+	}
+}
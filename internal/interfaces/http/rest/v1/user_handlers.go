@@ -0,0 +1,100 @@
+package v1
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jaydeep/go-n8n/internal/application/user"
+	domain "github.com/jaydeep/go-n8n/internal/domain/user"
+	"github.com/jaydeep/go-n8n/pkg/pagination"
+)
+
+// userListRequest holds the filters of the admin user listing
+type userListRequest struct {
+	Active       *bool  `form:"active"`
+	Role         string `form:"role"`
+	Dormant      bool   `form:"dormant"`
+	InactiveDays int    `form:"inactive_days" binding:"min=0"`
+	Limit        int    `form:"limit" binding:"min=0"`
+	Offset       int    `form:"offset" binding:"min=0"`
+}
+
+// listUsers lists users by name with their last activity. dormant=true
+// selects users inactive beyond the users.dormant_after_days setting and
+// inactive_days those inactive for at least that many days.
+func listUsers(svc *user.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req userListRequest
+		if err := c.ShouldBindQuery(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if req.Limit == 0 {
+			req.Limit = pagination.DefaultLimit
+		}
+		if req.Limit > pagination.MaxLimit {
+			req.Limit = pagination.MaxLimit
+		}
+
+		users, err := svc.List(c.Request.Context(), user.ListQuery{
+			Active:       req.Active,
+			Role:         domain.Role(req.Role),
+			Dormant:      req.Dormant,
+			InactiveDays: req.InactiveDays,
+			Limit:        req.Limit,
+			Offset:       req.Offset,
+		})
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"data": users, "count": len(users)})
+	}
+}
+
+// getUserAccount returns a user with their last activity
+func getUserAccount(svc *user.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, ok := paramUUID(c, "id")
+		if !ok {
+			return
+		}
+
+		u, err := svc.Get(c.Request.Context(), id)
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"data": u})
+	}
+}
+
+// activateUser reactivates a user, such as one deactivated for inactivity
+func activateUser(svc *user.Service) gin.HandlerFunc {
+	return setUserActive(svc, true)
+}
+
+// deactivateUser deactivates a user
+func deactivateUser(svc *user.Service) gin.HandlerFunc {
+	return setUserActive(svc, false)
+}
+
+func setUserActive(svc *user.Service, active bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		actor, ok := actorFrom(c)
+		if !ok {
+			return
+		}
+		id, ok := paramUUID(c, "id")
+		if !ok {
+			return
+		}
+
+		u, err := svc.SetActive(c.Request.Context(), actor, id, active)
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"data": u})
+	}
+}
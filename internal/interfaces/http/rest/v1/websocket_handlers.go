@@ -0,0 +1,21 @@
+package v1
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/jaydeep/go-n8n/internal/interfaces/websocket"
+)
+
+// serveWebSocket streams real-time events to the authenticated editor
+func serveWebSocket(hub *websocket.Hub) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, ok := currentUserID(c)
+		if !ok {
+			return
+		}
+
+		if err := hub.Serve(c.Writer, c.Request, userID.String()); err != nil {
+			// Upgrade has already written the error response
+			c.Error(err)
+		}
+	}
+}
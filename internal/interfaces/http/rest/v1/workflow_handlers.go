@@ -0,0 +1,463 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jaydeep/go-n8n/internal/application/audit"
+	"github.com/jaydeep/go-n8n/internal/application/workflow"
+	domain "github.com/jaydeep/go-n8n/internal/domain/workflow"
+)
+
+// loadWorkflow loads the workflow referenced by the :id parameter and checks
+// that the current user may access it
+func loadWorkflow(c *gin.Context, svc *workflow.Service) (*domain.Workflow, bool) {
+	id, ok := paramUUID(c, "id")
+	if !ok {
+		return nil, false
+	}
+
+	w, err := svc.Get(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err)
+		return nil, false
+	}
+
+	if !canAccess(c, w.UserID) {
+		c.JSON(http.StatusNotFound, gin.H{"error": translate(c, domain.ErrWorkflowNotFound.Error())})
+		return nil, false
+	}
+	return w, true
+}
+
+// loadWorkflowForReview is like loadWorkflow but also lets approvers, who
+// do not own the workflow, review its change requests
+func loadWorkflowForReview(c *gin.Context, svc *workflow.Service) (*domain.Workflow, bool) {
+	if !hasPermission(c, "workflow:approve") {
+		return loadWorkflow(c, svc)
+	}
+
+	id, ok := paramUUID(c, "id")
+	if !ok {
+		return nil, false
+	}
+
+	w, err := svc.Get(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err)
+		return nil, false
+	}
+	return w, true
+}
+
+// getWorkflow returns a workflow with its content hash, also sent as the
+// ETag
+func getWorkflow(svc *workflow.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		w, ok := loadWorkflow(c, svc)
+		if !ok {
+			return
+		}
+		respondWorkflow(c, http.StatusOK, w)
+	}
+}
+
+// getWorkflowBySlug returns the workflow with the :slug parameter
+func getWorkflowBySlug(svc *workflow.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		w, err := svc.FindBySlug(c.Request.Context(), c.Param("slug"))
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+		if !canAccess(c, w.UserID) {
+			c.JSON(http.StatusNotFound, gin.H{"error": translate(c, domain.ErrWorkflowNotFound.Error())})
+			return
+		}
+		respondWorkflow(c, http.StatusOK, w)
+	}
+}
+
+// updateWorkflow saves changes to the workflow draft, creating the workflow
+// under the :id parameter when it does not exist. Repeating a request
+// changes nothing, so clients managing workflows as code can retry it.
+func updateWorkflow(svc *workflow.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, ok := paramUUID(c, "id")
+		if !ok {
+			return
+		}
+		w, err := svc.Get(c.Request.Context(), id)
+		if err != nil && !errors.Is(err, domain.ErrWorkflowNotFound) {
+			respondError(c, err)
+			return
+		}
+		if err == nil && !canAccess(c, w.UserID) {
+			c.JSON(http.StatusNotFound, gin.H{"error": translate(c, domain.ErrWorkflowNotFound.Error())})
+			return
+		}
+		saveWorkflow(c, svc, id, w)
+	}
+}
+
+// putWorkflowBySlug saves changes to the workflow with the :slug
+// parameter, creating it under a new ID when no workflow has the slug
+func putWorkflowBySlug(svc *workflow.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		slug := c.Param("slug")
+		if err := domain.ValidateSlug(slug); err != nil {
+			respondError(c, err)
+			return
+		}
+		w, err := svc.FindBySlug(c.Request.Context(), slug)
+		if err != nil && !errors.Is(err, domain.ErrWorkflowNotFound) {
+			respondError(c, err)
+			return
+		}
+		if err == nil && !canAccess(c, w.UserID) {
+			c.JSON(http.StatusNotFound, gin.H{"error": translate(c, domain.ErrWorkflowNotFound.Error())})
+			return
+		}
+		id := uuid.New()
+		if w != nil {
+			id = w.ID
+		}
+		saveWorkflow(c, svc, id, w)
+	}
+}
+
+// saveWorkflow updates the draft of w from the request body, or creates a
+// workflow with the ID and the slug of the path, if any, when w is nil
+func saveWorkflow(c *gin.Context, svc *workflow.Service, id uuid.UUID, w *domain.Workflow) {
+	hash := ""
+	if w != nil {
+		hash = w.ContentHash()
+	}
+	if !checkPreconditions(c, hash) {
+		return
+	}
+	if w == nil && !hasPermission(c, "workflow:create") {
+		c.JSON(http.StatusForbidden, gin.H{"error": translate(c, "insufficient permissions")})
+		return
+	}
+
+	var input workflow.DraftInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if slug := c.Param("slug"); slug != "" {
+		input.Slug = &slug
+	}
+
+	if w != nil {
+		updated, err := svc.UpdateDraft(c.Request.Context(), w.ID, input)
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+		respondWorkflow(c, http.StatusOK, updated)
+		return
+	}
+
+	ownerID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	created, err := svc.Create(c.Request.Context(), id, ownerID, input)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	respondWorkflow(c, http.StatusCreated, created)
+}
+
+// respondWorkflow responds with a workflow and its content hash
+func respondWorkflow(c *gin.Context, status int, w *domain.Workflow) {
+	hash := w.ContentHash()
+	c.Header("ETag", contentETag(hash))
+	c.JSON(status, gin.H{"data": w, "content_hash": hash})
+}
+
+// deleteWorkflow deactivates and deletes a workflow
+func deleteWorkflow(svc *workflow.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		w, ok := loadWorkflow(c, svc)
+		if !ok {
+			return
+		}
+		if !checkPreconditions(c, w.ContentHash()) {
+			return
+		}
+		actor, ok := actorFrom(c)
+		if !ok {
+			return
+		}
+
+		if err := svc.Delete(c.Request.Context(), w.ID, actor); err != nil {
+			respondError(c, err)
+			return
+		}
+		c.Status(http.StatusNoContent)
+	}
+}
+
+// getWorkflowHash returns the content hash of a workflow with its versions,
+// so clients detect drift without fetching the whole workflow
+func getWorkflowHash(svc *workflow.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		w, ok := loadWorkflow(c, svc)
+		if !ok {
+			return
+		}
+
+		hash := w.ContentHash()
+		c.Header("ETag", contentETag(hash))
+		c.JSON(http.StatusOK, gin.H{"data": gin.H{
+			"id":                      w.ID,
+			"slug":                    w.Slug,
+			"content_hash":            hash,
+			"version":                 w.Version,
+			"published_version":       w.PublishedVersion,
+			"has_unpublished_changes": w.HasUnpublishedChanges(),
+			"updated_at":              w.UpdatedAt,
+		}})
+	}
+}
+
+// publishWorkflow pins the current draft as the production version, or opens
+// a change request when the workflow requires approval
+func publishWorkflow(svc *workflow.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		w, ok := loadWorkflow(c, svc)
+		if !ok {
+			return
+		}
+		actor, ok := actorFrom(c)
+		if !ok {
+			return
+		}
+
+		result, err := svc.Publish(c.Request.Context(), w.ID, actor)
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+		if result.ChangeRequest != nil {
+			c.JSON(http.StatusAccepted, gin.H{"data": result.ChangeRequest})
+			return
+		}
+		c.JSON(http.StatusCreated, gin.H{"data": result.Version})
+	}
+}
+
+// listChangeRequests lists the change requests of a workflow
+func listChangeRequests(svc *workflow.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		w, ok := loadWorkflowForReview(c, svc)
+		if !ok {
+			return
+		}
+
+		requests, err := svc.ChangeRequests(c.Request.Context(), w.ID)
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"data": requests})
+	}
+}
+
+// commentChangeRequest adds a review comment to a change request
+func commentChangeRequest(svc *workflow.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		w, ok := loadWorkflowForReview(c, svc)
+		if !ok {
+			return
+		}
+		requestID, ok := paramUUID(c, "requestId")
+		if !ok {
+			return
+		}
+		actor, ok := actorFrom(c)
+		if !ok {
+			return
+		}
+
+		var input struct {
+			Body string `json:"body" binding:"required"`
+		}
+		if err := c.ShouldBindJSON(&input); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		cr, err := svc.CommentOnChange(c.Request.Context(), w.ID, requestID, actor, input.Body)
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+		c.JSON(http.StatusCreated, gin.H{"data": cr})
+	}
+}
+
+// approveChangeRequest publishes the snapshot of a pending change request
+func approveChangeRequest(svc *workflow.Service) gin.HandlerFunc {
+	return decideChangeRequest(svc, svc.ApproveChange)
+}
+
+// rejectChangeRequest declines a pending change request
+func rejectChangeRequest(svc *workflow.Service) gin.HandlerFunc {
+	return decideChangeRequest(svc, svc.RejectChange)
+}
+
+// decideChangeRequest handles approve and reject, which only differ in the
+// service call
+func decideChangeRequest(
+	svc *workflow.Service,
+	decide func(ctx context.Context, workflowID, requestID uuid.UUID, actor audit.Actor, note string) (*domain.ChangeRequest, error),
+) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !hasPermission(c, "workflow:approve") {
+			c.JSON(http.StatusForbidden, gin.H{"error": translate(c, "approver role required")})
+			return
+		}
+		w, ok := loadWorkflowForReview(c, svc)
+		if !ok {
+			return
+		}
+		requestID, ok := paramUUID(c, "requestId")
+		if !ok {
+			return
+		}
+		actor, ok := actorFrom(c)
+		if !ok {
+			return
+		}
+
+		var input struct {
+			Note string `json:"note"`
+		}
+		if c.Request.ContentLength > 0 {
+			if err := c.ShouldBindJSON(&input); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+		}
+
+		cr, err := decide(c.Request.Context(), w.ID, requestID, actor, input.Note)
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"data": cr})
+	}
+}
+
+// activateWorkflow enables triggers for the published version
+func activateWorkflow(svc *workflow.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		w, ok := loadWorkflow(c, svc)
+		if !ok {
+			return
+		}
+		actor, ok := actorFrom(c)
+		if !ok {
+			return
+		}
+
+		activated, err := svc.Activate(c.Request.Context(), w.ID, actor)
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"data": activated})
+	}
+}
+
+// deactivateWorkflow disables triggers of a workflow
+func deactivateWorkflow(svc *workflow.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		w, ok := loadWorkflow(c, svc)
+		if !ok {
+			return
+		}
+		actor, ok := actorFrom(c)
+		if !ok {
+			return
+		}
+
+		deactivated, err := svc.Deactivate(c.Request.Context(), w.ID, actor)
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"data": deactivated})
+	}
+}
+
+// getWorkflowVersions lists the published versions of a workflow
+func getWorkflowVersions(svc *workflow.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		w, ok := loadWorkflow(c, svc)
+		if !ok {
+			return
+		}
+
+		versions, err := svc.Versions(c.Request.Context(), w.ID)
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{
+			"data":              versions,
+			"draft_version":     w.Version,
+			"published_version": w.PublishedVersion,
+		})
+	}
+}
+
+// restoreWorkflowVersion copies a published version into the draft
+func restoreWorkflowVersion(svc *workflow.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		w, ok := loadWorkflow(c, svc)
+		if !ok {
+			return
+		}
+
+		version, err := strconv.Atoi(c.Param("versionId"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid versionId"})
+			return
+		}
+
+		restored, err := svc.RestoreVersion(c.Request.Context(), w.ID, version)
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"data": restored})
+	}
+}
+
+// getExpressionContext describes what the expressions of a node can
+// reference, for autocomplete in the editor
+func getExpressionContext(svc *workflow.Service, autocomplete *workflow.Autocomplete) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		w, ok := loadWorkflow(c, svc)
+		if !ok {
+			return
+		}
+
+		exprCtx, err := autocomplete.Context(c.Request.Context(), w, c.Param("nodeId"))
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"data": exprCtx})
+	}
+}
@@ -0,0 +1,95 @@
+package v1
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jaydeep/go-n8n/internal/application/workflowimport"
+)
+
+// importPostmanCollection creates a workflow of HTTP Request nodes from a
+// Postman collection and, optionally, one of its environments. The
+// response lists what could not be converted.
+func importPostmanCollection(svc *workflowimport.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !hasPermission(c, "workflow:create") {
+			c.JSON(http.StatusForbidden, gin.H{"error": translate(c, "insufficient permissions")})
+			return
+		}
+		ownerID, ok := currentUserID(c)
+		if !ok {
+			return
+		}
+
+		var input workflowimport.PostmanInput
+		if err := c.ShouldBindJSON(&input); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		result, err := svc.ImportPostman(c.Request.Context(), ownerID, input)
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+		c.JSON(http.StatusCreated, gin.H{"data": result.Workflow, "warnings": result.Warnings})
+	}
+}
+
+// importZapierExport creates a workflow from a zap of a Zapier export.
+// Steps without an equivalent node become placeholders, listed in the
+// warnings of the response.
+func importZapierExport(svc *workflowimport.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !hasPermission(c, "workflow:create") {
+			c.JSON(http.StatusForbidden, gin.H{"error": translate(c, "insufficient permissions")})
+			return
+		}
+		ownerID, ok := currentUserID(c)
+		if !ok {
+			return
+		}
+
+		var input workflowimport.ZapierInput
+		if err := c.ShouldBindJSON(&input); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		result, err := svc.ImportZapier(c.Request.Context(), ownerID, input)
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+		c.JSON(http.StatusCreated, gin.H{"data": result.Workflow, "warnings": result.Warnings})
+	}
+}
+
+// importMakeScenario creates a workflow from the blueprint of a Make
+// scenario. Modules without an equivalent node become placeholders, listed
+// in the warnings of the response.
+func importMakeScenario(svc *workflowimport.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !hasPermission(c, "workflow:create") {
+			c.JSON(http.StatusForbidden, gin.H{"error": translate(c, "insufficient permissions")})
+			return
+		}
+		ownerID, ok := currentUserID(c)
+		if !ok {
+			return
+		}
+
+		var input workflowimport.MakeInput
+		if err := c.ShouldBindJSON(&input); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		result, err := svc.ImportMake(c.Request.Context(), ownerID, input)
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+		c.JSON(http.StatusCreated, gin.H{"data": result.Workflow, "warnings": result.Warnings})
+	}
+}
@@ -0,0 +1,230 @@
+// Package websocket pushes real-time events to connected editor clients.
+package websocket
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	gorilla "github.com/gorilla/websocket"
+	"github.com/jaydeep/go-n8n/pkg/logger"
+)
+
+const (
+	writeWait  = 10 * time.Second
+	pongWait   = 60 * time.Second
+	pingPeriod = pongWait * 9 / 10
+	sendBuffer = 32
+)
+
+// ErrHubClosed is returned for connections opened during shutdown
+var ErrHubClosed = errors.New("websocket hub is shutting down")
+
+// goingAway is the close frame sent to clients on shutdown
+var goingAway = gorilla.FormatCloseMessage(gorilla.CloseGoingAway, "server shutting down")
+
+// Event is a message pushed to clients
+type Event struct {
+	Type string      `json:"type"`
+	Data interface{} `json:"data,omitempty"`
+}
+
+// Event types
+const (
+	EventNodeTypesChanged = "node_types.changed"
+)
+
+// client is a single editor connection
+type client struct {
+	userID string
+	conn   *gorilla.Conn
+	send   chan []byte
+}
+
+// Hub keeps the editor connections and fans events out to them
+type Hub struct {
+	upgrader gorilla.Upgrader
+	log      *logger.Logger
+
+	mu      sync.RWMutex
+	clients map[*client]struct{}
+	closing bool
+	wg      sync.WaitGroup
+}
+
+// NewHub creates a new hub. Origins are checked by the CORS settings of
+// the HTTP server, so the upgrader accepts any origin.
+func NewHub(log *logger.Logger) *Hub {
+	return &Hub{
+		upgrader: gorilla.Upgrader{
+			ReadBufferSize:  1024,
+			WriteBufferSize: 1024,
+			CheckOrigin:     func(r *http.Request) bool { return true },
+		},
+		log:     log,
+		clients: make(map[*client]struct{}),
+	}
+}
+
+// Serve upgrades the request and streams events to the user until the
+// connection closes
+func (h *Hub) Serve(w http.ResponseWriter, r *http.Request, userID string) error {
+	h.mu.RLock()
+	closing := h.closing
+	h.mu.RUnlock()
+	if closing {
+		http.Error(w, ErrHubClosed.Error(), http.StatusServiceUnavailable)
+		return ErrHubClosed
+	}
+
+	conn, err := h.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return err
+	}
+
+	c := &client{userID: userID, conn: conn, send: make(chan []byte, sendBuffer)}
+	h.mu.Lock()
+	if h.closing {
+		h.mu.Unlock()
+		conn.WriteControl(gorilla.CloseMessage, goingAway, time.Now().Add(writeWait))
+		conn.Close()
+		return ErrHubClosed
+	}
+	h.clients[c] = struct{}{}
+	h.wg.Add(1)
+	h.mu.Unlock()
+
+	go h.writePump(c)
+	h.readPump(c)
+	return nil
+}
+
+// Broadcast sends an event to every connected client
+func (h *Hub) Broadcast(event Event) {
+	h.publish(event, func(*client) bool { return true })
+}
+
+// SendToUser sends an event to the connections of one user
+func (h *Hub) SendToUser(userID string, event Event) {
+	h.publish(event, func(c *client) bool { return c.userID == userID })
+}
+
+// Shutdown rejects new connections, asks the clients to close theirs and
+// waits for them to disconnect. Connections still open when ctx expires
+// are closed.
+func (h *Hub) Shutdown(ctx context.Context) error {
+	h.mu.Lock()
+	h.closing = true
+	clients := make([]*client, 0, len(h.clients))
+	for c := range h.clients {
+		clients = append(clients, c)
+	}
+	h.mu.Unlock()
+
+	// Clients answer the close frame, which ends their read pump
+	for _, c := range clients {
+		c.conn.WriteControl(gorilla.CloseMessage, goingAway, time.Now().Add(writeWait))
+	}
+
+	done := make(chan struct{})
+	go func() {
+		h.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		h.mu.RLock()
+		for c := range h.clients {
+			c.conn.Close()
+		}
+		h.mu.RUnlock()
+		return ctx.Err()
+	}
+}
+
+// Clients returns the number of open connections
+func (h *Hub) Clients() int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return len(h.clients)
+}
+
+func (h *Hub) publish(event Event, match func(*client) bool) {
+	data, err := json.Marshal(event)
+	if err != nil {
+		h.log.Errorw("Failed to encode websocket event", "type", event.Type, "error", err)
+		return
+	}
+
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	for c := range h.clients {
+		if !match(c) {
+			continue
+		}
+		select {
+		case c.send <- data:
+		default:
+			// Slow clients miss events rather than blocking publishers
+			h.log.Warnw("Dropping websocket event for slow client", "type", event.Type, "user_id", c.userID)
+		}
+	}
+}
+
+// readPump consumes client frames (only control frames are expected) and
+// unregisters the client when the connection closes
+func (h *Hub) readPump(c *client) {
+	defer func() {
+		h.mu.Lock()
+		delete(h.clients, c)
+		h.mu.Unlock()
+		close(c.send)
+		c.conn.Close()
+		h.wg.Done()
+	}()
+
+	c.conn.SetReadLimit(4096)
+	c.conn.SetReadDeadline(time.Now().Add(pongWait))
+	c.conn.SetPongHandler(func(string) error {
+		return c.conn.SetReadDeadline(time.Now().Add(pongWait))
+	})
+
+	for {
+		if _, _, err := c.conn.ReadMessage(); err != nil {
+			return
+		}
+	}
+}
+
+func (h *Hub) writePump(c *client) {
+	ticker := time.NewTicker(pingPeriod)
+	defer func() {
+		ticker.Stop()
+		c.conn.Close()
+	}()
+
+	for {
+		select {
+		case data, ok := <-c.send:
+			c.conn.SetWriteDeadline(time.Now().Add(writeWait))
+			if !ok {
+				c.conn.WriteMessage(gorilla.CloseMessage, []byte{})
+				return
+			}
+			if err := c.conn.WriteMessage(gorilla.TextMessage, data); err != nil {
+				return
+			}
+		case <-ticker.C:
+			c.conn.SetWriteDeadline(time.Now().Add(writeWait))
+			if err := c.conn.WriteMessage(gorilla.PingMessage, nil); err != nil {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,370 @@
+package action
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/jaydeep/go-n8n/internal/domain/node"
+	"github.com/jaydeep/go-n8n/pkg/binarydata"
+	"github.com/jaydeep/go-n8n/pkg/nodesdk"
+)
+
+// HTTPRequestType is the node type of the HTTP request node
+const HTTPRequestType = "http_request"
+
+// Response formats of the HTTP request node
+const (
+	ResponseFormatJSON   = "json"
+	ResponseFormatText   = "text"
+	ResponseFormatBinary = "binary"
+)
+
+var httpMethods = []string{"GET", "POST", "PUT", "PATCH", "DELETE", "HEAD", "OPTIONS"}
+
+// HTTPRequestNode sends an HTTP request for each input item. A binary
+// property of the item can be sent as the request body and the response
+// can be kept as binary data. With a binary data store, both are streamed
+// through the store, so a download can be uploaded by a following node
+// without either holding the file in memory.
+type HTTPRequestNode struct {
+	nodesdk.BaseNode
+}
+
+// NewHTTPRequestNode creates a new HTTP request node
+func NewHTTPRequestNode() node.NodeInterface {
+	return &HTTPRequestNode{
+		BaseNode: nodesdk.BaseNode{
+			Type:        HTTPRequestType,
+			Name:        "HTTP Request",
+			Category:    node.CategoryAction,
+			Version:     "1.0",
+			Description: "Sends an HTTP request and outputs the response",
+			Icon:        "fa:globe",
+		},
+	}
+}
+
+// Execute sends the request of each item in turn, with the parameters
+// evaluated for the item
+func (n *HTTPRequestNode) Execute(ctx context.Context, input *node.NodeInput) (*node.NodeOutput, error) {
+	client := nodesdk.HTTPClientFrom(ctx)
+	store := binarydata.FromContext(ctx)
+	credentialID := nodesdk.CredentialID(input)
+
+	calls := 0
+	output, err := nodesdk.ProcessItems(ctx, input, func(ctx context.Context, item node.Item, i int) (node.Item, error) {
+		params, err := nodesdk.ItemParams(input, i)
+		if err != nil {
+			return node.Item{}, err
+		}
+		req, err := n.request(ctx, params, item, store)
+		if err != nil {
+			return node.Item{}, err
+		}
+		if creds, err := nodesdk.Credentials(input); err == nil {
+			if err := nodesdk.ApplyAuth(req, creds); err != nil {
+				return node.Item{}, err
+			}
+		}
+
+		calls++
+		resp, err := client.Do(ctx, credentialID, req)
+		if err != nil {
+			return node.Item{}, err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode >= http.StatusBadRequest {
+			return node.Item{}, fmt.Errorf("request failed with status %d", resp.StatusCode)
+		}
+		return n.response(ctx, params, item, resp, store)
+	})
+	if output != nil {
+		output.AddUsage(node.Usage{HTTPCalls: int64(calls)})
+	}
+	return output, err
+}
+
+// request builds the request of an item. The body is the item's binary
+// property named by send_binary, streamed from the store when kept there,
+// or else the body parameter.
+func (n *HTTPRequestNode) request(ctx context.Context, params nodesdk.Params, item node.Item, store binarydata.Store) (*http.Request, error) {
+	method := strings.ToUpper(params.String("method", http.MethodGet))
+	target := params.String("url", "")
+
+	var req *http.Request
+	var err error
+	if name := params.String("send_binary", ""); name != "" {
+		data, ok := item.Binary[name]
+		if !ok {
+			return nil, fmt.Errorf("item has no binary property %q", name)
+		}
+		req, err = binaryRequest(ctx, method, target, data, store)
+	} else {
+		var body []byte
+		body, err = requestBody(params)
+		if err == nil {
+			req, err = http.NewRequestWithContext(ctx, method, target, bytes.NewReader(body))
+		}
+		if err == nil && len(body) > 0 && params.String("content_type", "") == "" {
+			req.Header.Set("Content-Type", "application/json")
+		}
+		if err == nil && len(body) == 0 {
+			req.Body, req.GetBody, req.ContentLength = http.NoBody, nil, 0
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for k, v := range params.Map("headers") {
+		req.Header.Set(k, fmt.Sprint(v))
+	}
+	if contentType := params.String("content_type", ""); contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	return req, nil
+}
+
+// binaryRequest builds a request sending binary data. Stored data is read
+// from the store as the request is sent and opened again should the
+// request be retried.
+func binaryRequest(ctx context.Context, method, target string, data node.Binary, store binarydata.Store) (*http.Request, error) {
+	if !data.Stored() {
+		req, err := http.NewRequestWithContext(ctx, method, target, bytes.NewReader(data.Data))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", data.MimeType)
+		return req, nil
+	}
+	if store == nil {
+		return nil, errors.New("binary data store is not available")
+	}
+
+	open := func() (io.ReadCloser, error) { return store.Open(ctx, data.ID) }
+	body, err := open()
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, method, target, body)
+	if err != nil {
+		body.Close()
+		return nil, err
+	}
+	req.GetBody = open
+	req.ContentLength = data.FileSize
+	req.Header.Set("Content-Type", data.MimeType)
+	return req, nil
+}
+
+// requestBody encodes the body parameter: strings are sent as they are,
+// other values as JSON
+func requestBody(params nodesdk.Params) ([]byte, error) {
+	body, ok := params["body"]
+	if !ok || body == nil {
+		return nil, nil
+	}
+	if s, ok := body.(string); ok {
+		return []byte(s), nil
+	}
+	return json.Marshal(body)
+}
+
+// response turns a response into the output item. Binary responses are
+// streamed into the store when there is one, and read into the item
+// otherwise.
+func (n *HTTPRequestNode) response(ctx context.Context, params nodesdk.Params, item node.Item, resp *http.Response, store binarydata.Store) (node.Item, error) {
+	fields := map[string]interface{}{
+		"status_code": resp.StatusCode,
+		"headers":     flattenHeaders(resp.Header),
+	}
+	editor := item.Edit()
+
+	switch params.String("response_format", ResponseFormatJSON) {
+	case ResponseFormatBinary:
+		data := node.Binary{
+			MimeType: resp.Header.Get("Content-Type"),
+			FileName: fileName(resp),
+		}
+		if store != nil {
+			id, size, err := store.Put(ctx, resp.Body)
+			if err != nil {
+				return node.Item{}, fmt.Errorf("store response: %w", err)
+			}
+			data.ID, data.FileSize = id, size
+		} else {
+			body, err := io.ReadAll(resp.Body)
+			if err != nil {
+				return node.Item{}, err
+			}
+			data.Data, data.FileSize = body, int64(len(body))
+		}
+		editor.SetBinary(params.String("binary_property", "data"), data)
+	case ResponseFormatText:
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return node.Item{}, err
+		}
+		fields["body"] = string(body)
+	default:
+		var body interface{}
+		if err := json.NewDecoder(resp.Body).Decode(&body); err != nil && !errors.Is(err, io.EOF) {
+			return node.Item{}, fmt.Errorf("decode response: %w", err)
+		}
+		fields["body"] = body
+	}
+
+	return editor.Merge(fields).Item(), nil
+}
+
+// fileName returns the file name of a response, from its
+// Content-Disposition header or the last segment of the URL path
+func fileName(resp *http.Response) string {
+	if _, params, err := mime.ParseMediaType(resp.Header.Get("Content-Disposition")); err == nil && params["filename"] != "" {
+		return params["filename"]
+	}
+	if resp.Request != nil {
+		if name := path.Base(resp.Request.URL.Path); name != "/" && name != "." {
+			return name
+		}
+	}
+	return "file"
+}
+
+func flattenHeaders(h http.Header) map[string]interface{} {
+	flat := make(map[string]interface{}, len(h))
+	for k := range h {
+		flat[strings.ToLower(k)] = h.Get(k)
+	}
+	return flat
+}
+
+// Validate validates the node parameters
+func (n *HTTPRequestNode) Validate(parameters map[string]interface{}) error {
+	target := nodesdk.GetString(parameters, "url", "")
+	if target == "" {
+		return errors.New("url is required")
+	}
+	// URLs built by expressions are only known at run time
+	if !strings.Contains(target, "{{") {
+		u, err := url.Parse(target)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return errors.New("url must be an absolute http or https URL")
+		}
+	}
+
+	method := strings.ToUpper(nodesdk.GetString(parameters, "method", http.MethodGet))
+	valid := false
+	for _, m := range httpMethods {
+		valid = valid || m == method
+	}
+	if !valid {
+		return errors.New("method must be one of " + strings.Join(httpMethods, ", "))
+	}
+
+	switch nodesdk.GetString(parameters, "response_format", ResponseFormatJSON) {
+	case ResponseFormatJSON, ResponseFormatText, ResponseFormatBinary:
+	default:
+		return errors.New("response_format must be json, text or binary")
+	}
+	return nil
+}
+
+// GetSchema returns the node schema
+func (n *HTTPRequestNode) GetSchema() *node.NodeSchema {
+	methods := make([]node.PropertyOption, 0, len(httpMethods))
+	for _, m := range httpMethods {
+		methods = append(methods, node.PropertyOption{Name: m, Value: m})
+	}
+
+	return &node.NodeSchema{
+		Type:        n.Type,
+		Name:        n.Name,
+		Group:       []string{"action"},
+		Version:     1,
+		Description: n.Description,
+		Icon:        n.Icon,
+		Defaults:    node.NodeDefaults{Name: n.Name},
+		Inputs:      []node.IOSchema{{Type: "main"}},
+		Outputs:     []node.IOSchema{{Type: "main"}},
+		Properties: []node.PropertySchema{
+			{
+				Name:        "method",
+				DisplayName: "Method",
+				Type:        node.PropertyTypeOptions,
+				Default:     http.MethodGet,
+				Options:     methods,
+			},
+			{
+				Name:        "url",
+				DisplayName: "URL",
+				Type:        node.PropertyTypeString,
+				Required:    true,
+				Description: "Absolute http or https URL to send the request to",
+			},
+			{
+				Name:        "headers",
+				DisplayName: "Headers",
+				Type:        node.PropertyTypeJSON,
+				Description: "Request headers by name",
+			},
+			{
+				Name:        "content_type",
+				DisplayName: "Content Type",
+				Type:        node.PropertyTypeString,
+				Description: "Content type of the request body; defaults to JSON, or the MIME type of binary data",
+			},
+			{
+				Name:        "body",
+				DisplayName: "Body",
+				Type:        node.PropertyTypeJSON,
+				Description: "Request body; strings are sent as they are, other values as JSON",
+			},
+			{
+				Name:        "send_binary",
+				DisplayName: "Send Binary Property",
+				Type:        node.PropertyTypeString,
+				Description: "Binary property of the item sent as the request body instead of the body, streamed from storage",
+			},
+			{
+				Name:        "response_format",
+				DisplayName: "Response Format",
+				Type:        node.PropertyTypeOptions,
+				Default:     ResponseFormatJSON,
+				Options: []node.PropertyOption{
+					{Name: "JSON", Value: ResponseFormatJSON},
+					{Name: "Text", Value: ResponseFormatText},
+					{Name: "Binary", Value: ResponseFormatBinary},
+				},
+			},
+			{
+				Name:        "binary_property",
+				DisplayName: "Binary Property",
+				Type:        node.PropertyTypeString,
+				Default:     "data",
+				Description: "Binary property the response is kept in, streamed to storage",
+				DisplayOptions: &node.DisplayOptions{
+					Show: map[string][]interface{}{"response_format": {ResponseFormatBinary}},
+				},
+			},
+		},
+	}
+}
+
+// GetDefaultParameters returns the default parameters
+func (n *HTTPRequestNode) GetDefaultParameters() map[string]interface{} {
+	return map[string]interface{}{
+		"method":          http.MethodGet,
+		"response_format": ResponseFormatJSON,
+		"binary_property": "data",
+	}
+}
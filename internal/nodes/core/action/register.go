@@ -0,0 +1,23 @@
+package action
+
+import (
+	"embed"
+
+	"github.com/jaydeep/go-n8n/internal/domain/node"
+	"github.com/jaydeep/go-n8n/pkg/i18n"
+	"github.com/jaydeep/go-n8n/pkg/nodesdk"
+)
+
+//go:embed icons locales
+var assets embed.FS
+
+// Register adds the action nodes, their icons and translations
+func Register(r *node.NodeRegistry, bundle *i18n.Bundle) error {
+	if err := r.Register(HTTPRequestType, node.CategoryAction, NewHTTPRequestNode); err != nil {
+		return err
+	}
+	if err := nodesdk.RegisterIcon(r, HTTPRequestType, assets, "icons/http_request.svg"); err != nil {
+		return err
+	}
+	return nodesdk.RegisterTranslations(bundle, assets, "locales")
+}
@@ -0,0 +1,107 @@
+package flow
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jaydeep/go-n8n/internal/domain/node"
+	"github.com/jaydeep/go-n8n/pkg/nodesdk"
+)
+
+// ExecuteWorkflowType is the node type of the execute workflow node
+const ExecuteWorkflowType = "execute_workflow"
+
+// ExecuteWorkflowNode runs the published version of another workflow as a
+// sub-workflow, with the items it receives as the input of the workflow's
+// first nodes. Waiting for it, the node outputs the items the sub-workflow
+// output and fails when it fails. Otherwise the sub-workflow is queued and
+// the items pass through unchanged.
+type ExecuteWorkflowNode struct {
+	nodesdk.BaseNode
+}
+
+// NewExecuteWorkflowNode creates a new execute workflow node
+func NewExecuteWorkflowNode() node.NodeInterface {
+	return &ExecuteWorkflowNode{
+		BaseNode: nodesdk.BaseNode{
+			Type:        ExecuteWorkflowType,
+			Name:        "Execute Workflow",
+			Category:    node.CategoryFlow,
+			Version:     "1.0",
+			Description: "Runs another workflow with the items and returns what it outputs",
+			Icon:        "fa:sign-in-alt",
+		},
+	}
+}
+
+// Execute runs the sub-workflow
+func (n *ExecuteWorkflowNode) Execute(ctx context.Context, input *node.NodeInput) (*node.NodeOutput, error) {
+	params := nodesdk.Params(input.Parameters)
+	runner := node.WorkflowRunnerFrom(ctx)
+	if runner == nil {
+		return nil, errors.New("sub-workflows can only run within an execution")
+	}
+
+	wait := params.Bool("wait", true)
+	_, items, err := runner.RunWorkflow(ctx, params.String("workflow_id", ""), input.Data, wait)
+	if err != nil {
+		return nil, err
+	}
+	if !wait {
+		return &node.NodeOutput{Data: input.Data}, nil
+	}
+	return &node.NodeOutput{Data: items}, nil
+}
+
+// Validate validates the node parameters
+func (n *ExecuteWorkflowNode) Validate(parameters map[string]interface{}) error {
+	workflowID := nodesdk.GetString(parameters, "workflow_id", "")
+	if workflowID == "" {
+		return errors.New("workflow_id is required")
+	}
+	// IDs built by expressions are only known at run time
+	if _, err := uuid.Parse(workflowID); err != nil && !strings.Contains(workflowID, "{{") {
+		return errors.New("workflow_id must be a workflow ID")
+	}
+	return nil
+}
+
+// GetSchema returns the node schema
+func (n *ExecuteWorkflowNode) GetSchema() *node.NodeSchema {
+	return &node.NodeSchema{
+		Type:        n.Type,
+		Name:        n.Name,
+		Group:       []string{"flow"},
+		Version:     1,
+		Description: n.Description,
+		Icon:        n.Icon,
+		Defaults:    node.NodeDefaults{Name: n.Name},
+		Inputs:      []node.IOSchema{{Type: "main"}},
+		Outputs:     []node.IOSchema{{Type: "main"}},
+		Properties: []node.PropertySchema{
+			{
+				Name:        "workflow_id",
+				DisplayName: "Workflow ID",
+				Type:        node.PropertyTypeString,
+				Required:    true,
+				Description: "Workflow to run; its published version runs",
+			},
+			{
+				Name:        "wait",
+				DisplayName: "Wait for Sub-Workflow",
+				Type:        node.PropertyTypeBoolean,
+				Default:     true,
+				Description: "Whether to wait for the workflow and output its items, or queue it and pass the items on",
+			},
+		},
+	}
+}
+
+// GetDefaultParameters returns the default parameters
+func (n *ExecuteWorkflowNode) GetDefaultParameters() map[string]interface{} {
+	return map[string]interface{}{
+		"wait": true,
+	}
+}
@@ -0,0 +1,58 @@
+package flow
+
+import (
+	"context"
+
+	"github.com/jaydeep/go-n8n/internal/domain/node"
+	"github.com/jaydeep/go-n8n/pkg/nodesdk"
+)
+
+// NoOpType is the node type of the no operation node
+const NoOpType = "no_op"
+
+// NoOpNode passes its items on unchanged. It marks a place in a workflow,
+// such as a step of an imported workflow that is yet to be built, whose
+// notes say what it should do.
+type NoOpNode struct {
+	nodesdk.BaseNode
+}
+
+// NewNoOpNode creates a new no operation node
+func NewNoOpNode() node.NodeInterface {
+	return &NoOpNode{
+		BaseNode: nodesdk.BaseNode{
+			Type:        NoOpType,
+			Name:        "No Operation",
+			Category:    node.CategoryFlow,
+			Version:     "1.0",
+			Description: "Passes the items on unchanged",
+			Icon:        "fa:arrow-right",
+		},
+	}
+}
+
+// Execute passes the items on
+func (n *NoOpNode) Execute(ctx context.Context, input *node.NodeInput) (*node.NodeOutput, error) {
+	return &node.NodeOutput{Data: input.Data}, nil
+}
+
+// Validate accepts any parameters
+func (n *NoOpNode) Validate(parameters map[string]interface{}) error {
+	return nil
+}
+
+// GetSchema returns the node schema
+func (n *NoOpNode) GetSchema() *node.NodeSchema {
+	return &node.NodeSchema{
+		Type:        n.Type,
+		Name:        n.Name,
+		Group:       []string{"flow"},
+		Version:     1,
+		Description: n.Description,
+		Icon:        n.Icon,
+		Defaults:    node.NodeDefaults{Name: n.Name},
+		Inputs:      []node.IOSchema{{Type: "main"}},
+		Outputs:     []node.IOSchema{{Type: "main"}},
+		Properties:  []node.PropertySchema{},
+	}
+}
@@ -0,0 +1,47 @@
+package flow
+
+import (
+	"embed"
+
+	"github.com/jaydeep/go-n8n/internal/domain/node"
+	"github.com/jaydeep/go-n8n/pkg/i18n"
+	"github.com/jaydeep/go-n8n/pkg/nodesdk"
+)
+
+//go:embed icons locales
+var assets embed.FS
+
+// Register adds the flow nodes, their icons and translations
+func Register(r *node.NodeRegistry, bundle *i18n.Bundle) error {
+	if err := r.Register(RespondToWebhookType, node.CategoryFlow, NewRespondToWebhookNode); err != nil {
+		return err
+	}
+	if err := nodesdk.RegisterIcon(r, RespondToWebhookType, assets, "icons/respond_to_webhook.svg"); err != nil {
+		return err
+	}
+	if err := r.Register(WaitType, node.CategoryFlow, NewWaitNode); err != nil {
+		return err
+	}
+	if err := nodesdk.RegisterIcon(r, WaitType, assets, "icons/wait.svg"); err != nil {
+		return err
+	}
+	if err := r.Register(ExecuteWorkflowType, node.CategoryFlow, NewExecuteWorkflowNode); err != nil {
+		return err
+	}
+	if err := nodesdk.RegisterIcon(r, ExecuteWorkflowType, assets, "icons/execute_workflow.svg"); err != nil {
+		return err
+	}
+	if err := r.Register(NoOpType, node.CategoryFlow, NewNoOpNode); err != nil {
+		return err
+	}
+	if err := nodesdk.RegisterIcon(r, NoOpType, assets, "icons/no_op.svg"); err != nil {
+		return err
+	}
+	if err := r.Register(SplitInBatchesType, node.CategoryFlow, NewSplitInBatchesNode); err != nil {
+		return err
+	}
+	if err := nodesdk.RegisterIcon(r, SplitInBatchesType, assets, "icons/split_in_batches.svg"); err != nil {
+		return err
+	}
+	return nodesdk.RegisterTranslations(bundle, assets, "locales")
+}
@@ -0,0 +1,116 @@
+package flow
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/jaydeep/go-n8n/internal/domain/node"
+	"github.com/jaydeep/go-n8n/pkg/nodesdk"
+)
+
+// RespondToWebhookType is the node type of the respond to webhook node
+const RespondToWebhookType = "respond_to_webhook"
+
+// RespondToWebhookNode defines the HTTP response sent to the request that
+// started the workflow. Items pass through unchanged; the status code is
+// reported in the output metadata.
+type RespondToWebhookNode struct {
+	nodesdk.BaseNode
+}
+
+// ResponseSettings holds the respond node parameters used by the HTTP
+// endpoints
+type ResponseSettings struct {
+	StatusCode    int
+	ResponseField string
+	// ResponseSchema documents the response body
+	ResponseSchema map[string]interface{}
+}
+
+// NewRespondToWebhookNode creates a new respond to webhook node
+func NewRespondToWebhookNode() node.NodeInterface {
+	return &RespondToWebhookNode{
+		BaseNode: nodesdk.BaseNode{
+			Type:        RespondToWebhookType,
+			Name:        "Respond to Webhook",
+			Category:    node.CategoryFlow,
+			Version:     "1.0",
+			Description: "Sets the HTTP response to the request that started the workflow",
+			Icon:        "fa:reply",
+		},
+	}
+}
+
+// Execute passes the items through and records the response status
+func (n *RespondToWebhookNode) Execute(ctx context.Context, input *node.NodeInput) (*node.NodeOutput, error) {
+	settings := ParseResponseSettings(input.Parameters)
+	return &node.NodeOutput{
+		Data: input.Data,
+		Metadata: map[string]interface{}{
+			"response_status": settings.StatusCode,
+			"response_field":  settings.ResponseField,
+		},
+	}, nil
+}
+
+// Validate validates the node parameters
+func (n *RespondToWebhookNode) Validate(parameters map[string]interface{}) error {
+	code := nodesdk.GetInt(parameters, "status_code", http.StatusOK)
+	if code < 100 || code > 599 {
+		return errors.New("status_code must be between 100 and 599")
+	}
+	return nil
+}
+
+// GetSchema returns the node schema
+func (n *RespondToWebhookNode) GetSchema() *node.NodeSchema {
+	return &node.NodeSchema{
+		Type:        n.Type,
+		Name:        n.Name,
+		Group:       []string{"flow"},
+		Version:     1,
+		Description: n.Description,
+		Icon:        n.Icon,
+		Defaults:    node.NodeDefaults{Name: n.Name},
+		Inputs:      []node.IOSchema{{Type: "main"}},
+		Outputs:     []node.IOSchema{{Type: "main"}},
+		Properties: []node.PropertySchema{
+			{
+				Name:        "status_code",
+				DisplayName: "Status Code",
+				Type:        node.PropertyTypeNumber,
+				Default:     http.StatusOK,
+				Description: "HTTP status code of the response",
+			},
+			{
+				Name:        "response_field",
+				DisplayName: "Response Field",
+				Type:        node.PropertyTypeString,
+				Description: "Field of the first item sent as the response body; empty sends the whole item",
+			},
+			{
+				Name:        "response_schema",
+				DisplayName: "Response Schema",
+				Type:        node.PropertyTypeJSON,
+				Description: "JSON schema of the response body, published in the endpoint documentation",
+			},
+		},
+	}
+}
+
+// GetDefaultParameters returns the default parameters
+func (n *RespondToWebhookNode) GetDefaultParameters() map[string]interface{} {
+	return map[string]interface{}{
+		"status_code": http.StatusOK,
+	}
+}
+
+// ParseResponseSettings reads the respond node parameters, applying defaults
+func ParseResponseSettings(parameters map[string]interface{}) ResponseSettings {
+	return ResponseSettings{
+		StatusCode:     nodesdk.GetInt(parameters, "status_code", http.StatusOK),
+		ResponseField:  nodesdk.GetString(parameters, "response_field", ""),
+		ResponseSchema: nodesdk.GetMap(parameters, "response_schema"),
+	}
+}
@@ -0,0 +1,139 @@
+package flow
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jaydeep/go-n8n/internal/domain/node"
+	"github.com/jaydeep/go-n8n/pkg/nodesdk"
+)
+
+// SplitInBatchesType is the node type of the split in batches node
+const SplitInBatchesType = "split_in_batches"
+
+// Outputs of the split in batches node
+const (
+	SplitInBatchesDoneOutput = 0
+	SplitInBatchesLoopOutput = 1
+)
+
+const defaultBatchSize = 10
+
+// SplitInBatchesNode loops over its items a batch at a time. Each run
+// outputs the next batch on its loop output, for the nodes connected after
+// it, whose last connects back to it; once no item is left it outputs the
+// items the loop brought back on its done output. The engine keeps the
+// items between the runs of the node.
+type SplitInBatchesNode struct {
+	nodesdk.BaseNode
+}
+
+// NewSplitInBatchesNode creates a new split in batches node
+func NewSplitInBatchesNode() node.NodeInterface {
+	return &SplitInBatchesNode{
+		BaseNode: nodesdk.BaseNode{
+			Type:        SplitInBatchesType,
+			Name:        "Split In Batches",
+			Category:    node.CategoryFlow,
+			Version:     "1.0",
+			Description: "Loops over the items in batches of a given size",
+			Icon:        "fa:sync",
+		},
+	}
+}
+
+// Execute outputs the next batch of items on the loop output, or the items
+// brought back by the loop on the done output once every batch has run.
+// The first run of a loop takes the items to split, the next ones the
+// items a batch became.
+func (n *SplitInBatchesNode) Execute(ctx context.Context, input *node.NodeInput) (*node.NodeOutput, error) {
+	state := make(map[string]interface{})
+	if input.Context != nil && input.Context.State != nil {
+		state = input.Context.State
+	}
+
+	remaining, looping := state["remaining"].([]node.Item)
+	processed, _ := state["processed"].([]node.Item)
+	if looping {
+		processed = append(processed, input.Data...)
+	} else {
+		remaining, processed = input.Data, []node.Item{}
+	}
+	if len(remaining) == 0 {
+		delete(state, "remaining")
+		delete(state, "processed")
+		return &node.NodeOutput{Data: processed, Output: SplitInBatchesDoneOutput}, nil
+	}
+
+	size := nodesdk.Params(input.Parameters).Int("batch_size", defaultBatchSize)
+	if size > len(remaining) {
+		size = len(remaining)
+	}
+	state["remaining"] = remaining[size:]
+	state["processed"] = processed
+	return &node.NodeOutput{
+		Data:     remaining[:size],
+		Output:   SplitInBatchesLoopOutput,
+		Metadata: map[string]interface{}{"remaining": len(remaining) - size},
+	}, nil
+}
+
+// LoopOutput returns the loop output, the one leading into the loop
+func (n *SplitInBatchesNode) LoopOutput() int {
+	return SplitInBatchesLoopOutput
+}
+
+// MaxIterations returns the max_iterations parameter
+func (n *SplitInBatchesNode) MaxIterations(parameters map[string]interface{}) int {
+	return nodesdk.Params(parameters).Int("max_iterations", 0)
+}
+
+// Validate validates the node parameters
+func (n *SplitInBatchesNode) Validate(parameters map[string]interface{}) error {
+	params := nodesdk.Params(parameters)
+	if params.Int("batch_size", defaultBatchSize) < 1 {
+		return errors.New("batch_size must be at least 1")
+	}
+	if params.Int("max_iterations", 0) < 0 {
+		return errors.New("max_iterations must not be negative")
+	}
+	return nil
+}
+
+// GetSchema returns the node schema
+func (n *SplitInBatchesNode) GetSchema() *node.NodeSchema {
+	return &node.NodeSchema{
+		Type:        n.Type,
+		Name:        n.Name,
+		Group:       []string{"flow"},
+		Version:     1,
+		Description: n.Description,
+		Icon:        n.Icon,
+		Defaults:    node.NodeDefaults{Name: n.Name},
+		Inputs:      []node.IOSchema{{Type: "main"}},
+		Outputs:     []node.IOSchema{{Type: "main", Label: "done"}, {Type: "main", Label: "loop"}},
+		Properties: []node.PropertySchema{
+			{
+				Name:        "batch_size",
+				DisplayName: "Batch Size",
+				Type:        node.PropertyTypeNumber,
+				Default:     defaultBatchSize,
+				Description: "Number of items in each batch",
+			},
+			{
+				Name:        "max_iterations",
+				DisplayName: "Max Iterations",
+				Type:        node.PropertyTypeNumber,
+				Default:     0,
+				Description: "Most times the loop may run before the execution fails; 0 keeps the limit of the engine",
+			},
+		},
+	}
+}
+
+// GetDefaultParameters returns the default parameters
+func (n *SplitInBatchesNode) GetDefaultParameters() map[string]interface{} {
+	return map[string]interface{}{
+		"batch_size": defaultBatchSize,
+	}
+}
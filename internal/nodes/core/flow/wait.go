@@ -0,0 +1,193 @@
+package flow
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/jaydeep/go-n8n/internal/domain/node"
+	"github.com/jaydeep/go-n8n/pkg/nodesdk"
+)
+
+// WaitType is the node type of the wait node
+const WaitType = "wait"
+
+// Ways the wait node resumes the execution
+const (
+	ResumeAfterInterval = "time_interval"
+	ResumeAtTime        = "specific_time"
+	ResumeOnWebhook     = "webhook"
+)
+
+var waitUnits = map[string]time.Duration{
+	"seconds": time.Second,
+	"minutes": time.Minute,
+	"hours":   time.Hour,
+	"days":    24 * time.Hour,
+}
+
+// WaitNode suspends the execution until a time interval has passed, until
+// a given time or until it is resumed through the resume endpoint. The
+// engine keeps the state of the execution meanwhile, so waits may outlast
+// the worker that ran the node. Items pass through unchanged, unless the
+// resume request carries data.
+type WaitNode struct {
+	nodesdk.BaseNode
+}
+
+// NewWaitNode creates a new wait node
+func NewWaitNode() node.NodeInterface {
+	return &WaitNode{
+		BaseNode: nodesdk.BaseNode{
+			Type:        WaitType,
+			Name:        "Wait",
+			Category:    node.CategoryFlow,
+			Version:     "1.0",
+			Description: "Pauses the execution until a time or until it is resumed by a webhook call",
+			Icon:        "fa:pause-circle",
+		},
+	}
+}
+
+// Execute asks the engine to suspend the execution. Waits that have
+// already ended pass the items on at once.
+func (n *WaitNode) Execute(ctx context.Context, input *node.NodeInput) (*node.NodeOutput, error) {
+	params := nodesdk.Params(input.Parameters)
+	now := time.Now()
+
+	switch params.String("resume", ResumeAfterInterval) {
+	case ResumeOnWebhook:
+		wait := &node.WaitError{Webhook: true}
+		if timeout := params.Duration("timeout", 0); timeout > 0 {
+			wait.Until = now.Add(timeout)
+		}
+		return nil, wait
+	case ResumeAtTime:
+		until, err := time.Parse(time.RFC3339, params.String("date_time", ""))
+		if err != nil {
+			return nil, errors.New("date_time must be an RFC 3339 time")
+		}
+		if until.After(now) {
+			return nil, &node.WaitError{Until: until}
+		}
+	default:
+		if d := waitInterval(params); d > 0 {
+			return nil, &node.WaitError{Until: now.Add(d)}
+		}
+	}
+	return &node.NodeOutput{Data: input.Data}, nil
+}
+
+// waitInterval returns the interval of the amount and unit parameters
+func waitInterval(params nodesdk.Params) time.Duration {
+	unit := waitUnits[params.String("unit", "minutes")]
+	return time.Duration(params.Float("amount", 1) * float64(unit))
+}
+
+// Validate validates the node parameters
+func (n *WaitNode) Validate(parameters map[string]interface{}) error {
+	params := nodesdk.Params(parameters)
+	switch params.String("resume", ResumeAfterInterval) {
+	case ResumeAfterInterval:
+		if _, ok := waitUnits[params.String("unit", "minutes")]; !ok {
+			return errors.New("unit must be seconds, minutes, hours or days")
+		}
+		if params.Float("amount", 1) < 0 {
+			return errors.New("amount must not be negative")
+		}
+	case ResumeAtTime:
+		// Times built by expressions are only known at run time
+		dateTime := params.String("date_time", "")
+		if dateTime == "" {
+			return errors.New("date_time is required")
+		}
+		if _, err := time.Parse(time.RFC3339, dateTime); err != nil && !strings.Contains(dateTime, "{{") {
+			return errors.New("date_time must be an RFC 3339 time")
+		}
+	case ResumeOnWebhook:
+		if timeout := params.String("timeout", ""); timeout != "" {
+			if d, err := time.ParseDuration(timeout); err != nil || d <= 0 {
+				return errors.New("timeout must be a positive duration such as 24h")
+			}
+		}
+	default:
+		return errors.New("resume must be time_interval, specific_time or webhook")
+	}
+	return nil
+}
+
+// GetSchema returns the node schema
+func (n *WaitNode) GetSchema() *node.NodeSchema {
+	show := func(resume string) *node.DisplayOptions {
+		return &node.DisplayOptions{Show: map[string][]interface{}{"resume": {resume}}}
+	}
+
+	return &node.NodeSchema{
+		Type:        n.Type,
+		Name:        n.Name,
+		Group:       []string{"flow"},
+		Version:     1,
+		Description: n.Description,
+		Icon:        n.Icon,
+		Defaults:    node.NodeDefaults{Name: n.Name},
+		Inputs:      []node.IOSchema{{Type: "main"}},
+		Outputs:     []node.IOSchema{{Type: "main"}},
+		Properties: []node.PropertySchema{
+			{
+				Name:        "resume",
+				DisplayName: "Resume",
+				Type:        node.PropertyTypeOptions,
+				Default:     ResumeAfterInterval,
+				Options: []node.PropertyOption{
+					{Name: "After Time Interval", Value: ResumeAfterInterval},
+					{Name: "At Specified Time", Value: ResumeAtTime},
+					{Name: "On Webhook Call", Value: ResumeOnWebhook},
+				},
+			},
+			{
+				Name:           "amount",
+				DisplayName:    "Amount",
+				Type:           node.PropertyTypeNumber,
+				Default:        1,
+				DisplayOptions: show(ResumeAfterInterval),
+			},
+			{
+				Name:        "unit",
+				DisplayName: "Unit",
+				Type:        node.PropertyTypeOptions,
+				Default:     "minutes",
+				Options: []node.PropertyOption{
+					{Name: "Seconds", Value: "seconds"},
+					{Name: "Minutes", Value: "minutes"},
+					{Name: "Hours", Value: "hours"},
+					{Name: "Days", Value: "days"},
+				},
+				DisplayOptions: show(ResumeAfterInterval),
+			},
+			{
+				Name:           "date_time",
+				DisplayName:    "Date and Time",
+				Type:           node.PropertyTypeDateTime,
+				Description:    "RFC 3339 time to resume at",
+				DisplayOptions: show(ResumeAtTime),
+			},
+			{
+				Name:           "timeout",
+				DisplayName:    "Timeout",
+				Type:           node.PropertyTypeString,
+				Description:    "Duration such as 24h after which the execution resumes without a call; empty waits for the call",
+				DisplayOptions: show(ResumeOnWebhook),
+			},
+		},
+	}
+}
+
+// GetDefaultParameters returns the default parameters
+func (n *WaitNode) GetDefaultParameters() map[string]interface{} {
+	return map[string]interface{}{
+		"resume": ResumeAfterInterval,
+		"amount": 1,
+		"unit":   "minutes",
+	}
+}
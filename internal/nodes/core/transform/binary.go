@@ -0,0 +1,112 @@
+package transform
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"mime"
+	"path"
+
+	"github.com/jaydeep/go-n8n/internal/domain/node"
+	"github.com/jaydeep/go-n8n/pkg/binarydata"
+)
+
+// openBinary returns a reader of binary data, streamed from the store when
+// kept there
+func openBinary(ctx context.Context, store binarydata.Store, data node.Binary) (io.ReadCloser, error) {
+	if !data.Stored() {
+		return io.NopCloser(bytes.NewReader(data.Data)), nil
+	}
+	if store == nil {
+		return nil, errors.New("binary data store is not available")
+	}
+	return store.Open(ctx, data.ID)
+}
+
+// seekableBinary is binary data that may be read in any order
+type seekableBinary interface {
+	io.ReadSeeker
+	io.ReaderAt
+	io.Closer
+}
+
+type bytesBinary struct{ *bytes.Reader }
+
+func (bytesBinary) Close() error { return nil }
+
+// openSeekable returns binary data for random access with its size. Stored
+// data is read in place when the store's reader allows it and read into
+// memory otherwise.
+func openSeekable(ctx context.Context, store binarydata.Store, data node.Binary) (seekableBinary, int64, error) {
+	if !data.Stored() {
+		return bytesBinary{bytes.NewReader(data.Data)}, int64(len(data.Data)), nil
+	}
+	r, err := openBinary(ctx, store, data)
+	if err != nil {
+		return nil, 0, err
+	}
+	if sr, ok := r.(seekableBinary); ok {
+		return sr, binarySize(data), nil
+	}
+	defer r.Close()
+	content, err := io.ReadAll(r)
+	if err != nil {
+		return nil, 0, err
+	}
+	return bytesBinary{bytes.NewReader(content)}, int64(len(content)), nil
+}
+
+// binarySize returns the size of binary data
+func binarySize(data node.Binary) int64 {
+	if data.Stored() {
+		return data.FileSize
+	}
+	return int64(len(data.Data))
+}
+
+// writeBinary creates binary data from what write writes. With a store the
+// data is streamed into it, and kept in the item otherwise.
+func writeBinary(ctx context.Context, store binarydata.Store, fileName, mimeType string, write func(io.Writer) error) (node.Binary, error) {
+	data := node.Binary{FileName: fileName, MimeType: mimeType}
+	if data.MimeType == "" {
+		data.MimeType = mimeTypeOf(fileName)
+	}
+
+	if store == nil {
+		var buf bytes.Buffer
+		if err := write(&buf); err != nil {
+			return node.Binary{}, err
+		}
+		data.Data, data.FileSize = buf.Bytes(), int64(buf.Len())
+		return data, nil
+	}
+
+	pr, pw := io.Pipe()
+	done := make(chan error, 1)
+	go func() {
+		err := write(pw)
+		pw.CloseWithError(err)
+		done <- err
+	}()
+	id, size, err := store.Put(ctx, pr)
+	// Unblock the writer should the store stop reading early
+	pr.CloseWithError(errors.New("binary data store stopped reading"))
+	writeErr := <-done
+	if err != nil {
+		return node.Binary{}, err
+	}
+	if writeErr != nil {
+		return node.Binary{}, writeErr
+	}
+	data.ID, data.FileSize = id, size
+	return data, nil
+}
+
+// mimeTypeOf returns the MIME type of a file name's extension
+func mimeTypeOf(fileName string) string {
+	if t := mime.TypeByExtension(path.Ext(fileName)); t != "" {
+		return t
+	}
+	return "application/octet-stream"
+}
@@ -0,0 +1,496 @@
+package transform
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/jaydeep/go-n8n/configs"
+	"github.com/jaydeep/go-n8n/internal/domain/node"
+	"github.com/jaydeep/go-n8n/pkg/binarydata"
+	"github.com/jaydeep/go-n8n/pkg/nodesdk"
+)
+
+// CompressionType is the node type of the compression node
+const CompressionType = "compression"
+
+// Operations of the compression node
+const (
+	OperationCompress   = "compress"
+	OperationDecompress = "decompress"
+)
+
+// Archive formats of the compression node
+const (
+	FormatZip   = "zip"
+	FormatGzip  = "gzip"
+	FormatTar   = "tar"
+	FormatTarGz = "tar_gz"
+)
+
+// Limits of what is extracted from an archive when none is configured
+const (
+	defaultMaxExpandedSize = 1 << 30
+	defaultMaxEntries      = 10000
+)
+
+// ErrArchiveTooLarge is returned for archives expanding past the configured
+// size or number of files
+var ErrArchiveTooLarge = errors.New("archive expands past the limit")
+
+var formatExtensions = map[string]string{
+	FormatZip:   ".zip",
+	FormatGzip:  ".gz",
+	FormatTar:   ".tar",
+	FormatTarGz: ".tar.gz",
+}
+
+// CompressionNode creates and extracts zip, gzip and tar archives of the
+// binary data of each item. Data kept in the binary data store is streamed
+// from and back into it, so archives are never held in memory as a whole.
+// Extraction stops once an archive expands past the configured limits.
+type CompressionNode struct {
+	nodesdk.BaseNode
+	limits configs.CompressionConfig
+}
+
+// NewCompressionNode creates a new compression node
+func NewCompressionNode(limits configs.CompressionConfig) node.NodeInterface {
+	if limits.MaxExpandedSize <= 0 {
+		limits.MaxExpandedSize = defaultMaxExpandedSize
+	}
+	if limits.MaxEntries <= 0 {
+		limits.MaxEntries = defaultMaxEntries
+	}
+	return &CompressionNode{
+		limits: limits,
+		BaseNode: nodesdk.BaseNode{
+			Type:        CompressionType,
+			Name:        "Compression",
+			Category:    node.CategoryTransform,
+			Version:     "1.0",
+			Description: "Compresses binary data into archives and extracts them",
+			Icon:        "fa:file-archive",
+		},
+	}
+}
+
+// Execute compresses or extracts the binary data of each item in turn
+func (n *CompressionNode) Execute(ctx context.Context, input *node.NodeInput) (*node.NodeOutput, error) {
+	params := nodesdk.Params(input.Parameters)
+	store := binarydata.FromContext(ctx)
+
+	return nodesdk.ProcessItems(ctx, input, func(ctx context.Context, item node.Item, _ int) (node.Item, error) {
+		if params.String("operation", OperationCompress) == OperationDecompress {
+			return decompress(ctx, params, item, store, n.limits)
+		}
+		return compress(ctx, params, item, store)
+	})
+}
+
+// compress replaces the binary properties to compress with an archive of
+// them. With gzip, which holds a single file, each property is compressed
+// in place instead.
+func compress(ctx context.Context, params nodesdk.Params, item node.Item, store binarydata.Store) (node.Item, error) {
+	names, err := binaryProperties(params, item)
+	if err != nil {
+		return node.Item{}, err
+	}
+	format := params.String("format", FormatZip)
+	editor := item.Edit()
+
+	if format == FormatGzip {
+		for _, name := range names {
+			data := item.Binary[name]
+			gz, err := writeBinary(ctx, store, data.FileName+".gz", "application/gzip", func(w io.Writer) error {
+				zw := gzip.NewWriter(w)
+				zw.Name = data.FileName
+				if err := copyBinary(ctx, store, data, zw); err != nil {
+					return err
+				}
+				return zw.Close()
+			})
+			if err != nil {
+				return node.Item{}, fmt.Errorf("compress %s: %w", name, err)
+			}
+			editor.SetBinary(name, gz)
+		}
+		return editor.Item(), nil
+	}
+
+	fileName := params.String("file_name", "")
+	if fileName == "" {
+		fileName = "archive" + formatExtensions[format]
+	}
+	archive, err := writeBinary(ctx, store, fileName, "", func(w io.Writer) error {
+		if format == FormatZip {
+			return writeZip(ctx, store, item, names, w)
+		}
+		return writeTar(ctx, store, item, names, w, format == FormatTarGz)
+	})
+	if err != nil {
+		return node.Item{}, fmt.Errorf("compress: %w", err)
+	}
+	return editor.DeleteBinary(names...).SetBinary(params.String("output_property", "data"), archive).Item(), nil
+}
+
+// binaryProperties returns the binary properties named by the
+// binary_properties parameter, or all of the item's in name order
+func binaryProperties(params nodesdk.Params, item node.Item) ([]string, error) {
+	var names []string
+	for _, name := range strings.Split(params.String("binary_properties", ""), ",") {
+		if name = strings.TrimSpace(name); name == "" {
+			continue
+		}
+		if _, ok := item.Binary[name]; !ok {
+			return nil, fmt.Errorf("item has no binary property %q", name)
+		}
+		names = append(names, name)
+	}
+	if len(names) > 0 {
+		return names, nil
+	}
+
+	for name := range item.Binary {
+		names = append(names, name)
+	}
+	if len(names) == 0 {
+		return nil, errors.New("item has no binary data")
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// entryName returns the archive entry name of a binary property
+func entryName(name string, data node.Binary) string {
+	if data.FileName != "" {
+		return data.FileName
+	}
+	return name
+}
+
+func writeZip(ctx context.Context, store binarydata.Store, item node.Item, names []string, w io.Writer) error {
+	zw := zip.NewWriter(w)
+	for _, name := range names {
+		data := item.Binary[name]
+		fw, err := zw.CreateHeader(&zip.FileHeader{Name: entryName(name, data), Method: zip.Deflate})
+		if err != nil {
+			return err
+		}
+		if err := copyBinary(ctx, store, data, fw); err != nil {
+			return err
+		}
+	}
+	return zw.Close()
+}
+
+func writeTar(ctx context.Context, store binarydata.Store, item node.Item, names []string, w io.Writer, gz bool) error {
+	var zw *gzip.Writer
+	if gz {
+		zw = gzip.NewWriter(w)
+		w = zw
+	}
+	tw := tar.NewWriter(w)
+	for _, name := range names {
+		data := item.Binary[name]
+		hdr := &tar.Header{Name: entryName(name, data), Mode: 0o644, Size: binarySize(data)}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		if err := copyBinary(ctx, store, data, tw); err != nil {
+			return err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	if zw != nil {
+		return zw.Close()
+	}
+	return nil
+}
+
+// copyBinary copies binary data to w
+func copyBinary(ctx context.Context, store binarydata.Store, data node.Binary, w io.Writer) error {
+	r, err := openBinary(ctx, store, data)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+	_, err = io.Copy(w, r)
+	return err
+}
+
+// decompress replaces the archive with the files it holds, kept in binary
+// properties named by the output prefix and their index. The format is
+// told by the data itself.
+func decompress(ctx context.Context, params nodesdk.Params, item node.Item, store binarydata.Store, limits configs.CompressionConfig) (node.Item, error) {
+	name := params.String("binary_property", "data")
+	data, ok := item.Binary[name]
+	if !ok {
+		return node.Item{}, fmt.Errorf("item has no binary property %q", name)
+	}
+
+	r, err := openBinary(ctx, store, data)
+	if err != nil {
+		return node.Item{}, err
+	}
+	defer r.Close()
+
+	var files []node.Binary
+	remaining := limits.MaxExpandedSize
+	add := func(fileName string, content io.Reader) error {
+		if len(files) >= limits.MaxEntries {
+			return fmt.Errorf("%w: more than %d files", ErrArchiveTooLarge, limits.MaxEntries)
+		}
+		file, err := writeBinary(ctx, store, fileName, "", func(w io.Writer) error {
+			n, err := io.Copy(w, io.LimitReader(content, remaining+1))
+			if remaining -= n; remaining < 0 {
+				return fmt.Errorf("%w: more than %d bytes", ErrArchiveTooLarge, limits.MaxExpandedSize)
+			}
+			return err
+		})
+		if err == nil {
+			files = append(files, file)
+		}
+		return err
+	}
+
+	br := bufio.NewReader(r)
+	switch {
+	case hasPrefix(br, zipMagic):
+		err = extractZip(r, br, data, limits.MaxExpandedSize, add)
+	case hasPrefix(br, gzipMagic):
+		err = extractGzip(br, data, add)
+	case isTar(br):
+		err = extractTar(br, add)
+	default:
+		err = errors.New("data is not a zip, gzip or tar archive")
+	}
+	if err != nil {
+		return node.Item{}, fmt.Errorf("decompress %s: %w", name, err)
+	}
+
+	editor := item.Edit().DeleteBinary(name)
+	prefix := params.String("output_prefix", "file_")
+	for i, file := range files {
+		editor.SetBinary(fmt.Sprintf("%s%d", prefix, i), file)
+	}
+	return editor.Item(), nil
+}
+
+var (
+	zipMagic  = []byte("PK\x03\x04")
+	gzipMagic = []byte{0x1f, 0x8b}
+)
+
+func hasPrefix(br *bufio.Reader, magic []byte) bool {
+	b, _ := br.Peek(len(magic))
+	return bytes.Equal(b, magic)
+}
+
+// isTar reports whether the data carries the ustar magic of tar headers
+func isTar(br *bufio.Reader) bool {
+	b, _ := br.Peek(262)
+	return len(b) == 262 && string(b[257:262]) == "ustar"
+}
+
+// extractZip extracts a zip archive. Zip archives are read from their end,
+// so stored data is read in place when the store's reader allows it and
+// read into memory otherwise, up to maxSize bytes.
+func extractZip(r io.Reader, br *bufio.Reader, data node.Binary, maxSize int64, add func(string, io.Reader) error) error {
+	ra, ok := r.(io.ReaderAt)
+	size := binarySize(data)
+	if !ok {
+		content, err := io.ReadAll(io.LimitReader(br, maxSize+1))
+		if err != nil {
+			return err
+		}
+		if int64(len(content)) > maxSize {
+			return fmt.Errorf("%w: more than %d bytes", ErrArchiveTooLarge, maxSize)
+		}
+		ra, size = bytes.NewReader(content), int64(len(content))
+	}
+
+	zr, err := zip.NewReader(ra, size)
+	if err != nil {
+		return err
+	}
+	for _, f := range zr.File {
+		if f.FileInfo().IsDir() {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return err
+		}
+		err = add(f.Name, rc)
+		rc.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// extractGzip extracts gzip data, which is either a compressed tar archive
+// or a single file
+func extractGzip(r io.Reader, data node.Binary, add func(string, io.Reader) error) error {
+	zr, err := gzip.NewReader(r)
+	if err != nil {
+		return err
+	}
+	defer zr.Close()
+
+	br := bufio.NewReader(zr)
+	if isTar(br) {
+		return extractTar(br, add)
+	}
+
+	fileName := zr.Name
+	if fileName == "" {
+		fileName = strings.TrimSuffix(path.Base(data.FileName), ".gz")
+	}
+	if fileName == "" || fileName == "." {
+		fileName = "file"
+	}
+	return add(fileName, br)
+}
+
+func extractTar(r io.Reader, add func(string, io.Reader) error) error {
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		if err := add(hdr.Name, tr); err != nil {
+			return err
+		}
+	}
+}
+
+// Validate validates the node parameters
+func (n *CompressionNode) Validate(parameters map[string]interface{}) error {
+	switch nodesdk.GetString(parameters, "operation", OperationCompress) {
+	case OperationCompress:
+		if _, ok := formatExtensions[nodesdk.GetString(parameters, "format", FormatZip)]; !ok {
+			return errors.New("format must be zip, gzip, tar or tar_gz")
+		}
+	case OperationDecompress:
+	default:
+		return errors.New("operation must be compress or decompress")
+	}
+	return nil
+}
+
+// GetSchema returns the node schema
+func (n *CompressionNode) GetSchema() *node.NodeSchema {
+	showCompress := &node.DisplayOptions{
+		Show: map[string][]interface{}{"operation": {OperationCompress}},
+	}
+	showDecompress := &node.DisplayOptions{
+		Show: map[string][]interface{}{"operation": {OperationDecompress}},
+	}
+
+	return &node.NodeSchema{
+		Type:        n.Type,
+		Name:        n.Name,
+		Group:       []string{"transform"},
+		Version:     1,
+		Description: n.Description,
+		Icon:        n.Icon,
+		Defaults:    node.NodeDefaults{Name: n.Name},
+		Inputs:      []node.IOSchema{{Type: "main"}},
+		Outputs:     []node.IOSchema{{Type: "main"}},
+		Properties: []node.PropertySchema{
+			{
+				Name:        "operation",
+				DisplayName: "Operation",
+				Type:        node.PropertyTypeOptions,
+				Default:     OperationCompress,
+				Options: []node.PropertyOption{
+					{Name: "Compress", Value: OperationCompress},
+					{Name: "Decompress", Value: OperationDecompress},
+				},
+			},
+			{
+				Name:        "format",
+				DisplayName: "Format",
+				Type:        node.PropertyTypeOptions,
+				Default:     FormatZip,
+				Options: []node.PropertyOption{
+					{Name: "Zip", Value: FormatZip},
+					{Name: "Gzip", Value: FormatGzip},
+					{Name: "Tar", Value: FormatTar},
+					{Name: "Tar (gzip)", Value: FormatTarGz},
+				},
+				DisplayOptions: showCompress,
+			},
+			{
+				Name:           "binary_properties",
+				DisplayName:    "Binary Properties",
+				Type:           node.PropertyTypeString,
+				Description:    "Comma-separated binary properties to compress; all of the item's when empty",
+				DisplayOptions: showCompress,
+			},
+			{
+				Name:           "file_name",
+				DisplayName:    "File Name",
+				Type:           node.PropertyTypeString,
+				Description:    "File name of the archive; gzip keeps the name of each file",
+				DisplayOptions: showCompress,
+			},
+			{
+				Name:           "output_property",
+				DisplayName:    "Output Property",
+				Type:           node.PropertyTypeString,
+				Default:        "data",
+				Description:    "Binary property the archive is kept in",
+				DisplayOptions: showCompress,
+			},
+			{
+				Name:           "binary_property",
+				DisplayName:    "Binary Property",
+				Type:           node.PropertyTypeString,
+				Default:        "data",
+				Description:    "Binary property holding the zip, gzip or tar archive",
+				DisplayOptions: showDecompress,
+			},
+			{
+				Name:           "output_prefix",
+				DisplayName:    "Output Prefix",
+				Type:           node.PropertyTypeString,
+				Default:        "file_",
+				Description:    "Prefix of the binary properties the extracted files are kept in, followed by their index",
+				DisplayOptions: showDecompress,
+			},
+		},
+	}
+}
+
+// GetDefaultParameters returns the default parameters
+func (n *CompressionNode) GetDefaultParameters() map[string]interface{} {
+	return map[string]interface{}{
+		"operation":       OperationCompress,
+		"format":          FormatZip,
+		"output_property": "data",
+		"binary_property": "data",
+		"output_prefix":   "file_",
+	}
+}
@@ -0,0 +1,97 @@
+package transform
+
+import (
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/jaydeep/go-n8n/configs"
+	"github.com/jaydeep/go-n8n/internal/domain/node"
+)
+
+// gzipBomb returns size zero bytes gzipped, about a thousandth of their size
+func gzipBomb(t *testing.T, size int64) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := io.CopyN(zw, zeros{}, size); err != nil {
+		t.Fatal(err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// zipOf returns a zip archive of files files of size zero bytes each,
+// compressed with method
+func zipOf(t *testing.T, method uint16, files int, size int64) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for i := 0; i < files; i++ {
+		fw, err := zw.CreateHeader(&zip.FileHeader{Name: fmt.Sprintf("file%d", i), Method: method})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := io.CopyN(fw, zeros{}, size); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+type zeros struct{}
+
+func (zeros) Read(p []byte) (int, error) {
+	clear(p)
+	return len(p), nil
+}
+
+func TestDecompressLimits(t *testing.T) {
+	limits := configs.CompressionConfig{MaxExpandedSize: 1 << 20, MaxEntries: 3}
+	tests := []struct {
+		name      string
+		archive   []byte
+		wantErr   error
+		wantFiles int
+	}{
+		{name: "gzip within limits", archive: gzipBomb(t, 1<<20), wantFiles: 1},
+		{name: "gzip bomb", archive: gzipBomb(t, 64<<20), wantErr: ErrArchiveTooLarge},
+		{name: "zip within limits", archive: zipOf(t, zip.Deflate, 3, 1<<18), wantFiles: 3},
+		{name: "zip bomb", archive: zipOf(t, zip.Deflate, 2, 32<<20), wantErr: ErrArchiveTooLarge},
+		{name: "zip files together past the limit", archive: zipOf(t, zip.Deflate, 3, 1<<19), wantErr: ErrArchiveTooLarge},
+		{name: "zip read into memory past the limit", archive: zipOf(t, zip.Store, 1, 2<<20), wantErr: ErrArchiveTooLarge},
+		{name: "zip of too many files", archive: zipOf(t, zip.Deflate, 4, 1), wantErr: ErrArchiveTooLarge},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := &node.NodeInput{
+				Data:       []node.Item{{Binary: map[string]node.Binary{"data": {FileName: "archive", Data: tt.archive}}}},
+				Parameters: map[string]interface{}{"operation": OperationDecompress},
+			}
+			out, err := NewCompressionNode(limits).Execute(context.Background(), input)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Execute() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+			if got := len(out.Data[0].Binary); got != tt.wantFiles {
+				t.Errorf("got %d files, want %d", got, tt.wantFiles)
+			}
+		})
+	}
+}
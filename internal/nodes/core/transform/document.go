@@ -0,0 +1,371 @@
+package transform
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"path"
+	"strings"
+
+	"github.com/jaydeep/go-n8n/configs"
+	"github.com/jaydeep/go-n8n/internal/domain/node"
+	"github.com/jaydeep/go-n8n/pkg/binarydata"
+	"github.com/jaydeep/go-n8n/pkg/nodesdk"
+	"github.com/ledongthuc/pdf"
+	"github.com/pdfcpu/pdfcpu/pkg/api"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+)
+
+// DocumentType is the node type of the document node
+const DocumentType = "document"
+
+// Operations of the document node
+const (
+	OperationExtractText = "extract_text"
+	OperationSplit       = "split"
+	OperationMerge       = "merge"
+	OperationHTMLToPDF   = "html_to_pdf"
+)
+
+func init() {
+	// pdfcpu would otherwise keep its configuration in the user's config
+	// directory, which workers need not have
+	api.DisableConfigDir()
+}
+
+// DocumentNode processes PDF documents: it extracts their text, splits
+// them into page ranges, merges them and renders HTML into them. PDFs are
+// read in place from the binary data store when its reader allows it;
+// rendering takes a Gotenberg service.
+type DocumentNode struct {
+	nodesdk.BaseNode
+	cfg configs.DocumentConfig
+}
+
+// NewDocumentNode creates a new document node
+func NewDocumentNode(cfg configs.DocumentConfig) node.NodeInterface {
+	return &DocumentNode{
+		BaseNode: nodesdk.BaseNode{
+			Type:        DocumentType,
+			Name:        "Document",
+			Category:    node.CategoryTransform,
+			Version:     "1.0",
+			Description: "Extracts text from PDFs, splits and merges them and renders HTML to PDF",
+			Icon:        "fa:file-pdf",
+		},
+		cfg: cfg,
+	}
+}
+
+// Execute runs the operation on each item in turn
+func (n *DocumentNode) Execute(ctx context.Context, input *node.NodeInput) (*node.NodeOutput, error) {
+	params := nodesdk.Params(input.Parameters)
+	store := binarydata.FromContext(ctx)
+
+	return nodesdk.ProcessItems(ctx, input, func(ctx context.Context, item node.Item, _ int) (node.Item, error) {
+		switch params.String("operation", OperationExtractText) {
+		case OperationSplit:
+			return splitPDF(ctx, params, item, store)
+		case OperationMerge:
+			return mergePDFs(ctx, params, item, store)
+		case OperationHTMLToPDF:
+			return n.renderHTML(ctx, params, item, store)
+		default:
+			return extractText(ctx, params, item, store)
+		}
+	})
+}
+
+// pdfProperty returns the binary property holding the PDF to read
+func pdfProperty(params nodesdk.Params, item node.Item) (string, node.Binary, error) {
+	name := params.String("binary_property", "data")
+	data, ok := item.Binary[name]
+	if !ok {
+		return "", node.Binary{}, fmt.Errorf("item has no binary property %q", name)
+	}
+	return name, data, nil
+}
+
+// extractText sets the text of the PDF on the item, as a whole and by page
+func extractText(ctx context.Context, params nodesdk.Params, item node.Item, store binarydata.Store) (node.Item, error) {
+	name, data, err := pdfProperty(params, item)
+	if err != nil {
+		return node.Item{}, err
+	}
+	r, size, err := openSeekable(ctx, store, data)
+	if err != nil {
+		return node.Item{}, err
+	}
+	defer r.Close()
+
+	pages, err := pageTexts(r, size)
+	if err != nil {
+		return node.Item{}, fmt.Errorf("extract text of %s: %w", name, err)
+	}
+	return item.Edit().Merge(map[string]interface{}{
+		"text":       strings.Join(pages, "\n"),
+		"pages":      pages,
+		"page_count": len(pages),
+	}).Item(), nil
+}
+
+// pageTexts returns the plain text of each page. The parser panics on some
+// malformed documents, which are reported as errors instead.
+func pageTexts(r io.ReaderAt, size int64) (pages []string, err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			pages, err = nil, fmt.Errorf("malformed PDF: %v", p)
+		}
+	}()
+
+	doc, err := pdf.NewReader(r, size)
+	if err != nil {
+		return nil, err
+	}
+	pages = make([]string, 0, doc.NumPage())
+	for i := 1; i <= doc.NumPage(); i++ {
+		page := doc.Page(i)
+		if page.V.IsNull() {
+			pages = append(pages, "")
+			continue
+		}
+		text, err := page.GetPlainText(nil)
+		if err != nil {
+			return nil, fmt.Errorf("page %d: %w", i, err)
+		}
+		pages = append(pages, text)
+	}
+	return pages, nil
+}
+
+// splitPDF replaces the PDF with documents of pages_per_file pages each,
+// kept in binary properties named by the output prefix and their index
+func splitPDF(ctx context.Context, params nodesdk.Params, item node.Item, store binarydata.Store) (node.Item, error) {
+	name, data, err := pdfProperty(params, item)
+	if err != nil {
+		return node.Item{}, err
+	}
+	r, _, err := openSeekable(ctx, store, data)
+	if err != nil {
+		return node.Item{}, err
+	}
+	defer r.Close()
+
+	spans, err := api.SplitRaw(r, params.Int("pages_per_file", 1), model.NewDefaultConfiguration())
+	if err != nil {
+		return node.Item{}, fmt.Errorf("split %s: %w", name, err)
+	}
+
+	fileName := path.Base(entryName(name, data))
+	base := strings.TrimSuffix(fileName, path.Ext(fileName))
+	editor := item.Edit().DeleteBinary(name)
+	prefix := params.String("output_prefix", "page_")
+	for i, span := range spans {
+		partName := fmt.Sprintf("%s_%d.pdf", base, span.From)
+		if span.Thru != span.From {
+			partName = fmt.Sprintf("%s_%d-%d.pdf", base, span.From, span.Thru)
+		}
+		part, err := writeBinary(ctx, store, partName, "application/pdf", func(w io.Writer) error {
+			_, err := io.Copy(w, span.Reader)
+			return err
+		})
+		if err != nil {
+			return node.Item{}, fmt.Errorf("split %s: %w", name, err)
+		}
+		editor.SetBinary(fmt.Sprintf("%s%d", prefix, i), part)
+	}
+	return editor.Item(), nil
+}
+
+// mergePDFs replaces the PDFs of the item with a single document holding
+// their pages in turn
+func mergePDFs(ctx context.Context, params nodesdk.Params, item node.Item, store binarydata.Store) (node.Item, error) {
+	names, err := binaryProperties(params, item)
+	if err != nil {
+		return node.Item{}, err
+	}
+
+	readers := make([]io.ReadSeeker, 0, len(names))
+	for _, name := range names {
+		r, _, err := openSeekable(ctx, store, item.Binary[name])
+		if err != nil {
+			return node.Item{}, err
+		}
+		defer r.Close()
+		readers = append(readers, r)
+	}
+
+	merged, err := writeBinary(ctx, store, params.String("file_name", "merged.pdf"), "application/pdf", func(w io.Writer) error {
+		return api.MergeRaw(readers, w, false, model.NewDefaultConfiguration())
+	})
+	if err != nil {
+		return node.Item{}, fmt.Errorf("merge: %w", err)
+	}
+	return item.Edit().DeleteBinary(names...).SetBinary(params.String("output_property", "data"), merged).Item(), nil
+}
+
+// renderHTML renders the html parameter to a PDF through the Gotenberg
+// service, streaming the document into the item
+func (n *DocumentNode) renderHTML(ctx context.Context, params nodesdk.Params, item node.Item, store binarydata.Store) (node.Item, error) {
+	if n.cfg.RendererURL == "" {
+		return node.Item{}, errors.New("HTML rendering is not configured; set node.document.renderer_url")
+	}
+
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	fw, err := mw.CreateFormFile("files", "index.html")
+	if err != nil {
+		return node.Item{}, err
+	}
+	if _, err := io.WriteString(fw, params.String("html", "")); err != nil {
+		return node.Item{}, err
+	}
+	if err := mw.Close(); err != nil {
+		return node.Item{}, err
+	}
+
+	url := strings.TrimSuffix(n.cfg.RendererURL, "/") + "/forms/chromium/convert/html"
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, &body)
+	if err != nil {
+		return node.Item{}, err
+	}
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+
+	resp, err := nodesdk.HTTPClientFrom(ctx).Do(ctx, "", req)
+	if err != nil {
+		return node.Item{}, fmt.Errorf("render HTML: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return node.Item{}, fmt.Errorf("render HTML: renderer returned %s: %s", resp.Status, bytes.TrimSpace(msg))
+	}
+
+	doc, err := writeBinary(ctx, store, params.String("file_name", "document.pdf"), "application/pdf", func(w io.Writer) error {
+		_, err := io.Copy(w, resp.Body)
+		return err
+	})
+	if err != nil {
+		return node.Item{}, fmt.Errorf("render HTML: %w", err)
+	}
+	return item.Edit().SetBinary(params.String("output_property", "data"), doc).Item(), nil
+}
+
+// Validate validates the node parameters
+func (n *DocumentNode) Validate(parameters map[string]interface{}) error {
+	params := nodesdk.Params(parameters)
+	switch params.String("operation", OperationExtractText) {
+	case OperationExtractText, OperationMerge:
+	case OperationSplit:
+		if params.Int("pages_per_file", 1) < 1 {
+			return errors.New("pages_per_file must be at least 1")
+		}
+	case OperationHTMLToPDF:
+		if params.String("html", "") == "" {
+			return errors.New("html is required")
+		}
+	default:
+		return errors.New("operation must be extract_text, split, merge or html_to_pdf")
+	}
+	return nil
+}
+
+// GetSchema returns the node schema
+func (n *DocumentNode) GetSchema() *node.NodeSchema {
+	show := func(operations ...interface{}) *node.DisplayOptions {
+		return &node.DisplayOptions{Show: map[string][]interface{}{"operation": operations}}
+	}
+
+	return &node.NodeSchema{
+		Type:        n.Type,
+		Name:        n.Name,
+		Group:       []string{"transform"},
+		Version:     1,
+		Description: n.Description,
+		Icon:        n.Icon,
+		Defaults:    node.NodeDefaults{Name: n.Name},
+		Inputs:      []node.IOSchema{{Type: "main"}},
+		Outputs:     []node.IOSchema{{Type: "main"}},
+		Properties: []node.PropertySchema{
+			{
+				Name:        "operation",
+				DisplayName: "Operation",
+				Type:        node.PropertyTypeOptions,
+				Default:     OperationExtractText,
+				Options: []node.PropertyOption{
+					{Name: "Extract Text", Value: OperationExtractText},
+					{Name: "Split Pages", Value: OperationSplit},
+					{Name: "Merge", Value: OperationMerge},
+					{Name: "HTML to PDF", Value: OperationHTMLToPDF},
+				},
+			},
+			{
+				Name:           "binary_property",
+				DisplayName:    "Binary Property",
+				Type:           node.PropertyTypeString,
+				Default:        "data",
+				Description:    "Binary property holding the PDF",
+				DisplayOptions: show(OperationExtractText, OperationSplit),
+			},
+			{
+				Name:           "pages_per_file",
+				DisplayName:    "Pages per File",
+				Type:           node.PropertyTypeNumber,
+				Default:        1,
+				DisplayOptions: show(OperationSplit),
+			},
+			{
+				Name:           "output_prefix",
+				DisplayName:    "Output Prefix",
+				Type:           node.PropertyTypeString,
+				Default:        "page_",
+				Description:    "Prefix of the binary properties the parts are kept in, followed by their index",
+				DisplayOptions: show(OperationSplit),
+			},
+			{
+				Name:           "binary_properties",
+				DisplayName:    "Binary Properties",
+				Type:           node.PropertyTypeString,
+				Description:    "Comma-separated binary properties holding the PDFs to merge, in order; all of the item's when empty",
+				DisplayOptions: show(OperationMerge),
+			},
+			{
+				Name:           "html",
+				DisplayName:    "HTML",
+				Type:           node.PropertyTypeString,
+				Description:    "HTML document to render",
+				DisplayOptions: show(OperationHTMLToPDF),
+			},
+			{
+				Name:           "file_name",
+				DisplayName:    "File Name",
+				Type:           node.PropertyTypeString,
+				Description:    "File name of the PDF created",
+				DisplayOptions: show(OperationMerge, OperationHTMLToPDF),
+			},
+			{
+				Name:           "output_property",
+				DisplayName:    "Output Property",
+				Type:           node.PropertyTypeString,
+				Default:        "data",
+				Description:    "Binary property the PDF created is kept in",
+				DisplayOptions: show(OperationMerge, OperationHTMLToPDF),
+			},
+		},
+	}
+}
+
+// GetDefaultParameters returns the default parameters
+func (n *DocumentNode) GetDefaultParameters() map[string]interface{} {
+	return map[string]interface{}{
+		"operation":       OperationExtractText,
+		"binary_property": "data",
+		"pages_per_file":  1,
+		"output_prefix":   "page_",
+		"output_property": "data",
+	}
+}
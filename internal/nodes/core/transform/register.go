@@ -0,0 +1,32 @@
+package transform
+
+import (
+	"embed"
+
+	"github.com/jaydeep/go-n8n/configs"
+	"github.com/jaydeep/go-n8n/internal/domain/node"
+	"github.com/jaydeep/go-n8n/pkg/i18n"
+	"github.com/jaydeep/go-n8n/pkg/nodesdk"
+)
+
+//go:embed icons locales
+var assets embed.FS
+
+// Register adds the transform nodes, their icons and translations
+func Register(r *node.NodeRegistry, bundle *i18n.Bundle, cfg configs.DocumentConfig, compression configs.CompressionConfig) error {
+	newCompression := func() node.NodeInterface { return NewCompressionNode(compression) }
+	if err := r.Register(CompressionType, node.CategoryTransform, newCompression); err != nil {
+		return err
+	}
+	if err := nodesdk.RegisterIcon(r, CompressionType, assets, "icons/compression.svg"); err != nil {
+		return err
+	}
+	newDocument := func() node.NodeInterface { return NewDocumentNode(cfg) }
+	if err := r.Register(DocumentType, node.CategoryTransform, newDocument); err != nil {
+		return err
+	}
+	if err := nodesdk.RegisterIcon(r, DocumentType, assets, "icons/document.svg"); err != nil {
+		return err
+	}
+	return nodesdk.RegisterTranslations(bundle, assets, "locales")
+}
@@ -0,0 +1,113 @@
+package trigger
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jaydeep/go-n8n/internal/domain/node"
+	"github.com/jaydeep/go-n8n/pkg/nodesdk"
+)
+
+// ChatTriggerType is the node type of the chat trigger
+const ChatTriggerType = "chat_trigger"
+
+// ChatTriggerNode starts a workflow for every message sent to the workflow's
+// chat endpoint. It emits the message together with the conversation
+// history of the session so an LLM node can answer in context.
+type ChatTriggerNode struct {
+	nodesdk.BaseNode
+}
+
+// ChatSettings holds the chat trigger parameters used by the chat endpoint
+type ChatSettings struct {
+	HistoryLength  int
+	ResponseField  string
+	InitialMessage string
+}
+
+// NewChatTriggerNode creates a new chat trigger node
+func NewChatTriggerNode() node.NodeInterface {
+	return &ChatTriggerNode{
+		BaseNode: nodesdk.BaseNode{
+			Type:        ChatTriggerType,
+			Name:        "Chat Trigger",
+			Category:    node.CategoryTrigger,
+			Version:     "1.0",
+			Description: "Starts the workflow when a chat message is received",
+			Icon:        "fa:comments",
+		},
+	}
+}
+
+// Execute passes the chat message item injected by the chat endpoint through
+func (n *ChatTriggerNode) Execute(ctx context.Context, input *node.NodeInput) (*node.NodeOutput, error) {
+	if len(input.Data) == 0 {
+		return nil, errors.New("chat trigger requires a message")
+	}
+
+	return &node.NodeOutput{
+		Data:     input.Data,
+		Metadata: make(map[string]interface{}),
+	}, nil
+}
+
+// Validate validates the node parameters
+func (n *ChatTriggerNode) Validate(parameters map[string]interface{}) error {
+	if nodesdk.GetInt(parameters, "history_length", 10) < 0 {
+		return errors.New("history_length must not be negative")
+	}
+	return nil
+}
+
+// GetSchema returns the node schema
+func (n *ChatTriggerNode) GetSchema() *node.NodeSchema {
+	return &node.NodeSchema{
+		Type:        n.Type,
+		Name:        n.Name,
+		Group:       []string{"trigger"},
+		Version:     1,
+		Description: n.Description,
+		Icon:        n.Icon,
+		Defaults:    node.NodeDefaults{Name: n.Name},
+		Outputs:     []node.IOSchema{{Type: "main"}},
+		Properties: []node.PropertySchema{
+			{
+				Name:        "history_length",
+				DisplayName: "History Length",
+				Type:        node.PropertyTypeNumber,
+				Default:     10,
+				Description: "Number of previous messages of the session passed to the workflow",
+			},
+			{
+				Name:        "response_field",
+				DisplayName: "Response Field",
+				Type:        node.PropertyTypeString,
+				Default:     "output",
+				Description: "Field of the last node's first item sent back as the reply",
+			},
+			{
+				Name:        "initial_message",
+				DisplayName: "Initial Message",
+				Type:        node.PropertyTypeString,
+				Description: "Greeting sent when a new session starts",
+			},
+		},
+	}
+}
+
+// GetDefaultParameters returns the default parameters
+func (n *ChatTriggerNode) GetDefaultParameters() map[string]interface{} {
+	return map[string]interface{}{
+		"history_length": 10,
+		"response_field": "output",
+	}
+}
+
+// ParseChatSettings reads the chat trigger parameters, applying defaults
+func ParseChatSettings(parameters map[string]interface{}) ChatSettings {
+	return ChatSettings{
+		HistoryLength:  nodesdk.GetInt(parameters, "history_length", 10),
+		ResponseField:  nodesdk.GetString(parameters, "response_field", "output"),
+		InitialMessage: nodesdk.GetString(parameters, "initial_message", ""),
+	}
+}
@@ -0,0 +1,29 @@
+package trigger
+
+import (
+	"embed"
+
+	"github.com/jaydeep/go-n8n/internal/domain/node"
+	"github.com/jaydeep/go-n8n/pkg/i18n"
+	"github.com/jaydeep/go-n8n/pkg/nodesdk"
+)
+
+//go:embed icons locales
+var assets embed.FS
+
+// Register adds the trigger nodes, their icons and translations
+func Register(r *node.NodeRegistry, bundle *i18n.Bundle) error {
+	if err := r.Register(ChatTriggerType, node.CategoryTrigger, NewChatTriggerNode); err != nil {
+		return err
+	}
+	if err := nodesdk.RegisterIcon(r, ChatTriggerType, assets, "icons/chat_trigger.svg"); err != nil {
+		return err
+	}
+	if err := r.Register(WebhookTriggerType, node.CategoryTrigger, NewWebhookTriggerNode); err != nil {
+		return err
+	}
+	if err := nodesdk.RegisterIcon(r, WebhookTriggerType, assets, "icons/webhook_trigger.svg"); err != nil {
+		return err
+	}
+	return nodesdk.RegisterTranslations(bundle, assets, "locales")
+}
@@ -0,0 +1,244 @@
+package trigger
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/jaydeep/go-n8n/internal/domain/node"
+	"github.com/jaydeep/go-n8n/pkg/netpolicy"
+	"github.com/jaydeep/go-n8n/pkg/nodesdk"
+)
+
+// WebhookTriggerType is the node type of the webhook trigger
+const WebhookTriggerType = "webhook_trigger"
+
+// webhookMethods are the HTTP methods a webhook trigger can listen on
+var webhookMethods = []string{"GET", "POST", "PUT", "PATCH", "DELETE"}
+
+// How the webhook trigger reads request bodies
+const (
+	// BodyJSON decodes the body as JSON
+	BodyJSON = "json"
+	// BodyForm parses URL-encoded and multipart forms; uploaded files
+	// become binary properties named by their form field
+	BodyForm = "form"
+	// BodyRaw keeps the body as the binary property data
+	BodyRaw = "raw"
+	// BodyNone ignores the body, for triggers reading the query only
+	BodyNone = "none"
+)
+
+// WebhookTriggerNode starts a workflow for every HTTP request received on
+// its webhook or on a workflow endpoint. It emits the request as an item.
+type WebhookTriggerNode struct {
+	nodesdk.BaseNode
+}
+
+// WebhookSettings holds the webhook trigger parameters used by the HTTP
+// endpoints
+type WebhookSettings struct {
+	Path   string
+	Method string
+	// RequestSchema is the JSON schema request bodies are validated against
+	RequestSchema map[string]interface{}
+	BodyFormat    string
+	// RawBody keeps the body received as the binary property raw_body as
+	// well, for signatures to be verified over the exact bytes
+	RawBody bool
+	// AllowedOrigins are the sites browsers may call the webhook from, or
+	// * for any; empty leaves it to the instance default
+	AllowedOrigins []string
+	// CORSMaxAge is how many seconds browsers may cache preflight
+	// responses; 0 leaves it to the instance default
+	CORSMaxAge int
+	// AllowedIPs restricts callers of the webhook to these IP addresses and
+	// CIDR ranges; empty allows every address
+	AllowedIPs []string
+}
+
+// NewWebhookTriggerNode creates a new webhook trigger node
+func NewWebhookTriggerNode() node.NodeInterface {
+	return &WebhookTriggerNode{
+		BaseNode: nodesdk.BaseNode{
+			Type:        WebhookTriggerType,
+			Name:        "Webhook Trigger",
+			Category:    node.CategoryTrigger,
+			Version:     "1.0",
+			Description: "Starts the workflow when an HTTP request is received",
+			Icon:        "fa:bolt",
+		},
+	}
+}
+
+// Execute passes the request item injected by the HTTP endpoint through
+func (n *WebhookTriggerNode) Execute(ctx context.Context, input *node.NodeInput) (*node.NodeOutput, error) {
+	if len(input.Data) == 0 {
+		return nil, errors.New("webhook trigger requires a request")
+	}
+
+	return &node.NodeOutput{
+		Data:     input.Data,
+		Metadata: make(map[string]interface{}),
+	}, nil
+}
+
+// Validate validates the node parameters
+func (n *WebhookTriggerNode) Validate(parameters map[string]interface{}) error {
+	switch nodesdk.GetString(parameters, "body_format", BodyJSON) {
+	case BodyJSON, BodyForm, BodyRaw, BodyNone:
+	default:
+		return errors.New("body_format must be json, form, raw or none")
+	}
+	if err := validateOrigins(nodesdk.GetString(parameters, "allowed_origins", "")); err != nil {
+		return err
+	}
+	if nodesdk.GetInt(parameters, "cors_max_age", 0) < 0 {
+		return errors.New("cors_max_age must not be negative")
+	}
+	if ips := nodesdk.GetString(parameters, "allowed_ips", ""); !strings.Contains(ips, "{{") {
+		if err := netpolicy.Validate(splitList(ips)); err != nil {
+			return fmt.Errorf("allowed_ips: %v", err)
+		}
+	}
+	method := strings.ToUpper(nodesdk.GetString(parameters, "http_method", "POST"))
+	for _, m := range webhookMethods {
+		if m == method {
+			return nil
+		}
+	}
+	return errors.New("http_method must be one of " + strings.Join(webhookMethods, ", "))
+}
+
+// GetSchema returns the node schema
+func (n *WebhookTriggerNode) GetSchema() *node.NodeSchema {
+	methods := make([]node.PropertyOption, 0, len(webhookMethods))
+	for _, m := range webhookMethods {
+		methods = append(methods, node.PropertyOption{Name: m, Value: m})
+	}
+
+	return &node.NodeSchema{
+		Type:        n.Type,
+		Name:        n.Name,
+		Group:       []string{"trigger"},
+		Version:     1,
+		Description: n.Description,
+		Icon:        n.Icon,
+		Defaults:    node.NodeDefaults{Name: n.Name},
+		Outputs:     []node.IOSchema{{Type: "main"}},
+		Properties: []node.PropertySchema{
+			{
+				Name:        "path",
+				DisplayName: "Path",
+				Type:        node.PropertyTypeString,
+				Description: "Path of the webhook URL",
+			},
+			{
+				Name:        "http_method",
+				DisplayName: "HTTP Method",
+				Type:        node.PropertyTypeOptions,
+				Default:     "POST",
+				Options:     methods,
+				Description: "HTTP method the webhook listens on",
+			},
+			{
+				Name:        "request_schema",
+				DisplayName: "Request Schema",
+				Type:        node.PropertyTypeJSON,
+				Description: "JSON schema request bodies must match",
+			},
+			{
+				Name:        "body_format",
+				DisplayName: "Body Format",
+				Type:        node.PropertyTypeOptions,
+				Default:     BodyJSON,
+				Options: []node.PropertyOption{
+					{Name: "JSON", Value: BodyJSON},
+					{Name: "Form", Value: BodyForm},
+					{Name: "Raw", Value: BodyRaw},
+					{Name: "None", Value: BodyNone},
+				},
+				Description: "How the request body is read: decoded as JSON, parsed as a URL-encoded or multipart form with files as binary data, kept as binary data or ignored",
+			},
+			{
+				Name:        "raw_body",
+				DisplayName: "Keep Raw Body",
+				Type:        node.PropertyTypeBoolean,
+				Default:     false,
+				Description: "Also keep the body received as the binary property raw_body, to verify signatures",
+			},
+			{
+				Name:        "allowed_origins",
+				DisplayName: "Allowed Origins",
+				Type:        node.PropertyTypeString,
+				Description: "Comma-separated origins browsers may call the webhook from, or * for any; empty uses the instance default",
+			},
+			{
+				Name:        "cors_max_age",
+				DisplayName: "Preflight Cache Seconds",
+				Type:        node.PropertyTypeNumber,
+				Default:     0,
+				Description: "How long browsers may cache the answer to preflight requests; 0 uses the instance default",
+			},
+			{
+				Name:        "allowed_ips",
+				DisplayName: "Allowed IPs",
+				Type:        node.PropertyTypeString,
+				Description: "Comma-separated IP addresses and CIDR ranges the webhook may be called from; empty allows every address",
+			},
+		},
+	}
+}
+
+// GetDefaultParameters returns the default parameters
+func (n *WebhookTriggerNode) GetDefaultParameters() map[string]interface{} {
+	return map[string]interface{}{
+		"http_method": "POST",
+		"body_format": BodyJSON,
+	}
+}
+
+// ParseWebhookSettings reads the webhook trigger parameters, applying defaults
+func ParseWebhookSettings(parameters map[string]interface{}) WebhookSettings {
+	return WebhookSettings{
+		Path:           nodesdk.GetString(parameters, "path", ""),
+		Method:         strings.ToUpper(nodesdk.GetString(parameters, "http_method", "POST")),
+		RequestSchema:  nodesdk.GetMap(parameters, "request_schema"),
+		BodyFormat:     nodesdk.GetString(parameters, "body_format", BodyJSON),
+		RawBody:        nodesdk.GetBool(parameters, "raw_body", false),
+		AllowedOrigins: splitList(nodesdk.GetString(parameters, "allowed_origins", "")),
+		CORSMaxAge:     nodesdk.GetInt(parameters, "cors_max_age", 0),
+		AllowedIPs:     splitList(nodesdk.GetString(parameters, "allowed_ips", "")),
+	}
+}
+
+// splitList returns the entries of a comma-separated list
+func splitList(list string) []string {
+	var entries []string
+	for _, entry := range strings.Split(list, ",") {
+		if entry = strings.TrimSpace(entry); entry != "" {
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
+
+// validateOrigins checks that every origin of a comma-separated list is *
+// or a scheme and host, such as https://example.com
+func validateOrigins(list string) error {
+	if strings.Contains(list, "{{") {
+		return nil
+	}
+	for _, origin := range splitList(list) {
+		if origin == "*" {
+			continue
+		}
+		u, err := url.Parse(origin)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" || u.Path != "" || u.RawQuery != "" {
+			return fmt.Errorf("allowed_origins: %q must be * or an origin such as https://example.com", origin)
+		}
+	}
+	return nil
+}
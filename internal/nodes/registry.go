@@ -0,0 +1,49 @@
+package nodes
+
+import (
+	"github.com/jaydeep/go-n8n/configs"
+	"github.com/jaydeep/go-n8n/internal/domain/node"
+	"github.com/jaydeep/go-n8n/internal/nodes/core/action"
+	"github.com/jaydeep/go-n8n/internal/nodes/core/flow"
+	"github.com/jaydeep/go-n8n/internal/nodes/core/transform"
+	"github.com/jaydeep/go-n8n/internal/nodes/core/trigger"
+	"github.com/jaydeep/go-n8n/pkg/i18n"
+	"github.com/jaydeep/go-n8n/pkg/logger"
+	"github.com/jaydeep/go-n8n/pkg/nodesdk/declarative"
+)
+
+// NewRegistry builds the node registry: the built-in nodes plus the
+// declarative nodes described in the custom nodes directory. Node
+// translations are added to bundle.
+func NewRegistry(cfg configs.NodeConfig, bundle *i18n.Bundle, log *logger.Logger) (*node.NodeRegistry, error) {
+	registry := node.NewNodeRegistry()
+
+	if err := trigger.Register(registry, bundle); err != nil {
+		return nil, err
+	}
+	if err := flow.Register(registry, bundle); err != nil {
+		return nil, err
+	}
+	if err := action.Register(registry, bundle); err != nil {
+		return nil, err
+	}
+	if err := transform.Register(registry, bundle, cfg.Document, cfg.Compression); err != nil {
+		return nil, err
+	}
+
+	if cfg.CustomDir != "" {
+		descriptors, err := declarative.LoadDir(cfg.CustomDir)
+		if err != nil {
+			return nil, err
+		}
+		if err := declarative.Register(registry, descriptors); err != nil {
+			return nil, err
+		}
+		for _, d := range descriptors {
+			declarative.RegisterTranslations(bundle, d)
+		}
+		log.Infow("Loaded declarative nodes", "dir", cfg.CustomDir, "count", len(descriptors))
+	}
+
+	return registry, nil
+}
@@ -0,0 +1,168 @@
+package nodes
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/jaydeep/go-n8n/internal/domain/node"
+	"github.com/jaydeep/go-n8n/pkg/i18n"
+	"github.com/jaydeep/go-n8n/pkg/logger"
+	"github.com/jaydeep/go-n8n/pkg/nodesdk/declarative"
+)
+
+// reloadDebounce groups the bursts of events editors produce on save
+const reloadDebounce = 300 * time.Millisecond
+
+// Reloader watches the custom nodes directory and re-registers changed
+// node descriptors without restarting the server. It is meant for
+// development; built-in node types are never replaced.
+type Reloader struct {
+	registry *node.NodeRegistry
+	bundle   *i18n.Bundle
+	dir      string
+	log      *logger.Logger
+	onChange func(changed, removed []string)
+
+	mu    sync.Mutex
+	files map[string]string // descriptor path -> node type
+}
+
+// NewReloader creates a reloader for the descriptors in dir, which are
+// expected to be registered already. onChange is called after each reload
+// with the node types that were (re)registered or removed.
+func NewReloader(registry *node.NodeRegistry, bundle *i18n.Bundle, dir string, log *logger.Logger, onChange func(changed, removed []string)) *Reloader {
+	r := &Reloader{
+		registry: registry,
+		bundle:   bundle,
+		dir:      dir,
+		log:      log,
+		onChange: onChange,
+		files:    make(map[string]string),
+	}
+
+	descriptors, err := declarative.LoadDir(dir)
+	if err != nil {
+		log.Warnw("Failed to scan custom nodes", "dir", dir, "error", err)
+	}
+	for _, d := range descriptors {
+		r.files[d.Source] = d.Type
+	}
+	return r
+}
+
+// Start watches the directory until ctx is cancelled
+func (r *Reloader) Start(ctx context.Context) {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		r.log.Errorw("Failed to start custom node watcher", "error", err)
+		return
+	}
+	defer watcher.Close()
+
+	if err := watcher.Add(r.dir); err != nil {
+		r.log.Warnw("Custom nodes directory not watched", "dir", r.dir, "error", err)
+		return
+	}
+	r.log.Infow("Watching custom nodes for changes", "dir", r.dir)
+
+	pending := make(map[string]struct{})
+	timer := time.NewTimer(reloadDebounce)
+	timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return
+			}
+			if !declarative.IsDescriptorFile(event.Name) || event.Op == fsnotify.Chmod {
+				continue
+			}
+			pending[filepath.Clean(event.Name)] = struct{}{}
+			timer.Reset(reloadDebounce)
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return
+			}
+			r.log.Warnw("Custom node watcher error", "error", err)
+		case <-timer.C:
+			paths := make([]string, 0, len(pending))
+			for path := range pending {
+				paths = append(paths, path)
+			}
+			pending = make(map[string]struct{})
+			r.Reload(paths...)
+		}
+	}
+}
+
+// Reload re-registers the descriptors at paths; missing files unregister
+// their node type
+func (r *Reloader) Reload(paths ...string) {
+	r.mu.Lock()
+	changed, removed := []string{}, []string{}
+	for _, path := range paths {
+		previous, known := r.files[path]
+
+		d, err := declarative.LoadFile(path)
+		if err != nil {
+			if known && errors.Is(err, os.ErrNotExist) {
+				r.registry.Unregister(previous)
+				delete(r.files, path)
+				removed = append(removed, previous)
+				r.log.Infow("Custom node removed", "type", previous, "file", path)
+				continue
+			}
+			// Keep serving the last good version while the file is being edited
+			r.log.Warnw("Invalid custom node descriptor", "file", path, "error", err)
+			continue
+		}
+
+		if !r.owns(d.Type, path) {
+			r.log.Warnw("Custom node type already registered", "type", d.Type, "file", path)
+			continue
+		}
+
+		if known && previous != d.Type {
+			removed = append(removed, previous)
+		}
+		if known {
+			// Also drops the previous icon
+			r.registry.Unregister(previous)
+		}
+		r.registry.Replace(d.Type, declarative.NewNode(d).GetCategory(), func() node.NodeInterface { return declarative.NewNode(d) })
+		if err := declarative.RegisterIcon(r.registry, d); err != nil {
+			r.log.Warnw("Invalid custom node icon", "type", d.Type, "file", path, "error", err)
+		}
+		declarative.RegisterTranslations(r.bundle, d)
+		r.files[path] = d.Type
+		changed = append(changed, d.Type)
+		r.log.Infow("Custom node reloaded", "type", d.Type, "file", path)
+	}
+	r.mu.Unlock()
+
+	if len(changed)+len(removed) == 0 || r.onChange == nil {
+		return
+	}
+	sort.Strings(changed)
+	sort.Strings(removed)
+	r.onChange(changed, removed)
+}
+
+// owns reports whether the file may register nodeType: either the type is
+// new or it was registered by this file
+func (r *Reloader) owns(nodeType, path string) bool {
+	if !r.registry.Has(nodeType) {
+		return true
+	}
+	return r.files[path] == nodeType
+}
@@ -0,0 +1,124 @@
+package binarydata
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+)
+
+// ErrUnknownRegion is returned for data of a region without a store of its
+// own, which is never kept in another store
+var ErrUnknownRegion = errors.New("no binary data storage for region")
+
+// RegionStore keeps the binary data of each data residency region in a
+// store of its own. The region of the context, set with WithRegion, selects
+// the store data is written to and read from; data without a region is kept
+// in the default store.
+type RegionStore struct {
+	def     Store
+	regions map[string]Store
+}
+
+// NewRegionStore creates a store keeping data without a region in def and
+// that of each region in its store
+func NewRegionStore(def Store, regions map[string]Store) *RegionStore {
+	return &RegionStore{def: def, regions: regions}
+}
+
+// Put streams r into the store of the region of ctx
+func (s *RegionStore) Put(ctx context.Context, r io.Reader) (string, int64, error) {
+	store, err := s.store(ctx)
+	if err != nil {
+		return "", 0, err
+	}
+	return store.Put(ctx, r)
+}
+
+// Open opens the data in the store of the region of ctx
+func (s *RegionStore) Open(ctx context.Context, id string) (io.ReadCloser, error) {
+	store, err := s.store(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return store.Open(ctx, id)
+}
+
+// Delete removes the data from the store of the region of ctx. Without a
+// region, as when cleaning up data listed across regions, it removes the
+// data from whichever store holds it.
+func (s *RegionStore) Delete(ctx context.Context, id string) error {
+	if RegionFromContext(ctx) != "" {
+		store, err := s.store(ctx)
+		if err != nil {
+			return err
+		}
+		return store.Delete(ctx, id)
+	}
+	for _, store := range s.stores() {
+		if err := store.Delete(ctx, id); !errors.Is(err, ErrNotFound) {
+			return err
+		}
+	}
+	return ErrNotFound
+}
+
+// List calls fn with the objects of every store that can list its data
+func (s *RegionStore) List(ctx context.Context, fn func(Object) error) error {
+	for _, store := range s.stores() {
+		if lister, ok := store.(Lister); ok {
+			if err := lister.List(ctx, fn); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// store returns the store of the region of ctx
+func (s *RegionStore) store(ctx context.Context) (Store, error) {
+	region := RegionFromContext(ctx)
+	if region == "" {
+		return s.def, nil
+	}
+	store, ok := s.regions[region]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownRegion, region)
+	}
+	return store, nil
+}
+
+// stores returns the default store followed by those of the regions, in
+// the order of their names
+func (s *RegionStore) stores() []Store {
+	names := make([]string, 0, len(s.regions))
+	for name := range s.regions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	stores := []Store{s.def}
+	for _, name := range names {
+		stores = append(stores, s.regions[name])
+	}
+	return stores
+}
+
+type regionKey struct{}
+
+// WithRegion returns a context whose binary data belongs to a data
+// residency region; an empty region leaves ctx as is
+func WithRegion(ctx context.Context, region string) context.Context {
+	if region == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, regionKey{}, region)
+}
+
+// RegionFromContext returns the data residency region of the binary data
+// of ctx, or "" when it has none
+func RegionFromContext(ctx context.Context) string {
+	region, _ := ctx.Value(regionKey{}).(string)
+	return region
+}
@@ -0,0 +1,178 @@
+// Package binarydata stores the binary data of items outside the items, so
+// files move between nodes as streams rather than as byte slices held in
+// memory. An item refers to stored data by the ID in its node.Binary.
+package binarydata
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrNotFound is returned when no data is stored under an ID
+var ErrNotFound = errors.New("binary data not found")
+
+// Store keeps binary data by ID
+type Store interface {
+	// Put streams r into the store and returns the ID and size of the
+	// stored data
+	Put(ctx context.Context, r io.Reader) (string, int64, error)
+
+	// Open returns a reader of the stored data, which the caller closes
+	Open(ctx context.Context, id string) (io.ReadCloser, error)
+
+	// Delete removes the stored data
+	Delete(ctx context.Context, id string) error
+}
+
+// Object is stored binary data as listed by a Lister
+type Object struct {
+	ID         string
+	Size       int64
+	ModifiedAt time.Time
+}
+
+// Lister is a Store that can list the data it holds
+type Lister interface {
+	Store
+
+	// List calls fn with every stored object, stopping at the first error
+	// fn returns
+	List(ctx context.Context, fn func(Object) error) error
+}
+
+// FileStore keeps binary data as files in a directory
+type FileStore struct {
+	dir string
+}
+
+// NewFileStore creates a store in dir, creating the directory if needed
+func NewFileStore(dir string) (*FileStore, error) {
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, fmt.Errorf("create binary data directory: %w", err)
+	}
+	return &FileStore{dir: dir}, nil
+}
+
+// Put copies r to a temporary file and moves it in place once complete, so
+// readers never see partial data
+func (s *FileStore) Put(ctx context.Context, r io.Reader) (string, int64, error) {
+	tmp, err := os.CreateTemp(s.dir, ".upload-*")
+	if err != nil {
+		return "", 0, err
+	}
+	defer os.Remove(tmp.Name())
+
+	size, err := io.Copy(tmp, contextReader{ctx: ctx, r: r})
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return "", 0, err
+	}
+
+	id := uuid.New().String()
+	if err := os.Rename(tmp.Name(), filepath.Join(s.dir, id)); err != nil {
+		return "", 0, err
+	}
+	return id, size, nil
+}
+
+// Open opens the file of the data
+func (s *FileStore) Open(ctx context.Context, id string) (io.ReadCloser, error) {
+	path, err := s.path(id)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	return f, err
+}
+
+// Delete removes the file of the data
+func (s *FileStore) Delete(ctx context.Context, id string) error {
+	path, err := s.path(id)
+	if err != nil {
+		return err
+	}
+	err = os.Remove(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return ErrNotFound
+	}
+	return err
+}
+
+// List calls fn with the files of the directory named by IDs, leaving out
+// uploads still in progress
+func (s *FileStore) List(ctx context.Context, fn func(Object) error) error {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			continue
+		}
+		if _, err := uuid.Parse(entry.Name()); err != nil {
+			continue
+		}
+		info, err := entry.Info()
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if err := fn(Object{ID: entry.Name(), Size: info.Size(), ModifiedAt: info.ModTime()}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// path returns the file of an ID. IDs are UUIDs, which keeps them from
+// naming files outside the directory.
+func (s *FileStore) path(id string) (string, error) {
+	if _, err := uuid.Parse(id); err != nil {
+		return "", ErrNotFound
+	}
+	return filepath.Join(s.dir, id), nil
+}
+
+// contextReader stops a copy once its context is done
+type contextReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+func (c contextReader) Read(p []byte) (int, error) {
+	if err := c.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return c.r.Read(p)
+}
+
+type storeKey struct{}
+
+// NewContext returns a context carrying the store for nodes
+func NewContext(ctx context.Context, s Store) context.Context {
+	return context.WithValue(ctx, storeKey{}, s)
+}
+
+// FromContext returns the store from ctx, or nil when binary data is kept
+// in the items
+func FromContext(ctx context.Context) Store {
+	s, _ := ctx.Value(storeKey{}).(Store)
+	return s
+}
@@ -0,0 +1,61 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/jaydeep/go-n8n/configs"
+	"github.com/redis/go-redis/v9"
+)
+
+// Client wraps the Redis client
+type Client struct {
+	*redis.Client
+}
+
+// Connect establishes a Redis connection
+func Connect(cfg configs.RedisConfig) (*Client, error) {
+	opts := &redis.Options{
+		Addr:            cfg.Addr,
+		Password:        cfg.Password,
+		DB:              cfg.DB,
+		MaxRetries:      cfg.MaxRetries,
+		PoolSize:        cfg.PoolSize,
+		MinIdleConns:    cfg.MinIdleConns,
+		ConnMaxLifetime: cfg.MaxConnAge,
+		ReadTimeout:     cfg.ReadTimeout,
+		WriteTimeout:    cfg.WriteTimeout,
+		PoolTimeout:     cfg.PoolTimeout,
+	}
+
+	// REDIS_URL overrides may use the redis:// URL form
+	if strings.HasPrefix(cfg.Addr, "redis://") || strings.HasPrefix(cfg.Addr, "rediss://") {
+		parsed, err := redis.ParseURL(cfg.Addr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid redis url: %w", err)
+		}
+		opts.Addr = parsed.Addr
+		opts.TLSConfig = parsed.TLSConfig
+		if parsed.Password != "" {
+			opts.Password = parsed.Password
+		}
+		if parsed.DB != 0 {
+			opts.DB = parsed.DB
+		}
+	}
+
+	client := redis.NewClient(opts)
+
+	// Test connection
+	if err := client.Ping(context.Background()).Err(); err != nil {
+		return nil, fmt.Errorf("failed to ping redis: %w", err)
+	}
+
+	return &Client{client}, nil
+}
+
+// Close closes the Redis connection
+func (c *Client) Close() error {
+	return c.Client.Close()
+}
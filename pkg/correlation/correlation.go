@@ -0,0 +1,53 @@
+// Package correlation carries the correlation ID that ties together the
+// work started by one trigger: the inbound request, the executions it
+// starts, their sub-workflows and error workflows, and their outbound
+// calls. It travels in contexts and is recorded on executions.
+package correlation
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// DefaultHeader is the HTTP header carrying the correlation ID
+const DefaultHeader = "X-Correlation-ID"
+
+// maxLength bounds IDs accepted from callers
+const maxLength = 128
+
+type contextKey struct{}
+
+// New generates a correlation ID
+func New() string {
+	return uuid.New().String()
+}
+
+// Valid returns whether id can be accepted from a caller: up to 128
+// letters, digits and the characters - _ . : /, so it is safe to repeat in
+// headers and logs
+func Valid(id string) bool {
+	if id == "" || len(id) > maxLength {
+		return false
+	}
+	for _, r := range id {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		case r == '-', r == '_', r == '.', r == ':', r == '/':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// WithID returns a context carrying the correlation ID
+func WithID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, contextKey{}, id)
+}
+
+// FromContext returns the correlation ID carried by ctx, or an empty string
+func FromContext(ctx context.Context) string {
+	id, _ := ctx.Value(contextKey{}).(string)
+	return id
+}
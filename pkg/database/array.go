@@ -0,0 +1,106 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"gorm.io/gorm/schema"
+)
+
+func init() {
+	schema.RegisterSerializer("array", ArraySerializer{})
+}
+
+// ArraySerializer stores string slices in the PostgreSQL array literal form,
+// {"a","b"}. PostgreSQL parses the literal into text[] columns and SQLite
+// keeps it as text, so entities tagged serializer:array work with both.
+type ArraySerializer struct{}
+
+// Scan parses an array literal into the field
+func (ArraySerializer) Scan(ctx context.Context, field *schema.Field, dst reflect.Value, dbValue interface{}) error {
+	var literal string
+	switch v := dbValue.(type) {
+	case nil:
+		return nil
+	case string:
+		literal = v
+	case []byte:
+		literal = string(v)
+	default:
+		return fmt.Errorf("array: unsupported value type %T", dbValue)
+	}
+
+	values, err := parseArray(literal)
+	if err != nil {
+		return err
+	}
+	field.ReflectValueOf(ctx, dst).Set(reflect.ValueOf(values))
+	return nil
+}
+
+// Value formats the field as an array literal
+func (ArraySerializer) Value(ctx context.Context, field *schema.Field, dst reflect.Value, fieldValue interface{}) (interface{}, error) {
+	values, ok := fieldValue.([]string)
+	if !ok {
+		return nil, fmt.Errorf("array: unsupported field type %T", fieldValue)
+	}
+
+	var b strings.Builder
+	b.WriteByte('{')
+	for i, v := range values {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteByte('"')
+		for _, r := range v {
+			if r == '"' || r == '\\' {
+				b.WriteByte('\\')
+			}
+			b.WriteRune(r)
+		}
+		b.WriteByte('"')
+	}
+	b.WriteByte('}')
+	return b.String(), nil
+}
+
+// parseArray parses a one-dimensional array literal
+func parseArray(literal string) ([]string, error) {
+	if len(literal) < 2 || literal[0] != '{' || literal[len(literal)-1] != '}' {
+		return nil, fmt.Errorf("array: malformed literal %q", literal)
+	}
+	body := literal[1 : len(literal)-1]
+	values := []string{}
+	if body == "" {
+		return values, nil
+	}
+
+	var (
+		current strings.Builder
+		quoted  bool
+		escaped bool
+	)
+	for i := 0; i < len(body); i++ {
+		c := body[i]
+		switch {
+		case escaped:
+			current.WriteByte(c)
+			escaped = false
+		case c == '\\':
+			escaped = true
+		case c == '"':
+			quoted = !quoted
+		case c == ',' && !quoted:
+			values = append(values, current.String())
+			current.Reset()
+		default:
+			current.WriteByte(c)
+		}
+	}
+	if quoted || escaped {
+		return nil, fmt.Errorf("array: malformed literal %q", literal)
+	}
+	return append(values, current.String()), nil
+}
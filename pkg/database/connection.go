@@ -1,6 +1,7 @@
 package database
 
 import (
+	"context"
 	"fmt"
 	"time"
 
@@ -29,12 +30,26 @@ type DB struct {
 	*gorm.DB
 }
 
-// Connect establishes a database connection
+// Connect establishes a database connection. The postgres driver connects
+// to a server; the sqlite driver opens the file named by Name.
 func Connect(cfg Config) (*DB, error) {
-	dsn := fmt.Sprintf(
-		"host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
-		cfg.Host, cfg.Port, cfg.User, cfg.Password, cfg.Name, cfg.SSLMode,
-	)
+	var dialector gorm.Dialector
+	switch cfg.Driver {
+	case "", DriverPostgres:
+		dsn := fmt.Sprintf(
+			"host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
+			cfg.Host, cfg.Port, cfg.User, cfg.Password, cfg.Name, cfg.SSLMode,
+		)
+		dialector = postgres.Open(dsn)
+	case DriverSQLite:
+		var err error
+		dialector, err = openSQLite(cfg)
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unsupported database driver %q", cfg.Driver)
+	}
 
 	// Set log level
 	logLevel := logger.Silent
@@ -48,7 +63,7 @@ func Connect(cfg Config) (*DB, error) {
 	}
 
 	// Open database connection
-	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{
+	db, err := gorm.Open(dialector, &gorm.Config{
 		Logger:                 logger.Default.LogMode(logLevel),
 		PrepareStmt:            true,
 		SkipDefaultTransaction: true,
@@ -82,6 +97,11 @@ func Connect(cfg Config) (*DB, error) {
 	return &DB{db}, nil
 }
 
+// Driver returns the name of the database driver in use
+func (db *DB) Driver() string {
+	return db.Dialector.Name()
+}
+
 // Close closes the database connection
 func (db *DB) Close() error {
 	sqlDB, err := db.DB.DB()
@@ -96,9 +116,10 @@ func (db *DB) Migrate(models ...interface{}) error {
 	return db.AutoMigrate(models...)
 }
 
-// Transaction executes a function within a transaction
-func (db *DB) Transaction(fn func(*gorm.DB) error) error {
-	return db.DB.Transaction(fn)
+// Transaction executes a function within a transaction. Cancelling ctx
+// aborts the statements in flight and rolls the transaction back.
+func (db *DB) Transaction(ctx context.Context, fn func(*gorm.DB) error) error {
+	return db.DB.WithContext(ctx).Transaction(fn)
 }
 
 // EnableUUID enables UUID extension in PostgreSQL
@@ -0,0 +1,115 @@
+package database
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	sqlitedriver "github.com/glebarez/go-sqlite"
+	"github.com/glebarez/sqlite"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	DriverPostgres = "postgres"
+	DriverSQLite   = "sqlite"
+)
+
+// sqliteTimeLayout is the layout times are stored in by the SQLite driver;
+// it sorts chronologically as long as all times share one UTC offset
+const sqliteTimeLayout = "2006-01-02 15:04:05.999999999-07:00"
+
+// sqliteTimeLayouts are the layouts parsed by the SQL functions below
+var sqliteTimeLayouts = []string{
+	sqliteTimeLayout,
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02T15:04:05.999999999-07:00",
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02",
+}
+
+func init() {
+	// The PostgreSQL functions the schema and repositories rely on
+	sqlitedriver.MustRegisterScalarFunction("uuid_generate_v4", 0, func(ctx *sqlitedriver.FunctionContext, args []driver.Value) (driver.Value, error) {
+		return uuid.NewString(), nil
+	})
+	sqlitedriver.MustRegisterDeterministicScalarFunction("date_trunc", 2, sqliteDateTrunc)
+}
+
+// openSQLite opens the database file named by cfg.Name, creating it and its
+// directory when missing. Write transactions take the lock up front so
+// concurrent writers wait for each other instead of failing.
+func openSQLite(cfg Config) (gorm.Dialector, error) {
+	path := cfg.Name
+	if path == "" {
+		return nil, fmt.Errorf("sqlite database file is required")
+	}
+	if dir := filepath.Dir(path); dir != "." {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return nil, fmt.Errorf("failed to create database directory: %w", err)
+		}
+	}
+
+	params := url.Values{}
+	params.Add("_pragma", "foreign_keys(1)")
+	params.Add("_pragma", "journal_mode(WAL)")
+	params.Add("_pragma", "busy_timeout(5000)")
+	params.Set("_time_format", "sqlite")
+	params.Set("_txlock", "immediate")
+	return sqlite.Open(path + "?" + params.Encode()), nil
+}
+
+// sqliteDateTrunc implements date_trunc for the units the repositories use
+func sqliteDateTrunc(ctx *sqlitedriver.FunctionContext, args []driver.Value) (driver.Value, error) {
+	unit, ok := args[0].(string)
+	if !ok {
+		return nil, fmt.Errorf("date_trunc: unit must be text")
+	}
+
+	var t time.Time
+	switch v := args[1].(type) {
+	case nil:
+		return nil, nil
+	case string:
+		parsed, err := parseSQLiteTime(v)
+		if err != nil {
+			return nil, err
+		}
+		t = parsed
+	case int64:
+		t = time.Unix(v, 0).UTC()
+	default:
+		return nil, fmt.Errorf("date_trunc: unsupported value type %T", v)
+	}
+
+	switch strings.ToLower(unit) {
+	case "minute":
+		t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), 0, 0, t.Location())
+	case "hour":
+		t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), 0, 0, 0, t.Location())
+	case "day":
+		t = time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	case "week":
+		offset := (int(t.Weekday()) + 6) % 7
+		t = time.Date(t.Year(), t.Month(), t.Day()-offset, 0, 0, 0, 0, t.Location())
+	case "month":
+		t = time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+	default:
+		return nil, fmt.Errorf("date_trunc: unsupported unit %q", unit)
+	}
+	return t.Format(sqliteTimeLayout), nil
+}
+
+func parseSQLiteTime(value string) (time.Time, error) {
+	for _, layout := range sqliteTimeLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("cannot parse time %q", value)
+}
@@ -0,0 +1,135 @@
+// Package encryption encrypts the secrets stored in the database with the
+// instance encryption key, and secrets leaving the instance with a
+// passphrase.
+package encryption
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+
+	"golang.org/x/crypto/argon2"
+)
+
+var (
+	// ErrKeyRequired is returned when no encryption key is configured
+	ErrKeyRequired = errors.New("encryption key is required")
+	// ErrDecrypt is returned for data that was altered or encrypted with
+	// another key
+	ErrDecrypt = errors.New("cannot decrypt data")
+	// ErrInvalidKDF is returned for key derivation parameters that are
+	// unsupported or too costly to evaluate
+	ErrInvalidKDF = errors.New("invalid key derivation parameters")
+)
+
+// KDFArgon2id is the key derivation function of passphrase ciphers
+const KDFArgon2id = "argon2id"
+
+// Bounds of the key derivation parameters accepted from outside, so a
+// crafted file cannot make the instance spend unbounded memory or time
+const (
+	maxKDFTime    = 10
+	maxKDFMemory  = 256 * 1024
+	maxKDFThreads = 16
+	minSaltSize   = 16
+)
+
+// KDFParams describe how a key was derived from a passphrase. They are
+// stored next to the data so it can be decrypted with the passphrase
+// alone.
+type KDFParams struct {
+	Algorithm string `json:"algorithm"`
+	Salt      []byte `json:"salt"`
+	Time      uint32 `json:"time"`
+	// Memory is in KiB
+	Memory  uint32 `json:"memory"`
+	Threads uint8  `json:"threads"`
+}
+
+// NewKDFParams returns the recommended Argon2id parameters with a random
+// salt
+func NewKDFParams() (KDFParams, error) {
+	salt := make([]byte, minSaltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return KDFParams{}, fmt.Errorf("encryption: %w", err)
+	}
+	return KDFParams{Algorithm: KDFArgon2id, Salt: salt, Time: 1, Memory: 64 * 1024, Threads: 4}, nil
+}
+
+// validate checks the parameters are supported and within bounds
+func (p KDFParams) validate() error {
+	if p.Algorithm != KDFArgon2id || len(p.Salt) < minSaltSize ||
+		p.Time < 1 || p.Time > maxKDFTime ||
+		p.Memory < 8*uint32(p.Threads) || p.Memory > maxKDFMemory ||
+		p.Threads < 1 || p.Threads > maxKDFThreads {
+		return ErrInvalidKDF
+	}
+	return nil
+}
+
+// Cipher encrypts data with AES-256-GCM. The key is derived from the
+// configured encryption key with SHA-256, so keys of any length can be
+// used; changing it makes the data encrypted before unreadable.
+type Cipher struct {
+	aead cipher.AEAD
+}
+
+// New creates a cipher from the encryption key
+func New(key string) (*Cipher, error) {
+	if key == "" {
+		return nil, ErrKeyRequired
+	}
+	sum := sha256.Sum256([]byte(key))
+	return newCipher(sum[:])
+}
+
+// newCipher creates an AES-256-GCM cipher from a 32 byte key
+func newCipher(key []byte) (*Cipher, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("encryption: %w", err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("encryption: %w", err)
+	}
+	return &Cipher{aead: aead}, nil
+}
+
+// FromPassphrase creates a cipher whose key is derived from a passphrase,
+// for data leaving the instance such as exports
+func FromPassphrase(passphrase string, params KDFParams) (*Cipher, error) {
+	if passphrase == "" {
+		return nil, ErrKeyRequired
+	}
+	if err := params.validate(); err != nil {
+		return nil, err
+	}
+	key := argon2.IDKey([]byte(passphrase), params.Salt, params.Time, params.Memory, params.Threads, 32)
+	return newCipher(key)
+}
+
+// Encrypt returns the sealed plaintext and the random nonce it was sealed
+// with, stored alongside as the IV
+func (c *Cipher) Encrypt(plaintext []byte) (data, iv []byte, err error) {
+	iv = make([]byte, c.aead.NonceSize())
+	if _, err := rand.Read(iv); err != nil {
+		return nil, nil, fmt.Errorf("encryption: %w", err)
+	}
+	return c.aead.Seal(nil, iv, plaintext, nil), iv, nil
+}
+
+// Decrypt opens data sealed by Encrypt
+func (c *Cipher) Decrypt(data, iv []byte) ([]byte, error) {
+	if len(iv) != c.aead.NonceSize() {
+		return nil, ErrDecrypt
+	}
+	plaintext, err := c.aead.Open(nil, iv, data, nil)
+	if err != nil {
+		return nil, ErrDecrypt
+	}
+	return plaintext, nil
+}
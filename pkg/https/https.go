@@ -0,0 +1,223 @@
+// Package https configures how the API server is served: over TLS without
+// a reverse proxy, with certificates loaded from files or issued
+// automatically over ACME, and over HTTP/2.
+package https
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/jaydeep/go-n8n/configs"
+	"golang.org/x/crypto/acme"
+	"golang.org/x/crypto/acme/autocert"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+)
+
+// Setup is the TLS configuration of the API server
+type Setup struct {
+	// TLSConfig is set on the HTTPS server
+	TLSConfig *tls.Config
+	// HTTPHandler serves the plain HTTP port: it answers ACME HTTP-01
+	// challenges and redirects everything else to HTTPS. It is nil when
+	// plain HTTP is not served.
+	HTTPHandler http.Handler
+	// HTTPAddr is the plain HTTP listen address
+	HTTPAddr string
+}
+
+// New prepares HTTPS serving on httpsPort from cfg, which must be enabled.
+// With ACME, certificates are issued for cfg.ACME.Domains and for the
+// hosts extraHosts allows, such as the custom domains of teams; extraHosts
+// may be nil.
+func New(cfg configs.TLSConfig, httpsPort int, extraHosts func(ctx context.Context, host string) error) (*Setup, error) {
+	hasFiles := cfg.CertFile != "" || cfg.KeyFile != ""
+	hasACME := len(cfg.ACME.Domains) > 0
+	switch {
+	case hasFiles && hasACME:
+		return nil, errors.New("server.tls: set either cert_file and key_file or acme.domains, not both")
+	case !hasFiles && !hasACME:
+		return nil, errors.New("server.tls: cert_file and key_file or acme.domains are required")
+	case hasFiles && (cfg.CertFile == "" || cfg.KeyFile == ""):
+		return nil, errors.New("server.tls: cert_file and key_file must be set together")
+	}
+
+	httpPort := cfg.HTTPPort
+	if httpPort <= 0 {
+		httpPort = 80
+	}
+	redirect := redirectHandler(httpsPort)
+	setup := &Setup{HTTPAddr: fmt.Sprintf(":%d", httpPort)}
+	if hasFiles {
+		cert, err := loadCertificate(cfg.CertFile, cfg.KeyFile)
+		if err != nil {
+			return nil, err
+		}
+		setup.TLSConfig = &tls.Config{
+			MinVersion:     tls.VersionTLS12,
+			GetCertificate: cert.get,
+		}
+		if cfg.RedirectHTTP {
+			setup.HTTPHandler = redirect
+		}
+		return setup, nil
+	}
+
+	cacheDir := cfg.ACME.CacheDir
+	if cacheDir == "" {
+		cacheDir = "./certs"
+	}
+	manager := &autocert.Manager{
+		Prompt:     autocert.AcceptTOS,
+		HostPolicy: hostPolicy(cfg.ACME.Domains, extraHosts),
+		Cache:      autocert.DirCache(cacheDir),
+		Email:      cfg.ACME.Email,
+	}
+	if cfg.ACME.DirectoryURL != "" {
+		manager.Client = &acme.Client{DirectoryURL: cfg.ACME.DirectoryURL}
+	}
+	// The manager's config also answers TLS-ALPN-01 challenges, so issuance
+	// works on port 443 alone when plain HTTP is not served
+	setup.TLSConfig = manager.TLSConfig()
+	setup.TLSConfig.MinVersion = tls.VersionTLS12
+	if cfg.RedirectHTTP {
+		setup.HTTPHandler = manager.HTTPHandler(redirect)
+	}
+	return setup, nil
+}
+
+// hostPolicy allows certificates for domains and for the hosts extra
+// allows
+func hostPolicy(domains []string, extra autocert.HostPolicy) autocert.HostPolicy {
+	whitelist := autocert.HostWhitelist(domains...)
+	if extra == nil {
+		return whitelist
+	}
+	return func(ctx context.Context, host string) error {
+		if err := whitelist(ctx, host); err == nil {
+			return nil
+		}
+		return extra(ctx, host)
+	}
+}
+
+// redirectHandler redirects requests to the same URL over HTTPS on port
+func redirectHandler(port int) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			http.Error(w, "use HTTPS", http.StatusBadRequest)
+			return
+		}
+		host := r.Host
+		if h, _, err := net.SplitHostPort(host); err == nil {
+			host = h
+		}
+		if port != 443 {
+			host = net.JoinHostPort(host, strconv.Itoa(port))
+		}
+		http.Redirect(w, r, "https://"+host+r.URL.RequestURI(), http.StatusMovedPermanently)
+	})
+}
+
+// certReloadInterval bounds how often the certificate files are checked
+// for changes
+const certReloadInterval = time.Minute
+
+// fileCertificate serves a certificate from files, reloading it once the
+// files change so renewed certificates are picked up without a restart
+type fileCertificate struct {
+	certFile, keyFile string
+
+	mu        sync.Mutex
+	cert      *tls.Certificate
+	modTime   time.Time
+	checkedAt time.Time
+}
+
+func loadCertificate(certFile, keyFile string) (*fileCertificate, error) {
+	f := &fileCertificate{certFile: certFile, keyFile: keyFile}
+	if err := f.reload(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func (f *fileCertificate) get(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if time.Since(f.checkedAt) > certReloadInterval {
+		// A failed reload keeps serving the current certificate, e.g. while
+		// a renewal has written the certificate but not yet the key
+		_ = f.reload()
+	}
+	return f.cert, nil
+}
+
+// reload loads the certificate if the files changed since the last load
+func (f *fileCertificate) reload() error {
+	f.checkedAt = time.Now()
+	modTime, err := latestModTime(f.certFile, f.keyFile)
+	if err != nil {
+		return err
+	}
+	if f.cert != nil && !modTime.After(f.modTime) {
+		return nil
+	}
+	cert, err := tls.LoadX509KeyPair(f.certFile, f.keyFile)
+	if err != nil {
+		return fmt.Errorf("failed to load TLS certificate: %w", err)
+	}
+	f.cert = &cert
+	f.modTime = modTime
+	return nil
+}
+
+func latestModTime(paths ...string) (time.Time, error) {
+	var latest time.Time
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("failed to read TLS certificate: %w", err)
+		}
+		if info.ModTime().After(latest) {
+			latest = info.ModTime()
+		}
+	}
+	return latest, nil
+}
+
+// ConfigureHTTP2 applies cfg to srv, whose Handler and TLSConfig must be
+// set. HTTP/2 is negotiated over TLS unless disabled; with cfg.H2C the
+// handler also accepts cleartext HTTP/2.
+func ConfigureHTTP2(srv *http.Server, cfg configs.HTTP2Config) error {
+	if cfg.Disabled {
+		if cfg.H2C {
+			return errors.New("server.http2: h2c requires HTTP/2")
+		}
+		// A non-nil empty map turns off the automatic HTTP/2 support
+		srv.TLSNextProto = map[string]func(*http.Server, *tls.Conn, http.Handler){}
+		return nil
+	}
+
+	h2s := &http2.Server{
+		MaxConcurrentStreams: cfg.MaxConcurrentStreams,
+		MaxReadFrameSize:     cfg.MaxReadFrameSize,
+		IdleTimeout:          cfg.IdleTimeout,
+	}
+	if err := http2.ConfigureServer(srv, h2s); err != nil {
+		return fmt.Errorf("failed to configure HTTP/2: %w", err)
+	}
+	if cfg.H2C {
+		srv.Handler = h2c.NewHandler(srv.Handler, h2s)
+	}
+	return nil
+}
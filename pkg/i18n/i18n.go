@@ -0,0 +1,189 @@
+// Package i18n translates node schemas and API messages.
+//
+// Messages are looked up by key in the bundle of the requested language,
+// then of its base language ("de" for "de-CH"), then of the default
+// language. API messages use their English text as key, so untranslated
+// messages are served as-is.
+package i18n
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// DefaultLanguage is the language messages are written in
+const DefaultLanguage = "en"
+
+// Bundle holds the translations of all languages
+type Bundle struct {
+	fallback string
+
+	mu       sync.RWMutex
+	messages map[string]map[string]string
+}
+
+// NewBundle creates an empty bundle falling back to the given language
+func NewBundle(fallback string) *Bundle {
+	if fallback == "" {
+		fallback = DefaultLanguage
+	}
+	return &Bundle{
+		fallback: Normalize(fallback),
+		messages: make(map[string]map[string]string),
+	}
+}
+
+// Add merges messages into a language; later additions win
+func (b *Bundle) Add(lang string, messages map[string]string) {
+	lang = Normalize(lang)
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	existing, ok := b.messages[lang]
+	if !ok {
+		existing = make(map[string]string, len(messages))
+		b.messages[lang] = existing
+	}
+	for k, v := range messages {
+		existing[k] = v
+	}
+}
+
+// LoadFS adds every <lang>.json file in dir of fsys; each file is a flat
+// object of key to message. Keys may be prefixed, e.g. with the node type
+// namespace of a node package.
+func (b *Bundle) LoadFS(fsys fs.FS, dir, prefix string) error {
+	files, err := fs.Glob(fsys, path.Join(dir, "*.json"))
+	if err != nil {
+		return err
+	}
+
+	for _, file := range files {
+		data, err := fs.ReadFile(fsys, file)
+		if err != nil {
+			return err
+		}
+
+		var messages map[string]string
+		if err := json.Unmarshal(data, &messages); err != nil {
+			return fmt.Errorf("%s: %w", file, err)
+		}
+
+		if prefix != "" {
+			prefixed := make(map[string]string, len(messages))
+			for k, v := range messages {
+				prefixed[prefix+k] = v
+			}
+			messages = prefixed
+		}
+		b.Add(strings.TrimSuffix(path.Base(file), ".json"), messages)
+	}
+	return nil
+}
+
+// Lookup returns the translation of key, if any
+func (b *Bundle) Lookup(lang, key string) (string, bool) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	for _, candidate := range b.candidates(lang) {
+		if msg, ok := b.messages[candidate][key]; ok {
+			return msg, true
+		}
+	}
+	return "", false
+}
+
+// T returns the translation of key, or def when there is none
+func (b *Bundle) T(lang, key, def string) string {
+	if b == nil {
+		return def
+	}
+	if msg, ok := b.Lookup(lang, key); ok {
+		return msg
+	}
+	return def
+}
+
+// Message translates an API message keyed by its English text
+func (b *Bundle) Message(lang, msg string) string {
+	return b.T(lang, msg, msg)
+}
+
+// Languages returns the languages with translations
+func (b *Bundle) Languages() []string {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	langs := make([]string, 0, len(b.messages))
+	for lang := range b.messages {
+		langs = append(langs, lang)
+	}
+	sort.Strings(langs)
+	return langs
+}
+
+// Match picks the best supported language from an Accept-Language header
+func (b *Bundle) Match(acceptLanguage string) string {
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		tag := Normalize(strings.SplitN(strings.TrimSpace(part), ";", 2)[0])
+		if tag == "" || tag == "*" {
+			continue
+		}
+
+		b.mu.RLock()
+		_, exact := b.messages[tag]
+		_, base := b.messages[baseLanguage(tag)]
+		b.mu.RUnlock()
+		if exact || base || tag == b.fallback {
+			return tag
+		}
+	}
+	return b.fallback
+}
+
+func (b *Bundle) candidates(lang string) []string {
+	lang = Normalize(lang)
+	candidates := make([]string, 0, 3)
+	if lang != "" {
+		candidates = append(candidates, lang)
+		if base := baseLanguage(lang); base != lang {
+			candidates = append(candidates, base)
+		}
+	}
+	return append(candidates, b.fallback)
+}
+
+// Normalize lower-cases a language tag and uses "-" as separator
+func Normalize(lang string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(lang), "_", "-"))
+}
+
+func baseLanguage(lang string) string {
+	if i := strings.IndexByte(lang, '-'); i > 0 {
+		return lang[:i]
+	}
+	return lang
+}
+
+type languageKey struct{}
+
+// WithLanguage returns a context carrying the request language
+func WithLanguage(ctx context.Context, lang string) context.Context {
+	return context.WithValue(ctx, languageKey{}, lang)
+}
+
+// LanguageFrom returns the request language, or DefaultLanguage
+func LanguageFrom(ctx context.Context) string {
+	if lang, ok := ctx.Value(languageKey{}).(string); ok && lang != "" {
+		return lang
+	}
+	return DefaultLanguage
+}
@@ -0,0 +1,241 @@
+// Package jwks fetches and caches the public keys an identity provider
+// publishes as a JSON Web Key Set.
+package jwks
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultRefreshInterval is how long fetched keys are used before the
+	// set is fetched again
+	DefaultRefreshInterval = time.Hour
+	// minRefetchInterval limits how often an unknown key ID triggers a
+	// fetch, so tokens with made up key IDs cannot flood the provider
+	minRefetchInterval = time.Minute
+	// maxSetSize bounds the size of a key set document
+	maxSetSize = 1 << 20
+)
+
+// ErrKeyNotFound is returned when the set has no usable key with the ID
+var ErrKeyNotFound = errors.New("jwks: key not found")
+
+// KeySet is the key set published at a URL. Keys are fetched on first use
+// and again after the refresh interval or when a token names an unknown
+// key, as providers add keys before signing with them.
+type KeySet struct {
+	url     string
+	refresh time.Duration
+	client  *http.Client
+
+	mu        sync.Mutex
+	keys      map[string]interface{}
+	err       error
+	fetchedAt time.Time
+	triedAt   time.Time
+	// fetching is closed once the fetch in flight, if any, completes
+	fetching chan struct{}
+}
+
+// New creates a key set fetched from url. A zero refresh uses
+// DefaultRefreshInterval.
+func New(url string, refresh time.Duration, client *http.Client) *KeySet {
+	if refresh <= 0 {
+		refresh = DefaultRefreshInterval
+	}
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	return &KeySet{url: url, refresh: refresh, client: client}
+}
+
+// Key returns the public key with the key ID kid. An empty kid matches the
+// only key of a set holding a single one. A single fetch runs at a time;
+// callers whose key may be in the set being fetched wait for it, the others
+// are served the keys fetched before.
+func (s *KeySet) Key(ctx context.Context, kid string) (interface{}, error) {
+	s.mu.Lock()
+	key := s.lookup(kid)
+	now := time.Now()
+	fetch := s.fetching == nil &&
+		(now.Sub(s.fetchedAt) >= s.refresh || (key == nil && now.Sub(s.triedAt) >= minRefetchInterval))
+	if fetch {
+		s.triedAt = now
+		s.fetching = make(chan struct{})
+	}
+	fetching := s.fetching
+	s.mu.Unlock()
+
+	switch {
+	case fetch:
+		s.update(ctx, now)
+	case key != nil:
+		return key, nil
+	case fetching != nil:
+		select {
+		case <-fetching:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if key := s.lookup(kid); key != nil {
+		return key, nil
+	}
+	if s.keys == nil && s.err != nil {
+		return nil, s.err
+	}
+	return nil, ErrKeyNotFound
+}
+
+// update fetches the set without holding the lock and swaps it in
+func (s *KeySet) update(ctx context.Context, now time.Time) {
+	keys, err := s.fetch(ctx)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	// On failure the keys fetched before are kept, so an unreachable
+	// provider does not lock everyone out until they expire
+	if err == nil {
+		s.keys = keys
+		s.fetchedAt = now
+	}
+	s.err = err
+	close(s.fetching)
+	s.fetching = nil
+}
+
+func (s *KeySet) lookup(kid string) interface{} {
+	if kid == "" && len(s.keys) == 1 {
+		for _, key := range s.keys {
+			return key
+		}
+	}
+	return s.keys[kid]
+}
+
+func (s *KeySet) fetch(ctx context.Context) (map[string]interface{}, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("jwks: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("jwks: fetch key set: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("jwks: fetch key set: status %d", resp.StatusCode)
+	}
+
+	var set struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxSetSize)).Decode(&set); err != nil {
+		return nil, fmt.Errorf("jwks: decode key set: %w", err)
+	}
+	keys := make(map[string]interface{}, len(set.Keys))
+	for _, k := range set.Keys {
+		// Encryption keys and key types not used to sign tokens are skipped
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		key, err := k.publicKey()
+		if err != nil {
+			continue
+		}
+		keys[k.Kid] = key
+	}
+	return keys, nil
+}
+
+// jsonWebKey is a public key of a key set, as defined by RFC 7517
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	// RSA
+	N string `json:"n"`
+	E string `json:"e"`
+	// EC and OKP
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+func (k jsonWebKey) publicKey() (interface{}, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeInt(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeInt(k.E)
+		if err != nil {
+			return nil, err
+		}
+		if !e.IsInt64() || e.Int64() < 3 || e.Int64() > 1<<31-1 {
+			return nil, fmt.Errorf("jwks: invalid RSA exponent")
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("jwks: unsupported curve %q", k.Crv)
+		}
+		x, err := decodeInt(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeInt(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		if !curve.IsOnCurve(x, y) {
+			return nil, fmt.Errorf("jwks: point not on curve %s", k.Crv)
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	case "OKP":
+		if k.Crv != "Ed25519" {
+			return nil, fmt.Errorf("jwks: unsupported curve %q", k.Crv)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil || len(x) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("jwks: invalid Ed25519 key")
+		}
+		return ed25519.PublicKey(x), nil
+	default:
+		return nil, fmt.Errorf("jwks: unsupported key type %q", k.Kty)
+	}
+}
+
+func decodeInt(s string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil || len(b) == 0 {
+		return nil, fmt.Errorf("jwks: invalid key parameter")
+	}
+	return new(big.Int).SetBytes(b), nil
+}
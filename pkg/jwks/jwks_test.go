@@ -0,0 +1,71 @@
+package jwks
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestKeyServedDuringFetch(t *testing.T) {
+	pub, _, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	set := fmt.Sprintf(`{"keys":[{"kty":"OKP","crv":"Ed25519","kid":"k1","x":%q}]}`, base64.RawURLEncoding.EncodeToString(pub))
+
+	var fetches atomic.Int32
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Every fetch after the first hangs until released
+		if fetches.Add(1) > 1 {
+			<-release
+		}
+		w.Write([]byte(set))
+	}))
+	defer server.Close()
+	defer close(release)
+
+	s := New(server.URL, time.Millisecond, nil)
+	ctx := context.Background()
+	if _, err := s.Key(ctx, "k1"); err != nil {
+		t.Fatalf("Key() error = %v", err)
+	}
+
+	// Once the set is stale the next caller fetches it again, which hangs
+	time.Sleep(2 * time.Millisecond)
+	go s.Key(ctx, "k1")
+	for fetches.Load() < 2 {
+		time.Sleep(time.Millisecond)
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := s.Key(ctx, "k1")
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("Key() error = %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Key() of a known key waited for the fetch in flight")
+	}
+
+	// Callers of unknown keys wait for the fetch in flight instead of
+	// fetching again
+	waitCtx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancel()
+	if _, err := s.Key(waitCtx, "k3"); err != context.DeadlineExceeded {
+		t.Errorf("Key() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+	if got := fetches.Load(); got != 2 {
+		t.Errorf("key set fetched %d times, want 2", got)
+	}
+}
@@ -0,0 +1,202 @@
+// Package leader runs singleton services on exactly one of several
+// instances. Each service campaigns for a named lock; the instance holding
+// it runs the service and renews the lock, and when it stops renewing,
+// another instance takes over once the lock is released or expires.
+package leader
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jaydeep/go-n8n/configs"
+	"github.com/jaydeep/go-n8n/pkg/database"
+	"github.com/jaydeep/go-n8n/pkg/logger"
+	"github.com/redis/go-redis/v9"
+)
+
+// Lock backends
+const (
+	BackendRedis    = "redis"
+	BackendPostgres = "postgres"
+	BackendLocal    = "local"
+)
+
+// ErrLockLost is returned by Renew once another instance may hold the lock
+var ErrLockLost = errors.New("leader lock lost")
+
+// Lock is a named lock held by at most one instance
+type Lock interface {
+	// Acquire takes the lock if it is free, without waiting
+	Acquire(ctx context.Context) (bool, error)
+	// Renew extends the lock. It returns ErrLockLost once the lock is no
+	// longer held; other errors are transient.
+	Renew(ctx context.Context) error
+	// Release gives the lock up
+	Release(ctx context.Context) error
+}
+
+// NewLock creates the lock named name on the configured backend. An empty
+// backend picks Redis when a client is given, then PostgreSQL, and a local
+// lock on SQLite, which serves a single instance only.
+func NewLock(cfg configs.LeaderConfig, name string, client *redis.Client, db *database.DB) (Lock, error) {
+	backend := cfg.Backend
+	if backend == "" {
+		switch {
+		case client != nil:
+			backend = BackendRedis
+		case db.Driver() == database.DriverPostgres:
+			backend = BackendPostgres
+		default:
+			backend = BackendLocal
+		}
+	}
+
+	switch backend {
+	case BackendRedis:
+		if client == nil {
+			return nil, errors.New("the redis leader backend requires redis.addr")
+		}
+		return NewRedisLock(client, name, cfg.LeaseTTL), nil
+	case BackendPostgres:
+		if db.Driver() != database.DriverPostgres {
+			return nil, errors.New("the postgres leader backend requires the postgres database driver")
+		}
+		return NewPostgresLock(db, name), nil
+	case BackendLocal:
+		return NewLocalLock(), nil
+	default:
+		return nil, fmt.Errorf("unsupported leader backend %q", backend)
+	}
+}
+
+// instanceID identifies this process as the holder of leases
+var instanceID = func() string {
+	host, err := os.Hostname()
+	if err != nil {
+		host = "unknown"
+	}
+	return fmt.Sprintf("%s-%s", host, uuid.NewString())
+}()
+
+// Elector runs a service while this instance holds its lock
+type Elector struct {
+	lock Lock
+	name string
+	run  func(ctx context.Context)
+	cfg  configs.LeaderConfig
+	log  *logger.Logger
+
+	leading atomic.Bool
+}
+
+// NewElector creates an elector running run while lock is held
+func NewElector(lock Lock, name string, run func(ctx context.Context), cfg configs.LeaderConfig, log *logger.Logger) *Elector {
+	if cfg.LeaseTTL <= 0 {
+		cfg.LeaseTTL = 15 * time.Second
+	}
+	if cfg.RenewInterval <= 0 || cfg.RenewInterval >= cfg.LeaseTTL {
+		cfg.RenewInterval = cfg.LeaseTTL / 3
+	}
+	if cfg.RetryInterval <= 0 {
+		cfg.RetryInterval = cfg.RenewInterval
+	}
+	return &Elector{
+		lock: lock,
+		name: name,
+		run:  run,
+		cfg:  cfg,
+		log:  log,
+	}
+}
+
+// Start campaigns for the lock until the context is cancelled, running the
+// service whenever it is won. On return the service has stopped and the
+// lock is released.
+func (e *Elector) Start(ctx context.Context) {
+	ticker := time.NewTicker(e.cfg.RetryInterval)
+	defer ticker.Stop()
+
+	for ctx.Err() == nil {
+		acquired, err := e.lock.Acquire(ctx)
+		if err != nil && ctx.Err() == nil {
+			e.log.Warnw("Failed to acquire leader lock", "service", e.name, "error", err)
+		}
+		if acquired {
+			e.lead(ctx)
+		}
+
+		select {
+		case <-ctx.Done():
+		case <-ticker.C:
+		}
+	}
+}
+
+// IsLeader reports whether this instance runs the service
+func (e *Elector) IsLeader() bool {
+	return e.leading.Load()
+}
+
+// lead runs the service until the lock is lost or ctx is cancelled
+func (e *Elector) lead(ctx context.Context) {
+	e.leading.Store(true)
+	defer e.leading.Store(false)
+	e.log.Infow("Acquired leadership", "service", e.name, "instance", instanceID)
+
+	runCtx, stop := context.WithCancel(ctx)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		e.run(runCtx)
+	}()
+
+	e.renew(ctx, done)
+	stop()
+	<-done
+
+	// Release with a fresh context: ctx is usually cancelled by now
+	releaseCtx, cancel := context.WithTimeout(context.Background(), e.cfg.RenewInterval)
+	defer cancel()
+	if err := e.lock.Release(releaseCtx); err != nil {
+		e.log.Warnw("Failed to release leader lock", "service", e.name, "error", err)
+	}
+	e.log.Infow("Released leadership", "service", e.name, "instance", instanceID)
+}
+
+// renew keeps the lock until it is lost, ctx is cancelled or the service
+// returns. Transient renewal errors are tolerated while the lease outlives
+// the next renewal; past that another instance may take over.
+func (e *Elector) renew(ctx context.Context, done <-chan struct{}) {
+	ticker := time.NewTicker(e.cfg.RenewInterval)
+	defer ticker.Stop()
+
+	renewed := time.Now()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-done:
+			return
+		case <-ticker.C:
+		}
+
+		err := e.lock.Renew(ctx)
+		switch {
+		case err == nil:
+			renewed = time.Now()
+		case errors.Is(err, ErrLockLost):
+			e.log.Warnw("Lost leadership", "service", e.name)
+			return
+		case time.Since(renewed)+e.cfg.RenewInterval >= e.cfg.LeaseTTL:
+			e.log.Warnw("Stepping down after failed lease renewals", "service", e.name, "error", err)
+			return
+		default:
+			e.log.Warnw("Failed to renew leader lock", "service", e.name, "error", err)
+		}
+	}
+}
@@ -0,0 +1,21 @@
+package leader
+
+import "context"
+
+// LocalLock is always held, for single instance deployments such as lite
+// mode
+type LocalLock struct{}
+
+// NewLocalLock creates a lock that is always acquired
+func NewLocalLock() LocalLock {
+	return LocalLock{}
+}
+
+// Acquire always succeeds
+func (LocalLock) Acquire(context.Context) (bool, error) { return true, nil }
+
+// Renew always succeeds
+func (LocalLock) Renew(context.Context) error { return nil }
+
+// Release does nothing
+func (LocalLock) Release(context.Context) error { return nil }
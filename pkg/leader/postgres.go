@@ -0,0 +1,89 @@
+package leader
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"hash/fnv"
+	"sync"
+
+	"github.com/jaydeep/go-n8n/pkg/database"
+)
+
+// PostgresLock is a session-level advisory lock. It is held on a dedicated
+// connection, so it is released as soon as the leader's session ends.
+type PostgresLock struct {
+	db  *database.DB
+	key int64
+
+	mu   sync.Mutex
+	conn *sql.Conn
+}
+
+// NewPostgresLock creates an advisory lock named name
+func NewPostgresLock(db *database.DB, name string) *PostgresLock {
+	h := fnv.New64a()
+	h.Write([]byte("n8n:leader:" + name))
+	return &PostgresLock{db: db, key: int64(h.Sum64())}
+}
+
+// Acquire takes the advisory lock if no session holds it
+func (l *PostgresLock) Acquire(ctx context.Context) (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.conn != nil {
+		return true, nil
+	}
+
+	sqlDB, err := l.db.DB.DB()
+	if err != nil {
+		return false, err
+	}
+	conn, err := sqlDB.Conn(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	var acquired bool
+	if err := conn.QueryRowContext(ctx, "SELECT pg_try_advisory_lock($1)", l.key).Scan(&acquired); err != nil {
+		conn.Close()
+		return false, err
+	}
+	if !acquired {
+		conn.Close()
+		return false, nil
+	}
+	l.conn = conn
+	return true, nil
+}
+
+// Renew checks that the session holding the lock is still alive
+func (l *PostgresLock) Renew(ctx context.Context) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.conn == nil {
+		return ErrLockLost
+	}
+
+	if err := l.conn.PingContext(ctx); err != nil {
+		// The lock went with the session
+		l.conn.Close()
+		l.conn = nil
+		return fmt.Errorf("%w: %v", ErrLockLost, err)
+	}
+	return nil
+}
+
+// Release unlocks and returns the connection to the pool
+func (l *PostgresLock) Release(ctx context.Context) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.conn == nil {
+		return nil
+	}
+
+	_, err := l.conn.ExecContext(ctx, "SELECT pg_advisory_unlock($1)", l.key)
+	l.conn.Close()
+	l.conn = nil
+	return err
+}
@@ -0,0 +1,64 @@
+package leader
+
+import (
+	"context"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// renewScript extends the lease only while this instance holds it
+var renewScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+    return redis.call("PEXPIRE", KEYS[1], ARGV[2])
+end
+return 0`)
+
+// releaseScript deletes the lease only while this instance holds it
+var releaseScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+    return redis.call("DEL", KEYS[1])
+end
+return 0`)
+
+// RedisLock is a lease on a Redis key that expires unless renewed, so a
+// crashed leader is replaced within the lease TTL
+type RedisLock struct {
+	client *redis.Client
+	key    string
+	ttl    time.Duration
+}
+
+// NewRedisLock creates a lease named name
+func NewRedisLock(client *redis.Client, name string, ttl time.Duration) *RedisLock {
+	if ttl <= 0 {
+		ttl = 15 * time.Second
+	}
+	return &RedisLock{
+		client: client,
+		key:    "n8n:leader:" + name,
+		ttl:    ttl,
+	}
+}
+
+// Acquire takes the lease if no instance holds it
+func (l *RedisLock) Acquire(ctx context.Context) (bool, error) {
+	return l.client.SetNX(ctx, l.key, instanceID, l.ttl).Result()
+}
+
+// Renew extends the lease by its TTL
+func (l *RedisLock) Renew(ctx context.Context) error {
+	extended, err := renewScript.Run(ctx, l.client, []string{l.key}, instanceID, l.ttl.Milliseconds()).Int()
+	if err != nil {
+		return err
+	}
+	if extended == 0 {
+		return ErrLockLost
+	}
+	return nil
+}
+
+// Release deletes the lease so another instance takes over immediately
+func (l *RedisLock) Release(ctx context.Context) error {
+	return releaseScript.Run(ctx, l.client, []string{l.key}, instanceID).Err()
+}
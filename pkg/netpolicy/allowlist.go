@@ -0,0 +1,78 @@
+// Package netpolicy restricts access by client network address.
+package netpolicy
+
+import (
+	"fmt"
+	"net/netip"
+	"strings"
+)
+
+// Allowlist holds the IP addresses and CIDR ranges allowed access. An
+// empty allowlist allows every address.
+type Allowlist struct {
+	prefixes []netip.Prefix
+}
+
+// Parse builds an allowlist from IP addresses and CIDR ranges such as
+// 10.0.0.0/8 or 2001:db8::/32
+func Parse(entries []string) (*Allowlist, error) {
+	a := &Allowlist{}
+	for _, entry := range entries {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		prefix, err := parseEntry(entry)
+		if err != nil {
+			return nil, err
+		}
+		a.prefixes = append(a.prefixes, prefix)
+	}
+	return a, nil
+}
+
+// Validate checks that every entry is an IP address or CIDR range
+func Validate(entries []string) error {
+	_, err := Parse(entries)
+	return err
+}
+
+func parseEntry(entry string) (netip.Prefix, error) {
+	if strings.Contains(entry, "/") {
+		prefix, err := netip.ParsePrefix(entry)
+		if err != nil {
+			return netip.Prefix{}, fmt.Errorf("invalid CIDR range %q", entry)
+		}
+		return prefix.Masked(), nil
+	}
+	addr, err := netip.ParseAddr(entry)
+	if err != nil {
+		return netip.Prefix{}, fmt.Errorf("invalid IP address %q", entry)
+	}
+	addr = addr.Unmap()
+	return netip.PrefixFrom(addr, addr.BitLen()), nil
+}
+
+// Empty reports whether the allowlist allows every address
+func (a *Allowlist) Empty() bool {
+	return a == nil || len(a.prefixes) == 0
+}
+
+// Allows reports whether ip is allowed. Unparsable addresses are only
+// allowed by an empty allowlist.
+func (a *Allowlist) Allows(ip string) bool {
+	if a.Empty() {
+		return true
+	}
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return false
+	}
+	addr = addr.Unmap()
+	for _, prefix := range a.prefixes {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
@@ -1,8 +1,7 @@
-package nodes
+package nodesdk
 
 import (
 	"context"
-	"errors"
 	"fmt"
 
 	"github.com/jaydeep/go-n8n/internal/domain/node"
@@ -58,81 +57,9 @@ func (n *BaseNode) GetDefaultParameters() map[string]interface{} {
 	return make(map[string]interface{})
 }
 
-// ValidateRequired validates required parameters
-func ValidateRequired(parameters map[string]interface{}, required []string) error {
-	for _, key := range required {
-		if _, exists := parameters[key]; !exists {
-			return fmt.Errorf("required parameter '%s' is missing", key)
-		}
-	}
-	return nil
-}
-
-// GetString gets a string parameter with default value
-func GetString(parameters map[string]interface{}, key string, defaultValue string) string {
-	if val, exists := parameters[key]; exists {
-		if str, ok := val.(string); ok {
-			return str
-		}
-	}
-	return defaultValue
-}
-
-// GetInt gets an integer parameter with default value
-func GetInt(parameters map[string]interface{}, key string, defaultValue int) int {
-	if val, exists := parameters[key]; exists {
-		switch v := val.(type) {
-		case int:
-			return v
-		case float64:
-			return int(v)
-		case float32:
-			return int(v)
-		}
-	}
-	return defaultValue
-}
-
-// GetBool gets a boolean parameter with default value
-func GetBool(parameters map[string]interface{}, key string, defaultValue bool) bool {
-	if val, exists := parameters[key]; exists {
-		if b, ok := val.(bool); ok {
-			return b
-		}
-	}
-	return defaultValue
-}
-
-// GetStringSlice gets a string slice parameter
-func GetStringSlice(parameters map[string]interface{}, key string) []string {
-	if val, exists := parameters[key]; exists {
-		switch v := val.(type) {
-		case []string:
-			return v
-		case []interface{}:
-			result := make([]string, len(v))
-			for i, item := range v {
-				if str, ok := item.(string); ok {
-					result[i] = str
-				}
-			}
-			return result
-		}
-	}
-	return []string{}
-}
-
-// GetMap gets a map parameter
-func GetMap(parameters map[string]interface{}, key string) map[string]interface{} {
-	if val, exists := parameters[key]; exists {
-		if m, ok := val.(map[string]interface{}); ok {
-			return m
-		}
-	}
-	return make(map[string]interface{})
-}
-
-// ProcessItems applies a function to each input item
+// ProcessItems applies a function to each input item. Each output item is
+// paired with the input item it was made from, unless fn paired it with
+// other items itself.
 func ProcessItems(ctx context.Context, input *node.NodeInput, fn func(context.Context, node.Item, int) (node.Item, error)) (*node.NodeOutput, error) {
 	output := &node.NodeOutput{
 		Data:     make([]node.Item, 0, len(input.Data)),
@@ -142,13 +69,16 @@ func ProcessItems(ctx context.Context, input *node.NodeInput, fn func(context.Co
 	for i, item := range input.Data {
 		select {
 		case <-ctx.Done():
-			return nil, errors.New("execution cancelled")
+			return nil, ctx.Err()
 		default:
 			processedItem, err := fn(ctx, item, i)
 			if err != nil {
 				output.Error = err
 				return output, err
 			}
+			if len(processedItem.PairedItem) == 0 || sharesPairing(processedItem, item) {
+				processedItem.PairedItem = []node.PairedItem{{Item: i}}
+			}
 			output.Data = append(output.Data, processedItem)
 		}
 	}
@@ -156,25 +86,32 @@ func ProcessItems(ctx context.Context, input *node.NodeInput, fn func(context.Co
 	return output, nil
 }
 
-// MergeItems merges multiple items into one
+// sharesPairing reports whether an item edited from another still carries
+// its pairing, which names the items the other was made from
+func sharesPairing(edited, original node.Item) bool {
+	return len(edited.PairedItem) > 0 && len(original.PairedItem) > 0 &&
+		&edited.PairedItem[0] == &original.PairedItem[0]
+}
+
+// MergeItems merges multiple items into one. Later items win on conflicting
+// fields; the input items are left unchanged and a single item is returned
+// as is.
 func MergeItems(items []node.Item) node.Item {
-	merged := node.Item{
-		JSON:   make(map[string]interface{}),
-		Binary: make(map[string]node.Binary),
+	if len(items) == 0 {
+		return node.Item{
+			JSON:   make(map[string]interface{}),
+			Binary: make(map[string]node.Binary),
+		}
 	}
 
-	for _, item := range items {
-		// Merge JSON data
-		for k, v := range item.JSON {
-			merged.JSON[k] = v
-		}
-		// Merge binary data
+	merged := items[0].Edit()
+	for _, item := range items[1:] {
+		merged.Merge(item.JSON)
 		for k, v := range item.Binary {
-			merged.Binary[k] = v
+			merged.SetBinary(k, v)
 		}
 	}
-
-	return merged
+	return merged.Item()
 }
 
 // SplitItems splits items based on a key
@@ -204,7 +141,8 @@ func FilterItems(items []node.Item, condition func(node.Item) bool) []node.Item
 	return filtered
 }
 
-// TransformItem transforms a single item
+// TransformItem transforms a single item. The item's JSON is shared with
+// other items, so transform must return a new map rather than modify it.
 func TransformItem(item node.Item, transform func(map[string]interface{}) map[string]interface{}) node.Item {
 	return node.Item{
 		JSON:   transform(item.JSON),
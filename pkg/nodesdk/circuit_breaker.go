@@ -0,0 +1,126 @@
+package nodesdk
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/jaydeep/go-n8n/configs"
+)
+
+// ErrCircuitOpen is returned without contacting the remote service while
+// its circuit breaker is open
+var ErrCircuitOpen = errors.New("circuit breaker open")
+
+// CircuitOpenError tells when the remote service may be tried again
+type CircuitOpenError struct {
+	Key        string
+	RetryAfter time.Duration
+}
+
+func (e *CircuitOpenError) Error() string {
+	return fmt.Sprintf("%s for %s, retry after %s", ErrCircuitOpen, e.Key, e.RetryAfter.Round(time.Second))
+}
+
+func (e *CircuitOpenError) Unwrap() error {
+	return ErrCircuitOpen
+}
+
+// IsRetryLater reports whether err means the remote service is temporarily
+// unavailable, so the node should be retried later rather than failed
+func IsRetryLater(err error) bool {
+	return errors.Is(err, ErrCircuitOpen) || errors.Is(err, ErrRateLimited)
+}
+
+// RetryAfter returns the delay suggested by a retry-later error
+func RetryAfter(err error) (time.Duration, bool) {
+	var open *CircuitOpenError
+	if errors.As(err, &open) {
+		return open.RetryAfter, true
+	}
+	var limited *RateLimitError
+	if errors.As(err, &limited) {
+		return limited.RetryAfter, true
+	}
+	return 0, false
+}
+
+// circuit is the breaker state of a single key
+type circuit struct {
+	failures  int
+	openUntil time.Time
+	probing   bool
+}
+
+// CircuitBreaker trips per credential and host after consecutive failures,
+// failing fast for a cooldown instead of letting workers hang on a dead
+// service. After the cooldown a single probe request is let through.
+type CircuitBreaker struct {
+	cfg      configs.CircuitBreakerConfig
+	mu       sync.Mutex
+	circuits map[string]*circuit
+}
+
+// NewCircuitBreaker creates a new circuit breaker
+func NewCircuitBreaker(cfg configs.CircuitBreakerConfig) *CircuitBreaker {
+	return &CircuitBreaker{
+		cfg:      cfg,
+		circuits: make(map[string]*circuit),
+	}
+}
+
+// Allow returns a CircuitOpenError when requests for key must not be sent
+func (b *CircuitBreaker) Allow(key string) error {
+	if b.cfg.FailureThreshold <= 0 {
+		return nil
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	c, ok := b.circuits[key]
+	if !ok || c.openUntil.IsZero() {
+		return nil
+	}
+
+	if wait := time.Until(c.openUntil); wait > 0 {
+		return &CircuitOpenError{Key: key, RetryAfter: wait}
+	}
+	if c.probing {
+		return &CircuitOpenError{Key: key, RetryAfter: b.cfg.Cooldown}
+	}
+	c.probing = true
+	return nil
+}
+
+// Success closes the circuit for key
+func (b *CircuitBreaker) Success(key string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	delete(b.circuits, key)
+}
+
+// Failure records a failed request and trips the breaker once the threshold
+// of consecutive failures is reached, or when the probe request fails
+func (b *CircuitBreaker) Failure(key string) {
+	if b.cfg.FailureThreshold <= 0 {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	c, ok := b.circuits[key]
+	if !ok {
+		c = &circuit{}
+		b.circuits[key] = c
+	}
+
+	c.failures++
+	if c.probing || c.failures >= b.cfg.FailureThreshold {
+		c.openUntil = time.Now().Add(b.cfg.Cooldown)
+		c.probing = false
+	}
+}
@@ -0,0 +1,53 @@
+package nodesdk
+
+import (
+	"errors"
+	"net/http"
+)
+
+// ErrCredentialsMissing is returned when a node needs credentials but none
+// were attached to it
+var ErrCredentialsMissing = errors.New("node credentials are missing")
+
+// Credentials returns the decrypted credential data of the node
+func Credentials(input *Input) (Params, error) {
+	if input == nil || len(input.Credentials) == 0 {
+		return nil, ErrCredentialsMissing
+	}
+	return Params(input.Credentials), nil
+}
+
+// CredentialID returns the ID under which the credential is rate limited,
+// or an empty string when the node has none
+func CredentialID(input *Input) string {
+	if input == nil {
+		return ""
+	}
+	return GetString(input.Credentials, "id", "")
+}
+
+// ApplyAuth authenticates req with the credential data. It understands the
+// common generic credential shapes: access_token/token (bearer),
+// username/password (basic) and api_key with an optional header_name
+// (default X-API-Key) or query_name.
+func ApplyAuth(req *http.Request, creds Params) error {
+	switch {
+	case creds.String("access_token", "") != "":
+		req.Header.Set("Authorization", "Bearer "+creds.String("access_token", ""))
+	case creds.String("token", "") != "":
+		req.Header.Set("Authorization", "Bearer "+creds.String("token", ""))
+	case creds.Has("username"):
+		req.SetBasicAuth(creds.String("username", ""), creds.String("password", ""))
+	case creds.String("api_key", "") != "":
+		if name := creds.String("query_name", ""); name != "" {
+			q := req.URL.Query()
+			q.Set(name, creds.String("api_key", ""))
+			req.URL.RawQuery = q.Encode()
+		} else {
+			req.Header.Set(creds.String("header_name", "X-API-Key"), creds.String("api_key", ""))
+		}
+	default:
+		return ErrCredentialsMissing
+	}
+	return nil
+}
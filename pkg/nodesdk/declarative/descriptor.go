@@ -0,0 +1,236 @@
+// Package declarative builds "codeless" REST nodes from JSON or YAML
+// descriptor files, so simple API integrations need no Go code.
+package declarative
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Auth types supported by descriptors
+const (
+	AuthNone   = "none"
+	AuthBearer = "bearer"
+	AuthBasic  = "basic"
+	AuthAPIKey = "api_key"
+)
+
+// Pagination types supported by descriptors
+const (
+	PaginateCursor = "cursor"
+	PaginateOffset = "offset"
+	PaginatePage   = "page"
+	PaginateLink   = "link_header"
+)
+
+// Descriptor defines a REST node: where the API lives, how to authenticate
+// and which operations map to which HTTP calls
+type Descriptor struct {
+	Type        string            `json:"type" yaml:"type"`
+	Name        string            `json:"name" yaml:"name"`
+	Description string            `json:"description" yaml:"description"`
+	Icon        string            `json:"icon" yaml:"icon"`
+	Category    string            `json:"category" yaml:"category"`
+	Version     string            `json:"version" yaml:"version"`
+	BaseURL     string            `json:"base_url" yaml:"base_url"`
+	Headers     map[string]string `json:"headers" yaml:"headers"`
+	Auth        Auth              `json:"auth" yaml:"auth"`
+	Operations  []Operation       `json:"operations" yaml:"operations"`
+	// Translations maps a language to schema texts keyed like
+	// "name" or "property.<name>.display_name"
+	Translations map[string]map[string]string `json:"translations" yaml:"translations"`
+
+	// Source is the file the descriptor was loaded from
+	Source string `json:"-" yaml:"-"`
+}
+
+// Auth describes how requests are authenticated with the node credential
+type Auth struct {
+	Type       string `json:"type" yaml:"type"`
+	Credential string `json:"credential" yaml:"credential"`
+	HeaderName string `json:"header_name" yaml:"header_name"`
+	QueryName  string `json:"query_name" yaml:"query_name"`
+}
+
+// Operation maps a node operation to an HTTP call. Path, query, header and
+// body values may reference parameters as {{name}} and fields of the
+// current item as {{$json.field}}.
+type Operation struct {
+	Name        string                 `json:"name" yaml:"name"`
+	DisplayName string                 `json:"display_name" yaml:"display_name"`
+	Description string                 `json:"description" yaml:"description"`
+	Method      string                 `json:"method" yaml:"method"`
+	Path        string                 `json:"path" yaml:"path"`
+	Query       map[string]string      `json:"query" yaml:"query"`
+	Headers     map[string]string      `json:"headers" yaml:"headers"`
+	Body        map[string]interface{} `json:"body" yaml:"body"`
+	Parameters  []Parameter            `json:"parameters" yaml:"parameters"`
+	// Output is the dot path of the items in the response, e.g. "data.items"
+	Output     string      `json:"output" yaml:"output"`
+	Pagination *Pagination `json:"pagination" yaml:"pagination"`
+}
+
+// Parameter is a user supplied parameter of an operation
+type Parameter struct {
+	Name        string      `json:"name" yaml:"name"`
+	DisplayName string      `json:"display_name" yaml:"display_name"`
+	Type        string      `json:"type" yaml:"type"`
+	Required    bool        `json:"required" yaml:"required"`
+	Default     interface{} `json:"default" yaml:"default"`
+	Description string      `json:"description" yaml:"description"`
+}
+
+// Pagination describes how to fetch further pages of a list operation
+type Pagination struct {
+	Type string `json:"type" yaml:"type"`
+	// CursorPath is the dot path of the next cursor in the response
+	CursorPath string `json:"cursor_path" yaml:"cursor_path"`
+	// CursorParam is the query parameter carrying the cursor
+	CursorParam string `json:"cursor_param" yaml:"cursor_param"`
+	// OffsetParam and PageParam name the offset/page query parameters
+	OffsetParam string `json:"offset_param" yaml:"offset_param"`
+	PageParam   string `json:"page_param" yaml:"page_param"`
+	// LimitParam and PageSize set the page size
+	LimitParam string `json:"limit_param" yaml:"limit_param"`
+	PageSize   int    `json:"page_size" yaml:"page_size"`
+	// MaxPages bounds the number of requests, 100 by default
+	MaxPages int `json:"max_pages" yaml:"max_pages"`
+}
+
+// Parse decodes a descriptor; the format is picked from the file extension
+func Parse(name string, data []byte) (*Descriptor, error) {
+	var d Descriptor
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".json":
+		if err := json.Unmarshal(data, &d); err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+	case ".yaml", ".yml":
+		if err := yaml.Unmarshal(data, &d); err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+	default:
+		return nil, fmt.Errorf("%s: unsupported descriptor format", name)
+	}
+
+	d.Source = name
+	if err := d.Validate(); err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	return &d, nil
+}
+
+// LoadFile reads and validates a descriptor file
+func LoadFile(path string) (*Descriptor, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(path, data)
+}
+
+// IsDescriptorFile reports whether path has a descriptor file extension
+func IsDescriptorFile(path string) bool {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json", ".yaml", ".yml":
+		return true
+	}
+	return false
+}
+
+// LoadDir loads every descriptor file directly in dir. A missing directory
+// yields no descriptors.
+func LoadDir(dir string) ([]*Descriptor, error) {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var descriptors []*Descriptor
+	for _, entry := range entries {
+		if entry.IsDir() || !IsDescriptorFile(entry.Name()) {
+			continue
+		}
+		d, err := LoadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		descriptors = append(descriptors, d)
+	}
+	return descriptors, nil
+}
+
+// Validate checks that the descriptor can be compiled into a node
+func (d *Descriptor) Validate() error {
+	if d.Type == "" {
+		return errors.New("descriptor type is required")
+	}
+	if d.Name == "" {
+		return errors.New("descriptor name is required")
+	}
+	if !strings.HasPrefix(d.BaseURL, "http://") && !strings.HasPrefix(d.BaseURL, "https://") {
+		return errors.New("descriptor base_url must be an http(s) URL")
+	}
+	if len(d.Operations) == 0 {
+		return errors.New("descriptor needs at least one operation")
+	}
+
+	switch d.Auth.Type {
+	case "", AuthNone, AuthBearer, AuthBasic, AuthAPIKey:
+	default:
+		return fmt.Errorf("unknown auth type %q", d.Auth.Type)
+	}
+	if d.Auth.Type != "" && d.Auth.Type != AuthNone && d.Auth.Credential == "" {
+		return errors.New("auth credential type is required")
+	}
+
+	seen := make(map[string]bool, len(d.Operations))
+	for _, op := range d.Operations {
+		if op.Name == "" {
+			return errors.New("operation name is required")
+		}
+		if seen[op.Name] {
+			return fmt.Errorf("duplicate operation %q", op.Name)
+		}
+		seen[op.Name] = true
+
+		switch strings.ToUpper(op.Method) {
+		case http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete, http.MethodHead:
+		default:
+			return fmt.Errorf("operation %q: unsupported method %q", op.Name, op.Method)
+		}
+
+		if p := op.Pagination; p != nil {
+			switch p.Type {
+			case PaginateCursor:
+				if p.CursorPath == "" || p.CursorParam == "" {
+					return fmt.Errorf("operation %q: cursor pagination needs cursor_path and cursor_param", op.Name)
+				}
+			case PaginateOffset, PaginatePage, PaginateLink:
+			default:
+				return fmt.Errorf("operation %q: unknown pagination type %q", op.Name, p.Type)
+			}
+		}
+	}
+	return nil
+}
+
+// Operation returns the operation with the given name
+func (d *Descriptor) Operation(name string) (*Operation, bool) {
+	for i := range d.Operations {
+		if d.Operations[i].Name == name {
+			return &d.Operations[i], true
+		}
+	}
+	return nil, false
+}
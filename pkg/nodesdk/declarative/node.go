@@ -0,0 +1,549 @@
+package declarative
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/jaydeep/go-n8n/pkg/i18n"
+	"github.com/jaydeep/go-n8n/pkg/nodesdk"
+)
+
+// maxErrorBody bounds the response body quoted in HTTP errors
+const maxErrorBody = 512
+
+var placeholder = regexp.MustCompile(`\{\{\s*([^}\s]+)\s*\}\}`)
+
+// RESTNode is the generic node a descriptor is compiled into
+type RESTNode struct {
+	nodesdk.BaseNode
+	descriptor *Descriptor
+}
+
+// NewNode compiles a descriptor into a node
+func NewNode(d *Descriptor) nodesdk.Node {
+	category := nodesdk.Category(d.Category)
+	if category == "" {
+		category = nodesdk.CategoryIntegration
+	}
+	version := d.Version
+	if version == "" {
+		version = "1.0"
+	}
+
+	return &RESTNode{
+		BaseNode: nodesdk.BaseNode{
+			Type:        d.Type,
+			Name:        d.Name,
+			Category:    category,
+			Version:     version,
+			Description: d.Description,
+			Icon:        d.Icon,
+		},
+		descriptor: d,
+	}
+}
+
+// Register compiles the descriptors and adds them and their icons to the
+// registry
+func Register(r *nodesdk.Registry, descriptors []*Descriptor) error {
+	for _, d := range descriptors {
+		d := d
+		node := NewNode(d)
+		if err := r.Register(d.Type, node.GetCategory(), func() nodesdk.Node { return NewNode(d) }); err != nil {
+			return fmt.Errorf("%s: %w", d.Source, err)
+		}
+		if err := RegisterIcon(r, d); err != nil {
+			return fmt.Errorf("%s: %w", d.Source, err)
+		}
+	}
+	return nil
+}
+
+// RegisterTranslations adds the translations of a descriptor to the bundle
+func RegisterTranslations(bundle *i18n.Bundle, d *Descriptor) {
+	for lang, messages := range d.Translations {
+		keyed := make(map[string]string, len(messages))
+		for k, v := range messages {
+			keyed[nodesdk.TranslationKey(d.Type, k)] = v
+		}
+		bundle.Add(lang, keyed)
+	}
+}
+
+// RegisterIcon registers the icon of a descriptor whose icon is given as
+// "file:<name>", relative to the descriptor file
+func RegisterIcon(r *nodesdk.Registry, d *Descriptor) error {
+	name, ok := strings.CutPrefix(d.Icon, "file:")
+	if !ok {
+		return nil
+	}
+	return nodesdk.RegisterIcon(r, d.Type, os.DirFS(filepath.Dir(d.Source)), name)
+}
+
+// Descriptor returns the descriptor the node was compiled from
+func (n *RESTNode) Descriptor() *Descriptor {
+	return n.descriptor
+}
+
+// GetCredentialTypes returns the credential type of the descriptor auth
+func (n *RESTNode) GetCredentialTypes() []string {
+	if n.descriptor.Auth.Credential == "" {
+		return []string{}
+	}
+	return []string{n.descriptor.Auth.Credential}
+}
+
+// HasSideEffects reports whether the selected operation changes remote state
+func (n *RESTNode) HasSideEffects(parameters map[string]interface{}) bool {
+	op, err := n.operation(parameters)
+	if err != nil {
+		return true
+	}
+	method := strings.ToUpper(op.Method)
+	return method != http.MethodGet && method != http.MethodHead
+}
+
+// Execute performs the operation's HTTP call once per input item
+func (n *RESTNode) Execute(ctx context.Context, input *nodesdk.Input) (*nodesdk.Output, error) {
+	op, err := n.operation(input.Parameters)
+	if err != nil {
+		return nil, err
+	}
+
+	items := input.Data
+	if len(items) == 0 {
+		items = []nodesdk.Item{{JSON: map[string]interface{}{}}}
+	}
+
+	output := nodesdk.CreateEmptyOutput()
+	for _, item := range items {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		results, err := n.call(ctx, input, op, item)
+		if err != nil {
+			output.Error = err
+			return output, err
+		}
+		output.Data = append(output.Data, results...)
+	}
+	return output, nil
+}
+
+// Validate validates the node parameters
+func (n *RESTNode) Validate(parameters map[string]interface{}) error {
+	op, err := n.operation(parameters)
+	if err != nil {
+		return err
+	}
+	for _, p := range op.Parameters {
+		if p.Required && p.Default == nil {
+			if _, ok := parameters[p.Name]; !ok {
+				return fmt.Errorf("required parameter '%s' is missing", p.Name)
+			}
+		}
+	}
+	return nil
+}
+
+// GetSchema returns the node schema: an operation selector plus the
+// parameters of each operation, shown only when it is selected
+func (n *RESTNode) GetSchema() *nodesdk.Schema {
+	d := n.descriptor
+
+	options := make([]nodesdk.PropertyOption, 0, len(d.Operations))
+	for _, op := range d.Operations {
+		options = append(options, nodesdk.PropertyOption{
+			Name:        displayName(op.DisplayName, op.Name),
+			Value:       op.Name,
+			Description: op.Description,
+		})
+	}
+
+	properties := []nodesdk.Property{{
+		Name:        "operation",
+		DisplayName: "Operation",
+		Type:        nodesdk.PropertyOptions,
+		Default:     d.Operations[0].Name,
+		Required:    true,
+		Options:     options,
+	}}
+	for _, op := range d.Operations {
+		for _, p := range op.Parameters {
+			propertyType := nodesdk.PropertyString
+			if p.Type != "" {
+				propertyType = nodesdk.PropertyType(p.Type)
+			}
+			properties = append(properties, nodesdk.Property{
+				Name:        p.Name,
+				DisplayName: displayName(p.DisplayName, p.Name),
+				Type:        propertyType,
+				Default:     p.Default,
+				Required:    p.Required,
+				Description: p.Description,
+				DisplayOptions: &nodesdk.DisplayOptions{
+					Show: map[string][]interface{}{"operation": {op.Name}},
+				},
+			})
+		}
+	}
+
+	var credentials []nodesdk.CredentialSchema
+	if d.Auth.Credential != "" {
+		credentials = []nodesdk.CredentialSchema{{Name: d.Auth.Credential, Required: true}}
+	}
+
+	return &nodesdk.Schema{
+		Type:        n.Type,
+		Name:        n.Name,
+		Group:       []string{string(n.Category)},
+		Version:     1,
+		Description: n.Description,
+		Icon:        n.Icon,
+		Defaults:    nodesdk.Defaults{Name: n.Name},
+		Inputs:      []nodesdk.IOSchema{{Type: "main"}},
+		Outputs:     []nodesdk.IOSchema{{Type: "main"}},
+		Properties:  properties,
+		Credentials: credentials,
+	}
+}
+
+// GetDefaultParameters returns the default parameters
+func (n *RESTNode) GetDefaultParameters() map[string]interface{} {
+	return map[string]interface{}{
+		"operation": n.descriptor.Operations[0].Name,
+	}
+}
+
+func (n *RESTNode) operation(parameters map[string]interface{}) (*Operation, error) {
+	name := nodesdk.GetString(parameters, "operation", n.descriptor.Operations[0].Name)
+	op, ok := n.descriptor.Operation(name)
+	if !ok {
+		return nil, fmt.Errorf("unknown operation %q", name)
+	}
+	return op, nil
+}
+
+// call performs the operation for one item, following pagination
+func (n *RESTNode) call(ctx context.Context, input *nodesdk.Input, op *Operation, item nodesdk.Item) ([]nodesdk.Item, error) {
+	values := n.values(op, input.Parameters, item)
+
+	endpoint, err := url.Parse(strings.TrimRight(n.descriptor.BaseURL, "/") + "/" + strings.TrimLeft(substitute(op.Path, values, true), "/"))
+	if err != nil {
+		return nil, err
+	}
+	query := endpoint.Query()
+	for k, v := range op.Query {
+		if resolved := substitute(v, values, false); resolved != "" {
+			query.Set(k, resolved)
+		}
+	}
+
+	var body []byte
+	if op.Body != nil {
+		body, err = json.Marshal(substituteValue(op.Body, values))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	p := op.Pagination
+	maxPages := 1
+	if p != nil {
+		maxPages = p.MaxPages
+		if maxPages <= 0 {
+			maxPages = 100
+		}
+		if p.LimitParam != "" && p.PageSize > 0 {
+			query.Set(p.LimitParam, strconv.Itoa(p.PageSize))
+		}
+	}
+
+	var results []nodesdk.Item
+	offset, page := 0, 1
+	for i := 0; i < maxPages; i++ {
+		if p != nil {
+			switch p.Type {
+			case PaginateOffset:
+				query.Set(paramName(p.OffsetParam, "offset"), strconv.Itoa(offset))
+			case PaginatePage:
+				query.Set(paramName(p.PageParam, "page"), strconv.Itoa(page))
+			}
+		}
+		endpoint.RawQuery = query.Encode()
+
+		resp, err := n.do(ctx, input, op, values, endpoint.String(), body)
+		if err != nil {
+			return nil, err
+		}
+
+		pageItems := toItems(lookup(resp.data, op.Output))
+		results = append(results, pageItems...)
+		if p == nil || len(pageItems) == 0 {
+			break
+		}
+
+		switch p.Type {
+		case PaginateCursor:
+			cursor := lookup(resp.data, p.CursorPath)
+			if cursor == nil || fmt.Sprint(cursor) == "" {
+				return results, nil
+			}
+			query.Set(p.CursorParam, fmt.Sprint(cursor))
+		case PaginateOffset:
+			offset += len(pageItems)
+			if p.PageSize > 0 && len(pageItems) < p.PageSize {
+				return results, nil
+			}
+		case PaginatePage:
+			page++
+			if p.PageSize > 0 && len(pageItems) < p.PageSize {
+				return results, nil
+			}
+		case PaginateLink:
+			next := nextLink(resp.header.Get("Link"))
+			if next == "" {
+				return results, nil
+			}
+			nextURL, err := endpoint.Parse(next)
+			if err != nil {
+				return nil, err
+			}
+			endpoint = nextURL
+			query = endpoint.Query()
+		}
+	}
+	return results, nil
+}
+
+type response struct {
+	data   interface{}
+	header http.Header
+}
+
+func (n *RESTNode) do(ctx context.Context, input *nodesdk.Input, op *Operation, values map[string]interface{}, endpoint string, body []byte) (*response, error) {
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, strings.ToUpper(op.Method), endpoint, reader)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	for k, v := range n.descriptor.Headers {
+		req.Header.Set(k, substitute(v, values, false))
+	}
+	for k, v := range op.Headers {
+		req.Header.Set(k, substitute(v, values, false))
+	}
+	if err := n.authenticate(req, input); err != nil {
+		return nil, err
+	}
+
+	resp, err := nodesdk.HTTPClientFrom(ctx).Do(ctx, nodesdk.CredentialID(input), req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	raw, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		if len(raw) > maxErrorBody {
+			raw = raw[:maxErrorBody]
+		}
+		return nil, fmt.Errorf("%s %s returned %d: %s", req.Method, req.URL.Path, resp.StatusCode, strings.TrimSpace(string(raw)))
+	}
+
+	var data interface{}
+	if len(bytes.TrimSpace(raw)) > 0 {
+		if err := json.Unmarshal(raw, &data); err != nil {
+			data = map[string]interface{}{"body": string(raw)}
+		}
+	}
+	return &response{data: data, header: resp.Header}, nil
+}
+
+func (n *RESTNode) authenticate(req *http.Request, input *nodesdk.Input) error {
+	auth := n.descriptor.Auth
+	if auth.Type == "" || auth.Type == AuthNone {
+		return nil
+	}
+
+	creds, err := nodesdk.Credentials(input)
+	if err != nil {
+		return err
+	}
+
+	switch auth.Type {
+	case AuthBearer:
+		token := creds.String("access_token", creds.String("token", ""))
+		if token == "" {
+			return nodesdk.ErrCredentialsMissing
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+	case AuthBasic:
+		req.SetBasicAuth(creds.String("username", ""), creds.String("password", ""))
+	case AuthAPIKey:
+		key := creds.String("api_key", "")
+		if key == "" {
+			return nodesdk.ErrCredentialsMissing
+		}
+		if auth.QueryName != "" {
+			q := req.URL.Query()
+			q.Set(auth.QueryName, key)
+			req.URL.RawQuery = q.Encode()
+		} else {
+			req.Header.Set(paramName(auth.HeaderName, "X-API-Key"), key)
+		}
+	}
+	return nil
+}
+
+// values merges operation defaults with the node parameters
+func (n *RESTNode) values(op *Operation, parameters map[string]interface{}, item nodesdk.Item) map[string]interface{} {
+	values := make(map[string]interface{}, len(op.Parameters)+len(parameters)+1)
+	for _, p := range op.Parameters {
+		if p.Default != nil {
+			values[p.Name] = p.Default
+		}
+	}
+	for k, v := range parameters {
+		values[k] = v
+	}
+	values["$json"] = item.JSON
+	return values
+}
+
+// substitute replaces {{name}} placeholders; path segments are escaped
+func substitute(s string, values map[string]interface{}, escapePath bool) string {
+	return placeholder.ReplaceAllStringFunc(s, func(m string) string {
+		v := lookup(values, placeholder.FindStringSubmatch(m)[1])
+		if v == nil {
+			return ""
+		}
+		str := fmt.Sprint(v)
+		if escapePath {
+			return url.PathEscape(str)
+		}
+		return str
+	})
+}
+
+// substituteValue resolves placeholders in a body template. A value that is
+// exactly one placeholder keeps the parameter's type.
+func substituteValue(v interface{}, values map[string]interface{}) interface{} {
+	switch t := v.(type) {
+	case string:
+		if m := placeholder.FindStringSubmatch(t); m != nil && m[0] == strings.TrimSpace(t) {
+			return lookup(values, m[1])
+		}
+		return substitute(t, values, false)
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(t))
+		for k, val := range t {
+			out[k] = substituteValue(val, values)
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(t))
+		for i, val := range t {
+			out[i] = substituteValue(val, values)
+		}
+		return out
+	}
+	return v
+}
+
+// lookup resolves a dot path in decoded JSON
+func lookup(data interface{}, path string) interface{} {
+	if path == "" {
+		return data
+	}
+	current := data
+	for _, key := range strings.Split(path, ".") {
+		switch t := current.(type) {
+		case map[string]interface{}:
+			current = t[key]
+		case []interface{}:
+			i, err := strconv.Atoi(key)
+			if err != nil || i < 0 || i >= len(t) {
+				return nil
+			}
+			current = t[i]
+		default:
+			return nil
+		}
+	}
+	return current
+}
+
+// toItems turns a response value into node items
+func toItems(v interface{}) []nodesdk.Item {
+	switch t := v.(type) {
+	case nil:
+		return nil
+	case []interface{}:
+		items := make([]nodesdk.Item, 0, len(t))
+		for _, el := range t {
+			items = append(items, toItem(el))
+		}
+		return items
+	}
+	return []nodesdk.Item{toItem(v)}
+}
+
+func toItem(v interface{}) nodesdk.Item {
+	if m, ok := v.(map[string]interface{}); ok {
+		return nodesdk.Item{JSON: m}
+	}
+	return nodesdk.Item{JSON: map[string]interface{}{"value": v}}
+}
+
+// nextLink extracts the rel="next" URL of an RFC 8288 Link header
+func nextLink(header string) string {
+	for _, part := range strings.Split(header, ",") {
+		sections := strings.Split(part, ";")
+		if len(sections) < 2 {
+			continue
+		}
+		for _, attr := range sections[1:] {
+			if strings.ReplaceAll(strings.TrimSpace(attr), " ", "") == `rel="next"` {
+				return strings.Trim(strings.TrimSpace(sections[0]), "<>")
+			}
+		}
+	}
+	return ""
+}
+
+func paramName(name, fallback string) string {
+	if name == "" {
+		return fallback
+	}
+	return name
+}
+
+func displayName(display, name string) string {
+	if display != "" {
+		return display
+	}
+	return name
+}
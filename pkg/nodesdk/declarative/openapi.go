@@ -0,0 +1,445 @@
+package declarative
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// OpenAPIOptions sets what an OpenAPI document does not say about the
+// node generated from it
+type OpenAPIOptions struct {
+	// Type is the node type, required
+	Type string
+	// Name overrides the title of the API as node name
+	Name string
+	// BaseURL overrides the first server of the document, and is required
+	// when that server is not an absolute http(s) URL
+	BaseURL string
+}
+
+// openAPIMethods are the operations of a path item a node can call, in the
+// order they are listed
+var openAPIMethods = []string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete, http.MethodHead}
+
+var (
+	pathTemplate  = regexp.MustCompile(`\{([^}/]+)\}`)
+	nonIdentifier = regexp.MustCompile(`[^A-Za-z0-9]+`)
+	camelBoundary = regexp.MustCompile(`([a-z0-9])([A-Z])`)
+)
+
+// openAPIDocument is the part of an OpenAPI 3 document nodes are generated
+// from. JSON documents decode as YAML.
+type openAPIDocument struct {
+	OpenAPI string `yaml:"openapi"`
+	Info    struct {
+		Title       string `yaml:"title"`
+		Description string `yaml:"description"`
+		Version     string `yaml:"version"`
+	} `yaml:"info"`
+	Servers []struct {
+		URL       string `yaml:"url"`
+		Variables map[string]struct {
+			Default string `yaml:"default"`
+		} `yaml:"variables"`
+	} `yaml:"servers"`
+	Paths      map[string]map[string]yaml.Node `yaml:"paths"`
+	Security   []map[string][]string           `yaml:"security"`
+	Components struct {
+		Parameters      map[string]*openAPIParameter   `yaml:"parameters"`
+		RequestBodies   map[string]*openAPIRequestBody `yaml:"requestBodies"`
+		Schemas         map[string]*openAPISchema      `yaml:"schemas"`
+		SecuritySchemes map[string]openAPISecurity     `yaml:"securitySchemes"`
+	} `yaml:"components"`
+}
+
+type openAPIOperation struct {
+	OperationID string              `yaml:"operationId"`
+	Summary     string              `yaml:"summary"`
+	Description string              `yaml:"description"`
+	Parameters  []*openAPIParameter `yaml:"parameters"`
+	RequestBody *openAPIRequestBody `yaml:"requestBody"`
+}
+
+type openAPIParameter struct {
+	Ref         string         `yaml:"$ref"`
+	Name        string         `yaml:"name"`
+	In          string         `yaml:"in"`
+	Description string         `yaml:"description"`
+	Required    bool           `yaml:"required"`
+	Schema      *openAPISchema `yaml:"schema"`
+}
+
+type openAPIRequestBody struct {
+	Ref      string `yaml:"$ref"`
+	Required bool   `yaml:"required"`
+	Content  map[string]struct {
+		Schema *openAPISchema `yaml:"schema"`
+	} `yaml:"content"`
+}
+
+type openAPISchema struct {
+	Ref         string                    `yaml:"$ref"`
+	Type        interface{}               `yaml:"type"`
+	Description string                    `yaml:"description"`
+	Default     interface{}               `yaml:"default"`
+	Properties  map[string]*openAPISchema `yaml:"properties"`
+	Required    []string                  `yaml:"required"`
+}
+
+type openAPISecurity struct {
+	Type   string `yaml:"type"`
+	Scheme string `yaml:"scheme"`
+	In     string `yaml:"in"`
+	Name   string `yaml:"name"`
+}
+
+// FromOpenAPI generates a descriptor from an OpenAPI 3 document in JSON or
+// YAML. Each operation of the document becomes a node operation, named
+// after its operationId, with its path, query and header parameters and
+// the properties of its JSON object request body as node parameters. The
+// security scheme the document requires, or else its first one, becomes
+// the node auth, with a credential type named after the node.
+func FromOpenAPI(data []byte, opts OpenAPIOptions) (*Descriptor, error) {
+	var doc openAPIDocument
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("invalid OpenAPI document: %w", err)
+	}
+	if !strings.HasPrefix(doc.OpenAPI, "3.") {
+		return nil, errors.New("only OpenAPI 3 documents are supported")
+	}
+	if opts.Type == "" {
+		return nil, errors.New("descriptor type is required")
+	}
+
+	d := &Descriptor{
+		Type:        opts.Type,
+		Name:        displayName(opts.Name, doc.Info.Title),
+		Description: doc.Info.Description,
+		Version:     doc.Info.Version,
+		BaseURL:     opts.BaseURL,
+		Auth:        doc.auth(opts.Type),
+		Source:      "openapi",
+	}
+	if d.Name == "" {
+		d.Name = opts.Type
+	}
+	if d.BaseURL == "" && len(doc.Servers) > 0 {
+		server := doc.Servers[0]
+		d.BaseURL = server.URL
+		for name, variable := range server.Variables {
+			d.BaseURL = strings.ReplaceAll(d.BaseURL, "{"+name+"}", variable.Default)
+		}
+	}
+	if !strings.HasPrefix(d.BaseURL, "http://") && !strings.HasPrefix(d.BaseURL, "https://") {
+		return nil, errors.New("the document has no absolute server URL; set the base URL")
+	}
+
+	paths := make([]string, 0, len(doc.Paths))
+	for path := range doc.Paths {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	names := make(map[string]int)
+	for _, path := range paths {
+		item := doc.Paths[path]
+		var shared []*openAPIParameter
+		if node, ok := item["parameters"]; ok {
+			if err := node.Decode(&shared); err != nil {
+				return nil, fmt.Errorf("path %s: %w", path, err)
+			}
+		}
+		for _, method := range openAPIMethods {
+			node, ok := item[strings.ToLower(method)]
+			if !ok {
+				continue
+			}
+			var op openAPIOperation
+			if err := node.Decode(&op); err != nil {
+				return nil, fmt.Errorf("%s %s: %w", method, path, err)
+			}
+			operation, err := doc.operation(method, path, shared, &op)
+			if err != nil {
+				return nil, fmt.Errorf("%s %s: %w", method, path, err)
+			}
+			names[operation.Name]++
+			if n := names[operation.Name]; n > 1 {
+				operation.Name += "_" + strconv.Itoa(n)
+			}
+			d.Operations = append(d.Operations, operation)
+		}
+	}
+
+	if err := d.Validate(); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// operation maps an OpenAPI operation to a node operation
+func (doc *openAPIDocument) operation(method, path string, shared []*openAPIParameter, op *openAPIOperation) (Operation, error) {
+	name := identifier(op.OperationID)
+	if name == "" {
+		name = identifier(strings.ToLower(method) + " " + path)
+	}
+	operation := Operation{
+		Name:        name,
+		DisplayName: op.Summary,
+		Description: op.Description,
+		Method:      method,
+		Path:        path,
+	}
+
+	// Operation parameters override the path item's of the same name and location
+	params := make(map[string]*openAPIParameter)
+	var order []string
+	for _, p := range append(append([]*openAPIParameter{}, shared...), op.Parameters...) {
+		resolved, err := doc.parameter(p)
+		if err != nil {
+			return Operation{}, err
+		}
+		key := resolved.In + ":" + resolved.Name
+		if _, ok := params[key]; !ok {
+			order = append(order, key)
+		}
+		params[key] = resolved
+	}
+
+	used := make(map[string]bool)
+	for _, key := range order {
+		p := params[key]
+		if p.In == "cookie" || p.Name == "" {
+			continue
+		}
+		param := doc.parameterOf(p.Name, p.Description, p.Required || p.In == "path", p.Schema, used)
+		ref := "{{" + param.Name + "}}"
+		switch p.In {
+		case "path":
+			operation.Path = strings.ReplaceAll(operation.Path, "{"+p.Name+"}", ref)
+		case "query":
+			if operation.Query == nil {
+				operation.Query = make(map[string]string)
+			}
+			operation.Query[p.Name] = ref
+		case "header":
+			if operation.Headers == nil {
+				operation.Headers = make(map[string]string)
+			}
+			operation.Headers[p.Name] = ref
+		default:
+			continue
+		}
+		operation.Parameters = append(operation.Parameters, param)
+	}
+	// Path templates without a declared parameter still need a value
+	operation.Path = pathTemplate.ReplaceAllStringFunc(operation.Path, func(m string) string {
+		if strings.HasPrefix(m, "{{") {
+			return m
+		}
+		name := strings.Trim(m, "{}")
+		param := doc.parameterOf(name, "", true, nil, used)
+		operation.Parameters = append(operation.Parameters, param)
+		return "{{" + param.Name + "}}"
+	})
+
+	body, err := doc.requestBody(op.RequestBody)
+	if err != nil {
+		return Operation{}, err
+	}
+	if body != nil && len(body.Properties) > 0 {
+		required := make(map[string]bool, len(body.Required))
+		for _, name := range body.Required {
+			required[name] = true
+		}
+		properties := make([]string, 0, len(body.Properties))
+		for name := range body.Properties {
+			properties = append(properties, name)
+		}
+		sort.Strings(properties)
+
+		operation.Body = make(map[string]interface{}, len(properties))
+		for _, name := range properties {
+			schema, err := doc.schema(body.Properties[name])
+			if err != nil {
+				return Operation{}, err
+			}
+			param := doc.parameterOf(name, schema.Description, required[name], schema, used)
+			operation.Body[name] = "{{" + param.Name + "}}"
+			operation.Parameters = append(operation.Parameters, param)
+		}
+	}
+	return operation, nil
+}
+
+// parameterOf returns a node parameter for an API value. The parameter is
+// named after the value, made safe for placeholders and unique among the
+// parameters of the operation.
+func (doc *openAPIDocument) parameterOf(name, description string, required bool, schema *openAPISchema, used map[string]bool) Parameter {
+	base := identifier(name)
+	if base == "" {
+		base = "value"
+	}
+	unique := base
+	for i := 2; used[unique]; i++ {
+		unique = base + "_" + strconv.Itoa(i)
+	}
+	used[unique] = true
+
+	param := Parameter{
+		Name:        unique,
+		DisplayName: name,
+		Type:        "string",
+		Required:    required,
+		Description: description,
+	}
+	if resolved, err := doc.schema(schema); err == nil && resolved != nil {
+		param.Type = parameterType(resolved.Type)
+		param.Default = resolved.Default
+		if param.Description == "" {
+			param.Description = resolved.Description
+		}
+	}
+	return param
+}
+
+// requestBody returns the schema of the JSON object body of an operation,
+// nil when it has none
+func (doc *openAPIDocument) requestBody(body *openAPIRequestBody) (*openAPISchema, error) {
+	if body == nil {
+		return nil, nil
+	}
+	if body.Ref != "" {
+		resolved, ok := doc.Components.RequestBodies[refName(body.Ref, "requestBodies")]
+		if !ok || resolved == nil || resolved.Ref != "" {
+			return nil, fmt.Errorf("unresolved reference %s", body.Ref)
+		}
+		body = resolved
+	}
+	for mediaType, content := range body.Content {
+		if mediaType != "application/json" && !strings.HasSuffix(mediaType, "+json") {
+			continue
+		}
+		schema, err := doc.schema(content.Schema)
+		if err != nil || schema == nil || parameterType(schema.Type) != "json" {
+			return nil, err
+		}
+		return schema, nil
+	}
+	return nil, nil
+}
+
+// parameter resolves a reference to a shared parameter
+func (doc *openAPIDocument) parameter(p *openAPIParameter) (*openAPIParameter, error) {
+	if p == nil {
+		return &openAPIParameter{}, nil
+	}
+	if p.Ref == "" {
+		return p, nil
+	}
+	resolved, ok := doc.Components.Parameters[refName(p.Ref, "parameters")]
+	if !ok || resolved == nil || resolved.Ref != "" {
+		return nil, fmt.Errorf("unresolved reference %s", p.Ref)
+	}
+	return resolved, nil
+}
+
+// schema resolves a chain of references to a shared schema
+func (doc *openAPIDocument) schema(s *openAPISchema) (*openAPISchema, error) {
+	for depth := 0; s != nil && s.Ref != ""; depth++ {
+		if depth > len(doc.Components.Schemas) {
+			return nil, fmt.Errorf("circular reference %s", s.Ref)
+		}
+		resolved, ok := doc.Components.Schemas[refName(s.Ref, "schemas")]
+		if !ok {
+			return nil, fmt.Errorf("unresolved reference %s", s.Ref)
+		}
+		s = resolved
+	}
+	return s, nil
+}
+
+// auth maps the security scheme the document requires, or else its first
+// one, to the node auth
+func (doc *openAPIDocument) auth(nodeType string) Auth {
+	var name string
+	for _, requirement := range doc.Security {
+		for scheme := range requirement {
+			name = scheme
+			break
+		}
+		if name != "" {
+			break
+		}
+	}
+	if name == "" {
+		schemes := make([]string, 0, len(doc.Components.SecuritySchemes))
+		for scheme := range doc.Components.SecuritySchemes {
+			schemes = append(schemes, scheme)
+		}
+		sort.Strings(schemes)
+		if len(schemes) == 0 {
+			return Auth{Type: AuthNone}
+		}
+		name = schemes[0]
+	}
+
+	credential := nodeType + "_api"
+	scheme := doc.Components.SecuritySchemes[name]
+	switch {
+	case scheme.Type == "http" && strings.EqualFold(scheme.Scheme, "basic"):
+		return Auth{Type: AuthBasic, Credential: credential}
+	case scheme.Type == "http", scheme.Type == "oauth2", scheme.Type == "openIdConnect":
+		return Auth{Type: AuthBearer, Credential: credential}
+	case scheme.Type == "apiKey" && scheme.In == "header":
+		return Auth{Type: AuthAPIKey, Credential: credential, HeaderName: scheme.Name}
+	case scheme.Type == "apiKey" && scheme.In == "query":
+		return Auth{Type: AuthAPIKey, Credential: credential, QueryName: scheme.Name}
+	}
+	return Auth{Type: AuthNone}
+}
+
+// parameterType maps a JSON schema type, a name or a list of names in
+// OpenAPI 3.1, to a node property type
+func parameterType(t interface{}) string {
+	types, ok := t.([]interface{})
+	if !ok {
+		types = []interface{}{t}
+	}
+	for _, t := range types {
+		switch t {
+		case "integer", "number":
+			return "number"
+		case "boolean":
+			return "boolean"
+		case "object", "array":
+			return "json"
+		case "string":
+			return "string"
+		}
+	}
+	return "string"
+}
+
+// refName returns the component a local reference such as
+// "#/components/schemas/User" points to
+func refName(ref, kind string) string {
+	name, ok := strings.CutPrefix(ref, "#/components/"+kind+"/")
+	if !ok {
+		return ""
+	}
+	return name
+}
+
+// identifier turns an operationId, path or value name into a snake case
+// name usable in placeholders
+func identifier(s string) string {
+	s = camelBoundary.ReplaceAllString(s, "${1}_${2}")
+	return strings.Trim(strings.ToLower(nonIdentifier.ReplaceAllString(s, "_")), "_")
+}
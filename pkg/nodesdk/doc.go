@@ -0,0 +1,7 @@
+// Package nodesdk is the toolkit for writing workflow nodes: a base
+// implementation of node.NodeInterface, typed parameter and credential
+// accessors, item helpers, and an HTTP client that honours the proxy,
+// rate-limit and circuit-breaker settings of the instance.
+//
+// New nodes can be generated with `cli node scaffold <name>`.
+package nodesdk
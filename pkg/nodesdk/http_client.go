@@ -0,0 +1,189 @@
+package nodesdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/jaydeep/go-n8n/configs"
+	"github.com/jaydeep/go-n8n/pkg/correlation"
+)
+
+// ErrRateLimited is returned when a remote API keeps answering 429 or asks
+// to retry later than the configured maximum
+var ErrRateLimited = errors.New("rate limited by remote service")
+
+// RateLimitError carries how long the remote service asked us to wait
+type RateLimitError struct {
+	Host       string
+	RetryAfter time.Duration
+}
+
+func (e *RateLimitError) Error() string {
+	return fmt.Sprintf("%s: %s asked to retry after %s", ErrRateLimited, e.Host, e.RetryAfter)
+}
+
+func (e *RateLimitError) Unwrap() error {
+	return ErrRateLimited
+}
+
+// HTTPClient is the HTTP helper nodes use for outbound API calls. Every
+// request goes through the circuit breaker and the rate limiter, and 429
+// responses are retried after the delay given by Retry-After. The
+// correlation ID of the execution is sent in the correlation header.
+type HTTPClient struct {
+	client  *http.Client
+	limiter *RateLimiter
+	breaker *CircuitBreaker
+	cfg     configs.OutboundRateLimitConfig
+	// correlationHeader carries the correlation ID, empty to send none
+	correlationHeader string
+}
+
+// NewHTTPClient creates a new node HTTP helper
+func NewHTTPClient(client *http.Client, cfg configs.OutboundRateLimitConfig, breaker configs.CircuitBreakerConfig) *HTTPClient {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &HTTPClient{
+		client:            client,
+		limiter:           NewRateLimiter(cfg),
+		breaker:           NewCircuitBreaker(breaker),
+		cfg:               cfg,
+		correlationHeader: correlation.DefaultHeader,
+	}
+}
+
+// NewHTTPClientFromConfig creates the HTTP helper from the node settings:
+// requests go through the configured proxy (or the HTTP(S)_PROXY
+// environment), time out after the node timeout, and are rate limited and
+// circuit broken per credential and host. The correlation ID is sent in
+// the configured correlation header.
+func NewHTTPClientFromConfig(cfg configs.NodeConfig) (*HTTPClient, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if cfg.Proxy != "" {
+		proxyURL, err := url.Parse(cfg.Proxy)
+		if err != nil {
+			return nil, fmt.Errorf("invalid node proxy: %w", err)
+		}
+		transport.Proxy = http.ProxyURL(proxyURL)
+	}
+
+	client := &http.Client{
+		Transport: transport,
+		Timeout:   cfg.Timeout,
+	}
+	c := NewHTTPClient(client, cfg.RateLimit, cfg.CircuitBreaker)
+	c.correlationHeader = cfg.CorrelationHeader
+	return c, nil
+}
+
+// Do sends req on behalf of the given credential (empty for none). The
+// request body must be replayable (GetBody set, as http.NewRequest does
+// for common body types) for 429 responses to be retried. The correlation
+// ID carried by ctx is added unless req sets the header itself.
+func (c *HTTPClient) Do(ctx context.Context, credentialID string, req *http.Request) (*http.Response, error) {
+	host := req.URL.Hostname()
+	key := LimitKey(credentialID, host)
+	id := correlation.FromContext(ctx)
+	propagate := c.correlationHeader != "" && id != "" && req.Header.Get(c.correlationHeader) == ""
+
+	for attempt := 0; ; attempt++ {
+		if err := c.breaker.Allow(key); err != nil {
+			return nil, err
+		}
+		if err := c.limiter.Wait(ctx, key, host); err != nil {
+			return nil, err
+		}
+
+		attemptReq := req.Clone(ctx)
+		if propagate {
+			attemptReq.Header.Set(c.correlationHeader, id)
+		}
+		if attempt > 0 && req.Body != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			attemptReq.Body = body
+		}
+
+		resp, err := c.client.Do(attemptReq)
+		if err != nil {
+			// Cancellation by the caller says nothing about the remote service
+			if ctx.Err() == nil {
+				c.breaker.Failure(key)
+			}
+			return nil, err
+		}
+		if resp.StatusCode >= http.StatusInternalServerError {
+			c.breaker.Failure(key)
+			return resp, nil
+		}
+		c.breaker.Success(key)
+		if resp.StatusCode != http.StatusTooManyRequests {
+			return resp, nil
+		}
+
+		wait := retryAfter(resp.Header.Get("Retry-After"), attempt)
+		c.limiter.Pause(key, wait)
+		drain(resp)
+
+		retryable := req.Body == nil || req.GetBody != nil
+		if !retryable || attempt >= c.cfg.MaxRetries || (c.cfg.MaxRetryAfter > 0 && wait > c.cfg.MaxRetryAfter) {
+			return nil, &RateLimitError{Host: host, RetryAfter: wait}
+		}
+	}
+}
+
+// retryAfter parses a Retry-After header (seconds or HTTP date), falling
+// back to exponential backoff when it is missing
+func retryAfter(header string, attempt int) time.Duration {
+	if header != "" {
+		if seconds, err := strconv.Atoi(header); err == nil && seconds >= 0 {
+			return time.Duration(seconds) * time.Second
+		}
+		if at, err := http.ParseTime(header); err == nil {
+			if d := time.Until(at); d > 0 {
+				return d
+			}
+			return 0
+		}
+	}
+	return time.Second << uint(attempt)
+}
+
+func drain(resp *http.Response) {
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	resp.Body.Close()
+}
+
+type httpClientKey struct{}
+
+var (
+	defaultHTTPClient     *HTTPClient
+	defaultHTTPClientOnce sync.Once
+)
+
+// WithHTTPClient returns a context carrying the HTTP helper for nodes
+func WithHTTPClient(ctx context.Context, client *HTTPClient) context.Context {
+	return context.WithValue(ctx, httpClientKey{}, client)
+}
+
+// HTTPClientFrom returns the HTTP helper from ctx, or a shared default one
+// without rate limits or circuit breaking
+func HTTPClientFrom(ctx context.Context) *HTTPClient {
+	if client, ok := ctx.Value(httpClientKey{}).(*HTTPClient); ok && client != nil {
+		return client
+	}
+	defaultHTTPClientOnce.Do(func() {
+		defaultHTTPClient = NewHTTPClient(nil, configs.OutboundRateLimitConfig{MaxRetries: 3}, configs.CircuitBreakerConfig{})
+	})
+	return defaultHTTPClient
+}
@@ -0,0 +1,57 @@
+package nodesdk
+
+import (
+	"io/fs"
+
+	"github.com/jaydeep/go-n8n/pkg/i18n"
+)
+
+// TranslationKey returns the bundle key of a node schema text, e.g.
+// TranslationKey("slack", "property.channel.display_name")
+func TranslationKey(nodeType, path string) string {
+	return "node." + nodeType + "." + path
+}
+
+// RegisterTranslations loads the <lang>.json files in dir of a node
+// package. Keys are relative to the "node." namespace, e.g.
+// "slack.name" or "slack.property.channel.description".
+func RegisterTranslations(bundle *i18n.Bundle, fsys fs.FS, dir string) error {
+	return bundle.LoadFS(fsys, dir, "node.")
+}
+
+// LocalizeSchema returns a copy of the schema with its display texts
+// translated; untranslated texts are kept
+func LocalizeSchema(schema *Schema, bundle *i18n.Bundle, lang string) *Schema {
+	if schema == nil || bundle == nil {
+		return schema
+	}
+
+	t := func(path, def string) string {
+		return bundle.T(lang, TranslationKey(schema.Type, path), def)
+	}
+
+	localized := *schema
+	localized.Name = t("name", schema.Name)
+	localized.Description = t("description", schema.Description)
+	localized.Defaults.Name = t("name", schema.Defaults.Name)
+
+	localized.Properties = make([]Property, len(schema.Properties))
+	for i, p := range schema.Properties {
+		prefix := "property." + p.Name + "."
+		p.DisplayName = t(prefix+"display_name", p.DisplayName)
+		p.Description = t(prefix+"description", p.Description)
+		p.Hint = t(prefix+"hint", p.Hint)
+
+		if len(p.Options) > 0 {
+			options := make([]PropertyOption, len(p.Options))
+			for j, o := range p.Options {
+				o.Name = t(prefix+"option."+o.Value+".name", o.Name)
+				o.Description = t(prefix+"option."+o.Value+".description", o.Description)
+				options[j] = o
+			}
+			p.Options = options
+		}
+		localized.Properties[i] = p
+	}
+	return &localized
+}
@@ -0,0 +1,48 @@
+package nodesdk
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"strings"
+)
+
+// MaxIconSize bounds the size of node icons
+const MaxIconSize = 256 << 10
+
+// ErrUnsupportedIcon is returned for icons that are not SVG or PNG
+var ErrUnsupportedIcon = errors.New("node icon must be an .svg or .png file")
+
+// LoadIcon reads an SVG or PNG icon, e.g. from an embed.FS of the node
+// package or os.DirFS of a custom node directory
+func LoadIcon(fsys fs.FS, name string) (Icon, error) {
+	var contentType string
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".svg":
+		contentType = "image/svg+xml"
+	case ".png":
+		contentType = "image/png"
+	default:
+		return Icon{}, ErrUnsupportedIcon
+	}
+
+	data, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return Icon{}, err
+	}
+	if len(data) > MaxIconSize {
+		return Icon{}, fmt.Errorf("node icon %s exceeds %d bytes", name, MaxIconSize)
+	}
+	return Icon{Data: data, ContentType: contentType}, nil
+}
+
+// RegisterIcon loads an icon and registers it for the node type
+func RegisterIcon(r *Registry, nodeType string, fsys fs.FS, name string) error {
+	icon, err := LoadIcon(fsys, name)
+	if err != nil {
+		return err
+	}
+	r.RegisterIcon(nodeType, icon)
+	return nil
+}
@@ -0,0 +1,164 @@
+// Package nodetest runs nodes against fixture inputs and compares their
+// output with golden files, so nodes can be tested without a running
+// instance.
+//
+// A fixture directory holds, per case:
+//
+//	<case>.input.json   the nodesdk.Input (data, parameters, credentials)
+//	<case>.golden.json  the expected output
+//	<case>.http.json    optional recorded HTTP interactions
+//
+// Run the tests with -nodetest.update to (re)write golden files and
+// -nodetest.record to record HTTP interactions against the real services.
+package nodetest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/jaydeep/go-n8n/configs"
+	"github.com/jaydeep/go-n8n/pkg/nodesdk"
+)
+
+var (
+	update = flag.Bool("nodetest.update", false, "rewrite golden files with the actual output")
+	record = flag.Bool("nodetest.record", false, "record HTTP interactions against the real services")
+)
+
+const (
+	inputSuffix  = ".input.json"
+	goldenSuffix = ".golden.json"
+	httpSuffix   = ".http.json"
+)
+
+// Result is the comparable form of a node output stored in golden files
+type Result struct {
+	Data  []nodesdk.Item `json:"data"`
+	Error string         `json:"error,omitempty"`
+}
+
+// Run executes the node for every fixture in dir as a subtest
+func Run(t *testing.T, n nodesdk.Node, dir string) {
+	t.Helper()
+
+	inputs, err := filepath.Glob(filepath.Join(dir, "*"+inputSuffix))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(inputs) == 0 {
+		t.Fatalf("no %s fixtures in %s", inputSuffix, dir)
+	}
+	sort.Strings(inputs)
+
+	for _, input := range inputs {
+		base := strings.TrimSuffix(input, inputSuffix)
+		t.Run(filepath.Base(base), func(t *testing.T) {
+			RunCase(t, n, base)
+		})
+	}
+}
+
+// RunCase executes the node for the fixture at base (path without suffix)
+func RunCase(t *testing.T, n nodesdk.Node, base string) {
+	t.Helper()
+
+	input, err := LoadInput(base + inputSuffix)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	recorder, err := NewRecorder(base+httpSuffix, *record)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client := nodesdk.NewHTTPClient(&http.Client{Transport: recorder}, configs.OutboundRateLimitConfig{}, configs.CircuitBreakerConfig{})
+	ctx := nodesdk.WithHTTPClient(context.Background(), client)
+
+	output, execErr := n.Execute(ctx, input)
+
+	if err := recorder.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	actual, err := Marshal(output, execErr)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	golden := base + goldenSuffix
+	if *update {
+		if err := os.WriteFile(golden, actual, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+
+	expected, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatalf("%v (run with -nodetest.update to create it)", err)
+	}
+	if !bytes.Equal(normalize(t, expected), normalize(t, actual)) {
+		t.Errorf("output differs from %s\n--- expected\n%s\n--- actual\n%s", golden, expected, actual)
+	}
+}
+
+// LoadInput reads a fixture input
+func LoadInput(path string) (*nodesdk.Input, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var input nodesdk.Input
+	if err := json.Unmarshal(data, &input); err != nil {
+		return nil, err
+	}
+	if input.Parameters == nil {
+		input.Parameters = map[string]interface{}{}
+	}
+	if input.Context == nil {
+		input.Context = &nodesdk.ExecutionContext{Mode: "test"}
+	}
+	return &input, nil
+}
+
+// Marshal renders an output and execution error as golden file content
+func Marshal(output *nodesdk.Output, err error) ([]byte, error) {
+	result := Result{Data: []nodesdk.Item{}}
+	if output != nil && output.Data != nil {
+		result.Data = output.Data
+	}
+	if err != nil {
+		result.Error = err.Error()
+	}
+
+	data, marshalErr := json.MarshalIndent(result, "", "  ")
+	if marshalErr != nil {
+		return nil, marshalErr
+	}
+	return append(data, '\n'), nil
+}
+
+// normalize re-encodes JSON so formatting differences don't fail a case
+func normalize(t *testing.T, data []byte) []byte {
+	t.Helper()
+
+	var v interface{}
+	if err := json.Unmarshal(data, &v); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	out, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return out
+}
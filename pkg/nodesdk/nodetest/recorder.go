@@ -0,0 +1,177 @@
+package nodetest
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sync"
+)
+
+// Interaction is a recorded HTTP request and its response
+type Interaction struct {
+	Request  RecordedRequest  `json:"request"`
+	Response RecordedResponse `json:"response"`
+}
+
+// RecordedRequest identifies a request; replay matches method, URL and body
+type RecordedRequest struct {
+	Method string `json:"method"`
+	URL    string `json:"url"`
+	Body   string `json:"body,omitempty"`
+}
+
+// RecordedResponse is replayed for a matching request
+type RecordedResponse struct {
+	Status  int               `json:"status"`
+	Headers map[string]string `json:"headers,omitempty"`
+	Body    string            `json:"body"`
+}
+
+// Recorder is an http.RoundTripper that replays recorded interactions, or
+// in record mode forwards requests to the real service and records them.
+// Credentials are never recorded: request headers are not stored.
+type Recorder struct {
+	path      string
+	recording bool
+	transport http.RoundTripper
+
+	mu           sync.Mutex
+	interactions []Interaction
+	used         []bool
+}
+
+// NewRecorder loads the interactions stored at path. A missing file means
+// no HTTP calls are expected, unless recording.
+func NewRecorder(path string, recording bool) (*Recorder, error) {
+	r := &Recorder{
+		path:      path,
+		recording: recording,
+		transport: http.DefaultTransport,
+	}
+	if recording {
+		return r, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return r, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &r.interactions); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	r.used = make([]bool, len(r.interactions))
+	return r, nil
+}
+
+// Interactions returns the recorded interactions
+func (r *Recorder) Interactions() []Interaction {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Interaction(nil), r.interactions...)
+}
+
+// RoundTrip implements http.RoundTripper
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		req.Body = io.NopCloser(bytes.NewReader(body))
+	}
+	recorded := RecordedRequest{Method: req.Method, URL: req.URL.String(), Body: string(body)}
+
+	if r.recording {
+		return r.forward(req, recorded)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, in := range r.interactions {
+		if r.used[i] || in.Request != recorded {
+			continue
+		}
+		r.used[i] = true
+		return in.Response.toHTTP(req), nil
+	}
+	return nil, fmt.Errorf("nodetest: no recorded response for %s %s (run with -nodetest.record)", req.Method, req.URL)
+}
+
+func (r *Recorder) forward(req *http.Request, recorded RecordedRequest) (*http.Response, error) {
+	resp, err := r.transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	headers := make(map[string]string)
+	for _, name := range []string{"Content-Type", "Link", "Retry-After"} {
+		if v := resp.Header.Get(name); v != "" {
+			headers[name] = v
+		}
+	}
+
+	in := Interaction{
+		Request:  recorded,
+		Response: RecordedResponse{Status: resp.StatusCode, Headers: headers, Body: string(body)},
+	}
+
+	r.mu.Lock()
+	r.interactions = append(r.interactions, in)
+	r.used = append(r.used, true)
+	r.mu.Unlock()
+
+	return in.Response.toHTTP(req), nil
+}
+
+// Save writes the interactions when recording
+func (r *Recorder) Save() error {
+	if !r.recording {
+		return nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.interactions) == 0 {
+		return nil
+	}
+
+	data, err := json.MarshalIndent(r.interactions, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(r.path, append(data, '\n'), 0o644)
+}
+
+func (resp RecordedResponse) toHTTP(req *http.Request) *http.Response {
+	header := make(http.Header, len(resp.Headers))
+	for k, v := range resp.Headers {
+		header.Set(k, v)
+	}
+	return &http.Response{
+		StatusCode:    resp.Status,
+		Status:        fmt.Sprintf("%d %s", resp.Status, http.StatusText(resp.Status)),
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader([]byte(resp.Body))),
+		ContentLength: int64(len(resp.Body)),
+		Request:       req,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+	}
+}
@@ -0,0 +1,195 @@
+package nodesdk
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/jaydeep/go-n8n/internal/domain/node"
+)
+
+// Params gives typed access to node parameters (or credential data)
+type Params map[string]interface{}
+
+// String returns a string parameter or the default
+func (p Params) String(key, defaultValue string) string {
+	return GetString(p, key, defaultValue)
+}
+
+// Int returns an integer parameter or the default
+func (p Params) Int(key string, defaultValue int) int {
+	return GetInt(p, key, defaultValue)
+}
+
+// Float returns a number parameter or the default
+func (p Params) Float(key string, defaultValue float64) float64 {
+	return GetFloat(p, key, defaultValue)
+}
+
+// Bool returns a boolean parameter or the default
+func (p Params) Bool(key string, defaultValue bool) bool {
+	return GetBool(p, key, defaultValue)
+}
+
+// Duration returns a duration parameter given as a Go duration string
+// ("30s") or a number of seconds
+func (p Params) Duration(key string, defaultValue time.Duration) time.Duration {
+	return GetDuration(p, key, defaultValue)
+}
+
+// StringSlice returns a string list parameter
+func (p Params) StringSlice(key string) []string {
+	return GetStringSlice(p, key)
+}
+
+// Map returns an object parameter
+func (p Params) Map(key string) map[string]interface{} {
+	return GetMap(p, key)
+}
+
+// ItemParams returns the parameters of a node with their expressions
+// evaluated for the input item at index. Nodes handling their items one by
+// one read them rather than input.Parameters, which holds the parameters
+// evaluated for the first item.
+func ItemParams(input *node.NodeInput, index int) (Params, error) {
+	if input.ItemParameters == nil {
+		return Params(input.Parameters), nil
+	}
+	params, err := input.ItemParameters(index)
+	if err != nil {
+		return nil, err
+	}
+	return Params(params), nil
+}
+
+// Has reports whether the parameter is set
+func (p Params) Has(key string) bool {
+	_, ok := p[key]
+	return ok
+}
+
+// Require returns an error naming the first missing parameter
+func (p Params) Require(keys ...string) error {
+	return ValidateRequired(p, keys)
+}
+
+// ValidateRequired validates required parameters
+func ValidateRequired(parameters map[string]interface{}, required []string) error {
+	for _, key := range required {
+		if _, exists := parameters[key]; !exists {
+			return fmt.Errorf("required parameter '%s' is missing", key)
+		}
+	}
+	return nil
+}
+
+// GetString gets a string parameter with default value
+func GetString(parameters map[string]interface{}, key string, defaultValue string) string {
+	if val, exists := parameters[key]; exists {
+		if str, ok := val.(string); ok {
+			return str
+		}
+	}
+	return defaultValue
+}
+
+// GetInt gets an integer parameter with default value
+func GetInt(parameters map[string]interface{}, key string, defaultValue int) int {
+	if val, exists := parameters[key]; exists {
+		switch v := val.(type) {
+		case int:
+			return v
+		case int64:
+			return int(v)
+		case float64:
+			return int(v)
+		case float32:
+			return int(v)
+		case string:
+			if i, err := strconv.Atoi(v); err == nil {
+				return i
+			}
+		}
+	}
+	return defaultValue
+}
+
+// GetFloat gets a number parameter with default value
+func GetFloat(parameters map[string]interface{}, key string, defaultValue float64) float64 {
+	if val, exists := parameters[key]; exists {
+		switch v := val.(type) {
+		case float64:
+			return v
+		case float32:
+			return float64(v)
+		case int:
+			return float64(v)
+		case int64:
+			return float64(v)
+		case string:
+			if f, err := strconv.ParseFloat(v, 64); err == nil {
+				return f
+			}
+		}
+	}
+	return defaultValue
+}
+
+// GetBool gets a boolean parameter with default value
+func GetBool(parameters map[string]interface{}, key string, defaultValue bool) bool {
+	if val, exists := parameters[key]; exists {
+		switch v := val.(type) {
+		case bool:
+			return v
+		case string:
+			if b, err := strconv.ParseBool(v); err == nil {
+				return b
+			}
+		}
+	}
+	return defaultValue
+}
+
+// GetDuration gets a duration parameter with default value
+func GetDuration(parameters map[string]interface{}, key string, defaultValue time.Duration) time.Duration {
+	if val, exists := parameters[key]; exists {
+		switch v := val.(type) {
+		case string:
+			if d, err := time.ParseDuration(v); err == nil {
+				return d
+			}
+		case int, int64, float32, float64:
+			return time.Duration(GetFloat(parameters, key, 0) * float64(time.Second))
+		}
+	}
+	return defaultValue
+}
+
+// GetStringSlice gets a string slice parameter
+func GetStringSlice(parameters map[string]interface{}, key string) []string {
+	if val, exists := parameters[key]; exists {
+		switch v := val.(type) {
+		case []string:
+			return v
+		case []interface{}:
+			result := make([]string, len(v))
+			for i, item := range v {
+				if str, ok := item.(string); ok {
+					result[i] = str
+				}
+			}
+			return result
+		}
+	}
+	return []string{}
+}
+
+// GetMap gets a map parameter
+func GetMap(parameters map[string]interface{}, key string) map[string]interface{} {
+	if val, exists := parameters[key]; exists {
+		if m, ok := val.(map[string]interface{}); ok {
+			return m
+		}
+	}
+	return make(map[string]interface{})
+}
@@ -0,0 +1,113 @@
+package nodesdk
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jaydeep/go-n8n/configs"
+	"golang.org/x/time/rate"
+)
+
+// RateLimiter schedules outbound requests with a token bucket per
+// credential and host, so many items hitting the same API are spread out
+// instead of getting the user's account throttled. Limits apply per worker
+// process.
+type RateLimiter struct {
+	cfg      configs.OutboundRateLimitConfig
+	mu       sync.Mutex
+	limiters map[string]*rate.Limiter
+	paused   map[string]time.Time
+}
+
+// NewRateLimiter creates a new outbound rate limiter
+func NewRateLimiter(cfg configs.OutboundRateLimitConfig) *RateLimiter {
+	return &RateLimiter{
+		cfg:      cfg,
+		limiters: make(map[string]*rate.Limiter),
+		paused:   make(map[string]time.Time),
+	}
+}
+
+// LimitKey builds the limiter key for requests to host made with the given
+// credential. Requests without a credential are limited by host only.
+func LimitKey(credentialID, host string) string {
+	host = strings.ToLower(host)
+	if credentialID == "" {
+		return host
+	}
+	return credentialID + "@" + host
+}
+
+// Wait blocks until a request for key to host may be sent, honouring any
+// pause set by Pause
+func (l *RateLimiter) Wait(ctx context.Context, key, host string) error {
+	limiter, until := l.get(key, host)
+
+	if delay := time.Until(until); delay > 0 {
+		timer := time.NewTimer(delay)
+		defer timer.Stop()
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+
+	return limiter.Wait(ctx)
+}
+
+// Pause holds back all requests for key for d, e.g. after a 429 response
+func (l *RateLimiter) Pause(key string, d time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	until := time.Now().Add(d)
+	if until.After(l.paused[key]) {
+		l.paused[key] = until
+	}
+}
+
+func (l *RateLimiter) get(key, host string) (*rate.Limiter, time.Time) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	limiter, ok := l.limiters[key]
+	if !ok {
+		rps, burst := l.limitFor(host)
+		limiter = rate.NewLimiter(rps, burst)
+		l.limiters[key] = limiter
+	}
+
+	until := l.paused[key]
+	if !until.IsZero() && time.Now().After(until) {
+		delete(l.paused, key)
+	}
+	return limiter, until
+}
+
+// limitFor returns the configured limit for host, falling back to the default
+func (l *RateLimiter) limitFor(host string) (rate.Limit, int) {
+	host = strings.ToLower(host)
+	for _, h := range l.cfg.Hosts {
+		if strings.EqualFold(h.Host, host) || strings.HasSuffix(host, "."+strings.ToLower(h.Host)) {
+			return limit(h.RequestsPerSecond), burst(h.Burst)
+		}
+	}
+	return limit(l.cfg.RequestsPerSecond), burst(l.cfg.Burst)
+}
+
+func limit(rps float64) rate.Limit {
+	if rps <= 0 {
+		return rate.Inf
+	}
+	return rate.Limit(rps)
+}
+
+func burst(b int) int {
+	if b <= 0 {
+		return 1
+	}
+	return b
+}
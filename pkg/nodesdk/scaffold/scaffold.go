@@ -0,0 +1,172 @@
+// Package scaffold generates the skeleton of a new node package.
+package scaffold
+
+import (
+	"bytes"
+	"embed"
+	"errors"
+	"fmt"
+	"go/format"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"text/template"
+	"unicode"
+)
+
+//go:embed templates/*.tmpl
+var templates embed.FS
+
+var namePattern = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_\- ]*$`)
+
+// Options configures the generated node
+type Options struct {
+	// Name is the human readable or kebab/snake case node name, e.g. "acme-crm"
+	Name string
+	// Dir is the parent directory; the node gets its own package below it
+	Dir string
+	// Category is one of the nodesdk categories, "action" by default
+	Category string
+	// Force overwrites existing files
+	Force bool
+}
+
+// data is passed to the templates
+type data struct {
+	Package     string
+	TypeName    string
+	NodeType    string
+	DisplayName string
+	Category    string
+}
+
+// Generate writes the node source, its test and golden fixtures into
+// Dir/<package> and returns the created file paths
+func Generate(opts Options) ([]string, error) {
+	if !namePattern.MatchString(opts.Name) {
+		return nil, fmt.Errorf("invalid node name %q: use letters, digits, '-', '_' or spaces", opts.Name)
+	}
+	category, err := categoryConstant(opts.Category)
+	if err != nil {
+		return nil, err
+	}
+
+	words := splitWords(opts.Name)
+	d := data{
+		Package:     strings.ToLower(strings.Join(words, "")),
+		TypeName:    pascal(words) + "Node",
+		NodeType:    strings.ToLower(strings.Join(words, "_")),
+		DisplayName: title(words),
+		Category:    category,
+	}
+
+	dir := filepath.Join(opts.Dir, d.Package)
+	files := []struct {
+		template string
+		path     string
+	}{
+		{"node.go.tmpl", d.NodeType + "_node.go"},
+		{"node_test.go.tmpl", d.NodeType + "_node_test.go"},
+		{"basic.input.json.tmpl", filepath.Join("testdata", "basic.input.json")},
+		{"basic.golden.json.tmpl", filepath.Join("testdata", "basic.golden.json")},
+	}
+
+	var created []string
+	for _, f := range files {
+		path := filepath.Join(dir, f.path)
+		if !opts.Force {
+			if _, err := os.Stat(path); err == nil {
+				return created, fmt.Errorf("%s already exists (use -force to overwrite)", path)
+			}
+		}
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			return created, err
+		}
+
+		src, err := render(f.template, d)
+		if err != nil {
+			return created, err
+		}
+		if err := os.WriteFile(path, src, 0o644); err != nil {
+			return created, err
+		}
+		created = append(created, path)
+	}
+	return created, nil
+}
+
+func render(name string, d data) ([]byte, error) {
+	tmpl, err := template.ParseFS(templates, "templates/"+name)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, d); err != nil {
+		return nil, err
+	}
+	if !strings.HasSuffix(name, ".go.tmpl") {
+		return buf.Bytes(), nil
+	}
+	return format.Source(buf.Bytes())
+}
+
+func categoryConstant(category string) (string, error) {
+	switch strings.ToLower(category) {
+	case "", "action":
+		return "CategoryAction", nil
+	case "trigger":
+		return "CategoryTrigger", nil
+	case "transform":
+		return "CategoryTransform", nil
+	case "flow":
+		return "CategoryFlow", nil
+	case "integration":
+		return "CategoryIntegration", nil
+	case "utility":
+		return "CategoryUtility", nil
+	}
+	return "", errors.New("unknown category " + category)
+}
+
+// splitWords splits "acme-crm", "acme_crm", "Acme CRM" and "AcmeCrm" into words
+func splitWords(name string) []string {
+	var words []string
+	var current []rune
+	runes := []rune(name)
+	for i, r := range runes {
+		switch {
+		case r == '-' || r == '_' || r == ' ':
+			if len(current) > 0 {
+				words = append(words, string(current))
+				current = nil
+			}
+			continue
+		case unicode.IsUpper(r) && len(current) > 0 && i > 0 && unicode.IsLower(runes[i-1]):
+			words = append(words, string(current))
+			current = nil
+		}
+		current = append(current, r)
+	}
+	if len(current) > 0 {
+		words = append(words, string(current))
+	}
+	return words
+}
+
+func pascal(words []string) string {
+	var b strings.Builder
+	for _, w := range words {
+		b.WriteString(strings.ToUpper(w[:1]) + strings.ToLower(w[1:]))
+	}
+	return b.String()
+}
+
+func title(words []string) string {
+	titled := make([]string, len(words))
+	for i, w := range words {
+		titled[i] = strings.ToUpper(w[:1]) + strings.ToLower(w[1:])
+	}
+	return strings.Join(titled, " ")
+}
@@ -0,0 +1,49 @@
+package nodesdk
+
+import "github.com/jaydeep/go-n8n/internal/domain/node"
+
+// Aliases of the node domain types, so node packages only need nodesdk
+type (
+	Node             = node.NodeInterface
+	Input            = node.NodeInput
+	Output           = node.NodeOutput
+	Item             = node.Item
+	ItemEditor       = node.ItemEditor
+	Binary           = node.Binary
+	ExecutionContext = node.ExecutionContext
+	Schema           = node.NodeSchema
+	Defaults         = node.NodeDefaults
+	IOSchema         = node.IOSchema
+	Property         = node.PropertySchema
+	PropertyType     = node.PropertyType
+	PropertyOption   = node.PropertyOption
+	DisplayOptions   = node.DisplayOptions
+	CredentialSchema = node.CredentialSchema
+	Category         = node.Category
+	Registry         = node.NodeRegistry
+	Icon             = node.Icon
+)
+
+// Node categories
+const (
+	CategoryTrigger     = node.CategoryTrigger
+	CategoryAction      = node.CategoryAction
+	CategoryTransform   = node.CategoryTransform
+	CategoryFlow        = node.CategoryFlow
+	CategoryIntegration = node.CategoryIntegration
+	CategoryUtility     = node.CategoryUtility
+)
+
+// Property types
+const (
+	PropertyString      = node.PropertyTypeString
+	PropertyNumber      = node.PropertyTypeNumber
+	PropertyBoolean     = node.PropertyTypeBoolean
+	PropertyOptions     = node.PropertyTypeOptions
+	PropertyMultiOption = node.PropertyTypeMultiOptions
+	PropertyJSON        = node.PropertyTypeJSON
+	PropertyCode        = node.PropertyTypeCode
+	PropertyDateTime    = node.PropertyTypeDateTime
+	PropertyCollection  = node.PropertyTypeCollection
+	PropertyHidden      = node.PropertyTypeHidden
+)
@@ -0,0 +1,84 @@
+// Package pagination implements opaque keyset cursors for lists ordered
+// newest first by a timestamp and ID.
+package pagination
+
+import (
+	"encoding/base64"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// DefaultLimit is the page size when none is requested
+	DefaultLimit = 50
+	// MaxLimit caps the requested page size
+	MaxLimit = 500
+)
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Cursor points after the last row of a page. The next page continues
+// with rows older than Time, or as old with a smaller ID.
+type Cursor struct {
+	Time time.Time
+	ID   uuid.UUID
+}
+
+// After returns the cursor following a row
+func After(t time.Time, id uuid.UUID) *Cursor {
+	return &Cursor{Time: t, ID: id}
+}
+
+// Encode returns the opaque form handed to clients
+func (c *Cursor) Encode() string {
+	raw := c.Time.UTC().Format(time.RFC3339Nano) + "|" + c.ID.String()
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// Decode parses a cursor produced by Encode. An empty string is no cursor.
+func Decode(s string) (*Cursor, error) {
+	if s == "" {
+		return nil, nil
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	ts, id, ok := strings.Cut(string(raw), "|")
+	if !ok {
+		return nil, ErrInvalidCursor
+	}
+	t, err := time.Parse(time.RFC3339Nano, ts)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	parsed, err := uuid.Parse(id)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	return &Cursor{Time: t, ID: parsed}, nil
+}
+
+// Limit clamps a requested page size to 1..MaxLimit
+func Limit(requested int) int {
+	if requested <= 0 {
+		return DefaultLimit
+	}
+	if requested > MaxLimit {
+		return MaxLimit
+	}
+	return requested
+}
+
+// Page trims rows fetched with a limit of limit+1 to limit and returns the
+// cursor of the next page, or nil when rows were the last page
+func Page[T any](rows []T, limit int, cursor func(T) *Cursor) ([]T, *Cursor) {
+	if len(rows) <= limit {
+		return rows, nil
+	}
+	rows = rows[:limit]
+	return rows, cursor(rows[limit-1])
+}
@@ -0,0 +1,443 @@
+// Package pool shares long-lived clients, such as database drivers, Kafka
+// producers, SMTP connections and HTTP transports, between node executions
+// so nodes don't open a new connection per item. Clients are keyed by the
+// credential they were opened with, closed after sitting idle and dropped
+// when their health check fails.
+package pool
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/jaydeep/go-n8n/configs"
+)
+
+const (
+	defaultIdleTimeout         = 5 * time.Minute
+	defaultHealthCheckInterval = 30 * time.Second
+	defaultHealthCheckTimeout  = 5 * time.Second
+)
+
+var (
+	// ErrPoolClosed is returned by Get after the pool has been closed
+	ErrPoolClosed = errors.New("connection pool closed")
+	// ErrPoolFull is returned by Get when MaxClients clients are open and
+	// all of them are in use
+	ErrPoolFull = errors.New("connection pool full")
+)
+
+// Opener opens a client. Clients are shared by concurrent leases, so they
+// must be safe for concurrent use.
+type Opener func(ctx context.Context) (interface{}, error)
+
+// Pinger is implemented by clients that can check their connection
+type Pinger interface {
+	Ping(ctx context.Context) error
+}
+
+// contextPinger is implemented by *sql.DB
+type contextPinger interface {
+	PingContext(ctx context.Context) error
+}
+
+// idleCloser is implemented by *http.Client and *http.Transport
+type idleCloser interface {
+	CloseIdleConnections()
+}
+
+// Key identifies a pooled client by the kind of client and the credential
+// it was opened with. The fingerprint changes when the credential data
+// does, so updated credentials open a new client.
+type Key struct {
+	Kind         string
+	CredentialID string
+	Fingerprint  string
+}
+
+// NewKey returns the key of a client of kind opened with the credential
+// data creds
+func NewKey(kind string, creds map[string]interface{}) Key {
+	key := Key{Kind: kind}
+	if id, ok := creds["id"].(string); ok {
+		key.CredentialID = id
+	}
+	// Map keys are marshalled in sorted order, so equal data hashes equally
+	data, _ := json.Marshal(creds)
+	sum := sha256.Sum256(data)
+	key.Fingerprint = hex.EncodeToString(sum[:8])
+	return key
+}
+
+// entry is a pooled client and its lease count
+type entry struct {
+	key    Key
+	client interface{}
+	err    error
+	// ready is closed once the client is opened or failed to open
+	ready    chan struct{}
+	refs     int
+	openedAt time.Time
+	lastUsed time.Time
+	// retired entries are no longer handed out and are closed once their
+	// last lease is released
+	retired bool
+}
+
+// Stats is a snapshot of the pool's clients and counters
+type Stats struct {
+	Clients      []ClientStats `json:"clients"`
+	Open         int           `json:"open"`
+	InUse        int           `json:"in_use"`
+	Hits         int64         `json:"hits"`
+	Misses       int64         `json:"misses"`
+	OpenFailures int64         `json:"open_failures"`
+	Evicted      int64         `json:"evicted"`
+	Unhealthy    int64         `json:"unhealthy"`
+	Closed       int64         `json:"closed"`
+}
+
+// ClientStats describes a single pooled client
+type ClientStats struct {
+	Kind         string    `json:"kind"`
+	CredentialID string    `json:"credential_id,omitempty"`
+	Leases       int       `json:"leases"`
+	OpenedAt     time.Time `json:"opened_at"`
+	LastUsed     time.Time `json:"last_used"`
+}
+
+// Pool shares clients by key
+type Pool struct {
+	cfg configs.PoolConfig
+
+	mu      sync.Mutex
+	entries map[Key]*entry
+	closed  bool
+	stats   Stats
+}
+
+// New creates a new client pool
+func New(cfg configs.PoolConfig) *Pool {
+	if cfg.IdleTimeout <= 0 {
+		cfg.IdleTimeout = defaultIdleTimeout
+	}
+	if cfg.HealthCheckInterval <= 0 {
+		cfg.HealthCheckInterval = defaultHealthCheckInterval
+	}
+	if cfg.HealthCheckTimeout <= 0 {
+		cfg.HealthCheckTimeout = defaultHealthCheckTimeout
+	}
+	return &Pool{
+		cfg:     cfg,
+		entries: make(map[Key]*entry),
+	}
+}
+
+// Get leases the client for key, opening it with open unless it is pooled
+// already. Concurrent callers for a missing key wait for a single open. The
+// lease must be released once the caller is done with the client.
+func (p *Pool) Get(ctx context.Context, key Key, open Opener) (*Lease, error) {
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		return nil, ErrPoolClosed
+	}
+
+	e, ok := p.entries[key]
+	if ok {
+		e.refs++
+		p.stats.Hits++
+		p.mu.Unlock()
+		return p.await(ctx, e)
+	}
+
+	var evicted *entry
+	if p.cfg.MaxClients > 0 && len(p.entries) >= p.cfg.MaxClients {
+		if evicted = p.leastRecentlyUsedIdle(); evicted == nil {
+			p.mu.Unlock()
+			return nil, ErrPoolFull
+		}
+		delete(p.entries, evicted.key)
+		evicted.retired = true
+		p.stats.Evicted++
+		p.stats.Closed++
+	}
+
+	e = &entry{key: key, ready: make(chan struct{}), refs: 1}
+	p.entries[key] = e
+	p.stats.Misses++
+	p.mu.Unlock()
+
+	if evicted != nil {
+		closeClient(evicted.client)
+	}
+
+	client, err := open(ctx)
+
+	p.mu.Lock()
+	e.client, e.err = client, err
+	e.openedAt = time.Now()
+	e.lastUsed = e.openedAt
+	if err != nil {
+		p.stats.OpenFailures++
+		if p.entries[key] == e {
+			delete(p.entries, key)
+		}
+		e.retired = true
+	}
+	close(e.ready)
+	p.mu.Unlock()
+
+	if err != nil {
+		p.release(e)
+		return nil, err
+	}
+	return &Lease{pool: p, entry: e}, nil
+}
+
+// await waits for a leased entry to be opened
+func (p *Pool) await(ctx context.Context, e *entry) (*Lease, error) {
+	select {
+	case <-e.ready:
+	case <-ctx.Done():
+		p.release(e)
+		return nil, ctx.Err()
+	}
+	if e.err != nil {
+		p.release(e)
+		return nil, e.err
+	}
+	return &Lease{pool: p, entry: e}, nil
+}
+
+// leastRecentlyUsedIdle returns the opened entry without leases that was
+// used longest ago
+func (p *Pool) leastRecentlyUsedIdle() *entry {
+	var lru *entry
+	for _, e := range p.entries {
+		if e.refs > 0 {
+			continue
+		}
+		if lru == nil || e.lastUsed.Before(lru.lastUsed) {
+			lru = e
+		}
+	}
+	return lru
+}
+
+// release drops a lease, closing a retired client after its last one
+func (p *Pool) release(e *entry) {
+	p.mu.Lock()
+	e.refs--
+	e.lastUsed = time.Now()
+	closeNow := e.retired && e.refs == 0 && e.err == nil
+	p.mu.Unlock()
+
+	if closeNow {
+		closeClient(e.client)
+	}
+}
+
+// retire stops handing out an entry, closing it now unless it is leased
+func (p *Pool) retire(e *entry) {
+	p.mu.Lock()
+	if e.retired {
+		p.mu.Unlock()
+		return
+	}
+	e.retired = true
+	if p.entries[e.key] == e {
+		delete(p.entries, e.key)
+	}
+	p.stats.Closed++
+	closeNow := e.refs == 0
+	p.mu.Unlock()
+
+	if closeNow {
+		closeClient(e.client)
+	}
+}
+
+// Start evicts idle clients and health checks the others until the context
+// is cancelled, then closes the pool
+func (p *Pool) Start(ctx context.Context) {
+	ticker := time.NewTicker(p.cfg.HealthCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			p.Close()
+			return
+		case <-ticker.C:
+			p.Sweep(ctx)
+		}
+	}
+}
+
+// Sweep closes clients idle for longer than IdleTimeout and drops those
+// failing their health check
+func (p *Pool) Sweep(ctx context.Context) {
+	now := time.Now()
+
+	var idle, check []*entry
+	p.mu.Lock()
+	for _, e := range p.entries {
+		select {
+		case <-e.ready:
+		default:
+			// Still opening
+			continue
+		}
+		if e.refs == 0 && now.Sub(e.lastUsed) > p.cfg.IdleTimeout {
+			idle = append(idle, e)
+		} else {
+			check = append(check, e)
+		}
+	}
+	p.stats.Evicted += int64(len(idle))
+	p.mu.Unlock()
+
+	for _, e := range idle {
+		p.retire(e)
+	}
+
+	for _, e := range check {
+		if ctx.Err() != nil {
+			return
+		}
+		if err := p.ping(ctx, e.client); err != nil {
+			p.mu.Lock()
+			p.stats.Unhealthy++
+			p.mu.Unlock()
+			p.retire(e)
+		}
+	}
+}
+
+func (p *Pool) ping(ctx context.Context, client interface{}) error {
+	ctx, cancel := context.WithTimeout(ctx, p.cfg.HealthCheckTimeout)
+	defer cancel()
+
+	switch c := client.(type) {
+	case Pinger:
+		return c.Ping(ctx)
+	case contextPinger:
+		return c.PingContext(ctx)
+	}
+	return nil
+}
+
+// Close closes the idle clients and the others once their leases are
+// released. Get fails afterwards.
+func (p *Pool) Close() {
+	p.mu.Lock()
+	p.closed = true
+	entries := make([]*entry, 0, len(p.entries))
+	for _, e := range p.entries {
+		entries = append(entries, e)
+	}
+	p.mu.Unlock()
+
+	for _, e := range entries {
+		p.retire(e)
+	}
+}
+
+// Stats returns a snapshot of the pool
+func (p *Pool) Stats() Stats {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	stats := p.stats
+	stats.Clients = make([]ClientStats, 0, len(p.entries))
+	for _, e := range p.entries {
+		stats.Clients = append(stats.Clients, ClientStats{
+			Kind:         e.key.Kind,
+			CredentialID: e.key.CredentialID,
+			Leases:       e.refs,
+			OpenedAt:     e.openedAt,
+			LastUsed:     e.lastUsed,
+		})
+		if e.refs > 0 {
+			stats.InUse++
+		}
+	}
+	stats.Open = len(stats.Clients)
+	sort.Slice(stats.Clients, func(i, j int) bool {
+		a, b := stats.Clients[i], stats.Clients[j]
+		if a.Kind != b.Kind {
+			return a.Kind < b.Kind
+		}
+		return a.CredentialID < b.CredentialID
+	})
+	return stats
+}
+
+// closeClient closes a client by whichever means it supports
+func closeClient(client interface{}) {
+	switch c := client.(type) {
+	case io.Closer:
+		c.Close()
+	case idleCloser:
+		c.CloseIdleConnections()
+	}
+}
+
+// Lease is a caller's hold on a pooled client
+type Lease struct {
+	pool  *Pool
+	entry *entry
+	once  sync.Once
+}
+
+// Client returns the leased client
+func (l *Lease) Client() interface{} {
+	return l.entry.client
+}
+
+// Release returns the client to the pool. Releasing twice has no effect.
+func (l *Lease) Release() {
+	l.once.Do(func() {
+		l.pool.release(l.entry)
+	})
+}
+
+// Discard releases the client and drops it from the pool, for callers that
+// found its connection broken. The next Get for its key opens a new one.
+func (l *Lease) Discard() {
+	l.once.Do(func() {
+		l.pool.retire(l.entry)
+		l.pool.release(l.entry)
+	})
+}
+
+type poolKey struct{}
+
+var (
+	defaultPool     *Pool
+	defaultPoolOnce sync.Once
+)
+
+// NewContext returns a context carrying the pool for nodes
+func NewContext(ctx context.Context, p *Pool) context.Context {
+	return context.WithValue(ctx, poolKey{}, p)
+}
+
+// FromContext returns the pool from ctx, or a shared default one
+func FromContext(ctx context.Context) *Pool {
+	if p, ok := ctx.Value(poolKey{}).(*Pool); ok && p != nil {
+		return p
+	}
+	defaultPoolOnce.Do(func() {
+		defaultPool = New(configs.PoolConfig{})
+		go defaultPool.Start(context.Background())
+	})
+	return defaultPool
+}
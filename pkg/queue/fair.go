@@ -0,0 +1,209 @@
+package queue
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// laneSeparator separates the name of a queue from the lanes its jobs
+	// wait on
+	laneSeparator = "#"
+	// fairPollInterval is how often Fair.Dequeue looks for jobs while no
+	// lane has any
+	fairPollInterval = 200 * time.Millisecond
+	// maxLaneWeight bounds the jobs taken from one lane in a row
+	maxLaneWeight = 100
+)
+
+// Weigher weighs the teams sharing a queue
+type Weigher interface {
+	// Weight returns how many jobs of the team are taken in a row before
+	// the next lane's turn, at least 1
+	Weight(ctx context.Context, team string) int
+}
+
+// rotation is where the round of the lanes of a queue stands
+type rotation struct {
+	lane   string
+	credit int
+}
+
+// Fair is a Queue taking the jobs of a queue from the lanes of the teams
+// and workflows they belong to in turn, rather than first in first out, so
+// that a burst of jobs of one workflow waits behind one job of every other
+// workflow instead of all of them. The jobs of a team share the team's
+// lane, which takes as many jobs in a row as the team weighs; other jobs
+// wait on the lane of their workflow. Jobs with neither, and jobs enqueued
+// before lanes existed, wait on the queue itself, which takes its turn
+// like a lane.
+//
+// Each consumer process keeps its own round, so the turns of lanes are
+// fair per process rather than exactly across processes.
+type Fair struct {
+	Queue
+	weigher Weigher
+
+	mu        sync.Mutex
+	rotations map[string]*rotation
+}
+
+// NewFair wraps q to take jobs from lanes in turn. weigher may be nil to
+// weigh every team 1.
+func NewFair(q Queue, weigher Weigher) *Fair {
+	return &Fair{Queue: q, weigher: weigher, rotations: make(map[string]*rotation)}
+}
+
+// Enqueue adds a job to the tail of its lane of its queue
+func (f *Fair) Enqueue(ctx context.Context, job *Job) error {
+	name := job.Queue
+	if lane := laneOf(job); lane != "" && name != "" && !strings.Contains(name, laneSeparator) {
+		job.Queue = name + laneSeparator + lane
+		defer func() { job.Queue = name }()
+	}
+	return f.Queue.Enqueue(ctx, job)
+}
+
+// Dequeue blocks up to timeout for the next job of the lane whose turn it
+// is among the lanes of the named queue with jobs waiting
+func (f *Fair) Dequeue(ctx context.Context, queue, consumer string, timeout time.Duration) (*Job, error) {
+	deadline := time.Now().Add(timeout)
+	for {
+		backlogs, err := f.backlogs(ctx, queue)
+		if err != nil {
+			return nil, err
+		}
+		for _, name := range f.turns(queue, backlogs) {
+			job, err := f.Queue.Dequeue(ctx, name, consumer, 0)
+			if errors.Is(err, ErrEmpty) {
+				// Taken by another consumer meanwhile
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+			f.served(ctx, queue, name)
+			job.Queue = queue
+			return job, nil
+		}
+
+		if err := wait(ctx, minDuration(fairPollInterval, time.Until(deadline))); !errors.Is(err, ErrEmpty) {
+			return nil, err
+		}
+		if !time.Now().Before(deadline) {
+			return nil, ErrEmpty
+		}
+	}
+}
+
+// Len returns the number of jobs waiting on all lanes of the named queue
+func (f *Fair) Len(ctx context.Context, queue string) (int64, error) {
+	backlogs, err := f.backlogs(ctx, queue)
+	if err != nil {
+		return 0, err
+	}
+	var waiting int64
+	for _, b := range backlogs {
+		waiting += b.Waiting
+	}
+	return waiting, nil
+}
+
+// Lag returns how long the oldest job waiting on a lane of the named queue
+// has been waiting
+func (f *Fair) Lag(ctx context.Context, queue string) (time.Duration, error) {
+	backlogs, err := f.backlogs(ctx, queue)
+	if err != nil {
+		return 0, err
+	}
+	var oldest time.Time
+	for _, b := range backlogs {
+		if oldest.IsZero() || (!b.Oldest.IsZero() && b.Oldest.Before(oldest)) {
+			oldest = b.Oldest
+		}
+	}
+	return lagSince(oldest), nil
+}
+
+// backlogs returns the backlogs of the queue itself and its lanes, leaving
+// out other queues sharing the prefix
+func (f *Fair) backlogs(ctx context.Context, queue string) ([]Backlog, error) {
+	all, err := f.Queue.Backlogs(ctx, queue)
+	if err != nil {
+		return nil, err
+	}
+	backlogs := all[:0]
+	for _, b := range all {
+		if b.Queue == queue || strings.HasPrefix(b.Queue, queue+laneSeparator) {
+			backlogs = append(backlogs, b)
+		}
+	}
+	return backlogs, nil
+}
+
+// turns returns the queues of the lanes with jobs waiting in the order of
+// their turns: the lane of the last job taken while it has credit left,
+// then the lanes after it in name order
+func (f *Fair) turns(queue string, backlogs []Backlog) []string {
+	names := make([]string, 0, len(backlogs))
+	for _, b := range backlogs {
+		names = append(names, b.Queue)
+	}
+	sort.Strings(names)
+
+	f.mu.Lock()
+	r := f.rotations[queue]
+	f.mu.Unlock()
+	if r == nil {
+		return names
+	}
+	start := sort.SearchStrings(names, r.lane)
+	if start < len(names) && names[start] == r.lane && r.credit <= 0 {
+		start++
+	}
+	turns := make([]string, 0, len(names))
+	turns = append(turns, names[start:]...)
+	return append(turns, names[:start]...)
+}
+
+// served records that a job was taken from a lane
+func (f *Fair) served(ctx context.Context, queue, lane string) {
+	f.mu.Lock()
+	r := f.rotations[queue]
+	if r != nil && r.lane == lane {
+		r.credit--
+		f.mu.Unlock()
+		return
+	}
+	f.mu.Unlock()
+
+	weight := 1
+	if team := strings.TrimPrefix(lane, queue+laneSeparator+"team:"); team != lane && f.weigher != nil {
+		weight = f.weigher.Weight(ctx, team)
+	}
+	if weight < 1 {
+		weight = 1
+	}
+	if weight > maxLaneWeight {
+		weight = maxLaneWeight
+	}
+
+	f.mu.Lock()
+	f.rotations[queue] = &rotation{lane: lane, credit: weight - 1}
+	f.mu.Unlock()
+}
+
+// laneOf returns the lane of a job: its team's, else its workflow's
+func laneOf(job *Job) string {
+	switch {
+	case job.Team != "":
+		return "team:" + job.Team
+	case job.WorkflowID != "":
+		return "workflow:" + job.WorkflowID
+	}
+	return ""
+}
@@ -0,0 +1,147 @@
+package queue
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MemoryQueue implements Queue in process for single binary deployments.
+// Jobs live only as long as the process, so executions still waiting on
+// the queue at exit are not resumed.
+type MemoryQueue struct {
+	mu       sync.Mutex
+	queues   map[string][]*Job
+	inflight map[string]map[string]*Job
+	// wake is closed when a job is enqueued on a queue consumers wait on
+	wake map[string]chan struct{}
+}
+
+// NewMemoryQueue creates a new in-process queue
+func NewMemoryQueue() *MemoryQueue {
+	return &MemoryQueue{
+		queues:   make(map[string][]*Job),
+		inflight: make(map[string]map[string]*Job),
+		wake:     make(map[string]chan struct{}),
+	}
+}
+
+// Enqueue adds a job to the tail of its queue
+func (q *MemoryQueue) Enqueue(ctx context.Context, job *Job) error {
+	if job.Queue == "" {
+		return errors.New("job queue name is required")
+	}
+	if job.ID == "" {
+		job.ID = uuid.New().String()
+	}
+	if job.EnqueuedAt.IsZero() {
+		job.EnqueuedAt = time.Now()
+	}
+
+	// Consumers get their own copy, as from a queue over the network
+	queued := *job
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.queues[job.Queue] = append(q.queues[job.Queue], &queued)
+	if wake, ok := q.wake[job.Queue]; ok {
+		close(wake)
+		delete(q.wake, job.Queue)
+	}
+	return nil
+}
+
+// Dequeue blocks up to timeout for the next job on the named queue
+func (q *MemoryQueue) Dequeue(ctx context.Context, queue, consumer string, timeout time.Duration) (*Job, error) {
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	for {
+		q.mu.Lock()
+		if jobs := q.queues[queue]; len(jobs) > 0 {
+			job := jobs[0]
+			jobs[0] = nil
+			q.queues[queue] = jobs[1:]
+			if q.inflight[consumer] == nil {
+				q.inflight[consumer] = make(map[string]*Job)
+			}
+			q.inflight[consumer][job.ID] = job
+			q.mu.Unlock()
+			return job, nil
+		}
+		wake, ok := q.wake[queue]
+		if !ok {
+			wake = make(chan struct{})
+			q.wake[queue] = wake
+		}
+		q.mu.Unlock()
+
+		select {
+		case <-wake:
+		case <-timer.C:
+			return nil, ErrEmpty
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// Ack removes a finished job from the consumer's in-flight set
+func (q *MemoryQueue) Ack(ctx context.Context, consumer string, job *Job) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	delete(q.inflight[consumer], job.ID)
+	return nil
+}
+
+// Requeue returns all in-flight jobs of a consumer to their queues
+func (q *MemoryQueue) Requeue(ctx context.Context, consumer string) (int, error) {
+	q.mu.Lock()
+	jobs := q.inflight[consumer]
+	delete(q.inflight, consumer)
+	q.mu.Unlock()
+
+	count := 0
+	for _, job := range jobs {
+		job.Attempts++
+		if err := q.Enqueue(ctx, job); err != nil {
+			return count, err
+		}
+		count++
+	}
+	return count, nil
+}
+
+// Len returns the number of jobs waiting on the named queue
+func (q *MemoryQueue) Len(ctx context.Context, queue string) (int64, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return int64(len(q.queues[queue])), nil
+}
+
+// Lag returns how long the job at the head of the named queue has waited
+func (q *MemoryQueue) Lag(ctx context.Context, queue string) (time.Duration, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if jobs := q.queues[queue]; len(jobs) > 0 {
+		return lagSince(jobs[0].EnqueuedAt), nil
+	}
+	return 0, nil
+}
+
+// Backlogs returns the backlogs of the queues starting with prefix
+func (q *MemoryQueue) Backlogs(ctx context.Context, prefix string) ([]Backlog, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	var backlogs []Backlog
+	for name, jobs := range q.queues {
+		if len(jobs) > 0 && strings.HasPrefix(name, prefix) {
+			backlogs = append(backlogs, Backlog{Queue: name, Waiting: int64(len(jobs)), Oldest: jobs[0].EnqueuedAt})
+		}
+	}
+	return backlogs, nil
+}
@@ -0,0 +1,181 @@
+package queue
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jaydeep/go-n8n/pkg/database"
+)
+
+// defaultPollInterval is how often Dequeue looks for jobs while waiting
+const defaultPollInterval = 500 * time.Millisecond
+
+// dequeueSQL claims the oldest waiting job of a queue. SKIP LOCKED lets
+// concurrent consumers claim different jobs without waiting on each other.
+const dequeueSQL = `
+UPDATE queue_jobs SET consumer = ?, dequeued_at = NOW()
+WHERE id = (
+    SELECT id FROM queue_jobs
+    WHERE queue = ? AND consumer IS NULL
+    ORDER BY enqueued_at, id
+    LIMIT 1
+    FOR UPDATE SKIP LOCKED
+)
+RETURNING id, payload, attempts`
+
+// backlogsSQL sums up the waiting jobs of the queues matching a LIKE
+// pattern
+const backlogsSQL = `
+SELECT queue, COUNT(*) AS waiting, MIN(enqueued_at) AS oldest
+FROM queue_jobs
+WHERE queue LIKE ? ESCAPE '\' AND consumer IS NULL
+GROUP BY queue`
+
+// likeEscaper escapes the wildcards of LIKE patterns
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+// PostgresQueue implements Queue on a PostgreSQL table for deployments
+// without Redis. Jobs are rows in queue_jobs; a dequeued job is claimed by
+// setting its consumer and deleted on ack. Consumers poll for jobs, so
+// Dequeue picks up new jobs within the poll interval.
+type PostgresQueue struct {
+	db           *database.DB
+	pollInterval time.Duration
+}
+
+// queueRow is a claimed queue_jobs row
+type queueRow struct {
+	ID       int64
+	Payload  string
+	Attempts int
+}
+
+// NewPostgresQueue creates a new PostgreSQL backed queue
+func NewPostgresQueue(db *database.DB, pollInterval time.Duration) *PostgresQueue {
+	if pollInterval <= 0 {
+		pollInterval = defaultPollInterval
+	}
+	return &PostgresQueue{db: db, pollInterval: pollInterval}
+}
+
+// Enqueue adds a job to the tail of its queue
+func (q *PostgresQueue) Enqueue(ctx context.Context, job *Job) error {
+	if job.Queue == "" {
+		return errors.New("job queue name is required")
+	}
+	if job.ID == "" {
+		job.ID = uuid.New().String()
+	}
+	if job.EnqueuedAt.IsZero() {
+		job.EnqueuedAt = time.Now()
+	}
+
+	data, err := json.Marshal(job)
+	if err != nil {
+		return fmt.Errorf("failed to encode job: %w", err)
+	}
+
+	return q.db.WithContext(ctx).Exec(
+		"INSERT INTO queue_jobs (queue, payload, attempts, enqueued_at) VALUES (?, ?, ?, ?)",
+		job.Queue, string(data), job.Attempts, job.EnqueuedAt,
+	).Error
+}
+
+// Dequeue blocks up to timeout for the next job on the named queue
+func (q *PostgresQueue) Dequeue(ctx context.Context, queue, consumer string, timeout time.Duration) (*Job, error) {
+	deadline := time.NewTimer(timeout)
+	defer deadline.Stop()
+	ticker := time.NewTicker(q.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		job, err := q.claim(ctx, queue, consumer)
+		if err != nil || job != nil {
+			return job, err
+		}
+
+		select {
+		case <-ticker.C:
+		case <-deadline.C:
+			return nil, ErrEmpty
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// claim takes the oldest waiting job, returning nil when there is none
+func (q *PostgresQueue) claim(ctx context.Context, queue, consumer string) (*Job, error) {
+	var rows []queueRow
+	if err := q.db.WithContext(ctx).Raw(dequeueSQL, consumer, queue).Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, nil
+	}
+	row := rows[0]
+
+	var job Job
+	if err := json.Unmarshal([]byte(row.Payload), &job); err != nil {
+		// Drop undecodable payloads so they don't poison the queue
+		q.db.WithContext(ctx).Exec("DELETE FROM queue_jobs WHERE id = ?", row.ID)
+		return nil, fmt.Errorf("failed to decode job: %w", err)
+	}
+	job.Attempts = row.Attempts
+	job.raw = strconv.FormatInt(row.ID, 10)
+
+	return &job, nil
+}
+
+// Ack deletes a finished job
+func (q *PostgresQueue) Ack(ctx context.Context, consumer string, job *Job) error {
+	return q.db.WithContext(ctx).Exec(
+		"DELETE FROM queue_jobs WHERE id = ? AND consumer = ?", job.raw, consumer,
+	).Error
+}
+
+// Requeue returns every job claimed by the consumer to the tail of its queue
+func (q *PostgresQueue) Requeue(ctx context.Context, consumer string) (int, error) {
+	result := q.db.WithContext(ctx).Exec(
+		`UPDATE queue_jobs SET consumer = NULL, dequeued_at = NULL, attempts = attempts + 1, enqueued_at = NOW()
+		WHERE consumer = ?`, consumer,
+	)
+	return int(result.RowsAffected), result.Error
+}
+
+// Len returns the number of jobs waiting on the named queue
+func (q *PostgresQueue) Len(ctx context.Context, queue string) (int64, error) {
+	var count int64
+	err := q.db.WithContext(ctx).
+		Table("queue_jobs").
+		Where("queue = ? AND consumer IS NULL", queue).
+		Count(&count).Error
+	return count, err
+}
+
+// Lag returns how long the oldest waiting job of the named queue has waited
+func (q *PostgresQueue) Lag(ctx context.Context, queue string) (time.Duration, error) {
+	var oldest sql.NullTime
+	err := q.db.WithContext(ctx).
+		Raw("SELECT MIN(enqueued_at) FROM queue_jobs WHERE queue = ? AND consumer IS NULL", queue).
+		Row().Scan(&oldest)
+	if err != nil {
+		return 0, err
+	}
+	return lagSince(oldest.Time), nil
+}
+
+// Backlogs returns the backlogs of the queues starting with prefix
+func (q *PostgresQueue) Backlogs(ctx context.Context, prefix string) ([]Backlog, error) {
+	var backlogs []Backlog
+	pattern := likeEscaper.Replace(prefix) + "%"
+	err := q.db.WithContext(ctx).Raw(backlogsSQL, pattern).Scan(&backlogs).Error
+	return backlogs, err
+}
@@ -0,0 +1,95 @@
+package queue
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var (
+	// ErrEmpty is returned by Dequeue when no job arrived before the timeout
+	ErrEmpty = errors.New("queue is empty")
+)
+
+// Job represents a unit of work placed on a queue. Its type, the mode of
+// the execution it runs, lets a Router place it on a dedicated queue, and
+// its region, the data residency region of the workflow, on the region's.
+// Its team, the team owning the workflow, lets a Throttle hold it back
+// while the team is over its quotas.
+type Job struct {
+	ID          string                 `json:"id"`
+	Queue       string                 `json:"queue"`
+	Type        string                 `json:"type,omitempty"`
+	Region      string                 `json:"region,omitempty"`
+	Team        string                 `json:"team,omitempty"`
+	ExecutionID string                 `json:"execution_id"`
+	WorkflowID  string                 `json:"workflow_id"`
+	Payload     map[string]interface{} `json:"payload,omitempty"`
+	Attempts    int                    `json:"attempts"`
+	EnqueuedAt  time.Time              `json:"enqueued_at"`
+	Deadline    time.Time              `json:"deadline,omitempty"`
+	// CorrelationID is the correlation ID of the execution, for the worker
+	// to carry into its logs and outbound calls
+	CorrelationID string `json:"correlation_id,omitempty"`
+
+	// raw identifies the dequeued job to the driver to ack it: the encoded
+	// form for Redis, the row id for PostgreSQL
+	raw string
+}
+
+// Context returns the context to process the job in, which is cancelled at
+// the job's deadline when it has one
+func (j *Job) Context(parent context.Context) (context.Context, context.CancelFunc) {
+	if j.Deadline.IsZero() {
+		return context.WithCancel(parent)
+	}
+	return context.WithDeadline(parent, j.Deadline)
+}
+
+// Queue is the interface implemented by all queue drivers.
+//
+// Dequeued jobs are tracked as in-flight for the consumer that took them
+// until they are acknowledged, so that jobs held by a crashed consumer can be
+// handed back to the queue with Requeue.
+type Queue interface {
+	// Enqueue adds a job to the tail of its queue
+	Enqueue(ctx context.Context, job *Job) error
+
+	// Dequeue blocks up to timeout for the next job on the named queue and
+	// marks it in-flight for the consumer. A zero timeout only takes a job
+	// already waiting.
+	Dequeue(ctx context.Context, queue, consumer string, timeout time.Duration) (*Job, error)
+
+	// Ack removes a finished job from the consumer's in-flight set
+	Ack(ctx context.Context, consumer string, job *Job) error
+
+	// Requeue returns all in-flight jobs of a consumer to their queues
+	Requeue(ctx context.Context, consumer string) (int, error)
+
+	// Len returns the number of jobs waiting on the named queue
+	Len(ctx context.Context, queue string) (int64, error)
+
+	// Lag returns how long the oldest job waiting on the named queue has
+	// been waiting, or zero when none is
+	Lag(ctx context.Context, queue string) (time.Duration, error)
+
+	// Backlogs returns the backlogs of the queues whose names start with
+	// prefix and that have jobs waiting, in no particular order
+	Backlogs(ctx context.Context, prefix string) ([]Backlog, error)
+}
+
+// Backlog describes the jobs waiting on a queue
+type Backlog struct {
+	Queue   string
+	Waiting int64
+	// Oldest is when the job next to be dequeued was enqueued
+	Oldest time.Time
+}
+
+// lagSince returns the time waited since enqueuedAt, or zero for no job
+func lagSince(enqueuedAt time.Time) time.Duration {
+	if enqueuedAt.IsZero() {
+		return 0
+	}
+	return time.Since(enqueuedAt)
+}
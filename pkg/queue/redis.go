@@ -0,0 +1,177 @@
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+const keyPrefix = "n8n:queue:"
+
+// RedisQueue implements Queue on top of Redis lists using the reliable queue
+// pattern: jobs are atomically moved to a per-consumer processing list on
+// dequeue and removed from it on ack.
+type RedisQueue struct {
+	client *redis.Client
+}
+
+// NewRedisQueue creates a new Redis backed queue
+func NewRedisQueue(client *redis.Client) *RedisQueue {
+	return &RedisQueue{client: client}
+}
+
+// Enqueue adds a job to the tail of its queue
+func (q *RedisQueue) Enqueue(ctx context.Context, job *Job) error {
+	if job.Queue == "" {
+		return errors.New("job queue name is required")
+	}
+	if job.ID == "" {
+		job.ID = uuid.New().String()
+	}
+	if job.EnqueuedAt.IsZero() {
+		job.EnqueuedAt = time.Now()
+	}
+
+	data, err := json.Marshal(job)
+	if err != nil {
+		return fmt.Errorf("failed to encode job: %w", err)
+	}
+
+	return q.client.LPush(ctx, queueKey(job.Queue), data).Err()
+}
+
+// Dequeue blocks up to timeout for the next job on the named queue
+func (q *RedisQueue) Dequeue(ctx context.Context, queue, consumer string, timeout time.Duration) (*Job, error) {
+	// BLMOVE blocks for at least a second, so zero timeouts use LMOVE
+	var data string
+	var err error
+	if timeout > 0 {
+		data, err = q.client.BLMove(ctx, queueKey(queue), processingKey(consumer), "RIGHT", "LEFT", timeout).Result()
+	} else {
+		data, err = q.client.LMove(ctx, queueKey(queue), processingKey(consumer), "RIGHT", "LEFT").Result()
+	}
+	if errors.Is(err, redis.Nil) {
+		return nil, ErrEmpty
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var job Job
+	if err := json.Unmarshal([]byte(data), &job); err != nil {
+		// Drop undecodable payloads so they don't poison the processing list
+		q.client.LRem(ctx, processingKey(consumer), 1, data)
+		return nil, fmt.Errorf("failed to decode job: %w", err)
+	}
+	job.raw = data
+
+	return &job, nil
+}
+
+// Ack removes a finished job from the consumer's processing list
+func (q *RedisQueue) Ack(ctx context.Context, consumer string, job *Job) error {
+	return q.client.LRem(ctx, processingKey(consumer), 1, job.raw).Err()
+}
+
+// Requeue moves every job in the consumer's processing list back to its queue
+func (q *RedisQueue) Requeue(ctx context.Context, consumer string) (int, error) {
+	count := 0
+	for {
+		data, err := q.client.RPop(ctx, processingKey(consumer)).Result()
+		if errors.Is(err, redis.Nil) {
+			return count, nil
+		}
+		if err != nil {
+			return count, err
+		}
+
+		var job Job
+		if err := json.Unmarshal([]byte(data), &job); err != nil {
+			continue
+		}
+		job.Attempts++
+		if err := q.Enqueue(ctx, &job); err != nil {
+			return count, err
+		}
+		count++
+	}
+}
+
+// Len returns the number of jobs waiting on the named queue
+func (q *RedisQueue) Len(ctx context.Context, queue string) (int64, error) {
+	return q.client.LLen(ctx, queueKey(queue)).Result()
+}
+
+// Lag returns how long the job next to be dequeued from the named queue
+// has waited
+func (q *RedisQueue) Lag(ctx context.Context, queue string) (time.Duration, error) {
+	data, err := q.client.LIndex(ctx, queueKey(queue), -1).Result()
+	if errors.Is(err, redis.Nil) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	var job Job
+	if err := json.Unmarshal([]byte(data), &job); err != nil {
+		return 0, fmt.Errorf("failed to decode job: %w", err)
+	}
+	return lagSince(job.EnqueuedAt), nil
+}
+
+// Backlogs returns the backlogs of the queues starting with prefix
+func (q *RedisQueue) Backlogs(ctx context.Context, prefix string) ([]Backlog, error) {
+	var keys []string
+	iter := q.client.Scan(ctx, 0, queueKey(globEscaper.Replace(prefix))+"*", 100).Iterator()
+	for iter.Next(ctx) {
+		if !strings.HasPrefix(iter.Val(), processingKey("")) {
+			keys = append(keys, iter.Val())
+		}
+	}
+	if err := iter.Err(); err != nil {
+		return nil, err
+	}
+
+	pipe := q.client.Pipeline()
+	lengths := make([]*redis.IntCmd, len(keys))
+	next := make([]*redis.StringCmd, len(keys))
+	for i, key := range keys {
+		lengths[i] = pipe.LLen(ctx, key)
+		next[i] = pipe.LIndex(ctx, key, -1)
+	}
+	if _, err := pipe.Exec(ctx); err != nil && !errors.Is(err, redis.Nil) {
+		return nil, err
+	}
+
+	var backlogs []Backlog
+	for i, key := range keys {
+		if lengths[i].Val() == 0 {
+			continue
+		}
+		backlog := Backlog{Queue: strings.TrimPrefix(key, keyPrefix), Waiting: lengths[i].Val()}
+		var job Job
+		if json.Unmarshal([]byte(next[i].Val()), &job) == nil {
+			backlog.Oldest = job.EnqueuedAt
+		}
+		backlogs = append(backlogs, backlog)
+	}
+	return backlogs, nil
+}
+
+// globEscaper escapes the wildcards of SCAN patterns
+var globEscaper = strings.NewReplacer(`\`, `\\`, "*", `\*`, "?", `\?`, "[", `\[`, "]", `\]`)
+
+func queueKey(queue string) string {
+	return keyPrefix + queue
+}
+
+func processingKey(consumer string) string {
+	return keyPrefix + "processing:" + consumer
+}
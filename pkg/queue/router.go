@@ -0,0 +1,119 @@
+package queue
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// ErrUnknownRegion is returned by Router.Enqueue for jobs of a region no
+// queue is configured for
+var ErrUnknownRegion = errors.New("no queue is configured for the region")
+
+// Router is a Queue placing jobs on the queue their type is routed to, so
+// that job types can be consumed by dedicated workers scaling on their own.
+// Jobs of other types, and jobs naming a queue other than the default one,
+// stay on the queue they name. Jobs of a region always go to the region's
+// queue, so that they are only processed by workers in the region.
+type Router struct {
+	Queue
+	defaultQueue string
+	routes       map[string]string
+	named        []string
+	regions      map[string]string
+}
+
+// NewRouter wraps q to route jobs by type and region. defaultQueue is the
+// queue jobs are placed on unless routed; named lists further queues jobs
+// may name; regions maps regions to their queues.
+func NewRouter(q Queue, defaultQueue string, routes map[string]string, named []string, regions map[string]string) (*Router, error) {
+	for jobType, name := range routes {
+		if name == "" {
+			return nil, fmt.Errorf("queue route for job type %q has no queue", jobType)
+		}
+	}
+	for region, name := range regions {
+		if name == "" {
+			return nil, fmt.Errorf("region %q has no queue", region)
+		}
+	}
+	for _, name := range named {
+		if name == "" {
+			return nil, fmt.Errorf("queue names must not be empty")
+		}
+	}
+	return &Router{Queue: q, defaultQueue: defaultQueue, routes: routes, named: named, regions: regions}, nil
+}
+
+// Enqueue adds a job to the tail of the queue of its region, or else the
+// queue its type is routed to unless it names a queue of its own. Jobs of
+// an unknown region are refused rather than processed anywhere.
+func (r *Router) Enqueue(ctx context.Context, job *Job) error {
+	switch {
+	case job.Region != "":
+		name, ok := r.regions[job.Region]
+		if !ok {
+			return fmt.Errorf("%w: %s", ErrUnknownRegion, job.Region)
+		}
+		job.Queue = name
+	case job.Queue == "" || job.Queue == r.defaultQueue:
+		job.Queue = r.defaultQueue
+		if name, ok := r.routes[job.Type]; ok {
+			job.Queue = name
+		}
+	}
+	return r.Queue.Enqueue(ctx, job)
+}
+
+// Queues returns the default queue followed by the routed, named and
+// region queues in name order
+func (r *Router) Queues() []string {
+	queues := []string{r.defaultQueue}
+	seen := map[string]bool{r.defaultQueue: true}
+	add := func(name string) {
+		if !seen[name] {
+			seen[name] = true
+			queues = append(queues, name)
+		}
+	}
+	for _, name := range r.routes {
+		add(name)
+	}
+	for _, name := range r.named {
+		add(name)
+	}
+	for _, name := range r.regions {
+		add(name)
+	}
+	sort.Strings(queues[1:])
+	return queues
+}
+
+// Has reports whether name is one of the queues of the router
+func (r *Router) Has(name string) bool {
+	for _, q := range r.Queues() {
+		if q == name {
+			return true
+		}
+	}
+	return false
+}
+
+// RegionQueue returns the queue of a region
+func (r *Router) RegionQueue(region string) (string, bool) {
+	name, ok := r.regions[region]
+	return name, ok
+}
+
+// JobTypes returns the job types routed to the named queue, in order
+func (r *Router) JobTypes(queue string) []string {
+	types := []string{}
+	for jobType, name := range r.routes {
+		if name == queue {
+			types = append(types, jobType)
+		}
+	}
+	sort.Strings(types)
+	return types
+}
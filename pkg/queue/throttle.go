@@ -0,0 +1,108 @@
+package queue
+
+import (
+	"context"
+	"time"
+)
+
+const (
+	// maxDeferrals bounds the jobs of refused teams one Dequeue moves to
+	// the tail of the queue before giving up
+	maxDeferrals = 100
+	// throttledWait is how long Dequeue waits before reporting the queue
+	// empty when only jobs of refused teams are waiting on it
+	throttledWait = time.Second
+)
+
+// Admitter decides whether the jobs of a team may start
+type Admitter interface {
+	// Admit reports whether a job of the team may start now, counting it
+	// against the team's quotas when it may
+	Admit(ctx context.Context, team string) bool
+}
+
+// Throttle is a Queue holding back the jobs of teams over their quotas, so
+// that one team flooding a queue cannot starve the others. A job of a team
+// the admitter refuses is moved to the tail of its queue, behind the jobs
+// of other teams, and taken again when its turn comes round. Jobs without
+// a team are never held back.
+type Throttle struct {
+	Queue
+	admitter Admitter
+}
+
+// NewThrottle wraps q to admit the jobs of teams through admitter
+func NewThrottle(q Queue, admitter Admitter) *Throttle {
+	return &Throttle{Queue: q, admitter: admitter}
+}
+
+// Dequeue blocks up to timeout for the next job on the named queue that may
+// start. Once it comes back to a job it deferred, only jobs of refused teams
+// are waiting, and it reports the queue empty after a short wait rather
+// than cycling through them.
+func (t *Throttle) Dequeue(ctx context.Context, queue, consumer string, timeout time.Duration) (*Job, error) {
+	deadline := time.Now().Add(timeout)
+	refused := make(map[string]bool)
+	deferred := make(map[string]bool)
+	for {
+		remaining := time.Until(deadline)
+		if remaining <= 0 {
+			return nil, ErrEmpty
+		}
+		job, err := t.Queue.Dequeue(ctx, queue, consumer, remaining)
+		if err != nil {
+			return nil, err
+		}
+		if job.Team == "" {
+			return job, nil
+		}
+		// A team refused once is not asked again for the jobs behind
+		if !refused[job.Team] {
+			if t.admitter.Admit(ctx, job.Team) {
+				return job, nil
+			}
+			refused[job.Team] = true
+		}
+
+		if err := t.deferJob(ctx, consumer, job); err != nil {
+			return nil, err
+		}
+		if deferred[job.ID] || len(deferred) >= maxDeferrals {
+			return nil, wait(ctx, minDuration(throttledWait, time.Until(deadline)))
+		}
+		deferred[job.ID] = true
+	}
+}
+
+// deferJob moves a dequeued job to the tail of its queue
+func (t *Throttle) deferJob(ctx context.Context, consumer string, job *Job) error {
+	requeued := *job
+	requeued.raw = ""
+	if err := t.Queue.Enqueue(ctx, &requeued); err != nil {
+		return err
+	}
+	return t.Queue.Ack(ctx, consumer, job)
+}
+
+// wait sleeps for d unless the context is cancelled first, returning
+// ErrEmpty or the context's error
+func wait(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ErrEmpty
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return ErrEmpty
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func minDuration(a, b time.Duration) time.Duration {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,151 @@
+// Package shutdown stops the subsystems of a process in dependency order:
+// first whatever takes in new work, then the work in flight, then buffered
+// data, and the remaining services last.
+package shutdown
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/jaydeep/go-n8n/pkg/logger"
+)
+
+// Phase is a step of the shutdown; phases run in ascending order and the
+// steps of one phase run concurrently
+type Phase int
+
+const (
+	// PhaseIntake stops taking new work: the HTTP server, triggers and
+	// queue consumers
+	PhaseIntake Phase = iota
+	// PhaseDrain waits for the work in flight: running executions and open
+	// WebSocket connections
+	PhaseDrain
+	// PhaseFlush writes out buffered data such as node records, analytics
+	// events and callbacks
+	PhaseFlush
+	// PhaseServices stops the remaining background services
+	PhaseServices
+)
+
+var phaseNames = map[Phase]string{
+	PhaseIntake:   "intake",
+	PhaseDrain:    "drain",
+	PhaseFlush:    "flush",
+	PhaseServices: "services",
+}
+
+func (p Phase) String() string {
+	if name, ok := phaseNames[p]; ok {
+		return name
+	}
+	return fmt.Sprintf("phase %d", int(p))
+}
+
+// step is a named shutdown action
+type step struct {
+	name string
+	stop func(ctx context.Context) error
+}
+
+// Coordinator runs the registered shutdown steps phase by phase
+type Coordinator struct {
+	log *logger.Logger
+
+	mu    sync.Mutex
+	steps map[Phase][]step
+}
+
+// New creates a new shutdown coordinator
+func New(log *logger.Logger) *Coordinator {
+	return &Coordinator{
+		log:   log,
+		steps: make(map[Phase][]step),
+	}
+}
+
+// OnShutdown registers stop to run in phase
+func (c *Coordinator) OnShutdown(phase Phase, name string, stop func(ctx context.Context) error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.steps[phase] = append(c.steps[phase], step{name: name, stop: stop})
+}
+
+// Go runs a background service until phase, when its context is cancelled
+// and the shutdown waits for it to return. Services that flush on
+// cancellation are thereby flushed in their phase.
+func (c *Coordinator) Go(phase Phase, name string, run func(ctx context.Context)) {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		run(ctx)
+	}()
+
+	c.OnShutdown(phase, name, func(stopCtx context.Context) error {
+		cancel()
+		select {
+		case <-done:
+			return nil
+		case <-stopCtx.Done():
+			return stopCtx.Err()
+		}
+	})
+}
+
+// Shutdown runs the phases in order. ctx bounds the whole shutdown: once it
+// expires the steps still running are abandoned and the later phases run
+// with an expired context, so they only do what needs no waiting. The
+// errors of all steps are returned together.
+func (c *Coordinator) Shutdown(ctx context.Context) error {
+	c.mu.Lock()
+	phases := make([]Phase, 0, len(c.steps))
+	for phase := range c.steps {
+		phases = append(phases, phase)
+	}
+	c.mu.Unlock()
+	sortPhases(phases)
+
+	var errs []error
+	for _, phase := range phases {
+		c.mu.Lock()
+		steps := c.steps[phase]
+		c.mu.Unlock()
+		errs = append(errs, c.runPhase(ctx, phase, steps)...)
+	}
+	return errors.Join(errs...)
+}
+
+// runPhase runs the steps of a phase concurrently and waits for them
+func (c *Coordinator) runPhase(ctx context.Context, phase Phase, steps []step) []error {
+	started := time.Now()
+	errs := make([]error, len(steps))
+
+	var wg sync.WaitGroup
+	for i, s := range steps {
+		wg.Add(1)
+		go func(i int, s step) {
+			defer wg.Done()
+			if err := s.stop(ctx); err != nil {
+				errs[i] = fmt.Errorf("%s: %w", s.name, err)
+				c.log.Warnw("Shutdown step failed", "phase", phase.String(), "step", s.name, "error", err)
+			}
+		}(i, s)
+	}
+	wg.Wait()
+
+	c.log.Infow("Shutdown phase completed", "phase", phase.String(), "steps", len(steps), "duration", time.Since(started))
+	return errs
+}
+
+// sortPhases sorts a few phases in ascending order
+func sortPhases(phases []Phase) {
+	for i := 1; i < len(phases); i++ {
+		for j := i; j > 0 && phases[j] < phases[j-1]; j-- {
+			phases[j], phases[j-1] = phases[j-1], phases[j]
+		}
+	}
+}